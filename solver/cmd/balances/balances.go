@@ -0,0 +1,174 @@
+package balances
+
+// Balances command - prints the solver's balance of every token in the token registry
+//
+// Usage:
+//
+//	solver balances [--format table|csv|json] [--output FILE]
+//
+// The table has a row per token symbol and a column per network; balances below the token's
+// lowBalance threshold are marked with "!" and listed below it, along with failed reads.
+// csv and json have one entry per token and network.
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/cmd/cliout"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+)
+
+// readTimeout bounds reading all balances
+const readTimeout = 2 * time.Minute
+
+// options are the parsed flags of the command
+type options struct {
+	format string
+	output string
+}
+
+// RunBalances prints the balance matrix; args excludes the "balances" command itself
+func RunBalances(args []string) error {
+	opts, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	config.InitializeNetworks()
+	if err := config.ApplyRuntime(&cfg.RuntimeConfig, hyperlane7683.ValidateRuleConfig, logutil.ValidateLogLevels); err != nil {
+		return err
+	}
+	if len(config.ActiveRuntime().Tokens) == 0 {
+		return fmt.Errorf("the token registry is empty, list tokens under \"tokens\" in SOLVER_CONFIG_FILE")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), readTimeout)
+	defer cancel()
+	balances := hyperlane7683.NewBalanceMonitor(nil, nil).Balances(ctx)
+
+	return cliout.WriteOutput(opts.output, func(w io.Writer) error {
+		switch opts.format {
+		case "csv":
+			return writeCSV(w, balances)
+		case "json":
+			return writeJSON(w, balances)
+		default:
+			return writeTable(w, balances)
+		}
+	})
+}
+
+func printUsage() {
+	fmt.Println("Usage: solver balances [--format table|csv|json] [--output FILE]")
+	fmt.Println("  Balances of the registry tokens; \"!\" marks balances below their lowBalance threshold")
+}
+
+func parseFlags(args []string) (options, error) {
+	var opts options
+	fs := flag.NewFlagSet("balances", flag.ContinueOnError)
+	fs.Usage = printUsage
+	fs.StringVar(&opts.format, "format", cliout.DefaultFormat("table"), "output format: table, csv or json")
+	fs.StringVar(&opts.output, "output", "", "write to file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if opts.format != "table" && opts.format != "csv" && opts.format != "json" {
+		return opts, fmt.Errorf("unsupported format %q (use table, csv or json)", opts.format)
+	}
+	return opts, nil
+}
+
+// writeTable renders the balances as a symbol by network matrix
+func writeTable(w io.Writer, balances []hyperlane7683.TokenBalance) error {
+	var networks, symbols []string
+	cells := make(map[string]map[string]string)
+	for _, balance := range balances {
+		if cells[balance.Symbol] == nil {
+			cells[balance.Symbol] = make(map[string]string)
+			symbols = append(symbols, balance.Symbol)
+		}
+		if !slices.Contains(networks, balance.Network) {
+			networks = append(networks, balance.Network)
+		}
+
+		cell := balance.Balance
+		switch {
+		case balance.Error != "":
+			cell = "error"
+		case balance.Low:
+			cell += " !"
+		}
+		cells[balance.Symbol][balance.Network] = cell
+	}
+	sort.Strings(networks)
+	sort.Strings(symbols)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "TOKEN\t%s\t\n", strings.Join(networks, "\t"))
+	for _, symbol := range symbols {
+		row := make([]string, len(networks))
+		for i, network := range networks {
+			row[i] = "-"
+			if cell, ok := cells[symbol][network]; ok {
+				row[i] = cell
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t\n", symbol, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	var notes []string
+	for _, balance := range balances {
+		switch {
+		case balance.Error != "":
+			notes = append(notes, fmt.Sprintf("❌ %s on %s: %s", balance.Symbol, balance.Network, balance.Error))
+		case balance.Low:
+			notes = append(notes, fmt.Sprintf("🪫 %s on %s is below its threshold of %s", balance.Symbol, balance.Network, balance.Threshold))
+		}
+	}
+	if len(notes) > 0 {
+		fmt.Fprintf(w, "\n%s\n", strings.Join(notes, "\n"))
+	}
+	return nil
+}
+
+// writeCSV writes one row per token and network
+func writeCSV(w io.Writer, balances []hyperlane7683.TokenBalance) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{{"network", "chain_id", "symbol", "token", "balance", "threshold", "low", "error"}}
+	for _, b := range balances {
+		rows = append(rows, []string{b.Network, strconv.FormatUint(b.ChainID, 10), b.Symbol, b.Token, b.Balance, b.Threshold, strconv.FormatBool(b.Low), b.Error})
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// writeJSON writes the balances as an indented JSON array
+func writeJSON(w io.Writer, balances []hyperlane7683.TokenBalance) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(balances)
+}
@@ -0,0 +1,56 @@
+package balances
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFlags(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		opts, err := parseFlags(nil)
+		require.NoError(t, err)
+		assert.Equal(t, "table", opts.format)
+		assert.Empty(t, opts.output)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseFlags([]string{"--format", "xml"})
+		assert.ErrorContains(t, err, "unsupported format")
+		_, err = parseFlags([]string{"Base"})
+		assert.ErrorContains(t, err, "unexpected argument")
+	})
+}
+
+func TestWriteBalances(t *testing.T) {
+	balances := []hyperlane7683.TokenBalance{
+		{ChainID: 84532, Network: "Base", Token: "0xa1", Symbol: "DOG", Balance: "50", Threshold: "100", Low: true},
+		{ChainID: 84532, Network: "Base", Token: "0x0", Symbol: "ETH", Balance: "2.5"},
+		{ChainID: 23448591, Network: "Starknet", Token: "0xa2", Symbol: "DOG", Error: "rpc down"},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeTable(&buf, balances))
+
+		lines := strings.Split(buf.String(), "\n")
+		assert.Equal(t, []string{"TOKEN", "Base", "Starknet"}, strings.Fields(lines[0]))
+		assert.Equal(t, []string{"DOG", "50", "!", "error"}, strings.Fields(lines[1]))
+		assert.Equal(t, []string{"ETH", "2.5", "-"}, strings.Fields(lines[2]))
+		assert.Contains(t, buf.String(), "DOG on Base is below its threshold of 100")
+		assert.Contains(t, buf.String(), "DOG on Starknet: rpc down")
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeCSV(&buf, balances))
+		assert.Equal(t, "network,chain_id,symbol,token,balance,threshold,low,error\n"+
+			"Base,84532,DOG,0xa1,50,100,true,\n"+
+			"Base,84532,ETH,0x0,2.5,,false,\n"+
+			"Starknet,23448591,DOG,0xa2,,,false,rpc down\n", buf.String())
+	})
+}
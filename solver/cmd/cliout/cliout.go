@@ -0,0 +1,105 @@
+// Package cliout switches the solver commands between human and JSON output.
+//
+// The global --json flag is stripped from the command line by main before dispatch. In JSON
+// mode the progress messages commands print for humans go to stderr, and stdout only carries
+// the command's result as one JSON document, so scripts and the integration tests can decode it
+// instead of scraping text. Commands with a --format flag default to json.
+//
+// Usage:
+//
+//	args, json := cliout.StripJSONFlag(os.Args[1:])
+//	if json {
+//		cliout.EnableJSON()
+//	}
+//	...
+//	if cliout.JSON() {
+//		return cliout.WriteJSON(result)
+//	}
+package cliout
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// jsonFlags are the spellings of the global flag
+var jsonFlags = map[string]bool{"--json": true, "-json": true}
+
+var (
+	jsonMode bool
+	stdout   io.Writer = os.Stdout
+)
+
+// StripJSONFlag removes the --json flag from args, wherever it appears before a "--" terminator,
+// and reports whether it was present
+func StripJSONFlag(args []string) ([]string, bool) {
+	stripped := make([]string, 0, len(args))
+	found := false
+	for i, arg := range args {
+		if arg == "--" {
+			stripped = append(stripped, args[i:]...)
+			break
+		}
+		if jsonFlags[arg] {
+			found = true
+			continue
+		}
+		stripped = append(stripped, arg)
+	}
+	return stripped, found
+}
+
+// EnableJSON turns JSON mode on: os.Stdout is pointed at stderr for the human messages and
+// Stdout keeps the process's stdout for results
+func EnableJSON() {
+	jsonMode = true
+	stdout = os.Stdout
+	os.Stdout = os.Stderr
+}
+
+// JSON reports whether the --json flag was given
+func JSON() bool {
+	return jsonMode
+}
+
+// Stdout returns where command results are written, os.Stdout outside JSON mode
+func Stdout() io.Writer {
+	if !jsonMode {
+		return os.Stdout
+	}
+	return stdout
+}
+
+// DefaultFormat returns the default of a --format flag: json in JSON mode, format otherwise
+func DefaultFormat(format string) string {
+	if jsonMode {
+		return "json"
+	}
+	return format
+}
+
+// WriteOutput runs write against Stdout, or the file at path when it isn't empty
+func WriteOutput(path string, write func(io.Writer) error) error {
+	if path == "" {
+		return write(Stdout())
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteJSON writes v as an indented JSON document to Stdout
+func WriteJSON(v any) error {
+	enc := json.NewEncoder(Stdout())
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
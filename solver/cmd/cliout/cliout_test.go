@@ -0,0 +1,74 @@
+package cliout
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripJSONFlag(t *testing.T) {
+	args, found := StripJSONFlag([]string{"balances", "--format", "csv"})
+	assert.False(t, found)
+	assert.Equal(t, []string{"balances", "--format", "csv"}, args)
+
+	args, found = StripJSONFlag([]string{"--json", "tools", "open-order", "evm", "-json"})
+	assert.True(t, found)
+	assert.Equal(t, []string{"tools", "open-order", "evm"}, args)
+
+	args, found = StripJSONFlag([]string{"replay", "--", "--json"})
+	assert.False(t, found, "after the terminator")
+	assert.Equal(t, []string{"replay", "--", "--json"}, args)
+}
+
+// TestEnableJSON tests that results and human messages are split between stdout and stderr
+func TestEnableJSON(t *testing.T) {
+	assert.Equal(t, "table", DefaultFormat("table"))
+
+	out, outW, err := os.Pipe()
+	require.NoError(t, err)
+	errR, errW, err := os.Pipe()
+	require.NoError(t, err)
+	realStdout, realStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = outW, errW
+	defer func() {
+		os.Stdout, os.Stderr = realStdout, realStderr
+		jsonMode, stdout = false, realStdout
+	}()
+
+	EnableJSON()
+	assert.True(t, JSON())
+	assert.Equal(t, "json", DefaultFormat("table"))
+	fmt.Println("🚀 Transaction sent")
+	require.NoError(t, WriteJSON(map[string]string{"orderId": "0x1"}))
+	require.NoError(t, outW.Close())
+	require.NoError(t, errW.Close())
+
+	result, err := io.ReadAll(out)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"orderId": "0x1"}`, string(result))
+	messages, err := io.ReadAll(errR)
+	require.NoError(t, err)
+	assert.Equal(t, "🚀 Transaction sent\n", string(messages))
+}
+
+// TestWriteOutput tests writing a result to a file
+func TestWriteOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.csv")
+	require.NoError(t, WriteOutput(path, func(w io.Writer) error {
+		_, err := io.WriteString(w, "order,status\n")
+		return err
+	}))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "order,status\n", string(data))
+
+	failed := errors.New("encode failed")
+	assert.ErrorIs(t, WriteOutput(path, func(io.Writer) error { return failed }), failed)
+	assert.ErrorContains(t, WriteOutput(filepath.Join(path, "missing", "out.csv"), func(io.Writer) error { return nil }), "failed to create")
+}
@@ -0,0 +1,497 @@
+package configcheck
+
+// Config command - validates the solver configuration against the configured networks, so a broken
+// setup is reported before the solver is started
+//
+// Usage:
+//
+//	solver config check [--format table|json] [--timeout DURATION]
+//
+// Every check passes, warns or fails; failures and warnings come with a hint on what to change:
+// - config: the config file loads and the token registry and rule settings validate
+// - domains: the networks and custom domainMappings map Hyperlane domains to chain IDs one to one
+// - keys: the EVM key parses and matches SOLVER_PUB_KEY; the Starknet public key belongs to its private key
+// - rpc: each network's RPC answers; EVM nodes must report the configured chain ID
+// - router: the Hyperlane7683 address holds a contract; EVM routers must report the configured domain
+// - account: the Starknet solver account is deployed
+// - gas, balance: the solver holds native gas (STRK on Starknet) and its registry token balances are
+//   readable and above their lowBalance thresholds
+//
+// The command fails when any check fails; warnings don't fail it. IS_DEVNET selects the LOCAL_* settings
+// as it does for the solver.
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/cliout"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	defaultCheckTimeout = 2 * time.Minute
+	// STRK pays Starknet fees and lives at the same address on every Starknet network
+	starknetSTRKAddress = "0x04718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d"
+	nativeDecimals      = 18
+)
+
+// Status is the outcome of a check
+type Status string
+
+// Check outcomes; only StatusFail fails the command
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Result is the outcome of one check
+type Result struct {
+	Check   string `json:"check"`
+	Network string `json:"network,omitempty"`
+	Status  Status `json:"status"`
+	Detail  string `json:"detail"`
+	Hint    string `json:"hint,omitempty"` // What to change, for warnings and failures
+}
+
+// options are the parsed flags of the check subcommand
+type options struct {
+	format  string
+	timeout time.Duration
+}
+
+// RunConfig dispatches config subcommands; args excludes the "config" command itself
+func RunConfig(args []string) error {
+	if len(args) == 0 {
+		printUsage()
+		return fmt.Errorf("missing config subcommand")
+	}
+
+	switch args[0] {
+	case "check":
+		return runCheck(args[1:])
+	default:
+		printUsage()
+		return fmt.Errorf("unknown config subcommand: %s", args[0])
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage: solver config check [--format table|json] [--timeout DURATION]")
+	fmt.Println("  Validates RPCs, chain IDs, routers, domains, keys and balances before starting the solver")
+}
+
+func runCheck(args []string) error {
+	opts, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+	results := newChecker().run(ctx)
+
+	if opts.format == "json" {
+		err = writeJSON(cliout.Stdout(), results)
+	} else {
+		err = writeTable(cliout.Stdout(), results)
+	}
+	if err != nil {
+		return err
+	}
+	if failed := countStatus(results, StatusFail); failed > 0 {
+		return fmt.Errorf("%d of %d configuration checks failed", failed, len(results))
+	}
+	return nil
+}
+
+func parseFlags(args []string) (options, error) {
+	var opts options
+	fs := flag.NewFlagSet("config check", flag.ContinueOnError)
+	fs.Usage = printUsage
+	fs.StringVar(&opts.format, "format", cliout.DefaultFormat("table"), "output format: table or json")
+	fs.DurationVar(&opts.timeout, "timeout", defaultCheckTimeout, "time allowed for all RPC checks")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if opts.format != "table" && opts.format != "json" {
+		return opts, fmt.Errorf("unsupported format %q (use table or json)", opts.format)
+	}
+	if opts.timeout <= 0 {
+		return opts, fmt.Errorf("--timeout must be positive")
+	}
+	return opts, nil
+}
+
+// checker runs the checks in order and collects their results; tests replace the loader and dialers
+type checker struct {
+	loadConfig   func() (*config.Config, error)
+	dialEVM      func(rpcURL string) (hyperlane7683.EVMClient, error)
+	dialStarknet func(rpcURL string) (rpc.RPCProvider, error)
+
+	results []Result
+}
+
+func newChecker() *checker {
+	return &checker{
+		loadConfig: config.LoadConfig,
+		dialEVM: func(rpcURL string) (hyperlane7683.EVMClient, error) {
+			client, err := ethclient.Dial(rpcURL)
+			if err != nil {
+				return nil, err
+			}
+			return client, nil
+		},
+		dialStarknet: func(rpcURL string) (rpc.RPCProvider, error) {
+			provider, err := rpc.NewProvider(rpcURL)
+			if err != nil {
+				return nil, err
+			}
+			return provider, nil
+		},
+	}
+}
+
+// run performs every check; networks whose RPC fails skip their remaining checks
+func (c *checker) run(ctx context.Context) []Result {
+	cfg := c.checkConfig()
+	c.checkDomains(cfg)
+	evmSolver := c.checkEVMKey()
+	starknetSolver := c.checkStarknetKey()
+
+	evmClients := make(map[uint64]hyperlane7683.EVMClient)
+	starknetClients := make(map[uint64]rpc.RPCProvider)
+	names := config.GetNetworkNames()
+	sort.Strings(names)
+	for _, name := range names {
+		network := config.Networks[name]
+		if config.IsStarknetNetwork(name) {
+			if provider := c.checkStarknetNetwork(ctx, network, starknetSolver); provider != nil {
+				starknetClients[network.ChainID] = provider
+			}
+			continue
+		}
+		if client := c.checkEVMNetwork(ctx, network, evmSolver); client != nil {
+			evmClients[network.ChainID] = client
+		}
+	}
+
+	c.checkBalances(ctx, evmClients, starknetClients)
+	return c.results
+}
+
+func (c *checker) add(status Status, check, network, detail, hint string) {
+	c.results = append(c.results, Result{Check: check, Network: network, Status: status, Detail: detail, Hint: hint})
+}
+
+// checkConfig loads the configuration and applies its runtime settings; networks come from the
+// environment, so the remaining checks run even when the config file is broken
+func (c *checker) checkConfig() *config.Config {
+	cfg, err := c.loadConfig()
+	config.InitializeNetworks()
+	if err != nil {
+		c.add(StatusFail, "config", "", err.Error(), "fix SOLVER_CONFIG_FILE or unset it to use the defaults")
+		return &config.Config{}
+	}
+	if err := config.ApplyRuntime(&cfg.RuntimeConfig, hyperlane7683.ValidateRuleConfig, logutil.ValidateLogLevels); err != nil {
+		c.add(StatusFail, "config", "", err.Error(), "fix the runtime settings in SOLVER_CONFIG_FILE")
+		return cfg
+	}
+	c.add(StatusPass, "config", "", fmt.Sprintf("%d networks, %d registry tokens", len(config.Networks), len(config.ActiveRuntime().Tokens)), "")
+	return cfg
+}
+
+// checkDomains builds the domain registry the solver builds at startup
+func (c *checker) checkDomains(cfg *config.Config) {
+	if _, err := config.NewDomainRegistry(config.Networks, cfg.DomainMappings); err != nil {
+		c.add(StatusFail, "domains", "", err.Error(),
+			"give every network its own <NAME>_DOMAIN_ID and <NAME>_CHAIN_ID, and remove domainMappings entries contradicting them")
+		return
+	}
+	c.add(StatusPass, "domains", "", fmt.Sprintf("%d networks and %d custom mappings map domains to chain IDs one to one",
+		len(config.Networks), len(cfg.DomainMappings)), "")
+}
+
+// checkEVMKey validates the solver's EVM key and returns its address, nil when unusable
+func (c *checker) checkEVMKey() *common.Address {
+	keyEnv := envName("SOLVER_PRIVATE_KEY")
+	key := envutil.GetSolverPrivateKey()
+	if key == "" {
+		c.add(StatusFail, "keys", "EVM", keyEnv+" is not set", "set "+keyEnv+" to the solver's EVM private key")
+		return nil
+	}
+	pk, err := crypto.HexToECDSA(strings.TrimPrefix(key, "0x"))
+	if err != nil {
+		c.add(StatusFail, "keys", "EVM", fmt.Sprintf("%s is not a valid private key: %v", keyEnv, err), "use the 32-byte hex key, with or without 0x")
+		return nil
+	}
+	solver := crypto.PubkeyToAddress(pk.PublicKey)
+
+	pubEnv := envName("SOLVER_PUB_KEY")
+	switch pub := envutil.GetSolverPublicKey(); {
+	case pub == "":
+		c.add(StatusFail, "keys", "EVM", pubEnv+" is not set; balances and rules read it", "set "+pubEnv+" to "+solver.Hex())
+	case !common.IsHexAddress(pub) || common.HexToAddress(pub) != solver:
+		c.add(StatusFail, "keys", "EVM", fmt.Sprintf("%s %s is not the address of %s (%s)", pubEnv, pub, keyEnv, solver.Hex()), "set "+pubEnv+" to "+solver.Hex())
+	default:
+		c.add(StatusPass, "keys", "EVM", "solver address "+solver.Hex(), "")
+	}
+	return &solver
+}
+
+// checkStarknetKey validates the solver's Starknet key pair and returns its account, nil when unusable
+func (c *checker) checkStarknetKey() *felt.Felt {
+	privEnv, pubEnv, addrEnv := envName("STARKNET_SOLVER_PRIVATE_KEY"), envName("STARKNET_SOLVER_PUBLIC_KEY"), envName("STARKNET_SOLVER_ADDRESS")
+	priv, pub := envutil.GetStarknetSolverPrivateKey(), envutil.GetStarknetSolverPublicKey()
+	if priv == "" || pub == "" {
+		c.add(StatusFail, "keys", "Starknet", privEnv+" and "+pubEnv+" must both be set", "set the key pair of the solver's Starknet account")
+		return nil
+	}
+	privBI, ok := new(big.Int).SetString(priv, 0)
+	if !ok {
+		c.add(StatusFail, "keys", "Starknet", privEnv+" is not a number", "use the 0x-prefixed hex private key")
+		return nil
+	}
+	pubBI, ok := new(big.Int).SetString(pub, 0)
+	if !ok {
+		c.add(StatusFail, "keys", "Starknet", pubEnv+" is not a number", "use the 0x-prefixed hex public key")
+		return nil
+	}
+	if x, _ := curve.PrivateKeyToPoint(privBI); x.Cmp(pubBI) != 0 {
+		c.add(StatusFail, "keys", "Starknet", pubEnv+" is not the public key of "+privEnv, fmt.Sprintf("set %s to %#x", pubEnv, x))
+		return nil
+	}
+	account, err := utils.HexToFelt(envutil.GetStarknetSolverAddress())
+	if err != nil {
+		c.add(StatusFail, "keys", "Starknet", fmt.Sprintf("%s is not a Starknet address: %v", addrEnv, err), "set "+addrEnv+" to the solver's account address")
+		return nil
+	}
+	c.add(StatusPass, "keys", "Starknet", "solver account "+account.String(), "")
+	return account
+}
+
+// checkEVMNetwork checks the RPC, router and gas of an EVM network and returns its client, nil when
+// the RPC is unusable
+func (c *checker) checkEVMNetwork(ctx context.Context, network config.NetworkConfig, solver *common.Address) hyperlane7683.EVMClient {
+	rpcHint := "check " + envName(config.NetworkEnvPrefix(network.Name)+"_RPC_URL")
+	client, err := c.dialEVM(network.RPCURL)
+	if err != nil {
+		c.add(StatusFail, "rpc", network.Name, fmt.Sprintf("cannot connect to %s: %v", network.RPCURL, err), rpcHint)
+		return nil
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		c.add(StatusFail, "rpc", network.Name, fmt.Sprintf("%s does not answer: %v", network.RPCURL, err), rpcHint)
+		return nil
+	}
+	if config.VerifyEVMChainID(network, chainID) != nil {
+		c.add(StatusFail, "rpc", network.Name, fmt.Sprintf("node reports chain ID %s, configured %d", chainID, network.ChainID),
+			rpcHint+" or "+config.NetworkEnvPrefix(network.Name)+"_CHAIN_ID")
+		return nil
+	}
+	c.add(StatusPass, "rpc", network.Name, fmt.Sprintf("%s answers, chain ID %d", network.RPCURL, network.ChainID), "")
+
+	c.checkEVMRouter(ctx, client, network)
+	if solver != nil {
+		c.checkGas(network.Name, func() (*big.Int, error) { return client.BalanceAt(ctx, *solver, nil) }, "ETH")
+	}
+	return client
+}
+
+// checkEVMRouter checks the router has code and reports the configured Hyperlane domain
+func (c *checker) checkEVMRouter(ctx context.Context, client hyperlane7683.EVMClient, network config.NetworkConfig) {
+	router := network.HyperlaneAddress
+	hint := "set EVM_HYPERLANE_ADDRESS to the Hyperlane7683 deployment of this chain"
+	code, err := client.CodeAt(ctx, router, nil)
+	if err != nil {
+		c.add(StatusFail, "router", network.Name, fmt.Sprintf("cannot read the code at %s: %v", router.Hex(), err), "")
+		return
+	}
+	if len(code) == 0 {
+		c.add(StatusFail, "router", network.Name, "no contract at "+router.Hex(), hint)
+		return
+	}
+
+	caller, err := contracts.NewHyperlane7683Caller(router, client)
+	if err != nil {
+		c.add(StatusFail, "router", network.Name, err.Error(), "")
+		return
+	}
+	domain, err := caller.LocalDomain(&bind.CallOpts{Context: ctx})
+	switch {
+	case err != nil:
+		c.add(StatusWarn, "router", network.Name, fmt.Sprintf("%s has code but localDomain() failed: %v", router.Hex(), err), hint)
+	case uint64(domain) != network.HyperlaneDomain:
+		c.add(StatusFail, "router", network.Name, fmt.Sprintf("router at %s has domain %d, configured %d", router.Hex(), domain, network.HyperlaneDomain),
+			fmt.Sprintf("set %s_DOMAIN_ID to %d", config.NetworkEnvPrefix(network.Name), domain))
+	default:
+		c.add(StatusPass, "router", network.Name, fmt.Sprintf("Hyperlane7683 at %s, domain %d", router.Hex(), domain), "")
+	}
+}
+
+// checkStarknetNetwork checks the RPC, router, account and gas of a Starknet network and returns its
+// provider, nil when the RPC is unusable
+func (c *checker) checkStarknetNetwork(ctx context.Context, network config.NetworkConfig, account *felt.Felt) rpc.RPCProvider {
+	rpcHint := "check " + envName(config.NetworkEnvPrefix(network.Name)+"_RPC_URL")
+	provider, err := c.dialStarknet(network.RPCURL)
+	if err != nil {
+		c.add(StatusFail, "rpc", network.Name, fmt.Sprintf("cannot connect to %s: %v", network.RPCURL, err), rpcHint)
+		return nil
+	}
+	chainID, err := provider.ChainID(ctx)
+	if err != nil {
+		c.add(StatusFail, "rpc", network.Name, fmt.Sprintf("%s does not answer: %v", network.RPCURL, err), rpcHint)
+		return nil
+	}
+	if err := config.VerifyStarknetChainID(network, chainID); err != nil {
+		c.add(StatusFail, "rpc", network.Name, fmt.Sprintf("node reports chain ID %q, configured %d", chainID, network.ChainID),
+			rpcHint+", "+config.NetworkEnvPrefix(network.Name)+"_CHAIN_ID or "+config.NodeChainIDEnv(network.Name))
+		return nil
+	}
+	c.add(StatusPass, "rpc", network.Name, fmt.Sprintf("%s answers, chain ID %s", network.RPCURL, chainID), "")
+
+	routerEnv := config.StarknetHyperlaneAddressEnv(network.Name)
+	routerHint := "set " + routerEnv + " to the Hyperlane7683 deployment of this network"
+	if router, err := utils.HexToFelt(config.StarknetHyperlaneAddress(network.Name)); err != nil {
+		c.add(StatusFail, "router", network.Name, routerEnv+" is not set or not an address", routerHint)
+	} else if _, err := provider.ClassHashAt(ctx, rpc.WithBlockTag(rpc.BlockTagLatest), router); err != nil {
+		c.add(StatusFail, "router", network.Name, fmt.Sprintf("no contract at %s: %v", router, err), routerHint)
+	} else {
+		c.add(StatusPass, "router", network.Name, "Hyperlane7683 at "+router.String(), "")
+	}
+
+	if account == nil {
+		return provider
+	}
+	if _, err := provider.ClassHashAt(ctx, rpc.WithBlockTag(rpc.BlockTagLatest), account); err != nil {
+		c.add(StatusFail, "account", network.Name, fmt.Sprintf("solver account %s is not deployed: %v", account, err),
+			"deploy the account or fix "+envName("STARKNET_SOLVER_ADDRESS"))
+		return provider
+	}
+	c.add(StatusPass, "account", network.Name, "solver account "+account.String()+" is deployed", "")
+	c.checkGas(network.Name, func() (*big.Int, error) {
+		return starknetutil.ERC20Balance(provider, starknetSTRKAddress, account.String())
+	}, "STRK")
+	return provider
+}
+
+// checkGas fails when the solver cannot pay fees on a network
+func (c *checker) checkGas(network string, read func() (*big.Int, error), unit string) {
+	balance, err := read()
+	switch {
+	case err != nil:
+		c.add(StatusFail, "gas", network, "cannot read the solver's "+unit+" balance: "+err.Error(), "")
+	case balance.Sign() == 0:
+		c.add(StatusFail, "gas", network, "the solver has no "+unit+" to pay fees", "fund the solver address on "+network)
+	default:
+		c.add(StatusPass, "gas", network, amount.New(balance, nativeDecimals).String()+" "+unit, "")
+	}
+}
+
+// checkBalances reads the solver's registry token balances through the reachable networks
+func (c *checker) checkBalances(ctx context.Context, evmClients map[uint64]hyperlane7683.EVMClient, starknetClients map[uint64]rpc.RPCProvider) {
+	if len(config.ActiveRuntime().Tokens) == 0 {
+		c.add(StatusWarn, "balance", "", "the token registry is empty, token balances are not checked", "list tokens under \"tokens\" in SOLVER_CONFIG_FILE")
+		return
+	}
+
+	monitor := hyperlane7683.NewBalanceMonitor(
+		func(chainID uint64) (hyperlane7683.EVMClient, error) {
+			if client, ok := evmClients[chainID]; ok {
+				return client, nil
+			}
+			return nil, fmt.Errorf("no usable RPC for chain %d", chainID)
+		},
+		func(chainID uint64) (rpc.RPCProvider, error) {
+			if provider, ok := starknetClients[chainID]; ok {
+				return provider, nil
+			}
+			return nil, fmt.Errorf("no usable RPC for chain %d", chainID)
+		},
+	)
+	for _, balance := range monitor.Balances(ctx) {
+		switch {
+		case balance.Error != "":
+			c.add(StatusFail, "balance", balance.Network, balance.Symbol+": "+balance.Error, "")
+		case balance.Low:
+			c.add(StatusWarn, "balance", balance.Network, fmt.Sprintf("%s: %s is below its threshold of %s", balance.Symbol, balance.Balance, balance.Threshold),
+				"top up the solver's "+balance.Symbol+" on "+balance.Network)
+		default:
+			c.add(StatusPass, "balance", balance.Network, balance.Symbol+": "+balance.Balance, "")
+		}
+	}
+}
+
+// envName returns the variable actually read for key: LOCAL_<key> on devnet
+func envName(key string) string {
+	if envutil.IsDevnet() {
+		return "LOCAL_" + key
+	}
+	return key
+}
+
+// countStatus returns the number of results with status
+func countStatus(results []Result, status Status) int {
+	n := 0
+	for _, result := range results {
+		if result.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// writeTable prints one line per check, its hint below it, and a summary
+func writeTable(w io.Writer, results []Result) error {
+	icons := map[Status]string{StatusPass: "✅", StatusWarn: "⚠️ ", StatusFail: "❌"}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tCHECK\tNETWORK\tDETAIL")
+	for _, result := range results {
+		network := result.Network
+		if network == "" {
+			network = "-"
+		}
+		fmt.Fprintf(tw, "%s %s\t%s\t%s\t%s\n", icons[result.Status], result.Status, result.Check, network, result.Detail)
+		if result.Hint != "" {
+			fmt.Fprintf(tw, "\t\t\t↳ %s\n", result.Hint)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d passed, %d warnings, %d failed\n",
+		countStatus(results, StatusPass), countStatus(results, StatusWarn), countStatus(results, StatusFail))
+	return err
+}
+
+// writeJSON writes the results as an indented JSON array
+func writeJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
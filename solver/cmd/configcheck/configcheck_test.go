@@ -0,0 +1,289 @@
+package configcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFlags(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		opts, err := parseFlags(nil)
+		require.NoError(t, err)
+		assert.Equal(t, "table", opts.format)
+		assert.Equal(t, defaultCheckTimeout, opts.timeout)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseFlags([]string{"--format", "csv"})
+		assert.ErrorContains(t, err, "unsupported format")
+		_, err = parseFlags([]string{"--timeout", "-1s"})
+		assert.ErrorContains(t, err, "--timeout must be positive")
+		_, err = parseFlags([]string{"Base"})
+		assert.ErrorContains(t, err, "unexpected argument")
+	})
+
+	t.Run("unknown subcommand", func(t *testing.T) {
+		assert.ErrorContains(t, RunConfig([]string{"fix"}), "unknown config subcommand")
+		assert.ErrorContains(t, RunConfig(nil), "missing config subcommand")
+	})
+}
+
+// testChains are healthy in-memory chains for every configured network
+type testChains struct {
+	evm      map[string]*chainmock.EVMBackend // by network name
+	starknet *chainmock.StarknetBackend
+	solver   common.Address
+	account  *felt.Felt
+	router   common.Address
+}
+
+// setupChains configures the solver keys and routers and returns healthy chains for them
+func setupChains(t *testing.T) *testChains {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	solver := crypto.PubkeyToAddress(key.PublicKey)
+	router := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	starknetPriv := big.NewInt(0x1234)
+	starknetPub, _ := curve.PrivateKeyToPoint(starknetPriv)
+
+	t.Setenv("IS_DEVNET", "false")
+	t.Setenv("SOLVER_PRIVATE_KEY", hexutil.Encode(crypto.FromECDSA(key)))
+	t.Setenv("SOLVER_PUB_KEY", solver.Hex())
+	t.Setenv("EVM_HYPERLANE_ADDRESS", router.Hex())
+	t.Setenv("STARKNET_SOLVER_PRIVATE_KEY", fmt.Sprintf("%#x", starknetPriv))
+	t.Setenv("STARKNET_SOLVER_PUBLIC_KEY", fmt.Sprintf("%#x", starknetPub))
+	t.Setenv("STARKNET_SOLVER_ADDRESS", "0xabc")
+	t.Setenv("STARKNET_HYPERLANE_ADDRESS", "0x5678")
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	t.Cleanup(config.ResetNetworks)
+	t.Cleanup(func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) })
+
+	chains := &testChains{evm: make(map[string]*chainmock.EVMBackend), solver: solver, router: router}
+	for name, network := range config.Networks {
+		if config.IsStarknetNetwork(name) {
+			continue
+		}
+		backend := chainmock.NewEVMBackend(network.ChainID)
+		backend.HandleCall(router, chainmock.Selector("localDomain()"), uint256Result(new(big.Int).SetUint64(network.HyperlaneDomain)))
+		backend.SetBalance(solver, big.NewInt(2e18))
+		chains.evm[name] = backend
+	}
+
+	chains.account = new(felt.Felt).SetUint64(0xabc)
+	chains.starknet = chainmock.NewStarknetBackend()
+	chains.starknet.HandleCall(new(felt.Felt).SetUint64(0x5678), "fill", func(rpc.FunctionCall) ([]*felt.Felt, error) { return nil, nil })
+	chains.starknet.HandleCall(chains.account, "__execute__", func(rpc.FunctionCall) ([]*felt.Felt, error) { return nil, nil })
+	strk, err := utils.HexToFelt(starknetSTRKAddress)
+	require.NoError(t, err)
+	chains.starknet.HandleCall(strk, "balanceOf", func(rpc.FunctionCall) ([]*felt.Felt, error) {
+		return []*felt.Felt{new(felt.Felt).SetUint64(5e18), new(felt.Felt)}, nil
+	})
+	return chains
+}
+
+// checker returns a checker dialing the test chains and loading cfg
+func (c *testChains) checker(cfg *config.Config) *checker {
+	byURL := make(map[string]*chainmock.EVMBackend)
+	for name, backend := range c.evm {
+		byURL[config.Networks[name].RPCURL] = backend
+	}
+	return &checker{
+		loadConfig: func() (*config.Config, error) { return cfg, nil },
+		dialEVM: func(rpcURL string) (hyperlane7683.EVMClient, error) {
+			if backend, ok := byURL[rpcURL]; ok {
+				return backend, nil
+			}
+			return nil, fmt.Errorf("connection refused")
+		},
+		dialStarknet: func(string) (rpc.RPCProvider, error) { return c.starknet, nil },
+	}
+}
+
+// uint256Result answers a call with an ABI-encoded uint
+func uint256Result(value *big.Int) chainmock.EVMCallHandler {
+	return func(ethereum.CallMsg) ([]byte, error) { return common.LeftPadBytes(value.Bytes(), 32), nil }
+}
+
+// find returns the result of check on network
+func find(t *testing.T, results []Result, check, network string) Result {
+	t.Helper()
+	for _, result := range results {
+		if result.Check == check && result.Network == network {
+			return result
+		}
+	}
+	require.Failf(t, "missing result", "no %s check for %q in %+v", check, network, results)
+	return Result{}
+}
+
+func TestChecker(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("a healthy setup passes", func(t *testing.T) {
+		chains := setupChains(t)
+		results := chains.checker(&config.Config{}).run(ctx)
+
+		assert.Zero(t, countStatus(results, StatusFail), "%+v", results)
+		assert.Equal(t, "solver address "+chains.solver.Hex(), find(t, results, "keys", "EVM").Detail)
+		assert.Equal(t, StatusPass, find(t, results, "keys", "Starknet").Status)
+		assert.Contains(t, find(t, results, "router", "Base").Detail, "domain")
+		assert.Equal(t, "2 ETH", find(t, results, "gas", "Base").Detail)
+		assert.Equal(t, "5 STRK", find(t, results, "gas", "Starknet").Detail)
+		assert.Equal(t, StatusPass, find(t, results, "account", "Starknet").Status)
+		// Without a registry only the gas balances are checked
+		assert.Equal(t, StatusWarn, find(t, results, "balance", "").Status)
+	})
+
+	t.Run("failures come with hints", func(t *testing.T) {
+		chains := setupChains(t)
+		chains.evm["Base"] = chainmock.NewEVMBackend(1)
+		chains.evm["Optimism"] = chainmock.NewEVMBackend(config.Networks["Optimism"].ChainID)
+		chains.evm["Arbitrum"].HandleCall(chains.router, chainmock.Selector("localDomain()"), uint256Result(big.NewInt(77)))
+		chains.evm["Ethereum"].SetBalance(chains.solver, new(big.Int))
+		chains.starknet = chainmock.NewStarknetBackend()
+		t.Setenv("SOLVER_PUB_KEY", "0x00000000000000000000000000000000000000c3")
+
+		results := chains.checker(&config.Config{}).run(ctx)
+
+		rpcResult := find(t, results, "rpc", "Base")
+		assert.Equal(t, StatusFail, rpcResult.Status)
+		assert.Contains(t, rpcResult.Detail, "node reports chain ID 1")
+		assert.Contains(t, rpcResult.Hint, "BASE_RPC_URL")
+
+		router := find(t, results, "router", "Optimism")
+		assert.Equal(t, StatusFail, router.Status)
+		assert.Contains(t, router.Hint, "EVM_HYPERLANE_ADDRESS")
+
+		domain := find(t, results, "router", "Arbitrum")
+		assert.Equal(t, StatusFail, domain.Status)
+		assert.Equal(t, "set ARBITRUM_DOMAIN_ID to 77", domain.Hint)
+
+		assert.Equal(t, "fund the solver address on Ethereum", find(t, results, "gas", "Ethereum").Hint)
+		assert.Equal(t, "set SOLVER_PUB_KEY to "+chains.solver.Hex(), find(t, results, "keys", "EVM").Hint)
+		assert.Equal(t, StatusFail, find(t, results, "router", "Starknet").Status)
+		assert.Contains(t, find(t, results, "account", "Starknet").Hint, "STARKNET_SOLVER_ADDRESS")
+	})
+
+	t.Run("unreachable RPCs skip the network's other checks", func(t *testing.T) {
+		chains := setupChains(t)
+		delete(chains.evm, "Base")
+		results := chains.checker(&config.Config{}).run(ctx)
+
+		assert.Contains(t, find(t, results, "rpc", "Base").Detail, "connection refused")
+		for _, result := range results {
+			if result.Network == "Base" {
+				assert.Equal(t, "rpc", result.Check)
+			}
+		}
+	})
+
+	t.Run("mismatched Starknet key pair", func(t *testing.T) {
+		chains := setupChains(t)
+		t.Setenv("STARKNET_SOLVER_PUBLIC_KEY", "0x1")
+		results := chains.checker(&config.Config{}).run(ctx)
+
+		keys := find(t, results, "keys", "Starknet")
+		assert.Equal(t, StatusFail, keys.Status)
+		assert.Contains(t, keys.Hint, "set STARKNET_SOLVER_PUBLIC_KEY to 0x")
+		// Without an account the Starknet account and gas checks are skipped
+		for _, result := range results {
+			assert.False(t, result.Network == "Starknet" && (result.Check == "account" || result.Check == "gas"), "%+v", result)
+		}
+	})
+
+	t.Run("Starknet node on another chain", func(t *testing.T) {
+		chains := setupChains(t)
+		t.Setenv("STARKNET_CHAIN_ID", "358974494")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		results := chains.checker(&config.Config{}).run(ctx)
+
+		rpcResult := find(t, results, "rpc", "Starknet")
+		assert.Equal(t, StatusFail, rpcResult.Status)
+		assert.Contains(t, rpcResult.Detail, `node reports chain ID "SN_SEPOLIA", configured 358974494`)
+		assert.Contains(t, rpcResult.Hint, "STARKNET_NODE_CHAIN_ID")
+	})
+
+	t.Run("conflicting domain mappings", func(t *testing.T) {
+		chains := setupChains(t)
+		ethereum := config.Networks["Ethereum"]
+		cfg := &config.Config{DomainMappings: []config.DomainMapping{{Domain: uint32(ethereum.HyperlaneDomain), ChainID: 999}}}
+		results := chains.checker(cfg).run(ctx)
+
+		domains := find(t, results, "domains", "")
+		assert.Equal(t, StatusFail, domains.Status)
+		assert.Contains(t, domains.Detail, "cannot map to 999")
+	})
+
+	t.Run("a broken config file still checks the networks", func(t *testing.T) {
+		chains := setupChains(t)
+		c := chains.checker(nil)
+		c.loadConfig = func() (*config.Config, error) { return nil, errors.New("failed to parse config file solver.json") }
+		results := c.run(ctx)
+
+		assert.Equal(t, StatusFail, find(t, results, "config", "").Status)
+		assert.Equal(t, StatusPass, find(t, results, "rpc", "Ethereum").Status)
+	})
+
+	t.Run("registry balances", func(t *testing.T) {
+		chains := setupChains(t)
+		dog := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+		chains.evm["Base"].HandleCall(dog, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(50_000_000)))
+		delete(chains.evm, "Optimism")
+		cfg := &config.Config{RuntimeConfig: config.RuntimeConfig{Tokens: []config.TokenConfig{
+			{ChainID: config.BaseSepoliaChainID, Address: dog.Hex(), Symbol: "DOG", Decimals: 6, LowBalance: "100"},
+			{ChainID: config.OptimismSepoliaChainID, Address: dog.Hex(), Symbol: "DOG", Decimals: 6},
+		}}}
+		results := chains.checker(cfg).run(ctx)
+
+		low := find(t, results, "balance", "Base")
+		assert.Equal(t, StatusWarn, low.Status)
+		assert.Equal(t, "DOG: 50 is below its threshold of 100", low.Detail)
+		unreadable := find(t, results, "balance", "Optimism")
+		assert.Equal(t, StatusFail, unreadable.Status)
+		assert.Contains(t, unreadable.Detail, "no usable RPC")
+	})
+}
+
+func TestWriteResults(t *testing.T) {
+	results := []Result{
+		{Check: "config", Status: StatusPass, Detail: "5 networks, 0 registry tokens"},
+		{Check: "gas", Network: "Base", Status: StatusFail, Detail: "the solver has no ETH to pay fees", Hint: "fund the solver address on Base"},
+		{Check: "balance", Status: StatusWarn, Detail: "the token registry is empty"},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeTable(&buf, results))
+		out := buf.String()
+		assert.Contains(t, out, "❌ fail")
+		assert.Contains(t, out, "↳ fund the solver address on Base")
+		assert.Contains(t, out, "1 passed, 1 warnings, 1 failed")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeJSON(&buf, results[1:2]))
+		assert.JSONEq(t, `[{"check":"gas","network":"Base","status":"fail","detail":"the solver has no ETH to pay fees","hint":"fund the solver address on Base"}]`, buf.String())
+	})
+}
@@ -0,0 +1,488 @@
+package contracts
+
+// Contracts command - declares Cairo contracts on Starknet networks (see declare.go), releases the
+// Hyperlane7683 router to one in a single step and verifies the deployments (see verify.go)
+//
+// Usage:
+//
+//	solver contracts release --network NAME [--sierra FILE] [--casm FILE] [--artifacts-dir DIR]
+//	  [--state FILE] [--manifest-dir DIR] [--max-fee STRK] [--fee-multiplier N] [--timeout DURATION]
+//
+// The classes are found in the artifacts directory unless given as files (see pkg/artifacts).
+// release declares the Sierra/CASM classes, skipping the declare when the class hash computed from
+// the Sierra file is already declared on the network, deploys the class through the UDC and checks
+// the deployed contract reports that class hash. The router is then recorded in the deployment state
+// (its other fields, e.g. the tokens, are kept) and the release in a manifest of its own,
+// <manifest-dir>/<network>-<time>.json, and audited as contracts.release. The solver still reads the
+// router address from <NAME>_HYPERLANE_ADDRESS, printed at the end.
+//
+// Settings (NAME is the network's variable prefix, e.g. STARKNET or STARKNET_SEPOLIA):
+// - <NAME>_DEPLOYER_ADDRESS, <NAME>_DEPLOYER_PRIVATE_KEY, <NAME>_DEPLOYER_PUBLIC_KEY: the deploying
+//   account (default the STARKNET_DEPLOYER_* variables)
+// - <NAME>_PERMIT2_ADDRESS, <NAME>_MAILBOX_ADDRESS, <NAME>_HOOK_ADDRESS, <NAME>_ISM_ADDRESS: the
+//   constructor arguments, all required
+// - <NAME>_OWNER_ADDRESS: owner of the router (default the deployer)
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/staterebuild"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/artifacts"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/admin"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+const (
+	contractName          = "Hyperlane7683"
+	defaultStatePath      = "state/network_state/deployment-state.json"
+	defaultManifestDir    = "state/deployment/releases"
+	defaultReleaseTimeout = 10 * time.Minute
+	// outputFilePerms are the permissions of the manifests and summaries written
+	outputFilePerms = 0600
+)
+
+// options are the parsed flags of the release subcommand
+type options struct {
+	network     string
+	artifacts   artifacts.Options
+	state       string
+	manifestDir string
+	declare     starknetutil.DeclareOptions
+	timeout     time.Duration
+}
+
+// Release is the release manifest, everything a release declared, deployed and verified
+type Release struct {
+	Network             string    `json:"network"`
+	ChainID             uint64    `json:"chainId"`
+	Contract            string    `json:"contract"`
+	Sierra              string    `json:"sierra"`
+	Casm                string    `json:"casm"`
+	ClassHash           string    `json:"classHash"`
+	AlreadyDeclared     bool      `json:"alreadyDeclared"`
+	DeclareTxHash       string    `json:"declareTxHash,omitempty"`
+	DeclareMaxFee       string    `json:"declareMaxFee,omitempty"`
+	Deployer            string    `json:"deployer"`
+	Address             string    `json:"address"`
+	DeployTxHash        string    `json:"deployTxHash"`
+	Salt                string    `json:"salt"`
+	ConstructorCalldata []string  `json:"constructorCalldata"`
+	BlockNumber         uint64    `json:"blockNumber"`
+	ReleasedAt          time.Time `json:"releasedAt"`
+}
+
+// chain is the Starknet network a release goes to; tests replace it
+type chain interface {
+	ChainID(ctx context.Context) (string, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	// IsDeclared reports whether classHash is declared on the network
+	IsDeclared(ctx context.Context, classHash *felt.Felt) (bool, error)
+	// Declare declares the classes and waits for the declare to be accepted
+	Declare(ctx context.Context, casmClass *contracts.CasmClass, contractClass *contracts.ContractClass, opts starknetutil.DeclareOptions) (*starknetutil.DeclareResult, error)
+	// Deploy deploys classHash through the UDC and waits for the deployment to be accepted
+	Deploy(ctx context.Context, classHash *felt.Felt, calldata []*felt.Felt) (txHash, address, salt *felt.Felt, err error)
+	ClassHashAt(ctx context.Context, address *felt.Felt) (*felt.Felt, error)
+}
+
+// RunContracts dispatches contracts subcommands; args excludes the "contracts" command itself
+func RunContracts(args []string) error {
+	if len(args) == 0 {
+		printUsage()
+		return fmt.Errorf("missing contracts subcommand")
+	}
+
+	switch args[0] {
+	case "release":
+		return runRelease(args[1:])
+	case "declare":
+		return runDeclare(args[1:])
+	case "verify":
+		return runVerify(args[1:])
+	default:
+		printUsage()
+		return fmt.Errorf("unknown contracts subcommand: %s", args[0])
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage: solver contracts declare --network NAME [--manifest FILE] [--only NAMES] [--summary FILE] [--artifacts-dir DIR]")
+	fmt.Println("                                [--max-fee STRK] [--fee-multiplier N] [--timeout DURATION]")
+	fmt.Println("  Declares the classes of every contract of a manifest (default Hyperlane7683, MockERC20, MockMailbox)")
+	fmt.Println("       solver contracts release --network NAME [--sierra FILE] [--casm FILE] [--artifacts-dir DIR] [--state FILE]")
+	fmt.Println("                                [--manifest-dir DIR] [--max-fee STRK] [--fee-multiplier N] [--timeout DURATION]")
+	fmt.Println("  Declares (unless already declared), deploys and verifies Hyperlane7683 on a Starknet network,")
+	fmt.Println("  then records it in the deployment state and a release manifest")
+	fmt.Println("       solver contracts verify [--state FILE] [--format table|json] [--timeout DURATION]")
+	fmt.Println("  Checks the routers and recorded tokens of every network against the configuration")
+}
+
+func runRelease(args []string) error {
+	opts, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+	network, err := starknetNetwork(opts.network)
+	if err != nil {
+		return err
+	}
+
+	classes, err := artifacts.Load(contractName, opts.artifacts)
+	if err != nil {
+		return err
+	}
+
+	prefix := config.NetworkEnvPrefix(network.Name)
+	deployer, err := deployerAccount(network, prefix)
+	if err != nil {
+		return err
+	}
+	calldata, err := constructorCalldata(prefix, deployer.Address.String())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+	c := &starknetChain{client: starknetutil.NewAccountClient(deployer)}
+	release, err := run(ctx, c, network, classes, calldata, opts, os.Stdout)
+
+	txHash := ""
+	if release != nil {
+		txHash = release.DeployTxHash
+	}
+	if auditErr := admin.AuditLogFromEnv().RecordCommand("contracts.release", network.Name, txHash, err); auditErr != nil {
+		fmt.Printf("⚠️  %v\n", auditErr)
+	}
+	if err != nil {
+		if hint := starknetutil.DeclareFailureHint(err); hint != "" {
+			return fmt.Errorf("%w (%s)", err, hint)
+		}
+		return err
+	}
+	release.Deployer = deployer.Address.String()
+
+	if err := recordState(opts.state, release); err != nil {
+		return err
+	}
+	fmt.Printf("💾 %s recorded in %s\n", network.Name, opts.state)
+	manifest, err := writeManifest(opts.manifestDir, release)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("💾 Release manifest written to %s\n", manifest)
+	fmt.Printf("✅ %s released at %s; set %s=%s for the solver\n",
+		contractName, release.Address, config.StarknetHyperlaneAddressEnv(network.Name), release.Address)
+	return nil
+}
+
+func parseFlags(args []string) (options, error) {
+	opts := options{}
+	fs := flag.NewFlagSet("contracts release", flag.ContinueOnError)
+	fs.Usage = printUsage
+	fs.StringVar(&opts.network, "network", "", "Starknet network to release to")
+	fs.StringVar(&opts.artifacts.Sierra, "sierra", "", "Sierra class file (default: found in the artifacts directory)")
+	fs.StringVar(&opts.artifacts.Casm, "casm", "", "CASM class file (default: found in the artifacts directory)")
+	fs.StringVar(&opts.artifacts.Dir, "artifacts-dir", "", "directory of the compiled classes (default $"+artifacts.DirEnv+" or "+artifacts.DefaultDir+")")
+	fs.StringVar(&opts.state, "state", defaultStatePath, "deployment state file to record the router in")
+	fs.StringVar(&opts.manifestDir, "manifest-dir", defaultManifestDir, "directory of the release manifests")
+	parseMaxFee := declareFlags(fs, &opts.declare)
+	fs.DurationVar(&opts.timeout, "timeout", defaultReleaseTimeout, "time allowed for the whole release")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if opts.network == "" {
+		return opts, fmt.Errorf("--network is required")
+	}
+	if err := parseMaxFee(); err != nil {
+		return opts, err
+	}
+	if opts.timeout <= 0 {
+		return opts, fmt.Errorf("--timeout must be positive")
+	}
+	return opts, nil
+}
+
+// declareFlags registers the fee flags of the declaring subcommands into opts; the returned func
+// applies --max-fee once the flags are parsed
+func declareFlags(fs *flag.FlagSet, opts *starknetutil.DeclareOptions) func() error {
+	maxFee := fs.String("max-fee", "", "most a declare may cost, in STRK (default no cap)")
+	fs.Float64Var(&opts.Multiplier, "fee-multiplier", starknetutil.DefaultFeeMultiplier, "headroom over the estimated declare resources")
+	return func() error {
+		if *maxFee == "" {
+			return nil
+		}
+		units, err := amount.Parse(*maxFee, starknetutil.TokenDecimals)
+		if err != nil {
+			return fmt.Errorf("invalid --max-fee: %w", err)
+		}
+		opts.MaxFee = units.Units
+		return nil
+	}
+}
+
+// starknetNetwork loads the configuration and returns the named network, which must be a Starknet one
+func starknetNetwork(name string) (config.NetworkConfig, error) {
+	if _, err := config.LoadConfig(); err != nil {
+		return config.NetworkConfig{}, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	config.InitializeNetworks()
+
+	network, err := config.GetNetworkConfig(name)
+	if err != nil {
+		return network, err
+	}
+	if !config.IsStarknetNetwork(network.Name) {
+		return network, fmt.Errorf("%s is not a Starknet network, contracts are only declared on Starknet", network.Name)
+	}
+	return network, nil
+}
+
+// deployerAccount builds the deploying account from <prefix>_DEPLOYER_*, falling back to STARKNET_DEPLOYER_*
+func deployerAccount(network config.NetworkConfig, prefix string) (*account.Account, error) {
+	env := func(suffix string) string {
+		return envutil.GetEnvWithDefault(prefix+"_DEPLOYER_"+suffix, os.Getenv("STARKNET_DEPLOYER_"+suffix))
+	}
+	address, privateKey, publicKey := env("ADDRESS"), env("PRIVATE_KEY"), env("PUBLIC_KEY")
+	if address == "" || privateKey == "" || publicKey == "" {
+		return nil, fmt.Errorf("%s_DEPLOYER_ADDRESS, %s_DEPLOYER_PRIVATE_KEY and %s_DEPLOYER_PUBLIC_KEY must be set", prefix, prefix, prefix)
+	}
+
+	addressFelt, err := utils.HexToFelt(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deployer address: %w", err)
+	}
+	key, ok := new(big.Int).SetString(privateKey, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid deployer private key")
+	}
+	ks := account.NewMemKeystore()
+	ks.Put(publicKey, key)
+
+	provider, err := rpc.NewProvider(network.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", network.Name, err)
+	}
+	acct, err := account.NewAccount(provider, addressFelt, publicKey, ks, account.CairoV2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize the deployer account: %w", err)
+	}
+	return acct, nil
+}
+
+// constructorCalldata builds the Hyperlane7683 constructor arguments: permit2, mailbox, owner, hook, ism
+func constructorCalldata(prefix, deployer string) ([]*felt.Felt, error) {
+	owner := envutil.GetEnvWithDefault(prefix+"_OWNER_ADDRESS", deployer)
+	args := []struct{ name, value string }{
+		{prefix + "_PERMIT2_ADDRESS", os.Getenv(prefix + "_PERMIT2_ADDRESS")},
+		{prefix + "_MAILBOX_ADDRESS", os.Getenv(prefix + "_MAILBOX_ADDRESS")},
+		{prefix + "_OWNER_ADDRESS", owner},
+		{prefix + "_HOOK_ADDRESS", os.Getenv(prefix + "_HOOK_ADDRESS")},
+		{prefix + "_ISM_ADDRESS", os.Getenv(prefix + "_ISM_ADDRESS")},
+	}
+
+	var missing []string
+	calldata := make([]*felt.Felt, 0, len(args))
+	for _, arg := range args {
+		if arg.value == "" {
+			missing = append(missing, arg.name)
+			continue
+		}
+		value, err := utils.HexToFelt(arg.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", arg.name, arg.value, err)
+		}
+		calldata = append(calldata, value)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing constructor arguments: %s", strings.Join(missing, ", "))
+	}
+	return calldata, nil
+}
+
+// run declares, deploys and verifies the classes on c, returning the release
+func run(ctx context.Context, c chain, network config.NetworkConfig, classes *artifacts.Classes, calldata []*felt.Felt, opts options, out io.Writer) (*Release, error) {
+	if err := verifyChain(ctx, c, network); err != nil {
+		return nil, err
+	}
+
+	classHash := hash.ClassHash(classes.Sierra)
+	release := &Release{
+		Network:   network.Name,
+		ChainID:   network.ChainID,
+		Contract:  contractName,
+		Sierra:    classes.SierraSource,
+		Casm:      classes.CasmSource,
+		ClassHash: classHash.String(),
+	}
+	for _, arg := range calldata {
+		release.ConstructorCalldata = append(release.ConstructorCalldata, arg.String())
+	}
+
+	result, err := declareClass(ctx, c, network, classes, opts.declare, out)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		release.AlreadyDeclared = true
+	} else {
+		release.DeclareTxHash = result.Hash
+		release.DeclareMaxFee = amount.New(result.MaxFee, starknetutil.TokenDecimals).String()
+	}
+
+	fmt.Fprintf(out, "📤 Deploying %s through the UDC...\n", contractName)
+	txHash, address, salt, err := c.Deploy(ctx, classHash, calldata)
+	if txHash != nil {
+		release.DeployTxHash = txHash.String()
+	}
+	if err != nil {
+		return release, fmt.Errorf("deploy failed: %w", err)
+	}
+	release.Address, release.Salt = address.String(), salt.String()
+	fmt.Fprintf(out, "✅ Deployed at %s in %s\n", release.Address, release.DeployTxHash)
+
+	onChain, err := c.ClassHashAt(ctx, address)
+	if err != nil {
+		return release, fmt.Errorf("failed to read the class hash at %s: %w", address, err)
+	}
+	if !onChain.Equal(classHash) {
+		return release, fmt.Errorf("contract at %s has class hash %s, expected %s", address, onChain, classHash)
+	}
+	fmt.Fprintf(out, "🔍 Class hash verified on-chain\n")
+
+	if release.BlockNumber, err = c.BlockNumber(ctx); err != nil {
+		return release, fmt.Errorf("failed to read the block number: %w", err)
+	}
+	release.ReleasedAt = time.Now().UTC()
+	return release, nil
+}
+
+// verifyChain checks c is the network it is configured as
+func verifyChain(ctx context.Context, c chain, network config.NetworkConfig) error {
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: failed to read the chain ID: %w", network.Name, err)
+	}
+	return config.VerifyStarknetChainID(network, chainID)
+}
+
+// declareClass declares classes on c unless their class hash is already declared, returning nil
+// then; the declared class hash must be the one computed from the Sierra class
+func declareClass(ctx context.Context, c chain, network config.NetworkConfig, classes *artifacts.Classes, opts starknetutil.DeclareOptions, out io.Writer) (*starknetutil.DeclareResult, error) {
+	classHash := hash.ClassHash(classes.Sierra)
+	declared, err := c.IsDeclared(ctx, classHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up class %s: %w", classHash, err)
+	}
+	if declared {
+		fmt.Fprintf(out, "⏭️  Class %s is already declared on %s\n", classHash, network.Name)
+		return nil, nil
+	}
+
+	fmt.Fprintf(out, "📤 Declaring class %s on %s...\n", classHash, network.Name)
+	result, err := c.Declare(ctx, classes.Casm, classes.Sierra, opts)
+	if err != nil {
+		return nil, fmt.Errorf("declare failed: %w", err)
+	}
+	if result.ClassHash != classHash.String() {
+		return nil, fmt.Errorf("declared class hash %s, the sierra file hashes to %s", result.ClassHash, classHash)
+	}
+	fmt.Fprintf(out, "✅ Declared in %s (estimated %s STRK, max %s STRK)\n", result.Hash,
+		amount.New(result.EstimatedFee, starknetutil.TokenDecimals), amount.New(result.MaxFee, starknetutil.TokenDecimals))
+	return result, nil
+}
+
+// recordState sets the release's router in the deployment state, keeping the network's other fields
+func recordState(path string, release *Release) error {
+	state, err := staterebuild.ReadState(path)
+	if err != nil {
+		return err
+	}
+	deployment := state.Networks[release.Network]
+	deployment.ChainID = release.ChainID
+	deployment.HyperlaneAddress = release.Address
+	deployment.BlockNumber = release.BlockNumber
+	state.Networks[release.Network] = deployment
+	return staterebuild.WriteState(path, state)
+}
+
+// writeManifest writes the release to a new manifest in dir, returning its path
+func writeManifest(dir string, release *Release) (string, error) {
+	name := fmt.Sprintf("%s-%s.json", strings.ToLower(config.NetworkEnvPrefix(release.Network)), release.ReleasedAt.Format("20060102T150405Z"))
+	path := filepath.Join(dir, name)
+	return path, writeJSON(path, release)
+}
+
+// starknetChain releases through a deployer account
+type starknetChain struct {
+	client *starknetutil.Client
+}
+
+func (s *starknetChain) ChainID(ctx context.Context) (string, error) {
+	return s.client.Provider.ChainID(ctx)
+}
+
+func (s *starknetChain) BlockNumber(ctx context.Context) (uint64, error) {
+	return s.client.Provider.BlockNumber(ctx)
+}
+
+func (s *starknetChain) IsDeclared(ctx context.Context, classHash *felt.Felt) (bool, error) {
+	_, err := s.client.Provider.Class(ctx, rpc.WithBlockTag(rpc.BlockTagLatest), classHash)
+	var rpcErr *rpc.RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrClassHashNotFound.Code {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *starknetChain) Declare(ctx context.Context, casmClass *contracts.CasmClass, contractClass *contracts.ContractClass, opts starknetutil.DeclareOptions) (*starknetutil.DeclareResult, error) {
+	result, err := s.client.Declare(ctx, casmClass, contractClass, opts)
+	if err != nil {
+		return nil, err
+	}
+	txHash, err := utils.HexToFelt(result.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid declare hash %q: %w", result.Hash, err)
+	}
+	if _, err := s.client.WaitForAcceptance(ctx, txHash, starknetutil.DefaultBlockTime); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *starknetChain) Deploy(ctx context.Context, classHash *felt.Felt, calldata []*felt.Felt) (*felt.Felt, *felt.Felt, *felt.Felt, error) {
+	deployer := s.client.Account
+	resp, salt, err := deployer.DeployContractWithUDC(ctx, classHash, calldata, nil, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if _, err := s.client.WaitForAcceptance(ctx, resp.Hash, starknetutil.DefaultBlockTime); err != nil {
+		return resp.Hash, nil, nil, err
+	}
+	address := utils.PrecomputeAddressForUDC(classHash, salt, calldata, utils.UDCCairoV0, deployer.Address)
+	return resp.Hash, address, salt, nil
+}
+
+func (s *starknetChain) ClassHashAt(ctx context.Context, address *felt.Felt) (*felt.Felt, error) {
+	return s.client.Provider.ClassHashAt(ctx, rpc.WithBlockTag(rpc.BlockTagLatest), address)
+}
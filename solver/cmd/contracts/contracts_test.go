@@ -0,0 +1,235 @@
+package contracts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/staterebuild"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/artifacts"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChain records what a release sends
+type fakeChain struct {
+	chainID     string
+	declared    bool
+	declareErr  error
+	deployErr   error
+	classHashAt *felt.Felt // nil reports the deployed class hash
+
+	declares int
+	deployed *felt.Felt
+	calldata []*felt.Felt
+}
+
+func (f *fakeChain) ChainID(context.Context) (string, error) { return f.chainID, nil }
+
+func (f *fakeChain) BlockNumber(context.Context) (uint64, error) { return 42, nil }
+
+func (f *fakeChain) IsDeclared(context.Context, *felt.Felt) (bool, error) { return f.declared, nil }
+
+func (f *fakeChain) Declare(_ context.Context, _ *contracts.CasmClass, contractClass *contracts.ContractClass, _ starknetutil.DeclareOptions) (*starknetutil.DeclareResult, error) {
+	f.declares++
+	if f.declareErr != nil {
+		return nil, f.declareErr
+	}
+	return &starknetutil.DeclareResult{
+		Hash:         "0xdec",
+		ClassHash:    hash.ClassHash(contractClass).String(),
+		EstimatedFee: big.NewInt(1e18),
+		MaxFee:       big.NewInt(2e18),
+	}, nil
+}
+
+func (f *fakeChain) Deploy(_ context.Context, classHash *felt.Felt, calldata []*felt.Felt) (*felt.Felt, *felt.Felt, *felt.Felt, error) {
+	if f.deployErr != nil {
+		return new(felt.Felt).SetUint64(0xd1), nil, nil, f.deployErr
+	}
+	f.deployed, f.calldata = classHash, calldata
+	return new(felt.Felt).SetUint64(0xd1), new(felt.Felt).SetUint64(0xadd), new(felt.Felt).SetUint64(7), nil
+}
+
+func (f *fakeChain) ClassHashAt(context.Context, *felt.Felt) (*felt.Felt, error) {
+	if f.classHashAt != nil {
+		return f.classHashAt, nil
+	}
+	return f.deployed, nil
+}
+
+func TestParseFlags(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		opts, err := parseFlags([]string{"--network", "Starknet"})
+		require.NoError(t, err)
+		assert.Equal(t, "Starknet", opts.network)
+		assert.Equal(t, artifacts.Options{}, opts.artifacts)
+		assert.Equal(t, defaultStatePath, opts.state)
+		assert.Equal(t, defaultManifestDir, opts.manifestDir)
+		assert.Equal(t, starknetutil.DefaultFeeMultiplier, opts.declare.Multiplier)
+		assert.Nil(t, opts.declare.MaxFee)
+	})
+
+	t.Run("max fee in STRK", func(t *testing.T) {
+		opts, err := parseFlags([]string{"--network", "Starknet", "--max-fee", "2.5"})
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(2.5e18), opts.declare.MaxFee)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseFlags(nil)
+		assert.ErrorContains(t, err, "--network is required")
+		_, err = parseFlags([]string{"--network", "Starknet", "--max-fee", "lots"})
+		assert.ErrorContains(t, err, "invalid --max-fee")
+		_, err = parseFlags([]string{"--network", "Starknet", "--timeout", "0s"})
+		assert.ErrorContains(t, err, "--timeout must be positive")
+		_, err = parseFlags([]string{"--network", "Starknet", "extra"})
+		assert.ErrorContains(t, err, "unexpected argument")
+	})
+
+	t.Run("unknown subcommand", func(t *testing.T) {
+		assert.ErrorContains(t, RunContracts([]string{"deploy"}), "unknown contracts subcommand")
+		assert.ErrorContains(t, RunContracts(nil), "missing contracts subcommand")
+	})
+}
+
+func TestConstructorCalldata(t *testing.T) {
+	t.Setenv("STARKNET_SEPOLIA_PERMIT2_ADDRESS", "0x1")
+	t.Setenv("STARKNET_SEPOLIA_MAILBOX_ADDRESS", "0x2")
+	t.Setenv("STARKNET_SEPOLIA_HOOK_ADDRESS", "0x4")
+	t.Setenv("STARKNET_SEPOLIA_ISM_ADDRESS", "0x5")
+
+	calldata, err := constructorCalldata("STARKNET_SEPOLIA", "0x3")
+	require.NoError(t, err)
+	var values []string
+	for _, arg := range calldata {
+		values = append(values, arg.String())
+	}
+	assert.Equal(t, []string{"0x1", "0x2", "0x3", "0x4", "0x5"}, values, "permit2, mailbox, owner (the deployer), hook, ism")
+
+	t.Setenv("STARKNET_SEPOLIA_OWNER_ADDRESS", "0x9")
+	calldata, err = constructorCalldata("STARKNET_SEPOLIA", "0x3")
+	require.NoError(t, err)
+	assert.Equal(t, "0x9", calldata[2].String())
+
+	t.Setenv("STARKNET_SEPOLIA_MAILBOX_ADDRESS", "")
+	t.Setenv("STARKNET_SEPOLIA_ISM_ADDRESS", "")
+	_, err = constructorCalldata("STARKNET_SEPOLIA", "0x3")
+	assert.ErrorContains(t, err, "STARKNET_SEPOLIA_MAILBOX_ADDRESS, STARKNET_SEPOLIA_ISM_ADDRESS")
+
+	t.Setenv("STARKNET_SEPOLIA_MAILBOX_ADDRESS", "mailbox")
+	t.Setenv("STARKNET_SEPOLIA_ISM_ADDRESS", "0x5")
+	_, err = constructorCalldata("STARKNET_SEPOLIA", "0x3")
+	assert.ErrorContains(t, err, "invalid STARKNET_SEPOLIA_MAILBOX_ADDRESS")
+}
+
+func TestRun(t *testing.T) {
+	network := config.NetworkConfig{Name: "Starknet", ChainID: 23448594291968334}
+	classes := &artifacts.Classes{
+		Sierra:       &contracts.ContractClass{ContractClassVersion: "0.1.0"},
+		Casm:         &contracts.CasmClass{},
+		SierraSource: "class.json",
+		CasmSource:   "embedded:casm.json",
+	}
+	classHash := hash.ClassHash(classes.Sierra)
+	calldata := []*felt.Felt{new(felt.Felt).SetUint64(1)}
+	opts := options{}
+
+	t.Run("declares, deploys and verifies", func(t *testing.T) {
+		chain := &fakeChain{chainID: "SN_MAIN"}
+		release, err := run(context.Background(), chain, network, classes, calldata, opts, io.Discard)
+		require.NoError(t, err)
+		assert.Equal(t, 1, chain.declares)
+		assert.Equal(t, classHash, chain.deployed)
+		assert.Equal(t, calldata, chain.calldata)
+
+		assert.Equal(t, classHash.String(), release.ClassHash)
+		assert.False(t, release.AlreadyDeclared)
+		assert.Equal(t, "0xdec", release.DeclareTxHash)
+		assert.Equal(t, "2", release.DeclareMaxFee)
+		assert.Equal(t, "0xadd", release.Address)
+		assert.Equal(t, "0xd1", release.DeployTxHash)
+		assert.Equal(t, "0x7", release.Salt)
+		assert.Equal(t, []string{"0x1"}, release.ConstructorCalldata)
+		assert.Equal(t, uint64(42), release.BlockNumber)
+		assert.Equal(t, "class.json", release.Sierra)
+		assert.Equal(t, "embedded:casm.json", release.Casm)
+	})
+
+	t.Run("declared classes are not declared again", func(t *testing.T) {
+		chain := &fakeChain{chainID: "SN_MAIN", declared: true}
+		release, err := run(context.Background(), chain, network, classes, calldata, opts, io.Discard)
+		require.NoError(t, err)
+		assert.Zero(t, chain.declares)
+		assert.True(t, release.AlreadyDeclared)
+		assert.Empty(t, release.DeclareTxHash)
+	})
+
+	t.Run("wrong chain", func(t *testing.T) {
+		chain := &fakeChain{chainID: "SN_SEPOLIA"}
+		_, err := run(context.Background(), chain, network, classes, calldata, opts, io.Discard)
+		assert.ErrorContains(t, err, "chain ID")
+		assert.Zero(t, chain.declares)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		chain := &fakeChain{chainID: "SN_MAIN", declareErr: starknetutil.ErrFeeAboveCap}
+		_, err := run(context.Background(), chain, network, classes, calldata, opts, io.Discard)
+		assert.ErrorIs(t, err, starknetutil.ErrFeeAboveCap)
+
+		chain = &fakeChain{chainID: "SN_MAIN", deployErr: errors.New("reverted")}
+		release, err := run(context.Background(), chain, network, classes, calldata, opts, io.Discard)
+		assert.ErrorContains(t, err, "deploy failed: reverted")
+		assert.Equal(t, "0xd1", release.DeployTxHash, "the deploy hash is kept for the audit log")
+
+		chain = &fakeChain{chainID: "SN_MAIN", classHashAt: new(felt.Felt).SetUint64(0xbad)}
+		_, err = run(context.Background(), chain, network, classes, calldata, opts, io.Discard)
+		assert.ErrorContains(t, err, "has class hash 0xbad")
+	})
+}
+
+func TestRecordRelease(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "deployment-state.json")
+	require.NoError(t, staterebuild.WriteState(statePath, &staterebuild.DeploymentState{Networks: map[string]staterebuild.NetworkDeployment{
+		"Starknet": {ChainID: 1, HyperlaneAddress: "0xold", Tokens: map[string]string{"DOG": "0xd0"}, BlockNumber: 5},
+		"Base":     {ChainID: 84532, HyperlaneAddress: "0xb2", BlockNumber: 9},
+	}}))
+
+	release := &Release{
+		Network:     "Starknet Sepolia",
+		ChainID:     1001,
+		Address:     "0xadd",
+		BlockNumber: 42,
+		ReleasedAt:  time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, recordState(statePath, release))
+	release.Network = "Starknet"
+	require.NoError(t, recordState(statePath, release))
+
+	state, err := staterebuild.ReadState(statePath)
+	require.NoError(t, err)
+	assert.Equal(t, staterebuild.NetworkDeployment{ChainID: release.ChainID, HyperlaneAddress: "0xadd", Tokens: map[string]string{"DOG": "0xd0"}, BlockNumber: 42}, state.Networks["Starknet"])
+	assert.Equal(t, "0xadd", state.Networks["Starknet Sepolia"].HyperlaneAddress)
+	assert.Equal(t, "0xb2", state.Networks["Base"].HyperlaneAddress, "other networks are kept")
+
+	path, err := writeManifest(filepath.Join(dir, "releases"), release)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "releases", "starknet-20261015T120000Z.json"), path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var written Release
+	require.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, *release, written)
+}
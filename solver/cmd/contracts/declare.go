@@ -0,0 +1,329 @@
+package contracts
+
+// Declare subcommand - declares the classes of several Cairo contracts in one run
+//
+// Usage:
+//
+//	solver contracts declare --network NAME [--manifest FILE] [--only NAMES] [--summary FILE]
+//	  [--artifacts-dir DIR] [--max-fee STRK] [--fee-multiplier N] [--timeout DURATION]
+//
+// The contracts come from a manifest, {"contracts": [{"name": "MockERC20"}, ...]}, by default
+// Hyperlane7683, MockERC20 and MockMailbox; --only picks some of them by name. An entry may set its
+// "sierra" and "casm" files, else they are found in the artifacts directory. Classes of "optional"
+// entries that are not built are skipped (MockMailbox comes from the hyperlane mocks crate and is
+// only built when listed in the cairo package's build-external-contracts). "declarationFile" names
+// the state/deployment file the deploy helpers read the class hash from.
+//
+// Classes already declared are not declared again, and a failed declare does not stop the others.
+// The outcome of every contract is written to the summary, by default
+// state/deployment/declarations.json; the command fails when any declare failed.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/artifacts"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/starknet.go/hash"
+)
+
+const (
+	defaultSummaryPath    = "state/deployment/declarations.json"
+	defaultDeclarationDir = "state/deployment"
+	defaultDeclareTimeout = 10 * time.Minute
+)
+
+// Statuses of a contract in the declare summary
+const (
+	declareStatusDeclared = "declared"
+	declareStatusAlready  = "already_declared"
+	declareStatusSkipped  = "skipped"
+	declareStatusFailed   = "failed"
+)
+
+// ContractEntry is one contract of a declare manifest
+type ContractEntry struct {
+	Name            string `json:"name"`
+	Sierra          string `json:"sierra,omitempty"`
+	Casm            string `json:"casm,omitempty"`
+	Optional        bool   `json:"optional,omitempty"`
+	DeclarationFile string `json:"declarationFile,omitempty"`
+}
+
+// DeclareManifest lists the contracts to declare, in order
+type DeclareManifest struct {
+	Contracts []ContractEntry `json:"contracts"`
+}
+
+// defaultDeclareManifest is declared without --manifest; the declaration files are the ones of the
+// per-contract declare helpers
+var defaultDeclareManifest = DeclareManifest{Contracts: []ContractEntry{
+	{Name: "Hyperlane7683", DeclarationFile: "starknet-hyperlane7683-declaration.json"},
+	{Name: "MockERC20", DeclarationFile: "starknet-mock-erc20-declaration.json"},
+	{Name: "MockMailbox", Optional: true},
+}}
+
+// DeclareOutcome is what happened to one contract
+type DeclareOutcome struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	ClassHash string `json:"classHash,omitempty"`
+	TxHash    string `json:"txHash,omitempty"`
+	MaxFee    string `json:"maxFee,omitempty"`
+	Sierra    string `json:"sierra,omitempty"`
+	Casm      string `json:"casm,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// DeclareSummary is the summary file of a declare run
+type DeclareSummary struct {
+	Network    string           `json:"network"`
+	ChainID    uint64           `json:"chainId"`
+	DeclaredAt time.Time        `json:"declaredAt"`
+	Contracts  []DeclareOutcome `json:"contracts"`
+}
+
+// declareOptions are the parsed flags of the declare subcommand
+type declareOptions struct {
+	network      string
+	manifest     string
+	only         []string
+	summary      string
+	artifactsDir string
+	declare      starknetutil.DeclareOptions
+	timeout      time.Duration
+}
+
+func runDeclare(args []string) error {
+	opts, err := parseDeclareFlags(args)
+	if err != nil {
+		return err
+	}
+	manifest, err := loadDeclareManifest(opts.manifest, opts.only)
+	if err != nil {
+		return err
+	}
+	network, err := starknetNetwork(opts.network)
+	if err != nil {
+		return err
+	}
+	deployer, err := deployerAccount(network, config.NetworkEnvPrefix(network.Name))
+	if err != nil {
+		return err
+	}
+
+	c := &starknetChain{client: starknetutil.NewAccountClient(deployer)}
+	summary, err := declareAll(context.Background(), c, network, manifest, opts, os.Stdout)
+	if summary != nil {
+		if writeErr := writeJSON(opts.summary, summary); writeErr != nil {
+			return writeErr
+		}
+		fmt.Printf("💾 Summary written to %s\n", opts.summary)
+		writeDeclarationFiles(defaultDeclarationDir, manifest, summary, os.Stdout)
+	}
+	return err
+}
+
+func parseDeclareFlags(args []string) (declareOptions, error) {
+	var opts declareOptions
+	var only string
+	fs := flag.NewFlagSet("contracts declare", flag.ContinueOnError)
+	fs.Usage = printUsage
+	fs.StringVar(&opts.network, "network", "", "Starknet network to declare on")
+	fs.StringVar(&opts.manifest, "manifest", "", "contracts to declare (default Hyperlane7683, MockERC20 and MockMailbox)")
+	fs.StringVar(&only, "only", "", "comma-separated contracts of the manifest to declare (default all)")
+	fs.StringVar(&opts.summary, "summary", defaultSummaryPath, "summary file to write")
+	fs.StringVar(&opts.artifactsDir, "artifacts-dir", "", "directory of the compiled classes (default $"+artifacts.DirEnv+" or "+artifacts.DefaultDir+")")
+	parseMaxFee := declareFlags(fs, &opts.declare)
+	fs.DurationVar(&opts.timeout, "timeout", defaultDeclareTimeout, "time allowed per contract")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if opts.network == "" {
+		return opts, fmt.Errorf("--network is required")
+	}
+	if err := parseMaxFee(); err != nil {
+		return opts, err
+	}
+	if opts.summary == "" {
+		return opts, fmt.Errorf("--summary must not be empty")
+	}
+	if opts.timeout <= 0 {
+		return opts, fmt.Errorf("--timeout must be positive")
+	}
+	for _, name := range strings.Split(only, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			opts.only = append(opts.only, name)
+		}
+	}
+	return opts, nil
+}
+
+// loadDeclareManifest reads the manifest at path, the default one when path is empty, keeping only
+// the contracts named in only when it is set
+func loadDeclareManifest(path string, only []string) (DeclareManifest, error) {
+	manifest := defaultDeclareManifest
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return manifest, fmt.Errorf("failed to read manifest %s: %w", path, err)
+		}
+		manifest = DeclareManifest{}
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return manifest, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, entry := range manifest.Contracts {
+		if entry.Name == "" {
+			return manifest, fmt.Errorf("manifest contract %d has no name", i)
+		}
+		if seen[strings.ToLower(entry.Name)] {
+			return manifest, fmt.Errorf("manifest lists %s twice", entry.Name)
+		}
+		seen[strings.ToLower(entry.Name)] = true
+	}
+	if len(only) == 0 {
+		return manifest, nil
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range only {
+		if !seen[strings.ToLower(name)] {
+			return manifest, fmt.Errorf("--only names %s, which is not in the manifest", name)
+		}
+		wanted[strings.ToLower(name)] = true
+	}
+	var picked []ContractEntry
+	for _, entry := range manifest.Contracts {
+		if wanted[strings.ToLower(entry.Name)] {
+			picked = append(picked, entry)
+		}
+	}
+	return DeclareManifest{Contracts: picked}, nil
+}
+
+// declareAll declares the contracts of the manifest one after the other on c; the summary is
+// returned with an error when any declare failed
+func declareAll(ctx context.Context, c chain, network config.NetworkConfig, manifest DeclareManifest, opts declareOptions, out io.Writer) (*DeclareSummary, error) {
+	verifyCtx, cancel := context.WithTimeout(ctx, opts.timeout)
+	err := verifyChain(verifyCtx, c, network)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &DeclareSummary{Network: network.Name, ChainID: network.ChainID, DeclaredAt: time.Now().UTC()}
+	var failed []string
+	for i, entry := range manifest.Contracts {
+		fmt.Fprintf(out, "📋 [%d/%d] %s\n", i+1, len(manifest.Contracts), entry.Name)
+		outcome := declareEntry(ctx, c, network, entry, opts, out)
+		if outcome.Status == declareStatusFailed {
+			fmt.Fprintf(out, "   ❌ %s\n", outcome.Error)
+			failed = append(failed, entry.Name)
+		}
+		summary.Contracts = append(summary.Contracts, outcome)
+	}
+
+	counts := make(map[string]int)
+	for _, outcome := range summary.Contracts {
+		counts[outcome.Status]++
+	}
+	fmt.Fprintf(out, "📊 %d declared, %d already declared, %d skipped, %d failed\n",
+		counts[declareStatusDeclared], counts[declareStatusAlready], counts[declareStatusSkipped], counts[declareStatusFailed])
+	if len(failed) > 0 {
+		return summary, fmt.Errorf("%d of %d declares failed: %s", len(failed), len(manifest.Contracts), strings.Join(failed, ", "))
+	}
+	return summary, nil
+}
+
+// declareEntry loads and declares one contract
+func declareEntry(ctx context.Context, c chain, network config.NetworkConfig, entry ContractEntry, opts declareOptions, out io.Writer) DeclareOutcome {
+	outcome := DeclareOutcome{Name: entry.Name}
+	classes, err := artifacts.Load(entry.Name, artifacts.Options{Sierra: entry.Sierra, Casm: entry.Casm, Dir: opts.artifactsDir})
+	if entry.Optional && errors.Is(err, artifacts.ErrNotFound) {
+		fmt.Fprintf(out, "   ⏭️  Classes not built, skipping\n")
+		outcome.Status = declareStatusSkipped
+		return outcome
+	}
+	if err != nil {
+		outcome.Status, outcome.Error = declareStatusFailed, err.Error()
+		return outcome
+	}
+	outcome.Sierra, outcome.Casm = classes.SierraSource, classes.CasmSource
+	outcome.ClassHash = hash.ClassHash(classes.Sierra).String()
+
+	declareCtx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+	result, err := declareClass(declareCtx, c, network, classes, opts.declare, out)
+	switch {
+	case err != nil:
+		outcome.Status, outcome.Error = declareStatusFailed, err.Error()
+		if hint := starknetutil.DeclareFailureHint(err); hint != "" {
+			outcome.Error += " (" + hint + ")"
+		}
+	case result == nil:
+		outcome.Status = declareStatusAlready
+	default:
+		outcome.Status = declareStatusDeclared
+		outcome.TxHash = result.Hash
+		outcome.MaxFee = amount.New(result.MaxFee, starknetutil.TokenDecimals).String()
+	}
+	return outcome
+}
+
+// writeDeclarationFiles writes the declaration file of each declared contract of the manifest that
+// names one, in the format of the per-contract declare helpers
+func writeDeclarationFiles(dir string, manifest DeclareManifest, summary *DeclareSummary, out io.Writer) {
+	for _, outcome := range summary.Contracts {
+		if outcome.Status != declareStatusDeclared && outcome.Status != declareStatusAlready {
+			continue
+		}
+		for _, entry := range manifest.Contracts {
+			if entry.Name != outcome.Name || entry.DeclarationFile == "" {
+				continue
+			}
+			path := filepath.Join(dir, entry.DeclarationFile)
+			info := map[string]string{
+				"networkName":     summary.Network,
+				"classHash":       outcome.ClassHash,
+				"transactionHash": outcome.TxHash,
+				"declarationTime": summary.DeclaredAt.Format(time.RFC3339),
+			}
+			if err := writeJSON(path, info); err != nil {
+				fmt.Fprintf(out, "⚠️  %v\n", err)
+				continue
+			}
+			fmt.Fprintf(out, "💾 %s class hash saved to %s\n", outcome.Name, path)
+		}
+	}
+}
+
+// writeJSON writes value as indented JSON to path, creating its directory
+func writeJSON(path string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), outputFilePerms); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
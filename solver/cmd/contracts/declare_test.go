@@ -0,0 +1,146 @@
+package contracts
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSierra = `{"sierra_program": [], "contract_class_version": "0.1.0", "abi": "[]"}`
+	testCasm   = `{"prime": "0x800000000000011000000000000000000000000000000000000000000000001", "compiler_version": "2.11.4",
+		"bytecode": [], "hints": [], "entry_points_by_type": {"CONSTRUCTOR": [], "EXTERNAL": [], "L1_HANDLER": []}}`
+)
+
+// writeArtifacts writes classes of the named contracts to a new artifacts directory
+func writeArtifacts(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "oif_starknet_"+name+".contract_class.json"), []byte(testSierra), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "oif_starknet_"+name+".compiled_contract_class.json"), []byte(testCasm), 0o600))
+	}
+	return dir
+}
+
+func TestParseDeclareFlags(t *testing.T) {
+	opts, err := parseDeclareFlags([]string{"--network", "Starknet", "--only", "MockERC20, Hyperlane7683", "--max-fee", "1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MockERC20", "Hyperlane7683"}, opts.only)
+	assert.Equal(t, defaultSummaryPath, opts.summary)
+	assert.Equal(t, "1000000000000000000", opts.declare.MaxFee.String())
+
+	_, err = parseDeclareFlags(nil)
+	assert.ErrorContains(t, err, "--network is required")
+	_, err = parseDeclareFlags([]string{"--network", "Starknet", "--summary", ""})
+	assert.ErrorContains(t, err, "--summary must not be empty")
+}
+
+func TestLoadDeclareManifest(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		manifest, err := loadDeclareManifest("", nil)
+		require.NoError(t, err)
+		assert.Equal(t, defaultDeclareManifest, manifest)
+
+		manifest, err = loadDeclareManifest("", []string{"mockerc20", "Hyperlane7683", "MockERC20"})
+		require.NoError(t, err)
+		require.Len(t, manifest.Contracts, 2, "in manifest order, once each")
+		assert.Equal(t, "Hyperlane7683", manifest.Contracts[0].Name)
+		assert.Equal(t, "MockERC20", manifest.Contracts[1].Name)
+
+		_, err = loadDeclareManifest("", []string{"Permit2"})
+		assert.ErrorContains(t, err, "--only names Permit2")
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "contracts.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"contracts": [{"name": "Token", "sierra": "token.json", "optional": true}]}`), 0o600))
+		manifest, err := loadDeclareManifest(path, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []ContractEntry{{Name: "Token", Sierra: "token.json", Optional: true}}, manifest.Contracts)
+
+		require.NoError(t, os.WriteFile(path, []byte(`{"contracts": [{"name": "Token"}, {"name": "token"}]}`), 0o600))
+		_, err = loadDeclareManifest(path, nil)
+		assert.ErrorContains(t, err, "twice")
+
+		require.NoError(t, os.WriteFile(path, []byte(`{"contracts": [{"sierra": "token.json"}]}`), 0o600))
+		_, err = loadDeclareManifest(path, nil)
+		assert.ErrorContains(t, err, "has no name")
+	})
+}
+
+func TestDeclareAll(t *testing.T) {
+	network := config.NetworkConfig{Name: "Starknet", ChainID: 23448594291968334}
+
+	t.Run("declares each contract and skips optional ones not built", func(t *testing.T) {
+		opts := declareOptions{artifactsDir: writeArtifacts(t, "Hyperlane7683", "MockERC20"), timeout: defaultDeclareTimeout}
+		chain := &fakeChain{chainID: "SN_MAIN"}
+		summary, err := declareAll(context.Background(), chain, network, defaultDeclareManifest, opts, io.Discard)
+		require.NoError(t, err)
+		assert.Equal(t, 2, chain.declares)
+
+		require.Len(t, summary.Contracts, 3)
+		assert.Equal(t, "Starknet", summary.Network)
+		for _, outcome := range summary.Contracts[:2] {
+			assert.Equal(t, declareStatusDeclared, outcome.Status, outcome.Name)
+			assert.Equal(t, "0xdec", outcome.TxHash)
+			assert.Equal(t, "2", outcome.MaxFee)
+			assert.NotEmpty(t, outcome.ClassHash)
+		}
+		assert.Equal(t, DeclareOutcome{Name: "MockMailbox", Status: declareStatusSkipped}, summary.Contracts[2])
+	})
+
+	t.Run("declared classes and failures", func(t *testing.T) {
+		opts := declareOptions{artifactsDir: writeArtifacts(t, "Hyperlane7683"), timeout: defaultDeclareTimeout}
+		manifest := DeclareManifest{Contracts: []ContractEntry{{Name: "Hyperlane7683"}, {Name: "MockERC20"}}}
+
+		summary, err := declareAll(context.Background(), &fakeChain{chainID: "SN_MAIN", declared: true}, network, manifest, opts, io.Discard)
+		assert.ErrorContains(t, err, "1 of 2 declares failed: MockERC20", "required classes must be built")
+		assert.Equal(t, declareStatusAlready, summary.Contracts[0].Status)
+		assert.Equal(t, declareStatusFailed, summary.Contracts[1].Status)
+		assert.Contains(t, summary.Contracts[1].Error, "scarb build")
+
+		chain := &fakeChain{chainID: "SN_MAIN", declareErr: starknetutil.ErrFeeAboveCap}
+		summary, err = declareAll(context.Background(), chain, network, manifest, opts, io.Discard)
+		assert.ErrorContains(t, err, "2 of 2 declares failed")
+		assert.Contains(t, summary.Contracts[0].Error, "--max-fee", "failures carry their hint")
+	})
+
+	t.Run("wrong chain", func(t *testing.T) {
+		opts := declareOptions{timeout: defaultDeclareTimeout}
+		summary, err := declareAll(context.Background(), &fakeChain{chainID: "SN_SEPOLIA"}, network, defaultDeclareManifest, opts, io.Discard)
+		assert.ErrorContains(t, err, "chain ID")
+		assert.Nil(t, summary)
+	})
+}
+
+func TestWriteDeclarationFiles(t *testing.T) {
+	dir := t.TempDir()
+	summary := &DeclareSummary{Network: "Starknet", Contracts: []DeclareOutcome{
+		{Name: "Hyperlane7683", Status: declareStatusAlready, ClassHash: "0x1"},
+		{Name: "MockERC20", Status: declareStatusFailed},
+		{Name: "Token", Status: declareStatusDeclared, ClassHash: "0x3", TxHash: "0xdec"},
+	}}
+	manifest := DeclareManifest{Contracts: append(defaultDeclareManifest.Contracts, ContractEntry{Name: "Token"})}
+	writeDeclarationFiles(dir, manifest, summary, io.Discard)
+
+	data, err := os.ReadFile(filepath.Join(dir, "starknet-hyperlane7683-declaration.json"))
+	require.NoError(t, err)
+	var info map[string]string
+	require.NoError(t, json.Unmarshal(data, &info))
+	assert.Equal(t, "0x1", info["classHash"])
+	assert.Equal(t, "Starknet", info["networkName"])
+
+	assert.NoFileExists(t, filepath.Join(dir, "starknet-mock-erc20-declaration.json"), "failed declares are not recorded")
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "contracts without a declaration file only go to the summary")
+}
@@ -0,0 +1,150 @@
+package contracts
+
+// Verify subcommand - checks the deployed contracts of every network against the configuration
+//
+// Usage:
+//
+//	solver contracts verify [--state FILE] [--format table|json] [--timeout DURATION]
+//
+// Every router must hold code (a class on Starknet) and report the configured Hyperlane domain, and
+// its mailbox and owner must be <NAME>_MAILBOX_ADDRESS and <NAME>_OWNER_ADDRESS when those are set.
+// The routers and tokens recorded in the deployment state and the registry tokens must hold code,
+// and recorded routers must be the configured ones. The command fails on any drift; the solver runs
+// the same checks on its routers and registry tokens at startup with STRICT_STARTUP=true.
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/cmd/cliout"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/staterebuild"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+)
+
+const defaultVerifyTimeout = 2 * time.Minute
+
+// verifyOptions are the parsed flags of the verify subcommand
+type verifyOptions struct {
+	state   string
+	format  string
+	timeout time.Duration
+}
+
+func runVerify(args []string) error {
+	opts, err := parseVerifyFlags(args)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	config.InitializeNetworks()
+	if err := config.ApplyRuntime(&cfg.RuntimeConfig, hyperlane7683.ValidateRuleConfig, logutil.ValidateLogLevels); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	state, err := staterebuild.ReadState(opts.state)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+	recorded := append(stateContracts(state), hyperlane7683.RegistryContracts()...)
+	checks := hyperlane7683.NewDeploymentVerifier(nil, nil).Verify(ctx, recorded)
+
+	if opts.format == "json" {
+		enc := json.NewEncoder(cliout.Stdout())
+		enc.SetIndent("", "  ")
+		err = enc.Encode(checks)
+	} else {
+		err = writeVerifyTable(cliout.Stdout(), checks)
+	}
+	if err != nil {
+		return err
+	}
+	return hyperlane7683.DeploymentDrift(checks)
+}
+
+func parseVerifyFlags(args []string) (verifyOptions, error) {
+	var opts verifyOptions
+	fs := flag.NewFlagSet("contracts verify", flag.ContinueOnError)
+	fs.Usage = printUsage
+	fs.StringVar(&opts.state, "state", defaultStatePath, "deployment state whose routers and tokens are checked")
+	fs.StringVar(&opts.format, "format", cliout.DefaultFormat("table"), "output format: table or json")
+	fs.DurationVar(&opts.timeout, "timeout", defaultVerifyTimeout, "time allowed for all RPC checks")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if opts.format != "table" && opts.format != "json" {
+		return opts, fmt.Errorf("unsupported format %q (use table or json)", opts.format)
+	}
+	if opts.timeout <= 0 {
+		return opts, fmt.Errorf("--timeout must be positive")
+	}
+	return opts, nil
+}
+
+// stateContracts returns the routers and tokens of the deployment state, by network and symbol
+func stateContracts(state *staterebuild.DeploymentState) []hyperlane7683.RecordedContract {
+	names := make([]string, 0, len(state.Networks))
+	for name := range state.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var recorded []hyperlane7683.RecordedContract
+	for _, name := range names {
+		deployment := state.Networks[name]
+		if deployment.HyperlaneAddress != "" {
+			recorded = append(recorded, hyperlane7683.RecordedContract{Network: name, Name: contractName, Address: deployment.HyperlaneAddress})
+		}
+		tokens := make(map[string]string, len(deployment.Tokens)+1)
+		for symbol, address := range deployment.Tokens {
+			tokens[symbol] = address
+		}
+		if _, ok := tokens["DOG"]; !ok && deployment.DogCoinAddress != "" {
+			tokens["DOG"] = deployment.DogCoinAddress
+		}
+		symbols := make([]string, 0, len(tokens))
+		for symbol := range tokens {
+			symbols = append(symbols, symbol)
+		}
+		sort.Strings(symbols)
+		for _, symbol := range symbols {
+			recorded = append(recorded, hyperlane7683.RecordedContract{Network: name, Name: symbol, Address: tokens[symbol]})
+		}
+	}
+	return recorded
+}
+
+// writeVerifyTable prints one line per check and a summary
+func writeVerifyTable(w io.Writer, checks []hyperlane7683.DeploymentCheck) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tNETWORK\tCONTRACT\tCHECK\tADDRESS\tDETAIL")
+	failed := 0
+	for _, check := range checks {
+		status := "✅ ok"
+		if !check.OK {
+			status = "❌ drift"
+			failed++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", status, check.Network, check.Contract, check.Check, check.Address, check.Detail)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d checks, %d failed\n", len(checks), failed)
+	return err
+}
@@ -0,0 +1,56 @@
+package contracts
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/cmd/staterebuild"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVerifyFlags(t *testing.T) {
+	opts, err := parseVerifyFlags(nil)
+	require.NoError(t, err)
+	assert.Equal(t, verifyOptions{state: defaultStatePath, format: "table", timeout: defaultVerifyTimeout}, opts)
+
+	opts, err = parseVerifyFlags([]string{"--state", "state.json", "--format", "json"})
+	require.NoError(t, err)
+	assert.Equal(t, "state.json", opts.state)
+
+	_, err = parseVerifyFlags([]string{"--format", "csv"})
+	assert.ErrorContains(t, err, "unsupported format")
+	_, err = parseVerifyFlags([]string{"--timeout", "0s"})
+	assert.ErrorContains(t, err, "--timeout must be positive")
+	_, err = parseVerifyFlags([]string{"Base"})
+	assert.ErrorContains(t, err, "unexpected argument")
+}
+
+func TestStateContracts(t *testing.T) {
+	state := &staterebuild.DeploymentState{Networks: map[string]staterebuild.NetworkDeployment{
+		"Starknet": {HyperlaneAddress: "0x7683", Tokens: map[string]string{"USDC": "0x2", "DOG": "0x1"}},
+		"Base":     {HyperlaneAddress: "0xb2", DogCoinAddress: "0xa1"},
+		"Optimism": {DogCoinAddress: "0xa1", Tokens: map[string]string{"DOG": "0xa3"}},
+	}}
+	assert.Equal(t, []hyperlane7683.RecordedContract{
+		{Network: "Base", Name: "Hyperlane7683", Address: "0xb2"},
+		{Network: "Base", Name: "DOG", Address: "0xa1"},
+		{Network: "Optimism", Name: "DOG", Address: "0xa3"},
+		{Network: "Starknet", Name: "Hyperlane7683", Address: "0x7683"},
+		{Network: "Starknet", Name: "DOG", Address: "0x1"},
+		{Network: "Starknet", Name: "USDC", Address: "0x2"},
+	}, stateContracts(state))
+}
+
+func TestWriteVerifyTable(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, writeVerifyTable(&out, []hyperlane7683.DeploymentCheck{
+		{Network: "Base", Contract: "Hyperlane7683", Address: "0xb2", Check: "domain", OK: true, Detail: "domain 84532"},
+		{Network: "Base", Contract: "DOG", Address: "0xa1", Check: "code", Detail: "no contract"},
+	}))
+	assert.Contains(t, out.String(), "✅ ok")
+	assert.Contains(t, out.String(), "❌ drift")
+	assert.Contains(t, out.String(), "no contract")
+	assert.Contains(t, out.String(), "2 checks, 1 failed")
+}
@@ -0,0 +1,333 @@
+package devnet
+
+// Devnet command - local fork environment for development and integration tests
+//
+// Usage:
+//
+//	solver devnet up [--skip-setup] [--deploy-tokens] [--env-out FILE]
+//	solver devnet status
+//	solver devnet down
+//
+// `up` launches the four Anvil forks and starknet-devnet, waits until every node answers
+// its chain ID call, writes a ready-to-use env file pointing the solver at the forks and
+// runs the setup tools against them (register the Starknet domain on the EVM routers,
+// fund Alice and the solver). Hyperlane7683 and the DogCoin tokens already exist at the
+// default fork blocks; --deploy-tokens additionally deploys fresh MockERC20s with Forge.
+// Node PIDs are kept in the devnet state file so `down` can stop them. `status --json` prints
+// the health of every node.
+//
+// Settings:
+// - DEVNET_<NETWORK>_PORT: local port (defaults 8545-8548, Starknet 5050)
+// - DEVNET_<NETWORK>_FORK_BLOCK: fork block (defaults to the LOCAL start blocks, 0 = latest)
+// - DEVNET_<NETWORK>_FORK_URL: upstream RPC (defaults to <NETWORK>_RPC_URL, then a public RPC)
+// - DEVNET_ANVIL_BIN / DEVNET_STARKNET_BIN: node binaries (default anvil / starknet-devnet)
+// - DEVNET_STATE_DIR: PID state and node logs (default state/devnet)
+// - DEVNET_STARTUP_TIMEOUT_SECONDS: health-check timeout (default 120)
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/cmd/cliout"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/joho/godotenv"
+)
+
+const (
+	defaultStateDir       = "state/devnet"
+	stateFileName         = "devnet.json"
+	defaultEnvOut         = ".env.devnet"
+	defaultStartupTimeout = 120
+	healthPollInterval    = time.Second
+)
+
+// devnetState is the on-disk record of a running devnet
+type devnetState struct {
+	StartedAt time.Time  `json:"startedAt"`
+	EnvFile   string     `json:"envFile"`
+	Nodes     []nodeSpec `json:"nodes"`
+}
+
+// RunDevnet dispatches devnet subcommands; args excludes the "devnet" command itself
+func RunDevnet(args []string) error {
+	if len(args) == 0 {
+		printUsage()
+		return fmt.Errorf("missing devnet subcommand")
+	}
+
+	// Fork URLs, chain IDs and overrides come from .env when present
+	_ = godotenv.Load()
+
+	switch args[0] {
+	case "up":
+		return runUp(args[1:])
+	case "down":
+		return runDown()
+	case "status":
+		return runStatus()
+	default:
+		printUsage()
+		return fmt.Errorf("unknown devnet subcommand: %s", args[0])
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage: solver devnet <up|status|down> [options]")
+	fmt.Println("  up [--skip-setup] [--deploy-tokens] [--env-out FILE]   Start and set up the local forks")
+	fmt.Println("  status                                                 Health-check the running forks")
+	fmt.Println("  down                                                   Stop the forks started by `up`")
+}
+
+// upOptions are the parsed flags of `devnet up`
+type upOptions struct {
+	skipSetup    bool
+	deployTokens bool
+	envOut       string
+}
+
+func parseUpFlags(args []string) (upOptions, error) {
+	var opts upOptions
+	fs := flag.NewFlagSet("devnet up", flag.ContinueOnError)
+	fs.BoolVar(&opts.skipSetup, "skip-setup", false, "only start the nodes, do not run the setup tools")
+	fs.BoolVar(&opts.deployTokens, "deploy-tokens", false, "deploy fresh MockERC20 tokens with Forge")
+	fs.StringVar(&opts.envOut, "env-out", defaultEnvOut, "env file to write for the solver")
+	err := fs.Parse(args)
+	return opts, err
+}
+
+func stateDir() string {
+	return envutil.GetEnvWithDefault("DEVNET_STATE_DIR", defaultStateDir)
+}
+
+func runUp(args []string) error {
+	opts, err := parseUpFlags(args)
+	if err != nil {
+		return err
+	}
+
+	dir := stateDir()
+	if existing, err := loadState(dir); err == nil && len(existing.Nodes) > 0 {
+		return fmt.Errorf("devnet already started at %s; run `solver devnet down` first", existing.StartedAt.Format(time.RFC3339))
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create devnet state directory: %w", err)
+	}
+
+	state := &devnetState{StartedAt: time.Now(), EnvFile: opts.envOut, Nodes: loadNodeSpecs()}
+
+	fmt.Println("🚀 Starting devnet forks...")
+	for i := range state.Nodes {
+		node := &state.Nodes[i]
+		if err := node.start(dir); err != nil {
+			stopAll(state, dir)
+			return fmt.Errorf("%s: %w", node.Network, err)
+		}
+		fmt.Printf("   🔧 %s (%s) on %s, fork block %d, logs %s\n", node.Network, node.Kind, node.RPCURL(), node.ForkBlock, node.LogFile)
+	}
+	if err := saveState(dir, state); err != nil {
+		stopAll(state, dir)
+		return err
+	}
+
+	fmt.Println("⏳ Waiting for the forks to become healthy...")
+	timeout := time.Duration(envutil.GetEnvInt("DEVNET_STARTUP_TIMEOUT_SECONDS", defaultStartupTimeout)) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for _, node := range state.Nodes {
+		if err := node.waitHealthy(ctx, healthPollInterval); err != nil {
+			stopAll(state, dir)
+			return fmt.Errorf("%w (see %s)", err, node.LogFile)
+		}
+		fmt.Printf("   ✅ %s is healthy\n", node.Network)
+	}
+
+	overrides := envOverrides(state.Nodes)
+	if err := writeEnvFile(opts.envOut, overrides); err != nil {
+		return err
+	}
+	fmt.Printf("📝 Wrote %s\n", opts.envOut)
+
+	if !opts.skipSetup {
+		if err := runSetup(opts, overrides); err != nil {
+			return fmt.Errorf("devnet is running but setup failed: %w", err)
+		}
+	}
+
+	fmt.Println("🎉 Devnet is up")
+	fmt.Printf("💡 Use it with: cp %s .env && make run-local\n", opts.envOut)
+	fmt.Println("🛑 Stop it with: solver devnet down")
+	return nil
+}
+
+// setupStep is one setup tool run against the forks
+type setupStep struct {
+	name string
+	args []string
+}
+
+// setupSteps lists the tools `up` runs once the forks are healthy
+func setupSteps(opts upOptions) []setupStep {
+	var steps []setupStep
+	if opts.deployTokens {
+		steps = append(steps, setupStep{name: "deploy MockERC20 tokens", args: []string{"./cmd/tools/deploy-forge-mock-erc20"}})
+	}
+	return append(steps,
+		setupStep{name: "register Starknet domain on EVM routers", args: []string{"./cmd/tools/additional-helpers/register-evm-routers"}},
+		setupStep{name: "fund Alice and solver accounts", args: []string{"./cmd/tools/fund-accounts", "all"}},
+	)
+}
+
+// runSetup runs the setup tools with the devnet overrides on top of the current environment
+func runSetup(opts upOptions, overrides []envOverride) error {
+	env := os.Environ()
+	for _, o := range overrides {
+		env = append(env, o.Key+"="+o.Value)
+	}
+
+	for _, step := range setupSteps(opts) {
+		fmt.Printf("🔧 Setup: %s...\n", step.name)
+		cmd := exec.Command("go", append([]string{"run"}, step.args...)...)
+		cmd.Env = env
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s: %w", step.name, err)
+		}
+	}
+	return nil
+}
+
+func runDown() error {
+	dir := stateDir()
+	state, err := loadState(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Println("💡 No devnet running")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	stopAll(state, dir)
+	fmt.Println("✅ Devnet stopped")
+	return nil
+}
+
+func runStatus() error {
+	state, err := loadState(stateDir())
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Println("💡 No devnet running")
+		if cliout.JSON() {
+			return cliout.WriteJSON([]nodeStatus{})
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	statuses := checkNodes(state.Nodes)
+	unhealthy := 0
+	for _, status := range statuses {
+		if !status.Healthy {
+			unhealthy++
+			fmt.Printf("❌ %-9s %s (pid %d): %s\n", status.Network, status.RPCURL, status.PID, status.Error)
+			continue
+		}
+		fmt.Printf("✅ %-9s %s (pid %d)\n", status.Network, status.RPCURL, status.PID)
+	}
+	if cliout.JSON() {
+		if err := cliout.WriteJSON(statuses); err != nil {
+			return err
+		}
+	}
+	if unhealthy > 0 {
+		return fmt.Errorf("%d of %d devnet nodes unhealthy", unhealthy, len(state.Nodes))
+	}
+	return nil
+}
+
+// nodeStatus is the health of one running node, as printed by status
+type nodeStatus struct {
+	Network string `json:"network"`
+	RPCURL  string `json:"rpcUrl"`
+	PID     int    `json:"pid"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// checkNodes calls the chain ID method of every node
+func checkNodes(nodes []nodeSpec) []nodeStatus {
+	statuses := make([]nodeStatus, 0, len(nodes))
+	for _, node := range nodes {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_, err := rpcCall(ctx, node.RPCURL(), node.chainIDMethod())
+		cancel()
+		status := nodeStatus{Network: node.Network, RPCURL: node.RPCURL(), PID: node.PID, Healthy: err == nil}
+		if err != nil {
+			status.Error = err.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// stopAll stops every started node and removes the state file
+func stopAll(state *devnetState, dir string) {
+	for _, node := range state.Nodes {
+		if err := node.stop(); err != nil {
+			fmt.Printf("⚠️  Failed to stop %s (pid %d): %v\n", node.Network, node.PID, err)
+		}
+	}
+	_ = os.Remove(filepath.Join(dir, stateFileName))
+}
+
+func loadState(dir string) (*devnetState, error) {
+	data, err := os.ReadFile(filepath.Join(dir, stateFileName))
+	if err != nil {
+		return nil, err
+	}
+	var state devnetState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse devnet state: %w", err)
+	}
+	return &state, nil
+}
+
+func saveState(dir string, state *devnetState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal devnet state: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, stateFileName), data, 0600); err != nil {
+		return fmt.Errorf("failed to write devnet state: %w", err)
+	}
+	return nil
+}
+
+// envOverride is one variable the devnet sets for the solver and setup tools
+type envOverride struct {
+	Key   string
+	Value string
+}
+
+// envOverrides points the solver's LOCAL_ settings at the running forks
+func envOverrides(nodes []nodeSpec) []envOverride {
+	overrides := []envOverride{{Key: "IS_DEVNET", Value: "true"}}
+	for _, node := range nodes {
+		prefix := node.envPrefix()
+		overrides = append(overrides,
+			envOverride{Key: "LOCAL_" + prefix + "_RPC_URL", Value: node.RPCURL()},
+			envOverride{Key: "LOCAL_" + prefix + "_SOLVER_START_BLOCK", Value: strconv.FormatUint(node.ForkBlock, 10)},
+		)
+	}
+	return overrides
+}
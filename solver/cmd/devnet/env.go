@@ -0,0 +1,63 @@
+package devnet
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// envBaseFiles are tried in order as the template of the generated env file
+var envBaseFiles = []string{".env", "example.env"}
+
+// writeEnvFile writes the first existing base env file with the overrides applied
+func writeEnvFile(path string, overrides []envOverride) error {
+	base := ""
+	for _, candidate := range envBaseFiles {
+		if data, err := os.ReadFile(candidate); err == nil {
+			base = string(data)
+			break
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(renderEnv(base, overrides)), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// renderEnv replaces the KEY=... lines of base with the overrides; keys missing from
+// base are appended in a devnet section
+func renderEnv(base string, overrides []envOverride) string {
+	values := make(map[string]string, len(overrides))
+	for _, o := range overrides {
+		values[o.Key] = o.Value
+	}
+
+	applied := make(map[string]bool, len(overrides))
+	lines := strings.Split(base, "\n")
+	for i, line := range lines {
+		key, _, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found || strings.HasPrefix(key, "#") {
+			continue
+		}
+		if value, ok := values[key]; ok {
+			lines[i] = key + "=" + value
+			applied[key] = true
+		}
+	}
+
+	out := strings.TrimRight(strings.Join(lines, "\n"), "\n")
+	var missing []string
+	for _, o := range overrides {
+		if !applied[o.Key] {
+			missing = append(missing, o.Key+"="+o.Value)
+		}
+	}
+	if len(missing) > 0 {
+		if out != "" {
+			out += "\n\n"
+		}
+		out += "### Devnet (written by `solver devnet up`) ###\n" + strings.Join(missing, "\n")
+	}
+	return out + "\n"
+}
@@ -0,0 +1,71 @@
+package devnet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRenderEnv tests applying devnet overrides to an env file
+func TestRenderEnv(t *testing.T) {
+	overrides := []envOverride{
+		{Key: "IS_DEVNET", Value: "true"},
+		{Key: "LOCAL_BASE_RPC_URL", Value: "http://localhost:9548"},
+		{Key: "LOCAL_BASE_SOLVER_START_BLOCK", Value: "123"},
+	}
+
+	t.Run("replaces_and_appends", func(t *testing.T) {
+		base := "### Config\nIS_DEVNET=false # toggle\n# LOCAL_BASE_RPC_URL=commented\nLOCAL_BASE_RPC_URL=http://localhost:8548\nLOG_LEVEL=info\n"
+		out := renderEnv(base, overrides)
+
+		assert.Equal(t, "### Config\nIS_DEVNET=true\n# LOCAL_BASE_RPC_URL=commented\nLOCAL_BASE_RPC_URL=http://localhost:9548\nLOG_LEVEL=info\n\n"+
+			"### Devnet (written by `solver devnet up`) ###\nLOCAL_BASE_SOLVER_START_BLOCK=123\n", out)
+	})
+
+	t.Run("empty_base", func(t *testing.T) {
+		out := renderEnv("", overrides[:1])
+		assert.Equal(t, "### Devnet (written by `solver devnet up`) ###\nIS_DEVNET=true\n", out)
+	})
+}
+
+// TestDevnetState tests the overrides and the state file round trip
+func TestDevnetState(t *testing.T) {
+	nodes := []nodeSpec{{Network: "Base", Kind: kindAnvil, Port: 9548, ChainID: 84532, ForkBlock: 7, ForkURL: "https://secret", PID: 42}}
+
+	t.Run("overrides", func(t *testing.T) {
+		assert.Equal(t, []envOverride{
+			{Key: "IS_DEVNET", Value: "true"},
+			{Key: "LOCAL_BASE_RPC_URL", Value: "http://localhost:9548"},
+			{Key: "LOCAL_BASE_SOLVER_START_BLOCK", Value: "7"},
+		}, envOverrides(nodes))
+	})
+
+	t.Run("round_trip", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, saveState(dir, &devnetState{StartedAt: time.Now(), EnvFile: ".env.devnet", Nodes: nodes}))
+
+		raw, err := os.ReadFile(filepath.Join(dir, stateFileName))
+		require.NoError(t, err)
+		assert.NotContains(t, string(raw), "secret")
+
+		state, err := loadState(dir)
+		require.NoError(t, err)
+		require.Len(t, state.Nodes, 1)
+		assert.Equal(t, 42, state.Nodes[0].PID)
+		assert.Equal(t, 9548, state.Nodes[0].Port)
+	})
+
+	t.Run("setup_steps", func(t *testing.T) {
+		assert.Len(t, setupSteps(upOptions{}), 2)
+		assert.Len(t, setupSteps(upOptions{deployTokens: true}), 3)
+	})
+
+	t.Run("unknown_subcommand", func(t *testing.T) {
+		assert.Error(t, RunDevnet([]string{"restart"}))
+		assert.Error(t, RunDevnet(nil))
+	})
+}
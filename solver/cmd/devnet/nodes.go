@@ -0,0 +1,205 @@
+package devnet
+
+// Local fork nodes: four Anvil forks (Ethereum, Optimism, Arbitrum, Base) and a
+// starknet-devnet fork of Starknet Sepolia.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// Node kinds
+const (
+	kindAnvil    = "anvil"
+	kindStarknet = "starknet-devnet"
+)
+
+// publicForkURLs are used when <NETWORK>_RPC_URL is not set (rate limited)
+var publicForkURLs = map[string]string{
+	"Ethereum": "https://rpc.sepolia.org",
+	"Optimism": "https://sepolia.optimism.io",
+	"Arbitrum": "https://sepolia-rollup.arbitrum.io/rpc",
+	"Base":     "https://sepolia.base.org",
+	"Starknet": "https://free-rpc.nethermind.io/starknet-sepolia-juno/",
+}
+
+// nodeSpec describes one fork node to launch
+type nodeSpec struct {
+	Network   string `json:"network"`
+	Kind      string `json:"kind"`
+	Port      int    `json:"port"`
+	ChainID   uint64 `json:"chainId"`
+	ForkURL   string `json:"-"` // May embed an API key; never written to the state file
+	ForkBlock uint64 `json:"forkBlock"`
+	PID       int    `json:"pid,omitempty"`
+	LogFile   string `json:"logFile,omitempty"`
+}
+
+// RPCURL is the local endpoint of the node
+func (n nodeSpec) RPCURL() string {
+	return fmt.Sprintf("http://localhost:%d", n.Port)
+}
+
+// envPrefix is the upper-case prefix of the network's env vars (e.g. BASE)
+func (n nodeSpec) envPrefix() string {
+	return strings.ToUpper(n.Network)
+}
+
+// loadNodeSpecs builds the node list from the environment
+func loadNodeSpecs() []nodeSpec {
+	specs := []nodeSpec{
+		{Network: "Ethereum", Kind: kindAnvil, Port: 8545, ChainID: config.EthereumSepoliaChainID, ForkBlock: config.EthereumLocalStartBlock},
+		{Network: "Optimism", Kind: kindAnvil, Port: 8546, ChainID: config.OptimismSepoliaChainID, ForkBlock: config.OptimismLocalStartBlock},
+		{Network: "Arbitrum", Kind: kindAnvil, Port: 8547, ChainID: config.ArbitrumSepoliaChainID, ForkBlock: config.ArbitrumLocalStartBlock},
+		{Network: "Base", Kind: kindAnvil, Port: 8548, ChainID: config.BaseSepoliaChainID, ForkBlock: config.BaseLocalStartBlock},
+		{Network: "Starknet", Kind: kindStarknet, Port: 5050, ChainID: config.StarknetSepoliaChainID, ForkBlock: config.StarknetLocalStartBlock},
+	}
+
+	for i := range specs {
+		spec := &specs[i]
+		prefix := spec.envPrefix()
+		spec.Port = envutil.GetEnvInt("DEVNET_"+prefix+"_PORT", spec.Port)
+		spec.ForkBlock = envutil.GetEnvUint64("DEVNET_"+prefix+"_FORK_BLOCK", spec.ForkBlock)
+		spec.ChainID = envutil.GetEnvUint64(prefix+"_CHAIN_ID", spec.ChainID)
+		spec.ForkURL = envutil.GetEnvWithDefault("DEVNET_"+prefix+"_FORK_URL",
+			envutil.GetEnvWithDefault(prefix+"_RPC_URL", publicForkURLs[spec.Network]))
+	}
+	return specs
+}
+
+// command returns the binary and arguments that launch the node
+func (n nodeSpec) command() (string, []string) {
+	if n.Kind == kindStarknet {
+		// A full state archive lets integration tests revert to a snapshot with devnet_abortBlocks
+		args := []string{"--port", strconv.Itoa(n.Port), "--seed", "0", "--fork-network", n.ForkURL, "--state-archive-capacity", "full"}
+		if n.ForkBlock > 0 {
+			args = append(args, "--fork-block", strconv.FormatUint(n.ForkBlock, 10))
+		}
+		return envutil.GetEnvWithDefault("DEVNET_STARKNET_BIN", "starknet-devnet"), args
+	}
+
+	args := []string{"--port", strconv.Itoa(n.Port), "--chain-id", strconv.FormatUint(n.ChainID, 10), "--fork-url", n.ForkURL}
+	if n.ForkBlock > 0 {
+		args = append(args, "--fork-block-number", strconv.FormatUint(n.ForkBlock, 10))
+	}
+	return envutil.GetEnvWithDefault("DEVNET_ANVIL_BIN", "anvil"), args
+}
+
+// start launches the node in the background, logging to logDir/<network>.log
+func (n *nodeSpec) start(logDir string) error {
+	bin, args := n.command()
+	if _, err := exec.LookPath(bin); err != nil {
+		return fmt.Errorf("%s not found in PATH: %w", bin, err)
+	}
+
+	n.LogFile = filepath.Join(logDir, strings.ToLower(n.Network)+".log")
+	logFile, err := os.Create(n.LogFile)
+	if err != nil {
+		return fmt.Errorf("failed to create log file: %w", err)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(bin, args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", bin, err)
+	}
+	n.PID = cmd.Process.Pid
+	// The node outlives this command; `devnet down` stops it by PID
+	return cmd.Process.Release()
+}
+
+// stop terminates the node process, if any
+func (n nodeSpec) stop() error {
+	if n.PID == 0 {
+		return nil
+	}
+	proc, err := os.FindProcess(n.PID)
+	if err != nil {
+		return err
+	}
+	if err := proc.Signal(os.Interrupt); err != nil {
+		return proc.Kill()
+	}
+	return nil
+}
+
+// chainIDMethod is the JSON-RPC method used as health check
+func (n nodeSpec) chainIDMethod() string {
+	if n.Kind == kindStarknet {
+		return "starknet_chainId"
+	}
+	return "eth_chainId"
+}
+
+// waitHealthy polls the node until it answers its chain ID call or the context expires.
+// EVM forks must report the configured chain ID; starknet-devnet reports the forked network's.
+func (n nodeSpec) waitHealthy(ctx context.Context, interval time.Duration) error {
+	var lastErr error
+	for {
+		result, err := rpcCall(ctx, n.RPCURL(), n.chainIDMethod())
+		if err == nil && n.Kind == kindAnvil {
+			var chainID uint64
+			chainID, err = strconv.ParseUint(strings.TrimPrefix(result, "0x"), 16, 64)
+			if err == nil && chainID != n.ChainID {
+				return fmt.Errorf("%s chain ID mismatch: expected %d, got %d", n.Network, n.ChainID, chainID)
+			}
+		}
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s not healthy at %s: %w", n.Network, n.RPCURL(), lastErr)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// rpcCall performs a parameterless JSON-RPC call and returns the string result
+func rpcCall(ctx context.Context, url, method string) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method, "params": []interface{}{}})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var decoded struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return "", fmt.Errorf("invalid %s response: %w", method, err)
+	}
+	if decoded.Error != nil {
+		return "", fmt.Errorf("%s returned error: %s", method, decoded.Error.Message)
+	}
+	return decoded.Result, nil
+}
@@ -0,0 +1,112 @@
+package devnet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chainIDServer answers every JSON-RPC call with result
+func chainIDServer(t *testing.T, result string) (*httptest.Server, int) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	t.Cleanup(server.Close)
+
+	port, err := strconv.Atoi(server.URL[strings.LastIndex(server.URL, ":")+1:])
+	require.NoError(t, err)
+	return server, port
+}
+
+// TestLoadNodeSpecs tests node configuration from the environment
+func TestLoadNodeSpecs(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		specs := loadNodeSpecs()
+		require.Len(t, specs, 5)
+		assert.Equal(t, "http://localhost:8545", specs[0].RPCURL())
+		assert.Equal(t, kindStarknet, specs[4].Kind)
+		assert.Equal(t, 5050, specs[4].Port)
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		t.Setenv("DEVNET_BASE_PORT", "9548")
+		t.Setenv("DEVNET_BASE_FORK_BLOCK", "123")
+		t.Setenv("DEVNET_BASE_FORK_URL", "https://base.example")
+
+		base := loadNodeSpecs()[3]
+		assert.Equal(t, "Base", base.Network)
+		assert.Equal(t, 9548, base.Port)
+		assert.Equal(t, uint64(123), base.ForkBlock)
+
+		bin, args := base.command()
+		assert.Equal(t, "anvil", bin)
+		assert.Equal(t, []string{"--port", "9548", "--chain-id", "84532", "--fork-url", "https://base.example", "--fork-block-number", "123"}, args)
+	})
+
+	t.Run("starknet_latest_block", func(t *testing.T) {
+		t.Setenv("DEVNET_STARKNET_FORK_BLOCK", "0")
+		t.Setenv("DEVNET_STARKNET_BIN", "/opt/starknet-devnet")
+
+		bin, args := loadNodeSpecs()[4].command()
+		assert.Equal(t, "/opt/starknet-devnet", bin)
+		assert.NotContains(t, args, "--fork-block")
+		assert.Contains(t, args, "--fork-network")
+		assert.Contains(t, args, "--state-archive-capacity")
+	})
+}
+
+// TestWaitHealthy tests the chain ID health check
+func TestWaitHealthy(t *testing.T) {
+	t.Run("evm_chain_id_matches", func(t *testing.T) {
+		_, port := chainIDServer(t, "0x14a34") // 84532
+		node := nodeSpec{Network: "Base", Kind: kindAnvil, Port: port, ChainID: 84532}
+		assert.NoError(t, node.waitHealthy(context.Background(), time.Millisecond))
+	})
+
+	t.Run("evm_chain_id_mismatch", func(t *testing.T) {
+		_, port := chainIDServer(t, "0x1")
+		node := nodeSpec{Network: "Base", Kind: kindAnvil, Port: port, ChainID: 84532}
+		assert.ErrorContains(t, node.waitHealthy(context.Background(), time.Millisecond), "mismatch")
+	})
+
+	t.Run("starknet", func(t *testing.T) {
+		_, port := chainIDServer(t, "0x534e5f5345504f4c4941")
+		node := nodeSpec{Network: "Starknet", Kind: kindStarknet, Port: port}
+		assert.NoError(t, node.waitHealthy(context.Background(), time.Millisecond))
+	})
+
+	t.Run("times_out", func(t *testing.T) {
+		server, port := chainIDServer(t, "0x1")
+		server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		node := nodeSpec{Network: "Base", Kind: kindAnvil, Port: port, ChainID: 1}
+		assert.ErrorContains(t, node.waitHealthy(ctx, 10*time.Millisecond), "not healthy")
+	})
+}
+
+// TestCheckNodes tests the health reported by status
+func TestCheckNodes(t *testing.T) {
+	_, port := chainIDServer(t, "0x14a34")
+	down, downPort := chainIDServer(t, "0x1")
+	down.Close()
+
+	statuses := checkNodes([]nodeSpec{
+		{Network: "Base", Kind: kindAnvil, Port: port, PID: 42},
+		{Network: "Starknet", Kind: kindStarknet, Port: downPort},
+	})
+	require.Len(t, statuses, 2)
+	assert.Equal(t, nodeStatus{Network: "Base", RPCURL: fmt.Sprintf("http://localhost:%d", port), PID: 42, Healthy: true}, statuses[0])
+	assert.False(t, statuses[1].Healthy)
+	assert.NotEmpty(t, statuses[1].Error)
+}
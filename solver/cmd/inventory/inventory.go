@@ -0,0 +1,462 @@
+package inventory
+
+// Inventory command - moves ERC20s between the operator treasury and the solver hot wallets
+//
+// Usage:
+//
+//	solver inventory deposit --token TOKEN --amount N [--network NAMES] [--confirmations N] [--yes]
+//	solver inventory withdraw --token TOKEN --amount N [--network NAMES] [--confirmations N] [--yes]
+//
+// deposit sends from the treasury to the solver and withdraw from the solver to the treasury, the
+// same amount on every network the registry token is listed on (or only the comma-separated
+// --network names). TOKEN is a registry symbol or address and N is in whole tokens (e.g. "250.5").
+// The transfers are listed and must be confirmed before anything is sent. EVM transfers wait for
+// --confirmations blocks and Starknet invokes for acceptance; each outcome is written to the admin
+// audit log as inventory.deposit or inventory.withdraw, with its transaction hash.
+//
+// Settings (read with the LOCAL_ prefix when IS_DEVNET=true, like the solver keys):
+// - TREASURY_PRIVATE_KEY: the treasury's EVM key, needed to deposit
+// - TREASURY_ADDRESS: the treasury's EVM address (default: the address of TREASURY_PRIVATE_KEY)
+// - TREASURY_STARKNET_ADDRESS: the treasury's Starknet account
+// - TREASURY_STARKNET_PRIVATE_KEY, TREASURY_STARKNET_PUBLIC_KEY: its key pair, needed to deposit
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/admin"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	// transferTimeout bounds sending and confirming one transfer
+	transferTimeout = 10 * time.Minute
+	// confirmationPollInterval is how often the EVM head is read while waiting for confirmations
+	confirmationPollInterval = 2 * time.Second
+)
+
+// Directions of a move, also the suffix of their audit actions
+const (
+	directionDeposit  = "deposit"
+	directionWithdraw = "withdraw"
+)
+
+// options are the parsed flags of a subcommand
+type options struct {
+	direction     string
+	token         string
+	amount        string
+	networks      []string
+	confirmations uint64
+	yes           bool
+}
+
+// wallet is one side of a transfer
+type wallet struct {
+	name      string // "treasury" or "solver"
+	address   string
+	key       string // Private key, empty when the wallet only receives
+	publicKey string // Starknet public key
+}
+
+// transfer moves amount of token from one wallet to the other on network
+type transfer struct {
+	network config.NetworkConfig
+	token   config.TokenConfig
+	amount  *big.Int
+	from    wallet
+	to      wallet
+}
+
+// target describes a transfer in the audit log, e.g. "Base:DOG:250.5"
+func (t transfer) target() string {
+	return fmt.Sprintf("%s:%s:%s", t.network.Name, t.token.Symbol, amount.New(t.amount, t.token.Decimals))
+}
+
+// sendFunc sends a transfer and waits for it to be confirmed, returning its hash once sent
+type sendFunc func(ctx context.Context, t transfer, confirmations uint64) (string, error)
+
+// RunInventory dispatches inventory subcommands; args excludes the "inventory" command itself
+func RunInventory(args []string) error {
+	if len(args) == 0 {
+		printUsage()
+		return fmt.Errorf("missing inventory command")
+	}
+	if args[0] != directionDeposit && args[0] != directionWithdraw {
+		printUsage()
+		return fmt.Errorf("unknown inventory command: %s", args[0])
+	}
+
+	opts, err := parseFlags(args[0], args[1:])
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	config.InitializeNetworks()
+	if err := config.ApplyRuntime(&cfg.RuntimeConfig, hyperlane7683.ValidateRuleConfig, logutil.ValidateLogLevels); err != nil {
+		return err
+	}
+
+	transfers, err := planTransfers(opts)
+	if err != nil {
+		return err
+	}
+	printPlan(os.Stdout, opts, transfers)
+	if !opts.yes && !confirm(os.Stdin, os.Stdout) {
+		return fmt.Errorf("aborted, nothing was sent")
+	}
+	return execute(context.Background(), transfers, opts, send, admin.AuditLogFromEnv(), os.Stdout)
+}
+
+func printUsage() {
+	fmt.Println("Usage: solver inventory deposit|withdraw --token TOKEN --amount N [--network NAMES] [--confirmations N] [--yes]")
+	fmt.Println("  deposit moves tokens from the treasury to the solver, withdraw from the solver to the treasury,")
+	fmt.Println("  on every network the token is registered on unless --network lists some")
+}
+
+func parseFlags(direction string, args []string) (options, error) {
+	opts := options{direction: direction}
+	var networks string
+	fs := flag.NewFlagSet("inventory "+direction, flag.ContinueOnError)
+	fs.Usage = printUsage
+	fs.StringVar(&opts.token, "token", "", "registry token symbol or address")
+	fs.StringVar(&opts.amount, "amount", "", "amount per network, in whole tokens")
+	fs.StringVar(&networks, "network", "", "comma-separated networks (default: every network the token is registered on)")
+	fs.Uint64Var(&opts.confirmations, "confirmations", 1, "EVM blocks to wait for, including the transfer's")
+	fs.BoolVar(&opts.yes, "yes", false, "send without asking for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if opts.token == "" {
+		return opts, fmt.Errorf("--token is required")
+	}
+	if opts.amount == "" {
+		return opts, fmt.Errorf("--amount is required")
+	}
+	if opts.confirmations == 0 {
+		return opts, fmt.Errorf("--confirmations must be at least 1")
+	}
+	for _, name := range strings.Split(networks, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			opts.networks = append(opts.networks, name)
+		}
+	}
+	return opts, nil
+}
+
+// planTransfers lists the transfers of opts, one per network the token is registered on
+func planTransfers(opts options) ([]transfer, error) {
+	var transfers []transfer
+	for _, token := range config.ActiveRuntime().Tokens {
+		if !strings.EqualFold(token.Symbol, opts.token) && !strings.EqualFold(token.Address, opts.token) {
+			continue
+		}
+		network, ok := networkForChainID(token.ChainID)
+		if !ok || (len(opts.networks) > 0 && !containsFold(opts.networks, network.Name)) {
+			continue
+		}
+		if types.IsNativeToken(token.Address) {
+			return nil, fmt.Errorf("%s on %s is the native token, only ERC20s can be moved", token.Symbol, network.Name)
+		}
+
+		units, err := amount.Parse(opts.amount, token.Decimals)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --amount: %w", err)
+		}
+		if units.Units.Sign() == 0 {
+			return nil, fmt.Errorf("--amount must be positive")
+		}
+
+		treasury, solver, err := wallets(network)
+		if err != nil {
+			return nil, err
+		}
+		t := transfer{network: network, token: token, amount: units.Units, from: treasury, to: solver}
+		if opts.direction == directionWithdraw {
+			t.from, t.to = solver, treasury
+		}
+		if t.from.key == "" {
+			return nil, fmt.Errorf("no %s key to send from on %s", t.from.name, network.Name)
+		}
+		transfers = append(transfers, t)
+	}
+
+	for _, name := range opts.networks {
+		if !planned(transfers, name) {
+			return nil, fmt.Errorf("token %s is not registered on network %q", opts.token, name)
+		}
+	}
+	if len(transfers) == 0 {
+		return nil, fmt.Errorf("token %s is not registered on any configured network", opts.token)
+	}
+	return transfers, nil
+}
+
+// wallets returns the treasury and solver wallets of network
+func wallets(network config.NetworkConfig) (wallet, wallet, error) {
+	if config.IsStarknetNetwork(network.Name) {
+		treasury := wallet{
+			name:      "treasury",
+			address:   envutil.GetConditionalAccountEnv("TREASURY_STARKNET_ADDRESS"),
+			key:       envutil.GetConditionalAccountEnv("TREASURY_STARKNET_PRIVATE_KEY"),
+			publicKey: envutil.GetConditionalAccountEnv("TREASURY_STARKNET_PUBLIC_KEY"),
+		}
+		solver := wallet{
+			name:      "solver",
+			address:   envutil.GetStarknetSolverAddress(),
+			key:       envutil.GetStarknetSolverPrivateKey(),
+			publicKey: envutil.GetStarknetSolverPublicKey(),
+		}
+		if treasury.address == "" {
+			return treasury, solver, fmt.Errorf("TREASURY_STARKNET_ADDRESS is not set, needed for %s", network.Name)
+		}
+		return treasury, solver, nil
+	}
+
+	treasury, err := evmWallet("treasury", envutil.GetConditionalAccountEnv("TREASURY_PRIVATE_KEY"), envutil.GetConditionalAccountEnv("TREASURY_ADDRESS"))
+	if err != nil {
+		return treasury, wallet{}, err
+	}
+	solver, err := evmWallet("solver", envutil.GetSolverPrivateKey(), envutil.GetSolverPublicKey())
+	if err != nil {
+		return treasury, solver, err
+	}
+	if treasury.address == "" {
+		return treasury, solver, fmt.Errorf("TREASURY_ADDRESS or TREASURY_PRIVATE_KEY must be set, needed for %s", network.Name)
+	}
+	return treasury, solver, nil
+}
+
+// evmWallet builds an EVM wallet from its key, its address defaulting to the key's
+func evmWallet(name, key, address string) (wallet, error) {
+	w := wallet{name: name, key: key, address: address}
+	if key != "" {
+		pk, err := ethutil.ParsePrivateKey(key)
+		if err != nil {
+			return w, fmt.Errorf("invalid %s private key: %w", name, err)
+		}
+		keyAddress := crypto.PubkeyToAddress(pk.PublicKey)
+		if address != "" && common.HexToAddress(address) != keyAddress {
+			return w, fmt.Errorf("%s address %s is not the address of its private key (%s)", name, address, keyAddress.Hex())
+		}
+		w.address = keyAddress.Hex()
+	}
+	if w.address != "" && !common.IsHexAddress(w.address) {
+		return w, fmt.Errorf("invalid %s address %q", name, w.address)
+	}
+	return w, nil
+}
+
+// printPlan lists the transfers about to be sent
+func printPlan(w io.Writer, opts options, transfers []transfer) {
+	fmt.Fprintf(w, "📋 %s of %s on %d network(s):\n", opts.direction, opts.token, len(transfers))
+	for _, t := range transfers {
+		fmt.Fprintf(w, "   %s: %s from %s %s to %s %s\n", t.network.Name, t.token.Format(t.amount), t.from.name, t.from.address, t.to.name, t.to.address)
+	}
+}
+
+// confirm asks on out and reads a yes from in
+func confirm(in io.Reader, out io.Writer) bool {
+	fmt.Fprint(out, "Send these transfers? [y/N] ")
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// execute sends the transfers one after the other and audits each outcome; a failed transfer does not
+// stop the others, the failures are returned together
+func execute(ctx context.Context, transfers []transfer, opts options, send sendFunc, audit *admin.AuditLog, out io.Writer) error {
+	action := "inventory." + opts.direction
+	var errs []error
+	for _, t := range transfers {
+		fmt.Fprintf(out, "💸 Sending %s on %s...\n", t.token.Format(t.amount), t.network.Name)
+
+		sendCtx, cancel := context.WithTimeout(ctx, transferTimeout)
+		txHash, err := send(sendCtx, t, opts.confirmations)
+		cancel()
+
+		if auditErr := audit.RecordCommand(action, t.target(), txHash, err); auditErr != nil {
+			fmt.Fprintf(out, "⚠️  %v\n", auditErr)
+		}
+		if err != nil {
+			fmt.Fprintf(out, "❌ %s: %v\n", t.network.Name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", t.network.Name, err))
+			continue
+		}
+		fmt.Fprintf(out, "✅ %s: %s\n", t.network.Name, txHash)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%d of %d transfers failed: %w", len(errs), len(transfers), errors.Join(errs...))
+	}
+	return nil
+}
+
+// send dials the transfer's network and sends it
+func send(ctx context.Context, t transfer, confirmations uint64) (string, error) {
+	if config.IsStarknetNetwork(t.network.Name) {
+		return sendStarknet(ctx, t)
+	}
+	return sendEVM(ctx, t, confirmations)
+}
+
+// sendEVM sends an ERC20 transfer and waits for confirmations blocks
+func sendEVM(ctx context.Context, t transfer, confirmations uint64) (string, error) {
+	client, err := ethclient.DialContext(ctx, t.network.RPCURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to dial %s: %w", t.network.Name, err)
+	}
+	defer client.Close()
+
+	key, err := ethutil.ParsePrivateKey(t.from.key)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s private key: %w", t.from.name, err)
+	}
+	auth, err := ethutil.NewTransactor(new(big.Int).SetUint64(t.network.ChainID), key)
+	if err != nil {
+		return "", err
+	}
+	auth.Context = ctx
+
+	tx, err := ethutil.ERC20Transfer(client, auth, common.HexToAddress(t.token.Address), common.HexToAddress(t.to.address), t.amount)
+	if err != nil {
+		return "", err
+	}
+	txHash := tx.Hash().Hex()
+
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	if err != nil {
+		return txHash, fmt.Errorf("failed waiting for %s: %w", txHash, err)
+	}
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		return txHash, fmt.Errorf("transfer %s reverted", txHash)
+	}
+	return txHash, waitConfirmations(ctx, client, receipt.BlockNumber.Uint64(), confirmations)
+}
+
+// blockNumberReader reads the chain head
+type blockNumberReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// waitConfirmations waits until the block included is confirmations deep, itself counting as one
+func waitConfirmations(ctx context.Context, client blockNumberReader, included, confirmations uint64) error {
+	ticker := time.NewTicker(confirmationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read block number: %w", err)
+		}
+		if head+1 >= included+confirmations {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for %d confirmations: %w", confirmations, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendStarknet invokes the token's transfer from the sending account and waits for acceptance
+func sendStarknet(ctx context.Context, t transfer) (string, error) {
+	provider, err := rpc.NewProvider(t.network.RPCURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to dial %s: %w", t.network.Name, err)
+	}
+
+	from, err := utils.HexToFelt(t.from.address)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s address: %w", t.from.name, err)
+	}
+	to, err := utils.HexToFelt(t.to.address)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s address: %w", t.to.name, err)
+	}
+	token, err := utils.HexToFelt(t.token.Address)
+	if err != nil {
+		return "", fmt.Errorf("invalid token address: %w", err)
+	}
+	privateKey, ok := new(big.Int).SetString(t.from.key, 0)
+	if !ok {
+		return "", fmt.Errorf("failed to parse the %s private key", t.from.name)
+	}
+	ks := account.NewMemKeystore()
+	ks.Put(t.from.publicKey, privateKey)
+	acct, err := account.NewAccount(provider, from, t.from.publicKey, ks, account.CairoV2)
+	if err != nil {
+		return "", fmt.Errorf("failed to create the %s account: %w", t.from.name, err)
+	}
+
+	client := starknetutil.NewAccountClient(acct)
+	txHash, err := client.Transfer(ctx, token, to, t.amount)
+	if err != nil {
+		return "", err
+	}
+	if _, err := client.WaitForAcceptance(ctx, txHash, starknetutil.DefaultBlockTime); err != nil {
+		return txHash.String(), err
+	}
+	return txHash.String(), nil
+}
+
+// networkForChainID returns the configured network of chainID
+func networkForChainID(chainID uint64) (config.NetworkConfig, bool) {
+	for _, network := range config.Networks {
+		if network.ChainID == chainID {
+			return network, true
+		}
+	}
+	return config.NetworkConfig{}, false
+}
+
+// containsFold reports whether names contains name, ignoring case
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// planned reports whether a transfer is planned on the network named name
+func planned(transfers []transfer, name string) bool {
+	for _, t := range transfers {
+		if strings.EqualFold(t.network.Name, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,217 @@
+package inventory
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/admin"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	solverKey       = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	solverAddress   = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+	treasuryKey     = "0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"
+	treasuryAddress = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+)
+
+func TestParseFlags(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		opts, err := parseFlags(directionDeposit, []string{"--token", "DOG", "--amount", "250.5"})
+		require.NoError(t, err)
+		assert.Equal(t, options{direction: directionDeposit, token: "DOG", amount: "250.5", confirmations: 1}, opts)
+	})
+
+	t.Run("networks", func(t *testing.T) {
+		opts, err := parseFlags(directionWithdraw, []string{"--token", "DOG", "--amount", "1", "--network", "Base, Starknet,", "--confirmations", "3", "--yes"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Base", "Starknet"}, opts.networks)
+		assert.Equal(t, uint64(3), opts.confirmations)
+		assert.True(t, opts.yes)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseFlags(directionDeposit, []string{"--amount", "1"})
+		assert.ErrorContains(t, err, "--token is required")
+		_, err = parseFlags(directionDeposit, []string{"--token", "DOG"})
+		assert.ErrorContains(t, err, "--amount is required")
+		_, err = parseFlags(directionDeposit, []string{"--token", "DOG", "--amount", "1", "--confirmations", "0"})
+		assert.ErrorContains(t, err, "at least 1")
+		_, err = parseFlags(directionDeposit, []string{"--token", "DOG", "--amount", "1", "Base"})
+		assert.ErrorContains(t, err, "unexpected argument")
+	})
+
+	t.Run("unknown subcommand", func(t *testing.T) {
+		assert.ErrorContains(t, RunInventory([]string{"move"}), "unknown inventory command")
+		assert.ErrorContains(t, RunInventory(nil), "missing inventory command")
+	})
+}
+
+func TestPlanTransfers(t *testing.T) {
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+	require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+		{ChainID: config.BaseSepoliaChainID, Address: "0x00000000000000000000000000000000000000d0", Symbol: "DOG", Decimals: 18},
+		{ChainID: config.EthereumSepoliaChainID, Address: "0x00000000000000000000000000000000000000d1", Symbol: "DOG", Decimals: 6},
+		{ChainID: config.StarknetSepoliaChainID, Address: "0x0d2", Symbol: "DOG", Decimals: 18},
+		{ChainID: config.BaseSepoliaChainID, Address: "0x0", Symbol: "ETH", Decimals: 18},
+	}}))
+
+	t.Setenv("IS_DEVNET", "")
+	t.Setenv("SOLVER_PRIVATE_KEY", solverKey)
+	t.Setenv("SOLVER_PUB_KEY", "")
+	t.Setenv("TREASURY_PRIVATE_KEY", treasuryKey)
+	t.Setenv("TREASURY_ADDRESS", "")
+	t.Setenv("STARKNET_SOLVER_ADDRESS", "0x5a")
+	t.Setenv("STARKNET_SOLVER_PRIVATE_KEY", "0x5b")
+	t.Setenv("STARKNET_SOLVER_PUBLIC_KEY", "0x5c")
+	t.Setenv("TREASURY_STARKNET_ADDRESS", "0x7a")
+	t.Setenv("TREASURY_STARKNET_PRIVATE_KEY", "")
+	t.Setenv("TREASURY_STARKNET_PUBLIC_KEY", "")
+
+	t.Run("deposits go from the treasury to the solver", func(t *testing.T) {
+		transfers, err := planTransfers(options{direction: directionDeposit, token: "dog", amount: "2.5", networks: []string{"base", "Ethereum"}})
+		require.NoError(t, err)
+		require.Len(t, transfers, 2)
+
+		assert.Equal(t, "Base", transfers[0].network.Name)
+		assert.Equal(t, "2500000000000000000", transfers[0].amount.String())
+		assert.Equal(t, treasuryAddress, transfers[0].from.address)
+		assert.Equal(t, solverAddress, transfers[0].to.address)
+		assert.Equal(t, "Base:DOG:2.5", transfers[0].target())
+
+		assert.Equal(t, "Ethereum", transfers[1].network.Name)
+		assert.Equal(t, "2500000", transfers[1].amount.String(), "amounts are scaled by each chain's decimals")
+	})
+
+	t.Run("withdrawals go from the solver to the treasury", func(t *testing.T) {
+		transfers, err := planTransfers(options{direction: directionWithdraw, token: "DOG", amount: "1"})
+		require.NoError(t, err)
+		require.Len(t, transfers, 3)
+		for _, transfer := range transfers {
+			assert.Equal(t, "solver", transfer.from.name)
+			assert.Equal(t, "treasury", transfer.to.name)
+		}
+		assert.Equal(t, "0x7a", transfers[2].to.address)
+	})
+
+	t.Run("sending needs the sender's key", func(t *testing.T) {
+		_, err := planTransfers(options{direction: directionDeposit, token: "DOG", amount: "1", networks: []string{"Starknet"}})
+		assert.ErrorContains(t, err, "no treasury key to send from on Starknet")
+	})
+
+	t.Run("treasury address must match its key", func(t *testing.T) {
+		t.Setenv("TREASURY_ADDRESS", solverAddress)
+		_, err := planTransfers(options{direction: directionWithdraw, token: "DOG", amount: "1", networks: []string{"Base"}})
+		assert.ErrorContains(t, err, "is not the address of its private key")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := planTransfers(options{direction: directionDeposit, token: "DOG", amount: "1", networks: []string{"Arbitrum"}})
+		assert.ErrorContains(t, err, `not registered on network "Arbitrum"`)
+		_, err = planTransfers(options{direction: directionDeposit, token: "CAT", amount: "1"})
+		assert.ErrorContains(t, err, "not registered on any configured network")
+		_, err = planTransfers(options{direction: directionDeposit, token: "ETH", amount: "1"})
+		assert.ErrorContains(t, err, "only ERC20s")
+		_, err = planTransfers(options{direction: directionDeposit, token: "DOG", amount: "0.0000001", networks: []string{"Ethereum"}})
+		assert.ErrorContains(t, err, "more than 6 decimals")
+		_, err = planTransfers(options{direction: directionDeposit, token: "DOG", amount: "0"})
+		assert.ErrorContains(t, err, "must be positive")
+	})
+}
+
+func TestConfirm(t *testing.T) {
+	var out bytes.Buffer
+	assert.True(t, confirm(strings.NewReader("y\n"), &out))
+	assert.True(t, confirm(strings.NewReader(" YES \n"), &out))
+	assert.False(t, confirm(strings.NewReader("\n"), &out))
+	assert.False(t, confirm(strings.NewReader(""), &out))
+	assert.Contains(t, out.String(), "[y/N]")
+}
+
+func TestExecute(t *testing.T) {
+	dog := config.TokenConfig{Symbol: "DOG", Decimals: 18}
+	transfers := []transfer{
+		{network: config.NetworkConfig{Name: "Base"}, token: dog, amount: big.NewInt(1e18)},
+		{network: config.NetworkConfig{Name: "Starknet"}, token: dog, amount: big.NewInt(2e18)},
+		{network: config.NetworkConfig{Name: "Ethereum"}, token: dog, amount: big.NewInt(3e18)},
+	}
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	var sent []string
+	send := func(_ context.Context, t transfer, confirmations uint64) (string, error) {
+		sent = append(sent, t.network.Name)
+		if t.network.Name == "Starknet" {
+			return "0x02", errors.New("reverted")
+		}
+		return "0x01", nil
+	}
+
+	var out bytes.Buffer
+	err := execute(context.Background(), transfers, options{direction: directionDeposit, confirmations: 2}, send, admin.NewAuditLog(path), &out)
+	require.ErrorContains(t, err, "1 of 3 transfers failed")
+	assert.ErrorContains(t, err, "Starknet: reverted")
+	assert.Equal(t, []string{"Base", "Starknet", "Ethereum"}, sent, "a failure does not stop the other networks")
+	assert.Contains(t, out.String(), "✅ Base: 0x01")
+	assert.Contains(t, out.String(), "❌ Starknet: reverted")
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	var entries []admin.AuditEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry admin.AuditEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.Len(t, entries, 3)
+	assert.Equal(t, "inventory.deposit", entries[0].Action)
+	assert.Equal(t, "Base:DOG:1", entries[0].Target)
+	assert.Equal(t, "0x01", entries[0].TxHash)
+	assert.Equal(t, "error", entries[1].Result)
+	assert.Equal(t, "0x02", entries[1].TxHash, "sent transactions are audited even when they fail")
+}
+
+// fakeHead returns the next of its heads on each read
+type fakeHead struct{ heads []uint64 }
+
+func (f *fakeHead) BlockNumber(context.Context) (uint64, error) {
+	head := f.heads[0]
+	if len(f.heads) > 1 {
+		f.heads = f.heads[1:]
+	}
+	return head, nil
+}
+
+func TestWaitConfirmations(t *testing.T) {
+	t.Run("the inclusion block is the first confirmation", func(t *testing.T) {
+		head := &fakeHead{heads: []uint64{100}}
+		require.NoError(t, waitConfirmations(context.Background(), head, 100, 1))
+	})
+
+	t.Run("waits for deeper blocks", func(t *testing.T) {
+		head := &fakeHead{heads: []uint64{100, 101}}
+		require.NoError(t, waitConfirmations(context.Background(), head, 100, 2))
+		assert.Equal(t, []uint64{101}, head.heads)
+	})
+
+	t.Run("stops with the context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := waitConfirmations(ctx, &fakeHead{heads: []uint64{100}}, 100, 3)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
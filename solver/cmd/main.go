@@ -7,12 +7,31 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/NethermindEth/oif-starknet/solver/cmd/balances"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/cliout"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/configcheck"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/devnet"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/inventory"
+	ordercmd "github.com/NethermindEth/oif-starknet/solver/cmd/orders"
+	replaycmd "github.com/NethermindEth/oif-starknet/solver/cmd/replay"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/report"
 	"github.com/NethermindEth/oif-starknet/solver/cmd/solver"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/staterebuild"
 	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/top"
 )
 
 func main() {
+	// --json applies to every command, wherever it is given
+	args, jsonOutput := cliout.StripJSONFlag(os.Args[1:])
+	os.Args = append(os.Args[:1], args...)
+	if jsonOutput {
+		cliout.EnableJSON()
+	}
+
 	if len(os.Args) < 2 {
 		printUsage()
 		os.Exit(1)
@@ -27,6 +46,66 @@ func main() {
 	case "tools":
 		// Route to development tools
 		runTools()
+	case "report":
+		// Summaries built from the order store
+		if err := report.RunReport(os.Args[2:]); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	case "orders":
+		// Order store inspection
+		if err := ordercmd.RunOrders(os.Args[2:]); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	case "balances":
+		// Solver balances of the registry tokens
+		if err := balances.RunBalances(os.Args[2:]); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	case "inventory":
+		// Token moves between the treasury and the solver wallets
+		if err := inventory.RunInventory(os.Args[2:]); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	case "config":
+		// Configuration validation against the configured networks
+		if err := configcheck.RunConfig(os.Args[2:]); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	case "devnet":
+		// Local fork environment
+		if err := devnet.RunDevnet(os.Args[2:]); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	case "replay":
+		// Offline replay of recorded chain traffic
+		if err := replaycmd.RunReplay(os.Args[2:]); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	case "state":
+		// Deployment state reconstruction from on-chain data
+		if err := staterebuild.RunState(os.Args[2:]); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	case "contracts":
+		// Contract declares and releases on Starknet, deployment verification
+		if err := contracts.RunContracts(os.Args[2:]); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	case "top":
+		// Live view of a running solver over its admin API
+		if err := top.RunTop(os.Args[2:]); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
 	case "help", "-h", "--help":
 		printUsage()
 	default:
@@ -40,11 +119,27 @@ func printUsage() {
 	fmt.Println("OIF Starknet Solver - Single Binary for All Operations")
 	fmt.Println()
 	fmt.Println("Usage:")
-	fmt.Println("  solver <command> [options]")
+	fmt.Println("  solver <command> [options] [--json]")
+	fmt.Println()
+	fmt.Println("  --json  Print the command's result as JSON on stdout; progress messages go to stderr")
 	fmt.Println()
 	fmt.Println("Commands:")
 	fmt.Println("  solver                    Run the main solver")
 	fmt.Println("  tools <tool> [options]    Run development tools")
+	fmt.Println("  report pnl [options]      PnL summary per chain pair and token (csv|json)")
+	fmt.Println("  report rejections [opts]  Rejected orders by reason code (csv|json)")
+	fmt.Println("  orders export [options]   Export order history with filters (csv|json)")
+	fmt.Println("  orders status <orderID>   Status of one order and the fill/settle tx hashes sent for it (text|json)")
+	fmt.Println("  balances [options]        Solver balances per token and network (table|csv|json)")
+	fmt.Println("  inventory <deposit|withdraw> [opts]  Move tokens between the treasury and the solver wallets")
+	fmt.Println("  config check [options]    Validate RPCs, routers, domains, keys and balances (table|json)")
+	fmt.Println("  devnet <up|status|down>   Manage local Anvil + starknet-devnet forks")
+	fmt.Println("  replay <file> [options]   Replay recorded chain traffic offline (text|json)")
+	fmt.Println("  state rebuild [options]   Rebuild deployment-state.json from on-chain data")
+	fmt.Println("  contracts release [opts]  Declare, deploy and verify Hyperlane7683 on a Starknet network")
+	fmt.Println("  contracts declare [opts]  Declare the router, ERC20 and mock classes in one run")
+	fmt.Println("  contracts verify [opts]   Check deployed routers and tokens against the configuration")
+	fmt.Println("  top [options]             Live listener lag, pending orders, fills and balances of a running solver")
 	fmt.Println("  help                      Show this help message")
 	fmt.Println()
 	fmt.Println("Development Tools:")
@@ -55,7 +150,25 @@ func printUsage() {
 	fmt.Println("  solver solver                    # Run main solver")
 	fmt.Println("  solver tools open-order starknet # Create Starknet order")
 	fmt.Println("  solver tools open-order evm      # Create EVM order")
+	fmt.Println("  solver tools open-order evm default-evm-evm --fill-deadline 2m --sender-nonce 42")
+	fmt.Println("  solver tools open-order evm chaos # Open orders the solver must reject")
 	fmt.Println("  solver tools setup-forks deploy  # Deploy to forks")
+	fmt.Println("  solver report pnl --from 2026-01-01 --to 2026-02-01 --format json")
+	fmt.Println("  solver report rejections --from 2026-01-01")
+	fmt.Println("  solver orders export --status REJECTED --origin Base --format csv")
+	fmt.Println("  solver orders status 0x1f2e...   # Tx hashes to look up on the explorers")
+	fmt.Println("  solver balances --format csv")
+	fmt.Println("  solver inventory deposit --token DOG --amount 500 --network Base,Starknet --confirmations 3")
+	fmt.Println("  solver config check              # Validate the configuration before starting")
+	fmt.Println("  solver devnet up                 # Start forks, set up accounts, write .env.devnet")
+	fmt.Println("  SOLVER_RECORD_FILE=traffic.jsonl solver solver  # Record chain traffic")
+	fmt.Println("  solver replay traffic.jsonl --format json        # Replay it offline")
+	fmt.Println("  solver state rebuild --manifest tokens.json      # Recover a lost deployment state")
+	fmt.Println("  solver contracts release --network \"Starknet Sepolia\" --max-fee 5")
+	fmt.Println("  solver contracts declare --network Starknet --only MockERC20")
+	fmt.Println("  solver contracts verify --format json          # Fail on deployment drift")
+	fmt.Println("  SOLVER_ADMIN_ADDR=127.0.0.1:8090 solver top    # Watch a running solver")
+	fmt.Println("  solver tools open-order evm default-evm-evm --json | jq -r '.[0].orderId'")
 }
 
 func runSolver() {
@@ -86,10 +199,10 @@ func runTools() {
 
 func runOpenOrder() {
 	if len(os.Args) < 4 {
-		fmt.Println("Usage: solver tools open-order <chain> [command]")
+		fmt.Println("Usage: solver tools open-order <chain> [command] [--open-deadline D] [--fill-deadline D] [--sender-nonce N] [--result-file FILE]")
 		fmt.Println("Available chains: starknet, evm")
-		fmt.Println("Available EVM commands: random-to-evm, random-to-sn, default-evm-evm, default-evm-sn")
-		fmt.Println("Available Starknet commands: random, default")
+		fmt.Println("Available EVM commands: random-to-evm, random-to-sn, default-evm-evm, default-evm-sn, chaos[-<scenario>]")
+		fmt.Println("Available Starknet commands: random, default, native, native-strk, random-to-sn, chaos[-<scenario>]")
 		os.Exit(1)
 	}
 
@@ -97,21 +210,15 @@ func runOpenOrder() {
 
 	switch strings.ToLower(chain) {
 	case "starknet":
-		// Get the command (default to random if not provided)
-		command := "random"
-		if len(os.Args) > 4 {
-			command = os.Args[4]
-		}
+		// Get the command (default to random if not provided) and the flags after it
+		command, opts := parseOpenOrderArgs(os.Args[4:], "random")
 		// Run the real Starknet order creation logic
-		openorder.RunStarknetOrder(command)
+		openorder.RunStarknetOrder(command, opts)
 	case "evm":
-		// Get the command (default to random-to-evm if not provided)
-		command := "random-to-evm"
-		if len(os.Args) > 4 {
-			command = os.Args[4]
-		}
+		// Get the command (default to random-to-evm if not provided) and the flags after it
+		command, opts := parseOpenOrderArgs(os.Args[4:], "random-to-evm")
 		// Run the real EVM order creation logic
-		openorder.RunEVMOrder(command)
+		openorder.RunEVMOrder(command, opts)
 	default:
 		fmt.Printf("Unknown chain: %s\n", chain)
 		fmt.Println("Available chains: starknet, evm")
@@ -119,6 +226,17 @@ func runOpenOrder() {
 	}
 }
 
+// parseOpenOrderArgs splits the open-order command from its deadline and nonce flags
+func parseOpenOrderArgs(args []string, defaultCommand string) (string, openorder.OrderOptions) {
+	command, flags := openorder.SplitOrderArgs(args, defaultCommand)
+	opts, err := openorder.ParseOrderOptions(flags, time.Now())
+	if err != nil {
+		fmt.Printf("Invalid open-order options: %v\n", err)
+		os.Exit(1)
+	}
+	return command, opts
+}
+
 func runSetupForks() {
 	if len(os.Args) < 4 {
 		fmt.Println("Usage: solver tools setup-forks <command>")
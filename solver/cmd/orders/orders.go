@@ -0,0 +1,160 @@
+package orders
+
+// Orders command - inspects the order store
+//
+// Usage:
+//
+//	solver orders export [--origin CHAIN] [--destination CHAIN] [--status S1,S2]
+//	                     [--from DATE] [--to DATE] [--format csv|json] [--output FILE]
+//	solver orders status <orderID> [--format text|json]
+//
+// CHAIN is a network name from the configuration or a chain ID. DATE is RFC3339 or
+// YYYY-MM-DD; the range is [from, to) over the time orders were first observed.
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/cmd/cliout"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	orderstore "github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+)
+
+// RunOrders dispatches orders subcommands; args excludes the "orders" command itself
+func RunOrders(args []string) error {
+	if len(args) == 0 {
+		printUsage()
+		return fmt.Errorf("missing orders subcommand")
+	}
+
+	switch args[0] {
+	case "export":
+		return runExport(args[1:])
+	case "status":
+		return runStatus(args[1:])
+	default:
+		printUsage()
+		return fmt.Errorf("unknown orders subcommand: %s", args[0])
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage: solver orders export [--origin CHAIN] [--destination CHAIN] [--status S1,S2]")
+	fmt.Println("                            [--from DATE] [--to DATE] [--format csv|json] [--output FILE]")
+	fmt.Println("       solver orders status <orderID> [--format text|json]")
+	fmt.Println("  CHAIN is a network name or chain ID; DATE is RFC3339 or YYYY-MM-DD")
+	fmt.Println("  Statuses: OBSERVED, REJECTED, FAILED, FILLED, SETTLED, LOST_RACE, EXPIRED")
+}
+
+// exportOptions are the parsed flags of the export subcommand
+type exportOptions struct {
+	origin      string
+	destination string
+	statuses    []orderstore.Status
+	from        time.Time
+	to          time.Time
+	format      string
+	output      string
+}
+
+func parseExportFlags(args []string) (exportOptions, error) {
+	var opts exportOptions
+	var statuses, from, to string
+
+	fs := flag.NewFlagSet("orders export", flag.ContinueOnError)
+	fs.StringVar(&opts.origin, "origin", "", "origin network name or chain ID")
+	fs.StringVar(&opts.destination, "destination", "", "destination network name or chain ID")
+	fs.StringVar(&statuses, "status", "", "comma-separated statuses to include")
+	fs.StringVar(&from, "from", "", "start of the range (inclusive)")
+	fs.StringVar(&to, "to", "", "end of the range (exclusive)")
+	fs.StringVar(&opts.format, "format", cliout.DefaultFormat("csv"), "output format: csv or json")
+	fs.StringVar(&opts.output, "output", "", "write to file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+
+	if statuses != "" {
+		for _, value := range strings.Split(statuses, ",") {
+			status, err := orderstore.ParseStatus(value)
+			if err != nil {
+				return opts, err
+			}
+			opts.statuses = append(opts.statuses, status)
+		}
+	}
+
+	var err error
+	if opts.from, err = parseTime(from); err != nil {
+		return opts, fmt.Errorf("invalid --from: %w", err)
+	}
+	if opts.to, err = parseTime(to); err != nil {
+		return opts, fmt.Errorf("invalid --to: %w", err)
+	}
+	if !opts.from.IsZero() && !opts.to.IsZero() && !opts.from.Before(opts.to) {
+		return opts, fmt.Errorf("--from must be before --to")
+	}
+	if opts.format != "csv" && opts.format != "json" {
+		return opts, fmt.Errorf("unsupported format %q (use csv or json)", opts.format)
+	}
+	return opts, nil
+}
+
+// parseTime accepts RFC3339 timestamps and YYYY-MM-DD dates (UTC); empty means unbounded
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// resolveChainID maps a network name or numeric chain ID to a chain ID; empty means any chain
+func resolveChainID(value string) (uint64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if id, err := strconv.ParseUint(value, 10, 64); err == nil {
+		return id, nil
+	}
+	return config.GetChainID(value)
+}
+
+func runExport(args []string) error {
+	opts, err := parseExportFlags(args)
+	if err != nil {
+		return err
+	}
+
+	// Network names in the filters resolve against the configured networks
+	if _, err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	config.InitializeNetworks()
+
+	filter := orderstore.Filter{Statuses: opts.statuses, From: opts.from, To: opts.to}
+	if filter.OriginChainID, err = resolveChainID(opts.origin); err != nil {
+		return fmt.Errorf("invalid --origin: %w", err)
+	}
+	if filter.DestinationChainID, err = resolveChainID(opts.destination); err != nil {
+		return fmt.Errorf("invalid --destination: %w", err)
+	}
+
+	store, err := orderstore.DefaultStore()
+	if err != nil {
+		return fmt.Errorf("failed to open order store: %w", err)
+	}
+
+	records := orderstore.Export(store.List(), filter)
+	return cliout.WriteOutput(opts.output, func(w io.Writer) error {
+		if opts.format == "json" {
+			return orderstore.WriteExportJSON(w, records)
+		}
+		return orderstore.WriteExportCSV(w, records)
+	})
+}
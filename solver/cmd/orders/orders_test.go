@@ -0,0 +1,106 @@
+package orders
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	orderstore "github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseExportFlags tests flag parsing for the orders export
+func TestParseExportFlags(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		opts, err := parseExportFlags(nil)
+		require.NoError(t, err)
+		assert.Empty(t, opts.statuses)
+		assert.True(t, opts.from.IsZero())
+		assert.Equal(t, "csv", opts.format)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		opts, err := parseExportFlags([]string{
+			"--origin", "Base", "--destination", "23448591",
+			"--status", "rejected,Settled", "--from", "2026-01-01", "--format", "json",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Base", opts.origin)
+		assert.Equal(t, "23448591", opts.destination)
+		assert.Equal(t, []orderstore.Status{orderstore.StatusRejected, orderstore.StatusSettled}, opts.statuses)
+		assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), opts.from)
+		assert.Equal(t, "json", opts.format)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseExportFlags([]string{"--status", "pending"})
+		assert.Error(t, err)
+
+		_, err = parseExportFlags([]string{"--from", "2026-02-01", "--to", "2026-01-01"})
+		assert.Error(t, err)
+
+		_, err = parseExportFlags([]string{"--format", "xml"})
+		assert.Error(t, err)
+	})
+
+	t.Run("numeric_chain_id", func(t *testing.T) {
+		id, err := resolveChainID("84532")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(84532), id)
+
+		id, err = resolveChainID("")
+		require.NoError(t, err)
+		assert.Zero(t, id)
+	})
+
+	t.Run("unknown_subcommand", func(t *testing.T) {
+		assert.Error(t, RunOrders([]string{"import"}))
+		assert.Error(t, RunOrders(nil))
+	})
+}
+
+// TestStatus tests the status subcommand's flags and text output
+func TestStatus(t *testing.T) {
+	t.Run("flags", func(t *testing.T) {
+		orderID, format, err := parseStatusFlags([]string{"0x01", "--format", "json"})
+		require.NoError(t, err)
+		assert.Equal(t, "0x01", orderID)
+		assert.Equal(t, "json", format)
+
+		orderID, format, err = parseStatusFlags([]string{"--format", "text", "0x02"})
+		require.NoError(t, err)
+		assert.Equal(t, "0x02", orderID)
+		assert.Equal(t, "text", format)
+
+		_, _, err = parseStatusFlags(nil)
+		assert.Error(t, err)
+		_, _, err = parseStatusFlags([]string{"0x01", "--format", "csv"})
+		assert.Error(t, err)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		sentAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		var buf strings.Builder
+		require.NoError(t, writeStatus(&buf, orderstore.Order{
+			OrderID:            "0x01",
+			OriginChainID:      111,
+			DestinationChainID: 222,
+			Status:             orderstore.StatusFilled,
+			UpdatedAt:          sentAt,
+			Txs: []orderstore.OrderTx{
+				{ChainID: 222, Kind: orderstore.TxKindFill, TxHash: "0xfill", SentAt: sentAt},
+				{ChainID: 222, Kind: orderstore.TxKindFill, TxHash: "0xreplacement", SentAt: sentAt},
+			},
+		}))
+		out := buf.String()
+		assert.Contains(t, out, "Route:       chain-111 -> chain-222")
+		assert.Contains(t, out, "Status:      FILLED")
+		assert.Contains(t, out, "fill  chain-222  2026-03-01T12:00:00Z  0xfill         -\n")
+		assert.Contains(t, out, "0xreplacement")
+
+		buf.Reset()
+		require.NoError(t, writeStatus(&buf, orderstore.Order{OrderID: "0x02", Status: orderstore.StatusObserved}))
+		assert.Contains(t, buf.String(), "Transactions: none sent")
+	})
+}
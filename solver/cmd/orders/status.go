@@ -0,0 +1,100 @@
+package orders
+
+// Status subcommand - one stored order with the fill and settle transactions the solver
+// broadcast for it, mined or not, and their explorer links (see config.ExplorerTxURL)
+//
+// Usage:
+//
+//	solver orders status <orderID> [--format text|json]
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/cmd/cliout"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	orderstore "github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+)
+
+// parseStatusFlags returns the order ID and output format of the status subcommand
+func parseStatusFlags(args []string) (string, string, error) {
+	fs := flag.NewFlagSet("orders status", flag.ContinueOnError)
+	format := fs.String("format", cliout.DefaultFormat("text"), "output format: text or json")
+
+	// The order ID may come before or after the flags
+	var orderID string
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		orderID, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return "", "", err
+	}
+	if orderID == "" && fs.NArg() > 0 {
+		orderID = fs.Arg(0)
+	}
+	if orderID == "" {
+		return "", "", fmt.Errorf("missing order ID")
+	}
+	if *format != "text" && *format != "json" {
+		return "", "", fmt.Errorf("unsupported format %q (use text or json)", *format)
+	}
+	return orderID, *format, nil
+}
+
+func runStatus(args []string) error {
+	orderID, format, err := parseStatusFlags(args)
+	if err != nil {
+		return err
+	}
+
+	// Chain IDs are shown as the configured network names
+	if _, err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	config.InitializeNetworks()
+
+	store, err := orderstore.DefaultStore()
+	if err != nil {
+		return fmt.Errorf("failed to open order store: %w", err)
+	}
+	order, ok := store.Get(orderID)
+	if !ok {
+		return fmt.Errorf("%w: %s", orderstore.ErrOrderNotFound, orderID)
+	}
+
+	if format == "json" {
+		return cliout.WriteJSON(order)
+	}
+	return writeStatus(cliout.Stdout(), order)
+}
+
+// writeStatus prints the order's status and its transactions, one per line
+func writeStatus(w io.Writer, order orderstore.Order) error {
+	fmt.Fprintf(w, "Order:       %s\n", order.OrderID)
+	fmt.Fprintf(w, "Route:       %s -> %s\n", logutil.NetworkNameByChainID(order.OriginChainID), logutil.NetworkNameByChainID(order.DestinationChainID))
+	fmt.Fprintf(w, "Status:      %s\n", order.Status)
+	if order.Reason != "" {
+		fmt.Fprintf(w, "Reason:      %s\n", order.Reason)
+	}
+	fmt.Fprintf(w, "Updated:     %s\n", order.UpdatedAt.UTC().Format(time.RFC3339))
+
+	if len(order.Txs) == 0 {
+		_, err := fmt.Fprintln(w, "Transactions: none sent")
+		return err
+	}
+	fmt.Fprintln(w, "Transactions:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  KIND\tNETWORK\tSENT\tTX HASH\tEXPLORER")
+	for _, tx := range order.Txs {
+		link := config.ExplorerTxURL(tx.ChainID, tx.TxHash)
+		if link == "" {
+			link = "-"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", tx.Kind, logutil.NetworkNameByChainID(tx.ChainID), tx.SentAt.UTC().Format(time.RFC3339), tx.TxHash, link)
+	}
+	return tw.Flush()
+}
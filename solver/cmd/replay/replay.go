@@ -0,0 +1,128 @@
+package replay
+
+// Replay command - re-runs a recording of chain traffic through the solver without network
+//
+// Usage:
+//
+//	solver replay <FILE> [--format text|json] [--output FILE]
+//
+// FILE is written by a solver started with SOLVER_RECORD_FILE set. Every recorded event is
+// decoded and evaluated again; fills and settlements are answered from the recording.
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/NethermindEth/oif-starknet/solver/cmd/cliout"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/replay"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+)
+
+// RunReplay replays a recording; args excludes the "replay" command itself
+func RunReplay(args []string) error {
+	opts, err := parseReplayFlags(args)
+	if err != nil {
+		printUsage()
+		return err
+	}
+
+	recording, err := replay.Load(opts.file)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	config.InitializeNetworks()
+	if err := config.InitializeDomainRegistry(cfg.DomainMappings); err != nil {
+		return fmt.Errorf("invalid domain configuration: %w", err)
+	}
+
+	results, err := solvercore.NewSolverManager(cfg).Replay(context.Background(), recording)
+	if err != nil {
+		return fmt.Errorf("replay failed: %w", err)
+	}
+
+	return cliout.WriteOutput(opts.output, func(w io.Writer) error {
+		if opts.format == "json" {
+			return writeJSON(w, results)
+		}
+		return writeText(w, results)
+	})
+}
+
+func printUsage() {
+	fmt.Println("Usage: solver replay <FILE> [--format text|json] [--output FILE]")
+	fmt.Println("  FILE is a recording written with SOLVER_RECORD_FILE set")
+}
+
+// replayOptions are the parsed arguments of the replay command
+type replayOptions struct {
+	file   string
+	format string
+	output string
+}
+
+func parseReplayFlags(args []string) (replayOptions, error) {
+	var opts replayOptions
+
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.StringVar(&opts.format, "format", cliout.DefaultFormat("text"), "output format: text or json")
+	fs.StringVar(&opts.output, "output", "", "write to file instead of stdout")
+
+	// Accept the recording before or after the flags
+	if len(args) > 0 && len(args[0]) > 0 && args[0][0] != '-' {
+		opts.file, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if opts.file == "" {
+		if fs.NArg() != 1 {
+			return opts, fmt.Errorf("expected one recording file")
+		}
+		opts.file = fs.Arg(0)
+	} else if fs.NArg() != 0 {
+		return opts, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	if opts.format != "text" && opts.format != "json" {
+		return opts, fmt.Errorf("unsupported format %q (use text or json)", opts.format)
+	}
+	return opts, nil
+}
+
+func writeJSON(w io.Writer, results []contracts.ReplayedOrder) error {
+	if results == nil {
+		results = []contracts.ReplayedOrder{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
+
+func writeText(w io.Writer, results []contracts.ReplayedOrder) error {
+	processed := 0
+	for _, result := range results {
+		outcome := "skipped"
+		switch {
+		case result.Error != "":
+			outcome = "error: " + result.Error
+		case result.Processed:
+			outcome = "processed"
+			processed++
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\tblock %d\t%s\n", result.OrderID, result.OriginChain, result.BlockNumber, outcome); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d orders replayed, %d processed\n", len(results), processed)
+	return err
+}
@@ -0,0 +1,74 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseReplayFlags tests argument parsing for the replay command
+func TestParseReplayFlags(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		opts, err := parseReplayFlags([]string{"traffic.jsonl"})
+		require.NoError(t, err)
+		assert.Equal(t, "traffic.jsonl", opts.file)
+		assert.Equal(t, "text", opts.format)
+		assert.Empty(t, opts.output)
+	})
+
+	t.Run("file_before_or_after_flags", func(t *testing.T) {
+		opts, err := parseReplayFlags([]string{"traffic.jsonl", "--format", "json"})
+		require.NoError(t, err)
+		assert.Equal(t, "traffic.jsonl", opts.file)
+		assert.Equal(t, "json", opts.format)
+
+		opts, err = parseReplayFlags([]string{"--output", "out.json", "traffic.jsonl"})
+		require.NoError(t, err)
+		assert.Equal(t, "traffic.jsonl", opts.file)
+		assert.Equal(t, "out.json", opts.output)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseReplayFlags(nil)
+		assert.Error(t, err)
+
+		_, err = parseReplayFlags([]string{"a.jsonl", "b.jsonl"})
+		assert.Error(t, err)
+
+		_, err = parseReplayFlags([]string{"a.jsonl", "--format", "csv"})
+		assert.Error(t, err)
+	})
+}
+
+// TestWriteResults tests the text and JSON output of a replay
+func TestWriteResults(t *testing.T) {
+	results := []contracts.ReplayedOrder{
+		{OrderID: "0x01", OriginChain: "Base", BlockNumber: 10, Processed: true},
+		{OrderID: "0x02", OriginChain: "Starknet", BlockNumber: 11, Error: "insufficient balance"},
+	}
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeText(&buf, results))
+		out := buf.String()
+		assert.Contains(t, out, "0x01\tBase\tblock 10\tprocessed")
+		assert.Contains(t, out, "error: insufficient balance")
+		assert.Contains(t, out, "2 orders replayed, 1 processed")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeJSON(&buf, results))
+		var decoded []contracts.ReplayedOrder
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		assert.Equal(t, results, decoded)
+
+		buf.Reset()
+		require.NoError(t, writeJSON(&buf, nil))
+		assert.Equal(t, "[]\n", buf.String())
+	})
+}
@@ -0,0 +1,145 @@
+package report
+
+// Report command - summarizes solver economics from the order store
+//
+// Usage:
+//
+//	solver report pnl [--from DATE] [--to DATE] [--format csv|json] [--output FILE]
+//	solver report rejections [--from DATE] [--to DATE] [--format csv|json] [--output FILE]
+//
+// DATE is RFC3339 or YYYY-MM-DD; the range is [from, to) over the time orders were filled (pnl)
+// or first observed (rejections).
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/cmd/cliout"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/accounting"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+)
+
+// RunReport dispatches report subcommands; args excludes the "report" command itself
+func RunReport(args []string) error {
+	if len(args) == 0 {
+		printUsage()
+		return fmt.Errorf("missing report type")
+	}
+
+	switch args[0] {
+	case "pnl":
+		return runPnL(args[1:])
+	case "rejections":
+		return runRejections(args[1:])
+	default:
+		printUsage()
+		return fmt.Errorf("unknown report: %s", args[0])
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage: solver report pnl|rejections [--from DATE] [--to DATE] [--format csv|json] [--output FILE]")
+	fmt.Println("  DATE is RFC3339 or YYYY-MM-DD; orders filled (pnl) or observed (rejections) in [from, to) are included")
+}
+
+// reportOptions are the parsed flags of a report
+type reportOptions struct {
+	from   time.Time
+	to     time.Time
+	format string
+	output string
+}
+
+func parseReportFlags(name string, args []string) (reportOptions, error) {
+	var opts reportOptions
+	var from, to string
+
+	fs := flag.NewFlagSet("report "+name, flag.ContinueOnError)
+	fs.StringVar(&from, "from", "", "start of the range (inclusive)")
+	fs.StringVar(&to, "to", "", "end of the range (exclusive)")
+	fs.StringVar(&opts.format, "format", cliout.DefaultFormat("csv"), "output format: csv or json")
+	fs.StringVar(&opts.output, "output", "", "write to file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+
+	var err error
+	if opts.from, err = parseTime(from); err != nil {
+		return opts, fmt.Errorf("invalid --from: %w", err)
+	}
+	if opts.to, err = parseTime(to); err != nil {
+		return opts, fmt.Errorf("invalid --to: %w", err)
+	}
+	if !opts.from.IsZero() && !opts.to.IsZero() && !opts.from.Before(opts.to) {
+		return opts, fmt.Errorf("--from must be before --to")
+	}
+	if opts.format != "csv" && opts.format != "json" {
+		return opts, fmt.Errorf("unsupported format %q (use csv or json)", opts.format)
+	}
+	return opts, nil
+}
+
+// parseTime accepts RFC3339 timestamps and YYYY-MM-DD dates (UTC); empty means unbounded
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+func runPnL(args []string) error {
+	opts, err := parseReportFlags("pnl", args)
+	if err != nil {
+		return err
+	}
+
+	// Network names in the report come from the configured networks
+	if _, err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	config.InitializeNetworks()
+
+	store, err := orders.DefaultStore()
+	if err != nil {
+		return fmt.Errorf("failed to open order store: %w", err)
+	}
+
+	report := accounting.BuildPnLReport(store.List(), opts.from, opts.to)
+	return cliout.WriteOutput(opts.output, func(w io.Writer) error {
+		if opts.format == "json" {
+			return report.WriteJSON(w)
+		}
+		return report.WriteCSV(w)
+	})
+}
+
+func runRejections(args []string) error {
+	opts, err := parseReportFlags("rejections", args)
+	if err != nil {
+		return err
+	}
+
+	// SOLVER_ORDER_STORE_FILE may be set in .env
+	if _, err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	store, err := orders.DefaultStore()
+	if err != nil {
+		return fmt.Errorf("failed to open order store: %w", err)
+	}
+
+	counts := orders.CountRejections(store.List(), orders.Filter{From: opts.from, To: opts.to})
+	return cliout.WriteOutput(opts.output, func(w io.Writer) error {
+		if opts.format == "json" {
+			return orders.WriteRejectionsJSON(w, counts)
+		}
+		return orders.WriteRejectionsCSV(w, counts)
+	})
+}
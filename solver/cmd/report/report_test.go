@@ -0,0 +1,44 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseReportFlags tests flag parsing shared by the reports
+func TestParseReportFlags(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		opts, err := parseReportFlags("pnl", nil)
+		require.NoError(t, err)
+		assert.True(t, opts.from.IsZero())
+		assert.True(t, opts.to.IsZero())
+		assert.Equal(t, "csv", opts.format)
+	})
+
+	t.Run("dates_and_timestamps", func(t *testing.T) {
+		opts, err := parseReportFlags("pnl", []string{"--from", "2026-01-01", "--to", "2026-01-02T12:00:00Z", "--format", "json"})
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), opts.from)
+		assert.Equal(t, time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC), opts.to)
+		assert.Equal(t, "json", opts.format)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseReportFlags("pnl", []string{"--from", "yesterday"})
+		assert.Error(t, err)
+
+		_, err = parseReportFlags("pnl", []string{"--from", "2026-02-01", "--to", "2026-01-01"})
+		assert.Error(t, err)
+
+		_, err = parseReportFlags("rejections", []string{"--format", "xml"})
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown_report", func(t *testing.T) {
+		assert.Error(t, RunReport([]string{"volume"}))
+		assert.Error(t, RunReport(nil))
+	})
+}
@@ -0,0 +1,64 @@
+package solver
+
+// Options of the embeddable solver (see New); without options it behaves like the solver command:
+// config from .env and SOLVER_CONFIG_FILE, logs to the standard logrus logger, metrics in the
+// process-wide registry and the default rules only
+
+import (
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/sirupsen/logrus"
+)
+
+// Option configures a Solver created by New
+type Option func(*options)
+
+type options struct {
+	loadConfig func() (*config.Config, error)
+	logger     logrus.FieldLogger
+	metrics    *metrics.Registry
+	rules      []contracts.Rule
+}
+
+func defaultOptions() *options {
+	return &options{
+		loadConfig: config.LoadConfig,
+		logger:     logrus.StandardLogger(),
+	}
+}
+
+// WithConfig runs the solver with a config built by the caller instead of loading one.
+// Networks and secrets are still read from the environment.
+func WithConfig(cfg *config.Config) Option {
+	return WithConfigLoader(func() (*config.Config, error) { return cfg, nil })
+}
+
+// WithConfigLoader replaces config.LoadConfig as the config source
+func WithConfigLoader(load func() (*config.Config, error)) Option {
+	return func(o *options) {
+		o.loadConfig = load
+	}
+}
+
+// WithLogger sets the logger of the solver's lifecycle messages
+func WithLogger(logger logrus.FieldLogger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithMetricsRegistry records the solver's metrics in the given registry instead of metrics.Default().
+// The registry becomes the process-wide default, so only one embedded solver should set it.
+func WithMetricsRegistry(registry *metrics.Registry) Option {
+	return func(o *options) {
+		o.metrics = registry
+	}
+}
+
+// WithRules adds validation rules evaluated after the default rules
+func WithRules(rules ...contracts.Rule) Option {
+	return func(o *options) {
+		o.rules = append(o.rules, rules...)
+	}
+}
@@ -0,0 +1,80 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rejectAll is a custom rule refusing every order
+type rejectAll struct{}
+
+func (rejectAll) Name() string { return "RejectAll" }
+
+func (rejectAll) Evaluate(context.Context, *types.ParsedArgs) contracts.RuleResult {
+	return contracts.RuleResult{Reason: "rejected"}
+}
+
+func TestOptions(t *testing.T) {
+	t.Run("defaults match the solver command", func(t *testing.T) {
+		o := defaultOptions()
+		assert.NotNil(t, o.loadConfig)
+		assert.Same(t, logrus.StandardLogger(), o.logger)
+		assert.Nil(t, o.metrics)
+		assert.Empty(t, o.rules)
+	})
+
+	t.Run("options override the defaults", func(t *testing.T) {
+		cfg := &config.Config{LogLevel: "debug"}
+		logger, _ := test.NewNullLogger()
+		registry := metrics.NewRegistry()
+
+		o := defaultOptions()
+		for _, opt := range []Option{
+			WithConfig(cfg),
+			WithLogger(logger),
+			WithMetricsRegistry(registry),
+			WithRules(rejectAll{}),
+			WithRules(rejectAll{}),
+		} {
+			opt(o)
+		}
+		loaded, err := o.loadConfig()
+		require.NoError(t, err)
+		assert.Same(t, cfg, loaded)
+		assert.Same(t, logger, o.logger)
+		assert.Same(t, registry, o.metrics)
+		assert.Len(t, o.rules, 2)
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("config errors are returned", func(t *testing.T) {
+		_, err := New(WithConfigLoader(func() (*config.Config, error) { return nil, errors.New("no file") }))
+		assert.EqualError(t, err, "failed to load configuration: no file")
+	})
+
+	t.Run("embedded solver uses the given config and metrics registry", func(t *testing.T) {
+		config.ResetNetworks()
+		defer config.ResetNetworks()
+		previous := metrics.Default()
+		defer metrics.SetDefault(previous)
+
+		cfg := &config.Config{}
+		registry := metrics.NewRegistry()
+		solver, err := New(WithConfig(cfg), WithMetricsRegistry(registry), WithRules(rejectAll{}))
+		require.NoError(t, err)
+		assert.Same(t, registry, metrics.Default())
+		assert.NotNil(t, solver.Manager())
+		assert.True(t, solver.Manager().GetSolverStatus()["hyperlane7683"])
+	})
+}
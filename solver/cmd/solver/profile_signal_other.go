@@ -0,0 +1,8 @@
+//go:build !unix
+
+package solver
+
+import "os"
+
+// notifyProfileSignal does nothing: SIGUSR2 only exists on unix systems
+func notifyProfileSignal(chan<- os.Signal) {}
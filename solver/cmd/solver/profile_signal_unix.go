@@ -0,0 +1,14 @@
+//go:build unix
+
+package solver
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyProfileSignal relays SIGUSR2, which asks for a goroutine dump and heap profile, to c
+func notifyProfileSignal(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGUSR2)
+}
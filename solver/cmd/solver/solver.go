@@ -1,17 +1,24 @@
 package solver
 
 // Solver package - contains the main solver logic
-// This allows the solver to be imported and run from the main CLI
+// This allows the solver to be imported and run from the main CLI, or embedded in another Go
+// service through New (see options.go)
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/admin"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/sirupsen/logrus"
 )
@@ -23,21 +30,96 @@ func (f *cleanFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	return append([]byte(entry.Message), '\n'), nil
 }
 
-// RunSolver runs the main solver application
-func RunSolver() {
+// Solver is the OIF solver embedded in a Go program
+type Solver struct {
+	cfg     *config.Config
+	logger  logrus.FieldLogger
+	manager *solvercore.SolverManager
+}
+
+// New loads the configuration and prepares a solver; Run starts it. Plugins can be added through
+// Manager before Run.
+func New(opts ...Option) (*Solver, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	// Load configuration
-	cfg, err := config.LoadConfig()
+	cfg, err := o.loadConfig()
 	if err != nil {
-		logrus.Fatalf("Failed to load configuration: %v", err)
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
 
 	// Initialize networks from centralized config after .env is loaded
 	config.InitializeNetworks()
 
+	// Build the domain <-> chain ID registry once, including custom mappings from the config file
+	if err := config.InitializeDomainRegistry(cfg.DomainMappings); err != nil {
+		return nil, fmt.Errorf("invalid domain configuration: %w", err)
+	}
+
+	// Allow/block lists, rule settings and poll intervals; reloadable through Reload
+	if err := config.ApplyRuntime(&cfg.RuntimeConfig, contracts.ValidateRuleConfig, logutil.ValidateLogLevels); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if o.metrics != nil {
+		metrics.SetDefault(o.metrics)
+	}
+
+	manager := solvercore.NewSolverManager(cfg)
+	for _, rule := range o.rules {
+		manager.AddRule(rule)
+	}
+	return &Solver{cfg: cfg, logger: o.logger, manager: manager}, nil
+}
+
+// Manager returns the solver manager, e.g. to register solvers and listeners before Run
+func (s *Solver) Manager() *solvercore.SolverManager {
+	return s.manager
+}
+
+// Reload re-reads the runtime config; an invalid config is rejected and the active one kept
+func (s *Solver) Reload() (*config.RuntimeConfig, error) {
+	return s.manager.ReloadConfig()
+}
+
+// Run starts the solver and blocks until ctx is cancelled, then shuts it down
+func (s *Solver) Run(ctx context.Context) error {
+	s.logger.Info("🚀 Starting OIF Starknet Solver...")
+	s.logger.Info("   📊 Monitoring networks:", strings.Join(config.GetNetworkNames(), ", "))
+	s.logger.Info("   ⏰ Poll interval: 1000ms (default)")
+
+	if err := s.manager.Start(ctx); err != nil {
+		return err
+	}
+	s.logger.Info("✅ Solver stopped gracefully")
+	return nil
+}
+
+// RunSolver runs the main solver application
+func RunSolver() {
 	// Set up clean logging
 	logrus.SetFormatter(&cleanFormatter{})
 	logrus.SetLevel(logrus.InfoLevel)
 
+	solver, err := New()
+	if err != nil {
+		logrus.Fatalf("Failed to start solver: %v", err)
+	}
+
+	// Copy log lines to the sinks in LOG_SINKS (read from .env, so after New)
+	stdout := os.Stdout
+	stopSinks, err := logutil.StartSinksFromEnv()
+	if err != nil {
+		logrus.Fatalf("Failed to open log sinks: %v", err)
+	}
+	defer stopSinks()
+	if os.Stdout != stdout {
+		logrus.SetOutput(os.Stdout)
+	}
+
 	// Create context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -52,20 +134,48 @@ func RunSolver() {
 		cancel()
 	}()
 
-	// Initialize solver manager
-	solverManager := solvercore.NewSolverManager(cfg)
+	// Reload the runtime config on SIGHUP; an invalid config is logged and the active one kept
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	defer signal.Stop(hupChan)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hupChan:
+				logrus.Info("🔄 SIGHUP received, reloading config...")
+				if _, err := solver.Reload(); err != nil {
+					logrus.Errorf("❌ %v", err)
+				}
+			}
+		}
+	}()
 
-	// Start the solver
-	logrus.Info("🚀 Starting OIF Starknet Solver...")
-	logrus.Info("   📊 Monitoring networks:", strings.Join(config.GetNetworkNames(), ", "))
-	logrus.Info("   ⏰ Poll interval: 1000ms (default)")
-	logrus.Info("   🛑 Press Ctrl+C to stop")
+	// Write a goroutine dump and heap profile on SIGUSR2, e.g. when a backfill seems stuck
+	profChan := make(chan os.Signal, 1)
+	notifyProfileSignal(profChan)
+	defer signal.Stop(profChan)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-profChan:
+				paths, err := admin.WriteProfiles(admin.ProfileDirFromEnv(), time.Now())
+				if err != nil {
+					logrus.Errorf("❌ Failed to write profiles: %v", err)
+					continue
+				}
+				logrus.Infof("🩺 SIGUSR2 received, profiles written to %s", strings.Join(paths, ", "))
+			}
+		}
+	}()
 
-	if err := solverManager.Start(ctx); err != nil {
+	logrus.Info("   🛑 Press Ctrl+C to stop")
+	if err := solver.Run(ctx); err != nil {
 		logrus.Fatalf("Solver failed: %v", err)
 	}
-
-	logrus.Info("✅ Solver stopped gracefully")
 }
 
 // TestConnection tests the connection to all configured networks
@@ -0,0 +1,407 @@
+package staterebuild
+
+// State command - reconstructs deployment-state.json from on-chain data when the file is lost or
+// corrupted
+//
+// Usage:
+//
+//	solver state rebuild [--manifest FILE] [--out FILE] [--timeout DURATION]
+//
+// Router addresses come from the network config and token addresses from the manifest, a JSON object
+// of token addresses by network and symbol:
+//
+//	{"Base": {"DOG": "0x..."}, "Starknet": {"DOG": "0x..."}}
+//
+// Without a manifest file the <NETWORK>_DOG_COIN_ADDRESS variables are used. Every node must report
+// the configured chain ID and every router and token must hold a contract, otherwise nothing is
+// written; each network is recorded with its current block number. A previous state file is kept
+// next to the new one as <out>.bak. With --json the rebuilt state is also printed, with its path.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/cmd/cliout"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	defaultManifestPath   = "state/deployment/token-manifest.json"
+	defaultStatePath      = "state/network_state/deployment-state.json"
+	defaultRebuildTimeout = 2 * time.Minute
+	// dogCoinSymbol is also written as dogCoinAddress, the field older readers of the state expect
+	dogCoinSymbol = "DOG"
+)
+
+// Manifest lists token addresses by network name and symbol
+type Manifest map[string]map[string]string
+
+// DeploymentState is the content of deployment-state.json
+type DeploymentState struct {
+	RebuiltAt time.Time                    `json:"rebuiltAt"`
+	Networks  map[string]NetworkDeployment `json:"networks"`
+}
+
+// NetworkDeployment is the deployment of one network
+type NetworkDeployment struct {
+	ChainID          uint64            `json:"chainId"`
+	HyperlaneAddress string            `json:"hyperlaneAddress"`
+	DogCoinAddress   string            `json:"dogCoinAddress,omitempty"`
+	Tokens           map[string]string `json:"tokens,omitempty"`
+	BlockNumber      uint64            `json:"blockNumber"`
+}
+
+// options are the parsed flags of the rebuild subcommand
+type options struct {
+	manifest string
+	out      string
+	timeout  time.Duration
+}
+
+// RunState dispatches state subcommands; args excludes the "state" command itself
+func RunState(args []string) error {
+	if len(args) == 0 {
+		printUsage()
+		return fmt.Errorf("missing state subcommand")
+	}
+
+	switch args[0] {
+	case "rebuild":
+		return runRebuild(args[1:])
+	default:
+		printUsage()
+		return fmt.Errorf("unknown state subcommand: %s", args[0])
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage: solver state rebuild [--manifest FILE] [--out FILE] [--timeout DURATION]")
+	fmt.Println("  Reconstructs deployment-state.json from the configured routers, a token manifest and on-chain data")
+}
+
+func runRebuild(args []string) error {
+	opts, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+	if _, err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	config.InitializeNetworks()
+
+	manifest, err := loadManifest(opts.manifest)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+	state, err := newRebuilder().rebuild(ctx, manifest)
+	if err != nil {
+		return err
+	}
+	if err := WriteState(opts.out, state); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Rebuilt %s (%d networks)\n", opts.out, len(state.Networks))
+	if cliout.JSON() {
+		return cliout.WriteJSON(rebuildResult{Path: opts.out, State: state})
+	}
+	return nil
+}
+
+// rebuildResult is the output of state rebuild with --json
+type rebuildResult struct {
+	Path  string           `json:"path"`
+	State *DeploymentState `json:"state"`
+}
+
+func parseFlags(args []string) (options, error) {
+	var opts options
+	fs := flag.NewFlagSet("state rebuild", flag.ContinueOnError)
+	fs.Usage = printUsage
+	fs.StringVar(&opts.manifest, "manifest", defaultManifestPath, "token manifest; <NETWORK>_DOG_COIN_ADDRESS is used when it does not exist")
+	fs.StringVar(&opts.out, "out", defaultStatePath, "deployment state file to write")
+	fs.DurationVar(&opts.timeout, "timeout", defaultRebuildTimeout, "time allowed for all RPC queries")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if opts.out == "" {
+		return opts, fmt.Errorf("--out must not be empty")
+	}
+	if opts.timeout <= 0 {
+		return opts, fmt.Errorf("--timeout must be positive")
+	}
+	return opts, nil
+}
+
+// loadManifest reads the manifest file, falling back to the DogCoin addresses of the environment
+// when the file does not exist
+func loadManifest(path string) (Manifest, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Printf("📋 No manifest at %s, using the <NETWORK>_DOG_COIN_ADDRESS variables\n", path)
+		return envManifest(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", path, err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	for network := range manifest {
+		if _, ok := config.Networks[network]; !ok {
+			return nil, fmt.Errorf("manifest lists unknown network %q", network)
+		}
+	}
+	return manifest, nil
+}
+
+// envManifest lists the <NETWORK>_DOG_COIN_ADDRESS of every configured network that sets one
+func envManifest() Manifest {
+	manifest := make(Manifest)
+	for name := range config.Networks {
+		if address := envutil.GetEnvWithDefault(config.NetworkEnvPrefix(name)+"_DOG_COIN_ADDRESS", ""); address != "" {
+			manifest[name] = map[string]string{dogCoinSymbol: address}
+		}
+	}
+	return manifest
+}
+
+// rebuilder queries the networks; tests replace the dialers
+type rebuilder struct {
+	dialEVM      func(rpcURL string) (hyperlane7683.EVMClient, error)
+	dialStarknet func(rpcURL string) (rpc.RPCProvider, error)
+}
+
+func newRebuilder() *rebuilder {
+	return &rebuilder{
+		dialEVM: func(rpcURL string) (hyperlane7683.EVMClient, error) {
+			client, err := ethclient.Dial(rpcURL)
+			if err != nil {
+				return nil, err
+			}
+			return client, nil
+		},
+		dialStarknet: func(rpcURL string) (rpc.RPCProvider, error) {
+			provider, err := rpc.NewProvider(rpcURL)
+			if err != nil {
+				return nil, err
+			}
+			return provider, nil
+		},
+	}
+}
+
+// rebuild queries every configured network and returns the state, or every problem found
+func (r *rebuilder) rebuild(ctx context.Context, manifest Manifest) (*DeploymentState, error) {
+	state := &DeploymentState{RebuiltAt: time.Now().UTC(), Networks: make(map[string]NetworkDeployment)}
+	var problems []string
+
+	names := config.GetNetworkNames()
+	sort.Strings(names)
+	for _, name := range names {
+		network := config.Networks[name]
+		fmt.Printf("🔍 Querying %s...\n", name)
+
+		var deployment NetworkDeployment
+		var errs []error
+		if config.IsStarknetNetwork(name) {
+			deployment, errs = r.starknetDeployment(ctx, network, manifest[name])
+		} else {
+			deployment, errs = r.evmDeployment(ctx, network, manifest[name])
+		}
+		if len(errs) > 0 {
+			for _, err := range errs {
+				fmt.Printf("   ❌ %v\n", err)
+				problems = append(problems, err.Error())
+			}
+			continue
+		}
+		deployment.DogCoinAddress = deployment.Tokens[dogCoinSymbol]
+		state.Networks[name] = deployment
+		fmt.Printf("   ✅ %s at block %d, %d tokens\n", name, deployment.BlockNumber, len(deployment.Tokens))
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("state not rebuilt, %d problems found:\n  %s", len(problems), strings.Join(problems, "\n  "))
+	}
+	return state, nil
+}
+
+// evmDeployment reads an EVM network's block number and checks its router and tokens hold code
+func (r *rebuilder) evmDeployment(ctx context.Context, network config.NetworkConfig, tokens map[string]string) (NetworkDeployment, []error) {
+	client, err := r.dialEVM(network.RPCURL)
+	if err != nil {
+		return NetworkDeployment{}, []error{fmt.Errorf("%s: cannot connect to %s: %w", network.Name, network.RPCURL, err)}
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return NetworkDeployment{}, []error{fmt.Errorf("%s: failed to read the chain ID: %w", network.Name, err)}
+	}
+	if err := config.VerifyEVMChainID(network, chainID); err != nil {
+		return NetworkDeployment{}, []error{err}
+	}
+	block, err := client.BlockNumber(ctx)
+	if err != nil {
+		return NetworkDeployment{}, []error{fmt.Errorf("%s: failed to read the block number: %w", network.Name, err)}
+	}
+
+	deployment := NetworkDeployment{ChainID: network.ChainID, HyperlaneAddress: network.HyperlaneAddress.Hex(), BlockNumber: block}
+	hasCode := func(label string, address common.Address) error {
+		code, err := client.CodeAt(ctx, address, nil)
+		if err != nil {
+			return fmt.Errorf("%s: failed to read the code of %s %s: %w", network.Name, label, address.Hex(), err)
+		}
+		if len(code) == 0 {
+			return fmt.Errorf("%s: no contract at %s %s", network.Name, label, address.Hex())
+		}
+		return nil
+	}
+
+	var errs []error
+	if err := hasCode("router", network.HyperlaneAddress); err != nil {
+		errs = append(errs, err)
+	}
+	for _, symbol := range sortedSymbols(tokens) {
+		if !common.IsHexAddress(tokens[symbol]) {
+			errs = append(errs, fmt.Errorf("%s: %s address %q is not an EVM address", network.Name, symbol, tokens[symbol]))
+			continue
+		}
+		address := common.HexToAddress(tokens[symbol])
+		if err := hasCode(symbol, address); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		deployment.addToken(symbol, address.Hex())
+	}
+	return deployment, errs
+}
+
+// starknetDeployment reads a Starknet network's block number and checks its router and tokens are deployed
+func (r *rebuilder) starknetDeployment(ctx context.Context, network config.NetworkConfig, tokens map[string]string) (NetworkDeployment, []error) {
+	provider, err := r.dialStarknet(network.RPCURL)
+	if err != nil {
+		return NetworkDeployment{}, []error{fmt.Errorf("%s: cannot connect to %s: %w", network.Name, network.RPCURL, err)}
+	}
+	chainID, err := provider.ChainID(ctx)
+	if err != nil {
+		return NetworkDeployment{}, []error{fmt.Errorf("%s: failed to read the chain ID: %w", network.Name, err)}
+	}
+	if err := config.VerifyStarknetChainID(network, chainID); err != nil {
+		return NetworkDeployment{}, []error{err}
+	}
+	block, err := provider.BlockNumber(ctx)
+	if err != nil {
+		return NetworkDeployment{}, []error{fmt.Errorf("%s: failed to read the block number: %w", network.Name, err)}
+	}
+
+	deployment := NetworkDeployment{ChainID: network.ChainID, BlockNumber: block}
+	deployed := func(label, address string) (string, error) {
+		addr, err := utils.HexToFelt(address)
+		if err != nil {
+			return "", fmt.Errorf("%s: %s address %q is not a Starknet address", network.Name, label, address)
+		}
+		if _, err := provider.ClassHashAt(ctx, rpc.WithBlockTag(rpc.BlockTagLatest), addr); err != nil {
+			return "", fmt.Errorf("%s: no contract at %s %s: %w", network.Name, label, addr, err)
+		}
+		return addr.String(), nil
+	}
+
+	var errs []error
+	routerEnv := config.StarknetHyperlaneAddressEnv(network.Name)
+	if router, err := deployed("router", config.StarknetHyperlaneAddress(network.Name)); err != nil {
+		errs = append(errs, fmt.Errorf("%w (check %s)", err, routerEnv))
+	} else {
+		deployment.HyperlaneAddress = router
+	}
+	for _, symbol := range sortedSymbols(tokens) {
+		address, err := deployed(symbol, tokens[symbol])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		deployment.addToken(symbol, address)
+	}
+	return deployment, errs
+}
+
+func (d *NetworkDeployment) addToken(symbol, address string) {
+	if d.Tokens == nil {
+		d.Tokens = make(map[string]string)
+	}
+	d.Tokens[symbol] = address
+}
+
+// sortedSymbols returns the symbols of tokens in order, so problems are reported deterministically
+func sortedSymbols(tokens map[string]string) []string {
+	symbols := make([]string, 0, len(tokens))
+	for symbol := range tokens {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// ReadState reads a deployment state file; a missing file is an empty state
+func ReadState(path string) (*DeploymentState, error) {
+	state := &DeploymentState{Networks: make(map[string]NetworkDeployment)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if state.Networks == nil {
+		state.Networks = make(map[string]NetworkDeployment)
+	}
+	return state, nil
+}
+
+// WriteState writes the state through a temporary file, keeping a previous file as path.bak
+func WriteState(path string, state *DeploymentState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the deployment state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if previous, err := os.ReadFile(path); err == nil {
+		if err := os.WriteFile(path+".bak", previous, 0600); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+		fmt.Printf("📦 Previous state kept as %s.bak\n", path)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package staterebuild
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testStarknetRouter = "0x5678"
+	testStarknetDog    = "0x9abc"
+)
+
+var (
+	testRouter = common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	testDog    = common.HexToAddress("0x00000000000000000000000000000000000000d0")
+)
+
+func TestParseFlags(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		opts, err := parseFlags(nil)
+		require.NoError(t, err)
+		assert.Equal(t, defaultManifestPath, opts.manifest)
+		assert.Equal(t, defaultStatePath, opts.out)
+		assert.Equal(t, defaultRebuildTimeout, opts.timeout)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseFlags([]string{"--out", ""})
+		assert.ErrorContains(t, err, "--out must not be empty")
+		_, err = parseFlags([]string{"--timeout", "0s"})
+		assert.ErrorContains(t, err, "--timeout must be positive")
+		_, err = parseFlags([]string{"Base"})
+		assert.ErrorContains(t, err, "unexpected argument")
+	})
+
+	t.Run("unknown subcommand", func(t *testing.T) {
+		assert.ErrorContains(t, RunState([]string{"repair"}), "unknown state subcommand")
+		assert.ErrorContains(t, RunState(nil), "missing state subcommand")
+	})
+}
+
+// setupNetworks configures the routers of the built-in networks
+func setupNetworks(t *testing.T) {
+	t.Helper()
+	t.Setenv("IS_DEVNET", "false")
+	t.Setenv("EVM_HYPERLANE_ADDRESS", testRouter.Hex())
+	t.Setenv("STARKNET_HYPERLANE_ADDRESS", testStarknetRouter)
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	t.Cleanup(config.ResetNetworks)
+}
+
+func TestLoadManifest(t *testing.T) {
+	setupNetworks(t)
+
+	t.Run("manifest file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "manifest.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"Base": {"DOG": "0xd0", "USDC": "0xe0"}}`), 0o600))
+		manifest, err := loadManifest(path)
+		require.NoError(t, err)
+		assert.Equal(t, Manifest{"Base": {"DOG": "0xd0", "USDC": "0xe0"}}, manifest)
+	})
+
+	t.Run("unknown network", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "manifest.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"Solana": {"DOG": "0xd0"}}`), 0o600))
+		_, err := loadManifest(path)
+		assert.ErrorContains(t, err, `unknown network "Solana"`)
+	})
+
+	t.Run("invalid manifest", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "manifest.json")
+		require.NoError(t, os.WriteFile(path, []byte(`["Base"]`), 0o600))
+		_, err := loadManifest(path)
+		assert.ErrorContains(t, err, "failed to parse manifest")
+	})
+
+	t.Run("missing file falls back to the environment", func(t *testing.T) {
+		for name := range config.Networks {
+			t.Setenv(config.NetworkEnvPrefix(name)+"_DOG_COIN_ADDRESS", "")
+		}
+		t.Setenv("BASE_DOG_COIN_ADDRESS", testDog.Hex())
+		t.Setenv("STARKNET_DOG_COIN_ADDRESS", testStarknetDog)
+		manifest, err := loadManifest(filepath.Join(t.TempDir(), "missing.json"))
+		require.NoError(t, err)
+		assert.Equal(t, Manifest{"Base": {"DOG": testDog.Hex()}, "Starknet": {"DOG": testStarknetDog}}, manifest)
+	})
+}
+
+// testChains are in-memory chains with the routers and DogCoin deployed on every network
+type testChains struct {
+	evm      map[string]*chainmock.EVMBackend // by network name
+	starknet *chainmock.StarknetBackend
+}
+
+func newTestChains() *testChains {
+	chains := &testChains{evm: make(map[string]*chainmock.EVMBackend), starknet: chainmock.NewStarknetBackend()}
+	for name, network := range config.Networks {
+		if config.IsStarknetNetwork(name) {
+			continue
+		}
+		backend := chainmock.NewEVMBackend(network.ChainID)
+		backend.HandleCall(testRouter, chainmock.Selector("localDomain()"), nil)
+		backend.HandleCall(testDog, chainmock.Selector("decimals()"), nil)
+		backend.SetBlockNumber(network.ChainID % 1000)
+		chains.evm[name] = backend
+	}
+	for _, address := range []string{testStarknetRouter, testStarknetDog} {
+		contract, _ := new(felt.Felt).SetString(address)
+		chains.starknet.HandleCall(contract, "decimals", func(rpc.FunctionCall) ([]*felt.Felt, error) { return nil, nil })
+	}
+	return chains
+}
+
+// manifest lists DogCoin on every network
+func (c *testChains) manifest() Manifest {
+	manifest := Manifest{"Starknet": {"DOG": testStarknetDog}}
+	for name := range c.evm {
+		manifest[name] = map[string]string{"DOG": testDog.Hex()}
+	}
+	return manifest
+}
+
+func (c *testChains) rebuilder() *rebuilder {
+	byURL := make(map[string]*chainmock.EVMBackend)
+	for name, backend := range c.evm {
+		byURL[config.Networks[name].RPCURL] = backend
+	}
+	return &rebuilder{
+		dialEVM: func(rpcURL string) (hyperlane7683.EVMClient, error) {
+			if backend, ok := byURL[rpcURL]; ok {
+				return backend, nil
+			}
+			return nil, fmt.Errorf("connection refused")
+		},
+		dialStarknet: func(string) (rpc.RPCProvider, error) { return c.starknet, nil },
+	}
+}
+
+func TestRebuild(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("every network is recorded", func(t *testing.T) {
+		setupNetworks(t)
+		chains := newTestChains()
+		state, err := chains.rebuilder().rebuild(ctx, chains.manifest())
+		require.NoError(t, err)
+
+		require.Len(t, state.Networks, len(config.Networks))
+		base := state.Networks["Base"]
+		assert.Equal(t, NetworkDeployment{
+			ChainID:          config.BaseSepoliaChainID,
+			HyperlaneAddress: testRouter.Hex(),
+			DogCoinAddress:   testDog.Hex(),
+			Tokens:           map[string]string{"DOG": testDog.Hex()},
+			BlockNumber:      config.BaseSepoliaChainID % 1000,
+		}, base)
+		starknet := state.Networks["Starknet"]
+		assert.Equal(t, testStarknetRouter, starknet.HyperlaneAddress)
+		assert.Equal(t, testStarknetDog, starknet.DogCoinAddress)
+	})
+
+	t.Run("networks without tokens keep their router", func(t *testing.T) {
+		setupNetworks(t)
+		chains := newTestChains()
+		state, err := chains.rebuilder().rebuild(ctx, Manifest{})
+		require.NoError(t, err)
+		assert.Empty(t, state.Networks["Optimism"].Tokens)
+		assert.Equal(t, testRouter.Hex(), state.Networks["Optimism"].HyperlaneAddress)
+	})
+
+	t.Run("every problem is reported", func(t *testing.T) {
+		setupNetworks(t)
+		chains := newTestChains()
+		chains.evm["Base"] = chainmock.NewEVMBackend(1)
+		chains.evm["Optimism"] = chainmock.NewEVMBackend(config.OptimismSepoliaChainID)
+		delete(chains.evm, "Arbitrum")
+		manifest := chains.manifest()
+		manifest["Ethereum"]["USDC"] = "not-an-address"
+		manifest["Starknet"]["USDC"] = "0xe0"
+
+		_, err := chains.rebuilder().rebuild(ctx, manifest)
+		require.Error(t, err)
+		for _, problem := range []string{
+			"Base RPC reports chain ID 1",
+			"Optimism: no contract at router",
+			"Optimism: no contract at DOG",
+			"Arbitrum: cannot connect",
+			`Ethereum: USDC address "not-an-address" is not an EVM address`,
+			"Starknet: no contract at USDC 0xe0",
+		} {
+			assert.Contains(t, err.Error(), problem)
+		}
+	})
+
+	t.Run("RPC failures stop the network", func(t *testing.T) {
+		setupNetworks(t)
+		chains := newTestChains()
+		chains.evm["Base"].FailNext("BlockNumber", errors.New("timeout"))
+		_, err := chains.rebuilder().rebuild(ctx, chains.manifest())
+		assert.ErrorContains(t, err, "Base: failed to read the block number: timeout")
+	})
+}
+
+func TestWriteState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "network_state", "deployment-state.json")
+	state := &DeploymentState{Networks: map[string]NetworkDeployment{"Base": {ChainID: 84532, HyperlaneAddress: testRouter.Hex(), BlockNumber: 7}}}
+
+	require.NoError(t, WriteState(path, state))
+	var written DeploymentState
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, state.Networks, written.Networks)
+	assert.NoFileExists(t, path+".bak")
+
+	state.Networks["Base"] = NetworkDeployment{ChainID: 84532, BlockNumber: 8}
+	require.NoError(t, WriteState(path, state))
+	previous, err := os.ReadFile(path + ".bak")
+	require.NoError(t, err)
+	assert.Equal(t, data, previous)
+	assert.NoFileExists(t, path+".tmp")
+
+	read, err := ReadState(path)
+	require.NoError(t, err)
+	assert.Equal(t, state.Networks, read.Networks)
+
+	missing, err := ReadState(filepath.Join(t.TempDir(), "missing.json"))
+	require.NoError(t, err)
+	assert.Empty(t, missing.Networks)
+	assert.NotNil(t, missing.Networks)
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"math/big"
 	"os"
@@ -11,11 +13,13 @@ import (
 	"time"
 
 	"github.com/NethermindEth/starknet.go/account"
-	"github.com/NethermindEth/starknet.go/contracts"
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/joho/godotenv"
 
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/artifacts"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 )
 
@@ -26,12 +30,27 @@ const (
 	deploymentFilePerms = 0600
 )
 
-const (
-	sierraContractFilePath = "../cairo/target/dev/oif_starknet_Hyperlane7683.contract_class.json"
-	casmContractFilePath   = "../cairo/target/dev/oif_starknet_Hyperlane7683.compiled_contract_class.json"
-)
+// contractName is the contract whose classes are declared
+const contractName = "Hyperlane7683"
 
 func main() {
+	maxFeeFlag := flag.String("max-fee", "", "most the declare may cost, in STRK (e.g. 5); default no cap")
+	multiplierFlag := flag.Float64("fee-multiplier", starknetutil.DefaultFeeMultiplier, "headroom over the estimated resource amounts and prices")
+	var artifactOpts artifacts.Options
+	flag.StringVar(&artifactOpts.Sierra, "sierra", "", "Sierra class file (default: found in the artifacts directory)")
+	flag.StringVar(&artifactOpts.Casm, "casm", "", "CASM class file (default: found in the artifacts directory)")
+	flag.StringVar(&artifactOpts.Dir, "artifacts-dir", "", "directory of the compiled classes (default $"+artifacts.DirEnv+" or "+artifacts.DefaultDir+")")
+	flag.Parse()
+
+	opts := starknetutil.DeclareOptions{Multiplier: *multiplierFlag}
+	if *maxFeeFlag != "" {
+		maxFee, err := amount.Parse(*maxFeeFlag, starknetutil.TokenDecimals)
+		if err != nil {
+			panic(fmt.Sprintf("❌ Invalid --max-fee: %s", err))
+		}
+		opts.MaxFee = maxFee.Units
+	}
+
 	if err := godotenv.Load(); err != nil {
 		fmt.Println("⚠️  No .env file found, using environment variables")
 	}
@@ -97,52 +116,54 @@ func main() {
 
 	fmt.Println("✅ Connected to Starknet RPC")
 
-	fmt.Printf("📋 Loading contract files:\n")
-	fmt.Printf("   Sierra: %s\n", sierraContractFilePath)
-	fmt.Printf("   Casm: %s\n", casmContractFilePath)
-
-	// Unmarshalling the casm contract class from a JSON file.
-	casmClass, err := utils.UnmarshalJSONFileToType[contracts.CasmClass](casmContractFilePath, "")
-	if err != nil {
-		panic(fmt.Sprintf("❌ Failed to parse casm contract: %s", err))
-	}
-
-	// Unmarshalling the sierra contract class from a JSON file.
-	contractClass, err := utils.UnmarshalJSONFileToType[contracts.ContractClass](sierraContractFilePath, "")
+	classes, err := artifacts.Load(contractName, artifactOpts)
 	if err != nil {
-		panic(fmt.Sprintf("❌ Failed to parse sierra contract: %s", err))
+		panic(fmt.Sprintf("❌ Failed to load contract classes: %s", err))
 	}
+	fmt.Printf("📋 Loaded contract classes:\n")
+	fmt.Printf("   Sierra: %s\n", classes.SierraSource)
+	fmt.Printf("   Casm: %s\n", classes.CasmSource)
 
-	// Building and sending the Broadcast Invoke Txn.
-	resp, err := accnt.BuildAndSendDeclareTxn(
-		context.Background(),
-		casmClass,
-		contractClass,
-		nil,
-	)
+	// Estimating the fee and sending a v3 declare within the resource bounds
+	fmt.Println("📤 Declaring contract...")
+	declarer := starknetutil.NewAccountClient(accnt)
+	resp, err := declarer.Declare(context.Background(), classes.Casm, classes.Sierra, opts)
 	if err != nil {
-		if strings.Contains(err.Error(), "is already declared") {
-			fmt.Println("✅ Contract already declared")
-			// Extract class hash from error message if possible, or just exit
+		if errors.Is(err, rpc.ErrClassAlreadyDeclared) || strings.Contains(err.Error(), "is already declared") {
 			fmt.Printf("⚠️  Contract is already declared, skipping\n")
 			return
 		}
-		panic(fmt.Sprintf("❌ Declaration failed: %s", err))
+		reportDeclareFailure(declarer, err)
 	}
+	fmt.Printf("⛽ Estimated fee: %s STRK (max %s STRK)\n",
+		amount.New(resp.EstimatedFee, starknetutil.TokenDecimals), amount.New(resp.MaxFee, starknetutil.TokenDecimals))
 
-	// Building and sending the declare transaction
-	fmt.Println("📤 Declaring contract...")
-	_, err = accnt.WaitForTransactionReceipt(context.Background(), resp.Hash, time.Second)
+	txHash, err := utils.HexToFelt(resp.Hash)
 	if err != nil {
+		panic(fmt.Sprintf("❌ Invalid declare txn hash: %s", err))
+	}
+	if _, err := declarer.WaitForAcceptance(context.Background(), txHash, time.Second); err != nil {
 		panic(fmt.Sprintf("❌ Declare txn failed: %s", err))
 	}
 
-	fmt.Printf("Class hash: %s\n", resp.ClassHash)
 	fmt.Printf("✅ Contract declaration completed!\n")
 	fmt.Printf("   Class Hash: %s\n", resp.ClassHash)
 
 	// Save declaration info
-	saveDeclarationInfo(resp.Hash.String(), resp.ClassHash.String(), networkName)
+	saveDeclarationInfo(resp.Hash, resp.ClassHash, networkName)
+}
+
+// reportDeclareFailure prints why a declare failed, with the account's STRK balance, and exits
+func reportDeclareFailure(declarer *starknetutil.Client, err error) {
+	fmt.Printf("❌ Declaration failed: %s\n", err)
+	if hint := starknetutil.DeclareFailureHint(err); hint != "" {
+		fmt.Printf("   💡 %s\n", hint)
+	}
+	strk, _ := utils.HexToFelt(starknetutil.STRKTokenAddress)
+	if balance, balanceErr := declarer.BalanceOf(context.Background(), strk, declarer.Account.Address); balanceErr == nil {
+		fmt.Printf("   💰 Account STRK balance: %s\n", amount.New(balance, starknetutil.TokenDecimals))
+	}
+	os.Exit(1)
 }
 
 // saveDeclarationInfo saves declaration information to a file
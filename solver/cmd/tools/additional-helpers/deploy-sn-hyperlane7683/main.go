@@ -15,6 +15,7 @@ import (
 	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/joho/godotenv"
 
+	"github.com/NethermindEth/oif-starknet/solver/pkg/receiptwait"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 )
 
@@ -148,7 +149,7 @@ func main() {
 	fmt.Println("⏳ Waiting for transaction confirmation...")
 
 	// Wait for transaction receipt
-	txReceipt, err := accnt.WaitForTransactionReceipt(context.Background(), txHash, time.Second)
+	txReceipt, err := receiptwait.Starknet(context.Background(), accnt.Provider, txHash, config.ExpectedBlockTime(networkName))
 	if err != nil {
 		panic(fmt.Sprintf("❌ Failed to get transaction receipt: %s", err))
 	}
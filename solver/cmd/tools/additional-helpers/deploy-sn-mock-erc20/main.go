@@ -16,6 +16,7 @@ import (
 	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/joho/godotenv"
 
+	"github.com/NethermindEth/oif-starknet/solver/pkg/receiptwait"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 )
 
@@ -179,7 +180,7 @@ func deployMockERC20(accnt *account.Account, classHashFelt *felt.Felt, tokenName
 	fmt.Printf("   ⏳ Waiting for transaction confirmation...\n")
 
 	// Wait for transaction receipt
-	txReceipt, err := accnt.WaitForTransactionReceipt(context.Background(), txHash, time.Second)
+	txReceipt, err := receiptwait.Starknet(context.Background(), accnt.Provider, txHash, config.ExpectedBlockTime("Starknet"))
 	if err != nil {
 		return "", fmt.Errorf("failed to wait for transaction receipt: %w", err)
 	}
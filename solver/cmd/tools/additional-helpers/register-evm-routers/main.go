@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -119,9 +121,9 @@ func main() {
 		}
 
 		owner := common.HexToAddress(ownerHex)
-		// Impersonate owner (anvil returns null on success)
-		var dummy any
-		if err := rpcClient.Call(&dummy, "anvil_impersonateAccount", owner.Hex()); err != nil {
+		anvil := ethutil.NewAnvil(rpcClient)
+		// Impersonate owner
+		if err := anvil.Impersonate(context.Background(), owner); err != nil {
 			rpcClient.Close()
 			log.Fatalf("failed to impersonate %s on %s: %v", owner.Hex(), networkName, err)
 		}
@@ -129,13 +131,13 @@ func main() {
 
 		// Ensure owner has large ETH balance and verify
 		// Set ~1e27 wei (~1e9 ETH)
-		rich := "0x33B2E3C9FD0803CE8000000"
-		if err := rpcClient.Call(&dummy, "anvil_setBalance", owner.Hex(), rich); err != nil {
+		rich, _ := new(big.Int).SetString("33B2E3C9FD0803CE8000000", 16)
+		if err := anvil.SetBalance(context.Background(), owner, rich); err != nil {
 			log.Fatalf("failed to set balance for %s on %s: %v", owner.Hex(), networkName, err)
 		}
 		if !hasBalance(rpcClient, owner) {
 			// Retry once
-			_ = rpcClient.Call(&dummy, "anvil_setBalance", owner.Hex(), rich)
+			_ = anvil.SetBalance(context.Background(), owner, rich)
 			if !hasBalance(rpcClient, owner) {
 				log.Fatalf("owner %s still unfunded on %s", owner.Hex(), networkName)
 			}
@@ -173,15 +175,15 @@ func main() {
 
 		// Send transactions via eth_sendTransaction
 		hlAddr := netCfg.HyperlaneAddress
-		if err := sendImpersonatedTx(rpcClient, owner, hlAddr, enrollData); err != nil {
+		if err := sendImpersonatedTx(rpcClient, anvil, owner, hlAddr, enrollData); err != nil {
 			log.Fatalf("enrollRemoteRouters failed: %v", err)
 		}
-		if err := sendImpersonatedTx(rpcClient, owner, hlAddr, gasData); err != nil {
+		if err := sendImpersonatedTx(rpcClient, anvil, owner, hlAddr, gasData); err != nil {
 			log.Fatalf("setDestinationGas failed: %v", err)
 		}
 
 		// Stop impersonation
-		_ = rpcClient.Call(&dummy, "anvil_stopImpersonatingAccount", owner.Hex())
+		_ = anvil.StopImpersonating(context.Background(), owner)
 		fmt.Printf("   ✅ Routers/gas registered on %s\n", networkName)
 		
 		// Close the RPC connection
@@ -191,14 +193,9 @@ func main() {
 	fmt.Printf("\n✅ EVM router registration complete\n")
 }
 
-func sendImpersonatedTx(c *rpc.Client, from, to common.Address, data []byte) error {
-	params := map[string]interface{}{
-		"from": from.Hex(),
-		"to":   to.Hex(),
-		"data": "0x" + hex.EncodeToString(data),
-	}
-	var txHash common.Hash
-	if err := c.Call(&txHash, "eth_sendTransaction", params); err != nil {
+func sendImpersonatedTx(c *rpc.Client, anvil *ethutil.Anvil, from, to common.Address, data []byte) error {
+	txHash, err := anvil.SendImpersonatedTransaction(context.Background(), from, to, data, nil)
+	if err != nil {
 		return err
 	}
 	for i := 0; i < 60; i++ {
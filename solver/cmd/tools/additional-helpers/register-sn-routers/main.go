@@ -14,6 +14,7 @@ import (
 	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/joho/godotenv"
 
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 )
 
@@ -106,20 +107,20 @@ func main() {
 	// routers: Array<u256> (each as low, high felts)
 	calldata = append(calldata, utils.Uint64ToFelt(uint64(len(entries))))
 	for _, e := range entries {
-		low, high := bytes32ToU256Felts(e.b32)
-		calldata = append(calldata, low, high)
+		calldata = append(calldata, starknetutil.U256Calldata(new(big.Int).SetBytes(e.b32[:]))...)
 	}
 
 	// enroll_remote_routers(uint32[] destinations, u256[] routers)
 	enrollCall := rpc.InvokeFunctionCall{ContractAddress: hlAddrF, FunctionName: "enroll_remote_routers", CallData: calldata}
-	tx1, err := acct.BuildAndSendInvokeTxn(context.Background(), []rpc.InvokeFunctionCall{enrollCall}, nil)
+	contracts := starknetutil.NewAccountClient(acct)
+	tx1, err := contracts.Invoke(context.Background(), enrollCall)
 	if err != nil {
 		panic(fmt.Errorf("enroll_remote_routers failed: %w", err))
 	}
-	fmt.Printf("   ⛽ enroll_remote_routers tx: %s\n", tx1.Hash.String())
+	fmt.Printf("   ⛽ enroll_remote_routers tx: %s\n", tx1.String())
 
 	// Wait for router enrollment to complete before setting gas
-	_, err = acct.WaitForTransactionReceipt(context.Background(), tx1.Hash, time.Second)
+	_, err = contracts.WaitForAcceptance(context.Background(), tx1, time.Second)
 	if err != nil {
 		panic(fmt.Errorf("enroll_remote_routers wait failed: %w", err))
 	}
@@ -150,15 +151,9 @@ func main() {
 
 		fmt.Printf("   ⚡ %s (domain %d): %s units\n", entry.name, entry.domain, gasAmount.String())
 
-		// Convert gas amount to u256 (low, high felts)
-		gasLow, gasHigh := bigIntToU256Felts(gasAmount)
-
 		// GasRouterConfig struct: { destination: u32, gas: u256 }
-		gasConfigsCalldata = append(gasConfigsCalldata,
-			utils.Uint64ToFelt(uint64(entry.domain)), // destination
-			gasLow,                                   // gas amount low
-			gasHigh,                                  // gas amount high
-		)
+		gasConfigsCalldata = append(gasConfigsCalldata, utils.Uint64ToFelt(uint64(entry.domain)))
+		gasConfigsCalldata = append(gasConfigsCalldata, starknetutil.U256Calldata(gasAmount)...)
 	}
 
 	// For the function signature:
@@ -181,14 +176,14 @@ func main() {
 		CallData:        finalCalldata,
 	}
 
-	tx2, err := acct.BuildAndSendInvokeTxn(context.Background(), []rpc.InvokeFunctionCall{gasCall}, nil)
+	tx2, err := contracts.Invoke(context.Background(), gasCall)
 	if err != nil {
 		panic(fmt.Errorf("batch set_destination_gas failed: %w", err))
 	}
-	fmt.Printf("   ⛽ Batch set_destination_gas tx: %s\n", tx2.Hash.String())
+	fmt.Printf("   ⛽ Batch set_destination_gas tx: %s\n", tx2.String())
 
 	// Wait for gas config to complete
-	_, err = acct.WaitForTransactionReceipt(context.Background(), tx2.Hash, time.Second)
+	_, err = contracts.WaitForAcceptance(context.Background(), tx2, time.Second)
 	if err != nil {
 		panic(fmt.Errorf("batch set_destination_gas wait failed: %w", err))
 	}
@@ -225,30 +220,3 @@ func hexToBytes32(hexStr string) (out [32]byte) {
 	}
 	return out
 }
-
-func bytes32ToU256Felts(b32 [32]byte) (*felt.Felt, *felt.Felt) {
-	// Split into high(16 bytes) and low(16 bytes)
-	high := new(big.Int).SetBytes(b32[0:16])
-	low := new(big.Int).SetBytes(b32[16:32])
-	return utils.BigIntToFelt(low), utils.BigIntToFelt(high)
-}
-
-func bigIntToU256Felts(num *big.Int) (*felt.Felt, *felt.Felt) {
-	// Convert a big.Int to u256 representation (low and high felts)
-	// u256 is represented as two felt.Felt values where:
-	// - low contains the first 128 bits
-	// - high contains the remaining 128 bits
-
-	// Create a mask for 128 bits (2^128 - 1)
-	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
-
-	// Extract low part (first 128 bits)
-	lowBigInt := new(big.Int).And(num, mask)
-	low := utils.BigIntToFelt(lowBigInt)
-
-	// Extract high part (remaining bits)
-	highBigInt := new(big.Int).Rsh(num, 128)
-	high := utils.BigIntToFelt(highBigInt)
-
-	return low, high
-}
@@ -14,6 +14,8 @@ import (
 	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/joho/godotenv"
 
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 )
 
@@ -226,25 +228,6 @@ func fundUsers(accnt *account.Account, dogCoin TokenInfo, aliceAddr, solverAddr
 	return nil
 }
 
-// toU256 converts a big.Int to u256 representation (low and high felts)
-// u256 is represented as two felt.Felt values where:
-// - low contains the first 128 bits
-// - high contains the remaining 128 bits
-func toU256(num *big.Int) (low, high *felt.Felt) {
-	// Create a mask for 128 bits (2^128 - 1)
-	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
-
-	// Extract low part (first 128 bits)
-	lowBigInt := new(big.Int).And(num, mask)
-	low = utils.BigIntToFelt(lowBigInt)
-
-	// Extract high part (remaining bits)
-	highBigInt := new(big.Int).Rsh(num, 128)
-	high = utils.BigIntToFelt(highBigInt)
-
-	return low, high
-}
-
 // mintTokens calls the mint function on a token contract
 func mintTokens(accnt *account.Account, tokenAddress, recipient, amount, tokenName string) error {
 	fmt.Printf("     🪙 Minting %s %s to %s...\n", amount, tokenName, recipient)
@@ -264,28 +247,25 @@ func mintTokens(accnt *account.Account, tokenAddress, recipient, amount, tokenNa
 	amountBigInt := new(big.Int)
 	amountBigInt.SetString(amount, 10) // Parse as decimal string
 
-	// Convert to u256 representation (low and high felts)
-	lowFelt, highFelt := toU256(amountBigInt)
-
-	// Build the mint function call with u256 (low, high)
+	// mint(to: ContractAddress, amount: u256)
 	mintCall := rpc.InvokeFunctionCall{
 		ContractAddress: tokenAddrFelt,
 		FunctionName:    "mint",
-		CallData:        []*felt.Felt{recipientFelt, lowFelt, highFelt},
+		CallData:        append([]*felt.Felt{recipientFelt}, starknetutil.U256Calldata(amountBigInt)...),
 	}
 
 	// Send the mint transaction
-	resp, err := accnt.BuildAndSendInvokeTxn(context.Background(), []rpc.InvokeFunctionCall{mintCall}, nil)
+	contracts := starknetutil.NewAccountClient(accnt)
+	txHash, err := contracts.Invoke(context.Background(), mintCall)
 	if err != nil {
 		return fmt.Errorf("failed to send mint transaction: %w", err)
 	}
 
-	fmt.Printf("     ⏳ Mint transaction sent: %s\n", resp.Hash.String())
+	fmt.Printf("     ⏳ Mint transaction sent: %s\n", txHash.String())
 	fmt.Printf("     ⏳ Waiting for confirmation...\n")
 
 	// Wait for transaction receipt
-	_, err = accnt.WaitForTransactionReceipt(context.Background(), resp.Hash, time.Second)
-	if err != nil {
+	if _, err := contracts.WaitForAcceptance(context.Background(), txHash, time.Second); err != nil {
 		return fmt.Errorf("failed to wait for mint confirmation: %w", err)
 	}
 
@@ -295,39 +275,7 @@ func mintTokens(accnt *account.Account, tokenAddress, recipient, amount, tokenNa
 
 // getTokenBalance gets the balance of a token for a specific address
 func getTokenBalance(accnt *account.Account, tokenAddress, userAddress string) (*big.Int, error) {
-	// Convert addresses to felt
-	tokenAddrFelt, err := utils.HexToFelt(tokenAddress)
-	if err != nil {
-		return nil, fmt.Errorf("invalid token address: %w", err)
-	}
-
-	userAddrFelt, err := utils.HexToFelt(userAddress)
-	if err != nil {
-		return nil, fmt.Errorf("invalid user address: %w", err)
-	}
-
-	// Build the balanceOf function call using rpc.FunctionCall
-	balanceCall := rpc.FunctionCall{
-		ContractAddress:    tokenAddrFelt,
-		EntryPointSelector: utils.GetSelectorFromNameFelt("balanceOf"),
-		Calldata:           []*felt.Felt{userAddrFelt},
-	}
-
-	// Call the contract to get balance using the RPC provider
-	resp, err := accnt.Provider.Call(context.Background(), balanceCall, rpc.WithBlockTag("latest"))
-	if err != nil {
-		return nil, fmt.Errorf("failed to call balanceOf: %w", err)
-	}
-
-	if len(resp) == 0 {
-		return nil, fmt.Errorf("no response from balanceOf call")
-	}
-
-	// Convert felt response to big.Int
-	balanceFelt := resp[0]
-	balanceBigInt := utils.FeltToBigInt(balanceFelt)
-
-	return balanceBigInt, nil
+	return starknetutil.ERC20Balance(accnt.Provider, tokenAddress, userAddress)
 }
 
 // setAllowances sets unlimited allowances for users on DogCoin token
@@ -407,35 +355,20 @@ func approveUnlimited(accnt *account.Account, tokenAddress string, spenderAddrFe
 	}
 
 	// Set unlimited allowance (max u256)
-	// For u256, we need to pass (low, high) where both are max u128
-	// Max u128 value is 2^128 - 1, so max u256 = (2^128 - 1) << 128 + (2^128 - 1)
-	maxLowFelt := new(felt.Felt)
-	_, _ = maxLowFelt.SetString("0xffffffffffffffffffffffffffffffff") // Max u128 value (2^128 - 1)
-	maxHighFelt := new(felt.Felt)
-	_, _ = maxHighFelt.SetString("0xffffffffffffffffffffffffffffffff") // Max u128 value (2^128 - 1)
-
-	// Debug: Show what allowance values we're setting
-	fmt.Printf("         🔍 Setting allowance: low=%s, high=%s\n", maxLowFelt.String(), maxHighFelt.String())
-
-	// Build the approve function call
-	approveCall := rpc.InvokeFunctionCall{
-		ContractAddress: tokenAddrFelt,
-		FunctionName:    "approve",
-		CallData:        []*felt.Felt{spenderAddrFelt, maxLowFelt, maxHighFelt},
-	}
+	maxU256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
 
 	// Send the approve transaction
-	resp, err := accnt.BuildAndSendInvokeTxn(context.Background(), []rpc.InvokeFunctionCall{approveCall}, nil)
+	contracts := starknetutil.NewAccountClient(accnt)
+	txHash, err := contracts.Approve(context.Background(), tokenAddrFelt, spenderAddrFelt, maxU256)
 	if err != nil {
 		return fmt.Errorf("failed to send approve transaction: %w", err)
 	}
 
-	fmt.Printf("         ⏳ Approve transaction sent: %s\n", resp.Hash.String())
+	fmt.Printf("         ⏳ Approve transaction sent: %s\n", txHash.String())
 	fmt.Printf("         ⏳ Waiting for confirmation...\n")
 
 	// Wait for transaction receipt
-	_, err = accnt.WaitForTransactionReceipt(context.Background(), resp.Hash, time.Second)
-	if err != nil {
+	if _, err := contracts.WaitForAcceptance(context.Background(), txHash, time.Second); err != nil {
 		return fmt.Errorf("failed to wait for approve confirmation: %w", err)
 	}
 
@@ -496,73 +429,10 @@ func verifyBalancesAndAllowances(accnt *account.Account, dogCoin TokenInfo, hype
 
 // getTokenAllowance gets the allowance of a token for a specific spender
 func getTokenAllowance(accnt *account.Account, tokenAddress, ownerAddress, spenderAddress string) (*big.Int, error) {
-	// Convert addresses to felt
-	tokenAddrFelt, err := utils.HexToFelt(tokenAddress)
-	if err != nil {
-		return nil, fmt.Errorf("invalid token address: %w", err)
-	}
-
-	ownerAddrFelt, err := utils.HexToFelt(ownerAddress)
-	if err != nil {
-		return nil, fmt.Errorf("invalid owner address: %w", err)
-	}
-
-	spenderAddrFelt, err := utils.HexToFelt(spenderAddress)
-	if err != nil {
-		return nil, fmt.Errorf("invalid spender address: %w", err)
-	}
-
-	// Build the allowance function call
-	allowanceCall := rpc.FunctionCall{
-		ContractAddress:    tokenAddrFelt,
-		EntryPointSelector: utils.GetSelectorFromNameFelt("allowance"),
-		Calldata:           []*felt.Felt{ownerAddrFelt, spenderAddrFelt},
-	}
-
-	// Debug: Show what we're calling
-	fmt.Printf("         🔍 Calling allowance(owner=%s, spender=%s)\n", ownerAddrFelt.String(), spenderAddrFelt.String())
-
-	// Call the contract to get allowance
-	resp, err := accnt.Provider.Call(context.Background(), allowanceCall, rpc.WithBlockTag("latest"))
-	if err != nil {
-		return nil, fmt.Errorf("failed to call allowance: %w", err)
-	}
-
-	// Debug: Show the full response
-	fmt.Printf("         🔍 Full allowance response: %d values\n", len(resp))
-	for i, val := range resp {
-		fmt.Printf("         🔍 Response[%d]: %s\n", i, val.String())
-	}
-
-	if len(resp) < 2 {
-		return nil, fmt.Errorf("insufficient response from allowance call: expected 2 values for u256, got %d", len(resp))
-	}
-
-	// For u256, the response should be [low, high] where:
-	// - low contains the first 128 bits
-	// - high contains the remaining 128 bits
-	lowFelt := resp[0]
-	highFelt := resp[1]
-
-	// Debug: Show the raw felt values
-	fmt.Printf("         🔍 Raw allowance response: low=%s, high=%s\n", lowFelt.String(), highFelt.String())
-
-	// Convert low and high felts to big.Ints
-	lowBigInt := utils.FeltToBigInt(lowFelt)
-	highBigInt := utils.FeltToBigInt(highFelt)
-
-	// Combine low and high into a single u256 value
-	// high << 128 + low
-	shiftedHigh := new(big.Int).Lsh(highBigInt, 128)
-	totalAllowance := new(big.Int).Add(shiftedHigh, lowBigInt)
-
-	return totalAllowance, nil
+	return starknetutil.ERC20Allowance(accnt.Provider, tokenAddress, ownerAddress, spenderAddress)
 }
 
 // formatTokenAmount formats a token amount for display (converts from wei to tokens)
-func formatTokenAmount(amount *big.Int) string {
-	// Convert from wei (18 decimals) to tokens
-	decimals := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
-	tokens := new(big.Float).Quo(new(big.Float).SetInt(amount), new(big.Float).SetInt(decimals))
-	return tokens.Text('f', 0) + " tokens"
+func formatTokenAmount(units *big.Int) string {
+	return amount.New(units, 18).Fixed(0) + " tokens"
 }
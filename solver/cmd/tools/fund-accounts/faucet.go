@@ -0,0 +1,197 @@
+package main
+
+// Module: Testnet faucet top-ups
+// - On real testnets (IS_DEVNET not true) with FAUCET_ENABLED=true, recipients whose native balance
+//   (ETH on EVM chains, STRK on Starknet) is below FAUCET_MIN_BALANCE are topped up from a faucet
+//   API before the DogCoin mints, so they can pay gas
+// - The faucet is called with a JSON POST of {"address", "network", "chainId"}; 2xx responses are
+//   accepted, 429 and 5xx responses and network errors are retried with exponential backoff (a 429's
+//   Retry-After is honored), and other 4xx responses (e.g. already claimed today) fail at once
+// - Faucets pay out asynchronously, so the balance is polled until it rises above the balance before
+//   the request
+// - Public faucets behind a captcha can't be called by a tool; any faucet with a JSON API can
+//
+// Settings:
+// - FAUCET_ENABLED: top up native balances from a faucet on real testnets (default false)
+// - FAUCET_URL: faucet API of every network; <NETWORK>_FAUCET_URL (e.g. BASE_FAUCET_URL) overrides it
+// - FAUCET_API_KEY: sent as a bearer token when set
+// - FAUCET_MIN_BALANCE: native balance, in whole tokens, below which a recipient is topped up (default 0.05)
+// - FAUCET_RETRIES: attempts per request (default 3)
+// - FAUCET_VERIFY_TIMEOUT_SECONDS: how long to wait for the funds to arrive (default 180)
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+)
+
+const (
+	// nativeDecimals is the decimals of ETH and STRK
+	nativeDecimals = 18
+	// faucetBackoff is the wait before the first retry, doubled after each failed attempt
+	faucetBackoff = 5 * time.Second
+	// faucetPollInterval is how often the balance is read while waiting for the funds
+	faucetPollInterval = 5 * time.Second
+	// faucetRequestTimeout bounds one faucet HTTP request
+	faucetRequestTimeout = 30 * time.Second
+)
+
+// errFaucetRejected marks faucet responses that retrying won't change
+var errFaucetRejected = errors.New("faucet rejected the request")
+
+// faucetClient requests native tokens from a faucet API
+type faucetClient struct {
+	url           string
+	apiKey        string
+	retries       int
+	minBalance    *big.Int
+	verifyTimeout time.Duration
+
+	httpClient   *http.Client
+	backoff      time.Duration
+	pollInterval time.Duration
+	sleep        func(ctx context.Context, d time.Duration) error
+}
+
+// faucetFromEnv returns the faucet of a network, or nil when faucets are disabled, on devnets and
+// for networks without a faucet URL
+func faucetFromEnv(network string) *faucetClient {
+	if !envutil.GetEnvBool("FAUCET_ENABLED", false) || envutil.IsDevnet() {
+		return nil
+	}
+	url := envutil.GetEnvWithDefault(strings.ToUpper(network)+"_FAUCET_URL", os.Getenv("FAUCET_URL"))
+	if url == "" {
+		fmt.Printf("   ⚠️  No faucet configured for %s (FAUCET_URL or %s_FAUCET_URL), skipping top-ups\n", network, strings.ToUpper(network))
+		return nil
+	}
+	minBalance, err := amount.Parse(envutil.GetEnvWithDefault("FAUCET_MIN_BALANCE", "0.05"), nativeDecimals)
+	if err != nil {
+		log.Fatalf("Invalid FAUCET_MIN_BALANCE: %v", err)
+	}
+	return &faucetClient{
+		url:           url,
+		apiKey:        os.Getenv("FAUCET_API_KEY"),
+		retries:       max(envutil.GetEnvInt("FAUCET_RETRIES", 3), 1),
+		minBalance:    minBalance.Units,
+		verifyTimeout: time.Duration(envutil.GetEnvInt("FAUCET_VERIFY_TIMEOUT_SECONDS", 180)) * time.Second,
+		httpClient:    &http.Client{Timeout: faucetRequestTimeout},
+		backoff:       faucetBackoff,
+		pollInterval:  faucetPollInterval,
+		sleep:         sleepContext,
+	}
+}
+
+// topUp requests funds for address when its balance is below the minimum and waits for them to arrive
+func (f *faucetClient) topUp(ctx context.Context, network string, chainID uint64, address string, balance func(context.Context) (*big.Int, error)) error {
+	before, err := balance(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read native balance: %w", err)
+	}
+	fmt.Printf("     ⛽ Native balance: %s\n", amount.New(before, nativeDecimals))
+	if before.Cmp(f.minBalance) >= 0 {
+		return nil
+	}
+
+	if err := f.request(ctx, network, chainID, address); err != nil {
+		return err
+	}
+	fmt.Printf("     🚰 Faucet request accepted, waiting for the funds...\n")
+
+	deadline := time.Now().Add(f.verifyTimeout)
+	for {
+		current, err := balance(ctx)
+		if err == nil && current.Cmp(before) > 0 {
+			fmt.Printf("     ✅ Native balance topped up: %s\n", amount.New(current, nativeDecimals))
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("native balance still %s after %s", amount.New(before, nativeDecimals), f.verifyTimeout)
+		}
+		if err := f.sleep(ctx, f.pollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// request calls the faucet, retrying throttled and failed requests with backoff
+func (f *faucetClient) request(ctx context.Context, network string, chainID uint64, address string) error {
+	body, err := json.Marshal(map[string]interface{}{"address": address, "network": network, "chainId": chainID})
+	if err != nil {
+		return err
+	}
+
+	backoff := f.backoff
+	for attempt := 1; ; attempt++ {
+		wait, err := f.send(ctx, body)
+		if err == nil || errors.Is(err, errFaucetRejected) || attempt >= f.retries {
+			if err != nil {
+				return fmt.Errorf("faucet request failed after %d attempt(s): %w", attempt, err)
+			}
+			return nil
+		}
+		if wait == 0 {
+			wait = backoff
+			backoff *= 2
+		}
+		fmt.Printf("     ⚠️  Faucet request failed (%v), retrying in %s\n", err, wait)
+		if err := f.sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// send posts one faucet request, returning the Retry-After wait of a throttled request
+func (f *faucetClient) send(ctx context.Context, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if f.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+f.apiKey)
+	}
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		seconds, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return time.Duration(seconds) * time.Second, fmt.Errorf("throttled: %s", strings.TrimSpace(string(message)))
+	case resp.StatusCode >= 500:
+		return 0, fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	default:
+		return 0, fmt.Errorf("%w: status %d: %s", errFaucetRejected, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
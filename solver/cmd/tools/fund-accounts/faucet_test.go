@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// faucetServer answers faucet requests with the given statuses in turn, the last one repeating
+type faucetServer struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []map[string]interface{}
+	auth     []string
+}
+
+func (s *faucetServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var body map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	s.requests = append(s.requests, body)
+	s.auth = append(s.auth, r.Header.Get("Authorization"))
+
+	status := s.statuses[min(len(s.requests), len(s.statuses))-1]
+	if status == http.StatusTooManyRequests {
+		w.Header().Set("Retry-After", "7")
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte("faucet says hi"))
+}
+
+func TestFaucetTopUp(t *testing.T) {
+	newFaucet := func(t *testing.T, statuses ...int) (*faucetClient, *faucetServer, *[]time.Duration) {
+		server := &faucetServer{statuses: statuses}
+		httpServer := httptest.NewServer(server)
+		t.Cleanup(httpServer.Close)
+		var sleeps []time.Duration
+		return &faucetClient{
+			url:           httpServer.URL,
+			apiKey:        "key",
+			retries:       3,
+			minBalance:    big.NewInt(100),
+			verifyTimeout: time.Minute,
+			httpClient:    httpServer.Client(),
+			backoff:       time.Second,
+			pollInterval:  time.Second,
+			sleep: func(_ context.Context, d time.Duration) error {
+				sleeps = append(sleeps, d)
+				return nil
+			},
+		}, server, &sleeps
+	}
+	// balances returns the given balances in turn, the last one repeating
+	balances := func(values ...int64) func(context.Context) (*big.Int, error) {
+		calls := 0
+		return func(context.Context) (*big.Int, error) {
+			calls++
+			return big.NewInt(values[min(calls, len(values))-1]), nil
+		}
+	}
+
+	t.Run("funded recipients are skipped", func(t *testing.T) {
+		faucet, server, _ := newFaucet(t, http.StatusOK)
+		require.NoError(t, faucet.topUp(context.Background(), "Base", 84532, "0xa1", balances(100)))
+		assert.Empty(t, server.requests)
+	})
+
+	t.Run("request is sent and the funds awaited", func(t *testing.T) {
+		faucet, server, sleeps := newFaucet(t, http.StatusOK)
+		require.NoError(t, faucet.topUp(context.Background(), "Base", 84532, "0xa1", balances(10, 10, 10, 60)))
+		require.Len(t, server.requests, 1)
+		assert.Equal(t, map[string]interface{}{"address": "0xa1", "network": "Base", "chainId": float64(84532)}, server.requests[0])
+		assert.Equal(t, "Bearer key", server.auth[0])
+		assert.Equal(t, []time.Duration{time.Second, time.Second}, *sleeps, "polled until the balance rose")
+	})
+
+	t.Run("server errors are retried with backoff", func(t *testing.T) {
+		faucet, server, sleeps := newFaucet(t, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK)
+		require.NoError(t, faucet.request(context.Background(), "Base", 84532, "0xa1"))
+		assert.Len(t, server.requests, 3)
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, *sleeps)
+	})
+
+	t.Run("throttled requests wait for Retry-After", func(t *testing.T) {
+		faucet, _, sleeps := newFaucet(t, http.StatusTooManyRequests, http.StatusOK)
+		require.NoError(t, faucet.request(context.Background(), "Base", 84532, "0xa1"))
+		assert.Equal(t, []time.Duration{7 * time.Second}, *sleeps)
+	})
+
+	t.Run("rejections and exhausted retries fail", func(t *testing.T) {
+		faucet, server, _ := newFaucet(t, http.StatusBadRequest)
+		err := faucet.request(context.Background(), "Base", 84532, "0xa1")
+		assert.ErrorIs(t, err, errFaucetRejected)
+		assert.ErrorContains(t, err, "faucet says hi")
+		assert.Len(t, server.requests, 1, "rejections are not retried")
+
+		faucet, server, _ = newFaucet(t, http.StatusInternalServerError)
+		assert.ErrorContains(t, faucet.request(context.Background(), "Base", 84532, "0xa1"), "after 3 attempt(s)")
+		assert.Len(t, server.requests, 3)
+	})
+
+	t.Run("funds that never arrive fail verification", func(t *testing.T) {
+		faucet, _, _ := newFaucet(t, http.StatusOK)
+		faucet.verifyTimeout = 0
+		assert.ErrorContains(t, faucet.topUp(context.Background(), "Base", 84532, "0xa1", balances(10)), "native balance still")
+	})
+}
+
+func TestFaucetFromEnv(t *testing.T) {
+	t.Setenv("IS_DEVNET", "false")
+	t.Setenv("FAUCET_URL", "https://faucet.example/api")
+	t.Setenv("BASE_FAUCET_URL", "https://base-faucet.example/api")
+
+	t.Setenv("FAUCET_ENABLED", "")
+	assert.Nil(t, faucetFromEnv("Base"), "disabled by default")
+
+	t.Setenv("FAUCET_ENABLED", "true")
+	base := faucetFromEnv("Base")
+	require.NotNil(t, base)
+	assert.Equal(t, "https://base-faucet.example/api", base.url)
+	assert.Equal(t, big.NewInt(5e16), base.minBalance)
+	assert.Equal(t, 3, base.retries)
+	assert.Equal(t, "https://faucet.example/api", faucetFromEnv("Starknet").url)
+
+	t.Setenv("IS_DEVNET", "true")
+	assert.Nil(t, faucetFromEnv("Base"), "devnets are funded directly")
+
+	t.Setenv("IS_DEVNET", "false")
+	t.Setenv("FAUCET_URL", "")
+	assert.Nil(t, faucetFromEnv("Optimism"))
+}
@@ -11,10 +11,8 @@ import (
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
@@ -23,8 +21,6 @@ const (
 	defaultFundingAmount = 420_690_000_000
 	// Token decimals (18 for most ERC20 tokens)
 	tokenDecimals = 18
-	// Gas limit for transactions
-	defaultGasLimit = 300000
 	// Base 10 for string parsing
 	base10 = 10
 )
@@ -163,7 +159,19 @@ func fundNetwork(networkName string, amount *big.Int) {
 	// Get recipient addresses
 	recipients := getRecipients(isDevnet)
 
-	// Fund each recipient using direct contract call (since Go bindings don't have Mint yet)
+	// On real testnets, top up gas from a faucet before minting
+	if faucet := faucetFromEnv(networkConfig.Name); faucet != nil {
+		for _, recipient := range recipients {
+			fmt.Printf("   🚰 Checking gas of %s (%s)...\n", recipient.Name, recipient.Address.Hex())
+			address := recipient.Address
+			balance := func(ctx context.Context) (*big.Int, error) { return client.BalanceAt(ctx, address, nil) }
+			if err := faucet.topUp(context.Background(), networkConfig.Name, networkConfig.ChainID, address.Hex(), balance); err != nil {
+				log.Printf("     ❌ Faucet top-up failed for %s: %v", recipient.Name, err)
+			}
+		}
+	}
+
+	// Fund each recipient by minting through the MockERC20 binding
 	for _, recipient := range recipients {
 		fmt.Printf("   💸 Funding %s (%s)...\n", recipient.Name, recipient.Address.Hex())
 
@@ -173,8 +181,7 @@ func fundNetwork(networkName string, amount *big.Int) {
 			fmt.Printf("     📊 Current balance: %s\n", ethutil.FormatTokenAmount(currentBalance, tokenDecimals))
 		}
 
-		// Call mint function directly using raw transaction
-		err = mintTokensRaw(client, auth, tokenAddress, recipient.Address, amount)
+		err = mintTokens(client, auth, tokenAddress, recipient.Address, amount)
 		if err != nil {
 			log.Printf("     ❌ Failed to mint tokens for %s: %v", recipient.Name, err)
 			continue
@@ -232,60 +239,17 @@ func createTokenAmount(tokens int64, decimals int) *big.Int {
 	return amount.Mul(amount, multiplier)
 }
 
-// mintTokensRaw calls the mint function directly using raw transaction
-func mintTokensRaw(client *ethclient.Client, auth *bind.TransactOpts, tokenAddress string, recipient common.Address, amount *big.Int) error {
-	// mint(address to, uint256 amount) function signature
-	mintABI := `[{"inputs":[{"internalType":"address","name":"to","type":"address"},{"internalType":"uint256","name":"amount","type":"uint256"}],"name":"mint","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
-
-	parsedABI, err := abi.JSON(strings.NewReader(mintABI))
-	if err != nil {
-		return fmt.Errorf("failed to parse mint ABI: %w", err)
-	}
-
-	// Pack the function call data
-	data, err := parsedABI.Pack("mint", recipient, amount)
-	if err != nil {
-		return fmt.Errorf("failed to pack mint call: %w", err)
-	}
-
-	// Get current nonce
-	nonce, err := client.PendingNonceAt(context.Background(), auth.From)
-	if err != nil {
-		return fmt.Errorf("failed to get nonce: %w", err)
-	}
-
-	// Get gas price
-	gasPrice, err := client.SuggestGasPrice(context.Background())
-	if err != nil {
-		return fmt.Errorf("failed to get gas price: %w", err)
-	}
-
-	// Create transaction
-	tx := types.NewTransaction(
-		nonce,
-		common.HexToAddress(tokenAddress),
-		big.NewInt(0),   // No ETH value
-		defaultGasLimit, // Gas limit for mint
-		gasPrice,
-		data,
-	)
-
-	// Sign transaction
-	signedTx, err := auth.Signer(auth.From, tx)
-	if err != nil {
-		return fmt.Errorf("failed to sign mint transaction: %w", err)
-	}
-
-	// Send transaction
-	err = client.SendTransaction(context.Background(), signedTx)
+// mintTokens mints amount MockERC20 tokens to recipient and waits for the transaction
+func mintTokens(client *ethclient.Client, auth *bind.TransactOpts, tokenAddress string, recipient common.Address, amount *big.Int) error {
+	tx, err := ethutil.ERC20Mint(client, auth, common.HexToAddress(tokenAddress), recipient, amount)
 	if err != nil {
-		return fmt.Errorf("failed to send mint transaction: %w", err)
+		return err
 	}
 
-	fmt.Printf("     🚀 Mint transaction: %s\n", signedTx.Hash().Hex())
+	fmt.Printf("     🚀 Mint transaction: %s\n", tx.Hash().Hex())
 
 	// Wait for confirmation
-	receipt, err := bind.WaitMined(context.Background(), client, signedTx)
+	receipt, err := bind.WaitMined(context.Background(), client, tx)
 	if err != nil {
 		return fmt.Errorf("failed to wait for mint confirmation: %w", err)
 	}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/devnetutil"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
@@ -17,6 +18,9 @@ import (
 	"github.com/NethermindEth/starknet.go/utils"
 )
 
+// devnetFeeMintSTRK is the STRK minted to each recipient on starknet-devnet, so they can pay fees
+const devnetFeeMintSTRK = 1000
+
 func fundStarknet(amount *big.Int) {
 	fmt.Printf("📡 Funding Starknet network...\n")
 
@@ -48,6 +52,20 @@ func fundStarknet(amount *big.Int) {
 	minterPublicKey := envutil.GetStarknetAlicePublicKey()
 	minterAddress := envutil.GetStarknetAliceAddress()
 
+	// On starknet-devnet, fall back to a predeployed account and top up fee tokens through the devnet API
+	var devnet *devnetutil.Client
+	if envutil.IsDevnet() {
+		devnet = devnetutil.NewClient(starknetConfig.RPCURL)
+		if minterPrivateKey == "" || minterPublicKey == "" {
+			accounts, err := devnet.PredeployedAccounts(context.Background())
+			if err != nil || len(accounts) == 0 {
+				log.Fatalf("Starknet minter credentials not found (Alice's keys) and no devnet predeployed account: %v", err)
+			}
+			minterAddress, minterPublicKey, minterPrivateKey = accounts[0].Address, accounts[0].PublicKey, accounts[0].PrivateKey
+			fmt.Printf("   🔑 Minting with devnet predeployed account %s\n", minterAddress)
+		}
+	}
+
 	if minterPrivateKey == "" || minterPublicKey == "" {
 		log.Fatalf("Starknet minter credentials not found (Alice's keys)")
 	}
@@ -70,57 +88,72 @@ func fundStarknet(amount *big.Int) {
 		log.Fatalf("Failed to create minter account: %v", err)
 	}
 
+	contracts := starknetutil.NewAccountClient(minterAccount)
+	tokenFelt, err := utils.HexToFelt(tokenAddress)
+	if err != nil {
+		log.Fatalf("Failed to convert token address to felt: %v", err)
+	}
+
 	// Get recipient addresses
 	recipients := getStarknetRecipients()
 
+	// On real testnets, top up fee tokens from a faucet before minting
+	if faucet := faucetFromEnv(starknetConfig.Name); faucet != nil {
+		for _, recipient := range recipients {
+			fmt.Printf("   🚰 Checking STRK of %s (%s)...\n", recipient.Name, recipient.Address)
+			address := recipient.Address
+			balance := func(context.Context) (*big.Int, error) {
+				return starknetutil.ERC20Balance(client, starknetutil.STRKTokenAddress, address)
+			}
+			if err := faucet.topUp(context.Background(), starknetConfig.Name, starknetConfig.ChainID, address, balance); err != nil {
+				log.Printf("     ❌ Faucet top-up failed for %s: %v", recipient.Name, err)
+			}
+		}
+	}
+
 	// Fund each recipient
 	for _, recipient := range recipients {
 		fmt.Printf("   💸 Funding %s (%s)...\n", recipient.Name, recipient.Address)
 
-		// Check current balance
-		currentBalance, err := starknetutil.ERC20Balance(client, tokenAddress, recipient.Address)
-		if err == nil {
-			fmt.Printf("     📊 Current balance: %s\n", starknetutil.FormatTokenAmount(currentBalance, tokenDecimals))
-		}
-
-		// Convert addresses to felts for the mint call
-		tokenFelt, err := utils.HexToFelt(tokenAddress)
-		if err != nil {
-			log.Printf("     ❌ Failed to convert token address to felt: %v", err)
-			continue
-		}
-
 		recipientFelt, err := utils.HexToFelt(recipient.Address)
 		if err != nil {
 			log.Printf("     ❌ Failed to convert recipient address to felt: %v", err)
 			continue
 		}
 
-		// Convert amount to two felts (low, high) for u256
-		amountLow, amountHigh := starknetutil.ConvertBigIntToU256Felts(amount)
+		if devnet != nil {
+			feeAmount := createTokenAmount(devnetFeeMintSTRK, tokenDecimals)
+			if result, err := devnet.Mint(context.Background(), recipient.Address, feeAmount, devnetutil.UnitFri); err != nil {
+				log.Printf("     ⚠️  Failed to mint devnet STRK for fees: %v", err)
+			} else {
+				fmt.Printf("     ⛽ Fee balance: %s STRK\n", starknetutil.FormatTokenAmount(result.NewBalance, tokenDecimals))
+			}
+		}
 
-		// Build mint calldata: mint(to: ContractAddress, amount: u256)
-		mintCalldata := []*felt.Felt{recipientFelt, amountLow, amountHigh}
+		// Check current balance
+		currentBalance, err := contracts.BalanceOf(context.Background(), tokenFelt, recipientFelt)
+		if err == nil {
+			fmt.Printf("     📊 Current balance: %s\n", starknetutil.FormatTokenAmount(currentBalance, tokenDecimals))
+		}
 
-		// Create mint transaction
+		// mint(to: ContractAddress, amount: u256)
 		mintCall := rpc.InvokeFunctionCall{
 			ContractAddress: tokenFelt,
 			FunctionName:    "mint",
-			CallData:        mintCalldata,
+			CallData:        append([]*felt.Felt{recipientFelt}, starknetutil.U256Calldata(amount)...),
 		}
 
 		// Send mint transaction
-		mintTx, err := minterAccount.BuildAndSendInvokeTxn(context.Background(), []rpc.InvokeFunctionCall{mintCall}, nil)
+		mintHash, err := contracts.Invoke(context.Background(), mintCall)
 		if err != nil {
 			log.Printf("     ❌ Failed to send mint transaction for %s: %v", recipient.Name, err)
 			continue
 		}
 
-		fmt.Printf("     🚀 Mint transaction: %s\n", mintTx.Hash.String())
+		fmt.Printf("     🚀 Mint transaction: %s\n", mintHash.String())
 
 		// Wait for confirmation
-		_, err = minterAccount.WaitForTransactionReceipt(context.Background(), mintTx.Hash, 2*time.Second)
-		if err != nil {
+		if _, err := contracts.WaitForAcceptance(context.Background(), mintHash, 2*time.Second); err != nil {
 			log.Printf("     ❌ Failed to wait for transaction confirmation: %v", err)
 			continue
 		}
@@ -128,7 +161,7 @@ func fundStarknet(amount *big.Int) {
 		fmt.Printf("     ✅ Minted %s tokens\n", starknetutil.FormatTokenAmount(amount, tokenDecimals))
 
 		// Verify new balance
-		newBalance, err := starknetutil.ERC20Balance(client, tokenAddress, recipient.Address)
+		newBalance, err := contracts.BalanceOf(context.Background(), tokenFelt, recipientFelt)
 		if err == nil {
 			fmt.Printf("     💰 New balance: %s\n", starknetutil.FormatTokenAmount(newBalance, tokenDecimals))
 		}
@@ -0,0 +1,160 @@
+package openorder
+
+// Chaos orders - opens pathological orders the solver is expected to reject while staying healthy
+//
+// Usage:
+//
+//	open-order evm chaos                   # one EVM → EVM order per scenario
+//	open-order starknet chaos              # one Starknet → EVM order per scenario
+//	open-order <chain> chaos-<scenario>    # a single scenario, e.g. chaos-random-settler
+//
+// Each order is a default order whose encoded order data is altered after the input token was
+// approved, so Alice still locks a normal amount. The expected rejection code is printed next to
+// each order; check them with `solver report rejections` and the solver logs afterwards.
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// chaosScenario alters the order data of a regular order into a pathological one
+type chaosScenario struct {
+	name     string
+	expected string // Rejection the solver should record
+	evm      func(*ABIOrderData)
+	starknet func(*StarknetOrderData)
+}
+
+var chaosScenarios = []chaosScenario{
+	{
+		name:     "unknown-token",
+		expected: "token_not_allowed with a token registry, insufficient_balance otherwise",
+		evm:      func(d *ABIOrderData) { copy(d.OutputToken[12:], randomBytes(20)) },
+		starknet: func(d *StarknetOrderData) { d.OutputToken = randomFelt() },
+	},
+	{
+		name:     "zero-output",
+		expected: "dust_order when a minimum fill is configured",
+		evm:      func(d *ABIOrderData) { d.AmountOut = new(big.Int) },
+		starknet: func(d *StarknetOrderData) { d.AmountOut = new(big.Int) },
+	},
+	{
+		name:     "random-settler",
+		expected: "untrusted_settler",
+		evm:      func(d *ABIOrderData) { copy(d.DestinationSettler[12:], randomBytes(20)) },
+		starknet: func(d *StarknetOrderData) { d.DestinationSettler = randomFelt() },
+	},
+	{
+		name:     "absurd-amount",
+		expected: "insufficient_balance (order_size with a maxOrderSize)",
+		evm:      func(d *ABIOrderData) { d.AmountOut = new(big.Int).Set(math.MaxBig256) },
+		starknet: func(d *StarknetOrderData) { d.AmountOut = new(big.Int).Set(math.MaxBig256) },
+	},
+}
+
+// selectChaosScenarios returns every scenario for "chaos" and the named one for "chaos-<name>"
+func selectChaosScenarios(command string) ([]chaosScenario, error) {
+	if command == "chaos" {
+		return chaosScenarios, nil
+	}
+	name := strings.TrimPrefix(command, "chaos-")
+	for _, scenario := range chaosScenarios {
+		if scenario.name == name {
+			return []chaosScenario{scenario}, nil
+		}
+	}
+	names := make([]string, len(chaosScenarios))
+	for i, scenario := range chaosScenarios {
+		names[i] = scenario.name
+	}
+	return nil, fmt.Errorf("unknown chaos scenario %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// openChaosEvmToEvm opens one EVM → EVM order per scenario
+func openChaosEvmToEvm(networks []NetworkConfig, command string) {
+	scenarios, err := selectChaosScenarios(command)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, scenario := range scenarios {
+		fmt.Printf("\n💥 Chaos scenario %s, expected rejection: %s\n", scenario.name, scenario.expected)
+		order := OrderConfig{
+			OriginChain:      "Ethereum",
+			DestinationChain: "Optimism",
+			InputToken:       "DogCoin",
+			OutputToken:      "DogCoin",
+			InputAmount:      CreateTokenAmount(testInputAmount, tokenDecimals),
+			OutputAmount:     CreateTokenAmount(1000, tokenDecimals),
+			User:             AliceUserName,
+			OpenDeadline:     uint32(time.Now().Add(1 * time.Hour).Unix()),
+			FillDeadline:     uint32(time.Now().Add(orderDeadlineHours * time.Hour).Unix()),
+			chaos:            &scenario,
+		}
+		executeOrder(&order, networks)
+	}
+	printChaosSummary(scenarios)
+}
+
+// openChaosStarknetToEvm opens one Starknet → EVM order per scenario
+func openChaosStarknetToEvm(networks []StarknetNetworkConfig, command string) {
+	scenarios, err := selectChaosScenarios(command)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	destinationChain := getEnvWithDefault("DEFAULT_EVM_DESTINATION", "Ethereum")
+	aliceAddress, err := getAliceAddressForNetwork(destinationChain)
+	if err != nil {
+		log.Fatalf("Failed to get Alice address for %s: %v", destinationChain, err)
+	}
+
+	for _, scenario := range scenarios {
+		fmt.Printf("\n💥 Chaos scenario %s, expected rejection: %s\n", scenario.name, scenario.expected)
+		order := StarknetOrderConfig{
+			OriginChain:      "Starknet",
+			DestinationChain: destinationChain,
+			InputToken:       "DogCoin",
+			OutputToken:      "DogCoin",
+			InputAmount:      CreateTokenAmount(1000, 18),
+			OutputAmount:     CreateTokenAmount(testOutputAmountStarknet, tokenDecimals),
+			User:             aliceAddress,
+			OpenDeadline:     uint64(time.Now().Add(1 * time.Hour).Unix()),
+			FillDeadline:     uint64(time.Now().Add(24 * time.Hour).Unix()),
+			chaos:            &scenario,
+		}
+		executeStarknetOrder(&order, networks)
+	}
+	printChaosSummary(scenarios)
+}
+
+// printChaosSummary lists the rejections to look for once the solver processed the orders
+func printChaosSummary(scenarios []chaosScenario) {
+	fmt.Printf("\n💥 Opened %d chaos orders; the solver should reject each one and keep running:\n", len(scenarios))
+	for _, scenario := range scenarios {
+		fmt.Printf("   • %-15s %s\n", scenario.name, scenario.expected)
+	}
+	fmt.Println("   Check with: solver report rejections")
+}
+
+// randomBytes returns n random bytes
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatalf("Failed to generate random bytes: %v", err)
+	}
+	return b
+}
+
+// randomFelt returns a random Starknet address
+func randomFelt() *felt.Felt {
+	return utils.BigIntToFelt(new(big.Int).SetBytes(randomBytes(31)))
+}
@@ -14,6 +14,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
@@ -86,6 +87,8 @@ type OrderData struct {
 	FillDeadline       *big.Int
 	MaxSpent           []TokenAmount
 	MinReceived        []TokenAmount
+	InputToken         string // Token name on the origin chain (see native_tokens.go)
+	OutputToken        string // Token name on the destination chain
 }
 
 // ABIOrderData struct for ABI encoding (matches Solidity interface)
@@ -142,30 +145,9 @@ func (od *OrderData) IsValid() bool {
 	return true
 }
 
-// Helper functions for uint256 conversion
-// ToUint256 converts *big.Int to uint256.Int
-func ToUint256(bi *big.Int) *uint256.Int {
-	if bi == nil {
-		return uint256.NewInt(0)
-	}
-	u, _ := uint256.FromBig(bi)
-	return u
-}
-
-// ToBigInt converts uint256.Int to *big.Int
-func ToBigInt(u *uint256.Int) *big.Int {
-	return u.ToBig()
-}
-
-// CreateTokenAmount creates a token amount using uint256 for better performance
+// CreateTokenAmount returns tokens whole tokens in base units
 func CreateTokenAmount(tokens int64, decimals int) *big.Int {
-	// Use uint256 for the arithmetic operations
-	tokenU := uint256.NewInt(uint64(tokens))
-	decimalsU := uint256.NewInt(10)
-	decimalsU.Exp(decimalsU, uint256.NewInt(uint64(decimals)))
-	result := new(uint256.Int)
-	result.Mul(tokenU, decimalsU)
-	return result.ToBig()
+	return new(big.Int).Mul(big.NewInt(tokens), amount.Pow10(decimals))
 }
 
 // NetworkConfig represents a single network configuration
@@ -188,6 +170,8 @@ type OrderConfig struct {
 	User             string
 	OpenDeadline     uint32
 	FillDeadline     uint32
+	// chaos alters the encoded order data into a pathological order; nil for a regular order
+	chaos *chaosScenario
 }
 
 // OrderParams contains the actual addresses and amounts for order execution
@@ -291,9 +275,11 @@ func getHyperlaneDomain(networkName string) uint32 {
 	return uint32(domain)
 }
 
-// RunEVMOrder creates an EVM order based on the command
-func RunEVMOrder(command string) {
+// RunEVMOrder creates an EVM order based on the command; opts override its deadlines and nonce
+func RunEVMOrder(command string, opts OrderOptions) {
 	fmt.Println("🎯 Running EVM order creation...")
+	orderOptions = opts
+	defer writeOpenedOrders()
 
 	// Load configuration (this loads .env and initializes networks)
 	_, err := config.LoadConfig()
@@ -307,6 +293,11 @@ func RunEVMOrder(command string) {
 	// Load network configuration
 	networks := loadNetworks()
 
+	if strings.HasPrefix(command, "chaos") {
+		openChaosEvmToEvm(networks, command)
+		return
+	}
+
 	switch command {
 	case "random-to-evm":
 		openRandomToEvm(networks)
@@ -316,6 +307,10 @@ func RunEVMOrder(command string) {
 		openDefaultEvmToEvm(networks)
 	case "default-evm-sn":
 		openDefaultEvmToStarknet(networks)
+	case "native-evm-evm":
+		openNativeEvmToEvm(networks)
+	case "native-evm-sn":
+		openNativeEvmToStarknet(networks)
 	default:
 		// Default to random EVM order
 		openRandomToEvm(networks)
@@ -442,8 +437,46 @@ func openDefaultEvmToStarknet(networks []NetworkConfig) {
 	executeOrder(&order, networks)
 }
 
+func openNativeEvmToEvm(networks []NetworkConfig) {
+	fmt.Println("🎯 Opening Native ETH EVM → EVM Test Order...")
+
+	order := OrderConfig{
+		OriginChain:      "Ethereum",
+		DestinationChain: "Optimism",
+		InputToken:       tokenETH,
+		OutputToken:      tokenETH,
+		InputAmount:      new(big.Int).Set(nativeInputAmount),  // 0.0101 ETH (what solver receives)
+		OutputAmount:     new(big.Int).Set(nativeOutputAmount), // 0.01 ETH (what solver provides)
+		User:             AliceUserName,
+		OpenDeadline:     uint32(time.Now().Add(1 * time.Hour).Unix()),
+		FillDeadline:     uint32(time.Now().Add(orderDeadlineHours * time.Hour).Unix()),
+	}
+
+	executeOrder(&order, networks)
+}
+
+func openNativeEvmToStarknet(networks []NetworkConfig) {
+	fmt.Println("🎯 Opening Native ETH EVM → Starknet Test Order...")
+
+	order := OrderConfig{
+		OriginChain:      "Ethereum",
+		DestinationChain: starknetNetworkName,
+		InputToken:       tokenETH,
+		OutputToken:      tokenETH, // Starknet's ETH ERC20
+		InputAmount:      new(big.Int).Set(nativeInputAmount),
+		OutputAmount:     new(big.Int).Set(nativeOutputAmount),
+		User:             AliceUserName,
+		OpenDeadline:     uint32(time.Now().Add(1 * time.Hour).Unix()),
+		FillDeadline:     uint32(time.Now().Add(orderDeadlineHours * time.Hour).Unix()),
+	}
+
+	executeOrder(&order, networks)
+}
+
 func executeOrder(order *OrderConfig, networks []NetworkConfig) {
 	fmt.Printf("\n📋 Executing Order: %s → %s\n", order.OriginChain, order.DestinationChain)
+	orderOptions.applyEVM(order)
+	orderOptions.describe()
 
 	// Find origin network
 	var originNetwork *NetworkConfig
@@ -535,82 +568,99 @@ func executeOrder(order *OrderConfig, networks []NetworkConfig) {
 	inputToken := originNetwork.dogCoinAddress
 	owner := auth.From
 	spender := originNetwork.hyperlaneAddress
+	var approveTxHash string
 
-	// Get initial balances
-	initialUserBalance, err := ethutil.ERC20Balance(client, inputToken, owner)
-	if err == nil {
-		fmt.Printf("   🔍 Initial InputToken balance(owner): %s\n", initialUserBalance.String())
-	} else {
-		fmt.Printf("   ⚠️  Could not read initial balance: %v\n", err)
-	}
-
-	initialHyperlaneBalance, err := ethutil.ERC20Balance(client, inputToken, spender)
-	if err == nil {
-		fmt.Printf("   🔍 Initial InputToken balance(hyperlane): %s\n", initialHyperlaneBalance.String())
-	} else {
-		fmt.Printf("   ⚠️  Could not read initial hyperlane balance: %v\n", err)
-	}
-
-	// Check if Alice has sufficient tokens for the order
-	requiredAmount := order.InputAmount
-	if initialUserBalance == nil || initialUserBalance.Cmp(requiredAmount) < 0 {
-		fmt.Printf("   ⚠️  Insufficient balance! Alice needs %s tokens but has %s\n",
-			ethutil.FormatTokenAmount(requiredAmount, 18),
-			ethutil.FormatTokenAmount(initialUserBalance, 18))
-		fmt.Printf("   💡 Please mint tokens manually using the MockERC20 contract's mint() function\n")
-		fmt.Printf("   📝 Contract address: %s\n", inputToken.Hex())
-		fmt.Printf("   🔧 Call: mint(\"%s\", \"%s\")\n", owner.Hex(), requiredAmount.String())
-		client.Close()
-		log.Fatalf("Insufficient token balance for order creation")
-	} else {
-		fmt.Printf("   ✅ Alice has sufficient tokens (%s)\n", ethutil.FormatTokenAmount(initialUserBalance, 18))
-	}
-
-	// Check allowance
-	allowance, err := ethutil.ERC20Allowance(client, inputToken, owner, spender)
-	if err == nil {
-		fmt.Printf("   🔍 Current allowance(owner->hyperlane): %s\n", allowance.String())
-	} else {
-		fmt.Printf("   ⚠️  Could not read allowance: %v\n", err)
-	}
-
-	// If allowance is insufficient, approve the Hyperlane contract
-	requiredAmount = order.InputAmount
-	if allowance.Cmp(requiredAmount) < 0 {
-		fmt.Printf("   🔄 Insufficient allowance, approving %s tokens...\n", requiredAmount.String())
-
-		// Approve the Hyperlane contract to spend the required amount
-		approveTx, err := ethutil.ERC20Approve(client, auth, inputToken, spender, requiredAmount)
-		if err != nil {
+	if isNativeToken(order.InputToken) {
+		// Native input is sent with open(), no approval needed
+		if err := checkNativeBalance(client, owner, order.InputAmount); err != nil {
 			client.Close()
-			log.Fatalf("Failed to approve tokens: %v", err)
+			log.Fatalf("%v", err)
+		}
+	} else {
+		// Get initial balances
+		initialUserBalance, err := ethutil.ERC20Balance(client, inputToken, owner)
+		if err == nil {
+			fmt.Printf("   🔍 Initial InputToken balance(owner): %s\n", initialUserBalance.String())
+		} else {
+			fmt.Printf("   ⚠️  Could not read initial balance: %v\n", err)
 		}
 
-		fmt.Printf("   🚀 Approval transaction sent: %s\n", approveTx.Hash().Hex())
+		initialHyperlaneBalance, err := ethutil.ERC20Balance(client, inputToken, spender)
+		if err == nil {
+			fmt.Printf("   🔍 Initial InputToken balance(hyperlane): %s\n", initialHyperlaneBalance.String())
+		} else {
+			fmt.Printf("   ⚠️  Could not read initial hyperlane balance: %v\n", err)
+		}
 
-		// Wait for approval transaction to be mined
-		fmt.Printf("   ⏳ Waiting for approval confirmation...\n")
-		receipt, err := ethutil.WaitForTransaction(client, approveTx)
-		if err != nil {
+		// Check if Alice has sufficient tokens for the order
+		requiredAmount := order.InputAmount
+		if initialUserBalance == nil || initialUserBalance.Cmp(requiredAmount) < 0 {
+			fmt.Printf("   ⚠️  Insufficient balance! Alice needs %s tokens but has %s\n",
+				ethutil.FormatTokenAmount(requiredAmount, 18),
+				ethutil.FormatTokenAmount(initialUserBalance, 18))
+			fmt.Printf("   💡 Please mint tokens manually using the MockERC20 contract's mint() function\n")
+			fmt.Printf("   📝 Contract address: %s\n", inputToken.Hex())
+			fmt.Printf("   🔧 Call: mint(\"%s\", \"%s\")\n", owner.Hex(), requiredAmount.String())
 			client.Close()
-			log.Fatalf("Failed to wait for approval transaction: %v", err)
+			log.Fatalf("Insufficient token balance for order creation")
+		} else {
+			fmt.Printf("   ✅ Alice has sufficient tokens (%s)\n", ethutil.FormatTokenAmount(initialUserBalance, 18))
 		}
 
-		if receipt.Status != 1 {
-			client.Close()
-			log.Fatalf("Approval transaction failed")
+		// Check allowance
+		allowance, err := ethutil.ERC20Allowance(client, inputToken, owner, spender)
+		if err == nil {
+			fmt.Printf("   🔍 Current allowance(owner->hyperlane): %s\n", allowance.String())
+		} else {
+			fmt.Printf("   ⚠️  Could not read allowance: %v\n", err)
 		}
 
-		fmt.Printf("   ✅ Approval confirmed!\n")
-	} else {
-		fmt.Printf("   ✅ Sufficient allowance already exists\n")
+		// If allowance is insufficient, approve the Hyperlane contract
+		requiredAmount = order.InputAmount
+		if allowance.Cmp(requiredAmount) < 0 {
+			fmt.Printf("   🔄 Insufficient allowance, approving %s tokens...\n", requiredAmount.String())
+
+			// Approve the Hyperlane contract to spend the required amount
+			approveTx, err := ethutil.ERC20Approve(client, auth, inputToken, spender, requiredAmount)
+			if err != nil {
+				client.Close()
+				log.Fatalf("Failed to approve tokens: %v", err)
+			}
+
+			fmt.Printf("   🚀 Approval transaction sent: %s\n", approveTx.Hash().Hex())
+			approveTxHash = approveTx.Hash().Hex()
+
+			// Wait for approval transaction to be mined
+			fmt.Printf("   ⏳ Waiting for approval confirmation...\n")
+			receipt, err := ethutil.WaitForTransaction(client, approveTx)
+			if err != nil {
+				client.Close()
+				log.Fatalf("Failed to wait for approval transaction: %v", err)
+			}
+
+			if receipt.Status != 1 {
+				client.Close()
+				log.Fatalf("Approval transaction failed")
+			}
+
+			fmt.Printf("   ✅ Approval confirmed!\n")
+		} else {
+			fmt.Printf("   ✅ Sufficient allowance already exists\n")
+		}
 	}
 
-	// Pick a fresh senderNonce recognized by the contract to avoid InvalidNonce
-	senderNonce, err := pickValidSenderNonce(client, originNetwork.hyperlaneAddress, auth.From)
-	if err != nil {
-		client.Close()
-		log.Fatalf("Failed to pick a valid sender nonce: %v", err)
+	// Pick a fresh senderNonce recognized by the contract to avoid InvalidNonce, unless one was given
+	senderNonce := orderOptions.SenderNonce
+	if senderNonce != nil {
+		if valid, err := isValidNonce(client, originNetwork.hyperlaneAddress, auth.From, senderNonce); err == nil && !valid {
+			fmt.Printf("   ⚠️  Sender nonce %s was already used, the open is expected to revert (InvalidNonce)\n", senderNonce.String())
+		}
+	} else {
+		senderNonce, err = pickValidSenderNonce(client, originNetwork.hyperlaneAddress, auth.From)
+		if err != nil {
+			client.Close()
+			log.Fatalf("Failed to pick a valid sender nonce: %v", err)
+		}
 	}
 
 	// Build the order data
@@ -621,7 +671,7 @@ func executeOrder(order *OrderConfig, networks []NetworkConfig) {
 	crossChainOrder := OnchainCrossChainOrder{
 		FillDeadline:  order.FillDeadline,
 		OrderDataType: getOrderDataTypeHash(),
-		OrderData:     encodeOrderData(&orderData, senderNonce, networks),
+		OrderData:     encodeOrderData(&orderData, senderNonce, networks, order.chaos),
 	}
 
 	// Debug: Log the encoded data
@@ -637,6 +687,11 @@ func executeOrder(order *OrderConfig, networks []NetworkConfig) {
 		log.Fatalf("Failed to bind Hyperlane7683: %v", err)
 	}
 
+	// Base7683.open requires msg.value to match native inputs
+	if isNativeToken(order.InputToken) {
+		auth.Value = new(big.Int).Set(order.InputAmount)
+	}
+
 	tx, err := contract.Open(auth, contracts.OnchainCrossChainOrder{
 		FillDeadline:  crossChainOrder.FillDeadline,
 		OrderDataType: crossChainOrder.OrderDataType,
@@ -677,6 +732,29 @@ func executeOrder(order *OrderConfig, networks []NetworkConfig) {
 		}
 	}
 
+	opened := OpenedOrder{
+		OriginChain:      order.OriginChain,
+		DestinationChain: order.DestinationChain,
+		TxHash:           tx.Hash().Hex(),
+		ApproveTxHash:    approveTxHash,
+		InputToken:       order.InputToken,
+		OutputToken:      order.OutputToken,
+		InputAmount:      amountString(order.InputAmount),
+		OutputAmount:     amountString(order.OutputAmount),
+		SenderNonce:      senderNonce.String(),
+		FillDeadline:     uint64(order.FillDeadline),
+		Success:          receipt.Status == 1,
+	}
+	if opened.Success {
+		if orderID, err := evmOpenedOrderID(receipt, originNetwork.hyperlaneAddress); err != nil {
+			fmt.Printf("   ⚠️  Could not read the order ID: %v\n", err)
+		} else {
+			fmt.Printf("   🆔 Order ID: %s\n", orderID)
+			opened.OrderID = orderID
+		}
+	}
+	recordOpenedOrder(opened)
+
 	fmt.Printf("\n🎉 Order execution completed!\n")
 	fmt.Printf("📊 Order Summary:\n")
 	fmt.Printf("   Input Amount: %s\n", order.InputAmount.String())
@@ -685,6 +763,21 @@ func executeOrder(order *OrderConfig, networks []NetworkConfig) {
 	fmt.Printf("   Destination Chain: %s\n", order.DestinationChain)
 }
 
+// checkNativeBalance makes sure owner can pay amount plus gas in the native asset
+func checkNativeBalance(client *ethclient.Client, owner common.Address, amount *big.Int) error {
+	balance, err := client.BalanceAt(context.Background(), owner, nil)
+	if err != nil {
+		return fmt.Errorf("could not read native balance: %w", err)
+	}
+	fmt.Printf("   🔍 Initial native balance(owner): %s\n", ethutil.FormatTokenAmount(balance, 18))
+	if balance.Cmp(amount) <= 0 {
+		return fmt.Errorf("insufficient native balance: Alice needs more than %s ETH (plus gas) but has %s",
+			ethutil.FormatTokenAmount(amount, 18), ethutil.FormatTokenAmount(balance, 18))
+	}
+	fmt.Printf("   ✅ Alice has sufficient native balance\n")
+	return nil
+}
+
 func buildOrderData(order *OrderConfig, originNetwork, destinationNetwork *NetworkConfig, originDomain uint32, _ *big.Int) OrderData {
 	// Input token from origin network, output token from destination network
 	// inputTokenAddr := originNetwork.dogCoinAddress
@@ -717,6 +810,13 @@ func buildOrderData(order *OrderConfig, originNetwork, destinationNetwork *Netwo
 	// Build proper OrderData with actual token amounts
 	// Map token names to actual addresses
 	inputTokenAddr := originNetwork.dogCoinAddress
+	if isNativeToken(order.InputToken) {
+		inputTokenAddr = common.Address{}
+	}
+	outputTokenAddr := destinationNetwork.dogCoinAddress
+	if isNativeToken(order.OutputToken) && destinationNetwork.name != starknetNetworkName {
+		outputTokenAddr = common.Address{}
+	}
 
 	// For cross-chain orders:
 	// - MaxSpent: What the solver needs to provide (destination chain tokens)
@@ -726,7 +826,7 @@ func buildOrderData(order *OrderConfig, originNetwork, destinationNetwork *Netwo
 	// Set up token amounts (same for both EVM→Starknet and EVM→EVM orders)
 	maxSpent = []TokenAmount{
 		{
-			Token:   destinationNetwork.dogCoinAddress.Hex(), // Destination chain token
+			Token:   outputTokenAddr.Hex(),                   // Destination chain token
 			Amount:  uint256.MustFromBig(order.OutputAmount), // Amount solver needs to provide
 			ChainID: big.NewInt(int64(destinationChainID)),   // Destination chain ID
 		},
@@ -743,10 +843,12 @@ func buildOrderData(order *OrderConfig, originNetwork, destinationNetwork *Netwo
 		OriginChainID:      big.NewInt(int64(originDomain)),
 		DestinationChainID: big.NewInt(int64(destinationChainID)),
 		User:               order.User,
-		OpenDeadline:       big.NewInt(time.Now().Add(1 * time.Hour).Unix()),
+		OpenDeadline:       big.NewInt(int64(order.OpenDeadline)),
 		FillDeadline:       big.NewInt(int64(order.FillDeadline)),
 		MaxSpent:           maxSpent,
 		MinReceived:        minReceived,
+		InputToken:         order.InputToken,
+		OutputToken:        order.OutputToken,
 	}
 }
 
@@ -878,9 +980,12 @@ func hexToBytes32(hexStr string) [32]byte {
 	return out
 }
 
-func encodeOrderData(orderData *OrderData, senderNonce *big.Int, networks []NetworkConfig) []byte {
+func encodeOrderData(orderData *OrderData, senderNonce *big.Int, networks []NetworkConfig, chaos *chaosScenario) []byte {
 	// Convert OrderData to ABIOrderData for encoding
 	abiOrderData := convertToABIOrderData(orderData, senderNonce, networks)
+	if chaos != nil {
+		chaos.evm(&abiOrderData)
+	}
 
 
 	// Pack as a tuple to match Solidity's abi.encode(order)
@@ -996,21 +1101,21 @@ func convertToABIOrderData(orderData *OrderData, senderNonce *big.Int, networks
 		}
 	}
 
-	// Set InputToken (origin chain token - what Alice locks up)
+	// Set InputToken (origin chain token - what Alice locks up); the native asset stays zero
 	if originTokenAddr != (common.Address{}) {
-		copy(inputTokenBytes[12:], originTokenAddr.Bytes()) // Left-pad to 32 bytes
+		inputTokenBytes = evmTokenBytes32(orderData.InputToken, originTokenAddr)
 	}
 
 	// Set OutputToken (destination chain token - what solver provides to Alice)
 	if destinationChainID == config.StarknetSepoliaChainID { // Starknet destination
 		// For Starknet, use the full address without padding
-		starknetDogCoin := os.Getenv("STARKNET_DOG_COIN_ADDRESS")
-		if starknetDogCoin != "" {
-			outputTokenBytes = hexToBytes32(starknetDogCoin)
+		starknetToken := starknetTokenAddress(orderData.OutputToken, os.Getenv("STARKNET_DOG_COIN_ADDRESS"))
+		if starknetToken != "" {
+			outputTokenBytes = hexToBytes32(starknetToken)
 		}
 	} else if destinationTokenAddr != (common.Address{}) {
-		// For EVM destinations, left-pad the 20-byte address
-		copy(outputTokenBytes[12:], destinationTokenAddr.Bytes()) // Left-pad to 32 bytes
+		// For EVM destinations, left-pad the 20-byte address; the native asset stays zero
+		outputTokenBytes = evmTokenBytes32(orderData.OutputToken, destinationTokenAddr)
 	}
 
 	// Set destination settler address (Hyperlane contract on destination chain)
@@ -0,0 +1,61 @@
+package openorder
+
+// Native asset orders
+// - On EVM chains "ETH" is the native asset: the order encodes it as the zero address, open() is
+//   sent with the input amount as msg.value and the solver fills by attaching the output amount
+// - On Starknet ETH and STRK are ERC20 contracts, so they are approved and pulled like DogCoin
+// - Any other token name selects the chain's DogCoin
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Token names accepted in order configs
+const (
+	tokenDogCoin = "DogCoin"
+	tokenETH     = "ETH"
+	tokenSTRK    = "STRK"
+)
+
+// Starknet fee token contracts (same address on mainnet, Sepolia and devnet)
+const (
+	starknetETHAddress  = "0x049d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7"
+	starknetSTRKAddress = "0x04718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d"
+)
+
+// Native test order amounts: the solver provides 0.01 and receives 0.0101
+var (
+	nativeOutputAmount = big.NewInt(10_000_000_000_000_000)
+	nativeInputAmount  = big.NewInt(10_100_000_000_000_000)
+)
+
+// isNativeToken reports whether the token name is the native asset of an EVM chain
+func isNativeToken(name string) bool {
+	return strings.EqualFold(name, tokenETH)
+}
+
+// evmTokenBytes32 returns the order encoding of a token on an EVM chain: the zero address for
+// the native asset, otherwise the left-padded DogCoin address
+func evmTokenBytes32(name string, dogCoin common.Address) [32]byte {
+	var out [32]byte
+	if isNativeToken(name) {
+		return out
+	}
+	copy(out[12:], dogCoin.Bytes())
+	return out
+}
+
+// starknetTokenAddress returns the Starknet contract of a token name
+func starknetTokenAddress(name, dogCoin string) string {
+	switch strings.ToUpper(name) {
+	case tokenETH:
+		return starknetETHAddress
+	case tokenSTRK:
+		return starknetSTRKAddress
+	default:
+		return dogCoin
+	}
+}
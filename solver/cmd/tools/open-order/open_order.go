@@ -7,29 +7,32 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 )
 
 // RunOpenOrder runs Alice's order creation tool
 func RunOpenOrder(args []string) {
 	if len(args) == 0 {
-		fmt.Println("Usage: open-order <chain> [command]")
+		fmt.Println("Usage: open-order <chain> [command] [--open-deadline D] [--fill-deadline D] [--sender-nonce N] [--result-file FILE]")
 		fmt.Println("Available chains: starknet, evm")
 		os.Exit(1)
 	}
 
 	chain := strings.ToLower(args[0])
-	command := "default"
-	if len(args) > 1 {
-		command = args[1]
+	command, flags := SplitOrderArgs(args[1:], "default")
+	opts, err := ParseOrderOptions(flags, time.Now())
+	if err != nil {
+		fmt.Printf("Invalid options: %v\n", err)
+		os.Exit(1)
 	}
 
 	switch chain {
 	case "starknet":
 		fmt.Println("🎯 Running Alice's Starknet order creation...")
-		RunStarknetOrder(command)
+		RunStarknetOrder(command, opts)
 	case "evm":
 		fmt.Println("🎯 Running Alice's EVM order creation...")
-		RunEVMOrder(command)
+		RunEVMOrder(command, opts)
 	default:
 		fmt.Printf("Unknown chain: %s\n", chain)
 		fmt.Println("Available chains: starknet, evm")
@@ -0,0 +1,130 @@
+package openorder
+
+// Order options - flags overriding the defaults of the open-order commands, so tests can create
+// orders that are about to expire or that reuse a sender nonce
+//
+// Usage:
+//
+//	open-order <chain> [command] [--open-deadline D] [--fill-deadline D] [--sender-nonce N] [--result-file FILE]
+//
+// Deadlines are a unix timestamp or a duration from now, e.g. 90s, 5m or -1m (already passed).
+// Orders are opened with open(), for which the router ignores the open deadline: --open-deadline
+// only changes the order data built locally. --sender-nonce uses the nonce as is, even one that
+// was already used; without it a fresh nonce is picked. --result-file writes the opened orders as
+// JSON (see OpenedOrder) once the run completes, for callers such as the integration tests.
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OrderOptions are the overrides parsed from the open-order flags; zero values keep the defaults
+type OrderOptions struct {
+	OpenDeadline uint32   // Unix timestamp, 0 keeps the command's default
+	FillDeadline uint32   // Unix timestamp, 0 keeps the command's default
+	SenderNonce  *big.Int // nil picks a fresh nonce
+	ResultFile   string   // Written with the opened orders when set
+}
+
+// orderOptions apply to the order being opened; set by RunEVMOrder and RunStarknetOrder
+var orderOptions OrderOptions
+
+// ParseOrderOptions parses the open-order flags; deadlines given as durations are relative to now
+func ParseOrderOptions(args []string, now time.Time) (OrderOptions, error) {
+	var opts OrderOptions
+	var openDeadline, fillDeadline, senderNonce string
+	fs := flag.NewFlagSet("open-order", flag.ContinueOnError)
+	fs.StringVar(&openDeadline, "open-deadline", "", "open deadline: unix timestamp or duration from now")
+	fs.StringVar(&fillDeadline, "fill-deadline", "", "fill deadline: unix timestamp or duration from now")
+	fs.StringVar(&senderNonce, "sender-nonce", "", "sender nonce to use, even if already used")
+	fs.StringVar(&opts.ResultFile, "result-file", "", "write the opened orders to this JSON file")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	var err error
+	if opts.OpenDeadline, err = parseDeadline(openDeadline, now); err != nil {
+		return opts, fmt.Errorf("--open-deadline: %w", err)
+	}
+	if opts.FillDeadline, err = parseDeadline(fillDeadline, now); err != nil {
+		return opts, fmt.Errorf("--fill-deadline: %w", err)
+	}
+	if senderNonce != "" {
+		nonce, ok := new(big.Int).SetString(senderNonce, 0)
+		if !ok || nonce.Sign() < 0 || nonce.BitLen() > 256 {
+			return opts, fmt.Errorf("--sender-nonce: invalid nonce %q", senderNonce)
+		}
+		opts.SenderNonce = nonce
+	}
+	return opts, nil
+}
+
+// SplitOrderArgs separates the optional command from the flags following it
+func SplitOrderArgs(args []string, defaultCommand string) (string, []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return defaultCommand, args
+	}
+	return args[0], args[1:]
+}
+
+// parseDeadline converts a unix timestamp or a duration from now into a timestamp; empty returns 0
+func parseDeadline(value string, now time.Time) (uint32, error) {
+	if value == "" {
+		return 0, nil
+	}
+	var deadline int64
+	if ts, err := strconv.ParseInt(value, 10, 64); err == nil {
+		deadline = ts
+	} else {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid deadline %q (use a unix timestamp or a duration such as 5m)", value)
+		}
+		deadline = now.Add(d).Unix()
+	}
+	if deadline <= 0 || deadline > math.MaxUint32 {
+		return 0, fmt.Errorf("deadline %q is out of range", value)
+	}
+	return uint32(deadline), nil
+}
+
+// applyEVM overrides the deadlines of an EVM order
+func (o OrderOptions) applyEVM(order *OrderConfig) {
+	if o.OpenDeadline != 0 {
+		order.OpenDeadline = o.OpenDeadline
+	}
+	if o.FillDeadline != 0 {
+		order.FillDeadline = o.FillDeadline
+	}
+}
+
+// applyStarknet overrides the deadlines of a Starknet order
+func (o OrderOptions) applyStarknet(order *StarknetOrderConfig) {
+	if o.OpenDeadline != 0 {
+		order.OpenDeadline = uint64(o.OpenDeadline)
+	}
+	if o.FillDeadline != 0 {
+		order.FillDeadline = uint64(o.FillDeadline)
+	}
+}
+
+// describe logs the overrides in effect
+func (o OrderOptions) describe() {
+	if o.OpenDeadline != 0 {
+		fmt.Printf("   ⏰ Open deadline: %s\n", time.Unix(int64(o.OpenDeadline), 0).UTC().Format(time.RFC3339))
+	}
+	if o.FillDeadline != 0 {
+		fmt.Printf("   ⏰ Fill deadline: %s\n", time.Unix(int64(o.FillDeadline), 0).UTC().Format(time.RFC3339))
+	}
+	if o.SenderNonce != nil {
+		fmt.Printf("   🔢 Sender nonce: %s\n", o.SenderNonce.String())
+	}
+}
@@ -6,8 +6,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestOrderOpening tests the order opening functionality
@@ -90,6 +93,88 @@ func TestOrderDataValidation(t *testing.T) {
 	})
 }
 
+// TestNativeTokenOrders tests the token encoding of native asset orders
+func TestNativeTokenOrders(t *testing.T) {
+	ethDogCoin := common.HexToAddress("0x00000000000000000000000000000000000000d1")
+	opDogCoin := common.HexToAddress("0x00000000000000000000000000000000000000d2")
+	networks := []NetworkConfig{
+		{name: "Ethereum", chainID: config.EthereumSepoliaChainID, dogCoinAddress: ethDogCoin},
+		{name: "Optimism", chainID: config.OptimismSepoliaChainID, dogCoinAddress: opDogCoin},
+	}
+	orderData := func(destinationChainID uint64, inputToken, outputToken string) OrderData {
+		return OrderData{
+			OriginChainID:      big.NewInt(config.EthereumSepoliaChainID),
+			DestinationChainID: new(big.Int).SetUint64(destinationChainID),
+			FillDeadline:       big.NewInt(time.Now().Add(1 * time.Hour).Unix()),
+			MaxSpent:           []TokenAmount{{Amount: uint256.MustFromBig(nativeOutputAmount)}},
+			MinReceived:        []TokenAmount{{Amount: uint256.MustFromBig(nativeInputAmount)}},
+			InputToken:         inputToken,
+			OutputToken:        outputToken,
+		}
+	}
+
+	t.Run("Native EVM tokens are the zero address", func(t *testing.T) {
+		data := orderData(config.OptimismSepoliaChainID, tokenETH, tokenETH)
+		encoded := convertToABIOrderData(&data, big.NewInt(1), networks)
+		assert.Equal(t, [32]byte{}, encoded.InputToken)
+		assert.Equal(t, [32]byte{}, encoded.OutputToken)
+		assert.Equal(t, nativeInputAmount, encoded.AmountIn)
+		assert.Equal(t, nativeOutputAmount, encoded.AmountOut)
+	})
+
+	t.Run("DogCoin orders keep the token addresses", func(t *testing.T) {
+		data := orderData(config.OptimismSepoliaChainID, tokenDogCoin, tokenDogCoin)
+		encoded := convertToABIOrderData(&data, big.NewInt(1), networks)
+		assert.Equal(t, common.LeftPadBytes(ethDogCoin.Bytes(), 32), encoded.InputToken[:])
+		assert.Equal(t, common.LeftPadBytes(opDogCoin.Bytes(), 32), encoded.OutputToken[:])
+	})
+
+	t.Run("Starknet ETH output is the ERC20 contract", func(t *testing.T) {
+		t.Setenv("STARKNET_DOG_COIN_ADDRESS", "0x00000000000000000000000000000000000000000000000000000000000000d3")
+		data := orderData(config.StarknetSepoliaChainID, tokenETH, tokenETH)
+		encoded := convertToABIOrderData(&data, big.NewInt(1), networks)
+		assert.Equal(t, [32]byte{}, encoded.InputToken)
+		assert.Equal(t, hexToBytes32(starknetETHAddress), encoded.OutputToken)
+
+		data = orderData(config.StarknetSepoliaChainID, tokenDogCoin, tokenDogCoin)
+		encoded = convertToABIOrderData(&data, big.NewInt(1), networks)
+		assert.Equal(t, hexToBytes32("0xd3"), encoded.OutputToken)
+	})
+
+	t.Run("Starknet token names", func(t *testing.T) {
+		assert.Equal(t, starknetETHAddress, starknetTokenAddress("eth", "0xd3"))
+		assert.Equal(t, starknetSTRKAddress, starknetTokenAddress(tokenSTRK, "0xd3"))
+		assert.Equal(t, "0xd3", starknetTokenAddress(tokenDogCoin, "0xd3"))
+		assert.False(t, isNativeToken(tokenSTRK), "STRK is not native on EVM chains")
+	})
+}
+
+// TestStarknetToStarknetOrderData tests that same-chain Starknet orders pay and settle on Starknet
+func TestStarknetToStarknetOrderData(t *testing.T) {
+	t.Setenv("IS_DEVNET", "false")
+	t.Setenv("STARKNET_ALICE_ADDRESS", "0x0000000000000000000000000000000000000000000000000000000000000a11")
+	origin := &StarknetNetworkConfig{
+		name:             "Starknet",
+		chainID:          config.StarknetSepoliaChainID,
+		hyperlaneAddress: "0x0000000000000000000000000000000000000000000000000000000000005678",
+		dogCoinAddress:   "0x00000000000000000000000000000000000000000000000000000000000000d3",
+	}
+	order := &StarknetOrderConfig{
+		OriginChain:      "Starknet",
+		DestinationChain: "Starknet",
+		InputToken:       tokenDogCoin,
+		OutputToken:      tokenDogCoin,
+		InputAmount:      big.NewInt(1000),
+		OutputAmount:     big.NewInt(990),
+	}
+
+	data := buildStarknetOrderData(order, origin, 1, 1, big.NewInt(1), "Starknet")
+	assert.Equal(t, "0xa11", data.Recipient.String(), "Alice's Starknet address, not her EVM one")
+	assert.Equal(t, "0x5678", data.DestinationSettler.String(), "the Starknet router, not an EVM address")
+	assert.Equal(t, "0xd3", data.OutputToken.String())
+	assert.Equal(t, data.OriginDomain, data.DestinationDomain)
+}
+
 // TestOrderIDCalculation tests the order ID calculation
 //
 //	func TestOrderIDCalculation(t *testing.T) {
@@ -150,3 +235,85 @@ func TestBalanceVerification(t *testing.T) {
 		assert.False(t, hasSufficientBalance, "User should not have sufficient balance")
 	})
 }
+
+// TestParseOrderOptions tests the deadline and nonce flags of the open-order commands
+func TestParseOrderOptions(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	t.Run("defaults", func(t *testing.T) {
+		opts, err := ParseOrderOptions(nil, now)
+		require.NoError(t, err)
+		assert.Equal(t, OrderOptions{}, opts)
+	})
+
+	t.Run("durations, timestamps and nonce", func(t *testing.T) {
+		opts, err := ParseOrderOptions([]string{"--open-deadline", "-1m", "--fill-deadline", "1700000090", "--sender-nonce", "0x2a"}, now)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(1_699_999_940), opts.OpenDeadline)
+		assert.Equal(t, uint32(1_700_000_090), opts.FillDeadline)
+		assert.Equal(t, big.NewInt(42), opts.SenderNonce)
+		assert.Empty(t, opts.ResultFile)
+
+		order := OrderConfig{OpenDeadline: 1, FillDeadline: 2}
+		opts.applyEVM(&order)
+		assert.Equal(t, uint32(1_699_999_940), order.OpenDeadline)
+		assert.Equal(t, uint32(1_700_000_090), order.FillDeadline)
+	})
+
+	t.Run("result file", func(t *testing.T) {
+		opts, err := ParseOrderOptions([]string{"--result-file", "order.json"}, now)
+		require.NoError(t, err)
+		assert.Equal(t, "order.json", opts.ResultFile)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParseOrderOptions([]string{"--fill-deadline", "soon"}, now)
+		assert.ErrorContains(t, err, "--fill-deadline")
+		_, err = ParseOrderOptions([]string{"--open-deadline", "99999999999"}, now)
+		assert.ErrorContains(t, err, "out of range")
+		_, err = ParseOrderOptions([]string{"--sender-nonce", "-1"}, now)
+		assert.ErrorContains(t, err, "--sender-nonce")
+		_, err = ParseOrderOptions([]string{"extra"}, now)
+		assert.ErrorContains(t, err, "unexpected argument")
+	})
+
+	t.Run("command before the flags", func(t *testing.T) {
+		command, flags := SplitOrderArgs([]string{"default-evm-evm", "--sender-nonce", "7"}, "random-to-evm")
+		assert.Equal(t, "default-evm-evm", command)
+		assert.Equal(t, []string{"--sender-nonce", "7"}, flags)
+
+		command, flags = SplitOrderArgs([]string{"--fill-deadline", "30s"}, "random-to-evm")
+		assert.Equal(t, "random-to-evm", command)
+		assert.Equal(t, []string{"--fill-deadline", "30s"}, flags)
+	})
+}
+
+// TestChaosScenarios tests selecting and applying the pathological order scenarios
+func TestChaosScenarios(t *testing.T) {
+	t.Run("selection", func(t *testing.T) {
+		all, err := selectChaosScenarios("chaos")
+		require.NoError(t, err)
+		assert.Len(t, all, len(chaosScenarios))
+
+		one, err := selectChaosScenarios("chaos-random-settler")
+		require.NoError(t, err)
+		require.Len(t, one, 1)
+		assert.Equal(t, "untrusted_settler", one[0].expected)
+
+		_, err = selectChaosScenarios("chaos-meteor")
+		assert.ErrorContains(t, err, "unknown chaos scenario")
+	})
+
+	t.Run("every scenario changes the EVM and Starknet order data", func(t *testing.T) {
+		for _, scenario := range chaosScenarios {
+			evm := ABIOrderData{AmountOut: big.NewInt(1000)}
+			original := evm
+			scenario.evm(&evm)
+			assert.NotEqual(t, original, evm, scenario.name)
+
+			starknet := StarknetOrderData{AmountOut: big.NewInt(1000)}
+			scenario.starknet(&starknet)
+			assert.NotEqual(t, StarknetOrderData{AmountOut: big.NewInt(1000)}, starknet, scenario.name)
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package openorder
+
+// Order results - the orders opened by a run, printed as a JSON array with the global --json flag
+// and written to --result-file, so callers such as the integration tests read the order ID,
+// transaction hashes and amounts instead of parsing the progress messages. A run that fails
+// exits before either is written.
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/NethermindEth/oif-starknet/solver/cmd/cliout"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+// OpenedOrder is an order opened by the run
+type OpenedOrder struct {
+	OrderID          string `json:"orderId,omitempty"` // Empty when the open reverted
+	OriginChain      string `json:"originChain"`
+	DestinationChain string `json:"destinationChain"`
+	TxHash           string `json:"txHash"`
+	ApproveTxHash    string `json:"approveTxHash,omitempty"`
+	InputToken       string `json:"inputToken"`
+	OutputToken      string `json:"outputToken"`
+	InputAmount      string `json:"inputAmount"`
+	OutputAmount     string `json:"outputAmount"`
+	SenderNonce      string `json:"senderNonce"`
+	FillDeadline     uint64 `json:"fillDeadline"`
+	Success          bool   `json:"success"`
+}
+
+// openedOrders are the orders opened so far, in order
+var openedOrders []OpenedOrder
+
+// recordOpenedOrder adds an order to the run's results
+func recordOpenedOrder(order OpenedOrder) {
+	openedOrders = append(openedOrders, order)
+}
+
+// writeOpenedOrders prints the run's orders in JSON mode and writes the result file when set; a
+// run that opened nothing writes []
+func writeOpenedOrders() {
+	orders := openedOrders
+	if orders == nil {
+		orders = []OpenedOrder{}
+	}
+	if orderOptions.ResultFile != "" {
+		if err := WriteResultFile(orderOptions.ResultFile, orders); err != nil {
+			log.Fatalf("Failed to write %s: %v", orderOptions.ResultFile, err)
+		}
+	}
+	if cliout.JSON() {
+		if err := cliout.WriteJSON(orders); err != nil {
+			log.Fatalf("Failed to write the opened orders: %v", err)
+		}
+	}
+}
+
+// WriteResultFile writes orders to path through a temporary file, so a reader never sees a
+// partial result
+func WriteResultFile(path string, orders []OpenedOrder) error {
+	data, err := json.MarshalIndent(orders, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ReadResultFile reads the orders written to a result file
+func ReadResultFile(path string) ([]OpenedOrder, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var orders []OpenedOrder
+	if err := json.Unmarshal(data, &orders); err != nil {
+		return nil, fmt.Errorf("invalid result file %s: %w", path, err)
+	}
+	return orders, nil
+}
+
+// amountString formats an order amount, empty when unset
+func amountString(amount *big.Int) string {
+	if amount == nil {
+		return ""
+	}
+	return amount.String()
+}
+
+// evmOpenedOrderID returns the order ID of the Open event router emitted in receipt
+func evmOpenedOrderID(receipt *types.Receipt, router common.Address) (string, error) {
+	filterer, err := contracts.NewHyperlane7683Filterer(router, nil)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range receipt.Logs {
+		if entry.Address != router {
+			continue
+		}
+		// Logs of other events fail to parse as Open
+		if event, err := filterer.ParseOpen(*entry); err == nil {
+			return common.BytesToHash(event.OrderId[:]).Hex(), nil
+		}
+	}
+	return "", fmt.Errorf("no Open event from %s in transaction %s", router.Hex(), receipt.TxHash.Hex())
+}
@@ -0,0 +1,71 @@
+package openorder
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEVMOpenedOrderID tests finding the order ID among the logs of an open transaction
+func TestEVMOpenedOrderID(t *testing.T) {
+	router := common.HexToAddress("0x00000000000000000000000000000000000076b3")
+	parsedABI, err := contracts.Hyperlane7683MetaData.GetAbi()
+	require.NoError(t, err)
+
+	var orderID [32]byte
+	orderID[31] = 0x42
+	data, err := parsedABI.Events["Open"].Inputs.NonIndexed().Pack(contracts.ResolvedCrossChainOrder{
+		OriginChainId: big.NewInt(84532),
+		OrderId:       orderID,
+	})
+	require.NoError(t, err)
+	open := &types.Log{Address: router, Topics: []common.Hash{parsedABI.Events["Open"].ID, common.BytesToHash(orderID[:])}, Data: data}
+	transfer := &types.Log{Address: router, Topics: []common.Hash{common.HexToHash("0xddf252ad")}}
+
+	id, err := evmOpenedOrderID(&types.Receipt{Logs: []*types.Log{transfer, open}}, router)
+	require.NoError(t, err)
+	assert.Equal(t, common.BytesToHash(orderID[:]).Hex(), id)
+
+	_, err = evmOpenedOrderID(&types.Receipt{Logs: []*types.Log{open}}, common.HexToAddress("0x01"))
+	assert.ErrorContains(t, err, "no Open event")
+}
+
+func TestRecordOpenedOrder(t *testing.T) {
+	defer func() { openedOrders = nil }()
+	recordOpenedOrder(OpenedOrder{OriginChain: "Base", DestinationChain: "Starknet", TxHash: "0xab", InputAmount: amountString(big.NewInt(10)), OutputAmount: amountString(nil)})
+	require.Len(t, openedOrders, 1)
+	assert.Equal(t, "10", openedOrders[0].InputAmount)
+	assert.Empty(t, openedOrders[0].OutputAmount)
+}
+
+func TestResultFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "order.json")
+	orders := []OpenedOrder{{OrderID: "0x42", OriginChain: "Base", DestinationChain: "Starknet", TxHash: "0xab", InputAmount: "10", OutputAmount: "9", Success: true}}
+	require.NoError(t, WriteResultFile(path, orders))
+
+	read, err := ReadResultFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, orders, read)
+	assert.NoFileExists(t, path+".tmp")
+
+	t.Run("written at the end of the run", func(t *testing.T) {
+		defer func() { openedOrders, orderOptions = nil, OrderOptions{} }()
+		orderOptions = OrderOptions{ResultFile: filepath.Join(t.TempDir(), "empty.json")}
+		writeOpenedOrders()
+		read, err := ReadResultFile(orderOptions.ResultFile)
+		require.NoError(t, err)
+		assert.Empty(t, read)
+		assert.NotNil(t, read, "written as []")
+	})
+
+	require.NoError(t, os.WriteFile(path, []byte("✅ Order opened"), 0600))
+	_, err = ReadResultFile(path)
+	assert.ErrorContains(t, err, "invalid result file")
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
 )
 
 const (
@@ -68,6 +69,8 @@ type StarknetOrderConfig struct {
 	User             string
 	OpenDeadline     uint64
 	FillDeadline     uint64
+	// chaos alters the order data into a pathological order; nil for a regular order
+	chaos *chaosScenario
 }
 
 // OrderData struct matching the Cairo OrderData
@@ -157,9 +160,11 @@ func loadStarknetNetworks() []StarknetNetworkConfig {
 	return networks
 }
 
-// RunStarknetOrder creates a Starknet order based on the command
-func RunStarknetOrder(command string) {
+// RunStarknetOrder creates a Starknet order based on the command; opts override its deadlines and nonce
+func RunStarknetOrder(command string, opts OrderOptions) {
 	fmt.Println("🎯 Running Starknet order creation...")
+	orderOptions = opts
+	defer writeOpenedOrders()
 
 	// Load configuration (this loads .env and initializes networks)
 	_, err := config.LoadConfig()
@@ -173,11 +178,22 @@ func RunStarknetOrder(command string) {
 	// Load network configuration
 	networks := loadStarknetNetworks()
 
+	if strings.HasPrefix(command, "chaos") {
+		openChaosStarknetToEvm(networks, command)
+		return
+	}
+
 	switch command {
 	case "random":
 		openRandomStarknetOrder(networks)
 	case "default":
 		openDefaultStarknetToEvm(networks)
+	case "native":
+		openNativeStarknetToEvm(networks, tokenETH)
+	case "native-strk":
+		openNativeStarknetToEvm(networks, tokenSTRK)
+	case "random-to-sn":
+		openRandomStarknetToStarknet(networks)
 	default:
 		// Default to random Starknet order
 		openRandomStarknetOrder(networks)
@@ -219,6 +235,38 @@ func openRandomStarknetOrder(networks []StarknetNetworkConfig) {
 	executeStarknetOrder(&order, networks)
 }
 
+// openRandomStarknetToStarknet opens a same-chain DogCoin swap on Starknet, paid to Alice's Starknet address
+func openRandomStarknetToStarknet(networks []StarknetNetworkConfig) {
+	fmt.Println("🎲 Opening Random Starknet → Starknet Test Order...")
+
+	originChain := "Starknet"
+	destinationChain := "Starknet"
+
+	user, err := getAliceAddressForNetwork(destinationChain)
+	if err != nil {
+		log.Fatalf("Failed to get Alice address for %s: %v", destinationChain, err)
+	}
+
+	// Random amounts
+	inputAmount := CreateTokenAmount(int64(secureRandomInt(maxTokenAmount-minTokenAmount)+minTokenAmount), 18) // 100-10000 tokens
+	delta := CreateTokenAmount(int64(secureRandomInt(maxDeltaAmount-minDeltaAmount)+minDeltaAmount), 18)       // 1-10 tokens
+	outputAmount := new(big.Int).Sub(inputAmount, delta)                                                       // slightly less to ensure it's fillable
+
+	order := StarknetOrderConfig{
+		OriginChain:      originChain,
+		DestinationChain: destinationChain,
+		InputToken:       "DogCoin",
+		OutputToken:      "DogCoin",
+		InputAmount:      inputAmount,
+		OutputAmount:     outputAmount,
+		User:             user,
+		OpenDeadline:     uint64(time.Now().Add(1 * time.Hour).Unix()),
+		FillDeadline:     uint64(time.Now().Add(24 * time.Hour).Unix()),
+	}
+
+	executeStarknetOrder(&order, networks)
+}
+
 func openDefaultStarknetToEvm(networks []StarknetNetworkConfig) {
 	fmt.Println("🎯 Opening Default Starknet → EVM Test Order...")
 
@@ -247,8 +295,37 @@ func openDefaultStarknetToEvm(networks []StarknetNetworkConfig) {
 	executeStarknetOrder(&order, networks)
 }
 
+// openNativeStarknetToEvm locks Starknet ETH or STRK for native ETH on the default EVM destination
+func openNativeStarknetToEvm(networks []StarknetNetworkConfig, inputToken string) {
+	fmt.Printf("🎯 Opening Native %s Starknet → EVM Test Order...\n", inputToken)
+
+	originChain := "Starknet"
+	destinationChain := getEnvWithDefault("DEFAULT_EVM_DESTINATION", "Ethereum")
+
+	aliceAddress, err := getAliceAddressForNetwork(destinationChain)
+	if err != nil {
+		log.Fatalf("Failed to get Alice address for %s: %v", destinationChain, err)
+	}
+
+	order := StarknetOrderConfig{
+		OriginChain:      originChain,
+		DestinationChain: destinationChain,
+		InputToken:       inputToken,
+		OutputToken:      tokenETH,
+		InputAmount:      new(big.Int).Set(nativeInputAmount),  // 0.0101 (what solver receives)
+		OutputAmount:     new(big.Int).Set(nativeOutputAmount), // 0.01 ETH (what solver provides)
+		User:             aliceAddress,
+		OpenDeadline:     uint64(time.Now().Add(1 * time.Hour).Unix()),
+		FillDeadline:     uint64(time.Now().Add(24 * time.Hour).Unix()),
+	}
+
+	executeStarknetOrder(&order, networks)
+}
+
 func executeStarknetOrder(order *StarknetOrderConfig, networks []StarknetNetworkConfig) {
 	fmt.Printf("\n📋 Executing Order: %s → %s\n", order.OriginChain, order.DestinationChain)
+	orderOptions.applyStarknet(order)
+	orderOptions.describe()
 
 	// Find origin network (should be Starknet)
 	var originNetwork *StarknetNetworkConfig
@@ -312,7 +389,7 @@ func executeStarknetOrder(order *StarknetOrderConfig, networks []StarknetNetwork
 	}
 
 	// Preflight: check balances and allowances
-	inputToken := originNetwork.dogCoinAddress
+	inputToken := starknetTokenAddress(order.InputToken, originNetwork.dogCoinAddress)
 	owner := userAddr
 	spender := originNetwork.hyperlaneAddress
 
@@ -360,6 +437,7 @@ func executeStarknetOrder(order *StarknetOrderConfig, networks []StarknetNetwork
 		fmt.Printf("❌ Failed to create account for %s: %v\n", order.User, err)
 		os.Exit(1)
 	}
+	contracts := starknetutil.NewAccountClient(userAccnt)
 
 	// Check allowance
 	allowance, err := starknetutil.ERC20Allowance(client, inputToken, owner, spender)
@@ -373,6 +451,7 @@ func executeStarknetOrder(order *StarknetOrderConfig, networks []StarknetNetwork
 	// initialBalance := initialUserBalance
 
 	// If allowance is insufficient, approve the Hyperlane contract
+	var approveTxHash string
 	requiredAmount = order.InputAmount
 	if allowance == nil || allowance.Cmp(requiredAmount) < 0 {
 		fmt.Printf("   🔄 Insufficient allowance, approving %s tokens...\n", starknetutil.FormatTokenAmount(requiredAmount, 18))
@@ -385,17 +464,18 @@ func executeStarknetOrder(order *StarknetOrderConfig, networks []StarknetNetwork
 		}
 
 		// Send approval transaction
-		approveTx, err := userAccnt.BuildAndSendInvokeTxn(context.Background(), []rpc.InvokeFunctionCall{*approveCall}, nil)
+		approveHash, err := contracts.Invoke(context.Background(), *approveCall)
 		if err != nil {
 			fmt.Printf("❌ Failed to send approval transaction: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("   🚀 Approval transaction sent: %s\n", approveTx.Hash.String())
+		fmt.Printf("   🚀 Approval transaction sent: %s\n", approveHash.String())
+		approveTxHash = approveHash.String()
 		fmt.Printf("   ⏳ Waiting for approval confirmation...\n")
 
 		// Wait for approval transaction to be mined
-		_, err = userAccnt.WaitForTransactionReceipt(context.Background(), approveTx.Hash, 2*time.Second)
+		_, err = contracts.WaitForAcceptance(context.Background(), approveHash, 2*time.Second)
 		if err != nil {
 			fmt.Printf("❌ Failed to wait for approval transaction: %v\n", err)
 			os.Exit(1)
@@ -406,11 +486,17 @@ func executeStarknetOrder(order *StarknetOrderConfig, networks []StarknetNetwork
 		fmt.Printf("   ✅ Sufficient allowance already exists\n")
 	}
 
-	// Generate a random nonce for the order
+	// Generate a random nonce for the order, unless one was given
 	senderNonce := big.NewInt(time.Now().UnixNano())
+	if orderOptions.SenderNonce != nil {
+		senderNonce = orderOptions.SenderNonce
+	}
 
 	// Build the order data
 	orderData := buildStarknetOrderData(order, originNetwork, originDomain, destinationDomain, senderNonce, order.DestinationChain)
+	if order.chaos != nil {
+		order.chaos.starknet(&orderData)
+	}
 
 	// Build the StarknetOnchainCrossChainOrder with u256 order_data_type (low, high)
 	lowHash, highHash := getOrderDataTypeHashU256()
@@ -441,31 +527,47 @@ func executeStarknetOrder(order *StarknetOrderConfig, networks []StarknetNetwork
 	}
 	calldata = append(calldata, crossChainOrder.OrderData...)
 
-	tx, err := userAccnt.BuildAndSendInvokeTxn(
-		context.Background(),
-		[]rpc.InvokeFunctionCall{{
-			ContractAddress: hyperlaneAddrFelt,
-			FunctionName:    "open",
-			CallData:        calldata,
-		}},
-		nil,
-	)
+	txHash, err := contracts.Invoke(context.Background(), rpc.InvokeFunctionCall{
+		ContractAddress: hyperlaneAddrFelt,
+		FunctionName:    "open",
+		CallData:        calldata,
+	})
 	if err != nil {
 		fmt.Printf("❌ Failed to send open transaction: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("   🚀 Transaction sent: %s\n", tx.Hash.String())
+	fmt.Printf("   🚀 Transaction sent: %s\n", txHash.String())
 	fmt.Printf("   ⏳ Waiting for confirmation...\n")
 
 	// Wait for transaction receipt
-	_, err = userAccnt.WaitForTransactionReceipt(context.Background(), tx.Hash, time.Second)
+	receipt, err := contracts.WaitForAcceptance(context.Background(), txHash, time.Second)
 	if err != nil {
 		fmt.Printf("❌ Failed to wait for transaction confirmation: %v\n", err)
 		os.Exit(1)
 	}
 
 	fmt.Printf("   ✅ Order opened successfully!\n")
+	opened := OpenedOrder{
+		OriginChain:      order.OriginChain,
+		DestinationChain: order.DestinationChain,
+		TxHash:           txHash.String(),
+		ApproveTxHash:    approveTxHash,
+		InputToken:       order.InputToken,
+		OutputToken:      order.OutputToken,
+		InputAmount:      amountString(order.InputAmount),
+		OutputAmount:     amountString(order.OutputAmount),
+		SenderNonce:      senderNonce.String(),
+		FillDeadline:     order.FillDeadline,
+		Success:          true,
+	}
+	if orderID, err := hyperlane7683.StarknetOpenedOrderID(receipt.Events, hyperlaneAddrFelt); err != nil {
+		fmt.Printf("   ⚠️  Could not read the order ID: %v\n", err)
+	} else {
+		fmt.Printf("   🆔 Order ID: %s\n", orderID)
+		opened.OrderID = orderID
+	}
+	recordOpenedOrder(opened)
 
 	fmt.Printf("\n🎉 Order execution completed!\n")
 	fmt.Printf("📊 Order Summary:\n")
@@ -487,27 +589,38 @@ func buildStarknetOrderData(order *StarknetOrderConfig, originNetwork *StarknetN
 
 	// Convert addresses to felt
 	userAddrFelt, _ := utils.HexToFelt(userAddr)
-	inputTokenFelt, _ := utils.HexToFelt(originNetwork.dogCoinAddress)
-
-	// For Starknet→EVM orders, recipient is always Alice's EVM address
-	// Use conditional environment variable based on IS_DEVNET
-	evmUserAddr := envutil.GetAlicePublicKey()
-	if evmUserAddr == "" {
-		log.Fatalf("Alice public key not set")
-	}
+	inputTokenFelt, _ := utils.HexToFelt(starknetTokenAddress(order.InputToken, originNetwork.dogCoinAddress))
 
 	var recipientFelt *felt.Felt
+	if isStarknetNetwork(destChainName) {
+		// For Starknet→Starknet orders, recipient is Alice's Starknet address
+		starknetUserAddr := envutil.GetStarknetAliceAddress()
+		if starknetUserAddr == "" {
+			log.Fatalf("Alice Starknet address not set")
+		}
+		recipientFelt, _ = utils.HexToFelt(starknetUserAddr)
+	} else {
+		// For Starknet→EVM orders, recipient is Alice's EVM address
+		// Use conditional environment variable based on IS_DEVNET
+		evmUserAddr := envutil.GetAlicePublicKey()
+		if evmUserAddr == "" {
+			log.Fatalf("Alice public key not set")
+		}
 
-	// Pad EVM address to 32 bytes for Cairo ContractAddress
-	evmAddr := common.HexToAddress(evmUserAddr)
-	paddedAddr := common.LeftPadBytes(evmAddr.Bytes(), 32)
-	recipientFelt, _ = utils.HexToFelt(hex.EncodeToString(paddedAddr))
+		// Pad EVM address to 32 bytes for Cairo ContractAddress
+		evmAddr := common.HexToAddress(evmUserAddr)
+		paddedAddr := common.LeftPadBytes(evmAddr.Bytes(), 32)
+		recipientFelt, _ = utils.HexToFelt(hex.EncodeToString(paddedAddr))
+	}
 
 	// Output token should be from the destination network, not origin
 	var outputTokenFelt *felt.Felt
 	if isStarknetNetwork(destChainName) {
-		// If destination is Starknet, use Starknet's DogCoin
-		outputTokenFelt, _ = utils.HexToFelt(originNetwork.dogCoinAddress)
+		// If destination is Starknet, use Starknet's token
+		outputTokenFelt, _ = utils.HexToFelt(starknetTokenAddress(order.OutputToken, originNetwork.dogCoinAddress))
+	} else if isNativeToken(order.OutputToken) {
+		// Native ETH on the EVM destination is the zero address
+		outputTokenFelt = new(felt.Felt)
 	} else {
 		// If destination is EVM, get DogCoin address from destination network config (.env)
 		if _, exists := config.Networks[destChainName]; exists {
@@ -529,9 +642,12 @@ func buildStarknetOrderData(order *StarknetOrderConfig, originNetwork *StarknetN
 		}
 	}
 
-	// Destination settler must be the EVM Hyperlane address for the destination network
+	// Destination settler must be the Hyperlane address for the destination network. Starknet
+	// addresses don't fit the EVM config, and the only Starknet network is the origin.
 	destSettlerHex := ""
-	if staticAddr, err := config.GetHyperlaneAddress(destChainName); err == nil {
+	if isStarknetNetwork(destChainName) {
+		destSettlerHex = originNetwork.hyperlaneAddress
+	} else if staticAddr, err := config.GetHyperlaneAddress(destChainName); err == nil {
 		destSettlerHex = staticAddr.Hex()
 	} else if destNetwork, exists := config.Networks[destChainName]; exists {
 		destSettlerHex = destNetwork.HyperlaneAddress.Hex()
@@ -578,11 +694,7 @@ func getOrderDataTypeHashU256() (low, high *felt.Felt) {
 	if !ok {
 		panic("Failed to parse ORDER_DATA_TYPE_HASH")
 	}
-	// Split into low/high 128-bit felts: low = lower 128, high = upper 128
-	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
-	lowBI := new(big.Int).And(bi, mask)
-	highBI := new(big.Int).Rsh(bi, 128)
-	return utils.BigIntToFelt(lowBI), utils.BigIntToFelt(highBI)
+	return starknetutil.ConvertBigIntToU256Felts(bi)
 }
 
 func encodeStarknetOrderData(orderData *StarknetOrderData) []*felt.Felt {
@@ -0,0 +1,379 @@
+package top
+
+// Top command - a live terminal view of a running solver, refreshed from its admin API
+//
+// Usage:
+//
+//	solver top [--addr HOST:PORT] [--interval DURATION] [--limit N] [--once]
+//
+// Shows the block lag of every listener, the pending orders with their states, the recent fills
+// and settlements and the solver balances of the last balance check. The address defaults to
+// SOLVER_ADMIN_ADDR and SOLVER_ADMIN_TOKEN is sent when set, as for any admin API client. --once
+// prints a single snapshot without clearing the screen, e.g. for scripts or CI logs; with the
+// global --json flag it is the default and the snapshot is printed as JSON.
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/cmd/cliout"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/admin"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+)
+
+const (
+	defaultInterval = 2 * time.Second
+	defaultLimit    = 10
+	requestTimeout  = 5 * time.Second
+
+	// clearScreen moves the cursor home and clears the terminal before each refresh
+	clearScreen = "\033[H\033[2J"
+)
+
+// Order states shown in each section
+var (
+	pendingStatuses = []orders.Status{orders.StatusObserved, orders.StatusFailed}
+	recentStatuses  = []orders.Status{orders.StatusFilled, orders.StatusSettled}
+)
+
+// options are the parsed flags of the command
+type options struct {
+	addr     string
+	interval time.Duration
+	limit    int
+	once     bool
+}
+
+// snapshot is one refresh of the admin API; a section that failed to load keeps its error
+type snapshot struct {
+	At          time.Time
+	Listeners   []base.ListenerSnapshot
+	Pending     []orders.ExportRecord
+	Recent      []orders.ExportRecord
+	Balances    []hyperlane7683.TokenBalance
+	Errors      map[string]error
+	networkByID map[uint64]string
+}
+
+// snapshotView is the snapshot printed with --json, errors by section
+type snapshotView struct {
+	At        time.Time                    `json:"at"`
+	Listeners []base.ListenerSnapshot      `json:"listeners"`
+	Pending   []orders.ExportRecord        `json:"pending"`
+	Recent    []orders.ExportRecord        `json:"recent"`
+	Balances  []hyperlane7683.TokenBalance `json:"balances"`
+	Errors    map[string]string            `json:"errors,omitempty"`
+}
+
+// view returns the JSON form of the snapshot
+func (s snapshot) view() snapshotView {
+	view := snapshotView{At: s.At, Listeners: s.Listeners, Pending: s.Pending, Recent: s.Recent, Balances: s.Balances}
+	for section, err := range s.Errors {
+		if view.Errors == nil {
+			view.Errors = make(map[string]string)
+		}
+		view.Errors[section] = err.Error()
+	}
+	return view
+}
+
+// client reads the admin API
+type client struct {
+	base  string
+	token string
+	http  *http.Client
+}
+
+// RunTop refreshes the view until interrupted; args excludes the "top" command itself
+func RunTop(args []string) error {
+	opts, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+	c := newClient(opts.addr, admin.TokenFromEnv())
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if opts.once {
+		snap := c.fetch(ctx, opts.limit)
+		if cliout.JSON() {
+			return cliout.WriteJSON(snap.view())
+		}
+		return render(os.Stdout, snap)
+	}
+
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+	for {
+		snap := c.fetch(ctx, opts.limit)
+		if ctx.Err() != nil {
+			return nil
+		}
+		fmt.Print(clearScreen)
+		if err := render(os.Stdout, snap); err != nil {
+			return err
+		}
+		fmt.Printf("\nRefreshing every %s from %s, Ctrl+C to quit\n", opts.interval, c.base)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage: solver top [--addr HOST:PORT] [--interval DURATION] [--limit N] [--once]")
+	fmt.Println("  Live listener lag, pending orders, recent fills and balances from the admin API")
+	fmt.Println("  (defaults to SOLVER_ADMIN_ADDR, sends SOLVER_ADMIN_TOKEN when set)")
+}
+
+func parseFlags(args []string) (options, error) {
+	var opts options
+	fs := flag.NewFlagSet("top", flag.ContinueOnError)
+	fs.Usage = printUsage
+	fs.StringVar(&opts.addr, "addr", admin.AddrFromEnv(), "admin API address of the running solver")
+	fs.DurationVar(&opts.interval, "interval", defaultInterval, "refresh interval")
+	fs.IntVar(&opts.limit, "limit", defaultLimit, "orders shown per section")
+	fs.BoolVar(&opts.once, "once", cliout.JSON(), "print one snapshot and exit")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if opts.addr == "" {
+		return opts, fmt.Errorf("no admin API address, set --addr or SOLVER_ADMIN_ADDR")
+	}
+	if opts.interval <= 0 {
+		return opts, fmt.Errorf("--interval must be positive")
+	}
+	if opts.limit <= 0 {
+		return opts, fmt.Errorf("--limit must be positive")
+	}
+	return opts, nil
+}
+
+// newClient returns a client of the admin API at addr, with or without a scheme
+func newClient(addr, token string) *client {
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	return &client{base: strings.TrimRight(addr, "/"), token: token, http: &http.Client{Timeout: requestTimeout}}
+}
+
+// fetch reads every section of the view
+func (c *client) fetch(ctx context.Context, limit int) snapshot {
+	snap := snapshot{At: time.Now(), Errors: make(map[string]error)}
+	record := func(section string, err error) {
+		if err != nil {
+			snap.Errors[section] = err
+		}
+	}
+	record("listeners", c.get(ctx, "/listeners", nil, &snap.Listeners))
+	record("pending", c.get(ctx, "/orders", orderQuery(pendingStatuses, limit), &snap.Pending))
+	record("recent", c.get(ctx, "/orders", orderQuery(recentStatuses, limit), &snap.Recent))
+	record("balances", c.get(ctx, "/balances", nil, &snap.Balances))
+	return snap
+}
+
+// orderQuery selects the most recently updated orders in one of statuses
+func orderQuery(statuses []orders.Status, limit int) url.Values {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+	return url.Values{"status": {strings.Join(names, ",")}, "limit": {strconv.Itoa(limit)}}
+}
+
+// get decodes the JSON response of an admin API route into v
+func (c *client) get(ctx context.Context, path string, query url.Values, v any) error {
+	target := c.base + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&body) == nil && body.Error != "" {
+			return fmt.Errorf("GET %s: %s: %s", path, resp.Status, body.Error)
+		}
+		return fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("GET %s: invalid response: %w", path, err)
+	}
+	return nil
+}
+
+// render writes every section of the snapshot
+func render(w io.Writer, snap snapshot) error {
+	snap.networkByID = make(map[uint64]string)
+	for _, balance := range snap.Balances {
+		snap.networkByID[balance.ChainID] = balance.Network
+	}
+
+	fmt.Fprintf(w, "OIF Solver - %s\n", snap.At.Format(time.DateTime))
+	sections := []struct {
+		key, title string
+		write      func(io.Writer, snapshot) error
+	}{
+		{"listeners", "Listeners", writeListeners},
+		{"pending", "Pending orders", writePending},
+		{"recent", "Recent fills and settlements", writeRecent},
+		{"balances", "Balances", writeBalances},
+	}
+	for _, section := range sections {
+		fmt.Fprintf(w, "\n%s\n", section.title)
+		if err := snap.Errors[section.key]; err != nil {
+			fmt.Fprintf(w, "  ⚠️  %v\n", err)
+			continue
+		}
+		if err := section.write(w, snap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeListeners(w io.Writer, snap snapshot) error {
+	if len(snap.Listeners) == 0 {
+		_, err := fmt.Fprintln(w, "  no listeners running")
+		return err
+	}
+	listeners := append([]base.ListenerSnapshot(nil), snap.Listeners...)
+	sort.Slice(listeners, func(i, j int) bool { return listeners[i].ChainName < listeners[j].ChainName })
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  CHAIN\tTYPE\tHEAD\tPROCESSED\tLAG\tEVENTS\tLAST POLL\tLAST ERROR")
+	for _, l := range listeners {
+		lastError := "-"
+		if l.LastError != "" {
+			lastError = fmt.Sprintf("%s (%s)", l.LastError, ago(snap.At, l.LastErrorAt))
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%d\t%d\t%d\t%d\t%s\t%s\n",
+			l.ChainName, l.NetworkType, l.Head, l.LastProcessedBlock, l.Lag, l.EventsSeen, ago(snap.At, l.LastPollAt), lastError)
+	}
+	return tw.Flush()
+}
+
+func writePending(w io.Writer, snap snapshot) error {
+	if len(snap.Pending) == 0 {
+		_, err := fmt.Fprintln(w, "  no pending orders")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  ORDER\tROUTE\tSTATUS\tAGE\tUPDATED\tREASON")
+	for _, order := range snap.Pending {
+		reason := order.Reason
+		if reason == "" {
+			reason = "-"
+		}
+		createdAt := order.CreatedAt
+		updatedAt := order.UpdatedAt
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\t%s\n",
+			shortID(order.OrderID), snap.route(order), order.Status, ago(snap.At, &createdAt), ago(snap.At, &updatedAt), reason)
+	}
+	return tw.Flush()
+}
+
+func writeRecent(w io.Writer, snap snapshot) error {
+	if len(snap.Recent) == 0 {
+		_, err := fmt.Fprintln(w, "  no fills yet")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  ORDER\tROUTE\tSTATUS\tFILLED\tSETTLED\tTXS")
+	for _, order := range snap.Recent {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\t%d\n",
+			shortID(order.OrderID), snap.route(order), order.Status, ago(snap.At, order.FilledAt), ago(snap.At, order.SettledAt), len(order.Transactions))
+	}
+	return tw.Flush()
+}
+
+func writeBalances(w io.Writer, snap snapshot) error {
+	if len(snap.Balances) == 0 {
+		_, err := fmt.Fprintln(w, "  no balance check yet")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  NETWORK\tTOKEN\tBALANCE\tTHRESHOLD\t")
+	for _, balance := range snap.Balances {
+		value, note := balance.Balance, ""
+		switch {
+		case balance.Error != "":
+			value, note = "error", balance.Error
+		case balance.Low:
+			note = "🪫 low"
+		}
+		threshold := balance.Threshold
+		if threshold == "" {
+			threshold = "-"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", balance.Network, balance.Symbol, value, threshold, note)
+	}
+	return tw.Flush()
+}
+
+// route names the origin and destination of an order by network, by chain ID when unknown
+func (s snapshot) route(order orders.ExportRecord) string {
+	return s.networkName(order.OriginChainID) + " → " + s.networkName(order.DestinationChainID)
+}
+
+func (s snapshot) networkName(chainID uint64) string {
+	if name, ok := s.networkByID[chainID]; ok {
+		return name
+	}
+	return strconv.FormatUint(chainID, 10)
+}
+
+// shortID shortens an order ID to its first and last bytes
+func shortID(id string) string {
+	if len(id) <= 14 {
+		return id
+	}
+	return id[:8] + "…" + id[len(id)-4:]
+}
+
+// ago formats the time elapsed since t, "-" when unset
+func ago(now time.Time, t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	elapsed := now.Sub(*t)
+	if elapsed < time.Second {
+		return "now"
+	}
+	return elapsed.Truncate(time.Second).String() + " ago"
+}
@@ -0,0 +1,121 @@
+package top
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/admin"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFlags(t *testing.T) {
+	t.Setenv("SOLVER_ADMIN_ADDR", "127.0.0.1:8090")
+	opts, err := parseFlags(nil)
+	require.NoError(t, err)
+	assert.Equal(t, options{addr: "127.0.0.1:8090", interval: defaultInterval, limit: defaultLimit}, opts)
+
+	opts, err = parseFlags([]string{"--addr", "https://solver:8090", "--interval", "5s", "--limit", "3", "--once"})
+	require.NoError(t, err)
+	assert.Equal(t, options{addr: "https://solver:8090", interval: 5 * time.Second, limit: 3, once: true}, opts)
+
+	_, err = parseFlags([]string{"--interval", "0s"})
+	assert.ErrorContains(t, err, "--interval must be positive")
+	_, err = parseFlags([]string{"--limit", "0"})
+	assert.ErrorContains(t, err, "--limit must be positive")
+	_, err = parseFlags([]string{"now"})
+	assert.ErrorContains(t, err, "unexpected argument")
+
+	t.Setenv("SOLVER_ADMIN_ADDR", "")
+	_, err = parseFlags(nil)
+	assert.ErrorContains(t, err, "SOLVER_ADMIN_ADDR")
+}
+
+func TestNewClient(t *testing.T) {
+	assert.Equal(t, "http://127.0.0.1:8090", newClient("127.0.0.1:8090", "").base)
+	assert.Equal(t, "https://solver:8090", newClient("https://solver:8090/", "").base)
+}
+
+// TestFetch tests reading every section from an admin API with a token
+func TestFetch(t *testing.T) {
+	now := time.Now()
+	srv := admin.NewServer("127.0.0.1:0", "secret")
+	srv.HandleFunc("GET /listeners", func(w http.ResponseWriter, _ *http.Request) {
+		admin.WriteJSON(w, http.StatusOK, []base.ListenerSnapshot{{ChainName: "Base", NetworkType: "EVM", Head: 100, LastProcessedBlock: 90, Lag: 10}})
+	})
+	var queries []string
+	srv.HandleFunc("GET /orders", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		admin.WriteJSON(w, http.StatusOK, []orders.ExportRecord{{OrderID: "0x1", Status: orders.StatusObserved, CreatedAt: now}})
+	})
+	srv.HandleFunc("GET /balances", func(w http.ResponseWriter, _ *http.Request) {
+		admin.WriteError(w, http.StatusServiceUnavailable, assert.AnError)
+	})
+	server := httptest.NewServer(srv)
+	defer server.Close()
+
+	snap := newClient(server.URL, "secret").fetch(context.Background(), 5)
+	require.Len(t, snap.Listeners, 1)
+	assert.Equal(t, uint64(10), snap.Listeners[0].Lag)
+	assert.Len(t, snap.Pending, 1)
+	assert.Len(t, snap.Recent, 1)
+	assert.Equal(t, []string{"limit=5&status=OBSERVED%2CFAILED", "limit=5&status=FILLED%2CSETTLED"}, queries)
+	require.Len(t, snap.Errors, 1)
+	assert.ErrorContains(t, snap.Errors["balances"], "GET /balances: 503 Service Unavailable: "+assert.AnError.Error())
+
+	view := snap.view()
+	assert.Equal(t, map[string]string{"balances": snap.Errors["balances"].Error()}, view.Errors)
+	assert.Equal(t, snap.Pending, view.Pending)
+
+	snap = newClient(server.URL, "wrong").fetch(context.Background(), 5)
+	assert.Len(t, snap.Errors, 4)
+	assert.ErrorContains(t, snap.Errors["listeners"], "unauthorized")
+}
+
+func TestRender(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	polled := now.Add(-3 * time.Second)
+	filledAt := now.Add(-time.Minute)
+	snap := snapshot{
+		At: now,
+		Listeners: []base.ListenerSnapshot{
+			{ChainName: "Starknet", NetworkType: "Starknet", Head: 500, LastProcessedBlock: 480, Lag: 20, EventsSeen: 4, LastPollAt: &polled},
+			{ChainName: "Base", NetworkType: "EVM", Head: 100, LastProcessedBlock: 90, Lag: 10, LastError: "rpc down", LastErrorAt: &polled},
+		},
+		Pending: []orders.ExportRecord{
+			{OrderID: "0x0123456789abcdef0123", OriginChainID: 84532, DestinationChainID: 999, Status: orders.StatusFailed, Reason: "fill reverted", CreatedAt: now.Add(-2 * time.Minute), UpdatedAt: now},
+		},
+		Balances: []hyperlane7683.TokenBalance{
+			{ChainID: 84532, Network: "Base", Symbol: "DOG", Balance: "50", Threshold: "100", Low: true},
+			{ChainID: 23448594291968334, Network: "Starknet", Symbol: "STRK", Error: "rpc down"},
+		},
+		Errors: map[string]error{},
+	}
+	snap.Recent = []orders.ExportRecord{{OrderID: "0x2", OriginChainID: 23448594291968334, DestinationChainID: 84532, Status: orders.StatusFilled, FilledAt: &filledAt}}
+
+	var out bytes.Buffer
+	require.NoError(t, render(&out, snap))
+	text := out.String()
+	assert.Contains(t, text, "OIF Solver - 2026-01-01 12:00:00")
+	assert.Regexp(t, `Base\s+EVM\s+100\s+90\s+10\s+0\s+-\s+rpc down \(3s ago\)`, text)
+	assert.Regexp(t, `Starknet\s+Starknet\s+500\s+480\s+20\s+4\s+3s ago`, text)
+	assert.Less(t, bytes.Index(out.Bytes(), []byte("Base  ")), bytes.Index(out.Bytes(), []byte("Starknet  ")), "listeners by name")
+	assert.Regexp(t, `0x012345…0123\s+Base → 999\s+FAILED\s+2m0s ago\s+now\s+fill reverted`, text)
+	assert.Regexp(t, `0x2\s+Starknet → Base\s+FILLED\s+1m0s ago\s+-\s+0`, text)
+	assert.Regexp(t, `Base\s+DOG\s+50\s+100\s+🪫 low`, text)
+	assert.Regexp(t, `Starknet\s+STRK\s+error\s+-\s+rpc down`, text)
+
+	out.Reset()
+	require.NoError(t, render(&out, snapshot{At: now, Errors: map[string]error{"listeners": assert.AnError}}))
+	assert.Contains(t, out.String(), "⚠️  "+assert.AnError.Error())
+	assert.Contains(t, out.String(), "no pending orders")
+	assert.Contains(t, out.String(), "no fills yet")
+	assert.Contains(t, out.String(), "no balance check yet")
+}
@@ -0,0 +1,151 @@
+// Command plugin embeds the solver as a library (solver.New) and extends it without forking the repository:
+//   - a "heartbeat" solver that periodically logs the progress of every listener
+//   - an "OrderFile" listener that feeds orders from a JSON lines file (one types.ParsedArgs per
+//     line, path in ORDER_FILE) to the Hyperlane7683 solver, e.g. orders relayed from another system
+//
+// Run it from the solver directory with the usual .env: ORDER_FILE=orders.jsonl go run ./examples/plugin
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/cmd/solver"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const heartbeatInterval = 30 * time.Second
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	// Same setup as the solver command: config from .env and SOLVER_CONFIG_FILE
+	s, err := solver.New()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	sm := s.Manager()
+	if err := sm.RegisterSolver("heartbeat", startHeartbeat); err != nil {
+		return err
+	}
+	if path := os.Getenv("ORDER_FILE"); path != "" {
+		err := sm.RegisterListenerFactory("OrderFile", func(context.Context, *solvercore.SolverManager, string) (base.Listener, error) {
+			return newFileListener(path), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return s.Run(ctx)
+}
+
+// startHeartbeat logs the listeners' progress until shutdown
+func startHeartbeat(ctx context.Context, sm *solvercore.SolverManager) (base.ShutdownFunc, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, snapshot := range sm.ListenerSnapshots() {
+					fmt.Printf("💓 %s: block %d, %d events seen\n",
+						snapshot.ChainName, snapshot.LastProcessedBlock, snapshot.EventsSeen)
+				}
+			}
+		}
+	}()
+	return base.ShutdownFunc(cancel), nil
+}
+
+// fileListener hands the orders of a JSON lines file to the solver once; its "blocks" are lines
+type fileListener struct {
+	path   string
+	status *contracts.ListenerStatus
+	stop   context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+func newFileListener(path string) *fileListener {
+	return &fileListener{
+		path:   path,
+		status: contracts.NewListenerStatus("OrderFile", "file", 0),
+		done:   make(chan struct{}),
+	}
+}
+
+// Start reads the file in the background; shutdown waits for the order in progress
+func (l *fileListener) Start(ctx context.Context, handler base.EventHandler) (base.ShutdownFunc, error) {
+	file, err := os.Open(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open order file: %w", err)
+	}
+	ctx, l.stop = context.WithCancel(ctx)
+
+	go func() {
+		defer close(l.done)
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		line := uint64(0)
+		for scanner.Scan() && ctx.Err() == nil {
+			line++
+			var args types.ParsedArgs
+			if err := json.Unmarshal(scanner.Bytes(), &args); err != nil {
+				l.status.RecordError(fmt.Errorf("line %d: %w", line, err))
+				continue
+			}
+			l.status.RecordHead(line)
+			l.status.RecordEvents(1)
+			if _, err := handler(args, "OrderFile", line); err != nil {
+				l.status.RecordError(fmt.Errorf("order %s: %w", args.OrderID, err))
+			}
+			l.status.RecordProcessed(line)
+		}
+		if err := scanner.Err(); err != nil {
+			l.status.RecordError(err)
+		}
+	}()
+
+	return func() { _ = l.Stop() }, nil
+}
+
+func (l *fileListener) Stop() error {
+	l.once.Do(func() {
+		if l.stop != nil {
+			l.stop()
+			<-l.done
+		}
+	})
+	return nil
+}
+
+func (l *fileListener) GetLastProcessedBlock() uint64 {
+	return l.status.Snapshot().LastProcessedBlock
+}
+
+func (l *fileListener) Snapshot() base.ListenerSnapshot {
+	return l.status.Snapshot()
+}
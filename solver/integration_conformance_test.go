@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCrossVMConformanceIntegration opens an order on one VM, lets the solver fill it on the other and
+// reads the order back from both routers: the OrderData stored by open on the origin must be byte-equal
+// to the origin_data stored by fill on the destination, and hash to the order ID both routers key it by
+func TestCrossVMConformanceIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	if os.Getenv("SKIP_INTEGRATION_TESTS") == "true" {
+		t.Skip("Integration tests disabled via SKIP_INTEGRATION_TESTS")
+	}
+	if !envutil.IsDevnet() {
+		t.Skip("Conformance runs against the local devnet - set IS_DEVNET=true to enable")
+	}
+	if os.Getenv("EXECUTE_SOLVER_TESTS") != "true" {
+		t.Skip("Solver integration tests disabled - set EXECUTE_SOLVER_TESTS=true to enable")
+	}
+
+	solverPath := "./bin/solver"
+	if _, err := os.Stat(solverPath); os.IsNotExist(err) {
+		t.Log("Building solver binary for integration tests...")
+		buildCmd := exec.CommandContext(context.Background(), "make", "build")
+		output, err := buildCmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to build solver: %v\nOutput: %s", err, string(output))
+		}
+	}
+
+	t.Run("EVMToStarknet", func(t *testing.T) {
+		withCleanChains(t)
+		testOrderRoundTrip(t, solverPath, []string{"tools", "open-order", "evm", "default-evm-sn"})
+	})
+
+	t.Run("StarknetToEVM", func(t *testing.T) {
+		withCleanChains(t)
+		testOrderRoundTrip(t, solverPath, []string{"tools", "open-order", "starknet", "default"})
+	})
+}
+
+// testOrderRoundTrip opens an order with orderCommand, waits for the solver to fill it and compares what
+// the origin and destination routers stored for it
+func testOrderRoundTrip(t *testing.T, solverPath string, orderCommand []string) {
+	adminAddr := startConformanceSolver(t, solverPath)
+
+	resultFile := filepath.Join(t.TempDir(), "opened-orders.json")
+	cmd := exec.CommandContext(context.Background(), solverPath, append(orderCommand, "--result-file", resultFile)...)
+	cmd.Env = append(os.Environ(), "TEST_MODE=true")
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "order creation failed: %s", string(output))
+	orderInfo, err := readOpenedOrder(resultFile)
+	require.NoError(t, err)
+	require.NotEmpty(t, orderInfo.OrderID, "no order ID in the open-order result")
+	require.NoError(t, waitForOpenTransaction(t, orderInfo))
+	t.Logf("📜 Order %s opened on %s for %s", orderInfo.OrderID, orderInfo.OriginChain, orderInfo.DestinationChain)
+
+	orderID := common.HexToHash(orderInfo.OrderID)
+	orderData := readOpenOrderData(t, orderInfo.OriginChain, orderID)
+	assert.Equal(t, orderID, crypto.Keccak256Hash(orderData), "origin order ID is not the hash of the stored OrderData")
+
+	require.True(t, waitForAllOrdersProcessed(t, adminAddr, []*OrderInfo{orderInfo}), "order was not filled")
+
+	originData := readFilledOriginData(t, orderInfo.DestinationChain, orderID)
+	assert.Equal(t, common.Bytes2Hex(orderData), common.Bytes2Hex(originData),
+		"origin_data filled on %s differs from the OrderData opened on %s", orderInfo.DestinationChain, orderInfo.OriginChain)
+	assert.Equal(t, orderID, crypto.Keccak256Hash(originData), "destination order ID is not the hash of the filled origin_data")
+}
+
+// startConformanceSolver starts the solver with its admin API on a free port and stops it when the
+// test case ends; it returns the admin API address
+func startConformanceSolver(t *testing.T, solverPath string) string {
+	t.Helper()
+	adminAddr := freeLocalAddr(t)
+	solverCmd := exec.CommandContext(context.Background(), solverPath, "solver")
+	solverCmd.Env = append(os.Environ(), "TEST_MODE=true", "SOLVER_ADMIN_ADDR="+adminAddr)
+	logs := &bytes.Buffer{}
+	solverCmd.Stdout = logs
+	solverCmd.Stderr = logs
+	require.NoError(t, solverCmd.Start(), "failed to start solver")
+
+	t.Cleanup(func() {
+		_ = solverCmd.Process.Signal(syscall.SIGTERM)
+		done := make(chan struct{})
+		go func() {
+			_ = solverCmd.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			_ = solverCmd.Process.Kill()
+			<-done
+		}
+		if t.Failed() {
+			t.Logf("🤖 Solver output:\n%s", logs.String())
+		}
+	})
+	return adminAddr
+}
+
+// readOpenOrderData returns the OrderData the origin router stored for orderID when it was opened.
+// Both routers store abi.encode(orderDataType, orderData).
+func readOpenOrderData(t *testing.T, network string, orderID common.Hash) []byte {
+	t.Helper()
+	var stored []byte
+	if config.IsStarknetNetwork(network) {
+		resp := callStarknetRouter(t, network, "open_orders", orderID)
+		var rest []*felt.Felt
+		stored, rest = decodeStarknetBytes(t, resp)
+		require.Empty(t, rest, "open_orders returned more than its Bytes")
+	} else {
+		var err error
+		stored, err = evmRouter(t, network).OpenOrders(&bind.CallOpts{}, orderID)
+		require.NoError(t, err)
+	}
+	require.GreaterOrEqual(t, len(stored), 96, "no open order %s on %s", orderID.Hex(), network)
+
+	size := new(big.Int).SetBytes(stored[64:96])
+	require.True(t, size.IsInt64() && size.Int64() <= int64(len(stored)-96), "OrderData size %s exceeds the stored order", size)
+	return stored[96 : 96+size.Int64()]
+}
+
+// readFilledOriginData returns the origin_data the destination router stored for orderID when it was filled
+func readFilledOriginData(t *testing.T, network string, orderID common.Hash) []byte {
+	t.Helper()
+	if config.IsStarknetNetwork(network) {
+		// FilledOrder { origin_data: Bytes, filler_data: Bytes }
+		resp := callStarknetRouter(t, network, "filled_orders", orderID)
+		originData, rest := decodeStarknetBytes(t, resp)
+		_, rest = decodeStarknetBytes(t, rest)
+		require.Empty(t, rest, "filled_orders returned more than a FilledOrder")
+		require.NotEmpty(t, originData, "no filled order %s on %s", orderID.Hex(), network)
+		return originData
+	}
+	filled, err := evmRouter(t, network).FilledOrders(&bind.CallOpts{}, orderID)
+	require.NoError(t, err)
+	require.NotEmpty(t, filled.OriginData, "no filled order %s on %s", orderID.Hex(), network)
+	return filled.OriginData
+}
+
+// evmRouter returns the Hyperlane7683 router of an EVM network; its client is closed when the test ends
+func evmRouter(t *testing.T, network string) *contracts.Hyperlane7683 {
+	t.Helper()
+	networkConfig, err := config.GetNetworkConfig(network)
+	require.NoError(t, err)
+	client, err := ethclient.Dial(networkConfig.RPCURL)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	router, err := contracts.NewHyperlane7683(networkConfig.HyperlaneAddress, client)
+	require.NoError(t, err)
+	return router
+}
+
+// callStarknetRouter calls a view of the Starknet Hyperlane7683 router taking the order ID as a u256
+func callStarknetRouter(t *testing.T, network, function string, orderID common.Hash) []*felt.Felt {
+	t.Helper()
+	networkConfig, err := config.GetNetworkConfig(network)
+	require.NoError(t, err)
+	provider, err := rpc.NewProvider(networkConfig.RPCURL)
+	require.NoError(t, err)
+	router, err := utils.HexToFelt(os.Getenv("STARKNET_HYPERLANE_ADDRESS"))
+	require.NoError(t, err, "STARKNET_HYPERLANE_ADDRESS not set")
+
+	low, high, err := starknetutil.ConvertSolidityOrderIDForStarknet(orderID.Hex())
+	require.NoError(t, err)
+	resp, err := starknetutil.NewClient(provider, nil).Call(context.Background(), router, function, low, high)
+	require.NoError(t, err)
+	return resp
+}
+
+// decodeStarknetBytes decodes the alexandria Bytes at the start of felts (size, word count, u128 words)
+// and returns its bytes and the felts after it
+func decodeStarknetBytes(t *testing.T, felts []*felt.Felt) ([]byte, []*felt.Felt) {
+	t.Helper()
+	require.GreaterOrEqual(t, len(felts), 2, "Bytes without a size and word count")
+	size, words := felts[0].Uint64(), felts[1].Uint64()
+	require.LessOrEqual(t, words, uint64(len(felts)-2), "Bytes of %d words in %d felts", words, len(felts))
+	b, err := starknetutil.U128FeltsToBytes(felts[2:2+words], int(size))
+	require.NoError(t, err)
+	return b, felts[2+words:]
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"os/exec"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Reorg test constants
+const (
+	reorgNetwork       = "Ethereum" // Origin of the default EVM → EVM order
+	reorgConfirmations = 5          // Confirmations window of the listener that must skip the reorged Open
+	reorgPollInterval  = 250        // Listener poll interval in milliseconds
+	reorgWaitTimeout   = 30 * time.Second
+)
+
+// reorgOpenTopic is the topic of the Hyperlane7683 Open event
+var reorgOpenTopic = common.HexToHash("0x3448bbc2203c608599ad448eeb1007cea04b788ac631f9f558e8dd01a3c27b3d")
+
+// TestReorgIntegration opens an order on the Ethereum fork, then reorgs the block holding its Open
+// event out of the chain with anvil_reorg and checks what the EVM listener made of it
+func TestReorgIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	if os.Getenv("SKIP_INTEGRATION_TESTS") == "true" {
+		t.Skip("Integration tests disabled via SKIP_INTEGRATION_TESTS")
+	}
+	if !envutil.IsDevnet() {
+		t.Skip("Reorgs need the local Anvil forks - set IS_DEVNET=true to enable")
+	}
+	if os.Getenv("EXECUTE_ORDER_COMMANDS") != "true" {
+		t.Skip("Order command execution disabled - set EXECUTE_ORDER_COMMANDS=true to enable")
+	}
+
+	solverPath := "./bin/solver"
+	if _, err := os.Stat(solverPath); os.IsNotExist(err) {
+		t.Log("Building solver binary for integration tests...")
+		buildCmd := exec.CommandContext(context.Background(), "make", "build")
+		output, err := buildCmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to build solver: %v\nOutput: %s", err, string(output))
+		}
+	}
+
+	networkConfig, err := config.GetNetworkConfig(reorgNetwork)
+	require.NoError(t, err)
+	ctx := context.Background()
+	anvil, err := ethutil.DialAnvil(ctx, networkConfig.RPCURL)
+	require.NoError(t, err)
+	defer anvil.Close()
+	client, err := ethclient.Dial(networkConfig.RPCURL)
+	require.NoError(t, err)
+	defer client.Close()
+
+	chain := &reorgChain{anvil: anvil, client: client, router: networkConfig.HyperlaneAddress}
+
+	t.Run("OpenReorgedInsideConfirmationsWindow", func(t *testing.T) {
+		withCleanChains(t)
+		listener, seen := startReorgListener(t, networkConfig.RPCURL, networkConfig.HyperlaneAddress, reorgConfirmations)
+
+		orderID, openBlock := chain.openOrder(t, solverPath)
+		head := chain.reorgOut(t, openBlock)
+		require.Less(t, head-openBlock+1, uint64(reorgConfirmations), "the reorg must stay inside the confirmations window")
+
+		// Confirm the replacement blocks and let the listener catch up past them
+		require.NoError(t, anvil.Mine(ctx, reorgConfirmations+1))
+		require.Eventually(t, func() bool { return listener.GetLastProcessedBlock() >= head },
+			reorgWaitTimeout, reorgPollInterval*time.Millisecond, "listener did not process the replacement blocks")
+		assert.False(t, seen.has(orderID), "the listener processed an Open event that was reorged out")
+	})
+
+	t.Run("OpenReorgedAfterProcessing", func(t *testing.T) {
+		withCleanChains(t)
+		_, seen := startReorgListener(t, networkConfig.RPCURL, networkConfig.HyperlaneAddress, 0)
+
+		orderID, openBlock := chain.openOrder(t, solverPath)
+		require.Eventually(t, func() bool { return seen.has(orderID) },
+			reorgWaitTimeout, reorgPollInterval*time.Millisecond, "listener did not process the Open event")
+		chain.reorgOut(t, openBlock)
+
+		// The listener keeps its progress past reorged blocks; once it detects reorgs, assert here that it
+		// rolls back to the fork point, drops the order and re-processes the replacement blocks
+		t.Skip("the EVM listener does not roll back reorged blocks yet; only its confirmations window protects it")
+	})
+}
+
+// reorgChain opens orders on an Anvil fork and reorgs them out
+type reorgChain struct {
+	anvil  *ethutil.Anvil
+	client *ethclient.Client
+	router common.Address
+}
+
+// openOrder opens the default EVM → EVM order and returns its order ID and the block of its Open event
+func (c *reorgChain) openOrder(t *testing.T, solverPath string) (string, uint64) {
+	t.Helper()
+	ctx := context.Background()
+	before, err := c.client.BlockNumber(ctx)
+	require.NoError(t, err)
+
+	cmd := exec.CommandContext(ctx, solverPath, "tools", "open-order", "evm", "default-evm-evm")
+	cmd.Env = append(os.Environ(), "TEST_MODE=true")
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "order creation failed: %s", string(output))
+
+	logs := c.openLogs(t, before+1)
+	require.NotEmpty(t, logs, "no Open event after block %d", before)
+	open := logs[len(logs)-1]
+	t.Logf("📜 Order %s opened in block %d", open.Topics[1].Hex(), open.BlockNumber)
+	return open.Topics[1].Hex(), open.BlockNumber
+}
+
+// reorgOut replaces every block from block to the head with empty blocks and checks the Open events of
+// those blocks are gone; it returns the head, which the reorg keeps
+func (c *reorgChain) reorgOut(t *testing.T, block uint64) uint64 {
+	t.Helper()
+	ctx := context.Background()
+	head, err := c.client.BlockNumber(ctx)
+	require.NoError(t, err)
+	replaced, err := c.client.HeaderByNumber(ctx, new(big.Int).SetUint64(block))
+	require.NoError(t, err)
+
+	require.NoError(t, c.anvil.Reorg(ctx, head-block+1))
+	t.Logf("🔀 Reorged blocks %d-%d", block, head)
+
+	after, err := c.client.BlockNumber(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, head, after, "the reorg changed the chain height")
+	replacement, err := c.client.HeaderByNumber(ctx, new(big.Int).SetUint64(block))
+	require.NoError(t, err)
+	assert.NotEqual(t, replaced.Hash(), replacement.Hash(), "block %d was not replaced", block)
+	assert.Empty(t, c.openLogs(t, block), "Open events survived the reorg")
+	return head
+}
+
+// openLogs returns the router's Open events from block fromBlock to the head
+func (c *reorgChain) openLogs(t *testing.T, fromBlock uint64) []gethtypes.Log {
+	t.Helper()
+	logs, err := c.client.FilterLogs(context.Background(), ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		Addresses: []common.Address{c.router},
+		Topics:    [][]common.Hash{{reorgOpenTopic}},
+	})
+	require.NoError(t, err)
+	return logs
+}
+
+// seenOrders records the order IDs of the Open events a listener processed
+type seenOrders struct {
+	mu  sync.Mutex
+	ids map[string]bool
+}
+
+func (s *seenOrders) has(orderID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ids[orderID]
+}
+
+// startReorgListener starts an EVM listener on the router from the current block that records the Open
+// events it processes; it is stopped when the test case ends
+func startReorgListener(t *testing.T, rpcURL string, router common.Address, confirmations uint64) (base.Listener, *seenOrders) {
+	t.Helper()
+	listenerConfig := base.NewListenerConfig(router.Hex(), reorgNetwork, big.NewInt(0), reorgPollInterval, confirmations, 0)
+	listener, err := hyperlane7683.NewEVMListener(listenerConfig, rpcURL)
+	require.NoError(t, err)
+
+	seen := &seenOrders{ids: make(map[string]bool)}
+	shutdown, err := listener.Start(context.Background(), func(args types.ParsedArgs, _ string, _ uint64) (bool, error) {
+		seen.mu.Lock()
+		defer seen.mu.Unlock()
+		seen.ids[args.OrderID] = true
+		return false, nil
+	})
+	require.NoError(t, err)
+	t.Cleanup(shutdown)
+	return listener, seen
+}
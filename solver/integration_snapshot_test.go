@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/devnetutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotEVMNetworks are the Anvil forks reverted between test cases
+var snapshotEVMNetworks = []string{"Ethereum", "Optimism", "Arbitrum", "Base"}
+
+// withCleanChains starts the test case from a clean solver state and, on devnet, snapshots every
+// local chain and reverts it when the test case ends, so orders, fills and balances of one case
+// don't leak into the next. The Anvil forks use evm_snapshot/evm_revert; starknet-devnet aborts the
+// blocks mined since the snapshot (`solver devnet up` starts it with a full state archive).
+func withCleanChains(t *testing.T) {
+	t.Helper()
+	cleanSolverState(t)
+
+	if !envutil.IsDevnet() {
+		t.Log("ℹ️  Not on devnet, chain state is not reverted after this test case")
+		return
+	}
+
+	ctx := context.Background()
+	for _, networkName := range snapshotEVMNetworks {
+		networkConfig, err := config.GetNetworkConfig(networkName)
+		require.NoError(t, err)
+		anvil, err := ethutil.DialAnvil(ctx, networkConfig.RPCURL)
+		require.NoError(t, err)
+		id, err := anvil.Snapshot(ctx)
+		require.NoError(t, err, "failed to snapshot %s", networkName)
+
+		t.Cleanup(func() {
+			defer anvil.Close()
+			if err := anvil.Revert(context.Background(), id); err != nil {
+				t.Errorf("failed to revert %s to snapshot %s: %v", networkName, id, err)
+			}
+		})
+	}
+
+	starknetConfig, err := config.GetNetworkConfig("Starknet")
+	require.NoError(t, err)
+	devnet := devnetutil.NewClient(starknetConfig.RPCURL)
+	// Katana (make start-networks) has no devnet_* methods; its state is kept
+	if _, err := devnet.PredeployedAccounts(ctx); err != nil {
+		t.Logf("ℹ️  Starknet node is not starknet-devnet, its state is not reverted: %v", err)
+		t.Log("📸 EVM chain snapshots taken, reverted when the test case ends")
+		return
+	}
+	block, err := devnet.Snapshot(ctx)
+	require.NoError(t, err, "failed to snapshot Starknet")
+	t.Cleanup(func() {
+		if err := devnet.Revert(context.Background(), block); err != nil {
+			t.Errorf("failed to revert Starknet to block %d: %v", block, err)
+		}
+	})
+
+	t.Log("📸 Chain snapshots taken, reverted when the test case ends")
+}
@@ -6,19 +6,24 @@ import (
 	"fmt"
 	"log"
 	"math/big"
+	"net"
 	"os"
 	"os/exec"
-	"regexp"
-	"strconv"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"testing"
 	"time"
 
+	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderwait"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/receiptwait"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/admin"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/NethermindEth/starknet.go/rpc"
@@ -38,13 +43,10 @@ type IntegrationTestConfig struct {
 // Integration test constants
 const (
 	// Solver monitoring constants
-	SolverCheckInterval    = 1 * time.Second                // How often to check solver output
-	SolverMaxTimeout       = 3 * time.Minute                // Maximum time to wait for solver
-	OrderProcessingPattern = "✅ Order processing completed" // Pattern to look for in solver output
+	SolverMaxTimeout = 3 * time.Minute // Maximum time to wait for the orders to be filled
 
 	// Order creation constants
-	OrderCreationTimeout   = 60 * time.Second // Max time to wait for order creation
-	OrderConfirmationDelay = 2 * time.Second  // Delay between order creation and confirmation check
+	OrderCreationTimeout = 60 * time.Second // Max time to wait for order creation
 )
 
 // TestOrderLifecycleIntegration tests the complete order lifecycle
@@ -254,16 +256,24 @@ func TestOrderCreationCommandsIntegration(t *testing.T) {
 	}
 
 	t.Run("EVMOrderCreation", func(t *testing.T) {
+		withCleanChains(t)
 		testOrderCreationWithBalanceVerification(t, solverPath, []string{"tools", "open-order", "evm"})
 	})
 
 	t.Run("StarknetOrderCreation", func(t *testing.T) {
+		withCleanChains(t)
 		testOrderCreationWithBalanceVerification(t, solverPath, []string{"tools", "open-order", "starknet"})
 	})
 
 	t.Run("CrossChainOrderCreation", func(t *testing.T) {
+		withCleanChains(t)
 		testOrderCreationWithBalanceVerification(t, solverPath, []string{"tools", "open-order", "evm", "random-to-sn"})
 	})
+
+	t.Run("StarknetToStarknetOrderCreation", func(t *testing.T) {
+		withCleanChains(t)
+		testOrderCreationWithBalanceVerification(t, solverPath, []string{"tools", "open-order", "starknet", "random-to-sn"})
+	})
 }
 
 // testOrderCreationWithBalanceVerification tests order creation with comprehensive balance verification
@@ -285,20 +295,21 @@ func testOrderCreationWithBalanceVerification(t *testing.T, solverPath string, c
 
 	// Step 2: Execute order creation command
 	t.Log("🚀 Step 2: Executing order creation command...")
-	cmd := exec.CommandContext(context.Background(), solverPath, command...)
+	// open-order writes the opened orders to the result file
+	resultFile := filepath.Join(t.TempDir(), "opened-orders.json")
+	cmd := exec.CommandContext(context.Background(), solverPath, append(command, "--result-file", resultFile)...)
 	cmd.Dir = "."
 	// Preserve current environment including IS_DEVNET setting
 	cmd.Env = append(os.Environ(), "TEST_MODE=true")
 
 	output, _ := cmd.CombinedOutput()
-	outputStr := string(output)
 
 	// Log the command output
-	t.Logf("📝 Command output:\n%s", outputStr)
+	t.Logf("📝 Command output:\n%s", string(output))
 
-	// Step 3: Parse order creation output to determine origin/destination chains
-	t.Log("🔍 Step 3: Parsing order creation output...")
-	orderInfo, err := parseOrderCreationOutput(outputStr)
+	// Step 3: Read the opened order to determine origin/destination chains
+	t.Log("🔍 Step 3: Reading the open-order result file...")
+	orderInfo, err := readOpenedOrder(resultFile)
 	if err != nil {
 		t.Logf("⚠️  Could not parse order creation output: %v", err)
 		t.Logf("   This is expected if the command failed or networks aren't running")
@@ -358,31 +369,23 @@ type OrderInfo struct {
 	TransactionHash  string
 }
 
-// waitForEVMTransaction waits for an EVM transaction to be confirmed with enhanced timeout and error handling
-func waitForEVMTransaction(t *testing.T, client *ethclient.Client, txHash common.Hash, timeout time.Duration) error {
+// waitForEVMTransaction waits for an EVM transaction to be confirmed, polling at the pace of the network's blocks
+func waitForEVMTransaction(t *testing.T, client *ethclient.Client, network string, txHash common.Hash, timeout time.Duration) error {
 	ctx, cancel := context.WithTimeout(t.Context(), timeout)
 	defer cancel()
 
 	t.Logf("⏳ Waiting for EVM transaction confirmation: %s", txHash.Hex())
 
-	// Poll for transaction receipt directly
-	for {
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("timeout waiting for EVM transaction: %w", ctx.Err())
-		default:
-			receipt, err := client.TransactionReceipt(ctx, txHash)
-			if err == nil && receipt != nil {
-				t.Logf("✅ EVM transaction confirmed: %s (gas used: %d)", txHash.Hex(), receipt.GasUsed)
-				return nil
-			}
-			time.Sleep(OrderConfirmationDelay)
-		}
+	receipt, err := receiptwait.EVM(ctx, client, txHash, config.ExpectedBlockTime(network))
+	if err != nil {
+		return fmt.Errorf("timeout waiting for EVM transaction: %w", err)
 	}
+	t.Logf("✅ EVM transaction confirmed: %s (gas used: %d)", txHash.Hex(), receipt.GasUsed)
+	return nil
 }
 
-// waitForStarknetTransaction waits for a Starknet transaction to be confirmed with L2 status checking
-func waitForStarknetTransaction(t *testing.T, provider *rpc.Provider, txHash string, timeout time.Duration) error {
+// waitForStarknetTransaction waits for a Starknet transaction to be accepted on L2, polling at the pace of the network's blocks
+func waitForStarknetTransaction(t *testing.T, provider *rpc.Provider, network string, txHash string, timeout time.Duration) error {
 	ctx, cancel := context.WithTimeout(t.Context(), timeout)
 	defer cancel()
 
@@ -394,23 +397,16 @@ func waitForStarknetTransaction(t *testing.T, provider *rpc.Provider, txHash str
 		return fmt.Errorf("failed to convert hash to felt: %w", err)
 	}
 
-	// Poll for transaction status using TransactionStatus
-	for {
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("timeout waiting for Starknet transaction: %w", ctx.Err())
-		default:
-			status, err := provider.TransactionStatus(ctx, hashFelt)
-			if err == nil && status != nil {
-				// Check if transaction is accepted on L2
-				if status.FinalityStatus == "ACCEPTED_ON_L2" {
-					t.Logf("✅ Starknet transaction confirmed: %s", txHash)
-					return nil
-				}
-			}
-			time.Sleep(OrderConfirmationDelay)
-		}
+	// Poll for transaction status using TransactionStatus; unknown hashes and RPC errors are retried
+	err = receiptwait.Default().Wait(ctx, config.ExpectedBlockTime(network), func(ctx context.Context) (bool, error) {
+		status, err := provider.TransactionStatus(ctx, hashFelt)
+		return err == nil && status != nil && status.FinalityStatus == "ACCEPTED_ON_L2", nil
+	})
+	if err != nil {
+		return fmt.Errorf("timeout waiting for Starknet transaction: %w", err)
 	}
+	t.Logf("✅ Starknet transaction confirmed: %s", txHash)
+	return nil
 }
 
 // waitForOpenTransaction waits for the `open` transaction to be confirmed using the appropriate method
@@ -433,7 +429,7 @@ func waitForOpenTransaction(t *testing.T, orderInfo *OrderInfo) error {
 			return fmt.Errorf("failed to create Starknet provider: %w", err)
 		}
 
-		return waitForStarknetTransaction(t, provider, orderInfo.TransactionHash, OrderCreationTimeout)
+		return waitForStarknetTransaction(t, provider, orderInfo.OriginChain, orderInfo.TransactionHash, OrderCreationTimeout)
 	} else {
 		// Use EVM RPC
 		client, err := ethclient.Dial(networkConfig.RPCURL)
@@ -445,7 +441,7 @@ func waitForOpenTransaction(t *testing.T, orderInfo *OrderInfo) error {
 		// Convert hex hash to common.Hash
 		txHash := common.HexToHash(orderInfo.TransactionHash)
 
-		return waitForEVMTransaction(t, client, txHash, OrderCreationTimeout)
+		return waitForEVMTransaction(t, client, orderInfo.OriginChain, txHash, OrderCreationTimeout)
 	}
 }
 
@@ -577,117 +573,28 @@ func getDogCoinAddress(networkName string) (string, error) {
 	return address, nil
 }
 
-// parseOrderCreationOutput parses the order creation command output to extract order information
-func parseOrderCreationOutput(output string) (*OrderInfo, error) {
-	orderInfo := &OrderInfo{}
-
-	// Shared regex components to avoid repetition
-	const (
-		alphanumMatch = `[a-zA-Z0-9_]+` // Alphanumeric with underscore pattern
-		numberMatch   = `\d+`           // Number pattern
-		floatMatch    = `[\d.]+`        // Float number pattern
-	)
-
-	// Composed regex patterns
-	orderExecutionPattern := `Executing Order:\s*(\w+)\s*→\s*(\w+)`
-	orderIDOffPattern := `Order ID \(off\): (0x` + alphanumMatch + `)`
-	orderIDSimplePattern := `Order ID: (` + alphanumMatch + `)`
-	orderIDTxHashPattern := `Transaction sent:\s*(0x` + alphanumMatch + `)`
-	txHashPattern := `Transaction sent:\s*(0x` + alphanumMatch + `)`
-	amountInPattern := `AmountIn:\s*(` + numberMatch + `)`
-	amountOutPattern := `AmountOut:\s*(` + numberMatch + `)`
-	starknetInputAmountPattern := `Input Amount:\s*(` + numberMatch + `)`
-	starknetOutputAmountPattern := `Output Amount:\s*(` + numberMatch + `)`
-	balanceChangePattern := `User balance change:.*\(Δ:\s*(` + floatMatch + `)\s*tokens\)`
-
-	// Parse origin and destination chains from "Executing Order: X → Y" line
-	orderMatch := regexp.MustCompile(orderExecutionPattern).FindStringSubmatch(output)
-	if len(orderMatch) >= 3 {
-		orderInfo.OriginChain = orderMatch[1]
-		orderInfo.DestinationChain = orderMatch[2]
-	}
-
-	// Try to extract order ID from various formats
-	orderIDRegex := regexp.MustCompile(orderIDOffPattern)
-	if matches := orderIDRegex.FindStringSubmatch(output); len(matches) > 1 {
-		orderInfo.OrderID = matches[1]
-	} else {
-		// Try alternative format for Starknet orders
-		orderIDRegex = regexp.MustCompile(orderIDSimplePattern)
-		if matches := orderIDRegex.FindStringSubmatch(output); len(matches) > 1 {
-			orderInfo.OrderID = matches[1]
-		} else {
-			// Try to extract from transaction hash as fallback
-			orderIDRegex = regexp.MustCompile(orderIDTxHashPattern)
-			if matches := orderIDRegex.FindStringSubmatch(output); len(matches) > 1 {
-				orderInfo.OrderID = matches[1]
-			}
-		}
-	}
-
-	// Extract transaction hash from "Transaction sent: 0x..." line
-	txHashRegex := regexp.MustCompile(txHashPattern)
-	if matches := txHashRegex.FindStringSubmatch(output); len(matches) > 1 {
-		orderInfo.TransactionHash = matches[1]
-	}
-
-	// Try to extract amounts from ABI debug section (EVM orders)
-	inputAmountRegex := regexp.MustCompile(amountInPattern)
-	if matches := inputAmountRegex.FindStringSubmatch(output); len(matches) > 1 {
-		orderInfo.InputAmount = matches[1]
-	}
-
-	outputAmountRegex := regexp.MustCompile(amountOutPattern)
-	if matches := outputAmountRegex.FindStringSubmatch(output); len(matches) > 1 {
-		orderInfo.OutputAmount = matches[1]
-	}
-
-	// Try to extract amounts from Starknet Order Summary section
-	starknetInputAmountRegex := regexp.MustCompile(starknetInputAmountPattern)
-	if matches := starknetInputAmountRegex.FindStringSubmatch(output); len(matches) > 1 {
-		orderInfo.InputAmount = matches[1]
-	}
-
-	starknetOutputAmountRegex := regexp.MustCompile(starknetOutputAmountPattern)
-	if matches := starknetOutputAmountRegex.FindStringSubmatch(output); len(matches) > 1 {
-		orderInfo.OutputAmount = matches[1]
+// readOpenedOrder reads the result file written by open-order --result-file and returns the first order
+func readOpenedOrder(path string) (*OrderInfo, error) {
+	opened, err := openorder.ReadResultFile(path)
+	if err != nil {
+		return nil, err
 	}
-
-	// Fallback: Try to extract amounts from Starknet balance change line (legacy parsing)
-	if orderInfo.InputAmount == "" {
-		starknetBalanceRegex := regexp.MustCompile(balanceChangePattern)
-		if matches := starknetBalanceRegex.FindStringSubmatch(output); len(matches) > 1 {
-			// Convert float string to integer (assuming 18 decimals)
-			deltaFloat, err := strconv.ParseFloat(matches[1], 64)
-			if err == nil {
-				// Convert to wei (18 decimals) - use exact multiplication to avoid precision loss
-				// Split the float into integer and fractional parts for precise conversion
-				integerPart := int64(deltaFloat)
-				fractionalPart := deltaFloat - float64(integerPart)
-
-				// Convert integer part to wei
-				integerWei := big.NewInt(integerPart)
-				integerWei.Mul(integerWei, big.NewInt(1e18))
-
-				// Convert fractional part to wei (with precision)
-				fractionalWei := big.NewInt(int64(fractionalPart * 1e18))
-
-				// Add them together
-				totalWei := new(big.Int).Add(integerWei, fractionalWei)
-
-				orderInfo.InputAmount = totalWei.String()
-				// For legacy parsing, assume same amount (will be inaccurate but better than nothing)
-				orderInfo.OutputAmount = totalWei.String()
-			}
-		}
+	if len(opened) == 0 {
+		return nil, fmt.Errorf("no order opened")
 	}
 
-	// If we couldn't parse enough information, return an error
-	if orderInfo.OriginChain == "" || orderInfo.DestinationChain == "" {
-		return nil, fmt.Errorf("could not parse origin/destination chains from output")
+	order := opened[0]
+	if order.OriginChain == "" || order.DestinationChain == "" {
+		return nil, fmt.Errorf("order without origin/destination chains")
 	}
-
-	return orderInfo, nil
+	return &OrderInfo{
+		OriginChain:      order.OriginChain,
+		DestinationChain: order.DestinationChain,
+		OrderID:          order.OrderID,
+		InputAmount:      order.InputAmount,
+		OutputAmount:     order.OutputAmount,
+		TransactionHash:  order.TxHash,
+	}, nil
 }
 
 // verifyBalanceChanges verifies that only the origin chain balances changed as expected
@@ -788,16 +695,24 @@ func TestOrderCreationOnly(t *testing.T) {
 	}
 
 	t.Run("OrderCreation_EVM_to_EVM", func(t *testing.T) {
+		withCleanChains(t)
 		testOrderCreationOnly(t, solverPath, []string{"tools", "open-order", "evm"})
 	})
 
 	t.Run("OrderCreation_EVM_to_Starknet", func(t *testing.T) {
+		withCleanChains(t)
 		testOrderCreationOnly(t, solverPath, []string{"tools", "open-order", "evm", "random-to-sn"})
 	})
 
 	t.Run("OrderCreation_Starknet_to_EVM", func(t *testing.T) {
+		withCleanChains(t)
 		testOrderCreationOnly(t, solverPath, []string{"tools", "open-order", "starknet"})
 	})
+
+	t.Run("OrderCreation_Starknet_to_Starknet", func(t *testing.T) {
+		withCleanChains(t)
+		testOrderCreationOnly(t, solverPath, []string{"tools", "open-order", "starknet", "random-to-sn"})
+	})
 }
 
 // TestSolverIntegration tests the complete order lifecycle: Open → Fill → Settle
@@ -833,17 +748,11 @@ func TestSolverIntegration(t *testing.T) {
 		}
 	}
 
-	// Clean solver state ONCE at the start of all integration tests
-	// This prevents backfilling from historical orders while allowing sequential execution
-	t.Log("🧹 Cleaning solver state once at the start of all integration tests...")
-	cleanSolverStateOnce(t)
-
 	t.Run("CompleteOrderLifecycle_MultiOrder", func(t *testing.T) {
-		// Clean solver state before multi-order test
-		t.Log("🧹 Cleaning solver state before multi-order test...")
-		cleanSolverState(t)
+		// Start from clean chains and solver state, reverted when the test ends
+		withCleanChains(t)
 		// Test the solver's ability to handle multiple orders simultaneously
-		// This covers EVM→EVM, EVM→Starknet, and Starknet→EVM order types
+		// This covers EVM→EVM, EVM→Starknet, Starknet→EVM and Starknet→Starknet order types
 		testCompleteOrderLifecycleMultiOrder(t, solverPath)
 	})
 }
@@ -878,20 +787,21 @@ func testOrderCreationOnly(t *testing.T, solverPath string, orderCommand []strin
 
 	// Step 2: Execute order creation command
 	t.Log("🚀 Step 2: Executing order creation command...")
-	cmd := exec.CommandContext(context.Background(), solverPath, orderCommand...)
+	// open-order writes the opened orders to the result file
+	resultFile := filepath.Join(t.TempDir(), "opened-orders.json")
+	cmd := exec.CommandContext(context.Background(), solverPath, append(orderCommand, "--result-file", resultFile)...)
 	cmd.Dir = "."
 	// Preserve current environment including IS_DEVNET setting
 	cmd.Env = append(os.Environ(), "TEST_MODE=true")
 
 	output, _ := cmd.CombinedOutput()
-	outputStr := string(output)
 
 	// Log the command output
-	t.Logf("📝 Order creation output:\n%s", outputStr)
+	t.Logf("📝 Order creation output:\n%s", string(output))
 
-	// Step 3: Parse order creation output to determine origin/destination chains
-	t.Log("🔍 Step 3: Parsing order creation output...")
-	orderInfo, err := parseOrderCreationOutput(outputStr)
+	// Step 3: Read the opened order to determine origin/destination chains
+	t.Log("🔍 Step 3: Reading the open-order result file...")
+	orderInfo, err := readOpenedOrder(resultFile)
 	if err != nil {
 		t.Logf("⚠️  Could not parse order creation output: %v", err)
 		t.Logf("   This is expected if the command failed or networks aren't running")
@@ -932,44 +842,6 @@ func testOrderCreationOnly(t *testing.T, solverPath string, orderCommand []strin
 	t.Log("🎉 Order creation test completed successfully!")
 }
 
-// cleanSolverStateOnce cleans the solver state once at the start of all integration tests
-// This prevents backfilling between individual test cases
-func cleanSolverStateOnce(t *testing.T) {
-	// Remove only old/corrupted solver state files, but keep the main state file
-	stateFiles := []string{
-		"solver-state.json", // Old file in current directory
-		"solvercore/solvers/hyperlane7683/solver-state.json", // Old file in solver directory
-	}
-
-	for _, file := range stateFiles {
-		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
-			t.Logf("⚠️  Could not remove %s: %v", file, err)
-		}
-	}
-
-	// Create clean state directory if it doesn't exist
-	if err := os.MkdirAll("state/solver_state", 0755); err != nil {
-		t.Logf("⚠️  Could not create state directory: %v", err)
-	}
-
-	// Set all solver start blocks to -1 (one block before current) for integration tests
-	// This prevents the solver from processing historical orders while still detecting new orders
-	t.Setenv("ETHEREUM_SOLVER_START_BLOCK", "-1")
-	t.Setenv("OPTIMISM_SOLVER_START_BLOCK", "-1")
-	t.Setenv("ARBITRUM_SOLVER_START_BLOCK", "-1")
-	t.Setenv("BASE_SOLVER_START_BLOCK", "-1")
-	t.Setenv("STARKNET_SOLVER_START_BLOCK", "-1")
-
-	// Also set LOCAL_ versions for forking mode
-	t.Setenv("LOCAL_ETHEREUM_SOLVER_START_BLOCK", "-1")
-	t.Setenv("LOCAL_OPTIMISM_SOLVER_START_BLOCK", "-1")
-	t.Setenv("LOCAL_ARBITRUM_SOLVER_START_BLOCK", "-1")
-	t.Setenv("LOCAL_BASE_SOLVER_START_BLOCK", "-1")
-	t.Setenv("LOCAL_STARKNET_SOLVER_START_BLOCK", "-1")
-
-	t.Log("✅ Solver state cleaned once and start blocks set to -1 (one block before current)")
-}
-
 // cleanSolverState cleans the solver state to prevent test interference
 func cleanSolverState(t *testing.T) {
 	// Remove solver state files
@@ -1178,7 +1050,7 @@ func verifyOrderCreationBalanceChanges(t *testing.T, before, after *NetworkBalan
 // TestMain sets up the test environment
 // testCompleteOrderLifecycleMultiOrder tests the solver's ability to handle multiple orders simultaneously
 func testCompleteOrderLifecycleMultiOrder(t *testing.T, solverPath string) {
-	t.Log("🔄 Testing multi-order processing: EVM→EVM, EVM→Starknet, Starknet→EVM")
+	t.Log("🔄 Testing multi-order processing: EVM→EVM, EVM→Starknet, Starknet→EVM, Starknet→Starknet")
 
 	// Step 1: Get all network balances BEFORE any order creation
 	t.Log("📊 Step 1: Getting all network balances BEFORE order creation...")
@@ -1217,10 +1089,12 @@ func testCompleteOrderLifecycleMultiOrder(t *testing.T, solverPath string) {
 	// Step 2: Start solver as background process BEFORE opening any orders
 	t.Log("🤖 Step 2: Starting solver as background process...")
 
+	// The admin API lets the test wait on each order's status
+	adminAddr := freeLocalAddr(t)
 	solverCmd := exec.CommandContext(context.Background(), solverPath, "solver")
 	solverCmd.Dir = "."
 	// Preserve current environment including IS_DEVNET setting
-	solverCmd.Env = append(os.Environ(), "TEST_MODE=true")
+	solverCmd.Env = append(os.Environ(), "TEST_MODE=true", "SOLVER_ADMIN_ADDR="+adminAddr)
 
 	// Set up pipes to capture output
 	solverCmd.Stdout = &bytes.Buffer{}
@@ -1253,14 +1127,15 @@ func testCompleteOrderLifecycleMultiOrder(t *testing.T, solverPath string) {
 		}
 	}()
 
-	// Step 3: Create three orders simultaneously
-	t.Log("🚀 Step 3: Creating three orders simultaneously...")
+	// Step 3: Create four orders simultaneously
+	t.Log("🚀 Step 3: Creating four orders simultaneously...")
 
-	// Define the three order commands
+	// Define the four order commands
 	orderCommands := [][]string{
-		{"tools", "open-order", "evm"},                 // EVM→EVM
-		{"tools", "open-order", "evm", "random-to-sn"}, // EVM→Starknet
-		{"tools", "open-order", "starknet"},            // Starknet→EVM
+		{"tools", "open-order", "evm"},                      // EVM→EVM
+		{"tools", "open-order", "evm", "random-to-sn"},      // EVM→Starknet
+		{"tools", "open-order", "starknet"},                 // Starknet→EVM
+		{"tools", "open-order", "starknet", "random-to-sn"}, // Starknet→Starknet
 	}
 
 	// Execute all order creation commands
@@ -1268,7 +1143,8 @@ func testCompleteOrderLifecycleMultiOrder(t *testing.T, solverPath string) {
 	for i, orderCommand := range orderCommands {
 		t.Logf("📝 Creating order %d: %s", i+1, strings.Join(orderCommand, " "))
 
-		cmd := exec.CommandContext(context.Background(), solverPath, orderCommand...)
+		resultFile := filepath.Join(t.TempDir(), fmt.Sprintf("opened-orders-%d.json", i+1))
+		cmd := exec.CommandContext(context.Background(), solverPath, append(orderCommand, "--result-file", resultFile)...)
 		cmd.Dir = "."
 		cmd.Env = append(os.Environ(), "TEST_MODE=true")
 
@@ -1278,8 +1154,8 @@ func testCompleteOrderLifecycleMultiOrder(t *testing.T, solverPath string) {
 		// Log the command output
 		t.Logf("📝 Order %d creation output:\n%s", i+1, outputStr)
 
-		// Parse order creation output
-		orderInfo, err := parseOrderCreationOutput(outputStr)
+		// Read the opened order
+		orderInfo, err := readOpenedOrder(resultFile)
 		if err != nil {
 			t.Logf("⚠️  Could not parse order %d creation output: %v", i+1, err)
 			t.Logf("   This is expected if the command failed or networks aren't running")
@@ -1343,7 +1219,7 @@ func testCompleteOrderLifecycleMultiOrder(t *testing.T, solverPath string) {
 	}
 
 	// Wait for all orders to be processed or timeout
-	allOrdersProcessed := waitForAllOrdersProcessed(t, solverCmd, orderInfos)
+	allOrdersProcessed := waitForAllOrdersProcessed(t, adminAddr, orderInfos)
 
 	if allOrdersProcessed {
 		t.Log("✅ All orders processed successfully!")
@@ -1530,195 +1406,39 @@ func verifyMultiOrderBalanceChanges(t *testing.T, beforeOrder, finalAlice *Netwo
 	t.Log("🎉 Multi-order balance verification completed successfully!")
 }
 
-// waitForAllOrdersProcessed monitors solver output in real-time to detect when all orders are processed
-func waitForAllOrdersProcessed(t *testing.T, solverCmd *exec.Cmd, orderInfos []*OrderInfo) bool {
-	t.Logf("🔍 Monitoring solver output for %d orders...", len(orderInfos))
-
-	// Count how many orders have valid order IDs
-	validOrderCount := 0
-	for _, orderInfo := range orderInfos {
-		if orderInfo.OrderID != "" {
-			validOrderCount++
-		}
-	}
-
-	t.Logf("🔍 Valid order IDs found: %d/%d", validOrderCount, len(orderInfos))
-
-	// Check if we already have enough completion patterns
-	// This handles the case where order IDs don't match but we have the right number of completions
-	stdout := solverCmd.Stdout.(*bytes.Buffer).String()
-	stderr := solverCmd.Stderr.(*bytes.Buffer).String()
-	output := stdout + stderr
-	completionCount := strings.Count(output, OrderProcessingPattern)
-	if completionCount >= len(orderInfos) {
-		t.Logf("🎉 Found %d completion patterns (expected: %d) - all orders already processed!", completionCount, len(orderInfos))
-		return true
-	}
-
-	// If no valid order IDs, fall back to counting completion patterns
-	if validOrderCount == 0 {
-		t.Log("⚠️  No valid order IDs found, falling back to completion pattern counting")
-		return waitForCompletionPatterns(t, solverCmd, len(orderInfos))
-	}
+// waitForAllOrdersProcessed waits, through the solver's admin API, for every order to be filled
+// (SETTLED counts too)
+func waitForAllOrdersProcessed(t *testing.T, adminAddr string, orderInfos []*OrderInfo) bool {
+	t.Logf("🔍 Waiting for %d orders to be filled...", len(orderInfos))
 
-	// Create a map to track which orders have been processed
-	processedOrders := make(map[string]bool)
-	for _, orderInfo := range orderInfos {
-		if orderInfo.OrderID != "" {
-			processedOrders[orderInfo.OrderID] = false
+	src := orderwait.AdminSource(adminAddr, admin.TokenFromEnv())
+	deadline := time.Now().Add(SolverMaxTimeout)
+	allProcessed := true
+	for i, orderInfo := range orderInfos {
+		if orderInfo.OrderID == "" {
+			t.Logf("⚠️  Order %d has no order ID, cannot wait for it", i+1)
+			allProcessed = false
+			continue
 		}
-	}
-
-	// Set up monitoring with timeout
-	timeout := time.After(SolverMaxTimeout)
-	ticker := time.NewTicker(SolverCheckInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-timeout:
-			t.Logf("⏰ Timeout reached after %v, stopping solver monitoring", SolverMaxTimeout)
-			return false
-
-		case <-ticker.C:
-			// Read current solver output
-			stdout := solverCmd.Stdout.(*bytes.Buffer).String()
-			stderr := solverCmd.Stderr.(*bytes.Buffer).String()
-			output := stdout + stderr
-
-			// Debug: Log the current output length and any completion patterns found
-			if output != "" {
-				completionCount := strings.Count(output, OrderProcessingPattern)
-				if completionCount > 0 {
-					t.Logf("🔍 Found %d completion patterns in solver output", completionCount)
-
-					// Check if we have enough completion patterns to exit early
-					if completionCount >= len(orderInfos) {
-						t.Logf("🎉 Found %d completion patterns (expected: %d) - all orders processed! Exiting early.", completionCount, len(orderInfos))
-						return true
-					}
-
-					// Debug: Show what order IDs we're looking for
-					var lookingFor []string
-					for orderID := range processedOrders {
-						if !processedOrders[orderID] {
-							truncated := orderID
-							if len(orderID) > 8 {
-								truncated = orderID[:8]
-							}
-							lookingFor = append(lookingFor, truncated)
-						}
-					}
-					t.Logf("🔍 Looking for order IDs: %v", lookingFor)
-
-					// Debug: Show actual completion lines in the output
-					lines := strings.Split(output, "\n")
-					for _, line := range lines {
-						if strings.Contains(line, OrderProcessingPattern) {
-							t.Logf("🔍 Found completion line: %s", line)
-						}
-					}
-				}
-			}
-
-			// Check for order processing completion patterns
-			ordersProcessedThisCheck := 0
-			for orderID, isProcessed := range processedOrders {
-				if !isProcessed {
-					// Look for the completion pattern with this specific order ID
-					// The actual pattern is: "[ETH] → [STRK] ✅ Order processing completed (Order: 0x5bd09b...)"
-					// We need to match the truncated order ID (first 8 characters)
-					truncatedOrderID := orderID
-					if len(orderID) > 8 {
-						truncatedOrderID = orderID[:8]
-					}
-
-					// Check if this specific order has the completion pattern
-					// Look for: "✅ Order processing completed (Order: 0x5bd09b...)"
-					orderCompletionPattern := OrderProcessingPattern + " (Order: " + truncatedOrderID + "...)"
-					if strings.Contains(output, orderCompletionPattern) {
-						processedOrders[orderID] = true
-						ordersProcessedThisCheck++
-						t.Logf("✅ Order %s processed successfully", orderID)
-					}
-				}
-			}
-
-			// Check if all orders have been processed
-			allProcessed := true
-			for _, isProcessed := range processedOrders {
-				if !isProcessed {
-					allProcessed = false
-					break
-				}
-			}
-
-			if allProcessed {
-				t.Logf("🎉 All %d orders have been processed!", len(processedOrders))
-				return true
-			}
-
-			// Log progress if any orders were processed in this check
-			if ordersProcessedThisCheck > 0 {
-				processedCount := 0
-				for _, isProcessed := range processedOrders {
-					if isProcessed {
-						processedCount++
-					}
-				}
-				t.Logf("📊 Progress: %d/%d orders processed", processedCount, len(processedOrders))
-			}
+		order, err := orderwait.WaitForOrderState(context.Background(), src, orderInfo.OrderID, orders.StatusFilled, time.Until(deadline))
+		if err != nil {
+			t.Logf("❌ Order %d: %v", i+1, err)
+			allProcessed = false
+			continue
 		}
+		t.Logf("✅ Order %s %s (%d/%d)", orderInfo.OrderID, order.Status, i+1, len(orderInfos))
 	}
+	return allProcessed
 }
 
-// waitForCompletionPatterns is a fallback method that counts completion patterns instead of matching order IDs
-func waitForCompletionPatterns(t *testing.T, solverCmd *exec.Cmd, expectedOrderCount int) bool {
-	t.Logf("🔍 Monitoring solver output for %d completion patterns...", expectedOrderCount)
-
-	// Set up monitoring with timeout
-	timeout := time.After(SolverMaxTimeout)
-	ticker := time.NewTicker(SolverCheckInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-timeout:
-			t.Logf("⏰ Timeout reached after %v, stopping solver monitoring", SolverMaxTimeout)
-			return false
-
-		case <-ticker.C:
-			// Read current solver output
-			stdout := solverCmd.Stdout.(*bytes.Buffer).String()
-			stderr := solverCmd.Stderr.(*bytes.Buffer).String()
-			output := stdout + stderr
-
-			// Count completion patterns
-			completionCount := strings.Count(output, OrderProcessingPattern)
-			if completionCount > 0 {
-				t.Logf("🔍 Found %d completion patterns in solver output (expected: %d)", completionCount, expectedOrderCount)
-
-				// Debug: Show actual completion lines in the output
-				lines := strings.Split(output, "\n")
-				for _, line := range lines {
-					if strings.Contains(line, OrderProcessingPattern) {
-						t.Logf("🔍 Found completion line: %s", line)
-					}
-				}
-
-				// Check if we have enough completion patterns to exit early
-				if completionCount >= expectedOrderCount {
-					t.Logf("🎉 Found %d completion patterns (expected: %d) - all orders processed! Exiting early.", completionCount, expectedOrderCount)
-					return true
-				}
-			}
-
-			// Log progress
-			if completionCount > 0 {
-				t.Logf("📊 Progress: %d/%d completion patterns found", completionCount, expectedOrderCount)
-			}
-		}
-	}
+// freeLocalAddr returns a loopback address with a port free at the time of the call
+func freeLocalAddr(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return addr
 }
 
 func TestMain(m *testing.M) {
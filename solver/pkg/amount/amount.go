@@ -0,0 +1,173 @@
+// Package amount handles token amounts in base units together with the token's decimals.
+//
+// Amounts are parsed from and formatted to decimal strings (e.g. "12.5" DOG with 6
+// decimals is 12_500_000 base units) with integer math only, so no precision is lost the
+// way it is when converting through float64 or big.Float. Conversions to uint256, the
+// contracts' amount type, fail instead of wrapping around.
+package amount
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/holiman/uint256"
+)
+
+// MaxDecimals is the largest number of decimals accepted; ERC20 tokens use at most 18, some
+// Starknet tokens more
+const MaxDecimals = 77
+
+// ErrUint256Overflow is returned when a value does not fit the contracts' uint256 amounts
+var ErrUint256Overflow = errors.New("amount does not fit uint256")
+
+// Amount is a token amount in base units with the token's decimals
+type Amount struct {
+	Units    *big.Int
+	Decimals int
+}
+
+// New returns the amount of units base units of a token with the given decimals; nil units is zero
+func New(units *big.Int, decimals int) Amount {
+	if units == nil {
+		units = new(big.Int)
+	}
+	return Amount{Units: units, Decimals: decimals}
+}
+
+// Pow10 returns 10^decimals, the number of base units in one whole token
+func Pow10(decimals int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+}
+
+// Parse converts a non-negative decimal string of whole tokens (e.g. "12.5") into an amount.
+// It fails on anything but digits with an optional fraction, and on fractions with more digits
+// than decimals.
+func Parse(value string, decimals int) (Amount, error) {
+	if decimals < 0 || decimals > MaxDecimals {
+		return Amount{}, fmt.Errorf("invalid decimals %d", decimals)
+	}
+	text := strings.TrimSpace(value)
+	whole, fraction, hasFraction := strings.Cut(text, ".")
+	if (whole == "" && fraction == "") || (hasFraction && fraction == "") || !digitsOnly(whole) || !digitsOnly(fraction) {
+		return Amount{}, fmt.Errorf("invalid amount %q", value)
+	}
+	if len(fraction) > decimals {
+		trimmed := strings.TrimRight(fraction, "0")
+		if len(trimmed) > decimals {
+			return Amount{}, fmt.Errorf("amount %q has more than %d decimals", value, decimals)
+		}
+		fraction = trimmed
+	}
+
+	units, _ := new(big.Int).SetString(whole+fraction+strings.Repeat("0", decimals-len(fraction)), 10)
+	if units == nil {
+		units = new(big.Int) // whole and fraction both empty after trimming, e.g. ".0"
+	}
+	return Amount{Units: units, Decimals: decimals}, nil
+}
+
+func digitsOnly(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String renders the amount exactly in whole tokens, without trailing zeros (e.g. "12.5")
+func (a Amount) String() string {
+	whole, fraction := a.split()
+	if fraction.Sign() == 0 {
+		return a.sign() + whole.String()
+	}
+	return a.sign() + whole.String() + "." + strings.TrimRight(a.padFraction(fraction), "0")
+}
+
+// Fixed renders the amount in whole tokens with exactly places decimals, rounding half away from zero
+func (a Amount) Fixed(places int) string {
+	places = max(places, 0)
+	units := New(a.Units, a.Decimals).Units
+	abs := new(big.Int).Abs(units)
+	if shift := a.Decimals - places; shift > 0 {
+		scale := Pow10(shift)
+		abs.Add(abs, new(big.Int).Quo(scale, big.NewInt(2))).Quo(abs, scale)
+	} else {
+		abs.Mul(abs, Pow10(-shift))
+	}
+
+	rounded := Amount{Units: abs, Decimals: places}
+	whole, fraction := rounded.split()
+	text := whole.String()
+	if places > 0 {
+		text += "." + rounded.padFraction(fraction)
+	}
+	if units.Sign() < 0 && abs.Sign() != 0 {
+		text = "-" + text
+	}
+	return text
+}
+
+// Scale returns the amount with the given decimals, e.g. to add up amounts of tokens with
+// different decimals; scaling down truncates toward zero
+func (a Amount) Scale(decimals int) Amount {
+	units := New(a.Units, a.Decimals).Units
+	switch shift := decimals - a.Decimals; {
+	case shift > 0:
+		units = new(big.Int).Mul(units, Pow10(shift))
+	case shift < 0:
+		units = new(big.Int).Quo(units, Pow10(-shift))
+	default:
+		units = new(big.Int).Set(units)
+	}
+	return Amount{Units: units, Decimals: decimals}
+}
+
+// Uint256 returns the base units as a uint256
+func (a Amount) Uint256() (*uint256.Int, error) {
+	return ToUint256(a.Units)
+}
+
+// ToUint256 converts base units to a uint256, failing on negative values and overflow; nil is zero
+func ToUint256(units *big.Int) (*uint256.Int, error) {
+	if units == nil {
+		return uint256.NewInt(0), nil
+	}
+	if units.Sign() < 0 {
+		return nil, fmt.Errorf("negative amount %s", units)
+	}
+	u, overflow := uint256.FromBig(units)
+	if overflow {
+		return nil, fmt.Errorf("%w: %s", ErrUint256Overflow, units)
+	}
+	return u, nil
+}
+
+// FromUint256 converts a uint256 to base units; nil is zero
+func FromUint256(u *uint256.Int) *big.Int {
+	if u == nil {
+		return new(big.Int)
+	}
+	return u.ToBig()
+}
+
+// split returns the absolute whole tokens and remaining base units
+func (a Amount) split() (*big.Int, *big.Int) {
+	units := New(a.Units, a.Decimals).Units
+	return new(big.Int).QuoRem(new(big.Int).Abs(units), Pow10(a.Decimals), new(big.Int))
+}
+
+// padFraction renders the fractional base units with a.Decimals digits
+func (a Amount) padFraction(fraction *big.Int) string {
+	digits := fraction.String()
+	return strings.Repeat("0", a.Decimals-len(digits)) + digits
+}
+
+func (a Amount) sign() string {
+	if a.Units != nil && a.Units.Sign() < 0 {
+		return "-"
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+package amount
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	valid := []struct {
+		value    string
+		decimals int
+		units    string
+	}{
+		{"12.5", 6, "12500000"},
+		{"1000", 18, "1000000000000000000000"},
+		{"0.000001", 6, "1"},
+		{".5", 1, "5"},
+		{" 7 ", 0, "7"},
+		{"1.50", 1, "15"},
+		{"0.1", 18, "100000000000000000"}, // exact, no float rounding
+		{"123456789012345678.123456789012345678", 18, "123456789012345678123456789012345678"},
+	}
+	for _, tt := range valid {
+		t.Run(tt.value, func(t *testing.T) {
+			parsed, err := Parse(tt.value, tt.decimals)
+			require.NoError(t, err)
+			assert.Equal(t, tt.units, parsed.Units.String())
+			assert.Equal(t, tt.decimals, parsed.Decimals)
+		})
+	}
+
+	invalid := []struct {
+		value    string
+		decimals int
+		err      string
+	}{
+		{"", 6, "invalid amount"},
+		{"-1", 6, "invalid amount"},
+		{"1e18", 18, "invalid amount"},
+		{"1.", 6, "invalid amount"},
+		{"1.2.3", 6, "invalid amount"},
+		{"abc", 6, "invalid amount"},
+		{"0.001", 2, "more than 2 decimals"},
+		{"1", -1, "invalid decimals"},
+	}
+	for _, tt := range invalid {
+		t.Run("invalid "+tt.value, func(t *testing.T) {
+			_, err := Parse(tt.value, tt.decimals)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	large, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	tests := []struct {
+		name   string
+		amount Amount
+		exact  string
+		fixed2 string
+	}{
+		{"whole", New(big.NewInt(12_000_000), 6), "12", "12.00"},
+		{"fraction", New(big.NewInt(12_500_000), 6), "12.5", "12.50"},
+		{"small fraction", New(big.NewInt(1), 6), "0.000001", "0.00"},
+		{"rounds half up", New(big.NewInt(12_345), 3), "12.345", "12.35"},
+		{"negative", New(big.NewInt(-1_500_000), 6), "-1.5", "-1.50"},
+		{"negative rounding to zero", New(big.NewInt(-1), 6), "-0.000001", "0.00"},
+		{"zero decimals", New(big.NewInt(42), 0), "42", "42.00"},
+		{"nil", New(nil, 18), "0", "0.00"},
+		{"large", New(large, 18), "123456789012.34567890123456789", "123456789012.35"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.exact, tt.amount.String())
+			assert.Equal(t, tt.fixed2, tt.amount.Fixed(2))
+		})
+	}
+
+	assert.Equal(t, "2", New(big.NewInt(1_500_000), 6).Fixed(0))
+}
+
+func TestScale(t *testing.T) {
+	assert.Equal(t, "12500000000000000000", New(big.NewInt(12_500_000), 6).Scale(18).Units.String())
+	assert.Equal(t, "12", New(big.NewInt(12_999_999), 6).Scale(0).Units.String(), "scaling down truncates")
+
+	units := big.NewInt(5)
+	scaled := New(units, 2).Scale(2)
+	scaled.Units.SetInt64(7)
+	assert.Equal(t, int64(5), units.Int64(), "scaling copies the units")
+}
+
+func TestUint256(t *testing.T) {
+	u, err := ToUint256(big.NewInt(42))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), u.Uint64())
+
+	u, err = ToUint256(nil)
+	require.NoError(t, err)
+	assert.True(t, u.IsZero())
+
+	_, err = ToUint256(big.NewInt(-1))
+	assert.ErrorContains(t, err, "negative amount")
+
+	_, err = New(new(big.Int).Lsh(big.NewInt(1), 256), 18).Uint256()
+	assert.ErrorIs(t, err, ErrUint256Overflow)
+
+	assert.Equal(t, "7", FromUint256(uint256.NewInt(7)).String())
+	assert.Equal(t, "0", FromUint256(nil).String())
+}
@@ -0,0 +1,151 @@
+// Package artifacts loads the compiled Cairo contract classes the Starknet tools declare.
+//
+// scarb build writes the classes of a contract as <package>_<Contract>.contract_class.json (Sierra)
+// and <package>_<Contract>.compiled_contract_class.json (CASM). Explicit file paths win; otherwise
+// the artifacts directory is searched for the contract under any package name, and binaries built
+// with -tags embed_artifacts fall back to the classes embedded at build time when the directory has
+// none, so they can be shipped without the cairo build output.
+//
+// Settings:
+// - CAIRO_ARTIFACTS_DIR: directory of the classes (default ../cairo/target/dev, relative to solver/)
+package artifacts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/NethermindEth/starknet.go/contracts"
+)
+
+const (
+	// DefaultDir is where scarb build writes the classes, seen from the solver directory
+	DefaultDir = "../cairo/target/dev"
+	// DirEnv overrides DefaultDir
+	DirEnv = "CAIRO_ARTIFACTS_DIR"
+
+	sierraSuffix = ".contract_class.json"
+	casmSuffix   = ".compiled_contract_class.json"
+	// embeddedPrefix marks the sources of embedded classes, e.g. "embedded:oif_starknet_MockERC20.contract_class.json"
+	embeddedPrefix = "embedded:"
+)
+
+// ErrNotFound is returned when a contract's classes are neither in the artifacts directory nor embedded
+var ErrNotFound = errors.New("contract classes not found")
+
+// embedded holds the classes built into the binary, nil without the embed_artifacts tag
+var embedded fs.FS
+
+// Options overrides where the classes of a contract are read from
+type Options struct {
+	// Sierra and Casm are class files; each is found in Dir when empty
+	Sierra string
+	Casm   string
+	// Dir is the directory searched, default CAIRO_ARTIFACTS_DIR or DefaultDir
+	Dir string
+}
+
+// Classes are the loaded classes of a contract and where each was read from: a file path, or
+// "embedded:<name>" for classes built into the binary
+type Classes struct {
+	Sierra       *contracts.ContractClass
+	Casm         *contracts.CasmClass
+	SierraSource string
+	CasmSource   string
+}
+
+// Load reads the Sierra and CASM classes of contract, e.g. "Hyperlane7683"
+func Load(contract string, opts Options) (*Classes, error) {
+	dir := opts.Dir
+	if dir == "" {
+		dir = os.Getenv(DirEnv)
+	}
+	if dir == "" {
+		dir = DefaultDir
+	}
+
+	classes := &Classes{}
+	sierra, source, err := read(contract, opts.Sierra, dir, sierraSuffix)
+	if err != nil {
+		return nil, err
+	}
+	classes.SierraSource = source
+	if err := json.Unmarshal(sierra, &classes.Sierra); err != nil {
+		return nil, fmt.Errorf("failed to parse sierra class %s: %w", source, err)
+	}
+
+	casm, source, err := read(contract, opts.Casm, dir, casmSuffix)
+	if err != nil {
+		return nil, err
+	}
+	classes.CasmSource = source
+	if err := json.Unmarshal(casm, &classes.Casm); err != nil {
+		return nil, fmt.Errorf("failed to parse casm class %s: %w", source, err)
+	}
+	return classes, nil
+}
+
+// read returns the content and source of one class: path when set, else the class found in dir,
+// else the embedded one
+func read(contract, path, dir, suffix string) ([]byte, string, error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return data, path, nil
+	}
+
+	found, err := find(os.DirFS(dir), contract, suffix)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", dir, err)
+	}
+	if found != "" {
+		path = filepath.Join(dir, found)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return data, path, nil
+	}
+
+	if embedded != nil {
+		if found, err = find(embedded, contract, suffix); err != nil {
+			return nil, "", fmt.Errorf("embedded classes: %w", err)
+		}
+		if found != "" {
+			data, err := fs.ReadFile(embedded, found)
+			if err != nil {
+				return nil, "", err
+			}
+			return data, embeddedPrefix + found, nil
+		}
+	}
+	return nil, "", fmt.Errorf("%w: no %s%s in %s; build the contracts with scarb build, or set %s or the class file",
+		ErrNotFound, contract, suffix, dir, DirEnv)
+}
+
+// find returns the file of fsys holding contract's class with suffix, "" when there is none; a
+// missing directory holds none
+func find(fsys fs.FS, contract, suffix string) (string, error) {
+	var matches []string
+	for _, pattern := range []string{contract + suffix, "*_" + contract + suffix} {
+		found, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return "", err
+		}
+		matches = append(matches, found...)
+	}
+	if len(matches) > 1 {
+		sort.Strings(matches)
+		return "", fmt.Errorf("several %s classes of %s: %v", suffix, contract, matches)
+	}
+	if len(matches) == 0 {
+		return "", nil
+	}
+	return matches[0], nil
+}
@@ -0,0 +1,107 @@
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSierra = `{"sierra_program": [], "contract_class_version": "0.1.0", "abi": "[]"}`
+	testCasm   = `{"prime": "0x800000000000011000000000000000000000000000000000000000000000001", "compiler_version": "2.11.4",
+		"bytecode": [], "hints": [], "entry_points_by_type": {"CONSTRUCTOR": [], "EXTERNAL": [], "L1_HANDLER": []}}`
+)
+
+// writeClasses writes the Sierra and CASM classes of contract to dir under package name pkg
+func writeClasses(t *testing.T, dir, pkg, contract string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, pkg+"_"+contract+sierraSuffix), []byte(testSierra), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, pkg+"_"+contract+casmSuffix), []byte(testCasm), 0o600))
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("found in the artifacts directory under any package name", func(t *testing.T) {
+		dir := t.TempDir()
+		writeClasses(t, dir, "oif_starknet", "Hyperlane7683")
+		writeClasses(t, dir, "oif_starknet", "MockERC20")
+
+		classes, err := Load("MockERC20", Options{Dir: dir})
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "oif_starknet_MockERC20.contract_class.json"), classes.SierraSource)
+		assert.Equal(t, filepath.Join(dir, "oif_starknet_MockERC20.compiled_contract_class.json"), classes.CasmSource)
+		assert.Equal(t, "0.1.0", classes.Sierra.ContractClassVersion)
+		assert.Equal(t, "2.11.4", classes.Casm.CompilerVersion)
+	})
+
+	t.Run("directory from the environment", func(t *testing.T) {
+		dir := t.TempDir()
+		writeClasses(t, dir, "other_pkg", "Hyperlane7683")
+		t.Setenv(DirEnv, dir)
+		classes, err := Load("Hyperlane7683", Options{})
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "other_pkg_Hyperlane7683.contract_class.json"), classes.SierraSource)
+	})
+
+	t.Run("explicit files win", func(t *testing.T) {
+		dir := t.TempDir()
+		writeClasses(t, dir, "oif_starknet", "Hyperlane7683")
+		sierra := filepath.Join(t.TempDir(), "router.json")
+		require.NoError(t, os.WriteFile(sierra, []byte(testSierra), 0o600))
+
+		classes, err := Load("Hyperlane7683", Options{Sierra: sierra, Dir: dir})
+		require.NoError(t, err)
+		assert.Equal(t, sierra, classes.SierraSource)
+		assert.Equal(t, filepath.Join(dir, "oif_starknet_Hyperlane7683.compiled_contract_class.json"), classes.CasmSource)
+
+		_, err = Load("Hyperlane7683", Options{Casm: filepath.Join(dir, "missing.json"), Dir: dir})
+		assert.ErrorContains(t, err, "missing.json")
+	})
+
+	t.Run("missing and ambiguous classes", func(t *testing.T) {
+		dir := t.TempDir()
+		_, err := Load("Hyperlane7683", Options{Dir: filepath.Join(dir, "nowhere")})
+		assert.ErrorContains(t, err, "no Hyperlane7683.contract_class.json in")
+		assert.ErrorContains(t, err, "scarb build")
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		writeClasses(t, dir, "a", "Hyperlane7683")
+		writeClasses(t, dir, "b", "Hyperlane7683")
+		_, err = Load("Hyperlane7683", Options{Dir: dir})
+		assert.ErrorContains(t, err, "several")
+	})
+
+	t.Run("embedded classes when the directory has none", func(t *testing.T) {
+		previous := embedded
+		t.Cleanup(func() { embedded = previous })
+		embedded = fstest.MapFS{
+			"oif_starknet_MockERC20.contract_class.json":          {Data: []byte(testSierra)},
+			"oif_starknet_MockERC20.compiled_contract_class.json": {Data: []byte(testCasm)},
+		}
+
+		classes, err := Load("MockERC20", Options{Dir: filepath.Join(t.TempDir(), "nowhere")})
+		require.NoError(t, err)
+		assert.Equal(t, "embedded:oif_starknet_MockERC20.contract_class.json", classes.SierraSource)
+		assert.Equal(t, "embedded:oif_starknet_MockERC20.compiled_contract_class.json", classes.CasmSource)
+
+		dir := t.TempDir()
+		writeClasses(t, dir, "oif_starknet", "MockERC20")
+		classes, err = Load("MockERC20", Options{Dir: dir})
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "oif_starknet_MockERC20.contract_class.json"), classes.SierraSource, "the directory comes first")
+
+		_, err = Load("Hyperlane7683", Options{Dir: dir})
+		assert.ErrorContains(t, err, "no Hyperlane7683")
+	})
+
+	t.Run("invalid classes", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "p_Broken"+sierraSuffix), []byte("not json"), 0o600))
+		_, err := Load("Broken", Options{Dir: dir})
+		assert.ErrorContains(t, err, "failed to parse sierra class")
+	})
+}
@@ -0,0 +1,22 @@
+//go:build embed_artifacts
+
+package artifacts
+
+// Embeds the classes copied to embedded/ by make embed-artifacts, so the binary can declare without
+// the cairo build output next to it: make embed-artifacts && go build -tags embed_artifacts ./cmd/main.go
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed embedded/*.json
+var embeddedFiles embed.FS
+
+func init() {
+	sub, err := fs.Sub(embeddedFiles, "embedded")
+	if err != nil {
+		panic(err)
+	}
+	embedded = sub
+}
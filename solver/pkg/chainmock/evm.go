@@ -0,0 +1,402 @@
+package chainmock
+
+// Module: In-memory EVM backend for tests
+// - Implements the client methods used by the solver's EVM handler, listener and rules
+// - Contract calls are answered by handlers registered per address and 4-byte selector
+// - Sent transactions are mined immediately into a new block with a successful receipt
+// - Open events are served from logs added with AddLog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// Gas reported by EstimateGas and charged in receipts
+	defaultGasUsed = 100000
+	// Gas price reported by SuggestGasPrice (1 gwei)
+	defaultGasPrice = 1_000_000_000
+)
+
+// contractCode is returned by CodeAt for addresses with registered handlers
+var contractCode = []byte{0x60, 0x80, 0x60, 0x40}
+
+// EVMCallHandler answers a contract call; the returned bytes are the ABI-encoded result
+type EVMCallHandler func(msg ethereum.CallMsg) ([]byte, error)
+
+// EVMTxHook runs when a transaction to the hooked address is mined and returns the receipt status
+type EVMTxHook func(tx *gethtypes.Transaction) uint64
+
+// EVMBackend is an in-memory EVM chain
+type EVMBackend struct {
+	mu sync.Mutex
+
+	chainID     *big.Int
+	signer      gethtypes.Signer
+	blockNumber uint64
+	gasPrice    *big.Int
+
+	calls    map[callKey]EVMCallHandler
+	code     map[common.Address]bool
+	txHooks  map[common.Address]EVMTxHook
+	nonces   map[common.Address]uint64
+	balances map[common.Address]*big.Int
+	sent     []*gethtypes.Transaction
+	receipts map[common.Hash]*gethtypes.Receipt
+	logs     []gethtypes.Log
+	failures map[string]error
+	times    map[uint64]uint64 // block timestamps set with SetBlockTime
+}
+
+type callKey struct {
+	to       common.Address
+	selector [4]byte
+}
+
+// NewEVMBackend creates an empty chain with the given chain ID at block 0
+func NewEVMBackend(chainID uint64) *EVMBackend {
+	id := new(big.Int).SetUint64(chainID)
+	return &EVMBackend{
+		chainID:  id,
+		signer:   gethtypes.LatestSignerForChainID(id),
+		gasPrice: big.NewInt(defaultGasPrice),
+		calls:    make(map[callKey]EVMCallHandler),
+		code:     make(map[common.Address]bool),
+		txHooks:  make(map[common.Address]EVMTxHook),
+		nonces:   make(map[common.Address]uint64),
+		balances: make(map[common.Address]*big.Int),
+		receipts: make(map[common.Hash]*gethtypes.Receipt),
+		failures: make(map[string]error),
+		times:    make(map[uint64]uint64),
+	}
+}
+
+// Selector returns the 4-byte selector of a Solidity signature such as "balanceOf(address)"
+func Selector(signature string) [4]byte {
+	var selector [4]byte
+	copy(selector[:], crypto.Keccak256([]byte(signature))[:4])
+	return selector
+}
+
+// HandleCall answers calls to `to` whose data starts with selector
+func (b *EVMBackend) HandleCall(to common.Address, selector [4]byte, handler EVMCallHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls[callKey{to: to, selector: selector}] = handler
+	b.code[to] = true
+}
+
+// OnTransaction runs hook for every transaction sent to `to`
+func (b *EVMBackend) OnTransaction(to common.Address, hook EVMTxHook) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.txHooks[to] = hook
+	b.code[to] = true
+}
+
+// SetBlockNumber sets the current block number
+func (b *EVMBackend) SetBlockNumber(number uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.blockNumber = number
+}
+
+// SetBalance sets the native balance of account
+func (b *EVMBackend) SetBalance(account common.Address, balance *big.Int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.balances[account] = new(big.Int).Set(balance)
+}
+
+// AddLog adds a log returned by FilterLogs; the chain advances to the log's block if needed
+func (b *EVMBackend) AddLog(log gethtypes.Log) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.logs = append(b.logs, log)
+	if log.BlockNumber > b.blockNumber {
+		b.blockNumber = log.BlockNumber
+	}
+}
+
+// FailNext makes the next call of method return err. Supported methods: ChainID, BlockNumber,
+// BalanceAt, CallContract, EstimateGas, SendTransaction, TransactionReceipt and FilterLogs.
+func (b *EVMBackend) FailNext(method string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures[method] = err
+}
+
+// Sent returns the transactions sent so far, oldest first
+func (b *EVMBackend) Sent() []*gethtypes.Transaction {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*gethtypes.Transaction(nil), b.sent...)
+}
+
+// SentTo returns the transactions sent to `to`, oldest first
+func (b *EVMBackend) SentTo(to common.Address) []*gethtypes.Transaction {
+	var txs []*gethtypes.Transaction
+	for _, tx := range b.Sent() {
+		if tx.To() != nil && *tx.To() == to {
+			txs = append(txs, tx)
+		}
+	}
+	return txs
+}
+
+// takeFailure returns and clears the injected failure of method; callers hold b.mu
+func (b *EVMBackend) takeFailure(method string) error {
+	err := b.failures[method]
+	delete(b.failures, method)
+	return err
+}
+
+// ChainID implements ethclient.Client.ChainID
+func (b *EVMBackend) ChainID(_ context.Context) (*big.Int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.takeFailure("ChainID"); err != nil {
+		return nil, err
+	}
+	return new(big.Int).Set(b.chainID), nil
+}
+
+// BlockNumber implements ethclient.Client.BlockNumber
+func (b *EVMBackend) BlockNumber(_ context.Context) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.takeFailure("BlockNumber"); err != nil {
+		return 0, err
+	}
+	return b.blockNumber, nil
+}
+
+// CodeAt reports code at addresses with registered handlers or hooks
+func (b *EVMBackend) CodeAt(_ context.Context, contract common.Address, _ *big.Int) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.code[contract] {
+		return contractCode, nil
+	}
+	return nil, nil
+}
+
+// BalanceAt returns the native balance set with SetBalance, zero for other accounts
+func (b *EVMBackend) BalanceAt(_ context.Context, account common.Address, _ *big.Int) (*big.Int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.takeFailure("BalanceAt"); err != nil {
+		return nil, err
+	}
+	if balance, ok := b.balances[account]; ok {
+		return new(big.Int).Set(balance), nil
+	}
+	return new(big.Int), nil
+}
+
+// PendingCodeAt is CodeAt on the pending state
+func (b *EVMBackend) PendingCodeAt(ctx context.Context, contract common.Address) ([]byte, error) {
+	return b.CodeAt(ctx, contract, nil)
+}
+
+// CallContract dispatches the call to the handler of its address and selector.
+// Calls without a handler return empty data, like a call to an address without code.
+func (b *EVMBackend) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	b.mu.Lock()
+	if err := b.takeFailure("CallContract"); err != nil {
+		b.mu.Unlock()
+		return nil, err
+	}
+	var handler EVMCallHandler
+	if msg.To != nil && len(msg.Data) >= 4 {
+		key := callKey{to: *msg.To}
+		copy(key.selector[:], msg.Data[:4])
+		handler = b.calls[key]
+	}
+	b.mu.Unlock()
+
+	// Handlers run unlocked so they may use the backend themselves
+	if handler == nil {
+		return nil, nil
+	}
+	return handler(msg)
+}
+
+// SetBlockTime sets the timestamp (unix seconds) of a block's header; other blocks have time 0
+func (b *EVMBackend) SetBlockTime(number, timestamp uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.times[number] = timestamp
+}
+
+// HeaderByNumber returns a pre-London header, so bound contracts send legacy transactions
+func (b *EVMBackend) HeaderByNumber(_ context.Context, number *big.Int) (*gethtypes.Header, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// Difficulty is set like a node would, so the header survives a JSON round trip
+	header := &gethtypes.Header{Number: new(big.Int).SetUint64(b.blockNumber), Difficulty: new(big.Int)}
+	if number != nil {
+		header.Number = new(big.Int).Set(number)
+	}
+	header.Time = b.times[header.Number.Uint64()]
+	return header, nil
+}
+
+// PendingNonceAt returns the number of transactions sent from account
+func (b *EVMBackend) PendingNonceAt(_ context.Context, account common.Address) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.nonces[account], nil
+}
+
+// SuggestGasPrice returns a fixed gas price
+func (b *EVMBackend) SuggestGasPrice(_ context.Context) (*big.Int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return new(big.Int).Set(b.gasPrice), nil
+}
+
+// SuggestGasTipCap returns a fixed tip
+func (b *EVMBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return b.SuggestGasPrice(ctx)
+}
+
+// EstimateGas returns a fixed gas amount
+func (b *EVMBackend) EstimateGas(_ context.Context, _ ethereum.CallMsg) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.takeFailure("EstimateGas"); err != nil {
+		return 0, err
+	}
+	return defaultGasUsed, nil
+}
+
+// SendTransaction mines tx into a new block. The receipt status comes from the hook
+// registered for the recipient, successful when there is none.
+func (b *EVMBackend) SendTransaction(_ context.Context, tx *gethtypes.Transaction) error {
+	b.mu.Lock()
+	if err := b.takeFailure("SendTransaction"); err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	from, err := gethtypes.Sender(b.signer, tx)
+	if err != nil {
+		b.mu.Unlock()
+		return fmt.Errorf("invalid transaction signature: %w", err)
+	}
+	if tx.Nonce() != b.nonces[from] {
+		b.mu.Unlock()
+		return fmt.Errorf("nonce too low or too high: have %d, want %d", tx.Nonce(), b.nonces[from])
+	}
+	b.nonces[from]++
+	var hook EVMTxHook
+	if tx.To() != nil {
+		hook = b.txHooks[*tx.To()]
+	}
+	b.mu.Unlock()
+
+	status := gethtypes.ReceiptStatusSuccessful
+	if hook != nil {
+		status = hook(tx)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.blockNumber++
+	b.sent = append(b.sent, tx)
+	b.receipts[tx.Hash()] = &gethtypes.Receipt{
+		Type:              tx.Type(),
+		Status:            status,
+		TxHash:            tx.Hash(),
+		GasUsed:           defaultGasUsed,
+		EffectiveGasPrice: tx.GasPrice(),
+		BlockNumber:       new(big.Int).SetUint64(b.blockNumber),
+		Logs:              []*gethtypes.Log{},
+	}
+	return nil
+}
+
+// TransactionReceipt returns the receipt of a sent transaction, ethereum.NotFound otherwise
+func (b *EVMBackend) TransactionReceipt(_ context.Context, txHash common.Hash) (*gethtypes.Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.takeFailure("TransactionReceipt"); err != nil {
+		return nil, err
+	}
+	receipt, ok := b.receipts[txHash]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return receipt, nil
+}
+
+// FilterLogs returns the added logs matching the query's block range, addresses and topics
+func (b *EVMBackend) FilterLogs(_ context.Context, query ethereum.FilterQuery) ([]gethtypes.Log, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.takeFailure("FilterLogs"); err != nil {
+		return nil, err
+	}
+
+	var logs []gethtypes.Log
+	for _, log := range b.logs {
+		if matchLog(log, query) {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+// SubscribeFilterLogs is not supported; the solver polls with FilterLogs
+func (b *EVMBackend) SubscribeFilterLogs(_ context.Context, _ ethereum.FilterQuery, _ chan<- gethtypes.Log) (ethereum.Subscription, error) {
+	return nil, errors.New("chainmock: log subscriptions are not supported")
+}
+
+// matchLog applies the filter semantics of eth_getLogs
+func matchLog(log gethtypes.Log, query ethereum.FilterQuery) bool {
+	if query.FromBlock != nil && log.BlockNumber < query.FromBlock.Uint64() {
+		return false
+	}
+	if query.ToBlock != nil && log.BlockNumber > query.ToBlock.Uint64() {
+		return false
+	}
+	if len(query.Addresses) > 0 {
+		found := false
+		for _, address := range query.Addresses {
+			if log.Address == address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for i, alternatives := range query.Topics {
+		if len(alternatives) == 0 {
+			continue // Wildcard position
+		}
+		if i >= len(log.Topics) {
+			return false
+		}
+		found := false
+		for _, topic := range alternatives {
+			if log.Topics[i] == topic {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package chainmock
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEVMBackendCalls(t *testing.T) {
+	token := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	balanceOf := Selector("balanceOf(address)")
+
+	t.Run("registered handler answers", func(t *testing.T) {
+		backend := NewEVMBackend(1)
+		backend.HandleCall(token, balanceOf, func(msg ethereum.CallMsg) ([]byte, error) {
+			return common.LeftPadBytes(big.NewInt(42).Bytes(), 32), nil
+		})
+
+		out, err := backend.CallContract(context.Background(), ethereum.CallMsg{To: &token, Data: balanceOf[:]}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), new(big.Int).SetBytes(out).Int64())
+
+		code, err := backend.CodeAt(context.Background(), token, nil)
+		require.NoError(t, err)
+		assert.NotEmpty(t, code)
+	})
+
+	t.Run("unknown call returns empty result", func(t *testing.T) {
+		backend := NewEVMBackend(1)
+		out, err := backend.CallContract(context.Background(), ethereum.CallMsg{To: &token, Data: balanceOf[:]}, nil)
+		require.NoError(t, err)
+		assert.Empty(t, out)
+	})
+
+	t.Run("native balances", func(t *testing.T) {
+		backend := NewEVMBackend(1)
+		backend.SetBalance(token, big.NewInt(7))
+
+		balance, err := backend.BalanceAt(context.Background(), token, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), balance.Int64())
+		balance, err = backend.BalanceAt(context.Background(), common.Address{0x02}, nil)
+		require.NoError(t, err)
+		assert.Zero(t, balance.Sign())
+	})
+
+	t.Run("FailNext is one-shot", func(t *testing.T) {
+		backend := NewEVMBackend(1)
+		backend.FailNext("BlockNumber", errors.New("rpc down"))
+
+		_, err := backend.BlockNumber(context.Background())
+		assert.EqualError(t, err, "rpc down")
+		_, err = backend.BlockNumber(context.Background())
+		assert.NoError(t, err)
+	})
+
+	t.Run("block times", func(t *testing.T) {
+		backend := NewEVMBackend(1)
+		backend.SetBlockTime(5, 1_700_000_000)
+
+		header, err := backend.HeaderByNumber(context.Background(), big.NewInt(5))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1_700_000_000), header.Time)
+		header, err = backend.HeaderByNumber(context.Background(), big.NewInt(6))
+		require.NoError(t, err)
+		assert.Zero(t, header.Time)
+	})
+}
+
+func TestEVMBackendTransactions(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	to := common.HexToAddress("0x2000000000000000000000000000000000000002")
+
+	t.Run("bound transact is mined with a receipt", func(t *testing.T) {
+		backend := NewEVMBackend(31337)
+		opts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(31337))
+		require.NoError(t, err)
+		backend.OnTransaction(to, nil)
+
+		contract := bind.NewBoundContract(to, abi.ABI{}, backend, backend, backend)
+		tx, err := contract.RawTransact(opts, []byte{0x01})
+		require.NoError(t, err)
+
+		receipt, err := bind.WaitMined(context.Background(), backend, tx)
+		require.NoError(t, err)
+		assert.Equal(t, gethtypes.ReceiptStatusSuccessful, receipt.Status)
+		assert.Equal(t, uint64(1), receipt.BlockNumber.Uint64())
+		assert.Len(t, backend.SentTo(to), 1)
+
+		nonce, err := backend.PendingNonceAt(context.Background(), opts.From)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), nonce)
+	})
+
+	t.Run("hook sets receipt status", func(t *testing.T) {
+		backend := NewEVMBackend(1)
+		backend.OnTransaction(to, func(tx *gethtypes.Transaction) uint64 {
+			return gethtypes.ReceiptStatusFailed
+		})
+
+		tx := signedTx(t, key, 1, 0, to)
+		require.NoError(t, backend.SendTransaction(context.Background(), tx))
+
+		receipt, err := backend.TransactionReceipt(context.Background(), tx.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, gethtypes.ReceiptStatusFailed, receipt.Status)
+	})
+
+	t.Run("wrong nonce is rejected", func(t *testing.T) {
+		backend := NewEVMBackend(1)
+		err := backend.SendTransaction(context.Background(), signedTx(t, key, 1, 5, to))
+		assert.Error(t, err)
+		assert.Empty(t, backend.Sent())
+	})
+
+	t.Run("unknown receipt is not found", func(t *testing.T) {
+		backend := NewEVMBackend(1)
+		_, err := backend.TransactionReceipt(context.Background(), common.Hash{0x01})
+		assert.ErrorIs(t, err, ethereum.NotFound)
+	})
+}
+
+func TestEVMBackendFilterLogs(t *testing.T) {
+	settler := common.HexToAddress("0x3000000000000000000000000000000000000003")
+	other := common.HexToAddress("0x4000000000000000000000000000000000000004")
+	topic := common.HexToHash("0xaa")
+
+	backend := NewEVMBackend(1)
+	backend.AddLog(gethtypes.Log{Address: settler, Topics: []common.Hash{topic}, BlockNumber: 5})
+	backend.AddLog(gethtypes.Log{Address: other, Topics: []common.Hash{topic}, BlockNumber: 6})
+	backend.AddLog(gethtypes.Log{Address: settler, Topics: []common.Hash{common.HexToHash("0xbb")}, BlockNumber: 7})
+	backend.AddLog(gethtypes.Log{Address: settler, Topics: []common.Hash{topic}, BlockNumber: 20})
+
+	head, err := backend.BlockNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20), head)
+
+	logs, err := backend.FilterLogs(context.Background(), ethereum.FilterQuery{
+		FromBlock: big.NewInt(1),
+		ToBlock:   big.NewInt(10),
+		Addresses: []common.Address{settler},
+		Topics:    [][]common.Hash{{topic}},
+	})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, uint64(5), logs[0].BlockNumber)
+}
+
+func signedTx(t *testing.T, key *ecdsa.PrivateKey, chainID int64, nonce uint64, to common.Address) *gethtypes.Transaction {
+	t.Helper()
+	tx := gethtypes.NewTx(&gethtypes.LegacyTx{Nonce: nonce, To: &to, Gas: defaultGasUsed, GasPrice: big.NewInt(defaultGasPrice)})
+	signed, err := gethtypes.SignTx(tx, gethtypes.LatestSignerForChainID(big.NewInt(chainID)), key)
+	require.NoError(t, err)
+	return signed
+}
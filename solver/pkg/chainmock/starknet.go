@@ -0,0 +1,365 @@
+package chainmock
+
+// Module: In-memory Starknet backend for tests
+// - Implements the rpc.RPCProvider methods used by the solver's Starknet handler, listener and rules
+// - Contract calls are answered by handlers registered per address and entry point selector
+// - Invokes are included immediately with a successful receipt and a fixed fee
+// - Open events are served from events added with AddEvent
+// - Contract classes are served from classes set with SetClass
+//
+// Methods the solver does not use are left to the embedded nil rpc.RPCProvider and panic.
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+const (
+	// Chain ID reported by ChainID, used by accounts to hash transactions
+	defaultStarknetChainID = "SN_SEPOLIA"
+	// Actual fee of every included invoke (fri)
+	defaultStarknetFee = 1000
+)
+
+// contractClassHash is returned by ClassHashAt for contracts with registered handlers
+var contractClassHash = new(felt.Felt).SetUint64(0xc1a55)
+
+// StarknetCallHandler answers a contract call with the returned felts
+type StarknetCallHandler func(call rpc.FunctionCall) ([]*felt.Felt, error)
+
+// StarknetInvokeHook runs when an invoke is included and returns its execution status
+type StarknetInvokeHook func(txn *rpc.BroadcastInvokeTxnV3) rpc.TxnExecutionStatus
+
+// StarknetBackend is an in-memory Starknet chain
+type StarknetBackend struct {
+	rpc.RPCProvider // nil; only the methods below are implemented
+
+	mu sync.Mutex
+
+	chainID     string
+	blockNumber uint64
+
+	calls      map[starknetCallKey]StarknetCallHandler
+	classes    map[felt.Felt]rpc.ClassOutput
+	invokeHook StarknetInvokeHook
+	nonces     map[felt.Felt]uint64
+	invokes    []*rpc.BroadcastInvokeTxnV3
+	receipts   map[felt.Felt]*rpc.TransactionReceiptWithBlockInfo
+	events     []rpc.EmittedEvent
+	failures   map[string]error
+	times      map[uint64]uint64 // block timestamps set with SetBlockTime
+}
+
+type starknetCallKey struct {
+	contract felt.Felt
+	selector felt.Felt
+}
+
+// NewStarknetBackend creates an empty Starknet chain at block 0
+func NewStarknetBackend() *StarknetBackend {
+	return &StarknetBackend{
+		chainID:  defaultStarknetChainID,
+		calls:    make(map[starknetCallKey]StarknetCallHandler),
+		classes:  make(map[felt.Felt]rpc.ClassOutput),
+		nonces:   make(map[felt.Felt]uint64),
+		receipts: make(map[felt.Felt]*rpc.TransactionReceiptWithBlockInfo),
+		failures: make(map[string]error),
+		times:    make(map[uint64]uint64),
+	}
+}
+
+// HandleCall answers calls to the named entry point (e.g. "balanceOf") of contract
+func (b *StarknetBackend) HandleCall(contract *felt.Felt, entryPoint string, handler StarknetCallHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	key := starknetCallKey{contract: *contract, selector: *utils.GetSelectorFromNameFelt(entryPoint)}
+	b.calls[key] = handler
+}
+
+// SetClass sets the class of contract returned by ClassAt
+func (b *StarknetBackend) SetClass(contract *felt.Felt, class rpc.ClassOutput) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.classes[*contract] = class
+}
+
+// OnInvoke runs hook for every included invoke
+func (b *StarknetBackend) OnInvoke(hook StarknetInvokeHook) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.invokeHook = hook
+}
+
+// SetBlockNumber sets the current block number
+func (b *StarknetBackend) SetBlockNumber(number uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.blockNumber = number
+}
+
+// SetBlockTime sets the timestamp (unix seconds) of a block; other blocks have time 0
+func (b *StarknetBackend) SetBlockTime(number, timestamp uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.times[number] = timestamp
+}
+
+// AddEvent adds an event returned by Events; the chain advances to the event's block if needed
+func (b *StarknetBackend) AddEvent(event rpc.EmittedEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, event)
+	if event.BlockNumber > b.blockNumber {
+		b.blockNumber = event.BlockNumber
+	}
+}
+
+// FailNext makes the next call of method return err. Supported methods: ChainID, BlockNumber,
+// Call, ClassAt, Events, EstimateFee, AddInvokeTransaction and TransactionReceipt.
+func (b *StarknetBackend) FailNext(method string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures[method] = err
+}
+
+// Invokes returns the invoke transactions included so far, oldest first
+func (b *StarknetBackend) Invokes() []*rpc.BroadcastInvokeTxnV3 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*rpc.BroadcastInvokeTxnV3(nil), b.invokes...)
+}
+
+// takeFailure returns and clears the injected failure of method; callers hold b.mu
+func (b *StarknetBackend) takeFailure(method string) error {
+	err := b.failures[method]
+	delete(b.failures, method)
+	return err
+}
+
+// ChainID returns the Starknet chain ID string
+func (b *StarknetBackend) ChainID(_ context.Context) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.takeFailure("ChainID"); err != nil {
+		return "", err
+	}
+	return b.chainID, nil
+}
+
+// BlockNumber returns the current block number
+func (b *StarknetBackend) BlockNumber(_ context.Context) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.takeFailure("BlockNumber"); err != nil {
+		return 0, err
+	}
+	return b.blockNumber, nil
+}
+
+// BlockWithTxHashes returns the header of a block by number (the latest block otherwise), without transactions
+func (b *StarknetBackend) BlockWithTxHashes(_ context.Context, blockID rpc.BlockID) (interface{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	number := b.blockNumber
+	if blockID.Number != nil {
+		number = *blockID.Number
+	}
+	return &rpc.BlockTxHashes{BlockHeader: rpc.BlockHeader{Number: number, Timestamp: b.times[number]}}, nil
+}
+
+// Call dispatches the call to the handler of its contract and selector
+func (b *StarknetBackend) Call(_ context.Context, call rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+	b.mu.Lock()
+	if err := b.takeFailure("Call"); err != nil {
+		b.mu.Unlock()
+		return nil, err
+	}
+	var handler StarknetCallHandler
+	if call.ContractAddress != nil && call.EntryPointSelector != nil {
+		handler = b.calls[starknetCallKey{contract: *call.ContractAddress, selector: *call.EntryPointSelector}]
+	}
+	b.mu.Unlock()
+
+	// Handlers run unlocked so they may use the backend themselves
+	if handler == nil {
+		return nil, rpc.ErrContractNotFound
+	}
+	return handler(call)
+}
+
+// ClassHashAt reports a class for contracts with registered handlers
+func (b *StarknetBackend) ClassHashAt(_ context.Context, _ rpc.BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for key := range b.calls {
+		if key.contract.Equal(contractAddress) {
+			return new(felt.Felt).Set(contractClassHash), nil
+		}
+	}
+	return nil, rpc.ErrContractNotFound
+}
+
+// ClassAt returns the class set with SetClass for contractAddress
+func (b *StarknetBackend) ClassAt(_ context.Context, _ rpc.BlockID, contractAddress *felt.Felt) (rpc.ClassOutput, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.takeFailure("ClassAt"); err != nil {
+		return nil, err
+	}
+	class, ok := b.classes[*contractAddress]
+	if !ok {
+		return nil, rpc.ErrContractNotFound
+	}
+	return class, nil
+}
+
+// Nonce returns the number of invokes included from contractAddress
+func (b *StarknetBackend) Nonce(_ context.Context, _ rpc.BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return new(felt.Felt).SetUint64(b.nonces[*contractAddress]), nil
+}
+
+// EstimateFee returns a small fixed estimate per transaction
+func (b *StarknetBackend) EstimateFee(_ context.Context, requests []rpc.BroadcastTxn, _ []rpc.SimulationFlag, _ rpc.BlockID) ([]rpc.FeeEstimation, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.takeFailure("EstimateFee"); err != nil {
+		return nil, err
+	}
+
+	one := new(felt.Felt).SetUint64(1)
+	estimates := make([]rpc.FeeEstimation, len(requests))
+	for i := range requests {
+		estimates[i] = rpc.FeeEstimation{
+			FeeEstimationCommon: rpc.FeeEstimationCommon{
+				L1GasConsumed:     one,
+				L1GasPrice:        one,
+				L2GasConsumed:     new(felt.Felt).SetUint64(defaultStarknetFee),
+				L2GasPrice:        one,
+				L1DataGasConsumed: one,
+				L1DataGasPrice:    one,
+				OverallFee:        new(felt.Felt).SetUint64(defaultStarknetFee + 2),
+			},
+			Unit: rpc.FriUnit,
+		}
+	}
+	return estimates, nil
+}
+
+// AddInvokeTransaction includes txn in a new block. The nonce must match the sender's;
+// the execution status comes from the invoke hook, SUCCEEDED when there is none.
+func (b *StarknetBackend) AddInvokeTransaction(_ context.Context, txn *rpc.BroadcastInvokeTxnV3) (rpc.AddInvokeTransactionResponse, error) {
+	b.mu.Lock()
+	if err := b.takeFailure("AddInvokeTransaction"); err != nil {
+		b.mu.Unlock()
+		return rpc.AddInvokeTransactionResponse{}, err
+	}
+	expected := b.nonces[*txn.SenderAddress]
+	if txn.Nonce == nil || txn.Nonce.Uint64() != expected {
+		b.mu.Unlock()
+		return rpc.AddInvokeTransactionResponse{}, rpc.ErrInvalidTransactionNonce
+	}
+	b.nonces[*txn.SenderAddress]++
+	hook := b.invokeHook
+	b.mu.Unlock()
+
+	status := rpc.TxnExecutionStatusSUCCEEDED
+	if hook != nil {
+		status = hook(txn)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.blockNumber++
+	b.invokes = append(b.invokes, txn)
+	hash := new(felt.Felt).SetUint64(uint64(len(b.invokes)))
+	receipt := &rpc.TransactionReceiptWithBlockInfo{
+		TransactionReceipt: rpc.TransactionReceipt{
+			Hash:            hash,
+			Type:            rpc.TransactionTypeInvoke,
+			ActualFee:       rpc.FeePayment{Amount: new(felt.Felt).SetUint64(defaultStarknetFee), Unit: rpc.UnitFri},
+			FinalityStatus:  rpc.TxnFinalityStatusAcceptedOnL2,
+			ExecutionStatus: status,
+		},
+		BlockHash:   new(felt.Felt).SetUint64(b.blockNumber),
+		BlockNumber: uint(b.blockNumber),
+	}
+	if status == rpc.TxnExecutionStatusREVERTED {
+		receipt.RevertReason = "reverted by chainmock hook"
+	}
+	b.receipts[*hash] = receipt
+	return rpc.AddInvokeTransactionResponse{Hash: hash}, nil
+}
+
+// TransactionReceipt returns the receipt of an included invoke, rpc.ErrHashNotFound otherwise
+func (b *StarknetBackend) TransactionReceipt(_ context.Context, transactionHash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.takeFailure("TransactionReceipt"); err != nil {
+		return nil, err
+	}
+	receipt, ok := b.receipts[*transactionHash]
+	if !ok {
+		return nil, rpc.ErrHashNotFound
+	}
+	return receipt, nil
+}
+
+// Events returns the added events matching the filter's block range, address and keys.
+// All matches are returned in a single chunk.
+func (b *StarknetBackend) Events(_ context.Context, input rpc.EventsInput) (*rpc.EventChunk, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.takeFailure("Events"); err != nil {
+		return nil, err
+	}
+	if input.ChunkSize <= 0 {
+		return nil, fmt.Errorf("chainmock: chunk size must be positive")
+	}
+
+	chunk := &rpc.EventChunk{Events: []rpc.EmittedEvent{}}
+	for _, event := range b.events {
+		if matchEvent(event, input.EventFilter) {
+			chunk.Events = append(chunk.Events, event)
+		}
+	}
+	return chunk, nil
+}
+
+// matchEvent applies the filter semantics of starknet_getEvents
+func matchEvent(event rpc.EmittedEvent, filter rpc.EventFilter) bool {
+	if filter.FromBlock.Number != nil && event.BlockNumber < *filter.FromBlock.Number {
+		return false
+	}
+	if filter.ToBlock.Number != nil && event.BlockNumber > *filter.ToBlock.Number {
+		return false
+	}
+	if filter.Address != nil && (event.FromAddress == nil || !event.FromAddress.Equal(filter.Address)) {
+		return false
+	}
+	for i, alternatives := range filter.Keys {
+		if len(alternatives) == 0 {
+			continue // Wildcard position
+		}
+		if i >= len(event.Keys) {
+			return false
+		}
+		found := false
+		for _, key := range alternatives {
+			if event.Keys[i].Equal(key) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,163 @@
+package chainmock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStarknetBackendCalls(t *testing.T) {
+	token := new(felt.Felt).SetUint64(0x1234)
+
+	t.Run("registered handler answers", func(t *testing.T) {
+		backend := NewStarknetBackend()
+		backend.HandleCall(token, "balance_of", func(call rpc.FunctionCall) ([]*felt.Felt, error) {
+			return []*felt.Felt{new(felt.Felt).SetUint64(7), new(felt.Felt)}, nil
+		})
+
+		out, err := backend.Call(context.Background(), rpc.FunctionCall{
+			ContractAddress:    token,
+			EntryPointSelector: utils.GetSelectorFromNameFelt("balance_of"),
+		}, rpc.WithBlockTag(rpc.BlockTagLatest))
+		require.NoError(t, err)
+		require.Len(t, out, 2)
+		assert.Equal(t, uint64(7), out[0].Uint64())
+	})
+
+	t.Run("unknown contract is not found", func(t *testing.T) {
+		backend := NewStarknetBackend()
+		_, err := backend.Call(context.Background(), rpc.FunctionCall{
+			ContractAddress:    token,
+			EntryPointSelector: utils.GetSelectorFromNameFelt("balance_of"),
+		}, rpc.WithBlockTag(rpc.BlockTagLatest))
+		assert.ErrorIs(t, err, rpc.ErrContractNotFound)
+	})
+
+	t.Run("contracts with handlers have a class", func(t *testing.T) {
+		backend := NewStarknetBackend()
+		backend.HandleCall(token, "balance_of", func(rpc.FunctionCall) ([]*felt.Felt, error) { return nil, nil })
+
+		classHash, err := backend.ClassHashAt(context.Background(), rpc.WithBlockTag(rpc.BlockTagLatest), token)
+		require.NoError(t, err)
+		assert.False(t, classHash.IsZero())
+		_, err = backend.ClassHashAt(context.Background(), rpc.WithBlockTag(rpc.BlockTagLatest), new(felt.Felt).SetUint64(0x99))
+		assert.ErrorIs(t, err, rpc.ErrContractNotFound)
+	})
+
+	t.Run("chain id", func(t *testing.T) {
+		chainID, err := NewStarknetBackend().ChainID(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "SN_SEPOLIA", chainID)
+	})
+
+	t.Run("block times", func(t *testing.T) {
+		backend := NewStarknetBackend()
+		backend.SetBlockTime(5, 1_700_000_000)
+
+		block, err := backend.BlockWithTxHashes(context.Background(), rpc.WithBlockNumber(5))
+		require.NoError(t, err)
+		require.IsType(t, &rpc.BlockTxHashes{}, block)
+		assert.Equal(t, uint64(1_700_000_000), block.(*rpc.BlockTxHashes).Timestamp)
+	})
+}
+
+func TestStarknetBackendInvokes(t *testing.T) {
+	sender := new(felt.Felt).SetUint64(0xabc)
+
+	invoke := func(nonce uint64) *rpc.BroadcastInvokeTxnV3 {
+		return &rpc.BroadcastInvokeTxnV3{SenderAddress: sender, Nonce: new(felt.Felt).SetUint64(nonce)}
+	}
+
+	t.Run("invoke is included with a receipt", func(t *testing.T) {
+		backend := NewStarknetBackend()
+		resp, err := backend.AddInvokeTransaction(context.Background(), invoke(0))
+		require.NoError(t, err)
+
+		receipt, err := backend.TransactionReceipt(context.Background(), resp.Hash)
+		require.NoError(t, err)
+		assert.Equal(t, rpc.TxnExecutionStatusSUCCEEDED, receipt.ExecutionStatus)
+		assert.Equal(t, uint64(defaultStarknetFee), receipt.ActualFee.Amount.Uint64())
+		assert.Equal(t, uint(1), receipt.BlockNumber)
+
+		nonce, err := backend.Nonce(context.Background(), rpc.WithBlockTag(rpc.BlockTagLatest), sender)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), nonce.Uint64())
+		assert.Len(t, backend.Invokes(), 1)
+	})
+
+	t.Run("hook reverts the invoke", func(t *testing.T) {
+		backend := NewStarknetBackend()
+		backend.OnInvoke(func(txn *rpc.BroadcastInvokeTxnV3) rpc.TxnExecutionStatus {
+			return rpc.TxnExecutionStatusREVERTED
+		})
+
+		resp, err := backend.AddInvokeTransaction(context.Background(), invoke(0))
+		require.NoError(t, err)
+		receipt, err := backend.TransactionReceipt(context.Background(), resp.Hash)
+		require.NoError(t, err)
+		assert.Equal(t, rpc.TxnExecutionStatusREVERTED, receipt.ExecutionStatus)
+		assert.NotEmpty(t, receipt.RevertReason)
+	})
+
+	t.Run("wrong nonce is rejected", func(t *testing.T) {
+		backend := NewStarknetBackend()
+		_, err := backend.AddInvokeTransaction(context.Background(), invoke(3))
+		assert.ErrorIs(t, err, rpc.ErrInvalidTransactionNonce)
+		assert.Empty(t, backend.Invokes())
+	})
+
+	t.Run("unknown receipt is not found", func(t *testing.T) {
+		backend := NewStarknetBackend()
+		_, err := backend.TransactionReceipt(context.Background(), new(felt.Felt).SetUint64(99))
+		assert.ErrorIs(t, err, rpc.ErrHashNotFound)
+	})
+}
+
+func TestStarknetBackendEvents(t *testing.T) {
+	hub := new(felt.Felt).SetUint64(0x1)
+	other := new(felt.Felt).SetUint64(0x2)
+	openKey := utils.GetSelectorFromNameFelt("Open")
+
+	backend := NewStarknetBackend()
+	add := func(from *felt.Felt, key *felt.Felt, block uint64) {
+		backend.AddEvent(rpc.EmittedEvent{
+			Event: rpc.Event{
+				FromAddress:  from,
+				EventContent: rpc.EventContent{Keys: []*felt.Felt{key}},
+			},
+			BlockNumber: block,
+		})
+	}
+	add(hub, openKey, 5)
+	add(other, openKey, 6)
+	add(hub, utils.GetSelectorFromNameFelt("Filled"), 7)
+	add(hub, openKey, 20)
+
+	head, err := backend.BlockNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20), head)
+
+	from, to := uint64(1), uint64(10)
+	input := rpc.EventsInput{
+		EventFilter: rpc.EventFilter{
+			FromBlock: rpc.BlockID{Number: &from},
+			ToBlock:   rpc.BlockID{Number: &to},
+			Address:   hub,
+			Keys:      [][]*felt.Felt{{openKey}},
+		},
+		ResultPageRequest: rpc.ResultPageRequest{ChunkSize: 100},
+	}
+	chunk, err := backend.Events(context.Background(), input)
+	require.NoError(t, err)
+	require.Len(t, chunk.Events, 1)
+	assert.Equal(t, uint64(5), chunk.Events[0].BlockNumber)
+
+	input.ChunkSize = 0
+	_, err = backend.Events(context.Background(), input)
+	assert.Error(t, err)
+}
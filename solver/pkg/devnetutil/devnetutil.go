@@ -0,0 +1,212 @@
+package devnetutil
+
+// Module: starknet-devnet helpers
+// - Wraps the devnet_* JSON-RPC methods of starknet-devnet, so integration tests and tools
+//   can prepare a devnet without manual setup
+// - Mint tops up an account's fee token balance (WEI for ETH, FRI for STRK)
+// - PredeployedAccounts lists the accounts devnet funds at startup, with their keys
+// - SetTime and IncreaseTime move the block timestamp, for deadline tests
+// - Snapshot and Revert roll the chain back between test cases by aborting the blocks mined since
+//   the snapshot; this needs devnet started with --state-archive-capacity full
+//
+// These methods only exist on starknet-devnet; other Starknet nodes reject them.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+)
+
+// Unit is the fee token of a devnet balance
+type Unit string
+
+const (
+	// UnitWei is ETH in wei
+	UnitWei Unit = "WEI"
+	// UnitFri is STRK in fri
+	UnitFri Unit = "FRI"
+)
+
+// Client calls the devnet_* methods of a starknet-devnet node
+type Client struct {
+	URL  string
+	HTTP *http.Client
+}
+
+// NewClient creates a client for the devnet at url
+func NewClient(url string) *Client {
+	return &Client{URL: url, HTTP: http.DefaultClient}
+}
+
+// NewClientFromEnv creates a client for the Starknet RPC URL of the environment (LOCAL_STARKNET_RPC_URL on devnet)
+func NewClientFromEnv() *Client {
+	return NewClient(envutil.GetStarknetRPCURL())
+}
+
+// PredeployedAccount is an account devnet deploys and funds at startup
+type PredeployedAccount struct {
+	Address        string   `json:"address"`
+	PublicKey      string   `json:"public_key"`
+	PrivateKey     string   `json:"private_key"`
+	InitialBalance *big.Int `json:"-"`
+}
+
+// MintResult is the outcome of a devnet mint
+type MintResult struct {
+	NewBalance *big.Int
+	Unit       Unit
+	TxHash     string
+}
+
+// Mint adds amount of unit to the fee token balance of address
+func (c *Client) Mint(ctx context.Context, address string, amount *big.Int, unit Unit) (*MintResult, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, fmt.Errorf("mint amount must be positive")
+	}
+	params := map[string]interface{}{
+		"address": address,
+		"amount":  json.Number(amount.String()),
+		"unit":    unit,
+	}
+	var result struct {
+		NewBalance string `json:"new_balance"`
+		Unit       Unit   `json:"unit"`
+		TxHash     string `json:"tx_hash"`
+	}
+	if err := c.call(ctx, "devnet_mint", params, &result); err != nil {
+		return nil, err
+	}
+	balance, ok := new(big.Int).SetString(result.NewBalance, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid devnet_mint balance %q", result.NewBalance)
+	}
+	return &MintResult{NewBalance: balance, Unit: result.Unit, TxHash: result.TxHash}, nil
+}
+
+// PredeployedAccounts returns the accounts devnet deployed at startup
+func (c *Client) PredeployedAccounts(ctx context.Context) ([]PredeployedAccount, error) {
+	var result []struct {
+		PredeployedAccount
+		InitialBalance string `json:"initial_balance"`
+	}
+	if err := c.call(ctx, "devnet_getPredeployedAccounts", map[string]interface{}{}, &result); err != nil {
+		return nil, err
+	}
+	accounts := make([]PredeployedAccount, 0, len(result))
+	for _, entry := range result {
+		account := entry.PredeployedAccount
+		if entry.InitialBalance != "" {
+			balance, ok := new(big.Int).SetString(entry.InitialBalance, 0)
+			if !ok {
+				return nil, fmt.Errorf("invalid initial balance %q for %s", entry.InitialBalance, account.Address)
+			}
+			account.InitialBalance = balance
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+// SetTime sets the timestamp of the next block; with generateBlock a block is created right away
+func (c *Client) SetTime(ctx context.Context, t time.Time, generateBlock bool) error {
+	params := map[string]interface{}{"time": t.Unix(), "generate_block": generateBlock}
+	return c.call(ctx, "devnet_setTime", params, nil)
+}
+
+// IncreaseTime moves the devnet clock forward by d and creates a block
+func (c *Client) IncreaseTime(ctx context.Context, d time.Duration) error {
+	if d < time.Second {
+		return fmt.Errorf("time increase must be at least one second, got %s", d)
+	}
+	params := map[string]interface{}{"time": int64(d / time.Second)}
+	return c.call(ctx, "devnet_increaseTime", params, nil)
+}
+
+// BlockNumber returns the number of the latest block
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	var number uint64
+	if err := c.call(ctx, "starknet_blockNumber", []interface{}{}, &number); err != nil {
+		return 0, err
+	}
+	return number, nil
+}
+
+// AbortBlocks aborts the blocks from startingBlock up to the latest one and returns their hashes
+func (c *Client) AbortBlocks(ctx context.Context, startingBlock uint64) ([]string, error) {
+	params := map[string]interface{}{"starting_block_id": map[string]uint64{"block_number": startingBlock}}
+	var result struct {
+		Aborted []string `json:"aborted"`
+	}
+	if err := c.call(ctx, "devnet_abortBlocks", params, &result); err != nil {
+		return nil, err
+	}
+	return result.Aborted, nil
+}
+
+// Snapshot returns the latest block number, to be passed to Revert
+func (c *Client) Snapshot(ctx context.Context) (uint64, error) {
+	return c.BlockNumber(ctx)
+}
+
+// Revert aborts every block mined after the snapshot, restoring the chain state at that time
+func (c *Client) Revert(ctx context.Context, snapshot uint64) error {
+	latest, err := c.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if latest <= snapshot {
+		return nil
+	}
+	_, err = c.AbortBlocks(ctx, snapshot+1)
+	return err
+}
+
+// call performs a JSON-RPC call and decodes its result into result, when not nil
+func (c *Client) call(ctx context.Context, method string, params, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := c.HTTP
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var decoded struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return fmt.Errorf("invalid %s response (HTTP %d): %w", method, resp.StatusCode, err)
+	}
+	if decoded.Error != nil {
+		return fmt.Errorf("%s returned error %d: %s", method, decoded.Error.Code, decoded.Error.Message)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(decoded.Result, result); err != nil {
+		return fmt.Errorf("invalid %s result: %w", method, err)
+	}
+	return nil
+}
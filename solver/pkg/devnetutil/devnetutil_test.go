@@ -0,0 +1,179 @@
+package devnetutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rpcRequest is a decoded JSON-RPC request
+type rpcRequest struct {
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params"`
+}
+
+// newTestDevnet serves handler's result, or its error when the returned error is set
+func newTestDevnet(t *testing.T, handler func(req rpcRequest) (interface{}, error)) (*Client, *[]rpcRequest) {
+	t.Helper()
+	var requests []rpcRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var raw struct {
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+		req := rpcRequest{Method: raw.Method}
+		// Positional params (e.g. starknet_blockNumber's empty list) are left out of Params
+		if len(raw.Params) > 0 && raw.Params[0] == '{' {
+			decoder := json.NewDecoder(bytes.NewReader(raw.Params))
+			decoder.UseNumber()
+			require.NoError(t, decoder.Decode(&req.Params))
+		}
+		requests = append(requests, req)
+
+		result, err := handler(req)
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": 1}
+		if err != nil {
+			response["error"] = map[string]interface{}{"code": -32601, "message": err.Error()}
+		} else {
+			response["result"] = result
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(server.URL), &requests
+}
+
+// rpcError is an error message returned by the test devnet
+type rpcError string
+
+func (e rpcError) Error() string { return string(e) }
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	large, _ := new(big.Int).SetString("1000000000000000000000", 10)
+
+	t.Run("Mint sends the amount as a number and decodes the new balance", func(t *testing.T) {
+		client, requests := newTestDevnet(t, func(rpcRequest) (interface{}, error) {
+			return map[string]interface{}{"new_balance": "1000000000000000000042", "unit": "FRI", "tx_hash": "0xabc"}, nil
+		})
+
+		result, err := client.Mint(ctx, "0x123", large, UnitFri)
+		require.NoError(t, err)
+		assert.Equal(t, "1000000000000000000042", result.NewBalance.String())
+		assert.Equal(t, UnitFri, result.Unit)
+		assert.Equal(t, "0xabc", result.TxHash)
+
+		require.Len(t, *requests, 1)
+		req := (*requests)[0]
+		assert.Equal(t, "devnet_mint", req.Method)
+		assert.Equal(t, "0x123", req.Params["address"])
+		assert.Equal(t, json.Number("1000000000000000000000"), req.Params["amount"])
+		assert.Equal(t, "FRI", req.Params["unit"])
+	})
+
+	t.Run("Mint rejects non-positive amounts", func(t *testing.T) {
+		_, err := NewClient("http://unused").Mint(ctx, "0x123", big.NewInt(0), UnitWei)
+		assert.ErrorContains(t, err, "must be positive")
+	})
+
+	t.Run("PredeployedAccounts decodes keys and balances", func(t *testing.T) {
+		client, requests := newTestDevnet(t, func(rpcRequest) (interface{}, error) {
+			return []map[string]interface{}{{
+				"address":         "0x64b4",
+				"public_key":      "0x39d9",
+				"private_key":     "0x71d7",
+				"initial_balance": "1000000000000000000000",
+			}}, nil
+		})
+
+		accounts, err := client.PredeployedAccounts(ctx)
+		require.NoError(t, err)
+		require.Len(t, accounts, 1)
+		assert.Equal(t, "0x64b4", accounts[0].Address)
+		assert.Equal(t, "0x39d9", accounts[0].PublicKey)
+		assert.Equal(t, "0x71d7", accounts[0].PrivateKey)
+		assert.Equal(t, large, accounts[0].InitialBalance)
+		assert.Equal(t, "devnet_getPredeployedAccounts", (*requests)[0].Method)
+	})
+
+	t.Run("SetTime sends the unix time", func(t *testing.T) {
+		client, requests := newTestDevnet(t, func(rpcRequest) (interface{}, error) {
+			return map[string]interface{}{"block_timestamp": 1_700_000_000}, nil
+		})
+
+		require.NoError(t, client.SetTime(ctx, time.Unix(1_700_000_000, 0), true))
+		req := (*requests)[0]
+		assert.Equal(t, "devnet_setTime", req.Method)
+		assert.Equal(t, json.Number("1700000000"), req.Params["time"])
+		assert.Equal(t, true, req.Params["generate_block"])
+	})
+
+	t.Run("IncreaseTime sends whole seconds", func(t *testing.T) {
+		client, requests := newTestDevnet(t, func(rpcRequest) (interface{}, error) {
+			return map[string]interface{}{"timestamp_increased_by": 3600}, nil
+		})
+
+		require.NoError(t, client.IncreaseTime(ctx, time.Hour))
+		req := (*requests)[0]
+		assert.Equal(t, "devnet_increaseTime", req.Method)
+		assert.Equal(t, json.Number("3600"), req.Params["time"])
+
+		assert.ErrorContains(t, client.IncreaseTime(ctx, time.Millisecond), "at least one second")
+	})
+
+	t.Run("Revert aborts the blocks mined after the snapshot", func(t *testing.T) {
+		latest := uint64(10)
+		client, requests := newTestDevnet(t, func(req rpcRequest) (interface{}, error) {
+			switch req.Method {
+			case "starknet_blockNumber":
+				return latest, nil
+			case "devnet_abortBlocks":
+				return map[string]interface{}{"aborted": []string{"0xb2", "0xb1"}}, nil
+			}
+			return nil, rpcError("Method not found")
+		})
+
+		snapshot, err := client.Snapshot(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(10), snapshot)
+
+		latest = 12
+		require.NoError(t, client.Revert(ctx, snapshot))
+		abort := (*requests)[len(*requests)-1]
+		assert.Equal(t, "devnet_abortBlocks", abort.Method)
+		assert.Equal(t, map[string]interface{}{"block_number": json.Number("11")}, abort.Params["starting_block_id"])
+	})
+
+	t.Run("Revert without new blocks aborts nothing", func(t *testing.T) {
+		client, requests := newTestDevnet(t, func(rpcRequest) (interface{}, error) {
+			return 10, nil
+		})
+
+		require.NoError(t, client.Revert(ctx, 10))
+		require.Len(t, *requests, 1)
+		assert.Equal(t, "starknet_blockNumber", (*requests)[0].Method)
+	})
+
+	t.Run("RPC errors name the method", func(t *testing.T) {
+		client, _ := newTestDevnet(t, func(rpcRequest) (interface{}, error) {
+			return nil, rpcError("Method not found")
+		})
+
+		err := client.SetTime(ctx, time.Now(), false)
+		assert.ErrorContains(t, err, "devnet_setTime returned error -32601: Method not found")
+	})
+
+	t.Run("unreachable devnet", func(t *testing.T) {
+		_, err := NewClient("http://127.0.0.1:1").PredeployedAccounts(ctx)
+		assert.ErrorContains(t, err, "devnet_getPredeployedAccounts failed")
+	})
+}
@@ -91,6 +91,28 @@ func TestGetConditionalUint64(t *testing.T) {
 	})
 }
 
+func TestGetEnvBool(t *testing.T) {
+	tests := []struct {
+		name         string
+		value        string
+		defaultValue bool
+		expected     bool
+	}{
+		{"unset uses default", "", true, true},
+		{"false", "false", true, false},
+		{"zero", "0", true, false},
+		{"true", "true", false, true},
+		{"invalid uses default", "maybe", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_BOOL_VAR", tt.value)
+			assert.Equal(t, tt.expected, GetEnvBool("TEST_BOOL_VAR", tt.defaultValue))
+		})
+	}
+}
+
 func TestIsDevnet(t *testing.T) {
 	t.Run("Returns true when IS_DEVNET=true", func(t *testing.T) {
 		t.Setenv("IS_DEVNET", "true")
@@ -598,6 +620,29 @@ func TestGetEnvInt(t *testing.T) {
 	})
 }
 
+func TestGetEnvFloat64(t *testing.T) {
+	t.Run("Returns parsed float when valid", func(t *testing.T) {
+		t.Setenv("TEST_FLOAT", "1.25")
+
+		result := GetEnvFloat64("TEST_FLOAT", 2.5)
+		assert.InDelta(t, 1.25, result, 1e-9)
+	})
+
+	t.Run("Returns default when invalid", func(t *testing.T) {
+		t.Setenv("TEST_FLOAT", "invalid")
+
+		result := GetEnvFloat64("TEST_FLOAT", 2.5)
+		assert.InDelta(t, 2.5, result, 1e-9)
+	})
+
+	t.Run("Returns default when not set", func(t *testing.T) {
+		os.Unsetenv("TEST_FLOAT")
+
+		result := GetEnvFloat64("TEST_FLOAT", 2.5)
+		assert.InDelta(t, 2.5, result, 1e-9)
+	})
+}
+
 func TestParseUint64(t *testing.T) {
 	t.Run("Parses valid uint64", func(t *testing.T) {
 		result, err := parseUint64("12345")
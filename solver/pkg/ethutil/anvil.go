@@ -0,0 +1,151 @@
+package ethutil
+
+// Module: Anvil fork helpers
+// - Wraps the anvil_* and evm_* JSON-RPC methods of Anvil, so integration tests can control
+//   accounts, balances, blocks and time instead of sleeping until the fork catches up
+// - Impersonate lets a test send transactions from any address (e.g. a router owner) with
+//   SendImpersonatedTransaction, without its key
+// - Mine and SetNextBlockTimestamp make deadlines deterministic; Snapshot and Revert roll the
+//   chain back between test cases
+// - Reorg replaces the latest blocks with empty ones at the same height, dropping their transactions
+//   and events; RevertAndMine does the same from a snapshot on nodes without anvil_reorg
+//
+// These methods only exist on Anvil (and partly Hardhat); other nodes reject them.
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Anvil calls the fork management methods of an Anvil node
+type Anvil struct {
+	client *rpc.Client
+}
+
+// NewAnvil creates the helper on an RPC client; the caller keeps ownership of client
+func NewAnvil(client *rpc.Client) *Anvil {
+	return &Anvil{client: client}
+}
+
+// DialAnvil connects to the Anvil node at rpcURL; Close releases the connection
+func DialAnvil(ctx context.Context, rpcURL string) (*Anvil, error) {
+	client, err := rpc.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", rpcURL, err)
+	}
+	return NewAnvil(client), nil
+}
+
+// Close closes the RPC connection
+func (a *Anvil) Close() {
+	a.client.Close()
+}
+
+// Impersonate lets transactions be sent from addr without its key
+func (a *Anvil) Impersonate(ctx context.Context, addr common.Address) error {
+	return a.call(ctx, nil, "anvil_impersonateAccount", addr)
+}
+
+// StopImpersonating reverts Impersonate
+func (a *Anvil) StopImpersonating(ctx context.Context, addr common.Address) error {
+	return a.call(ctx, nil, "anvil_stopImpersonatingAccount", addr)
+}
+
+// SetBalance sets the native balance of addr to wei
+func (a *Anvil) SetBalance(ctx context.Context, addr common.Address, wei *big.Int) error {
+	return a.call(ctx, nil, "anvil_setBalance", addr, (*hexutil.Big)(wei))
+}
+
+// Mine mines the given number of blocks right away, including pending transactions
+func (a *Anvil) Mine(ctx context.Context, blocks uint64) error {
+	for range blocks {
+		if err := a.call(ctx, nil, "evm_mine"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetNextBlockTimestamp sets the timestamp of the next mined block; it must be after the latest block's
+func (a *Anvil) SetNextBlockTimestamp(ctx context.Context, t time.Time) error {
+	return a.call(ctx, nil, "evm_setNextBlockTimestamp", hexutil.Uint64(t.Unix()))
+}
+
+// MineAt mines one block with timestamp t
+func (a *Anvil) MineAt(ctx context.Context, t time.Time) error {
+	if err := a.SetNextBlockTimestamp(ctx, t); err != nil {
+		return err
+	}
+	return a.Mine(ctx, 1)
+}
+
+// Snapshot saves the chain state and returns its ID for Revert
+func (a *Anvil) Snapshot(ctx context.Context) (string, error) {
+	var id string
+	if err := a.call(ctx, &id, "evm_snapshot"); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Revert restores the chain state saved by Snapshot; a snapshot can only be reverted to once
+func (a *Anvil) Revert(ctx context.Context, id string) error {
+	var reverted bool
+	if err := a.call(ctx, &reverted, "evm_revert", id); err != nil {
+		return err
+	}
+	if !reverted {
+		return fmt.Errorf("snapshot %s not found", id)
+	}
+	return nil
+}
+
+// Reorg replaces the latest depth blocks with as many empty blocks, so the chain keeps its height but
+// the transactions of those blocks are dropped and their hashes change
+func (a *Anvil) Reorg(ctx context.Context, depth uint64) error {
+	if depth == 0 {
+		return fmt.Errorf("reorg depth must be positive")
+	}
+	// Anvil reads the depth as a plain number and the (transaction, block offset) pairs to re-mine
+	return a.call(ctx, nil, "anvil_reorg", depth, []interface{}{})
+}
+
+// RevertAndMine reverts to the snapshot id and mines blocks empty blocks, a reorg of the blocks mined
+// since the snapshot when blocks matches their count
+func (a *Anvil) RevertAndMine(ctx context.Context, id string, blocks uint64) error {
+	if err := a.Revert(ctx, id); err != nil {
+		return err
+	}
+	return a.Mine(ctx, blocks)
+}
+
+// SendImpersonatedTransaction sends a transaction from an impersonated address and returns its hash
+func (a *Anvil) SendImpersonatedTransaction(ctx context.Context, from, to common.Address, data []byte, value *big.Int) (common.Hash, error) {
+	params := map[string]interface{}{
+		"from": from,
+		"to":   to,
+		"data": hexutil.Bytes(data),
+	}
+	if value != nil {
+		params["value"] = (*hexutil.Big)(value)
+	}
+	var txHash common.Hash
+	if err := a.call(ctx, &txHash, "eth_sendTransaction", params); err != nil {
+		return common.Hash{}, err
+	}
+	return txHash, nil
+}
+
+// call performs a JSON-RPC call, naming the method in errors
+func (a *Anvil) call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if err := a.client.CallContext(ctx, result, method, args...); err != nil {
+		return fmt.Errorf("%s failed: %w", method, err)
+	}
+	return nil
+}
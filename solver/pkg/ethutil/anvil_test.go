@@ -0,0 +1,194 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAnvil serves the anvil_*, evm_* and eth_sendTransaction methods in memory
+type fakeAnvil struct {
+	impersonated map[common.Address]bool
+	balances     map[common.Address]*big.Int
+	blocks       []uint64 // block timestamps
+	nextTime     uint64
+	snapshots    map[string]int
+	sent         []map[string]interface{}
+	reorgs       int
+}
+
+type fakeAnvilNamespace struct{ node *fakeAnvil }
+
+func (n fakeAnvilNamespace) ImpersonateAccount(addr common.Address) {
+	n.node.impersonated[addr] = true
+}
+
+func (n fakeAnvilNamespace) StopImpersonatingAccount(addr common.Address) {
+	delete(n.node.impersonated, addr)
+}
+
+func (n fakeAnvilNamespace) SetBalance(addr common.Address, wei *hexutil.Big) {
+	n.node.balances[addr] = wei.ToInt()
+}
+
+func (n fakeAnvilNamespace) Reorg(depth uint64, pairs []interface{}) error {
+	if depth == 0 || depth >= uint64(len(n.node.blocks)) {
+		return fmt.Errorf("invalid reorg depth %d", depth)
+	}
+	height := len(n.node.blocks)
+	for i := height - int(depth); i < height; i++ {
+		n.node.blocks[i]++
+	}
+	n.node.reorgs++
+	return nil
+}
+
+type fakeEVMNamespace struct{ node *fakeAnvil }
+
+func (n fakeEVMNamespace) Mine() string {
+	timestamp := n.node.nextTime
+	if timestamp == 0 {
+		timestamp = n.node.blocks[len(n.node.blocks)-1] + 1
+	}
+	n.node.blocks = append(n.node.blocks, timestamp)
+	n.node.nextTime = 0
+	return "0x0"
+}
+
+func (n fakeEVMNamespace) SetNextBlockTimestamp(t hexutil.Uint64) {
+	n.node.nextTime = uint64(t)
+}
+
+func (n fakeEVMNamespace) Snapshot() string {
+	id := hexutil.EncodeUint64(uint64(len(n.node.snapshots) + 1))
+	n.node.snapshots[id] = len(n.node.blocks)
+	return id
+}
+
+func (n fakeEVMNamespace) Revert(id string) bool {
+	height, ok := n.node.snapshots[id]
+	if !ok {
+		return false
+	}
+	delete(n.node.snapshots, id)
+	n.node.blocks = n.node.blocks[:height]
+	return true
+}
+
+type fakeEthNamespace struct{ node *fakeAnvil }
+
+func (n fakeEthNamespace) SendTransaction(params map[string]interface{}) common.Hash {
+	n.node.sent = append(n.node.sent, params)
+	return common.HexToHash("0xabc")
+}
+
+func newFakeAnvil(t *testing.T) (*Anvil, *fakeAnvil) {
+	t.Helper()
+	node := &fakeAnvil{
+		impersonated: make(map[common.Address]bool),
+		balances:     make(map[common.Address]*big.Int),
+		blocks:       []uint64{1_700_000_000},
+		snapshots:    make(map[string]int),
+	}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("anvil", fakeAnvilNamespace{node}))
+	require.NoError(t, server.RegisterName("evm", fakeEVMNamespace{node}))
+	require.NoError(t, server.RegisterName("eth", fakeEthNamespace{node}))
+	client := rpc.DialInProc(server)
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+	return NewAnvil(client), node
+}
+
+func TestAnvil(t *testing.T) {
+	ctx := context.Background()
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	router := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+
+	t.Run("impersonates and sends from the owner", func(t *testing.T) {
+		anvil, node := newFakeAnvil(t)
+		require.NoError(t, anvil.Impersonate(ctx, owner))
+		assert.True(t, node.impersonated[owner])
+
+		txHash, err := anvil.SendImpersonatedTransaction(ctx, owner, router, []byte{0x12, 0x34}, big.NewInt(5))
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToHash("0xabc"), txHash)
+		require.Len(t, node.sent, 1)
+		assert.Equal(t, "0x1234", node.sent[0]["data"])
+		assert.Equal(t, "0x5", node.sent[0]["value"])
+		assert.Equal(t, router.Hex(), common.HexToAddress(node.sent[0]["to"].(string)).Hex())
+
+		require.NoError(t, anvil.StopImpersonating(ctx, owner))
+		assert.False(t, node.impersonated[owner])
+	})
+
+	t.Run("sets balances wider than 64 bits", func(t *testing.T) {
+		anvil, node := newFakeAnvil(t)
+		rich, _ := new(big.Int).SetString("1000000000000000000000000000", 10)
+		require.NoError(t, anvil.SetBalance(ctx, owner, rich))
+		assert.Equal(t, rich, node.balances[owner])
+	})
+
+	t.Run("mines blocks at a chosen time", func(t *testing.T) {
+		anvil, node := newFakeAnvil(t)
+		require.NoError(t, anvil.Mine(ctx, 2))
+		assert.Len(t, node.blocks, 3)
+
+		deadline := time.Unix(1_800_000_000, 0)
+		require.NoError(t, anvil.MineAt(ctx, deadline))
+		assert.Equal(t, uint64(1_800_000_000), node.blocks[len(node.blocks)-1])
+	})
+
+	t.Run("reverts to a snapshot once", func(t *testing.T) {
+		anvil, node := newFakeAnvil(t)
+		id, err := anvil.Snapshot(ctx)
+		require.NoError(t, err)
+		require.NoError(t, anvil.Mine(ctx, 3))
+
+		require.NoError(t, anvil.Revert(ctx, id))
+		assert.Len(t, node.blocks, 1)
+		assert.ErrorContains(t, anvil.Revert(ctx, id), "snapshot "+id+" not found")
+	})
+
+	t.Run("reorgs the latest blocks at the same height", func(t *testing.T) {
+		anvil, node := newFakeAnvil(t)
+		require.NoError(t, anvil.Mine(ctx, 3))
+		before := append([]uint64(nil), node.blocks...)
+
+		require.NoError(t, anvil.Reorg(ctx, 2))
+		assert.Equal(t, 1, node.reorgs)
+		require.Len(t, node.blocks, 4)
+		assert.Equal(t, before[:2], node.blocks[:2])
+		assert.NotEqual(t, before[2:], node.blocks[2:])
+
+		assert.ErrorContains(t, anvil.Reorg(ctx, 0), "reorg depth must be positive")
+		assert.ErrorContains(t, anvil.Reorg(ctx, 4), "anvil_reorg failed")
+	})
+
+	t.Run("replays a reorg from a snapshot", func(t *testing.T) {
+		anvil, node := newFakeAnvil(t)
+		id, err := anvil.Snapshot(ctx)
+		require.NoError(t, err)
+		require.NoError(t, anvil.Mine(ctx, 2))
+
+		require.NoError(t, anvil.RevertAndMine(ctx, id, 2))
+		assert.Len(t, node.blocks, 3)
+		assert.ErrorContains(t, anvil.RevertAndMine(ctx, id, 2), "not found")
+	})
+
+	t.Run("errors name the method", func(t *testing.T) {
+		anvil, _ := newFakeAnvil(t)
+		err := anvil.call(ctx, nil, "anvil_reset")
+		assert.ErrorContains(t, err, "anvil_reset failed")
+	})
+}
@@ -1,16 +1,16 @@
 package ethutil
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"fmt"
 	"math/big"
-	"strings"
 	"time"
 
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
@@ -18,12 +18,6 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-const (
-	// Gas limit constants
-	transferGasLimit = 100000
-	approveGasLimit  = 200000
-)
-
 // ERC20ABI contains the minimal ABI for ERC20 operations
 var ERC20ABI = `[
 	{
@@ -43,6 +37,20 @@ var ERC20ABI = `[
 		"stateMutability": "view",
 		"type": "function"
 	},
+	{
+		"inputs": [],
+		"name": "symbol",
+		"outputs": [{"internalType": "string", "name": "", "type": "string"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "decimals",
+		"outputs": [{"internalType": "uint8", "name": "", "type": "uint8"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
 	{
 		"inputs": [
 			{"internalType": "address", "name": "recipient", "type": "address"},
@@ -98,162 +106,138 @@ func GetBlockNumber(rpcURL string) (uint64, error) {
 }
 
 // ERC20Balance gets the ERC20 token balance for a given address
-func ERC20Balance(client *ethclient.Client, tokenAddress, ownerAddress common.Address) (*big.Int, error) {
-	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
+func ERC20Balance(client bind.ContractCaller, tokenAddress, ownerAddress common.Address) (*big.Int, error) {
+	token, err := contracts.NewMockERC20Caller(tokenAddress, client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+		return nil, fmt.Errorf("failed to bind ERC20 at %s: %w", tokenAddress.Hex(), err)
 	}
 
-	data, err := parsedABI.Pack("balanceOf", ownerAddress)
-	if err != nil {
-		return nil, fmt.Errorf("failed to pack balanceOf call: %w", err)
-	}
-
-	msg := ethereum.CallMsg{
-		From:            common.Address{},
-		To:              &tokenAddress,
-		Gas:             0,
-		GasPrice:        nil,
-		GasFeeCap:       nil,
-		GasTipCap:       nil,
-		Value:           nil,
-		Data:            data,
-		AccessList:      nil,
-		BlobGasFeeCap:   nil,
-		BlobHashes:      nil,
-		AuthorizationList: nil,
-	}
-	result, err := client.CallContract(context.Background(), msg, nil)
+	balance, err := token.BalanceOf(&bind.CallOpts{Context: context.Background()}, ownerAddress)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call balanceOf: %w", err)
-	}
-
-	// Check if result is empty (contract might not exist)
-	if len(result) == 0 {
-		return nil, fmt.Errorf("empty result from balanceOf call - contract may not exist at address %s", tokenAddress.Hex())
-	}
-
-	var balance *big.Int
-	if err := parsedABI.UnpackIntoInterface(&balance, "balanceOf", result); err != nil {
-		return nil, fmt.Errorf("failed to unpack balanceOf result: %w", err)
+		return nil, fmt.Errorf("failed to call balanceOf on %s: %w", tokenAddress.Hex(), err)
 	}
 
 	return balance, nil
 }
 
 // ERC20Allowance gets the ERC20 token allowance for a given owner and spender
-func ERC20Allowance(client *ethclient.Client, tokenAddress, ownerAddress, spenderAddress common.Address) (*big.Int, error) {
-	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
+func ERC20Allowance(client bind.ContractCaller, tokenAddress, ownerAddress, spenderAddress common.Address) (*big.Int, error) {
+	token, err := contracts.NewMockERC20Caller(tokenAddress, client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+		return nil, fmt.Errorf("failed to bind ERC20 at %s: %w", tokenAddress.Hex(), err)
 	}
 
-	data, err := parsedABI.Pack("allowance", ownerAddress, spenderAddress)
+	allowance, err := token.Allowance(&bind.CallOpts{Context: context.Background()}, ownerAddress, spenderAddress)
 	if err != nil {
-		return nil, fmt.Errorf("failed to pack allowance call: %w", err)
-	}
-
-	msg := ethereum.CallMsg{
-		From:            common.Address{},
-		To:              &tokenAddress,
-		Gas:             0,
-		GasPrice:        nil,
-		GasFeeCap:       nil,
-		GasTipCap:       nil,
-		Value:           nil,
-		Data:            data,
-		AccessList:      nil,
-		BlobGasFeeCap:   nil,
-		BlobHashes:      nil,
-		AuthorizationList: nil,
-	}
-	result, err := client.CallContract(context.Background(), msg, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call allowance: %w", err)
-	}
-
-	var allowance *big.Int
-	if err := parsedABI.UnpackIntoInterface(&allowance, "allowance", result); err != nil {
-		return nil, fmt.Errorf("failed to unpack allowance result: %w", err)
+		return nil, fmt.Errorf("failed to call allowance on %s: %w", tokenAddress.Hex(), err)
 	}
 
 	return allowance, nil
 }
 
-// createERC20Transaction creates a generic ERC20 transaction
-func createERC20Transaction(
-	client *ethclient.Client,
-	auth *bind.TransactOpts,
-	tokenAddress common.Address,
-	method string,
-	args []interface{},
-	gasLimit uint64,
-) (*gethtypes.Transaction, error) {
-	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
+// ERC20Metadata reads the symbol and decimals of an ERC20 token. Tokens that return symbol as
+// bytes32 (e.g. MKR) are decoded too.
+func ERC20Metadata(client bind.ContractCaller, tokenAddress common.Address) (string, uint8, error) {
+	token, err := contracts.NewMockERC20Caller(tokenAddress, client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+		return "", 0, fmt.Errorf("failed to bind ERC20 at %s: %w", tokenAddress.Hex(), err)
 	}
+	opts := &bind.CallOpts{Context: context.Background()}
 
-	data, err := parsedABI.Pack(method, args...)
+	symbol, err := token.Symbol(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to pack %s call: %w", method, err)
+		// The binding only decodes string symbols; retry the call as bytes32
+		fallback, fallbackErr := bytes32Symbol(client, tokenAddress)
+		if fallbackErr != nil {
+			return "", 0, fmt.Errorf("failed to call symbol on %s: %w", tokenAddress.Hex(), err)
+		}
+		symbol = fallback
 	}
 
-	// Get current nonce
-	nonce, err := client.PendingNonceAt(context.Background(), auth.From)
+	decimals, err := token.Decimals(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nonce: %w", err)
+		return "", 0, fmt.Errorf("failed to call decimals on %s: %w", tokenAddress.Hex(), err)
 	}
 
-	// Get current gas price
-	gasPrice, err := client.SuggestGasPrice(context.Background())
+	return symbol, decimals, nil
+}
+
+// bytes32Symbol reads the symbol of tokens that return it as bytes32 instead of string
+func bytes32Symbol(client bind.ContractCaller, tokenAddress common.Address) (string, error) {
+	parsedABI, err := contracts.MockERC20MetaData.GetAbi()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get gas price: %w", err)
+		return "", err
 	}
-
-	// Create transaction
-	tx := gethtypes.NewTransaction(
-		nonce,
-		tokenAddress,
-		big.NewInt(0), // No ETH value
-		gasLimit,      // Gas limit
-		gasPrice,
-		data,
-	)
-
-	// Sign transaction
-	signedTx, err := auth.Signer(auth.From, tx)
+	data, err := parsedABI.Pack("symbol")
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign %s transaction: %w", method, err)
+		return "", err
 	}
-
-	// Send transaction
-	err = client.SendTransaction(context.Background(), signedTx)
+	result, err := client.CallContract(context.Background(), ethereum.CallMsg{To: &tokenAddress, Data: data}, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send %s transaction: %w", method, err)
+		return "", err
 	}
-
-	return signedTx, nil
+	if len(result) != 32 {
+		return "", fmt.Errorf("unexpected symbol result length %d", len(result))
+	}
+	return string(bytes.TrimRight(result, "\x00")), nil
 }
 
-// ERC20Transfer creates a transfer transaction for ERC20 tokens
+// ERC20Transfer sends a transfer of ERC20 tokens. The nonce, gas price and gas limit are
+// filled in by the binding unless set on auth.
 func ERC20Transfer(
-	client *ethclient.Client,
+	client bind.ContractTransactor,
 	auth *bind.TransactOpts,
 	tokenAddress, recipientAddress common.Address,
 	amount *big.Int,
 ) (*gethtypes.Transaction, error) {
-	return createERC20Transaction(client, auth, tokenAddress, "transfer", []interface{}{recipientAddress, amount}, transferGasLimit)
+	token, err := contracts.NewMockERC20Transactor(tokenAddress, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind ERC20 at %s: %w", tokenAddress.Hex(), err)
+	}
+
+	tx, err := token.Transfer(auth, recipientAddress, amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send transfer transaction: %w", err)
+	}
+	return tx, nil
 }
 
-// ERC20Approve creates an approve transaction for ERC20 tokens
+// ERC20Approve sends an approve transaction for ERC20 tokens, filled in like ERC20Transfer
 func ERC20Approve(
-	client *ethclient.Client,
+	client bind.ContractTransactor,
 	auth *bind.TransactOpts,
 	tokenAddress, spenderAddress common.Address,
 	amount *big.Int,
 ) (*gethtypes.Transaction, error) {
-	return createERC20Transaction(client, auth, tokenAddress, "approve", []interface{}{spenderAddress, amount}, approveGasLimit)
+	token, err := contracts.NewMockERC20Transactor(tokenAddress, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind ERC20 at %s: %w", tokenAddress.Hex(), err)
+	}
+
+	tx, err := token.Approve(auth, spenderAddress, amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send approve transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// ERC20Mint sends a mint transaction to the test MockERC20 token, filled in like ERC20Transfer
+func ERC20Mint(
+	client bind.ContractTransactor,
+	auth *bind.TransactOpts,
+	tokenAddress, recipientAddress common.Address,
+	amount *big.Int,
+) (*gethtypes.Transaction, error) {
+	token, err := contracts.NewMockERC20Transactor(tokenAddress, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind MockERC20 at %s: %w", tokenAddress.Hex(), err)
+	}
+
+	tx, err := token.Mint(auth, recipientAddress, amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send mint transaction: %w", err)
+	}
+	return tx, nil
 }
 
 // WaitForTransaction waits for a transaction to be mined and returns the receipt
@@ -6,27 +6,154 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestERC20Balance(t *testing.T) {
-	t.Run("ERC20Balance function exists", func(t *testing.T) {
-		// Test that the function is properly defined
-		// In a real test, we'd need a mock client
-		assert.NotNil(t, ERC20Balance)
+	token := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	owner := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	spender := common.HexToAddress("0x3000000000000000000000000000000000000003")
+
+	uint256Result := func(v *big.Int) chainmock.EVMCallHandler {
+		return func(ethereum.CallMsg) ([]byte, error) {
+			return common.LeftPadBytes(v.Bytes(), 32), nil
+		}
+	}
+
+	t.Run("ERC20Balance decodes balanceOf", func(t *testing.T) {
+		backend := chainmock.NewEVMBackend(1)
+		backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), func(msg ethereum.CallMsg) ([]byte, error) {
+			// Argument is the left-padded owner address
+			assert.Equal(t, common.LeftPadBytes(owner.Bytes(), 32), msg.Data[4:])
+			return common.LeftPadBytes(big.NewInt(1500).Bytes(), 32), nil
+		})
+
+		balance, err := ERC20Balance(backend, token, owner)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(1500), balance)
+	})
+
+	t.Run("ERC20Allowance decodes allowance", func(t *testing.T) {
+		backend := chainmock.NewEVMBackend(1)
+		backend.HandleCall(token, chainmock.Selector("allowance(address,address)"), uint256Result(big.NewInt(77)))
+
+		allowance, err := ERC20Allowance(backend, token, owner, spender)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(77), allowance)
+	})
+
+	t.Run("ERC20Metadata decodes symbol and decimals", func(t *testing.T) {
+		backend := chainmock.NewEVMBackend(1)
+		backend.HandleCall(token, chainmock.Selector("symbol()"), func(ethereum.CallMsg) ([]byte, error) {
+			// Dynamic string: offset, length, padded bytes
+			encoded := common.LeftPadBytes(big.NewInt(32).Bytes(), 32)
+			encoded = append(encoded, common.LeftPadBytes(big.NewInt(3).Bytes(), 32)...)
+			return append(encoded, common.RightPadBytes([]byte("DOG"), 32)...), nil
+		})
+		backend.HandleCall(token, chainmock.Selector("decimals()"), uint256Result(big.NewInt(6)))
+
+		symbol, decimals, err := ERC20Metadata(backend, token)
+		require.NoError(t, err)
+		assert.Equal(t, "DOG", symbol)
+		assert.Equal(t, uint8(6), decimals)
+	})
+
+	t.Run("ERC20Metadata decodes a bytes32 symbol", func(t *testing.T) {
+		backend := chainmock.NewEVMBackend(1)
+		backend.HandleCall(token, chainmock.Selector("symbol()"), func(ethereum.CallMsg) ([]byte, error) {
+			return common.RightPadBytes([]byte("MKR"), 32), nil
+		})
+		backend.HandleCall(token, chainmock.Selector("decimals()"), uint256Result(big.NewInt(18)))
+
+		symbol, decimals, err := ERC20Metadata(backend, token)
+		require.NoError(t, err)
+		assert.Equal(t, "MKR", symbol)
+		assert.Equal(t, uint8(18), decimals)
 	})
 
-	t.Run("ERC20Allowance function exists", func(t *testing.T) {
-		// Test that the function is properly defined
-		assert.NotNil(t, ERC20Allowance)
+	t.Run("ERC20Metadata fails without symbol", func(t *testing.T) {
+		backend := chainmock.NewEVMBackend(1)
+		_, _, err := ERC20Metadata(backend, token)
+		assert.ErrorContains(t, err, "symbol")
 	})
 
-	t.Run("ERC20Approve function exists", func(t *testing.T) {
-		// Test that the function is properly defined
-		assert.NotNil(t, ERC20Approve)
+	t.Run("call error is returned", func(t *testing.T) {
+		backend := chainmock.NewEVMBackend(1)
+		backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(1)))
+		backend.FailNext("CallContract", fmt.Errorf("rpc down"))
+
+		_, err := ERC20Balance(backend, token, owner)
+		assert.Error(t, err)
+	})
+}
+
+func TestERC20Transactions(t *testing.T) {
+	token := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	recipient := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	sends := []struct {
+		name      string
+		signature string
+		send      func(*chainmock.EVMBackend, *bind.TransactOpts) (*gethtypes.Transaction, error)
+	}{
+		{"ERC20Transfer", "transfer(address,uint256)", func(b *chainmock.EVMBackend, auth *bind.TransactOpts) (*gethtypes.Transaction, error) {
+			return ERC20Transfer(b, auth, token, recipient, big.NewInt(500))
+		}},
+		{"ERC20Approve", "approve(address,uint256)", func(b *chainmock.EVMBackend, auth *bind.TransactOpts) (*gethtypes.Transaction, error) {
+			return ERC20Approve(b, auth, token, recipient, big.NewInt(500))
+		}},
+		{"ERC20Mint", "mint(address,uint256)", func(b *chainmock.EVMBackend, auth *bind.TransactOpts) (*gethtypes.Transaction, error) {
+			return ERC20Mint(b, auth, token, recipient, big.NewInt(500))
+		}},
+	}
+	for _, tt := range sends {
+		t.Run(tt.name+" sends through the binding", func(t *testing.T) {
+			backend := chainmock.NewEVMBackend(1)
+			backend.OnTransaction(token, func(*gethtypes.Transaction) uint64 { return gethtypes.ReceiptStatusSuccessful })
+			auth, err := NewTransactor(big.NewInt(1), privateKey)
+			require.NoError(t, err)
+
+			// Two sends in a row: the binding picks up the pending nonce each time
+			for nonce := uint64(0); nonce < 2; nonce++ {
+				tx, err := tt.send(backend, auth)
+				require.NoError(t, err)
+				selector := chainmock.Selector(tt.signature)
+				assert.Equal(t, selector[:], tx.Data()[:4])
+				assert.Equal(t, common.LeftPadBytes(recipient.Bytes(), 32), tx.Data()[4:36])
+				assert.Equal(t, nonce, tx.Nonce())
+				assert.NotZero(t, tx.Gas(), "gas limit is estimated")
+			}
+			assert.Len(t, backend.SentTo(token), 2)
+		})
+	}
+
+	t.Run("send error is returned", func(t *testing.T) {
+		backend := chainmock.NewEVMBackend(1)
+		backend.OnTransaction(token, func(*gethtypes.Transaction) uint64 { return gethtypes.ReceiptStatusSuccessful })
+		backend.FailNext("SendTransaction", fmt.Errorf("rpc down"))
+		auth, err := NewTransactor(big.NewInt(1), privateKey)
+		require.NoError(t, err)
+
+		_, err = ERC20Transfer(backend, auth, token, recipient, big.NewInt(1))
+		assert.ErrorContains(t, err, "failed to send transfer transaction")
+	})
+
+	t.Run("token without code is rejected", func(t *testing.T) {
+		auth, err := NewTransactor(big.NewInt(1), privateKey)
+		require.NoError(t, err)
+
+		_, err = ERC20Approve(chainmock.NewEVMBackend(1), auth, token, recipient, big.NewInt(1))
+		assert.ErrorIs(t, err, bind.ErrNoCode)
 	})
 }
 
@@ -194,27 +321,22 @@ func TestErrorHandlingBasic(t *testing.T) {
 func TestUtilityFunctions(t *testing.T) {
 	t.Run("Wei to Ether conversion", func(t *testing.T) {
 		wei := big.NewInt(1000000000000000000) // 1 ETH
-		ether := new(big.Float).SetInt(wei)
-		ether.Quo(ether, big.NewFloat(1e18))
 
-		assert.Equal(t, "1", ether.Text('f', 0))
+		assert.Equal(t, "1", amount.New(wei, 18).String())
 	})
 
 	t.Run("Ether to Wei conversion", func(t *testing.T) {
-		ether := big.NewFloat(1.5)
-		wei := new(big.Float).Mul(ether, big.NewFloat(1e18))
-		weiInt, _ := wei.Int(nil)
+		wei, err := amount.Parse("1.5", 18)
+		require.NoError(t, err)
 
 		expected := big.NewInt(1500000000000000000) // 1.5 ETH in wei
-		assert.Equal(t, expected, weiInt)
+		assert.Equal(t, expected, wei.Units)
 	})
 
 	t.Run("Gas price formatting", func(t *testing.T) {
 		gasPrice := big.NewInt(20000000000) // 20 gwei
-		gwei := new(big.Float).SetInt(gasPrice)
-		gwei.Quo(gwei, big.NewFloat(1e9))
 
-		assert.Equal(t, "20", gwei.Text('f', 0))
+		assert.Equal(t, "20", amount.New(gasPrice, 9).String())
 	})
 }
 
@@ -348,19 +470,16 @@ func TestBigIntOperations(t *testing.T) {
 
 	t.Run("wei to ether conversion", func(t *testing.T) {
 		wei := big.NewInt(1500000000000000000) // 1.5 ETH
-		ether := new(big.Float).SetInt(wei)
-		ether.Quo(ether, big.NewFloat(1e18))
 
-		assert.Equal(t, "1.5", ether.Text('f', 1))
+		assert.Equal(t, "1.5", amount.New(wei, 18).Fixed(1))
 	})
 
 	t.Run("ether to wei conversion", func(t *testing.T) {
-		ether := big.NewFloat(2.5)
-		wei := new(big.Float).Mul(ether, big.NewFloat(1e18))
-		weiInt, _ := wei.Int(nil)
+		wei, err := amount.Parse("2.5", 18)
+		require.NoError(t, err)
 
 		expected := big.NewInt(2500000000000000000) // 2.5 ETH in wei
-		assert.Equal(t, expected, weiInt)
+		assert.Equal(t, expected, wei.Units)
 	})
 }
 
@@ -636,9 +755,7 @@ func TestTokenAmountFormatting(t *testing.T) {
 		}
 
 		for _, tc := range testCases {
-			ether := new(big.Float).SetInt(tc.wei)
-			ether.Quo(ether, big.NewFloat(1e18))
-			assert.Equal(t, tc.ether, ether.Text('f', 1))
+			assert.Equal(t, tc.ether, amount.New(tc.wei, 18).Fixed(1))
 		}
 	})
 
@@ -0,0 +1,176 @@
+package orderwait
+
+// Module: Order state waiter
+// - WaitForOrderState polls the solver's view of an order until it reaches a status, so
+//   integration tests and tools wait on explicit state transitions instead of scraping the
+//   solver's console output
+// - The order is read from a Source: StoreSource reads an order store in this process,
+//   AdminSource asks a running solver through GET /orders/{id} of its admin API
+//   (SOLVER_ADMIN_ADDR, see solvercore/admin_api.go)
+//
+// An order that is not stored yet is waited for, since the solver only stores it once its
+// listener sees the Open event. Waiting fails early when the order reaches a final status other
+// than the one waited for; SETTLED counts as reaching FILLED.
+//
+// Usage:
+//
+//	src := orderwait.AdminSource("127.0.0.1:8090", "")
+//	order, err := orderwait.WaitForOrderState(ctx, src, orderID, orders.StatusSettled, 3*time.Minute)
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+)
+
+const (
+	// PollInterval is how often WaitForOrderState reads the order
+	PollInterval = 500 * time.Millisecond
+
+	requestTimeout = 5 * time.Second
+)
+
+// finalStatuses are the statuses an order never leaves
+var finalStatuses = map[orders.Status]bool{
+	orders.StatusSettled:  true,
+	orders.StatusRejected: true,
+	orders.StatusLostRace: true,
+	orders.StatusExpired:  true,
+}
+
+// Source reads the solver's view of an order, orders.ErrOrderNotFound when it is not stored
+type Source interface {
+	Order(ctx context.Context, orderID string) (orders.Order, error)
+}
+
+// SourceFunc adapts a function to a Source
+type SourceFunc func(ctx context.Context, orderID string) (orders.Order, error)
+
+// Order calls f
+func (f SourceFunc) Order(ctx context.Context, orderID string) (orders.Order, error) {
+	return f(ctx, orderID)
+}
+
+// StoreSource reads orders from store
+func StoreSource(store *orders.Store) Source {
+	return SourceFunc(func(_ context.Context, orderID string) (orders.Order, error) {
+		order, ok := store.Get(orderID)
+		if !ok {
+			return orders.Order{}, orders.ErrOrderNotFound
+		}
+		return order, nil
+	})
+}
+
+// adminSource reads orders from the admin API of a running solver
+type adminSource struct {
+	base  string
+	token string
+	http  *http.Client
+}
+
+// AdminSource reads orders from the admin API at addr, with or without a scheme. token is
+// SOLVER_ADMIN_TOKEN of the solver, empty when it is not set.
+func AdminSource(addr, token string) Source {
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	return &adminSource{base: strings.TrimRight(addr, "/"), token: token, http: &http.Client{Timeout: requestTimeout}}
+}
+
+// Order reads GET /orders/{id}
+func (s *adminSource) Order(ctx context.Context, orderID string) (orders.Order, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.base+"/orders/"+url.PathEscape(orderID), nil)
+	if err != nil {
+		return orders.Order{}, err
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return orders.Order{}, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return orders.Order{}, orders.ErrOrderNotFound
+	default:
+		var body struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&body) == nil && body.Error != "" {
+			return orders.Order{}, fmt.Errorf("GET /orders/%s: %s: %s", orderID, resp.Status, body.Error)
+		}
+		return orders.Order{}, fmt.Errorf("GET /orders/%s: %s", orderID, resp.Status)
+	}
+
+	var order orders.Order
+	if err := json.NewDecoder(resp.Body).Decode(&order); err != nil {
+		return orders.Order{}, fmt.Errorf("GET /orders/%s: invalid response: %w", orderID, err)
+	}
+	return order, nil
+}
+
+// Reached reports whether an order in status has reached state
+func Reached(status, state orders.Status) bool {
+	return status == state || (state == orders.StatusFilled && status == orders.StatusSettled)
+}
+
+// WaitForOrderState polls src until the order reaches state and returns it. It fails when the
+// order ends in another final status, when timeout passes or when ctx is done. Errors reading
+// the order, e.g. while the solver is starting, are retried and reported on timeout.
+func WaitForOrderState(ctx context.Context, src Source, orderID string, state orders.Status, timeout time.Duration) (orders.Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+
+	var (
+		last    orders.Order
+		lastErr error
+	)
+	for {
+		order, err := src.Order(ctx, orderID)
+		switch {
+		case err == nil:
+			last, lastErr = order, nil
+			if Reached(order.Status, state) {
+				return order, nil
+			}
+			if finalStatuses[order.Status] {
+				if order.Reason != "" {
+					return order, fmt.Errorf("order %s ended %s, not %s: %s", orderID, order.Status, state, order.Reason)
+				}
+				return order, fmt.Errorf("order %s ended %s, not %s", orderID, order.Status, state)
+			}
+		case errors.Is(err, orders.ErrOrderNotFound):
+			lastErr = nil
+		default:
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			switch {
+			case lastErr != nil:
+				return last, fmt.Errorf("order %s did not reach %s within %v: %w", orderID, state, timeout, lastErr)
+			case last.Status == "":
+				return last, fmt.Errorf("order %s not seen by the solver within %v", orderID, timeout)
+			default:
+				return last, fmt.Errorf("order %s still %s after %v, waiting for %s", orderID, last.Status, timeout, state)
+			}
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package orderwait
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/admin"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sequence returns the statuses one after the other, repeating the last one; an empty status
+// is an order not stored yet
+func sequence(statuses ...orders.Status) Source {
+	calls := 0
+	return SourceFunc(func(_ context.Context, orderID string) (orders.Order, error) {
+		status := statuses[min(calls, len(statuses)-1)]
+		calls++
+		if status == "" {
+			return orders.Order{}, orders.ErrOrderNotFound
+		}
+		return orders.Order{OrderID: orderID, Status: status, Reason: "fill reverted"}, nil
+	})
+}
+
+func TestReached(t *testing.T) {
+	assert.True(t, Reached(orders.StatusFilled, orders.StatusFilled))
+	assert.True(t, Reached(orders.StatusSettled, orders.StatusFilled))
+	assert.False(t, Reached(orders.StatusFilled, orders.StatusSettled))
+	assert.False(t, Reached(orders.StatusObserved, orders.StatusFilled))
+}
+
+func TestWaitForOrderState(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("waits for the order to be stored and reach the state", func(t *testing.T) {
+		order, err := WaitForOrderState(ctx, sequence("", orders.StatusObserved, orders.StatusSettled), "0x1", orders.StatusFilled, 5*time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, orders.StatusSettled, order.Status)
+	})
+
+	t.Run("fails early on another final status", func(t *testing.T) {
+		start := time.Now()
+		order, err := WaitForOrderState(ctx, sequence(orders.StatusRejected), "0x1", orders.StatusSettled, time.Minute)
+		assert.EqualError(t, err, "order 0x1 ended REJECTED, not SETTLED: fill reverted")
+		assert.Equal(t, orders.StatusRejected, order.Status)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("retries failed orders until the timeout", func(t *testing.T) {
+		order, err := WaitForOrderState(ctx, sequence(orders.StatusFailed), "0x1", orders.StatusFilled, 2*PollInterval)
+		assert.EqualError(t, err, "order 0x1 still FAILED after 1s, waiting for FILLED")
+		assert.Equal(t, orders.StatusFailed, order.Status)
+	})
+
+	t.Run("never stored", func(t *testing.T) {
+		_, err := WaitForOrderState(ctx, sequence(""), "0x1", orders.StatusFilled, PollInterval)
+		assert.EqualError(t, err, "order 0x1 not seen by the solver within 500ms")
+	})
+
+	t.Run("reports the last read error", func(t *testing.T) {
+		src := SourceFunc(func(context.Context, string) (orders.Order, error) { return orders.Order{}, assert.AnError })
+		_, err := WaitForOrderState(ctx, src, "0x1", orders.StatusFilled, PollInterval)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestStoreSource(t *testing.T) {
+	store, err := orders.NewStore(t.TempDir() + "/orders.json")
+	require.NoError(t, err)
+	require.NoError(t, store.Observe(&types.ParsedArgs{
+		OrderID:       "0x1",
+		ResolvedOrder: types.ResolvedCrossChainOrder{OriginChainID: big.NewInt(84532)},
+	}))
+	require.NoError(t, store.SetStatus("0x1", orders.StatusFilled, ""))
+
+	order, err := StoreSource(store).Order(context.Background(), "0x1")
+	require.NoError(t, err)
+	assert.Equal(t, orders.StatusFilled, order.Status)
+	_, err = StoreSource(store).Order(context.Background(), "0x2")
+	assert.ErrorIs(t, err, orders.ErrOrderNotFound)
+}
+
+// TestAdminSource tests reading orders from an admin API with a token
+func TestAdminSource(t *testing.T) {
+	srv := admin.NewServer("127.0.0.1:0", "secret")
+	srv.HandleFunc("GET /orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.PathValue("id") {
+		case "0x1":
+			admin.WriteJSON(w, http.StatusOK, orders.Order{OrderID: "0x1", Status: orders.StatusSettled})
+		case "0x2":
+			admin.WriteError(w, http.StatusServiceUnavailable, errors.New("database down"))
+		default:
+			admin.WriteError(w, http.StatusNotFound, orders.ErrOrderNotFound)
+		}
+	})
+	server := httptest.NewServer(srv)
+	defer server.Close()
+
+	src := AdminSource(server.URL+"/", "secret")
+	order, err := src.Order(context.Background(), "0x1")
+	require.NoError(t, err)
+	assert.Equal(t, orders.StatusSettled, order.Status)
+	_, err = src.Order(context.Background(), "0x2")
+	assert.EqualError(t, err, "GET /orders/0x2: 503 Service Unavailable: database down")
+	_, err = src.Order(context.Background(), "0x3")
+	assert.ErrorIs(t, err, orders.ErrOrderNotFound)
+
+	_, err = AdminSource(server.URL, "wrong").Order(context.Background(), "0x1")
+	assert.ErrorContains(t, err, "401")
+}
@@ -0,0 +1,153 @@
+package receiptwait
+
+// Module: Transaction receipt waiter
+// - Wait polls until a transaction is included, starting at a quarter of the chain's expected
+//   block time (config.ExpectedBlockTime) and doubling the wait after each miss up to two block
+//   times, so fast chains are checked often and slow ones aren't hammered
+// - A process-wide cap bounds how many waits poll at once (RECEIPT_WAIT_MAX_CONCURRENT,
+//   default 16); further waits queue for a slot until their context ends
+// - EVM and Starknet wait for a receipt on the respective chains; a receipt that is not found yet
+//   is retried, any other RPC error ends the wait
+//
+// Usage:
+//
+//	receipt, err := receiptwait.EVM(ctx, client, txHash, config.ExpectedBlockTime("Base"))
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// DefaultMaxConcurrent is the default number of waits polling at once
+	DefaultMaxConcurrent = 16
+
+	minInterval = 50 * time.Millisecond
+	maxInterval = 30 * time.Second
+)
+
+// PollFunc checks once whether the awaited transaction is included; an error ends the wait
+type PollFunc func(ctx context.Context) (bool, error)
+
+// Waiter paces receipt polling and caps the number of concurrent waits
+type Waiter struct {
+	slots chan struct{}
+}
+
+// NewWaiter returns a waiter letting at most maxConcurrent waits poll at once (at least one)
+func NewWaiter(maxConcurrent int) *Waiter {
+	return &Waiter{slots: make(chan struct{}, max(maxConcurrent, 1))}
+}
+
+var (
+	defaultWaiter     *Waiter
+	defaultWaiterOnce sync.Once
+)
+
+// Default returns the process-wide waiter, sized by RECEIPT_WAIT_MAX_CONCURRENT
+func Default() *Waiter {
+	defaultWaiterOnce.Do(func() {
+		defaultWaiter = NewWaiter(envutil.GetEnvInt("RECEIPT_WAIT_MAX_CONCURRENT", DefaultMaxConcurrent))
+	})
+	return defaultWaiter
+}
+
+// Backoff returns the first and the longest wait between polls on a chain producing a block every blockTime
+func Backoff(blockTime time.Duration) (initial, longest time.Duration) {
+	initial = min(max(blockTime/4, minInterval), time.Second)
+	longest = min(max(2*blockTime, initial), maxInterval)
+	return initial, longest
+}
+
+// Wait calls poll until it reports the transaction included, returns an error or ctx ends.
+// The first poll happens as soon as a slot is free, even when ctx ends meanwhile.
+func (w *Waiter) Wait(ctx context.Context, blockTime time.Duration, poll PollFunc) error {
+	select {
+	case w.slots <- struct{}{}:
+	default:
+		select {
+		case w.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	defer func() { <-w.slots }()
+
+	interval, longest := Backoff(blockTime)
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for {
+		done, err := poll(ctx)
+		if err != nil || done {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		interval = min(2*interval, longest)
+		timer.Reset(interval)
+	}
+}
+
+// EVMReceiptReader reads EVM transaction receipts, e.g. an ethclient.Client
+type EVMReceiptReader interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*gethtypes.Receipt, error)
+}
+
+// StarknetReceiptReader reads Starknet transaction receipts, e.g. an rpc.Provider
+type StarknetReceiptReader interface {
+	TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error)
+}
+
+// EVM waits with the default waiter until txHash has a receipt and returns it, whatever its status
+func EVM(ctx context.Context, client EVMReceiptReader, txHash common.Hash, blockTime time.Duration) (*gethtypes.Receipt, error) {
+	var receipt *gethtypes.Receipt
+	err := Default().Wait(ctx, blockTime, func(ctx context.Context) (bool, error) {
+		var err error
+		receipt, err = client.TransactionReceipt(ctx, txHash)
+		if errors.Is(err, ethereum.NotFound) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// Starknet waits with the default waiter until txHash has a receipt and returns it, whatever its
+// execution status
+func Starknet(ctx context.Context, provider StarknetReceiptReader, txHash *felt.Felt, blockTime time.Duration) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	var receipt *rpc.TransactionReceiptWithBlockInfo
+	err := Default().Wait(ctx, blockTime, func(ctx context.Context) (bool, error) {
+		var err error
+		receipt, err = provider.TransactionReceipt(ctx, txHash)
+		if IsStarknetHashNotFound(err) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// IsStarknetHashNotFound reports whether err is the RPC error of a transaction the node doesn't know yet
+func IsStarknetHashNotFound(err error) bool {
+	var rpcErr *rpc.RPCError
+	return errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrHashNotFound.Code
+}
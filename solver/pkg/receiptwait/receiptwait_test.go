@@ -0,0 +1,114 @@
+package receiptwait
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff(t *testing.T) {
+	initial, longest := Backoff(12 * time.Second)
+	assert.Equal(t, time.Second, initial, "capped at a second")
+	assert.Equal(t, 24*time.Second, longest)
+
+	initial, longest = Backoff(250 * time.Millisecond)
+	assert.Equal(t, 62500*time.Microsecond, initial)
+	assert.Equal(t, 500*time.Millisecond, longest)
+
+	initial, longest = Backoff(0)
+	assert.Equal(t, minInterval, initial)
+	assert.Equal(t, minInterval, longest)
+
+	_, longest = Backoff(time.Minute)
+	assert.Equal(t, maxInterval, longest)
+}
+
+func TestWait(t *testing.T) {
+	t.Run("polls until included", func(t *testing.T) {
+		polls := 0
+		err := NewWaiter(1).Wait(context.Background(), 0, func(context.Context) (bool, error) {
+			polls++
+			return polls == 3, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, polls)
+	})
+
+	t.Run("stops on a poll error", func(t *testing.T) {
+		boom := errors.New("boom")
+		err := NewWaiter(1).Wait(context.Background(), 0, func(context.Context) (bool, error) { return false, boom })
+		assert.ErrorIs(t, err, boom)
+	})
+
+	t.Run("stops when the context ends", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err := NewWaiter(1).Wait(ctx, time.Second, func(context.Context) (bool, error) { return false, nil })
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("caps concurrent waits", func(t *testing.T) {
+		waiter := NewWaiter(1)
+		release := make(chan struct{})
+		started := make(chan struct{})
+		go func() {
+			_ = waiter.Wait(context.Background(), 0, func(context.Context) (bool, error) {
+				close(started)
+				<-release
+				return true, nil
+			})
+		}()
+		<-started
+
+		var polled atomic.Bool
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err := waiter.Wait(ctx, 0, func(context.Context) (bool, error) { polled.Store(true); return true, nil })
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.False(t, polled.Load(), "no slot while the first wait polls")
+
+		close(release)
+		require.NoError(t, waiter.Wait(context.Background(), 0, func(context.Context) (bool, error) { return true, nil }))
+	})
+}
+
+// fakeEVMReceipts misses the receipt until the given number of calls
+type fakeEVMReceipts struct {
+	missing int
+	err     error
+}
+
+func (f *fakeEVMReceipts) TransactionReceipt(context.Context, common.Hash) (*gethtypes.Receipt, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.missing > 0 {
+		f.missing--
+		return nil, ethereum.NotFound
+	}
+	return &gethtypes.Receipt{Status: gethtypes.ReceiptStatusSuccessful}, nil
+}
+
+func TestEVM(t *testing.T) {
+	receipt, err := EVM(context.Background(), &fakeEVMReceipts{missing: 2}, common.Hash{0x01}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, gethtypes.ReceiptStatusSuccessful, receipt.Status)
+
+	_, err = EVM(context.Background(), &fakeEVMReceipts{err: errors.New("connection refused")}, common.Hash{0x01}, 0)
+	assert.ErrorContains(t, err, "connection refused")
+}
+
+func TestIsStarknetHashNotFound(t *testing.T) {
+	assert.True(t, IsStarknetHashNotFound(rpc.ErrHashNotFound))
+	assert.False(t, IsStarknetHashNotFound(errors.New("connection refused")))
+	assert.False(t, IsStarknetHashNotFound(nil))
+}
@@ -0,0 +1,393 @@
+package starknetutil
+
+// Module: ABI-aware calldata for Cairo contract calls
+// - ParseABI reads a Sierra contract ABI (the "abi" of a Cairo 1+ contract class, or any subset of
+//   its entries); functions may be top-level or inside interface entries
+// - HasEvent tells whether the ABI declares an event, e.g. to check a deployed class emits the events
+//   a listener filters on
+// - ABI.Calldata serializes typed Go values following the declared Cairo type of each input, so
+//   callers never count felts or write length prefixes by hand
+// - Every value is checked against its type (argument count, struct members, enum variants,
+//   integer ranges) before any calldata is returned, so a malformed call is never submitted
+//
+// Go values accepted per Cairo type:
+// - felt252, ContractAddress, ClassHash, EthAddress: *felt.Felt, *big.Int, Go integers or a hex string
+// - u8..u128, usize, u256: *big.Int, Go integers, *felt.Felt or a numeric string, range-checked
+// - bool: bool
+// - Array<T>, Span<T>: any Go slice or array of values for T
+// - alexandria Bytes: []byte (size + big-endian u128 words), or the struct members
+// - structs: map[string]interface{} by member name, or a Go struct with `cairo:"member"` field tags
+// - enums: CairoEnum{Variant, Value}
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// Cairo types with a dedicated encoding
+const (
+	cairoFelt252       = "core::felt252"
+	cairoBool          = "core::bool"
+	cairoUnit          = "()"
+	cairoBytes         = "alexandria_bytes::bytes::Bytes"
+	cairoArrayPrefix   = "core::array::Array::<"
+	cairoSpanPrefix    = "core::array::Span::<"
+	cairoIntegerPrefix = "core::integer::"
+)
+
+// Felt-sized address types, serialized as a single felt
+var cairoFeltTypes = map[string]bool{
+	cairoFelt252: true,
+	"core::starknet::contract_address::ContractAddress": true,
+	"core::starknet::class_hash::ClassHash":             true,
+	"core::starknet::eth_address::EthAddress":           true,
+}
+
+// Bit sizes of the unsigned integer types
+var cairoUintBits = map[string]int{
+	"u8": 8, "u16": 16, "u32": 32, "u64": 64, "u128": 128, "usize": 32, "u256": 256,
+}
+
+// feltPrime is the Starknet field prime, 2^251 + 17·2^192 + 1; felts are below it
+var feltPrime, _ = new(big.Int).SetString("800000000000011000000000000000000000000000000000000000000000001", 16)
+
+// ABIParam is a named, typed function input or output, struct member or enum variant
+type ABIParam struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ABIEntry is an entry of a Sierra contract ABI
+type ABIEntry struct {
+	Type            string     `json:"type"` // function, interface, struct, enum, event, impl, constructor, l1_handler
+	Name            string     `json:"name"`
+	Kind            string     `json:"kind,omitempty"` // event: struct or enum
+	Inputs          []ABIParam `json:"inputs,omitempty"`
+	Outputs         []ABIParam `json:"outputs,omitempty"`
+	StateMutability string     `json:"state_mutability,omitempty"`
+	Members         []ABIParam `json:"members,omitempty"`  // struct
+	Variants        []ABIParam `json:"variants,omitempty"` // enum
+	Items           []ABIEntry `json:"items,omitempty"`    // interface
+}
+
+// ABI is a parsed contract ABI, indexed for serialization
+type ABI struct {
+	functions map[string]ABIEntry
+	structs   map[string]ABIEntry
+	enums     map[string]ABIEntry
+	events    map[string]ABIEntry
+}
+
+// CairoEnum is the value of a Cairo enum: the variant's name and its value (nil for unit variants)
+type CairoEnum struct {
+	Variant string
+	Value   interface{}
+}
+
+// ParseABI parses a Sierra contract ABI from its JSON form
+func ParseABI(data []byte) (*ABI, error) {
+	var entries []ABIEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid contract ABI: %w", err)
+	}
+	abi := &ABI{functions: map[string]ABIEntry{}, structs: map[string]ABIEntry{}, enums: map[string]ABIEntry{}, events: map[string]ABIEntry{}}
+	var index func(entries []ABIEntry)
+	index = func(entries []ABIEntry) {
+		for _, entry := range entries {
+			switch entry.Type {
+			case "function":
+				abi.functions[entry.Name] = entry
+			case "struct":
+				abi.structs[entry.Name] = entry
+			case "enum":
+				abi.enums[entry.Name] = entry
+			case "event":
+				abi.events[entry.Name] = entry
+			case "interface":
+				index(entry.Items)
+			}
+		}
+	}
+	index(entries)
+	return abi, nil
+}
+
+// MustParseABI is ParseABI for ABIs embedded in the binary; it panics on invalid JSON
+func MustParseABI(data string) *ABI {
+	abi, err := ParseABI([]byte(data))
+	if err != nil {
+		panic(err)
+	}
+	return abi
+}
+
+// Function returns the ABI entry of a function
+func (a *ABI) Function(name string) (ABIEntry, bool) {
+	entry, ok := a.functions[name]
+	return entry, ok
+}
+
+// HasEvent reports whether the ABI declares the event struct name, either by its full path (e.g.
+// "oif_starknet::base7683::Base7683Component::Open") or by its last segment ("Open"), the name its
+// selector is derived from
+func (a *ABI) HasEvent(name string) bool {
+	for path, entry := range a.events {
+		if entry.Kind != "struct" {
+			continue
+		}
+		if path == name || path[strings.LastIndex(path, "::")+2:] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Calldata serializes args as the inputs of function, in declaration order
+func (a *ABI) Calldata(function string, args ...interface{}) ([]*felt.Felt, error) {
+	entry, ok := a.functions[function]
+	if !ok {
+		return nil, fmt.Errorf("function %s is not in the ABI", function)
+	}
+	if len(args) != len(entry.Inputs) {
+		return nil, fmt.Errorf("%s takes %d arguments (%s), got %d", function, len(entry.Inputs), paramNames(entry.Inputs), len(args))
+	}
+	var calldata []*felt.Felt
+	for i, input := range entry.Inputs {
+		encoded, err := a.encode(input.Type, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", function, input.Name, err)
+		}
+		calldata = append(calldata, encoded...)
+	}
+	return calldata, nil
+}
+
+// InvokeCall builds the call of function on contract with args serialized as its inputs
+func (a *ABI) InvokeCall(contract *felt.Felt, function string, args ...interface{}) (rpc.InvokeFunctionCall, error) {
+	calldata, err := a.Calldata(function, args...)
+	if err != nil {
+		return rpc.InvokeFunctionCall{}, err
+	}
+	return rpc.InvokeFunctionCall{ContractAddress: contract, FunctionName: function, CallData: calldata}, nil
+}
+
+// encode serializes value as the Cairo type typeName
+func (a *ABI) encode(typeName string, value interface{}) ([]*felt.Felt, error) {
+	switch {
+	case cairoFeltTypes[typeName]:
+		f, err := toFelt(value)
+		if err != nil {
+			return nil, err
+		}
+		return []*felt.Felt{f}, nil
+	case typeName == cairoBool:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected bool, got %T", value)
+		}
+		if b {
+			return []*felt.Felt{utils.Uint64ToFelt(1)}, nil
+		}
+		return []*felt.Felt{utils.Uint64ToFelt(0)}, nil
+	case typeName == cairoUnit:
+		if value != nil {
+			return nil, fmt.Errorf("expected no value for (), got %T", value)
+		}
+		return nil, nil
+	case strings.HasPrefix(typeName, cairoIntegerPrefix):
+		return encodeUint(strings.TrimPrefix(typeName, cairoIntegerPrefix), value)
+	case strings.HasPrefix(typeName, cairoArrayPrefix), strings.HasPrefix(typeName, cairoSpanPrefix):
+		return a.encodeArray(genericArgument(typeName), value)
+	case typeName == cairoBytes:
+		if b, ok := value.([]byte); ok {
+			return encodeBytes(b), nil
+		}
+	}
+
+	if entry, ok := a.structs[typeName]; ok {
+		return a.encodeStruct(entry, value)
+	}
+	if entry, ok := a.enums[typeName]; ok {
+		return a.encodeEnum(entry, value)
+	}
+	return nil, fmt.Errorf("unsupported Cairo type %s", typeName)
+}
+
+// encodeUint serializes an unsigned integer; u256 takes two u128 felts (low, high)
+func encodeUint(name string, value interface{}) ([]*felt.Felt, error) {
+	bits, ok := cairoUintBits[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported integer type %s", name)
+	}
+	n, err := toBigInt(value)
+	if err != nil {
+		return nil, err
+	}
+	if n.Sign() < 0 || n.BitLen() > bits {
+		return nil, fmt.Errorf("%s does not fit in a %s", n, name)
+	}
+	if bits == 256 {
+		return U256Calldata(n), nil
+	}
+	return []*felt.Felt{utils.BigIntToFelt(n)}, nil
+}
+
+// encodeArray serializes a length followed by the elements
+func (a *ABI) encodeArray(elementType string, value interface{}) ([]*felt.Felt, error) {
+	v := reflect.ValueOf(value)
+	if value == nil || (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) {
+		return nil, fmt.Errorf("expected a slice for Array<%s>, got %T", elementType, value)
+	}
+	calldata := []*felt.Felt{utils.Uint64ToFelt(uint64(v.Len()))}
+	for i := 0; i < v.Len(); i++ {
+		encoded, err := a.encode(elementType, v.Index(i).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+		calldata = append(calldata, encoded...)
+	}
+	return calldata, nil
+}
+
+// encodeBytes serializes an alexandria Bytes: its size in bytes, then its u128 words as an array
+func encodeBytes(b []byte) []*felt.Felt {
+	words := BytesToU128Felts(b)
+	calldata := make([]*felt.Felt, 0, len(words)+2)
+	calldata = append(calldata, utils.Uint64ToFelt(uint64(len(b))), utils.Uint64ToFelt(uint64(len(words))))
+	return append(calldata, words...)
+}
+
+// encodeStruct serializes the members of a struct in declaration order
+func (a *ABI) encodeStruct(entry ABIEntry, value interface{}) ([]*felt.Felt, error) {
+	members, err := structMembers(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", entry.Name, err)
+	}
+	if len(members) != len(entry.Members) {
+		return nil, fmt.Errorf("%s has %d members (%s), got %d", entry.Name, len(entry.Members), paramNames(entry.Members), len(members))
+	}
+	var calldata []*felt.Felt
+	for _, member := range entry.Members {
+		memberValue, ok := members[member.Name]
+		if !ok {
+			return nil, fmt.Errorf("%s: missing member %s", entry.Name, member.Name)
+		}
+		encoded, err := a.encode(member.Type, memberValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", entry.Name, member.Name, err)
+		}
+		calldata = append(calldata, encoded...)
+	}
+	return calldata, nil
+}
+
+// encodeEnum serializes the variant index followed by the variant's value
+func (a *ABI) encodeEnum(entry ABIEntry, value interface{}) ([]*felt.Felt, error) {
+	enum, ok := value.(CairoEnum)
+	if !ok {
+		return nil, fmt.Errorf("expected a CairoEnum for %s, got %T", entry.Name, value)
+	}
+	for i, variant := range entry.Variants {
+		if variant.Name != enum.Variant {
+			continue
+		}
+		encoded, err := a.encode(variant.Type, enum.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s::%s: %w", entry.Name, variant.Name, err)
+		}
+		return append([]*felt.Felt{utils.Uint64ToFelt(uint64(i))}, encoded...), nil
+	}
+	return nil, fmt.Errorf("%s has no variant %s (%s)", entry.Name, enum.Variant, paramNames(entry.Variants))
+}
+
+// structMembers returns the member values of a map or a Go struct with `cairo` field tags
+func structMembers(value interface{}) (map[string]interface{}, error) {
+	if members, ok := value.(map[string]interface{}); ok {
+		return members, nil
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a map or a struct, got %T", value)
+	}
+	members := make(map[string]interface{})
+	for i := 0; i < v.NumField(); i++ {
+		if name := v.Type().Field(i).Tag.Get("cairo"); name != "" && name != "-" {
+			members[name] = v.Field(i).Interface()
+		}
+	}
+	return members, nil
+}
+
+// toFelt converts value to a felt, checking it is below the field prime
+func toFelt(value interface{}) (*felt.Felt, error) {
+	if f, ok := value.(*felt.Felt); ok {
+		if f == nil {
+			return nil, fmt.Errorf("nil felt")
+		}
+		return f, nil
+	}
+	n, err := toBigInt(value)
+	if err != nil {
+		return nil, err
+	}
+	if n.Sign() < 0 || n.Cmp(feltPrime) >= 0 {
+		return nil, fmt.Errorf("%s is not a felt", n)
+	}
+	return utils.BigIntToFelt(n), nil
+}
+
+// toBigInt converts a Go integer, *big.Int, *felt.Felt or numeric string (decimal or 0x hex)
+func toBigInt(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		if v == nil {
+			return nil, fmt.Errorf("nil integer")
+		}
+		return v, nil
+	case *felt.Felt:
+		if v == nil {
+			return nil, fmt.Errorf("nil felt")
+		}
+		return utils.FeltToBigInt(v), nil
+	case string:
+		n, ok := new(big.Int).SetString(v, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid number %q", v)
+		}
+		return n, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Int).SetUint64(rv.Uint()), nil
+	}
+	return nil, fmt.Errorf("expected a number, got %T", value)
+}
+
+// genericArgument returns T of a generic type name such as core::array::Array::<T>
+func genericArgument(typeName string) string {
+	start := strings.Index(typeName, "<")
+	return typeName[start+1 : len(typeName)-1]
+}
+
+// paramNames lists parameter names for error messages
+func paramNames(params []ABIParam) string {
+	names := make([]string, len(params))
+	for i, param := range params {
+		names[i] = param.Name
+	}
+	return strings.Join(names, ", ")
+}
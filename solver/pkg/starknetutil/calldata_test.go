@@ -0,0 +1,206 @@
+package starknetutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = `[
+  {
+    "type": "interface",
+    "name": "test::ITest",
+    "items": [
+      {
+        "type": "function",
+        "name": "transfer",
+        "inputs": [
+          {"name": "recipient", "type": "core::starknet::contract_address::ContractAddress"},
+          {"name": "amount", "type": "core::integer::u256"}
+        ],
+        "outputs": [{"type": "core::bool"}],
+        "state_mutability": "external"
+      }
+    ]
+  },
+  {
+    "type": "function",
+    "name": "submit",
+    "inputs": [
+      {"name": "order", "type": "test::Order"},
+      {"name": "tags", "type": "core::array::Span::<core::felt252>"},
+      {"name": "data", "type": "alexandria_bytes::bytes::Bytes"},
+      {"name": "mode", "type": "test::Mode"},
+      {"name": "strict", "type": "core::bool"}
+    ],
+    "outputs": [],
+    "state_mutability": "external"
+  },
+  {
+    "type": "struct",
+    "name": "test::Order",
+    "members": [
+      {"name": "deadline", "type": "core::integer::u32"},
+      {"name": "amounts", "type": "core::array::Array::<core::integer::u256>"}
+    ]
+  },
+  {
+    "type": "enum",
+    "name": "test::Mode",
+    "variants": [
+      {"name": "Fast", "type": "()"},
+      {"name": "Capped", "type": "core::integer::u64"}
+    ]
+  },
+  {
+    "type": "struct",
+    "name": "alexandria_bytes::bytes::Bytes",
+    "members": [
+      {"name": "size", "type": "core::integer::usize"},
+      {"name": "data", "type": "core::array::Array::<core::integer::u128>"}
+    ]
+  },
+  {
+    "type": "event",
+    "name": "test::Component::Opened",
+    "kind": "struct",
+    "members": [{"name": "id", "type": "core::felt252", "kind": "key"}]
+  },
+  {
+    "type": "event",
+    "name": "test::Component::Event",
+    "kind": "enum",
+    "variants": [{"name": "Opened", "type": "test::Component::Opened", "kind": "nested"}]
+  }
+]`
+
+func feltStrings(calldata []*felt.Felt) []string {
+	out := make([]string, len(calldata))
+	for i, f := range calldata {
+		out[i] = f.String()
+	}
+	return out
+}
+
+func TestABICalldata(t *testing.T) {
+	abi, err := ParseABI([]byte(testABI))
+	require.NoError(t, err)
+
+	t.Run("functions inside interfaces are found", func(t *testing.T) {
+		entry, ok := abi.Function("transfer")
+		require.True(t, ok)
+		assert.Len(t, entry.Inputs, 2)
+		_, ok = abi.Function("approve")
+		assert.False(t, ok)
+	})
+
+	t.Run("matches the ERC20 transfer calldata", func(t *testing.T) {
+		token, _ := utils.HexToFelt("0x0abc")
+		recipient, _ := utils.HexToFelt("0x0def")
+		amount := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(2), 128), big.NewInt(5))
+
+		call, err := abi.InvokeCall(token, "transfer", recipient, amount)
+		require.NoError(t, err)
+		assert.Equal(t, "transfer", call.FunctionName)
+		assert.Equal(t, token, call.ContractAddress)
+		assert.Equal(t, TransferCall(token, recipient, amount).CallData, call.CallData)
+	})
+
+	t.Run("nested types are serialized in declaration order", func(t *testing.T) {
+		type order struct {
+			Deadline uint32     `cairo:"deadline"`
+			Amounts  []*big.Int `cairo:"amounts"`
+			Note     string
+		}
+		data := make([]byte, 20)
+		data[0], data[19] = 0xaa, 0xbb
+
+		calldata, err := abi.Calldata("submit",
+			order{Deadline: 100, Amounts: []*big.Int{big.NewInt(7), big.NewInt(8)}},
+			[]string{"0x1", "0x2", "0x3"},
+			data,
+			CairoEnum{Variant: "Capped", Value: uint64(9)},
+			true,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"0x64", "0x2", "0x7", "0x0", "0x8", "0x0", // order: deadline, amounts
+			"0x3", "0x1", "0x2", "0x3", // tags
+			"0x14", "0x2", "0xaa000000000000000000000000000000", "0xbb000000000000000000000000", // data: size, words
+			"0x1", "0x9", // mode: Capped(9)
+			"0x1", // strict
+		}, feltStrings(calldata))
+	})
+
+	t.Run("maps, struct Bytes and unit variants", func(t *testing.T) {
+		calldata, err := abi.Calldata("submit",
+			map[string]interface{}{"deadline": 1, "amounts": []int{}},
+			[]*felt.Felt{},
+			map[string]interface{}{"size": 0, "data": []uint64{}},
+			CairoEnum{Variant: "Fast"},
+			false,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"0x1", "0x0", "0x0", "0x0", "0x0", "0x0", "0x0"}, feltStrings(calldata))
+	})
+
+	t.Run("invalid values are rejected before serialization", func(t *testing.T) {
+		validOrder := map[string]interface{}{"deadline": 1, "amounts": []int{}}
+		recipient := utils.Uint64ToFelt(1)
+		maxU256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+		tests := []struct {
+			name     string
+			function string
+			args     []interface{}
+			wantErr  string
+		}{
+			{"unknown function", "approve", nil, "function approve is not in the ABI"},
+			{"missing argument", "transfer", []interface{}{recipient}, "transfer takes 2 arguments (recipient, amount), got 1"},
+			{"u256 overflow", "transfer", []interface{}{recipient, new(big.Int).Add(maxU256, big.NewInt(1))}, "transfer: amount: "},
+			{"negative amount", "transfer", []interface{}{recipient, -1}, "does not fit in a u256"},
+			{"felt above the prime", "transfer", []interface{}{feltPrime, 1}, "transfer: recipient: "},
+			{"wrong Go type", "transfer", []interface{}{recipient, 1.5}, "expected a number, got float64"},
+			{"u32 overflow", "submit", []interface{}{map[string]interface{}{"deadline": uint64(1) << 32, "amounts": []int{}}, []int{}, []byte{}, CairoEnum{Variant: "Fast"}, true}, "test::Order.deadline: 4294967296 does not fit in a u32"},
+			{"missing member", "submit", []interface{}{map[string]interface{}{"deadline": 1, "amount": []int{}}, []int{}, []byte{}, CairoEnum{Variant: "Fast"}, true}, "test::Order: missing member amounts"},
+			{"not an array", "submit", []interface{}{validOrder, "0x1", []byte{}, CairoEnum{Variant: "Fast"}, true}, "expected a slice"},
+			{"bad array element", "submit", []interface{}{validOrder, []interface{}{"0x1", "xyz"}, []byte{}, CairoEnum{Variant: "Fast"}, true}, `tags: [1]: invalid number "xyz"`},
+			{"unknown variant", "submit", []interface{}{validOrder, []int{}, []byte{}, CairoEnum{Variant: "Slow"}, true}, "test::Mode has no variant Slow (Fast, Capped)"},
+			{"value for unit variant", "submit", []interface{}{validOrder, []int{}, []byte{}, CairoEnum{Variant: "Fast", Value: 1}, true}, "test::Mode::Fast"},
+			{"bool as number", "submit", []interface{}{validOrder, []int{}, []byte{}, CairoEnum{Variant: "Fast"}, 1}, "expected bool"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := abi.Calldata(tt.function, tt.args...)
+				assert.ErrorContains(t, err, tt.wantErr)
+			})
+		}
+	})
+
+	t.Run("unsupported types are reported", func(t *testing.T) {
+		abi, err := ParseABI([]byte(`[{"type": "function", "name": "f", "inputs": [{"name": "x", "type": "core::integer::i64"}]},
+			{"type": "function", "name": "g", "inputs": [{"name": "x", "type": "test::Unknown"}]}]`))
+		require.NoError(t, err)
+		_, err = abi.Calldata("f", 1)
+		assert.ErrorContains(t, err, "unsupported integer type i64")
+		_, err = abi.Calldata("g", 1)
+		assert.ErrorContains(t, err, "unsupported Cairo type test::Unknown")
+	})
+
+	t.Run("events by path or name", func(t *testing.T) {
+		assert.True(t, abi.HasEvent("Opened"))
+		assert.True(t, abi.HasEvent("test::Component::Opened"))
+		assert.False(t, abi.HasEvent("Event"), "enums only group the events")
+		assert.False(t, abi.HasEvent("Filled"))
+	})
+
+	t.Run("invalid ABI JSON", func(t *testing.T) {
+		_, err := ParseABI([]byte(`{"type": "function"}`))
+		assert.ErrorContains(t, err, "invalid contract ABI")
+		assert.Panics(t, func() { MustParseABI("not json") })
+	})
+}
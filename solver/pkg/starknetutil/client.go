@@ -0,0 +1,141 @@
+package starknetutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/receiptwait"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// DefaultBlockTime is the block time WaitForAcceptance paces its polling on when the network's is not known
+const DefaultBlockTime = time.Second
+
+// Client calls and invokes Starknet contracts, taking care of entry point selectors and
+// u256 (low, high) encoding. Calls only need the provider; invokes need the account.
+type Client struct {
+	Provider rpc.RPCProvider
+	Account  *account.Account
+}
+
+// NewClient creates a client on provider; acct may be nil for a read-only client
+func NewClient(provider rpc.RPCProvider, acct *account.Account) *Client {
+	return &Client{Provider: provider, Account: acct}
+}
+
+// NewAccountClient creates a client invoking through acct, reading through its provider
+func NewAccountClient(acct *account.Account) *Client {
+	return &Client{Provider: acct.Provider, Account: acct}
+}
+
+// Call calls the function of contract on the latest block
+func (c *Client) Call(ctx context.Context, contract *felt.Felt, function string, calldata ...*felt.Felt) ([]*felt.Felt, error) {
+	if calldata == nil {
+		calldata = []*felt.Felt{}
+	}
+	resp, err := c.Provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    contract,
+		EntryPointSelector: utils.GetSelectorFromNameFelt(function),
+		Calldata:           calldata,
+	}, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return nil, fmt.Errorf("%s call failed: %w", function, err)
+	}
+	return resp, nil
+}
+
+// CallU256 calls a function returning a u256
+func (c *Client) CallU256(ctx context.Context, contract *felt.Felt, function string, calldata ...*felt.Felt) (*big.Int, error) {
+	resp, err := c.Call(ctx, contract, function, calldata...)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp) < 2 {
+		return nil, fmt.Errorf("%s returned %d felts, expected a u256 (2 felts)", function, len(resp))
+	}
+	return U256FromFelts(resp[0], resp[1]), nil
+}
+
+// ClassABI returns the ABI of the Sierra class deployed at contract
+func (c *Client) ClassABI(ctx context.Context, contract *felt.Felt) (*ABI, error) {
+	class, err := c.Provider.ClassAt(ctx, rpc.WithBlockTag(rpc.BlockTagLatest), contract)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the class of %s: %w", contract.String(), err)
+	}
+	sierra, ok := class.(*contracts.ContractClass)
+	if !ok {
+		return nil, fmt.Errorf("class of %s is not a Sierra class (%T)", contract.String(), class)
+	}
+	return ParseABI([]byte(sierra.ABI))
+}
+
+// Invoke sends the calls as one invoke transaction from the account and returns its hash
+func (c *Client) Invoke(ctx context.Context, calls ...rpc.InvokeFunctionCall) (*felt.Felt, error) {
+	if c.Account == nil {
+		return nil, fmt.Errorf("invoke needs an account")
+	}
+	resp, err := c.Account.BuildAndSendInvokeTxn(ctx, calls, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send invoke: %w", err)
+	}
+	return resp.Hash, nil
+}
+
+// WaitForAcceptance waits until txHash has a receipt, failing if it reverted. Polling backs off
+// from a fraction of blockTime, the network's expected block time (see receiptwait).
+func (c *Client) WaitForAcceptance(ctx context.Context, txHash *felt.Felt, blockTime time.Duration) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	receipt, err := receiptwait.Starknet(ctx, c.Provider, txHash, blockTime)
+	switch {
+	case err != nil && ctx.Err() != nil:
+		return nil, err
+	case err != nil:
+		return nil, fmt.Errorf("failed to get receipt for %s: %w", txHash.String(), err)
+	case receipt.ExecutionStatus == rpc.TxnExecutionStatusREVERTED:
+		return receipt, fmt.Errorf("starknet tx %s reverted: %s", txHash.String(), receipt.RevertReason)
+	}
+	return receipt, nil
+}
+
+// BalanceOf returns balanceOf(owner) of an ERC20 token
+func (c *Client) BalanceOf(ctx context.Context, token, owner *felt.Felt) (*big.Int, error) {
+	return c.CallU256(ctx, token, "balanceOf", owner)
+}
+
+// Allowance returns allowance(owner, spender) of an ERC20 token
+func (c *Client) Allowance(ctx context.Context, token, owner, spender *felt.Felt) (*big.Int, error) {
+	return c.CallU256(ctx, token, "allowance", owner, spender)
+}
+
+// Approve invokes approve(spender, amount) on an ERC20 token and returns the tx hash
+func (c *Client) Approve(ctx context.Context, token, spender *felt.Felt, amount *big.Int) (*felt.Felt, error) {
+	return c.Invoke(ctx, ApproveCall(token, spender, amount))
+}
+
+// Transfer invokes transfer(recipient, amount) on an ERC20 token and returns the tx hash
+func (c *Client) Transfer(ctx context.Context, token, recipient *felt.Felt, amount *big.Int) (*felt.Felt, error) {
+	return c.Invoke(ctx, TransferCall(token, recipient, amount))
+}
+
+// ApproveCall builds approve(spender: ContractAddress, amount: u256), e.g. to batch it into a multicall
+func ApproveCall(token, spender *felt.Felt, amount *big.Int) rpc.InvokeFunctionCall {
+	return rpc.InvokeFunctionCall{
+		ContractAddress: token,
+		FunctionName:    "approve",
+		CallData:        append([]*felt.Felt{spender}, U256Calldata(amount)...),
+	}
+}
+
+// TransferCall builds transfer(recipient: ContractAddress, amount: u256)
+func TransferCall(token, recipient *felt.Felt, amount *big.Int) rpc.InvokeFunctionCall {
+	return rpc.InvokeFunctionCall{
+		ContractAddress: token,
+		FunctionName:    "transfer",
+		CallData:        append([]*felt.Felt{recipient}, U256Calldata(amount)...),
+	}
+}
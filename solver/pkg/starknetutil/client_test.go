@@ -0,0 +1,148 @@
+package starknetutil
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAccount returns an account with private key 0x1 on backend
+func testAccount(t *testing.T, backend *chainmock.StarknetBackend) *account.Account {
+	t.Helper()
+	pub := "0x2c2b8f559e1221468140ad7b2352b1a5be32660d0bf1a3ae3a054a4ec5254e4"
+	ks := account.NewMemKeystore()
+	ks.Put(pub, big.NewInt(1))
+	acct, err := account.NewAccount(backend, new(felt.Felt).SetUint64(0xacc), pub, ks, account.CairoV2)
+	require.NoError(t, err)
+	return acct
+}
+
+func TestClient(t *testing.T) {
+	token := new(felt.Felt).SetUint64(0x1234)
+	owner := new(felt.Felt).SetUint64(0xa11ce)
+	spender := new(felt.Felt).SetUint64(0x5e11e4)
+	large, _ := new(big.Int).SetString("340282366920938463463374607431768211457", 10) // 2^128 + 1
+
+	t.Run("CallU256 decodes low and high felts", func(t *testing.T) {
+		backend := chainmock.NewStarknetBackend()
+		backend.HandleCall(token, "balanceOf", func(call rpc.FunctionCall) ([]*felt.Felt, error) {
+			assert.Equal(t, []*felt.Felt{owner}, call.Calldata)
+			return U256Calldata(large), nil
+		})
+
+		balance, err := NewClient(backend, nil).BalanceOf(context.Background(), token, owner)
+		require.NoError(t, err)
+		assert.Equal(t, large, balance)
+	})
+
+	t.Run("Allowance passes owner and spender", func(t *testing.T) {
+		backend := chainmock.NewStarknetBackend()
+		backend.HandleCall(token, "allowance", func(call rpc.FunctionCall) ([]*felt.Felt, error) {
+			assert.Equal(t, []*felt.Felt{owner, spender}, call.Calldata)
+			return U256Calldata(big.NewInt(77)), nil
+		})
+
+		allowance, err := NewClient(backend, nil).Allowance(context.Background(), token, owner, spender)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(77), allowance)
+	})
+
+	t.Run("CallU256 rejects short responses", func(t *testing.T) {
+		backend := chainmock.NewStarknetBackend()
+		backend.HandleCall(token, "balanceOf", func(rpc.FunctionCall) ([]*felt.Felt, error) {
+			return []*felt.Felt{new(felt.Felt).SetUint64(1)}, nil
+		})
+
+		_, err := NewClient(backend, nil).BalanceOf(context.Background(), token, owner)
+		assert.ErrorContains(t, err, "expected a u256")
+	})
+
+	t.Run("call errors name the function", func(t *testing.T) {
+		backend := chainmock.NewStarknetBackend()
+		backend.FailNext("Call", errors.New("rpc down"))
+
+		_, err := NewClient(backend, nil).Call(context.Background(), token, "symbol")
+		assert.ErrorContains(t, err, "symbol call failed: rpc down")
+	})
+
+	t.Run("Approve invokes and WaitForAcceptance returns the receipt", func(t *testing.T) {
+		backend := chainmock.NewStarknetBackend()
+		client := NewAccountClient(testAccount(t, backend))
+
+		txHash, err := client.Approve(context.Background(), token, spender, large)
+		require.NoError(t, err)
+		receipt, err := client.WaitForAcceptance(context.Background(), txHash, time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, rpc.TxnExecutionStatusSUCCEEDED, receipt.ExecutionStatus)
+
+		invokes := backend.Invokes()
+		require.Len(t, invokes, 1)
+		// Account calldata: call count, then to, selector, calldata length, calldata
+		calldata := invokes[0].Calldata
+		assert.Equal(t, token, calldata[1])
+		assert.Equal(t, append([]*felt.Felt{spender}, U256Calldata(large)...), calldata[4:])
+	})
+
+	t.Run("Transfer sends one invoke per call", func(t *testing.T) {
+		backend := chainmock.NewStarknetBackend()
+		client := NewAccountClient(testAccount(t, backend))
+
+		for range 2 {
+			_, err := client.Transfer(context.Background(), token, owner, big.NewInt(5))
+			require.NoError(t, err)
+		}
+		assert.Len(t, backend.Invokes(), 2)
+	})
+
+	t.Run("WaitForAcceptance fails on revert", func(t *testing.T) {
+		backend := chainmock.NewStarknetBackend()
+		backend.OnInvoke(func(*rpc.BroadcastInvokeTxnV3) rpc.TxnExecutionStatus { return rpc.TxnExecutionStatusREVERTED })
+		client := NewAccountClient(testAccount(t, backend))
+
+		txHash, err := client.Invoke(context.Background(), TransferCall(token, owner, big.NewInt(5)))
+		require.NoError(t, err)
+		_, err = client.WaitForAcceptance(context.Background(), txHash, time.Millisecond)
+		assert.ErrorContains(t, err, "reverted")
+	})
+
+	t.Run("WaitForAcceptance polls until the receipt exists", func(t *testing.T) {
+		backend := chainmock.NewStarknetBackend()
+		backend.FailNext("TransactionReceipt", rpc.ErrHashNotFound)
+		client := NewAccountClient(testAccount(t, backend))
+
+		txHash, err := client.Invoke(context.Background(), TransferCall(token, owner, big.NewInt(5)))
+		require.NoError(t, err)
+		_, err = client.WaitForAcceptance(context.Background(), txHash, time.Millisecond)
+		assert.NoError(t, err)
+	})
+
+	t.Run("ClassABI parses the ABI of the deployed Sierra class", func(t *testing.T) {
+		backend := chainmock.NewStarknetBackend()
+		backend.SetClass(token, &contracts.ContractClass{ABI: contracts.NestedString(testABI)})
+		backend.SetClass(owner, &contracts.DeprecatedContractClass{})
+		client := NewClient(backend, nil)
+
+		abi, err := client.ClassABI(context.Background(), token)
+		require.NoError(t, err)
+		assert.True(t, abi.HasEvent("Opened"))
+		_, err = client.ClassABI(context.Background(), owner)
+		assert.ErrorContains(t, err, "is not a Sierra class")
+		_, err = client.ClassABI(context.Background(), spender)
+		assert.ErrorContains(t, err, "failed to get the class")
+	})
+
+	t.Run("read-only client cannot invoke", func(t *testing.T) {
+		_, err := NewClient(chainmock.NewStarknetBackend(), nil).Invoke(context.Background(), TransferCall(token, owner, big.NewInt(5)))
+		assert.ErrorContains(t, err, "needs an account")
+	})
+}
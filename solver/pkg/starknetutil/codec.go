@@ -0,0 +1,117 @@
+package starknetutil
+
+// Module: Cairo value codecs shared by the solver and tools
+// - u256 <-> (low, high) felts
+// - Solidity bytes32 orderIDs <-> Starknet u256 orderIDs
+// - raw bytes <-> big-endian u128 word arrays (Cairo Bytes)
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+)
+
+// ConvertBigIntToU256Felts converts a big.Int to two felts, one for the low 128 bits and one for the high 128 bits
+func ConvertBigIntToU256Felts(value *big.Int) (low, high *felt.Felt) {
+	// Convert to uint256 for better performance
+	u := ToUint256(value)
+
+	// Create mask for lower 128 bits
+	lowerMask := uint256.NewInt(1)
+	lowerMask.Lsh(lowerMask, U128BitShift)
+	lowerMask.SubUint64(lowerMask, 1)
+
+	// Extract low and high parts
+	lowPart := new(uint256.Int)
+	lowPart.And(u, lowerMask)
+	highPart := new(uint256.Int)
+	highPart.Rsh(u, U128BitShift)
+
+	// Convert back to felts
+	low = utils.BigIntToFelt(lowPart.ToBig())
+	high = utils.BigIntToFelt(highPart.ToBig())
+	return low, high
+}
+
+// U256Calldata encodes value as the two felts (low, high) of a Cairo u256 argument
+func U256Calldata(value *big.Int) []*felt.Felt {
+	low, high := ConvertBigIntToU256Felts(value)
+	return []*felt.Felt{low, high}
+}
+
+// U256FromFelts decodes a Cairo u256 from its low and high felts
+func U256FromFelts(low, high *felt.Felt) *big.Int {
+	value := new(big.Int).Lsh(utils.FeltToBigInt(high), U128BitShift)
+	return value.Or(value, utils.FeltToBigInt(low))
+}
+
+// ConvertSolidityOrderIDForStarknet converts a Solidity-style orderID (bytes32) into the low and high felts of a Starknet u256 orderID
+// Note: Assigns the left 16 bytes to the high felt and the right 16 bytes to the low felt
+func ConvertSolidityOrderIDForStarknet(orderID string) (low, high *felt.Felt, err error) {
+	orderBN := utils.HexToBN(orderID)
+	if orderBN == nil {
+		return nil, nil, fmt.Errorf("invalid hex string: %s", orderID)
+	}
+
+	orderBytes := orderBN.Bytes()
+	if len(orderBytes) < Bytes32Length {
+		paddedBytes := make([]byte, Bytes32Length)
+		copy(paddedBytes[Bytes32Length-len(orderBytes):], orderBytes)
+		orderBytes = paddedBytes
+	}
+
+	left16 := utils.BigIntToFelt(new(big.Int).SetBytes(orderBytes[0:16]))
+	right16 := utils.BigIntToFelt(new(big.Int).SetBytes(orderBytes[16:32]))
+
+	low = right16
+	high = left16
+
+	return low, high, nil
+}
+
+// ConvertStarknetOrderIDForSolidity is the inverse of ConvertSolidityOrderIDForStarknet: it joins the
+// low and high felts of a u256 orderID into a 0x-prefixed bytes32 hex string
+func ConvertStarknetOrderIDForSolidity(low, high *felt.Felt) (string, error) {
+	if utils.FeltToBigInt(low).BitLen() > U128BitShift || utils.FeltToBigInt(high).BitLen() > U128BitShift {
+		return "", fmt.Errorf("orderID halves do not fit in u128: low %s high %s", low, high)
+	}
+	return common.BigToHash(U256FromFelts(low, high)).Hex(), nil
+}
+
+// BytesToU128Felts converts bytes to u128 felts for Cairo
+func BytesToU128Felts(b []byte) []*felt.Felt {
+	words := make([]*felt.Felt, 0, (len(b)+Bytes16Length-1)/Bytes16Length)
+	for i := 0; i < len(b); i += Bytes16Length {
+		end := i + Bytes16Length
+		chunk := make([]byte, Bytes16Length)
+		if end > len(b) {
+			copy(chunk, b[i:])
+		} else {
+			copy(chunk, b[i:end])
+		}
+		// Keep big-endian u128 words; Cairo decoders reconstruct bytes in order
+		words = append(words, utils.BigIntToFelt(new(big.Int).SetBytes(chunk)))
+	}
+	return words
+}
+
+// U128FeltsToBytes is the inverse of BytesToU128Felts: it joins the big-endian u128 words and
+// trims the zero padding of the last word down to size bytes
+func U128FeltsToBytes(words []*felt.Felt, size int) ([]byte, error) {
+	if size < 0 || size > len(words)*Bytes16Length {
+		return nil, fmt.Errorf("size %d does not fit in %d u128 words", size, len(words))
+	}
+	b := make([]byte, len(words)*Bytes16Length)
+	for i, word := range words {
+		value := utils.FeltToBigInt(word)
+		if value.BitLen() > U128BitShift {
+			return nil, fmt.Errorf("word %d does not fit in a u128: %s", i, value)
+		}
+		value.FillBytes(b[i*Bytes16Length : (i+1)*Bytes16Length])
+	}
+	return b[:size], nil
+}
@@ -0,0 +1,95 @@
+package starknetutil
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// u256Boundaries returns 0, 1 and every 2^k-1, 2^k for k in [1, 256), plus 2^256-1
+func u256Boundaries() []*big.Int {
+	values := []*big.Int{big.NewInt(0), big.NewInt(1)}
+	for k := uint(1); k < 256; k++ {
+		power := new(big.Int).Lsh(big.NewInt(1), k)
+		values = append(values, new(big.Int).Sub(power, big.NewInt(1)), power)
+	}
+	maxU256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	return append(values, maxU256)
+}
+
+func TestU256RoundTrip(t *testing.T) {
+	for _, value := range u256Boundaries() {
+		low, high := ConvertBigIntToU256Felts(value)
+		assert.LessOrEqual(t, utils.FeltToBigInt(low).BitLen(), U128BitShift, "low of %s", value)
+		assert.LessOrEqual(t, utils.FeltToBigInt(high).BitLen(), U128BitShift, "high of %s", value)
+		assert.Equal(t, value, U256FromFelts(low, high), "value %s", value)
+
+		calldata := U256Calldata(value)
+		require.Len(t, calldata, 2)
+		assert.Equal(t, []*felt.Felt{low, high}, calldata)
+	}
+}
+
+func TestOrderIDRoundTrip(t *testing.T) {
+	for _, value := range u256Boundaries() {
+		orderID := common.BigToHash(value).Hex()
+
+		low, high, err := ConvertSolidityOrderIDForStarknet(orderID)
+		require.NoError(t, err)
+		got, err := ConvertStarknetOrderIDForSolidity(low, high)
+		require.NoError(t, err)
+		assert.Equal(t, orderID, got)
+	}
+
+	t.Run("short orderIDs are left-padded", func(t *testing.T) {
+		low, high, err := ConvertSolidityOrderIDForStarknet("0x1234")
+		require.NoError(t, err)
+		got, err := ConvertStarknetOrderIDForSolidity(low, high)
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToHash("0x1234").Hex(), got)
+	})
+
+	t.Run("halves wider than u128 are rejected", func(t *testing.T) {
+		wide := new(felt.Felt).SetBytes(new(big.Int).Lsh(big.NewInt(1), U128BitShift).Bytes())
+		_, err := ConvertStarknetOrderIDForSolidity(wide, new(felt.Felt))
+		assert.Error(t, err)
+		_, err = ConvertStarknetOrderIDForSolidity(new(felt.Felt), wide)
+		assert.Error(t, err)
+	})
+}
+
+func TestBytesRoundTrip(t *testing.T) {
+	for size := 0; size <= 3*Bytes32Length; size++ {
+		t.Run(fmt.Sprintf("%d bytes", size), func(t *testing.T) {
+			for _, fill := range []byte{0x00, 0x01, 0xff} {
+				input := bytes.Repeat([]byte{fill}, size)
+				words := BytesToU128Felts(input)
+				assert.Len(t, words, (size+Bytes16Length-1)/Bytes16Length)
+
+				got, err := U128FeltsToBytes(words, size)
+				require.NoError(t, err)
+				assert.Equal(t, input, got)
+			}
+		})
+	}
+
+	t.Run("size beyond the words is rejected", func(t *testing.T) {
+		_, err := U128FeltsToBytes(BytesToU128Felts([]byte{1}), Bytes16Length+1)
+		assert.Error(t, err)
+		_, err = U128FeltsToBytes(nil, -1)
+		assert.Error(t, err)
+	})
+
+	t.Run("words wider than u128 are rejected", func(t *testing.T) {
+		wide := new(felt.Felt).SetBytes(new(big.Int).Lsh(big.NewInt(1), U128BitShift).Bytes())
+		_, err := U128FeltsToBytes([]*felt.Felt{wide}, Bytes16Length)
+		assert.Error(t, err)
+	})
+}
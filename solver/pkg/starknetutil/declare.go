@@ -0,0 +1,170 @@
+package starknetutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// DefaultFeeMultiplier is the safety factor applied to estimated resource amounts and prices
+const DefaultFeeMultiplier = 1.5
+
+// ErrFeeAboveCap is returned when a transaction's estimated fee is above the caller's max fee
+var ErrFeeAboveCap = errors.New("estimated fee above max fee")
+
+// DeclareOptions sets the fees of a declare
+type DeclareOptions struct {
+	// Multiplier is applied to the estimated resource amounts and prices; <= 0 uses DefaultFeeMultiplier
+	Multiplier float64
+	// MaxFee caps what the declare can be charged, in fri; nil means no cap
+	MaxFee *big.Int
+}
+
+// DeclareResult is a sent declare transaction
+type DeclareResult struct {
+	Hash      string
+	ClassHash string
+	// EstimatedFee is the estimated overall fee and MaxFee the most the resource bounds allow, in fri
+	EstimatedFee *big.Int
+	MaxFee       *big.Int
+}
+
+// Declare sends a v3 declare of a contract class from the account. The resource bounds come from a
+// fee estimate with opts.Multiplier of headroom, lowered to fit opts.MaxFee when set; a declare whose
+// estimate alone is above opts.MaxFee is not sent and fails with ErrFeeAboveCap.
+func (c *Client) Declare(ctx context.Context, casmClass *contracts.CasmClass, contractClass *contracts.ContractClass, opts DeclareOptions) (*DeclareResult, error) {
+	if c.Account == nil {
+		return nil, fmt.Errorf("declare needs an account")
+	}
+	nonce, err := c.Account.Nonce(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account nonce: %w", err)
+	}
+
+	// Sign with zero resource bounds to estimate the fee
+	zeroBounds := &rpc.ResourceBoundsMapping{
+		L1Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		L1DataGas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		L2Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+	}
+	txn, err := utils.BuildDeclareTxn(c.Account.Address, casmClass, contractClass, nonce, zeroBounds, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build declare: %w", err)
+	}
+	if err := c.Account.SignDeclareTransaction(ctx, txn); err != nil {
+		return nil, fmt.Errorf("failed to sign declare for estimation: %w", err)
+	}
+
+	txnOpts := account.TxnOptions{}
+	estimates, err := c.Provider.EstimateFee(ctx, []rpc.BroadcastTxn{txn}, txnOpts.SimulationFlags(), txnOpts.BlockID())
+	if err != nil {
+		return nil, fmt.Errorf("fee estimation failed: %w", err)
+	}
+	if len(estimates) == 0 {
+		return nil, fmt.Errorf("fee estimation returned no results")
+	}
+	bounds, maxFee, err := DeclareResourceBounds(estimates[0], opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-sign with the resource bounds, as they are part of the txn hash
+	txn.ResourceBounds = bounds
+	txn.Version = rpc.TransactionV3
+	if err := c.Account.SignDeclareTransaction(ctx, txn); err != nil {
+		return nil, fmt.Errorf("failed to sign declare: %w", err)
+	}
+	resp, err := c.Provider.AddDeclareTransaction(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+	return &DeclareResult{
+		Hash:         resp.Hash.String(),
+		ClassHash:    resp.ClassHash.String(),
+		EstimatedFee: estimates[0].OverallFee.BigInt(new(big.Int)),
+		MaxFee:       maxFee,
+	}, nil
+}
+
+// DeclareResourceBounds returns the resource bounds of a fee estimate with opts.Multiplier of headroom
+// and the max fee they allow. Over opts.MaxFee, the headroom is reduced until the bounds fit.
+func DeclareResourceBounds(estimate rpc.FeeEstimation, opts DeclareOptions) (*rpc.ResourceBoundsMapping, *big.Int, error) {
+	multiplier := opts.Multiplier
+	if multiplier <= 0 {
+		multiplier = DefaultFeeMultiplier
+	}
+	estimated := estimate.OverallFee.BigInt(new(big.Int))
+	if opts.MaxFee != nil && estimated.Cmp(opts.MaxFee) > 0 {
+		return nil, nil, fmt.Errorf("%w: estimated %s fri, max %s fri", ErrFeeAboveCap, estimated, opts.MaxFee)
+	}
+
+	bounds := utils.FeeEstToResBoundsMap(estimate, multiplier)
+	maxFee, err := MaxFeeFromBounds(bounds)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.MaxFee == nil || maxFee.Cmp(opts.MaxFee) <= 0 {
+		return bounds, maxFee, nil
+	}
+
+	// Both amounts and prices are multiplied, so the max fee grows with the square of the multiplier
+	ratio, _ := new(big.Rat).SetFrac(opts.MaxFee, maxFee).Float64()
+	bounds = utils.FeeEstToResBoundsMap(estimate, multiplier*math.Sqrt(ratio))
+	if maxFee, err = MaxFeeFromBounds(bounds); err != nil {
+		return nil, nil, err
+	}
+	if maxFee.Cmp(opts.MaxFee) > 0 {
+		// Rounding up of the scaled bounds; no headroom left
+		bounds = utils.FeeEstToResBoundsMap(estimate, 1)
+		if maxFee, err = MaxFeeFromBounds(bounds); err != nil {
+			return nil, nil, err
+		}
+	}
+	return bounds, maxFee, nil
+}
+
+// MaxFeeFromBounds returns the most the sequencer can charge for the given resource bounds, in fri
+func MaxFeeFromBounds(bounds *rpc.ResourceBoundsMapping) (*big.Int, error) {
+	total := new(big.Int)
+	for name, b := range map[string]rpc.ResourceBounds{
+		"l1_gas":      bounds.L1Gas,
+		"l1_data_gas": bounds.L1DataGas,
+		"l2_gas":      bounds.L2Gas,
+	} {
+		amount, err := b.MaxAmount.ToUint64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s max amount: %w", name, err)
+		}
+		price, ok := new(big.Int).SetString(string(b.MaxPricePerUnit), 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s max price per unit: %s", name, b.MaxPricePerUnit)
+		}
+		total.Add(total, new(big.Int).Mul(new(big.Int).SetUint64(amount), price))
+	}
+	return total, nil
+}
+
+// DeclareFailureHint explains common declare failures to the operator, "" for other errors
+func DeclareFailureHint(err error) string {
+	var rpcErr *rpc.RPCError
+	switch {
+	case errors.Is(err, ErrFeeAboveCap):
+		return "the estimated fee is above --max-fee; raise it or wait for lower gas prices"
+	case !errors.As(err, &rpcErr):
+		return ""
+	case rpcErr.Code == rpc.ErrInsufficientAccountBalance.Code:
+		return "the account's STRK balance is below the declare's max fee; fund the account or lower --fee-multiplier"
+	case rpcErr.Code == rpc.ErrInsufficientResourcesForValidate.Code:
+		return "the resource bounds don't cover the fee; raise --max-fee or --fee-multiplier"
+	case rpcErr.Code == rpc.ErrClassAlreadyDeclared.Code:
+		return "the class is already declared"
+	}
+	return ""
+}
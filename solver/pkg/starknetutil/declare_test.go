@@ -0,0 +1,67 @@
+package starknetutil
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxFeeFromBounds(t *testing.T) {
+	bounds := &rpc.ResourceBoundsMapping{
+		L1Gas:     rpc.ResourceBounds{MaxAmount: "0x2", MaxPricePerUnit: "0x10"},
+		L1DataGas: rpc.ResourceBounds{MaxAmount: "0x1", MaxPricePerUnit: "0x1"},
+		L2Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x5"},
+	}
+	maxFee, err := MaxFeeFromBounds(bounds)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(33), maxFee)
+
+	bounds.L2Gas.MaxPricePerUnit = "price"
+	_, err = MaxFeeFromBounds(bounds)
+	assert.ErrorContains(t, err, "l2_gas")
+}
+
+func TestDeclareResourceBounds(t *testing.T) {
+	// 1,000,000 L2 gas at 10 fri: 10,000,000 fri
+	estimate := rpc.FeeEstimation{FeeEstimationCommon: rpc.FeeEstimationCommon{
+		L1GasConsumed:     new(felt.Felt),
+		L1GasPrice:        new(felt.Felt).SetUint64(1),
+		L1DataGasConsumed: new(felt.Felt),
+		L1DataGasPrice:    new(felt.Felt).SetUint64(1),
+		L2GasConsumed:     new(felt.Felt).SetUint64(1_000_000),
+		L2GasPrice:        new(felt.Felt).SetUint64(10),
+		OverallFee:        new(felt.Felt).SetUint64(10_000_000),
+	}}
+
+	t.Run("default headroom without a cap", func(t *testing.T) {
+		_, maxFee, err := DeclareResourceBounds(estimate, DeclareOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(22_500_000), maxFee, "1.5x amount and 1.5x price")
+	})
+
+	t.Run("headroom is reduced to fit the max fee", func(t *testing.T) {
+		_, maxFee, err := DeclareResourceBounds(estimate, DeclareOptions{MaxFee: big.NewInt(15_000_000)})
+		require.NoError(t, err)
+		assert.LessOrEqual(t, maxFee.Cmp(big.NewInt(15_000_000)), 0, "max fee %s", maxFee)
+		assert.GreaterOrEqual(t, maxFee.Cmp(big.NewInt(10_000_000)), 0, "max fee %s", maxFee)
+	})
+
+	t.Run("estimates above the max fee fail", func(t *testing.T) {
+		_, _, err := DeclareResourceBounds(estimate, DeclareOptions{MaxFee: big.NewInt(9_999_999)})
+		assert.ErrorIs(t, err, ErrFeeAboveCap)
+		assert.ErrorContains(t, err, "estimated 10000000 fri, max 9999999 fri")
+	})
+}
+
+func TestDeclareFailureHint(t *testing.T) {
+	assert.Contains(t, DeclareFailureHint(fmt.Errorf("declare: %w", rpc.ErrInsufficientAccountBalance)), "fund the account")
+	assert.Contains(t, DeclareFailureHint(rpc.ErrInsufficientResourcesForValidate), "--max-fee")
+	assert.Contains(t, DeclareFailureHint(fmt.Errorf("%w: estimated 2 fri, max 1 fri", ErrFeeAboveCap)), "raise it")
+	assert.Equal(t, "the class is already declared", DeclareFailureHint(rpc.ErrClassAlreadyDeclared))
+	assert.Empty(t, DeclareFailureHint(fmt.Errorf("connection refused")))
+}
@@ -25,6 +25,9 @@ const (
 	TokenDecimals = 18
 )
 
+// STRKTokenAddress is the STRK fee token, at the same address on every Starknet network
+const STRKTokenAddress = "0x04718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d"
+
 // Helper functions for uint256 conversion
 // ToUint256 converts *big.Int to uint256.Int
 func ToUint256(bi *big.Int) *uint256.Int {
@@ -49,8 +52,7 @@ var StarknetERC20ABI = map[string]string{
 }
 
 // ERC20Balance gets the ERC20 token balance for a given address on Starknet
-func ERC20Balance(provider *rpc.Provider, tokenAddress, ownerAddress string) (*big.Int, error) {
-	// Convert addresses to felt
+func ERC20Balance(provider rpc.RPCProvider, tokenAddress, ownerAddress string) (*big.Int, error) {
 	tokenAddrFelt, err := utils.HexToFelt(tokenAddress)
 	if err != nil {
 		return nil, fmt.Errorf("invalid token address: %w", err)
@@ -61,33 +63,11 @@ func ERC20Balance(provider *rpc.Provider, tokenAddress, ownerAddress string) (*b
 		return nil, fmt.Errorf("invalid owner address: %w", err)
 	}
 
-	// Build the balanceOf function call
-	balanceCall := rpc.FunctionCall{
-		ContractAddress:    tokenAddrFelt,
-		EntryPointSelector: utils.GetSelectorFromNameFelt("balanceOf"),
-		Calldata:           []*felt.Felt{ownerAddrFelt},
-	}
-
-	// Call the contract to get balance
-	resp, err := provider.Call(context.Background(), balanceCall, rpc.WithBlockTag("latest"))
-	if err != nil {
-		return nil, fmt.Errorf("failed to call balanceOf: %w", err)
-	}
-
-	if len(resp) == 0 {
-		return nil, fmt.Errorf("no response from balanceOf call")
-	}
-
-	// Convert felt response to big.Int
-	balanceFelt := resp[0]
-	balanceBigInt := utils.FeltToBigInt(balanceFelt)
-
-	return balanceBigInt, nil
+	return NewClient(provider, nil).BalanceOf(context.Background(), tokenAddrFelt, ownerAddrFelt)
 }
 
 // ERC20Allowance gets the ERC20 token allowance for a given owner and spender on Starknet
-func ERC20Allowance(provider *rpc.Provider, tokenAddress, ownerAddress, spenderAddress string) (*big.Int, error) {
-	// Convert addresses to felt
+func ERC20Allowance(provider rpc.RPCProvider, tokenAddress, ownerAddress, spenderAddress string) (*big.Int, error) {
 	tokenAddrFelt, err := utils.HexToFelt(tokenAddress)
 	if err != nil {
 		return nil, fmt.Errorf("invalid token address: %w", err)
@@ -103,42 +83,11 @@ func ERC20Allowance(provider *rpc.Provider, tokenAddress, ownerAddress, spenderA
 		return nil, fmt.Errorf("invalid spender address: %w", err)
 	}
 
-	// Build the allowance function call
-	allowanceCall := rpc.FunctionCall{
-		ContractAddress:    tokenAddrFelt,
-		EntryPointSelector: utils.GetSelectorFromNameFelt("allowance"),
-		Calldata:           []*felt.Felt{ownerAddrFelt, spenderAddrFelt},
-	}
-
-	// Call the contract to get allowance
-	resp, err := provider.Call(context.Background(), allowanceCall, rpc.WithBlockTag("latest"))
-	if err != nil {
-		return nil, fmt.Errorf("failed to call allowance: %w", err)
-	}
-
-	if len(resp) < 2 {
-		return nil, fmt.Errorf("invalid allowance result length: expected 2 felts, got %d", len(resp))
-	}
-
-	// Convert two felts (low, high) back to u256
-	low := utils.FeltToBigInt(resp[0])
-	high := utils.FeltToBigInt(resp[1])
-
-	// Use uint256 for better performance
-	lowU := ToUint256(low)
-	highU := ToUint256(high)
-
-	// Combine low and high into u256: (high << 128) | low
-	result := new(uint256.Int)
-	result.Lsh(highU, U128BitShift)
-	result.Or(result, lowU)
-
-	return result.ToBig(), nil
+	return NewClient(provider, nil).Allowance(context.Background(), tokenAddrFelt, ownerAddrFelt, spenderAddrFelt)
 }
 
 // ERC20Approve creates an approve transaction for ERC20 tokens on Starknet
 func ERC20Approve(tokenAddress, spenderAddress string, amount *big.Int) (*rpc.InvokeFunctionCall, error) {
-	// Convert addresses to felt
 	tokenAddrFelt, err := utils.HexToFelt(tokenAddress)
 	if err != nil {
 		return nil, fmt.Errorf("invalid token address: %w", err)
@@ -149,33 +98,51 @@ func ERC20Approve(tokenAddress, spenderAddress string, amount *big.Int) (*rpc.In
 		return nil, fmt.Errorf("invalid spender address: %w", err)
 	}
 
-	// Convert amount to two felts (low, high) for u256 using uint256 for better performance
-	amountU := ToUint256(amount)
-
-	// Create mask for lower 128 bits
-	lowerMask := uint256.NewInt(1)
-	lowerMask.Lsh(lowerMask, U128BitShift)
-	lowerMask.SubUint64(lowerMask, 1)
+	invoke := ApproveCall(tokenAddrFelt, spenderAddrFelt, amount)
+	return &invoke, nil
+}
 
-	// Extract low and high parts
-	low128 := new(uint256.Int)
-	low128.And(amountU, lowerMask)
-	high128 := new(uint256.Int)
-	high128.Rsh(amountU, U128BitShift)
+// ERC20Metadata reads the symbol and decimals of an ERC20 token on Starknet. The symbol may be
+// a legacy short string (one felt) or a Cairo ByteArray.
+func ERC20Metadata(provider rpc.RPCProvider, tokenAddress string) (string, uint8, error) {
+	tokenAddrFelt, err := utils.HexToFelt(tokenAddress)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid token address: %w", err)
+	}
 
-	lowFelt := utils.BigIntToFelt(low128.ToBig())
-	highFelt := utils.BigIntToFelt(high128.ToBig())
+	client := NewClient(provider, nil)
+	call := func(entryPoint string) ([]*felt.Felt, error) {
+		resp, err := client.Call(context.Background(), tokenAddrFelt, entryPoint)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp) == 0 {
+			return nil, fmt.Errorf("no response from %s call", entryPoint)
+		}
+		return resp, nil
+	}
 
-	// Build approve calldata: approve(spender: felt, amount: u256)
-	approveCalldata := []*felt.Felt{spenderAddrFelt, lowFelt, highFelt}
+	resp, err := call("symbol")
+	if err != nil {
+		return "", 0, err
+	}
+	var symbol string
+	if len(resp) == 1 {
+		symbol = string(utils.FeltToBigInt(resp[0]).Bytes())
+	} else if symbol, err = utils.ByteArrFeltToString(resp); err != nil {
+		return "", 0, fmt.Errorf("failed to decode symbol: %w", err)
+	}
 
-	invoke := rpc.InvokeFunctionCall{
-		ContractAddress: tokenAddrFelt,
-		FunctionName:    "approve",
-		CallData:        approveCalldata,
+	resp, err = call("decimals")
+	if err != nil {
+		return "", 0, err
+	}
+	decimals := utils.FeltToBigInt(resp[0])
+	if !decimals.IsUint64() || decimals.Uint64() > 255 {
+		return "", 0, fmt.Errorf("invalid decimals %s", decimals)
 	}
 
-	return &invoke, nil
+	return symbol, uint8(decimals.Uint64()), nil
 }
 
 // FormatTokenAmount formats a token amount for display (converts from wei to tokens)
@@ -183,66 +150,3 @@ func ERC20Approve(tokenAddress, spenderAddress string, amount *big.Int) (*rpc.In
 func FormatTokenAmount(amount *big.Int, decimals int) string {
 	return types.FormatTokenAmount(amount, decimals)
 }
-
-// ConvertBigIntToU256Felts converts a big.Int to two felts, one for the low 128 bits and one for the high 128 bits
-func ConvertBigIntToU256Felts(value *big.Int) (low, high *felt.Felt) {
-	// Convert to uint256 for better performance
-	u := ToUint256(value)
-
-	// Create mask for lower 128 bits
-	lowerMask := uint256.NewInt(1)
-	lowerMask.Lsh(lowerMask, U128BitShift)
-	lowerMask.SubUint64(lowerMask, 1)
-
-	// Extract low and high parts
-	lowPart := new(uint256.Int)
-	lowPart.And(u, lowerMask)
-	highPart := new(uint256.Int)
-	highPart.Rsh(u, U128BitShift)
-
-	// Convert back to felts
-	low = utils.BigIntToFelt(lowPart.ToBig())
-	high = utils.BigIntToFelt(highPart.ToBig())
-	return low, high
-}
-
-// ConvertSolidityOrderIDForStarknet converts a Solidity-style orderID (bytes32) into the low and high felts of a Starknet u256 orderID
-// Note: Assigns the left 16 bytes to the high felt and the right 16 bytes to the low felt
-func ConvertSolidityOrderIDForStarknet(orderID string) (low, high *felt.Felt, err error) {
-	orderBN := utils.HexToBN(orderID)
-	if orderBN == nil {
-		return nil, nil, fmt.Errorf("invalid hex string: %s", orderID)
-	}
-
-	orderBytes := orderBN.Bytes()
-	if len(orderBytes) < Bytes32Length {
-		paddedBytes := make([]byte, Bytes32Length)
-		copy(paddedBytes[Bytes32Length-len(orderBytes):], orderBytes)
-		orderBytes = paddedBytes
-	}
-
-	left16 := utils.BigIntToFelt(new(big.Int).SetBytes(orderBytes[0:16]))
-	right16 := utils.BigIntToFelt(new(big.Int).SetBytes(orderBytes[16:32]))
-
-	low = right16
-	high = left16
-
-	return low, high, nil
-}
-
-// BytesToU128Felts converts bytes to u128 felts for Cairo
-func BytesToU128Felts(b []byte) []*felt.Felt {
-	words := make([]*felt.Felt, 0, (len(b)+Bytes16Length-1)/Bytes16Length)
-	for i := 0; i < len(b); i += Bytes16Length {
-		end := i + Bytes16Length
-		chunk := make([]byte, Bytes16Length)
-		if end > len(b) {
-			copy(chunk, b[i:])
-		} else {
-			copy(chunk, b[i:end])
-		}
-		// Keep big-endian u128 words; Cairo decoders reconstruct bytes in order
-		words = append(words, utils.BigIntToFelt(new(big.Int).SetBytes(chunk)))
-	}
-	return words
-}
@@ -0,0 +1,66 @@
+package starknetutil
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// FuzzBytesToU128Felts checks that the u128 words always reassemble into the zero-padded input
+func FuzzBytesToU128Felts(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0x42})
+	f.Add(make([]byte, 16))
+	f.Add(bytes.Repeat([]byte{0xff}, 33))
+	f.Add(make([]byte, 448)) // EVM origin_data
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		words := BytesToU128Felts(input)
+		if want := (len(input) + Bytes16Length - 1) / Bytes16Length; len(words) != want {
+			t.Fatalf("got %d words for %d bytes, want %d", len(words), len(input), want)
+		}
+
+		var reassembled []byte
+		for i, word := range words {
+			value := utils.FeltToBigInt(word)
+			if value.BitLen() > U128BitShift {
+				t.Fatalf("word %d does not fit in a u128: %s", i, value)
+			}
+			reassembled = append(reassembled, value.FillBytes(make([]byte, Bytes16Length))...)
+		}
+		padded := append(append([]byte{}, input...), make([]byte, len(reassembled)-len(input))...)
+		if !bytes.Equal(reassembled, padded) {
+			t.Fatalf("words reassemble to %x, want %x", reassembled, padded)
+		}
+	})
+}
+
+// FuzzConvertSolidityOrderIDForStarknet checks that valid orderIDs split into u256 halves that recombine
+func FuzzConvertSolidityOrderIDForStarknet(f *testing.F) {
+	f.Add("0x1234")
+	f.Add("0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+	f.Add("0x")
+	f.Add("not hex")
+	f.Add("-0x01")
+
+	f.Fuzz(func(t *testing.T, orderID string) {
+		low, high, err := ConvertSolidityOrderIDForStarknet(orderID)
+		if err != nil {
+			return
+		}
+
+		value := utils.HexToBN(orderID)
+		if value == nil || value.Sign() < 0 || value.BitLen() > 256 {
+			return // Accepted leniently; only well-formed orderIDs have a defined split
+		}
+		lowValue, highValue := utils.FeltToBigInt(low), utils.FeltToBigInt(high)
+		if lowValue.BitLen() > U128BitShift || highValue.BitLen() > U128BitShift {
+			t.Fatalf("halves do not fit in u128: low %s high %s", lowValue, highValue)
+		}
+		if got := new(big.Int).Add(lowValue, new(big.Int).Lsh(highValue, U128BitShift)); got.Cmp(value) != 0 {
+			t.Fatalf("halves recombine to %s, want %s", got, value)
+		}
+	})
+}
@@ -1,10 +1,14 @@
 package starknetutil
 
 import (
+	"encoding/hex"
 	"math/big"
 	"strings"
 	"testing"
 
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
@@ -481,3 +485,44 @@ func TestUint256Operations(t *testing.T) {
 		assert.Equal(t, 0, small.Cmp(new(uint256.Int).Set(small)))
 	})
 }
+
+func TestERC20Metadata(t *testing.T) {
+	const tokenHex = "0x1234"
+	token, err := utils.HexToFelt(tokenHex)
+	require.NoError(t, err)
+	decimals := func(rpc.FunctionCall) ([]*felt.Felt, error) {
+		return []*felt.Felt{new(felt.Felt).SetUint64(6)}, nil
+	}
+
+	t.Run("short string symbol", func(t *testing.T) {
+		backend := chainmock.NewStarknetBackend()
+		backend.HandleCall(token, "symbol", func(rpc.FunctionCall) ([]*felt.Felt, error) {
+			symbol, err := utils.HexToFelt("0x" + hex.EncodeToString([]byte("STRK")))
+			return []*felt.Felt{symbol}, err
+		})
+		backend.HandleCall(token, "decimals", decimals)
+
+		symbol, dec, err := ERC20Metadata(backend, tokenHex)
+		require.NoError(t, err)
+		assert.Equal(t, "STRK", symbol)
+		assert.Equal(t, uint8(6), dec)
+	})
+
+	t.Run("ByteArray symbol", func(t *testing.T) {
+		backend := chainmock.NewStarknetBackend()
+		backend.HandleCall(token, "symbol", func(rpc.FunctionCall) ([]*felt.Felt, error) {
+			return utils.StringToByteArrFelt("DOGCOIN")
+		})
+		backend.HandleCall(token, "decimals", decimals)
+
+		symbol, _, err := ERC20Metadata(backend, tokenHex)
+		require.NoError(t, err)
+		assert.Equal(t, "DOGCOIN", symbol)
+	})
+
+	t.Run("missing entry point", func(t *testing.T) {
+		backend := chainmock.NewStarknetBackend()
+		_, _, err := ERC20Metadata(backend, tokenHex)
+		assert.ErrorContains(t, err, "symbol")
+	})
+}
@@ -0,0 +1,200 @@
+// Package accounting summarizes the economics of the orders in the order store.
+//
+// For every order the solver filled it combines:
+// - the output paid on the destination chain (MaxSpent of our fill)
+// - the input received on the origin chain once settlement arrived (MinReceived)
+// - the network fees of every approve/fill/settle transaction, per fee unit (WEI or FRI)
+// - the Hyperlane interchain gas payments attached to settlements
+//
+// Orders are grouped per chain pair and token pair. Amounts stay in token base units;
+// token PnL (input - output) is only meaningful for like-for-like token pairs. Token symbols
+// come from the token registry or metadata discovered on-chain.
+package accounting
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+)
+
+// PnLRow aggregates the orders of one chain pair and token pair
+type PnLRow struct {
+	OriginChain      string   `json:"originChain"`
+	DestinationChain string   `json:"destinationChain"`
+	InputToken       string   `json:"inputToken"`
+	OutputToken      string   `json:"outputToken"`
+	InputSymbol      string   `json:"inputSymbol,omitempty"` // From the token registry or discovered metadata
+	OutputSymbol     string   `json:"outputSymbol,omitempty"`
+	Orders           int      `json:"orders"`        // Orders filled by the solver
+	Settled          int      `json:"settled"`       // Orders whose input was received on the origin
+	InputReceived    *big.Int `json:"inputReceived"` // Sum of inputs received, input token base units
+	OutputPaid       *big.Int `json:"outputPaid"`    // Sum of outputs paid, output token base units
+	TokenPnL         *big.Int `json:"tokenPnl"`      // InputReceived - OutputPaid
+	GasFeeWei        *big.Int `json:"gasFeeWei"`     // Network fees paid in WEI
+	GasFeeFri        *big.Int `json:"gasFeeFri"`     // Network fees paid in FRI (STRK)
+	HyperlaneGasWei  *big.Int `json:"hyperlaneGasWei"`
+}
+
+// PnLReport is the PnL summary for orders filled in [From, To)
+type PnLReport struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	Rows []PnLRow  `json:"rows"`
+}
+
+type pnlKey struct {
+	origin, destination     uint64
+	inputToken, outputToken string
+}
+
+// BuildPnLReport aggregates the orders the solver filled in [from, to); a zero from or to is unbounded
+func BuildPnLReport(list []orders.Order, from, to time.Time) PnLReport {
+	rows := make(map[pnlKey]*PnLRow)
+
+	for i := range list {
+		order := &list[i]
+		if !order.FilledByUs() || !inRange(order.FilledAt, from, to) {
+			continue
+		}
+
+		key := pnlKey{origin: order.OriginChainID, destination: order.DestinationChainID}
+		if order.Args != nil {
+			if minReceived := order.Args.ResolvedOrder.MinReceived; len(minReceived) > 0 {
+				key.inputToken = minReceived[0].Token
+			}
+		}
+		if len(order.OutputPaid) > 0 {
+			key.outputToken = order.OutputPaid[0].Token
+		}
+
+		row, ok := rows[key]
+		if !ok {
+			row = newPnLRow(key)
+			rows[key] = row
+		}
+
+		row.Orders++
+		if len(order.InputReceived) > 0 {
+			row.Settled++
+		}
+		for _, in := range order.InputReceived {
+			addTo(row.InputReceived, in.Amount)
+		}
+		for _, out := range order.OutputPaid {
+			addTo(row.OutputPaid, out.Amount)
+		}
+		for _, cost := range order.Costs {
+			switch cost.FeeUnit {
+			case "FRI":
+				addTo(row.GasFeeFri, cost.Fee)
+			default:
+				addTo(row.GasFeeWei, cost.Fee)
+			}
+			addTo(row.HyperlaneGasWei, cost.GasPayment)
+		}
+	}
+
+	report := PnLReport{From: from, To: to, Rows: make([]PnLRow, 0, len(rows))}
+	for _, row := range rows {
+		row.TokenPnL = new(big.Int).Sub(row.InputReceived, row.OutputPaid)
+		report.Rows = append(report.Rows, *row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if a.OriginChain != b.OriginChain {
+			return a.OriginChain < b.OriginChain
+		}
+		if a.DestinationChain != b.DestinationChain {
+			return a.DestinationChain < b.DestinationChain
+		}
+		if a.InputToken != b.InputToken {
+			return a.InputToken < b.InputToken
+		}
+		return a.OutputToken < b.OutputToken
+	})
+	return report
+}
+
+func newPnLRow(key pnlKey) *PnLRow {
+	return &PnLRow{
+		OriginChain:      logutil.NetworkNameByChainID(key.origin),
+		DestinationChain: logutil.NetworkNameByChainID(key.destination),
+		InputToken:       key.inputToken,
+		OutputToken:      key.outputToken,
+		InputSymbol:      tokenSymbol(key.origin, key.inputToken),
+		OutputSymbol:     tokenSymbol(key.destination, key.outputToken),
+		InputReceived:    new(big.Int),
+		OutputPaid:       new(big.Int),
+		GasFeeWei:        new(big.Int),
+		GasFeeFri:        new(big.Int),
+		HyperlaneGasWei:  new(big.Int),
+	}
+}
+
+// tokenSymbol returns the symbol of a known token, empty otherwise
+func tokenSymbol(chainID uint64, address string) string {
+	if token, ok := config.LookupToken(chainID, address); ok {
+		return token.Symbol
+	}
+	return ""
+}
+
+func addTo(total, amount *big.Int) {
+	if amount != nil {
+		total.Add(total, amount)
+	}
+}
+
+func inRange(t, from, to time.Time) bool {
+	if t.IsZero() {
+		return false
+	}
+	if !from.IsZero() && t.Before(from) {
+		return false
+	}
+	if !to.IsZero() && !t.Before(to) {
+		return false
+	}
+	return true
+}
+
+var pnlCSVHeader = []string{
+	"origin_chain", "destination_chain", "input_token", "output_token", "input_symbol", "output_symbol", "orders", "settled",
+	"input_received", "output_paid", "token_pnl", "gas_fee_wei", "gas_fee_fri", "hyperlane_gas_wei",
+}
+
+// WriteCSV writes one line per row, preceded by a header
+func (r PnLReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(pnlCSVHeader); err != nil {
+		return err
+	}
+	for _, row := range r.Rows {
+		record := []string{
+			row.OriginChain, row.DestinationChain, row.InputToken, row.OutputToken, row.InputSymbol, row.OutputSymbol,
+			strconv.Itoa(row.Orders), strconv.Itoa(row.Settled),
+			row.InputReceived.String(), row.OutputPaid.String(), row.TokenPnL.String(),
+			row.GasFeeWei.String(), row.GasFeeFri.String(), row.HyperlaneGasWei.String(),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the report as indented JSON
+func (r PnLReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,103 @@
+package accounting
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var day = time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+func filledOrder(id string, filledAt time.Time, input, output int64, costs ...orders.TxCost) orders.Order {
+	return orders.Order{
+		OrderID:            id,
+		OriginChainID:      84532,
+		DestinationChainID: 23448591,
+		Status:             orders.StatusSettled,
+		Args: &types.ParsedArgs{
+			OrderID: id,
+			ResolvedOrder: types.ResolvedCrossChainOrder{
+				MinReceived: []types.Output{{Token: "0xin", Amount: big.NewInt(input)}},
+			},
+		},
+		OutputPaid: []orders.TokenAmount{{ChainID: 23448591, Token: "0xout", Amount: big.NewInt(output)}},
+		Costs:      costs,
+		FilledAt:   filledAt,
+	}
+}
+
+// TestBuildPnLReport tests aggregation of order economics
+func TestBuildPnLReport(t *testing.T) {
+	fill := orders.TxCost{Kind: orders.TxKindFillSettle, Fee: big.NewInt(7), FeeUnit: "FRI", GasPayment: big.NewInt(3)}
+	approve := orders.TxCost{Kind: orders.TxKindApprove, Fee: big.NewInt(2), FeeUnit: "WEI"}
+
+	settled := filledOrder("0x01", day, 100, 90, fill, approve)
+	settled.InputReceived = []orders.TokenAmount{{ChainID: 84532, Token: "0xin", Amount: big.NewInt(100)}}
+	pending := filledOrder("0x02", day.Add(time.Hour), 50, 45, fill)
+	outOfRange := filledOrder("0x03", day.Add(-48*time.Hour), 10, 9, fill)
+	notOurs := filledOrder("0x04", day, 10, 9) // no fill cost: filled by someone else
+
+	report := BuildPnLReport([]orders.Order{settled, pending, outOfRange, notOurs}, day.Add(-time.Hour), day.Add(24*time.Hour))
+	require.Len(t, report.Rows, 1)
+
+	row := report.Rows[0]
+	assert.Equal(t, 2, row.Orders)
+	assert.Equal(t, 1, row.Settled)
+	assert.Equal(t, "0xin", row.InputToken)
+	assert.Equal(t, "0xout", row.OutputToken)
+	assert.Equal(t, big.NewInt(100), row.InputReceived)
+	assert.Equal(t, big.NewInt(135), row.OutputPaid)
+	assert.Equal(t, big.NewInt(-35), row.TokenPnL)
+	assert.Equal(t, big.NewInt(14), row.GasFeeFri)
+	assert.Equal(t, big.NewInt(2), row.GasFeeWei)
+	assert.Equal(t, big.NewInt(6), row.HyperlaneGasWei)
+
+	t.Run("unbounded_range", func(t *testing.T) {
+		all := BuildPnLReport([]orders.Order{settled, outOfRange}, time.Time{}, time.Time{})
+		require.Len(t, all.Rows, 1)
+		assert.Equal(t, 2, all.Rows[0].Orders)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, report.WriteCSV(&buf))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.True(t, strings.HasPrefix(lines[0], "origin_chain,destination_chain"))
+		assert.True(t, strings.HasSuffix(lines[1], ",2,1,100,135,-35,2,14,6"))
+	})
+
+	t.Run("symbols of known tokens", func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+			{ChainID: 84532, Address: "0xa1", Symbol: "DOG", Decimals: 6},
+		}}))
+		defer config.ApplyRuntime(&config.RuntimeConfig{})
+
+		order := filledOrder("0x05", day, 10, 9, fill)
+		order.Args.ResolvedOrder.MinReceived[0].Token = "0x00000000000000000000000000000000000000a1"
+		rows := BuildPnLReport([]orders.Order{order}, time.Time{}, time.Time{}).Rows
+		require.Len(t, rows, 1)
+		assert.Equal(t, "DOG", rows[0].InputSymbol)
+		assert.Empty(t, rows[0].OutputSymbol)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, report.WriteJSON(&buf))
+
+		var decoded PnLReport
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		require.Len(t, decoded.Rows, 1)
+		assert.Equal(t, big.NewInt(-35), decoded.Rows[0].TokenPnL)
+	})
+}
@@ -0,0 +1,116 @@
+// Package admin serves the solver's runtime admin API over HTTP.
+//
+// The server only runs when SOLVER_ADMIN_ADDR is set (e.g. "127.0.0.1:8090"). When
+// SOLVER_ADMIN_TOKEN is also set, every request must send it as "Authorization: Bearer <token>".
+//
+// Usage:
+//
+//	srv := admin.NewServer(admin.AddrFromEnv(), admin.TokenFromEnv())
+//	srv.HandleFunc("GET /spend-limits", handler)
+//	if err := srv.Start(); err != nil { ... }
+//	defer srv.Shutdown(ctx)
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+const readHeaderTimeout = 5 * time.Second
+
+// AddrFromEnv returns SOLVER_ADMIN_ADDR, empty when the admin API is disabled
+func AddrFromEnv() string {
+	return os.Getenv("SOLVER_ADMIN_ADDR")
+}
+
+// TokenFromEnv returns SOLVER_ADMIN_TOKEN, empty when requests are not authenticated
+func TokenFromEnv() string {
+	return os.Getenv("SOLVER_ADMIN_TOKEN")
+}
+
+// Server is the admin HTTP server; handlers are registered before Start
+type Server struct {
+	addr     string
+	token    string
+	mux      *http.ServeMux
+	server   *http.Server
+	listener net.Listener
+}
+
+// NewServer creates a server listening on addr; an empty token disables authentication
+func NewServer(addr, token string) *Server {
+	return &Server{
+		addr:  addr,
+		token: token,
+		mux:   http.NewServeMux(),
+	}
+}
+
+// HandleFunc registers a handler for an http.ServeMux pattern such as "POST /spend-limits/reset"
+func (s *Server) HandleFunc(pattern string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, handler)
+}
+
+// ServeHTTP authenticates the request and dispatches it to the registered handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" {
+		expected := "Bearer " + s.token
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(expected)) != 1 {
+			WriteError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// Start listens on the server address and serves requests in the background
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("admin API listen on %s: %w", s.addr, err)
+	}
+	s.listener = listener
+	s.server = &http.Server{Handler: s, ReadHeaderTimeout: readHeaderTimeout}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("⚠️  Admin API stopped: %v\n", err)
+		}
+	}()
+	return nil
+}
+
+// Addr returns the address the server listens on, resolved once started (e.g. for port 0)
+func (s *Server) Addr() string {
+	if s.listener != nil {
+		return s.listener.Addr().String()
+	}
+	return s.addr
+}
+
+// Shutdown stops the server, waiting for in-flight requests until ctx is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.server == nil {
+		return nil
+	}
+	return s.server.Shutdown(ctx)
+}
+
+// WriteJSON writes v as a JSON response with the given status code
+func WriteJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// WriteError writes err as a JSON {"error": ...} response
+func WriteError(w http.ResponseWriter, status int, err error) {
+	WriteJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,84 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	newServer := func(token string) *Server {
+		srv := NewServer("127.0.0.1:0", token)
+		srv.HandleFunc("GET /ping", func(w http.ResponseWriter, _ *http.Request) {
+			WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		})
+		srv.HandleFunc("POST /fail", func(w http.ResponseWriter, _ *http.Request) {
+			WriteError(w, http.StatusBadRequest, errors.New("bad input"))
+		})
+		return srv
+	}
+
+	t.Run("routes requests to handlers", func(t *testing.T) {
+		srv := newServer("")
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+
+		rec = httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/fail", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.JSONEq(t, `{"error":"bad input"}`, rec.Body.String())
+
+		rec = httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/ping", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("token is required when configured", func(t *testing.T) {
+		srv := newServer("secret")
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("Authorization", "Bearer wrong")
+		rec = httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec = httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("serves over TCP until shut down", func(t *testing.T) {
+		srv := newServer("")
+		require.NoError(t, srv.Start())
+
+		resp, err := http.Get("http://" + srv.Addr() + "/ping")
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"status":"ok"}`, string(body))
+
+		require.NoError(t, srv.Shutdown(context.Background()))
+		_, err = http.Get("http://" + srv.Addr() + "/ping")
+		assert.Error(t, err)
+	})
+
+	t.Run("shutdown before start is a no-op", func(t *testing.T) {
+		assert.NoError(t, NewServer("127.0.0.1:0", "").Shutdown(context.Background()))
+	})
+}
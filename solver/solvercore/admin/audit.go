@@ -0,0 +1,102 @@
+package admin
+
+// Audit log of the manual actions taken through the admin API and the operator commands (e.g.
+// solver inventory), one JSON object per line.
+// Entries are appended as soon as an action completes, successful or not, and the log is
+// never rewritten. They go to the admin_audit log of the storage backend: a JSONL file with
+// the file backend.
+//
+// Settings:
+// - SOLVER_ADMIN_AUDIT_FILE: path of the audit log of the file backend (default state/solver_state/admin-audit.jsonl)
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
+)
+
+// AuditEntry is one manual action
+type AuditEntry struct {
+	Time       time.Time `json:"time"`
+	Action     string    `json:"action"`     // e.g. "order.fill"
+	Target     string    `json:"target"`     // e.g. the order ID
+	RemoteAddr string    `json:"remoteAddr"` // "cli:<user>" for operator commands
+	TxHash     string    `json:"txHash,omitempty"`
+	Result     string    `json:"result"` // "ok" or "error"
+	Error      string    `json:"error,omitempty"`
+}
+
+// AuditLog appends manual actions to a storage log. Methods are safe for concurrent use.
+type AuditLog struct {
+	path    string // File of the file backend, empty for database backends
+	backend storage.Storage
+	now     func() time.Time
+}
+
+// NewAuditLog creates an audit log writing to the file at path; the file is created on the first entry
+func NewAuditLog(path string) *AuditLog {
+	backend := storage.NewFileStorage(filepath.Dir(path), map[string]string{storage.AdminAudit: path})
+	return &AuditLog{path: path, backend: backend, now: time.Now}
+}
+
+// AuditLogFromEnv returns the audit log of the default storage backend, the file at
+// SOLVER_ADMIN_AUDIT_FILE with the file backend
+func AuditLogFromEnv() *AuditLog {
+	return auditLogIn(storage.Default())
+}
+
+// auditLogIn returns the audit log of backend, the file at SOLVER_ADMIN_AUDIT_FILE when backend is
+// the file backend or failed to open
+func auditLogIn(backend storage.Storage, err error) *AuditLog {
+	if err != nil || backend.Backend() == storage.BackendFile {
+		return NewAuditLog(storage.FilePath(storage.AdminAudit))
+	}
+	return &AuditLog{backend: backend, now: time.Now}
+}
+
+// Path returns the audit log file, empty for database backends
+func (a *AuditLog) Path() string {
+	return a.path
+}
+
+// Record appends the outcome of an action requested by r; actionErr nil means it succeeded
+func (a *AuditLog) Record(r *http.Request, action, target string, actionErr error) error {
+	return a.append(AuditEntry{Action: action, Target: target, RemoteAddr: r.RemoteAddr}, actionErr)
+}
+
+// RecordCommand appends the outcome of an action taken by an operator command, with the
+// transaction it sent if any
+func (a *AuditLog) RecordCommand(action, target, txHash string, actionErr error) error {
+	return a.append(AuditEntry{Action: action, Target: target, RemoteAddr: commandUser(), TxHash: txHash}, actionErr)
+}
+
+// append stamps entry with the time and result and writes it
+func (a *AuditLog) append(entry AuditEntry, actionErr error) error {
+	entry.Time = a.now().UTC()
+	entry.Result = "ok"
+	if actionErr != nil {
+		entry.Result = "error"
+		entry.Error = actionErr.Error()
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	if err := a.backend.Append(storage.AdminAudit, line); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+// commandUser identifies the operator running a command as "cli:<user>"
+func commandUser() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return "cli:" + current.Username
+	}
+	return "cli"
+}
@@ -0,0 +1,92 @@
+package admin
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
+)
+
+func TestAuditLog(t *testing.T) {
+	readEntries := func(t *testing.T, path string) []AuditEntry {
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+
+		var entries []AuditEntry
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var entry AuditEntry
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+
+	t.Run("appends one entry per action", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nested", "audit.jsonl")
+		log := NewAuditLog(path)
+		log.now = func() time.Time { return time.Unix(1_700_000_000, 0) }
+
+		req := httptest.NewRequest(http.MethodPost, "/orders/0x01/fill", nil)
+		require.NoError(t, log.Record(req, "order.fill", "0x01", nil))
+		require.NoError(t, log.Record(req, "order.settle", "0x01", errors.New("not filled")))
+
+		entries := readEntries(t, path)
+		require.Len(t, entries, 2)
+		assert.Equal(t, AuditEntry{
+			Time:       time.Unix(1_700_000_000, 0).UTC(),
+			Action:     "order.fill",
+			Target:     "0x01",
+			RemoteAddr: req.RemoteAddr,
+			Result:     "ok",
+		}, entries[0])
+		assert.Equal(t, "error", entries[1].Result)
+		assert.Equal(t, "not filled", entries[1].Error)
+
+		// Reopening keeps the previous entries
+		require.NoError(t, NewAuditLog(path).Record(req, "order.fill", "0x02", nil))
+		assert.Len(t, readEntries(t, path), 3)
+	})
+
+	t.Run("operator commands", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+		log := NewAuditLog(path)
+
+		require.NoError(t, log.RecordCommand("inventory.deposit", "Base:DOG:100", "0xabc", nil))
+		require.NoError(t, log.RecordCommand("inventory.withdraw", "Starknet:DOG:5", "", errors.New("insufficient balance")))
+
+		entries := readEntries(t, path)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "inventory.deposit", entries[0].Action)
+		assert.Equal(t, "0xabc", entries[0].TxHash)
+		assert.Equal(t, "ok", entries[0].Result)
+		assert.True(t, strings.HasPrefix(entries[0].RemoteAddr, "cli"))
+		assert.Empty(t, entries[1].TxHash)
+		assert.Equal(t, "insufficient balance", entries[1].Error)
+	})
+
+	t.Run("path from env", func(t *testing.T) {
+		files := storage.NewFileStorage(t.TempDir(), nil)
+		t.Setenv("SOLVER_ADMIN_AUDIT_FILE", "custom.jsonl")
+		assert.Equal(t, "custom.jsonl", auditLogIn(files, nil).Path())
+		t.Setenv("SOLVER_ADMIN_AUDIT_FILE", "")
+		assert.Equal(t, "state/solver_state/admin-audit.jsonl", auditLogIn(files, nil).Path())
+
+		database, err := storage.OpenSQL(storage.BackendSQLite, filepath.Join(t.TempDir(), "solver.db"))
+		require.NoError(t, err)
+		defer database.Close()
+		assert.Empty(t, auditLogIn(database, nil).Path(), "database backends have no file")
+	})
+}
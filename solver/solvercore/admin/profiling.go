@@ -0,0 +1,88 @@
+package admin
+
+// Runtime profiling of the solver. With SOLVER_ADMIN_PPROF=true the admin server serves
+// net/http/pprof under /debug/pprof/, behind the same token as the other routes, e.g. to capture
+// a CPU profile during a long backfill:
+//
+//	go tool pprof http://127.0.0.1:8090/debug/pprof/profile?seconds=30
+//
+// WriteProfiles writes a goroutine dump and a heap profile to files instead, for a solver whose
+// admin API is disabled or unresponsive; the solver binary calls it on SIGUSR2.
+//
+// Settings:
+// - SOLVER_ADMIN_PPROF: serve the pprof endpoints on the admin server (default false)
+// - SOLVER_PROFILE_DIR: directory the SIGUSR2 profiles are written to (default state/solver_state/profiles)
+
+import (
+	"fmt"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+)
+
+const defaultProfileDir = "state/solver_state/profiles"
+
+// ProfilingFromEnv reports whether SOLVER_ADMIN_PPROF enables the pprof endpoints
+func ProfilingFromEnv() bool {
+	return envutil.GetEnvBool("SOLVER_ADMIN_PPROF", false)
+}
+
+// ProfileDirFromEnv returns SOLVER_PROFILE_DIR, the directory WriteProfiles writes to
+func ProfileDirFromEnv() string {
+	return envutil.GetEnvWithDefault("SOLVER_PROFILE_DIR", defaultProfileDir)
+}
+
+// HandleProfiling serves the net/http/pprof endpoints under /debug/pprof/. They are registered on
+// the server's own mux rather than http.DefaultServeMux, so they are only reachable through it.
+func (s *Server) HandleProfiling() {
+	s.mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+	s.mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+	s.mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+	s.mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+	s.mux.HandleFunc("POST /debug/pprof/symbol", pprof.Symbol)
+	s.mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+}
+
+// WriteProfiles writes a goroutine dump (goroutines-<time>.txt, with full stacks) and a heap profile
+// (heap-<time>.pb.gz) to dir and returns their paths
+func WriteProfiles(dir string, now time.Time) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	stamp := now.UTC().Format("20060102T150405Z")
+
+	goroutines := filepath.Join(dir, "goroutines-"+stamp+".txt")
+	if err := writeProfile(goroutines, func(f *os.File) error {
+		return runtimepprof.Lookup("goroutine").WriteTo(f, 2)
+	}); err != nil {
+		return nil, err
+	}
+
+	heap := filepath.Join(dir, "heap-"+stamp+".pb.gz")
+	if err := writeProfile(heap, func(f *os.File) error {
+		// Collect garbage first so the profile shows live objects up to now
+		runtime.GC()
+		return runtimepprof.Lookup("heap").WriteTo(f, 0)
+	}); err != nil {
+		return []string{goroutines}, err
+	}
+	return []string{goroutines, heap}, nil
+}
+
+// writeProfile creates path and fills it with write
+func writeProfile(path string, write func(*os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiling(t *testing.T) {
+	t.Run("pprof is served behind the token", func(t *testing.T) {
+		srv := NewServer("127.0.0.1:0", "secret")
+		srv.HandleProfiling()
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		for _, path := range []string{"/debug/pprof/", "/debug/pprof/goroutine?debug=1", "/debug/pprof/cmdline"} {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			rec = httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusOK, rec.Code, path)
+		}
+	})
+
+	t.Run("pprof is not served unless enabled", func(t *testing.T) {
+		srv := NewServer("127.0.0.1:0", "")
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("profiles are written to files", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "profiles")
+		paths, err := WriteProfiles(dir, time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			filepath.Join(dir, "goroutines-20260301T123000Z.txt"),
+			filepath.Join(dir, "heap-20260301T123000Z.pb.gz"),
+		}, paths)
+
+		dump, err := os.ReadFile(paths[0])
+		require.NoError(t, err)
+		assert.Contains(t, string(dump), "TestProfiling", "full stacks of every goroutine")
+		info, err := os.Stat(paths[1])
+		require.NoError(t, err)
+		assert.NotZero(t, info.Size())
+	})
+}
@@ -0,0 +1,370 @@
+package solvercore
+
+// Module: Admin API routes
+// - Registers the solver's runtime controls on the admin HTTP server (see package admin)
+//
+// Routes:
+// - GET  /spend-limits                   spending per chain over the current 24h window
+// - POST /spend-limits/reset[?chain_id=] clears one chain's window, or all of them
+// - GET  /tx-limits                      transactions pending, sent in the last minute and waiting per chain
+// - GET  /config                         active runtime config (allow/block lists, rules, poll intervals)
+// - POST /config/reload                  re-reads the runtime config from SOLVER_CONFIG_FILE
+// - GET  /networks                       pause switches of every network
+// - POST /networks/{name}/pause|resume   stops or restarts listening on a network and filling toward it
+// - POST /networks/{name}/fills/pause|resume  stops or restarts filling toward a network only
+// - GET  /listeners                      block progress of every listener (head, lag, events, last error)
+// - GET  /competition                    fills seen per destination chain, ours and competitors'
+// - GET  /orders[?status=A,B][&limit=N]  stored orders, most recently updated first (default limit 50)
+// - GET  /orders/{id}                    one stored order with the fill/settle tx hashes sent for it, for
+//                                        waiting on its status (see pkg/orderwait)
+// - GET  /balances                       solver balances of the last balance check
+// - GET  /events[?limit=N]               latest order lifecycle events, newest first (see package events)
+// - GET  /metrics                        every metric in the Prometheus text format, for scraping
+// - POST /orders/{id}/fill|settle        fills or settles a stored order now, for stuck orders; only
+//                                        served with SOLVER_ADMIN_TOKEN set, audited (see admin.AuditLog)
+// - GET  /debug/pprof/...                net/http/pprof profiles; only served with SOLVER_ADMIN_PPROF=true
+//                                        (see admin/profiling.go)
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/admin"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/events"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+)
+
+const (
+	// adminShutdownTimeout bounds how long Shutdown waits for in-flight admin requests
+	adminShutdownTimeout = 5 * time.Second
+
+	// recentEventsSize is how many order events GET /events keeps
+	recentEventsSize = 500
+)
+
+// startAdminAPI starts the admin API when SOLVER_ADMIN_ADDR is set
+func (sm *SolverManager) startAdminAPI() error {
+	addr := admin.AddrFromEnv()
+	if addr == "" {
+		return nil
+	}
+
+	token := admin.TokenFromEnv()
+	srv := admin.NewServer(addr, token)
+	registerSpendLimitRoutes(srv, contracts.DefaultSpendLimiter())
+	registerTxRateRoutes(srv, contracts.DefaultTxRateLimiter())
+	registerConfigRoutes(srv, sm.ReloadConfig)
+	registerNetworkRoutes(srv)
+	registerListenerRoutes(srv, sm.ListenerSnapshots)
+	registerCompetitionRoutes(srv, contracts.DefaultCompetitionTracker())
+	registerOrderListRoutes(srv, listStoredOrders)
+	registerOrderLookupRoutes(srv, getStoredOrder)
+	registerBalanceRoutes(srv, sm.lastBalances)
+	recentEvents := events.NewRecorder(recentEventsSize)
+	registerEventRoutes(srv, recentEvents)
+	registerMetricsRoutes(srv, metrics.Default())
+	if token != "" {
+		registerOrderRoutes(srv, sm.forceFill, sm.forceSettle, admin.AuditLogFromEnv())
+	} else {
+		fmt.Printf("   🛠️  Manual order actions disabled: SOLVER_ADMIN_TOKEN is not set\n")
+	}
+	if admin.ProfilingFromEnv() {
+		srv.HandleProfiling()
+		fmt.Printf("   🩺 Profiling served under /debug/pprof/\n")
+	}
+	if err := srv.Start(); err != nil {
+		return err
+	}
+	sm.adminServer = srv
+	sm.stopAdminEvents = recentEvents.Start(context.Background(), events.Default())
+	fmt.Printf("   🛠️  Admin API listening on %s\n", srv.Addr())
+	return nil
+}
+
+// registerSpendLimitRoutes exposes the daily spend limits
+func registerSpendLimitRoutes(srv *admin.Server, limiter *contracts.SpendLimiter) {
+	srv.HandleFunc("GET /spend-limits", func(w http.ResponseWriter, _ *http.Request) {
+		admin.WriteJSON(w, http.StatusOK, limiter.Status())
+	})
+
+	srv.HandleFunc("POST /spend-limits/reset", func(w http.ResponseWriter, r *http.Request) {
+		value := r.URL.Query().Get("chain_id")
+		if value == "" {
+			limiter.ResetAll()
+			fmt.Printf("🛠️  Daily spend limits reset for all chains\n")
+			admin.WriteJSON(w, http.StatusOK, limiter.Status())
+			return
+		}
+
+		chainID, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			admin.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid chain_id %q", value))
+			return
+		}
+		limiter.Reset(chainID)
+		fmt.Printf("🛠️  Daily spend limits reset for chain %d\n", chainID)
+		admin.WriteJSON(w, http.StatusOK, limiter.Status())
+	})
+}
+
+// registerTxRateRoutes exposes the per-chain transaction rate limits
+func registerTxRateRoutes(srv *admin.Server, limiter *contracts.TxRateLimiter) {
+	srv.HandleFunc("GET /tx-limits", func(w http.ResponseWriter, _ *http.Request) {
+		admin.WriteJSON(w, http.StatusOK, limiter.Status())
+	})
+}
+
+// registerConfigRoutes exposes the runtime config and its reload
+func registerConfigRoutes(srv *admin.Server, reload func() (*config.RuntimeConfig, error)) {
+	srv.HandleFunc("GET /config", func(w http.ResponseWriter, _ *http.Request) {
+		admin.WriteJSON(w, http.StatusOK, config.ActiveRuntime())
+	})
+
+	srv.HandleFunc("POST /config/reload", func(w http.ResponseWriter, _ *http.Request) {
+		runtime, err := reload()
+		if err != nil {
+			// The active config is unchanged
+			admin.WriteError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		admin.WriteJSON(w, http.StatusOK, runtime)
+	})
+}
+
+// registerNetworkRoutes exposes the per-network pause switches
+func registerNetworkRoutes(srv *admin.Server) {
+	srv.HandleFunc("GET /networks", func(w http.ResponseWriter, _ *http.Request) {
+		admin.WriteJSON(w, http.StatusOK, config.AllNetworkFlags())
+	})
+
+	handle := func(pattern, action string, set func(string, bool) (config.NetworkFlags, error), enabled bool) {
+		srv.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			name := r.PathValue("name")
+			flags, err := set(name, enabled)
+			if err != nil {
+				admin.WriteError(w, http.StatusNotFound, err)
+				return
+			}
+			fmt.Printf("🛠️  %s %s\n", action, name)
+			admin.WriteJSON(w, http.StatusOK, flags)
+		})
+	}
+	handle("POST /networks/{name}/pause", "Paused network", config.SetNetworkEnabled, false)
+	handle("POST /networks/{name}/resume", "Resumed network", config.SetNetworkEnabled, true)
+	handle("POST /networks/{name}/fills/pause", "Paused fills toward", config.SetFillsEnabled, false)
+	handle("POST /networks/{name}/fills/resume", "Resumed fills toward", config.SetFillsEnabled, true)
+}
+
+// registerListenerRoutes exposes the listeners' block progress
+func registerListenerRoutes(srv *admin.Server, snapshots func() []base.ListenerSnapshot) {
+	srv.HandleFunc("GET /listeners", func(w http.ResponseWriter, _ *http.Request) {
+		admin.WriteJSON(w, http.StatusOK, snapshots())
+	})
+}
+
+// registerCompetitionRoutes exposes the fill counts of the competition tracker
+func registerCompetitionRoutes(srv *admin.Server, tracker *contracts.CompetitionTracker) {
+	srv.HandleFunc("GET /competition", func(w http.ResponseWriter, _ *http.Request) {
+		admin.WriteJSON(w, http.StatusOK, tracker.Stats())
+	})
+}
+
+// defaultOrderListLimit is the number of orders served by GET /orders without a limit
+const defaultOrderListLimit = 50
+
+// registerOrderListRoutes exposes the stored orders, most recently updated first
+func registerOrderListRoutes(srv *admin.Server, list func() ([]orders.Order, error)) {
+	srv.HandleFunc("GET /orders", func(w http.ResponseWriter, r *http.Request) {
+		var filter orders.Filter
+		if value := r.URL.Query().Get("status"); value != "" {
+			for _, name := range strings.Split(value, ",") {
+				status, err := orders.ParseStatus(name)
+				if err != nil {
+					admin.WriteError(w, http.StatusBadRequest, err)
+					return
+				}
+				filter.Statuses = append(filter.Statuses, status)
+			}
+		}
+		limit := defaultOrderListLimit
+		if value := r.URL.Query().Get("limit"); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				admin.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", value))
+				return
+			}
+			limit = n
+		}
+
+		stored, err := list()
+		if err != nil {
+			admin.WriteError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		records := orders.Export(stored, filter)
+		sort.SliceStable(records, func(i, j int) bool {
+			return records[i].UpdatedAt.After(records[j].UpdatedAt)
+		})
+		if len(records) > limit {
+			records = records[:limit]
+		}
+		admin.WriteJSON(w, http.StatusOK, records)
+	})
+}
+
+// listStoredOrders returns every order of the default store
+func listStoredOrders() ([]orders.Order, error) {
+	store, err := orders.DefaultStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open order store: %w", err)
+	}
+	return store.List(), nil
+}
+
+// registerOrderLookupRoutes exposes one stored order
+func registerOrderLookupRoutes(srv *admin.Server, get func(orderID string) (orders.Order, error)) {
+	srv.HandleFunc("GET /orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		order, err := get(r.PathValue("id"))
+		switch {
+		case errors.Is(err, orders.ErrOrderNotFound):
+			admin.WriteError(w, http.StatusNotFound, err)
+		case err != nil:
+			admin.WriteError(w, http.StatusServiceUnavailable, err)
+		default:
+			admin.WriteJSON(w, http.StatusOK, order)
+		}
+	})
+}
+
+// getStoredOrder reads one order from the order store
+func getStoredOrder(orderID string) (orders.Order, error) {
+	store, err := orders.DefaultStore()
+	if err != nil {
+		return orders.Order{}, fmt.Errorf("failed to open order store: %w", err)
+	}
+	order, ok := store.Get(orderID)
+	if !ok {
+		return orders.Order{}, fmt.Errorf("%w: %s", orders.ErrOrderNotFound, orderID)
+	}
+	return order, nil
+}
+
+// registerEventRoutes exposes the latest order lifecycle events
+func registerEventRoutes(srv *admin.Server, recorder *events.Recorder) {
+	srv.HandleFunc("GET /events", func(w http.ResponseWriter, r *http.Request) {
+		limit := 0
+		if value := r.URL.Query().Get("limit"); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				admin.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", value))
+				return
+			}
+			limit = n
+		}
+		admin.WriteJSON(w, http.StatusOK, recorder.Recent(limit))
+	})
+}
+
+// registerBalanceRoutes exposes the balances of the last balance check
+func registerBalanceRoutes(srv *admin.Server, last func() []contracts.TokenBalance) {
+	srv.HandleFunc("GET /balances", func(w http.ResponseWriter, _ *http.Request) {
+		balances := last()
+		if balances == nil {
+			// Not checked yet, or the Hyperlane7683 solver is not running
+			balances = []contracts.TokenBalance{}
+		}
+		admin.WriteJSON(w, http.StatusOK, balances)
+	})
+}
+
+// registerMetricsRoutes exposes the metrics registry to Prometheus
+func registerMetricsRoutes(srv *admin.Server, registry *metrics.Registry) {
+	srv.HandleFunc("GET /metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = registry.WriteText(w)
+	})
+}
+
+// errSolverNotRunning is returned by manual order actions before the Hyperlane7683 solver is initialized
+var errSolverNotRunning = errors.New("hyperlane7683 solver is not running")
+
+// orderAction is a manual action on a stored order, returning the updated order
+type orderAction func(ctx context.Context, orderID string) (orders.Order, error)
+
+// registerOrderRoutes exposes the manual fill and settlement of stored orders; every call is
+// written to the audit log, whatever its outcome
+func registerOrderRoutes(srv *admin.Server, fill, settle orderAction, audit *admin.AuditLog) {
+	handle := func(pattern, action string, run orderAction) {
+		srv.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			orderID := r.PathValue("id")
+			fmt.Printf("🛠️  %s of order %s requested by %s\n", action, orderID, r.RemoteAddr)
+
+			// An operator dropping the connection must not abandon a transaction half-sent
+			order, err := run(context.WithoutCancel(r.Context()), orderID)
+			if auditErr := audit.Record(r, action, orderID, err); auditErr != nil {
+				fmt.Printf("⚠️  Failed to audit %s of order %s: %v\n", action, orderID, auditErr)
+			}
+			if err != nil {
+				admin.WriteError(w, orderActionStatus(err), err)
+				return
+			}
+			admin.WriteJSON(w, http.StatusOK, order)
+		})
+	}
+	handle("POST /orders/{id}/fill", "order.fill", fill)
+	handle("POST /orders/{id}/settle", "order.settle", settle)
+}
+
+// orderActionStatus maps a failed manual order action to its HTTP status
+func orderActionStatus(err error) int {
+	switch {
+	case errors.Is(err, orders.ErrOrderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, contracts.ErrActionNotAllowed):
+		return http.StatusConflict
+	case errors.Is(err, errSolverNotRunning):
+		return http.StatusServiceUnavailable
+	default:
+		// Refused by the safety checks, or the transaction failed
+		return http.StatusUnprocessableEntity
+	}
+}
+
+// forceFill fills a stored order through the Hyperlane7683 solver
+func (sm *SolverManager) forceFill(ctx context.Context, orderID string) (orders.Order, error) {
+	solver := sm.hyperlaneSolver()
+	if solver == nil {
+		return orders.Order{}, errSolverNotRunning
+	}
+	return solver.ForceFill(ctx, orderID)
+}
+
+// forceSettle settles a stored order through the Hyperlane7683 solver
+func (sm *SolverManager) forceSettle(ctx context.Context, orderID string) (orders.Order, error) {
+	solver := sm.hyperlaneSolver()
+	if solver == nil {
+		return orders.Order{}, errSolverNotRunning
+	}
+	return solver.ForceSettle(ctx, orderID)
+}
+
+// lastBalances returns the balances of the balance monitor's last check
+func (sm *SolverManager) lastBalances() []contracts.TokenBalance {
+	sm.hyperlaneMu.RLock()
+	monitor := sm.balanceMonitor
+	sm.hyperlaneMu.RUnlock()
+	if monitor == nil {
+		return nil
+	}
+	return monitor.LastBalances()
+}
@@ -0,0 +1,463 @@
+package solvercore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/admin"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/events"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpendLimitRoutes(t *testing.T) {
+	limiter := contracts.NewSpendLimiter(big.NewInt(100), nil)
+	srv := admin.NewServer("127.0.0.1:0", "")
+	registerSpendLimitRoutes(srv, limiter)
+
+	request := func(method, target string) (*httptest.ResponseRecorder, []contracts.SpendStatus) {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+		var statuses []contracts.SpendStatus
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &statuses))
+		}
+		return rec, statuses
+	}
+	spend := func() {
+		limiter.RecordTx(orders.TxCost{ChainID: 1, Fee: big.NewInt(100)})
+		limiter.RecordTx(orders.TxCost{ChainID: 2, Fee: big.NewInt(40)})
+	}
+
+	t.Run("status lists spending per chain", func(t *testing.T) {
+		spend()
+		defer limiter.ResetAll()
+
+		rec, statuses := request(http.MethodGet, "/spend-limits")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		require.Len(t, statuses, 2)
+		assert.Equal(t, "100", statuses[0].Gas)
+		assert.Equal(t, "100", statuses[0].MaxGas)
+	})
+
+	t.Run("reset one chain", func(t *testing.T) {
+		spend()
+		defer limiter.ResetAll()
+		require.Error(t, limiter.CheckGas(1, nil))
+
+		rec, statuses := request(http.MethodPost, "/spend-limits/reset?chain_id=1")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		require.Len(t, statuses, 1)
+		assert.Equal(t, uint64(2), statuses[0].ChainID)
+		assert.NoError(t, limiter.CheckGas(1, nil))
+	})
+
+	t.Run("reset all chains", func(t *testing.T) {
+		spend()
+		rec, statuses := request(http.MethodPost, "/spend-limits/reset")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, statuses)
+	})
+
+	t.Run("invalid chain id is rejected", func(t *testing.T) {
+		rec, _ := request(http.MethodPost, "/spend-limits/reset?chain_id=base")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "invalid chain_id")
+	})
+
+	t.Run("reset requires POST", func(t *testing.T) {
+		rec, _ := request(http.MethodGet, "/spend-limits/reset")
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
+
+func TestTxRateRoutes(t *testing.T) {
+	limiter := contracts.NewTxRateLimiter(contracts.TxRateLimits{MaxPending: 2})
+	release, err := limiter.Acquire(context.Background(), 84532)
+	require.NoError(t, err)
+	defer release()
+
+	srv := admin.NewServer("127.0.0.1:0", "")
+	registerTxRateRoutes(srv, limiter)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tx-limits", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var statuses []contracts.TxRateStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &statuses))
+	assert.Equal(t, []contracts.TxRateStatus{{ChainID: 84532, Pending: 1, SentLastMinute: 1, MaxPending: 2}}, statuses)
+}
+
+func TestConfigRoutes(t *testing.T) {
+	srv := admin.NewServer("127.0.0.1:0", "")
+	var reloadErr error
+	reloaded := &config.RuntimeConfig{Rules: config.RulesConfig{MinProfit: "9"}}
+	registerConfigRoutes(srv, func() (*config.RuntimeConfig, error) {
+		if reloadErr != nil {
+			return nil, reloadErr
+		}
+		return reloaded, nil
+	})
+
+	t.Run("active config", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/config", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"allowBlockLists"`)
+	})
+
+	t.Run("reload returns the new config", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/config/reload", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"minProfit":"9"`)
+	})
+
+	t.Run("failed reload is reported", func(t *testing.T) {
+		reloadErr = errors.New("config reload failed: invalid runtime config")
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/config/reload", nil))
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), "invalid runtime config")
+	})
+}
+
+func TestNetworkRoutes(t *testing.T) {
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+
+	srv := admin.NewServer("127.0.0.1:0", "")
+	registerNetworkRoutes(srv)
+	request := func(method, target string) (*httptest.ResponseRecorder, config.NetworkFlags) {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+		var flags config.NetworkFlags
+		if rec.Code == http.StatusOK && method == http.MethodPost {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &flags))
+		}
+		return rec, flags
+	}
+
+	t.Run("pause and resume fills", func(t *testing.T) {
+		rec, flags := request(http.MethodPost, "/networks/Base/fills/pause")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, config.NetworkFlags{Name: "Base", Enabled: true, FillsEnabled: false}, flags)
+		assert.False(t, config.FillsAllowedByChainID(config.BaseSepoliaChainID))
+
+		_, flags = request(http.MethodPost, "/networks/Base/fills/resume")
+		assert.True(t, flags.FillsEnabled)
+		assert.True(t, config.FillsAllowedByChainID(config.BaseSepoliaChainID))
+	})
+
+	t.Run("pause and resume a network", func(t *testing.T) {
+		_, flags := request(http.MethodPost, "/networks/Starknet/pause")
+		assert.False(t, flags.Enabled)
+		assert.False(t, config.NetworkEnabled("Starknet"))
+
+		_, flags = request(http.MethodPost, "/networks/Starknet/resume")
+		assert.True(t, flags.Enabled)
+	})
+
+	t.Run("list networks", func(t *testing.T) {
+		request(http.MethodPost, "/networks/Arbitrum/fills/pause")
+		rec, _ := request(http.MethodGet, "/networks")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var all []config.NetworkFlags
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &all))
+		require.NotEmpty(t, all)
+		assert.Equal(t, config.NetworkFlags{Name: "Arbitrum", Enabled: true, FillsEnabled: false}, all[0])
+	})
+
+	t.Run("unknown network", func(t *testing.T) {
+		rec, _ := request(http.MethodPost, "/networks/Solana/pause")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "network not found")
+	})
+}
+
+func TestListenerRoutes(t *testing.T) {
+	sm := NewSolverManager(nil)
+	status := contracts.NewListenerStatus("Base", "EVM", 90)
+	status.RecordHead(100)
+	status.RecordEvents(3)
+	status.RecordError(errors.New("rpc down"))
+	sm.listeners = []base.Listener{&snapshotListener{status: status}}
+
+	srv := admin.NewServer("127.0.0.1:0", "")
+	registerListenerRoutes(srv, sm.ListenerSnapshots)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/listeners", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var snapshots []base.ListenerSnapshot
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &snapshots))
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, "Base", snapshots[0].ChainName)
+	assert.Equal(t, uint64(10), snapshots[0].Lag)
+	assert.Equal(t, uint64(3), snapshots[0].EventsSeen)
+	assert.Equal(t, "rpc down", snapshots[0].LastError)
+	assert.NotNil(t, snapshots[0].LastErrorAt)
+	assert.Contains(t, rec.Body.String(), `"lastProcessedBlock":90`)
+}
+
+func TestCompetitionRoutes(t *testing.T) {
+	tracker := contracts.NewCompetitionTracker()
+	tracker.SetOwnFillCheck(func(_, txHash string) bool { return txHash == "0xours" })
+	tracker.RecordFill("Base", "0x01", "0xours", 10)
+	tracker.RecordFill("Base", "0x02", "0xtheirs", 11)
+
+	srv := admin.NewServer("127.0.0.1:0", "")
+	registerCompetitionRoutes(srv, tracker)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/competition", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var stats []contracts.CompetitionStats
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	require.Len(t, stats, 1)
+	assert.Equal(t, "Base", stats[0].ChainName)
+	assert.Equal(t, uint64(1), stats[0].OwnFills)
+	assert.Equal(t, uint64(1), stats[0].CompetitorFills)
+	assert.NotNil(t, stats[0].LastCompetitorFillAt)
+}
+
+func TestMetricsRoutes(t *testing.T) {
+	registry := metrics.NewRegistry()
+	registry.Counter("solver_filled_volume_total", "origin", "Base", "destination", "Starknet", "token", "USDC").Add(12.5)
+
+	srv := admin.NewServer("127.0.0.1:0", "")
+	registerMetricsRoutes(srv, registry)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, rec.Body.String(), `solver_filled_volume_total{destination="Starknet",origin="Base",token="USDC"} 12.5`)
+}
+
+func TestOrderListRoutes(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	stored := []orders.Order{
+		{OrderID: "0x1", Status: orders.StatusSettled, CreatedAt: start, UpdatedAt: start.Add(3 * time.Minute)},
+		{OrderID: "0x2", Status: orders.StatusObserved, CreatedAt: start.Add(time.Minute), UpdatedAt: start.Add(time.Minute)},
+		{OrderID: "0x3", Status: orders.StatusFilled, CreatedAt: start.Add(2 * time.Minute), UpdatedAt: start.Add(5 * time.Minute)},
+	}
+	var listErr error
+	srv := admin.NewServer("127.0.0.1:0", "")
+	registerOrderListRoutes(srv, func() ([]orders.Order, error) { return stored, listErr })
+
+	get := func(t *testing.T, target string) (*httptest.ResponseRecorder, []string) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		var records []orders.ExportRecord
+		ids := []string{}
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &records))
+			for _, record := range records {
+				ids = append(ids, record.OrderID)
+			}
+		}
+		return rec, ids
+	}
+
+	t.Run("most recently updated first", func(t *testing.T) {
+		rec, ids := get(t, "/orders")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []string{"0x3", "0x1", "0x2"}, ids)
+	})
+
+	t.Run("status filter and limit", func(t *testing.T) {
+		_, ids := get(t, "/orders?status=observed,FILLED")
+		assert.Equal(t, []string{"0x3", "0x2"}, ids)
+		_, ids = get(t, "/orders?limit=1")
+		assert.Equal(t, []string{"0x3"}, ids)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		rec, _ := get(t, "/orders?status=DONE")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		rec, _ = get(t, "/orders?limit=0")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("store unavailable", func(t *testing.T) {
+		listErr = errors.New("database down")
+		defer func() { listErr = nil }()
+		rec, _ := get(t, "/orders")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
+
+func TestOrderLookupRoutes(t *testing.T) {
+	srv := admin.NewServer("127.0.0.1:0", "")
+	registerOrderLookupRoutes(srv, func(orderID string) (orders.Order, error) {
+		switch orderID {
+		case "0x1":
+			return orders.Order{OrderID: "0x1", Status: orders.StatusFilled}, nil
+		case "0x2":
+			return orders.Order{}, errors.New("database down")
+		default:
+			return orders.Order{}, orders.ErrOrderNotFound
+		}
+	})
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	rec := get("/orders/0x1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var order orders.Order
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &order))
+	assert.Equal(t, orders.StatusFilled, order.Status)
+
+	assert.Equal(t, http.StatusServiceUnavailable, get("/orders/0x2").Code)
+	assert.Equal(t, http.StatusNotFound, get("/orders/0x3").Code)
+}
+
+func TestEventRoutes(t *testing.T) {
+	recorder := events.NewRecorder(10)
+	bus := events.NewBus()
+	stop := recorder.Start(context.Background(), bus)
+	defer stop()
+	srv := admin.NewServer("127.0.0.1:0", "")
+	registerEventRoutes(srv, recorder)
+
+	bus.Publish(events.Event{Kind: events.OrderObserved, OrderID: "0x1"})
+	bus.Publish(events.Event{Kind: events.OrderFilled, OrderID: "0x1"})
+	require.Eventually(t, func() bool { return len(recorder.Recent(0)) == 2 }, time.Second, 10*time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events?limit=1", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list []events.Event
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, events.OrderFilled, list[0].Kind)
+
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events?limit=-1", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestBalanceRoutes(t *testing.T) {
+	sm := NewSolverManager(nil)
+	srv := admin.NewServer("127.0.0.1:0", "")
+	registerBalanceRoutes(srv, sm.lastBalances)
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/balances", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[]`, rec.Body.String(), "no balance monitor yet")
+
+	srv = admin.NewServer("127.0.0.1:0", "")
+	registerBalanceRoutes(srv, func() []contracts.TokenBalance {
+		return []contracts.TokenBalance{{ChainID: 84532, Network: "Base", Symbol: "DOG", Balance: "50", Threshold: "100", Low: true}}
+	})
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/balances", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var balances []contracts.TokenBalance
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &balances))
+	require.Len(t, balances, 1)
+	assert.True(t, balances[0].Low)
+}
+
+// snapshotListener is a listener that only reports a status
+type snapshotListener struct {
+	base.Listener
+	status *contracts.ListenerStatus
+}
+
+func (l *snapshotListener) Snapshot() base.ListenerSnapshot {
+	return l.status.Snapshot()
+}
+
+func TestOrderRoutes(t *testing.T) {
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	srv := admin.NewServer("127.0.0.1:0", "secret")
+	var calls []string
+	fill := func(_ context.Context, orderID string) (orders.Order, error) {
+		calls = append(calls, "fill "+orderID)
+		if orderID == "0xmissing" {
+			return orders.Order{}, fmt.Errorf("%w: %s", orders.ErrOrderNotFound, orderID)
+		}
+		return orders.Order{OrderID: orderID, Status: orders.StatusFilled}, nil
+	}
+	settle := func(_ context.Context, orderID string) (orders.Order, error) {
+		calls = append(calls, "settle "+orderID)
+		return orders.Order{OrderID: orderID, Status: orders.StatusObserved}, fmt.Errorf("%w: order %s is OBSERVED", contracts.ErrActionNotAllowed, orderID)
+	}
+	registerOrderRoutes(srv, fill, settle, admin.NewAuditLog(auditPath))
+
+	post := func(path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("requires the token", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, post("/orders/0x01/fill", "").Code)
+		assert.Empty(t, calls)
+	})
+
+	t.Run("fill", func(t *testing.T) {
+		rec := post("/orders/0x01/fill", "secret")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var order orders.Order
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &order))
+		assert.Equal(t, orders.StatusFilled, order.Status)
+
+		assert.Equal(t, http.StatusNotFound, post("/orders/0xmissing/fill", "secret").Code)
+	})
+
+	t.Run("settle not allowed", func(t *testing.T) {
+		rec := post("/orders/0x01/settle", "secret")
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Contains(t, rec.Body.String(), "is OBSERVED")
+	})
+
+	t.Run("every action is audited", func(t *testing.T) {
+		assert.Equal(t, []string{"fill 0x01", "fill 0xmissing", "settle 0x01"}, calls)
+
+		data, err := os.ReadFile(auditPath)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, lines, 3)
+
+		var entry admin.AuditEntry
+		require.NoError(t, json.Unmarshal([]byte(lines[2]), &entry))
+		assert.Equal(t, "order.settle", entry.Action)
+		assert.Equal(t, "0x01", entry.Target)
+		assert.Equal(t, "error", entry.Result)
+		assert.Contains(t, entry.Error, "action not allowed")
+	})
+
+	t.Run("solver not running", func(t *testing.T) {
+		_, err := (&SolverManager{}).forceSettle(context.Background(), "0x01")
+		assert.ErrorIs(t, err, errSolverNotRunning)
+		assert.Equal(t, http.StatusServiceUnavailable, orderActionStatus(err))
+	})
+}
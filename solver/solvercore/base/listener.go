@@ -3,6 +3,7 @@ package base
 import (
 	"context"
 	"math/big"
+	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 )
@@ -24,6 +25,22 @@ type Listener interface {
 
 	// GetLastProcessedBlock returns the last processed block number
 	GetLastProcessedBlock() uint64
+
+	// Snapshot returns the listener's current block progress
+	Snapshot() ListenerSnapshot
+}
+
+// ListenerSnapshot reports a listener's block progress for monitoring
+type ListenerSnapshot struct {
+	ChainName          string     `json:"chainName"`
+	NetworkType        string     `json:"networkType"`
+	Head               uint64     `json:"head"`               // latest block seen on the chain
+	LastProcessedBlock uint64     `json:"lastProcessedBlock"` // highest block fully processed
+	Lag                uint64     `json:"lag"`                // head - lastProcessedBlock, includes the confirmations window
+	EventsSeen         uint64     `json:"eventsSeen"`         // Open events fetched since start
+	LastError          string     `json:"lastError,omitempty"`
+	LastErrorAt        *time.Time `json:"lastErrorAt,omitempty"`
+	LastPollAt         *time.Time `json:"lastPollAt,omitempty"` // last successful poll
 }
 
 // ListenerConfig contains configuration for a listener
@@ -0,0 +1,67 @@
+package config
+
+// Module: Chain ID sanity check
+// - The solver refuses to start when the node behind a network's RPC URL reports another chain than
+//   the configured <NAME>_CHAIN_ID, so an .env typo cannot make it sign fills for the wrong network
+// - EVM nodes report the numeric chain ID, which is compared as is
+// - Starknet nodes report a short string (SN_SEPOLIA, SN_MAIN) while Starknet networks are configured
+//   with Hyperlane chain IDs: known names map to their chain ID, anything else is read as a felt, which
+//   covers Katana started with --chain-id 23448591
+// - <NAME>_NODE_CHAIN_ID accepts the chain ID a Starknet node reports when neither rule applies, e.g.
+//   an appchain reporting its own name
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+)
+
+// StarknetMainnetChainID is the Hyperlane chain ID of Starknet mainnet
+const StarknetMainnetChainID = 358974494
+
+// starknetChainNames maps the Hyperlane chain IDs of the public Starknet networks to the chain ID
+// their nodes report
+var starknetChainNames = map[uint64]string{
+	StarknetSepoliaChainID: "SN_SEPOLIA",
+	StarknetMainnetChainID: "SN_MAIN",
+}
+
+// NodeChainIDEnv returns the variable overriding the chain ID a Starknet network's node reports
+func NodeChainIDEnv(networkName string) string {
+	return NetworkEnvPrefix(networkName) + "_NODE_CHAIN_ID"
+}
+
+// VerifyEVMChainID fails when an EVM node reports another chain ID than the network's configured one
+func VerifyEVMChainID(network NetworkConfig, reported *big.Int) error {
+	if reported == nil || !reported.IsUint64() || reported.Uint64() != network.ChainID {
+		return chainIDMismatch(network, fmt.Sprint(reported))
+	}
+	return nil
+}
+
+// VerifyStarknetChainID fails when a Starknet node reports another chain than the network's
+// configured one; reported is the chain ID as returned by the provider, a decoded short string
+func VerifyStarknetChainID(network NetworkConfig, reported string) error {
+	if expected := envutil.GetEnvWithDefault(NodeChainIDEnv(network.Name), ""); expected != "" {
+		if reported != expected {
+			return fmt.Errorf("%s RPC reports chain ID %q, %s expects %q", network.Name, reported, NodeChainIDEnv(network.Name), expected)
+		}
+		return nil
+	}
+	if name, ok := starknetChainNames[network.ChainID]; ok && reported == name {
+		return nil
+	}
+	// Nodes started with a numeric chain ID report it as a felt, which the provider decodes as a string
+	if new(big.Int).SetBytes([]byte(reported)).Cmp(new(big.Int).SetUint64(network.ChainID)) == 0 {
+		return nil
+	}
+	return chainIDMismatch(network, fmt.Sprintf("%q", reported))
+}
+
+// chainIDMismatch describes a chain ID mismatch with the variables to check
+func chainIDMismatch(network NetworkConfig, reported string) error {
+	prefix := NetworkEnvPrefix(network.Name)
+	return fmt.Errorf("%s RPC reports chain ID %s, configured %d (check %s_RPC_URL and %s_CHAIN_ID)",
+		network.Name, reported, network.ChainID, prefix, prefix)
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyEVMChainID(t *testing.T) {
+	network := NetworkConfig{Name: "Base", ChainID: BaseSepoliaChainID}
+
+	t.Run("matching chain ID", func(t *testing.T) {
+		assert.NoError(t, VerifyEVMChainID(network, big.NewInt(BaseSepoliaChainID)))
+	})
+
+	t.Run("mismatch names the variables to check", func(t *testing.T) {
+		err := VerifyEVMChainID(network, big.NewInt(EthereumSepoliaChainID))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Base RPC reports chain ID 11155111, configured 84532")
+		assert.Contains(t, err.Error(), "BASE_RPC_URL and BASE_CHAIN_ID")
+	})
+
+	t.Run("missing or oversized chain ID", func(t *testing.T) {
+		assert.Error(t, VerifyEVMChainID(network, nil))
+		assert.Error(t, VerifyEVMChainID(network, new(big.Int).Lsh(big.NewInt(1), 64)))
+	})
+}
+
+func TestVerifyStarknetChainID(t *testing.T) {
+	sepolia := NetworkConfig{Name: "Starknet", ChainID: StarknetSepoliaChainID}
+
+	t.Run("known network names", func(t *testing.T) {
+		assert.NoError(t, VerifyStarknetChainID(sepolia, "SN_SEPOLIA"))
+		assert.NoError(t, VerifyStarknetChainID(NetworkConfig{Name: "Starknet", ChainID: StarknetMainnetChainID}, "SN_MAIN"))
+		assert.Error(t, VerifyStarknetChainID(sepolia, "SN_MAIN"))
+	})
+
+	t.Run("numeric chain ID decoded as a short string", func(t *testing.T) {
+		katana := string(new(big.Int).SetUint64(StarknetSepoliaChainID).Bytes())
+		assert.NoError(t, VerifyStarknetChainID(sepolia, katana))
+		assert.Error(t, VerifyStarknetChainID(NetworkConfig{Name: "Starknet", ChainID: 1}, katana))
+	})
+
+	t.Run("unknown names are refused", func(t *testing.T) {
+		appchain := NetworkConfig{Name: "Starknet Appchain", ChainID: 424242}
+		err := VerifyStarknetChainID(appchain, "SN_APPCHAIN")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "STARKNET_APPCHAIN_RPC_URL")
+	})
+
+	t.Run("node chain ID override", func(t *testing.T) {
+		appchain := NetworkConfig{Name: "Starknet Appchain", ChainID: 424242}
+		t.Setenv("STARKNET_APPCHAIN_NODE_CHAIN_ID", "SN_APPCHAIN")
+		assert.NoError(t, VerifyStarknetChainID(appchain, "SN_APPCHAIN"))
+
+		err := VerifyStarknetChainID(appchain, "SN_SEPOLIA")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "STARKNET_APPCHAIN_NODE_CHAIN_ID expects \"SN_APPCHAIN\"")
+	})
+}
@@ -0,0 +1,97 @@
+package config
+
+// Module: Checkpoint storage
+// - With a database storage backend the last indexed block of each network and the seen Open
+//   events are kept only in its checkpoints and seen_events collections; the solver state file is
+//   not written for them, so a busy solver doesn't rewrite it on every block range and event, and
+//   a standby sharing the database takes over both
+// - Networks without a stored checkpoint start from the solver state file (e.g. right after moving
+//   to a database), then from their configured start block
+// - The file backend needs no copy: the state file already is its checkpoint store
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
+)
+
+var (
+	checkpointStorageMu sync.RWMutex
+	checkpointStorage   storage.Storage
+)
+
+// SetCheckpointStorage keeps the checkpoints and seen events in s instead of the solver state file;
+// nil goes back to the file. The seen events are reloaded from the new store.
+func SetCheckpointStorage(s storage.Storage) {
+	checkpointStorageMu.Lock()
+	checkpointStorage = s
+	checkpointStorageMu.Unlock()
+	ResetSeenEvents()
+}
+
+func getCheckpointStorage() storage.Storage {
+	checkpointStorageMu.RLock()
+	defer checkpointStorageMu.RUnlock()
+	return checkpointStorage
+}
+
+// putCheckpoint stores the checkpoint of a network in s
+func putCheckpoint(s storage.Storage, networkName string, network SolverNetworkState) error {
+	doc, err := json.Marshal(network)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	return s.Put(storage.Checkpoints, networkName, doc)
+}
+
+// overlayCheckpoints replaces the checkpoints of state with those of the checkpoint storage, if any
+func overlayCheckpoints(state *SolverState) error {
+	s := getCheckpointStorage()
+	if s == nil {
+		return nil
+	}
+	docs, err := s.Load(storage.Checkpoints)
+	if err != nil {
+		return err
+	}
+	for networkName, doc := range docs {
+		if _, ok := state.Networks[networkName]; !ok {
+			continue
+		}
+		var network SolverNetworkState
+		if err := json.Unmarshal(doc, &network); err != nil {
+			return fmt.Errorf("failed to parse checkpoint of %s: %w", networkName, err)
+		}
+		state.Networks[networkName] = network
+	}
+	return nil
+}
+
+// loadStoredSeenEvents returns the seen events of s by key
+func loadStoredSeenEvents(s storage.Storage) (map[string]time.Time, error) {
+	docs, err := s.Load(storage.SeenEvents)
+	if err != nil {
+		return nil, err
+	}
+	events := make(map[string]time.Time, len(docs))
+	for key, doc := range docs {
+		var seenAt int64
+		if err := json.Unmarshal(doc, &seenAt); err != nil {
+			return nil, fmt.Errorf("failed to parse seen event %s: %w", key, err)
+		}
+		events[key] = time.Unix(seenAt, 0)
+	}
+	return events, nil
+}
+
+// putSeenEvent stores when an event was handled in s, in unix seconds
+func putSeenEvent(s storage.Storage, key string, seenAt time.Time) error {
+	doc, err := json.Marshal(seenAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to marshal seen event: %w", err)
+	}
+	return s.Put(storage.SeenEvents, key, doc)
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckpointStorage tests keeping checkpoints and seen events in a storage backend instead of
+// the state file
+func TestCheckpointStorage(t *testing.T) {
+	var stateFile string
+	setup := func(t *testing.T) storage.Storage {
+		t.Helper()
+		stateFile = filepath.Join(t.TempDir(), "solver-state.json")
+		t.Setenv("SOLVER_STATE_FILE", stateFile)
+		ResetNetworks()
+		InitializeNetworks()
+		t.Cleanup(ResetNetworks)
+		s := storage.NewFileStorage(t.TempDir(), nil)
+		SetCheckpointStorage(s)
+		t.Cleanup(func() { SetCheckpointStorage(nil) })
+		return s
+	}
+
+	t.Run("updates are stored", func(t *testing.T) {
+		s := setup(t)
+		require.NoError(t, UpdateLastIndexedBlock("Ethereum", 1234))
+
+		docs, err := s.Load(storage.Checkpoints)
+		require.NoError(t, err)
+		var network SolverNetworkState
+		require.NoError(t, json.Unmarshal(docs["Ethereum"], &network))
+		assert.Equal(t, uint64(1234), network.LastIndexedBlock)
+	})
+
+	t.Run("updates don't write the state file", func(t *testing.T) {
+		setup(t)
+		require.NoError(t, UpdateLastIndexedBlock("Ethereum", 1234))
+		state, err := GetSolverState()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1234), state.Networks["Ethereum"].LastIndexedBlock)
+		_, err = os.Stat(stateFile)
+		assert.True(t, os.IsNotExist(err))
+
+		assert.ErrorContains(t, UpdateLastIndexedBlock("Unconfigured", 1), "not found")
+	})
+
+	t.Run("stored checkpoints take precedence over the state file", func(t *testing.T) {
+		s := setup(t)
+		SetCheckpointStorage(nil)
+		require.NoError(t, UpdateLastIndexedBlock("Ethereum", 100))
+		require.NoError(t, UpdateLastIndexedBlock("Base", 200))
+		SetCheckpointStorage(s)
+		require.NoError(t, s.Put(storage.Checkpoints, "Ethereum", json.RawMessage(`{"lastIndexedBlock":5000}`)))
+		require.NoError(t, s.Put(storage.Checkpoints, "Unconfigured", json.RawMessage(`{"lastIndexedBlock":1}`)))
+
+		state, err := GetSolverState()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(5000), state.Networks["Ethereum"].LastIndexedBlock)
+		assert.Equal(t, uint64(200), state.Networks["Base"].LastIndexedBlock, "networks without a stored checkpoint keep the state file's")
+		assert.NotContains(t, state.Networks, "Unconfigured")
+	})
+
+	t.Run("seen events are stored with the checkpoint and shared", func(t *testing.T) {
+		s := setup(t)
+		key := SeenEventKey("Base", "0x01", "0xaa", 0)
+		MarkEventSeen(key)
+		require.NoError(t, UpdateLastIndexedBlock("Base", 100))
+		_, err := os.Stat(stateFile)
+		assert.True(t, os.IsNotExist(err), "the state file is not written")
+
+		docs, err := s.Load(storage.SeenEvents)
+		require.NoError(t, err)
+		assert.Contains(t, docs, key)
+
+		// Another instance using the same database sees the event
+		ResetSeenEvents()
+		assert.True(t, EventSeen(key))
+	})
+}
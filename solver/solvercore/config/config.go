@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
@@ -20,6 +21,10 @@ type Config struct {
 	LogLevel   string                  `json:"logLevel"`
 	LogFormat  string                  `json:"logFormat"`
 	MaxRetries int                     `json:"maxRetries"`
+	// DomainMappings adds Hyperlane domain -> chain ID pairs on top of the configured networks
+	DomainMappings []DomainMapping `json:"domainMappings"`
+	// Settings that can be reloaded at runtime (see ReloadRuntime)
+	RuntimeConfig
 }
 
 // Default solver configurations
@@ -29,7 +34,8 @@ var defaultSolvers = map[string]SolverConfig{
 	},
 }
 
-// LoadConfig loads configuration from environment variables
+// LoadConfig loads configuration from the optional config file (SOLVER_CONFIG_FILE)
+// and environment variables; environment variables take precedence over the file
 func LoadConfig() (*Config, error) {
 	// Load .env file first
 	if err := godotenv.Load(); err != nil {
@@ -50,6 +56,13 @@ func LoadConfig() (*Config, error) {
 		config.Solvers[name] = solver
 	}
 
+	// Apply the optional JSON config file on top of defaults
+	if path := os.Getenv("SOLVER_CONFIG_FILE"); path != "" {
+		if err := loadConfigFile(path, config); err != nil {
+			return nil, err
+		}
+	}
+
 	// Override with environment variables
 	if logLevel := os.Getenv("LOG_LEVEL"); logLevel != "" {
 		config.LogLevel = logLevel
@@ -83,6 +96,18 @@ func LoadConfig() (*Config, error) {
 	return config, nil
 }
 
+// loadConfigFile reads a JSON config file into config, keeping defaults for absent fields
+func loadConfigFile(path string, config *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
 // IsSolverEnabled checks if a solver is enabled
 func (c *Config) IsSolverEnabled(solverName string) bool {
 	solver, exists := c.Solvers[solverName]
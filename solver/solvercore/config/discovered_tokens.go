@@ -0,0 +1,108 @@
+package config
+
+// Module: Discovered token metadata
+// - Symbol and decimals read on-chain for tokens missing from the token registry
+// - Persisted in the solver state file and kept in memory, so each token is queried once
+// - LookupToken consults the registry first; discovered tokens are used for display and amount
+//   normalization only, they do not pass the TokenCheck rule
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+var (
+	discoveredTokens   map[tokenKey]TokenConfig // nil until loaded from the state file
+	discoveredTokensMu sync.Mutex
+)
+
+// ResetDiscoveredTokens drops the in-memory cache so it is reloaded from the state file
+func ResetDiscoveredTokens() {
+	discoveredTokensMu.Lock()
+	defer discoveredTokensMu.Unlock()
+	discoveredTokens = nil
+}
+
+// discoveredTokenKey is the key of a token in SolverState.Tokens
+func discoveredTokenKey(key tokenKey) string {
+	return fmt.Sprintf("%d:%s", key.chainID, key.address)
+}
+
+// loadDiscoveredTokensLocked fills the cache from the state file; callers hold discoveredTokensMu.
+// A missing state file is not created here, logging an amount must not write state.
+func loadDiscoveredTokensLocked() {
+	if discoveredTokens != nil {
+		return
+	}
+	discoveredTokens = make(map[tokenKey]TokenConfig)
+	if _, err := os.Stat(getSolverStateFilePath()); err != nil {
+		return
+	}
+	state, err := GetSolverState()
+	if err != nil {
+		fmt.Printf("⚠️  Failed to load discovered tokens: %v\n", err)
+		return
+	}
+	for _, token := range state.Tokens {
+		address, err := normalizeTokenAddress(token.Address)
+		if err != nil {
+			continue
+		}
+		discoveredTokens[tokenKey{chainID: token.ChainID, address: address}] = token
+	}
+}
+
+// LookupDiscoveredToken returns metadata discovered on-chain for a token
+func LookupDiscoveredToken(chainID uint64, address string) (TokenConfig, bool) {
+	normalized, err := normalizeTokenAddress(address)
+	if err != nil {
+		return TokenConfig{}, false
+	}
+
+	discoveredTokensMu.Lock()
+	defer discoveredTokensMu.Unlock()
+	loadDiscoveredTokensLocked()
+	token, ok := discoveredTokens[tokenKey{chainID: chainID, address: normalized}]
+	return token, ok
+}
+
+// SaveDiscoveredToken caches token metadata in memory and in the solver state file.
+// Order size bounds are dropped, they only come from the registry.
+func SaveDiscoveredToken(token TokenConfig) error {
+	token.MinOrderSize, token.MaxOrderSize = "", ""
+	if err := token.validate(); err != nil {
+		return fmt.Errorf("invalid token metadata for %s: %w", token.Address, err)
+	}
+	address, _ := normalizeTokenAddress(token.Address)
+	key := tokenKey{chainID: token.ChainID, address: address}
+
+	discoveredTokensMu.Lock()
+	defer discoveredTokensMu.Unlock()
+	loadDiscoveredTokensLocked()
+
+	solverStateMu.Lock()
+	defer solverStateMu.Unlock()
+	state, err := readSolverStateLocked()
+	if err != nil {
+		return fmt.Errorf("failed to get solver state: %w", err)
+	}
+	if state.Tokens == nil {
+		state.Tokens = make(map[string]TokenConfig)
+	}
+	state.Tokens[discoveredTokenKey(key)] = token
+	if err := saveSolverStateLocked(state); err != nil {
+		return fmt.Errorf("failed to save solver state: %w", err)
+	}
+
+	discoveredTokens[key] = token
+	return nil
+}
+
+// LookupToken returns the metadata of a token from the registry, or else from discovered tokens
+func LookupToken(chainID uint64, address string) (TokenConfig, bool) {
+	if token, ok := ActiveRuntime().TokenRegistry().Lookup(chainID, address); ok {
+		return token, true
+	}
+	return LookupDiscoveredToken(chainID, address)
+}
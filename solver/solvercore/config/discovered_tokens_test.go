@@ -0,0 +1,81 @@
+package config
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoveredTokens(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "solver-state.json")
+	t.Setenv("SOLVER_STATE_FILE", stateFile)
+	ResetDiscoveredTokens()
+	t.Cleanup(ResetDiscoveredTokens)
+	restoreRuntime(t)
+
+	dog := TokenConfig{ChainID: 84532, Address: "0x00000000000000000000000000000000000000A1", Symbol: "DOG", Decimals: 6}
+
+	t.Run("lookups do not create the state file", func(t *testing.T) {
+		_, ok := LookupToken(dog.ChainID, dog.Address)
+		assert.False(t, ok)
+		assert.Equal(t, "1 (base units)", FormatTokenAmount(dog.ChainID, dog.Address, big.NewInt(1)))
+		_, err := os.Stat(stateFile)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("saved tokens are used for lookups and formatting", func(t *testing.T) {
+		withBounds := dog
+		withBounds.MaxOrderSize = "10"
+		require.NoError(t, SaveDiscoveredToken(withBounds))
+
+		token, ok := LookupToken(dog.ChainID, "0xa1")
+		require.True(t, ok)
+		assert.Equal(t, dog, token, "order size bounds are not kept")
+		assert.Equal(t, "12.5 DOG", FormatTokenAmount(dog.ChainID, dog.Address, big.NewInt(12_500_000)))
+		assert.Zero(t, ActiveRuntime().TokenRegistry().Len(), "discovered tokens are not registered")
+	})
+
+	t.Run("tokens are reloaded from the state file", func(t *testing.T) {
+		ResetDiscoveredTokens()
+		token, ok := LookupDiscoveredToken(dog.ChainID, dog.Address)
+		require.True(t, ok)
+		assert.Equal(t, "DOG", token.Symbol)
+
+		state, err := GetSolverState()
+		require.NoError(t, err)
+		assert.Contains(t, state.Tokens, "84532:0xa1")
+	})
+
+	t.Run("registry takes precedence", func(t *testing.T) {
+		registered := dog
+		registered.Symbol = "DOGE"
+		require.NoError(t, ApplyRuntime(&RuntimeConfig{Tokens: []TokenConfig{registered}}))
+		defer ApplyRuntime(&RuntimeConfig{})
+
+		token, ok := LookupToken(dog.ChainID, dog.Address)
+		require.True(t, ok)
+		assert.Equal(t, "DOGE", token.Symbol)
+	})
+
+	t.Run("invalid metadata is rejected", func(t *testing.T) {
+		err := SaveDiscoveredToken(TokenConfig{ChainID: 1, Address: "0xb2"})
+		assert.ErrorContains(t, err, "empty symbol")
+	})
+
+	t.Run("amounts are scaled to other decimals", func(t *testing.T) {
+		scaled, ok := ScaleTokenAmount(dog.ChainID, dog.Address, big.NewInt(1_500_000), 18)
+		require.True(t, ok)
+		assert.Equal(t, "1500000000000000000", scaled.String())
+
+		scaled, ok = ScaleTokenAmount(dog.ChainID, dog.Address, big.NewInt(1_500_000), 2)
+		require.True(t, ok)
+		assert.Equal(t, big.NewInt(150), scaled)
+
+		_, ok = ScaleTokenAmount(1, dog.Address, big.NewInt(1), 18)
+		assert.False(t, ok)
+	})
+}
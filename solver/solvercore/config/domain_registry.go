@@ -0,0 +1,146 @@
+package config
+
+// Module: Hyperlane domain <-> chain ID registry
+// - Built once from the configured networks plus optional custom mappings
+// - Strict lookups: unknown domains/chain IDs are errors, never silent fallbacks
+// - Rejects ambiguous configurations (one domain mapped to two chains, or vice versa)
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrUnknownDomain is returned when a Hyperlane domain has no registered chain ID
+	ErrUnknownDomain = errors.New("unknown hyperlane domain")
+	// ErrUnknownChainID is returned when a chain ID has no registered Hyperlane domain
+	ErrUnknownChainID = errors.New("unknown chain ID")
+)
+
+// DomainMapping is a single Hyperlane domain -> chain ID mapping loaded from the config file
+// Use this for chains whose domain differs from the chain ID and that are not part of Networks
+type DomainMapping struct {
+	Domain  uint32 `json:"domain"`
+	ChainID uint64 `json:"chainId"`
+}
+
+// DomainRegistry resolves Hyperlane domains to chain IDs and back
+type DomainRegistry struct {
+	domainToChain map[uint32]uint64
+	chainToDomain map[uint64]uint32
+}
+
+// NewDomainRegistry builds a registry from the network configs and custom mappings.
+// Custom mappings are applied after networks; a mapping that contradicts an existing
+// entry is rejected instead of silently overriding it.
+func NewDomainRegistry(networks map[string]NetworkConfig, custom []DomainMapping) (*DomainRegistry, error) {
+	r := &DomainRegistry{
+		domainToChain: make(map[uint32]uint64),
+		chainToDomain: make(map[uint64]uint32),
+	}
+
+	// Iterate networks in a stable order so conflict errors are deterministic
+	names := make([]string, 0, len(networks))
+	for name := range networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		network := networks[name]
+		if network.HyperlaneDomain > uint64(^uint32(0)) {
+			return nil, fmt.Errorf("network %s: hyperlane domain %d does not fit in uint32", name, network.HyperlaneDomain)
+		}
+		if err := r.add(uint32(network.HyperlaneDomain), network.ChainID); err != nil {
+			return nil, fmt.Errorf("network %s: %w", name, err)
+		}
+	}
+
+	for _, m := range custom {
+		if err := r.add(m.Domain, m.ChainID); err != nil {
+			return nil, fmt.Errorf("custom domain mapping: %w", err)
+		}
+	}
+
+	return r, nil
+}
+
+// add registers a domain <-> chain ID pair, rejecting conflicting duplicates
+func (r *DomainRegistry) add(domain uint32, chainID uint64) error {
+	if existing, ok := r.domainToChain[domain]; ok && existing != chainID {
+		return fmt.Errorf("domain %d already mapped to chain ID %d, cannot map to %d", domain, existing, chainID)
+	}
+	if existing, ok := r.chainToDomain[chainID]; ok && existing != domain {
+		return fmt.Errorf("chain ID %d already mapped to domain %d, cannot map to %d", chainID, existing, domain)
+	}
+	r.domainToChain[domain] = chainID
+	r.chainToDomain[chainID] = domain
+	return nil
+}
+
+// ChainIDForDomain returns the chain ID registered for a Hyperlane domain
+func (r *DomainRegistry) ChainIDForDomain(domain uint32) (uint64, error) {
+	chainID, ok := r.domainToChain[domain]
+	if !ok {
+		return 0, fmt.Errorf("%w: %d", ErrUnknownDomain, domain)
+	}
+	return chainID, nil
+}
+
+// DomainForChainID returns the Hyperlane domain registered for a chain ID
+func (r *DomainRegistry) DomainForChainID(chainID uint64) (uint32, error) {
+	domain, ok := r.chainToDomain[chainID]
+	if !ok {
+		return 0, fmt.Errorf("%w: %d", ErrUnknownChainID, chainID)
+	}
+	return domain, nil
+}
+
+var (
+	domainRegistry   *DomainRegistry
+	domainRegistryMu sync.RWMutex
+)
+
+// InitializeDomainRegistry builds the process-wide registry from Networks and the given
+// custom mappings. Must be called after InitializeNetworks.
+func InitializeDomainRegistry(custom []DomainMapping) error {
+	ensureInitialized()
+
+	registry, err := NewDomainRegistry(Networks, custom)
+	if err != nil {
+		return fmt.Errorf("failed to build domain registry: %w", err)
+	}
+
+	domainRegistryMu.Lock()
+	domainRegistry = registry
+	domainRegistryMu.Unlock()
+	return nil
+}
+
+// GetDomainRegistry returns the process-wide registry, building it from Networks
+// (without custom mappings) if InitializeDomainRegistry has not been called
+func GetDomainRegistry() (*DomainRegistry, error) {
+	domainRegistryMu.RLock()
+	registry := domainRegistry
+	domainRegistryMu.RUnlock()
+	if registry != nil {
+		return registry, nil
+	}
+
+	if err := InitializeDomainRegistry(nil); err != nil {
+		return nil, err
+	}
+
+	domainRegistryMu.RLock()
+	defer domainRegistryMu.RUnlock()
+	return domainRegistry, nil
+}
+
+// resetDomainRegistry drops the cached registry so it is rebuilt on next use
+func resetDomainRegistry() {
+	domainRegistryMu.Lock()
+	domainRegistry = nil
+	domainRegistryMu.Unlock()
+}
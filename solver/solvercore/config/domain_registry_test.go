@@ -0,0 +1,90 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainRegistry(t *testing.T) {
+	networks := map[string]NetworkConfig{
+		"Ethereum": {ChainID: 11155111, HyperlaneDomain: 11155111},
+		"Starknet": {ChainID: 23448591, HyperlaneDomain: 23448591},
+	}
+
+	t.Run("builds lookups from networks", func(t *testing.T) {
+		registry, err := NewDomainRegistry(networks, nil)
+		require.NoError(t, err)
+
+		chainID, err := registry.ChainIDForDomain(23448591)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(23448591), chainID)
+
+		domain, err := registry.DomainForChainID(11155111)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(11155111), domain)
+	})
+
+	t.Run("custom mappings are added", func(t *testing.T) {
+		registry, err := NewDomainRegistry(networks, []DomainMapping{{Domain: 1000, ChainID: 42}})
+		require.NoError(t, err)
+
+		chainID, err := registry.ChainIDForDomain(1000)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(42), chainID)
+
+		domain, err := registry.DomainForChainID(42)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(1000), domain)
+	})
+
+	t.Run("duplicate identical mapping is accepted", func(t *testing.T) {
+		_, err := NewDomainRegistry(networks, []DomainMapping{{Domain: 11155111, ChainID: 11155111}})
+		assert.NoError(t, err)
+	})
+
+	t.Run("conflicting domain is rejected", func(t *testing.T) {
+		_, err := NewDomainRegistry(networks, []DomainMapping{{Domain: 11155111, ChainID: 1}})
+		assert.Error(t, err)
+	})
+
+	t.Run("conflicting chain ID is rejected", func(t *testing.T) {
+		_, err := NewDomainRegistry(networks, []DomainMapping{{Domain: 1, ChainID: 23448591}})
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown lookups return sentinel errors", func(t *testing.T) {
+		registry, err := NewDomainRegistry(networks, nil)
+		require.NoError(t, err)
+
+		_, err = registry.ChainIDForDomain(999)
+		assert.True(t, errors.Is(err, ErrUnknownDomain))
+
+		_, err = registry.DomainForChainID(999)
+		assert.True(t, errors.Is(err, ErrUnknownChainID))
+	})
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	t.Run("domain mappings are read from SOLVER_CONFIG_FILE", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "solver.json")
+		content := `{"domainMappings": [{"domain": 1000, "chainId": 42}]}`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		t.Setenv("SOLVER_CONFIG_FILE", path)
+
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, []DomainMapping{{Domain: 1000, ChainID: 42}}, cfg.DomainMappings)
+	})
+
+	t.Run("missing file is an error", func(t *testing.T) {
+		t.Setenv("SOLVER_CONFIG_FILE", filepath.Join(t.TempDir(), "missing.json"))
+
+		_, err := LoadConfig()
+		assert.Error(t, err)
+	})
+}
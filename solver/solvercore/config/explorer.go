@@ -0,0 +1,90 @@
+package config
+
+// Module: Block explorer links
+// - Every network has an explorer base URL (NetworkConfig.ExplorerURL): <NETWORK>_EXPLORER_URL, else
+//   the public explorer of its chain ID (Etherscan, Arbiscan, Basescan, Voyager); local forks
+//   (IS_DEVNET=true) have none unless the variable is set
+// - TxURL and AddressURL render links for logs, webhooks and CLI output, and are empty without an
+//   explorer; Starknet explorers (Voyager, Starkscan) list addresses under /contract/, EVM ones under /address/
+// - Hyperlane7683 orders have no explorer page of their own; an order links to the transactions the
+//   solver sent for it (see ExplorerTxURL)
+//
+// Settings:
+// - <NETWORK>_EXPLORER_URL: explorer base URL of a network, e.g. https://sepolia.starkscan.co
+
+import (
+	"strings"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+)
+
+// defaultExplorerURLs are the public explorers of the well-known chains, by chain ID
+var defaultExplorerURLs = map[uint64]string{
+	1:                      "https://etherscan.io",
+	10:                     "https://optimistic.etherscan.io",
+	8453:                   "https://basescan.org",
+	42161:                  "https://arbiscan.io",
+	EthereumSepoliaChainID: "https://sepolia.etherscan.io",
+	OptimismSepoliaChainID: "https://sepolia-optimism.etherscan.io",
+	ArbitrumSepoliaChainID: "https://sepolia.arbiscan.io",
+	BaseSepoliaChainID:     "https://sepolia.basescan.org",
+	StarknetSepoliaChainID: "https://sepolia.voyager.online",
+}
+
+// explorerURL returns the explorer base URL of a network, without a trailing slash
+func explorerURL(networkName string, chainID uint64) string {
+	fallback := defaultExplorerURLs[chainID]
+	if envutil.IsDevnet() {
+		fallback = ""
+	}
+	return strings.TrimRight(envutil.GetEnvWithDefault(NetworkEnvPrefix(networkName)+"_EXPLORER_URL", fallback), "/")
+}
+
+// TxURL returns the explorer link of a transaction on the network, empty without an explorer
+func (n NetworkConfig) TxURL(txHash string) string {
+	if n.ExplorerURL == "" || txHash == "" {
+		return ""
+	}
+	return n.ExplorerURL + "/tx/" + txHash
+}
+
+// AddressURL returns the explorer link of an account or contract on the network, empty without an explorer
+func (n NetworkConfig) AddressURL(address string) string {
+	if n.ExplorerURL == "" || address == "" {
+		return ""
+	}
+	if IsStarknetNetwork(n.Name) {
+		return n.ExplorerURL + "/contract/" + address
+	}
+	return n.ExplorerURL + "/address/" + address
+}
+
+// ExplorerTxURL returns the explorer link of a transaction on the network of chainID, empty when the
+// network is unknown or has no explorer
+func ExplorerTxURL(chainID uint64, txHash string) string {
+	network, ok := networkByChainID(chainID)
+	if !ok {
+		return ""
+	}
+	return network.TxURL(txHash)
+}
+
+// ExplorerAddressURL returns the explorer link of an address on the network of chainID, empty when
+// the network is unknown or has no explorer
+func ExplorerAddressURL(chainID uint64, address string) string {
+	network, ok := networkByChainID(chainID)
+	if !ok {
+		return ""
+	}
+	return network.AddressURL(address)
+}
+
+// networkByChainID returns the configured network of chainID
+func networkByChainID(chainID uint64) (NetworkConfig, bool) {
+	for _, network := range Networks {
+		if network.ChainID == chainID {
+			return network, true
+		}
+	}
+	return NetworkConfig{}, false
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplorerLinks(t *testing.T) {
+	setup := func(t *testing.T) {
+		ResetNetworks()
+		t.Cleanup(ResetNetworks)
+		InitializeNetworks()
+	}
+
+	t.Run("public explorers by chain ID", func(t *testing.T) {
+		t.Setenv("IS_DEVNET", "false")
+		setup(t)
+
+		assert.Equal(t, "https://sepolia.basescan.org/tx/0xabc", ExplorerTxURL(BaseSepoliaChainID, "0xabc"))
+		assert.Equal(t, "https://sepolia.arbiscan.io/address/0x01", ExplorerAddressURL(ArbitrumSepoliaChainID, "0x01"))
+		assert.Equal(t, "https://sepolia.voyager.online/tx/0x0def", ExplorerTxURL(StarknetSepoliaChainID, "0x0def"))
+		assert.Equal(t, "https://sepolia.voyager.online/contract/0x02", ExplorerAddressURL(StarknetSepoliaChainID, "0x02"))
+		assert.Empty(t, ExplorerTxURL(999, "0xabc"), "unknown chain")
+		assert.Empty(t, ExplorerTxURL(BaseSepoliaChainID, ""))
+	})
+
+	t.Run("override per network", func(t *testing.T) {
+		t.Setenv("IS_DEVNET", "false")
+		t.Setenv("STARKNET_EXPLORER_URL", "https://sepolia.starkscan.co/")
+		t.Setenv("BASE_CHAIN_ID", "8453")
+		setup(t)
+
+		assert.Equal(t, "https://sepolia.starkscan.co/tx/0x0def", ExplorerTxURL(StarknetSepoliaChainID, "0x0def"))
+		assert.Equal(t, "https://basescan.org/tx/0xabc", ExplorerTxURL(8453, "0xabc"), "follows the chain ID")
+	})
+
+	t.Run("no explorer on devnet", func(t *testing.T) {
+		t.Setenv("IS_DEVNET", "true")
+		t.Setenv("ETHEREUM_EXPLORER_URL", "http://localhost:5100")
+		setup(t)
+
+		assert.Empty(t, ExplorerTxURL(BaseSepoliaChainID, "0xabc"))
+		assert.Equal(t, "http://localhost:5100/tx/0xabc", ExplorerTxURL(EthereumSepoliaChainID, "0xabc"))
+	})
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkFlags(t *testing.T) {
+	t.Setenv("BASE_FILLS_ENABLED", "false")
+	t.Setenv("OPTIMISM_ENABLED", "false")
+	ResetNetworks()
+	InitializeNetworks()
+	t.Cleanup(func() {
+		ResetNetworks()
+		InitializeNetworks()
+	})
+
+	t.Run("flags start from the network config", func(t *testing.T) {
+		flags, err := GetNetworkFlags("Base")
+		require.NoError(t, err)
+		assert.Equal(t, NetworkFlags{Name: "Base", Enabled: true, FillsEnabled: false}, flags)
+
+		assert.True(t, NetworkEnabled("Ethereum"))
+		assert.False(t, NetworkEnabled("Optimism"))
+		assert.False(t, NetworkEnabled("Solana"))
+	})
+
+	t.Run("fills need the network and its fills enabled", func(t *testing.T) {
+		assert.True(t, FillsAllowedByChainID(Networks["Ethereum"].ChainID))
+		assert.False(t, FillsAllowedByChainID(Networks["Base"].ChainID))
+		assert.False(t, FillsAllowedByChainID(Networks["Optimism"].ChainID))
+		assert.True(t, FillsAllowedByChainID(999999), "unknown chains are left to later checks")
+	})
+
+	t.Run("switches change at runtime", func(t *testing.T) {
+		flags, err := SetFillsEnabled("Base", true)
+		require.NoError(t, err)
+		assert.True(t, flags.FillsEnabled)
+		assert.True(t, FillsAllowedByChainID(Networks["Base"].ChainID))
+
+		_, err = SetNetworkEnabled("Ethereum", false)
+		require.NoError(t, err)
+		assert.False(t, NetworkEnabled("Ethereum"))
+		assert.False(t, FillsAllowedByChainID(Networks["Ethereum"].ChainID))
+		assert.True(t, Networks["Ethereum"].Enabled, "network config is not modified")
+
+		_, err = SetFillsEnabled("Solana", false)
+		assert.ErrorContains(t, err, "network not found")
+	})
+
+	t.Run("all networks are listed by name", func(t *testing.T) {
+		all := AllNetworkFlags()
+		require.Len(t, all, len(Networks))
+		assert.Equal(t, "Arbitrum", all[0].Name)
+		assert.Equal(t, NetworkFlags{Name: "Base", Enabled: true, FillsEnabled: true}, all[1])
+	})
+
+	t.Run("reset drops runtime changes", func(t *testing.T) {
+		ResetNetworks()
+		InitializeNetworks()
+		assert.True(t, NetworkEnabled("Ethereum"))
+		assert.False(t, FillsAllowedByChainID(Networks["Base"].ChainID))
+	})
+}
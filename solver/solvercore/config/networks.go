@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
 	"github.com/ethereum/go-ethereum/common"
@@ -35,8 +36,26 @@ const (
 	StarknetDefaultPollIntervalMs = 2000
 	DefaultMaxBlockRange          = 10
 	StarknetDefaultMaxBlockRange  = 100
+	DefaultCheckpointBlocks       = 50
+
+	// Expected time between blocks, in milliseconds
+	EthereumBlockTimeMs = 12000
+	OptimismBlockTimeMs = 2000
+	ArbitrumBlockTimeMs = 250
+	BaseBlockTimeMs     = 2000
+	StarknetBlockTimeMs = 6000
+	DevnetBlockTimeMs   = 500 // local forks mine on every transaction
 )
 
+// defaultBlockTimesMs are the expected block times of the built-in networks
+var defaultBlockTimesMs = map[string]int{
+	"Ethereum": EthereumBlockTimeMs,
+	"Optimism": OptimismBlockTimeMs,
+	"Arbitrum": ArbitrumBlockTimeMs,
+	"Base":     BaseBlockTimeMs,
+	"Starknet": StarknetBlockTimeMs,
+}
+
 // NetworkConfig represents a single network configuration
 type NetworkConfig struct {
 	Name             string
@@ -50,6 +69,34 @@ type NetworkConfig struct {
 	PollInterval       int    // milliseconds, 0 = use default
 	ConfirmationBlocks uint64 // 0 = use default
 	MaxBlockRange      uint64 // 0 = use default
+	// Operator switches, changeable at runtime (see SetNetworkEnabled, SetFillsEnabled)
+	Enabled      bool // listen for orders opened on this network
+	FillsEnabled bool // fill orders toward this network
+	// Block explorer base URL, empty when the network has none (see explorer.go)
+	ExplorerURL string
+}
+
+// CheckpointBlocks returns how many blocks a listener processes within one block range before
+// persisting its progress, so a restart during a long range resumes close to where it stopped
+// (CHECKPOINT_BLOCKS, 0 = only at the end of each range)
+func CheckpointBlocks() uint64 {
+	return envutil.GetEnvUint64("CHECKPOINT_BLOCKS", DefaultCheckpointBlocks)
+}
+
+// ExpectedBlockTime returns how often the network produces blocks, e.g. to pace receipt polling:
+// <NETWORK>_BLOCK_TIME_MS, else DevnetBlockTimeMs when IS_DEVNET=true, else the network's known
+// block time (other Starknet networks use StarknetBlockTimeMs, other EVM networks OptimismBlockTimeMs)
+func ExpectedBlockTime(networkName string) time.Duration {
+	fallback, ok := defaultBlockTimesMs[networkName]
+	switch {
+	case envutil.IsDevnet():
+		fallback = DevnetBlockTimeMs
+	case !ok && IsStarknetNetwork(networkName):
+		fallback = StarknetBlockTimeMs
+	case !ok:
+		fallback = OptimismBlockTimeMs
+	}
+	return time.Duration(envutil.GetEnvInt(NetworkEnvPrefix(networkName)+"_BLOCK_TIME_MS", fallback)) * time.Millisecond
 }
 
 // GetConditionalAccountEnv gets account-related environment variables based on IS_DEVNET flag
@@ -74,6 +121,8 @@ func InitializeNetworks() {
 func ResetNetworks() {
 	networksInitialized = false
 	Networks = nil
+	resetDomainRegistry()
+	resetNetworkFlags()
 }
 
 // ensureInitialized initializes networks if not already done (fallback for legacy usage)
@@ -100,6 +149,8 @@ func initializeNetworks() {
 			PollInterval:       envutil.GetEnvInt("POLL_INTERVAL_MS", DefaultPollIntervalMs),
 			ConfirmationBlocks: envutil.GetEnvUint64("CONFIRMATION_BLOCKS", 0),
 			MaxBlockRange:      envutil.GetEnvUint64("MAX_BLOCK_RANGE", DefaultMaxBlockRange),
+			Enabled:            envutil.GetEnvBool("ETHEREUM_ENABLED", true),
+			FillsEnabled:       envutil.GetEnvBool("ETHEREUM_FILLS_ENABLED", true),
 		},
 		"Optimism": {
 			Name:               "Optimism",
@@ -112,6 +163,8 @@ func initializeNetworks() {
 			PollInterval:       envutil.GetEnvInt("POLL_INTERVAL_MS", DefaultPollIntervalMs),
 			ConfirmationBlocks: envutil.GetEnvUint64("CONFIRMATION_BLOCKS", 0),
 			MaxBlockRange:      envutil.GetEnvUint64("MAX_BLOCK_RANGE", DefaultMaxBlockRange),
+			Enabled:            envutil.GetEnvBool("OPTIMISM_ENABLED", true),
+			FillsEnabled:       envutil.GetEnvBool("OPTIMISM_FILLS_ENABLED", true),
 		},
 		"Arbitrum": {
 			Name:               "Arbitrum",
@@ -124,6 +177,8 @@ func initializeNetworks() {
 			PollInterval:       envutil.GetEnvInt("POLL_INTERVAL_MS", DefaultPollIntervalMs),
 			ConfirmationBlocks: envutil.GetEnvUint64("CONFIRMATION_BLOCKS", 0),
 			MaxBlockRange:      envutil.GetEnvUint64("MAX_BLOCK_RANGE", DefaultMaxBlockRange),
+			Enabled:            envutil.GetEnvBool("ARBITRUM_ENABLED", true),
+			FillsEnabled:       envutil.GetEnvBool("ARBITRUM_FILLS_ENABLED", true),
 		},
 		"Base": {
 			Name:               "Base",
@@ -136,6 +191,8 @@ func initializeNetworks() {
 			PollInterval:       envutil.GetEnvInt("POLL_INTERVAL_MS", DefaultPollIntervalMs),
 			ConfirmationBlocks: envutil.GetEnvUint64("CONFIRMATION_BLOCKS", 0),
 			MaxBlockRange:      envutil.GetEnvUint64("MAX_BLOCK_RANGE", DefaultMaxBlockRange),
+			Enabled:            envutil.GetEnvBool("BASE_ENABLED", true),
+			FillsEnabled:       envutil.GetEnvBool("BASE_FILLS_ENABLED", true),
 		},
 		"Starknet": {
 			Name:               "Starknet",
@@ -149,8 +206,17 @@ func initializeNetworks() {
 			ConfirmationBlocks: envutil.GetEnvUint64("STARKNET_CONFIRMATION_BLOCKS", 0),
 			MaxBlockRange: envutil.GetEnvUint64("STARKNET_MAX_BLOCK_RANGE",
 				envutil.GetEnvUint64("MAX_BLOCK_RANGE", StarknetDefaultMaxBlockRange)),
+			Enabled:      envutil.GetEnvBool("STARKNET_ENABLED", true),
+			FillsEnabled: envutil.GetEnvBool("STARKNET_FILLS_ENABLED", true),
 		},
 	}
+	for _, network := range additionalStarknetNetworks(Networks) {
+		Networks[network.Name] = network
+	}
+	for name, network := range Networks {
+		network.ExplorerURL = explorerURL(name, network.ChainID)
+		Networks[name] = network
+	}
 	networksInitialized = true
 }
 
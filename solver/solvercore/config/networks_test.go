@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
 	"github.com/stretchr/testify/assert"
@@ -153,3 +154,18 @@ func TestUtilityFunctions(t *testing.T) {
 	// Note: parseUint64 is now internal to envutil package, so we test it indirectly
 	// through the public functions that use it
 }
+
+func TestExpectedBlockTime(t *testing.T) {
+	t.Setenv("IS_DEVNET", "false")
+	assert.Equal(t, 12*time.Second, ExpectedBlockTime("Ethereum"))
+	assert.Equal(t, 250*time.Millisecond, ExpectedBlockTime("Arbitrum"))
+	assert.Equal(t, 6*time.Second, ExpectedBlockTime("Starknet Mainnet"))
+	assert.Equal(t, 2*time.Second, ExpectedBlockTime("Polygon"))
+
+	t.Setenv("BASE_BLOCK_TIME_MS", "1000")
+	assert.Equal(t, time.Second, ExpectedBlockTime("Base"))
+
+	t.Setenv("IS_DEVNET", "true")
+	assert.Equal(t, 500*time.Millisecond, ExpectedBlockTime("Ethereum"))
+	assert.Equal(t, time.Second, ExpectedBlockTime("Base"), "override still applies")
+}
@@ -0,0 +1,122 @@
+package config
+
+// Module: Per token pair rule thresholds
+// - The "rules.pairs" section of the config file overrides the global profitability thresholds,
+//   slippage and maximum order size for orders between an input and an output token on a chain pair
+// - Tokens are matched by registry symbol and chains by network name; "*" (or an empty field)
+//   matches any token or network
+// - The most specific entry wins (the one with the most non-wildcard fields); ties go to the first
+//   listed. Fields an entry doesn't set keep the global value
+// - Consulted by the TokenCheck (max order size) and ProfitabilityCheck rules, and reloaded with
+//   the runtime config
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+)
+
+// PairRules overrides rule thresholds for orders from an input token to an output token on a chain pair
+type PairRules struct {
+	// InputToken is the symbol of the token locked on the origin, which the solver receives, and
+	// OutputToken the symbol of the token the solver spends on the destination
+	InputToken  string `json:"inputToken,omitempty"`
+	OutputToken string `json:"outputToken,omitempty"`
+	// Origin and Destination are network names
+	Origin      string `json:"origin,omitempty"`
+	Destination string `json:"destination,omitempty"`
+	// MinProfit overrides rules.minProfit, in token base units (decimal string)
+	MinProfit string `json:"minProfit,omitempty"`
+	// MinMarginBps and MaxSlippageBps override the rules settings of the same name
+	MinMarginBps   *int `json:"minMarginBps,omitempty"`
+	MaxSlippageBps *int `json:"maxSlippageBps,omitempty"`
+	// MaxOrderSize bounds the amount the solver spends on an order, in whole output tokens (e.g. "500")
+	MaxOrderSize string `json:"maxOrderSize,omitempty"`
+}
+
+// PairRulesFor returns the most specific pair entry matching an order, false when none does.
+// Unknown token symbols are passed empty and only match wildcard entries.
+func (c *RuntimeConfig) PairRulesFor(inputToken, outputToken, origin, destination string) (PairRules, bool) {
+	best, bestScore := -1, -1
+	for i, pair := range c.Rules.Pairs {
+		score := 0
+		matched := true
+		for _, field := range [][2]string{
+			{pair.InputToken, inputToken},
+			{pair.OutputToken, outputToken},
+			{pair.Origin, origin},
+			{pair.Destination, destination},
+		} {
+			switch {
+			case field[0] == "" || field[0] == AnyNetwork:
+			case field[0] == field[1]:
+				score++
+			default:
+				matched = false
+			}
+		}
+		if matched && score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return PairRules{}, false
+	}
+	return c.Rules.Pairs[best], true
+}
+
+// MinProfitAmount returns the minimum net profit override in base units, nil when not set
+func (p PairRules) MinProfitAmount() *big.Int {
+	if p.MinProfit == "" {
+		return nil
+	}
+	minProfit, err := parseMinProfit(p.MinProfit)
+	if err != nil {
+		// Unreachable for a validated config
+		return nil
+	}
+	return minProfit
+}
+
+// MaxOrder returns the maximum order size in base units of an output token with the given decimals,
+// nil without a bound
+func (p PairRules) MaxOrder(decimals int) *big.Int {
+	if p.MaxOrderSize == "" {
+		return nil
+	}
+	// Parsed at full precision, so fractional digits beyond the token's decimals are truncated
+	parsed, err := amount.Parse(p.MaxOrderSize, maxTokenDecimals)
+	if err != nil {
+		// Unreachable for a validated config
+		return nil
+	}
+	return parsed.Scale(decimals).Units
+}
+
+func (p PairRules) validate() error {
+	var errs []error
+	for _, network := range []string{p.Origin, p.Destination} {
+		if _, ok := Networks[network]; !ok && network != "" && network != AnyNetwork {
+			errs = append(errs, fmt.Errorf("unknown network %q", network))
+		}
+	}
+	if p.MinProfit != "" {
+		if _, err := parseMinProfit(p.MinProfit); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if p.MinMarginBps != nil && *p.MinMarginBps < 0 {
+		errs = append(errs, fmt.Errorf("minMarginBps: must be >= 0, got %d", *p.MinMarginBps))
+	}
+	if p.MaxSlippageBps != nil && (*p.MaxSlippageBps < 0 || *p.MaxSlippageBps >= maxBps) {
+		errs = append(errs, fmt.Errorf("maxSlippageBps: must be between 0 and %d, got %d", maxBps-1, *p.MaxSlippageBps))
+	}
+	if p.MaxOrderSize != "" {
+		if _, err := amount.Parse(p.MaxOrderSize, maxTokenDecimals); err != nil {
+			errs = append(errs, fmt.Errorf("maxOrderSize: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPairRules(t *testing.T) {
+	InitializeNetworks()
+	bps := func(value int) *int { return &value }
+
+	t.Run("most specific entry wins", func(t *testing.T) {
+		config := RuntimeConfig{Rules: RulesConfig{Pairs: []PairRules{
+			{InputToken: "*", OutputToken: "*", Origin: "*", Destination: "Starknet", MinProfit: "1"},
+			{InputToken: "USDC", OutputToken: "USDC", MinProfit: "2"},
+			{InputToken: "USDC", OutputToken: "USDC", Origin: "Base", Destination: "Starknet", MinProfit: "3"},
+		}}}
+
+		pair, ok := config.PairRulesFor("USDC", "USDC", "Base", "Starknet")
+		require.True(t, ok)
+		assert.Equal(t, big.NewInt(3), pair.MinProfitAmount())
+
+		pair, ok = config.PairRulesFor("USDC", "USDC", "Ethereum", "Starknet")
+		require.True(t, ok)
+		assert.Equal(t, big.NewInt(2), pair.MinProfitAmount())
+
+		pair, ok = config.PairRulesFor("DOG", "DOG", "Base", "Starknet")
+		require.True(t, ok)
+		assert.Equal(t, big.NewInt(1), pair.MinProfitAmount())
+
+		_, ok = config.PairRulesFor("DOG", "DOG", "Starknet", "Base")
+		assert.False(t, ok)
+	})
+
+	t.Run("ties go to the first entry", func(t *testing.T) {
+		config := RuntimeConfig{Rules: RulesConfig{Pairs: []PairRules{
+			{InputToken: "USDC", MinProfit: "1"},
+			{OutputToken: "USDC", MinProfit: "2"},
+		}}}
+		pair, ok := config.PairRulesFor("USDC", "USDC", "Base", "Starknet")
+		require.True(t, ok)
+		assert.Equal(t, "1", pair.MinProfit)
+	})
+
+	t.Run("unknown tokens only match wildcards", func(t *testing.T) {
+		config := RuntimeConfig{Rules: RulesConfig{Pairs: []PairRules{{InputToken: "USDC"}}}}
+		_, ok := config.PairRulesFor("", "", "Base", "Starknet")
+		assert.False(t, ok)
+	})
+
+	t.Run("max order size in output token decimals", func(t *testing.T) {
+		pair := PairRules{MaxOrderSize: "12.5"}
+		assert.Equal(t, big.NewInt(12_500_000), pair.MaxOrder(6))
+		assert.Equal(t, big.NewInt(12), pair.MaxOrder(0), "fractional digits beyond the decimals are truncated")
+		assert.Nil(t, PairRules{}.MaxOrder(18))
+		assert.Nil(t, PairRules{}.MinProfitAmount())
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		valid := RuntimeConfig{Rules: RulesConfig{Pairs: []PairRules{
+			{InputToken: "USDC", Origin: "Base", Destination: "*", MinProfit: "10", MinMarginBps: bps(25), MaxSlippageBps: bps(50), MaxOrderSize: "500"},
+		}}}
+		assert.NoError(t, valid.Validate())
+
+		for name, pair := range map[string]PairRules{
+			"unknown network":  {Origin: "Solana"},
+			"min profit":       {MinProfit: "0.5"},
+			"negative margin":  {MinMarginBps: bps(-1)},
+			"slippage of 100%": {MaxSlippageBps: bps(10_000)},
+			"max order size":   {MaxOrderSize: "-1"},
+		} {
+			config := RuntimeConfig{Rules: RulesConfig{Pairs: []PairRules{{}, pair}}}
+			assert.ErrorContains(t, config.Validate(), "rules.pairs[1]", name)
+		}
+	})
+}
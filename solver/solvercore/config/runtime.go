@@ -0,0 +1,381 @@
+package config
+
+// Module: Hot-reloadable runtime configuration
+// - Settings that can change while the solver runs: allow/block lists, rule settings, the routing
+//   table, poll intervals, the token registry and per-component log levels
+// - Read from the config file (SOLVER_CONFIG_FILE) at startup and again on SIGHUP or POST /config/reload
+// - A reload is validated in full before it replaces the active config in a single swap;
+//   an invalid file leaves the active config untouched
+// - Consumers read ActiveRuntime() when they need a setting, so listeners keep running across reloads
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// ErrNoConfigFile is returned by ReloadRuntime when SOLVER_CONFIG_FILE is not set
+var ErrNoConfigFile = errors.New("SOLVER_CONFIG_FILE is not set")
+
+// RulesConfig configures the order validation rules
+type RulesConfig struct {
+	// Disabled lists rules to skip by name (e.g. "BalanceCheck")
+	Disabled []string `json:"disabled,omitempty"`
+	// MinProfit is the minimum net profit of an order in token base units (decimal string, default 0)
+	MinProfit string `json:"minProfit,omitempty"`
+	// MinFillNotional rejects dust orders whose fill notional (MaxSpent summed, in whole 18-decimal
+	// tokens) is below it, e.g. "0.01" (decimal string, default no minimum)
+	MinFillNotional string `json:"minFillNotional,omitempty"`
+	// TrustedSettlers lists destination settlers accepted by network name in addition to the
+	// configured Hyperlane7683 address (e.g. {"Base": ["0x..."]})
+	TrustedSettlers map[string][]string `json:"trustedSettlers,omitempty"`
+	// AllowUnknownTokens accepts orders with tokens missing from the token registry
+	AllowUnknownTokens bool `json:"allowUnknownTokens,omitempty"`
+	// MaxOrderAge records orders whose origin block is older than it as EXPIRED instead of filling
+	// them, e.g. "6h" while backfilling (Go duration, default no limit)
+	MaxOrderAge string `json:"maxOrderAge,omitempty"`
+	// MinFillWindow rejects orders whose fill deadline is less than it away, leaving no time to
+	// fill them, e.g. "2m" (Go duration, default only deadlines already passed are rejected)
+	MinFillWindow string `json:"minFillWindow,omitempty"`
+	// MinMarginBps is the minimum margin of an order whose input and output tokens differ, in basis
+	// points of the value the solver spends, at the token prices (default 0)
+	MinMarginBps int `json:"minMarginBps,omitempty"`
+	// MaxSlippageBps is the adverse price move, in basis points, an order whose input and output
+	// tokens differ must absorb while keeping MinMarginBps (default 0)
+	MaxSlippageBps int `json:"maxSlippageBps,omitempty"`
+	// MaxRateFactor rejects like-for-like orders whose amount received over amount spent, both
+	// scaled by their token decimals, is more than this factor away from 1:1 (default 10)
+	MaxRateFactor int `json:"maxRateFactor,omitempty"`
+	// Pairs overrides the thresholds above, and the max order size, per input token, output token
+	// and chain pair (see pair_rules.go)
+	Pairs []PairRules `json:"pairs,omitempty"`
+}
+
+// maxBps is 100% in basis points
+const maxBps = 10_000
+
+// defaultMaxRateFactor is the rate factor of DecimalsCheck when rules.maxRateFactor is not set
+const defaultMaxRateFactor = 10
+
+// AnyNetwork matches every network in the routing table
+const AnyNetwork = "*"
+
+// RoutesConfig is the routing table: whether the solver serves orders from an origin network (first
+// key) to a destination network (second key), e.g. {"Base": {"Starknet": true}, "Starknet": {"Arbitrum": false}}.
+// "*" matches any network; the most specific entry wins, origin before destination.
+type RoutesConfig map[string]map[string]bool
+
+// RuntimeConfig holds the settings that can be reloaded without restarting the solver
+type RuntimeConfig struct {
+	AllowBlockLists types.AllowBlockLists `json:"allowBlockLists"`
+	Rules           RulesConfig           `json:"rules"`
+	// Routes lists the origin→destination pairs the solver serves; without it every pair is served
+	Routes RoutesConfig `json:"routes,omitempty"`
+	// PollIntervalsMs overrides the poll interval of listeners by network name (e.g. {"Base": 500})
+	PollIntervalsMs map[string]int `json:"pollIntervalsMs,omitempty"`
+	// Tokens is the token registry; when empty every token is accepted
+	Tokens []TokenConfig `json:"tokens,omitempty"`
+	// LogLevels sets the log level of solver components (e.g. {"default": "info", "listener": "warn"});
+	// names are checked by logutil.ValidateLogLevels
+	LogLevels map[string]string `json:"logLevels,omitempty"`
+
+	// tokenRegistry indexes Tokens; built by ApplyRuntime
+	tokenRegistry *TokenRegistry
+}
+
+// RuntimeValidator checks settings the config package cannot check itself (e.g. rule names)
+type RuntimeValidator func(*RuntimeConfig) error
+
+// Validate checks the config; networks must be initialized to validate poll interval overrides
+func (c *RuntimeConfig) Validate() error {
+	var errs []error
+
+	lists := map[string][]types.AllowBlockListItem{"allowList": c.AllowBlockLists.AllowList, "blockList": c.AllowBlockLists.BlockList}
+	for _, name := range []string{"allowList", "blockList"} {
+		for i, item := range lists[name] {
+			if item.SenderAddress == "" || item.DestinationDomain == "" || item.RecipientAddress == "" {
+				errs = append(errs, fmt.Errorf("allowBlockLists.%s[%d]: empty field, use \"*\" to match any value", name, i))
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, name := range c.Rules.Disabled {
+		if name == "" || seen[name] {
+			errs = append(errs, fmt.Errorf("rules.disabled[%d]: empty or duplicate rule name %q", i, name))
+		}
+		seen[name] = true
+	}
+	if c.Rules.MinProfit != "" {
+		if _, err := c.minProfit(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if _, err := c.minFillNotional(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := c.maxOrderAge(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := c.minFillWindow(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Rules.MinMarginBps < 0 {
+		errs = append(errs, fmt.Errorf("rules.minMarginBps: must be >= 0, got %d", c.Rules.MinMarginBps))
+	}
+	if c.Rules.MaxSlippageBps < 0 || c.Rules.MaxSlippageBps >= maxBps {
+		errs = append(errs, fmt.Errorf("rules.maxSlippageBps: must be between 0 and %d, got %d", maxBps-1, c.Rules.MaxSlippageBps))
+	}
+	if c.Rules.MaxRateFactor < 0 || c.Rules.MaxRateFactor == 1 {
+		errs = append(errs, fmt.Errorf("rules.maxRateFactor: must be at least 2, got %d", c.Rules.MaxRateFactor))
+	}
+
+	ensureInitialized()
+	for i, pair := range c.Rules.Pairs {
+		if err := pair.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("rules.pairs[%d]: %w", i, err))
+		}
+	}
+	for network, settlers := range c.Rules.TrustedSettlers {
+		if _, ok := Networks[network]; !ok {
+			errs = append(errs, fmt.Errorf("rules.trustedSettlers: unknown network %q", network))
+		}
+		for i, settler := range settlers {
+			if settler == "" {
+				errs = append(errs, fmt.Errorf("rules.trustedSettlers.%s[%d]: empty address", network, i))
+			}
+		}
+	}
+	for origin, destinations := range c.Routes {
+		if _, ok := Networks[origin]; !ok && origin != AnyNetwork {
+			errs = append(errs, fmt.Errorf("routes: unknown origin network %q", origin))
+		}
+		for destination := range destinations {
+			if _, ok := Networks[destination]; !ok && destination != AnyNetwork {
+				errs = append(errs, fmt.Errorf("routes.%s: unknown destination network %q", origin, destination))
+			}
+		}
+	}
+	for network, interval := range c.PollIntervalsMs {
+		if _, ok := Networks[network]; !ok {
+			errs = append(errs, fmt.Errorf("pollIntervalsMs: unknown network %q", network))
+		}
+		if interval <= 0 {
+			errs = append(errs, fmt.Errorf("pollIntervalsMs.%s: must be > 0, got %d", network, interval))
+		}
+	}
+
+	if _, err := NewTokenRegistry(c.Tokens); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// minProfit parses Rules.MinProfit
+func (c *RuntimeConfig) minProfit() (*big.Int, error) {
+	if c.Rules.MinProfit == "" {
+		return new(big.Int), nil
+	}
+	minProfit, err := parseMinProfit(c.Rules.MinProfit)
+	if err != nil {
+		return nil, fmt.Errorf("rules.%w", err)
+	}
+	return minProfit, nil
+}
+
+// parseMinProfit parses a minimum profit in base units
+func parseMinProfit(value string) (*big.Int, error) {
+	minProfit, ok := new(big.Int).SetString(value, 10)
+	if !ok || minProfit.Sign() < 0 || minProfit.BitLen() > 256 {
+		return nil, fmt.Errorf("minProfit: invalid amount %q", value)
+	}
+	return minProfit, nil
+}
+
+// MinProfit returns the minimum net profit of an order in token base units
+func (c *RuntimeConfig) MinProfit() *big.Int {
+	minProfit, err := c.minProfit()
+	if err != nil {
+		// Unreachable for a validated config
+		return new(big.Int)
+	}
+	return minProfit
+}
+
+// minFillNotional parses Rules.MinFillNotional into 18-decimal base units
+func (c *RuntimeConfig) minFillNotional() (*big.Int, error) {
+	if c.Rules.MinFillNotional == "" {
+		return nil, nil
+	}
+	parsed, err := amount.Parse(c.Rules.MinFillNotional, NotionalDecimals)
+	if err != nil {
+		return nil, fmt.Errorf("rules.minFillNotional: %w", err)
+	}
+	return parsed.Units, nil
+}
+
+// MinFillNotional returns the minimum fill notional of an order in 18-decimal base units, or nil without a minimum
+func (c *RuntimeConfig) MinFillNotional() *big.Int {
+	notional, err := c.minFillNotional()
+	if err != nil {
+		// Unreachable for a validated config
+		return nil
+	}
+	return notional
+}
+
+// maxOrderAge parses Rules.MaxOrderAge
+func (c *RuntimeConfig) maxOrderAge() (time.Duration, error) {
+	if c.Rules.MaxOrderAge == "" {
+		return 0, nil
+	}
+	age, err := time.ParseDuration(c.Rules.MaxOrderAge)
+	if err != nil || age <= 0 {
+		return 0, fmt.Errorf("rules.maxOrderAge: invalid duration %q", c.Rules.MaxOrderAge)
+	}
+	return age, nil
+}
+
+// MaxOrderAge returns the maximum age of an order's origin block, or 0 without a limit
+func (c *RuntimeConfig) MaxOrderAge() time.Duration {
+	age, err := c.maxOrderAge()
+	if err != nil {
+		// Unreachable for a validated config
+		return 0
+	}
+	return age
+}
+
+// minFillWindow parses Rules.MinFillWindow
+func (c *RuntimeConfig) minFillWindow() (time.Duration, error) {
+	if c.Rules.MinFillWindow == "" {
+		return 0, nil
+	}
+	window, err := time.ParseDuration(c.Rules.MinFillWindow)
+	if err != nil || window < 0 {
+		return 0, fmt.Errorf("rules.minFillWindow: invalid duration %q", c.Rules.MinFillWindow)
+	}
+	return window, nil
+}
+
+// MinFillWindow returns the minimum time left before an order's fill deadline to accept it
+func (c *RuntimeConfig) MinFillWindow() time.Duration {
+	window, err := c.minFillWindow()
+	if err != nil {
+		// Unreachable for a validated config
+		return 0
+	}
+	return window
+}
+
+// MaxRateFactor returns how far from 1:1 the decimal-scaled rate of a like-for-like order may be
+func (c *RuntimeConfig) MaxRateFactor() int {
+	if c.Rules.MaxRateFactor == 0 {
+		return defaultMaxRateFactor
+	}
+	return c.Rules.MaxRateFactor
+}
+
+// RuleEnabled reports whether the named rule should run
+func (c *RuntimeConfig) RuleEnabled(name string) bool {
+	for _, disabled := range c.Rules.Disabled {
+		if disabled == name {
+			return false
+		}
+	}
+	return true
+}
+
+// RouteEnabled reports whether the solver serves orders from origin to destination. Without a routing
+// table every pair is served; with one, only pairs whose most specific entry is true.
+func (c *RuntimeConfig) RouteEnabled(origin, destination string) bool {
+	if len(c.Routes) == 0 {
+		return true
+	}
+	for _, key := range [][2]string{
+		{origin, destination},
+		{origin, AnyNetwork},
+		{AnyNetwork, destination},
+		{AnyNetwork, AnyNetwork},
+	} {
+		if enabled, ok := c.Routes[key[0]][key[1]]; ok {
+			return enabled
+		}
+	}
+	return false
+}
+
+// PollInterval returns the poll interval of the network in milliseconds, or fallback without an override
+func (c *RuntimeConfig) PollInterval(network string, fallback int) int {
+	if interval, ok := c.PollIntervalsMs[network]; ok {
+		return interval
+	}
+	return fallback
+}
+
+// TokenRegistry returns the registry built from Tokens; invalid entries leave it empty
+func (c *RuntimeConfig) TokenRegistry() *TokenRegistry {
+	if c.tokenRegistry != nil {
+		return c.tokenRegistry
+	}
+	registry, err := NewTokenRegistry(c.Tokens)
+	if err != nil {
+		// Unreachable for a validated config
+		registry, _ = NewTokenRegistry(nil)
+	}
+	return registry
+}
+
+var (
+	activeRuntime   = &RuntimeConfig{}
+	activeRuntimeMu sync.RWMutex
+)
+
+// ActiveRuntime returns the active runtime config; it must not be modified
+func ActiveRuntime() *RuntimeConfig {
+	activeRuntimeMu.RLock()
+	defer activeRuntimeMu.RUnlock()
+	return activeRuntime
+}
+
+// ApplyRuntime validates runtime and makes it the active config. Must be called after InitializeNetworks.
+func ApplyRuntime(runtime *RuntimeConfig, validators ...RuntimeValidator) error {
+	errs := []error{runtime.Validate()}
+	for _, validate := range validators {
+		errs = append(errs, validate(runtime))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid runtime config: %w", err)
+	}
+
+	runtime.tokenRegistry = runtime.TokenRegistry()
+
+	activeRuntimeMu.Lock()
+	activeRuntime = runtime
+	activeRuntimeMu.Unlock()
+	return nil
+}
+
+// ReloadRuntime re-reads the runtime config from SOLVER_CONFIG_FILE and applies it if valid
+func ReloadRuntime(validators ...RuntimeValidator) (*RuntimeConfig, error) {
+	path := os.Getenv("SOLVER_CONFIG_FILE")
+	if path == "" {
+		return nil, ErrNoConfigFile
+	}
+
+	config := &Config{}
+	if err := loadConfigFile(path, config); err != nil {
+		return nil, err
+	}
+	if err := ApplyRuntime(&config.RuntimeConfig, validators...); err != nil {
+		return nil, err
+	}
+	return &config.RuntimeConfig, nil
+}
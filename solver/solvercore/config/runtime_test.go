@@ -0,0 +1,295 @@
+package config
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// restoreRuntime resets the active runtime config when the test ends
+func restoreRuntime(t *testing.T) {
+	t.Cleanup(func() {
+		activeRuntimeMu.Lock()
+		activeRuntime = &RuntimeConfig{}
+		activeRuntimeMu.Unlock()
+	})
+}
+
+func TestRuntimeConfigValidate(t *testing.T) {
+	InitializeNetworks()
+
+	item := types.AllowBlockListItem{SenderAddress: "*", DestinationDomain: "Base", RecipientAddress: "*"}
+	tests := []struct {
+		name    string
+		config  RuntimeConfig
+		wantErr string
+	}{
+		{name: "empty config", config: RuntimeConfig{}},
+		{
+			name: "full config",
+			config: RuntimeConfig{
+				AllowBlockLists: types.AllowBlockLists{AllowList: []types.AllowBlockListItem{item}, BlockList: []types.AllowBlockListItem{item}},
+				Rules:           RulesConfig{Disabled: []string{"BalanceCheck"}, MinProfit: "1000", MinFillNotional: "0.01", MinMarginBps: 50, MaxSlippageBps: 100, TrustedSettlers: map[string][]string{"Base": {"0x01"}}},
+				PollIntervalsMs: map[string]int{"Base": 500},
+				Routes:          RoutesConfig{"Base": {"Starknet": true}, "*": {"Arbitrum": false}},
+			},
+		},
+		{
+			name:    "list item with empty field",
+			config:  RuntimeConfig{AllowBlockLists: types.AllowBlockLists{BlockList: []types.AllowBlockListItem{{SenderAddress: "*"}}}},
+			wantErr: "allowBlockLists.blockList[0]",
+		},
+		{
+			name:    "duplicate disabled rule",
+			config:  RuntimeConfig{Rules: RulesConfig{Disabled: []string{"BalanceCheck", "BalanceCheck"}}},
+			wantErr: "rules.disabled[1]",
+		},
+		{
+			name:    "negative min profit",
+			config:  RuntimeConfig{Rules: RulesConfig{MinProfit: "-1"}},
+			wantErr: "rules.minProfit",
+		},
+		{
+			name:    "non-integer min profit",
+			config:  RuntimeConfig{Rules: RulesConfig{MinProfit: "0.5"}},
+			wantErr: "rules.minProfit",
+		},
+		{
+			name:    "invalid min fill notional",
+			config:  RuntimeConfig{Rules: RulesConfig{MinFillNotional: "-0.01"}},
+			wantErr: "rules.minFillNotional",
+		},
+		{
+			name:    "min fill notional below 18 decimals",
+			config:  RuntimeConfig{Rules: RulesConfig{MinFillNotional: "0.0000000000000000001"}},
+			wantErr: "rules.minFillNotional",
+		},
+		{
+			name:    "invalid max order age",
+			config:  RuntimeConfig{Rules: RulesConfig{MaxOrderAge: "6 hours"}},
+			wantErr: "rules.maxOrderAge",
+		},
+		{
+			name:    "negative max order age",
+			config:  RuntimeConfig{Rules: RulesConfig{MaxOrderAge: "-1h"}},
+			wantErr: "rules.maxOrderAge",
+		},
+		{
+			name:    "invalid min fill window",
+			config:  RuntimeConfig{Rules: RulesConfig{MinFillWindow: "two minutes"}},
+			wantErr: "rules.minFillWindow",
+		},
+		{
+			name:    "negative min margin",
+			config:  RuntimeConfig{Rules: RulesConfig{MinMarginBps: -1}},
+			wantErr: "rules.minMarginBps",
+		},
+		{
+			name:    "max slippage of 100%",
+			config:  RuntimeConfig{Rules: RulesConfig{MaxSlippageBps: 10_000}},
+			wantErr: "rules.maxSlippageBps",
+		},
+		{
+			name:    "max rate factor of 1",
+			config:  RuntimeConfig{Rules: RulesConfig{MaxRateFactor: 1}},
+			wantErr: "rules.maxRateFactor",
+		},
+		{
+			name:    "trusted settlers on unknown network",
+			config:  RuntimeConfig{Rules: RulesConfig{TrustedSettlers: map[string][]string{"Solana": {"0x01"}}}},
+			wantErr: "rules.trustedSettlers: unknown network",
+		},
+		{
+			name:    "empty trusted settler",
+			config:  RuntimeConfig{Rules: RulesConfig{TrustedSettlers: map[string][]string{"Base": {""}}}},
+			wantErr: "rules.trustedSettlers.Base[0]",
+		},
+		{
+			name:    "invalid token",
+			config:  RuntimeConfig{Tokens: []TokenConfig{{ChainID: 84532, Address: "0xa1"}}},
+			wantErr: "tokens[0]: empty symbol",
+		},
+		{
+			name:    "unknown network",
+			config:  RuntimeConfig{PollIntervalsMs: map[string]int{"Solana": 500}},
+			wantErr: "unknown network",
+		},
+		{
+			name:    "route from unknown network",
+			config:  RuntimeConfig{Routes: RoutesConfig{"Solana": {"Base": true}}},
+			wantErr: `routes: unknown origin network "Solana"`,
+		},
+		{
+			name:    "route to unknown network",
+			config:  RuntimeConfig{Routes: RoutesConfig{"Base": {"Solana": true}}},
+			wantErr: `routes.Base: unknown destination network "Solana"`,
+		},
+		{
+			name:    "zero poll interval",
+			config:  RuntimeConfig{PollIntervalsMs: map[string]int{"Base": 0}},
+			wantErr: "pollIntervalsMs.Base",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRuntimeConfigAccessors(t *testing.T) {
+	config := RuntimeConfig{
+		Rules:           RulesConfig{Disabled: []string{"BalanceCheck"}, MinProfit: "250", MinFillNotional: "1.5", MaxOrderAge: "6h", MinFillWindow: "2m", MaxRateFactor: 100},
+		PollIntervalsMs: map[string]int{"Base": 500},
+	}
+
+	assert.False(t, config.RuleEnabled("BalanceCheck"))
+	assert.True(t, config.RuleEnabled("ProfitabilityCheck"))
+	assert.Equal(t, big.NewInt(250), config.MinProfit())
+	assert.Equal(t, big.NewInt(15e17), config.MinFillNotional())
+	assert.Equal(t, 6*time.Hour, config.MaxOrderAge())
+	assert.Equal(t, 2*time.Minute, config.MinFillWindow())
+	assert.Equal(t, 100, config.MaxRateFactor())
+	assert.Equal(t, 500, config.PollInterval("Base", 1000))
+	assert.Equal(t, 2000, config.PollInterval("Starknet", 2000))
+
+	empty := RuntimeConfig{}
+	assert.Equal(t, big.NewInt(0), empty.MinProfit())
+	assert.Nil(t, empty.MinFillNotional())
+	assert.Zero(t, empty.MaxOrderAge())
+	assert.Zero(t, empty.MinFillWindow())
+	assert.Equal(t, 10, empty.MaxRateFactor())
+	assert.True(t, empty.RuleEnabled("BalanceCheck"))
+	assert.True(t, empty.RouteEnabled("Starknet", "Arbitrum"))
+}
+
+func TestRouteEnabled(t *testing.T) {
+	config := RuntimeConfig{Routes: RoutesConfig{
+		"Base":     {"Starknet": true, "*": false},
+		"Starknet": {"Arbitrum": false},
+		"*":        {"Arbitrum": true, "Base": true},
+	}}
+
+	tests := []struct {
+		origin, destination string
+		want                bool
+	}{
+		{"Base", "Starknet", true},      // Exact entry
+		{"Base", "Arbitrum", false},     // Origin wildcard of Base before the destination wildcard
+		{"Starknet", "Arbitrum", false}, // Exact entry before the destination wildcard
+		{"Optimism", "Arbitrum", true},  // Destination wildcard
+		{"Optimism", "Starknet", false}, // Not in the table
+		{"Ethereum", "Base", true},      // Destination wildcard
+		{"Starknet", "Base", true},      // Destination wildcard, no exact entry for Starknet → Base
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, config.RouteEnabled(tt.origin, tt.destination), "%s → %s", tt.origin, tt.destination)
+	}
+}
+
+func TestApplyRuntime(t *testing.T) {
+	InitializeNetworks()
+	restoreRuntime(t)
+
+	t.Run("valid config becomes active", func(t *testing.T) {
+		config := &RuntimeConfig{Rules: RulesConfig{MinProfit: "7"}}
+		require.NoError(t, ApplyRuntime(config))
+		assert.Same(t, config, ActiveRuntime())
+	})
+
+	t.Run("invalid config keeps the active one", func(t *testing.T) {
+		active := ActiveRuntime()
+		err := ApplyRuntime(&RuntimeConfig{Rules: RulesConfig{MinProfit: "lots"}})
+		assert.ErrorContains(t, err, "invalid runtime config")
+		assert.Same(t, active, ActiveRuntime())
+	})
+
+	t.Run("validators can reject a config", func(t *testing.T) {
+		active := ActiveRuntime()
+		err := ApplyRuntime(&RuntimeConfig{}, func(*RuntimeConfig) error { return errors.New("unknown rule") })
+		assert.ErrorContains(t, err, "unknown rule")
+		assert.Same(t, active, ActiveRuntime())
+	})
+}
+
+func TestReloadRuntime(t *testing.T) {
+	InitializeNetworks()
+	restoreRuntime(t)
+
+	path := filepath.Join(t.TempDir(), "solver.config.json")
+	write := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	t.Run("requires a config file", func(t *testing.T) {
+		t.Setenv("SOLVER_CONFIG_FILE", "")
+		_, err := ReloadRuntime()
+		assert.ErrorIs(t, err, ErrNoConfigFile)
+	})
+
+	t.Run("reads the runtime sections of the config file", func(t *testing.T) {
+		t.Setenv("SOLVER_CONFIG_FILE", path)
+		write(`{
+			"logLevel": "debug",
+			"allowBlockLists": {"blockList": [{"senderAddress": "0xbad", "destinationDomain": "*", "recipientAddress": "*"}]},
+			"rules": {"disabled": ["BalanceCheck"], "minProfit": "100"},
+			"pollIntervalsMs": {"Base": 250}
+		}`)
+
+		config, err := ReloadRuntime()
+		require.NoError(t, err)
+		assert.Same(t, config, ActiveRuntime())
+		require.Len(t, config.AllowBlockLists.BlockList, 1)
+		assert.Equal(t, "0xbad", config.AllowBlockLists.BlockList[0].SenderAddress)
+		assert.False(t, config.RuleEnabled("BalanceCheck"))
+		assert.Equal(t, 250, config.PollInterval("Base", 1000))
+	})
+
+	t.Run("reads the token registry", func(t *testing.T) {
+		t.Setenv("SOLVER_CONFIG_FILE", path)
+		write(`{"tokens": [{"chainId": 84532, "address": "0xa1", "symbol": "DOG", "decimals": 6, "maxOrderSize": "100"}]}`)
+
+		config, err := ReloadRuntime()
+		require.NoError(t, err)
+		token, ok := config.TokenRegistry().Lookup(84532, "0xa1")
+		require.True(t, ok)
+		assert.Equal(t, big.NewInt(100_000_000), token.MaxOrder())
+	})
+
+	t.Run("invalid file keeps the active config", func(t *testing.T) {
+		t.Setenv("SOLVER_CONFIG_FILE", path)
+		active := ActiveRuntime()
+
+		write(`{"pollIntervalsMs": {"Base": -1}}`)
+		_, err := ReloadRuntime()
+		assert.ErrorContains(t, err, "pollIntervalsMs.Base")
+		assert.Same(t, active, ActiveRuntime())
+
+		write(`{"rules": `)
+		_, err = ReloadRuntime()
+		assert.ErrorContains(t, err, "failed to parse config file")
+		assert.Same(t, active, ActiveRuntime())
+	})
+
+	t.Run("LoadConfig reads the same sections", func(t *testing.T) {
+		t.Setenv("SOLVER_CONFIG_FILE", path)
+		write(`{"rules": {"minProfit": "5"}}`)
+
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, "5", cfg.Rules.MinProfit)
+	})
+}
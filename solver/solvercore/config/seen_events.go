@@ -0,0 +1,191 @@
+package config
+
+// Module: Seen order events
+// - Remembers the Open events already handed to the solver, keyed by origin chain, order ID,
+//   transaction hash and log index
+// - Keeps blocks processed twice (backfill/poll boundary, resuming from a checkpoint) from
+//   dispatching the same event again
+// - Entries are persisted in the checkpoint storage (see SetCheckpointStorage), or the solver
+//   state file without one, and expire after SeenEventTTL
+// - Marking an event only updates the in-memory set; the changes are persisted in one write with
+//   the next checkpoint (UpdateLastIndexedBlock). An event handled after the last checkpoint is in
+//   a block range processed again after a crash, so it may be dispatched once more then.
+// - Expired entries are dropped when the set is loaded and whenever it is persisted
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
+)
+
+// SeenEventTTL is how long a handled event is remembered
+const SeenEventTTL = time.Hour
+
+var (
+	seenEvents    map[string]time.Time // nil until loaded from where it is persisted
+	seenEventsMu  sync.Mutex
+	seenEventsNow = time.Now
+
+	// Changes since the set was last persisted: events marked, and expired events to drop
+	seenEventsMarked  = make(map[string]time.Time)
+	seenEventsExpired = make(map[string]bool)
+)
+
+// seenEventChanges are the changes to the seen set taken for persisting
+type seenEventChanges struct {
+	all     map[string]time.Time // the whole set, for the state file
+	marked  map[string]time.Time
+	expired map[string]bool
+}
+
+// empty reports whether there is nothing to persist
+func (c seenEventChanges) empty() bool {
+	return len(c.marked) == 0 && len(c.expired) == 0
+}
+
+// SeenEventKey identifies an on-chain Open event
+func SeenEventKey(chainName, orderID, txHash string, logIndex uint) string {
+	return fmt.Sprintf("%s:%s:%s:%d", chainName, orderID, txHash, logIndex)
+}
+
+// ResetSeenEvents drops the in-memory set, with its unpersisted changes, so it is reloaded from
+// where it is persisted
+func ResetSeenEvents() {
+	seenEventsMu.Lock()
+	defer seenEventsMu.Unlock()
+	seenEvents = nil
+	seenEventsMarked = make(map[string]time.Time)
+	seenEventsExpired = make(map[string]bool)
+}
+
+// loadSeenEventsLocked fills the set from the checkpoint storage or the state file; callers hold seenEventsMu
+func loadSeenEventsLocked() {
+	if seenEvents != nil {
+		return
+	}
+	seenEvents = make(map[string]time.Time)
+	defer pruneSeenEventsLocked()
+	if s := getCheckpointStorage(); s != nil {
+		stored, err := loadStoredSeenEvents(s)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to load seen events: %v\n", err)
+			return
+		}
+		seenEvents = stored
+		return
+	}
+	if _, err := os.Stat(getSolverStateFilePath()); err != nil {
+		return
+	}
+	solverStateMu.Lock()
+	state, err := readSolverStateLocked()
+	solverStateMu.Unlock()
+	if err != nil {
+		fmt.Printf("⚠️  Failed to load seen events: %v\n", err)
+		return
+	}
+	for key, seenAt := range state.SeenEvents {
+		seenEvents[key] = time.Unix(seenAt, 0)
+	}
+}
+
+// pruneSeenEventsLocked drops the expired entries, to be removed where the set is persisted; callers hold seenEventsMu
+func pruneSeenEventsLocked() {
+	now := seenEventsNow()
+	for key, seenAt := range seenEvents {
+		if now.Sub(seenAt) >= SeenEventTTL {
+			delete(seenEvents, key)
+			delete(seenEventsMarked, key)
+			seenEventsExpired[key] = true
+		}
+	}
+}
+
+// EventSeen reports whether the event was handled less than SeenEventTTL ago
+func EventSeen(key string) bool {
+	seenEventsMu.Lock()
+	defer seenEventsMu.Unlock()
+	loadSeenEventsLocked()
+	seenAt, ok := seenEvents[key]
+	return ok && seenEventsNow().Sub(seenAt) < SeenEventTTL
+}
+
+// MarkEventSeen records a handled event; it is persisted with the next checkpoint
+func MarkEventSeen(key string) {
+	seenEventsMu.Lock()
+	defer seenEventsMu.Unlock()
+	loadSeenEventsLocked()
+
+	now := seenEventsNow()
+	seenEvents[key] = now
+	seenEventsMarked[key] = now
+	delete(seenEventsExpired, key)
+}
+
+// takeSeenEventChanges prunes the set and returns the changes to persist, which are no longer
+// pending; they are handed back with restoreSeenEventChanges if persisting them fails
+func takeSeenEventChanges() seenEventChanges {
+	seenEventsMu.Lock()
+	defer seenEventsMu.Unlock()
+	if seenEvents == nil {
+		return seenEventChanges{}
+	}
+	pruneSeenEventsLocked()
+	changes := seenEventChanges{
+		all:     make(map[string]time.Time, len(seenEvents)),
+		marked:  seenEventsMarked,
+		expired: seenEventsExpired,
+	}
+	for key, seenAt := range seenEvents {
+		changes.all[key] = seenAt
+	}
+	seenEventsMarked = make(map[string]time.Time)
+	seenEventsExpired = make(map[string]bool)
+	return changes
+}
+
+// restoreSeenEventChanges makes changes that failed to persist pending again, unless newer ones replaced them
+func restoreSeenEventChanges(changes seenEventChanges) {
+	seenEventsMu.Lock()
+	defer seenEventsMu.Unlock()
+	if seenEvents == nil {
+		return
+	}
+	for key, seenAt := range changes.marked {
+		if _, ok := seenEventsMarked[key]; !ok && !seenEventsExpired[key] {
+			seenEventsMarked[key] = seenAt
+		}
+	}
+	for key := range changes.expired {
+		if _, ok := seenEventsMarked[key]; !ok {
+			seenEventsExpired[key] = true
+		}
+	}
+}
+
+// storeSeenEventChanges persists changes in the checkpoint storage s
+func storeSeenEventChanges(s storage.Storage, changes seenEventChanges) error {
+	for key, seenAt := range changes.marked {
+		if err := putSeenEvent(s, key, seenAt); err != nil {
+			return fmt.Errorf("failed to store seen event: %w", err)
+		}
+	}
+	for key := range changes.expired {
+		if err := s.Delete(storage.SeenEvents, key); err != nil {
+			return fmt.Errorf("failed to drop seen event: %w", err)
+		}
+	}
+	return nil
+}
+
+// seenEventsState returns the whole set as kept in SolverState.SeenEvents
+func (c seenEventChanges) seenEventsState() map[string]int64 {
+	events := make(map[string]int64, len(c.all))
+	for key, seenAt := range c.all {
+		events[key] = seenAt.Unix()
+	}
+	return events
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeenEvents(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "solver-state.json")
+	t.Setenv("SOLVER_STATE_FILE", stateFile)
+	ResetNetworks()
+	InitializeNetworks()
+	t.Cleanup(ResetNetworks)
+	ResetSeenEvents()
+	t.Cleanup(ResetSeenEvents)
+	now := time.Unix(1_700_000_000, 0)
+	seenEventsNow = func() time.Time { return now }
+	t.Cleanup(func() { seenEventsNow = time.Now })
+
+	first := SeenEventKey("Base", "0x01", "0xaa", 0)
+	second := SeenEventKey("Base", "0x01", "0xaa", 1)
+
+	t.Run("lookups do not create the state file", func(t *testing.T) {
+		assert.False(t, EventSeen(first))
+		_, err := os.Stat(stateFile)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("marked events are seen and persisted with the next checkpoint", func(t *testing.T) {
+		MarkEventSeen(first)
+		assert.True(t, EventSeen(first))
+		assert.False(t, EventSeen(second), "log index is part of the key")
+		_, err := os.Stat(stateFile)
+		assert.True(t, os.IsNotExist(err), "marking doesn't write the state file")
+
+		require.NoError(t, UpdateLastIndexedBlock("Base", 100))
+		ResetSeenEvents()
+		assert.True(t, EventSeen(first))
+		state, err := GetSolverState()
+		require.NoError(t, err)
+		assert.Equal(t, now.Unix(), state.SeenEvents[first])
+	})
+
+	t.Run("events expire", func(t *testing.T) {
+		now = now.Add(SeenEventTTL)
+		assert.False(t, EventSeen(first))
+
+		MarkEventSeen(second)
+		require.NoError(t, UpdateLastIndexedBlock("Base", 101))
+		state, err := GetSolverState()
+		require.NoError(t, err)
+		assert.NotContains(t, state.SeenEvents, first, "expired entries are dropped")
+		assert.Contains(t, state.SeenEvents, second)
+	})
+
+	t.Run("expired entries are dropped on load", func(t *testing.T) {
+		now = now.Add(SeenEventTTL)
+		ResetSeenEvents()
+		assert.False(t, EventSeen(second))
+
+		require.NoError(t, UpdateLastIndexedBlock("Base", 102))
+		state, err := GetSolverState()
+		require.NoError(t, err)
+		assert.Empty(t, state.SeenEvents)
+	})
+}
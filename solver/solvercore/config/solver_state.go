@@ -1,7 +1,8 @@
 // Package config manages solver state persistence across networks.
 //
-// SolverState tracks only the last processed blocks for solver listeners
-// across all networks (Ethereum, Optimism, Arbitrum, Base, Starknet).
+// SolverState tracks the last processed blocks for solver listeners
+// across all networks (Ethereum, Optimism, Arbitrum, Base, Starknet),
+// caches token metadata discovered on-chain and remembers recently handled events.
 // All contract addresses and network config come from .env files.
 //
 // Key Features:
@@ -39,6 +40,10 @@ const (
 // SolverState holds only the solver persistence data across all networks
 type SolverState struct {
 	Networks map[string]SolverNetworkState `json:"networks"`
+	// Tokens caches token metadata discovered on-chain, keyed by "<chainID>:<address>"
+	Tokens map[string]TokenConfig `json:"tokens,omitempty"`
+	// SeenEvents holds recently handled Open events (see SeenEventKey) and when they were handled, in unix seconds
+	SeenEvents map[string]int64 `json:"seenEvents,omitempty"`
 }
 
 // SolverNetworkState holds only the last indexed block for solver listeners
@@ -53,7 +58,7 @@ func getDefaultSolverState() SolverState {
 	// Ensure config is loaded before accessing Networks
 	InitializeNetworks()
 
-	return SolverState{
+	state := SolverState{
 		Networks: map[string]SolverNetworkState{
 			"Ethereum": {
 				LastIndexedBlock: resolveSolverStartBlock(Networks["Ethereum"].SolverStartBlock),
@@ -77,6 +82,26 @@ func getDefaultSolverState() SolverState {
 			},
 		},
 	}
+	addConfiguredNetworks(&state)
+	return state
+}
+
+// addConfiguredNetworks adds networks configured after the state file was created (e.g. through
+// STARKNET_NETWORKS), starting from their configured start block; it reports whether any was added
+func addConfiguredNetworks(state *SolverState) bool {
+	InitializeNetworks()
+	if state.Networks == nil {
+		state.Networks = make(map[string]SolverNetworkState)
+	}
+	added := false
+	for name, network := range Networks {
+		if _, exists := state.Networks[name]; exists {
+			continue
+		}
+		state.Networks[name] = SolverNetworkState{LastIndexedBlock: resolveSolverStartBlock(network.SolverStartBlock)}
+		added = true
+	}
+	return added
 }
 
 // resolveSolverStartBlock resolves a solver start block to a valid uint64
@@ -95,11 +120,31 @@ func resolveSolverStartBlock(solverStartBlock int64) uint64 {
 // process-local lock to serialize state file access
 var solverStateMu sync.Mutex
 
-// GetSolverState loads the current solver state from file
+// GetSolverState loads the current solver state from file, with the checkpoints of the checkpoint
+// storage if any
 func GetSolverState() (*SolverState, error) {
 	solverStateMu.Lock()
 	defer solverStateMu.Unlock()
-	return readSolverStateLocked()
+
+	// The checkpoint storage doesn't need the state file: without one the defaults are not saved
+	if getCheckpointStorage() != nil {
+		if _, err := os.Stat(getSolverStateFilePath()); os.IsNotExist(err) {
+			state := getDefaultSolverState()
+			if err := overlayCheckpoints(&state); err != nil {
+				return nil, fmt.Errorf("failed to load checkpoints: %w", err)
+			}
+			return &state, nil
+		}
+	}
+
+	state, err := readSolverStateLocked()
+	if err != nil {
+		return nil, err
+	}
+	if err := overlayCheckpoints(state); err != nil {
+		return nil, fmt.Errorf("failed to load checkpoints: %w", err)
+	}
+	return state, nil
 }
 
 // SaveSolverState saves the solver state to file
@@ -109,8 +154,37 @@ func SaveSolverState(state *SolverState) error {
 	return saveSolverStateLocked(state)
 }
 
-// UpdateLastIndexedBlock updates the LastIndexedBlock for a specific network and saves to file
+// UpdateLastIndexedBlock updates the LastIndexedBlock for a specific network and saves it to the
+// checkpoint storage, or the state file without one, with the seen events marked since the last
+// checkpoint
 func UpdateLastIndexedBlock(networkName string, newBlockNumber uint64) error {
+	if s := getCheckpointStorage(); s != nil {
+		if !ValidateNetworkName(networkName) {
+			return fmt.Errorf("network %s not found in solver state", networkName)
+		}
+		seen := takeSeenEventChanges()
+		if err := storeSeenEventChanges(s, seen); err != nil {
+			restoreSeenEventChanges(seen)
+			return err
+		}
+		network := SolverNetworkState{LastIndexedBlock: newBlockNumber, LastUpdated: time.Now().Format(time.RFC3339)}
+		if err := putCheckpoint(s, networkName, network); err != nil {
+			return fmt.Errorf("failed to store checkpoint: %w", err)
+		}
+		return nil
+	}
+
+	// The seen events are taken before locking the state file, as loading them locks it too
+	seen := takeSeenEventChanges()
+	if err := updateStateFileCheckpoint(networkName, newBlockNumber, seen); err != nil {
+		restoreSeenEventChanges(seen)
+		return err
+	}
+	return nil
+}
+
+// updateStateFileCheckpoint saves the LastIndexedBlock of a network and the seen events to the state file
+func updateStateFileCheckpoint(networkName string, newBlockNumber uint64, seen seenEventChanges) error {
 	solverStateMu.Lock()
 	defer solverStateMu.Unlock()
 
@@ -127,11 +201,13 @@ func UpdateLastIndexedBlock(networkName string, newBlockNumber uint64) error {
 	network.LastIndexedBlock = newBlockNumber
 	network.LastUpdated = time.Now().Format(time.RFC3339)
 	state.Networks[networkName] = network
+	if !seen.empty() {
+		state.SeenEvents = seen.seenEventsState()
+	}
 
 	if err := saveSolverStateLocked(state); err != nil {
 		return fmt.Errorf("failed to save solver state: %w", err)
 	}
-
 	return nil
 }
 
@@ -201,6 +277,11 @@ func readSolverStateLocked() (*SolverState, error) {
 			continue
 		}
 
+		if addConfiguredNetworks(&state) {
+			if err := saveSolverStateLocked(&state); err != nil {
+				return nil, fmt.Errorf("failed to add new networks to solver state: %w", err)
+			}
+		}
 		return &state, nil
 	}
 	return nil, lastErr
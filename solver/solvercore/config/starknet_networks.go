@@ -0,0 +1,138 @@
+package config
+
+// Module: Additional Starknet networks
+// - The built-in "Starknet" network is configured by the STARKNET_* variables; STARKNET_NETWORKS
+//   adds more Starknet chains next to it (e.g. a devnet and Sepolia, or an appchain), comma separated
+// - Every added name must contain "Starknet", which is how the solver tells Starknet networks apart
+// - Each added network reads <NAME>_* variables, NAME being the upper-cased network name with spaces
+//   replaced by underscores (the same prefix as RPC_TIMEOUT_<NETWORK>_SECONDS):
+//   <NAME>_RPC_URL and <NAME>_CHAIN_ID (required), <NAME>_HYPERLANE_ADDRESS, <NAME>_DOMAIN_ID
+//   (default chain ID), <NAME>_SOLVER_START_BLOCK, <NAME>_POLL_INTERVAL_MS, <NAME>_CONFIRMATION_BLOCKS,
+//   <NAME>_MAX_BLOCK_RANGE, <NAME>_ENABLED, <NAME>_FILLS_ENABLED and <NAME>_EXPLORER_URL
+// - Chain IDs must be unique across networks, since orders are routed by chain ID
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+)
+
+// StarknetNetworksEnv lists the additional Starknet networks by name
+const StarknetNetworksEnv = "STARKNET_NETWORKS"
+
+// IsStarknetNetwork reports whether a network name designates a Starknet network
+func IsStarknetNetwork(networkName string) bool {
+	return strings.Contains(strings.ToLower(networkName), "starknet")
+}
+
+// NetworkEnvPrefix returns the prefix of the environment variables of a network, e.g. "STARKNET_SEPOLIA"
+// for "Starknet Sepolia"
+func NetworkEnvPrefix(networkName string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(networkName), " ", "_"))
+}
+
+// StarknetHyperlaneAddressEnv returns the variable holding the Hyperlane7683 address of a Starknet
+// network: STARKNET_HYPERLANE_ADDRESS for the built-in network (or an unnamed one), else <NAME>_HYPERLANE_ADDRESS
+func StarknetHyperlaneAddressEnv(networkName string) string {
+	if networkName == "" || networkName == "Starknet" {
+		return "STARKNET_HYPERLANE_ADDRESS"
+	}
+	return NetworkEnvPrefix(networkName) + "_HYPERLANE_ADDRESS"
+}
+
+// StarknetHyperlaneAddress returns the Hyperlane7683 address of a Starknet network; it is read from
+// the environment because NetworkConfig only holds 20-byte addresses
+func StarknetHyperlaneAddress(networkName string) string {
+	return envutil.GetEnvWithDefault(StarknetHyperlaneAddressEnv(networkName), "")
+}
+
+// StarknetNetworkNames returns the names of the configured Starknet networks
+func StarknetNetworkNames() []string {
+	ensureInitialized()
+	var names []string
+	for _, name := range GetNetworkNames() {
+		if IsStarknetNetwork(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// additionalStarknetNetworks reads the networks listed in STARKNET_NETWORKS; invalid entries are
+// reported and skipped so the other networks still start
+func additionalStarknetNetworks(existing map[string]NetworkConfig) []NetworkConfig {
+	var added []NetworkConfig
+	for _, name := range strings.Split(os.Getenv(StarknetNetworksEnv), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		network, err := starknetNetworkFromEnv(name)
+		if err == nil {
+			err = checkUniqueNetwork(network, existing, added)
+		}
+		if err != nil {
+			fmt.Printf("⚠️  Skipping Starknet network %q from %s: %v\n", name, StarknetNetworksEnv, err)
+			continue
+		}
+		added = append(added, network)
+	}
+	return added
+}
+
+// starknetNetworkFromEnv builds the config of an additional Starknet network from its <NAME>_* variables
+func starknetNetworkFromEnv(name string) (NetworkConfig, error) {
+	if !IsStarknetNetwork(name) {
+		return NetworkConfig{}, fmt.Errorf("name must contain \"Starknet\"")
+	}
+	prefix := NetworkEnvPrefix(name)
+	rpcURL := envutil.GetEnvWithDefault(prefix+"_RPC_URL", "")
+	if rpcURL == "" {
+		return NetworkConfig{}, fmt.Errorf("%s_RPC_URL not set", prefix)
+	}
+	chainID := envutil.GetEnvUint64(prefix+"_CHAIN_ID", 0)
+	if chainID == 0 {
+		return NetworkConfig{}, fmt.Errorf("%s_CHAIN_ID not set", prefix)
+	}
+
+	// Negative start blocks count back from the latest block, as for the built-in networks
+	startBlock := int64(envutil.GetEnvInt(prefix+"_SOLVER_START_BLOCK", StarknetDefaultStartBlock))
+	forkStartBlock := uint64(0)
+	if startBlock > 0 {
+		forkStartBlock = uint64(startBlock)
+	}
+	return NetworkConfig{
+		Name:               name,
+		RPCURL:             rpcURL,
+		ChainID:            chainID,
+		HyperlaneDomain:    envutil.GetEnvUint64(prefix+"_DOMAIN_ID", chainID),
+		ForkStartBlock:     forkStartBlock,
+		SolverStartBlock:   startBlock,
+		PollInterval:       envutil.GetEnvInt(prefix+"_POLL_INTERVAL_MS", envutil.GetEnvInt("POLL_INTERVAL_MS", StarknetDefaultPollIntervalMs)),
+		ConfirmationBlocks: envutil.GetEnvUint64(prefix+"_CONFIRMATION_BLOCKS", 0),
+		MaxBlockRange: envutil.GetEnvUint64(prefix+"_MAX_BLOCK_RANGE",
+			envutil.GetEnvUint64("MAX_BLOCK_RANGE", StarknetDefaultMaxBlockRange)),
+		Enabled:      envutil.GetEnvBool(prefix+"_ENABLED", true),
+		FillsEnabled: envutil.GetEnvBool(prefix+"_FILLS_ENABLED", true),
+	}, nil
+}
+
+// checkUniqueNetwork rejects a network whose name or chain ID is already taken
+func checkUniqueNetwork(network NetworkConfig, existing map[string]NetworkConfig, added []NetworkConfig) error {
+	taken := make([]NetworkConfig, 0, len(existing)+len(added))
+	for _, other := range existing {
+		taken = append(taken, other)
+	}
+	taken = append(taken, added...)
+	for _, other := range taken {
+		if other.Name == network.Name {
+			return fmt.Errorf("network %s already exists", network.Name)
+		}
+		if other.ChainID == network.ChainID {
+			return fmt.Errorf("chain ID %d already used by %s", network.ChainID, other.Name)
+		}
+	}
+	return nil
+}
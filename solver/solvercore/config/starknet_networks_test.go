@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStarknetNetworks(t *testing.T) {
+	resetNetworks := func(t *testing.T) {
+		ResetNetworks()
+		InitializeNetworks()
+		t.Cleanup(func() {
+			ResetNetworks()
+			InitializeNetworks()
+		})
+	}
+
+	t.Run("added networks read their own variables", func(t *testing.T) {
+		t.Setenv("STARKNET_NETWORKS", "StarknetDevnet, Starknet Appchain")
+		t.Setenv("STARKNETDEVNET_RPC_URL", "http://localhost:5050")
+		t.Setenv("STARKNETDEVNET_CHAIN_ID", "5050")
+		t.Setenv("STARKNETDEVNET_SOLVER_START_BLOCK", "-10")
+		t.Setenv("STARKNETDEVNET_FILLS_ENABLED", "false")
+		t.Setenv("STARKNET_APPCHAIN_RPC_URL", "http://localhost:5051")
+		t.Setenv("STARKNET_APPCHAIN_CHAIN_ID", "5051")
+		t.Setenv("STARKNET_APPCHAIN_DOMAIN_ID", "77")
+		t.Setenv("STARKNET_APPCHAIN_HYPERLANE_ADDRESS", "0xabc")
+		resetNetworks(t)
+
+		devnet, err := GetNetworkConfig("StarknetDevnet")
+		require.NoError(t, err)
+		assert.Equal(t, "http://localhost:5050", devnet.RPCURL)
+		assert.Equal(t, uint64(5050), devnet.ChainID)
+		assert.Equal(t, uint64(5050), devnet.HyperlaneDomain, "domain defaults to the chain ID")
+		assert.Equal(t, int64(-10), devnet.SolverStartBlock)
+		assert.True(t, devnet.Enabled)
+		assert.False(t, devnet.FillsEnabled)
+
+		appchain, err := GetNetworkConfig("Starknet Appchain")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(77), appchain.HyperlaneDomain)
+		assert.Equal(t, "0xabc", StarknetHyperlaneAddress("Starknet Appchain"))
+
+		assert.ElementsMatch(t, []string{"Starknet", "StarknetDevnet", "Starknet Appchain"}, StarknetNetworkNames())
+	})
+
+	t.Run("invalid entries are skipped", func(t *testing.T) {
+		t.Setenv("STARKNET_NETWORKS", "Solana,StarknetNoURL,StarknetDuplicate")
+		t.Setenv("SOLANA_RPC_URL", "http://localhost:8899")
+		t.Setenv("SOLANA_CHAIN_ID", "101")
+		t.Setenv("STARKNETNOURL_CHAIN_ID", "5052")
+		t.Setenv("STARKNETDUPLICATE_RPC_URL", "http://localhost:5053")
+		t.Setenv("STARKNETDUPLICATE_CHAIN_ID", "84532") // Base Sepolia
+		resetNetworks(t)
+
+		for _, name := range []string{"Solana", "StarknetNoURL", "StarknetDuplicate"} {
+			assert.False(t, ValidateNetworkName(name), name)
+		}
+		assert.Equal(t, []string{"Starknet"}, StarknetNetworkNames())
+	})
+
+	t.Run("built-in network keeps STARKNET_HYPERLANE_ADDRESS", func(t *testing.T) {
+		assert.Equal(t, "STARKNET_HYPERLANE_ADDRESS", StarknetHyperlaneAddressEnv("Starknet"))
+		assert.Equal(t, "STARKNET_HYPERLANE_ADDRESS", StarknetHyperlaneAddressEnv(""))
+		assert.Equal(t, "STARKNET_SEPOLIA_HYPERLANE_ADDRESS", StarknetHyperlaneAddressEnv("Starknet Sepolia"))
+		assert.True(t, IsStarknetNetwork("starknet-devnet"))
+		assert.False(t, IsStarknetNetwork("Base"))
+	})
+
+	t.Run("solver state picks up added networks", func(t *testing.T) {
+		stateFile := filepath.Join(t.TempDir(), "solver-state.json")
+		t.Setenv("SOLVER_STATE_FILE", stateFile)
+		require.NoError(t, os.WriteFile(stateFile, []byte(`{"networks": {"Starknet": {"lastIndexedBlock": 42}}}`), 0o600))
+		t.Setenv("STARKNET_NETWORKS", "StarknetDevnet")
+		t.Setenv("STARKNETDEVNET_RPC_URL", "http://localhost:5050")
+		t.Setenv("STARKNETDEVNET_CHAIN_ID", "5050")
+		t.Setenv("STARKNETDEVNET_SOLVER_START_BLOCK", "7")
+		resetNetworks(t)
+
+		state, err := GetSolverState()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(42), state.Networks["Starknet"].LastIndexedBlock)
+		assert.Equal(t, uint64(7), state.Networks["StarknetDevnet"].LastIndexedBlock)
+
+		require.NoError(t, UpdateLastIndexedBlock("StarknetDevnet", 9))
+		state, err = GetSolverState()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(9), state.Networks["StarknetDevnet"].LastIndexedBlock)
+	})
+}
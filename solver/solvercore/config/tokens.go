@@ -0,0 +1,259 @@
+package config
+
+// Module: Token registry
+// - Tokens the solver knows per chain: symbol, decimals and optional min/max order size
+// - Loaded from the "tokens" section of the config file and reloaded with the runtime config
+// - Consulted by the TokenCheck rule and to format amounts in logs ("12.5 DOG" instead of base units)
+// - Lists the balances the balance monitor watches, with their optional low-balance alert thresholds
+// - Tokens with a targetBalance are moved between chains by the rebalancer (see rebalancer.go of
+//   the hyperlane7683 solver) when a chain drops below its lowBalance threshold
+// - Tokens with a preApprove amount are approved for the chain's Hyperlane7683 router at startup
+// - Token prices value orders whose input and output tokens differ (see package pricefeed)
+// - Tokens missing from the registry fall back to metadata discovered on-chain (discovered_tokens.go)
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// maxTokenDecimals bounds decimals so amounts can always be scaled (ERC20 decimals is a uint8)
+const maxTokenDecimals = 77
+
+// TokenConfig describes one token on one chain
+type TokenConfig struct {
+	ChainID  uint64 `json:"chainId"`
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+	// MinOrderSize and MaxOrderSize bound the amount the solver spends on an order, in whole
+	// tokens (e.g. "12.5"); empty means no bound
+	MinOrderSize string `json:"minOrderSize,omitempty"`
+	MaxOrderSize string `json:"maxOrderSize,omitempty"`
+	// LowBalance alerts when the solver's balance of the token drops below it, in whole tokens
+	// (e.g. "100"); empty means no alert
+	LowBalance string `json:"lowBalance,omitempty"`
+	// TargetBalance is the balance the rebalancer restores on a chain below LowBalance, taking the
+	// excess of chains above it, in whole tokens (e.g. "1000"); empty leaves the token out of rebalancing
+	TargetBalance string `json:"targetBalance,omitempty"`
+	// PreApprove is the router allowance ensured at startup, in whole tokens (e.g. "1000"); empty
+	// leaves approvals to the fills
+	PreApprove string `json:"preApprove,omitempty"`
+	// Price is the value of one whole token in the quote currency shared by all tokens (e.g. "1.25"
+	// for USD), used to value orders whose input and output tokens differ; empty means unpriced
+	Price string `json:"price,omitempty"`
+}
+
+// MinOrder returns the minimum order size in base units, nil without a bound
+func (t TokenConfig) MinOrder() *big.Int {
+	amount, _ := t.parseAmount(t.MinOrderSize)
+	return amount
+}
+
+// MaxOrder returns the maximum order size in base units, nil without a bound
+func (t TokenConfig) MaxOrder() *big.Int {
+	amount, _ := t.parseAmount(t.MaxOrderSize)
+	return amount
+}
+
+// LowBalanceThreshold returns the low-balance alert threshold in base units, nil without one
+func (t TokenConfig) LowBalanceThreshold() *big.Int {
+	amount, _ := t.parseAmount(t.LowBalance)
+	return amount
+}
+
+// TargetBalanceAmount returns the rebalancing target in base units, nil without one
+func (t TokenConfig) TargetBalanceAmount() *big.Int {
+	amount, _ := t.parseAmount(t.TargetBalance)
+	return amount
+}
+
+// PreApproveAmount returns the allowance ensured at startup in base units, nil without one
+func (t TokenConfig) PreApproveAmount() *big.Int {
+	amount, _ := t.parseAmount(t.PreApprove)
+	return amount
+}
+
+// PriceUnits returns the price of one whole token with NotionalDecimals decimals, nil when unpriced
+func (t TokenConfig) PriceUnits() *big.Int {
+	price, _ := parsePrice(t.Price)
+	return price
+}
+
+// parsePrice converts a price (e.g. "1.25") into NotionalDecimals units, nil when empty
+func parsePrice(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := amount.Parse(value, NotionalDecimals)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Units.Sign() == 0 {
+		return nil, errors.New("must be above 0")
+	}
+	return parsed.Units, nil
+}
+
+// Format renders a base unit amount with the token's decimals and symbol, e.g. "12.5 DOG"
+func (t TokenConfig) Format(amount *big.Int) string {
+	return types.FormatTokenAmountWithSymbol(amount, t.Decimals, t.Symbol)
+}
+
+// parseAmount converts a whole token amount (e.g. "12.5") into base units, nil when empty
+func (t TokenConfig) parseAmount(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := amount.Parse(value, t.Decimals)
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Units, nil
+}
+
+func (t TokenConfig) validate() error {
+	var errs []error
+	if _, err := normalizeTokenAddress(t.Address); err != nil {
+		errs = append(errs, err)
+	}
+	if t.Symbol == "" {
+		errs = append(errs, errors.New("empty symbol"))
+	}
+	if t.Decimals < 0 || t.Decimals > maxTokenDecimals {
+		errs = append(errs, fmt.Errorf("decimals must be between 0 and %d, got %d", maxTokenDecimals, t.Decimals))
+		return errors.Join(errs...)
+	}
+
+	minOrder, err := t.parseAmount(t.MinOrderSize)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("minOrderSize: %w", err))
+	}
+	maxOrder, err := t.parseAmount(t.MaxOrderSize)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("maxOrderSize: %w", err))
+	}
+	if minOrder != nil && maxOrder != nil && minOrder.Cmp(maxOrder) > 0 {
+		errs = append(errs, errors.New("minOrderSize is above maxOrderSize"))
+	}
+	if _, err := t.parseAmount(t.LowBalance); err != nil {
+		errs = append(errs, fmt.Errorf("lowBalance: %w", err))
+	}
+	lowBalance, _ := t.parseAmount(t.LowBalance)
+	target, err := t.parseAmount(t.TargetBalance)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("targetBalance: %w", err))
+	}
+	if target != nil && lowBalance != nil && target.Cmp(lowBalance) < 0 {
+		errs = append(errs, errors.New("targetBalance is below lowBalance"))
+	}
+	if _, err := t.parseAmount(t.PreApprove); err != nil {
+		errs = append(errs, fmt.Errorf("preApprove: %w", err))
+	}
+	if _, err := parsePrice(t.Price); err != nil {
+		errs = append(errs, fmt.Errorf("price: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// normalizeTokenAddress returns a canonical form of a hex address so EVM addresses, their
+// bytes32 encoding in events and zero-padded Starknet felts of the same value compare equal
+func normalizeTokenAddress(address string) (string, error) {
+	digits, hasPrefix := strings.CutPrefix(strings.ToLower(address), "0x")
+	value, ok := new(big.Int).SetString(digits, 16)
+	if !hasPrefix || !ok || value.Sign() < 0 {
+		return "", fmt.Errorf("invalid token address %q", address)
+	}
+	return "0x" + value.Text(16), nil
+}
+
+type tokenKey struct {
+	chainID uint64
+	address string
+}
+
+// TokenRegistry looks up token metadata by chain ID and address
+type TokenRegistry struct {
+	tokens map[tokenKey]TokenConfig
+}
+
+// NewTokenRegistry builds a registry, rejecting invalid entries and tokens listed twice
+func NewTokenRegistry(tokens []TokenConfig) (*TokenRegistry, error) {
+	r := &TokenRegistry{tokens: make(map[tokenKey]TokenConfig, len(tokens))}
+	var errs []error
+	for i, token := range tokens {
+		if err := token.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("tokens[%d]: %w", i, err))
+			continue
+		}
+		address, _ := normalizeTokenAddress(token.Address)
+		key := tokenKey{chainID: token.ChainID, address: address}
+		if _, ok := r.tokens[key]; ok {
+			errs = append(errs, fmt.Errorf("tokens[%d]: %s listed twice for chain %d", i, token.Address, token.ChainID))
+			continue
+		}
+		r.tokens[key] = token
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Len returns the number of registered tokens
+func (r *TokenRegistry) Len() int {
+	return len(r.tokens)
+}
+
+// Lookup returns the token registered for an address on a chain
+func (r *TokenRegistry) Lookup(chainID uint64, address string) (TokenConfig, bool) {
+	normalized, err := normalizeTokenAddress(address)
+	if err != nil {
+		return TokenConfig{}, false
+	}
+	token, ok := r.tokens[tokenKey{chainID: chainID, address: normalized}]
+	return token, ok
+}
+
+// FormatAmount renders an amount of a token for logs, falling back to base units for unknown tokens
+func (r *TokenRegistry) FormatAmount(chainID uint64, address string, amount *big.Int) string {
+	if token, ok := r.Lookup(chainID, address); ok {
+		return token.Format(amount)
+	}
+	return formatBaseUnits(amount)
+}
+
+func formatBaseUnits(amount *big.Int) string {
+	if amount == nil {
+		return "0"
+	}
+	return amount.String() + " (base units)"
+}
+
+// FormatTokenAmount renders an amount of a token with the active registry or discovered
+// metadata, e.g. "12.5 DOG"
+func FormatTokenAmount(chainID uint64, address string, amount *big.Int) string {
+	if token, ok := LookupToken(chainID, address); ok {
+		return token.Format(amount)
+	}
+	return formatBaseUnits(amount)
+}
+
+// NotionalDecimals is the unit order notionals are counted in: amounts of tokens with known
+// decimals are scaled to it and added up as if every token had the same value
+const NotionalDecimals = 18
+
+// ScaleTokenAmount converts a base unit amount of a token to the given number of decimals so
+// amounts of tokens with different decimals can be added up; scaling down truncates.
+// ok is false when the token's decimals are unknown.
+func ScaleTokenAmount(chainID uint64, address string, units *big.Int, decimals int) (*big.Int, bool) {
+	token, ok := LookupToken(chainID, address)
+	if !ok || units == nil {
+		return nil, false
+	}
+	return amount.New(units, token.Decimals).Scale(decimals).Units, true
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenRegistry(t *testing.T) {
+	dog := TokenConfig{
+		ChainID:      84532,
+		Address:      "0x00000000000000000000000000000000000000A1",
+		Symbol:       "DOG",
+		Decimals:     6,
+		MinOrderSize: "1.5",
+		MaxOrderSize: "1000",
+		LowBalance:   "250",
+		PreApprove:   "1000",
+	}
+
+	t.Run("lookup matches any encoding of the address", func(t *testing.T) {
+		registry, err := NewTokenRegistry([]TokenConfig{dog})
+		require.NoError(t, err)
+		assert.Equal(t, 1, registry.Len())
+
+		for _, address := range []string{
+			"0x00000000000000000000000000000000000000a1",
+			"0x00000000000000000000000000000000000000000000000000000000000000a1",
+			"0xa1",
+		} {
+			token, ok := registry.Lookup(84532, address)
+			assert.True(t, ok, address)
+			assert.Equal(t, "DOG", token.Symbol)
+		}
+
+		_, ok := registry.Lookup(11155111, dog.Address)
+		assert.False(t, ok, "tokens are registered per chain")
+		_, ok = registry.Lookup(84532, "not an address")
+		assert.False(t, ok)
+	})
+
+	t.Run("order sizes are converted to base units", func(t *testing.T) {
+		assert.Equal(t, big.NewInt(1_500_000), dog.MinOrder())
+		assert.Equal(t, big.NewInt(1_000_000_000), dog.MaxOrder())
+		assert.Nil(t, TokenConfig{Decimals: 6}.MinOrder())
+		assert.Equal(t, big.NewInt(250_000_000), dog.LowBalanceThreshold())
+		assert.Nil(t, TokenConfig{Decimals: 6}.LowBalanceThreshold())
+		assert.Equal(t, big.NewInt(2_000_000), TokenConfig{Decimals: 6, TargetBalance: "2"}.TargetBalanceAmount())
+		assert.Nil(t, dog.TargetBalanceAmount())
+		assert.Equal(t, big.NewInt(1_000_000_000), dog.PreApproveAmount())
+		assert.Nil(t, TokenConfig{Decimals: 6}.PreApproveAmount())
+		assert.Equal(t, big.NewInt(1_250_000_000_000_000_000), TokenConfig{Price: "1.25"}.PriceUnits())
+		assert.Nil(t, dog.PriceUnits())
+	})
+
+	t.Run("amounts are formatted with the symbol", func(t *testing.T) {
+		registry, err := NewTokenRegistry([]TokenConfig{dog})
+		require.NoError(t, err)
+		assert.Equal(t, "12.5 DOG", registry.FormatAmount(84532, dog.Address, big.NewInt(12_500_000)))
+		assert.Equal(t, "12500000 (base units)", registry.FormatAmount(1, dog.Address, big.NewInt(12_500_000)))
+	})
+
+	t.Run("invalid entries are rejected", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			token   TokenConfig
+			wantErr string
+		}{
+			{"invalid address", TokenConfig{Address: "a1", Symbol: "DOG"}, "invalid token address"},
+			{"missing symbol", TokenConfig{Address: "0xa1"}, "empty symbol"},
+			{"negative decimals", TokenConfig{Address: "0xa1", Symbol: "DOG", Decimals: -1}, "decimals"},
+			{"too many decimals in size", TokenConfig{Address: "0xa1", Symbol: "DOG", Decimals: 2, MinOrderSize: "0.001"}, "minOrderSize"},
+			{"negative size", TokenConfig{Address: "0xa1", Symbol: "DOG", MaxOrderSize: "-1"}, "maxOrderSize"},
+			{"min above max", TokenConfig{Address: "0xa1", Symbol: "DOG", MinOrderSize: "2", MaxOrderSize: "1"}, "above maxOrderSize"},
+			{"invalid low balance", TokenConfig{Address: "0xa1", Symbol: "DOG", LowBalance: "lots"}, "lowBalance"},
+			{"invalid target balance", TokenConfig{Address: "0xa1", Symbol: "DOG", TargetBalance: "-1"}, "targetBalance"},
+			{"target below low balance", TokenConfig{Address: "0xa1", Symbol: "DOG", LowBalance: "10", TargetBalance: "5"}, "targetBalance is below lowBalance"},
+			{"invalid pre-approve amount", TokenConfig{Address: "0xa1", Symbol: "DOG", PreApprove: "-1"}, "preApprove"},
+			{"invalid price", TokenConfig{Address: "0xa1", Symbol: "DOG", Price: "cheap"}, "price"},
+			{"zero price", TokenConfig{Address: "0xa1", Symbol: "DOG", Price: "0"}, "price: must be above 0"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := NewTokenRegistry([]TokenConfig{tt.token})
+				assert.ErrorContains(t, err, tt.wantErr)
+			})
+		}
+	})
+
+	t.Run("duplicate tokens are rejected", func(t *testing.T) {
+		padded := dog
+		padded.Address = "0x00000000000000000000000000000000000000000000000000000000000000a1"
+		_, err := NewTokenRegistry([]TokenConfig{dog, padded})
+		assert.ErrorContains(t, err, "tokens[1]")
+		assert.ErrorContains(t, err, "listed twice")
+	})
+
+	t.Run("active runtime config provides the registry", func(t *testing.T) {
+		restoreRuntime(t)
+		require.NoError(t, ApplyRuntime(&RuntimeConfig{Tokens: []TokenConfig{dog}}))
+		assert.Equal(t, "0.000001 DOG", FormatTokenAmount(84532, dog.Address, big.NewInt(1)))
+
+		err := ApplyRuntime(&RuntimeConfig{Tokens: []TokenConfig{{Address: "0xa1"}}})
+		assert.ErrorContains(t, err, "tokens[0]")
+		assert.Equal(t, 1, ActiveRuntime().TokenRegistry().Len())
+	})
+}
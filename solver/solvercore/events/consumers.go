@@ -0,0 +1,168 @@
+package events
+
+// Module: Event consumers
+// - StartMetrics counts events in solver_order_events_total{kind} and exposes the deliveries
+//   the bus dropped in solver_order_events_dropped
+// - Recorder keeps the latest events for GET /events of the admin API
+// - Webhook posts every event as JSON to ORDER_EVENTS_WEBHOOK_URL
+//
+// Each consumer subscribes on Start and returns the function stopping it, like the solver's other
+// background workers.
+//
+// Settings:
+// - ORDER_EVENTS_WEBHOOK_URL: receives order events as JSON (Event); unset disables the webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+)
+
+const (
+	// subscriberBuffer is the channel capacity of the consumers; events past it are dropped
+	subscriberBuffer = 256
+
+	eventsMetric        = "solver_order_events_total"
+	droppedEventsMetric = "solver_order_events_dropped"
+
+	webhookTimeout = 10 * time.Second
+)
+
+// consume runs handle for every event of bus until ctx is done or the returned function is called
+func consume(ctx context.Context, bus *Bus, handle func(context.Context, Event)) func() {
+	ch, unsubscribe := bus.Subscribe(subscriberBuffer)
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-ch:
+				handle(ctx, event)
+			}
+		}
+	}()
+	return cancel
+}
+
+// StartMetrics counts the events of bus in registry
+func StartMetrics(ctx context.Context, bus *Bus, registry *metrics.Registry) func() {
+	for _, kind := range Kinds {
+		registry.Counter(eventsMetric, "kind", string(kind))
+	}
+	return consume(ctx, bus, func(_ context.Context, event Event) {
+		registry.Counter(eventsMetric, "kind", string(event.Kind)).Inc()
+		registry.Gauge(droppedEventsMetric).Set(float64(bus.Dropped()))
+	})
+}
+
+// Recorder keeps the latest events, oldest overwritten first
+type Recorder struct {
+	mu     sync.Mutex
+	events []Event
+	next   int
+	full   bool
+}
+
+// NewRecorder returns a recorder keeping up to size events
+func NewRecorder(size int) *Recorder {
+	return &Recorder{events: make([]Event, size)}
+}
+
+// Start records the events of bus
+func (r *Recorder) Start(ctx context.Context, bus *Bus) func() {
+	return consume(ctx, bus, func(_ context.Context, event Event) {
+		r.record(event)
+	})
+}
+
+// record adds event, overwriting the oldest one when full
+func (r *Recorder) record(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.events) == 0 {
+		return
+	}
+	r.events[r.next] = event
+	r.next = (r.next + 1) % len(r.events)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Recent returns up to limit events, newest first; limit <= 0 returns every recorded event
+func (r *Recorder) Recent(limit int) []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := r.next
+	if r.full {
+		count = len(r.events)
+	}
+	if limit > 0 && limit < count {
+		count = limit
+	}
+	recent := make([]Event, 0, count)
+	for i := 1; i <= count; i++ {
+		recent = append(recent, r.events[(r.next-i+len(r.events))%len(r.events)])
+	}
+	return recent
+}
+
+// Webhook posts events to a URL
+type Webhook struct {
+	url  string
+	http *http.Client
+}
+
+// NewWebhook returns a webhook posting to url
+func NewWebhook(url string) *Webhook {
+	return &Webhook{url: url, http: &http.Client{Timeout: webhookTimeout}}
+}
+
+// WebhookFromEnv returns the webhook of ORDER_EVENTS_WEBHOOK_URL, nil when it is not set
+func WebhookFromEnv() *Webhook {
+	url := os.Getenv("ORDER_EVENTS_WEBHOOK_URL")
+	if url == "" {
+		return nil
+	}
+	return NewWebhook(url)
+}
+
+// Start posts the events of bus, one at a time in publication order
+func (w *Webhook) Start(ctx context.Context, bus *Bus) func() {
+	return consume(ctx, bus, w.post)
+}
+
+// post sends one event; failures are logged and the event is not retried
+func (w *Webhook) post(ctx context.Context, event Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to encode order event: %v\n", err)
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		fmt.Printf("⚠️  Failed to create order event request: %v\n", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.http.Do(req)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to send order event %s of %s: %v\n", event.Kind, event.OrderID, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		fmt.Printf("⚠️  Order event webhook returned %s\n", resp.Status)
+	}
+}
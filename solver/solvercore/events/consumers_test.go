@@ -0,0 +1,89 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartMetrics(t *testing.T) {
+	bus := NewBus()
+	registry := metrics.NewRegistry()
+	stop := StartMetrics(context.Background(), bus, registry)
+	defer stop()
+
+	bus.Publish(Event{Kind: OrderFilled})
+	bus.Publish(Event{Kind: OrderFilled})
+	assert.Eventually(t, func() bool {
+		return registry.Counter(eventsMetric, "kind", string(OrderFilled)).Value() == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.Zero(t, registry.Counter(eventsMetric, "kind", string(OrderRejected)).Value())
+}
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder(3)
+	assert.Empty(t, recorder.Recent(0))
+
+	for _, id := range []string{"0x1", "0x2"} {
+		recorder.record(Event{OrderID: id})
+	}
+	assert.Equal(t, []Event{{OrderID: "0x2"}, {OrderID: "0x1"}}, recorder.Recent(0))
+
+	for _, id := range []string{"0x3", "0x4"} {
+		recorder.record(Event{OrderID: id})
+	}
+	assert.Equal(t, []Event{{OrderID: "0x4"}, {OrderID: "0x3"}, {OrderID: "0x2"}}, recorder.Recent(0), "oldest overwritten")
+	assert.Equal(t, []Event{{OrderID: "0x4"}}, recorder.Recent(1))
+
+	t.Run("from the bus", func(t *testing.T) {
+		bus := NewBus()
+		recorder := NewRecorder(10)
+		stop := recorder.Start(context.Background(), bus)
+		bus.Publish(Event{Kind: OrderObserved, OrderID: "0x5"})
+		assert.Eventually(t, func() bool { return len(recorder.Recent(0)) == 1 }, time.Second, 10*time.Millisecond)
+
+		stop()
+		assert.Eventually(t, func() bool {
+			bus.mu.RLock()
+			defer bus.mu.RUnlock()
+			return len(bus.subs) == 0
+		}, time.Second, 10*time.Millisecond, "unsubscribed once stopped")
+	})
+}
+
+func TestWebhook(t *testing.T) {
+	received := make(chan Event, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var event Event
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		received <- event
+	}))
+	defer server.Close()
+
+	t.Setenv("ORDER_EVENTS_WEBHOOK_URL", "")
+	assert.Nil(t, WebhookFromEnv())
+	t.Setenv("ORDER_EVENTS_WEBHOOK_URL", server.URL)
+	webhook := WebhookFromEnv()
+	require.NotNil(t, webhook)
+
+	bus := NewBus()
+	stop := webhook.Start(context.Background(), bus)
+	defer stop()
+	bus.Publish(Event{Kind: OrderRejected, OrderID: "0x1", Reason: "insufficient_balance"})
+
+	select {
+	case event := <-received:
+		assert.Equal(t, OrderRejected, event.Kind)
+		assert.Equal(t, "insufficient_balance", event.Reason)
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not posted")
+	}
+}
@@ -0,0 +1,137 @@
+// Package events is an in-process bus of order lifecycle notifications.
+//
+// The solver pipeline publishes an Event when it first sees an order and when the order is
+// filled, settled or rejected. Side effects such as metrics, webhooks and the admin API's recent
+// events subscribe to the bus instead of being called from the pipeline, and tests can wait on
+// the events directly.
+//
+// Publish never blocks: every subscriber has a buffered channel and an event that does not fit
+// is dropped for that subscriber and counted in Dropped, so a slow consumer cannot hold up fills.
+//
+// Usage:
+//
+//	ch, unsubscribe := events.Default().Subscribe(64, events.OrderFilled, events.OrderSettled)
+//	defer unsubscribe()
+//	for event := range ch { ... }
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Kind is the lifecycle step an event reports
+type Kind string
+
+const (
+	OrderObserved Kind = "order_observed" // First seen on the origin chain
+	OrderFilled   Kind = "order_filled"   // Filled by the solver, settlement pending
+	OrderSettled  Kind = "order_settled"  // Filled and settled
+	OrderRejected Kind = "order_rejected" // Refused, Reason holds the rejection code
+)
+
+// Kinds lists every event kind
+var Kinds = []Kind{OrderObserved, OrderFilled, OrderSettled, OrderRejected}
+
+// Event is one lifecycle step of an order
+type Event struct {
+	Kind               Kind      `json:"kind"`
+	OrderID            string    `json:"orderId"`
+	OriginChainID      uint64    `json:"originChainId,omitempty"`
+	DestinationChainID uint64    `json:"destinationChainId,omitempty"`
+	Reason             string    `json:"reason,omitempty"`
+	TxHash             string    `json:"txHash,omitempty"`      // Fill of order_filled, settlement of order_settled
+	ExplorerURL        string    `json:"explorerUrl,omitempty"` // Explorer link of TxHash, when its chain has one
+	At                 time.Time `json:"at"`
+}
+
+// subscription is one subscriber's channel and the kinds it receives, all when empty
+type subscription struct {
+	ch    chan Event
+	kinds map[Kind]bool
+}
+
+// Bus fans published events out to its subscribers
+type Bus struct {
+	mu      sync.RWMutex
+	subs    map[*subscription]struct{}
+	dropped atomic.Uint64
+	now     func() time.Time
+}
+
+// NewBus returns a bus without subscribers
+func NewBus() *Bus {
+	return &Bus{subs: make(map[*subscription]struct{}), now: time.Now}
+}
+
+// Subscribe returns a channel receiving the events of kinds, every kind when none is given, and
+// the function ending the subscription, which closes the channel. buffer is the channel capacity.
+func (b *Bus) Subscribe(buffer int, kinds ...Kind) (<-chan Event, func()) {
+	sub := &subscription{ch: make(chan Event, buffer)}
+	if len(kinds) > 0 {
+		sub.kinds = make(map[Kind]bool, len(kinds))
+		for _, kind := range kinds {
+			sub.kinds[kind] = true
+		}
+	}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, sub)
+			b.mu.Unlock()
+			close(sub.ch)
+		})
+	}
+}
+
+// Publish sends event to every subscriber of its kind, stamping At when unset
+func (b *Bus) Publish(event Event) {
+	if event.At.IsZero() {
+		event.At = b.now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.subs {
+		if sub.kinds != nil && !sub.kinds[event.Kind] {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			b.dropped.Add(1)
+		}
+	}
+}
+
+// Dropped returns how many deliveries were dropped because a subscriber's channel was full
+func (b *Bus) Dropped() uint64 {
+	return b.dropped.Load()
+}
+
+var (
+	defaultBus   = NewBus()
+	defaultBusMu sync.RWMutex
+)
+
+// Default returns the process-wide bus
+func Default() *Bus {
+	defaultBusMu.RLock()
+	defer defaultBusMu.RUnlock()
+	return defaultBus
+}
+
+// SetDefault replaces the process-wide bus. Components keep the bus they were created with, so
+// call it before starting the solver.
+func SetDefault(b *Bus) {
+	defaultBusMu.Lock()
+	defer defaultBusMu.Unlock()
+	defaultBus = b
+}
@@ -0,0 +1,50 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBus(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	bus := NewBus()
+	bus.now = func() time.Time { return at }
+
+	all, unsubscribeAll := bus.Subscribe(4)
+	fills, unsubscribeFills := bus.Subscribe(1, OrderFilled, OrderSettled)
+	defer unsubscribeFills()
+
+	bus.Publish(Event{Kind: OrderObserved, OrderID: "0x1"})
+	bus.Publish(Event{Kind: OrderFilled, OrderID: "0x1"})
+	bus.Publish(Event{Kind: OrderSettled, OrderID: "0x1", At: at.Add(time.Minute)})
+
+	assert.Equal(t, Event{Kind: OrderObserved, OrderID: "0x1", At: at}, <-all)
+	assert.Equal(t, OrderFilled, (<-all).Kind)
+	assert.Equal(t, at.Add(time.Minute), (<-all).At, "At kept when set")
+	assert.Equal(t, OrderFilled, (<-fills).Kind)
+
+	t.Run("a full subscriber misses events without blocking", func(t *testing.T) {
+		assert.Equal(t, uint64(1), bus.Dropped(), "settled did not fit the fills buffer")
+		assert.Empty(t, fills)
+	})
+
+	t.Run("unsubscribe closes the channel", func(t *testing.T) {
+		unsubscribeAll()
+		unsubscribeAll()
+		_, open := <-all
+		assert.False(t, open)
+		bus.Publish(Event{Kind: OrderObserved})
+	})
+}
+
+func TestDefault(t *testing.T) {
+	original := Default()
+	defer SetDefault(original)
+
+	bus := NewBus()
+	SetDefault(bus)
+	require.Same(t, bus, Default())
+}
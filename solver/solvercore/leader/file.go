@@ -0,0 +1,87 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	defaultDirPerms  = 0755
+	defaultFilePerms = 0600
+)
+
+// FileLock is an exclusive flock on a file, released by the kernel when the holder exits. The holder
+// writes its instance ID to the file for operators.
+type FileLock struct {
+	path string
+	mu   sync.Mutex
+	file *os.File // Open while held
+}
+
+// NewFileLock locks the file at path, created when missing
+func NewFileLock(path string) *FileLock {
+	return &FileLock{path: path}
+}
+
+// Name implements Lock
+func (l *FileLock) Name() string {
+	return fmt.Sprintf("file lock %s", l.path)
+}
+
+// TryAcquire implements Lock
+func (l *FileLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		return true, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), defaultDirPerms); err != nil {
+		return false, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE, defaultFilePerms)
+	if err != nil {
+		return false, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	acquired, err := tryLockFile(file)
+	if err != nil || !acquired {
+		file.Close()
+		return false, err
+	}
+
+	// The holder's ID is informational; failing to write it doesn't lose the lock
+	if err := file.Truncate(0); err == nil {
+		_, _ = file.WriteAt([]byte(instanceID()+"\n"), 0)
+	}
+	l.file = file
+	return true, nil
+}
+
+// Check implements Lock; an flock can't be taken away from a running holder
+func (l *FileLock) Check(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return fmt.Errorf("lock not held")
+	}
+	return nil
+}
+
+// Release implements Lock
+func (l *FileLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	file := l.file
+	l.file = nil
+	if err := unlockFile(file); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to unlock %s: %w", l.path, err)
+	}
+	return file.Close()
+}
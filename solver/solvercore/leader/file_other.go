@@ -0,0 +1,18 @@
+//go:build !unix
+
+package leader
+
+import (
+	"errors"
+	"os"
+)
+
+var errFileLockUnsupported = errors.New("the file lock is only available on unix systems")
+
+func tryLockFile(file *os.File) (bool, error) {
+	return false, errFileLockUnsupported
+}
+
+func unlockFile(file *os.File) error {
+	return errFileLockUnsupported
+}
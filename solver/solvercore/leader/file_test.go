@@ -0,0 +1,42 @@
+//go:build unix
+
+package leader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFileLock tests that one instance at a time holds the file lock
+func TestFileLock(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "nested", "leader.lock")
+	first, second := NewFileLock(path), NewFileLock(path)
+
+	acquired, err := first.TryAcquire(ctx)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	require.NoError(t, first.Check(ctx))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, instanceID(), strings.TrimSpace(string(data)))
+
+	acquired, err = second.TryAcquire(ctx)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+	assert.Error(t, second.Check(ctx))
+
+	require.NoError(t, first.Release(ctx))
+	require.NoError(t, first.Release(ctx))
+	acquired, err = second.TryAcquire(ctx)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	require.NoError(t, second.Release(ctx))
+}
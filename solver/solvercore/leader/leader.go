@@ -0,0 +1,203 @@
+// Package leader elects one of several redundant solver instances to submit transactions.
+//
+// Every instance connects to the chains and serves its admin API, but only the holder of a shared
+// lock runs the solvers and listeners. The others are hot standbys retrying the lock; when the
+// leader stops or dies the lock is freed and a standby takes over, its listeners resuming from the
+// checkpoints the leader persisted. The checkpoints must therefore be shared: a state directory on a
+// shared volume for the file lock, or a database storage backend (STORAGE_BACKEND=postgres).
+//
+// Locks:
+// - file: an flock on a file, for instances on one host or sharing a volume
+// - postgres: a session advisory lock, released by Postgres when the leader's connection drops
+// - redis: a key holding the leader's ID with a lease renewed while leading
+//
+// The leader checks its lock every third of the lease. A leader that cannot confirm its lock any more,
+// or whose last successful check is two thirds of the lease old, stops its solvers and exits - a third
+// of the lease before a standby could take the lock over - so a supervisor restarts it as a standby.
+//
+// Settings:
+// - LEADER_ELECTION: none, file, postgres or redis (default none: the instance always leads)
+// - LEADER_LOCK_FILE: lock file of the file lock (default state/solver_state/leader.lock)
+// - LEADER_ELECTION_DSN: Postgres connection string (default STORAGE_DSN) or Redis URL (redis://[:password@]host:port[/db])
+// - LEADER_ELECTION_KEY: name of the lock shared by the instances (default oif-solver)
+// - LEADER_LEASE_SECONDS: lease of the Redis lock and how often the leader checks its lock (default 15)
+// - LEADER_RETRY_SECONDS: interval at which a standby retries the lock (default 5)
+package leader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+)
+
+// Lock kinds
+const (
+	LockNone     = "none"
+	LockFile     = "file"
+	LockPostgres = "postgres"
+	LockRedis    = "redis"
+)
+
+const (
+	defaultLockFile     = "state/solver_state/leader.lock"
+	defaultLockKey      = "oif-solver"
+	defaultLeaseSeconds = 15
+	defaultRetrySeconds = 5
+)
+
+// Lock is a lock held by at most one instance at a time
+type Lock interface {
+	// Name describes the lock in logs
+	Name() string
+	// TryAcquire takes the lock if it is free, reporting whether this instance now holds it
+	TryAcquire(ctx context.Context) (bool, error)
+	// Check confirms the lock is still held, renewing its lease if it has one; an error means it may be lost
+	Check(ctx context.Context) error
+	// Release frees the lock for a standby
+	Release(ctx context.Context) error
+}
+
+// Election acquires and holds a lock on behalf of the solver
+type Election struct {
+	lock          Lock
+	retryInterval time.Duration // Between acquisition attempts of a standby
+	checkInterval time.Duration // Between checks of the leader's lock
+	validity      time.Duration // How long the acquisition or a successful check keeps the leadership
+	acquiredAt    time.Time     // Start of the attempt that took the lock
+}
+
+// NewElection elects a leader through lock with the given lease: the leader checks the lock every
+// third of it and steps down when no check succeeded for two thirds of it
+func NewElection(lock Lock, retryInterval, lease time.Duration) *Election {
+	return &Election{lock: lock, retryInterval: retryInterval, checkInterval: lease / 3, validity: lease - lease/3}
+}
+
+// FromEnv returns the election configured by LEADER_ELECTION, nil when disabled
+func FromEnv() (*Election, error) {
+	kind := envutil.GetEnvWithDefault("LEADER_ELECTION", LockNone)
+	key := envutil.GetEnvWithDefault("LEADER_ELECTION_KEY", defaultLockKey)
+	lease := time.Duration(envutil.GetEnvInt("LEADER_LEASE_SECONDS", defaultLeaseSeconds)) * time.Second
+	retry := time.Duration(envutil.GetEnvInt("LEADER_RETRY_SECONDS", defaultRetrySeconds)) * time.Second
+	if lease <= 0 || retry <= 0 {
+		return nil, fmt.Errorf("LEADER_LEASE_SECONDS and LEADER_RETRY_SECONDS must be positive")
+	}
+
+	var lock Lock
+	switch kind {
+	case LockNone, "":
+		return nil, nil
+	case LockFile:
+		lock = NewFileLock(envutil.GetEnvWithDefault("LEADER_LOCK_FILE", defaultLockFile))
+	case LockPostgres:
+		dsn := envutil.GetEnvWithDefault("LEADER_ELECTION_DSN", os.Getenv("STORAGE_DSN"))
+		if dsn == "" {
+			return nil, fmt.Errorf("LEADER_ELECTION_DSN (or STORAGE_DSN) is required for the postgres lock")
+		}
+		pgLock, err := OpenPostgresLock(dsn, key)
+		if err != nil {
+			return nil, err
+		}
+		lock = pgLock
+	case LockRedis:
+		url := envutil.GetEnvWithDefault("LEADER_ELECTION_DSN", "")
+		if url == "" {
+			return nil, fmt.Errorf("LEADER_ELECTION_DSN is required for the redis lock")
+		}
+		redisLock, err := NewRedisLock(url, key, instanceID(), lease)
+		if err != nil {
+			return nil, err
+		}
+		lock = redisLock
+	default:
+		return nil, fmt.Errorf("unknown LEADER_ELECTION %q (use none, file, postgres or redis)", kind)
+	}
+	return NewElection(lock, retry, lease), nil
+}
+
+// Name describes the election lock
+func (e *Election) Name() string {
+	return e.lock.Name()
+}
+
+// WaitForLeadership blocks until this instance holds the lock or ctx is done
+func (e *Election) WaitForLeadership(ctx context.Context) error {
+	fmt.Printf("🧍 Standing by for leadership (%s)...\n", e.lock.Name())
+	for {
+		start := time.Now()
+		acquired, err := e.lock.TryAcquire(ctx)
+		if err != nil {
+			fmt.Printf("⚠️  Leader election attempt failed: %v\n", err)
+		}
+		if acquired {
+			e.acquiredAt = start
+			fmt.Printf("👑 Elected leader (%s)\n", e.lock.Name())
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(e.retryInterval):
+		}
+	}
+}
+
+// Lead checks the lock until ctx is done, returning a context cancelled as soon as a check fails or
+// the leadership outlived its last successful check by the validity
+func (e *Election) Lead(ctx context.Context) context.Context {
+	leaderCtx, cancel := context.WithCancel(ctx)
+	validUntil := e.acquiredAt.Add(e.validity)
+	if e.acquiredAt.IsZero() {
+		validUntil = time.Now().Add(e.validity)
+	}
+	go func() {
+		defer cancel()
+		ticker := time.NewTicker(e.checkInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-leaderCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			// A check still running when the leadership runs out is a failed one
+			start := time.Now()
+			checkCtx, cancelCheck := context.WithDeadline(leaderCtx, validUntil)
+			err := e.lock.Check(checkCtx)
+			cancelCheck()
+			if err == nil && time.Now().After(validUntil) {
+				err = fmt.Errorf("check took longer than the leadership lasts")
+			}
+			if err != nil {
+				if leaderCtx.Err() == nil {
+					fmt.Printf("🚨 Lost leadership (%s): %v\n", e.lock.Name(), err)
+				}
+				return
+			}
+			validUntil = start.Add(e.validity)
+		}
+	}()
+	return leaderCtx
+}
+
+// Resign releases the lock so a standby can take over
+func (e *Election) Resign(ctx context.Context) {
+	if err := e.lock.Release(ctx); err != nil {
+		fmt.Printf("⚠️  Failed to release leadership (%s): %v\n", e.lock.Name(), err)
+		return
+	}
+	fmt.Printf("👋 Released leadership (%s)\n", e.lock.Name())
+}
+
+// instanceID identifies this process in locks held by it
+func instanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
@@ -0,0 +1,187 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLock is a lock whose availability and health tests control
+type fakeLock struct {
+	mu       sync.Mutex
+	free     bool
+	held     bool
+	checkErr error
+	hang     bool      // Checks block until their context is done
+	deadline time.Time // Deadline of the last check
+	released bool
+}
+
+func (l *fakeLock) Name() string { return "fake lock" }
+
+func (l *fakeLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.free {
+		l.held = true
+	}
+	return l.held, nil
+}
+
+func (l *fakeLock) Check(ctx context.Context) error {
+	l.mu.Lock()
+	l.deadline, _ = ctx.Deadline()
+	hang, err := l.hang, l.checkErr
+	l.mu.Unlock()
+	if hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return err
+}
+
+func (l *fakeLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.held, l.released = false, true
+	return nil
+}
+
+func (l *fakeLock) set(f func(l *fakeLock)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f(l)
+}
+
+// TestElection tests standing by, leading and losing leadership
+func TestElection(t *testing.T) {
+	t.Run("standby takes over once the lock is free", func(t *testing.T) {
+		lock := &fakeLock{}
+		election := NewElection(lock, 5*time.Millisecond, time.Hour)
+
+		elected := make(chan error, 1)
+		go func() { elected <- election.WaitForLeadership(context.Background()) }()
+		select {
+		case <-elected:
+			t.Fatal("elected while the lock is taken")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		lock.set(func(l *fakeLock) { l.free = true })
+		select {
+		case err := <-elected:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("not elected after the lock was freed")
+		}
+	})
+
+	t.Run("standing by ends with the context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := NewElection(&fakeLock{}, time.Hour, time.Hour).WaitForLeadership(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("leader context is cancelled when the lock is lost", func(t *testing.T) {
+		lock := &fakeLock{free: true}
+		election := NewElection(lock, time.Hour, 300*time.Millisecond)
+		require.NoError(t, election.WaitForLeadership(context.Background()))
+
+		leaderCtx := election.Lead(context.Background())
+		select {
+		case <-leaderCtx.Done():
+			t.Fatal("leadership lost while the lock is healthy")
+		case <-time.After(350 * time.Millisecond):
+		}
+
+		lock.set(func(l *fakeLock) { l.checkErr = errors.New("connection lost") })
+		select {
+		case <-leaderCtx.Done():
+		case <-time.After(time.Second):
+			t.Fatal("leadership kept after the lock was lost")
+		}
+	})
+
+	t.Run("leader steps down before the lease ends when a check hangs", func(t *testing.T) {
+		const lease = 300 * time.Millisecond
+		lock := &fakeLock{free: true, hang: true}
+		election := NewElection(lock, time.Hour, lease)
+		require.NoError(t, election.WaitForLeadership(context.Background()))
+
+		leaderCtx := election.Lead(context.Background())
+		select {
+		case <-leaderCtx.Done():
+		case <-time.After(time.Second):
+			t.Fatal("leadership kept while the check hangs")
+		}
+		lock.set(func(l *fakeLock) {
+			assert.Equal(t, election.acquiredAt.Add(lease-lease/3), l.deadline)
+		})
+	})
+
+	t.Run("resigning releases the lock", func(t *testing.T) {
+		lock := &fakeLock{free: true}
+		election := NewElection(lock, time.Hour, time.Hour)
+		require.NoError(t, election.WaitForLeadership(context.Background()))
+		election.Resign(context.Background())
+		assert.True(t, lock.released)
+	})
+}
+
+// TestFromEnv tests configuring the election from the environment
+func TestFromEnv(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Setenv("LEADER_ELECTION", "")
+		election, err := FromEnv()
+		require.NoError(t, err)
+		assert.Nil(t, election)
+	})
+
+	t.Run("file lock", func(t *testing.T) {
+		t.Setenv("LEADER_ELECTION", LockFile)
+		t.Setenv("LEADER_LOCK_FILE", "/tmp/solver-leader.lock")
+		election, err := FromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, "file lock /tmp/solver-leader.lock", election.Name())
+	})
+
+	t.Run("redis lock", func(t *testing.T) {
+		t.Setenv("LEADER_ELECTION", LockRedis)
+		t.Setenv("LEADER_ELECTION_DSN", "redis://:secret@cache:6380/2")
+		t.Setenv("LEADER_LEASE_SECONDS", "30")
+		election, err := FromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, 10*time.Second, election.checkInterval)
+		assert.Equal(t, 20*time.Second, election.validity)
+		lock, ok := election.lock.(*RedisLock)
+		require.True(t, ok)
+		options := lock.client.Options()
+		assert.Equal(t, "cache:6380", options.Addr)
+		assert.Equal(t, "secret", options.Password)
+		assert.Equal(t, 2, options.DB)
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		for name, env := range map[string]map[string]string{
+			"unknown lock":         {"LEADER_ELECTION": "etcd"},
+			"redis without URL":    {"LEADER_ELECTION": LockRedis, "LEADER_ELECTION_DSN": ""},
+			"invalid redis URL":    {"LEADER_ELECTION": LockRedis, "LEADER_ELECTION_DSN": "cache:6379"},
+			"postgres without DSN": {"LEADER_ELECTION": LockPostgres, "LEADER_ELECTION_DSN": "", "STORAGE_DSN": ""},
+			"zero lease":           {"LEADER_ELECTION": LockFile, "LEADER_LEASE_SECONDS": "0"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				for key, value := range env {
+					t.Setenv(key, value)
+				}
+				_, err := FromEnv()
+				assert.Error(t, err)
+			})
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
+)
+
+// PostgresLock is a session advisory lock. It lives on one dedicated connection, so Postgres
+// releases it as soon as that connection drops, e.g. when the leader dies.
+type PostgresLock struct {
+	db  *sql.DB
+	key string
+	id  int64 // Advisory lock ID derived from key
+
+	mu   sync.Mutex
+	conn *sql.Conn // Open while held
+}
+
+// OpenPostgresLock connects to the database at dsn with the Postgres driver of the storage backend
+func OpenPostgresLock(dsn, key string) (*PostgresLock, error) {
+	driver := storage.SQLDriver(storage.BackendPostgres)
+	if driver == "" {
+		return nil, fmt.Errorf("no database/sql driver registered for the postgres lock")
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open leader election database: %w", err)
+	}
+	return NewPostgresLock(db, key), nil
+}
+
+// NewPostgresLock locks key in db
+func NewPostgresLock(db *sql.DB, key string) *PostgresLock {
+	return &PostgresLock{db: db, key: key, id: advisoryLockID(key)}
+}
+
+// Name implements Lock
+func (l *PostgresLock) Name() string {
+	return fmt.Sprintf("postgres advisory lock %q", l.key)
+}
+
+// TryAcquire implements Lock
+func (l *PostgresLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn != nil {
+		return true, nil
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to leader election database: %w", err)
+	}
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, l.id).Scan(&acquired); err != nil {
+		conn.Close()
+		return false, fmt.Errorf("failed to take advisory lock: %w", err)
+	}
+	if !acquired {
+		conn.Close()
+		return false, nil
+	}
+	l.conn = conn
+	return true, nil
+}
+
+// Check implements Lock; the lock is held as long as its connection answers
+func (l *PostgresLock) Check(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return fmt.Errorf("lock not held")
+	}
+	if err := l.conn.PingContext(ctx); err != nil {
+		return fmt.Errorf("leader election connection lost: %w", err)
+	}
+	return nil
+}
+
+// Release implements Lock
+func (l *PostgresLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return nil
+	}
+	conn := l.conn
+	l.conn = nil
+	_, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, l.id)
+	// Closing the connection ends the session, which frees the lock even when the unlock failed
+	conn.Close()
+	if err != nil {
+		return fmt.Errorf("failed to release advisory lock: %w", err)
+	}
+	return nil
+}
+
+// advisoryLockID maps a lock name to the bigint key of a Postgres advisory lock
+func advisoryLockID(key string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return int64(h.Sum64())
+}
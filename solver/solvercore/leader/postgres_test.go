@@ -0,0 +1,25 @@
+package leader
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPostgresLock tests the parts of the Postgres lock that need no database
+func TestPostgresLock(t *testing.T) {
+	t.Run("lock IDs are stable per key", func(t *testing.T) {
+		assert.Equal(t, advisoryLockID("oif-solver"), advisoryLockID("oif-solver"))
+		assert.NotEqual(t, advisoryLockID("oif-solver"), advisoryLockID("oif-solver-staging"))
+	})
+
+	t.Run("opens with the pgx driver", func(t *testing.T) {
+		lock, err := OpenPostgresLock("postgres://localhost/solver", "oif-solver")
+		require.NoError(t, err)
+		defer lock.db.Close()
+		assert.Equal(t, `postgres advisory lock "oif-solver"`, lock.Name())
+		assert.Equal(t, "pgx", storage.SQLDriver(storage.BackendPostgres))
+	})
+}
@@ -0,0 +1,94 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisDialTimeout = 5 * time.Second
+
+// Scripts changing the lock only while it holds this instance's ID
+var (
+	redisRenewScript   = redis.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("PEXPIRE", KEYS[1], ARGV[2]) else return 0 end`)
+	redisReleaseScript = redis.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`)
+)
+
+// RedisLock is a key holding the leader's ID with a lease. The leader renews the lease on every check
+// and takes the lock as lost as soon as a renewal fails, since it can't tell how long the key outlives it.
+type RedisLock struct {
+	client *redis.Client
+	key    string
+	id     string
+	lease  time.Duration
+
+	mu   sync.Mutex
+	held bool
+}
+
+// NewRedisLock locks key on the Redis server at rawURL (redis://[:password@]host:port[/db]) as id
+func NewRedisLock(rawURL, key, id string, lease time.Duration) (*RedisLock, error) {
+	options, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL %q (use redis://[:password@]host:port[/db]): %w", rawURL, err)
+	}
+	options.DialTimeout = redisDialTimeout
+	return &RedisLock{client: redis.NewClient(options), key: key, id: id, lease: lease}, nil
+}
+
+// Name implements Lock
+func (l *RedisLock) Name() string {
+	return fmt.Sprintf("redis lock %q at %s", l.key, l.client.Options().Addr)
+}
+
+// TryAcquire implements Lock
+func (l *RedisLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held {
+		return true, nil
+	}
+
+	acquired, err := l.client.SetNX(ctx, l.key, l.id, l.lease).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to take redis lock: %w", err)
+	}
+	l.held = acquired
+	return acquired, nil
+}
+
+// Check implements Lock; it renews the lease
+func (l *RedisLock) Check(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.held {
+		return fmt.Errorf("lock not held")
+	}
+
+	renewed, err := redisRenewScript.Run(ctx, l.client, []string{l.key}, l.id, l.lease.Milliseconds()).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to renew the leader lease: %w", err)
+	}
+	if renewed != 1 {
+		l.held = false
+		return fmt.Errorf("lock expired or taken over by another instance")
+	}
+	return nil
+}
+
+// Release implements Lock
+func (l *RedisLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.held {
+		return nil
+	}
+	l.held = false
+	if err := redisReleaseScript.Run(ctx, l.client, []string{l.key}, l.id).Err(); err != nil {
+		return fmt.Errorf("failed to delete lock (it expires with its lease): %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package leader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRedisLock tests acquiring, renewing and releasing the Redis lock on an in-process Redis server
+func TestRedisLock(t *testing.T) {
+	ctx := context.Background()
+	const lease = 15 * time.Second
+
+	newLock := func(t *testing.T, url, id string) *RedisLock {
+		lock, err := NewRedisLock(url, "oif-solver", id, lease)
+		require.NoError(t, err)
+		return lock
+	}
+
+	t.Run("one holder at a time", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		server.RequireAuth("secret")
+		url := "redis://:secret@" + server.Addr() + "/1"
+		first, second := newLock(t, url, "first"), newLock(t, url, "second")
+
+		acquired, err := first.TryAcquire(ctx)
+		require.NoError(t, err)
+		require.True(t, acquired)
+		acquired, err = second.TryAcquire(ctx)
+		require.NoError(t, err)
+		assert.False(t, acquired)
+
+		server.Select(1)
+		server.CheckGet(t, "oif-solver", "first")
+		assert.Equal(t, lease, server.TTL("oif-solver"))
+
+		require.NoError(t, first.Check(ctx))
+		require.NoError(t, first.Release(ctx))
+		acquired, err = second.TryAcquire(ctx)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+	})
+
+	t.Run("checks renew the lease", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		lock := newLock(t, "redis://"+server.Addr(), "first")
+		acquired, err := lock.TryAcquire(ctx)
+		require.NoError(t, err)
+		require.True(t, acquired)
+
+		server.FastForward(10 * time.Second)
+		require.NoError(t, lock.Check(ctx))
+		assert.Equal(t, lease, server.TTL("oif-solver"))
+	})
+
+	t.Run("lock lost once the lease expired", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		lock := newLock(t, "redis://"+server.Addr(), "first")
+		acquired, err := lock.TryAcquire(ctx)
+		require.NoError(t, err)
+		require.True(t, acquired)
+
+		server.FastForward(lease)
+		require.NoError(t, server.Set("oif-solver", "second"))
+		assert.ErrorContains(t, lock.Check(ctx), "taken over")
+		assert.Error(t, lock.Check(ctx))
+		require.NoError(t, lock.Release(ctx))
+		server.CheckGet(t, "oif-solver", "second")
+	})
+
+	t.Run("a failed renewal loses the lock right away", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		lock := newLock(t, "redis://"+server.Addr(), "first")
+		acquired, err := lock.TryAcquire(ctx)
+		require.NoError(t, err)
+		require.True(t, acquired)
+
+		server.Close()
+		assert.ErrorContains(t, lock.Check(ctx), "failed to renew the leader lease")
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		server.RequireAuth("secret")
+		_, err := newLock(t, "redis://:wrong@"+server.Addr(), "first").TryAcquire(ctx)
+		assert.ErrorContains(t, err, "WRONGPASS")
+	})
+
+	t.Run("default port", func(t *testing.T) {
+		assert.Equal(t, `redis lock "oif-solver" at cache:6379`, newLock(t, "redis://cache", "first").Name())
+	})
+}
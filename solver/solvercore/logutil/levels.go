@@ -0,0 +1,159 @@
+package logutil
+
+// Per-component log levels. Each component logs through its own Logger (For), and a line is
+// printed when its level is at or above the level set for the component in the runtime config:
+//
+//	"logLevels": {"default": "info", "listener": "warn", "rules": "debug"}
+//
+// A component without an entry uses "default", and without that info. The levels are read on every
+// line, so a SIGHUP or POST /config/reload changes them while the solver runs.
+//
+// Printf keeps the emoji-marked lines of the solver as they are and infers their level: a line
+// with ❌ is an error, one with ⚠️ a warning and anything else info.
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// Level is the severity of a log line
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelError {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel parses a level name: debug, info, warn or error
+func ParseLevel(name string) (Level, error) {
+	i := slices.Index(levelNames, strings.ToLower(name))
+	if i < 0 {
+		return LevelInfo, fmt.Errorf("unknown log level %q, want one of %s", name, strings.Join(levelNames, ", "))
+	}
+	return Level(i), nil
+}
+
+// Components with their own log level; ComponentDefault sets the level of every other one
+const (
+	ComponentDefault  = "default"
+	ComponentListener = "listener"
+	ComponentFiller   = "filler"
+	ComponentRules    = "rules"
+)
+
+var components = []string{ComponentDefault, ComponentListener, ComponentFiller, ComponentRules}
+
+// ValidateLogLevels rejects runtime configs with unknown components or levels in logLevels
+func ValidateLogLevels(runtime *config.RuntimeConfig) error {
+	var errs []error
+	for component, name := range runtime.LogLevels {
+		if !slices.Contains(components, component) {
+			errs = append(errs, fmt.Errorf("logLevels: unknown component %q, want one of %s", component, strings.Join(components, ", ")))
+		}
+		if _, err := ParseLevel(name); err != nil {
+			errs = append(errs, fmt.Errorf("logLevels.%s: %w", component, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ComponentLevel returns the active log level of component
+func ComponentLevel(component string) Level {
+	levels := config.ActiveRuntime().LogLevels
+	for _, key := range []string{component, ComponentDefault} {
+		if name, ok := levels[key]; ok {
+			if level, err := ParseLevel(name); err == nil {
+				return level
+			}
+		}
+	}
+	return LevelInfo
+}
+
+// LineLevel infers the level of an emoji-marked log line
+func LineLevel(line string) Level {
+	switch {
+	case strings.Contains(line, "❌"):
+		return LevelError
+	case strings.Contains(line, "⚠️"):
+		return LevelWarn
+	default:
+		return LevelInfo
+	}
+}
+
+// Logger prints the lines of one component that pass its log level
+type Logger struct {
+	component string
+}
+
+// For returns the logger of component
+func For(component string) *Logger {
+	return &Logger{component: component}
+}
+
+// Enabled reports whether lines of level are printed
+func (l *Logger) Enabled(level Level) bool {
+	return level >= ComponentLevel(l.component)
+}
+
+func (l *Logger) logf(level Level, format string, args ...interface{}) {
+	if l.Enabled(level) {
+		fmt.Printf(format, args...)
+	}
+}
+
+// Printf prints a line at the level LineLevel infers from format
+func (l *Logger) Printf(format string, args ...interface{}) {
+	l.logf(LineLevel(format), format, args...)
+}
+
+// Debugf prints a debug line
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.logf(LevelDebug, format, args...)
+}
+
+// Infof prints an info line
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.logf(LevelInfo, format, args...)
+}
+
+// Warnf prints a warning
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.logf(LevelWarn, format, args...)
+}
+
+// Errorf prints an error
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.logf(LevelError, format, args...)
+}
+
+// WithNetworkTagf prints a line prefixed with the network tag, like LogWithNetworkTagf
+func (l *Logger) WithNetworkTagf(networkName, format string, args ...interface{}) {
+	l.Printf(Prefix(networkName)+format, args...)
+}
+
+// CrossChainOperation prints a cross-chain operation with origin → destination format
+func (l *Logger) CrossChainOperation(operation string, originChainID, destChainID uint64, orderID string) {
+	if !l.Enabled(LineLevel(operation)) {
+		return
+	}
+	originClean := strings.TrimSpace(GetNetworkTagByChainID(originChainID))
+	destClean := strings.TrimSpace(GetNetworkTagByChainID(destChainID))
+	fmt.Printf("%s → %s 🔄 %s (Order: %s)\n", originClean, destClean, operation, orderID[:8]+"...")
+}
@@ -0,0 +1,98 @@
+package logutil
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setLogLevels makes levels the active logLevels for the rest of the test
+func setLogLevels(t *testing.T, levels map[string]string) {
+	t.Helper()
+	previous := config.ActiveRuntime()
+	require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{LogLevels: levels}, ValidateLogLevels))
+	t.Cleanup(func() { require.NoError(t, config.ApplyRuntime(previous)) })
+}
+
+func TestParseLevel(t *testing.T) {
+	for _, name := range []string{"debug", "info", "warn", "error"} {
+		level, err := ParseLevel(name)
+		require.NoError(t, err)
+		assert.Equal(t, name, level.String())
+	}
+	level, err := ParseLevel("WARN")
+	require.NoError(t, err)
+	assert.Equal(t, LevelWarn, level)
+
+	_, err = ParseLevel("verbose")
+	assert.ErrorContains(t, err, `unknown log level "verbose"`)
+}
+
+func TestValidateLogLevels(t *testing.T) {
+	assert.NoError(t, ValidateLogLevels(&config.RuntimeConfig{}))
+	assert.NoError(t, ValidateLogLevels(&config.RuntimeConfig{LogLevels: map[string]string{"default": "warn", "rules": "debug"}}))
+
+	err := ValidateLogLevels(&config.RuntimeConfig{LogLevels: map[string]string{"settler": "info", "listener": "loud"}})
+	assert.ErrorContains(t, err, `logLevels: unknown component "settler"`)
+	assert.ErrorContains(t, err, `logLevels.listener: unknown log level "loud"`)
+}
+
+func TestLineLevel(t *testing.T) {
+	assert.Equal(t, LevelError, LineLevel("❌ Failed to parse Open event: boom\n"))
+	assert.Equal(t, LevelWarn, LineLevel("[BASE] ⚠️  Failed to persist checkpoint\n"))
+	assert.Equal(t, LevelInfo, LineLevel("[BASE] 📦 Processed blocks 1-2\n"))
+}
+
+func TestComponentLevels(t *testing.T) {
+	t.Run("info without logLevels", func(t *testing.T) {
+		setLogLevels(t, nil)
+		assert.Equal(t, LevelInfo, ComponentLevel(ComponentListener))
+
+		output := captureOutput(func() {
+			For(ComponentListener).Debugf("debug\n")
+			For(ComponentListener).Printf("📦 info\n")
+		})
+		assert.Equal(t, "📦 info\n", output)
+	})
+
+	t.Run("components fall back to the default level", func(t *testing.T) {
+		setLogLevels(t, map[string]string{"default": "warn", "rules": "debug"})
+		assert.Equal(t, LevelWarn, ComponentLevel(ComponentFiller))
+		assert.Equal(t, LevelDebug, ComponentLevel(ComponentRules))
+
+		output := captureOutput(func() {
+			filler := For(ComponentFiller)
+			filler.Printf("🔄 filling\n")
+			filler.Printf("⚠️  slow fill\n")
+			filler.Errorf("fill failed\n")
+			For(ComponentRules).Debugf("rule detail\n")
+		})
+		assert.Equal(t, "⚠️  slow fill\nfill failed\nrule detail\n", output)
+	})
+
+	t.Run("levels follow a config reload", func(t *testing.T) {
+		setLogLevels(t, map[string]string{"listener": "error"})
+		listener := For(ComponentListener)
+		assert.Empty(t, captureOutput(func() { listener.WithNetworkTagf("Base", "📦 Processed blocks 1-2\n") }))
+
+		setLogLevels(t, map[string]string{"listener": "info"})
+		assert.Contains(t, captureOutput(func() { listener.WithNetworkTagf("Base", "📦 Processed blocks 1-2\n") }), "[BASE]")
+	})
+
+	t.Run("helpers log for their component", func(t *testing.T) {
+		setLogLevels(t, map[string]string{"listener": "warn"})
+		output := captureOutput(func() {
+			LogBlockProcessing("Base", 1, 2, 3)
+			CrossChainOperation("Fill Order", 84532, 11155111, "0x1234567890abcdef")
+		})
+		assert.NotContains(t, output, "Processed blocks")
+		assert.Contains(t, output, "Fill Order")
+
+		setLogLevels(t, map[string]string{"rules": "warn"})
+		assert.Empty(t, captureOutput(func() {
+			For(ComponentRules).CrossChainOperation("Rule 'BalanceCheck' passed", 84532, 11155111, "0x1234567890abcdef")
+		}))
+	})
+}
@@ -132,3 +132,11 @@ func NetworkNameByChainID(chainID uint64) string {
 	}
 	return fmt.Sprintf("chain-%d", chainID)
 }
+
+// TxRef returns txHash followed by its explorer link, or txHash alone when the chain has no explorer
+func TxRef(chainID uint64, txHash string) string {
+	if url := config.ExplorerTxURL(chainID, txHash); url != "" {
+		return fmt.Sprintf("%s (%s)", txHash, url)
+	}
+	return txHash
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -62,6 +63,16 @@ func TestNetworkNameByChainID(t *testing.T) {
 	})
 }
 
+func TestTxRef(t *testing.T) {
+	t.Setenv("IS_DEVNET", "false")
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+
+	assert.Equal(t, "0xabc (https://sepolia.basescan.org/tx/0xabc)", TxRef(config.BaseSepoliaChainID, "0xabc"))
+	assert.Equal(t, "0xabc", TxRef(999999, "0xabc"), "no explorer")
+}
+
 func TestNetworkTagFormatting(t *testing.T) {
 	t.Run("Network tag consistency", func(t *testing.T) {
 		// Test that network tags are consistently formatted
@@ -197,6 +208,34 @@ func TestLogOrderProcessing(t *testing.T) {
 	})
 }
 
+func TestLogOrderAmounts(t *testing.T) {
+	require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+		{ChainID: 84532, Address: "0x00000000000000000000000000000000000000a1", Symbol: "DOG", Decimals: 6},
+	}}))
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+
+	args := types.ParsedArgs{
+		OrderID: "0x1234567890abcdef1234567890abcdef12345678",
+		ResolvedOrder: types.ResolvedCrossChainOrder{
+			OriginChainID: big.NewInt(11155111),
+			MaxSpent: []types.Output{
+				{Token: "0x00000000000000000000000000000000000000000000000000000000000000a1", Amount: big.NewInt(12_500_000), ChainID: big.NewInt(84532)},
+			},
+			MinReceived: []types.Output{
+				{Token: "0x00000000000000000000000000000000000000b1", Amount: big.NewInt(42)},
+			},
+			FillInstructions: []types.FillInstruction{{DestinationChainID: big.NewInt(84532)}},
+		},
+	}
+
+	output := captureOutput(func() {
+		LogOrderAmounts(&args)
+	})
+
+	assert.Contains(t, output, "Spend 12.5 DOG")
+	assert.Contains(t, output, "receive 42 (base units)")
+}
+
 func TestLogFillOperation(t *testing.T) {
 	t.Run("LogFillOperation success", func(t *testing.T) {
 		output := captureOutput(func() {
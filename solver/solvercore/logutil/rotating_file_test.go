@@ -0,0 +1,107 @@
+package logutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	// openTestFile opens a rotating file whose clock is *now
+	openTestFile := func(t *testing.T, maxSize int64, maxAge time.Duration, maxBackups int, now *time.Time) (*RotatingFile, string) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "logs", "solver.log")
+		f, err := OpenRotatingFile(path, maxSize, maxAge, maxBackups)
+		require.NoError(t, err)
+		f.now = func() time.Time { return *now }
+		f.opened = *now
+		t.Cleanup(func() { _ = f.Close() })
+		return f, path
+	}
+	read := func(t *testing.T, path string) string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("rotates by size", func(t *testing.T) {
+		now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		f, path := openTestFile(t, 10, 0, 0, &now)
+
+		_, err := f.Write([]byte("line one\n"))
+		require.NoError(t, err)
+		now = now.Add(time.Second)
+		_, err = f.Write([]byte("line two\n"))
+		require.NoError(t, err)
+
+		backups, err := f.Backups()
+		require.NoError(t, err)
+		require.Equal(t, []string{path + ".20260301T120001.000"}, backups)
+		assert.Equal(t, "line one\n", read(t, backups[0]))
+		assert.Equal(t, "line two\n", read(t, path))
+	})
+
+	t.Run("a line larger than the limit is still written", func(t *testing.T) {
+		now := time.Now()
+		f, path := openTestFile(t, 4, 0, 0, &now)
+		_, err := f.Write([]byte("a long line\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "a long line\n", read(t, path))
+	})
+
+	t.Run("rotates by age", func(t *testing.T) {
+		now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		f, path := openTestFile(t, 0, time.Hour, 0, &now)
+
+		_, err := f.Write([]byte("before\n"))
+		require.NoError(t, err)
+		now = now.Add(59 * time.Minute)
+		_, err = f.Write([]byte("same file\n"))
+		require.NoError(t, err)
+		now = now.Add(time.Minute)
+		_, err = f.Write([]byte("after\n"))
+		require.NoError(t, err)
+
+		backups, err := f.Backups()
+		require.NoError(t, err)
+		require.Len(t, backups, 1)
+		assert.Equal(t, "before\nsame file\n", read(t, backups[0]))
+		assert.Equal(t, "after\n", read(t, path))
+	})
+
+	t.Run("keeps maxBackups rotated files", func(t *testing.T) {
+		now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		f, path := openTestFile(t, 1, 0, 2, &now)
+		for _, line := range []string{"1\n", "2\n", "3\n", "4\n"} {
+			_, err := f.Write([]byte(line))
+			require.NoError(t, err)
+			now = now.Add(time.Second)
+		}
+
+		backups, err := f.Backups()
+		require.NoError(t, err)
+		require.Len(t, backups, 2)
+		assert.Equal(t, "2\n", read(t, backups[0]))
+		assert.Equal(t, "3\n", read(t, backups[1]))
+		assert.Equal(t, "4\n", read(t, path))
+	})
+
+	t.Run("appends to an existing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "solver.log")
+		require.NoError(t, os.WriteFile(path, []byte("old\n"), 0o644))
+		f, err := OpenRotatingFile(path, 0, 0, 0)
+		require.NoError(t, err)
+		_, err = f.Write([]byte("new\n"))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		assert.Equal(t, "old\nnew\n", read(t, path))
+
+		_, err = f.Write([]byte("closed\n"))
+		assert.ErrorIs(t, err, os.ErrClosed)
+	})
+}
@@ -0,0 +1,170 @@
+package logutil
+
+// Log sinks. The solver prints its log lines to stdout; with LOG_SINKS set to more than stdout,
+// StartSinksFromEnv takes over os.Stdout and copies every line to each sink:
+// - stdout: the process's original stdout, with colors
+// - file: a file rotated by size and age, without colors
+// - syslog: the local syslog daemon or a remote one, without colors and with the severity
+//   LineLevel infers (unix only)
+//
+// Settings:
+// - LOG_SINKS: comma-separated sinks (default stdout), e.g. "stdout,file,syslog"
+// - LOG_FILE: path of the log file (default state/solver_state/solver.log)
+// - LOG_FILE_MAX_SIZE_MB: rotate the file once it reaches this size (default 100, 0 disables)
+// - LOG_FILE_MAX_AGE_HOURS: rotate the file once it is this old (default 24, 0 disables)
+// - LOG_FILE_MAX_BACKUPS: rotated files to keep (default 7, 0 keeps all)
+// - LOG_SYSLOG_ADDR: remote syslog as network://host:port, e.g. udp://logs:514 (default local daemon)
+// - LOG_SYSLOG_TAG: syslog tag of the lines (default oif-solver)
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+)
+
+const (
+	SinkStdout = "stdout"
+	SinkFile   = "file"
+	SinkSyslog = "syslog"
+
+	defaultLogFile = "state/solver_state/solver.log"
+)
+
+// colorCodes matches the ANSI color escapes of the network tags
+var colorCodes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// Sinks copies log lines to several writers
+type Sinks struct {
+	writers []io.Writer
+	closers []io.Closer
+}
+
+// SinksFromEnv opens the sinks listed in LOG_SINKS
+func SinksFromEnv() (*Sinks, error) {
+	sinks := &Sinks{}
+	for _, name := range strings.Split(envutil.GetEnvWithDefault("LOG_SINKS", SinkStdout), ",") {
+		switch strings.TrimSpace(name) {
+		case SinkStdout:
+			sinks.writers = append(sinks.writers, os.Stdout)
+		case SinkFile:
+			file, err := OpenRotatingFile(
+				envutil.GetEnvWithDefault("LOG_FILE", defaultLogFile),
+				int64(envutil.GetEnvInt("LOG_FILE_MAX_SIZE_MB", 100))<<20,
+				time.Duration(envutil.GetEnvInt("LOG_FILE_MAX_AGE_HOURS", 24))*time.Hour,
+				envutil.GetEnvInt("LOG_FILE_MAX_BACKUPS", 7),
+			)
+			if err != nil {
+				_ = sinks.Close()
+				return nil, err
+			}
+			sinks.Add(plainWriter{file}, file)
+		case SinkSyslog:
+			writer, err := openSyslog(os.Getenv("LOG_SYSLOG_ADDR"), envutil.GetEnvWithDefault("LOG_SYSLOG_TAG", "oif-solver"))
+			if err != nil {
+				_ = sinks.Close()
+				return nil, fmt.Errorf("failed to open syslog: %w", err)
+			}
+			sinks.Add(writer, writer)
+		case "":
+		default:
+			_ = sinks.Close()
+			return nil, fmt.Errorf("unknown log sink %q in LOG_SINKS, want %s, %s or %s", name, SinkStdout, SinkFile, SinkSyslog)
+		}
+	}
+	return sinks, nil
+}
+
+// Add adds a sink; closer, if not nil, is closed with the sinks
+func (s *Sinks) Add(writer io.Writer, closer io.Closer) {
+	s.writers = append(s.writers, writer)
+	if closer != nil {
+		s.closers = append(s.closers, closer)
+	}
+}
+
+// writeLine writes line to every sink; a failing sink does not hold up the others
+func (s *Sinks) writeLine(line []byte) {
+	for _, w := range s.writers {
+		_, _ = w.Write(line)
+	}
+}
+
+// Close closes the sinks that were opened for them
+func (s *Sinks) Close() error {
+	var errs []error
+	for _, c := range s.closers {
+		errs = append(errs, c.Close())
+	}
+	s.closers = nil
+	return errors.Join(errs...)
+}
+
+// RedirectStdout replaces os.Stdout with a pipe whose lines are copied to sinks. The returned stop
+// func restores os.Stdout, copies the lines still buffered and closes the sinks.
+func RedirectStdout(sinks *Sinks) (stop func(), err error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log pipe: %w", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		reader := bufio.NewReader(r)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				sinks.writeLine(line)
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return func() {
+		os.Stdout = stdout
+		_ = w.Close()
+		<-done
+		_ = r.Close()
+		_ = sinks.Close()
+	}, nil
+}
+
+// StartSinksFromEnv redirects stdout to the sinks in LOG_SINKS; with stdout alone it does nothing
+func StartSinksFromEnv() (stop func(), err error) {
+	if strings.TrimSpace(envutil.GetEnvWithDefault("LOG_SINKS", SinkStdout)) == SinkStdout {
+		return func() {}, nil
+	}
+	sinks, err := SinksFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	stop, err = RedirectStdout(sinks)
+	if err != nil {
+		_ = sinks.Close()
+		return nil, err
+	}
+	return stop, nil
+}
+
+// plainWriter strips color codes before writing
+type plainWriter struct {
+	w io.Writer
+}
+
+func (p plainWriter) Write(b []byte) (int, error) {
+	if _, err := p.w.Write(colorCodes.ReplaceAll(b, nil)); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
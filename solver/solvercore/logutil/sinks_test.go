@@ -0,0 +1,84 @@
+package logutil
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSinks(t *testing.T) {
+	t.Run("stdout lines are copied to every sink", func(t *testing.T) {
+		var colored, plain bytes.Buffer
+		sinks := &Sinks{}
+		sinks.Add(&colored, nil)
+		sinks.Add(plainWriter{&plain}, nil)
+
+		stop, err := RedirectStdout(sinks)
+		require.NoError(t, err)
+		fmt.Printf("%s📦 Processed blocks 1-2\n", Prefix("Base"))
+		fmt.Printf("no newline at the end")
+		stop()
+
+		assert.Equal(t, Prefix("Base")+"📦 Processed blocks 1-2\nno newline at the end", colored.String())
+		assert.Equal(t, "[BASE] 📦 Processed blocks 1-2\nno newline at the end", plain.String())
+	})
+
+	t.Run("stop restores stdout and closes the sinks", func(t *testing.T) {
+		stdout := os.Stdout
+		path := filepath.Join(t.TempDir(), "solver.log")
+		file, err := OpenRotatingFile(path, 0, 0, 0)
+		require.NoError(t, err)
+		sinks := &Sinks{}
+		sinks.Add(file, file)
+
+		stop, err := RedirectStdout(sinks)
+		require.NoError(t, err)
+		assert.NotSame(t, stdout, os.Stdout)
+		fmt.Println("to the file")
+		stop()
+
+		assert.Same(t, stdout, os.Stdout)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "to the file\n", string(data))
+		_, err = file.Write([]byte("x"))
+		assert.ErrorIs(t, err, os.ErrClosed)
+	})
+
+	t.Run("LOG_SINKS", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("LOG_SINKS", "stdout, file")
+		t.Setenv("LOG_FILE", filepath.Join(dir, "logs", "solver.log"))
+		sinks, err := SinksFromEnv()
+		require.NoError(t, err)
+		assert.Len(t, sinks.writers, 2)
+		assert.Len(t, sinks.closers, 1, "stdout is not closed")
+		require.NoError(t, sinks.Close())
+		assert.FileExists(t, filepath.Join(dir, "logs", "solver.log"))
+
+		t.Setenv("LOG_SINKS", "stdout,kafka")
+		_, err = SinksFromEnv()
+		assert.ErrorContains(t, err, `unknown log sink "kafka"`)
+	})
+
+	t.Run("stdout alone is not redirected", func(t *testing.T) {
+		t.Setenv("LOG_SINKS", "")
+		stdout := os.Stdout
+		stop, err := StartSinksFromEnv()
+		require.NoError(t, err)
+		assert.Same(t, stdout, os.Stdout)
+		stop()
+	})
+
+	t.Run("remote syslog address needs a network", func(t *testing.T) {
+		_, err := openSyslog("logs:514", "oif-solver")
+		require.Error(t, err)
+		assert.True(t, strings.Contains(err.Error(), "network://host:port") || strings.Contains(err.Error(), "not supported"))
+	})
+}
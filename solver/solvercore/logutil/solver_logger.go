@@ -1,12 +1,19 @@
 package logutil
 
 import (
-	"fmt"
+	"math/big"
 	"strings"
 
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 )
 
+// Loggers of the components the helpers below log for
+var (
+	listenerLog = For(ComponentListener)
+	fillerLog   = For(ComponentFiller)
+)
+
 // SolverLogger provides enhanced logging for solver operations
 type SolverLogger struct {
 	networkName string
@@ -32,14 +39,7 @@ func GetNetworkTagByChainID(chainID uint64) string {
 
 // CrossChainOperation logs a cross-chain operation with origin → destination format
 func CrossChainOperation(operation string, originChainID, destChainID uint64, orderID string) {
-	originTag := GetNetworkTagByChainID(originChainID)
-	destTag := GetNetworkTagByChainID(destChainID)
-
-	// Keep the color codes for better readability
-	originClean := strings.TrimSpace(originTag)
-	destClean := strings.TrimSpace(destTag)
-
-	fmt.Printf("%s → %s 🔄 %s (Order: %s)\n", originClean, destClean, operation, orderID[:8]+"...")
+	fillerLog.CrossChainOperation(operation, originChainID, destChainID, orderID)
 }
 
 // removeColorCodes removes ANSI color codes from a string
@@ -65,20 +65,52 @@ func LogOrderProcessing(args *types.ParsedArgs, operation string) {
 				destChainID.Uint64(),
 				args.OrderID)
 		} else {
-			fmt.Printf("🔄 %s (Order: %s)\n", operation, args.OrderID[:8]+"...")
+			fillerLog.Printf("🔄 %s (Order: %s)\n", operation, args.OrderID[:8]+"...")
 		}
 	} else {
-		fmt.Printf("🔄 %s (Order: %s)\n", operation, args.OrderID[:8]+"...")
+		fillerLog.Printf("🔄 %s (Order: %s)\n", operation, args.OrderID[:8]+"...")
+	}
+}
+
+// LogOrderAmounts logs what the solver spends and receives on an order, formatted with the token registry
+func LogOrderAmounts(args *types.ParsedArgs) {
+	var destinationChainID *big.Int
+	if len(args.ResolvedOrder.FillInstructions) > 0 {
+		destinationChainID = args.ResolvedOrder.FillInstructions[0].DestinationChainID
+	}
+	fillerLog.Printf("💱 Spend %s, receive %s (Order: %s)\n",
+		formatOutputs(args.ResolvedOrder.MaxSpent, destinationChainID),
+		formatOutputs(args.ResolvedOrder.MinReceived, args.ResolvedOrder.OriginChainID),
+		args.OrderID[:8]+"...")
+}
+
+// formatOutputs renders order outputs as "12.5 DOG + 1 ETH"; outputs without a chain ID use fallbackChainID
+func formatOutputs(outputs []types.Output, fallbackChainID *big.Int) string {
+	if len(outputs) == 0 {
+		return "nothing"
+	}
+	parts := make([]string, 0, len(outputs))
+	for _, output := range outputs {
+		chainID := output.ChainID
+		if chainID == nil {
+			chainID = fallbackChainID
+		}
+		var id uint64
+		if chainID != nil {
+			id = chainID.Uint64()
+		}
+		parts = append(parts, config.FormatTokenAmount(id, output.Token, output.Amount))
 	}
+	return strings.Join(parts, " + ")
 }
 
 // LogFillOperation logs a fill operation with network context
 func LogFillOperation(networkName, orderID string, success bool) {
 	tag := Prefix(networkName)
 	if success {
-		fmt.Printf("%s✅ Fill completed (Order: %s)\n", tag, orderID[:8]+"...")
+		fillerLog.Printf("%s✅ Fill completed (Order: %s)\n", tag, orderID[:8]+"...")
 	} else {
-		fmt.Printf("%s❌ Fill failed (Order: %s)\n", tag, orderID[:8]+"...")
+		fillerLog.Printf("%s❌ Fill failed (Order: %s)\n", tag, orderID[:8]+"...")
 	}
 }
 
@@ -86,9 +118,9 @@ func LogFillOperation(networkName, orderID string, success bool) {
 func LogSettleOperation(networkName, orderID string, success bool) {
 	tag := Prefix(networkName)
 	if success {
-		fmt.Printf("%s✅ Settlement completed (Order: %s)\n", tag, orderID[:8]+"...")
+		fillerLog.Printf("%s✅ Settlement completed (Order: %s)\n", tag, orderID[:8]+"...")
 	} else {
-		fmt.Printf("%s❌ Settlement failed (Order: %s)\n", tag, orderID[:8]+"...")
+		fillerLog.Printf("%s❌ Settlement failed (Order: %s)\n", tag, orderID[:8]+"...")
 	}
 }
 
@@ -96,10 +128,10 @@ func LogSettleOperation(networkName, orderID string, success bool) {
 func LogBlockProcessing(networkName string, fromBlock, toBlock uint64, eventCount int) {
 	tag := Prefix(networkName)
 	if eventCount > 0 {
-		fmt.Printf("%s📦 Processed blocks %d-%d: %d events\n", tag, fromBlock, toBlock, eventCount)
+		listenerLog.Printf("%s📦 Processed blocks %d-%d: %d events\n", tag, fromBlock, toBlock, eventCount)
 	} else if toBlock-fromBlock > 0 {
 		// Only log if processing multiple blocks to reduce noise
-		fmt.Printf("%s📦 Processed blocks %d-%d\n", tag, fromBlock, toBlock)
+		listenerLog.Printf("%s📦 Processed blocks %d-%d\n", tag, fromBlock, toBlock)
 	}
 }
 
@@ -107,16 +139,16 @@ func LogBlockProcessing(networkName string, fromBlock, toBlock uint64, eventCoun
 func LogStatusCheck(networkName string, attempt, maxAttempts int, status, expected string) {
 	tag := Prefix(networkName)
 	if attempt == 1 {
-		fmt.Printf("%s📊 Status: %s (expected: %s)\n", tag, status, expected)
+		fillerLog.Printf("%s📊 Status: %s (expected: %s)\n", tag, status, expected)
 	} else {
-		fmt.Printf("%s📊 Retry %d/%d: %s (expected: %s)\n", tag, attempt, maxAttempts, status, expected)
+		fillerLog.Printf("%s📊 Retry %d/%d: %s (expected: %s)\n", tag, attempt, maxAttempts, status, expected)
 	}
 }
 
 // LogRetryWait logs retry wait information
 func LogRetryWait(networkName string, attempt, maxAttempts int, delay string) {
 	tag := Prefix(networkName)
-	fmt.Printf("%s⏳ Waiting %s before retry %d/%d...\n", tag, delay, attempt+1, maxAttempts)
+	fillerLog.Printf("%s⏳ Waiting %s before retry %d/%d...\n", tag, delay, attempt+1, maxAttempts)
 }
 
 // LogOperationComplete logs the completion of an operation with cross-chain context
@@ -131,22 +163,22 @@ func LogOperationComplete(args *types.ParsedArgs, operation string, success bool
 			destClean := strings.TrimSpace(destTag)
 
 			if success {
-				fmt.Printf("%s → %s ✅ %s completed (Order: %s)\n", originClean, destClean, operation, args.OrderID[:8]+"...")
+				fillerLog.Printf("%s → %s ✅ %s completed (Order: %s)\n", originClean, destClean, operation, args.OrderID[:8]+"...")
 			} else {
-				fmt.Printf("%s → %s ❌ %s failed (Order: %s)\n", originClean, destClean, operation, args.OrderID[:8]+"...")
+				fillerLog.Printf("%s → %s ❌ %s failed (Order: %s)\n", originClean, destClean, operation, args.OrderID[:8]+"...")
 			}
 		} else {
 			if success {
-				fmt.Printf("✅ %s completed (Order: %s)\n", operation, args.OrderID[:8]+"...")
+				fillerLog.Printf("✅ %s completed (Order: %s)\n", operation, args.OrderID[:8]+"...")
 			} else {
-				fmt.Printf("❌ %s failed (Order: %s)\n", operation, args.OrderID[:8]+"...")
+				fillerLog.Printf("❌ %s failed (Order: %s)\n", operation, args.OrderID[:8]+"...")
 			}
 		}
 	} else {
 		if success {
-			fmt.Printf("✅ %s completed (Order: %s)\n", operation, args.OrderID[:8]+"...")
+			fillerLog.Printf("✅ %s completed (Order: %s)\n", operation, args.OrderID[:8]+"...")
 		} else {
-			fmt.Printf("❌ %s failed (Order: %s)\n", operation, args.OrderID[:8]+"...")
+			fillerLog.Printf("❌ %s failed (Order: %s)\n", operation, args.OrderID[:8]+"...")
 		}
 	}
 }
@@ -154,7 +186,7 @@ func LogOperationComplete(args *types.ParsedArgs, operation string, success bool
 // LogWithNetworkTagf adds a network tag to any log message
 func LogWithNetworkTagf(networkName, format string, args ...interface{}) {
 	tag := Prefix(networkName)
-	fmt.Printf(tag+format, args...)
+	fillerLog.Printf(tag+format, args...)
 }
 
 // LogPersistence logs persistence operations with reduced frequency
@@ -168,6 +200,6 @@ func LogPersistence(networkName string, blockNumber uint64) {
 	// Only log every 30 blocks or if it's the first block
 	if counter%30 == 1 || counter == 1 {
 		tag := Prefix(networkName)
-		fmt.Printf("%s💾 Persisted LastIndexedBlock=%d\n", tag, blockNumber)
+		listenerLog.Printf("%s💾 Persisted LastIndexedBlock=%d\n", tag, blockNumber)
 	}
 }
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package logutil
+
+import (
+	"errors"
+	"io"
+)
+
+// openSyslog is not supported: log/syslog is only available on unix
+func openSyslog(addr, tag string) (io.WriteCloser, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
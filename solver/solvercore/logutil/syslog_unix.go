@@ -0,0 +1,52 @@
+//go:build unix
+
+package logutil
+
+import (
+	"fmt"
+	"io"
+	"log/syslog"
+	"strings"
+)
+
+// syslogSink writes each line as a syslog message with the severity of its level
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+// openSyslog connects to the syslog at addr (network://host:port), or the local daemon if empty
+func openSyslog(addr, tag string) (io.WriteCloser, error) {
+	var network, raddr string
+	if addr != "" {
+		var ok bool
+		if network, raddr, ok = strings.Cut(addr, "://"); !ok {
+			return nil, fmt.Errorf("invalid LOG_SYSLOG_ADDR %q, want network://host:port", addr)
+		}
+	}
+	w, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, err
+	}
+	return syslogSink{w: w}, nil
+}
+
+func (s syslogSink) Write(p []byte) (int, error) {
+	line := strings.TrimRight(colorCodes.ReplaceAllString(string(p), ""), "\n")
+	var err error
+	switch LineLevel(line) {
+	case LevelError:
+		err = s.w.Err(line)
+	case LevelWarn:
+		err = s.w.Warning(line)
+	default:
+		err = s.w.Info(line)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s syslogSink) Close() error {
+	return s.w.Close()
+}
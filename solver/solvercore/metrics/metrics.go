@@ -0,0 +1,327 @@
+// Package metrics provides a small in-process registry of labeled counters, gauges and histograms.
+//
+// Metrics are identified by name plus a set of label pairs and can be rendered in the
+// Prometheus text exposition format with WriteText.
+//
+// Usage:
+//
+//	metrics.Default().Counter("solver_orders_total", "status", "filled").Inc()
+//	metrics.Default().Gauge("solver_listener_lag_blocks", "chain", "Base").Set(12)
+//	metrics.Default().Histogram("solver_fill_latency_seconds", metrics.LatencyBuckets, "origin", "Base").Observe(4.2)
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Counter is a monotonically increasing value
+type Counter struct {
+	mu    sync.Mutex
+	value float64
+}
+
+// Inc adds one to the counter
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add adds delta to the counter; negative deltas are ignored
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		return
+	}
+	c.mu.Lock()
+	c.value += delta
+	c.mu.Unlock()
+}
+
+// Value returns the current count
+func (c *Counter) Value() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value
+}
+
+// Gauge is a value that can go up and down
+type Gauge struct {
+	mu    sync.Mutex
+	value float64
+}
+
+// Set replaces the gauge value
+func (g *Gauge) Set(value float64) {
+	g.mu.Lock()
+	g.value = value
+	g.mu.Unlock()
+}
+
+// Add adds delta (which may be negative) to the gauge
+func (g *Gauge) Add(delta float64) {
+	g.mu.Lock()
+	g.value += delta
+	g.mu.Unlock()
+}
+
+// Value returns the current gauge value
+func (g *Gauge) Value() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.value
+}
+
+// LatencyBuckets are histogram buckets for durations in seconds, from a second to an hour
+var LatencyBuckets = []float64{1, 2, 5, 10, 20, 30, 60, 120, 300, 600, 1800, 3600}
+
+// Histogram counts observations in buckets by upper bound, rendered as the cumulative
+// _bucket, _sum and _count series Prometheus derives quantiles from
+type Histogram struct {
+	mu      sync.Mutex
+	buckets []float64 // Upper bounds, ascending
+	counts  []uint64  // Observations per bucket, not cumulative
+	count   uint64
+	sum     float64
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &Histogram{buckets: sorted, counts: make([]uint64, len(sorted))}
+}
+
+// Observe adds value to the histogram; values above the last bucket only count toward +Inf
+func (h *Histogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if i := sort.SearchFloat64s(h.buckets, value); i < len(h.buckets) {
+		h.counts[i]++
+	}
+	h.count++
+	h.sum += value
+}
+
+// Count returns the number of observations
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+// Sum returns the sum of the observations
+func (h *Histogram) Sum() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.sum
+}
+
+// snapshot returns the bucket bounds with their cumulative counts, the count and the sum
+func (h *Histogram) snapshot() (bounds []float64, cumulative []uint64, count uint64, sum float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	cumulative = make([]uint64, len(h.counts))
+	var total uint64
+	for i, c := range h.counts {
+		total += c
+		cumulative[i] = total
+	}
+	return h.buckets, cumulative, h.count, h.sum
+}
+
+type metricKind string
+
+const (
+	kindCounter   metricKind = "counter"
+	kindGauge     metricKind = "gauge"
+	kindHistogram metricKind = "histogram"
+)
+
+// series is one labeled instance of a metric
+type series struct {
+	labels    string // rendered {k="v",...}, empty when unlabeled
+	counter   *Counter
+	gauge     *Gauge
+	histogram *Histogram
+}
+
+// family groups all series sharing a metric name
+type family struct {
+	kind   metricKind
+	series map[string]*series
+}
+
+// Registry holds metric families by name
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+var (
+	defaultRegistry   = NewRegistry()
+	defaultRegistryMu sync.RWMutex
+)
+
+// Default returns the process-wide registry
+func Default() *Registry {
+	defaultRegistryMu.RLock()
+	defer defaultRegistryMu.RUnlock()
+	return defaultRegistry
+}
+
+// SetDefault replaces the process-wide registry, e.g. with one owned by a service embedding the
+// solver. Components keep the registry they were created with, so call it before starting the solver.
+func SetDefault(r *Registry) {
+	defaultRegistryMu.Lock()
+	defer defaultRegistryMu.Unlock()
+	defaultRegistry = r
+}
+
+// Counter returns the counter for name and label pairs (key, value, key, value, ...),
+// creating it on first use. It panics if name is already registered as a gauge.
+func (r *Registry) Counter(name string, labelPairs ...string) *Counter {
+	s := r.getSeries(name, kindCounter, nil, labelPairs)
+	return s.counter
+}
+
+// Gauge returns the gauge for name and label pairs, creating it on first use.
+// It panics if name is already registered as a counter.
+func (r *Registry) Gauge(name string, labelPairs ...string) *Gauge {
+	s := r.getSeries(name, kindGauge, nil, labelPairs)
+	return s.gauge
+}
+
+// Histogram returns the histogram for name and label pairs, creating it with buckets (upper
+// bounds) on first use; later calls get the existing buckets. It panics if name is already
+// registered as a counter or gauge.
+func (r *Registry) Histogram(name string, buckets []float64, labelPairs ...string) *Histogram {
+	s := r.getSeries(name, kindHistogram, buckets, labelPairs)
+	return s.histogram
+}
+
+func (r *Registry) getSeries(name string, kind metricKind, buckets []float64, labelPairs []string) *series {
+	labels := renderLabels(labelPairs)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{kind: kind, series: make(map[string]*series)}
+		r.families[name] = f
+	}
+	if f.kind != kind {
+		panic(fmt.Sprintf("metric %s registered as %s, requested as %s", name, f.kind, kind))
+	}
+
+	s, ok := f.series[labels]
+	if !ok {
+		s = &series{labels: labels}
+		switch kind {
+		case kindCounter:
+			s.counter = &Counter{}
+		case kindGauge:
+			s.gauge = &Gauge{}
+		case kindHistogram:
+			s.histogram = newHistogram(buckets)
+		}
+		f.series[labels] = s
+	}
+	return s
+}
+
+// WriteText renders all metrics in the Prometheus text exposition format, sorted by name
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		f := r.families[name]
+		fmt.Fprintf(&b, "# TYPE %s %s\n", name, f.kind)
+
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			s := f.series[key]
+			switch {
+			case s.counter != nil:
+				fmt.Fprintf(&b, "%s%s %s\n", name, s.labels, formatValue(s.counter.Value()))
+			case s.gauge != nil:
+				fmt.Fprintf(&b, "%s%s %s\n", name, s.labels, formatValue(s.gauge.Value()))
+			default:
+				writeHistogram(&b, name, s)
+			}
+		}
+	}
+	r.mu.Unlock()
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeHistogram renders the _bucket, _sum and _count series of a histogram
+func writeHistogram(b *strings.Builder, name string, s *series) {
+	bounds, cumulative, count, sum := s.histogram.snapshot()
+	for i, bound := range bounds {
+		fmt.Fprintf(b, "%s_bucket%s %d\n", name, withLabel(s.labels, "le", formatValue(bound)), cumulative[i])
+	}
+	fmt.Fprintf(b, "%s_bucket%s %d\n", name, withLabel(s.labels, "le", "+Inf"), count)
+	fmt.Fprintf(b, "%s_sum%s %s\n", name, s.labels, formatValue(sum))
+	fmt.Fprintf(b, "%s_count%s %d\n", name, s.labels, count)
+}
+
+// withLabel appends key="value" to rendered labels
+func withLabel(labels, key, value string) string {
+	label := fmt.Sprintf("%s=%q", key, value)
+	if labels == "" {
+		return "{" + label + "}"
+	}
+	return strings.TrimSuffix(labels, "}") + "," + label + "}"
+}
+
+// renderLabels renders label pairs as {k="v",...} sorted by key; an odd trailing key gets an empty value
+func renderLabels(pairs []string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	type label struct{ key, value string }
+	labels := make([]label, 0, (len(pairs)+1)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		l := label{key: pairs[i]}
+		if i+1 < len(pairs) {
+			l.value = pairs[i+1]
+		}
+		labels = append(labels, l)
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].key < labels[j].key })
+
+	parts := make([]string, len(labels))
+	for i, l := range labels {
+		parts[i] = fmt.Sprintf("%s=%q", l.key, l.value)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%g", v)
+}
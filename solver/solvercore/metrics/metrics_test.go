@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRegistry tests labeled counters, gauges and text rendering
+func TestRegistry(t *testing.T) {
+	t.Run("counters_by_labels", func(t *testing.T) {
+		r := NewRegistry()
+
+		r.Counter("orders_total", "status", "filled").Inc()
+		r.Counter("orders_total", "status", "filled").Add(2)
+		r.Counter("orders_total", "status", "rejected").Inc()
+		r.Counter("orders_total", "status", "rejected").Add(-5) // ignored
+
+		assert.Equal(t, 3.0, r.Counter("orders_total", "status", "filled").Value())
+		assert.Equal(t, 1.0, r.Counter("orders_total", "status", "rejected").Value())
+	})
+
+	t.Run("label_order_does_not_matter", func(t *testing.T) {
+		r := NewRegistry()
+
+		r.Counter("fills_total", "origin", "Base", "destination", "Starknet").Inc()
+		assert.Equal(t, 1.0, r.Counter("fills_total", "destination", "Starknet", "origin", "Base").Value())
+	})
+
+	t.Run("gauges", func(t *testing.T) {
+		r := NewRegistry()
+
+		g := r.Gauge("lag_blocks", "chain", "Base")
+		g.Set(10)
+		g.Add(-3)
+		assert.Equal(t, 7.0, g.Value())
+	})
+
+	t.Run("kind_mismatch_panics", func(t *testing.T) {
+		r := NewRegistry()
+		r.Counter("mixed")
+
+		assert.Panics(t, func() { r.Gauge("mixed") })
+	})
+
+	t.Run("write_text", func(t *testing.T) {
+		r := NewRegistry()
+		r.Gauge("b_gauge").Set(1.5)
+		r.Counter("a_total", "kind", "missing_fill", "chain", "Base").Add(2)
+
+		var out strings.Builder
+		require.NoError(t, r.WriteText(&out))
+		assert.Equal(t,
+			"# TYPE a_total counter\n"+
+				"a_total{chain=\"Base\",kind=\"missing_fill\"} 2\n"+
+				"# TYPE b_gauge gauge\n"+
+				"b_gauge 1.5\n",
+			out.String())
+	})
+
+	t.Run("histograms", func(t *testing.T) {
+		r := NewRegistry()
+
+		h := r.Histogram("latency_seconds", []float64{10, 1, 5}, "origin", "Base")
+		for _, v := range []float64{0.5, 1, 3, 7, 60} {
+			h.Observe(v)
+		}
+		assert.Same(t, h, r.Histogram("latency_seconds", nil, "origin", "Base"))
+		assert.Equal(t, uint64(5), h.Count())
+		assert.Equal(t, 71.5, h.Sum())
+		assert.Panics(t, func() { r.Counter("latency_seconds") })
+
+		r.Histogram("margin_bps", []float64{0}).Observe(-3)
+
+		var out strings.Builder
+		require.NoError(t, r.WriteText(&out))
+		assert.Equal(t,
+			"# TYPE latency_seconds histogram\n"+
+				"latency_seconds_bucket{origin=\"Base\",le=\"1\"} 2\n"+
+				"latency_seconds_bucket{origin=\"Base\",le=\"5\"} 3\n"+
+				"latency_seconds_bucket{origin=\"Base\",le=\"10\"} 4\n"+
+				"latency_seconds_bucket{origin=\"Base\",le=\"+Inf\"} 5\n"+
+				"latency_seconds_sum{origin=\"Base\"} 71.5\n"+
+				"latency_seconds_count{origin=\"Base\"} 5\n"+
+				"# TYPE margin_bps histogram\n"+
+				"margin_bps_bucket{le=\"0\"} 1\n"+
+				"margin_bps_bucket{le=\"+Inf\"} 1\n"+
+				"margin_bps_sum -3\n"+
+				"margin_bps_count 1\n",
+			out.String())
+	})
+
+	t.Run("set_default", func(t *testing.T) {
+		previous := Default()
+		defer SetDefault(previous)
+
+		r := NewRegistry()
+		SetDefault(r)
+		Default().Counter("embedded_total").Inc()
+		assert.Same(t, r, Default())
+		assert.Equal(t, 1.0, r.Counter("embedded_total").Value())
+	})
+}
@@ -0,0 +1,45 @@
+package orders
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// BenchmarkStoreWrites measures recording an order and its status changes; every write rewrites
+// the whole file, so the cost grows with the number of orders already stored
+func BenchmarkStoreWrites(b *testing.B) {
+	for _, existing := range []int{0, 1000} {
+		b.Run(fmt.Sprintf("existing=%d", existing), func(b *testing.B) {
+			store, err := NewStore(filepath.Join(b.TempDir(), "orders.json"))
+			require.NoError(b, err)
+			for i := range existing {
+				orderID := fmt.Sprintf("0xe%063x", i)
+				store.orders[orderID] = &Order{OrderID: orderID, Status: StatusSettled, Args: testArgs(orderID)}
+			}
+			require.NoError(b, store.Update(fmt.Sprintf("0xe%063x", 0), func(*Order) {}))
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := range b.N {
+				orderID := fmt.Sprintf("0x%064x", i)
+				if err := store.Observe(testArgs(orderID)); err != nil {
+					b.Fatal(err)
+				}
+				if err := store.SetStatus(orderID, StatusFilled, ""); err != nil {
+					b.Fatal(err)
+				}
+				// Keep the store at its initial size
+				store.mu.Lock()
+				delete(store.orders, orderID)
+				store.mu.Unlock()
+				if err := store.backend.Delete(storage.Orders, orderID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package orders
+
+// Export of the order store for offline analysis (CSV or JSON)
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Filter selects orders for export; zero fields match everything
+type Filter struct {
+	OriginChainID      uint64
+	DestinationChainID uint64
+	Statuses           []Status
+	From               time.Time // Inclusive, compared against CreatedAt
+	To                 time.Time // Exclusive, compared against CreatedAt
+}
+
+// Match reports whether the order passes the filter
+func (f Filter) Match(o Order) bool {
+	if f.OriginChainID != 0 && o.OriginChainID != f.OriginChainID {
+		return false
+	}
+	if f.DestinationChainID != 0 && o.DestinationChainID != f.DestinationChainID {
+		return false
+	}
+	if len(f.Statuses) > 0 {
+		found := false
+		for _, status := range f.Statuses {
+			if o.Status == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !f.From.IsZero() && o.CreatedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !o.CreatedAt.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// ParseStatus parses a status name case-insensitively
+func ParseStatus(value string) (Status, error) {
+	status := Status(strings.ToUpper(strings.TrimSpace(value)))
+	switch status {
+	case StatusObserved, StatusRejected, StatusFailed, StatusFilled, StatusSettled, StatusLostRace, StatusExpired:
+		return status, nil
+	default:
+		return "", fmt.Errorf("unknown order status: %s", value)
+	}
+}
+
+// ExportRecord is the flattened view of an order written by the export
+type ExportRecord struct {
+	OrderID            string        `json:"orderId"`
+	OriginChainID      uint64        `json:"originChainId"`
+	DestinationChainID uint64        `json:"destinationChainId"`
+	Status             Status        `json:"status"`
+	Reason             string        `json:"reason,omitempty"`
+	Inputs             []TokenAmount `json:"inputs"`  // Order MinReceived
+	Outputs            []TokenAmount `json:"outputs"` // Order MaxSpent
+	InputReceived      []TokenAmount `json:"inputReceived,omitempty"`
+	OutputPaid         []TokenAmount `json:"outputPaid,omitempty"`
+	Transactions       []TxCost      `json:"transactions,omitempty"`
+	OriginStatus       string        `json:"originStatus,omitempty"`
+	DestinationStatus  string        `json:"destinationStatus,omitempty"`
+	CreatedAt          time.Time     `json:"createdAt"`
+	UpdatedAt          time.Time     `json:"updatedAt"`
+	FilledAt           *time.Time    `json:"filledAt,omitempty"`
+	SettledAt          *time.Time    `json:"settledAt,omitempty"`
+}
+
+// NewExportRecord flattens an order
+func NewExportRecord(o Order) ExportRecord {
+	rec := ExportRecord{
+		OrderID:            o.OrderID,
+		OriginChainID:      o.OriginChainID,
+		DestinationChainID: o.DestinationChainID,
+		Status:             o.Status,
+		Reason:             o.Reason,
+		Inputs:             []TokenAmount{},
+		Outputs:            []TokenAmount{},
+		InputReceived:      o.InputReceived,
+		OutputPaid:         o.OutputPaid,
+		Transactions:       o.Costs,
+		OriginStatus:       o.OriginStatus,
+		DestinationStatus:  o.DestinationStatus,
+		CreatedAt:          o.CreatedAt,
+		UpdatedAt:          o.UpdatedAt,
+	}
+	if o.Args != nil {
+		for _, in := range o.Args.ResolvedOrder.MinReceived {
+			rec.Inputs = append(rec.Inputs, tokenAmountFromOutput(in))
+		}
+		for _, out := range o.Args.ResolvedOrder.MaxSpent {
+			rec.Outputs = append(rec.Outputs, tokenAmountFromOutput(out))
+		}
+	}
+	if !o.FilledAt.IsZero() {
+		filledAt := o.FilledAt
+		rec.FilledAt = &filledAt
+	}
+	if !o.SettledAt.IsZero() {
+		settledAt := o.SettledAt
+		rec.SettledAt = &settledAt
+	}
+	return rec
+}
+
+// Export returns the records of the orders that pass the filter, oldest first
+func Export(list []Order, filter Filter) []ExportRecord {
+	records := make([]ExportRecord, 0, len(list))
+	for _, order := range list {
+		if filter.Match(order) {
+			records = append(records, NewExportRecord(order))
+		}
+	}
+	return records
+}
+
+var exportCSVHeader = []string{
+	"order_id", "origin_chain_id", "destination_chain_id", "status", "reason",
+	"inputs", "outputs", "input_received", "output_paid", "tx_hashes", "fees",
+	"origin_status", "destination_status", "created_at", "updated_at", "filled_at", "settled_at",
+}
+
+// WriteExportCSV writes one line per record. Multi-valued columns are ';'-separated:
+// token amounts as token:amount@chain, transactions as kind:hash@chain and fees as amount unit.
+func WriteExportCSV(w io.Writer, records []ExportRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportCSVHeader); err != nil {
+		return err
+	}
+	for _, rec := range records {
+		var txs, fees []string
+		for _, tx := range rec.Transactions {
+			txs = append(txs, fmt.Sprintf("%s:%s@%d", tx.Kind, tx.TxHash, tx.ChainID))
+			fees = append(fees, fmt.Sprintf("%s %s", amountString(tx.Fee), tx.FeeUnit))
+		}
+		line := []string{
+			rec.OrderID,
+			fmt.Sprint(rec.OriginChainID),
+			fmt.Sprint(rec.DestinationChainID),
+			string(rec.Status),
+			rec.Reason,
+			joinTokenAmounts(rec.Inputs),
+			joinTokenAmounts(rec.Outputs),
+			joinTokenAmounts(rec.InputReceived),
+			joinTokenAmounts(rec.OutputPaid),
+			strings.Join(txs, ";"),
+			strings.Join(fees, ";"),
+			rec.OriginStatus,
+			rec.DestinationStatus,
+			formatTime(&rec.CreatedAt),
+			formatTime(&rec.UpdatedAt),
+			formatTime(rec.FilledAt),
+			formatTime(rec.SettledAt),
+		}
+		if err := cw.Write(line); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteExportJSON writes the records as an indented JSON array
+func WriteExportJSON(w io.Writer, records []ExportRecord) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
+
+func joinTokenAmounts(amounts []TokenAmount) string {
+	parts := make([]string, 0, len(amounts))
+	for _, ta := range amounts {
+		parts = append(parts, fmt.Sprintf("%s:%s@%d", ta.Token, amountString(ta.Amount), ta.ChainID))
+	}
+	return strings.Join(parts, ";")
+}
+
+func amountString(amount *big.Int) string {
+	if amount == nil {
+		return "0"
+	}
+	return amount.String()
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,100 @@
+package orders
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportOrder(id string, status Status, createdAt time.Time) Order {
+	args := testArgs(id)
+	args.ResolvedOrder.MinReceived = []types.Output{{Token: "0xin", Amount: big.NewInt(100), ChainID: big.NewInt(84532)}}
+	args.ResolvedOrder.MaxSpent = []types.Output{{Token: "0xout", Amount: big.NewInt(90), ChainID: big.NewInt(23448591)}}
+	return Order{
+		OrderID:            id,
+		OriginChainID:      84532,
+		DestinationChainID: 23448591,
+		Status:             status,
+		Args:               args,
+		CreatedAt:          createdAt,
+		UpdatedAt:          createdAt,
+	}
+}
+
+// TestExport tests filtering and encoding of the order export
+func TestExport(t *testing.T) {
+	day := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	rejected := exportOrder("0x01", StatusRejected, day)
+	rejected.Reason = "below margin"
+	settled := exportOrder("0x02", StatusSettled, day.Add(time.Hour))
+	settled.FilledAt = day.Add(time.Hour)
+	settled.SettledAt = day.Add(2 * time.Hour)
+	settled.Costs = []TxCost{{ChainID: 23448591, Kind: TxKindFillSettle, TxHash: "0xabc", Fee: big.NewInt(7), FeeUnit: "FRI"}}
+	otherPair := exportOrder("0x03", StatusSettled, day)
+	otherPair.DestinationChainID = 11155111
+	list := []Order{rejected, settled, otherPair}
+
+	t.Run("filters", func(t *testing.T) {
+		assert.Len(t, Export(list, Filter{}), 3)
+		assert.Len(t, Export(list, Filter{DestinationChainID: 23448591}), 2)
+		assert.Len(t, Export(list, Filter{OriginChainID: 1}), 0)
+
+		bySettled := Export(list, Filter{Statuses: []Status{StatusSettled}, DestinationChainID: 23448591})
+		require.Len(t, bySettled, 1)
+		assert.Equal(t, "0x02", bySettled[0].OrderID)
+
+		byTime := Export(list, Filter{From: day.Add(30 * time.Minute), To: day.Add(24 * time.Hour)})
+		require.Len(t, byTime, 1)
+		assert.Equal(t, "0x02", byTime[0].OrderID)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteExportCSV(&buf, Export(list[:2], Filter{})))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 3)
+		assert.True(t, strings.HasPrefix(lines[0], "order_id,origin_chain_id"))
+		assert.Contains(t, lines[1], "REJECTED,below margin,0xin:100@84532,0xout:90@23448591")
+		assert.Contains(t, lines[2], "fill+settle:0xabc@23448591,7 FRI")
+		assert.True(t, strings.HasSuffix(lines[2], "2026-01-10T13:00:00Z,2026-01-10T14:00:00Z"))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteExportJSON(&buf, Export(list[:2], Filter{})))
+
+		var decoded []ExportRecord
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		require.Len(t, decoded, 2)
+		assert.Equal(t, "below margin", decoded[0].Reason)
+		assert.Nil(t, decoded[0].FilledAt)
+		require.Len(t, decoded[1].Transactions, 1)
+		assert.Equal(t, "0xabc", decoded[1].Transactions[0].TxHash)
+	})
+
+	t.Run("parse_status", func(t *testing.T) {
+		status, err := ParseStatus(" filled ")
+		require.NoError(t, err)
+		assert.Equal(t, StatusFilled, status)
+
+		status, err = ParseStatus("lost_race")
+		require.NoError(t, err)
+		assert.Equal(t, StatusLostRace, status)
+
+		status, err = ParseStatus("expired")
+		require.NoError(t, err)
+		assert.Equal(t, StatusExpired, status)
+
+		_, err = ParseStatus("pending")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,71 @@
+package orders
+
+// Breakdown of rejected orders by reason code, to tune the rules (balances, margins, tokens, deadlines)
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// UnclassifiedReason counts rejected orders recorded without a reason code
+const UnclassifiedReason = "unclassified"
+
+// RejectionCount is the number of orders rejected for one reason
+type RejectionCount struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// CountRejections counts the rejected orders that pass the filter by reason code, most frequent first.
+// The filter's statuses are ignored.
+func CountRejections(list []Order, filter Filter) []RejectionCount {
+	filter.Statuses = []Status{StatusRejected}
+	counts := make(map[string]int)
+	for _, order := range list {
+		if !filter.Match(order) {
+			continue
+		}
+		reason := order.RejectReason
+		if reason == "" {
+			reason = UnclassifiedReason
+		}
+		counts[reason]++
+	}
+
+	result := make([]RejectionCount, 0, len(counts))
+	for reason, count := range counts {
+		result = append(result, RejectionCount{Reason: reason, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Reason < result[j].Reason
+	})
+	return result
+}
+
+// WriteRejectionsCSV writes one reason,count line per reason
+func WriteRejectionsCSV(w io.Writer, counts []RejectionCount) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"reason", "count"}); err != nil {
+		return err
+	}
+	for _, c := range counts {
+		if err := cw.Write([]string{c.Reason, strconv.Itoa(c.Count)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteRejectionsJSON writes the counts as an indented JSON array
+func WriteRejectionsJSON(w io.Writer, counts []RejectionCount) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(counts)
+}
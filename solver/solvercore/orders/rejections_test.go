@@ -0,0 +1,61 @@
+package orders
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCountRejections tests the breakdown of rejected orders by reason code
+func TestCountRejections(t *testing.T) {
+	day := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	rejected := func(id, code string, createdAt time.Time) Order {
+		order := exportOrder(id, StatusRejected, createdAt)
+		order.RejectReason = code
+		return order
+	}
+	list := []Order{
+		rejected("0x01", "below_margin", day),
+		rejected("0x02", "insufficient_balance", day),
+		rejected("0x03", "insufficient_balance", day.Add(time.Hour)),
+		rejected("0x04", "", day),
+		exportOrder("0x05", StatusFilled, day),
+		rejected("0x06", "below_margin", day.Add(48*time.Hour)),
+	}
+
+	t.Run("by_reason", func(t *testing.T) {
+		counts := CountRejections(list, Filter{Statuses: []Status{StatusFilled}})
+		assert.Equal(t, []RejectionCount{
+			{Reason: "below_margin", Count: 2},
+			{Reason: "insufficient_balance", Count: 2},
+			{Reason: UnclassifiedReason, Count: 1},
+		}, counts)
+	})
+
+	t.Run("time_range", func(t *testing.T) {
+		counts := CountRejections(list, Filter{From: day, To: day.Add(24 * time.Hour)})
+		require.Len(t, counts, 3)
+		assert.Equal(t, RejectionCount{Reason: "insufficient_balance", Count: 2}, counts[0])
+		assert.Equal(t, RejectionCount{Reason: "below_margin", Count: 1}, counts[1])
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteRejectionsCSV(&buf, CountRejections(list[:2], Filter{})))
+		assert.Equal(t, "reason,count\nbelow_margin,1\ninsufficient_balance,1\n", buf.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteRejectionsJSON(&buf, CountRejections(list[:1], Filter{})))
+
+		var decoded []RejectionCount
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		assert.Equal(t, []RejectionCount{{Reason: "below_margin", Count: 1}}, decoded)
+	})
+}
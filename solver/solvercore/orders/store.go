@@ -0,0 +1,429 @@
+// Package orders persists the solver's view of every order it has observed.
+//
+// The store records the parsed order, the local processing status and the last
+// on-chain status seen for the order on each chain. It is used by the solver
+// pipeline (to record progress), by the reconciliation job (to compare local state
+// against the chains) and by reporting commands.
+//
+// Orders are kept in memory and each change is written to the storage backend (see
+// package storage): with the file backend the whole store is rewritten atomically to a
+// single JSON file, a database backend updates the changed order only.
+//
+// Settings:
+// - SOLVER_ORDER_STORE_FILE: path of the order store file of the file backend (default state/solver_state/orders.json)
+package orders
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// ErrOrderNotFound is returned when an order ID is not in the store
+var ErrOrderNotFound = errors.New("order not found")
+
+// Status is the solver's local processing status of an order
+type Status string
+
+const (
+	StatusObserved Status = "OBSERVED"  // Seen on the origin chain, not yet filled
+	StatusRejected Status = "REJECTED"  // Refused by allow/block lists or rules
+	StatusFailed   Status = "FAILED"    // Fill or settle attempt failed
+	StatusFilled   Status = "FILLED"    // Filled on the destination chain, settlement pending
+	StatusSettled  Status = "SETTLED"   // Filled and settled
+	StatusLostRace Status = "LOST_RACE" // Filled first by another solver
+	StatusExpired  Status = "EXPIRED"   // Past its fill deadline or older than rules.maxOrderAge when seen
+)
+
+// Transaction kinds recorded in TxCost
+const (
+	TxKindApprove    = "approve"
+	TxKindFill       = "fill"
+	TxKindSettle     = "settle"
+	TxKindFillSettle = "fill+settle" // Starknet multicall doing both
+	TxKindOpen       = "open"        // Orders the solver opens itself to rebalance its inventory
+)
+
+// TokenAmount is an amount of one token on one chain
+type TokenAmount struct {
+	ChainID uint64   `json:"chainId"`
+	Token   string   `json:"token"`
+	Amount  *big.Int `json:"amount"`
+}
+
+// TxCost is the cost of one transaction the solver sent for an order
+type TxCost struct {
+	ChainID    uint64   `json:"chainId"`
+	Kind       string   `json:"kind"`
+	TxHash     string   `json:"txHash"`
+	Fee        *big.Int `json:"fee"`                  // Network fee paid, in FeeUnit
+	FeeUnit    string   `json:"feeUnit"`              // WEI or FRI
+	GasPayment *big.Int `json:"gasPayment,omitempty"` // Hyperlane interchain gas payment attached to settle (wei)
+}
+
+// OrderTx is a fill or settle transaction the solver broadcast for an order, mined or not
+type OrderTx struct {
+	ChainID uint64    `json:"chainId"`
+	Kind    string    `json:"kind"`
+	TxHash  string    `json:"txHash"`
+	SentAt  time.Time `json:"sentAt"`
+}
+
+// Order is a single order record
+type Order struct {
+	OrderID            string            `json:"orderId"`
+	OriginChainID      uint64            `json:"originChainId"`
+	DestinationChainID uint64            `json:"destinationChainId"`
+	Status             Status            `json:"status"`
+	Reason             string            `json:"reason,omitempty"`       // Rejection reason or last error
+	RejectReason       string            `json:"rejectReason,omitempty"` // Reason code of a REJECTED order (e.g. insufficient_balance)
+	Args               *types.ParsedArgs `json:"args,omitempty"`
+
+	// Economics: what the solver paid out, received and spent on transactions
+	OutputPaid    []TokenAmount `json:"outputPaid,omitempty"`    // MaxSpent paid on the destination by our fill
+	InputReceived []TokenAmount `json:"inputReceived,omitempty"` // MinReceived credited on the origin after settlement
+	Costs         []TxCost      `json:"costs,omitempty"`
+
+	// Every fill and settle transaction broadcast for the order, replacements included, so
+	// operators can look them up on the explorers before (or without) a receipt
+	Txs []OrderTx `json:"txs,omitempty"`
+
+	// Last statuses read from the routers by the reconciliation job, or from origin events by the listeners
+	OriginStatus      string    `json:"originStatus,omitempty"`
+	DestinationStatus string    `json:"destinationStatus,omitempty"`
+	LastReconciledAt  time.Time `json:"lastReconciledAt,omitempty"`
+
+	// Settled or Refunded event of the order seen on the origin chain
+	OriginEventTxHash  string    `json:"originEventTxHash,omitempty"`
+	ProceedsReceivedAt time.Time `json:"proceedsReceivedAt,omitempty"` // When the Settled event paying the solver was seen
+	ProceedsOverdueAt  time.Time `json:"proceedsOverdueAt,omitempty"`  // When the proceeds were flagged as not arrived in time
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	FilledAt  time.Time `json:"filledAt,omitempty"`
+	SettledAt time.Time `json:"settledAt,omitempty"`
+}
+
+// Transition sets the status and reason, stamping FilledAt/SettledAt on the first
+// transition to FILLED or SETTLED and clearing RejectReason when leaving REJECTED
+func (o *Order) Transition(status Status, reason string, at time.Time) {
+	o.Status = status
+	o.Reason = reason
+	if status != StatusRejected {
+		o.RejectReason = ""
+	}
+	if (status == StatusFilled || status == StatusSettled) && o.FilledAt.IsZero() {
+		o.FilledAt = at
+	}
+	if status == StatusSettled && o.SettledAt.IsZero() {
+		o.SettledAt = at
+	}
+}
+
+// FilledByUs reports whether the solver sent a fill for this order
+func (o *Order) FilledByUs() bool {
+	for _, cost := range o.Costs {
+		if cost.Kind == TxKindFill || cost.Kind == TxKindFillSettle {
+			return true
+		}
+	}
+	return false
+}
+
+// FilledInTx reports whether txHash is a fill the solver sent for this order
+func (o *Order) FilledInTx(txHash string) bool {
+	for _, cost := range o.Costs {
+		if (cost.Kind == TxKindFill || cost.Kind == TxKindFillSettle) && strings.EqualFold(cost.TxHash, txHash) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProceedsPending reports whether the solver filled and settled the order but the origin router has not
+// released the input to it yet
+func (o *Order) ProceedsPending() bool {
+	return o.Status == StatusSettled && o.FilledByUs() && o.ProceedsReceivedAt.IsZero() && o.OriginStatus != "SETTLED"
+}
+
+// Store is a thread-safe order store kept in memory and persisted to a storage backend
+type Store struct {
+	mu      sync.Mutex
+	path    string // File of the file backend, empty for database backends
+	backend storage.Storage
+	orders  map[string]*Order
+	now     func() time.Time
+}
+
+// NewStore opens the file store at path, starting empty if the file does not exist
+func NewStore(path string) (*Store, error) {
+	s, err := OpenStore(storage.NewFileStorage(filepath.Dir(path), map[string]string{storage.Orders: path}))
+	if err != nil {
+		return nil, err
+	}
+	s.path = path
+	return s, nil
+}
+
+// OpenStore loads the orders kept in backend
+func OpenStore(backend storage.Storage) (*Store, error) {
+	docs, err := backend.Load(storage.Orders)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load order store: %w", err)
+	}
+	s := &Store{
+		backend: backend,
+		orders:  make(map[string]*Order, len(docs)),
+		now:     time.Now,
+	}
+	for orderID, doc := range docs {
+		var order Order
+		if err := json.Unmarshal(doc, &order); err != nil {
+			return nil, fmt.Errorf("failed to parse order %s: %w", orderID, err)
+		}
+		s.orders[orderID] = &order
+	}
+	return s, nil
+}
+
+var (
+	defaultStore     *Store
+	defaultStoreErr  error
+	defaultStoreOnce sync.Once
+)
+
+// DefaultStore returns the process-wide store in the default storage backend
+func DefaultStore() (*Store, error) {
+	defaultStoreOnce.Do(func() {
+		backend, err := storage.Default()
+		if err != nil {
+			defaultStoreErr = err
+			return
+		}
+		defaultStore, defaultStoreErr = OpenStore(backend)
+		if defaultStoreErr == nil && backend.Backend() == storage.BackendFile {
+			defaultStore.path = storage.FilePath(storage.Orders)
+		}
+	})
+	return defaultStore, defaultStoreErr
+}
+
+// Path returns the file backing the store, empty for database backends
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Observe records a newly seen order; existing records are left untouched
+func (s *Store) Observe(args *types.ParsedArgs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.orders[args.OrderID]; exists {
+		return nil
+	}
+
+	now := s.now()
+	order := &Order{
+		OrderID:   args.OrderID,
+		Status:    StatusObserved,
+		Args:      args,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if args.ResolvedOrder.OriginChainID != nil {
+		order.OriginChainID = args.ResolvedOrder.OriginChainID.Uint64()
+	}
+	if len(args.ResolvedOrder.FillInstructions) > 0 && args.ResolvedOrder.FillInstructions[0].DestinationChainID != nil {
+		order.DestinationChainID = args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
+	}
+
+	s.orders[args.OrderID] = order
+	return s.saveLocked(order)
+}
+
+// SetStatus updates the local status of an order; reason may be empty
+func (s *Store) SetStatus(orderID string, status Status, reason string) error {
+	return s.Update(orderID, func(o *Order) {
+		o.Transition(status, reason, s.now())
+	})
+}
+
+// Reject records an order as REJECTED with a reason code (e.g. insufficient_balance) and
+// the detailed reason
+func (s *Store) Reject(orderID, code, reason string) error {
+	return s.Update(orderID, func(o *Order) {
+		o.Transition(StatusRejected, reason, s.now())
+		o.RejectReason = code
+	})
+}
+
+// AddCost records a transaction sent for the order. A fill also records the MaxSpent
+// outputs as paid, since only our own fills move the solver's tokens.
+func (s *Store) AddCost(orderID string, cost TxCost) error {
+	return s.Update(orderID, func(o *Order) {
+		o.Costs = append(o.Costs, cost)
+
+		isFill := cost.Kind == TxKindFill || cost.Kind == TxKindFillSettle
+		if isFill && len(o.OutputPaid) == 0 && o.Args != nil {
+			for _, out := range o.Args.ResolvedOrder.MaxSpent {
+				o.OutputPaid = append(o.OutputPaid, tokenAmountFromOutput(out))
+			}
+		}
+	})
+}
+
+// RecordTx records a transaction broadcast for the order; a hash already recorded on the chain is ignored
+func (s *Store) RecordTx(orderID string, tx OrderTx) error {
+	return s.Update(orderID, func(o *Order) {
+		for _, known := range o.Txs {
+			if known.ChainID == tx.ChainID && strings.EqualFold(known.TxHash, tx.TxHash) {
+				return
+			}
+		}
+		o.Txs = append(o.Txs, tx)
+	})
+}
+
+// tokenAmountFromOutput converts an order output to a TokenAmount
+func tokenAmountFromOutput(out types.Output) TokenAmount {
+	ta := TokenAmount{Token: out.Token, Amount: new(big.Int)}
+	if out.ChainID != nil {
+		ta.ChainID = out.ChainID.Uint64()
+	}
+	if out.Amount != nil {
+		ta.Amount.Set(out.Amount)
+	}
+	return ta
+}
+
+// RecordInputReceived marks the order's MinReceived as credited on the origin chain
+func (s *Store) RecordInputReceived(orderID string) error {
+	return s.Update(orderID, recordInputReceived)
+}
+
+// recordInputReceived copies the order's MinReceived into InputReceived once
+func recordInputReceived(o *Order) {
+	if len(o.InputReceived) > 0 || o.Args == nil {
+		return
+	}
+	for _, in := range o.Args.ResolvedOrder.MinReceived {
+		o.InputReceived = append(o.InputReceived, tokenAmountFromOutput(in))
+	}
+}
+
+// RecordOriginSettled records the origin router's Settled event: the order's input was released
+// to the filler in txHash, which is empty when the status was read from the router instead
+func (s *Store) RecordOriginSettled(orderID, txHash string) error {
+	return s.Update(orderID, func(o *Order) {
+		o.OriginStatus = "SETTLED"
+		if txHash != "" {
+			o.OriginEventTxHash = txHash
+		}
+		if o.ProceedsReceivedAt.IsZero() {
+			o.ProceedsReceivedAt = s.now()
+		}
+		recordInputReceived(o)
+	})
+}
+
+// RecordOriginRefunded records the origin router's Refunded event: the order's input went back to
+// the user in txHash
+func (s *Store) RecordOriginRefunded(orderID, txHash string) error {
+	return s.Update(orderID, func(o *Order) {
+		o.OriginStatus = "REFUNDED"
+		o.OriginEventTxHash = txHash
+	})
+}
+
+// FlagProceedsOverdue marks the order's settlement proceeds as not arrived in time; only the first flag is kept
+func (s *Store) FlagProceedsOverdue(orderID string) error {
+	return s.Update(orderID, func(o *Order) {
+		if o.ProceedsOverdueAt.IsZero() {
+			o.ProceedsOverdueAt = s.now()
+		}
+	})
+}
+
+// Update applies fn to the stored order and persists the result
+func (s *Store) Update(orderID string, fn func(*Order)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[orderID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrOrderNotFound, orderID)
+	}
+
+	fn(order)
+	order.UpdatedAt = s.now()
+	return s.saveLocked(order)
+}
+
+// Get returns a copy of the order
+func (s *Store) Get(orderID string) (Order, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[orderID]
+	if !ok {
+		return Order{}, false
+	}
+	return *order, true
+}
+
+// List returns copies of all orders, oldest first
+func (s *Store) List() []Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]Order, 0, len(s.orders))
+	for _, order := range s.orders {
+		list = append(list, *order)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.Before(list[j].CreatedAt)
+		}
+		return list[i].OrderID < list[j].OrderID
+	})
+	return list
+}
+
+// Sample returns up to n orders accepted by keep (nil keeps all), least recently reconciled first
+func (s *Store) Sample(n int, keep func(Order) bool) []Order {
+	var candidates []Order
+	for _, order := range s.List() {
+		if keep == nil || keep(order) {
+			candidates = append(candidates, order)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].LastReconciledAt.Before(candidates[j].LastReconciledAt)
+	})
+	if n >= 0 && len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	return candidates
+}
+
+// saveLocked persists order; s.mu must be held
+func (s *Store) saveLocked(order *Order) error {
+	doc, err := json.Marshal(order)
+	if err != nil {
+		return fmt.Errorf("failed to marshal order %s: %w", order.OrderID, err)
+	}
+	if err := s.backend.Put(storage.Orders, order.OrderID, doc); err != nil {
+		return fmt.Errorf("failed to save order store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package orders
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testArgs(orderID string) *types.ParsedArgs {
+	return &types.ParsedArgs{
+		OrderID: orderID,
+		ResolvedOrder: types.ResolvedCrossChainOrder{
+			OriginChainID: big.NewInt(84532),
+			FillInstructions: []types.FillInstruction{
+				{DestinationChainID: big.NewInt(23448591), DestinationSettler: "0x1234"},
+			},
+		},
+	}
+}
+
+// TestStore tests recording and persisting orders
+func TestStore(t *testing.T) {
+	t.Run("observe_and_update", func(t *testing.T) {
+		store, err := NewStore(filepath.Join(t.TempDir(), "orders.json"))
+		require.NoError(t, err)
+
+		require.NoError(t, store.Observe(testArgs("0x01")))
+		order, ok := store.Get("0x01")
+		require.True(t, ok)
+		assert.Equal(t, StatusObserved, order.Status)
+		assert.Equal(t, uint64(84532), order.OriginChainID)
+		assert.Equal(t, uint64(23448591), order.DestinationChainID)
+
+		require.NoError(t, store.SetStatus("0x01", StatusRejected, "below margin"))
+		order, _ = store.Get("0x01")
+		assert.Equal(t, StatusRejected, order.Status)
+		assert.Equal(t, "below margin", order.Reason)
+
+		// Observing again does not reset the record
+		require.NoError(t, store.Observe(testArgs("0x01")))
+		order, _ = store.Get("0x01")
+		assert.Equal(t, StatusRejected, order.Status)
+	})
+
+	t.Run("unknown_order", func(t *testing.T) {
+		store, err := NewStore(filepath.Join(t.TempDir(), "orders.json"))
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, store.SetStatus("0xmissing", StatusFilled, ""), ErrOrderNotFound)
+	})
+
+	t.Run("persists_across_reopen", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nested", "orders.json")
+		store, err := NewStore(path)
+		require.NoError(t, err)
+		require.NoError(t, store.Observe(testArgs("0x01")))
+		require.NoError(t, store.SetStatus("0x01", StatusFilled, ""))
+
+		reopened, err := NewStore(path)
+		require.NoError(t, err)
+		order, ok := reopened.Get("0x01")
+		require.True(t, ok)
+		assert.Equal(t, StatusFilled, order.Status)
+		require.NotNil(t, order.Args)
+		assert.Equal(t, "0x1234", order.Args.ResolvedOrder.FillInstructions[0].DestinationSettler)
+	})
+
+	t.Run("corrupted_file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "orders.json")
+		require.NoError(t, os.WriteFile(path, []byte("{not json"), 0600))
+
+		_, err := NewStore(path)
+		assert.Error(t, err)
+	})
+
+	t.Run("sample_least_recently_reconciled_first", func(t *testing.T) {
+		store, err := NewStore(filepath.Join(t.TempDir(), "orders.json"))
+		require.NoError(t, err)
+
+		for _, id := range []string{"0x01", "0x02", "0x03"} {
+			require.NoError(t, store.Observe(testArgs(id)))
+		}
+		require.NoError(t, store.Update("0x01", func(o *Order) { o.LastReconciledAt = time.Now() }))
+		require.NoError(t, store.SetStatus("0x03", StatusRejected, "blocked"))
+
+		sample := store.Sample(2, nil)
+		require.Len(t, sample, 2)
+		assert.Equal(t, "0x02", sample[0].OrderID)
+		assert.Equal(t, "0x03", sample[1].OrderID)
+
+		notRejected := store.Sample(10, func(o Order) bool { return o.Status != StatusRejected })
+		require.Len(t, notRejected, 2)
+		assert.Equal(t, "0x02", notRejected[0].OrderID)
+		assert.Equal(t, "0x01", notRejected[1].OrderID)
+	})
+
+	t.Run("accounting", func(t *testing.T) {
+		store, err := NewStore(filepath.Join(t.TempDir(), "orders.json"))
+		require.NoError(t, err)
+
+		args := testArgs("0x01")
+		args.ResolvedOrder.MaxSpent = []types.Output{{Token: "0xout", Amount: big.NewInt(90), ChainID: big.NewInt(23448591)}}
+		args.ResolvedOrder.MinReceived = []types.Output{{Token: "0xin", Amount: big.NewInt(100), ChainID: big.NewInt(84532)}}
+		require.NoError(t, store.Observe(args))
+
+		// Approvals alone do not mean the solver paid the output
+		require.NoError(t, store.AddCost("0x01", TxCost{Kind: TxKindApprove, Fee: big.NewInt(1), FeeUnit: "WEI"}))
+		order, _ := store.Get("0x01")
+		assert.False(t, order.FilledByUs())
+		assert.Empty(t, order.OutputPaid)
+
+		require.NoError(t, store.AddCost("0x01", TxCost{Kind: TxKindFill, Fee: big.NewInt(5), FeeUnit: "WEI"}))
+		require.NoError(t, store.RecordInputReceived("0x01"))
+		order, _ = store.Get("0x01")
+		assert.True(t, order.FilledByUs())
+		assert.Len(t, order.Costs, 2)
+		require.NoError(t, store.AddCost("0x01", TxCost{Kind: TxKindFill, TxHash: "0xAbC", Fee: big.NewInt(1), FeeUnit: "WEI"}))
+		order, _ = store.Get("0x01")
+		assert.True(t, order.FilledInTx("0xabc"))
+		assert.False(t, order.FilledInTx("0xdef"))
+		require.Len(t, order.OutputPaid, 1)
+		assert.Equal(t, uint64(23448591), order.OutputPaid[0].ChainID)
+		assert.Equal(t, big.NewInt(90), order.OutputPaid[0].Amount)
+		require.Len(t, order.InputReceived, 1)
+		assert.Equal(t, big.NewInt(100), order.InputReceived[0].Amount)
+	})
+
+	t.Run("broadcast_txs", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "orders.json")
+		store, err := NewStore(path)
+		require.NoError(t, err)
+		require.NoError(t, store.Observe(testArgs("0x01")))
+
+		sentAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		require.NoError(t, store.RecordTx("0x01", OrderTx{ChainID: 23448591, Kind: TxKindFill, TxHash: "0xAbC", SentAt: sentAt}))
+		require.NoError(t, store.RecordTx("0x01", OrderTx{ChainID: 23448591, Kind: TxKindFill, TxHash: "0xabc", SentAt: sentAt}))
+		require.NoError(t, store.RecordTx("0x01", OrderTx{ChainID: 23448591, Kind: TxKindSettle, TxHash: "0xdef", SentAt: sentAt}))
+		assert.ErrorIs(t, store.RecordTx("0xmissing", OrderTx{TxHash: "0x01"}), ErrOrderNotFound)
+
+		reopened, err := NewStore(path)
+		require.NoError(t, err)
+		order, _ := reopened.Get("0x01")
+		assert.Equal(t, []OrderTx{
+			{ChainID: 23448591, Kind: TxKindFill, TxHash: "0xAbC", SentAt: sentAt},
+			{ChainID: 23448591, Kind: TxKindSettle, TxHash: "0xdef", SentAt: sentAt},
+		}, order.Txs)
+	})
+
+	t.Run("origin_events", func(t *testing.T) {
+		store, err := NewStore(filepath.Join(t.TempDir(), "orders.json"))
+		require.NoError(t, err)
+		now := time.Unix(1_700_000_000, 0)
+		store.now = func() time.Time { return now }
+
+		args := testArgs("0x01")
+		args.ResolvedOrder.MinReceived = []types.Output{{Token: "0xin", Amount: big.NewInt(100), ChainID: big.NewInt(84532)}}
+		require.NoError(t, store.Observe(args))
+		require.NoError(t, store.Observe(testArgs("0x02")))
+
+		require.NoError(t, store.RecordOriginSettled("0x01", "0xaa"))
+		order, _ := store.Get("0x01")
+		assert.Equal(t, "SETTLED", order.OriginStatus)
+		assert.Equal(t, "0xaa", order.OriginEventTxHash)
+		assert.Equal(t, now, order.ProceedsReceivedAt)
+		require.Len(t, order.InputReceived, 1)
+		assert.Equal(t, big.NewInt(100), order.InputReceived[0].Amount)
+
+		require.NoError(t, store.RecordOriginRefunded("0x02", "0xbb"))
+		order, _ = store.Get("0x02")
+		assert.Equal(t, "REFUNDED", order.OriginStatus)
+		assert.Empty(t, order.InputReceived)
+		assert.True(t, order.ProceedsReceivedAt.IsZero())
+
+		assert.ErrorIs(t, store.RecordOriginSettled("0x03", "0xcc"), ErrOrderNotFound)
+
+		// Proceeds are pending for orders we filled and settled until the origin releases them
+		require.NoError(t, store.AddCost("0x02", TxCost{Kind: TxKindFill}))
+		require.NoError(t, store.SetStatus("0x02", StatusSettled, ""))
+		order, _ = store.Get("0x02")
+		assert.True(t, order.ProceedsPending())
+		require.NoError(t, store.FlagProceedsOverdue("0x02"))
+		now = now.Add(time.Hour)
+		require.NoError(t, store.FlagProceedsOverdue("0x02"))
+		order, _ = store.Get("0x02")
+		assert.Equal(t, now.Add(-time.Hour), order.ProceedsOverdueAt, "first flag is kept")
+		require.NoError(t, store.RecordOriginSettled("0x02", ""))
+		order, _ = store.Get("0x02")
+		assert.False(t, order.ProceedsPending())
+		assert.Equal(t, "0xbb", order.OriginEventTxHash, "status read from the router keeps the event tx")
+	})
+
+	t.Run("status_timestamps", func(t *testing.T) {
+		store, err := NewStore(filepath.Join(t.TempDir(), "orders.json"))
+		require.NoError(t, err)
+		now := time.Unix(1_700_000_000, 0)
+		store.now = func() time.Time { return now }
+
+		require.NoError(t, store.Observe(testArgs("0x01")))
+		require.NoError(t, store.SetStatus("0x01", StatusFilled, ""))
+		now = now.Add(time.Minute)
+		require.NoError(t, store.SetStatus("0x01", StatusSettled, ""))
+
+		order, _ := store.Get("0x01")
+		assert.Equal(t, time.Unix(1_700_000_000, 0), order.FilledAt)
+		assert.Equal(t, now, order.SettledAt)
+	})
+
+	t.Run("reject_reason", func(t *testing.T) {
+		store, err := NewStore(filepath.Join(t.TempDir(), "orders.json"))
+		require.NoError(t, err)
+		require.NoError(t, store.Observe(testArgs("0x01")))
+
+		require.NoError(t, store.Reject("0x01", "insufficient_balance", "Insufficient balance for token 0xa1"))
+		order, _ := store.Get("0x01")
+		assert.Equal(t, StatusRejected, order.Status)
+		assert.Equal(t, "insufficient_balance", order.RejectReason)
+		assert.Equal(t, "Insufficient balance for token 0xa1", order.Reason)
+
+		// A later fill of the order (e.g. a replay) clears the code
+		require.NoError(t, store.SetStatus("0x01", StatusFilled, ""))
+		order, _ = store.Get("0x01")
+		assert.Empty(t, order.RejectReason)
+	})
+}
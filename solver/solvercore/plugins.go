@@ -0,0 +1,80 @@
+package solvercore
+
+// Module: Plugin registration
+// - Lets programs embedding the solver as a library add their own solvers and listeners without
+//   forking the repository (see examples/plugin)
+// - RegisterSolver adds a solver next to hyperlane7683; it is started with the others and can use
+//   the manager's clients and signers
+// - RegisterListenerFactory adds a listener whose events are handled by the Hyperlane7683 solver,
+//   for a new chain or in place of the built-in listener of an existing network
+// - AddRule adds a custom validation rule to the Hyperlane7683 solver, evaluated after the default rules
+// - Registrations must happen before Start (or InitializeSolvers)
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+)
+
+// SolverFactory starts a registered solver; the returned shutdown func (may be nil) runs on Shutdown
+type SolverFactory func(ctx context.Context, sm *SolverManager) (base.ShutdownFunc, error)
+
+// ListenerFactory creates the listener of a network; the manager starts it with the Hyperlane7683
+// event handler
+type ListenerFactory func(ctx context.Context, sm *SolverManager, networkName string) (base.Listener, error)
+
+// RegisterSolver adds a solver under a new name, enabled by default
+func (sm *SolverManager) RegisterSolver(name string, factory SolverFactory) error {
+	if name == "" || factory == nil {
+		return fmt.Errorf("solver name and factory are required")
+	}
+	if _, exists := sm.solverRegistry[name]; exists {
+		return fmt.Errorf("solver %s already registered", name)
+	}
+	sm.solverRegistry[name] = SolverConfig{Enabled: true, Options: map[string]interface{}{}}
+	sm.solverFactories[name] = factory
+	return nil
+}
+
+// RegisterListenerFactory adds the listener of a network; for a built-in network it replaces the
+// default listener
+func (sm *SolverManager) RegisterListenerFactory(networkName string, factory ListenerFactory) error {
+	if networkName == "" || factory == nil {
+		return fmt.Errorf("network name and listener factory are required")
+	}
+	if _, exists := sm.listenerFactories[networkName]; exists {
+		return fmt.Errorf("listener of %s already registered", networkName)
+	}
+	sm.listenerFactories[networkName] = factory
+	return nil
+}
+
+// AddRule adds a custom validation rule to the Hyperlane7683 solver
+func (sm *SolverManager) AddRule(rule contracts.Rule) {
+	sm.rules = append(sm.rules, rule)
+}
+
+// addCustomRules hands the custom rules to a Hyperlane7683 solver
+func (sm *SolverManager) addCustomRules(solver *contracts.Hyperlane7683Solver) {
+	for _, rule := range sm.rules {
+		solver.AddRule(rule)
+	}
+}
+
+// allListenerSources returns the built-in listener sources followed by the networks that only
+// have a registered listener, sorted by name
+func (sm *SolverManager) allListenerSources() []string {
+	sources := listenerSources()
+	var registered []string
+	for name := range sm.listenerFactories {
+		if !slices.Contains(sources, name) {
+			registered = append(registered, name)
+		}
+	}
+	sort.Strings(registered)
+	return append(sources, registered...)
+}
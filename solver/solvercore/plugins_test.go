@@ -0,0 +1,68 @@
+package solvercore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterSolver(t *testing.T) {
+	noop := func(context.Context, *SolverManager) (base.ShutdownFunc, error) { return nil, nil }
+
+	t.Run("registered solver starts and stops with the manager", func(t *testing.T) {
+		sm := NewSolverManager(&config.Config{})
+		stopped := false
+		var started *SolverManager
+		require.NoError(t, sm.RegisterSolver("custom", func(_ context.Context, manager *SolverManager) (base.ShutdownFunc, error) {
+			started = manager
+			return func() { stopped = true }, nil
+		}))
+		assert.True(t, sm.GetSolverStatus()["custom"])
+
+		require.NoError(t, sm.initializeSolver(context.Background(), "custom"))
+		assert.Same(t, sm, started)
+		sm.Shutdown()
+		assert.True(t, stopped)
+	})
+
+	t.Run("factory errors are returned", func(t *testing.T) {
+		sm := NewSolverManager(&config.Config{})
+		require.NoError(t, sm.RegisterSolver("broken", func(context.Context, *SolverManager) (base.ShutdownFunc, error) {
+			return nil, errors.New("no RPC")
+		}))
+		assert.EqualError(t, sm.initializeSolver(context.Background(), "broken"), "no RPC")
+		assert.Empty(t, sm.activeShutdowns)
+	})
+
+	t.Run("names must be new", func(t *testing.T) {
+		sm := NewSolverManager(&config.Config{})
+		assert.Error(t, sm.RegisterSolver("hyperlane7683", noop))
+		require.NoError(t, sm.RegisterSolver("custom", noop))
+		assert.Error(t, sm.RegisterSolver("custom", noop))
+		assert.Error(t, sm.RegisterSolver("", noop))
+		assert.Error(t, sm.RegisterSolver("nil", nil))
+	})
+}
+
+func TestRegisterListenerFactory(t *testing.T) {
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+
+	factory := func(context.Context, *SolverManager, string) (base.Listener, error) { return nil, nil }
+	sm := NewSolverManager(&config.Config{})
+	require.NoError(t, sm.RegisterListenerFactory("Solana", factory))
+	require.NoError(t, sm.RegisterListenerFactory("Base", factory))
+	require.NoError(t, sm.RegisterListenerFactory("Aptos", factory))
+	assert.Error(t, sm.RegisterListenerFactory("Base", factory))
+	assert.Error(t, sm.RegisterListenerFactory("Sui", nil))
+
+	// Registered built-in networks keep their place, new ones follow sorted by name
+	assert.Equal(t, []string{"Base", "Optimism", "Arbitrum", "Ethereum", "Starknet", "Aptos", "Solana"},
+		sm.allListenerSources())
+}
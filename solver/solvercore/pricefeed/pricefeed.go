@@ -0,0 +1,87 @@
+// Package pricefeed values token amounts in a quote currency shared by all tokens (e.g. USD), so
+// orders whose input and output tokens differ can be compared.
+//
+// Prices are per whole token with config.NotionalDecimals decimals. The default feed reads the
+// "price" of each token in the token registry, which is reloaded with the runtime config; a live
+// source (an oracle, an exchange API) replaces it through SetDefault before the solver starts.
+//
+// Usage:
+//
+//	value, err := pricefeed.Value(ctx, pricefeed.Default(), chainID, token, units)
+package pricefeed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// ErrNoPrice is returned for tokens the feed has no price for
+var ErrNoPrice = errors.New("no price")
+
+// NativeToken is the address a chain's native gas token (e.g. ETH on EVM chains) is priced and
+// registered under, since it has no contract
+const NativeToken = "0x0"
+
+// Feed returns token prices
+type Feed interface {
+	// Price returns the value of one whole token of address on chainID, with config.NotionalDecimals decimals
+	Price(ctx context.Context, chainID uint64, address string) (*big.Int, error)
+}
+
+// RegistryFeed prices tokens with the "price" of their token registry entry
+type RegistryFeed struct{}
+
+// Price implements Feed
+func (RegistryFeed) Price(_ context.Context, chainID uint64, address string) (*big.Int, error) {
+	token, ok := config.ActiveRuntime().TokenRegistry().Lookup(chainID, address)
+	if !ok {
+		return nil, fmt.Errorf("%w: token %s on chain %d is not in the token registry", ErrNoPrice, address, chainID)
+	}
+	price := token.PriceUnits()
+	if price == nil {
+		return nil, fmt.Errorf("%w: token %s on chain %d has no price in the token registry", ErrNoPrice, token.Symbol, chainID)
+	}
+	return price, nil
+}
+
+var (
+	defaultFeed   Feed = RegistryFeed{}
+	defaultFeedMu sync.RWMutex
+)
+
+// Default returns the feed used by the solver's rules
+func Default() Feed {
+	defaultFeedMu.RLock()
+	defer defaultFeedMu.RUnlock()
+	return defaultFeed
+}
+
+// SetDefault replaces the feed used by the solver's rules; nil restores the registry feed
+func SetDefault(feed Feed) {
+	defaultFeedMu.Lock()
+	defer defaultFeedMu.Unlock()
+	if feed == nil {
+		feed = RegistryFeed{}
+	}
+	defaultFeed = feed
+}
+
+// Value returns the value of units base units of a token, with config.NotionalDecimals decimals.
+// The token's decimals come from the registry or on-chain discovery.
+func Value(ctx context.Context, feed Feed, chainID uint64, address string, units *big.Int) (*big.Int, error) {
+	token, ok := config.LookupToken(chainID, address)
+	if !ok {
+		return nil, fmt.Errorf("%w: decimals of token %s on chain %d are unknown", ErrNoPrice, address, chainID)
+	}
+	price, err := feed.Price(ctx, chainID, address)
+	if err != nil {
+		return nil, err
+	}
+	value := new(big.Int).Mul(units, price)
+	return value.Quo(value, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(token.Decimals)), nil)), nil
+}
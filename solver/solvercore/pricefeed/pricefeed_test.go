@@ -0,0 +1,61 @@
+package pricefeed
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+type fixedFeed struct{ price *big.Int }
+
+func (f fixedFeed) Price(context.Context, uint64, string) (*big.Int, error) {
+	return f.price, nil
+}
+
+func TestRegistryFeed(t *testing.T) {
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+	require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+		{ChainID: 84532, Address: "0xc0", Symbol: "USDC", Decimals: 6, Price: "1.25"},
+		{ChainID: 84532, Address: "0xe0", Symbol: "CAT", Decimals: 18},
+	}}))
+	ctx := context.Background()
+
+	t.Run("prices come from the registry", func(t *testing.T) {
+		price, err := RegistryFeed{}.Price(ctx, 84532, "0xc0")
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(1_250_000_000_000_000_000), price)
+	})
+
+	t.Run("unpriced and unknown tokens have no price", func(t *testing.T) {
+		_, err := RegistryFeed{}.Price(ctx, 84532, "0xe0")
+		assert.ErrorIs(t, err, ErrNoPrice)
+		_, err = RegistryFeed{}.Price(ctx, 1, "0xc0")
+		assert.ErrorIs(t, err, ErrNoPrice)
+	})
+
+	t.Run("values are scaled by the token decimals", func(t *testing.T) {
+		// 2 USDC at 1.25
+		value, err := Value(ctx, RegistryFeed{}, 84532, "0xc0", big.NewInt(2_000_000))
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(2_500_000_000_000_000_000), value)
+
+		_, err = Value(ctx, fixedFeed{price: big.NewInt(1)}, 84532, "0xf0", big.NewInt(1))
+		assert.ErrorIs(t, err, ErrNoPrice, "decimals of unknown tokens")
+	})
+}
+
+func TestSetDefault(t *testing.T) {
+	defer SetDefault(nil)
+
+	assert.Equal(t, RegistryFeed{}, Default())
+	feed := fixedFeed{price: big.NewInt(1)}
+	SetDefault(feed)
+	assert.Equal(t, feed, Default())
+	SetDefault(nil)
+	assert.Equal(t, RegistryFeed{}, Default())
+}
@@ -0,0 +1,243 @@
+package replay
+
+// Module: Recording and replaying EVM clients
+// - RecordingEVMClient forwards to a live client and records every call
+// - ReplayEVMClient answers the same calls from a Recording
+// - Sent transactions are matched by hash; signing is deterministic, so a replayed fill
+//   matches its recording as long as the inputs (nonce, gas price, calldata) do
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// Recorded EVM methods
+const (
+	MethodEVMChainID         = "evm.ChainID"
+	MethodEVMBlockNumber     = "evm.BlockNumber"
+	MethodEVMCodeAt          = "evm.CodeAt"
+	MethodEVMBalanceAt       = "evm.BalanceAt"
+	MethodEVMPendingCodeAt   = "evm.PendingCodeAt"
+	MethodEVMCall            = "evm.CallContract"
+	MethodEVMHeaderByNumber  = "evm.HeaderByNumber"
+	MethodEVMPendingNonceAt  = "evm.PendingNonceAt"
+	MethodEVMGasPrice        = "evm.SuggestGasPrice"
+	MethodEVMGasTipCap       = "evm.SuggestGasTipCap"
+	MethodEVMEstimateGas     = "evm.EstimateGas"
+	MethodEVMSendTransaction = "evm.SendTransaction"
+	MethodEVMReceipt         = "evm.TransactionReceipt"
+	MethodEVMFilterLogs      = "evm.FilterLogs"
+)
+
+// evmBackend is the client surface the solver uses (hyperlane7683.EVMClient)
+type evmBackend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	ChainID(ctx context.Context) (*big.Int, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// callRequest identifies an eth_call or eth_estimateGas
+type callRequest struct {
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to,omitempty"`
+	Data  hexutil.Bytes   `json:"data,omitempty"`
+	Value *hexutil.Big    `json:"value,omitempty"`
+	Block *hexutil.Big    `json:"block,omitempty"`
+}
+
+func newCallRequest(msg ethereum.CallMsg, block *big.Int) callRequest {
+	return callRequest{From: msg.From, To: msg.To, Data: msg.Data, Value: (*hexutil.Big)(msg.Value), Block: (*hexutil.Big)(block)}
+}
+
+// codeRequest identifies an eth_getCode or eth_getBalance
+type codeRequest struct {
+	Address common.Address `json:"address"`
+	Block   *hexutil.Big   `json:"block,omitempty"`
+}
+
+// RecordingEVMClient records the traffic of an EVM client
+type RecordingEVMClient struct {
+	inner    evmBackend
+	chainID  uint64
+	recorder *Recorder
+}
+
+// NewRecordingEVMClient wraps inner, recording its traffic under chainID
+func NewRecordingEVMClient(inner evmBackend, chainID uint64, recorder *Recorder) *RecordingEVMClient {
+	return &RecordingEVMClient{inner: inner, chainID: chainID, recorder: recorder}
+}
+
+func (c *RecordingEVMClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return record(c.recorder, c.chainID, MethodEVMChainID, nil, func() (*big.Int, error) {
+		return c.inner.ChainID(ctx)
+	})
+}
+
+func (c *RecordingEVMClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return record(c.recorder, c.chainID, MethodEVMBlockNumber, nil, func() (uint64, error) {
+		return c.inner.BlockNumber(ctx)
+	})
+}
+
+func (c *RecordingEVMClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	code, err := record(c.recorder, c.chainID, MethodEVMCodeAt, codeRequest{contract, (*hexutil.Big)(blockNumber)}, func() (hexutil.Bytes, error) {
+		return c.inner.CodeAt(ctx, contract, blockNumber)
+	})
+	return code, err
+}
+
+func (c *RecordingEVMClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return record(c.recorder, c.chainID, MethodEVMBalanceAt, codeRequest{account, (*hexutil.Big)(blockNumber)}, func() (*big.Int, error) {
+		return c.inner.BalanceAt(ctx, account, blockNumber)
+	})
+}
+
+func (c *RecordingEVMClient) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	code, err := record(c.recorder, c.chainID, MethodEVMPendingCodeAt, codeRequest{Address: account}, func() (hexutil.Bytes, error) {
+		return c.inner.PendingCodeAt(ctx, account)
+	})
+	return code, err
+}
+
+func (c *RecordingEVMClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	out, err := record(c.recorder, c.chainID, MethodEVMCall, newCallRequest(msg, blockNumber), func() (hexutil.Bytes, error) {
+		return c.inner.CallContract(ctx, msg, blockNumber)
+	})
+	return out, err
+}
+
+func (c *RecordingEVMClient) HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error) {
+	return record(c.recorder, c.chainID, MethodEVMHeaderByNumber, (*hexutil.Big)(number), func() (*gethtypes.Header, error) {
+		return c.inner.HeaderByNumber(ctx, number)
+	})
+}
+
+func (c *RecordingEVMClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return record(c.recorder, c.chainID, MethodEVMPendingNonceAt, account, func() (uint64, error) {
+		return c.inner.PendingNonceAt(ctx, account)
+	})
+}
+
+func (c *RecordingEVMClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return record(c.recorder, c.chainID, MethodEVMGasPrice, nil, func() (*big.Int, error) {
+		return c.inner.SuggestGasPrice(ctx)
+	})
+}
+
+func (c *RecordingEVMClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return record(c.recorder, c.chainID, MethodEVMGasTipCap, nil, func() (*big.Int, error) {
+		return c.inner.SuggestGasTipCap(ctx)
+	})
+}
+
+func (c *RecordingEVMClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return record(c.recorder, c.chainID, MethodEVMEstimateGas, newCallRequest(call, nil), func() (uint64, error) {
+		return c.inner.EstimateGas(ctx, call)
+	})
+}
+
+func (c *RecordingEVMClient) SendTransaction(ctx context.Context, tx *gethtypes.Transaction) error {
+	_, err := record(c.recorder, c.chainID, MethodEVMSendTransaction, tx.Hash(), func() (struct{}, error) {
+		return struct{}{}, c.inner.SendTransaction(ctx, tx)
+	})
+	return err
+}
+
+func (c *RecordingEVMClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*gethtypes.Receipt, error) {
+	return record(c.recorder, c.chainID, MethodEVMReceipt, txHash, func() (*gethtypes.Receipt, error) {
+		return c.inner.TransactionReceipt(ctx, txHash)
+	})
+}
+
+func (c *RecordingEVMClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]gethtypes.Log, error) {
+	return record(c.recorder, c.chainID, MethodEVMFilterLogs, query, func() ([]gethtypes.Log, error) {
+		return c.inner.FilterLogs(ctx, query)
+	})
+}
+
+// SubscribeFilterLogs is forwarded unrecorded; the solver polls with FilterLogs
+func (c *RecordingEVMClient) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- gethtypes.Log) (ethereum.Subscription, error) {
+	return c.inner.SubscribeFilterLogs(ctx, query, ch)
+}
+
+// ReplayEVMClient answers EVM calls from a recording
+type ReplayEVMClient struct {
+	recording *Recording
+	chainID   uint64
+}
+
+// EVMClient returns a client replaying the traffic recorded for chainID
+func (r *Recording) EVMClient(chainID uint64) *ReplayEVMClient {
+	return &ReplayEVMClient{recording: r, chainID: chainID}
+}
+
+func (c *ReplayEVMClient) ChainID(_ context.Context) (*big.Int, error) {
+	return replayed[*big.Int](c.recording, c.chainID, MethodEVMChainID, nil)
+}
+
+func (c *ReplayEVMClient) BlockNumber(_ context.Context) (uint64, error) {
+	return replayed[uint64](c.recording, c.chainID, MethodEVMBlockNumber, nil)
+}
+
+func (c *ReplayEVMClient) CodeAt(_ context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return replayed[hexutil.Bytes](c.recording, c.chainID, MethodEVMCodeAt, codeRequest{contract, (*hexutil.Big)(blockNumber)})
+}
+
+func (c *ReplayEVMClient) BalanceAt(_ context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return replayed[*big.Int](c.recording, c.chainID, MethodEVMBalanceAt, codeRequest{account, (*hexutil.Big)(blockNumber)})
+}
+
+func (c *ReplayEVMClient) PendingCodeAt(_ context.Context, account common.Address) ([]byte, error) {
+	return replayed[hexutil.Bytes](c.recording, c.chainID, MethodEVMPendingCodeAt, codeRequest{Address: account})
+}
+
+func (c *ReplayEVMClient) CallContract(_ context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return replayed[hexutil.Bytes](c.recording, c.chainID, MethodEVMCall, newCallRequest(msg, blockNumber))
+}
+
+func (c *ReplayEVMClient) HeaderByNumber(_ context.Context, number *big.Int) (*gethtypes.Header, error) {
+	return replayed[*gethtypes.Header](c.recording, c.chainID, MethodEVMHeaderByNumber, (*hexutil.Big)(number))
+}
+
+func (c *ReplayEVMClient) PendingNonceAt(_ context.Context, account common.Address) (uint64, error) {
+	return replayed[uint64](c.recording, c.chainID, MethodEVMPendingNonceAt, account)
+}
+
+func (c *ReplayEVMClient) SuggestGasPrice(_ context.Context) (*big.Int, error) {
+	return replayed[*big.Int](c.recording, c.chainID, MethodEVMGasPrice, nil)
+}
+
+func (c *ReplayEVMClient) SuggestGasTipCap(_ context.Context) (*big.Int, error) {
+	return replayed[*big.Int](c.recording, c.chainID, MethodEVMGasTipCap, nil)
+}
+
+func (c *ReplayEVMClient) EstimateGas(_ context.Context, call ethereum.CallMsg) (uint64, error) {
+	return replayed[uint64](c.recording, c.chainID, MethodEVMEstimateGas, newCallRequest(call, nil))
+}
+
+func (c *ReplayEVMClient) SendTransaction(_ context.Context, tx *gethtypes.Transaction) error {
+	_, err := replayed[struct{}](c.recording, c.chainID, MethodEVMSendTransaction, tx.Hash())
+	return err
+}
+
+func (c *ReplayEVMClient) TransactionReceipt(_ context.Context, txHash common.Hash) (*gethtypes.Receipt, error) {
+	return replayed[*gethtypes.Receipt](c.recording, c.chainID, MethodEVMReceipt, txHash)
+}
+
+func (c *ReplayEVMClient) FilterLogs(_ context.Context, query ethereum.FilterQuery) ([]gethtypes.Log, error) {
+	return replayed[[]gethtypes.Log](c.recording, c.chainID, MethodEVMFilterLogs, query)
+}
+
+// SubscribeFilterLogs is not supported during replay
+func (c *ReplayEVMClient) SubscribeFilterLogs(_ context.Context, _ ethereum.FilterQuery, _ chan<- gethtypes.Log) (ethereum.Subscription, error) {
+	return nil, errors.New("replay: log subscriptions are not supported")
+}
@@ -0,0 +1,96 @@
+package replay
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// evmTraffic drives the calls the solver makes: token and native balance reads, a log query and a mined transaction
+func evmTraffic(t *testing.T, client evmBackend, opts *bind.TransactOpts, token, hub common.Address) ([]byte, []gethtypes.Log, *gethtypes.Receipt) {
+	t.Helper()
+	ctx := context.Background()
+	balanceOf := chainmock.Selector("balanceOf(address)")
+
+	balance, err := client.CallContract(ctx, ethereum.CallMsg{To: &token, Data: balanceOf[:]}, nil)
+	require.NoError(t, err)
+	native, err := client.BalanceAt(ctx, opts.From, nil)
+	require.NoError(t, err)
+	require.Zero(t, native.Sign())
+
+	logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: big.NewInt(1),
+		ToBlock:   big.NewInt(10),
+		Addresses: []common.Address{hub},
+	})
+	require.NoError(t, err)
+
+	tx, err := bind.NewBoundContract(hub, abi.ABI{}, client, client, client).RawTransact(opts, []byte{0x01})
+	require.NoError(t, err)
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	require.NoError(t, err)
+
+	return balance, logs, receipt
+}
+
+// TestEVMRecordAndReplay tests that replayed EVM traffic matches the recorded traffic
+func TestEVMRecordAndReplay(t *testing.T) {
+	const chainID = 31337
+	token := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	hub := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	backend := chainmock.NewEVMBackend(chainID)
+	backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), func(msg ethereum.CallMsg) ([]byte, error) {
+		return common.LeftPadBytes(big.NewInt(42).Bytes(), 32), nil
+	})
+	backend.OnTransaction(hub, nil)
+	backend.AddLog(gethtypes.Log{Address: hub, Topics: []common.Hash{{0x01}}, Data: []byte{0xaa}, BlockNumber: 5})
+
+	path := filepath.Join(t.TempDir(), "evm.jsonl")
+	recorder, err := NewRecorder(path)
+	require.NoError(t, err)
+
+	opts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(chainID))
+	require.NoError(t, err)
+	wantBalance, wantLogs, wantReceipt := evmTraffic(t, NewRecordingEVMClient(backend, chainID, recorder), opts, token, hub)
+	require.NoError(t, recorder.Close())
+
+	recording, err := Load(path)
+	require.NoError(t, err)
+
+	// Same key and inputs sign the same transaction, so the replayed send matches its recording
+	opts, err = bind.NewKeyedTransactorWithChainID(key, big.NewInt(chainID))
+	require.NoError(t, err)
+	balance, logs, receipt := evmTraffic(t, recording.EVMClient(chainID), opts, token, hub)
+
+	assert.Equal(t, wantBalance, balance)
+	require.Len(t, logs, 1)
+	assert.Equal(t, wantLogs[0].Data, logs[0].Data)
+	assert.Equal(t, wantLogs[0].BlockNumber, logs[0].BlockNumber)
+	assert.Equal(t, wantReceipt.Status, receipt.Status)
+	assert.Equal(t, wantReceipt.TxHash, receipt.TxHash)
+	assert.Len(t, backend.Sent(), 1, "replay must not reach the backend")
+
+	t.Run("other chain is not recorded", func(t *testing.T) {
+		_, err := recording.EVMClient(1).BlockNumber(context.Background())
+		assert.ErrorIs(t, err, ErrNotRecorded)
+	})
+
+	t.Run("subscriptions are unsupported", func(t *testing.T) {
+		_, err := recording.EVMClient(chainID).SubscribeFilterLogs(context.Background(), ethereum.FilterQuery{}, nil)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,258 @@
+// Package replay records the solver's chain traffic and replays it without network.
+//
+// A Recorder wraps the EVM and Starknet clients used by the solver and appends every
+// request and response to a JSON lines file. A Recording loads such a file and
+// provides clients that answer from it, so the observed events can be pushed through
+// the decoding and decision logic again, deterministically, as a regression test
+// against historical traffic.
+//
+// Replay clients match requests by chain ID, method and request. Repeated requests
+// are answered in recorded order; once exhausted the last response is repeated, so
+// extra polling during replay does not fail. Requests that were never recorded fail
+// with ErrNotRecorded.
+//
+// Settings:
+// - SOLVER_RECORD_FILE: when set, the solver records its chain traffic to this file
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum"
+)
+
+const (
+	defaultDirPerms = 0755
+	// Largest JSON line accepted by Load; event pages and receipts can be large
+	maxLineSize = 64 * 1024 * 1024
+)
+
+// ErrNotRecorded is returned by replay clients for requests missing from the recording
+var ErrNotRecorded = errors.New("request not recorded")
+
+// Entry is one recorded request and its outcome
+type Entry struct {
+	Seq      uint64          `json:"seq"`
+	ChainID  uint64          `json:"chainId"`
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	RPCCode  int             `json:"rpcCode,omitempty"` // Starknet RPC error code, 0 otherwise
+}
+
+// RecordFileFromEnv returns SOLVER_RECORD_FILE, empty when recording is disabled
+func RecordFileFromEnv() string {
+	return os.Getenv("SOLVER_RECORD_FILE")
+}
+
+// Recorder appends entries to a JSON lines file
+type Recorder struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	seq  uint64
+}
+
+// NewRecorder creates (or truncates) the recording file at path
+func NewRecorder(path string) (*Recorder, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, defaultDirPerms); err != nil {
+			return nil, fmt.Errorf("failed to create recording directory: %w", err)
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording file: %w", err)
+	}
+	return &Recorder{path: path, file: file}, nil
+}
+
+// Path returns the recording file
+func (r *Recorder) Path() string {
+	return r.path
+}
+
+// Record appends one request; the response is only stored when callErr is nil
+func (r *Recorder) Record(chainID uint64, method string, request, response interface{}, callErr error) error {
+	entry := Entry{ChainID: chainID, Method: method}
+
+	var err error
+	if request != nil {
+		if entry.Request, err = json.Marshal(request); err != nil {
+			return fmt.Errorf("failed to encode %s request: %w", method, err)
+		}
+	}
+	if callErr != nil {
+		entry.Error = callErr.Error()
+		var rpcErr *rpc.RPCError
+		if errors.As(callErr, &rpcErr) {
+			entry.RPCCode = rpcErr.Code
+			entry.Error = rpcErr.Message
+		}
+	} else if response != nil {
+		if entry.Response, err = json.Marshal(response); err != nil {
+			return fmt.Errorf("failed to encode %s response: %w", method, err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	entry.Seq = r.seq
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s entry: %w", method, err)
+	}
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write %s entry: %w", method, err)
+	}
+	return nil
+}
+
+// Close flushes and closes the recording file
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.file.Sync(); err != nil {
+		r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
+
+// record runs call and records it; recording failures are logged, never returned
+func record[T any](r *Recorder, chainID uint64, method string, request interface{}, call func() (T, error)) (T, error) {
+	response, err := call()
+	if recErr := r.Record(chainID, method, request, response, err); recErr != nil {
+		fmt.Printf("⚠️  Failed to record %s: %v\n", method, recErr)
+	}
+	return response, err
+}
+
+// Recording serves recorded responses to replay clients
+type Recording struct {
+	mu      sync.Mutex
+	entries []Entry
+	queues  map[entryKey][]Entry
+	last    map[entryKey]Entry
+}
+
+type entryKey struct {
+	chainID uint64
+	method  string
+	request string
+}
+
+// Load reads a recording file
+func Load(path string) (*Recording, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid recording entry on line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	return NewRecording(entries), nil
+}
+
+// NewRecording builds a recording from entries, ordered by sequence number
+func NewRecording(entries []Entry) *Recording {
+	sorted := append([]Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Seq < sorted[j].Seq })
+
+	r := &Recording{
+		entries: sorted,
+		queues:  make(map[entryKey][]Entry),
+		last:    make(map[entryKey]Entry),
+	}
+	for _, entry := range sorted {
+		key := entryKey{chainID: entry.ChainID, method: entry.Method, request: string(entry.Request)}
+		r.queues[key] = append(r.queues[key], entry)
+	}
+	return r
+}
+
+// Entries returns all entries in recorded order
+func (r *Recording) Entries() []Entry {
+	return append([]Entry(nil), r.entries...)
+}
+
+// next returns the next recorded outcome of the request
+func (r *Recording) next(chainID uint64, method string, request interface{}) (Entry, error) {
+	var encoded []byte
+	if request != nil {
+		var err error
+		if encoded, err = json.Marshal(request); err != nil {
+			return Entry{}, fmt.Errorf("failed to encode %s request: %w", method, err)
+		}
+	}
+	key := entryKey{chainID: chainID, method: method, request: string(encoded)}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if queue := r.queues[key]; len(queue) > 0 {
+		r.queues[key] = queue[1:]
+		r.last[key] = queue[0]
+		return queue[0], nil
+	}
+	if entry, ok := r.last[key]; ok {
+		return entry, nil
+	}
+	return Entry{}, fmt.Errorf("%w: chain %d %s %s", ErrNotRecorded, chainID, method, encoded)
+}
+
+// replayed answers a request from the recording
+func replayed[T any](r *Recording, chainID uint64, method string, request interface{}) (T, error) {
+	var response T
+	entry, err := r.next(chainID, method, request)
+	if err != nil {
+		return response, err
+	}
+	if err := entry.err(); err != nil {
+		return response, err
+	}
+	if len(entry.Response) > 0 {
+		if err := json.Unmarshal(entry.Response, &response); err != nil {
+			return response, fmt.Errorf("invalid recorded %s response: %w", method, err)
+		}
+	}
+	return response, nil
+}
+
+// err rebuilds the recorded error, restoring the sentinels callers check for
+func (e Entry) err() error {
+	switch {
+	case e.RPCCode != 0:
+		return &rpc.RPCError{Code: e.RPCCode, Message: e.Error}
+	case e.Error == "":
+		return nil
+	case e.Error == ethereum.NotFound.Error():
+		return ethereum.NotFound
+	default:
+		return errors.New(e.Error)
+	}
+}
@@ -0,0 +1,99 @@
+package replay
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordTo writes entries through a Recorder and loads them back
+func recordTo(t *testing.T, record func(r *Recorder)) *Recording {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "nested", "traffic.jsonl")
+	recorder, err := NewRecorder(path)
+	require.NoError(t, err)
+	record(recorder)
+	require.NoError(t, recorder.Close())
+
+	recording, err := Load(path)
+	require.NoError(t, err)
+	return recording
+}
+
+// TestRecorderRoundTrip tests that recorded requests load back in order
+func TestRecorderRoundTrip(t *testing.T) {
+	recording := recordTo(t, func(r *Recorder) {
+		require.NoError(t, r.Record(1, "evm.BlockNumber", nil, uint64(10), nil))
+		require.NoError(t, r.Record(2, "starknet.Call", map[string]string{"to": "0x1"}, []string{"0x5"}, nil))
+		require.NoError(t, r.Record(1, "evm.BlockNumber", nil, nil, errors.New("rpc down")))
+	})
+
+	entries := recording.Entries()
+	require.Len(t, entries, 3)
+	assert.Equal(t, []uint64{1, 2, 3}, []uint64{entries[0].Seq, entries[1].Seq, entries[2].Seq})
+	assert.Equal(t, "starknet.Call", entries[1].Method)
+	assert.JSONEq(t, `{"to":"0x1"}`, string(entries[1].Request))
+	assert.Equal(t, "rpc down", entries[2].Error)
+	assert.Empty(t, entries[2].Response)
+}
+
+// TestRecordingNext tests how repeated and unknown requests are answered
+func TestRecordingNext(t *testing.T) {
+	recording := NewRecording([]Entry{
+		{Seq: 2, ChainID: 1, Method: "evm.BlockNumber", Response: []byte("11")},
+		{Seq: 1, ChainID: 1, Method: "evm.BlockNumber", Response: []byte("10")},
+	})
+
+	t.Run("recorded order then last response", func(t *testing.T) {
+		for _, want := range []uint64{10, 11, 11} {
+			got, err := replayed[uint64](recording, 1, "evm.BlockNumber", nil)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+	})
+
+	t.Run("unknown request", func(t *testing.T) {
+		_, err := replayed[uint64](recording, 2, "evm.BlockNumber", nil)
+		assert.ErrorIs(t, err, ErrNotRecorded)
+
+		_, err = replayed[uint64](recording, 1, "evm.BlockNumber", "latest")
+		assert.ErrorIs(t, err, ErrNotRecorded)
+	})
+}
+
+// TestEntryErr tests that recorded errors keep the sentinels callers check for
+func TestEntryErr(t *testing.T) {
+	assert.NoError(t, Entry{}.err())
+	assert.ErrorIs(t, Entry{Error: ethereum.NotFound.Error()}.err(), ethereum.NotFound)
+	assert.EqualError(t, Entry{Error: "rpc down"}.err(), "rpc down")
+
+	var rpcErr *rpc.RPCError
+	require.ErrorAs(t, Entry{Error: "Transaction hash not found", RPCCode: 29}.err(), &rpcErr)
+	assert.Equal(t, 29, rpcErr.Code)
+}
+
+// TestLoadErrors tests loading missing and malformed recordings
+func TestLoadErrors(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.jsonl"))
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "bad.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"seq\":1}\n\nnot json\n"), 0600))
+	_, err = Load(path)
+	assert.ErrorContains(t, err, "line 3")
+}
+
+// TestRecordFileFromEnv tests the recording setting
+func TestRecordFileFromEnv(t *testing.T) {
+	t.Setenv("SOLVER_RECORD_FILE", "")
+	assert.Empty(t, RecordFileFromEnv())
+
+	t.Setenv("SOLVER_RECORD_FILE", "traffic.jsonl")
+	assert.Equal(t, "traffic.jsonl", RecordFileFromEnv())
+}
@@ -0,0 +1,180 @@
+package replay
+
+// Module: Recording and replaying Starknet providers
+// - RecordingStarknetProvider forwards to a live provider and records the methods the solver uses
+// - ReplayStarknetProvider answers the same methods from a Recording
+// - Invokes and fee estimates are matched by sender, nonce and calldata rather than by the
+//   whole transaction, so the signature and resource bounds do not need to be reproduced
+//
+// Methods the solver does not use are forwarded unrecorded by RecordingStarknetProvider and
+// left to the embedded nil rpc.RPCProvider (which panics) by ReplayStarknetProvider.
+
+import (
+	"context"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// Recorded Starknet methods
+const (
+	MethodStarknetChainID     = "starknet.ChainID"
+	MethodStarknetBlockNumber = "starknet.BlockNumber"
+	MethodStarknetCall        = "starknet.Call"
+	MethodStarknetNonce       = "starknet.Nonce"
+	MethodStarknetEstimateFee = "starknet.EstimateFee"
+	MethodStarknetInvoke      = "starknet.AddInvokeTransaction"
+	MethodStarknetReceipt     = "starknet.TransactionReceipt"
+	MethodStarknetEvents      = "starknet.Events"
+)
+
+// starknetCallRequest identifies a starknet_call
+type starknetCallRequest struct {
+	Call  rpc.FunctionCall `json:"call"`
+	Block rpc.BlockID      `json:"block"`
+}
+
+// starknetNonceRequest identifies a starknet_getNonce
+type starknetNonceRequest struct {
+	Block   rpc.BlockID `json:"block"`
+	Address *felt.Felt  `json:"address"`
+}
+
+// invokeKey identifies an invoke by what the solver decided to send
+type invokeKey struct {
+	Sender   *felt.Felt   `json:"sender"`
+	Nonce    *felt.Felt   `json:"nonce"`
+	Calldata []*felt.Felt `json:"calldata"`
+}
+
+// invokeKeys returns the keys of fee estimation requests; other transaction types are kept whole
+func invokeKeys(requests []rpc.BroadcastTxn) []interface{} {
+	keys := make([]interface{}, len(requests))
+	for i, request := range requests {
+		switch txn := request.(type) {
+		case *rpc.BroadcastInvokeTxnV3:
+			keys[i] = invokeKey{Sender: txn.SenderAddress, Nonce: txn.Nonce, Calldata: txn.Calldata}
+		case rpc.BroadcastInvokeTxnV3:
+			keys[i] = invokeKey{Sender: txn.SenderAddress, Nonce: txn.Nonce, Calldata: txn.Calldata}
+		default:
+			keys[i] = request
+		}
+	}
+	return keys
+}
+
+// RecordingStarknetProvider records the traffic of a Starknet provider
+type RecordingStarknetProvider struct {
+	rpc.RPCProvider
+
+	chainID  uint64
+	recorder *Recorder
+}
+
+// NewRecordingStarknetProvider wraps inner, recording its traffic under chainID
+func NewRecordingStarknetProvider(inner rpc.RPCProvider, chainID uint64, recorder *Recorder) *RecordingStarknetProvider {
+	return &RecordingStarknetProvider{RPCProvider: inner, chainID: chainID, recorder: recorder}
+}
+
+func (p *RecordingStarknetProvider) ChainID(ctx context.Context) (string, error) {
+	return record(p.recorder, p.chainID, MethodStarknetChainID, nil, func() (string, error) {
+		return p.RPCProvider.ChainID(ctx)
+	})
+}
+
+func (p *RecordingStarknetProvider) BlockNumber(ctx context.Context) (uint64, error) {
+	return record(p.recorder, p.chainID, MethodStarknetBlockNumber, nil, func() (uint64, error) {
+		return p.RPCProvider.BlockNumber(ctx)
+	})
+}
+
+func (p *RecordingStarknetProvider) Call(ctx context.Context, call rpc.FunctionCall, blockID rpc.BlockID) ([]*felt.Felt, error) {
+	return record(p.recorder, p.chainID, MethodStarknetCall, starknetCallRequest{call, blockID}, func() ([]*felt.Felt, error) {
+		return p.RPCProvider.Call(ctx, call, blockID)
+	})
+}
+
+func (p *RecordingStarknetProvider) Nonce(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
+	return record(p.recorder, p.chainID, MethodStarknetNonce, starknetNonceRequest{blockID, contractAddress}, func() (*felt.Felt, error) {
+		return p.RPCProvider.Nonce(ctx, blockID, contractAddress)
+	})
+}
+
+func (p *RecordingStarknetProvider) EstimateFee(
+	ctx context.Context,
+	requests []rpc.BroadcastTxn,
+	simulationFlags []rpc.SimulationFlag,
+	blockID rpc.BlockID,
+) ([]rpc.FeeEstimation, error) {
+	return record(p.recorder, p.chainID, MethodStarknetEstimateFee, invokeKeys(requests), func() ([]rpc.FeeEstimation, error) {
+		return p.RPCProvider.EstimateFee(ctx, requests, simulationFlags, blockID)
+	})
+}
+
+func (p *RecordingStarknetProvider) AddInvokeTransaction(ctx context.Context, txn *rpc.BroadcastInvokeTxnV3) (rpc.AddInvokeTransactionResponse, error) {
+	return record(p.recorder, p.chainID, MethodStarknetInvoke, invokeKeys([]rpc.BroadcastTxn{txn})[0], func() (rpc.AddInvokeTransactionResponse, error) {
+		return p.RPCProvider.AddInvokeTransaction(ctx, txn)
+	})
+}
+
+func (p *RecordingStarknetProvider) TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	return record(p.recorder, p.chainID, MethodStarknetReceipt, transactionHash, func() (*rpc.TransactionReceiptWithBlockInfo, error) {
+		return p.RPCProvider.TransactionReceipt(ctx, transactionHash)
+	})
+}
+
+func (p *RecordingStarknetProvider) Events(ctx context.Context, input rpc.EventsInput) (*rpc.EventChunk, error) {
+	return record(p.recorder, p.chainID, MethodStarknetEvents, input, func() (*rpc.EventChunk, error) {
+		return p.RPCProvider.Events(ctx, input)
+	})
+}
+
+// ReplayStarknetProvider answers Starknet calls from a recording
+type ReplayStarknetProvider struct {
+	rpc.RPCProvider // nil; only the recorded methods are implemented
+
+	recording *Recording
+	chainID   uint64
+}
+
+// StarknetProvider returns a provider replaying the traffic recorded for chainID
+func (r *Recording) StarknetProvider(chainID uint64) *ReplayStarknetProvider {
+	return &ReplayStarknetProvider{recording: r, chainID: chainID}
+}
+
+func (p *ReplayStarknetProvider) ChainID(_ context.Context) (string, error) {
+	return replayed[string](p.recording, p.chainID, MethodStarknetChainID, nil)
+}
+
+func (p *ReplayStarknetProvider) BlockNumber(_ context.Context) (uint64, error) {
+	return replayed[uint64](p.recording, p.chainID, MethodStarknetBlockNumber, nil)
+}
+
+func (p *ReplayStarknetProvider) Call(_ context.Context, call rpc.FunctionCall, blockID rpc.BlockID) ([]*felt.Felt, error) {
+	return replayed[[]*felt.Felt](p.recording, p.chainID, MethodStarknetCall, starknetCallRequest{call, blockID})
+}
+
+func (p *ReplayStarknetProvider) Nonce(_ context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
+	return replayed[*felt.Felt](p.recording, p.chainID, MethodStarknetNonce, starknetNonceRequest{blockID, contractAddress})
+}
+
+func (p *ReplayStarknetProvider) EstimateFee(
+	_ context.Context,
+	requests []rpc.BroadcastTxn,
+	_ []rpc.SimulationFlag,
+	_ rpc.BlockID,
+) ([]rpc.FeeEstimation, error) {
+	return replayed[[]rpc.FeeEstimation](p.recording, p.chainID, MethodStarknetEstimateFee, invokeKeys(requests))
+}
+
+func (p *ReplayStarknetProvider) AddInvokeTransaction(_ context.Context, txn *rpc.BroadcastInvokeTxnV3) (rpc.AddInvokeTransactionResponse, error) {
+	return replayed[rpc.AddInvokeTransactionResponse](p.recording, p.chainID, MethodStarknetInvoke, invokeKeys([]rpc.BroadcastTxn{txn})[0])
+}
+
+func (p *ReplayStarknetProvider) TransactionReceipt(_ context.Context, transactionHash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	return replayed[*rpc.TransactionReceiptWithBlockInfo](p.recording, p.chainID, MethodStarknetReceipt, transactionHash)
+}
+
+func (p *ReplayStarknetProvider) Events(_ context.Context, input rpc.EventsInput) (*rpc.EventChunk, error) {
+	return replayed[*rpc.EventChunk](p.recording, p.chainID, MethodStarknetEvents, input)
+}
@@ -0,0 +1,125 @@
+package replay
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// starknetOutcome is what the solver reads back from a Starknet provider
+type starknetOutcome struct {
+	balance []*felt.Felt
+	events  *rpc.EventChunk
+	fees    []rpc.FeeEstimation
+	receipt *rpc.TransactionReceiptWithBlockInfo
+}
+
+// starknetTraffic drives the calls the solver makes: a balance read, an event query and an invoke
+func starknetTraffic(t *testing.T, provider rpc.RPCProvider, token, hub, sender *felt.Felt, signature []*felt.Felt) starknetOutcome {
+	t.Helper()
+	ctx := context.Background()
+	latest := rpc.WithBlockTag(rpc.BlockTagLatest)
+	var out starknetOutcome
+	var err error
+
+	out.balance, err = provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    token,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("balance_of"),
+		Calldata:           []*felt.Felt{sender},
+	}, latest)
+	require.NoError(t, err)
+
+	from, to := uint64(1), uint64(10)
+	out.events, err = provider.Events(ctx, rpc.EventsInput{
+		EventFilter: rpc.EventFilter{
+			FromBlock: rpc.BlockID{Number: &from},
+			ToBlock:   rpc.BlockID{Number: &to},
+			Address:   hub,
+		},
+		ResultPageRequest: rpc.ResultPageRequest{ChunkSize: 100},
+	})
+	require.NoError(t, err)
+
+	nonce, err := provider.Nonce(ctx, latest, sender)
+	require.NoError(t, err)
+	// The signature differs between runs; invokes are matched without it
+	txn := &rpc.BroadcastInvokeTxnV3{
+		SenderAddress: sender,
+		Nonce:         nonce,
+		Calldata:      []*felt.Felt{hub},
+		Signature:     signature,
+	}
+	out.fees, err = provider.EstimateFee(ctx, []rpc.BroadcastTxn{txn}, nil, latest)
+	require.NoError(t, err)
+	resp, err := provider.AddInvokeTransaction(ctx, txn)
+	require.NoError(t, err)
+	out.receipt, err = provider.TransactionReceipt(ctx, resp.Hash)
+	require.NoError(t, err)
+	return out
+}
+
+// TestStarknetRecordAndReplay tests that replayed Starknet traffic matches the recorded traffic
+func TestStarknetRecordAndReplay(t *testing.T) {
+	const chainID = 23448591
+	token := new(felt.Felt).SetUint64(0x1234)
+	hub := new(felt.Felt).SetUint64(0x1)
+	sender := new(felt.Felt).SetUint64(0xabc)
+
+	backend := chainmock.NewStarknetBackend()
+	backend.HandleCall(token, "balance_of", func(call rpc.FunctionCall) ([]*felt.Felt, error) {
+		return []*felt.Felt{new(felt.Felt).SetUint64(7), new(felt.Felt)}, nil
+	})
+	backend.AddEvent(rpc.EmittedEvent{
+		Event: rpc.Event{
+			FromAddress:  hub,
+			EventContent: rpc.EventContent{Keys: []*felt.Felt{utils.GetSelectorFromNameFelt("Open")}},
+		},
+		BlockNumber: 5,
+	})
+
+	path := filepath.Join(t.TempDir(), "starknet.jsonl")
+	recorder, err := NewRecorder(path)
+	require.NoError(t, err)
+	want := starknetTraffic(t, NewRecordingStarknetProvider(backend, chainID, recorder), token, hub, sender,
+		[]*felt.Felt{new(felt.Felt).SetUint64(1)})
+	require.NoError(t, recorder.Close())
+
+	recording, err := Load(path)
+	require.NoError(t, err)
+	got := starknetTraffic(t, recording.StarknetProvider(chainID), token, hub, sender,
+		[]*felt.Felt{new(felt.Felt).SetUint64(2)})
+
+	assert.Equal(t, want.balance, got.balance)
+	require.Len(t, got.events.Events, 1)
+	assert.Equal(t, want.events.Events[0].BlockNumber, got.events.Events[0].BlockNumber)
+	assert.Equal(t, want.fees[0].OverallFee, got.fees[0].OverallFee)
+	assert.Equal(t, want.receipt.Hash, got.receipt.Hash)
+	assert.Equal(t, want.receipt.ExecutionStatus, got.receipt.ExecutionStatus)
+	assert.Len(t, backend.Invokes(), 1, "replay must not reach the backend")
+
+	t.Run("recorded rpc errors keep their code", func(t *testing.T) {
+		_, err := recording.StarknetProvider(chainID).TransactionReceipt(context.Background(), new(felt.Felt).SetUint64(99))
+		assert.ErrorIs(t, err, ErrNotRecorded)
+
+		path := filepath.Join(t.TempDir(), "errors.jsonl")
+		recorder, err := NewRecorder(path)
+		require.NoError(t, err)
+		_, err = NewRecordingStarknetProvider(backend, chainID, recorder).TransactionReceipt(context.Background(), new(felt.Felt).SetUint64(99))
+		require.ErrorIs(t, err, rpc.ErrHashNotFound)
+		require.NoError(t, recorder.Close())
+
+		recording, err := Load(path)
+		require.NoError(t, err)
+		_, err = recording.StarknetProvider(chainID).TransactionReceipt(context.Background(), new(felt.Felt).SetUint64(99))
+		var rpcErr *rpc.RPCError
+		require.ErrorAs(t, err, &rpcErr)
+		assert.Equal(t, rpc.ErrHashNotFound.Code, rpcErr.Code)
+	})
+}
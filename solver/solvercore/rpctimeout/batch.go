@@ -0,0 +1,103 @@
+package rpctimeout
+
+// Batched log queries: the listeners read the logs of several block ranges in one JSON-RPC batch
+// during backfill. A batch is bounded by the LOGS timeout like a single query. Clients without a
+// raw JSON-RPC client to batch on return ErrBatchUnsupported and callers query one range at a time.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/starknet.go/client"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrBatchUnsupported is returned by batch calls on clients that cannot send batches
+var ErrBatchUnsupported = errors.New("batch requests not supported by this client")
+
+// gethRPCClient is implemented by ethclient.Client
+type gethRPCClient interface {
+	Client() *gethrpc.Client
+}
+
+// StarknetBatchCaller sends JSON-RPC batches, e.g. a starknet.go client.Client
+type StarknetBatchCaller interface {
+	BatchCallContext(ctx context.Context, b []client.BatchElem) error
+}
+
+// BatchFilterLogs runs eth_getLogs for every query in one batch and returns the logs in query order.
+// The batch fails as a whole when the provider rejects it or any of its queries.
+func (c *EVMClient) BatchFilterLogs(ctx context.Context, queries []ethereum.FilterQuery) ([][]gethtypes.Log, error) {
+	inner, ok := c.inner.(gethRPCClient)
+	if !ok {
+		return nil, ErrBatchUnsupported
+	}
+	results := make([][]gethtypes.Log, len(queries))
+	elems := make([]gethrpc.BatchElem, len(queries))
+	for i, query := range queries {
+		if query.FromBlock == nil || query.ToBlock == nil {
+			return nil, fmt.Errorf("batched eth_getLogs needs a block range")
+		}
+		elems[i] = gethrpc.BatchElem{
+			Method: "eth_getLogs",
+			Args: []interface{}{map[string]interface{}{
+				"address":   query.Addresses,
+				"topics":    query.Topics,
+				"fromBlock": hexutil.EncodeBig(query.FromBlock),
+				"toBlock":   hexutil.EncodeBig(query.ToBlock),
+			}},
+			Result: &results[i],
+		}
+	}
+
+	_, err := withTimeout(ctx, c.timeouts, OperationLogs, "eth_getLogs batch", func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, inner.Client().BatchCallContext(ctx, elems)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, elem := range elems {
+		if elem.Error != nil {
+			return nil, fmt.Errorf("eth_getLogs %d of %d in batch: %w", i+1, len(elems), elem.Error)
+		}
+	}
+	return results, nil
+}
+
+// SetBatchCaller gives the provider a client to send batches on; without one BatchEvents returns
+// ErrBatchUnsupported
+func (p *StarknetProvider) SetBatchCaller(caller StarknetBatchCaller) {
+	p.batch = caller
+}
+
+// BatchEvents runs starknet_getEvents for every input in one batch and returns the pages in input
+// order. The batch fails as a whole when the provider rejects it or any of its queries.
+func (p *StarknetProvider) BatchEvents(ctx context.Context, inputs []rpc.EventsInput) ([]*rpc.EventChunk, error) {
+	if p.batch == nil {
+		return nil, ErrBatchUnsupported
+	}
+	results := make([]*rpc.EventChunk, len(inputs))
+	elems := make([]client.BatchElem, len(inputs))
+	for i, input := range inputs {
+		results[i] = &rpc.EventChunk{}
+		elems[i] = client.BatchElem{Method: "starknet_getEvents", Args: []interface{}{input}, Result: results[i]}
+	}
+
+	_, err := withTimeout(ctx, p.timeouts, OperationLogs, "starknet_getEvents batch", func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, p.batch.BatchCallContext(ctx, elems)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, elem := range elems {
+		if elem.Error != nil {
+			return nil, fmt.Errorf("starknet_getEvents %d of %d in batch: %w", i+1, len(elems), elem.Error)
+		}
+	}
+	return results, nil
+}
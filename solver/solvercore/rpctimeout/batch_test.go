@@ -0,0 +1,62 @@
+package rpctimeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/starknet.go/client"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBatchCaller answers every batched query with a page of continuation token "page-<i>",
+// or fails the query at index failAt
+type fakeBatchCaller struct {
+	failAt int
+	calls  int
+}
+
+func (c *fakeBatchCaller) BatchCallContext(_ context.Context, elems []client.BatchElem) error {
+	c.calls++
+	for i := range elems {
+		if i == c.failAt {
+			elems[i].Error = errors.New("block range too large")
+			continue
+		}
+		elems[i].Result.(*rpc.EventChunk).ContinuationToken = fmt.Sprintf("page-%d", i)
+	}
+	return nil
+}
+
+func TestStarknetBatchEvents(t *testing.T) {
+	newProvider := func() *StarknetProvider {
+		return NewStarknetProviderWithTimeouts(chainmock.NewStarknetBackend(), Timeouts{OperationLogs: time.Second})
+	}
+
+	_, err := newProvider().BatchEvents(context.Background(), []rpc.EventsInput{{}, {}})
+	assert.ErrorIs(t, err, ErrBatchUnsupported)
+
+	provider := newProvider()
+	caller := &fakeBatchCaller{failAt: -1}
+	provider.SetBatchCaller(caller)
+	pages, err := provider.BatchEvents(context.Background(), []rpc.EventsInput{{}, {}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, caller.calls)
+	require.Len(t, pages, 2)
+	assert.Equal(t, "page-0", pages[0].ContinuationToken)
+	assert.Equal(t, "page-1", pages[1].ContinuationToken)
+
+	caller.failAt = 1
+	_, err = provider.BatchEvents(context.Background(), []rpc.EventsInput{{}, {}})
+	assert.ErrorContains(t, err, "starknet_getEvents 2 of 2 in batch: block range too large")
+}
+
+func TestEVMBatchFilterLogsUnsupported(t *testing.T) {
+	_, err := NewEVMClientWithTimeouts(chainmock.NewEVMBackend(1), Timeouts{OperationLogs: time.Second}).BatchFilterLogs(context.Background(), nil)
+	assert.ErrorIs(t, err, ErrBatchUnsupported)
+}
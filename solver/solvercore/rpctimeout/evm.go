@@ -0,0 +1,118 @@
+package rpctimeout
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// evmBackend is the client surface the solver uses (hyperlane7683.EVMClient)
+type evmBackend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	ChainID(ctx context.Context) (*big.Int, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// EVMClient bounds the calls of an EVM client
+type EVMClient struct {
+	inner    evmBackend
+	timeouts Timeouts
+}
+
+// NewEVMClient wraps inner with the timeouts configured for network
+func NewEVMClient(inner evmBackend, network string) *EVMClient {
+	return NewEVMClientWithTimeouts(inner, FromEnv(network))
+}
+
+// NewEVMClientWithTimeouts wraps inner with explicit timeouts
+func NewEVMClientWithTimeouts(inner evmBackend, timeouts Timeouts) *EVMClient {
+	return &EVMClient{inner: inner, timeouts: timeouts}
+}
+
+func (c *EVMClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return withTimeout(ctx, c.timeouts, OperationRead, "eth_chainId", c.inner.ChainID)
+}
+
+func (c *EVMClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return withTimeout(ctx, c.timeouts, OperationRead, "eth_blockNumber", c.inner.BlockNumber)
+}
+
+func (c *EVMClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return withTimeout(ctx, c.timeouts, OperationRead, "eth_getCode", func(ctx context.Context) ([]byte, error) {
+		return c.inner.CodeAt(ctx, contract, blockNumber)
+	})
+}
+
+func (c *EVMClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return withTimeout(ctx, c.timeouts, OperationRead, "eth_getBalance", func(ctx context.Context) (*big.Int, error) {
+		return c.inner.BalanceAt(ctx, account, blockNumber)
+	})
+}
+
+func (c *EVMClient) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return withTimeout(ctx, c.timeouts, OperationRead, "eth_getCode", func(ctx context.Context) ([]byte, error) {
+		return c.inner.PendingCodeAt(ctx, account)
+	})
+}
+
+func (c *EVMClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return withTimeout(ctx, c.timeouts, OperationRead, "eth_call", func(ctx context.Context) ([]byte, error) {
+		return c.inner.CallContract(ctx, msg, blockNumber)
+	})
+}
+
+func (c *EVMClient) HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error) {
+	return withTimeout(ctx, c.timeouts, OperationRead, "eth_getBlockByNumber", func(ctx context.Context) (*gethtypes.Header, error) {
+		return c.inner.HeaderByNumber(ctx, number)
+	})
+}
+
+func (c *EVMClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return withTimeout(ctx, c.timeouts, OperationRead, "eth_getTransactionCount", func(ctx context.Context) (uint64, error) {
+		return c.inner.PendingNonceAt(ctx, account)
+	})
+}
+
+func (c *EVMClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return withTimeout(ctx, c.timeouts, OperationRead, "eth_gasPrice", c.inner.SuggestGasPrice)
+}
+
+func (c *EVMClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return withTimeout(ctx, c.timeouts, OperationRead, "eth_maxPriorityFeePerGas", c.inner.SuggestGasTipCap)
+}
+
+func (c *EVMClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return withTimeout(ctx, c.timeouts, OperationRead, "eth_estimateGas", func(ctx context.Context) (uint64, error) {
+		return c.inner.EstimateGas(ctx, call)
+	})
+}
+
+func (c *EVMClient) SendTransaction(ctx context.Context, tx *gethtypes.Transaction) error {
+	_, err := withTimeout(ctx, c.timeouts, OperationSend, "eth_sendRawTransaction", func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, c.inner.SendTransaction(ctx, tx)
+	})
+	return err
+}
+
+func (c *EVMClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*gethtypes.Receipt, error) {
+	return withTimeout(ctx, c.timeouts, OperationRead, "eth_getTransactionReceipt", func(ctx context.Context) (*gethtypes.Receipt, error) {
+		return c.inner.TransactionReceipt(ctx, txHash)
+	})
+}
+
+func (c *EVMClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]gethtypes.Log, error) {
+	return withTimeout(ctx, c.timeouts, OperationLogs, "eth_getLogs", func(ctx context.Context) ([]gethtypes.Log, error) {
+		return c.inner.FilterLogs(ctx, query)
+	})
+}
+
+// SubscribeFilterLogs is forwarded unbounded: the subscription lives as long as the caller's context
+func (c *EVMClient) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- gethtypes.Log) (ethereum.Subscription, error) {
+	return c.inner.SubscribeFilterLogs(ctx, query, ch)
+}
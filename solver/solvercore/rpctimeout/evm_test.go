@@ -0,0 +1,36 @@
+package rpctimeout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/ethereum/go-ethereum"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hungEVMBackend never answers log queries
+type hungEVMBackend struct {
+	*chainmock.EVMBackend
+}
+
+func (b hungEVMBackend) FilterLogs(ctx context.Context, _ ethereum.FilterQuery) ([]gethtypes.Log, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestEVMClient(t *testing.T) {
+	client := NewEVMClientWithTimeouts(hungEVMBackend{chainmock.NewEVMBackend(31337)},
+		Timeouts{OperationRead: time.Second, OperationLogs: 10 * time.Millisecond})
+
+	chainID, err := client.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(31337), chainID.Uint64())
+
+	_, err = client.FilterLogs(context.Background(), ethereum.FilterQuery{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "eth_getLogs timed out")
+}
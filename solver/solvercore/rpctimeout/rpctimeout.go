@@ -0,0 +1,79 @@
+// Package rpctimeout bounds every chain RPC call the solver makes.
+//
+// EVMClient and StarknetProvider wrap a client and give each call its own deadline,
+// derived from the caller's context, so a hung endpoint fails the call instead of
+// stalling a listener or the fill pipeline. A shorter deadline already set by the
+// caller still applies.
+//
+// Calls are grouped by operation: reads (contract calls, balances, nonces, gas and fee
+// estimates, receipts), log queries (eth_getLogs, starknet_getEvents), which can scan
+// large block ranges, and transaction submission.
+//
+// Settings (seconds, 0 = no timeout), most specific first:
+// - RPC_TIMEOUT_<NETWORK>_<OPERATION>_SECONDS, e.g. RPC_TIMEOUT_BASE_LOGS_SECONDS
+// - RPC_TIMEOUT_<NETWORK>_SECONDS, e.g. RPC_TIMEOUT_STARKNET_SECONDS
+// - RPC_TIMEOUT_<OPERATION>_SECONDS: READ (default 15), LOGS (default 30), SEND (default 30)
+// - RPC_TIMEOUT_SECONDS: all operations of all networks
+package rpctimeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+)
+
+// Operation groups RPC calls sharing a timeout
+type Operation string
+
+const (
+	OperationRead Operation = "READ"
+	OperationLogs Operation = "LOGS"
+	OperationSend Operation = "SEND"
+)
+
+// Default timeouts by operation
+var defaultTimeouts = map[Operation]time.Duration{
+	OperationRead: 15 * time.Second,
+	OperationLogs: 30 * time.Second,
+	OperationSend: 30 * time.Second,
+}
+
+// Timeouts holds the timeout of each operation; a zero timeout leaves calls unbounded
+type Timeouts map[Operation]time.Duration
+
+// FromEnv returns the timeouts configured for a network
+func FromEnv(network string) Timeouts {
+	prefix := "RPC_TIMEOUT_" + strings.ToUpper(strings.ReplaceAll(network, " ", "_"))
+	timeouts := make(Timeouts, len(defaultTimeouts))
+	for op, fallback := range defaultTimeouts {
+		seconds := int(fallback / time.Second)
+		for _, key := range []string{"RPC_TIMEOUT_SECONDS", "RPC_TIMEOUT_" + string(op) + "_SECONDS", prefix + "_SECONDS", prefix + "_" + string(op) + "_SECONDS"} {
+			if os.Getenv(key) != "" {
+				seconds = envutil.GetEnvInt(key, seconds)
+			}
+		}
+		timeouts[op] = time.Duration(max(seconds, 0)) * time.Second
+	}
+	return timeouts
+}
+
+// withTimeout runs call with the timeout of op. Errors caused by that timeout, rather than by
+// the caller's context, name the method and the timeout.
+func withTimeout[T any](ctx context.Context, timeouts Timeouts, op Operation, method string, call func(context.Context) (T, error)) (T, error) {
+	timeout := timeouts[op]
+	if timeout <= 0 {
+		return call(ctx)
+	}
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	result, err := call(callCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return result, fmt.Errorf("%s timed out after %s: %w", method, timeout, err)
+	}
+	return result, err
+}
@@ -0,0 +1,65 @@
+package rpctimeout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Run("defaults by operation", func(t *testing.T) {
+		timeouts := FromEnv("Base")
+		assert.Equal(t, 15*time.Second, timeouts[OperationRead])
+		assert.Equal(t, 30*time.Second, timeouts[OperationLogs])
+		assert.Equal(t, 30*time.Second, timeouts[OperationSend])
+	})
+
+	t.Run("most specific setting wins", func(t *testing.T) {
+		t.Setenv("RPC_TIMEOUT_SECONDS", "20")
+		t.Setenv("RPC_TIMEOUT_LOGS_SECONDS", "60")
+		t.Setenv("RPC_TIMEOUT_STARKNET_SECONDS", "40")
+		t.Setenv("RPC_TIMEOUT_STARKNET_SEND_SECONDS", "0")
+
+		base := FromEnv("Base")
+		assert.Equal(t, 20*time.Second, base[OperationRead])
+		assert.Equal(t, 60*time.Second, base[OperationLogs])
+		assert.Equal(t, 20*time.Second, base[OperationSend])
+
+		starknet := FromEnv("Starknet")
+		assert.Equal(t, 40*time.Second, starknet[OperationRead])
+		assert.Equal(t, 40*time.Second, starknet[OperationLogs])
+		assert.Equal(t, time.Duration(0), starknet[OperationSend], "0 disables the timeout")
+	})
+}
+
+func TestWithTimeout(t *testing.T) {
+	hang := func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	timeouts := Timeouts{OperationRead: 10 * time.Millisecond}
+
+	t.Run("timed out calls name the method", func(t *testing.T) {
+		_, err := withTimeout(context.Background(), timeouts, OperationRead, "eth_call", hang)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, "eth_call timed out after 10ms")
+	})
+
+	t.Run("caller cancellation is passed through", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := withTimeout(ctx, timeouts, OperationRead, "eth_call", hang)
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("zero timeout leaves the call unbounded", func(t *testing.T) {
+		_, err := withTimeout(context.Background(), timeouts, OperationSend, "eth_sendRawTransaction", func(ctx context.Context) (int, error) {
+			_, ok := ctx.Deadline()
+			assert.False(t, ok)
+			return 1, nil
+		})
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,82 @@
+package rpctimeout
+
+import (
+	"context"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// StarknetProvider bounds the calls of a Starknet provider the solver uses; other methods are
+// forwarded unbounded
+type StarknetProvider struct {
+	rpc.RPCProvider
+
+	timeouts Timeouts
+	batch    StarknetBatchCaller // Sends BatchEvents; nil when batches are not supported (see batch.go)
+}
+
+// NewStarknetProvider wraps inner with the timeouts configured for network
+func NewStarknetProvider(inner rpc.RPCProvider, network string) *StarknetProvider {
+	return NewStarknetProviderWithTimeouts(inner, FromEnv(network))
+}
+
+// NewStarknetProviderWithTimeouts wraps inner with explicit timeouts
+func NewStarknetProviderWithTimeouts(inner rpc.RPCProvider, timeouts Timeouts) *StarknetProvider {
+	return &StarknetProvider{RPCProvider: inner, timeouts: timeouts}
+}
+
+func (p *StarknetProvider) ChainID(ctx context.Context) (string, error) {
+	return withTimeout(ctx, p.timeouts, OperationRead, "starknet_chainId", p.RPCProvider.ChainID)
+}
+
+func (p *StarknetProvider) BlockNumber(ctx context.Context) (uint64, error) {
+	return withTimeout(ctx, p.timeouts, OperationRead, "starknet_blockNumber", p.RPCProvider.BlockNumber)
+}
+
+func (p *StarknetProvider) Call(ctx context.Context, call rpc.FunctionCall, blockID rpc.BlockID) ([]*felt.Felt, error) {
+	return withTimeout(ctx, p.timeouts, OperationRead, "starknet_call", func(ctx context.Context) ([]*felt.Felt, error) {
+		return p.RPCProvider.Call(ctx, call, blockID)
+	})
+}
+
+func (p *StarknetProvider) Nonce(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
+	return withTimeout(ctx, p.timeouts, OperationRead, "starknet_getNonce", func(ctx context.Context) (*felt.Felt, error) {
+		return p.RPCProvider.Nonce(ctx, blockID, contractAddress)
+	})
+}
+
+func (p *StarknetProvider) ClassAt(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (rpc.ClassOutput, error) {
+	return withTimeout(ctx, p.timeouts, OperationRead, "starknet_getClassAt", func(ctx context.Context) (rpc.ClassOutput, error) {
+		return p.RPCProvider.ClassAt(ctx, blockID, contractAddress)
+	})
+}
+
+func (p *StarknetProvider) EstimateFee(
+	ctx context.Context,
+	requests []rpc.BroadcastTxn,
+	simulationFlags []rpc.SimulationFlag,
+	blockID rpc.BlockID,
+) ([]rpc.FeeEstimation, error) {
+	return withTimeout(ctx, p.timeouts, OperationRead, "starknet_estimateFee", func(ctx context.Context) ([]rpc.FeeEstimation, error) {
+		return p.RPCProvider.EstimateFee(ctx, requests, simulationFlags, blockID)
+	})
+}
+
+func (p *StarknetProvider) AddInvokeTransaction(ctx context.Context, txn *rpc.BroadcastInvokeTxnV3) (rpc.AddInvokeTransactionResponse, error) {
+	return withTimeout(ctx, p.timeouts, OperationSend, "starknet_addInvokeTransaction", func(ctx context.Context) (rpc.AddInvokeTransactionResponse, error) {
+		return p.RPCProvider.AddInvokeTransaction(ctx, txn)
+	})
+}
+
+func (p *StarknetProvider) TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	return withTimeout(ctx, p.timeouts, OperationRead, "starknet_getTransactionReceipt", func(ctx context.Context) (*rpc.TransactionReceiptWithBlockInfo, error) {
+		return p.RPCProvider.TransactionReceipt(ctx, transactionHash)
+	})
+}
+
+func (p *StarknetProvider) Events(ctx context.Context, input rpc.EventsInput) (*rpc.EventChunk, error) {
+	return withTimeout(ctx, p.timeouts, OperationLogs, "starknet_getEvents", func(ctx context.Context) (*rpc.EventChunk, error) {
+		return p.RPCProvider.Events(ctx, input)
+	})
+}
@@ -0,0 +1,37 @@
+package rpctimeout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hungStarknetBackend never answers event queries
+type hungStarknetBackend struct {
+	*chainmock.StarknetBackend
+}
+
+func (b hungStarknetBackend) Events(ctx context.Context, _ rpc.EventsInput) (*rpc.EventChunk, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestStarknetProvider(t *testing.T) {
+	backend := chainmock.NewStarknetBackend()
+	backend.SetBlockNumber(42)
+	provider := NewStarknetProviderWithTimeouts(hungStarknetBackend{backend},
+		Timeouts{OperationRead: time.Second, OperationLogs: 10 * time.Millisecond})
+
+	block, err := provider.BlockNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), block)
+
+	_, err = provider.Events(context.Background(), rpc.EventsInput{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "starknet_getEvents timed out")
+}
@@ -4,15 +4,28 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"slices"
+	"sort"
 
 	"strings"
+	"sync"
 
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/admin"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/events"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/leader"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/replay"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/rpctimeout"
 	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/client"
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -37,11 +50,24 @@ type SolverRegistry map[string]SolverConfig
 // SolverManager manages multiple protocol solvers
 // Following the TypeScript SolverManager pattern
 type SolverManager struct {
-	evmClients      map[uint64]*ethclient.Client
-	starknetClient  *rpc.Provider
+	evmClients      map[uint64]contracts.EVMClient
+	starknetClients map[uint64]rpc.RPCProvider
 	activeShutdowns []func()
 	solverRegistry  SolverRegistry
 	allowBlockLists types.AllowBlockLists
+	recorder        *replay.Recorder // Records chain traffic when SOLVER_RECORD_FILE is set
+	adminServer     *admin.Server    // Runs when SOLVER_ADMIN_ADDR is set
+	stopAdminEvents func()           // Stops recording the events served by GET /events
+	hyperlane7683   *contracts.Hyperlane7683Solver
+	balanceMonitor  *contracts.BalanceMonitor
+	hyperlaneMu     sync.RWMutex // Guards hyperlane7683 and balanceMonitor, read by the admin API
+	reloadMu        sync.Mutex   // Serializes config reloads
+	listeners       []base.Listener
+	listenersMu     sync.RWMutex // Guards listeners, read by the admin API
+
+	solverFactories   map[string]SolverFactory   // Solvers added through RegisterSolver
+	listenerFactories map[string]ListenerFactory // Listeners added through RegisterListenerFactory
+	rules             []contracts.Rule           // Custom rules added through AddRule
 }
 
 // NewSolverManager creates a new solver manager
@@ -54,22 +80,57 @@ func NewSolverManager(cfg *config.Config) *SolverManager {
 		},
 	}
 
-	return &SolverManager{
-		evmClients:      make(map[uint64]*ethclient.Client),
-		starknetClient:  nil, // Will be initialized later
-		activeShutdowns: make([]func(), 0),
-		solverRegistry:  registry,
+	sm := &SolverManager{
+		evmClients:        make(map[uint64]contracts.EVMClient),
+		starknetClients:   make(map[uint64]rpc.RPCProvider),
+		activeShutdowns:   make([]func(), 0),
+		solverRegistry:    registry,
+		solverFactories:   make(map[string]SolverFactory),
+		listenerFactories: make(map[string]ListenerFactory),
 		allowBlockLists: types.AllowBlockLists{
 			AllowList: []types.AllowBlockListItem{},
 			BlockList: []types.AllowBlockListItem{},
 		},
 	}
+	if cfg != nil && (len(cfg.AllowBlockLists.AllowList) > 0 || len(cfg.AllowBlockLists.BlockList) > 0) {
+		sm.allowBlockLists = cfg.AllowBlockLists
+	}
+	return sm
 }
 
 // SetAllowBlockLists configures the allow/block lists for the solver manager
 // This allows runtime configuration of which orders to process
 func (sm *SolverManager) SetAllowBlockLists(allowBlockLists types.AllowBlockLists) {
 	sm.allowBlockLists = allowBlockLists
+	if solver := sm.hyperlaneSolver(); solver != nil {
+		solver.SetAllowBlockLists(allowBlockLists)
+	}
+}
+
+// hyperlaneSolver returns the Hyperlane7683 solver, nil until it is initialized
+func (sm *SolverManager) hyperlaneSolver() *contracts.Hyperlane7683Solver {
+	sm.hyperlaneMu.RLock()
+	defer sm.hyperlaneMu.RUnlock()
+	return sm.hyperlane7683
+}
+
+// ReloadConfig re-reads the runtime config (allow/block lists, rule settings, poll intervals)
+// from SOLVER_CONFIG_FILE and swaps it in without restarting listeners. An invalid config is
+// rejected and the active one is kept.
+func (sm *SolverManager) ReloadConfig() (*config.RuntimeConfig, error) {
+	sm.reloadMu.Lock()
+	defer sm.reloadMu.Unlock()
+
+	runtime, err := config.ReloadRuntime(contracts.ValidateRuleConfig, logutil.ValidateLogLevels)
+	if err != nil {
+		return nil, fmt.Errorf("config reload failed: %w", err)
+	}
+	sm.SetAllowBlockLists(runtime.AllowBlockLists)
+
+	fmt.Printf("🔄 Config reloaded: %d allow, %d block entries, %d disabled rules, %d poll interval overrides\n",
+		len(runtime.AllowBlockLists.AllowList), len(runtime.AllowBlockLists.BlockList),
+		len(runtime.Rules.Disabled), len(runtime.PollIntervalsMs))
+	return runtime, nil
 }
 
 // GetAllowBlockLists returns the current allow/block lists configuration
@@ -79,18 +140,45 @@ func (sm *SolverManager) GetAllowBlockLists() types.AllowBlockLists {
 
 // InitializeSolvers starts all enabled solvers
 func (sm *SolverManager) InitializeSolvers(ctx context.Context) error {
+	if err := sm.initializeClients(); err != nil {
+		return err
+	}
+	return sm.startSolvers(ctx)
+}
+
+// initializeClients connects to every network and starts the recorder and admin API, everything a
+// standby keeps ready before it leads
+func (sm *SolverManager) initializeClients() error {
 	fmt.Printf("🚀 Initializing solvers...\n")
 
+	// Record all chain traffic for later replay if requested
+	if path := replay.RecordFileFromEnv(); path != "" {
+		recorder, err := replay.NewRecorder(path)
+		if err != nil {
+			return fmt.Errorf("failed to start recording: %w", err)
+		}
+		sm.recorder = recorder
+		fmt.Printf("   📼 Recording chain traffic to %s\n", path)
+	}
+
+	if err := sm.startAdminAPI(); err != nil {
+		return fmt.Errorf("failed to start admin API: %w", err)
+	}
+
 	// Initialize EVM clients for all EVM networks
 	if err := sm.initializeEVMClients(); err != nil {
 		return fmt.Errorf("failed to initialize EVM clients: %w", err)
 	}
 
-	// Initialize Starknet client
+	// Initialize Starknet clients for all Starknet networks
 	if err := sm.initializeStarknetClients(); err != nil {
-		return fmt.Errorf("failed to initialize Starknet client: %w", err)
+		return fmt.Errorf("failed to initialize Starknet clients: %w", err)
 	}
+	return nil
+}
 
+// startSolvers starts the enabled solvers and their listeners
+func (sm *SolverManager) startSolvers(ctx context.Context) error {
 	// Initialize individual solvers
 	for solverName, config := range sm.solverRegistry {
 		if !config.Enabled {
@@ -113,7 +201,19 @@ func (sm *SolverManager) initializeSolver(ctx context.Context, name string) erro
 	case "hyperlane7683":
 		return sm.initializeHyperlane7683(ctx)
 	default:
-		return fmt.Errorf("unknown solver: %s", name)
+		factory, ok := sm.solverFactories[name]
+		if !ok {
+			return fmt.Errorf("unknown solver: %s", name)
+		}
+		shutdown, err := factory(ctx, sm)
+		if err != nil {
+			return err
+		}
+		if shutdown != nil {
+			sm.activeShutdowns = append(sm.activeShutdowns, shutdown)
+		}
+		fmt.Printf("   ✅ Started solver %s\n", name)
+		return nil
 	}
 }
 
@@ -124,7 +224,7 @@ func (sm *SolverManager) initializeEVMClients() error {
 	evmCount := 0
 	for networkName, networkConfig := range config.Networks {
 		// Check if this is NOT a Starknet network (i.e., it's an EVM network)
-		if strings.Contains(strings.ToLower(networkName), "starknet") {
+		if config.IsStarknetNetwork(networkName) {
 			continue
 		}
 		
@@ -135,7 +235,16 @@ func (sm *SolverManager) initializeEVMClients() error {
 			return fmt.Errorf("failed to create EVM client for %s: %w", networkName, err)
 		}
 
-		sm.evmClients[networkConfig.ChainID] = client
+		bounded := rpctimeout.NewEVMClient(client, networkName)
+		if err := verifyEVMChainID(bounded, networkConfig); err != nil {
+			client.Close()
+			return err
+		}
+		if sm.recorder != nil {
+			sm.evmClients[networkConfig.ChainID] = replay.NewRecordingEVMClient(bounded, networkConfig.ChainID, sm.recorder)
+		} else {
+			sm.evmClients[networkConfig.ChainID] = bounded
+		}
 		fmt.Printf("   ✅ EVM client initialized for %s\n", networkName)
 		evmCount++
 	}
@@ -144,13 +253,14 @@ func (sm *SolverManager) initializeEVMClients() error {
 	return nil
 }
 
-// initializeStarknetClients initializes Starknet RPC connection for the first Starknet network found
+// initializeStarknetClients initializes Starknet RPC connections for all Starknet networks
 func (sm *SolverManager) initializeStarknetClients() error {
-	fmt.Printf("🔗 Initializing Starknet client...\n")
+	fmt.Printf("🔗 Initializing Starknet clients...\n")
 
+	starknetCount := 0
 	for networkName, networkConfig := range config.Networks {
 		// Check if this is a Starknet network
-		if !strings.Contains(strings.ToLower(networkName), "starknet") {
+		if !config.IsStarknetNetwork(networkName) {
 			continue
 		}
 		
@@ -161,25 +271,64 @@ func (sm *SolverManager) initializeStarknetClients() error {
 			return fmt.Errorf("failed to create Starknet provider for %s: %w", networkName, err)
 		}
 
-		sm.starknetClient = provider
-		fmt.Printf("✅ Starknet client initialized successfully\n")
-		return nil // Only need one Starknet client
+		bounded := rpctimeout.NewStarknetProvider(provider, networkName)
+		if batchClient, err := client.DialContext(context.Background(), networkConfig.RPCURL); err != nil {
+			fmt.Printf("   ⚠️  Batched log queries disabled for %s: %v\n", networkName, err)
+		} else {
+			bounded.SetBatchCaller(batchClient)
+		}
+		if err := verifyStarknetChainID(bounded, networkConfig); err != nil {
+			return err
+		}
+		if sm.recorder != nil {
+			sm.starknetClients[networkConfig.ChainID] = replay.NewRecordingStarknetProvider(bounded, networkConfig.ChainID, sm.recorder)
+		} else {
+			sm.starknetClients[networkConfig.ChainID] = bounded
+		}
+		fmt.Printf("   ✅ Starknet client initialized for %s\n", networkName)
+		starknetCount++
 	}
 
-	fmt.Printf("⚠️  No Starknet networks found in config\n")
+	if starknetCount == 0 {
+		fmt.Printf("⚠️  No Starknet networks found in config\n")
+		return nil
+	}
+	fmt.Printf("✅ All Starknet clients initialized (%d networks)\n", starknetCount)
 	return nil
 }
 
-// GetStarknetClient returns the Starknet client
-func (sm *SolverManager) GetStarknetClient() (*rpc.Provider, error) {
-	if sm.starknetClient == nil {
-		return nil, fmt.Errorf("starknet client not initialized")
+// verifyEVMChainID refuses an EVM client whose node serves another chain than the configured one
+func verifyEVMChainID(client interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+}, networkConfig config.NetworkConfig) error {
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to read the chain ID of %s: %w", networkConfig.Name, err)
+	}
+	return config.VerifyEVMChainID(networkConfig, chainID)
+}
+
+// verifyStarknetChainID refuses a Starknet provider whose node serves another chain than the configured one
+func verifyStarknetChainID(provider interface {
+	ChainID(ctx context.Context) (string, error)
+}, networkConfig config.NetworkConfig) error {
+	chainID, err := provider.ChainID(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to read the chain ID of %s: %w", networkConfig.Name, err)
+	}
+	return config.VerifyStarknetChainID(networkConfig, chainID)
+}
+
+// GetStarknetClient returns the Starknet client for the given chain ID
+func (sm *SolverManager) GetStarknetClient(chainID uint64) (rpc.RPCProvider, error) {
+	if client, exists := sm.starknetClients[chainID]; exists {
+		return client, nil
 	}
-	return sm.starknetClient, nil
+	return nil, fmt.Errorf("starknet client not initialized for chain ID %d", chainID)
 }
 
 // GetEVMClient returns an EVM client for the given chain ID
-func (sm *SolverManager) GetEVMClient(chainID uint64) (*ethclient.Client, error) {
+func (sm *SolverManager) GetEVMClient(chainID uint64) (contracts.EVMClient, error) {
 	if client, exists := sm.evmClients[chainID]; exists {
 		return client, nil
 	}
@@ -211,12 +360,14 @@ func (sm *SolverManager) GetEVMSigner(chainID uint64) (*bind.TransactOpts, error
 	return signer, nil
 }
 
-// GetStarknetSigner returns the Starknet signer
-func (sm *SolverManager) GetStarknetSigner() (*account.Account, error) {
+// GetStarknetSigner returns the Starknet signer for the given chain ID; the solver uses the same
+// STARKNET_SOLVER_* account on every Starknet network
+func (sm *SolverManager) GetStarknetSigner(chainID uint64) (*account.Account, error) {
 	// For now, create a new signer each time
 	// In the future, this could be cached
-	if sm.starknetClient == nil {
-		return nil, fmt.Errorf("starknet client not initialized")
+	client, err := sm.GetStarknetClient(chainID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Use conditional environment variables based on IS_DEVNET
@@ -240,7 +391,7 @@ func (sm *SolverManager) GetStarknetSigner() (*account.Account, error) {
 	}
 	ks.Put(pub, privBI)
 
-	acct, err := account.NewAccount(sm.starknetClient, addrF, pub, ks, account.CairoV2)
+	acct, err := account.NewAccount(client, addrF, pub, ks, account.CairoV2)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Starknet account: %w", err)
 	}
@@ -252,6 +403,15 @@ func (sm *SolverManager) GetStarknetSigner() (*account.Account, error) {
 func (sm *SolverManager) initializeHyperlane7683(ctx context.Context) error {
 	fmt.Printf("   🔧 Setting up Hyperlane7683 solver components...\n")
 
+	// Refuse to start against routers or tokens that drifted from the configuration (STRICT_STARTUP)
+	if contracts.StrictStartup() {
+		verifier := contracts.NewDeploymentVerifier(sm.GetEVMClient, sm.GetStarknetClient)
+		if err := contracts.DeploymentDrift(verifier.Verify(ctx, contracts.RegistryContracts())); err != nil {
+			return fmt.Errorf("strict startup: %w", err)
+		}
+		fmt.Printf("   🔎 Deployment verified (strict startup)\n")
+	}
+
 	// Create solver with client and signer getter functions
 	hyperlane7683Solver := contracts.NewHyperlane7683Solver(
 		sm.GetEVMClient,      // EVM client getter
@@ -261,17 +421,105 @@ func (sm *SolverManager) initializeHyperlane7683(ctx context.Context) error {
 		sm.allowBlockLists,   // Allow/block lists
 	)
 	hyperlane7683Solver.AddDefaultRules()
+	sm.addCustomRules(hyperlane7683Solver)
+	sm.hyperlaneMu.Lock()
+	sm.hyperlane7683 = hyperlane7683Solver
+	sm.hyperlaneMu.Unlock()
+
+	// Keep checkpoints and seen events in the database instead of the state file when orders and
+	// audit logs live there
+	backend, err := storage.Default()
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	if backend.Backend() != storage.BackendFile {
+		config.SetCheckpointStorage(backend)
+	}
+
+	// Record order progress and periodically reconcile it against on-chain state
+	orderStore, err := orders.DefaultStore()
+	if err != nil {
+		return fmt.Errorf("failed to open order store: %w", err)
+	}
+	hyperlane7683Solver.SetOrderStore(orderStore)
+	contracts.SeedRejectionMetrics(metrics.Default(), orderStore)
+	// Count the order lifecycle events and post them to the webhook, if one is configured
+	sm.activeShutdowns = append(sm.activeShutdowns, events.StartMetrics(ctx, events.Default(), metrics.Default()))
+	if webhook := events.WebhookFromEnv(); webhook != nil {
+		sm.activeShutdowns = append(sm.activeShutdowns, webhook.Start(ctx, events.Default()))
+		fmt.Printf("   📣 Posting order events to ORDER_EVENTS_WEBHOOK_URL\n")
+	}
+	hyperlane7683Solver.SetTxAuditLog(contracts.TxAuditLogFromEnv())
+	txOutbox, err := contracts.TxOutboxFromEnv()
+	if err != nil {
+		return fmt.Errorf("failed to open tx outbox: %w", err)
+	}
+	hyperlane7683Solver.SetTxOutbox(txOutbox)
+	if err := contracts.DefaultSpendLimiter().SetStorage(backend); err != nil {
+		return fmt.Errorf("failed to open spend limits: %w", err)
+	}
+	hyperlane7683Solver.TrackCompetition(contracts.DefaultCompetitionTracker())
+	hyperlane7683Solver.TrackSettlements(contracts.DefaultSettlementTracker())
+	hyperlane7683Solver.SetTokenDiscovery(contracts.NewTokenDiscovery(sm.GetEVMClient, sm.GetStarknetClient))
+	reconciler := contracts.NewReconciler(hyperlane7683Solver, orderStore)
+	sm.activeShutdowns = append(sm.activeShutdowns, reconciler.Start(ctx))
+
+	// Watch the solver's token balances and alert when one runs low
+	balanceMonitor := contracts.NewBalanceMonitor(sm.GetEVMClient, sm.GetStarknetClient)
+	sm.hyperlaneMu.Lock()
+	sm.balanceMonitor = balanceMonitor
+	sm.hyperlaneMu.Unlock()
+	sm.activeShutdowns = append(sm.activeShutdowns, balanceMonitor.Start(ctx))
+
+	if hyperlane7683Solver.ObserverMode() {
+		// Nothing is approved or settled: orders passing the rules are only recorded
+		fmt.Printf("   👀 Observer mode: orders are evaluated and recorded, no transaction is signed\n")
+	} else {
+		// Approve the routers for tokens with a preApprove amount so the first fills skip inline approvals
+		go func() {
+			if err := hyperlane7683Solver.PrewarmApprovals(ctx); err != nil {
+				fmt.Printf("⚠️  Approval pre-warming failed: %v\n", err)
+			}
+		}()
+
+		// Resolve fills and settles a previous run left in flight before any order is handled again
+		hyperlane7683Solver.RecoverTxOutbox(ctx)
 
-	// Event handler that processes intents
+		// Settle filled orders on a separate worker so settlements never hold up new fills
+		settler := contracts.NewSettleScheduler(hyperlane7683Solver, orderStore)
+		hyperlane7683Solver.ScheduleSettlements(settler)
+		sm.activeShutdowns = append(sm.activeShutdowns, settler.Start(ctx))
+
+		// Move inventory toward low chains by opening orders of the solver's own (REBALANCER_ENABLED)
+		rebalancer := contracts.NewRebalancer(hyperlane7683Solver, balanceMonitor)
+		sm.activeShutdowns = append(sm.activeShutdowns, rebalancer.Start(ctx))
+	}
+
+	// Event handler that processes intents; expired orders are recorded without filling
 	eventHandler := func(args types.ParsedArgs, originChainName string, blockNumber uint64) (bool, error) {
-		return hyperlane7683Solver.ProcessIntent(ctx, &args)
+		return hyperlane7683Solver.ProcessOpenedIntent(ctx, &args, blockNumber)
 	}
 
 	// Start listeners for each intent source
 	fmt.Printf("   📡 Starting network listeners...\n")
 	listenerCount := 0
 
-	for _, source := range []string{"Base", "Optimism", "Arbitrum", "Ethereum", "Starknet"} {
+	for _, source := range sm.allListenerSources() {
+		if factory, ok := sm.listenerFactories[source]; ok {
+			listener, err := factory(ctx, sm, source)
+			if err != nil {
+				return fmt.Errorf("failed to create listener for %s: %w", source, err)
+			}
+			shutdown, err := listener.Start(ctx, eventHandler)
+			if err != nil {
+				return fmt.Errorf("failed to start listener for %s: %w", source, err)
+			}
+			sm.addListener(listener, shutdown)
+			listenerCount++
+			fmt.Printf("     ✅ Started registered listener for %s\n", source)
+			continue
+		}
+
 		networkConfig, exists := config.Networks[source]
 		if !exists {
 			fmt.Printf("     ⚠️  Network %s not found in config, skipping...\n", source)
@@ -279,12 +527,13 @@ func (sm *SolverManager) initializeHyperlane7683(ctx context.Context) error {
 		}
 
 		var shutdown base.ShutdownFunc
+		var listener base.Listener
 
 		// Create appropriate listener based on chain type
-		if source == "Starknet" {
+		if config.IsStarknetNetwork(source) {
 			hyperlaneAddr, err := getStarknetHyperlaneAddress(&networkConfig)
 			if err != nil {
-				return fmt.Errorf("failed to get Starknet Hyperlane address: %w", err)
+				return fmt.Errorf("failed to get Hyperlane address of %s: %w", source, err)
 			}
 
 			// Create Starknet listener config with original solver start block
@@ -298,7 +547,12 @@ func (sm *SolverManager) initializeHyperlane7683(ctx context.Context) error {
 				networkConfig.MaxBlockRange,                // max block range from config
 			)
 
-			starknetListener, err := contracts.NewStarknetListener(listenerConfig, networkConfig.RPCURL)
+			// Listeners share the manager's clients so recordings include their event queries
+			starknetClient, err := sm.GetStarknetClient(networkConfig.ChainID)
+			if err != nil {
+				return fmt.Errorf("failed to create Starknet listener: %w", err)
+			}
+			starknetListener, err := contracts.NewStarknetListenerWithProvider(listenerConfig, starknetClient)
 			if err != nil {
 				return fmt.Errorf("failed to create Starknet listener: %w", err)
 			}
@@ -306,6 +560,7 @@ func (sm *SolverManager) initializeHyperlane7683(ctx context.Context) error {
 			if err != nil {
 				return fmt.Errorf("failed to start Starknet listener for %s: %w", source, err)
 			}
+			listener = starknetListener
 		} else {
 			// Create EVM listener config with original solver start block
 			// The listener will handle negative value resolution
@@ -318,7 +573,11 @@ func (sm *SolverManager) initializeHyperlane7683(ctx context.Context) error {
 				networkConfig.MaxBlockRange,                // max block range from config
 			)
 
-			evmListener, err := contracts.NewEVMListener(listenerConfig, networkConfig.RPCURL)
+			evmClient, err := sm.GetEVMClient(networkConfig.ChainID)
+			if err != nil {
+				return fmt.Errorf("failed to create EVM listener: %w", err)
+			}
+			evmListener, err := contracts.NewEVMListenerWithClient(listenerConfig, evmClient)
 			if err != nil {
 				return fmt.Errorf("failed to create EVM listener: %w", err)
 			}
@@ -326,17 +585,43 @@ func (sm *SolverManager) initializeHyperlane7683(ctx context.Context) error {
 			if err != nil {
 				return fmt.Errorf("failed to start EVM listener for %s: %w", source, err)
 			}
+			listener = evmListener
 		}
 
-		sm.activeShutdowns = append(sm.activeShutdowns, shutdown)
+		sm.addListener(listener, shutdown)
 		listenerCount++
 		fmt.Printf("     ✅ Started listener for %s\n", source)
+		if !config.NetworkEnabled(source) {
+			fmt.Printf("     ⏸️  %s is paused, its listener idles until resumed\n", source)
+		}
 	}
 
 	fmt.Printf("   📡 All network listeners started (%d networks)\n", listenerCount)
 	return nil
 }
 
+// addListener tracks a started listener for snapshots and shutdown
+func (sm *SolverManager) addListener(listener base.Listener, shutdown base.ShutdownFunc) {
+	sm.activeShutdowns = append(sm.activeShutdowns, shutdown)
+	sm.listenersMu.Lock()
+	sm.listeners = append(sm.listeners, listener)
+	sm.listenersMu.Unlock()
+}
+
+// listenerSources returns the networks to listen on: the built-in networks followed by the
+// Starknet networks added through STARKNET_NETWORKS, sorted by name
+func listenerSources() []string {
+	sources := []string{"Base", "Optimism", "Arbitrum", "Ethereum", "Starknet"}
+	var added []string
+	for _, name := range config.StarknetNetworkNames() {
+		if !slices.Contains(sources, name) {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(added)
+	return append(sources, added...)
+}
+
 // AddSolver dynamically adds a new solver to the registry
 func (sm *SolverManager) AddSolver(name string, config SolverConfig) {
 	sm.solverRegistry[name] = config
@@ -362,21 +647,69 @@ func (sm *SolverManager) DisableSolver(name string) error {
 	return fmt.Errorf("solver %s not found", name)
 }
 
-// Start initializes and runs all solvers
+// Start initializes and runs all solvers. With leader election the solvers only start once this
+// instance leads; it returns an error when leadership is lost so a supervisor restarts it as a standby.
 func (sm *SolverManager) Start(ctx context.Context) error {
-	// Initialize all solvers
-	if err := sm.InitializeSolvers(ctx); err != nil {
+	election, err := leader.FromEnv()
+	if err != nil {
+		return fmt.Errorf("failed to set up leader election: %w", err)
+	}
+	if election == nil {
+		// Initialize all solvers
+		if err := sm.InitializeSolvers(ctx); err != nil {
+			return fmt.Errorf("failed to initialize solvers: %w", err)
+		}
+
+		// Wait for context cancellation (shutdown signal)
+		<-ctx.Done()
+
+		// Graceful shutdown
+		sm.Shutdown()
+		return nil
+	}
+	return sm.startAsCandidate(ctx, election)
+}
+
+// startAsCandidate keeps the clients ready while standing by, then runs the solvers while leading.
+// Listeners start after the election, so they resume from the checkpoints the previous leader left.
+func (sm *SolverManager) startAsCandidate(ctx context.Context, election *leader.Election) error {
+	if err := sm.initializeClients(); err != nil {
+		sm.Shutdown()
 		return fmt.Errorf("failed to initialize solvers: %w", err)
 	}
+	if err := election.WaitForLeadership(ctx); err != nil {
+		sm.Shutdown()
+		return nil
+	}
 
-	// Wait for context cancellation (shutdown signal)
-	<-ctx.Done()
+	// Solvers stop before the lock is released, so the next leader starts from their last checkpoints
+	defer election.Resign(context.Background())
+	leaderCtx := election.Lead(ctx)
+	if err := sm.startSolvers(leaderCtx); err != nil {
+		sm.Shutdown()
+		return fmt.Errorf("failed to initialize solvers: %w", err)
+	}
 
-	// Graceful shutdown
+	<-leaderCtx.Done()
 	sm.Shutdown()
+	if ctx.Err() == nil {
+		return fmt.Errorf("lost leadership (%s), stopped submitting transactions", election.Name())
+	}
 	return nil
 }
 
+// ListenerSnapshots returns the block progress of every running listener
+func (sm *SolverManager) ListenerSnapshots() []base.ListenerSnapshot {
+	sm.listenersMu.RLock()
+	defer sm.listenersMu.RUnlock()
+
+	snapshots := make([]base.ListenerSnapshot, 0, len(sm.listeners))
+	for _, listener := range sm.listeners {
+		snapshots = append(snapshots, listener.Snapshot())
+	}
+	return snapshots
+}
+
 // Shutdown stops all active solvers
 func (sm *SolverManager) Shutdown() {
 	fmt.Printf("🛑 Shutting down solvers...\n")
@@ -388,9 +721,58 @@ func (sm *SolverManager) Shutdown() {
 	}
 
 	sm.activeShutdowns = make([]func(), 0)
+	sm.listenersMu.Lock()
+	sm.listeners = nil
+	sm.listenersMu.Unlock()
+
+	if sm.adminServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), adminShutdownTimeout)
+		if err := sm.adminServer.Shutdown(ctx); err != nil {
+			fmt.Printf("⚠️  Failed to stop admin API: %v\n", err)
+		}
+		cancel()
+		sm.adminServer = nil
+		sm.stopAdminEvents()
+	}
+
+	if sm.recorder != nil {
+		if err := sm.recorder.Close(); err != nil {
+			fmt.Printf("⚠️  Failed to close recording %s: %v\n", sm.recorder.Path(), err)
+		} else {
+			fmt.Printf("📼 Recording saved to %s\n", sm.recorder.Path())
+		}
+		sm.recorder = nil
+	}
 	fmt.Printf("✅ All solvers shut down successfully (%d listeners stopped)\n", listenerCount)
 }
 
+// Replay runs the Hyperlane7683 solver on a recording instead of the live chains.
+// Nothing reaches the network, and the order store and solver state are not touched.
+func (sm *SolverManager) Replay(ctx context.Context, recording *replay.Recording) ([]contracts.ReplayedOrder, error) {
+	for networkName, networkConfig := range config.Networks {
+		if config.IsStarknetNetwork(networkName) {
+			sm.starknetClients[networkConfig.ChainID] = recording.StarknetProvider(networkConfig.ChainID)
+			continue
+		}
+		sm.evmClients[networkConfig.ChainID] = recording.EVMClient(networkConfig.ChainID)
+	}
+
+	hyperlane7683Solver := contracts.NewHyperlane7683Solver(
+		sm.GetEVMClient,
+		sm.GetStarknetClient,
+		sm.GetEVMSigner,
+		sm.GetStarknetSigner,
+		sm.allowBlockLists,
+	)
+	hyperlane7683Solver.AddDefaultRules()
+	sm.addCustomRules(hyperlane7683Solver)
+
+	eventHandler := func(args types.ParsedArgs, originChainName string, blockNumber uint64) (bool, error) {
+		return hyperlane7683Solver.ProcessIntent(ctx, &args)
+	}
+	return contracts.ReplayRecording(ctx, recording, eventHandler)
+}
+
 // GetSolverStatus returns the status of all solvers
 func (sm *SolverManager) GetSolverStatus() map[string]bool {
 	status := make(map[string]bool)
@@ -401,14 +783,15 @@ func (sm *SolverManager) GetSolverStatus() map[string]bool {
 }
 
 
-// getStarknetHyperlaneAddress gets the Starknet Hyperlane address from environment
-func getStarknetHyperlaneAddress(_ *config.NetworkConfig) (string, error) {
-	envAddr := envutil.GetEnvWithDefault("STARKNET_HYPERLANE_ADDRESS", "")
+// getStarknetHyperlaneAddress gets the Hyperlane address of a Starknet network from environment
+// (STARKNET_HYPERLANE_ADDRESS, or <NAME>_HYPERLANE_ADDRESS for networks added by STARKNET_NETWORKS)
+func getStarknetHyperlaneAddress(networkConfig *config.NetworkConfig) (string, error) {
+	envAddr := config.StarknetHyperlaneAddress(networkConfig.Name)
 	if envAddr != "" {
 		fmt.Printf("   🔄 Using Starknet Hyperlane address from .env: %s\n", envAddr)
 		return envAddr, nil
 	} else {
-		return "", fmt.Errorf("no STARKNET_HYPERLANE_ADDRESS set in .env")
+		return "", fmt.Errorf("no %s set in .env", config.StarknetHyperlaneAddressEnv(networkConfig.Name))
 	}
 }
 
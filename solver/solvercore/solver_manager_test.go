@@ -1,12 +1,16 @@
 package solvercore
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewSolverManager(t *testing.T) {
@@ -124,12 +128,44 @@ func TestGetEVMClientNotFound(t *testing.T) {
 func TestGetStarknetClientNotInitialized(t *testing.T) {
 	sm := NewSolverManager(&config.Config{})
 
-	client, err := sm.GetStarknetClient()
+	client, err := sm.GetStarknetClient(config.StarknetSepoliaChainID)
 	assert.Nil(t, client)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "starknet client not initialized")
 }
 
+func TestGetStarknetClientByChainID(t *testing.T) {
+	sm := NewSolverManager(&config.Config{})
+	sepolia := chainmock.NewStarknetBackend()
+	devnet := chainmock.NewStarknetBackend()
+	sm.starknetClients[config.StarknetSepoliaChainID] = sepolia
+	sm.starknetClients[5050] = devnet
+
+	client, err := sm.GetStarknetClient(5050)
+	require.NoError(t, err)
+	assert.Same(t, devnet, client)
+
+	client, err = sm.GetStarknetClient(config.StarknetSepoliaChainID)
+	require.NoError(t, err)
+	assert.Same(t, sepolia, client)
+
+	_, err = sm.GetStarknetClient(1)
+	assert.ErrorContains(t, err, "starknet client not initialized for chain ID 1")
+}
+
+func TestListenerSources(t *testing.T) {
+	t.Setenv("STARKNET_NETWORKS", "StarknetDevnet,Starknet Appchain")
+	t.Setenv("STARKNETDEVNET_RPC_URL", "http://localhost:5050")
+	t.Setenv("STARKNETDEVNET_CHAIN_ID", "5050")
+	t.Setenv("STARKNET_APPCHAIN_RPC_URL", "http://localhost:5051")
+	t.Setenv("STARKNET_APPCHAIN_CHAIN_ID", "5051")
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+
+	assert.Equal(t, []string{"Base", "Optimism", "Arbitrum", "Ethereum", "Starknet", "Starknet Appchain", "StarknetDevnet"}, listenerSources())
+}
+
 func TestGetEVMSigner(t *testing.T) {
 	sm := NewSolverManager(&config.Config{})
 
@@ -180,7 +216,7 @@ func TestGetEVMSignerInvalidKey(t *testing.T) {
 func TestGetStarknetSignerNotInitialized(t *testing.T) {
 	sm := NewSolverManager(&config.Config{})
 
-	signer, err := sm.GetStarknetSigner()
+	signer, err := sm.GetStarknetSigner(config.StarknetSepoliaChainID)
 	assert.Nil(t, signer)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "starknet client not initialized")
@@ -193,7 +229,7 @@ func TestGetStarknetSignerMissingKeys(t *testing.T) {
 	t.Setenv("IS_DEVNET", "false")
 	defer os.Unsetenv("IS_DEVNET")
 
-	signer, err := sm.GetStarknetSigner()
+	signer, err := sm.GetStarknetSigner(config.StarknetSepoliaChainID)
 	assert.Nil(t, signer)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "starknet client not initialized")
@@ -214,7 +250,7 @@ func TestGetStarknetSignerInvalidAddress(t *testing.T) {
 		os.Unsetenv("STARKNET_SOLVER_PRIVATE_KEY")
 	}()
 
-	signer, err := sm.GetStarknetSigner()
+	signer, err := sm.GetStarknetSigner(config.StarknetSepoliaChainID)
 	assert.Nil(t, signer)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "starknet client not initialized")
@@ -235,7 +271,7 @@ func TestGetStarknetSignerInvalidPrivateKey(t *testing.T) {
 		os.Unsetenv("STARKNET_SOLVER_PRIVATE_KEY")
 	}()
 
-	signer, err := sm.GetStarknetSigner()
+	signer, err := sm.GetStarknetSigner(config.StarknetSepoliaChainID)
 	assert.Nil(t, signer)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "starknet client not initialized")
@@ -268,6 +304,18 @@ func TestGetStarknetHyperlaneAddressMissing(t *testing.T) {
 	assert.Contains(t, err.Error(), "no STARKNET_HYPERLANE_ADDRESS set in .env")
 }
 
+func TestGetStarknetHyperlaneAddressAddedNetwork(t *testing.T) {
+	t.Setenv("STARKNET_HYPERLANE_ADDRESS", "0x1")
+	t.Setenv("STARKNETDEVNET_HYPERLANE_ADDRESS", "0x2")
+
+	addr, err := getStarknetHyperlaneAddress(&config.NetworkConfig{Name: "StarknetDevnet"})
+	require.NoError(t, err)
+	assert.Equal(t, "0x2", addr)
+
+	_, err = getStarknetHyperlaneAddress(&config.NetworkConfig{Name: "StarknetAppchain"})
+	assert.ErrorContains(t, err, "no STARKNETAPPCHAIN_HYPERLANE_ADDRESS set in .env")
+}
+
 func TestShutdown(t *testing.T) {
 	sm := NewSolverManager(&config.Config{})
 
@@ -283,3 +331,65 @@ func TestShutdown(t *testing.T) {
 	assert.Equal(t, 2, shutdownCount)
 	assert.Equal(t, 0, len(sm.activeShutdowns))
 }
+
+func TestReloadConfig(t *testing.T) {
+	config.InitializeNetworks()
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+
+	path := filepath.Join(t.TempDir(), "solver.config.json")
+	t.Setenv("SOLVER_CONFIG_FILE", path)
+	sm := NewSolverManager(&config.Config{})
+
+	t.Run("valid config replaces lists and rules", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte(`{
+			"allowBlockLists": {"blockList": [{"senderAddress": "0xbad", "destinationDomain": "*", "recipientAddress": "*"}]},
+			"rules": {"disabled": ["BalanceCheck"]},
+			"pollIntervalsMs": {"Base": 250}
+		}`), 0o600))
+
+		runtime, err := sm.ReloadConfig()
+		require.NoError(t, err)
+		assert.Same(t, runtime, config.ActiveRuntime())
+		assert.Equal(t, runtime.AllowBlockLists, sm.GetAllowBlockLists())
+		assert.Equal(t, 250, config.ActiveRuntime().PollInterval("Base", 1000))
+	})
+
+	t.Run("unknown rule keeps the active config", func(t *testing.T) {
+		active := config.ActiveRuntime()
+		require.NoError(t, os.WriteFile(path, []byte(`{"rules": {"disabled": ["NoSuchRule"]}}`), 0o600))
+
+		_, err := sm.ReloadConfig()
+		assert.ErrorContains(t, err, "NoSuchRule")
+		assert.Same(t, active, config.ActiveRuntime())
+		assert.Len(t, sm.GetAllowBlockLists().BlockList, 1)
+	})
+}
+
+func TestVerifyClientChainID(t *testing.T) {
+	base := config.NetworkConfig{Name: "Base", ChainID: config.BaseSepoliaChainID}
+	starknet := config.NetworkConfig{Name: "Starknet", ChainID: config.StarknetSepoliaChainID}
+
+	t.Run("EVM client on the configured chain", func(t *testing.T) {
+		assert.NoError(t, verifyEVMChainID(chainmock.NewEVMBackend(config.BaseSepoliaChainID), base))
+	})
+
+	t.Run("EVM client on another chain is refused", func(t *testing.T) {
+		err := verifyEVMChainID(chainmock.NewEVMBackend(config.OptimismSepoliaChainID), base)
+		assert.ErrorContains(t, err, "Base RPC reports chain ID 11155420, configured 84532")
+	})
+
+	t.Run("EVM chain ID unavailable", func(t *testing.T) {
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		backend.FailNext("ChainID", errors.New("connection refused"))
+		assert.ErrorContains(t, verifyEVMChainID(backend, base), "failed to read the chain ID of Base")
+	})
+
+	t.Run("Starknet provider on the configured chain", func(t *testing.T) {
+		assert.NoError(t, verifyStarknetChainID(chainmock.NewStarknetBackend(), starknet))
+	})
+
+	t.Run("Starknet provider on another chain is refused", func(t *testing.T) {
+		mainnet := config.NetworkConfig{Name: "Starknet", ChainID: config.StarknetMainnetChainID}
+		assert.ErrorContains(t, verifyStarknetChainID(chainmock.NewStarknetBackend(), mainnet), `reports chain ID "SN_SEPOLIA"`)
+	})
+}
@@ -0,0 +1,157 @@
+package hyperlane7683
+
+// Module: ERC20 approval policy and startup pre-warming
+// - Fills approve the Hyperlane7683 router for the tokens they spend when the current allowance is
+//   too low. The approval policy decides how much they approve: exactly the amount needed, or the
+//   maximum uint256 so a token is approved once per chain
+// - At startup, registry tokens with a preApprove amount have the router allowance of their chain
+//   raised to at least that amount (approving per the policy), so the first fills after a deployment
+//   don't wait for inline approval transactions. Native tokens are sent as value and never approved
+// - Pre-warming runs in the background; failures are logged and left to the inline approvals
+//
+// Settings:
+// - APPROVAL_POLICY: exact or unlimited (default exact)
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// ApprovalPolicy decides the amount approved when an allowance is too low
+type ApprovalPolicy string
+
+// Approval policies; the zero value behaves as ApprovalPolicyExact
+const (
+	ApprovalPolicyExact     ApprovalPolicy = "exact"
+	ApprovalPolicyUnlimited ApprovalPolicy = "unlimited"
+)
+
+// ParseApprovalPolicy parses an APPROVAL_POLICY value
+func ParseApprovalPolicy(value string) (ApprovalPolicy, error) {
+	switch policy := ApprovalPolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case ApprovalPolicyExact, ApprovalPolicyUnlimited:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown approval policy %q (use exact or unlimited)", value)
+	}
+}
+
+// approvalPolicyFromEnv returns the policy selected by APPROVAL_POLICY, falling back to exact
+func approvalPolicyFromEnv() ApprovalPolicy {
+	policy, err := ParseApprovalPolicy(envutil.GetEnvWithDefault("APPROVAL_POLICY", string(ApprovalPolicyExact)))
+	if err != nil {
+		fillerLog.Printf("⚠️  %v, using exact\n", err)
+		return ApprovalPolicyExact
+	}
+	return policy
+}
+
+// approveAmount returns the amount to approve when required is not covered by the allowance
+func (p ApprovalPolicy) approveAmount(required *big.Int) *big.Int {
+	if p == ApprovalPolicyUnlimited {
+		return new(big.Int).Set(math.MaxBig256)
+	}
+	return required
+}
+
+// approvalPrewarmer is implemented by chain handlers that can approve a token ahead of any order
+type approvalPrewarmer interface {
+	prewarmApproval(ctx context.Context, token, spender string, amount *big.Int) error
+}
+
+// PrewarmApprovals raises the router allowance of every registry token with a preApprove amount to at
+// least that amount. It returns the failures of all tokens; tokens already approved send nothing.
+func (f *Hyperlane7683Solver) PrewarmApprovals(ctx context.Context) error {
+	if f.observerMode {
+		return nil
+	}
+	var tokens []config.TokenConfig
+	for _, token := range config.ActiveRuntime().Tokens {
+		if token.PreApproveAmount() != nil && !types.IsNativeToken(token.Address) {
+			tokens = append(tokens, token)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+	fillerLog.Printf("🔓 Pre-approving %d tokens for the Hyperlane7683 routers (%s approvals)\n", len(tokens), f.approvalPolicy)
+
+	var errs []error
+	for _, token := range tokens {
+		if err := f.prewarmApproval(ctx, token); err != nil {
+			errs = append(errs, fmt.Errorf("%s on chain %d: %w", token.Symbol, token.ChainID, err))
+			continue
+		}
+		fillerLog.Printf("   ✅ %s on chain %d: router allowance covers %s\n", token.Symbol, token.ChainID, token.PreApprove)
+	}
+	return errors.Join(errs...)
+}
+
+// prewarmApproval ensures the router allowance of one token through the handler of its chain
+func (f *Hyperlane7683Solver) prewarmApproval(ctx context.Context, token config.TokenConfig) error {
+	router, err := hyperlaneRouterAddress(token.ChainID)
+	if err != nil {
+		return err
+	}
+	handler, _, err := f.handlerForChain(new(big.Int).SetUint64(token.ChainID))
+	if err != nil {
+		return err
+	}
+	prewarmer, ok := handler.(approvalPrewarmer)
+	if !ok {
+		return fmt.Errorf("chain handler does not support approvals")
+	}
+	return prewarmer.prewarmApproval(ctx, token.Address, router, token.PreApproveAmount())
+}
+
+// prewarmApproval approves spender for token when its allowance is below amount
+func (h *HyperlaneEVM) prewarmApproval(ctx context.Context, token, spender string, amount *big.Int) error {
+	tokenAddr, err := types.ToEVMAddress(token)
+	if err != nil {
+		return err
+	}
+	spenderAddr, err := types.ToEVMAddress(spender)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.ensureTokenApproval(ctx, nil, tokenAddr, spenderAddr, amount)
+}
+
+// prewarmApproval approves spender for token in its own invoke when its allowance is below amount
+func (h *HyperlaneStarknet) prewarmApproval(ctx context.Context, token, spender string, amount *big.Int) error {
+	tokenFelt, err := utils.HexToFelt(token)
+	if err != nil {
+		return fmt.Errorf("invalid Starknet token address %s: %w", token, err)
+	}
+	spenderFelt, err := utils.HexToFelt(spender)
+	if err != nil {
+		return fmt.Errorf("invalid Starknet router address %s: %w", spender, err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	calls, err := h.buildApprovalCalls(ctx, []tokenAmount{{token: tokenFelt, amount: amount}}, spenderFelt)
+	if err != nil || len(calls) == 0 {
+		return err
+	}
+	txHash, _, err := h.executeCalls(ctx, nil, orders.TxKindApprove, calls, new(big.Int), nil)
+	if err != nil {
+		return fmt.Errorf("starknet approve failed: %w", err)
+	}
+	fillerLog.Printf("   ✅ Approval confirmed: %s\n", txHash)
+	return nil
+}
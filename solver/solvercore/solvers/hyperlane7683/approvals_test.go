@@ -0,0 +1,156 @@
+package hyperlane7683
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestApprovalPolicy tests parsing the policy and the amounts it approves
+func TestApprovalPolicy(t *testing.T) {
+	t.Run("parse", func(t *testing.T) {
+		policy, err := ParseApprovalPolicy(" Unlimited ")
+		require.NoError(t, err)
+		assert.Equal(t, ApprovalPolicyUnlimited, policy)
+		_, err = ParseApprovalPolicy("infinite")
+		assert.ErrorContains(t, err, "unknown approval policy")
+	})
+
+	t.Run("from the environment", func(t *testing.T) {
+		t.Setenv("APPROVAL_POLICY", "unlimited")
+		assert.Equal(t, ApprovalPolicyUnlimited, approvalPolicyFromEnv())
+		t.Setenv("APPROVAL_POLICY", "bogus")
+		assert.Equal(t, ApprovalPolicyExact, approvalPolicyFromEnv())
+		t.Setenv("APPROVAL_POLICY", "")
+		assert.Equal(t, ApprovalPolicyExact, approvalPolicyFromEnv())
+	})
+
+	t.Run("approve amount", func(t *testing.T) {
+		required := big.NewInt(1000)
+		assert.Equal(t, required, ApprovalPolicyExact.approveAmount(required))
+		assert.Equal(t, required, ApprovalPolicy("").approveAmount(required))
+		assert.Equal(t, math.MaxBig256, ApprovalPolicyUnlimited.approveAmount(required))
+	})
+}
+
+// TestPrewarmApprovals tests approving the routers for the registry tokens at startup
+func TestPrewarmApprovals(t *testing.T) {
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+
+	t.Run("EVM tokens below their preApprove amount are approved", func(t *testing.T) {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		signer, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(config.BaseSepoliaChainID))
+		require.NoError(t, err)
+		router := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+		t.Setenv("SOLVER_PUB_KEY", signer.From.Hex())
+		t.Setenv("EVM_HYPERLANE_ADDRESS", router.Hex())
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		dog := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+		cat := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+			{ChainID: config.BaseSepoliaChainID, Address: dog.Hex(), Symbol: "DOG", Decimals: 6, PreApprove: "1000"},
+			{ChainID: config.BaseSepoliaChainID, Address: cat.Hex(), Symbol: "CAT", Decimals: 6, PreApprove: "10"},
+			{ChainID: config.BaseSepoliaChainID, Address: "0x0000000000000000000000000000000000000000", Symbol: "ETH", Decimals: 18, PreApprove: "1"},
+			{ChainID: config.BaseSepoliaChainID, Address: "0x00000000000000000000000000000000000000a3", Symbol: "COW", Decimals: 6},
+		}}))
+
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		backend.HandleCall(dog, chainmock.Selector("allowance(address,address)"), uint256Result(big.NewInt(5)))
+		backend.HandleCall(cat, chainmock.Selector("allowance(address,address)"), uint256Result(big.NewInt(10_000_000)))
+
+		for _, tc := range []struct {
+			policy   string
+			approved *big.Int
+		}{
+			{"exact", big.NewInt(1_000_000_000)},
+			{"unlimited", math.MaxBig256},
+		} {
+			t.Setenv("APPROVAL_POLICY", tc.policy)
+			solver := NewHyperlane7683Solver(
+				func(uint64) (EVMClient, error) { return backend, nil },
+				nil,
+				func(uint64) (*bind.TransactOpts, error) { return signer, nil },
+				nil,
+				types.AllowBlockLists{},
+			)
+			require.NoError(t, solver.PrewarmApprovals(context.Background()))
+
+			// Only DOG is below its amount; CAT is covered and ETH and COW are skipped
+			sent := backend.SentTo(dog)
+			require.NotEmpty(t, sent, tc.policy)
+			data := sent[len(sent)-1].Data()
+			approve := chainmock.Selector("approve(address,uint256)")
+			assert.True(t, bytes.HasPrefix(data, approve[:]), tc.policy)
+			assert.Equal(t, router, common.BytesToAddress(data[4:36]), tc.policy)
+			assert.Equal(t, tc.approved, new(big.Int).SetBytes(data[36:68]), tc.policy)
+		}
+		assert.Len(t, backend.Sent(), 2)
+	})
+
+	t.Run("Starknet tokens are approved in their own invoke", func(t *testing.T) {
+		t.Setenv("APPROVAL_POLICY", "unlimited")
+		t.Setenv("STARKNET_SOLVER_PUBLIC_KEY", "0x2c2b8f559e1221468140ad7b2352b1a5be32660d0bf1a3ae3a054a4ec5254e4")
+		t.Setenv("STARKNET_SOLVER_PRIVATE_KEY", "0x1")
+		t.Setenv("STARKNET_SOLVER_ADDRESS", "0x0000000000000000000000000000000000000000000000000000000000000abc")
+		t.Setenv("STARKNET_HYPERLANE_ADDRESS", "0x0000000000000000000000000000000000000000000000000000000000005678")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := "0x0000000000000000000000000000000000000000000000000000000000001234"
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+			{ChainID: config.StarknetSepoliaChainID, Address: token, Symbol: "DOG", Decimals: 18, PreApprove: "5"},
+		}}))
+		tokenFelt, err := utils.HexToFelt(token)
+		require.NoError(t, err)
+		routerFelt, err := utils.HexToFelt("0x5678")
+		require.NoError(t, err)
+
+		backend := chainmock.NewStarknetBackend()
+		backend.HandleCall(tokenFelt, "allowance", func(rpc.FunctionCall) ([]*felt.Felt, error) {
+			return []*felt.Felt{new(felt.Felt), new(felt.Felt)}, nil
+		})
+
+		solver := NewHyperlane7683Solver(
+			nil,
+			func(uint64) (rpc.RPCProvider, error) { return backend, nil },
+			nil, nil,
+			types.AllowBlockLists{},
+		)
+		require.NoError(t, solver.PrewarmApprovals(context.Background()))
+
+		invokes := backend.Invokes()
+		require.Len(t, invokes, 1)
+		calldata := invokes[0].Calldata
+		require.GreaterOrEqual(t, len(calldata), 3)
+		maxU128 := new(felt.Felt).SetBigInt(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1)))
+		assert.Equal(t, []*felt.Felt{routerFelt, maxU128, maxU128}, calldata[len(calldata)-3:])
+		assert.Contains(t, calldata, tokenFelt)
+	})
+
+	t.Run("failures are reported per token", func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+			{ChainID: 999, Address: "0x00000000000000000000000000000000000000a1", Symbol: "DOG", Decimals: 6, PreApprove: "1"},
+		}}))
+		solver := NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{})
+		assert.ErrorContains(t, solver.PrewarmApprovals(context.Background()), "DOG on chain 999")
+	})
+}
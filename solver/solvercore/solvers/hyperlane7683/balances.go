@@ -0,0 +1,271 @@
+package hyperlane7683
+
+// Module: Solver balance monitoring
+// - Reads the solver's balance of every token in the token registry on its chain (the zero address
+//   is the native asset on EVM chains) and publishes it as solver_token_balance{chain,token}, in whole tokens
+// - Tokens with a lowBalance threshold in the registry alert once when their balance drops below it:
+//   a log line, solver_token_balance_low{chain,token} set to 1 and a POST to the alert webhook.
+//   A balance back above the threshold clears the gauge and posts a recovery
+// - Failed reads are logged and leave the alert state of the token unchanged
+// - The same reads back the `solver balances` command; the last check is served by the admin API
+//
+// Settings:
+// - BALANCE_CHECK_INTERVAL_SECONDS: time between checks, 0 disables the monitor (default 300)
+// - BALANCE_ALERT_WEBHOOK_URL: receives alerts as JSON (BalanceAlert); unset only logs them
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	defaultBalanceCheckIntervalSeconds = 300
+	balanceWebhookTimeout              = 10 * time.Second
+)
+
+// Kinds of balance alerts
+const (
+	BalanceAlertLow       = "low_balance"
+	BalanceAlertRecovered = "balance_recovered"
+)
+
+// TokenBalance is the solver's balance of one registry token
+type TokenBalance struct {
+	ChainID   uint64 `json:"chainId"`
+	Network   string `json:"network"`
+	Token     string `json:"token"`
+	Symbol    string `json:"symbol"`
+	Balance   string `json:"balance,omitempty"`   // Whole tokens; empty when the read failed
+	Threshold string `json:"threshold,omitempty"` // Low-balance threshold in whole tokens
+	Low       bool   `json:"low"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BalanceAlert is posted to the webhook when a balance crosses its threshold
+type BalanceAlert struct {
+	Kind string    `json:"kind"` // BalanceAlertLow or BalanceAlertRecovered
+	Time time.Time `json:"time"`
+	TokenBalance
+}
+
+// BalanceMonitor reads the solver's token balances and alerts on low ones
+type BalanceMonitor struct {
+	// Dials clients the same way as the balance rule; nil getters dial the configured RPCs
+	clients  *BalanceRule
+	metrics  *metrics.Registry
+	interval time.Duration
+	webhook  string
+	http     *http.Client
+	now      func() time.Time
+
+	mu   sync.Mutex
+	low  map[string]bool // Tokens alerted as low, by chain and address
+	last []TokenBalance  // Balances of the last RunOnce
+}
+
+// NewBalanceMonitor creates a monitor using the given client getters; nil getters dial the configured RPCs
+func NewBalanceMonitor(getEVMClient func(chainID uint64) (EVMClient, error), getStarknetClient func(chainID uint64) (rpc.RPCProvider, error)) *BalanceMonitor {
+	return &BalanceMonitor{
+		clients:  &BalanceRule{getEVMClient: getEVMClient, getStarknetClient: getStarknetClient},
+		metrics:  metrics.Default(),
+		interval: time.Duration(envutil.GetEnvInt("BALANCE_CHECK_INTERVAL_SECONDS", defaultBalanceCheckIntervalSeconds)) * time.Second,
+		webhook:  os.Getenv("BALANCE_ALERT_WEBHOOK_URL"),
+		http:     &http.Client{Timeout: balanceWebhookTimeout},
+		now:      time.Now,
+		low:      make(map[string]bool),
+	}
+}
+
+// Start checks the balances right away and then every interval until ctx is done or the returned
+// function is called. It returns a no-op shutdown function when the monitor is disabled.
+func (m *BalanceMonitor) Start(ctx context.Context) func() {
+	if m.interval <= 0 {
+		fmt.Printf("   ⏭️  Balance monitoring disabled\n")
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			m.RunOnce(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	fmt.Printf("   💰 Balance monitoring every %s\n", m.interval)
+	return cancel
+}
+
+// RunOnce reads every balance, publishes the metrics and sends alerts for thresholds crossed since the last run
+func (m *BalanceMonitor) RunOnce(ctx context.Context) []TokenBalance {
+	balances := m.Balances(ctx)
+	m.mu.Lock()
+	m.last = balances
+	m.mu.Unlock()
+	for _, balance := range balances {
+		if balance.Error != "" {
+			fmt.Printf("⚠️  Failed to read %s balance on %s: %s\n", balance.Symbol, balance.Network, balance.Error)
+			continue
+		}
+		if value, err := strconv.ParseFloat(balance.Balance, 64); err == nil {
+			m.metrics.Gauge("solver_token_balance", "chain", balance.Network, "token", balance.Symbol).Set(value)
+		}
+		if balance.Threshold == "" {
+			continue
+		}
+
+		low := 0.0
+		if balance.Low {
+			low = 1
+		}
+		m.metrics.Gauge("solver_token_balance_low", "chain", balance.Network, "token", balance.Symbol).Set(low)
+
+		key := fmt.Sprintf("%d/%s", balance.ChainID, balance.Token)
+		m.mu.Lock()
+		wasLow := m.low[key]
+		m.low[key] = balance.Low
+		m.mu.Unlock()
+
+		switch {
+		case balance.Low && !wasLow:
+			fmt.Printf("🪫 Low balance on %s: %s %s, threshold %s\n", balance.Network, balance.Balance, balance.Symbol, balance.Threshold)
+			m.alert(ctx, BalanceAlertLow, balance)
+		case !balance.Low && wasLow:
+			fmt.Printf("🔋 Balance recovered on %s: %s %s, threshold %s\n", balance.Network, balance.Balance, balance.Symbol, balance.Threshold)
+			m.alert(ctx, BalanceAlertRecovered, balance)
+		}
+	}
+	return balances
+}
+
+// LastBalances returns the balances read by the last RunOnce, nil before the first check
+func (m *BalanceMonitor) LastBalances() []TokenBalance {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TokenBalance(nil), m.last...)
+}
+
+// Balances reads the solver's balance of every registry token, sorted by network and symbol
+func (m *BalanceMonitor) Balances(ctx context.Context) []TokenBalance {
+	tokens := config.ActiveRuntime().Tokens
+	balances := make([]TokenBalance, 0, len(tokens))
+	for _, token := range tokens {
+		balance := TokenBalance{ChainID: token.ChainID, Token: token.Address, Symbol: token.Symbol, Network: fmt.Sprintf("%d", token.ChainID)}
+		threshold := token.LowBalanceThreshold()
+		if threshold != nil {
+			balance.Threshold = amount.New(threshold, token.Decimals).String()
+		}
+
+		network, ok := networkForChainID(token.ChainID)
+		if !ok {
+			balance.Error = "network not configured"
+			balances = append(balances, balance)
+			continue
+		}
+		balance.Network = network.Name
+
+		units, err := m.readBalance(ctx, network, token.Address)
+		if err != nil {
+			balance.Error = err.Error()
+		} else {
+			balance.Balance = amount.New(units, token.Decimals).String()
+			balance.Low = threshold != nil && units.Cmp(threshold) < 0
+		}
+		balances = append(balances, balance)
+	}
+
+	sort.SliceStable(balances, func(i, j int) bool {
+		if balances[i].Network != balances[j].Network {
+			return balances[i].Network < balances[j].Network
+		}
+		return balances[i].Symbol < balances[j].Symbol
+	})
+	return balances
+}
+
+// readBalance reads the solver's balance of token on network
+func (m *BalanceMonitor) readBalance(ctx context.Context, network config.NetworkConfig, token string) (*big.Int, error) {
+	if config.IsStarknetNetwork(network.Name) {
+		solver := envutil.GetStarknetSolverAddress()
+		if solver == "" {
+			return nil, fmt.Errorf("Starknet solver address not set")
+		}
+		provider, err := m.clients.starknetProvider(network.ChainID)
+		if err != nil {
+			return nil, err
+		}
+		return starknetutil.ERC20Balance(provider, token, solver)
+	}
+
+	solver := envutil.GetSolverPublicKey()
+	if solver == "" {
+		return nil, fmt.Errorf("solver public key not set")
+	}
+	client, release, err := m.clients.evmClient(&network)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if types.IsNativeToken(token) {
+		return client.BalanceAt(ctx, common.HexToAddress(solver), nil)
+	}
+	tokenAddr, err := types.ToEVMAddress(token)
+	if err != nil {
+		return nil, err
+	}
+	return ethutil.ERC20Balance(client, tokenAddr, common.HexToAddress(solver))
+}
+
+// alert posts a balance alert to the webhook, if one is configured
+func (m *BalanceMonitor) alert(ctx context.Context, kind string, balance TokenBalance) {
+	if m.webhook == "" {
+		return
+	}
+	body, err := json.Marshal(BalanceAlert{Kind: kind, Time: m.now().UTC(), TokenBalance: balance})
+	if err != nil {
+		fmt.Printf("⚠️  Failed to encode balance alert: %v\n", err)
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.webhook, bytes.NewReader(body))
+	if err != nil {
+		fmt.Printf("⚠️  Failed to create balance alert request: %v\n", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := m.http.Do(req)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to send balance alert: %v\n", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		fmt.Printf("⚠️  Balance alert webhook returned %s\n", resp.Status)
+	}
+}
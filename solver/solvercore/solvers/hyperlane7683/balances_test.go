@@ -0,0 +1,117 @@
+package hyperlane7683
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBalanceMonitor tests balance reads and low-balance alerts
+func TestBalanceMonitor(t *testing.T) {
+	solver := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	t.Setenv("SOLVER_PUB_KEY", solver.Hex())
+	t.Setenv("STARKNET_SOLVER_ADDRESS", "0x0000000000000000000000000000000000000000000000000000000000000abc")
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+
+	dog := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	strk := "0x0000000000000000000000000000000000000000000000000000000000001234"
+	require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+		{ChainID: config.BaseSepoliaChainID, Address: dog.Hex(), Symbol: "DOG", Decimals: 6, LowBalance: "100"},
+		{ChainID: config.BaseSepoliaChainID, Address: "0x0000000000000000000000000000000000000000", Symbol: "ETH", Decimals: 18},
+		{ChainID: config.StarknetSepoliaChainID, Address: strk, Symbol: "STRK", Decimals: 18, LowBalance: "1"},
+		{ChainID: 999, Address: dog.Hex(), Symbol: "DOG", Decimals: 6},
+	}}))
+
+	evm := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+	evm.SetBalance(solver, big.NewInt(2_500_000_000_000_000_000))
+	var dogBalance sync.Mutex
+	dogUnits := big.NewInt(50_000_000)
+	evm.HandleCall(dog, chainmock.Selector("balanceOf(address)"), func(ethereum.CallMsg) ([]byte, error) {
+		dogBalance.Lock()
+		defer dogBalance.Unlock()
+		return common.LeftPadBytes(dogUnits.Bytes(), 32), nil
+	})
+
+	starknet := chainmock.NewStarknetBackend()
+	strkFelt, err := utils.HexToFelt(strk)
+	require.NoError(t, err)
+	starknet.HandleCall(strkFelt, "balanceOf", func(rpc.FunctionCall) ([]*felt.Felt, error) {
+		return []*felt.Felt{new(felt.Felt).SetUint64(3_000_000_000_000_000_000), new(felt.Felt)}, nil
+	})
+
+	var alerts []BalanceAlert
+	var alertsMu sync.Mutex
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert BalanceAlert
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		alertsMu.Lock()
+		alerts = append(alerts, alert)
+		alertsMu.Unlock()
+	}))
+	defer webhook.Close()
+
+	monitor := NewBalanceMonitor(
+		func(uint64) (EVMClient, error) { return evm, nil },
+		func(uint64) (rpc.RPCProvider, error) { return starknet, nil },
+	)
+	monitor.metrics = metrics.NewRegistry()
+	monitor.webhook = webhook.URL
+	ctx := context.Background()
+
+	t.Run("reads every registry token", func(t *testing.T) {
+		balances := monitor.Balances(ctx)
+		require.Len(t, balances, 4)
+
+		assert.Equal(t, "999", balances[0].Network)
+		assert.Equal(t, "network not configured", balances[0].Error)
+		assert.Equal(t, TokenBalance{ChainID: config.BaseSepoliaChainID, Network: "Base", Token: dog.Hex(), Symbol: "DOG", Balance: "50", Threshold: "100", Low: true}, balances[1])
+		assert.Equal(t, "2.5", balances[2].Balance, "native balance")
+		assert.False(t, balances[2].Low, "no threshold")
+		assert.Equal(t, TokenBalance{ChainID: config.StarknetSepoliaChainID, Network: "Starknet", Token: strk, Symbol: "STRK", Balance: "3", Threshold: "1"}, balances[3])
+	})
+
+	t.Run("alerts once when a balance drops below its threshold", func(t *testing.T) {
+		assert.Nil(t, monitor.LastBalances(), "no check yet")
+		monitor.RunOnce(ctx)
+		monitor.RunOnce(ctx)
+
+		require.Len(t, alerts, 1)
+		assert.Equal(t, BalanceAlertLow, alerts[0].Kind)
+		assert.Equal(t, "DOG", alerts[0].Symbol)
+		assert.Equal(t, "50", alerts[0].Balance)
+		assert.Equal(t, 50.0, monitor.metrics.Gauge("solver_token_balance", "chain", "Base", "token", "DOG").Value())
+		assert.Equal(t, 1.0, monitor.metrics.Gauge("solver_token_balance_low", "chain", "Base", "token", "DOG").Value())
+		assert.Equal(t, 0.0, monitor.metrics.Gauge("solver_token_balance_low", "chain", "Starknet", "token", "STRK").Value())
+	})
+
+	t.Run("alerts when the balance recovers", func(t *testing.T) {
+		dogBalance.Lock()
+		dogUnits = big.NewInt(150_000_000)
+		dogBalance.Unlock()
+		monitor.RunOnce(ctx)
+
+		require.Len(t, alerts, 2)
+		assert.Equal(t, BalanceAlertRecovered, alerts[1].Kind)
+		assert.Equal(t, "150", alerts[1].Balance)
+		assert.Equal(t, "150", monitor.LastBalances()[1].Balance, "last check kept")
+		assert.Equal(t, 0.0, monitor.metrics.Gauge("solver_token_balance_low", "chain", "Base", "token", "DOG").Value())
+	})
+}
@@ -0,0 +1,190 @@
+package hyperlane7683
+
+// Benchmarks for the order hot path: event decoding, origin_data conversion, rules evaluation and
+// a synthetic end-to-end run of orders through mock chains. Run with `make test-bench`.
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math/big"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// silenceStdout drops the solver's console logging for the rest of the benchmark
+func silenceStdout(b *testing.B) {
+	b.Helper()
+	devNull, err := os.Open(os.DevNull)
+	require.NoError(b, err)
+	stdout := os.Stdout
+	os.Stdout = devNull
+	b.Cleanup(func() {
+		os.Stdout = stdout
+		devNull.Close()
+	})
+}
+
+// benchOrderID returns a distinct order ID for iteration i
+func benchOrderID(i int) common.Hash {
+	var orderID common.Hash
+	binary.BigEndian.PutUint64(orderID[24:], uint64(i)+1)
+	return orderID
+}
+
+func BenchmarkDecodeEVMOpenEvent(b *testing.B) {
+	silenceStdout(b)
+	settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	log := openLog(b, settler, benchOrderID(0), 12)
+	filterer, err := contracts.NewHyperlane7683Filterer(settler, nil)
+	require.NoError(b, err)
+	listener := &evmListener{config: &base.ListenerConfig{ChainName: "Ethereum"}}
+	handler := func(types.ParsedArgs, string, uint64) (bool, error) { return true, nil }
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		event, err := filterer.ParseOpen(log)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := listener.handleParsedOpenEvent(event, handler); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeStarknetOpenEvent(b *testing.B) {
+	data := openEventFelts()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, err := decodeResolvedOrderFromFelts(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBuildFillCall(b *testing.B) {
+	orderID := benchOrderID(0).Hex()
+	originData := make([]byte, evmOriginDataSize)
+	for i := range originData {
+		originData[i] = byte(i)
+	}
+	settler, err := utils.HexToFelt("0x5678")
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, err := buildFillCall(orderID, originData, settler); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRulesEngine(b *testing.B) {
+	silenceStdout(b)
+	b.Setenv("SOLVER_PUB_KEY", "0x00000000000000000000000000000000000000aa")
+	b.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	b.Cleanup(config.ResetNetworks)
+
+	token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+	backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(1_000_000)))
+	engine := NewRulesEngineWithClients(func(uint64) (EVMClient, error) { return backend, nil }, nil)
+	args := endToEndArgs(benchOrderID(0).Hex(), token.Hex(),
+		"0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if result := engine.EvaluateAll(context.Background(), &args); !result.Passed {
+			b.Fatal(result.Reason)
+		}
+	}
+}
+
+// BenchmarkProcessIntentEndToEnd drives b.N distinct orders through rules, approve, fill and
+// settle against a mock EVM destination chain
+func BenchmarkProcessIntentEndToEnd(b *testing.B) {
+	silenceStdout(b)
+	approvalDelay, settleDelay := approvalSettleDelay, fillSettleDelay
+	approvalSettleDelay, fillSettleDelay = 0, 0
+	b.Cleanup(func() { approvalSettleDelay, fillSettleDelay = approvalDelay, settleDelay })
+	key, err := crypto.GenerateKey()
+	require.NoError(b, err)
+	signer, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(config.BaseSepoliaChainID))
+	require.NoError(b, err)
+	b.Setenv("SOLVER_PUB_KEY", signer.From.Hex())
+	b.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	b.Cleanup(config.ResetNetworks)
+
+	token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+
+	// The settler reports an order FILLED once a fill for it was sent
+	var filledMu sync.Mutex
+	filled := make(map[common.Hash]bool)
+	fillSelector := chainmock.Selector("fill(bytes32,bytes,bytes)")
+	backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(new(big.Int).Lsh(big.NewInt(1), 128)))
+	backend.HandleCall(token, chainmock.Selector("allowance(address,address)"), uint256Result(new(big.Int).Lsh(big.NewInt(1), 128)))
+	backend.HandleCall(settler, chainmock.Selector("orderStatus(bytes32)"), func(msg ethereum.CallMsg) ([]byte, error) {
+		filledMu.Lock()
+		defer filledMu.Unlock()
+		if filled[common.BytesToHash(msg.Data[4:36])] {
+			return common.RightPadBytes([]byte(orderStatusFilled), 32), nil
+		}
+		return make([]byte, 32), nil
+	})
+	backend.HandleCall(settler, chainmock.Selector("quoteGasPayment(uint32)"), uint256Result(big.NewInt(7)))
+	backend.OnTransaction(settler, func(tx *gethtypes.Transaction) uint64 {
+		if bytes.HasPrefix(tx.Data(), fillSelector[:]) {
+			filledMu.Lock()
+			filled[common.BytesToHash(tx.Data()[4:36])] = true
+			filledMu.Unlock()
+		}
+		return gethtypes.ReceiptStatusSuccessful
+	})
+
+	solver := NewHyperlane7683Solver(
+		func(uint64) (EVMClient, error) { return backend, nil },
+		nil,
+		func(uint64) (*bind.TransactOpts, error) { return signer, nil },
+		nil,
+		types.AllowBlockLists{},
+	)
+	orders := make([]types.ParsedArgs, b.N)
+	for i := range orders {
+		orders[i] = endToEndArgs(benchOrderID(i).Hex(), token.Hex(), settler.Hex(), config.BaseSepoliaChainID)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		ok, err := solver.ProcessIntent(context.Background(), &orders[i])
+		if err != nil || !ok {
+			b.Fatalf("order %d not processed: %v", i, err)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package hyperlane7683
 
 import (
 	"context"
+	"time"
 
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 )
 
@@ -13,6 +15,7 @@ const (
 	OrderActionSettle   OrderAction = iota // Order needs settlement
 	OrderActionComplete                    // Order is 100% complete (filled + settled)
 	OrderActionError                       // Error occurred during fill
+	OrderActionLostRace                    // Another solver filled the order first; nothing to settle
 )
 
 // ChainHandler defines the interface that all chain-specific handlers must implement.
@@ -38,6 +41,43 @@ type ChainHandler interface {
 	GetOrderStatus(ctx context.Context, args *types.ParsedArgs) (string, error)
 }
 
+// txCostRecorder receives the cost of every transaction a handler sends for an order
+type txCostRecorder func(args *types.ParsedArgs, cost orders.TxCost)
+
+// record forwards cost to the recorder, if one is set
+func (r txCostRecorder) record(args *types.ParsedArgs, cost orders.TxCost) {
+	if r != nil {
+		r(args, cost)
+	}
+}
+
+// txSentRecorder receives every fill and settle transaction a handler broadcasts for an order
+type txSentRecorder func(orderID string, tx orders.OrderTx)
+
+// record forwards a fill or settle of an order to the recorder, if one is set; approvals and
+// transactions sent outside an order are skipped
+func (r txSentRecorder) record(orderID string, chainID uint64, kind, txHash string) {
+	switch kind {
+	case orders.TxKindFill, orders.TxKindSettle, orders.TxKindFillSettle:
+	default:
+		return
+	}
+	if r != nil && orderID != "" {
+		r(orderID, orders.OrderTx{ChainID: chainID, Kind: kind, TxHash: txHash, SentAt: time.Now()})
+	}
+}
+
+// fillOwnership reports whether the solver itself sent the fill of an order
+type fillOwnership func(orderID string) bool
+
+// filledByUs asks the ownership check, if one is set; without one every fill is assumed to be ours
+func (f fillOwnership) filledByUs(orderID string) bool {
+	if f == nil {
+		return true
+	}
+	return f(orderID)
+}
+
 // ChainHandlerFactory creates chain handlers for specific networks
 // This allows the solver to create handlers on-demand for different chains
 type ChainHandlerFactory interface {
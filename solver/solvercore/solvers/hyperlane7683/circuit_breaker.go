@@ -0,0 +1,250 @@
+package hyperlane7683
+
+// Module: Per-chain circuit breaker
+// - Counts consecutive failures (RPC or transaction) per chain ID
+// - After the threshold is reached the circuit opens: fills targeting the chain and polling of
+//   the chain are paused for a cool-down period, and an alert is emitted
+// - Fills and settles and listener polling count their failures in separate breakers, so polls that
+//   keep succeeding don't reset the failures of fills that keep failing (and the other way round)
+// - After the cool-down a single probe is let through (half-open); success closes the circuit,
+//   failure re-opens it for another cool-down. A probe that ends without telling either (a
+//   transaction still pending) is released, and the next operation probes instead.
+// - Orders refused before sending (spend limits, origin_data mismatch) say nothing about the chain
+//   and are not counted, so a few bad orders can't pause it.
+//
+// Settings:
+// - CIRCUIT_BREAKER_FAILURE_THRESHOLD: consecutive failures before opening (default 5)
+// - CIRCUIT_BREAKER_COOLDOWN_SECONDS: how long the circuit stays open before probing (default 60)
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+)
+
+const (
+	defaultCircuitFailureThreshold = 5
+	defaultCircuitCooldownSeconds  = 60
+)
+
+// ErrCircuitOpen is returned by Allow while a chain's circuit is open
+var ErrCircuitOpen = errors.New("circuit open")
+
+// isChainFailure reports whether a failed fill or settle counts against its chain's circuit: pending
+// transactions and orders refused before sending don't
+func isChainFailure(err error) bool {
+	return !errors.Is(err, ErrTxPending) && !errors.Is(err, ErrSpendLimitReached) && !errors.Is(err, ErrOriginDataMismatch)
+}
+
+// CircuitState is the state of a single chain's circuit
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // Normal operation
+	CircuitOpen                         // Paused until the cool-down elapses
+	CircuitHalfOpen                     // Cool-down elapsed, one probe in flight
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitAlert describes a circuit state change worth alerting on
+type CircuitAlert struct {
+	ChainID  uint64
+	State    CircuitState
+	Failures int
+	LastErr  error
+}
+
+// chainCircuit tracks failures for one chain
+type chainCircuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	lastErr  error
+}
+
+// CircuitBreaker tracks circuits for all chains
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+	onAlert   func(CircuitAlert)
+	chains    map[uint64]*chainCircuit
+}
+
+// NewCircuitBreaker creates a circuit breaker; alerts are logged by default
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = defaultCircuitFailureThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = defaultCircuitCooldownSeconds * time.Second
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+		onAlert:   logCircuitAlert,
+		chains:    make(map[uint64]*chainCircuit),
+	}
+}
+
+var (
+	defaultCircuitBreaker         *CircuitBreaker
+	defaultCircuitBreakerOnce     sync.Once
+	defaultPollCircuitBreaker     *CircuitBreaker
+	defaultPollCircuitBreakerOnce sync.Once
+)
+
+// DefaultCircuitBreaker returns the process-wide breaker of fills and settles
+func DefaultCircuitBreaker() *CircuitBreaker {
+	defaultCircuitBreakerOnce.Do(func() {
+		defaultCircuitBreaker = newCircuitBreakerFromEnv()
+	})
+	return defaultCircuitBreaker
+}
+
+// DefaultPollCircuitBreaker returns the process-wide breaker of listener polling
+func DefaultPollCircuitBreaker() *CircuitBreaker {
+	defaultPollCircuitBreakerOnce.Do(func() {
+		defaultPollCircuitBreaker = newCircuitBreakerFromEnv()
+	})
+	return defaultPollCircuitBreaker
+}
+
+func newCircuitBreakerFromEnv() *CircuitBreaker {
+	return NewCircuitBreaker(
+		envutil.GetEnvInt("CIRCUIT_BREAKER_FAILURE_THRESHOLD", defaultCircuitFailureThreshold),
+		time.Duration(envutil.GetEnvInt("CIRCUIT_BREAKER_COOLDOWN_SECONDS", defaultCircuitCooldownSeconds))*time.Second,
+	)
+}
+
+// SetAlertHandler replaces the alert handler (e.g. to forward alerts to metrics or paging)
+func (cb *CircuitBreaker) SetAlertHandler(handler func(CircuitAlert)) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.onAlert = handler
+}
+
+// Allow returns nil if an operation on chainID may proceed, or an error wrapping ErrCircuitOpen.
+// Once the cool-down has elapsed, exactly one caller is let through as a recovery probe.
+func (cb *CircuitBreaker) Allow(chainID uint64) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c, ok := cb.chains[chainID]
+	if !ok {
+		return nil
+	}
+
+	switch c.state {
+	case CircuitOpen:
+		remaining := cb.cooldown - cb.now().Sub(c.openedAt)
+		if remaining > 0 {
+			return fmt.Errorf("%w for chain %d (retry in %s): %v", ErrCircuitOpen, chainID, remaining.Round(time.Second), c.lastErr)
+		}
+		c.state = CircuitHalfOpen
+		logutil.LogWithNetworkTagf(logutil.NetworkNameByChainID(chainID), "🔌 Circuit half-open, probing for recovery\n")
+		return nil
+	case CircuitHalfOpen:
+		return fmt.Errorf("%w for chain %d (recovery probe in flight)", ErrCircuitOpen, chainID)
+	default:
+		return nil
+	}
+}
+
+// RecordSuccess resets the chain's failure count and closes its circuit
+func (cb *CircuitBreaker) RecordSuccess(chainID uint64) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c, ok := cb.chains[chainID]
+	if !ok {
+		return
+	}
+
+	wasTripped := c.state != CircuitClosed
+	c.state = CircuitClosed
+	c.failures = 0
+	c.lastErr = nil
+	if wasTripped {
+		cb.alert(CircuitAlert{ChainID: chainID, State: CircuitClosed})
+	}
+}
+
+// RecordFailure counts a failure; reaching the threshold (or failing a probe) opens the circuit
+func (cb *CircuitBreaker) RecordFailure(chainID uint64, err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c, ok := cb.chains[chainID]
+	if !ok {
+		c = &chainCircuit{}
+		cb.chains[chainID] = c
+	}
+
+	c.failures++
+	c.lastErr = err
+
+	if c.state == CircuitHalfOpen || (c.state == CircuitClosed && c.failures >= cb.threshold) {
+		c.state = CircuitOpen
+		c.openedAt = cb.now()
+		cb.alert(CircuitAlert{ChainID: chainID, State: CircuitOpen, Failures: c.failures, LastErr: err})
+	}
+}
+
+// ReleaseProbe ends a recovery probe that neither succeeded nor failed: the circuit stays open with
+// its cool-down elapsed, so the next operation is let through as the probe
+func (cb *CircuitBreaker) ReleaseProbe(chainID uint64) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if c, ok := cb.chains[chainID]; ok && c.state == CircuitHalfOpen {
+		c.state = CircuitOpen
+	}
+}
+
+// State returns the current state of the chain's circuit
+func (cb *CircuitBreaker) State(chainID uint64) CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if c, ok := cb.chains[chainID]; ok {
+		return c.state
+	}
+	return CircuitClosed
+}
+
+// alert must be called with cb.mu held
+func (cb *CircuitBreaker) alert(a CircuitAlert) {
+	if cb.onAlert != nil {
+		cb.onAlert(a)
+	}
+}
+
+// logCircuitAlert is the default alert handler
+func logCircuitAlert(a CircuitAlert) {
+	networkName := logutil.NetworkNameByChainID(a.ChainID)
+	switch a.State {
+	case CircuitOpen:
+		logutil.LogWithNetworkTagf(networkName, "🚨 ALERT: circuit opened after %d consecutive failures, pausing chain: %v\n", a.Failures, a.LastErr)
+	case CircuitClosed:
+		logutil.LogWithNetworkTagf(networkName, "✅ Circuit closed, chain recovered\n")
+	}
+}
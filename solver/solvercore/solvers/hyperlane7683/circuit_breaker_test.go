@@ -0,0 +1,168 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// newTestCircuitBreaker returns a breaker with a controllable clock and recorded alerts
+func newTestCircuitBreaker(threshold int, cooldown time.Duration) (*CircuitBreaker, *time.Time, *[]CircuitAlert) {
+	now := time.Unix(1_700_000_000, 0)
+	var alerts []CircuitAlert
+
+	cb := NewCircuitBreaker(threshold, cooldown)
+	cb.now = func() time.Time { return now }
+	cb.SetAlertHandler(func(a CircuitAlert) { alerts = append(alerts, a) })
+	return cb, &now, &alerts
+}
+
+// TestCircuitBreaker tests the per-chain circuit breaker state machine
+func TestCircuitBreaker(t *testing.T) {
+	const chainID = 11155111
+	rpcErr := errors.New("connection refused")
+
+	t.Run("opens_after_threshold", func(t *testing.T) {
+		cb, _, alerts := newTestCircuitBreaker(3, time.Minute)
+
+		cb.RecordFailure(chainID, rpcErr)
+		cb.RecordFailure(chainID, rpcErr)
+		assert.NoError(t, cb.Allow(chainID))
+		assert.Empty(t, *alerts)
+
+		cb.RecordFailure(chainID, rpcErr)
+		assert.Equal(t, CircuitOpen, cb.State(chainID))
+		assert.ErrorIs(t, cb.Allow(chainID), ErrCircuitOpen)
+		require.Len(t, *alerts, 1)
+		assert.Equal(t, CircuitOpen, (*alerts)[0].State)
+		assert.Equal(t, 3, (*alerts)[0].Failures)
+	})
+
+	t.Run("success_resets_failure_count", func(t *testing.T) {
+		cb, _, _ := newTestCircuitBreaker(2, time.Minute)
+
+		cb.RecordFailure(chainID, rpcErr)
+		cb.RecordSuccess(chainID)
+		cb.RecordFailure(chainID, rpcErr)
+		assert.Equal(t, CircuitClosed, cb.State(chainID))
+	})
+
+	t.Run("chains_are_independent", func(t *testing.T) {
+		cb, _, _ := newTestCircuitBreaker(1, time.Minute)
+
+		cb.RecordFailure(chainID, rpcErr)
+		assert.Error(t, cb.Allow(chainID))
+		assert.NoError(t, cb.Allow(chainID+1))
+	})
+
+	t.Run("half_open_probe_recovers", func(t *testing.T) {
+		cb, now, alerts := newTestCircuitBreaker(1, time.Minute)
+
+		cb.RecordFailure(chainID, rpcErr)
+		*now = now.Add(time.Minute)
+
+		// Exactly one probe is let through
+		assert.NoError(t, cb.Allow(chainID))
+		assert.Equal(t, CircuitHalfOpen, cb.State(chainID))
+		assert.ErrorIs(t, cb.Allow(chainID), ErrCircuitOpen)
+
+		cb.RecordSuccess(chainID)
+		assert.Equal(t, CircuitClosed, cb.State(chainID))
+		assert.NoError(t, cb.Allow(chainID))
+		require.Len(t, *alerts, 2)
+		assert.Equal(t, CircuitClosed, (*alerts)[1].State)
+	})
+
+	t.Run("failed_probe_reopens", func(t *testing.T) {
+		cb, now, _ := newTestCircuitBreaker(3, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			cb.RecordFailure(chainID, rpcErr)
+		}
+		*now = now.Add(time.Minute)
+		require.NoError(t, cb.Allow(chainID))
+
+		cb.RecordFailure(chainID, rpcErr)
+		assert.Equal(t, CircuitOpen, cb.State(chainID))
+		assert.ErrorIs(t, cb.Allow(chainID), ErrCircuitOpen)
+	})
+
+	t.Run("pending_probe_is_released", func(t *testing.T) {
+		cb, now, _ := newTestCircuitBreaker(1, time.Minute)
+
+		cb.RecordFailure(chainID, rpcErr)
+		*now = now.Add(time.Minute)
+		require.NoError(t, cb.Allow(chainID))
+
+		// The probe's transaction is still pending: the next operation probes instead
+		cb.ReleaseProbe(chainID)
+		assert.Equal(t, CircuitOpen, cb.State(chainID))
+		require.NoError(t, cb.Allow(chainID))
+		assert.Equal(t, CircuitHalfOpen, cb.State(chainID))
+	})
+
+	t.Run("state_strings", func(t *testing.T) {
+		assert.Equal(t, "closed", CircuitClosed.String())
+		assert.Equal(t, "open", CircuitOpen.String())
+		assert.Equal(t, "half-open", CircuitHalfOpen.String())
+	})
+}
+
+// TestFillCircuitIgnoresPolls tests that polls which keep succeeding don't reset the failures of
+// fills which keep failing on the same chain
+func TestFillCircuitIgnoresPolls(t *testing.T) {
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+
+	solver := NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{})
+	solver.evmHandlers[config.BaseSepoliaChainID] = &statusChainHandler{}
+	solver.breaker, _, _ = newTestCircuitBreaker(3, time.Minute)
+	chainID := big.NewInt(config.BaseSepoliaChainID)
+	fill := func(ChainHandler) (OrderAction, error) { return OrderActionError, errors.New("execution reverted") }
+
+	polls := 0
+	for i := 0; i < 3; i++ {
+		_, err := solver.executeChainOperation(context.Background(), nil, chainID, "fill", fill)
+		require.Error(t, err)
+		pollWithCircuitBreaker("Base", nil, func() error { polls++; return nil })
+	}
+	assert.Equal(t, 3, polls)
+	assert.Equal(t, CircuitOpen, solver.breaker.State(config.BaseSepoliaChainID))
+	assert.Equal(t, CircuitClosed, DefaultPollCircuitBreaker().State(config.BaseSepoliaChainID))
+
+	_, err := solver.executeChainOperation(context.Background(), nil, chainID, "fill", fill)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+}
+
+// TestFillCircuitIgnoresRefusedOrders tests that orders refused by the daily spend limits or for
+// their origin_data leave the fill circuit of a healthy chain closed
+func TestFillCircuitIgnoresRefusedOrders(t *testing.T) {
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+
+	solver := NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{})
+	solver.evmHandlers[config.BaseSepoliaChainID] = &statusChainHandler{}
+	solver.breaker, _, _ = newTestCircuitBreaker(3, time.Minute)
+	chainID := big.NewInt(config.BaseSepoliaChainID)
+	for _, refusal := range []error{ErrSpendLimitReached, ErrOriginDataMismatch} {
+		refused := func(ChainHandler) (OrderAction, error) {
+			return OrderActionError, fmt.Errorf("aborting transaction: %w", refusal)
+		}
+		for i := 0; i < 5; i++ {
+			_, err := solver.executeChainOperation(context.Background(), nil, chainID, "fill", refused)
+			assert.ErrorIs(t, err, refusal)
+		}
+		assert.Equal(t, CircuitClosed, solver.breaker.State(config.BaseSepoliaChainID), "refused by %v", refusal)
+	}
+}
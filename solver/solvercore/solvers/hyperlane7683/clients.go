@@ -0,0 +1,27 @@
+package hyperlane7683
+
+// Module: Chain client interfaces for Hyperlane7683
+// - EVMClient is the subset of *ethclient.Client used by handlers, listeners and rules
+// - Starknet code depends on rpc.RPCProvider, which *rpc.Provider implements
+// - Both can be replaced by the in-memory backends of pkg/chainmock in tests
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// EVMClient is an EVM JSON-RPC client: contract calls, transactions, receipts, logs and native balances
+type EVMClient interface {
+	bind.ContractBackend
+	bind.DeployBackend
+
+	// ChainID returns the chain ID used for replay protection
+	ChainID(ctx context.Context) (*big.Int, error)
+	// BlockNumber returns the most recent block number
+	BlockNumber(ctx context.Context) (uint64, error)
+	// BalanceAt returns the native balance of account (nil block is the latest)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
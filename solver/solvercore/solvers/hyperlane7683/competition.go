@@ -0,0 +1,153 @@
+package hyperlane7683
+
+// Module: Competing solver tracking
+// - The listeners report every Filled event seen on the Hyperlane7683 contracts they watch
+// - Fills the solver did not send itself are remembered for competitorFillTTL, so orders still
+//   waiting to be processed (e.g. behind a backfill) are dropped before any fill is attempted
+// - Keeps per-chain counts of our fills and competitor fills, exposed through GET /competition
+//   and the solver_competitor_fills_total metric
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+)
+
+// competitorFillTTL is how long a competitor's fill is remembered; an order is not handled
+// by the solver this long after being filled on the destination
+const competitorFillTTL = 24 * time.Hour
+
+// CompetitorFill is a fill sent by another solver
+type CompetitorFill struct {
+	OrderID     string    `json:"orderId"`
+	ChainName   string    `json:"chainName"`
+	TxHash      string    `json:"txHash"`
+	BlockNumber uint64    `json:"blockNumber"`
+	SeenAt      time.Time `json:"seenAt"`
+}
+
+// CompetitionStats counts the fills seen on one destination chain
+type CompetitionStats struct {
+	ChainName            string     `json:"chainName"`
+	OwnFills             uint64     `json:"ownFills"`
+	CompetitorFills      uint64     `json:"competitorFills"`
+	LastCompetitorFillAt *time.Time `json:"lastCompetitorFillAt,omitempty"`
+}
+
+// CompetitionTracker records the fills seen on chain. Methods are safe for concurrent use and
+// no-ops on a nil receiver, so listeners and solvers built without one need no special casing.
+type CompetitionTracker struct {
+	mu        sync.Mutex
+	isOwnFill func(orderID, txHash string) bool // nil treats every fill as a competitor's
+	fills     map[string]CompetitorFill         // competitor fills by order ID
+	stats     map[string]*CompetitionStats      // by chain name
+	metrics   *metrics.Registry
+	now       func() time.Time
+}
+
+// NewCompetitionTracker creates an empty tracker
+func NewCompetitionTracker() *CompetitionTracker {
+	return &CompetitionTracker{
+		fills:   make(map[string]CompetitorFill),
+		stats:   make(map[string]*CompetitionStats),
+		metrics: metrics.Default(),
+		now:     time.Now,
+	}
+}
+
+var (
+	defaultCompetitionTracker     *CompetitionTracker
+	defaultCompetitionTrackerOnce sync.Once
+)
+
+// DefaultCompetitionTracker returns the process-wide tracker shared by the listeners and the solver
+func DefaultCompetitionTracker() *CompetitionTracker {
+	defaultCompetitionTrackerOnce.Do(func() {
+		defaultCompetitionTracker = NewCompetitionTracker()
+	})
+	return defaultCompetitionTracker
+}
+
+// SetOwnFillCheck sets how fills sent by the solver itself are recognized
+func (t *CompetitionTracker) SetOwnFillCheck(isOwnFill func(orderID, txHash string) bool) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.isOwnFill = isOwnFill
+}
+
+// RecordFill records a Filled event seen on chainName
+func (t *CompetitionTracker) RecordFill(chainName, orderID, txHash string, blockNumber uint64) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	for id, fill := range t.fills {
+		if now.Sub(fill.SeenAt) >= competitorFillTTL {
+			delete(t.fills, id)
+		}
+	}
+
+	stats, ok := t.stats[chainName]
+	if !ok {
+		stats = &CompetitionStats{ChainName: chainName}
+		t.stats[chainName] = stats
+	}
+	if t.isOwnFill != nil && t.isOwnFill(orderID, txHash) {
+		stats.OwnFills++
+		return
+	}
+	if _, seen := t.fills[orderID]; seen {
+		return
+	}
+
+	t.fills[orderID] = CompetitorFill{OrderID: orderID, ChainName: chainName, TxHash: txHash, BlockNumber: blockNumber, SeenAt: now}
+	stats.CompetitorFills++
+	stats.LastCompetitorFillAt = &now
+	t.metrics.Counter("solver_competitor_fills_total", "chain", chainName).Inc()
+	fmt.Printf("%s🏁 order %s filled by another solver (tx %s)\n", logutil.Prefix(chainName), orderID, txHash)
+}
+
+// FilledByCompetitor returns the competitor's fill of an order, if one was seen
+func (t *CompetitionTracker) FilledByCompetitor(orderID string) (CompetitorFill, bool) {
+	if t == nil {
+		return CompetitorFill{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fill, ok := t.fills[orderID]
+	if !ok || t.now().Sub(fill.SeenAt) >= competitorFillTTL {
+		return CompetitorFill{}, false
+	}
+	return fill, true
+}
+
+// Stats returns the fill counts of every chain a fill was seen on, sorted by chain name
+func (t *CompetitionTracker) Stats() []CompetitionStats {
+	if t == nil {
+		return []CompetitionStats{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make([]CompetitionStats, 0, len(t.stats))
+	for _, s := range t.stats {
+		stat := *s
+		if s.LastCompetitorFillAt != nil {
+			lastAt := *s.LastCompetitorFillAt
+			stat.LastCompetitorFillAt = &lastAt
+		}
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].ChainName < stats[j].ChainName })
+	return stats
+}
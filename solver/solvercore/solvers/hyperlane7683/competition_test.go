@@ -0,0 +1,195 @@
+package hyperlane7683
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompetitionTracker(t *testing.T) {
+	t.Run("tells our fills from competitors'", func(t *testing.T) {
+		tracker := NewCompetitionTracker()
+		now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		tracker.now = func() time.Time { return now }
+		tracker.SetOwnFillCheck(func(orderID, txHash string) bool { return orderID == "0x01" && txHash == "0xaa" })
+
+		tracker.RecordFill("Base", "0x01", "0xaa", 10)
+		tracker.RecordFill("Base", "0x02", "0xbb", 11)
+		tracker.RecordFill("Base", "0x02", "0xbb", 11) // seen again, e.g. overlapping ranges
+		tracker.RecordFill("Starknet", "0x03", "0xcc", 12)
+
+		_, ok := tracker.FilledByCompetitor("0x01")
+		assert.False(t, ok)
+		fill, ok := tracker.FilledByCompetitor("0x02")
+		require.True(t, ok)
+		assert.Equal(t, CompetitorFill{OrderID: "0x02", ChainName: "Base", TxHash: "0xbb", BlockNumber: 11, SeenAt: now}, fill)
+
+		stats := tracker.Stats()
+		require.Len(t, stats, 2)
+		assert.Equal(t, "Base", stats[0].ChainName)
+		assert.Equal(t, uint64(1), stats[0].OwnFills)
+		assert.Equal(t, uint64(1), stats[0].CompetitorFills)
+		require.NotNil(t, stats[0].LastCompetitorFillAt)
+		assert.Equal(t, now, *stats[0].LastCompetitorFillAt)
+		assert.Equal(t, "Starknet", stats[1].ChainName)
+		assert.Equal(t, uint64(1), stats[1].CompetitorFills)
+	})
+
+	t.Run("fills expire", func(t *testing.T) {
+		tracker := NewCompetitionTracker()
+		now := time.Now()
+		tracker.now = func() time.Time { return now }
+		tracker.RecordFill("Base", "0x02", "0xbb", 11)
+
+		now = now.Add(competitorFillTTL)
+		_, ok := tracker.FilledByCompetitor("0x02")
+		assert.False(t, ok)
+		assert.Equal(t, uint64(1), tracker.Stats()[0].CompetitorFills, "counts are kept")
+	})
+
+	t.Run("nil tracker is a no-op", func(t *testing.T) {
+		var tracker *CompetitionTracker
+		tracker.SetOwnFillCheck(nil)
+		tracker.RecordFill("Base", "0x02", "0xbb", 11)
+		_, ok := tracker.FilledByCompetitor("0x02")
+		assert.False(t, ok)
+		assert.Empty(t, tracker.Stats())
+	})
+}
+
+// filledLog builds a Filled log emitted by settler
+func filledLog(t testing.TB, settler common.Address, orderID [32]byte, txHash common.Hash, blockNumber uint64) gethtypes.Log {
+	t.Helper()
+	parsedABI, err := contracts.Hyperlane7683MetaData.GetAbi()
+	require.NoError(t, err)
+	data, err := parsedABI.Events["Filled"].Inputs.NonIndexed().Pack(orderID, []byte{0x01}, []byte{0x02})
+	require.NoError(t, err)
+	return gethtypes.Log{
+		Address:     settler,
+		Topics:      []common.Hash{filledEventTopic},
+		Data:        data,
+		BlockNumber: blockNumber,
+		TxHash:      txHash,
+	}
+}
+
+func TestListenersReportFills(t *testing.T) {
+	t.Run("EVM", func(t *testing.T) {
+		settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		backend.AddLog(openLog(t, settler, [32]byte{0x01}, 12))
+		backend.AddLog(filledLog(t, settler, [32]byte{0x02}, common.HexToHash("0xbb"), 13))
+
+		tracker := NewCompetitionTracker()
+		listener := &evmListener{
+			config:          &base.ListenerConfig{ChainName: "Base", ContractAddress: settler.Hex()},
+			client:          backend,
+			contractAddress: settler,
+			stopChan:        make(chan struct{}),
+			fills:           tracker,
+		}
+		var opened []string
+		handler := func(args types.ParsedArgs, _ string, _ uint64) (bool, error) {
+			opened = append(opened, args.OrderID)
+			return true, nil
+		}
+
+		_, err := listener.processBlockRange(context.Background(), 10, 15, handler)
+		require.NoError(t, err)
+		assert.Equal(t, []string{common.Hash{0x01}.Hex()}, opened, "Filled events are not dispatched")
+		fill, ok := tracker.FilledByCompetitor(common.Hash{0x02}.Hex())
+		require.True(t, ok)
+		assert.Equal(t, common.HexToHash("0xbb").Hex(), fill.TxHash)
+		assert.Equal(t, uint64(13), fill.BlockNumber)
+	})
+
+	t.Run("Starknet", func(t *testing.T) {
+		hub := new(felt.Felt).SetUint64(0x5678)
+		u := func(v uint64) *felt.Felt { return new(felt.Felt).SetUint64(v) }
+		backend := chainmock.NewStarknetBackend()
+		// order_id as u256 (low, high), then origin_data and filler_data as Bytes
+		backend.AddEvent(rpc.EmittedEvent{
+			Event: rpc.Event{
+				FromAddress:  hub,
+				EventContent: rpc.EventContent{Keys: []*felt.Felt{filledEventSelector}, Data: []*felt.Felt{u(0x02), u(0), u(0), u(0), u(0), u(0)}},
+			},
+			BlockNumber:     7,
+			TransactionHash: u(0xbb),
+		})
+
+		tracker := NewCompetitionTracker()
+		listener := &starknetListener{
+			config:          &base.ListenerConfig{ChainName: "Starknet"},
+			provider:        backend,
+			contractAddress: hub,
+			stopChan:        make(chan struct{}),
+			fills:           tracker,
+		}
+		handler := func(types.ParsedArgs, string, uint64) (bool, error) {
+			t.Fatal("Filled events are not dispatched")
+			return false, nil
+		}
+
+		_, err := listener.processBlockRange(context.Background(), 5, 10, handler)
+		require.NoError(t, err)
+		fill, ok := tracker.FilledByCompetitor(common.BytesToHash([]byte{0x02}).Hex())
+		require.True(t, ok)
+		assert.Equal(t, "0xbb", fill.TxHash)
+		assert.Equal(t, uint64(7), fill.BlockNumber)
+	})
+
+	t.Run("truncated Starknet Filled event", func(t *testing.T) {
+		_, err := decodeFilledOrderID([]*felt.Felt{new(felt.Felt).SetUint64(1)})
+		assert.ErrorIs(t, err, errEventDataTruncated)
+	})
+}
+
+func TestProcessIntentSkipsCompetitorFills(t *testing.T) {
+	orderID := common.BytesToHash([]byte{0x02}).Hex()
+	token := "0x00000000000000000000000000000000000000a1"
+	settler := "0x00000000000000000000000000000000000000b2"
+	backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+	store, err := orders.NewStore(filepath.Join(t.TempDir(), "orders.json"))
+	require.NoError(t, err)
+
+	solver := NewHyperlane7683Solver(
+		func(uint64) (EVMClient, error) { return backend, nil },
+		nil, nil, nil,
+		types.AllowBlockLists{},
+	)
+	solver.SetOrderStore(store)
+	tracker := NewCompetitionTracker()
+	solver.TrackCompetition(tracker)
+
+	// A fill recorded as ours is not a competitor's
+	require.NoError(t, store.Observe(&types.ParsedArgs{OrderID: "0x01"}))
+	require.NoError(t, store.AddCost("0x01", orders.TxCost{Kind: orders.TxKindFill, TxHash: "0xaa"}))
+	tracker.RecordFill("Base", "0x01", "0xaa", 10)
+	tracker.RecordFill("Base", orderID, "0xbb", 11)
+	_, ok := tracker.FilledByCompetitor("0x01")
+	assert.False(t, ok)
+
+	args := endToEndArgs(orderID, token, settler, config.BaseSepoliaChainID)
+	processed, err := solver.ProcessIntent(context.Background(), &args)
+	require.NoError(t, err)
+	assert.False(t, processed)
+	assert.Empty(t, backend.Sent(), "no fill is attempted")
+
+	order, found := store.Get(orderID)
+	require.True(t, found)
+	assert.Equal(t, orders.StatusLostRace, order.Status)
+}
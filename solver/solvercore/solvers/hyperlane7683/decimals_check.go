@@ -0,0 +1,94 @@
+package hyperlane7683
+
+// Module: Token decimals check
+// - Like-for-like orders (same token symbol on both sides) should trade close to 1:1 in whole tokens,
+//   but their amounts are in base units of tokens that can have different decimals on each chain
+//   (e.g. USDC with 6 decimals on one chain and 18 on another)
+// - An order written with the wrong decimals on one side has an implied rate off by powers of ten;
+//   ProfitabilityCheck compares base units and would accept the solver receiving 10^-12 of what it spends
+// - Both sides are scaled to 18 decimals with the decimals of the registry or of on-chain discovery
+//   (see token_discovery.go), and orders whose rate is more than rules.maxRateFactor away from 1:1,
+//   in either direction, are rejected with decimals_mismatch
+// - Cross-token orders are left to the price check of ProfitabilityCheck, and orders with a token of
+//   unknown decimals pass
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// DecimalsRule rejects like-for-like orders whose implied exchange rate, after scaling both sides
+// by their token decimals, is more than MaxRateFactor away from 1:1
+type DecimalsRule struct {
+	// MaxRateFactor below 2 disables the check
+	MaxRateFactor int
+}
+
+func (dr *DecimalsRule) Name() string {
+	return "DecimalsCheck"
+}
+
+func (dr *DecimalsRule) Evaluate(_ context.Context, args *types.ParsedArgs) RuleResult {
+	if dr.MaxRateFactor < 2 {
+		return RuleResult{Passed: true, Reason: "No rate factor configured"}
+	}
+	if isCrossTokenOrder(args) {
+		return RuleResult{Passed: true, Reason: "Cross-token order, rate checked at the token prices"}
+	}
+
+	sides := orderSides(args)
+	spent, spentDecimals, ok := scaledSideAmount(sides[0])
+	if !ok {
+		return RuleResult{Passed: true, Reason: "Decimals of the tokens spent are unknown"}
+	}
+	received, receivedDecimals, ok := scaledSideAmount(sides[1])
+	if !ok {
+		return RuleResult{Passed: true, Reason: "Decimals of the tokens received are unknown"}
+	}
+	if spent.Sign() == 0 || received.Sign() == 0 {
+		return RuleResult{Passed: true, Reason: "Order spends or receives nothing"}
+	}
+
+	rate := new(big.Rat).SetFrac(received, spent)
+	summary := fmt.Sprintf("receives %s for %s spent (rate %s)",
+		amount.New(received, config.NotionalDecimals), amount.New(spent, config.NotionalDecimals), rate.FloatString(4))
+
+	factor := big.NewInt(int64(dr.MaxRateFactor))
+	if new(big.Int).Mul(received, factor).Cmp(spent) < 0 || new(big.Int).Mul(spent, factor).Cmp(received) < 0 {
+		return RuleResult{
+			Passed: false,
+			Reason: fmt.Sprintf("Implied rate more than %dx from 1:1: %s; check the decimals of the tokens spent (%s) and received (%s)",
+				dr.MaxRateFactor, summary, spentDecimals, receivedDecimals),
+		}
+	}
+	return RuleResult{Passed: true, Reason: "Implied rate within bounds: " + summary}
+}
+
+// scaledSideAmount adds up the outputs of one side of an order scaled to 18 decimals, with the
+// decimals of their tokens ("6" or "6/18"); ok is false when a token's decimals are unknown
+func scaledSideAmount(side orderSide) (total *big.Int, decimals string, ok bool) {
+	total = new(big.Int)
+	var seen []string
+	for _, output := range side.outputs {
+		chainID := outputChainID(output, side.fallbackChain)
+		token, known := config.LookupToken(chainID, output.Token)
+		if !known || output.Amount == nil {
+			return nil, "", false
+		}
+		total.Add(total, amount.New(output.Amount, token.Decimals).Scale(config.NotionalDecimals).Units)
+
+		digits := strconv.Itoa(token.Decimals)
+		if !slices.Contains(seen, digits) {
+			seen = append(seen, digits)
+		}
+	}
+	return total, strings.Join(seen, "/"), true
+}
@@ -0,0 +1,83 @@
+package hyperlane7683
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+func TestDecimalsRule(t *testing.T) {
+	config.InitializeNetworks()
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+	require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+		{ChainID: config.BaseSepoliaChainID, Address: "0xc0", Symbol: "USDC", Decimals: 6},
+		{ChainID: config.EthereumSepoliaChainID, Address: "0xc1", Symbol: "USDC", Decimals: 18},
+		{ChainID: config.EthereumSepoliaChainID, Address: "0xd1", Symbol: "DOG", Decimals: 18},
+	}}))
+
+	// Spends USDC (6 decimals) on Base Sepolia for tokens received on Ethereum Sepolia
+	orderArgs := func(spent *big.Int, receivedToken string, received *big.Int) types.ParsedArgs {
+		return types.ParsedArgs{
+			OrderID: "0x5555555555555555555555555555555555555555555555555555555555555555",
+			ResolvedOrder: types.ResolvedCrossChainOrder{
+				OriginChainID:    big.NewInt(config.EthereumSepoliaChainID),
+				MaxSpent:         []types.Output{{Token: "0xc0", Amount: spent}},
+				MinReceived:      []types.Output{{Token: receivedToken, Amount: received}},
+				FillInstructions: []types.FillInstruction{{DestinationChainID: big.NewInt(config.BaseSepoliaChainID)}},
+			},
+		}
+	}
+	evaluate := func(rule *DecimalsRule, args types.ParsedArgs) RuleResult {
+		return rule.Evaluate(context.Background(), &args)
+	}
+	rule := &DecimalsRule{MaxRateFactor: 10}
+	oneUSDC6 := big.NewInt(1_000_000)
+	oneUSDC18 := big.NewInt(1e18)
+
+	t.Run("Rule name", func(t *testing.T) {
+		assert.Equal(t, "DecimalsCheck", rule.Name())
+	})
+
+	t.Run("Orders near 1:1 in whole tokens pass", func(t *testing.T) {
+		result := evaluate(rule, orderArgs(oneUSDC6, "0xc1", new(big.Int).Mul(oneUSDC18, big.NewInt(2))))
+		assert.True(t, result.Passed, result.Reason)
+	})
+
+	t.Run("Inputs written with the wrong decimals are rejected", func(t *testing.T) {
+		// 1.000001 USDC received in 6-decimal units on an 18-decimal token: more than the spent
+		// amount in base units, but 10^-12 of it in tokens
+		args := orderArgs(oneUSDC6, "0xc1", big.NewInt(1_000_001))
+		result := evaluate(rule, args)
+		assert.False(t, result.Passed)
+		assert.Contains(t, result.Reason, "more than 10x from 1:1")
+		assert.Contains(t, result.Reason, "spent (6) and received (18)")
+		assert.True(t, (&ProfitabilityRule{}).Evaluate(context.Background(), &args).Passed, "the base unit comparison alone accepts it")
+	})
+
+	t.Run("Orders overpaying the solver are rejected too", func(t *testing.T) {
+		result := evaluate(rule, orderArgs(oneUSDC6, "0xc1", new(big.Int).Mul(oneUSDC18, big.NewInt(11))))
+		assert.False(t, result.Passed)
+	})
+
+	t.Run("Engine reports decimals_mismatch", func(t *testing.T) {
+		engine := &RulesEngine{}
+		engine.AddRule(rule)
+		args := orderArgs(oneUSDC6, "0xc1", big.NewInt(1_000_001))
+		assert.Equal(t, RejectDecimalsMismatch, engine.EvaluateAll(context.Background(), &args).Code)
+	})
+
+	t.Run("Cross-token and unknown tokens are left to other rules", func(t *testing.T) {
+		assert.True(t, evaluate(rule, orderArgs(oneUSDC6, "0xd1", big.NewInt(1))).Passed)
+		assert.True(t, evaluate(rule, orderArgs(oneUSDC6, "0xf0", big.NewInt(1))).Passed)
+	})
+
+	t.Run("A factor below 2 disables the check", func(t *testing.T) {
+		assert.True(t, evaluate(&DecimalsRule{}, orderArgs(oneUSDC6, "0xc1", big.NewInt(1))).Passed)
+	})
+}
@@ -0,0 +1,352 @@
+package hyperlane7683
+
+// Module: Deployment verification
+// - Checks every configured network against its Hyperlane7683 deployment: the router must hold code
+//   (a class on Starknet) and report the configured Hyperlane domain; its mailbox and owner must be
+//   <NETWORK>_MAILBOX_ADDRESS and <NETWORK>_OWNER_ADDRESS when those are set (EVM_HYPERLANE_OWNER for the
+//   owner of EVM routers), and are only reported otherwise
+// - Other recorded contracts (registry tokens, or the tokens of the deployment state) must hold code
+// - Any failed read counts as drift, so an unreachable network is never reported as verified
+// - Backs `solver contracts verify`; with STRICT_STARTUP the solver verifies its routers and registry
+//   tokens before starting and refuses to start on drift
+//
+// Settings:
+// - STRICT_STARTUP: verify the deployment at startup and fail on drift (default false)
+// - <NETWORK>_MAILBOX_ADDRESS, <NETWORK>_OWNER_ADDRESS: expected mailbox and owner of a network's
+//   router, e.g. BASE_MAILBOX_ADDRESS or STARKNET_SEPOLIA_OWNER_ADDRESS
+// - EVM_HYPERLANE_OWNER: expected owner of the EVM routers without <NETWORK>_OWNER_ADDRESS
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// routerContract names the router in deployment checks
+const routerContract = "Hyperlane7683"
+
+// Deployment checks
+const (
+	DeploymentCheckCode    = "code"
+	DeploymentCheckDomain  = "domain"
+	DeploymentCheckMailbox = "mailbox"
+	DeploymentCheckOwner   = "owner"
+	// DeploymentCheckRecorded compares a recorded router with the configured one
+	DeploymentCheckRecorded = "recorded"
+)
+
+// RecordedContract is a contract expected at an address of a network
+type RecordedContract struct {
+	Network string
+	Name    string // e.g. a token symbol; "Hyperlane7683" must be the configured router
+	Address string
+}
+
+// DeploymentCheck is the outcome of one check of a deployed contract
+type DeploymentCheck struct {
+	Network  string `json:"network"`
+	Contract string `json:"contract"`
+	Address  string `json:"address"`
+	Check    string `json:"check"`
+	OK       bool   `json:"ok"`
+	Detail   string `json:"detail"`
+}
+
+// DeploymentVerifier compares the deployed contracts with the configuration
+type DeploymentVerifier struct {
+	// Dials clients the same way as the balance rule; nil getters dial the configured RPCs
+	clients *BalanceRule
+}
+
+// NewDeploymentVerifier creates a verifier using the given client getters; nil getters dial the configured RPCs
+func NewDeploymentVerifier(getEVMClient func(chainID uint64) (EVMClient, error), getStarknetClient func(chainID uint64) (rpc.RPCProvider, error)) *DeploymentVerifier {
+	return &DeploymentVerifier{clients: &BalanceRule{getEVMClient: getEVMClient, getStarknetClient: getStarknetClient}}
+}
+
+// StrictStartup reports whether the solver verifies its deployment before starting (STRICT_STARTUP)
+func StrictStartup() bool {
+	return envutil.GetEnvBool("STRICT_STARTUP", false)
+}
+
+// RegistryContracts returns the registry tokens of the configured networks as recorded contracts;
+// native tokens have no contract and are left out
+func RegistryContracts() []RecordedContract {
+	var recorded []RecordedContract
+	for _, token := range config.ActiveRuntime().Tokens {
+		network, ok := networkForChainID(token.ChainID)
+		if !ok || types.IsNativeToken(token.Address) {
+			continue
+		}
+		recorded = append(recorded, RecordedContract{Network: network.Name, Name: token.Symbol, Address: token.Address})
+	}
+	return recorded
+}
+
+// Verify checks the router of every configured network and the recorded contracts, by network name
+func (v *DeploymentVerifier) Verify(ctx context.Context, recorded []RecordedContract) []DeploymentCheck {
+	byNetwork := make(map[string][]RecordedContract)
+	for _, contract := range recorded {
+		byNetwork[contract.Network] = append(byNetwork[contract.Network], contract)
+	}
+
+	names := config.GetNetworkNames()
+	sort.Strings(names)
+	var checks []DeploymentCheck
+	for _, name := range names {
+		network := config.Networks[name]
+		if config.IsStarknetNetwork(name) {
+			checks = append(checks, v.verifyStarknet(ctx, network, byNetwork[name])...)
+		} else {
+			checks = append(checks, v.verifyEVM(ctx, network, byNetwork[name])...)
+		}
+		delete(byNetwork, name)
+	}
+
+	// Contracts recorded for networks that are not configured cannot be checked
+	unknown := make([]string, 0, len(byNetwork))
+	for name := range byNetwork {
+		unknown = append(unknown, name)
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		for _, contract := range byNetwork[name] {
+			checks = append(checks, DeploymentCheck{Network: name, Contract: contract.Name, Address: contract.Address,
+				Check: DeploymentCheckCode, Detail: "network is not configured"})
+		}
+	}
+	return checks
+}
+
+// checkFunc builds a check of a contract of one network
+type checkFunc func(contract, address, kind string, ok bool, format string, args ...any) DeploymentCheck
+
+// networkCheck returns the checkFunc of network
+func networkCheck(network string) checkFunc {
+	return func(contract, address, kind string, ok bool, format string, args ...any) DeploymentCheck {
+		return DeploymentCheck{Network: network, Contract: contract, Address: address, Check: kind, OK: ok, Detail: fmt.Sprintf(format, args...)}
+	}
+}
+
+// verifyEVM checks the router and recorded contracts of an EVM network
+func (v *DeploymentVerifier) verifyEVM(ctx context.Context, network config.NetworkConfig, recorded []RecordedContract) []DeploymentCheck {
+	router := network.HyperlaneAddress.Hex()
+	check := networkCheck(network.Name)
+
+	client, release, err := v.clients.evmClient(&network)
+	if err != nil {
+		return []DeploymentCheck{check(routerContract, router, DeploymentCheckCode, false, "%v", err)}
+	}
+	defer release()
+
+	hasCode := func(contract, address string) (DeploymentCheck, bool) {
+		if !common.IsHexAddress(address) {
+			return check(contract, address, DeploymentCheckCode, false, "not an EVM address"), false
+		}
+		code, err := client.CodeAt(ctx, common.HexToAddress(address), nil)
+		switch {
+		case err != nil:
+			return check(contract, address, DeploymentCheckCode, false, "cannot read the code: %v", err), false
+		case len(code) == 0:
+			return check(contract, address, DeploymentCheckCode, false, "no contract"), false
+		default:
+			return check(contract, address, DeploymentCheckCode, true, "%d bytes of code", len(code)), true
+		}
+	}
+
+	result, ok := hasCode(routerContract, router)
+	checks := []DeploymentCheck{result}
+	if ok {
+		checks = append(checks, v.verifyEVMRouter(ctx, client, network, check)...)
+	}
+	for _, contract := range recorded {
+		if contract.Name == routerContract {
+			same := common.IsHexAddress(contract.Address) && common.HexToAddress(contract.Address) == network.HyperlaneAddress
+			checks = append(checks, recordedRouterCheck(check, contract.Address, router, same))
+			continue
+		}
+		result, _ := hasCode(contract.Name, contract.Address)
+		checks = append(checks, result)
+	}
+	return checks
+}
+
+// verifyEVMRouter reads the domain, mailbox and owner of an EVM router
+func (v *DeploymentVerifier) verifyEVMRouter(ctx context.Context, client EVMClient, network config.NetworkConfig, check checkFunc) []DeploymentCheck {
+	router := network.HyperlaneAddress.Hex()
+	caller, err := contracts.NewHyperlane7683Caller(network.HyperlaneAddress, client)
+	if err != nil {
+		return []DeploymentCheck{check(routerContract, router, DeploymentCheckDomain, false, "%v", err)}
+	}
+	opts := &bind.CallOpts{Context: ctx}
+
+	var checks []DeploymentCheck
+	domain, err := caller.LocalDomain(opts)
+	if err != nil {
+		checks = append(checks, check(routerContract, router, DeploymentCheckDomain, false, "localDomain() failed: %v", err))
+	} else {
+		checks = append(checks, domainCheck(check, router, uint64(domain), network.HyperlaneDomain))
+	}
+
+	for _, read := range []struct {
+		kind string
+		call func(*bind.CallOpts) (common.Address, error)
+	}{{DeploymentCheckMailbox, caller.Mailbox}, {DeploymentCheckOwner, caller.Owner}} {
+		address, err := read.call(opts)
+		if err != nil {
+			checks = append(checks, check(routerContract, router, read.kind, false, "%s() failed: %v", read.kind, err))
+			continue
+		}
+		checks = append(checks, addressCheck(check, router, read.kind, address.Hex(), expectedAddressEnvs(network.Name, read.kind), func(want string) bool {
+			return common.IsHexAddress(want) && common.HexToAddress(want) == address
+		}))
+	}
+	return checks
+}
+
+// verifyStarknet checks the router and recorded contracts of a Starknet network
+func (v *DeploymentVerifier) verifyStarknet(ctx context.Context, network config.NetworkConfig, recorded []RecordedContract) []DeploymentCheck {
+	router := config.StarknetHyperlaneAddress(network.Name)
+	check := networkCheck(network.Name)
+
+	provider, err := v.clients.starknetProvider(network.ChainID)
+	if err != nil {
+		return []DeploymentCheck{check(routerContract, router, DeploymentCheckCode, false, "%v", err)}
+	}
+
+	hasClass := func(contract, address string) (DeploymentCheck, *felt.Felt) {
+		addressFelt, err := utils.HexToFelt(address)
+		if err != nil {
+			return check(contract, address, DeploymentCheckCode, false, "not a Starknet address"), nil
+		}
+		classHash, err := provider.ClassHashAt(ctx, rpc.WithBlockTag(rpc.BlockTagLatest), addressFelt)
+		if err != nil {
+			return check(contract, address, DeploymentCheckCode, false, "no contract: %v", err), nil
+		}
+		return check(contract, address, DeploymentCheckCode, true, "class %s", classHash), addressFelt
+	}
+
+	result, routerFelt := hasClass(routerContract, router)
+	if router == "" {
+		result.Detail = config.StarknetHyperlaneAddressEnv(network.Name) + " is not set"
+	}
+	checks := []DeploymentCheck{result}
+	if routerFelt != nil {
+		checks = append(checks, v.verifyStarknetRouter(ctx, starknetutil.NewClient(provider, nil), network, routerFelt, check)...)
+	}
+	for _, contract := range recorded {
+		if contract.Name == routerContract {
+			configured, configErr := utils.HexToFelt(router)
+			recordedFelt, err := utils.HexToFelt(contract.Address)
+			same := err == nil && configErr == nil && recordedFelt.Equal(configured)
+			checks = append(checks, recordedRouterCheck(check, contract.Address, router, same))
+			continue
+		}
+		result, _ := hasClass(contract.Name, contract.Address)
+		checks = append(checks, result)
+	}
+	return checks
+}
+
+// verifyStarknetRouter reads the domain, mailbox and owner of a Starknet router
+func (v *DeploymentVerifier) verifyStarknetRouter(ctx context.Context, client *starknetutil.Client, network config.NetworkConfig, router *felt.Felt, check checkFunc) []DeploymentCheck {
+	address := router.String()
+	read := func(function string) (*felt.Felt, error) {
+		resp, err := client.Call(ctx, router, function)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp) == 0 {
+			return nil, fmt.Errorf("%s returned nothing", function)
+		}
+		return resp[0], nil
+	}
+
+	var checks []DeploymentCheck
+	if domain, err := read("get_local_domain"); err != nil {
+		checks = append(checks, check(routerContract, address, DeploymentCheckDomain, false, "%v", err))
+	} else {
+		checks = append(checks, domainCheck(check, address, domain.Uint64(), network.HyperlaneDomain))
+	}
+
+	for _, kind := range []string{DeploymentCheckMailbox, DeploymentCheckOwner} {
+		value, err := read(kind)
+		if err != nil {
+			checks = append(checks, check(routerContract, address, kind, false, "%v", err))
+			continue
+		}
+		checks = append(checks, addressCheck(check, address, kind, value.String(), expectedAddressEnvs(network.Name, kind), func(want string) bool {
+			wantFelt, err := utils.HexToFelt(want)
+			return err == nil && wantFelt.Equal(value)
+		}))
+	}
+	return checks
+}
+
+// recordedRouterCheck reports whether the router recorded at address is the configured router
+func recordedRouterCheck(check checkFunc, address, router string, same bool) DeploymentCheck {
+	if !same {
+		return check(routerContract, address, DeploymentCheckRecorded, false, "recorded router is not the configured router %s", router)
+	}
+	return check(routerContract, address, DeploymentCheckRecorded, true, "recorded router is the configured one")
+}
+
+// domainCheck compares the domain reported by a router with the configured one
+func domainCheck(check checkFunc, router string, domain, configured uint64) DeploymentCheck {
+	if domain != configured {
+		return check(routerContract, router, DeploymentCheckDomain, false, "router reports domain %d, configured %d", domain, configured)
+	}
+	return check(routerContract, router, DeploymentCheckDomain, true, "domain %d", domain)
+}
+
+// expectedAddressEnvs returns the variables holding the expected mailbox or owner of a network's
+// router, in order: <NETWORK>_<KIND>_ADDRESS, then EVM_HYPERLANE_OWNER for the owner of EVM routers,
+// which share their address and owner
+func expectedAddressEnvs(network, kind string) []string {
+	envs := []string{config.NetworkEnvPrefix(network) + "_" + strings.ToUpper(kind) + "_ADDRESS"}
+	if kind == DeploymentCheckOwner && !config.IsStarknetNetwork(network) {
+		envs = append(envs, "EVM_HYPERLANE_OWNER")
+	}
+	return envs
+}
+
+// addressCheck compares an address read from a router with the first of envs that is set
+func addressCheck(check checkFunc, router, kind, got string, envs []string, matches func(want string) bool) DeploymentCheck {
+	for _, env := range envs {
+		want := os.Getenv(env)
+		if want == "" {
+			continue
+		}
+		if !matches(want) {
+			return check(routerContract, router, kind, false, "router %s is %s, %s is %s", kind, got, env, want)
+		}
+		return check(routerContract, router, kind, true, "%s %s", kind, got)
+	}
+	return check(routerContract, router, kind, true, "%s %s (%s not set, not compared)", kind, got, strings.Join(envs, ", "))
+}
+
+// DeploymentDrift returns an error listing the failed checks, nil when all passed
+func DeploymentDrift(checks []DeploymentCheck) error {
+	var failed []string
+	for _, check := range checks {
+		if !check.OK {
+			failed = append(failed, fmt.Sprintf("%s %s %s: %s", check.Network, check.Contract, check.Check, check.Detail))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("deployment drift in %d of %d checks: %s", len(failed), len(checks), strings.Join(failed, "; "))
+}
@@ -0,0 +1,149 @@
+package hyperlane7683
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deploymentChains returns a verifier over in-memory chains where every configured router reports
+// its network's domain, mailbox 0xc3 and owner 0xd4
+func deploymentChains(t *testing.T) (*DeploymentVerifier, map[uint64]*chainmock.EVMBackend, *chainmock.StarknetBackend) {
+	t.Helper()
+	t.Setenv("STARKNET_HYPERLANE_ADDRESS", "0x0000000000000000000000000000000000000000000000000000000000007683")
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	t.Cleanup(config.ResetNetworks)
+
+	evm := make(map[uint64]*chainmock.EVMBackend)
+	starknet := chainmock.NewStarknetBackend()
+	for name, network := range config.Networks {
+		if config.IsStarknetNetwork(name) {
+			router, err := utils.HexToFelt(config.StarknetHyperlaneAddress(name))
+			require.NoError(t, err)
+			results := map[string]uint64{"get_local_domain": network.HyperlaneDomain, "mailbox": 0xc3, "owner": 0xd4}
+			for function, value := range results {
+				starknet.HandleCall(router, function, func(rpc.FunctionCall) ([]*felt.Felt, error) {
+					return []*felt.Felt{new(felt.Felt).SetUint64(value)}, nil
+				})
+			}
+			continue
+		}
+		backend := chainmock.NewEVMBackend(network.ChainID)
+		backend.HandleCall(network.HyperlaneAddress, chainmock.Selector("localDomain()"), uint256Result(new(big.Int).SetUint64(network.HyperlaneDomain)))
+		backend.HandleCall(network.HyperlaneAddress, chainmock.Selector("mailbox()"), uint256Result(big.NewInt(0xc3)))
+		backend.HandleCall(network.HyperlaneAddress, chainmock.Selector("owner()"), uint256Result(big.NewInt(0xd4)))
+		evm[network.ChainID] = backend
+	}
+
+	verifier := NewDeploymentVerifier(
+		func(chainID uint64) (EVMClient, error) {
+			if backend, ok := evm[chainID]; ok {
+				return backend, nil
+			}
+			return nil, fmt.Errorf("EVM client not found for chain ID %d", chainID)
+		},
+		func(uint64) (rpc.RPCProvider, error) { return starknet, nil },
+	)
+	return verifier, evm, starknet
+}
+
+// failedChecks returns the failed checks as "network contract check"
+func failedChecks(checks []DeploymentCheck) []string {
+	var failed []string
+	for _, check := range checks {
+		if !check.OK {
+			failed = append(failed, check.Network+" "+check.Contract+" "+check.Check)
+		}
+	}
+	return failed
+}
+
+// TestDeploymentVerifier tests router, mailbox, owner and recorded contract checks
+func TestDeploymentVerifier(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("matching deployment", func(t *testing.T) {
+		verifier, _, _ := deploymentChains(t)
+		checks := verifier.Verify(ctx, nil)
+		require.NoError(t, DeploymentDrift(checks))
+		assert.Len(t, checks, 4*len(config.Networks), "code, domain, mailbox and owner of every router")
+
+		for _, check := range checks {
+			if check.Network == "Base" && check.Check == DeploymentCheckMailbox {
+				assert.Contains(t, check.Detail, "BASE_MAILBOX_ADDRESS not set")
+			}
+		}
+	})
+
+	t.Run("mailbox, owner and domain drift", func(t *testing.T) {
+		t.Setenv("BASE_MAILBOX_ADDRESS", "0x00000000000000000000000000000000000000c3")
+		t.Setenv("EVM_HYPERLANE_OWNER", "0x00000000000000000000000000000000000000d4")
+		t.Setenv("OPTIMISM_OWNER_ADDRESS", "0x00000000000000000000000000000000000000ee")
+		t.Setenv("STARKNET_OWNER_ADDRESS", "0xee")
+		t.Setenv("STARKNET_MAILBOX_ADDRESS", "0x00c3")
+		verifier, evm, _ := deploymentChains(t)
+		ethereum := config.Networks["Ethereum"]
+		evm[ethereum.ChainID].HandleCall(ethereum.HyperlaneAddress, chainmock.Selector("localDomain()"), uint256Result(big.NewInt(1)))
+
+		checks := verifier.Verify(ctx, nil)
+		assert.ElementsMatch(t, []string{"Ethereum Hyperlane7683 domain", "Optimism Hyperlane7683 owner", "Starknet Hyperlane7683 owner"}, failedChecks(checks))
+		err := DeploymentDrift(checks)
+		assert.ErrorContains(t, err, "deployment drift in 3 of")
+		assert.ErrorContains(t, err, fmt.Sprintf("router reports domain 1, configured %d", ethereum.HyperlaneDomain))
+		assert.ErrorContains(t, err, "OPTIMISM_OWNER_ADDRESS is 0x00000000000000000000000000000000000000ee")
+	})
+
+	t.Run("recorded contracts", func(t *testing.T) {
+		verifier, evm, starknet := deploymentChains(t)
+		dog := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+		evm[config.BaseSepoliaChainID].HandleCall(dog, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(0)))
+		strk, err := utils.HexToFelt("0x1234")
+		require.NoError(t, err)
+		starknet.HandleCall(strk, "balanceOf", func(rpc.FunctionCall) ([]*felt.Felt, error) { return nil, nil })
+
+		checks := verifier.Verify(ctx, []RecordedContract{
+			{Network: "Base", Name: "DOG", Address: dog.Hex()},
+			{Network: "Base", Name: "CAT", Address: "0x00000000000000000000000000000000000000a2"},
+			{Network: "Base", Name: "Hyperlane7683", Address: config.Networks["Base"].HyperlaneAddress.Hex()},
+			{Network: "Arbitrum", Name: "Hyperlane7683", Address: "0x00000000000000000000000000000000000000b2"},
+			{Network: "Starknet", Name: "STRK", Address: "0x1234"},
+			{Network: "Starknet", Name: "DOG", Address: "0x4321"},
+			{Network: "Starknet", Name: "Hyperlane7683", Address: "0x7683"},
+			{Network: "Gone", Name: "DOG", Address: dog.Hex()},
+		})
+		assert.ElementsMatch(t, []string{"Base CAT code", "Arbitrum Hyperlane7683 recorded", "Starknet DOG code", "Gone DOG code"}, failedChecks(checks))
+	})
+
+	t.Run("unreachable network", func(t *testing.T) {
+		verifier, evm, _ := deploymentChains(t)
+		delete(evm, config.BaseSepoliaChainID)
+		checks := verifier.Verify(ctx, nil)
+		assert.Equal(t, []string{"Base Hyperlane7683 code"}, failedChecks(checks))
+	})
+}
+
+// TestRegistryContracts tests the registry tokens recorded for verification
+func TestRegistryContracts(t *testing.T) {
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+
+	require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+		{ChainID: config.BaseSepoliaChainID, Address: "0x00000000000000000000000000000000000000a1", Symbol: "DOG", Decimals: 6},
+		{ChainID: config.BaseSepoliaChainID, Address: "0x0000000000000000000000000000000000000000", Symbol: "ETH", Decimals: 18},
+		{ChainID: 999, Address: "0x00000000000000000000000000000000000000a1", Symbol: "DOG", Decimals: 6},
+	}}))
+	assert.Equal(t, []RecordedContract{{Network: "Base", Name: "DOG", Address: "0x00000000000000000000000000000000000000a1"}}, RegistryContracts())
+}
@@ -0,0 +1,186 @@
+package hyperlane7683
+
+// Module: EVM transaction replacement
+// - Fills and settles not mined within EVM_TX_STUCK_TIMEOUT_SECONDS are rebroadcast with the same
+//   nonce and bumped fees: priority fee and fee cap for EIP-1559 transactions, gas price for legacy
+//   ones, never below what the node currently suggests
+// - Every version is recorded in the tx audit log and the tx outbox; versions share a nonce, so at
+//   most one of them is mined and the receipts of all of them are watched until one is
+// - A rebroadcast rejected by the node (e.g. "nonce too low" once an earlier version was mined) is
+//   logged and the versions already sent are still waited for
+// - After the last replacement the handler gives up waiting; the outbox entry stays so the next
+//   attempt looks the versions up instead of sending the operation again
+//
+// Settings:
+// - EVM_TX_STUCK_TIMEOUT_SECONDS: time without a receipt before a tx is replaced (default 90, 0 disables replacement)
+// - EVM_TX_MAX_REPLACEMENTS: replacements of a stuck tx before giving up (default 2)
+// - EVM_TX_GAS_BUMP: factor applied to the fees on each replacement (default 1.125; nodes require at least 1.1)
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/receiptwait"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	defaultEVMStuckTimeoutSeconds = 90
+	defaultEVMMaxReplacements     = 2
+	defaultEVMGasBump             = 1.125
+	minEVMGasBump                 = 1.1
+)
+
+// errEVMTxStuck is returned when no version of a transaction was mined in time
+var errEVMTxStuck = errors.New("evm transaction stuck")
+
+// evmReplaceConfig controls stuck detection and replacement
+type evmReplaceConfig struct {
+	StuckTimeout    time.Duration // 0 waits for the first version without replacing it
+	MaxReplacements int
+	GasBump         float64
+}
+
+// loadEVMReplaceConfig reads the replacement settings from the environment
+func loadEVMReplaceConfig() (evmReplaceConfig, error) {
+	cfg := evmReplaceConfig{
+		StuckTimeout:    time.Duration(envutil.GetEnvInt("EVM_TX_STUCK_TIMEOUT_SECONDS", defaultEVMStuckTimeoutSeconds)) * time.Second,
+		MaxReplacements: envutil.GetEnvInt("EVM_TX_MAX_REPLACEMENTS", defaultEVMMaxReplacements),
+		GasBump:         envutil.GetEnvFloat64("EVM_TX_GAS_BUMP", defaultEVMGasBump),
+	}
+	if cfg.StuckTimeout < 0 {
+		return cfg, fmt.Errorf("EVM_TX_STUCK_TIMEOUT_SECONDS must be >= 0")
+	}
+	if cfg.MaxReplacements < 0 {
+		return cfg, fmt.Errorf("EVM_TX_MAX_REPLACEMENTS must be >= 0, got %d", cfg.MaxReplacements)
+	}
+	if cfg.GasBump < minEVMGasBump {
+		return cfg, fmt.Errorf("EVM_TX_GAS_BUMP must be >= %v, got %v", minEVMGasBump, cfg.GasBump)
+	}
+	return cfg, nil
+}
+
+// waitMined waits for tx to be mined, replacing it with bumped fees each time no version was mined
+// within the stuck timeout. It returns the mined version and its receipt; every version is
+// audited and added to the outbox entry of the order's kind. err is errEVMTxStuck when no version
+// was mined after the last replacement, or the error that ended the wait.
+func (h *HyperlaneEVM) waitMined(ctx context.Context, args *types.ParsedArgs, kind string, to common.Address, tx *gethtypes.Transaction, sent TxAuditEntry) (*gethtypes.Transaction, *gethtypes.Receipt, error) {
+	blockTime := config.ExpectedBlockTime(logutil.NetworkNameByChainID(h.chainID))
+	versions := []*gethtypes.Transaction{tx}
+	audits := map[common.Hash]TxAuditEntry{tx.Hash(): sent}
+
+	for replacements := 0; ; replacements++ {
+		waitCtx, cancel := ctx, context.CancelFunc(func() {})
+		if h.replace.StuckTimeout > 0 {
+			waitCtx, cancel = context.WithTimeout(ctx, h.replace.StuckTimeout)
+		}
+		mined, receipt, err := h.waitForAnyVersion(waitCtx, versions, blockTime)
+		cancel()
+		if err == nil {
+			h.txAudit.record(evmReceiptAuditEntry(audits[mined.Hash()], receipt, nil))
+			return mined, receipt, nil
+		}
+		if ctx.Err() != nil || !errors.Is(err, context.DeadlineExceeded) {
+			h.txAudit.record(evmReceiptAuditEntry(sent, nil, err))
+			return nil, nil, err
+		}
+		if replacements == h.replace.MaxReplacements {
+			err = fmt.Errorf("%w: nonce %d not mined after %d replacements", errEVMTxStuck, tx.Nonce(), replacements)
+			h.txAudit.record(evmReceiptAuditEntry(sent, nil, err))
+			return nil, nil, err
+		}
+
+		fillerLog.Printf("   🔁 %s tx stuck at nonce %d, replacing (%d/%d) with %.3fx fees\n",
+			kind, tx.Nonce(), replacements+1, h.replace.MaxReplacements, h.replace.GasBump)
+		replacement, err := h.bumpTx(ctx, versions[len(versions)-1])
+		if err == nil {
+			err = h.client.SendTransaction(ctx, replacement)
+		}
+		entry := h.auditSend(args, kind, to, replacement, err)
+		if err != nil {
+			// Usually an earlier version was just mined; keep waiting for the versions already out
+			fillerLog.Printf("   ⚠️  %s tx replacement failed: %v\n", kind, err)
+			continue
+		}
+		versions = append(versions, replacement)
+		audits[replacement.Hash()] = entry
+		h.txOutbox.AddTx(kind, h.chainID, args.OrderID, replacement.Hash().Hex())
+		h.recordSent.record(args.OrderID, h.chainID, kind, replacement.Hash().Hex())
+		fillerLog.Printf("   🔄 %s tx replaced: %s\n", kind, logutil.TxRef(h.chainID, replacement.Hash().Hex()))
+	}
+}
+
+// waitForAnyVersion polls the receipts of every version until one is mined. Failed receipt
+// lookups are retried, like bind.WaitMined does.
+func (h *HyperlaneEVM) waitForAnyVersion(ctx context.Context, versions []*gethtypes.Transaction, blockTime time.Duration) (*gethtypes.Transaction, *gethtypes.Receipt, error) {
+	var mined *gethtypes.Transaction
+	var receipt *gethtypes.Receipt
+	err := receiptwait.Default().Wait(ctx, blockTime, func(ctx context.Context) (bool, error) {
+		for _, version := range versions {
+			r, err := h.client.TransactionReceipt(ctx, version.Hash())
+			if err != nil {
+				continue
+			}
+			mined, receipt = version, r
+			return true, nil
+		}
+		return false, nil
+	})
+	return mined, receipt, err
+}
+
+// bumpTx signs a copy of tx with its fees multiplied by the gas bump, raised to the node's current
+// suggestion when that is higher
+func (h *HyperlaneEVM) bumpTx(ctx context.Context, tx *gethtypes.Transaction) (*gethtypes.Transaction, error) {
+	var data gethtypes.TxData
+	switch tx.Type() {
+	case gethtypes.DynamicFeeTxType:
+		tip, err := h.client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to suggest gas tip: %w", err)
+		}
+		tip = maxBig(bumpFee(tx.GasTipCap(), h.replace.GasBump), tip)
+		feeCap := maxBig(bumpFee(tx.GasFeeCap(), h.replace.GasBump), tip)
+		data = &gethtypes.DynamicFeeTx{
+			ChainID: tx.ChainId(), Nonce: tx.Nonce(), GasTipCap: tip, GasFeeCap: feeCap,
+			Gas: tx.Gas(), To: tx.To(), Value: tx.Value(), Data: tx.Data(), AccessList: tx.AccessList(),
+		}
+	case gethtypes.LegacyTxType:
+		price, err := h.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to suggest gas price: %w", err)
+		}
+		data = &gethtypes.LegacyTx{
+			Nonce: tx.Nonce(), GasPrice: maxBig(bumpFee(tx.GasPrice(), h.replace.GasBump), price),
+			Gas: tx.Gas(), To: tx.To(), Value: tx.Value(), Data: tx.Data(),
+		}
+	default:
+		return nil, fmt.Errorf("cannot replace transaction of type %d", tx.Type())
+	}
+	return h.signer.Signer(h.signer.From, gethtypes.NewTx(data))
+}
+
+// bumpFee multiplies fee by factor, and raises it by at least one wei so a bump of a tiny fee still counts
+func bumpFee(fee *big.Int, factor float64) *big.Int {
+	bumped, _ := new(big.Float).Mul(new(big.Float).SetInt(fee), big.NewFloat(factor)).Int(nil)
+	if bumped.Cmp(fee) <= 0 {
+		bumped = new(big.Int).Add(fee, big.NewInt(1))
+	}
+	return bumped
+}
+
+// maxBig returns the larger of a and b
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,125 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// droppingEVMBackend accepts the first drop transactions without ever mining them, like a node
+// whose mempool holds underpriced transactions
+type droppingEVMBackend struct {
+	*chainmock.EVMBackend
+	drop    int
+	dropped []*gethtypes.Transaction
+}
+
+func (b *droppingEVMBackend) SendTransaction(ctx context.Context, tx *gethtypes.Transaction) error {
+	if len(b.dropped) < b.drop {
+		b.dropped = append(b.dropped, tx)
+		return nil
+	}
+	return b.EVMBackend.SendTransaction(ctx, tx)
+}
+
+func TestLoadEVMReplaceConfig(t *testing.T) {
+	cfg, err := loadEVMReplaceConfig()
+	require.NoError(t, err)
+	assert.Equal(t, evmReplaceConfig{StuckTimeout: 90 * time.Second, MaxReplacements: 2, GasBump: 1.125}, cfg)
+
+	t.Setenv("EVM_TX_GAS_BUMP", "1.05")
+	_, err = loadEVMReplaceConfig()
+	assert.ErrorContains(t, err, "EVM_TX_GAS_BUMP must be >= 1.1")
+}
+
+func TestEVMTxReplacement(t *testing.T) {
+	t.Setenv("BASE_BLOCK_TIME_MS", "1")
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(config.BaseSepoliaChainID))
+	require.NoError(t, err)
+	router := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	args := &types.ParsedArgs{OrderID: settleOrderID(1)}
+
+	setup := func(t *testing.T, drop int) (*HyperlaneEVM, *droppingEVMBackend, *gethtypes.Transaction) {
+		backend := &droppingEVMBackend{EVMBackend: chainmock.NewEVMBackend(config.BaseSepoliaChainID), drop: drop}
+		outbox, err := OpenTxOutbox(storage.NewFileStorage(filepath.Join(t.TempDir(), "outbox"), nil))
+		require.NoError(t, err)
+		handler := NewHyperlaneEVM(backend, signer, config.BaseSepoliaChainID)
+		handler.txOutbox = outbox
+		handler.replace = evmReplaceConfig{StuckTimeout: 20 * time.Millisecond, MaxReplacements: 2, GasBump: 1.125}
+
+		tx, err := signer.Signer(signer.From, gethtypes.NewTx(&gethtypes.DynamicFeeTx{
+			ChainID: big.NewInt(config.BaseSepoliaChainID), GasTipCap: big.NewInt(100), GasFeeCap: big.NewInt(1000), Gas: 21000, To: &router,
+		}))
+		require.NoError(t, err)
+		require.NoError(t, outbox.Begin(orders.TxKindSettle, config.BaseSepoliaChainID, args.OrderID))
+		require.NoError(t, backend.SendTransaction(context.Background(), tx))
+		outbox.AddTx(orders.TxKindSettle, config.BaseSepoliaChainID, args.OrderID, tx.Hash().Hex())
+		return handler, backend, tx
+	}
+
+	t.Run("a mined transaction is not replaced", func(t *testing.T) {
+		handler, backend, tx := setup(t, 0)
+		mined, receipt, err := handler.waitMined(context.Background(), args, orders.TxKindSettle, router, tx, TxAuditEntry{})
+		require.NoError(t, err)
+		assert.Equal(t, tx.Hash(), mined.Hash())
+		assert.Equal(t, gethtypes.ReceiptStatusSuccessful, receipt.Status)
+		assert.Len(t, backend.Sent(), 1)
+	})
+
+	t.Run("a stuck transaction is replaced with bumped fees", func(t *testing.T) {
+		handler, backend, tx := setup(t, 1)
+		var recorded []orders.OrderTx
+		handler.recordSent = func(orderID string, tx orders.OrderTx) { recorded = append(recorded, tx) }
+		mined, _, err := handler.waitMined(context.Background(), args, orders.TxKindSettle, router, tx, TxAuditEntry{})
+		require.NoError(t, err)
+
+		assert.NotEqual(t, tx.Hash(), mined.Hash())
+		assert.Equal(t, tx.Nonce(), mined.Nonce())
+		assert.Equal(t, tx.Data(), mined.Data())
+		// The node suggests a 1 gwei tip, above the bumped one
+		assert.Equal(t, big.NewInt(1_000_000_000), mined.GasTipCap())
+		assert.Equal(t, big.NewInt(1_000_000_000), mined.GasFeeCap())
+
+		entry, ok := handler.txOutbox.Get(orders.TxKindSettle, config.BaseSepoliaChainID, args.OrderID)
+		require.True(t, ok)
+		assert.Equal(t, []string{tx.Hash().Hex(), mined.Hash().Hex()}, entry.TxHashes)
+		require.Len(t, recorded, 1, "the order store gets the replacement too")
+		assert.Equal(t, mined.Hash().Hex(), recorded[0].TxHash)
+		assert.Equal(t, orders.TxKindSettle, recorded[0].Kind)
+		assert.Len(t, backend.Sent(), 1)
+	})
+
+	t.Run("gives up after the last replacement", func(t *testing.T) {
+		handler, _, tx := setup(t, 3)
+		_, _, err := handler.waitMined(context.Background(), args, orders.TxKindSettle, router, tx, TxAuditEntry{})
+		assert.True(t, errors.Is(err, errEVMTxStuck))
+
+		entry, ok := handler.txOutbox.Get(orders.TxKindSettle, config.BaseSepoliaChainID, args.OrderID)
+		require.True(t, ok, "the next attempt looks the versions up")
+		assert.Len(t, entry.TxHashes, 3)
+	})
+}
+
+func TestBumpFee(t *testing.T) {
+	assert.Equal(t, big.NewInt(1125), bumpFee(big.NewInt(1000), 1.125))
+	assert.Equal(t, big.NewInt(2), bumpFee(big.NewInt(1), 1.125), "at least one wei more")
+	assert.Equal(t, big.NewInt(1), bumpFee(big.NewInt(0), 1.125))
+}
@@ -0,0 +1,121 @@
+package hyperlane7683
+
+// Module: Cross-token profitability
+// - Orders normally spend and receive the same token (DogCoin ↔ DogCoin) and are compared in base
+//   units; when the registry shows the solver would spend one token and receive another, both sides
+//   are valued through the price feed (see package pricefeed) instead
+// - The effective exchange rate is the value received over the value spent; the order passes when,
+//   after an adverse price move of rules.maxSlippageBps, it still leaves rules.minMarginBps of margin:
+//   received × (1 − slippage) ≥ spent × (1 + margin)
+// - Orders with a token the feed can't price are rejected with no_price
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/pricefeed"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// bpsDenominator is 100% in basis points
+const bpsDenominator = 10_000
+
+// isCrossTokenOrder reports whether the order spends and receives different tokens, by registry
+// symbol. Orders with a token of unknown symbol are assumed like-for-like, as before prices existed.
+func isCrossTokenOrder(args *types.ParsedArgs) bool {
+	symbols := make(map[string]bool)
+	for _, side := range orderSides(args) {
+		for _, output := range side.outputs {
+			token, ok := config.LookupToken(outputChainID(output, side.fallbackChain), output.Token)
+			if !ok {
+				return false
+			}
+			symbols[token.Symbol] = true
+		}
+	}
+	return len(symbols) > 1
+}
+
+// orderSide is one side of an order with the chain of outputs that don't set theirs
+type orderSide struct {
+	outputs       []types.Output
+	fallbackChain *big.Int
+}
+
+// orderSides returns what the solver spends (MaxSpent, on the destination) and receives (MinReceived, on the origin)
+func orderSides(args *types.ParsedArgs) [2]orderSide {
+	var destination *big.Int
+	if len(args.ResolvedOrder.FillInstructions) > 0 {
+		destination = args.ResolvedOrder.FillInstructions[0].DestinationChainID
+	}
+	return [2]orderSide{
+		{outputs: args.ResolvedOrder.MaxSpent, fallbackChain: destination},
+		{outputs: args.ResolvedOrder.MinReceived, fallbackChain: args.ResolvedOrder.OriginChainID},
+	}
+}
+
+// sideValue adds up the value of the outputs of one side of an order at the feed's prices
+func sideValue(ctx context.Context, feed pricefeed.Feed, side orderSide) (*big.Int, error) {
+	total := new(big.Int)
+	for _, output := range side.outputs {
+		if output.Amount == nil {
+			return nil, fmt.Errorf("output of token %s has no amount", output.Token)
+		}
+		value, err := pricefeed.Value(ctx, feed, outputChainID(output, side.fallbackChain), output.Token, output.Amount)
+		if err != nil {
+			return nil, err
+		}
+		total.Add(total, value)
+	}
+	return total, nil
+}
+
+// evaluateCrossToken checks that the value received covers the value spent with the configured
+// margin and slippage allowance
+func (pr *ProfitabilityRule) evaluateCrossToken(ctx context.Context, args *types.ParsedArgs) RuleResult {
+	feed := pr.Prices
+	if feed == nil {
+		feed = pricefeed.Default()
+	}
+
+	sides := orderSides(args)
+	spent, err := sideValue(ctx, feed, sides[0])
+	if err == nil && spent.Sign() == 0 {
+		err = errors.New("the order spends nothing")
+	}
+	if err != nil {
+		return RuleResult{Passed: false, Reason: fmt.Sprintf("Cannot value the tokens spent: %v", err), Code: rejectCodeForPriceError(err)}
+	}
+	received, err := sideValue(ctx, feed, sides[1])
+	if err != nil {
+		return RuleResult{Passed: false, Reason: fmt.Sprintf("Cannot value the tokens received: %v", err), Code: rejectCodeForPriceError(err)}
+	}
+
+	// received × (10000 − slippage) ≥ spent × (10000 + margin), both in basis points
+	worstReceived := new(big.Int).Mul(received, big.NewInt(int64(bpsDenominator-pr.MaxSlippageBps)))
+	required := new(big.Int).Mul(spent, big.NewInt(int64(bpsDenominator+pr.MinMarginBps)))
+
+	rate := new(big.Rat).SetFrac(received, spent)
+	summary := fmt.Sprintf("receives %s for %s spent (rate %s)",
+		amount.New(received, config.NotionalDecimals), amount.New(spent, config.NotionalDecimals), rate.FloatString(4))
+	if worstReceived.Cmp(required) < 0 {
+		return RuleResult{
+			Passed: false,
+			Reason: fmt.Sprintf("Cross-token order below margin: %s, need %d bps margin after %d bps slippage",
+				summary, pr.MinMarginBps, pr.MaxSlippageBps),
+		}
+	}
+	return RuleResult{Passed: true, Reason: "Cross-token order profitable: " + summary}
+}
+
+// rejectCodeForPriceError is no_price for tokens the feed can't price, below_margin otherwise
+func rejectCodeForPriceError(err error) string {
+	if errors.Is(err, pricefeed.ErrNoPrice) {
+		return RejectNoPrice
+	}
+	return RejectBelowMargin
+}
@@ -0,0 +1,98 @@
+package hyperlane7683
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// fixedFeed prices every token at the same price
+type fixedFeed struct{ price *big.Int }
+
+func (f fixedFeed) Price(context.Context, uint64, string) (*big.Int, error) {
+	return f.price, nil
+}
+
+func TestCrossTokenProfitability(t *testing.T) {
+	config.InitializeNetworks()
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+	require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+		{ChainID: config.BaseSepoliaChainID, Address: "0xd0", Symbol: "DOG", Decimals: 18, Price: "1"},
+		{ChainID: config.EthereumSepoliaChainID, Address: "0xc0", Symbol: "USDC", Decimals: 6, Price: "2"},
+		{ChainID: config.EthereumSepoliaChainID, Address: "0xd1", Symbol: "DOG", Decimals: 18, Price: "1"},
+		{ChainID: config.EthereumSepoliaChainID, Address: "0xe0", Symbol: "CAT", Decimals: 18},
+	}}))
+
+	dogs := new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))
+	// Spends 100 DOG on Base Sepolia for received tokens on Ethereum Sepolia
+	orderArgs := func(receivedToken string, received *big.Int) types.ParsedArgs {
+		return types.ParsedArgs{
+			OrderID: "0x5555555555555555555555555555555555555555555555555555555555555555",
+			ResolvedOrder: types.ResolvedCrossChainOrder{
+				OriginChainID:    big.NewInt(config.EthereumSepoliaChainID),
+				MaxSpent:         []types.Output{{Token: "0xd0", Amount: dogs}},
+				MinReceived:      []types.Output{{Token: receivedToken, Amount: received}},
+				FillInstructions: []types.FillInstruction{{DestinationChainID: big.NewInt(config.BaseSepoliaChainID)}},
+			},
+		}
+	}
+	evaluate := func(rule *ProfitabilityRule, args types.ParsedArgs) RuleResult {
+		return rule.Evaluate(context.Background(), &args)
+	}
+	// 51 USDC at 2 is worth 102 DOG: a 200 bps margin
+	usdc := big.NewInt(51_000_000)
+
+	t.Run("Like-for-like orders compare base units", func(t *testing.T) {
+		args := orderArgs("0xd1", new(big.Int).Add(dogs, big.NewInt(1)))
+		assert.False(t, isCrossTokenOrder(&args))
+		assert.True(t, evaluate(&ProfitabilityRule{MinMarginBps: 100}, args).Passed, "margins only apply across tokens")
+	})
+
+	t.Run("Orders with an unknown token are like-for-like", func(t *testing.T) {
+		args := orderArgs("0xf0", dogs)
+		assert.False(t, isCrossTokenOrder(&args))
+	})
+
+	t.Run("Different tokens are valued at their prices", func(t *testing.T) {
+		args := orderArgs("0xc0", usdc)
+		assert.True(t, isCrossTokenOrder(&args))
+
+		result := evaluate(&ProfitabilityRule{MinMarginBps: 200}, args)
+		assert.True(t, result.Passed)
+		assert.Equal(t, "Cross-token order profitable: receives 102 for 100 spent (rate 1.0200)", result.Reason)
+		assert.True(t, evaluate(&ProfitabilityRule{MinMarginBps: 150, MaxSlippageBps: 49}, args).Passed)
+	})
+
+	t.Run("Orders below margin after slippage are rejected", func(t *testing.T) {
+		args := orderArgs("0xc0", usdc)
+		result := evaluate(&ProfitabilityRule{MinMarginBps: 201}, args)
+		assert.False(t, result.Passed)
+		assert.Contains(t, result.Reason, "Cross-token order below margin: receives 102 for 100 spent")
+
+		// 102 × 0.99 = 100.98 < 101.5
+		result = evaluate(&ProfitabilityRule{MinMarginBps: 150, MaxSlippageBps: 100}, args)
+		assert.False(t, result.Passed)
+		assert.Contains(t, result.Reason, "need 150 bps margin after 100 bps slippage")
+	})
+
+	t.Run("Unpriced tokens are rejected", func(t *testing.T) {
+		result := evaluate(&ProfitabilityRule{}, orderArgs("0xe0", dogs))
+		assert.False(t, result.Passed)
+		assert.Equal(t, RejectNoPrice, result.Code)
+		assert.Contains(t, result.Reason, "CAT on chain 11155111 has no price")
+	})
+
+	t.Run("The rule's feed overrides registry prices", func(t *testing.T) {
+		// At equal prices 100 CAT for 100 DOG leaves no margin
+		rule := &ProfitabilityRule{Prices: fixedFeed{price: big.NewInt(1e18)}}
+		assert.True(t, evaluate(rule, orderArgs("0xe0", dogs)).Passed)
+		rule.MinMarginBps = 1
+		assert.False(t, evaluate(rule, orderArgs("0xe0", dogs)).Passed)
+	})
+}
@@ -0,0 +1,126 @@
+package hyperlane7683
+
+// Module: Fill metrics
+// - Each fill the solver sends is measured once its transaction is included: volume per chain pair
+//   and token, margin and latency, for the Grafana dashboard in dashboards/solver.json
+// - Multi-instruction orders are measured per leg; their margin is left out, since it is only
+//   defined for the whole order (without an order store every fill is taken as a whole order)
+// - Margins are valued through the price feed, or in base units for like-for-like orders the feed
+//   can't price (as ProfitabilityRule does); the average is _sum / _count of the histogram
+// - Latency runs from the order's first sighting (its order store record) to the fill's inclusion
+//
+// Metrics:
+// - solver_fills_total{origin,destination}: fills sent
+// - solver_filled_volume_total{origin,destination,token}: output paid, in whole tokens; the token is
+//   its registry symbol, or its address when unknown (counted with 18 decimals)
+// - solver_fill_margin_bps{origin,destination}: histogram of order margins, in basis points of the value spent
+// - solver_fill_latency_seconds{origin,destination}: histogram of the time from sighting to fill
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/pricefeed"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const (
+	fillsMetric        = "solver_fills_total"
+	filledVolumeMetric = "solver_filled_volume_total"
+	fillMarginMetric   = "solver_fill_margin_bps"
+	fillLatencyMetric  = "solver_fill_latency_seconds"
+)
+
+// marginBuckets are the upper bounds of solver_fill_margin_bps; losses fall in the first bucket
+var marginBuckets = []float64{0, 5, 10, 25, 50, 100, 200, 500, 1000, 2500}
+
+// recordFillMetrics measures a fill of leg included at filledAt
+func (f *Hyperlane7683Solver) recordFillMetrics(leg *types.ParsedArgs, filledAt time.Time) {
+	if f.metrics == nil || len(leg.ResolvedOrder.FillInstructions) == 0 {
+		return
+	}
+	destinationChainID := leg.ResolvedOrder.FillInstructions[0].DestinationChainID
+	pair := []string{"origin", chainLabel(leg.ResolvedOrder.OriginChainID), "destination", chainLabel(destinationChainID)}
+
+	f.metrics.Counter(fillsMetric, pair...).Inc()
+	for _, output := range leg.ResolvedOrder.MaxSpent {
+		token, volume := outputVolume(outputChainID(output, destinationChainID), output)
+		f.metrics.Counter(filledVolumeMetric, append(pair, "token", token)...).Add(volume)
+	}
+
+	var order orders.Order
+	stored := false
+	if f.orderStore != nil {
+		order, stored = f.orderStore.Get(leg.OrderID)
+	}
+
+	// A leg only carries part of what the order spends; the stored order tells whether it has others
+	singleLeg := !stored || order.Args == nil || len(order.Args.ResolvedOrder.FillInstructions) <= 1
+	if margin, ok := fillMarginBps(context.Background(), pricefeed.Default(), leg); ok && singleLeg {
+		f.metrics.Histogram(fillMarginMetric, marginBuckets, pair...).Observe(margin)
+	}
+
+	if stored && !order.CreatedAt.IsZero() {
+		f.metrics.Histogram(fillLatencyMetric, metrics.LatencyBuckets, pair...).Observe(filledAt.Sub(order.CreatedAt).Seconds())
+	}
+}
+
+// chainLabel names a chain for metric labels
+func chainLabel(chainID *big.Int) string {
+	if chainID == nil {
+		return "unknown"
+	}
+	return logutil.NetworkNameByChainID(chainID.Uint64())
+}
+
+// outputVolume returns the token label and whole-token amount of an output
+func outputVolume(chainID uint64, output types.Output) (string, float64) {
+	label, decimals := output.Token, config.NotionalDecimals
+	if token, ok := config.LookupToken(chainID, output.Token); ok {
+		label, decimals = token.Symbol, token.Decimals
+	}
+	if output.Amount == nil {
+		return label, 0
+	}
+	volume, _ := new(big.Rat).SetFrac(output.Amount, amount.Pow10(decimals)).Float64()
+	return label, volume
+}
+
+// fillMarginBps returns the margin of an order in basis points of the value spent
+func fillMarginBps(ctx context.Context, feed pricefeed.Feed, args *types.ParsedArgs) (float64, bool) {
+	sides := orderSides(args)
+	spent, err := sideValue(ctx, feed, sides[0])
+	var received *big.Int
+	if err == nil {
+		received, err = sideValue(ctx, feed, sides[1])
+	}
+	if errors.Is(err, pricefeed.ErrNoPrice) && !isCrossTokenOrder(args) {
+		// NOTE: Like ProfitabilityRule, like-for-like orders are compared in base units
+		spent, received, err = outputsTotal(args.ResolvedOrder.MaxSpent), outputsTotal(args.ResolvedOrder.MinReceived), nil
+	}
+	if err != nil || spent.Sign() <= 0 {
+		return 0, false
+	}
+
+	margin := new(big.Rat).SetFrac(new(big.Int).Sub(received, spent), spent)
+	bps, _ := margin.Mul(margin, big.NewRat(bpsDenominator, 1)).Float64()
+	return bps, true
+}
+
+// outputsTotal adds up the amounts of outputs in base units
+func outputsTotal(outputs []types.Output) *big.Int {
+	total := new(big.Int)
+	for _, output := range outputs {
+		if output.Amount != nil {
+			total.Add(total, output.Amount)
+		}
+	}
+	return total
+}
@@ -0,0 +1,91 @@
+package hyperlane7683
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFillMetrics(t *testing.T) {
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+	require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+		{ChainID: config.BaseSepoliaChainID, Address: "0xd0", Symbol: "DOG", Decimals: 18, Price: "1"},
+		{ChainID: config.EthereumSepoliaChainID, Address: "0xc0", Symbol: "USDC", Decimals: 6, Price: "2"},
+	}}))
+
+	newSolver := func(t *testing.T) (*Hyperlane7683Solver, *orders.Store, *metrics.Registry) {
+		store, err := orders.NewStore(filepath.Join(t.TempDir(), "orders.json"))
+		require.NoError(t, err)
+		solver := NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{})
+		solver.SetOrderStore(store)
+		solver.metrics = metrics.NewRegistry()
+		return solver, store, solver.metrics
+	}
+	dogs := new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))
+	// Spends 100 DOG on Base Sepolia for 51 USDC on Ethereum Sepolia, worth 102 DOG: a 200 bps margin
+	orderArgs := func(spentToken, receivedToken string, received *big.Int) types.ParsedArgs {
+		return types.ParsedArgs{
+			OrderID: settleOrderID(7),
+			ResolvedOrder: types.ResolvedCrossChainOrder{
+				OriginChainID:    big.NewInt(config.EthereumSepoliaChainID),
+				MaxSpent:         []types.Output{{Token: spentToken, Amount: dogs}},
+				MinReceived:      []types.Output{{Token: receivedToken, Amount: received}},
+				FillInstructions: []types.FillInstruction{{DestinationChainID: big.NewInt(config.BaseSepoliaChainID)}},
+			},
+		}
+	}
+	pair := []string{"origin", "Ethereum", "destination", "Base"}
+
+	t.Run("fills are measured per chain pair and token", func(t *testing.T) {
+		solver, store, registry := newSolver(t)
+		args := orderArgs("0xd0", "0xc0", big.NewInt(51_000_000))
+		require.NoError(t, store.Observe(&args))
+
+		solver.recordTxCost(&args, orders.TxCost{ChainID: config.BaseSepoliaChainID, Kind: orders.TxKindApprove, TxHash: "0x01"})
+		assert.Zero(t, registry.Counter(fillsMetric, pair...).Value(), "approvals are not fills")
+
+		solver.recordTxCost(&args, orders.TxCost{ChainID: config.BaseSepoliaChainID, Kind: orders.TxKindFill, TxHash: "0x02"})
+		assert.Equal(t, 1.0, registry.Counter(fillsMetric, pair...).Value())
+		assert.Equal(t, 100.0, registry.Counter(filledVolumeMetric, append(pair, "token", "DOG")...).Value())
+
+		margin := registry.Histogram(fillMarginMetric, nil, pair...)
+		assert.Equal(t, uint64(1), margin.Count())
+		assert.InDelta(t, 200, margin.Sum(), 1e-9)
+
+		latency := registry.Histogram(fillLatencyMetric, nil, pair...)
+		assert.Equal(t, uint64(1), latency.Count())
+		assert.GreaterOrEqual(t, latency.Sum(), 0.0)
+	})
+
+	t.Run("unpriced like-for-like orders compare base units", func(t *testing.T) {
+		solver, _, registry := newSolver(t)
+		args := orderArgs("0xf0", "0xf1", new(big.Int).Add(dogs, new(big.Int).Div(dogs, big.NewInt(100))))
+
+		solver.recordTxCost(&args, orders.TxCost{ChainID: config.BaseSepoliaChainID, Kind: orders.TxKindFillSettle, TxHash: "0x03"})
+		assert.Equal(t, 100.0, registry.Counter(filledVolumeMetric, append(pair, "token", "0xf0")...).Value(), "unknown tokens count with 18 decimals")
+		assert.InDelta(t, 100, registry.Histogram(fillMarginMetric, nil, pair...).Sum(), 1e-9)
+		assert.Zero(t, registry.Histogram(fillLatencyMetric, nil, pair...).Count(), "the order was never observed")
+	})
+
+	t.Run("legs of multi-instruction orders have no margin", func(t *testing.T) {
+		solver, store, registry := newSolver(t)
+		args := orderArgs("0xd0", "0xc0", big.NewInt(51_000_000))
+		args.ResolvedOrder.FillInstructions = append(args.ResolvedOrder.FillInstructions,
+			types.FillInstruction{DestinationChainID: big.NewInt(config.EthereumSepoliaChainID)})
+		require.NoError(t, store.Observe(&args))
+
+		solver.recordTxCost(legArgs(&args, 0), orders.TxCost{ChainID: config.BaseSepoliaChainID, Kind: orders.TxKindFill, TxHash: "0x04"})
+		assert.Equal(t, 100.0, registry.Counter(filledVolumeMetric, append(pair, "token", "DOG")...).Value())
+		assert.Zero(t, registry.Histogram(fillMarginMetric, nil, pair...).Count())
+	})
+}
@@ -0,0 +1,119 @@
+package hyperlane7683
+
+// Module: Settlement gas payment quotes
+// - Caches quote_gas_payment results per (chain, origin domain, destination settler) for a short TTL
+// - A failed settle invalidates its quote so the next attempt quotes again
+// - Quotes above the configured cap are refused, so no settle ever attaches more than the cap
+//
+// Settings:
+// - SETTLE_GAS_QUOTE_TTL_SECONDS: how long a quote is reused (default 30, 0 disables caching)
+// - SETTLE_MAX_GAS_PAYMENT_ETH: maximum gas payment attached to a settle, in ETH (unset or 0: no cap)
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+)
+
+const defaultGasQuoteTTLSeconds = 30
+
+// ErrGasPaymentAboveCap is returned for quotes above SETTLE_MAX_GAS_PAYMENT_ETH
+var ErrGasPaymentAboveCap = errors.New("settle gas payment above cap")
+
+// gasQuoteKey identifies the quote of one settler for one origin domain
+type gasQuoteKey struct {
+	chainID      uint64
+	originDomain uint32
+	settler      string
+}
+
+type gasQuote struct {
+	amount    *big.Int
+	expiresAt time.Time
+}
+
+// GasQuoteCache caches settlement gas quotes and enforces the gas payment cap
+type GasQuoteCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxPayment *big.Int // nil means no cap
+	capErr     error    // invalid cap setting; every quote fails until it is fixed
+	now        func() time.Time
+	quotes     map[gasQuoteKey]gasQuote
+}
+
+// NewGasQuoteCache creates a cache; ttl <= 0 disables caching and a nil maxPayment disables the cap
+func NewGasQuoteCache(ttl time.Duration, maxPayment *big.Int) *GasQuoteCache {
+	return &GasQuoteCache{
+		ttl:        ttl,
+		maxPayment: maxPayment,
+		now:        time.Now,
+		quotes:     make(map[gasQuoteKey]gasQuote),
+	}
+}
+
+var (
+	defaultGasQuoteCache     *GasQuoteCache
+	defaultGasQuoteCacheOnce sync.Once
+)
+
+// DefaultGasQuoteCache returns the process-wide cache shared by the chain handlers
+func DefaultGasQuoteCache() *GasQuoteCache {
+	defaultGasQuoteCacheOnce.Do(func() {
+		ttl := time.Duration(envutil.GetEnvInt("SETTLE_GAS_QUOTE_TTL_SECONDS", defaultGasQuoteTTLSeconds)) * time.Second
+		maxPayment, err := parseFeeCap("SETTLE_MAX_GAS_PAYMENT_ETH")
+		defaultGasQuoteCache = NewGasQuoteCache(ttl, maxPayment)
+		if err != nil {
+			// Fail closed: without a valid cap the solver must not attach any gas payment
+			fmt.Printf("⚠️  %v; settlements are disabled until it is fixed\n", err)
+			defaultGasQuoteCache.capErr = err
+		}
+	})
+	return defaultGasQuoteCache
+}
+
+// Quote returns the cached quote for the settler, calling fetch when there is none or it expired
+func (c *GasQuoteCache) Quote(chainID uint64, originDomain uint32, settler string, fetch func() (*big.Int, error)) (*big.Int, error) {
+	if c.capErr != nil {
+		return nil, c.capErr
+	}
+	key := gasQuoteKey{chainID: chainID, originDomain: originDomain, settler: strings.ToLower(settler)}
+
+	c.mu.Lock()
+	cached, ok := c.quotes[key]
+	c.mu.Unlock()
+
+	amount := cached.amount
+	if !ok || !c.now().Before(cached.expiresAt) {
+		fetched, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		if fetched == nil || fetched.Sign() < 0 {
+			return nil, fmt.Errorf("invalid gas payment quote: %v", fetched)
+		}
+		amount = fetched
+		if c.ttl > 0 {
+			c.mu.Lock()
+			c.quotes[key] = gasQuote{amount: new(big.Int).Set(amount), expiresAt: c.now().Add(c.ttl)}
+			c.mu.Unlock()
+		}
+	}
+
+	if c.maxPayment != nil && amount.Cmp(c.maxPayment) > 0 {
+		return nil, fmt.Errorf("%w: quoted %s wei, cap %s wei", ErrGasPaymentAboveCap, amount, c.maxPayment)
+	}
+	return new(big.Int).Set(amount), nil
+}
+
+// Invalidate drops the settler's cached quote, e.g. after a settle using it failed
+func (c *GasQuoteCache) Invalidate(chainID uint64, originDomain uint32, settler string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.quotes, gasQuoteKey{chainID: chainID, originDomain: originDomain, settler: strings.ToLower(settler)})
+}
@@ -0,0 +1,131 @@
+package hyperlane7683
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingFetch returns a quote fetcher that counts its calls
+func countingFetch(amount int64, calls *int) func() (*big.Int, error) {
+	return func() (*big.Int, error) {
+		*calls++
+		return big.NewInt(amount), nil
+	}
+}
+
+func TestGasQuoteCache(t *testing.T) {
+	const settler = "0xAbC0000000000000000000000000000000000001"
+
+	t.Run("quotes are reused until they expire", func(t *testing.T) {
+		cache := NewGasQuoteCache(30*time.Second, nil)
+		now := time.Unix(1_700_000_000, 0)
+		cache.now = func() time.Time { return now }
+
+		calls := 0
+		for i := 0; i < 3; i++ {
+			quote, err := cache.Quote(1, 10, settler, countingFetch(7, &calls))
+			require.NoError(t, err)
+			assert.Equal(t, int64(7), quote.Int64())
+		}
+		assert.Equal(t, 1, calls)
+
+		now = now.Add(30 * time.Second)
+		_, err := cache.Quote(1, 10, settler, countingFetch(8, &calls))
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("keys are per chain, origin domain and settler", func(t *testing.T) {
+		cache := NewGasQuoteCache(time.Minute, nil)
+		calls := 0
+		_, _ = cache.Quote(1, 10, settler, countingFetch(7, &calls))
+		_, _ = cache.Quote(2, 10, settler, countingFetch(7, &calls))
+		_, _ = cache.Quote(1, 11, settler, countingFetch(7, &calls))
+		_, _ = cache.Quote(1, 10, "0x02", countingFetch(7, &calls))
+		assert.Equal(t, 4, calls)
+
+		// Address case does not matter
+		_, _ = cache.Quote(1, 10, "0xabc0000000000000000000000000000000000001", countingFetch(7, &calls))
+		assert.Equal(t, 4, calls)
+	})
+
+	t.Run("invalidate forces a new quote", func(t *testing.T) {
+		cache := NewGasQuoteCache(time.Minute, nil)
+		calls := 0
+		_, _ = cache.Quote(1, 10, settler, countingFetch(7, &calls))
+		cache.Invalidate(1, 10, settler)
+		quote, err := cache.Quote(1, 10, settler, countingFetch(9, &calls))
+		require.NoError(t, err)
+		assert.Equal(t, int64(9), quote.Int64())
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("zero ttl disables caching", func(t *testing.T) {
+		cache := NewGasQuoteCache(0, nil)
+		calls := 0
+		_, _ = cache.Quote(1, 10, settler, countingFetch(7, &calls))
+		_, _ = cache.Quote(1, 10, settler, countingFetch(7, &calls))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("fetch errors are not cached", func(t *testing.T) {
+		cache := NewGasQuoteCache(time.Minute, nil)
+		_, err := cache.Quote(1, 10, settler, func() (*big.Int, error) { return nil, errors.New("rpc down") })
+		assert.EqualError(t, err, "rpc down")
+
+		calls := 0
+		_, err = cache.Quote(1, 10, settler, countingFetch(7, &calls))
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+
+		_, err = NewGasQuoteCache(time.Minute, nil).Quote(1, 10, settler, func() (*big.Int, error) { return big.NewInt(-1), nil })
+		assert.Error(t, err)
+	})
+
+	t.Run("quotes above the cap are refused", func(t *testing.T) {
+		cache := NewGasQuoteCache(time.Minute, big.NewInt(100))
+		quote, err := cache.Quote(1, 10, settler, func() (*big.Int, error) { return big.NewInt(100), nil })
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), quote.Int64())
+
+		_, err = cache.Quote(1, 11, settler, func() (*big.Int, error) { return big.NewInt(101), nil })
+		assert.ErrorIs(t, err, ErrGasPaymentAboveCap)
+	})
+
+	t.Run("callers cannot modify the cached quote", func(t *testing.T) {
+		cache := NewGasQuoteCache(time.Minute, nil)
+		calls := 0
+		quote, _ := cache.Quote(1, 10, settler, countingFetch(7, &calls))
+		quote.SetInt64(1_000_000)
+		again, _ := cache.Quote(1, 10, settler, countingFetch(7, &calls))
+		assert.Equal(t, int64(7), again.Int64())
+	})
+
+	t.Run("invalid cap setting fails closed", func(t *testing.T) {
+		t.Setenv("SETTLE_MAX_GAS_PAYMENT_ETH", "lots")
+		defaultGasQuoteCacheOnce = sync.Once{}
+		defer func() { defaultGasQuoteCacheOnce = sync.Once{} }()
+
+		calls := 0
+		_, err := DefaultGasQuoteCache().Quote(1, 10, settler, countingFetch(7, &calls))
+		assert.ErrorContains(t, err, "SETTLE_MAX_GAS_PAYMENT_ETH")
+		assert.Zero(t, calls)
+	})
+
+	t.Run("cap setting is in ETH", func(t *testing.T) {
+		t.Setenv("SETTLE_MAX_GAS_PAYMENT_ETH", "0.001")
+		t.Setenv("SETTLE_GAS_QUOTE_TTL_SECONDS", "5")
+		defaultGasQuoteCacheOnce = sync.Once{}
+		defer func() { defaultGasQuoteCacheOnce = sync.Once{} }()
+
+		cache := DefaultGasQuoteCache()
+		assert.Equal(t, big.NewInt(1_000_000_000_000_000), cache.maxPayment)
+		assert.Equal(t, 5*time.Second, cache.ttl)
+	})
+}
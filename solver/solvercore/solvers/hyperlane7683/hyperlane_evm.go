@@ -11,6 +11,7 @@ package hyperlane7683
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
@@ -18,23 +19,25 @@ import (
 	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
-	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/pricefeed"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 const (
 	// Order status constants
-	orderStatusFilled  = "FILLED"
-	orderStatusSettled = "SETTLED"
-	orderStatusUnknown = "UNKNOWN"
+	orderStatusFilled   = "FILLED"
+	orderStatusSettled  = "SETTLED"
+	orderStatusUnknown  = "UNKNOWN"
+	orderStatusOpened   = "OPENED"   // Origin-side status of an open order
+	orderStatusRefunded = "REFUNDED" // Origin-side status of a refunded order
 )
 
 const (
@@ -44,29 +47,58 @@ const (
 	approveGasLimit = 200000
 )
 
+// approvalSettleDelay gives the chain time to reflect new approvals before the fill is sent;
+// a variable so benchmarks can run without it
+var approvalSettleDelay = 1 * time.Second
+
 // HyperlaneEVM contains all EVM-specific logic for the Hyperlane7683 protocol
 type HyperlaneEVM struct {
-	client  *ethclient.Client
+	client  EVMClient
 	signer  *bind.TransactOpts
 	chainID uint64
 	mu      sync.Mutex // Serialize operations to prevent nonce conflicts
+
+	// Receives the cost of every transaction sent for an order
+	recordCost txCostRecorder
+	// Receives the hash of every fill and settle broadcast for an order
+	recordSent txSentRecorder
+	// Tells our own fills from competing solvers' fills of an order
+	fillOwnership fillOwnership
+	// Settlement gas quotes and the gas payment cap
+	gasQuotes *GasQuoteCache
+	// Daily fee and fill notional limits
+	spendLimits *SpendLimiter
+	// Pending and per-minute transaction limits
+	txLimits *TxRateLimiter
+	// Records every transaction sent; nil disables the audit log
+	txAudit *TxAuditLog
+	// Fill and settle transactions in flight; nil sends them without looking for an earlier one
+	txOutbox *TxOutbox
+	// Replacement of stuck fills and settles (see evm_replace.go); the zero value never replaces
+	replace evmReplaceConfig
+	// Whether approvals cover the amount needed or are unlimited (see approvals.go)
+	approvalPolicy ApprovalPolicy
+	// Signs ERC-2612 permits; nil approves every token with approve (see permits.go)
+	signPermit permitSigner
+	// Permit support per token, guarded by mu; created on first use
+	permitTokens map[common.Address]int
 }
 
 // NewHyperlaneEVM creates a new EVM handler for Hyperlane operations
-func NewHyperlaneEVM(client *ethclient.Client, signer *bind.TransactOpts, chainID uint64) *HyperlaneEVM {
+func NewHyperlaneEVM(client EVMClient, signer *bind.TransactOpts, chainID uint64) *HyperlaneEVM {
 	return &HyperlaneEVM{
-		client:  client,
-		signer:  signer,
-		chainID: chainID,
-		mu:      sync.Mutex{},
+		client:      client,
+		signer:      signer,
+		chainID:     chainID,
+		mu:          sync.Mutex{},
+		gasQuotes:   DefaultGasQuoteCache(),
+		spendLimits: DefaultSpendLimiter(),
+		txLimits:    DefaultTxRateLimiter(),
 	}
 }
 
 // Fill executes a fill operation on an EVM chain
 func (h *HyperlaneEVM) Fill(ctx context.Context, args *types.ParsedArgs) (OrderAction, error) {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
 	if len(args.ResolvedOrder.FillInstructions) == 0 {
 		return OrderActionError, fmt.Errorf("no fill instructions found")
 	}
@@ -84,10 +116,24 @@ func (h *HyperlaneEVM) Fill(ctx context.Context, args *types.ParsedArgs) (OrderA
 		return OrderActionError, fmt.Errorf("failed to convert destination settler to EVM address: %w", err)
 	}
 
+	// A fill sent before a restart may still be pending or already included. It is waited for before
+	// taking the lock, so other fills and settles on the chain go on meanwhile.
+	earlierFill, err := h.txOutbox.Await(ctx, orders.TxKindFill, h.chainID, args.OrderID, h.lookupOutboxTx)
+	if err != nil {
+		return OrderActionError, err
+	}
+	if earlierFill != nil {
+		h.recordCost.record(args, *earlierFill)
+		return OrderActionSettle, nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	// Pre-check: skip if order is already filled or settled
 	status, err := h.GetOrderStatus(ctx, args)
 	if err != nil {
-		fmt.Printf("   ⚠️  Status check failed: %v\n", err)
+		fillerLog.Printf("   ⚠️  Status check failed: %v\n", err)
 		return OrderActionError, err
 	}
 
@@ -95,13 +141,20 @@ func (h *HyperlaneEVM) Fill(ctx context.Context, args *types.ParsedArgs) (OrderA
 	networkName := logutil.NetworkNameByChainID(h.chainID)
 	logutil.LogStatusCheck(networkName, 1, 1, status, "UNKNOWN")
 
-	if status == orderStatusFilled {
-		fmt.Printf("⏭️  Order already filled, proceeding to settlement\n")
-		return OrderActionSettle, nil
+	if status == orderStatusFilled || status == orderStatusSettled {
+		return h.alreadyFilled(ctx, args, orderID, destinationSettlerAddr, status)
 	}
-	if status == orderStatusSettled {
-		fmt.Printf("🎉  Order already settled, nothing to do\n")
-		return OrderActionSettle, nil
+
+	// Refuse the fill before any transaction if its origin_data doesn't describe the approved order
+	var fillerDataBytes []byte
+	if err := validateFillOriginData(args, orderID, fillerDataBytes); err != nil {
+		return OrderActionError, err
+	}
+
+	// Refuse the fill before any transaction if it would exceed the daily limits
+	notional, err := h.spendLimits.CheckOrderFill(ctx, h.chainID, args)
+	if err != nil {
+		return OrderActionError, err
 	}
 
 	// Handle max spent approvals if needed
@@ -115,10 +168,10 @@ func (h *HyperlaneEVM) Fill(ctx context.Context, args *types.ParsedArgs) (OrderA
 	destChainID := instruction.DestinationChainID.Uint64()
 	logutil.CrossChainOperation(fmt.Sprintf("Executing fill call to contract %s", destinationSettlerAddr.Hex()), originChainID, destChainID, args.OrderID)
 
-	// Set native token value if needed
+	// Native outputs are paid with the fill call itself (BasicSwap7683 requires msg.value == amount)
 	originalValue := h.signer.Value
-	if len(args.ResolvedOrder.MaxSpent) > 0 && args.ResolvedOrder.MaxSpent[0].Token == "" {
-		h.signer.Value = new(big.Int).Set(args.ResolvedOrder.MaxSpent[0].Amount)
+	if value := nativeFillValue(args, destChainID); value.Sign() > 0 {
+		h.signer.Value = value
 	}
 	defer func() { h.signer.Value = originalValue }()
 
@@ -127,28 +180,117 @@ func (h *HyperlaneEVM) Fill(ctx context.Context, args *types.ParsedArgs) (OrderA
 		return OrderActionError, fmt.Errorf("failed to bind contract at %s: %w", instruction.DestinationSettler, err)
 	}
 
-	var fillerDataBytes []byte
-	tx, err := contract.Fill(h.signer, orderID, instruction.OriginData, fillerDataBytes)
+	release, err := h.txLimits.Acquire(ctx, h.chainID)
+	if err != nil {
+		return OrderActionError, err
+	}
+	defer release()
+
+	// Sign the fill before sending it, so the spend limit is checked against its estimated fee
+	tx, err := contract.Fill(h.unsentSigner(), orderID, instruction.OriginData, fillerDataBytes)
+	if err != nil {
+		h.auditSend(args, orders.TxKindFill, destinationSettlerAddr, nil, err)
+		// Gas estimation reverts when a competing fill landed after the pre-check
+		if h.lostRace(ctx, args, orderID, destinationSettlerAddr) {
+			return OrderActionLostRace, nil
+		}
+		return OrderActionError, fmt.Errorf("fill transaction failed: %w", err)
+	}
+	if err := h.spendLimits.CheckGas(h.chainID, maxTxFee(tx)); err != nil {
+		return OrderActionError, err
+	}
+
+	// Without a record of the fill, a restart could not tell it was sent and would fill again
+	if err := h.txOutbox.Begin(orders.TxKindFill, h.chainID, args.OrderID); err != nil {
+		return OrderActionError, err
+	}
+	err = h.client.SendTransaction(ctx, tx)
+	sent := h.auditSend(args, orders.TxKindFill, destinationSettlerAddr, tx, err)
 	if err != nil {
+		h.txOutbox.Release(orders.TxKindFill, h.chainID, args.OrderID)
 		return OrderActionError, fmt.Errorf("fill transaction failed: %w", err)
 	}
 
-	logutil.CrossChainOperation(fmt.Sprintf("Fill transaction sent: %s", tx.Hash().Hex()), originChainID, destChainID, args.OrderID)
+	h.txOutbox.AddTx(orders.TxKindFill, h.chainID, args.OrderID, tx.Hash().Hex())
+	h.recordSent.record(args.OrderID, h.chainID, orders.TxKindFill, tx.Hash().Hex())
+	logutil.CrossChainOperation(fmt.Sprintf("Fill transaction sent: %s", logutil.TxRef(h.chainID, tx.Hash().Hex())), originChainID, destChainID, args.OrderID)
 
-	// Wait for confirmation
-	receipt, err := bind.WaitMined(ctx, h.client, tx)
+	// Wait for confirmation, replacing a stuck fill; without a receipt the outbox entry stays for
+	// the next attempt to look up
+	tx, receipt, err := h.waitMined(ctx, args, orders.TxKindFill, destinationSettlerAddr, tx, sent)
 	if err != nil {
 		return OrderActionError, fmt.Errorf("failed to wait for fill confirmation: %w", err)
 	}
+	h.txOutbox.Done(orders.TxKindFill, h.chainID, args.OrderID)
+
+	cost := evmTxCost(h.chainID, orders.TxKindFill, tx, receipt, nil)
+	h.recordCost.record(args, cost)
+	h.spendLimits.RecordTx(cost)
 
 	if receipt.Status == 1 {
+		h.spendLimits.RecordFill(h.chainID, notional)
 		logutil.CrossChainOperation(fmt.Sprintf("EVM Fill successful! Gas used: %d", receipt.GasUsed), originChainID, destChainID, args.OrderID)
 		return OrderActionSettle, nil // Need to settle this order
 	} else {
+		if h.lostRace(ctx, args, orderID, destinationSettlerAddr) {
+			return OrderActionLostRace, nil
+		}
 		return OrderActionError, fmt.Errorf("fill transaction failed with status: %d", receipt.Status)
 	}
 }
 
+// nativeFillValue sums the native amounts the order spends on the destination chain
+func nativeFillValue(args *types.ParsedArgs, destinationChainID uint64) *big.Int {
+	value := new(big.Int)
+	for _, maxSpent := range args.ResolvedOrder.MaxSpent {
+		if !types.IsNativeToken(maxSpent.Token) || maxSpent.Amount == nil {
+			continue
+		}
+		if maxSpent.ChainID != nil && maxSpent.ChainID.Uint64() != destinationChainID {
+			continue
+		}
+		value.Add(value, maxSpent.Amount)
+	}
+	return value
+}
+
+// alreadyFilled decides what to do with an order the router reports FILLED or SETTLED before we
+// sent a fill: continue with our own fill, or stop when another solver filled it
+func (h *HyperlaneEVM) alreadyFilled(ctx context.Context, args *types.ParsedArgs, orderID [32]byte, settler common.Address, status string) (OrderAction, error) {
+	if !h.fillOwnership.filledByUs(args.OrderID) {
+		h.logLostRace(ctx, args, orderID, settler)
+		return OrderActionLostRace, nil
+	}
+	if status == orderStatusSettled {
+		fillerLog.Printf("🎉  Order already settled, nothing to do\n")
+		return OrderActionComplete, nil
+	}
+	fillerLog.Printf("⏭️  Order already filled, proceeding to settlement\n")
+	return OrderActionSettle, nil
+}
+
+// lostRace reports whether our fill failed because the order was filled by someone else in the meantime
+func (h *HyperlaneEVM) lostRace(ctx context.Context, args *types.ParsedArgs, orderID [32]byte, settler common.Address) bool {
+	status, err := h.GetOrderStatus(ctx, args)
+	if err != nil || (status != orderStatusFilled && status != orderStatusSettled) {
+		return false
+	}
+	h.logLostRace(ctx, args, orderID, settler)
+	return true
+}
+
+// logLostRace logs the competing fill as recorded in the router's filledOrders mapping
+func (h *HyperlaneEVM) logLostRace(ctx context.Context, args *types.ParsedArgs, orderID [32]byte, settler common.Address) {
+	detail := ""
+	if router, err := contracts.NewHyperlane7683Caller(settler, h.client); err == nil {
+		if filled, err := router.FilledOrders(&bind.CallOpts{Context: ctx}, orderID); err == nil {
+			detail = fmt.Sprintf(" (filler data 0x%x)", filled.FillerData)
+		}
+	}
+	fillerLog.Printf("%s🏁 Order %s was filled by another solver first%s\n",
+		logutil.Prefix(logutil.NetworkNameByChainID(h.chainID)), args.OrderID, detail)
+}
+
 // Settle executes settlement on an EVM chain
 func (h *HyperlaneEVM) Settle(ctx context.Context, args *types.ParsedArgs) error {
 	h.mu.Lock()
@@ -171,6 +313,22 @@ func (h *HyperlaneEVM) Settle(ctx context.Context, args *types.ParsedArgs) error
 		return fmt.Errorf("failed to convert destination settler to EVM address: %w", err)
 	}
 
+	// An order already settled, e.g. by a settle sent before a restart, needs no transaction
+	if status, err := h.GetOrderStatus(ctx, args); err == nil && status == orderStatusSettled {
+		h.txOutbox.Done(orders.TxKindSettle, h.chainID, args.OrderID)
+		fillerLog.Printf("🎉 Order %s already settled, nothing to send\n", args.OrderID)
+		return nil
+	}
+	// A settle sent earlier may still be pending or already included
+	earlierSettle, err := h.txOutbox.Resume(ctx, orders.TxKindSettle, h.chainID, args.OrderID, h.lookupOutboxTx)
+	if err != nil {
+		return err
+	}
+	if earlierSettle != nil {
+		h.recordCost.record(args, *earlierSettle)
+		return nil
+	}
+
 	// Pre-settle check: ensure order is FILLED with retry logic
 	status, err := h.waitForOrderStatus(ctx, args, orderStatusFilled, maxRetryAttempts, 2*time.Second)
 	if err != nil {
@@ -193,17 +351,16 @@ func (h *HyperlaneEVM) Settle(ctx context.Context, args *types.ParsedArgs) error
 	}
 
 	// Check if origin is Starknet and we're on live networks (not forking)
-	starknetDomain := uint32(config.Networks["Starknet"].HyperlaneDomain)
-	if originDomain == starknetDomain {
+	if isStarknetDomain(originDomain) {
 		if !envutil.IsDevnet() {
 			// Live networks: Skip settlement until Starknet domain is registered
-			fmt.Printf("   ⚠️  Skipping EVM settlement for Starknet origin (domain %d) on live network\n", originDomain)
-			fmt.Printf("   ⏳ Starknet domain not yet registered on EVM contracts - waiting for Hyperlane team\n")
-			fmt.Printf("   📝 Order filled successfully, settlement will be available once domain is registered\n")
+			fillerLog.Printf("   ⚠️  Skipping EVM settlement for Starknet origin (domain %d) on live network\n", originDomain)
+			fillerLog.Printf("   ⏳ Starknet domain not yet registered on EVM contracts - waiting for Hyperlane team\n")
+			fillerLog.Printf("   📝 Order filled successfully, settlement will be available once domain is registered\n")
 			return nil // Skip settlement but don't treat as error
 		} else {
 			// Fork mode: Continue with settlement (domains are mocked/registered)
-			fmt.Printf("   🔧 Fork mode detected - proceeding with Starknet settlement (domain %d registered)\n", originDomain)
+			fillerLog.Printf("   🔧 Fork mode detected - proceeding with Starknet settlement (domain %d registered)\n", originDomain)
 		}
 	}
 
@@ -211,16 +368,12 @@ func (h *HyperlaneEVM) Settle(ctx context.Context, args *types.ParsedArgs) error
 	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
 	destChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
 	logutil.CrossChainOperation(fmt.Sprintf("Quoting gas payment for origin domain: %d", originDomain), originChainID, destChainID, args.OrderID)
-	gasPayment, err := contract.QuoteGasPayment(&bind.CallOpts{
-		Pending:     false,
-		From:        common.Address{},
-		BlockNumber: nil,
-		BlockHash:   common.Hash{},
-		Context:     ctx,
-	}, originDomain)
+	gasPayment, err := h.quoteSettlementGas(ctx, contract, destinationSettler, originDomain)
 	if err != nil {
 		return fmt.Errorf("quoteGasPayment failed on %s: %w", destinationSettler, err)
 	}
+	// A failed settle may be due to a stale quote; drop it so the retry quotes again
+	invalidateQuote := func() { h.gasQuotes.Invalidate(h.chainID, originDomain, destinationSettler.Hex()) }
 
 	// Prepare order IDs array (contract expects array)
 	orderIDs := make([][32]byte, 1)
@@ -233,20 +386,52 @@ func (h *HyperlaneEVM) Settle(ctx context.Context, args *types.ParsedArgs) error
 	h.signer.Value = new(big.Int).Set(gasPayment)
 	defer func() { h.signer.Value = originalValue }()
 
+	release, err := h.txLimits.Acquire(ctx, h.chainID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// Sign the settle before sending it, so the spend limit is checked against its estimated fee
+	tx, err := contract.Settle(h.unsentSigner(), orderIDs)
+	if err != nil {
+		h.auditSend(args, orders.TxKindSettle, destinationSettler, nil, err)
+		invalidateQuote()
+		return fmt.Errorf("settle tx failed on %s: %w", destinationSettler, err)
+	}
+	if err := h.spendLimits.CheckGas(h.chainID, maxTxFee(tx)); err != nil {
+		return err
+	}
 
-	tx, err := contract.Settle(h.signer, orderIDs)
+	// Without a record of the settle, a restart could not tell it was sent and would pay gas again
+	if err := h.txOutbox.Begin(orders.TxKindSettle, h.chainID, args.OrderID); err != nil {
+		return err
+	}
+	err = h.client.SendTransaction(ctx, tx)
+	sent := h.auditSend(args, orders.TxKindSettle, destinationSettler, tx, err)
 	if err != nil {
+		h.txOutbox.Release(orders.TxKindSettle, h.chainID, args.OrderID)
+		invalidateQuote()
 		return fmt.Errorf("settle tx failed on %s: %w", destinationSettler, err)
 	}
-	logutil.CrossChainOperation(fmt.Sprintf("Settle transaction sent: %s", tx.Hash().Hex()), originChainID, destChainID, args.OrderID)
+	h.txOutbox.AddTx(orders.TxKindSettle, h.chainID, args.OrderID, tx.Hash().Hex())
+	h.recordSent.record(args.OrderID, h.chainID, orders.TxKindSettle, tx.Hash().Hex())
+	logutil.CrossChainOperation(fmt.Sprintf("Settle transaction sent: %s", logutil.TxRef(h.chainID, tx.Hash().Hex())), originChainID, destChainID, args.OrderID)
 
-	// Wait for confirmation
-	receipt, err := bind.WaitMined(ctx, h.client, tx)
+	// Wait for confirmation, replacing a stuck settle; without a receipt the outbox entry stays for
+	// the next attempt to look up
+	tx, receipt, err := h.waitMined(ctx, args, orders.TxKindSettle, destinationSettler, tx, sent)
 	if err != nil {
+		invalidateQuote()
 		return fmt.Errorf("waiting settle failed on %s: %w", destinationSettler, err)
 	}
+	h.txOutbox.Done(orders.TxKindSettle, h.chainID, args.OrderID)
+	cost := evmTxCost(h.chainID, orders.TxKindSettle, tx, receipt, gasPayment)
+	h.recordCost.record(args, cost)
+	h.spendLimits.RecordTx(cost)
 
 	if receipt.Status == 0 {
+		invalidateQuote()
 		return fmt.Errorf("settle transaction failed on %s at block %d", destinationSettler, receipt.BlockNumber)
 	}
 
@@ -257,6 +442,25 @@ func (h *HyperlaneEVM) Settle(ctx context.Context, args *types.ParsedArgs) error
 	return nil
 }
 
+// lookupOutboxTx looks up a fill or settle transaction sent earlier
+func (h *HyperlaneEVM) lookupOutboxTx(ctx context.Context, txHash string) (outboxTx, error) {
+	receipt, err := h.client.TransactionReceipt(ctx, common.HexToHash(txHash))
+	if errors.Is(err, ethereum.NotFound) {
+		return outboxTx{status: outboxTxUnknown}, nil
+	}
+	if err != nil {
+		return outboxTx{}, err
+	}
+	tx := outboxTx{status: outboxTxReverted, feeUnit: "WEI"}
+	if receipt.EffectiveGasPrice != nil {
+		tx.fee = new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+	}
+	if receipt.Status == gethtypes.ReceiptStatusSuccessful {
+		tx.status = outboxTxSucceeded
+	}
+	return tx, nil
+}
+
 // GetOrderStatus returns the current status of an order
 func (h *HyperlaneEVM) GetOrderStatus(ctx context.Context, args *types.ParsedArgs) (string, error) {
 	if len(args.ResolvedOrder.FillInstructions) == 0 {
@@ -322,22 +526,56 @@ func (h *HyperlaneEVM) GetOrderStatus(ctx context.Context, args *types.ParsedArg
 	return status, nil
 }
 
+// quoteSettlementGas quotes the Hyperlane gas payment owed to settle back to originDomain through the settler
+func (h *HyperlaneEVM) quoteSettlementGas(ctx context.Context, contract *contracts.Hyperlane7683, destinationSettler common.Address, originDomain uint32) (*big.Int, error) {
+	return h.gasQuotes.Quote(h.chainID, originDomain, destinationSettler.Hex(), func() (*big.Int, error) {
+		return contract.QuoteGasPayment(&bind.CallOpts{
+			Pending:     false,
+			From:        common.Address{},
+			BlockNumber: nil,
+			BlockHash:   common.Hash{},
+			Context:     ctx,
+		}, originDomain)
+	})
+}
+
+// quoteSettlement quotes the gas payment of settling an order, paid in the chain's native token
+func (h *HyperlaneEVM) quoteSettlement(ctx context.Context, args *types.ParsedArgs) (settlementGas, error) {
+	if len(args.ResolvedOrder.FillInstructions) == 0 {
+		return settlementGas{}, fmt.Errorf("no fill instructions found")
+	}
+	destinationSettler, err := types.ToEVMAddress(args.ResolvedOrder.FillInstructions[0].DestinationSettler)
+	if err != nil {
+		return settlementGas{}, fmt.Errorf("failed to convert destination settler to EVM address: %w", err)
+	}
+	originDomain, err := h.getOriginDomain(args)
+	if err != nil {
+		return settlementGas{}, fmt.Errorf("failed to get origin domain: %w", err)
+	}
+	quote := settlementGas{chainID: h.chainID, token: pricefeed.NativeToken, amount: new(big.Int)}
+	// Settle skips Starknet origins on live networks, so they send no gas payment
+	if isStarknetDomain(originDomain) && !envutil.IsDevnet() {
+		return quote, nil
+	}
+
+	contract, err := contracts.NewHyperlane7683(destinationSettler, h.client)
+	if err != nil {
+		return settlementGas{}, fmt.Errorf("failed to bind contract at %s: %w", destinationSettler, err)
+	}
+	quote.amount, err = h.quoteSettlementGas(ctx, contract, destinationSettler, originDomain)
+	if err != nil {
+		return settlementGas{}, fmt.Errorf("quoteGasPayment failed on %s: %w", destinationSettler, err)
+	}
+	return quote, nil
+}
+
 // getOriginDomainFromArgs extracts the origin domain using the config system
 func (h *HyperlaneEVM) getOriginDomain(args *types.ParsedArgs) (uint32, error) {
 	if args.ResolvedOrder.OriginChainID == nil {
 		return 0, fmt.Errorf("no origin chain ID in resolved order")
 	}
 
-	chainID := args.ResolvedOrder.OriginChainID.Uint64()
-
-	// Use the config system (.env) to find the domain for this chain ID
-	for _, network := range config.Networks {
-		if network.ChainID == chainID {
-			return uint32(network.HyperlaneDomain), nil
-		}
-	}
-
-	return 0, fmt.Errorf("no domain found for chain ID %d in config (check your .env file)", chainID)
+	return originDomainForChainID(args.ResolvedOrder.OriginChainID.Uint64())
 }
 
 // setupApprovals handles all ERC20 approvals needed for the fill operation
@@ -356,14 +594,14 @@ func (h *HyperlaneEVM) setupApprovals(ctx context.Context, args *types.ParsedArg
 	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
 
 	for _, maxSpent := range args.ResolvedOrder.MaxSpent {
-		// Skip native ETH (empty string)
-		if maxSpent.Token == "" {
+		// Native ETH is sent as the fill's value, not approved
+		if types.IsNativeToken(maxSpent.Token) {
 			continue
 		}
 
 		// Only approve tokens that belong to this chain (destination chain)
 		if maxSpent.ChainID.Uint64() != destinationChainID {
-			fmt.Printf("   ⚠️  Skipping approval for token %s on chain %d (this handler is for chain %d)\n",
+			fillerLog.Printf("   ⚠️  Skipping approval for token %s on chain %d (this handler is for chain %d)\n",
 				maxSpent.Token, maxSpent.ChainID.Uint64(), destinationChainID)
 			continue
 		}
@@ -374,14 +612,14 @@ func (h *HyperlaneEVM) setupApprovals(ctx context.Context, args *types.ParsedArg
 			return fmt.Errorf("failed to convert token address for approval: %w", err)
 		}
 
-		if err := h.ensureTokenApproval(ctx, tokenAddr, destinationSettlerAddr, maxSpent.Amount); err != nil {
+		if err := h.ensureTokenApproval(ctx, args, tokenAddr, destinationSettlerAddr, maxSpent.Amount); err != nil {
 			return fmt.Errorf("approval failed for token %s: %w", maxSpent.Token, err)
 		}
 	}
 	logutil.CrossChainOperation("EVM token approvals set", originChainID, destinationChainID, args.OrderID)
 
 	// Add a small delay to ensure blockchain state is updated after approvals
-	time.Sleep(1 * time.Second)
+	time.Sleep(approvalSettleDelay)
 
 	return nil
 }
@@ -405,96 +643,47 @@ func (h *HyperlaneEVM) interpretStatusHash(_ context.Context, statusHash common.
 		return "SETTLED"
 	}
 
+	// Origin-side statuses, seen when querying the origin router
+	if statusHash == common.HexToHash("0x4f50454e45440000000000000000000000000000000000000000000000000000") {
+		return orderStatusOpened
+	}
+	if statusHash == common.HexToHash("0x524546554e444544000000000000000000000000000000000000000000000000") {
+		return orderStatusRefunded
+	}
+
 	return statusHash.Hex()
 }
 
 // ensureTokenApproval ensures the solver has approved an arbitrary ERC20 token for the Hyperlane contract
-func (h *HyperlaneEVM) ensureTokenApproval(ctx context.Context, tokenAddr, spender common.Address, amount *big.Int) error {
-	// Check current allowance
-	allowanceABI := `[{
-		"type": "function",
-		"name": "allowance",
-		"inputs": [
-			{"type": "address", "name": "owner"},
-			{"type": "address", "name": "spender"}
-		],
-		"outputs": [{"type": "uint256", "name": ""}],
-		"stateMutability": "view"
-	}]`
-	parsedABI, err := abi.JSON(strings.NewReader(allowanceABI))
-	if err != nil {
-		return fmt.Errorf("failed to parse allowance ABI: %w", err)
-	}
-
-	callData, err := parsedABI.Pack("allowance", h.signer.From, spender)
+func (h *HyperlaneEVM) ensureTokenApproval(ctx context.Context, args *types.ParsedArgs, tokenAddr, spender common.Address, amount *big.Int) error {
+	token, err := contracts.NewMockERC20(tokenAddr, h.client)
 	if err != nil {
-		return fmt.Errorf("failed to pack allowance call: %w", err)
+		return fmt.Errorf("failed to bind ERC20 at %s: %w", tokenAddr.Hex(), err)
 	}
 
-	result, err := h.client.CallContract(ctx, ethereum.CallMsg{
-		From:            common.Address{},
-		To:              &tokenAddr,
-		Gas:             0,
-		GasPrice:        nil,
-		GasFeeCap:       nil,
-		GasTipCap:       nil,
-		Value:           nil,
-		Data:            callData,
-		AccessList:      nil,
-		BlobGasFeeCap:   nil,
-		BlobHashes:      nil,
-		AuthorizationList: nil,
-	}, nil)
-	if err != nil {
-		return fmt.Errorf("allowance call failed: %w", err)
-	}
-
-	if len(result) == 0 {
+	// Check current allowance
+	currentAllowance, err := token.Allowance(&bind.CallOpts{Context: ctx}, h.signer.From, spender)
+	if errors.Is(err, bind.ErrNoCode) {
 		// Token doesn't exist on this chain (likely cross-chain order) - skip approval
-		fmt.Printf("   ⚠️  Token %s not found on this chain, skipping approval (cross-chain order)\n", tokenAddr.Hex())
+		fillerLog.Printf("   ⚠️  Token %s not found on this chain, skipping approval (cross-chain order)\n", tokenAddr.Hex())
 		chainID, err := h.client.ChainID(ctx)
 		if err == nil {
-			fmt.Printf("   ⚠️  This chain ID: %s\n", chainID.String())
+			fillerLog.Printf("   ⚠️  This chain ID: %s\n", chainID.String())
 		}
 		return nil
 	}
-
-	if len(result) < 32 {
-		return fmt.Errorf("invalid allowance result length: %d", len(result))
+	if err != nil {
+		return fmt.Errorf("allowance call failed: %w", err)
 	}
 
-	currentAllowance := new(big.Int).SetBytes(result)
-
 	// If allowance is sufficient, no approval needed
 	if currentAllowance.Cmp(amount) >= 0 {
 		return nil
 	}
 
-	// Approve exact amount needed
-	approveABI := `[{
-		"type": "function",
-		"name": "approve",
-		"inputs": [
-			{"type": "address", "name": "spender"},
-			{"type": "uint256", "name": "amount"}
-		],
-		"outputs": [{"type": "bool", "name": ""}],
-		"stateMutability": "nonpayable"
-	}]`
-	parsedApproveABI, err := abi.JSON(strings.NewReader(approveABI))
-	if err != nil {
-		return fmt.Errorf("failed to parse approve ABI: %w", err)
-	}
-
-	approveData, err := parsedApproveABI.Pack("approve", spender, amount)
-	if err != nil {
-		return fmt.Errorf("failed to pack approve call: %w", err)
-	}
-
-	// Get current nonce
-	nonce, err := h.client.PendingNonceAt(ctx, h.signer.From)
-	if err != nil {
-		return fmt.Errorf("failed to get nonce: %w", err)
+	// Tokens with ERC-2612 permits are approved with a signed permit instead
+	if permitted, err := h.approveWithPermit(ctx, args, tokenAddr, spender, amount); permitted || err != nil {
+		return err
 	}
 
 	// Get gas price
@@ -503,43 +692,79 @@ func (h *HyperlaneEVM) ensureTokenApproval(ctx context.Context, tokenAddr, spend
 		return fmt.Errorf("failed to get gas price: %w", err)
 	}
 
-	// Create approve transaction
-	approveTx := gethtypes.NewTransaction(
-		nonce,
-		tokenAddr,
-		big.NewInt(0),
-		approveGasLimit, // Gas limit for approve
-		gasPrice,
-		approveData,
-	)
-
-	// Sign transaction
-	signedTx, err := h.signer.Signer(h.signer.From, approveTx)
+	// The approve fee is bounded by its fixed gas limit
+	maxApproveFee := new(big.Int).Mul(gasPrice, big.NewInt(approveGasLimit))
+	if err := h.spendLimits.CheckGas(h.chainID, maxApproveFee); err != nil {
+		return err
+	}
+	release, err := h.txLimits.Acquire(ctx, h.chainID)
 	if err != nil {
-		return fmt.Errorf("failed to sign approve transaction: %w", err)
+		return err
 	}
+	defer release()
 
-	// Send transaction
-	if err := h.client.SendTransaction(ctx, signedTx); err != nil {
+	// Approve the amount needed, or unlimited per the approval policy; the binding fills in the nonce
+	opts := *h.signer
+	opts.Context = ctx
+	opts.GasLimit = approveGasLimit
+	opts.GasPrice = gasPrice
+	signedTx, err := token.Approve(&opts, spender, h.approvalPolicy.approveAmount(amount))
+	sent := h.auditSend(args, orders.TxKindApprove, tokenAddr, signedTx, err)
+	if err != nil {
 		return fmt.Errorf("failed to send approve transaction: %w", err)
 	}
 
-	fmt.Printf("   🚀 Approve transaction sent: %s\n", signedTx.Hash().Hex())
+	fillerLog.Printf("   🚀 Approve transaction sent: %s\n", signedTx.Hash().Hex())
 
 	// Wait for confirmation
 	receipt, err := bind.WaitMined(ctx, h.client, signedTx)
+	h.txAudit.record(evmReceiptAuditEntry(sent, receipt, err))
 	if err != nil {
 		return fmt.Errorf("failed to wait for approve confirmation: %w", err)
 	}
+	cost := evmTxCost(h.chainID, orders.TxKindApprove, signedTx, receipt, nil)
+	h.recordCost.record(args, cost)
+	h.spendLimits.RecordTx(cost)
 
 	if receipt.Status != 1 {
 		return fmt.Errorf("approve transaction failed with status: %d", receipt.Status)
 	}
 
-	fmt.Printf("   ✅ Approval confirmed! Gas used: %d\n", receipt.GasUsed)
+	fillerLog.Printf("   ✅ Approval confirmed! Gas used: %d\n", receipt.GasUsed)
 	return nil
 }
 
+// unsentSigner returns a copy of the signer that signs transactions without sending them
+func (h *HyperlaneEVM) unsentSigner() *bind.TransactOpts {
+	opts := *h.signer
+	opts.NoSend = true
+	return &opts
+}
+
+// maxTxFee is the most network fee a signed transaction can pay: its gas limit at its fee cap
+func maxTxFee(tx *gethtypes.Transaction) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasFeeCap())
+}
+
+// evmTxCost builds the cost record of a mined transaction; gasPayment is the value attached to settle
+func evmTxCost(chainID uint64, kind string, tx *gethtypes.Transaction, receipt *gethtypes.Receipt, gasPayment *big.Int) orders.TxCost {
+	// EffectiveGasPrice is missing on some older nodes; fall back to the tx gas price
+	gasPrice := receipt.EffectiveGasPrice
+	if gasPrice == nil {
+		gasPrice = tx.GasPrice()
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), gasPrice)
+
+	return orders.TxCost{
+		ChainID:    chainID,
+		Kind:       kind,
+		TxHash:     tx.Hash().Hex(),
+		Fee:        fee,
+		FeeUnit:    "WEI",
+		GasPayment: gasPayment,
+	}
+}
+
 // waitForOrderStatus waits for the order status to become the expected value with retry logic
 func (h *HyperlaneEVM) waitForOrderStatus(
 	ctx context.Context,
@@ -555,7 +780,7 @@ func (h *HyperlaneEVM) waitForOrderStatus(
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		status, err := h.GetOrderStatus(ctx, args)
 		if err != nil {
-			fmt.Printf("   ⚠️  Status check attempt %d failed: %v\n", attempt, err)
+			fillerLog.Printf("   ⚠️  Status check attempt %d failed: %v\n", attempt, err)
 		} else {
 			logutil.LogStatusCheck(networkName, attempt, maxRetries, status, expectedStatus)
 			if status == expectedStatus {
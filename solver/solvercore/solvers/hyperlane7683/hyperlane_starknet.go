@@ -1,17 +1,20 @@
 package hyperlane7683
 
 // Module: Starknet chain handler for Hyperlane7683
-// - Executes fill/settle/status calls against EVM Hyperlane7683 contracts
-// - Manages ERC20 approvals and gas/value handling for calls
+// - Executes fill/settle/status calls against Starknet Hyperlane7683 contracts
+// - Batches approvals, fill and settle into a single multicall transaction; a settle the settlement
+//   worker would defer for its gas payment is split off and sent by the worker instead
 //
 // Interface Contract:
-// - Fill(): Must acquire mutex, setup approvals, execute fill, return OrderAction
-// - Settle(): Must acquire mutex, quote gas, ensure ETH approval, execute settle
+// - Fill(): Must acquire mutex, quote gas, send approvals + fill + settle as one multicall (approvals + fill
+//   when the settle is split off), return OrderAction
+// - Settle(): Must acquire mutex, quote gas, send ETH approval + settle as one multicall (already-filled orders only)
 // - getOrderStatus(): Must check order status and return human-readable status
 // - All methods should use consistent logging patterns and error handling
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"sync"
@@ -19,8 +22,9 @@ import (
 
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
-	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/pricefeed"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 
 	"github.com/NethermindEth/juno/core/felt"
@@ -30,158 +34,285 @@ import (
 )
 
 const (
-	// Calldata constants
-	calldataBaseSize = 6
-	// EVM origin data size (bytes)
+	// EVM origin data size (bytes) of an OrderData without data payload; a payload adds its length
+	// rounded up to 32 bytes
 	evmOriginDataSize = 448
+	// ETH token address on Starknet, used to pay settlement gas
+	starknetETHAddress = "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7"
 )
 
 // HyperlaneStarknet contains all Starknet-specific logic for the Hyperlane 7683 protocol
 type HyperlaneStarknet struct {
 	// Client
-	provider *rpc.Provider
+	provider rpc.RPCProvider
+	// Contract calls on provider; invokes go through txQueue
+	contracts *starknetutil.Client
 	// Signer
 	account    *account.Account
 	solverAddr *felt.Felt
 	chainID    uint64
 
 	// hyperlaneAddr *felt.Felt
-	mu sync.Mutex // Serialize operations to prevent nonce conflicts
+	mu sync.Mutex // Serialize fill/settle flows (status checks + submission)
+
+	// Fee multiplier and caps applied to every invoke
+	feePolicy starknetFeePolicy
+	// Queue owning the local nonce; all invokes go through it
+	txQueue *starknetTxQueue
+	// Receives the cost of every transaction sent for an order
+	recordCost txCostRecorder
+	// Receives the hash of every fill and settle broadcast for an order
+	recordSent txSentRecorder
+	// Settlement gas quotes and the gas payment cap
+	gasQuotes *GasQuoteCache
+	// How long the settlement worker may defer a settle for its gas payment; 0 always bundles the
+	// settle with the fill
+	settleMaxDefer time.Duration
+	// Daily fee and fill notional limits
+	spendLimits *SpendLimiter
+	// Pending and per-minute transaction limits
+	txLimits *TxRateLimiter
+	// Records every transaction sent; nil disables the audit log
+	txAudit *TxAuditLog
+	// Fill and settle transactions in flight; nil sends them without looking for an earlier one
+	txOutbox *TxOutbox
+	// Whether approvals cover the amount needed or are unlimited (see approvals.go)
+	approvalPolicy ApprovalPolicy
 }
 
 // NewHyperlaneStarknet creates a new Starknet handler for Hyperlane operations
 func NewHyperlaneStarknet(rpcURL string, chainID uint64) *HyperlaneStarknet {
 	provider, err := rpc.NewProvider(rpcURL)
 	if err != nil {
-		fmt.Printf("failed to create Starknet provider: %v", err)
+		fillerLog.Printf("failed to create Starknet provider: %v", err)
 		return nil
 	}
+	return newHyperlaneStarknetWithProvider(provider, chainID)
+}
 
+// newHyperlaneStarknetWithProvider creates a Starknet handler on an existing provider
+func newHyperlaneStarknetWithProvider(provider rpc.RPCProvider, chainID uint64) *HyperlaneStarknet {
 	// Use conditional environment variables based on IS_DEVNET
 	pub := envutil.GetStarknetSolverPublicKey()
 	addrHex := envutil.GetStarknetSolverAddress()
 	priv := envutil.GetStarknetSolverPrivateKey()
 
 	if pub == "" || addrHex == "" || priv == "" {
-		fmt.Printf("missing STARKNET_SOLVER_* env vars for Starknet signer")
+		fillerLog.Printf("missing STARKNET_SOLVER_* env vars for Starknet signer")
 		return nil
 	}
 
 	addrF, err := utils.HexToFelt(addrHex)
 	if err != nil {
-		fmt.Printf("invalid STARKNET_SOLVER_ADDRESS: %v", err)
+		fillerLog.Printf("invalid STARKNET_SOLVER_ADDRESS: %v", err)
+		return nil
+	}
+
+	feePolicy, err := loadStarknetFeePolicy()
+	if err != nil {
+		fillerLog.Printf("invalid Starknet fee configuration: %v", err)
+		return nil
+	}
+
+	queueConfig, err := loadStarknetQueueConfig()
+	if err != nil {
+		fillerLog.Printf("invalid Starknet tx queue configuration: %v", err)
 		return nil
 	}
 
 	ks := account.NewMemKeystore()
 	privBI, ok := new(big.Int).SetString(priv, 0)
 	if !ok {
-		fmt.Printf("failed to parse STARKNET_SOLVER_PRIVATE_KEY")
+		fillerLog.Printf("failed to parse STARKNET_SOLVER_PRIVATE_KEY")
 		return nil
 	}
 
 	ks.Put(pub, privBI)
 	acct, err := account.NewAccount(provider, addrF, pub, ks, account.CairoV2)
 	if err != nil {
-		fmt.Printf("failed to create Starknet account: %v", err)
+		fillerLog.Printf("failed to create Starknet account: %v", err)
 		return nil
 	}
 
-	return &HyperlaneStarknet{
-		account:    acct,
-		provider:   provider,
-		solverAddr: addrF,
-		chainID:    chainID,
-		mu:         sync.Mutex{},
+	h := &HyperlaneStarknet{
+		account:     acct,
+		provider:    provider,
+		contracts:   starknetutil.NewClient(provider, acct),
+		solverAddr:  addrF,
+		chainID:     chainID,
+		mu:          sync.Mutex{},
+		feePolicy:   feePolicy,
+		gasQuotes:   DefaultGasQuoteCache(),
+		spendLimits: DefaultSpendLimiter(),
+		txLimits:    DefaultTxRateLimiter(),
+
+		settleMaxDefer: settleMaxDeferFromEnv(),
 	}
+	h.txQueue = newStarknetTxQueue(h, queueConfig)
+	return h
 }
 
 // Fill executes a fill operation on Starknet
+// Approvals, fill and settle are sent as a single multicall so only one confirmation is awaited
 func (h *HyperlaneStarknet) Fill(ctx context.Context, args *types.ParsedArgs) (OrderAction, error) {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
 	if len(args.ResolvedOrder.FillInstructions) == 0 {
 		return OrderActionError, fmt.Errorf("no fill instructions found")
 	}
 
 	instruction := args.ResolvedOrder.FillInstructions[0]
 
-	// Use the order ID from the event
-	orderID := args.OrderID
-
 	// Convert destination settler string to Starknet address (felt) for contract operations
 	destinationSettlerAddr, err := types.ToStarknetAddress(instruction.DestinationSettler)
 	if err != nil {
 		return OrderActionError, fmt.Errorf("failed to convert destination settler to felt: %w", err)
 	}
 
+	// A fill sent before a restart may still be pending or already included: a fill+settle, or a
+	// fill alone when the settle was split off (see splitSettle). It is waited for
+	// before taking the lock, so other fills and settles on the chain go on meanwhile.
+	for _, earlier := range []struct {
+		kind   string
+		action OrderAction
+	}{{orders.TxKindFillSettle, OrderActionComplete}, {orders.TxKindFill, OrderActionSettle}} {
+		earlierFill, err := h.txOutbox.Await(ctx, earlier.kind, h.chainID, args.OrderID, h.lookupOutboxTx)
+		if err != nil {
+			return OrderActionError, err
+		}
+		if earlierFill != nil {
+			h.recordCost.record(args, *earlierFill)
+			return earlier.action, nil
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	// Pre-check: skip if order is already filled or settled
 	status, err := h.GetOrderStatus(ctx, args)
 	if err != nil {
-		fmt.Printf("   ⚠️  Status check failed: %v\n", err)
+		fillerLog.Printf("   ⚠️  Status check failed: %v\n", err)
 		return OrderActionError, err
 	}
 	networkName := logutil.NetworkNameByChainID(h.chainID)
 	logutil.LogStatusCheck(networkName, 1, 1, status, orderStatusUnknown)
 	if status == orderStatusFilled {
-		fmt.Printf("⏭️  Order already filled, proceeding to settlement\n")
+		fillerLog.Printf("⏭️  Order already filled, proceeding to settlement\n")
 		return OrderActionSettle, nil
 	}
 	if status == orderStatusSettled {
-		fmt.Printf("🎉  Order already settled, nothing to do\n")
-		return OrderActionSettle, nil
+		fillerLog.Printf("🎉  Order already settled, nothing to do\n")
+		return OrderActionComplete, nil
 	}
 
-	// Handle max spent approvals if needed
-	if err := h.setupApprovals(ctx, args, destinationSettlerAddr); err != nil {
-		return OrderActionError, fmt.Errorf("failed to setup approvals: %w", err)
-	}
+	// Get chain IDs for cross-chain logging
+	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
+	destChainID := instruction.DestinationChainID.Uint64()
 
-	// Prepare calldata; has a capacity of 6 + len(words)
-	// - Order ID: 2 felts (u256)
-	// - Origin data: 1 felt for size (usize), 1 felt for length (usize), 1 felt for each element
-	// - Filler data: 1 felt for size (usize), 1 felt for length (usize), 0 elements
-	originData := instruction.OriginData
-	words := starknetutil.BytesToU128Felts(originData)
+	// Refuse the fill before building anything if it would exceed the daily limits
+	notional, err := h.spendLimits.CheckOrderFill(ctx, h.chainID, args)
+	if err != nil {
+		return OrderActionError, err
+	}
 
-	// Convert bytes32 representation of orderID to u256 (2 felts)
-	orderIDLow, orderIDHigh, err := starknetutil.ConvertSolidityOrderIDForStarknet(orderID)
+	fillCall, err := buildFillCall(args.OrderID, instruction.OriginData, destinationSettlerAddr)
 	if err != nil {
-		return OrderActionError, fmt.Errorf("failed to convert solidity order ID for starknet: %w", err)
+		return OrderActionError, err
 	}
 
-	calldata := make([]*felt.Felt, 0, calldataBaseSize+len(words))
-	calldata = append(calldata, 
-		orderIDLow, orderIDHigh,
-		utils.Uint64ToFelt(uint64(len(originData))),
-		utils.Uint64ToFelt(uint64(len(words))),
-	)
-	calldata = append(calldata, words...)
-	calldata = append(calldata, utils.Uint64ToFelt(0), utils.Uint64ToFelt(0)) // empty (size=0, len=0)
+	fillOnly := false
+	gasPayment, err := h.quoteSettlementGas(ctx, args, destinationSettlerAddr)
+	if reason := h.splitSettle(ctx, args, gasPayment, err); reason != nil {
+		fillerLog.Printf("⏸️  Filling order %s without its settle, left to the settlement worker: %v\n", args.OrderID, reason)
+		fillOnly, gasPayment, err = true, new(big.Int), nil
+	}
+	if err != nil {
+		return OrderActionError, err
+	}
+	var settleCall rpc.InvokeFunctionCall
+	if !fillOnly {
+		settleCall, err = buildSettleCall(args.OrderID, gasPayment, destinationSettlerAddr)
+		if err != nil {
+			return OrderActionError, err
+		}
+	}
+	fillKind := orders.TxKindFillSettle
+	if fillOnly {
+		fillKind = orders.TxKindFill
+	}
 
-	// Execute the fill transaction
-	invoke := rpc.InvokeFunctionCall{ContractAddress: destinationSettlerAddr, FunctionName: "fill", CallData: calldata}
-	tx, err := h.account.BuildAndSendInvokeTxn(ctx, []rpc.InvokeFunctionCall{invoke}, nil)
+	// The settle gas payment is pulled in ETH, so it is approved alongside the MaxSpent tokens
+	required, err := collectApprovalAmounts(args, destChainID, gasPayment)
 	if err != nil {
-		return OrderActionError, fmt.Errorf("starknet fill send failed: %w", err)
+		return OrderActionError, fmt.Errorf("failed to collect approvals: %w", err)
+	}
+	approvals, err := h.buildApprovalCalls(ctx, required, destinationSettlerAddr)
+	if err != nil {
+		return OrderActionError, fmt.Errorf("failed to setup approvals: %w", err)
 	}
-	// Get chain IDs for cross-chain logging
-	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
-	destChainID := instruction.DestinationChainID.Uint64()
-	logutil.CrossChainOperation(fmt.Sprintf("Fill transaction sent: %s", tx.Hash.String()), originChainID, destChainID, orderID)
 
-	// Wait for confirmation
-	_, waitErr := h.account.WaitForTransactionReceipt(ctx, tx.Hash, 2*time.Second)
-	if waitErr != nil {
-		return OrderActionError, fmt.Errorf("starknet fill wait failed: %w", waitErr)
+	// Without a record of the fill, a restart could not tell it was sent and would fill again;
+	// the tx queue adds every broadcast version of it to the entry
+	if err := h.txOutbox.Begin(fillKind, h.chainID, args.OrderID); err != nil {
+		return OrderActionError, err
 	}
-	logutil.CrossChainOperation("Fill transaction confirmed", originChainID, destChainID, orderID)
 
-	return OrderActionSettle, nil
+	// Approvals are only included when the current allowance is insufficient
+	calls := make([]rpc.InvokeFunctionCall, 0, len(approvals)+2)
+	calls = append(calls, approvals...)
+	if fillOnly {
+		calls = append(calls, fillCall)
+		logutil.CrossChainOperation(fmt.Sprintf("Sending fill (%d approvals)", len(approvals)), originChainID, destChainID, args.OrderID)
+		txHash, fee, err := h.executeCalls(ctx, args, orders.TxKindFill, calls, gasPayment, args)
+		if err != nil {
+			h.txOutbox.Release(fillKind, h.chainID, args.OrderID)
+			return OrderActionError, fmt.Errorf("starknet fill failed: %w", err)
+		}
+		h.txOutbox.Done(fillKind, h.chainID, args.OrderID)
+		h.recordCost.record(args, starknetTxCost(h.chainID, orders.TxKindFill, txHash, fee, nil))
+		h.spendLimits.RecordFill(h.chainID, notional)
+		logutil.CrossChainOperation(fmt.Sprintf("Fill confirmed: %s", txHash), originChainID, destChainID, args.OrderID)
+		return OrderActionSettle, nil
+	}
+	calls = append(calls, fillCall, settleCall)
+
+	logutil.CrossChainOperation(fmt.Sprintf("Sending fill+settle multicall (%d approvals, gas payment %s wei)", len(approvals), gasPayment.String()),
+		originChainID, destChainID, args.OrderID)
+	txHash, fee, err := h.executeCalls(ctx, args, orders.TxKindFillSettle, calls, gasPayment, args)
+	if err != nil {
+		h.txOutbox.Release(fillKind, h.chainID, args.OrderID)
+		h.invalidateSettlementGas(args, destinationSettlerAddr)
+		return OrderActionError, fmt.Errorf("starknet fill+settle multicall failed: %w", err)
+	}
+	h.txOutbox.Done(fillKind, h.chainID, args.OrderID)
+	h.recordCost.record(args, starknetTxCost(h.chainID, orders.TxKindFillSettle, txHash, fee, gasPayment))
+	h.spendLimits.RecordFill(h.chainID, notional)
+	logutil.CrossChainOperation(fmt.Sprintf("Fill+settle multicall confirmed: %s", txHash), originChainID, destChainID, args.OrderID)
+
+	return OrderActionComplete, nil
+}
+
+// splitSettle returns why the settle of an order should be left out of its fill's multicall, so the
+// settlement worker can defer it: its gas payment is above the cap, or would use up the order's margin.
+// It returns nil to bundle the settle, which is always the case with deferral disabled.
+func (h *HyperlaneStarknet) splitSettle(ctx context.Context, args *types.ParsedArgs, gasPayment *big.Int, quoteErr error) error {
+	if h.settleMaxDefer <= 0 {
+		return nil
+	}
+	if errors.Is(quoteErr, ErrGasPaymentAboveCap) {
+		return quoteErr
+	}
+	if quoteErr != nil {
+		return nil
+	}
+	quotes := []settlementGas{{chainID: h.chainID, token: starknetETHAddress, amount: gasPayment}}
+	if err := checkSettlementMargin(ctx, pricefeed.Default(), args, quotes); errors.Is(err, ErrSettlementDeferred) {
+		return err
+	}
+	return nil
 }
 
 // Settle executes settlement on Starknet
+// Only used when the order was filled outside of Fill's multicall (e.g. a previous run crashed mid-way)
 func (h *HyperlaneStarknet) Settle(ctx context.Context, args *types.ParsedArgs) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -192,15 +323,28 @@ func (h *HyperlaneStarknet) Settle(ctx context.Context, args *types.ParsedArgs)
 
 	instruction := args.ResolvedOrder.FillInstructions[0]
 
-	// Use the order ID from the event
-	orderID := args.OrderID
-
 	// Convert destination settler string to Starknet address (felt) for contract operations
 	destinationSettler, err := types.ToStarknetAddress(instruction.DestinationSettler)
 	if err != nil {
 		return fmt.Errorf("failed to convert destination settler to felt: %w", err)
 	}
 
+	// An order already settled, e.g. by a settle sent before a restart, needs no transaction
+	if status, err := h.GetOrderStatus(ctx, args); err == nil && status == orderStatusSettled {
+		h.txOutbox.Done(orders.TxKindSettle, h.chainID, args.OrderID)
+		fillerLog.Printf("🎉 Order %s already settled, nothing to send\n", args.OrderID)
+		return nil
+	}
+	// A settle sent earlier may still be pending or already included
+	earlierSettle, err := h.txOutbox.Resume(ctx, orders.TxKindSettle, h.chainID, args.OrderID, h.lookupOutboxTx)
+	if err != nil {
+		return err
+	}
+	if earlierSettle != nil {
+		h.recordCost.record(args, *earlierSettle)
+		return nil
+	}
+
 	// Pre-settle check: ensure order is FILLED with retry logic
 	status, err := h.waitForOrderStatus(ctx, args, orderStatusFilled, 5, 2*time.Second)
 	if err != nil {
@@ -210,60 +354,174 @@ func (h *HyperlaneStarknet) Settle(ctx context.Context, args *types.ParsedArgs)
 		return fmt.Errorf("order status must be filled in order to settle, got: %s", status)
 	}
 
-	// Get gas payment (protocol fee) that must be sent with settlement
+	// Get chain IDs for cross-chain logging
+	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
+	destChainID := instruction.DestinationChainID.Uint64()
+
+	gasPayment, err := h.quoteSettlementGas(ctx, args, destinationSettler)
+	if err != nil {
+		return err
+	}
+	settleCall, err := buildSettleCall(args.OrderID, gasPayment, destinationSettler)
+	if err != nil {
+		return err
+	}
+
+	// Approve ETH for the quoted gas amount in the same transaction as the settle
+	ethAddress, err := utils.HexToFelt(starknetETHAddress)
+	if err != nil {
+		return fmt.Errorf("failed to convert ETH address to felt: %w", err)
+	}
+	approvals, err := h.buildApprovalCalls(ctx, []tokenAmount{{token: ethAddress, amount: gasPayment}}, destinationSettler)
+	if err != nil {
+		return fmt.Errorf("ETH approval failed for settlement gas: %w", err)
+	}
+
+	// Without a record of the settle, a restart could not tell it was sent and would pay gas again;
+	// the tx queue adds every broadcast version of it to the entry
+	if err := h.txOutbox.Begin(orders.TxKindSettle, h.chainID, args.OrderID); err != nil {
+		return err
+	}
+	calls := append(approvals, settleCall)
+	txHash, fee, err := h.executeCalls(ctx, args, orders.TxKindSettle, calls, gasPayment, nil)
+	if err != nil {
+		h.txOutbox.Release(orders.TxKindSettle, h.chainID, args.OrderID)
+		h.invalidateSettlementGas(args, destinationSettler)
+		return fmt.Errorf("starknet settle failed: %w", err)
+	}
+	h.txOutbox.Done(orders.TxKindSettle, h.chainID, args.OrderID)
+	h.recordCost.record(args, starknetTxCost(h.chainID, orders.TxKindSettle, txHash, fee, gasPayment))
+
+	logutil.CrossChainOperation(fmt.Sprintf("Starknet settle transaction confirmed: %s", txHash), originChainID, destChainID, args.OrderID)
+	return nil
+}
+
+// lookupOutboxTx looks up a fill or settle transaction sent earlier
+func (h *HyperlaneStarknet) lookupOutboxTx(ctx context.Context, txHash string) (outboxTx, error) {
+	hash, err := utils.HexToFelt(txHash)
+	if err != nil {
+		return outboxTx{}, fmt.Errorf("invalid tx hash: %w", err)
+	}
+	receipt, err := h.provider.TransactionReceipt(ctx, hash)
+	if isStarknetRPCError(err, rpc.ErrHashNotFound) {
+		return outboxTx{status: outboxTxUnknown}, nil
+	}
+	if err != nil {
+		return outboxTx{}, err
+	}
+	cost := starknetTxCost(h.chainID, "", txHash, receipt.ActualFee, nil)
+	tx := outboxTx{status: outboxTxSucceeded, fee: cost.Fee, feeUnit: cost.FeeUnit}
+	if receipt.ExecutionStatus == rpc.TxnExecutionStatusREVERTED {
+		tx.status = outboxTxReverted
+	}
+	return tx, nil
+}
+
+// executeCalls sends the calls as one invoke transaction of the given kind through the tx queue and waits for inclusion.
+// The fee policy, spend limits and tx rate limits are enforced before sending; filled is the order whose margin the fees
+// are checked against, nil when the transaction fills nothing.
+func (h *HyperlaneStarknet) executeCalls(ctx context.Context, args *types.ParsedArgs, kind string, calls []rpc.InvokeFunctionCall, gasPayment *big.Int, filled *types.ParsedArgs) (string, rpc.FeePayment, error) {
+	release, err := h.txLimits.Acquire(ctx, h.chainID)
+	if err != nil {
+		return "", rpc.FeePayment{}, err
+	}
+	defer release()
+
+	txHash, fee, err := h.txQueue.Submit(ctx, starknetTxTag{kind: kind, orderID: auditOrderID(args)}, calls, gasPayment, filled)
+	// A reverted invoke is still charged, so its fee counts towards the daily limit
+	if fee.Amount != nil {
+		h.spendLimits.RecordTx(starknetTxCost(h.chainID, "", "", fee, gasPayment))
+	}
+	if err != nil {
+		return "", fee, err
+	}
+	return txHash.String(), fee, nil
+}
+
+// starknetTxCost builds the cost record of an included invoke
+func starknetTxCost(chainID uint64, kind, txHash string, fee rpc.FeePayment, gasPayment *big.Int) orders.TxCost {
+	cost := orders.TxCost{
+		ChainID:    chainID,
+		Kind:       kind,
+		TxHash:     txHash,
+		Fee:        new(big.Int),
+		FeeUnit:    string(fee.Unit),
+		GasPayment: gasPayment,
+	}
+	if fee.Amount != nil {
+		fee.Amount.BigInt(cost.Fee)
+	}
+	return cost
+}
+
+// quoteSettlementGas quotes the Hyperlane gas payment owed to settle the order back to its origin
+func (h *HyperlaneStarknet) quoteSettlementGas(ctx context.Context, args *types.ParsedArgs, destinationSettler *felt.Felt) (*big.Int, error) {
 	originDomain, err := h.getOriginDomain(args)
 	if err != nil {
-		return fmt.Errorf("failed to get origin domain: %w", err)
+		return nil, fmt.Errorf("failed to get origin domain: %w", err)
 	}
 
-	// Get chain IDs for cross-chain logging
 	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
 	destChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
 	logutil.CrossChainOperation(fmt.Sprintf("Quoting gas payment for origin domain: %d", originDomain), originChainID, destChainID, args.OrderID)
-	gasPayment, err := h.quoteGasPayment(ctx, originDomain, destinationSettler)
+
+	gasPayment, err := h.gasQuotes.Quote(h.chainID, originDomain, destinationSettler.String(), func() (*big.Int, error) {
+		return h.quoteGasPayment(ctx, originDomain, destinationSettler)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to quote gas payment: %w", err)
+		return nil, fmt.Errorf("failed to quote gas payment: %w", err)
 	}
+	return gasPayment, nil
+}
 
-	// Approve ETH for the quoted gas amount
-	if err := h.ensureETHApproval(ctx, gasPayment, destinationSettler); err != nil {
-		return fmt.Errorf("ETH approval failed for settlement gas: %w", err)
+// quoteSettlement quotes the gas payment of settling an already filled order, paid in ETH
+func (h *HyperlaneStarknet) quoteSettlement(ctx context.Context, args *types.ParsedArgs) (settlementGas, error) {
+	if len(args.ResolvedOrder.FillInstructions) == 0 {
+		return settlementGas{}, fmt.Errorf("no fill instructions found")
 	}
-	logutil.CrossChainOperation(fmt.Sprintf("ETH approved for settlement gas payment: %s wei", gasPayment.String()), originChainID, destChainID, args.OrderID)
-
-	// Prepare calldata
-	orderIDLow, orderIDHigh, err := starknetutil.ConvertSolidityOrderIDForStarknet(orderID)
+	destinationSettler, err := types.ToStarknetAddress(args.ResolvedOrder.FillInstructions[0].DestinationSettler)
 	if err != nil {
-		return fmt.Errorf("failed to convert solidity order ID for starknet: %w", err)
+		return settlementGas{}, fmt.Errorf("failed to convert destination settler to felt: %w", err)
 	}
-	gasLow, gasHigh := starknetutil.ConvertBigIntToU256Felts(gasPayment)
-	calldata := []*felt.Felt{
-		utils.Uint64ToFelt(1),   // order ID array length
-		orderIDLow, orderIDHigh, // order ID (u256) low and high
-		gasLow, gasHigh, // gas amount (u256) low and high
+	gasPayment, err := h.quoteSettlementGas(ctx, args, destinationSettler)
+	if err != nil {
+		return settlementGas{}, err
 	}
+	return settlementGas{chainID: h.chainID, token: starknetETHAddress, amount: gasPayment}, nil
+}
 
-	// Execute the settle transaction
-	invoke := rpc.InvokeFunctionCall{
-		ContractAddress: destinationSettler,
-		FunctionName:    "settle",
-		CallData:        calldata,
+// invalidateSettlementGas drops the cached quote after a failed settle so the retry quotes again
+func (h *HyperlaneStarknet) invalidateSettlementGas(args *types.ParsedArgs, destinationSettler *felt.Felt) {
+	if originDomain, err := h.getOriginDomain(args); err == nil {
+		h.gasQuotes.Invalidate(h.chainID, originDomain, destinationSettler.String())
 	}
+}
 
-	// Wait for confirmation
-	tx, err := h.account.BuildAndSendInvokeTxn(ctx, []rpc.InvokeFunctionCall{invoke}, nil)
+// buildFillCall builds the fill(order_id, origin_data, filler_data) call with empty filler data
+func buildFillCall(orderID string, originData []byte, destinationSettler *felt.Felt) (rpc.InvokeFunctionCall, error) {
+	orderIDValue, err := starknetOrderID(orderID)
 	if err != nil {
-		return fmt.Errorf("starknet settle send failed: %w", err)
+		return rpc.InvokeFunctionCall{}, err
 	}
+	return hyperlane7683StarknetABI.InvokeCall(destinationSettler, "fill", orderIDValue, originData, []byte{})
+}
 
-	logutil.CrossChainOperation(fmt.Sprintf("Starknet settle tx sent: %s", tx.Hash.String()), originChainID, destChainID, args.OrderID)
-	_, waitErr := h.account.WaitForTransactionReceipt(ctx, tx.Hash, 2*time.Second)
-	if waitErr != nil {
-		return fmt.Errorf("starknet settle wait failed: %w", waitErr)
+// buildSettleCall builds the settle(order_ids, value) call for a single order
+func buildSettleCall(orderID string, gasPayment *big.Int, destinationSettler *felt.Felt) (rpc.InvokeFunctionCall, error) {
+	orderIDValue, err := starknetOrderID(orderID)
+	if err != nil {
+		return rpc.InvokeFunctionCall{}, err
 	}
+	return hyperlane7683StarknetABI.InvokeCall(destinationSettler, "settle", []*big.Int{orderIDValue}, gasPayment)
+}
 
-	logutil.CrossChainOperation("Starknet settle transaction confirmed", originChainID, destChainID, args.OrderID)
-	return nil
+// starknetOrderID converts a Solidity bytes32 order ID into the u256 order ID of the Cairo contract
+func starknetOrderID(orderID string) (*big.Int, error) {
+	low, high, err := starknetutil.ConvertSolidityOrderIDForStarknet(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert solidity order ID for starknet: %w", err)
+	}
+	return starknetutil.U256FromFelts(low, high), nil
 }
 
 // GetOrderStatus returns the current status of an order
@@ -286,12 +544,7 @@ func (h *HyperlaneStarknet) GetOrderStatus(ctx context.Context, args *types.Pars
 		return orderStatusUnknown, fmt.Errorf("failed to convert solidity order id for cairo: %w", err)
 	}
 
-	call := rpc.FunctionCall{
-		ContractAddress:      destinationSettlerAddr,
-		EntryPointSelector:   utils.GetSelectorFromNameFelt("order_status"),
-		Calldata:             []*felt.Felt{orderIDLow, orderIDHigh},
-	}
-	resp, err := h.provider.Call(ctx, call, rpc.WithBlockTag("latest"))
+	resp, err := h.contracts.Call(ctx, destinationSettlerAddr, "order_status", orderIDLow, orderIDHigh)
 	if err != nil || len(resp) == 0 {
 		return orderStatusUnknown, err
 	}
@@ -306,58 +559,76 @@ func (h *HyperlaneStarknet) getOriginDomain(args *types.ParsedArgs) (uint32, err
 		return 0, fmt.Errorf("no origin chain ID in resolved order")
 	}
 
-	chainID := args.ResolvedOrder.OriginChainID.Uint64()
-
-	// Use the config system (.env) to find the domain for this chain ID
-	for _, network := range config.Networks {
-		if network.ChainID == chainID {
-			return uint32(network.HyperlaneDomain), nil
-		}
-	}
-
-	return 0, fmt.Errorf("no domain found for chain ID %d in config (check your .env file)", chainID)
+	return originDomainForChainID(args.ResolvedOrder.OriginChainID.Uint64())
 }
 
-// setupApprovals ensures each MaxSpent token allowances are set
-func (h *HyperlaneStarknet) setupApprovals(ctx context.Context, args *types.ParsedArgs, destinationSettler *felt.Felt) error {
-	if len(args.ResolvedOrder.MaxSpent) == 0 {
-		return nil
-	}
+// tokenAmount is the total allowance a token must grant the settler
+type tokenAmount struct {
+	token  *felt.Felt
+	amount *big.Int
+}
 
-	// Get destination chain ID from fill instruction
-	if len(args.ResolvedOrder.FillInstructions) == 0 {
-		return fmt.Errorf("no fill instructions found")
+// collectApprovalAmounts sums the MaxSpent amounts per token for this chain plus the ETH
+// settle gas payment, so a token used for both gets a single approval covering the total
+func collectApprovalAmounts(args *types.ParsedArgs, destinationChainID uint64, gasPayment *big.Int) ([]tokenAmount, error) {
+	var required []tokenAmount
+	add := func(token *felt.Felt, amount *big.Int) {
+		for i := range required {
+			if required[i].token.Equal(token) {
+				required[i].amount = new(big.Int).Add(required[i].amount, amount)
+				return
+			}
+		}
+		required = append(required, tokenAmount{token: token, amount: new(big.Int).Set(amount)})
 	}
-	destinationChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
-
-	// Get origin chain ID for cross-chain logging
-	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
 
 	for _, maxSpent := range args.ResolvedOrder.MaxSpent {
-		// Skip native ETH (empty string)
-		if maxSpent.Token == "" {
+		// Skip the zero address; Starknet's ETH and STRK are regular ERC20 contracts
+		if types.IsNativeToken(maxSpent.Token) {
 			continue
 		}
 
 		// Only approve tokens that belong to this chain (destination chain)
 		if maxSpent.ChainID.Uint64() != destinationChainID {
-			fmt.Printf("   ⚠️  Skipping approval for token %s on chain %d (this handler is for chain %d)\n",
+			fillerLog.Printf("   ⚠️  Skipping approval for token %s on chain %d (this handler is for chain %d)\n",
 				maxSpent.Token, maxSpent.ChainID.Uint64(), destinationChainID)
 			continue
 		}
 
-		// Convert token address to Starknet format
-		if err := h.ensureTokenApproval(ctx, maxSpent.Token, maxSpent.Amount, destinationSettler); err != nil {
-			return fmt.Errorf("starknet approval failed for token %s: %w", maxSpent.Token, err)
+		token, err := utils.HexToFelt(maxSpent.Token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Starknet token address %s: %w", maxSpent.Token, err)
 		}
+		add(token, maxSpent.Amount)
 	}
 
-	logutil.CrossChainOperation("Set token approvals", originChainID, destinationChainID, args.OrderID)
+	if gasPayment != nil && gasPayment.Sign() > 0 {
+		ethAddress, err := utils.HexToFelt(starknetETHAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert ETH address to felt: %w", err)
+		}
+		add(ethAddress, gasPayment)
+	}
 
-	// Add a small delay to ensure blockchain state is updated after approvals
-	time.Sleep(1 * time.Second)
+	return required, nil
+}
 
-	return nil
+// buildApprovalCalls returns approve calls for each token whose current allowance is below the required amount
+func (h *HyperlaneStarknet) buildApprovalCalls(ctx context.Context, required []tokenAmount, spender *felt.Felt) ([]rpc.InvokeFunctionCall, error) {
+	var calls []rpc.InvokeFunctionCall
+	for _, r := range required {
+		current, err := h.allowance(ctx, r.token, spender)
+		if err != nil {
+			return nil, fmt.Errorf("token %s: %w", r.token.String(), err)
+		}
+		if current.Cmp(r.amount) >= 0 {
+			continue
+		}
+
+		// Approve the amount needed, or unlimited per the approval policy
+		calls = append(calls, starknetutil.ApproveCall(r.token, spender, h.approvalPolicy.approveAmount(r.amount)))
+	}
+	return calls, nil
 }
 
 // interpretStarknetStatus returns the string representation of the order status
@@ -369,6 +640,10 @@ func (h *HyperlaneStarknet) interpretStarknetStatus(status string) string {
 		return orderStatusFilled
 	case "0x534554544c4544":
 		return orderStatusSettled
+	case "0x4f50454e4544":
+		return orderStatusOpened
+	case "0x524546554e444544":
+		return orderStatusRefunded
 	default:
 		return status
 	}
@@ -379,151 +654,21 @@ func (h *HyperlaneStarknet) quoteGasPayment(ctx context.Context, originDomain ui
 	// Convert origin domain to felt
 	domainFelt := utils.BigIntToFelt(big.NewInt(int64(originDomain)))
 
-	// Call quote_gas_payment(origin_domain: u32) -> u256
-	call := rpc.FunctionCall{
-		ContractAddress:    hyperlaneAddress,
-		EntryPointSelector: utils.GetSelectorFromNameFelt("quote_gas_payment"),
-		Calldata:           []*felt.Felt{domainFelt},
-	}
-
-	resp, err := h.provider.Call(ctx, call, rpc.WithBlockTag("latest"))
-	if err != nil {
-		return nil, fmt.Errorf("starknet quote_gas_payment call failed: %w", err)
-	}
-
-	if len(resp) < 2 {
-		return nil, fmt.Errorf("starknet quote_gas_payment returned insufficient data: expected 2 felts, got %d", len(resp))
-	}
-
-	// Convert two felts (low, high) back to u256
-	low := utils.FeltToBigInt(resp[0])
-	high := utils.FeltToBigInt(resp[1])
-
-	// Combine low and high into u256: (high << 128) | low
-	result := new(big.Int).Lsh(high, 128)
-	result.Or(result, low)
-
-	return result, nil
-}
-
-// EnsureETHApproval ensures the solver has approved the ETH address for settlement
-func (h *HyperlaneStarknet) ensureETHApproval(ctx context.Context, amount *big.Int, hyperlaneAddress *felt.Felt) error {
-	// Hard-coded ETH address on Starknet
-	ethAddress := "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7"
-	ethFelt, err := utils.HexToFelt(ethAddress)
-	if err != nil {
-		return fmt.Errorf("failed to convert ETH address to felt: %w", err)
-	}
-
-	// Check current allowance
-	call := rpc.FunctionCall{
-		ContractAddress:    ethFelt,
-		EntryPointSelector: utils.GetSelectorFromNameFelt("allowance"),
-		Calldata:           []*felt.Felt{h.solverAddr, hyperlaneAddress},
-	}
-
-	resp, err := h.provider.Call(ctx, call, rpc.WithBlockTag("latest"))
-	if err != nil {
-		return fmt.Errorf("starknet ETH allowance call failed: %w", err)
-	}
-
-	if len(resp) < 2 {
-		return fmt.Errorf("starknet ETH allowance returned insufficient data: expected 2 felts, got %d", len(resp))
-	}
-
-	// Convert two felts (low, high) back to u256
-	low := utils.FeltToBigInt(resp[0])
-	high := utils.FeltToBigInt(resp[1])
-	currentAllowance := new(big.Int).Lsh(high, 128)
-	currentAllowance.Or(currentAllowance, low)
-
-	// If allowance is sufficient, no need to approve
-	if currentAllowance.Cmp(amount) >= 0 {
-		return nil
-	}
-
-	// Need to approve - convert amount to two felts (low, high)
-	low128 := new(big.Int).And(amount, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1)))
-	high128 := new(big.Int).Rsh(amount, 128)
-
-	lowFelt := utils.BigIntToFelt(low128)
-	highFelt := utils.BigIntToFelt(high128)
-
-	// Build approve calldata: approve(spender: felt, amount: u256)
-	approveCalldata := []*felt.Felt{hyperlaneAddress, lowFelt, highFelt}
-
-	invoke := rpc.InvokeFunctionCall{
-		ContractAddress: ethFelt,
-		FunctionName:    "approve",
-		CallData:        approveCalldata,
-	}
-
-	tx, err := h.account.BuildAndSendInvokeTxn(ctx, []rpc.InvokeFunctionCall{invoke}, nil)
+	// quote_gas_payment(origin_domain: u32) -> u256
+	gasPayment, err := h.contracts.CallU256(ctx, hyperlaneAddress, "quote_gas_payment", domainFelt)
 	if err != nil {
-		return fmt.Errorf("starknet ETH approve send failed: %w", err)
+		return nil, fmt.Errorf("starknet %w", err)
 	}
-
-	fmt.Printf("   🔄 Starknet ETH approve tx sent: %s\n", tx.Hash.String())
-	_, waitErr := h.account.WaitForTransactionReceipt(ctx, tx.Hash, 2*time.Second)
-	if waitErr != nil {
-		return fmt.Errorf("starknet ETH approve wait failed: %w", waitErr)
-	}
-
-	fmt.Printf("   ✅ Starknet ETH approval confirmed\n")
-	return nil
+	return gasPayment, nil
 }
 
-// ensureTokenApproval ensures the solver has approved an arbitrary ERC20 token for the Hyperlane contract
-func (h *HyperlaneStarknet) ensureTokenApproval(ctx context.Context, tokenHex string, amount *big.Int, hyperlaneAddress *felt.Felt) error {
-	tokenFelt, err := utils.HexToFelt(tokenHex)
-	if err != nil {
-		return fmt.Errorf("invalid Starknet token address: %w", err)
-	}
-
-	// allowance(owner=solverAddr, spender=hyperlaneAddr) -> (low, high)
-	call := rpc.FunctionCall{
-		ContractAddress:    tokenFelt,
-		EntryPointSelector: utils.GetSelectorFromNameFelt("allowance"),
-		Calldata:           []*felt.Felt{h.solverAddr, hyperlaneAddress},
-	}
-
-	resp, err := h.provider.Call(ctx, call, rpc.WithBlockTag("latest"))
+// allowance returns allowance(owner=solverAddr, spender) for an ERC20 token
+func (h *HyperlaneStarknet) allowance(ctx context.Context, token, spender *felt.Felt) (*big.Int, error) {
+	allowance, err := h.contracts.Allowance(ctx, token, h.solverAddr, spender)
 	if err != nil {
-		return fmt.Errorf("starknet allowance call failed: %w", err)
+		return nil, fmt.Errorf("starknet %w", err)
 	}
-	if len(resp) < 2 {
-		return fmt.Errorf("starknet allowance response too short: %d", len(resp))
-	}
-
-	low := utils.FeltToBigInt(resp[0])
-	high := utils.FeltToBigInt(resp[1])
-	current := new(big.Int).Add(low, new(big.Int).Lsh(high, 128))
-	if current.Cmp(amount) >= 0 {
-		return nil
-	}
-
-	// Approve exact amount: approve(spender: felt, amount: u256)
-	low128 := new(big.Int).And(amount, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1)))
-	high128 := new(big.Int).Rsh(amount, 128)
-	lowF := utils.BigIntToFelt(low128)
-	highF := utils.BigIntToFelt(high128)
-
-	invoke := rpc.InvokeFunctionCall{
-		ContractAddress: tokenFelt,
-		FunctionName:    "approve",
-		CallData:        []*felt.Felt{hyperlaneAddress, lowF, highF},
-	}
-
-	tx, err := h.account.BuildAndSendInvokeTxn(ctx, []rpc.InvokeFunctionCall{invoke}, nil)
-	if err != nil {
-		return fmt.Errorf("starknet token approve send failed: %w", err)
-	}
-
-	_, waitErr := h.account.WaitForTransactionReceipt(ctx, tx.Hash, 2*time.Second)
-	if waitErr != nil {
-		return fmt.Errorf("starknet token approve wait failed: %w", waitErr)
-	}
-	return nil
+	return allowance, nil
 }
 
 // waitForOrderStatus waits for the order status to become the expected value with retry logic
@@ -539,9 +684,9 @@ func (h *HyperlaneStarknet) waitForOrderStatus(
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		status, err := h.GetOrderStatus(ctx, args)
 		if err != nil {
-			fmt.Printf("   ⚠️  Status check attempt %d failed: %v\n", attempt, err)
+			fillerLog.Printf("   ⚠️  Status check attempt %d failed: %v\n", attempt, err)
 		} else {
-			fmt.Printf("   📊 Status check attempt %d: %s (expected: %s)\n", attempt, status, expectedStatus)
+			fillerLog.Printf("   📊 Status check attempt %d: %s (expected: %s)\n", attempt, status, expectedStatus)
 			if status == expectedStatus {
 				return status, nil
 			}
@@ -549,7 +694,7 @@ func (h *HyperlaneStarknet) waitForOrderStatus(
 
 		// Don't wait after the last attempt
 		if attempt < maxRetries {
-			fmt.Printf("   ⏳ Waiting %v before retry %d/%d...\n", delay, attempt+1, maxRetries)
+			fillerLog.Printf("   ⏳ Waiting %v before retry %d/%d...\n", delay, attempt+1, maxRetries)
 			select {
 			case <-ctx.Done():
 				return "", ctx.Err()
@@ -0,0 +1,180 @@
+package hyperlane7683
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOrderID = "0x0000000000000000000000000000000000000000000000000000000000000001"
+
+// TestStarknetMulticallCalls tests the calls batched into the fill+settle multicall
+func TestStarknetMulticallCalls(t *testing.T) {
+	settler, err := utils.HexToFelt("0x1234")
+	require.NoError(t, err)
+
+	t.Run("fill_call_calldata", func(t *testing.T) {
+		originData := make([]byte, 32)
+		call, err := buildFillCall(testOrderID, originData, settler)
+		require.NoError(t, err)
+
+		assert.Equal(t, "fill", call.FunctionName)
+		assert.Equal(t, settler, call.ContractAddress)
+		// order id (2) + size + len + 2 words + filler size + filler len
+		assert.Len(t, call.CallData, 8)
+		assert.Equal(t, utils.Uint64ToFelt(32), call.CallData[2])
+		assert.Equal(t, utils.Uint64ToFelt(2), call.CallData[3])
+	})
+
+	// Calldata accepted by the deployed Cairo contract
+	orderID := "0x00112233445566778899aabbccddeeff0102030405060708090a0b0c0d0e0f10"
+	feltStrings := func(calldata []*felt.Felt) []string {
+		out := make([]string, len(calldata))
+		for i, f := range calldata {
+			out[i] = f.String()
+		}
+		return out
+	}
+
+	t.Run("fill_call_known_good_calldata", func(t *testing.T) {
+		originData := make([]byte, 40)
+		for i := range originData {
+			originData[i] = byte(i + 1)
+		}
+		call, err := buildFillCall(orderID, originData, settler)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"0x102030405060708090a0b0c0d0e0f10", "0x112233445566778899aabbccddeeff", // order_id: u256 (low, high)
+			"0x28", "0x3", // origin_data: size, word count
+			"0x102030405060708090a0b0c0d0e0f10", "0x1112131415161718191a1b1c1d1e1f20", "0x21222324252627280000000000000000",
+			"0x0", "0x0", // filler_data: empty
+		}, feltStrings(call.CallData))
+	})
+
+	t.Run("fill_call_carries_origin_data_with_payload", func(t *testing.T) {
+		// An EVM OrderData with a 5-byte payload, padded to 480 bytes; the Cairo decoder reads the
+		// payload back by its size word
+		originData := encodeEVMOriginData(make([]byte, 11*32), []byte{1, 2, 3, 4, 5})
+		require.Len(t, originData, evmOriginDataSize+32)
+
+		call, err := buildFillCall(orderID, originData, settler)
+		require.NoError(t, err)
+		assert.Equal(t, utils.Uint64ToFelt(uint64(len(originData))), call.CallData[2])
+		words := int(call.CallData[3].Uint64())
+		passed, err := starknetutil.U128FeltsToBytes(call.CallData[4:4+words], len(originData))
+		require.NoError(t, err)
+		assert.Equal(t, originData, passed)
+	})
+
+	t.Run("settle_call_known_good_calldata", func(t *testing.T) {
+		gasPayment := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+		call, err := buildSettleCall(orderID, gasPayment, settler)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"0x1",                                                                   // order_ids: length
+			"0x102030405060708090a0b0c0d0e0f10", "0x112233445566778899aabbccddeeff", // order_ids[0]: u256
+			"0x1", "0x1", // value: u256 (low, high)
+		}, feltStrings(call.CallData))
+	})
+
+	t.Run("settle_call_calldata", func(t *testing.T) {
+		call, err := buildSettleCall(testOrderID, big.NewInt(500), settler)
+		require.NoError(t, err)
+
+		assert.Equal(t, "settle", call.FunctionName)
+		assert.Len(t, call.CallData, 5)
+		assert.Equal(t, utils.Uint64ToFelt(1), call.CallData[0])
+		assert.Equal(t, utils.Uint64ToFelt(500), call.CallData[3])
+		assert.Equal(t, utils.Uint64ToFelt(0), call.CallData[4])
+	})
+
+	t.Run("invalid_order_id", func(t *testing.T) {
+		_, err := buildFillCall("not-hex", nil, settler)
+		assert.Error(t, err)
+		_, err = buildSettleCall("not-hex", big.NewInt(1), settler)
+		assert.Error(t, err)
+	})
+}
+
+// TestCollectApprovalAmounts tests how approval requirements are gathered for the multicall
+func TestCollectApprovalAmounts(t *testing.T) {
+	const destChainID = 23448591
+	token := "0x0abc"
+
+	t.Run("filters_and_sums_per_token", func(t *testing.T) {
+		args := &types.ParsedArgs{
+			ResolvedOrder: types.ResolvedCrossChainOrder{
+				MaxSpent: []types.Output{
+					{Token: token, Amount: big.NewInt(100), ChainID: big.NewInt(destChainID)},
+					{Token: token, Amount: big.NewInt(50), ChainID: big.NewInt(destChainID)},
+					{Token: "0x0def", Amount: big.NewInt(10), ChainID: big.NewInt(1)},     // other chain
+					{Token: "", Amount: big.NewInt(10), ChainID: big.NewInt(destChainID)}, // native
+				},
+			},
+		}
+
+		required, err := collectApprovalAmounts(args, destChainID, nil)
+		require.NoError(t, err)
+		require.Len(t, required, 1)
+		assert.Equal(t, "0xabc", required[0].token.String())
+		assert.Equal(t, big.NewInt(150), required[0].amount)
+	})
+
+	t.Run("gas_payment_adds_eth", func(t *testing.T) {
+		args := &types.ParsedArgs{
+			ResolvedOrder: types.ResolvedCrossChainOrder{
+				MaxSpent: []types.Output{
+					{Token: starknetETHAddress, Amount: big.NewInt(100), ChainID: big.NewInt(destChainID)},
+				},
+			},
+		}
+
+		required, err := collectApprovalAmounts(args, destChainID, big.NewInt(7))
+		require.NoError(t, err)
+		require.Len(t, required, 1)
+		assert.Equal(t, big.NewInt(107), required[0].amount)
+	})
+
+	t.Run("zero_gas_payment_skipped", func(t *testing.T) {
+		required, err := collectApprovalAmounts(&types.ParsedArgs{}, destChainID, big.NewInt(0))
+		require.NoError(t, err)
+		assert.Empty(t, required)
+	})
+
+	t.Run("invalid_token_address", func(t *testing.T) {
+		args := &types.ParsedArgs{
+			ResolvedOrder: types.ResolvedCrossChainOrder{
+				MaxSpent: []types.Output{
+					{Token: "not-hex", Amount: big.NewInt(1), ChainID: big.NewInt(destChainID)},
+				},
+			},
+		}
+
+		_, err := collectApprovalAmounts(args, destChainID, nil)
+		assert.Error(t, err)
+	})
+}
+
+// TestStarknetTxCost tests converting a receipt fee into a cost record
+func TestStarknetTxCost(t *testing.T) {
+	fee := rpc.FeePayment{Amount: new(felt.Felt).SetUint64(1234), Unit: rpc.UnitFri}
+	cost := starknetTxCost(23448591, orders.TxKindFillSettle, "0xabc", fee, big.NewInt(5))
+
+	assert.Equal(t, uint64(23448591), cost.ChainID)
+	assert.Equal(t, orders.TxKindFillSettle, cost.Kind)
+	assert.Equal(t, "0xabc", cost.TxHash)
+	assert.Equal(t, big.NewInt(1234), cost.Fee)
+	assert.Equal(t, "FRI", cost.FeeUnit)
+	assert.Equal(t, big.NewInt(5), cost.GasPayment)
+
+	empty := starknetTxCost(1, orders.TxKindSettle, "0x1", rpc.FeePayment{}, nil)
+	assert.Equal(t, 0, empty.Fee.Sign())
+}
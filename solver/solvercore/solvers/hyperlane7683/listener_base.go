@@ -3,12 +3,17 @@ package hyperlane7683
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 )
 
+// listenerLog logs event listening at the "listener" log level
+var listenerLog = logutil.For(logutil.ComponentListener)
+
 // BlockNumberProvider defines the interface for getting the current block number
 // This allows both EVM and Starknet listeners to use the same block processing logic
 type BlockNumberProvider interface {
@@ -21,16 +26,98 @@ type BaseListener struct {
 	lastProcessedBlock uint64
 	blockProvider      BlockNumberProvider
 	networkType        string // "EVM" or "Starknet" for logging
+	status             *ListenerStatus
+	checkpoint         *blockCheckpointer
+	queue              *orderQueue     // orders of the backfill range being processed; nil keeps block order
+	pacer              *pollPacer      // wait between polls; nil waits the poll interval
+	prefetch           rangePrefetcher // reads the logs of the next backfill ranges in one batch; nil reads them one at a time
 }
 
 // NewBaseListener creates a new base listener with common functionality
 func NewBaseListener(config base.ListenerConfig, blockProvider BlockNumberProvider, networkType string) *BaseListener {
+	queue := newOrderQueue(orderStrategyFromEnv())
+	checkpoint := newBlockCheckpointer(config.ChainName, 0)
+	checkpoint.queue = queue
 	return &BaseListener{
 		config:             config,
 		lastProcessedBlock: 0,
 		blockProvider:      blockProvider,
 		networkType:        networkType,
+		status:             NewListenerStatus(config.ChainName, networkType, 0),
+		checkpoint:         checkpoint,
+		queue:              queue,
+		pacer:              pollPacerFromEnv(config.ChainName),
+	}
+}
+
+// dispatchOnce invokes the handler unless the Open event identified by the order ID, txHash and
+// logIndex was already handled, so blocks processed again (backfill/polling boundary, resuming
+// from a checkpoint) don't dispatch an event twice. Events are remembered (config.MarkEventSeen)
+// once the handler returned and persisted with the next checkpoint; an event whose handling was
+// interrupted by a crash is handled again after the restart. Without dedupe every event is
+// dispatched, e.g. when replaying a recording.
+func dispatchOnce(dedupe bool, handler base.EventHandler, args types.ParsedArgs, chainName string, blockNumber uint64, txHash string, logIndex uint) (bool, error) {
+	if !dedupe {
+		return handler(args, chainName, blockNumber)
+	}
+	key := config.SeenEventKey(chainName, args.OrderID, txHash, logIndex)
+	if config.EventSeen(key) {
+		listenerLog.Printf("%s⏭️  Open event for order %s already handled, skipping\n", logutil.Prefix(chainName), args.OrderID)
+		return false, nil
+	}
+	settled, err := handler(args, chainName, blockNumber)
+	config.MarkEventSeen(key)
+	return settled, err
+}
+
+// orderRecipients names the chains an order is filled on, one recipient per destination, for the
+// allow and block lists. Same-chain orders name their origin chain like any other destination;
+// chainName is used when the order has no fill instruction.
+func orderRecipients(order types.ResolvedCrossChainOrder, chainName string) []types.Recipient {
+	var recipients []types.Recipient
+	seen := make(map[string]bool)
+	for _, instruction := range order.FillInstructions {
+		if instruction.DestinationChainID == nil {
+			continue
+		}
+		name := logutil.NetworkNameByChainID(instruction.DestinationChainID.Uint64())
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		recipients = append(recipients, types.Recipient{DestinationChainName: name, RecipientAddress: "*"})
 	}
+	if len(recipients) == 0 {
+		recipients = append(recipients, types.Recipient{DestinationChainName: chainName, RecipientAddress: "*"})
+	}
+	return recipients
+}
+
+// blockCheckpointer persists a listener's progress inside a block range, every config.CheckpointBlocks()
+// blocks, so a crash during a long backfill range doesn't restart the whole range.
+// Used from the listener's polling goroutine only.
+type blockCheckpointer struct {
+	chainName string
+	every     uint64
+	saved     uint64      // last persisted block
+	queue     *orderQueue // no checkpoint while it holds orders of blocks already processed
+}
+
+func newBlockCheckpointer(chainName string, saved uint64) *blockCheckpointer {
+	return &blockCheckpointer{chainName: chainName, every: config.CheckpointBlocks(), saved: saved}
+}
+
+// Advance records that block is fully processed and persists it once every blocks have
+// been processed since the last checkpoint. A nil checkpointer does nothing.
+func (c *blockCheckpointer) Advance(block uint64) {
+	if c == nil || c.every == 0 || block < c.saved+c.every || c.queue.Len() > 0 {
+		return
+	}
+	if err := config.UpdateLastIndexedBlock(c.chainName, block); err != nil {
+		listenerLog.Printf("%s⚠️  Failed to persist checkpoint at block %d: %v\n", logutil.Prefix(c.chainName), block, err)
+		return
+	}
+	c.saved = block
 }
 
 // ResolveSolverStartBlock resolves the actual start block based on solver start block configuration
@@ -74,6 +161,7 @@ func ProcessCurrentBlockRangeCommon(
 	blockProvider BlockNumberProvider,
 	listenerConfig *base.ListenerConfig,
 	lastProcessedBlock *uint64,
+	status *ListenerStatus,
 	networkType string,
 	processBlockRange func(context.Context, uint64, uint64, base.EventHandler) (uint64, error),
 ) error {
@@ -81,6 +169,7 @@ func ProcessCurrentBlockRangeCommon(
 	if err != nil {
 		return fmt.Errorf("failed to get current block number: %v", err)
 	}
+	status.RecordHead(currentBlock)
 
 	// Apply confirmations window if configured
 	safeBlock := currentBlock
@@ -94,6 +183,7 @@ func ProcessCurrentBlockRangeCommon(
 	// Check if we have any new blocks to process
 	if fromBlock > toBlock {
 		// No new blocks to process, we're up to date
+		status.RecordPoll()
 		return nil
 	}
 
@@ -107,25 +197,53 @@ func ProcessCurrentBlockRangeCommon(
 			end = toBlock
 		}
 
-		logutil.LogWithNetworkTagf(listenerConfig.ChainName, "🧭 %s range: from=%d to=%d (current=%d, conf=%d)\n",
-			networkType, start, end, currentBlock, listenerConfig.ConfirmationBlocks)
-
 		chunkLast, err := processBlockRange(ctx, start, end, handler)
 		if err != nil {
 			return fmt.Errorf("failed to process blocks %d-%d: %v", start, end, err)
 		}
 
 		newLast = chunkLast
+		status.RecordProcessed(newLast)
 		if err := config.UpdateLastIndexedBlock(listenerConfig.ChainName, newLast); err != nil {
-			fmt.Printf("⚠️  Failed to persist LastIndexedBlock for %s: %v\n", listenerConfig.ChainName, err)
+			listenerLog.Printf("⚠️  Failed to persist LastIndexedBlock for %s: %v\n", listenerConfig.ChainName, err)
 		}
 	}
 
 	// Block processing complete
 	*lastProcessedBlock = newLast
+	status.RecordPoll()
 	return nil
 }
 
+// pollWithCircuitBreaker runs one polling iteration guarded by the chain's polling circuit breaker.
+// While the circuit is open polling is skipped instead of retrying the same failing range.
+// Polling is also skipped while the network is paused; the missed blocks are processed on resume.
+// Failures are recorded in the listener's status.
+func pollWithCircuitBreaker(chainName string, status *ListenerStatus, process func() error) {
+	if !config.NetworkEnabled(chainName) {
+		return
+	}
+
+	breaker := DefaultPollCircuitBreaker()
+	chainID := config.Networks[chainName].ChainID
+	if err := breaker.Allow(chainID); err != nil {
+		return // paused; the alert was emitted when the circuit opened
+	}
+
+	if err := process(); err != nil {
+		listenerLog.Printf("%s❌ Failed to process current block range: %v\n", logutil.Prefix(chainName), err)
+		status.RecordError(err)
+		breaker.RecordFailure(chainID, err)
+		return
+	}
+	breaker.RecordSuccess(chainID)
+}
+
+// pollInterval returns how long a listener waits between polls; runtime config overrides apply without a restart
+func pollInterval(cfg *base.ListenerConfig) time.Duration {
+	return time.Duration(config.ActiveRuntime().PollInterval(cfg.ChainName, cfg.PollInterval)) * time.Millisecond
+}
+
 // GetLastProcessedBlock returns the last processed block number
 func (bl *BaseListener) GetLastProcessedBlock() uint64 {
 	return bl.lastProcessedBlock
@@ -134,6 +252,13 @@ func (bl *BaseListener) GetLastProcessedBlock() uint64 {
 // SetLastProcessedBlock sets the last processed block number
 func (bl *BaseListener) SetLastProcessedBlock(block uint64) {
 	bl.lastProcessedBlock = block
+	bl.status.RecordProcessed(block)
+	bl.checkpoint.saved = block
+}
+
+// Status returns the listener's progress tracker
+func (bl *BaseListener) Status() *ListenerStatus {
+	return bl.status
 }
 
 // GetConfig returns the listener configuration
@@ -148,12 +273,18 @@ func (bl *BaseListener) CatchUpHistoricalBlocks(
 	processBlockRange func(context.Context, uint64, uint64, base.EventHandler) (uint64, error),
 ) error {
 	p := logutil.Prefix(bl.config.ChainName)
-	fmt.Printf("%s🔄 Catching up on historical blocks...\n", p)
+	if !config.NetworkEnabled(bl.config.ChainName) {
+		listenerLog.Printf("%s⏸️  Network paused, historical blocks are processed once it is resumed\n", p)
+		return nil
+	}
+	listenerLog.Printf("%s🔄 Catching up on historical blocks...\n", p)
 
 	currentBlock, err := bl.blockProvider.BlockNumber(ctx)
 	if err != nil {
+		bl.status.RecordError(err)
 		return fmt.Errorf("%sfailed to get current block number: %v", p, err)
 	}
+	bl.status.RecordHead(currentBlock)
 
 	// Apply confirmations during backfill as well
 	safeBlock := currentBlock
@@ -165,35 +296,56 @@ func (bl *BaseListener) CatchUpHistoricalBlocks(
 	fromBlock := bl.lastProcessedBlock + 1
 	toBlock := safeBlock
 	if fromBlock >= toBlock {
-		fmt.Printf("%s✅ Already up to date, no historical blocks to process\n", p)
+		listenerLog.Printf("%s✅ Already up to date, no historical blocks to process\n", p)
 		return nil
 	}
 
+	// Orders of each range are handed to the solver by the selected strategy once the range is read
+	bl.queue.Begin()
+	defer bl.queue.End()
+
 	chunkSize := bl.config.MaxBlockRange
+	prefetched := 0 // ranges of the last batch not processed yet
 	for start := fromBlock; start < toBlock; start += chunkSize {
 		end := start + chunkSize
 		if end > toBlock {
 			end = toBlock
 		}
 
+		if bl.prefetch != nil && prefetched == 0 {
+			if size := bl.prefetch.BatchSize(); size > 1 {
+				ranges := backfillRanges(start, toBlock, chunkSize, size)
+				bl.prefetch.Prefetch(ctx, ranges)
+				prefetched = len(ranges)
+			}
+		}
+		if prefetched > 0 {
+			prefetched--
+		}
+
 		newLast, err := processBlockRange(ctx, start, end, handler)
 		if err != nil {
+			bl.status.RecordError(err)
 			return fmt.Errorf("%sfailed to process historical blocks %d-%d: %v", p, start, end, err)
 		}
+		if err := bl.queue.Drain(ctx, handler); err != nil {
+			return fmt.Errorf("%sfailed to handle orders of historical blocks %d-%d: %v", p, start, end, err)
+		}
 
 		bl.lastProcessedBlock = newLast
+		bl.status.RecordProcessed(newLast)
 		if err := config.UpdateLastIndexedBlock(bl.config.ChainName, newLast); err != nil {
-			fmt.Printf("%s⚠️  Failed to persist LastIndexedBlock: %v\n", p, err)
+			listenerLog.Printf("%s⚠️  Failed to persist LastIndexedBlock: %v\n", p, err)
 		}
 	}
 
-	fmt.Printf("%s✅ Historical block processing complete\n", p)
+	listenerLog.Printf("%s✅ Historical block processing complete\n", p)
 	return nil
 }
 
 // CommonListenerConfig holds common configuration for both EVM and Starknet listeners
 type CommonListenerConfig struct {
-	ListenerConfig *base.ListenerConfig
+	ListenerConfig     *base.ListenerConfig
 	LastProcessedBlock uint64
 }
 
@@ -219,7 +371,7 @@ func ResolveCommonListenerConfig(
 		if configStartBlock == 0 {
 			// Zero - start at current block (live)
 			resolvedStartBlock = currentBlock
-			fmt.Printf("%s📚 Start block was 0, using current block %d\n",
+			listenerLog.Printf("%s📚 Start block was 0, using current block %d\n",
 				logutil.Prefix(listenerConfig.ChainName), currentBlock)
 		} else {
 			// Negative number - start N blocks before current block
@@ -231,7 +383,7 @@ func ResolveCommonListenerConfig(
 				resolvedStartBlock = 0
 			}
 
-			fmt.Printf("%s📚 Start block was %d, using current block %d - %d = %d\n",
+			listenerLog.Printf("%s📚 Start block was %d, using current block %d - %d = %d\n",
 				logutil.Prefix(listenerConfig.ChainName), configStartBlock, currentBlock, -configStartBlock, resolvedStartBlock)
 		}
 	}
@@ -245,12 +397,16 @@ func ResolveCommonListenerConfig(
 	if networkState, exists := state.Networks[listenerConfig.ChainName]; exists {
 		deploymentStateBlock := networkState.LastIndexedBlock
 		if deploymentStateBlock > resolvedStartBlock {
-			lastProcessedBlock = deploymentStateBlock
-			fmt.Printf("%s📚 Using deployment state block %d (higher than config start block %d)\n",
-				logutil.Prefix(listenerConfig.ChainName), deploymentStateBlock, resolvedStartBlock)
+			head, err := blockProvider.BlockNumber(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get current block number: %v", err)
+			}
+			lastProcessedBlock = max(resolveStaleCheckpoint(listenerConfig.ChainName, deploymentStateBlock, head, resetStaleCheckpointsFromEnv()), resolvedStartBlock)
+			listenerLog.Printf("%s📚 Using deployment state block %d (higher than config start block %d)\n",
+				logutil.Prefix(listenerConfig.ChainName), lastProcessedBlock, resolvedStartBlock)
 		} else {
 			lastProcessedBlock = resolvedStartBlock
-			fmt.Printf("%s📚 Using config start block %d (deployment state block %d is lower)\n",
+			listenerLog.Printf("%s📚 Using config start block %d (deployment state block %d is lower)\n",
 				logutil.Prefix(listenerConfig.ChainName), resolvedStartBlock, deploymentStateBlock)
 		}
 	} else {
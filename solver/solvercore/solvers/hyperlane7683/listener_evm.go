@@ -3,6 +3,8 @@ package hyperlane7683
 // Module: EVM Open event listener for Hyperlane7683
 // - Polls/backfills block ranges on EVM networks
 // - Parses Hyperlane7683 Open events via abigen bindings
+// - Reports Filled events to the competition tracker (see competition.go)
+// - Reports Settled/Refunded events of orders opened on this chain to the settlement tracker (see settlements.go)
 // - Translates to types.ParsedArgs and invokes the solver
 // - Persists last processed block via deployment state
 
@@ -17,6 +19,7 @@ import (
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
 	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/rpctimeout"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -27,15 +30,32 @@ import (
 // Open event topic
 var openEventTopic = common.HexToHash("0x3448bbc2203c608599ad448eeb1007cea04b788ac631f9f558e8dd01a3c27b3d")
 
+// Filled event topic
+var filledEventTopic = common.HexToHash("0x57f1f65270c1c2c1771948825ee86f8d23d11ab44b16eb9c213056e042d06e59")
+
+// Settled and Refunded event topics, emitted on the origin chain when an order's input is released
+var (
+	settledEventTopic  = common.HexToHash("0xa569bfd2e3bd9bd14cfdabad61aef5f3d5b18b0fcdf78805e65349dda2210fbc")
+	refundedEventTopic = common.HexToHash("0x5e9f0820fcfb53b644becb775b651bae68c337106f21433e526551d1e02c1c0e")
+)
+
 // evmListener implements listener.Listener for EVM chains
 type evmListener struct {
 	config             *base.ListenerConfig
-	client             *ethclient.Client
+	client             EVMClient
 	contractAddress    common.Address
 	lastProcessedBlock uint64
 	stopChan           chan struct{}
 	mu                 sync.RWMutex
 	baseListener       *BaseListener
+	status             *ListenerStatus
+	checkpoint         *blockCheckpointer
+	dedupeEvents       bool // skip events already handled (see dispatchOnce); off when replaying
+	fills              *CompetitionTracker
+	settlements        *SettlementTracker
+	queue              *orderQueue
+	pacer              *pollPacer
+	batch              *logBatch[[]ethtypes.Log] // logs of the next backfill ranges (see log_batch.go); nil reads one range at a time
 }
 
 func NewEVMListener(listenerConfig *base.ListenerConfig, rpcURL string) (base.Listener, error) {
@@ -43,7 +63,20 @@ func NewEVMListener(listenerConfig *base.ListenerConfig, rpcURL string) (base.Li
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial RPC: %w", err)
 	}
+	return NewEVMListenerWithClient(listenerConfig, rpctimeout.NewEVMClient(client, listenerConfig.ChainName))
+}
 
+// NewEVMListenerWithClient creates an EVM listener on an existing client, e.g. the one shared with the solver
+func NewEVMListenerWithClient(listenerConfig *base.ListenerConfig, client EVMClient) (base.Listener, error) {
+	listener, err := newEVMListenerWithClient(listenerConfig, client)
+	if err != nil {
+		return nil, err
+	}
+	return listener, nil
+}
+
+// newEVMListenerWithClient creates an EVM listener on an existing client
+func newEVMListenerWithClient(listenerConfig *base.ListenerConfig, client EVMClient) (*evmListener, error) {
 	address, err := types.ToEVMAddress(listenerConfig.ContractAddress)
 	if err != nil {
 		return nil, fmt.Errorf("invalid EVM contract address: %w", err)
@@ -57,8 +90,8 @@ func NewEVMListener(listenerConfig *base.ListenerConfig, rpcURL string) (base.Li
 
 	baseListener := NewBaseListener(*listenerConfig, client, "EVM")
 	baseListener.SetLastProcessedBlock(commonConfig.LastProcessedBlock)
-	
-	return &evmListener{
+
+	listener := &evmListener{
 		config:             listenerConfig,
 		client:             client,
 		contractAddress:    address,
@@ -66,7 +99,19 @@ func NewEVMListener(listenerConfig *base.ListenerConfig, rpcURL string) (base.Li
 		stopChan:           make(chan struct{}),
 		mu:                 sync.RWMutex{},
 		baseListener:       baseListener,
-	}, nil
+		status:             baseListener.Status(),
+		checkpoint:         baseListener.checkpoint,
+		dedupeEvents:       true,
+		fills:              DefaultCompetitionTracker(),
+		settlements:        DefaultSettlementTracker(),
+		queue:              baseListener.queue,
+		pacer:              baseListener.pacer,
+	}
+	if batch := newEVMLogBatch(listener); batch != nil {
+		listener.batch = batch
+		baseListener.prefetch = batch
+	}
+	return listener, nil
 }
 
 // Start begins listening for events
@@ -88,22 +133,27 @@ func (l *evmListener) GetLastProcessedBlock() uint64 {
 	return l.lastProcessedBlock
 }
 
+// Snapshot returns the listener's block progress; it doesn't wait for an in-flight poll
+func (l *evmListener) Snapshot() base.ListenerSnapshot {
+	return l.status.Snapshot()
+}
+
 // MarkBlockFullyProcessed marks a block as fully processed and updates LastIndexedBlock
 func (l *evmListener) MarkBlockFullyProcessed(blockNumber uint64) error {
 	if blockNumber != l.lastProcessedBlock+1 {
 		return fmt.Errorf("cannot mark block %d as processed, expected %d", blockNumber, l.lastProcessedBlock+1)
 	}
 	l.lastProcessedBlock = blockNumber
-	fmt.Printf("%s✅ block %d processed\n", logutil.Prefix(l.config.ChainName), blockNumber)
+	listenerLog.Printf("%s✅ block %d processed\n", logutil.Prefix(l.config.ChainName), blockNumber)
 	return nil
 }
 
 func (l *evmListener) startEventLoop(ctx context.Context, handler base.EventHandler) {
 	p := logutil.Prefix(l.config.ChainName)
 	if err := l.catchUpHistoricalBlocks(ctx, handler); err != nil {
-		fmt.Printf("%s❌ backfill failed: %v\n", p, err)
+		listenerLog.Printf("%s❌ backfill failed: %v\n", p, err)
 	}
-	fmt.Printf("%s🔄 backfill complete\n", p)
+	listenerLog.Printf("%s🔄 backfill complete\n", p)
 	l.startPolling(ctx, handler)
 }
 
@@ -112,21 +162,21 @@ func (l *evmListener) catchUpHistoricalBlocks(ctx context.Context, handler base.
 }
 
 func (l *evmListener) startPolling(ctx context.Context, handler base.EventHandler) {
-	fmt.Printf("%s📭 Starting event polling...\n", logutil.Prefix(l.config.ChainName))
+	listenerLog.Printf("%s📭 Starting event polling...\n", logutil.Prefix(l.config.ChainName))
 
 	for {
 		select {
 		case <-ctx.Done():
-			fmt.Printf("🔄 Context canceled, stopping event polling\n")
+			listenerLog.Printf("🔄 Context canceled, stopping event polling\n")
 			return
 		case <-l.stopChan:
-			fmt.Printf("🔄 Stop signal received, stopping event polling\n")
+			listenerLog.Printf("🔄 Stop signal received, stopping event polling\n")
 			return
 		default:
-			if err := l.processCurrentBlockRange(ctx, handler); err != nil {
-				fmt.Printf("%s❌ Failed to process current block range: %v\n", logutil.Prefix(l.config.ChainName), err)
-			}
-			time.Sleep(time.Duration(l.config.PollInterval) * time.Millisecond)
+			pollWithCircuitBreaker(l.config.ChainName, l.status, func() error {
+				return l.processCurrentBlockRange(ctx, handler)
+			})
+			time.Sleep(l.pacer.Next(pollInterval(l.config), l.status.Snapshot()))
 		}
 	}
 }
@@ -134,28 +184,30 @@ func (l *evmListener) startPolling(ctx context.Context, handler base.EventHandle
 func (l *evmListener) processCurrentBlockRange(ctx context.Context, handler base.EventHandler) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	return ProcessCurrentBlockRangeCommon(ctx, handler, l.client, l.config, &l.lastProcessedBlock, "EVM", l.processBlockRange)
+	return ProcessCurrentBlockRangeCommon(ctx, handler, l.client, l.config, &l.lastProcessedBlock, l.status, "EVM", l.processBlockRange)
 }
 
 // processBlockRange processes logs in [fromBlock, toBlock] and returns the highest contiguous block fully processed
 func (l *evmListener) processBlockRange(ctx context.Context, fromBlock, toBlock uint64, handler base.EventHandler) (uint64, error) {
 	if fromBlock > toBlock {
-		fmt.Printf("⚠️  Invalid block range (%s) in processBlockRange: fromBlock (%d) > toBlock (%d), skipping\n", l.config.ChainName, fromBlock, toBlock)
+		listenerLog.Printf("⚠️  Invalid block range (%s) in processBlockRange: fromBlock (%d) > toBlock (%d), skipping\n", l.config.ChainName, fromBlock, toBlock)
 		return l.lastProcessedBlock, nil
 	}
 
-	// Fetch events for the block range
-	query := ethereum.FilterQuery{
-		FromBlock:  big.NewInt(int64(fromBlock)),
-		ToBlock:    big.NewInt(int64(toBlock)),
-		Addresses:  []common.Address{l.contractAddress},
-		Topics:     [][]common.Hash{{openEventTopic}},
-		BlockHash:  nil,
+	// Use generated binding to parse the events, before taking them from the backfill batch so a
+	// failure leaves the range to be fetched again
+	filterer, err := contracts.NewHyperlane7683Filterer(l.contractAddress, l.client)
+	if err != nil {
+		return l.lastProcessedBlock, fmt.Errorf("failed to bind filterer: %w", err)
 	}
 
-	logs, err := l.client.FilterLogs(ctx, query)
-	if err != nil {
-		return l.lastProcessedBlock, fmt.Errorf("failed to filter logs: %w", err)
+	// Fetch events for the block range, unless they were read in the last backfill batch
+	logs, prefetched := l.batch.Take(blockRange{from: fromBlock, to: toBlock})
+	if !prefetched {
+		logs, err = l.client.FilterLogs(ctx, l.logQuery(fromBlock, toBlock))
+		if err != nil {
+			return l.lastProcessedBlock, fmt.Errorf("failed to filter logs: %w", err)
+		}
 	}
 
 	// Use the new logging system for reduced verbosity
@@ -163,10 +215,15 @@ func (l *evmListener) processBlockRange(ctx context.Context, fromBlock, toBlock
 
 	// Group logs by block
 	byBlock := make(map[uint64][]ethtypes.Log)
+	opens := 0
 	for i := range logs {
 		event := &logs[i]
 		byBlock[event.BlockNumber] = append(byBlock[event.BlockNumber], *event)
+		if len(event.Topics) > 0 && event.Topics[0] == openEventTopic {
+			opens++
+		}
 	}
+	l.status.RecordEvents(opens)
 
 	// Process blocks in order
 	newLast := l.lastProcessedBlock
@@ -176,40 +233,77 @@ func (l *evmListener) processBlockRange(ctx context.Context, fromBlock, toBlock
 		// Process each event in this block
 		for i := range events {
 			logEvent := &events[i]
-			// Use generated binding to parse Open events
-			filterer, err := contracts.NewHyperlane7683Filterer(l.contractAddress, l.client)
-			if err != nil {
-				fmt.Printf("❌ Failed to bind filterer: %v\n", err)
+
+			// Filled events only feed the competition tracker
+			if len(logEvent.Topics) > 0 && logEvent.Topics[0] == filledEventTopic {
+				filled, err := filterer.ParseFilled(*logEvent)
+				if err != nil {
+					listenerLog.Printf("❌ Failed to parse Filled event: %v\n", err)
+					continue
+				}
+				l.fills.RecordFill(l.config.ChainName, common.BytesToHash(filled.OrderId[:]).Hex(), logEvent.TxHash.Hex(), logEvent.BlockNumber)
+				continue
+			}
+
+			// Settled and Refunded events only feed the settlement tracker
+			if len(logEvent.Topics) > 0 && logEvent.Topics[0] == settledEventTopic {
+				settled, err := filterer.ParseSettled(*logEvent)
+				if err != nil {
+					listenerLog.Printf("❌ Failed to parse Settled event: %v\n", err)
+					continue
+				}
+				l.settlements.RecordSettled(l.config.ChainName, common.BytesToHash(settled.OrderId[:]).Hex(), settled.Receiver.Hex(), logEvent.TxHash.Hex())
+				continue
+			}
+			if len(logEvent.Topics) > 0 && logEvent.Topics[0] == refundedEventTopic {
+				refunded, err := filterer.ParseRefunded(*logEvent)
+				if err != nil {
+					listenerLog.Printf("❌ Failed to parse Refunded event: %v\n", err)
+					continue
+				}
+				l.settlements.RecordRefunded(l.config.ChainName, common.BytesToHash(refunded.OrderId[:]).Hex(), refunded.Receiver.Hex(), logEvent.TxHash.Hex())
 				continue
 			}
 
 			// Parse Open event
 			event, err := filterer.ParseOpen(*logEvent)
 			if err != nil {
-				fmt.Printf("❌ Failed to parse Open event: %v\n", err)
+				listenerLog.Printf("❌ Failed to parse Open event: %v\n", err)
 				continue
 			}
 
 			// Handle the event
 			_, err = l.handleParsedOpenEvent(event, handler)
 			if err != nil {
-				fmt.Printf("❌ Failed to handle Open event: %v\n", err)
+				listenerLog.Printf("❌ Failed to handle Open event: %v\n", err)
 				continue
 			}
 		}
 
 		// Mark block as processed
 		newLast = b
+		l.status.RecordProcessed(b)
+		l.checkpoint.Advance(b)
 
 		// Only log individual blocks if there are events
 		if len(events) > 0 {
-			logutil.LogWithNetworkTagf(l.config.ChainName, "   ✅ Block %d processed: %d events\n", b, len(events))
+			listenerLog.WithNetworkTagf(l.config.ChainName, "   ✅ Block %d processed: %d events\n", b, len(events))
 		}
 	}
 
 	return newLast, nil
 }
 
+// logQuery returns the log filter of the listener's events in [fromBlock, toBlock]
+func (l *evmListener) logQuery(fromBlock, toBlock uint64) ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		FromBlock: big.NewInt(int64(fromBlock)),
+		ToBlock:   big.NewInt(int64(toBlock)),
+		Addresses: []common.Address{l.contractAddress},
+		Topics:    [][]common.Hash{{openEventTopic, filledEventTopic, settledEventTopic, refundedEventTopic}},
+	}
+}
+
 // handleParsedOpenEvent converts a typed binding event into our internal ParsedArgs and dispatches the handler
 func (l *evmListener) handleParsedOpenEvent(ev *contracts.Hyperlane7683Open, handler base.EventHandler) (bool, error) {
 	p := logutil.Prefix(l.config.ChainName)
@@ -253,18 +347,15 @@ func (l *evmListener) handleParsedOpenEvent(ev *contracts.Hyperlane7683Open, han
 	parsedArgs := types.ParsedArgs{
 		OrderID:       common.BytesToHash(ev.OrderId[:]).Hex(),
 		SenderAddress: ro.User,
-		Recipients: []types.Recipient{{
-			DestinationChainName: l.config.ChainName,
-			RecipientAddress:     "*",
-		}},
+		Recipients:    orderRecipients(ro, l.config.ChainName),
 		ResolvedOrder: ro,
 	}
 
-	fmt.Printf("%s📜 Open order: OrderID=%s\n", p, parsedArgs.OrderID)
-	fmt.Printf("%s📊 Order details: User=%s\n", p, ro.User)
+	listenerLog.Printf("%s📜 Open order: OrderID=%s\n", p, parsedArgs.OrderID)
+	listenerLog.Printf("%s📊 Order details: User=%s\n", p, ro.User)
 
 	// Just pass to handler, let the solver decide what to do
-	return handler(parsedArgs, l.config.ChainName, ev.Raw.BlockNumber)
+	return l.queue.Dispatch(l.dedupeEvents, handler, parsedArgs, l.config.ChainName, ev.Raw.BlockNumber, ev.Raw.TxHash.Hex(), ev.Raw.Index)
 }
 
 // bytes32ToHexString converts a bytes32 address to a hex string
@@ -2,14 +2,24 @@ package hyperlane7683
 
 import (
 	"context"
+	"errors"
 	"math/big"
+	"path/filepath"
 	"testing"
 
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+var _ EVMClient = (*chainmock.EVMBackend)(nil)
+
 // TestEVMListener tests the EVM listener functionality
 func TestEVMListener(t *testing.T) {
 	t.Run("NewEVMListener_invalid_rpc", func(t *testing.T) {
@@ -173,3 +183,214 @@ func (m *mockEVMListener) Stop() error {
 func (m *mockEVMListener) GetLastProcessedBlock() uint64 {
 	return m.lastProcessedBlock
 }
+
+func (m *mockEVMListener) Snapshot() base.ListenerSnapshot {
+	return base.ListenerSnapshot{LastProcessedBlock: m.lastProcessedBlock}
+}
+
+// TestEVMListenerProcessBlockRange runs the listener against an in-memory chain
+func TestEVMListenerProcessBlockRange(t *testing.T) {
+	settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	backend := chainmock.NewEVMBackend(config.EthereumSepoliaChainID)
+
+	var orderID [32]byte
+	orderID[31] = 0x42
+	backend.AddLog(openLog(t, settler, orderID, 12))
+	backend.AddLog(openLog(t, common.HexToAddress("0x00000000000000000000000000000000000000ff"), [32]byte{0x01}, 13))
+	backend.SetBlockNumber(20)
+
+	listener := &evmListener{
+		config:             &base.ListenerConfig{ChainName: "Ethereum", ContractAddress: settler.Hex()},
+		client:             backend,
+		contractAddress:    settler,
+		lastProcessedBlock: 9,
+		stopChan:           make(chan struct{}),
+	}
+
+	var received []types.ParsedArgs
+	var blocks []uint64
+	handler := func(args types.ParsedArgs, originChainName string, blockNumber uint64) (bool, error) {
+		assert.Equal(t, "Ethereum", originChainName)
+		received = append(received, args)
+		blocks = append(blocks, blockNumber)
+		return true, nil
+	}
+
+	t.Run("Open events are parsed and dispatched", func(t *testing.T) {
+		last, err := listener.processBlockRange(context.Background(), 10, 15, handler)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(15), last)
+
+		require.Len(t, received, 1, "only the settler's event is dispatched")
+		assert.Equal(t, common.BytesToHash(orderID[:]).Hex(), received[0].OrderID)
+		assert.Equal(t, []uint64{12}, blocks)
+		require.Len(t, received[0].ResolvedOrder.MaxSpent, 1)
+		assert.Equal(t, big.NewInt(1000), received[0].ResolvedOrder.MaxSpent[0].Amount)
+		require.Len(t, received[0].ResolvedOrder.FillInstructions, 1)
+		assert.Equal(t, big.NewInt(config.BaseSepoliaChainID), received[0].ResolvedOrder.FillInstructions[0].DestinationChainID)
+	})
+
+	t.Run("FilterLogs error keeps last processed block", func(t *testing.T) {
+		backend.FailNext("FilterLogs", errors.New("rpc down"))
+		last, err := listener.processBlockRange(context.Background(), 16, 20, handler)
+		assert.Error(t, err)
+		assert.Equal(t, uint64(9), last)
+	})
+}
+
+// openLog builds an Open log emitted by settler in the given block
+func openLog(t testing.TB, settler common.Address, orderID [32]byte, blockNumber uint64) gethtypes.Log {
+	t.Helper()
+	return openLogWithDeadline(t, settler, orderID, blockNumber, 0)
+}
+
+// openLogWithDeadline builds an Open log for an order with the given fill deadline
+func openLogWithDeadline(t testing.TB, settler common.Address, orderID [32]byte, blockNumber uint64, fillDeadline uint32) gethtypes.Log {
+	t.Helper()
+	parsedABI, err := contracts.Hyperlane7683MetaData.GetAbi()
+	require.NoError(t, err)
+
+	var token, recipient [32]byte
+	token[31] = 0xa1
+	recipient[31] = 0xcc
+	order := contracts.ResolvedCrossChainOrder{
+		User:          common.HexToAddress("0x00000000000000000000000000000000000000cc"),
+		OriginChainId: big.NewInt(config.EthereumSepoliaChainID),
+		FillDeadline:  fillDeadline,
+		OrderId:       orderID,
+		MaxSpent: []contracts.Output{
+			{Token: token, Amount: big.NewInt(1000), Recipient: recipient, ChainId: big.NewInt(config.BaseSepoliaChainID)},
+		},
+		MinReceived: []contracts.Output{
+			{Token: token, Amount: big.NewInt(1100), Recipient: recipient, ChainId: big.NewInt(config.EthereumSepoliaChainID)},
+		},
+		FillInstructions: []contracts.FillInstruction{
+			{DestinationChainId: big.NewInt(config.BaseSepoliaChainID), DestinationSettler: recipient, OriginData: []byte{0x01}},
+		},
+	}
+	data, err := parsedABI.Events["Open"].Inputs.NonIndexed().Pack(order)
+	require.NoError(t, err)
+
+	return gethtypes.Log{
+		Address:     settler,
+		Topics:      []common.Hash{openEventTopic, common.BytesToHash(orderID[:])},
+		Data:        data,
+		BlockNumber: blockNumber,
+	}
+}
+
+// TestPausedNetworkSkipsPolling tests that a paused network is neither polled nor backfilled
+func TestPausedNetworkSkipsPolling(t *testing.T) {
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+	_, err := config.SetNetworkEnabled("Base", false)
+	require.NoError(t, err)
+
+	polls := 0
+	poll := func() error { polls++; return nil }
+
+	pollWithCircuitBreaker("Base", nil, poll)
+	assert.Zero(t, polls)
+
+	listener := NewBaseListener(base.ListenerConfig{ChainName: "Base", MaxBlockRange: 10}, chainmock.NewEVMBackend(config.BaseSepoliaChainID), "EVM")
+	err = listener.CatchUpHistoricalBlocks(context.Background(), nil, func(context.Context, uint64, uint64, base.EventHandler) (uint64, error) {
+		return 0, errors.New("paused network must not be backfilled")
+	})
+	assert.NoError(t, err)
+
+	_, err = config.SetNetworkEnabled("Base", true)
+	require.NoError(t, err)
+	pollWithCircuitBreaker("Base", nil, poll)
+	assert.Equal(t, 1, polls)
+}
+
+// TestBackfillCheckpoints tests that progress inside a long backfill range is persisted every
+// CHECKPOINT_BLOCKS blocks, so a restart resumes close to where the solver stopped
+func TestBackfillCheckpoints(t *testing.T) {
+	t.Setenv("SOLVER_STATE_FILE", filepath.Join(t.TempDir(), "solver-state.json"))
+	t.Setenv("CHECKPOINT_BLOCKS", "5")
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+
+	settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	backend := chainmock.NewEVMBackend(config.EthereumSepoliaChainID)
+	backend.AddLog(openLog(t, settler, [32]byte{0x01}, 12))
+	backend.AddLog(openLog(t, settler, [32]byte{0x02}, 17))
+	backend.SetBlockNumber(20)
+
+	cfg := &base.ListenerConfig{ChainName: "Ethereum", ContractAddress: settler.Hex(), MaxBlockRange: 1000}
+	baseListener := NewBaseListener(*cfg, backend, "EVM")
+	baseListener.SetLastProcessedBlock(1)
+	listener := &evmListener{
+		config:             cfg,
+		client:             backend,
+		contractAddress:    settler,
+		lastProcessedBlock: 1,
+		stopChan:           make(chan struct{}),
+		baseListener:       baseListener,
+		status:             baseListener.Status(),
+		checkpoint:         baseListener.checkpoint,
+	}
+
+	// Record what a restart would resume from when each order is handled
+	persisted := make(map[uint64]uint64)
+	handler := func(_ types.ParsedArgs, _ string, blockNumber uint64) (bool, error) {
+		state, err := config.GetSolverState()
+		require.NoError(t, err)
+		persisted[blockNumber] = state.Networks["Ethereum"].LastIndexedBlock
+		return true, nil
+	}
+
+	require.NoError(t, listener.catchUpHistoricalBlocks(context.Background(), handler))
+	assert.Equal(t, map[uint64]uint64{12: 11, 17: 16}, persisted)
+	assert.Equal(t, uint64(20), listener.Snapshot().LastProcessedBlock)
+
+	state, err := config.GetSolverState()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20), state.Networks["Ethereum"].LastIndexedBlock)
+}
+
+// TestEVMListenerDeduplicatesEvents tests that processing a block again doesn't dispatch its events twice
+func TestEVMListenerDeduplicatesEvents(t *testing.T) {
+	t.Setenv("SOLVER_STATE_FILE", filepath.Join(t.TempDir(), "solver-state.json"))
+	config.ResetSeenEvents()
+	t.Cleanup(config.ResetSeenEvents)
+
+	settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	backend := chainmock.NewEVMBackend(config.EthereumSepoliaChainID)
+	backend.AddLog(openLog(t, settler, [32]byte{0x01}, 12))
+	backend.SetBlockNumber(20)
+
+	newListener := func(dedupe bool) *evmListener {
+		return &evmListener{
+			config:          &base.ListenerConfig{ChainName: "Ethereum", ContractAddress: settler.Hex()},
+			client:          backend,
+			contractAddress: settler,
+			stopChan:        make(chan struct{}),
+			dedupeEvents:    dedupe,
+		}
+	}
+	handled := 0
+	handler := func(types.ParsedArgs, string, uint64) (bool, error) { handled++; return true, nil }
+
+	listener := newListener(true)
+	_, err := listener.processBlockRange(context.Background(), 10, 15, handler)
+	require.NoError(t, err)
+	_, err = listener.processBlockRange(context.Background(), 12, 12, handler)
+	require.NoError(t, err)
+	assert.Equal(t, 1, handled, "overlapping range is not dispatched again")
+
+	// Also after a restart, the seen events being saved with the checkpoint
+	require.NoError(t, config.UpdateLastIndexedBlock("Ethereum", 15))
+	config.ResetSeenEvents()
+	_, err = newListener(true).processBlockRange(context.Background(), 12, 12, handler)
+	require.NoError(t, err)
+	assert.Equal(t, 1, handled)
+
+	// Replays dispatch every event
+	_, err = newListener(false).processBlockRange(context.Background(), 12, 12, handler)
+	require.NoError(t, err)
+	assert.Equal(t, 2, handled)
+}
@@ -4,40 +4,66 @@ package hyperlane7683
 // - Polls/backfills block ranges on Starknet
 // - Parses Cairo Open events and reconstructs EVM-compatible ResolvedCrossChainOrder
 // - Invokes the filler with parsed args
+// - Reports Filled events to the competition tracker (see competition.go)
+// - Optionally polls right away on WebSocket Open event notifications (see starknet_ws.go)
+// - Event selectors are derived from the event names; on creation the listener checks the router's
+//   class declares those events, so a misconfigured address fails at startup instead of never matching
 // - Persists last processed block via deployment state
 
 import (
 	"bytes"
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"sync"
-	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/client"
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/ethereum/go-ethereum/common"
 
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/rpctimeout"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 )
 
-// Open event topic
-var openEventSelector, _ = utils.HexToFelt("0x35D8BA7F4BF26B6E2E2060E5BD28107042BE35460FBD828C9D29A2D8AF14445")
+// OrderData words in origin_data up to fill_deadline (leading offset word included)
+const orderDataFieldCount = 12
+
+// Router events the listener reads; an event's first key is the selector of its name
+const (
+	openEventName   = "Open"
+	filledEventName = "Filled"
+)
+
+var (
+	openEventSelector   = utils.GetSelectorFromNameFelt(openEventName)
+	filledEventSelector = utils.GetSelectorFromNameFelt(filledEventName)
+)
 
 // starknetListener implements listener.Listener for Starknet chains
 type starknetListener struct {
 	config             *base.ListenerConfig
-	provider           *rpc.Provider
+	provider           rpc.RPCProvider
 	contractAddress    *felt.Felt
 	lastProcessedBlock uint64
 	stopChan           chan struct{}
 	mu                 sync.RWMutex
 	baseListener       *BaseListener
+	status             *ListenerStatus
+	checkpoint         *blockCheckpointer
+	dedupeEvents       bool // skip events already handled (see dispatchOnce); off when replaying
+	fills              *CompetitionTracker
+	queue              *orderQueue
+	pacer              *pollPacer
+	waker              *starknetEventWaker        // wakes polling on WebSocket notifications; nil polls only
+	batch              *logBatch[*rpc.EventChunk] // events of the next backfill ranges (see log_batch.go); nil reads one range at a time
 }
 
 // NewStarknetListener creates a new Starknet listener
@@ -46,13 +72,33 @@ func NewStarknetListener(listenerConfig *base.ListenerConfig, rpcURL string) (ba
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect Starknet RPC: %w", err)
 	}
+	bounded := rpctimeout.NewStarknetProvider(provider, listenerConfig.ChainName)
+	if batchClient, err := client.DialContext(context.Background(), rpcURL); err == nil {
+		bounded.SetBatchCaller(batchClient)
+	}
+	return NewStarknetListenerWithProvider(listenerConfig, bounded)
+}
+
+// NewStarknetListenerWithProvider creates a Starknet listener on an existing provider, e.g. the one shared with the solver
+func NewStarknetListenerWithProvider(listenerConfig *base.ListenerConfig, provider rpc.RPCProvider) (base.Listener, error) {
+	listener, err := newStarknetListenerWithProvider(listenerConfig, provider)
+	if err != nil {
+		return nil, err
+	}
+	return listener, nil
+}
 
+// newStarknetListenerWithProvider creates a Starknet listener on an existing provider
+func newStarknetListenerWithProvider(listenerConfig *base.ListenerConfig, provider rpc.RPCProvider) (*starknetListener, error) {
 	addrFelt, err := types.ToStarknetAddress(listenerConfig.ContractAddress)
 	if err != nil {
 		return nil, fmt.Errorf("invalid Starknet contract address: %w", err)
 	}
 
 	ctx := context.Background()
+	if err := verifyStarknetRouterEvents(ctx, provider, addrFelt); err != nil {
+		return nil, err
+	}
 	commonConfig, err := ResolveCommonListenerConfig(ctx, listenerConfig, provider)
 	if err != nil {
 		return nil, err
@@ -60,8 +106,8 @@ func NewStarknetListener(listenerConfig *base.ListenerConfig, rpcURL string) (ba
 
 	baseListener := NewBaseListener(*listenerConfig, provider, "Starknet")
 	baseListener.SetLastProcessedBlock(commonConfig.LastProcessedBlock)
-	
-	return &starknetListener{
+
+	listener := &starknetListener{
 		config:             listenerConfig,
 		provider:           provider,
 		contractAddress:    addrFelt,
@@ -69,7 +115,33 @@ func NewStarknetListener(listenerConfig *base.ListenerConfig, rpcURL string) (ba
 		stopChan:           make(chan struct{}),
 		mu:                 sync.RWMutex{},
 		baseListener:       baseListener,
-	}, nil
+		status:             baseListener.Status(),
+		checkpoint:         baseListener.checkpoint,
+		dedupeEvents:       true,
+		fills:              DefaultCompetitionTracker(),
+		queue:              baseListener.queue,
+		pacer:              baseListener.pacer,
+		waker:              starknetEventWakerFromEnv(listenerConfig.ChainName, addrFelt),
+	}
+	if batch := newStarknetEventBatch(listener); batch != nil {
+		listener.batch = batch
+		baseListener.prefetch = batch
+	}
+	return listener, nil
+}
+
+// verifyStarknetRouterEvents checks the class deployed at router declares the events the listener filters on
+func verifyStarknetRouterEvents(ctx context.Context, provider rpc.RPCProvider, router *felt.Felt) error {
+	abi, err := starknetutil.NewClient(provider, nil).ClassABI(ctx, router)
+	if err != nil {
+		return fmt.Errorf("cannot read the events of Starknet router %s: %w", router.String(), err)
+	}
+	for _, event := range []string{openEventName, filledEventName} {
+		if !abi.HasEvent(event) {
+			return fmt.Errorf("Starknet router %s does not declare the %s event; is it a Hyperlane7683 router?", router.String(), event)
+		}
+	}
+	return nil
 }
 
 // Start begins listening for events
@@ -91,22 +163,28 @@ func (l *starknetListener) GetLastProcessedBlock() uint64 {
 	return l.lastProcessedBlock
 }
 
+// Snapshot returns the listener's block progress; it doesn't wait for an in-flight poll
+func (l *starknetListener) Snapshot() base.ListenerSnapshot {
+	return l.status.Snapshot()
+}
+
 // MarkBlockFullyProcessed marks a block as fully processed
 func (l *starknetListener) MarkBlockFullyProcessed(blockNumber uint64) error {
 	if blockNumber != l.lastProcessedBlock+1 {
 		return fmt.Errorf("cannot mark block %d as processed, expected %d", blockNumber, l.lastProcessedBlock+1)
 	}
 	l.lastProcessedBlock = blockNumber
-	fmt.Printf("%s✅ block %d processed\n", logutil.Prefix(l.config.ChainName), blockNumber)
+	listenerLog.Printf("%s✅ block %d processed\n", logutil.Prefix(l.config.ChainName), blockNumber)
 	return nil
 }
 
 func (l *starknetListener) startEventLoop(ctx context.Context, handler base.EventHandler) {
 	p := logutil.Prefix(l.config.ChainName)
 	if err := l.catchUpHistoricalBlocks(ctx, handler); err != nil {
-		fmt.Printf("%s❌ backfill failed: %v\n", p, err)
+		listenerLog.Printf("%s❌ backfill failed: %v\n", p, err)
 	}
-	fmt.Printf("%s🔄 backfill complete\n", p)
+	listenerLog.Printf("%s🔄 backfill complete\n", p)
+	go l.waker.Run(ctx, l.stopChan)
 	l.startPolling(ctx, handler)
 }
 
@@ -115,20 +193,20 @@ func (l *starknetListener) catchUpHistoricalBlocks(ctx context.Context, handler
 }
 
 func (l *starknetListener) startPolling(ctx context.Context, handler base.EventHandler) {
-	fmt.Printf("%s📭 Starting event polling...\n", logutil.Prefix(l.config.ChainName))
+	listenerLog.Printf("%s📭 Starting event polling...\n", logutil.Prefix(l.config.ChainName))
 	for {
 		select {
 		case <-ctx.Done():
-			fmt.Printf("🔄 Context canceled, stopping event polling\n")
+			listenerLog.Printf("🔄 Context canceled, stopping event polling\n")
 			return
 		case <-l.stopChan:
-			fmt.Printf("🔄 Stop signal received, stopping event polling\n")
+			listenerLog.Printf("🔄 Stop signal received, stopping event polling\n")
 			return
 		default:
-			if err := l.processCurrentBlockRange(ctx, handler); err != nil {
-				fmt.Printf("%s❌ Failed to process current block range: %v\n", logutil.Prefix(l.config.ChainName), err)
-			}
-			time.Sleep(time.Duration(l.config.PollInterval) * time.Millisecond)
+			pollWithCircuitBreaker(l.config.ChainName, l.status, func() error {
+				return l.processCurrentBlockRange(ctx, handler)
+			})
+			l.waker.Wait(ctx, l.stopChan, l.pacer.Next(pollInterval(l.config), l.status.Snapshot()))
 		}
 	}
 }
@@ -138,134 +216,210 @@ func (l *starknetListener) startPolling(ctx context.Context, handler base.EventH
 func (l *starknetListener) processCurrentBlockRange(ctx context.Context, handler base.EventHandler) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	return ProcessCurrentBlockRangeCommon(ctx, handler, l.provider, l.config, &l.lastProcessedBlock, "Starknet", l.processBlockRange)
+	return ProcessCurrentBlockRangeCommon(ctx, handler, l.provider, l.config, &l.lastProcessedBlock, l.status, "Starknet", l.processBlockRange)
 }
 
 // processBlockRange processes events in [fromBlock, toBlock] and returns the highest contiguous block fully processed
 func (l *starknetListener) processBlockRange(ctx context.Context, fromBlock, toBlock uint64, handler base.EventHandler) (uint64, error) {
 	if fromBlock > toBlock {
-		fmt.Printf("⚠️  Invalid block range (%s) in processBlockRange: fromBlock (%d) > toBlock (%d), skipping\n", l.config.ChainName, fromBlock, toBlock)
+		listenerLog.Printf("⚠️  Invalid block range (%s) in processBlockRange: fromBlock (%d) > toBlock (%d), skipping\n", l.config.ChainName, fromBlock, toBlock)
 		return l.lastProcessedBlock, nil
 	}
 
-	// Fetch events for the block range
-	filter := rpc.EventFilter{
-		FromBlock: rpc.BlockID{
-			Number: &fromBlock,
-			Hash:   nil,
-			Tag:    "",
-		},
-		ToBlock: rpc.BlockID{
-			Number: &toBlock,
-			Hash:   nil,
-			Tag:    "",
-		},
-		Address: l.contractAddress,
-		Keys:    [][]*felt.Felt{{openEventSelector}},
-	}
-
-	query := rpc.EventsInput{
-		EventFilter:       filter,
-		ResultPageRequest: rpc.ResultPageRequest{ChunkSize: 128, ContinuationToken: ""},
-	}
-
-	logs, err := l.provider.Events(ctx, query)
-	if err != nil {
-		return l.lastProcessedBlock, fmt.Errorf("failed to filter events: %w", err)
+	// Fetch events for the block range, unless they were read in the last backfill batch
+	logs, prefetched := l.batch.Take(blockRange{from: fromBlock, to: toBlock})
+	if !prefetched {
+		var err error
+		logs, err = l.provider.Events(ctx, l.eventsQuery(fromBlock, toBlock, ""))
+		if err != nil {
+			return l.lastProcessedBlock, fmt.Errorf("failed to filter events: %w", err)
+		}
 	}
 
-	logutil.LogWithNetworkTagf(l.config.ChainName, "📩 events found: %d\n", len(logs.Events))
-	if len(logs.Events) > 0 {
-		fmt.Printf("📩 Found %d Open events on %s\n", len(logs.Events), l.config.ChainName)
-	}
+	logutil.LogBlockProcessing(l.config.ChainName, fromBlock, toBlock, len(logs.Events))
 
 	// Group logs by block
 	byBlock := make(map[uint64][]rpc.EmittedEvent)
+	opens := 0
 	for _, event := range logs.Events {
 		byBlock[event.BlockNumber] = append(byBlock[event.BlockNumber], event)
+		if hasEventSelector(event, openEventSelector) {
+			opens++
+		}
 	}
+	l.status.RecordEvents(opens)
 
 	// Process blocks in order
 	newLast := l.lastProcessedBlock
 	for b := fromBlock; b <= toBlock; b++ {
 		events := byBlock[b]
+		// Starknet events carry no log index; number them per transaction in the order returned
+		txEventIndex := make(map[string]uint)
 
 		// Process each event in this block
 		for _, event := range events {
-			// Ensure each event is the correct type
-			isOpen := false
-			if len(event.Event.Keys) >= 1 {
-				actual := event.Event.Keys[0].Bytes()
-				expected := openEventSelector.Bytes()
-				if bytes.Equal(actual[:], expected[:]) {
-					isOpen = true
+			// Filled events only feed the competition tracker
+			if hasEventSelector(event, filledEventSelector) {
+				orderID, derr := decodeFilledOrderID(event.Event.Data)
+				if derr != nil {
+					listenerLog.Printf("❌ Failed to decode Filled event: %v\n", derr)
+					continue
 				}
+				l.fills.RecordFill(l.config.ChainName, orderID, event.TransactionHash.String(), b)
+				continue
 			}
-			if !isOpen {
+
+			// Ensure each event is the correct type
+			if !hasEventSelector(event, openEventSelector) {
 				continue
 			}
 
 			// Parse Open event
-			ro := decodeResolvedOrderFromFelts(event.Event.Data)
+			ro, derr := decodeResolvedOrderFromFelts(event.Event.Data)
+			if derr != nil {
+				listenerLog.Printf("❌ Failed to decode Open event: %v\n", derr)
+				continue
+			}
 			parsedArgs := types.ParsedArgs{
 				OrderID:       common.BytesToHash(ro.OrderID[:]).Hex(),
 				SenderAddress: ro.User,
-				Recipients:    []types.Recipient{{DestinationChainName: l.config.ChainName, RecipientAddress: "*"}},
+				Recipients:    orderRecipients(ro, l.config.ChainName),
 				ResolvedOrder: ro,
 			}
 
 			// Handle the event
-			_, herr := handler(parsedArgs, l.config.ChainName, b)
+			txHash := event.TransactionHash.String()
+			_, herr := l.queue.Dispatch(l.dedupeEvents, handler, parsedArgs, l.config.ChainName, b, txHash, txEventIndex[txHash])
+			txEventIndex[txHash]++
 			if herr != nil {
-				fmt.Printf("❌ Failed to handle event: %v\n", herr)
+				listenerLog.Printf("❌ Failed to handle event: %v\n", herr)
 				continue
 			}
 		}
 
 		// Mark block as processed
 		newLast = b
+		l.status.RecordProcessed(b)
+		l.checkpoint.Advance(b)
 		// Only log individual blocks if there are events
 		if len(events) > 0 {
-			logutil.LogWithNetworkTagf(l.config.ChainName, "   ✅ Block %d processed: %d events\n", b, len(events))
+			listenerLog.WithNetworkTagf(l.config.ChainName, "   ✅ Block %d processed: %d events\n", b, len(events))
 		}
 	}
 
 	return newLast, nil
 }
 
+// eventsQuery returns the page of the listener's events in [fromBlock, toBlock] starting at continuationToken
+func (l *starknetListener) eventsQuery(fromBlock, toBlock uint64, continuationToken string) rpc.EventsInput {
+	return rpc.EventsInput{
+		EventFilter: rpc.EventFilter{
+			FromBlock: rpc.BlockID{Number: &fromBlock},
+			ToBlock:   rpc.BlockID{Number: &toBlock},
+			Address:   l.contractAddress,
+			Keys:      [][]*felt.Felt{{openEventSelector, filledEventSelector}},
+		},
+		ResultPageRequest: rpc.ResultPageRequest{ChunkSize: 128, ContinuationToken: continuationToken},
+	}
+}
+
+// hasEventSelector reports whether the event's first key is selector
+func hasEventSelector(event rpc.EmittedEvent, selector *felt.Felt) bool {
+	if len(event.Event.Keys) < 1 {
+		return false
+	}
+	actual := event.Event.Keys[0].Bytes()
+	expected := selector.Bytes()
+	return bytes.Equal(actual[:], expected[:])
+}
+
+// StarknetOpenedOrderID returns the order ID of the Open event router emitted among a transaction's events
+func StarknetOpenedOrderID(events []rpc.Event, router *felt.Felt) (string, error) {
+	for _, event := range events {
+		if event.FromAddress == nil || !event.FromAddress.Equal(router) || len(event.Keys) < 1 || !event.Keys[0].Equal(openEventSelector) {
+			continue
+		}
+		ro, err := decodeResolvedOrderFromFelts(event.Data)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode Open event: %w", err)
+		}
+		return common.BytesToHash(ro.OrderID[:]).Hex(), nil
+	}
+	return "", fmt.Errorf("no Open event from %s", router.String())
+}
+
 // --- Decoders ---
 
-func decodeResolvedOrderFromFelts(data []*felt.Felt) types.ResolvedCrossChainOrder {
+// Decoding errors; every error returned by the decoder wraps one of these with the field it hit
+var (
+	errEventDataTruncated = errors.New("event data truncated")
+	errEventDataInvalid   = errors.New("invalid event data")
+)
+
+func decodeResolvedOrderFromFelts(data []*felt.Felt) (types.ResolvedCrossChainOrder, error) {
 	decoder := newFeltDecoder(data)
-	
-	ro := types.ResolvedCrossChainOrder{
-		User:             "",
-		OriginChainID:    nil,
-		OpenDeadline:     0,
-		FillDeadline:     0,
-		OrderID:          [32]byte{},
-		MaxSpent:         nil,
-		MinReceived:      nil,
-		FillInstructions: nil,
-	}
-	ro.User = decoder.readAddress()
-	ro.OriginChainID = new(big.Int).SetUint64(uint64(decoder.readU32()))
-	ro.OpenDeadline = uint32(decoder.readU64())
-	ro.FillDeadline = uint32(decoder.readU64())
-
-	orderID := decoder.readU256()
+
+	user, err := decoder.readAddress("user")
+	if err != nil {
+		return types.ResolvedCrossChainOrder{}, err
+	}
+	originChainID, err := decoder.readU32("origin_chain_id")
+	if err != nil {
+		return types.ResolvedCrossChainOrder{}, err
+	}
+	openDeadline, err := decoder.readU64("open_deadline")
+	if err != nil {
+		return types.ResolvedCrossChainOrder{}, err
+	}
+	fillDeadline, err := decoder.readU64("fill_deadline")
+	if err != nil {
+		return types.ResolvedCrossChainOrder{}, err
+	}
+	orderID, err := decoder.readU256("order_id")
+	if err != nil {
+		return types.ResolvedCrossChainOrder{}, err
+	}
+	maxSpent, err := decoder.readOutputs("max_spent")
+	if err != nil {
+		return types.ResolvedCrossChainOrder{}, err
+	}
+	minReceived, err := decoder.readOutputs("min_received")
+	if err != nil {
+		return types.ResolvedCrossChainOrder{}, err
+	}
+	fillInstructions, err := decoder.readFillInstructions("fill_instructions")
+	if err != nil {
+		return types.ResolvedCrossChainOrder{}, err
+	}
+
 	var orderArr [32]byte
-	orderBytes := orderID.Bytes()
-	copy(orderArr[32-len(orderBytes):], orderBytes)
+	orderID.FillBytes(orderArr[:])
+
+	return types.ResolvedCrossChainOrder{
+		User:             user,
+		OriginChainID:    new(big.Int).SetUint64(uint64(originChainID)),
+		OpenDeadline:     uint32(openDeadline),
+		FillDeadline:     uint32(fillDeadline),
+		OrderID:          orderArr,
+		MaxSpent:         maxSpent,
+		MinReceived:      minReceived,
+		FillInstructions: fillInstructions,
+	}, nil
+}
 
-	ro.OrderID = orderArr
-	ro.MaxSpent = decoder.readOutputs()
-	ro.MinReceived = decoder.readOutputs()
-	ro.FillInstructions = decoder.readFillInstructions()
-	return ro
+// decodeFilledOrderID reads the order ID of a Filled event, the first field of its data
+func decodeFilledOrderID(data []*felt.Felt) (string, error) {
+	orderID, err := newFeltDecoder(data).readU256("order_id")
+	if err != nil {
+		return "", err
+	}
+	var orderArr [32]byte
+	orderID.FillBytes(orderArr[:])
+	return common.BytesToHash(orderArr[:]).Hex(), nil
 }
 
-// feltDecoder handles decoding of felt data
+// feltDecoder reads Cairo-serialized values from event data in order.
+// Every read checks the remaining length and names the field in its error.
 type feltDecoder struct {
 	data []*felt.Felt
 	idx  int
@@ -275,160 +429,280 @@ func newFeltDecoder(data []*felt.Felt) *feltDecoder {
 	return &feltDecoder{data: data, idx: 0}
 }
 
-func (d *feltDecoder) readFelt() *felt.Felt {
+// remaining returns the number of unread felts
+func (d *feltDecoder) remaining() int {
+	return len(d.data) - d.idx
+}
+
+func (d *feltDecoder) readFelt(field string) (*felt.Felt, error) {
+	if d.remaining() < 1 {
+		return nil, fmt.Errorf("%s: %w: need felt %d, have %d", field, errEventDataTruncated, d.idx+1, len(d.data))
+	}
 	f := d.data[d.idx]
+	if f == nil {
+		return nil, fmt.Errorf("%s: %w: felt %d is nil", field, errEventDataInvalid, d.idx)
+	}
 	d.idx++
-	return f
+	return f, nil
+}
+
+// readUint reads a felt that must fit in bits bits
+func (d *feltDecoder) readUint(field string, bits int) (*big.Int, error) {
+	f, err := d.readFelt(field)
+	if err != nil {
+		return nil, err
+	}
+	bi := utils.FeltToBigInt(f)
+	if bi.BitLen() > bits {
+		return nil, fmt.Errorf("%s: %w: %s does not fit in u%d", field, errEventDataInvalid, bi, bits)
+	}
+	return bi, nil
+}
+
+func (d *feltDecoder) readU32(field string) (uint32, error) {
+	bi, err := d.readUint(field, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(bi.Uint64()), nil
+}
+
+func (d *feltDecoder) readU64(field string) (uint64, error) {
+	bi, err := d.readUint(field, 64)
+	if err != nil {
+		return 0, err
+	}
+	return bi.Uint64(), nil
 }
 
-func (d *feltDecoder) readU32() uint32 {
-	bi := utils.FeltToBigInt(d.readFelt())
-	return uint32(bi.Uint64())
+// readU256 reads a u256 serialized as low and high u128 felts
+func (d *feltDecoder) readU256(field string) (*big.Int, error) {
+	if d.remaining() < 2 {
+		return nil, fmt.Errorf("%s: %w: u256 needs 2 felts, have %d", field, errEventDataTruncated, max(d.remaining(), 0))
+	}
+	low, err := d.readU128Felt(field + ".low")
+	if err != nil {
+		return nil, err
+	}
+	high, err := d.readU128Felt(field + ".high")
+	if err != nil {
+		return nil, err
+	}
+	return starknetutil.U256FromFelts(low, high), nil
 }
 
-func (d *feltDecoder) readU64() uint64 {
-	bi := utils.FeltToBigInt(d.readFelt())
-	return bi.Uint64()
+// readU128Felt reads a felt that must fit in a u128, returning the felt itself
+func (d *feltDecoder) readU128Felt(field string) (*felt.Felt, error) {
+	f, err := d.readFelt(field)
+	if err != nil {
+		return nil, err
+	}
+	if bi := utils.FeltToBigInt(f); bi.BitLen() > 128 {
+		return nil, fmt.Errorf("%s: %w: %s does not fit in u128", field, errEventDataInvalid, bi)
+	}
+	return f, nil
 }
 
-func (d *feltDecoder) readU256() *big.Int {
-	low := utils.FeltToBigInt(d.readFelt())
-	high := utils.FeltToBigInt(d.readFelt())
-	return new(big.Int).Add(low, new(big.Int).Lsh(high, 128))
+// readAddress reads a felt as a 0x-prefixed bytes32 hex string
+func (d *feltDecoder) readAddress(field string) (string, error) {
+	f, err := d.readFelt(field)
+	if err != nil {
+		return "", err
+	}
+	feltBytes := f.Bytes()
+	return "0x" + hex.EncodeToString(feltBytes[:]), nil
 }
 
-func (d *feltDecoder) readAddress() string {
-	feltBytes := d.readFelt().Bytes()
-	// Convert to slice to handle consistently
-	b := feltBytes[:]
-	// Pad to 32 bytes if shorter (for consistent bytes32 format)
-	if len(b) < 32 {
-		padded := make([]byte, 32)
-		copy(padded[32-len(b):], b)
-		return "0x" + hex.EncodeToString(padded)
+// readLength reads an array length; each element takes at least one felt, so a length
+// beyond the remaining data is malformed
+func (d *feltDecoder) readLength(field string) (uint64, error) {
+	length, err := d.readUint(field+".length", 64)
+	if err != nil {
+		return 0, err
 	}
-	return "0x" + hex.EncodeToString(b)
+	if length.Uint64() > uint64(d.remaining()) {
+		return 0, fmt.Errorf("%s: %w: length %d with %d felts left", field, errEventDataTruncated, length.Uint64(), d.remaining())
+	}
+	return length.Uint64(), nil
 }
 
-func (d *feltDecoder) readOutput() types.Output {
-	out := types.Output{
-		Token:    "",
-		Amount:   nil,
-		Recipient: "",
-		ChainID:  nil,
+func (d *feltDecoder) readOutput(field string) (types.Output, error) {
+	token, err := d.readAddress(field + ".token")
+	if err != nil {
+		return types.Output{}, err
+	}
+	amount, err := d.readU256(field + ".amount")
+	if err != nil {
+		return types.Output{}, err
+	}
+	recipient, err := d.readAddress(field + ".recipient")
+	if err != nil {
+		return types.Output{}, err
+	}
+	chainDomain, err := d.readU32(field + ".chain_id")
+	if err != nil {
+		return types.Output{}, err
 	}
-	out.Token = d.readAddress()
-	out.Amount = d.readU256()
-	out.Recipient = d.readAddress()
-	chainDomain := d.readU32()
-	// Map domain to actual chain ID using config
-	if chainID, err := domainToChainID(chainDomain); err == nil {
-		out.ChainID = chainID
-	} else {
-		fmt.Printf("   ⚠️  Warning: Could not map domain %d to chain ID for output, using domain as chain ID\n", chainDomain)
-		out.ChainID = new(big.Int).SetUint64(uint64(chainDomain))
+	// Map domain to actual chain ID using the domain registry
+	chainID, err := domainToChainID(chainDomain)
+	if err != nil {
+		return types.Output{}, fmt.Errorf("%s.chain_id: %w", field, err)
 	}
-	return out
+	return types.Output{Token: token, Amount: amount, Recipient: recipient, ChainID: chainID}, nil
 }
 
-func (d *feltDecoder) readOutputs() []types.Output {
-	length := utils.FeltToBigInt(d.readFelt()).Uint64()
+func (d *feltDecoder) readOutputs(field string) ([]types.Output, error) {
+	length, err := d.readLength(field)
+	if err != nil {
+		return nil, err
+	}
 	outs := make([]types.Output, 0, length)
 	for i := uint64(0); i < length; i++ {
-		outs = append(outs, d.readOutput())
+		out, err := d.readOutput(fmt.Sprintf("%s[%d]", field, i))
+		if err != nil {
+			return nil, err
+		}
+		outs = append(outs, out)
 	}
-	return outs
+	return outs, nil
 }
 
-func (d *feltDecoder) readFillInstruction() types.FillInstruction {
-	fi := types.FillInstruction{
-		DestinationChainID:  nil,
-		DestinationSettler:  "",
-		OriginData:          nil,
+func (d *feltDecoder) readFillInstruction(field string) (types.FillInstruction, error) {
+	destinationDomain, err := d.readU32(field + ".destination_chain_id")
+	if err != nil {
+		return types.FillInstruction{}, err
 	}
-	destinationDomain := d.readU32()
-	// Map destination domain to actual chain ID using config
-	if chainID, err := domainToChainID(destinationDomain); err == nil {
-		fi.DestinationChainID = chainID
-	} else {
-		fmt.Printf("   ⚠️  Warning: Could not map domain %d to chain ID, using domain as chain ID\n", destinationDomain)
-		fi.DestinationChainID = new(big.Int).SetUint64(uint64(destinationDomain))
+	// Map destination domain to actual chain ID using the domain registry
+	chainID, err := domainToChainID(destinationDomain)
+	if err != nil {
+		return types.FillInstruction{}, fmt.Errorf("%s.destination_chain_id: %w", field, err)
+	}
+	settler, err := d.readAddress(field + ".destination_settler")
+	if err != nil {
+		return types.FillInstruction{}, err
 	}
-	fi.DestinationSettler = d.readAddress()
-
 	// Parse the origin_data bytes (OrderData struct) from the event data
-	fi.OriginData = d.parseOriginData()
-	return fi
-}
-
-func (d *feltDecoder) parseOriginData() []byte {
-	// Read size and u128 array length from the event data (absolute indices)
-	size := utils.FeltToBigInt(d.data[21]).Uint64()
-	u128ArrayLength := utils.FeltToBigInt(d.data[22]).Uint64()
-	_ = size
-	_ = u128ArrayLength
-
-	// Parse each bytes32 field from the u128 array
-	orderDataFields := make([][]byte, 0)
-	for i := uint64(0); i < u128ArrayLength && (23+int(i)+1) < len(d.data); i += 2 {
-		// Read two u128 felts and combine into bytes32
-		lowFelt := d.data[23+int(i)]
-		highFelt := d.data[23+int(i)+1]
-		lowBytes := lowFelt.Bytes()
-		highBytes := highFelt.Bytes()
-		lowU128 := lowBytes[16:]
-		highU128 := highBytes[16:]
-		bytes32 := make([]byte, 32)
-		copy(bytes32[0:16], lowU128)
-		copy(bytes32[16:32], highU128)
-		orderDataFields = append(orderDataFields, bytes32)
-	}
-
-	// Build EVM origin_data bytes (ABI-compatible, 448 bytes total)
-	evmOriginData := make([]byte, 0, evmOriginDataSize)
-	firstWord := make([]byte, 32)
-	firstWord[31] = 0x20
-	evmOriginData = append(evmOriginData, firstWord...)
-	evmOriginData = append(evmOriginData, orderDataFields[1]...)  // sender
-	evmOriginData = append(evmOriginData, orderDataFields[2]...)  // recipient
-	evmOriginData = append(evmOriginData, orderDataFields[3]...)  // input_token
-	evmOriginData = append(evmOriginData, orderDataFields[4]...)  // output_token
-	evmOriginData = append(evmOriginData, orderDataFields[5]...)  // amount_in
-	evmOriginData = append(evmOriginData, orderDataFields[6]...)  // amount_out
-	evmOriginData = append(evmOriginData, orderDataFields[7]...)  // sender_nonce
-	evmOriginData = append(evmOriginData, orderDataFields[8]...)  // origin_domain
-	evmOriginData = append(evmOriginData, orderDataFields[9]...)  // destination_domain
-	evmOriginData = append(evmOriginData, orderDataFields[10]...) // destination_settler
-	evmOriginData = append(evmOriginData, orderDataFields[11]...) // fill_deadline
-	dataOffset := make([]byte, 32)
-	dataOffset[31] = 0x80
-	dataOffset[30] = 0x01
-	evmOriginData = append(evmOriginData, dataOffset...)
-	dataSize := make([]byte, 32)
-	dataSize[31] = 0x00
-	evmOriginData = append(evmOriginData, dataSize...)
-	if len(evmOriginData) != evmOriginDataSize {
-		fmt.Printf("   ⚠️  origin_data unexpected length: %d\n", len(evmOriginData))
-	}
-	return evmOriginData
-}
-
-func (d *feltDecoder) readFillInstructions() []types.FillInstruction {
-	length := utils.FeltToBigInt(d.readFelt()).Uint64()
+	originData, err := d.parseOriginData(field + ".origin_data")
+	if err != nil {
+		return types.FillInstruction{}, err
+	}
+	return types.FillInstruction{DestinationChainID: chainID, DestinationSettler: settler, OriginData: originData}, nil
+}
+
+// parseOriginData reads origin_data, the Cairo OrderEncoder::encode(OrderData) serialized as Bytes,
+// and re-encodes it as Solidity's abi.encode(OrderData) for EVM fills, data payload included
+func (d *feltDecoder) parseOriginData(field string) ([]byte, error) {
+	// origin_data is serialized as Bytes: size in bytes, then the u128 array (length + words)
+	size, err := d.readU32(field + ".size")
+	if err != nil {
+		return nil, err
+	}
+	u128ArrayLength, err := d.readLength(field)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(size) > u128ArrayLength*16 {
+		return nil, fmt.Errorf("%s: %w: size %d exceeds %d u128 words", field, errEventDataInvalid, size, u128ArrayLength)
+	}
+
+	// Words hold 16 bytes each, big-endian; the last one is zero-padded past size
+	raw := make([]byte, u128ArrayLength*16)
+	for i := uint64(0); i < u128ArrayLength; i++ {
+		word, err := d.readUint(fmt.Sprintf("%s[%d]", field, i), 128)
+		if err != nil {
+			return nil, err
+		}
+		word.FillBytes(raw[i*16 : (i+1)*16])
+	}
+	raw = raw[:size]
+
+	if len(raw) < orderDataFieldCount*32 {
+		return nil, fmt.Errorf("%s: %w: %d OrderData fields, want at least %d", field, errEventDataInvalid, len(raw)/32, orderDataFieldCount)
+	}
+	data, err := cairoOrderDataPayload(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", field, err)
+	}
+	// The leading offset word is followed by the 11 static fields, sender to fill_deadline
+	return encodeEVMOriginData(raw[32:orderDataFieldCount*32], data), nil
+}
+
+// cairoOrderDataPayload returns the data field of a Cairo-encoded OrderData. Its offset word,
+// relative to the struct after the leading offset word, points at the payload size followed by the
+// payload, which Cairo does not pad. An encoding ending after fill_deadline has no payload.
+func cairoOrderDataPayload(raw []byte) ([]byte, error) {
+	head := orderDataFieldCount * 32
+	if len(raw) == head {
+		return nil, nil
+	}
+	if len(raw) < head+32 {
+		return nil, fmt.Errorf("%w: data offset needs 32 bytes, have %d", errEventDataInvalid, len(raw)-head)
+	}
+	offset := new(big.Int).SetBytes(raw[head : head+32])
+	if !offset.IsUint64() || offset.Uint64() < uint64(head) || offset.Uint64()+64 > uint64(len(raw)) {
+		return nil, fmt.Errorf("%w: data offset %s outside the %d bytes of OrderData", errEventDataInvalid, offset, len(raw))
+	}
+	sizeAt := 32 + offset.Uint64()
+	size := new(big.Int).SetBytes(raw[sizeAt : sizeAt+32])
+	left := uint64(len(raw)) - sizeAt - 32
+	if !size.IsUint64() || size.Uint64() > left {
+		return nil, fmt.Errorf("%w: data size %s exceeds the %d bytes after it", errEventDataInvalid, size, left)
+	}
+	return raw[sizeAt+32 : sizeAt+32+size.Uint64()], nil
+}
+
+// encodeEVMOriginData builds abi.encode(OrderData) from the 11 static OrderData words and the data
+// payload: the tuple offset, the static words, the data offset, then the payload length and bytes
+// padded to a 32-byte boundary
+func encodeEVMOriginData(staticFields, data []byte) []byte {
+	padded := (len(data) + 31) / 32 * 32
+	encoded := make([]byte, 0, evmOriginDataSize+padded)
+	encoded = append(encoded, common.BigToHash(big.NewInt(0x20)).Bytes()...)
+	encoded = append(encoded, staticFields...)
+	encoded = append(encoded, common.BigToHash(big.NewInt(int64(orderDataFieldCount)*32)).Bytes()...)
+	encoded = append(encoded, common.BigToHash(big.NewInt(int64(len(data)))).Bytes()...)
+	encoded = append(encoded, data...)
+	return append(encoded, make([]byte, padded-len(data))...)
+}
+
+func (d *feltDecoder) readFillInstructions(field string) ([]types.FillInstruction, error) {
+	length, err := d.readLength(field)
+	if err != nil {
+		return nil, err
+	}
 	arr := make([]types.FillInstruction, 0, length)
 	for i := uint64(0); i < length; i++ {
-		arr = append(arr, d.readFillInstruction())
+		fi, err := d.readFillInstruction(fmt.Sprintf("%s[%d]", field, i))
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, fi)
 	}
-	return arr
+	return arr, nil
 }
 
 // domainToChainID maps a Hyperlane domain ID to its corresponding chain ID
 func domainToChainID(domain uint32) (*big.Int, error) {
-	// Search through all networks to find the one with matching HyperlaneDomain
-	for _, network := range config.Networks {
-		if network.HyperlaneDomain == uint64(domain) {
-			return big.NewInt(int64(network.ChainID)), nil
-		}
+	registry, err := config.GetDomainRegistry()
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := registry.ChainIDForDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetUint64(chainID), nil
+}
+
+// originDomainForChainID maps an order's origin chain ID to its Hyperlane domain
+func originDomainForChainID(chainID uint64) (uint32, error) {
+	registry, err := config.GetDomainRegistry()
+	if err != nil {
+		return 0, err
+	}
+	domain, err := registry.DomainForChainID(chainID)
+	if err != nil {
+		return 0, fmt.Errorf("no domain for origin chain (check your .env file): %w", err)
 	}
-	return nil, fmt.Errorf("no chain found for domain %d", domain)
+	return domain, nil
 }
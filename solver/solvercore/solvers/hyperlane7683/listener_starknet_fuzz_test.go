@@ -0,0 +1,52 @@
+package hyperlane7683
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// fuzzFeltWidth is the number of fuzz input bytes per felt; small felts keep lengths and
+// domains in a range where the decoder gets past the headers
+const fuzzFeltWidth = 4
+
+// feltsFromFuzz splits fuzz input into big-endian felts of fuzzFeltWidth bytes
+func feltsFromFuzz(input []byte) []*felt.Felt {
+	felts := make([]*felt.Felt, 0, len(input)/fuzzFeltWidth)
+	for i := 0; i+fuzzFeltWidth <= len(input); i += fuzzFeltWidth {
+		felts = append(felts, new(felt.Felt).SetUint64(uint64(binary.BigEndian.Uint32(input[i:]))))
+	}
+	return felts
+}
+
+// fuzzFromFelts is the inverse of feltsFromFuzz for felts below 2^32
+func fuzzFromFelts(felts []*felt.Felt) []byte {
+	input := make([]byte, 0, len(felts)*fuzzFeltWidth)
+	for _, f := range felts {
+		input = binary.BigEndian.AppendUint32(input, uint32(f.Uint64()))
+	}
+	return input
+}
+
+// FuzzDecodeResolvedOrderFromFelts checks that malformed Open event data is rejected, never panics
+func FuzzDecodeResolvedOrderFromFelts(f *testing.F) {
+	valid := openEventFelts()
+	f.Add(fuzzFromFelts(valid))
+	f.Add(fuzzFromFelts(valid[:21]))
+	f.Add(fuzzFromFelts(openEventFeltsWithData([]byte("hello"))))
+	f.Add([]byte{})
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff})
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		order, err := decodeResolvedOrderFromFelts(feltsFromFuzz(input))
+		if err != nil {
+			return
+		}
+		for _, instruction := range order.FillInstructions {
+			if len(instruction.OriginData) < evmOriginDataSize || len(instruction.OriginData)%32 != 0 {
+				t.Fatalf("decoded origin_data has %d bytes, want %d plus whole words", len(instruction.OriginData), evmOriginDataSize)
+			}
+		}
+	})
+}
@@ -1,12 +1,24 @@
 package hyperlane7683
 
 import (
+	"bytes"
 	"context"
 	"math/big"
 	"testing"
 
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestStarknetListener tests the Starknet listener functionality
@@ -43,6 +55,45 @@ func TestStarknetListener(t *testing.T) {
 	})
 }
 
+// TestStarknetRouterEvents tests the event selectors and the check of the router's declared events
+func TestStarknetRouterEvents(t *testing.T) {
+	routerEvents := func(names ...string) *contracts.ContractClass {
+		abi := `[`
+		for i, name := range names {
+			if i > 0 {
+				abi += `,`
+			}
+			abi += `{"type": "event", "name": "oif_starknet::base7683::Base7683Component::` + name + `", "kind": "struct", "members": []}`
+		}
+		return &contracts.ContractClass{ABI: contracts.NestedString(abi + `]`)}
+	}
+	router := new(felt.Felt).SetUint64(0x7683)
+
+	t.Run("selectors are the Cairo selectors of the event names", func(t *testing.T) {
+		assert.Equal(t, "0x35d8ba7f4bf26b6e2e2060e5bd28107042be35460fbd828c9d29a2d8af14445", openEventSelector.String())
+		assert.Equal(t, utils.GetSelectorFromNameFelt("Filled"), filledEventSelector)
+	})
+
+	t.Run("router declaring Open and Filled", func(t *testing.T) {
+		backend := chainmock.NewStarknetBackend()
+		backend.SetClass(router, routerEvents("Open", "Filled", "Settled"))
+		assert.NoError(t, verifyStarknetRouterEvents(context.Background(), backend, router))
+	})
+
+	t.Run("router missing an event", func(t *testing.T) {
+		backend := chainmock.NewStarknetBackend()
+		backend.SetClass(router, routerEvents("Open"))
+		err := verifyStarknetRouterEvents(context.Background(), backend, router)
+		assert.ErrorContains(t, err, "does not declare the Filled event")
+	})
+
+	t.Run("no contract at the router address fails listener creation", func(t *testing.T) {
+		listenerConfig := base.NewListenerConfig(router.String(), "Starknet", big.NewInt(0), 1000, 0, 0)
+		_, err := NewStarknetListenerWithProvider(listenerConfig, chainmock.NewStarknetBackend())
+		assert.ErrorContains(t, err, "cannot read the events of Starknet router")
+	})
+}
+
 // TestStarknetListenerConfig tests listener configuration
 func TestStarknetListenerConfig(t *testing.T) {
 	t.Run("listener_config_validation", func(t *testing.T) {
@@ -167,6 +218,207 @@ func TestStarknetListenerMethods(t *testing.T) {
 	})
 }
 
+// TestStarknetOpenedOrderID tests finding the order ID among a transaction's events
+func TestStarknetOpenedOrderID(t *testing.T) {
+	router := new(felt.Felt).SetUint64(0x7683)
+	other := new(felt.Felt).SetUint64(0x1234)
+	transfer := utils.GetSelectorFromNameFelt("Transfer")
+	open := func(from *felt.Felt) rpc.Event {
+		return rpc.Event{FromAddress: from, EventContent: rpc.EventContent{Keys: []*felt.Felt{openEventSelector}, Data: openEventFelts()}}
+	}
+
+	orderID, err := StarknetOpenedOrderID([]rpc.Event{
+		{FromAddress: router, EventContent: rpc.EventContent{Keys: []*felt.Felt{transfer}}},
+		open(router),
+	}, router)
+	require.NoError(t, err)
+	assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000042", orderID)
+
+	_, err = StarknetOpenedOrderID([]rpc.Event{open(other)}, router)
+	assert.ErrorContains(t, err, "no Open event from 0x7683")
+
+	truncated := open(router)
+	truncated.Data = truncated.Data[:3]
+	_, err = StarknetOpenedOrderID([]rpc.Event{truncated}, router)
+	assert.ErrorContains(t, err, "failed to decode Open event")
+}
+
+// TestDecodeResolvedOrderFromFelts tests decoding Cairo Open event data
+func TestDecodeResolvedOrderFromFelts(t *testing.T) {
+	data := openEventFelts()
+
+	t.Run("valid event", func(t *testing.T) {
+		order, err := decodeResolvedOrderFromFelts(data)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(config.StarknetSepoliaChainID), order.OriginChainID)
+		assert.Equal(t, byte(0x42), order.OrderID[31])
+		require.Len(t, order.MaxSpent, 1)
+		assert.Equal(t, big.NewInt(1000), order.MaxSpent[0].Amount)
+		require.Len(t, order.FillInstructions, 1)
+		assert.Equal(t, big.NewInt(config.BaseSepoliaChainID), order.FillInstructions[0].DestinationChainID)
+		require.Len(t, order.FillInstructions[0].OriginData, evmOriginDataSize)
+		// Word 1 of origin_data is the sender field (u128 pair 2, 3)
+		assert.Equal(t, byte(0x01), order.FillInstructions[0].OriginData[63])
+		// Recipients name the destination, not the chain the event was read on
+		assert.Equal(t, []types.Recipient{{DestinationChainName: "Base", RecipientAddress: "*"}}, orderRecipients(order, "Starknet"))
+	})
+
+	t.Run("Starknet to Starknet event decodes into a same-chain order", func(t *testing.T) {
+		starknetToStarknet := append([]*felt.Felt(nil), data...)
+		starknetToStarknet[17] = new(felt.Felt).SetUint64(config.StarknetSepoliaChainID) // min_received[0].chain_id
+		starknetToStarknet[19] = new(felt.Felt).SetUint64(config.StarknetSepoliaChainID) // fill_instructions[0].destination_chain_id
+
+		order, err := decodeResolvedOrderFromFelts(starknetToStarknet)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(config.StarknetSepoliaChainID), order.FillInstructions[0].DestinationChainID)
+		assert.Equal(t, []types.Recipient{{DestinationChainName: "Starknet", RecipientAddress: "*"}}, orderRecipients(order, "Starknet"))
+	})
+
+	t.Run("every truncation is an error", func(t *testing.T) {
+		for n := 0; n < len(data); n++ {
+			_, err := decodeResolvedOrderFromFelts(data[:n])
+			assert.ErrorIs(t, err, errEventDataTruncated, "truncated to %d felts", n)
+		}
+	})
+
+	t.Run("truncation names the field", func(t *testing.T) {
+		fields := map[int]string{
+			0:  "user",
+			5:  "order_id",
+			8:  "max_spent[0].amount",
+			18: "fill_instructions.length",
+			21: "fill_instructions[0].origin_data.size",
+			30: "fill_instructions[0].origin_data",
+		}
+		for n, field := range fields {
+			_, err := decodeResolvedOrderFromFelts(data[:n])
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), field+":", "truncated to %d felts", n)
+		}
+	})
+
+	large := func(bits uint) *felt.Felt {
+		return new(felt.Felt).SetBigInt(new(big.Int).Lsh(big.NewInt(1), bits))
+	}
+	tests := []struct {
+		name  string
+		index int
+		value *felt.Felt
+		err   error
+		field string
+	}{
+		{"nil felt", 3, nil, errEventDataInvalid, "fill_deadline"},
+		{"u32 overflow", 1, large(32), errEventDataInvalid, "origin_chain_id"},
+		{"u64 overflow", 2, large(64), errEventDataInvalid, "open_deadline"},
+		{"u128 overflow in u256", 5, large(128), errEventDataInvalid, "order_id.high"},
+		{"array length beyond data", 6, new(felt.Felt).SetUint64(1 << 62), errEventDataTruncated, "max_spent"},
+		{"array length overflow", 12, large(64), errEventDataInvalid, "min_received.length"},
+		{"unknown output domain", 11, new(felt.Felt).SetUint64(999), nil, "max_spent[0].chain_id"},
+		{"unknown destination domain", 19, new(felt.Felt).SetUint64(999), nil, "fill_instructions[0].destination_chain_id"},
+		{"origin_data size beyond words", 21, new(felt.Felt).SetUint64(1000), errEventDataInvalid, "fill_instructions[0].origin_data"},
+		{"origin_data word overflow", 25, large(128), errEventDataInvalid, "fill_instructions[0].origin_data[2]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			corrupt := append([]*felt.Felt(nil), data...)
+			corrupt[tt.index] = tt.value
+			_, err := decodeResolvedOrderFromFelts(corrupt)
+			require.Error(t, err)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			}
+			assert.Contains(t, err.Error(), tt.field+":")
+		})
+	}
+
+	t.Run("data payload", func(t *testing.T) {
+		for _, payload := range [][]byte{{0xde, 0xad, 0xbe, 0xef, 0x01}, bytes.Repeat([]byte{0x7f}, 32), bytes.Repeat([]byte{0x42}, 70)} {
+			order, err := decodeResolvedOrderFromFelts(openEventFeltsWithData(payload))
+			require.NoError(t, err)
+			originData := order.FillInstructions[0].OriginData
+			assert.Len(t, originData, evmOriginDataSize+(len(payload)+31)/32*32, "payload padded to whole words")
+
+			values, err := orderDataArguments.Unpack(originData)
+			require.NoError(t, err)
+			decoded := *abi.ConvertType(values[0], new(evmOrderData)).(*evmOrderData)
+			assert.Equal(t, payload, decoded.Data)
+			assert.Equal(t, byte(0x01), decoded.Sender[31])
+		}
+	})
+
+	t.Run("empty data payload", func(t *testing.T) {
+		order, err := decodeResolvedOrderFromFelts(openEventFeltsWithData(nil))
+		require.NoError(t, err)
+		assert.Equal(t, order.FillInstructions[0].OriginData, mustDecodeOriginData(t, openEventFelts()), "same as an encoding ending at fill_deadline")
+	})
+
+	t.Run("data size beyond origin_data", func(t *testing.T) {
+		data := openEventFeltsWithData([]byte{0x01, 0x02})
+		// origin_data words start at felt 23; the size word is the low u128 of word 13
+		data[23+27] = new(felt.Felt).SetUint64(3)
+		_, err := decodeResolvedOrderFromFelts(data)
+		assert.ErrorIs(t, err, errEventDataInvalid)
+		assert.ErrorContains(t, err, "data size 3 exceeds the 2 bytes after it")
+	})
+
+	t.Run("short origin_data", func(t *testing.T) {
+		short := append([]*felt.Felt(nil), data[:21]...)
+		short = append(short, new(felt.Felt).SetUint64(40), new(felt.Felt).SetUint64(3), new(felt.Felt), new(felt.Felt), new(felt.Felt))
+		_, err := decodeResolvedOrderFromFelts(short)
+		assert.ErrorIs(t, err, errEventDataInvalid)
+		assert.ErrorContains(t, err, "1 OrderData fields")
+	})
+}
+
+// openEventFelts encodes a Starknet Open event with one input, one output and one fill instruction
+func openEventFelts() []*felt.Felt {
+	u := func(v uint64) *felt.Felt { return new(felt.Felt).SetUint64(v) }
+	output := func(amount, domain uint64) []*felt.Felt {
+		return []*felt.Felt{u(0xa1), u(amount), u(0), u(0xcc), u(domain)}
+	}
+
+	data := []*felt.Felt{u(0xcc), u(config.StarknetSepoliaChainID), u(100), u(200), u(0x42), u(0)}
+	data = append(data, u(1))
+	data = append(data, output(1000, config.StarknetSepoliaChainID)...)
+	data = append(data, u(1))
+	data = append(data, output(1100, config.BaseSepoliaChainID)...)
+	data = append(data, u(1), u(config.BaseSepoliaChainID), u(0xb2))
+
+	// origin_data: 12 OrderData words as u128 pairs; the second word holds the sender
+	words := make([]*felt.Felt, 2*orderDataFieldCount)
+	for i := range words {
+		words[i] = u(0)
+	}
+	words[3] = u(0x01)
+	data = append(data, u(uint64(len(words))*16), u(uint64(len(words))))
+	return append(data, words...)
+}
+
+// openEventFeltsWithData encodes the Open event of openEventFelts with origin_data as Cairo's
+// OrderEncoder::encode writes it: the data offset and size words, then the unpadded payload
+func openEventFeltsWithData(payload []byte) []*felt.Felt {
+	data := openEventFelts()
+	head := data[:21]
+
+	raw := make([]byte, orderDataFieldCount*32)
+	raw[63] = 0x01 // sender
+	raw = append(raw, common.BigToHash(big.NewInt(0x180)).Bytes()...)
+	raw = append(raw, common.BigToHash(big.NewInt(int64(len(payload)))).Bytes()...)
+	raw = append(raw, payload...)
+
+	words := starknetutil.BytesToU128Felts(raw)
+	event := append([]*felt.Felt(nil), head...)
+	event = append(event, new(felt.Felt).SetUint64(uint64(len(raw))), new(felt.Felt).SetUint64(uint64(len(words))))
+	return append(event, words...)
+}
+
+// mustDecodeOriginData decodes the origin_data of the event's first fill instruction
+func mustDecodeOriginData(t *testing.T, data []*felt.Felt) []byte {
+	order, err := decodeResolvedOrderFromFelts(data)
+	require.NoError(t, err)
+	return order.FillInstructions[0].OriginData
+}
+
 // Mock implementations for testing
 
 // mockStarknetListener implements base.Listener for testing
@@ -185,3 +437,7 @@ func (m *mockStarknetListener) Stop() error {
 func (m *mockStarknetListener) GetLastProcessedBlock() uint64 {
 	return m.lastProcessedBlock
 }
+
+func (m *mockStarknetListener) Snapshot() base.ListenerSnapshot {
+	return base.ListenerSnapshot{LastProcessedBlock: m.lastProcessedBlock}
+}
@@ -0,0 +1,119 @@
+package hyperlane7683
+
+// Module: Listener status tracking
+// - Records the chain head, last processed block, events seen and the last polling error
+// - Shared by the EVM and Starknet listeners and the common block range processing
+// - Read through Listener.Snapshot, e.g. by the admin API's GET /listeners
+
+import (
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+)
+
+// ListenerStatus holds the progress of one listener. Methods are safe for concurrent use and
+// no-ops on a nil receiver, so listeners built without one (e.g. in tests) need no special casing.
+type ListenerStatus struct {
+	mu          sync.Mutex
+	chainName   string
+	networkType string
+	head        uint64
+	lastBlock   uint64
+	eventsSeen  uint64
+	lastErr     string
+	lastErrAt   time.Time
+	lastPollAt  time.Time
+	now         func() time.Time
+}
+
+// NewListenerStatus creates the status of a listener starting after lastProcessedBlock
+func NewListenerStatus(chainName, networkType string, lastProcessedBlock uint64) *ListenerStatus {
+	return &ListenerStatus{
+		chainName:   chainName,
+		networkType: networkType,
+		lastBlock:   lastProcessedBlock,
+		now:         time.Now,
+	}
+}
+
+// RecordHead records the latest block number reported by the chain
+func (s *ListenerStatus) RecordHead(head uint64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.head = head
+}
+
+// RecordProcessed records the highest block fully processed
+func (s *ListenerStatus) RecordProcessed(block uint64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastBlock = block
+}
+
+// RecordPoll records a successful poll
+func (s *ListenerStatus) RecordPoll() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastPollAt = s.now()
+}
+
+// RecordEvents adds the number of Open events fetched for a block range
+func (s *ListenerStatus) RecordEvents(count int) {
+	if s == nil || count <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.eventsSeen += uint64(count)
+}
+
+// RecordError records a failed poll or backfill; the error is kept until the next failure
+func (s *ListenerStatus) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastErr = err.Error()
+	s.lastErrAt = s.now()
+}
+
+// Snapshot returns the recorded progress
+func (s *ListenerStatus) Snapshot() base.ListenerSnapshot {
+	if s == nil {
+		return base.ListenerSnapshot{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := base.ListenerSnapshot{
+		ChainName:          s.chainName,
+		NetworkType:        s.networkType,
+		Head:               s.head,
+		LastProcessedBlock: s.lastBlock,
+		EventsSeen:         s.eventsSeen,
+		LastError:          s.lastErr,
+	}
+	if s.head > s.lastBlock {
+		snapshot.Lag = s.head - s.lastBlock
+	}
+	if !s.lastErrAt.IsZero() {
+		lastErrAt := s.lastErrAt
+		snapshot.LastErrorAt = &lastErrAt
+	}
+	if !s.lastPollAt.IsZero() {
+		lastPollAt := s.lastPollAt
+		snapshot.LastPollAt = &lastPollAt
+	}
+	return snapshot
+}
@@ -0,0 +1,95 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenerSnapshot(t *testing.T) {
+	t.Setenv("SOLVER_STATE_FILE", filepath.Join(t.TempDir(), "solver-state.json"))
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+
+	settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	backend := chainmock.NewEVMBackend(config.EthereumSepoliaChainID)
+	backend.AddLog(openLog(t, settler, [32]byte{0x01}, 12))
+	backend.AddLog(openLog(t, settler, [32]byte{0x02}, 14))
+	backend.SetBlockNumber(20)
+
+	cfg := &base.ListenerConfig{ChainName: "Ethereum", ContractAddress: settler.Hex(), ConfirmationBlocks: 2, MaxBlockRange: 4}
+	baseListener := NewBaseListener(*cfg, backend, "EVM")
+	baseListener.SetLastProcessedBlock(9)
+	listener := &evmListener{
+		config:             cfg,
+		client:             backend,
+		contractAddress:    settler,
+		lastProcessedBlock: 9,
+		stopChan:           make(chan struct{}),
+		baseListener:       baseListener,
+		status:             baseListener.Status(),
+	}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	listener.status.now = func() time.Time { return now }
+	handler := func(types.ParsedArgs, string, uint64) (bool, error) { return true, nil }
+	poll := func() error { return listener.processCurrentBlockRange(context.Background(), handler) }
+
+	t.Run("before the first poll", func(t *testing.T) {
+		snapshot := listener.Snapshot()
+		assert.Equal(t, base.ListenerSnapshot{ChainName: "Ethereum", NetworkType: "EVM", LastProcessedBlock: 9}, snapshot)
+	})
+
+	t.Run("poll records head, progress and events", func(t *testing.T) {
+		pollWithCircuitBreaker("Ethereum", listener.status, poll)
+
+		snapshot := listener.Snapshot()
+		assert.Equal(t, uint64(20), snapshot.Head)
+		assert.Equal(t, uint64(18), snapshot.LastProcessedBlock, "confirmations are not processed yet")
+		assert.Equal(t, uint64(2), snapshot.Lag)
+		assert.Equal(t, uint64(2), snapshot.EventsSeen)
+		require.NotNil(t, snapshot.LastPollAt)
+		assert.Equal(t, now, *snapshot.LastPollAt)
+		assert.Empty(t, snapshot.LastError)
+	})
+
+	t.Run("failed poll keeps progress and records the error", func(t *testing.T) {
+		backend.SetBlockNumber(30)
+		backend.FailNext("FilterLogs", errors.New("rpc down"))
+		now = now.Add(time.Minute)
+		pollWithCircuitBreaker("Ethereum", listener.status, poll)
+
+		snapshot := listener.Snapshot()
+		assert.Equal(t, uint64(30), snapshot.Head)
+		assert.Equal(t, uint64(18), snapshot.LastProcessedBlock)
+		assert.Equal(t, uint64(12), snapshot.Lag)
+		assert.Contains(t, snapshot.LastError, "rpc down")
+		require.NotNil(t, snapshot.LastErrorAt)
+		assert.Equal(t, now, *snapshot.LastErrorAt)
+		assert.Equal(t, now.Add(-time.Minute), *snapshot.LastPollAt)
+
+		// The next poll catches up; the error stays visible until the next failure
+		pollWithCircuitBreaker("Ethereum", listener.status, poll)
+		snapshot = listener.Snapshot()
+		assert.Equal(t, uint64(28), snapshot.LastProcessedBlock)
+		assert.Equal(t, now, *snapshot.LastPollAt)
+		assert.Contains(t, snapshot.LastError, "rpc down")
+	})
+
+	t.Run("nil status is a no-op", func(t *testing.T) {
+		var status *ListenerStatus
+		status.RecordHead(1)
+		status.RecordError(errors.New("ignored"))
+		assert.Equal(t, base.ListenerSnapshot{}, status.Snapshot())
+	})
+}
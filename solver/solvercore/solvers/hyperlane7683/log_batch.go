@@ -0,0 +1,185 @@
+package hyperlane7683
+
+// Module: Batched backfill log queries
+// - During backfill the listener reads the logs of its next ranges in one JSON-RPC batch before
+//   processing them one by one: eth_getLogs per range on EVM, starknet_getEvents per range on Starknet
+// - Batches are spaced by RPC_BATCH_MIN_INTERVAL_MS so a long backfill can't hammer a provider
+// - When a provider refuses a batch, or any query in it, the listener falls back to one call per
+//   range for the rest of its life; clients that can't batch (e.g. while recording) never try
+// - Polling the chain head is not batched: it reads a single range
+//
+// Settings:
+// - RPC_BATCH_SIZE: ranges per batch during backfill (default 10; 0 or 1 disables batching)
+// - RPC_BATCH_<NETWORK>_SIZE: override for one network, e.g. RPC_BATCH_BASE_SIZE
+// - RPC_BATCH_MIN_INTERVAL_MS: minimum time between two batches of a listener (default 0)
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/rpctimeout"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const defaultRPCBatchSize = 10
+
+// evmLogBatcher reads the logs of several queries in one round trip (rpctimeout.EVMClient)
+type evmLogBatcher interface {
+	BatchFilterLogs(ctx context.Context, queries []ethereum.FilterQuery) ([][]ethtypes.Log, error)
+}
+
+// starknetEventBatcher reads several event pages in one round trip (rpctimeout.StarknetProvider)
+type starknetEventBatcher interface {
+	BatchEvents(ctx context.Context, inputs []rpc.EventsInput) ([]*rpc.EventChunk, error)
+}
+
+// blockRange is an inclusive range of blocks read in one log query
+type blockRange struct {
+	from, to uint64
+}
+
+// rangePrefetcher reads the logs of the next backfill ranges ahead of their processing
+type rangePrefetcher interface {
+	// BatchSize is how many ranges to prefetch at once; below 2 nothing is prefetched
+	BatchSize() int
+	Prefetch(ctx context.Context, ranges []blockRange)
+}
+
+// backfillRanges returns up to n ranges of the backfill loop from start, as CatchUpHistoricalBlocks walks them
+func backfillRanges(start, toBlock, chunkSize uint64, n int) []blockRange {
+	var ranges []blockRange
+	for from := start; from < toBlock && len(ranges) < n; from += chunkSize {
+		ranges = append(ranges, blockRange{from: from, to: min(from+chunkSize, toBlock)})
+	}
+	return ranges
+}
+
+// logBatch holds the results of batched log queries until the listener processes their range
+type logBatch[T any] struct {
+	chainName   string
+	size        int
+	minInterval time.Duration
+	fetch       func(ctx context.Context, ranges []blockRange) ([]T, error)
+	now         func() time.Time
+
+	mu        sync.Mutex
+	results   map[blockRange]T
+	disabled  bool
+	lastBatch time.Time
+}
+
+// newLogBatch returns a batch reader for a listener of chainName, configured from the environment
+func newLogBatch[T any](chainName string, fetch func(ctx context.Context, ranges []blockRange) ([]T, error)) *logBatch[T] {
+	size := envutil.GetEnvInt("RPC_BATCH_SIZE", defaultRPCBatchSize)
+	size = envutil.GetEnvInt("RPC_BATCH_"+config.NetworkEnvPrefix(chainName)+"_SIZE", size)
+	return &logBatch[T]{
+		chainName:   chainName,
+		size:        size,
+		minInterval: time.Duration(envutil.GetEnvInt("RPC_BATCH_MIN_INTERVAL_MS", 0)) * time.Millisecond,
+		fetch:       fetch,
+		now:         time.Now,
+		results:     make(map[blockRange]T),
+	}
+}
+
+// newEVMLogBatch batches the log queries of l when its client supports it; nil otherwise
+func newEVMLogBatch(l *evmListener) *logBatch[[]ethtypes.Log] {
+	batcher, ok := l.client.(evmLogBatcher)
+	if !ok {
+		return nil
+	}
+	return newLogBatch(l.config.ChainName, func(ctx context.Context, ranges []blockRange) ([][]ethtypes.Log, error) {
+		queries := make([]ethereum.FilterQuery, len(ranges))
+		for i, r := range ranges {
+			queries[i] = l.logQuery(r.from, r.to)
+		}
+		return batcher.BatchFilterLogs(ctx, queries)
+	})
+}
+
+// newStarknetEventBatch batches the event queries of l when its provider supports it; nil otherwise
+func newStarknetEventBatch(l *starknetListener) *logBatch[*rpc.EventChunk] {
+	batcher, ok := l.provider.(starknetEventBatcher)
+	if !ok {
+		return nil
+	}
+	return newLogBatch(l.config.ChainName, func(ctx context.Context, ranges []blockRange) ([]*rpc.EventChunk, error) {
+		inputs := make([]rpc.EventsInput, len(ranges))
+		for i, r := range ranges {
+			inputs[i] = l.eventsQuery(r.from, r.to, "")
+		}
+		return batcher.BatchEvents(ctx, inputs)
+	})
+}
+
+// BatchSize returns the configured batch size, 0 once batching is disabled
+func (b *logBatch[T]) BatchSize() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.disabled {
+		return 0
+	}
+	return b.size
+}
+
+// Prefetch reads the logs of ranges in one batch, waiting for the minimum interval since the last
+// one. A refused batch disables batching; its ranges are then read one at a time.
+func (b *logBatch[T]) Prefetch(ctx context.Context, ranges []blockRange) {
+	if len(ranges) < 2 || b.BatchSize() < 2 {
+		return
+	}
+
+	b.mu.Lock()
+	wait := b.lastBatch.Add(b.minInterval).Sub(b.now())
+	b.mu.Unlock()
+	if wait > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+
+	results, err := b.fetch(ctx, ranges)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastBatch = b.now()
+	// Results of ranges that were not processed, e.g. after a failed range, are stale by now
+	clear(b.results)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		b.disabled = true
+		if !errors.Is(err, rpctimeout.ErrBatchUnsupported) {
+			listenerLog.Printf("%s⚠️  Batched log queries failed, reading one range at a time: %v\n", logutil.Prefix(b.chainName), err)
+		}
+		return
+	}
+	for i, r := range ranges {
+		if i < len(results) {
+			b.results[r] = results[i]
+		}
+	}
+}
+
+// Take returns and forgets the prefetched logs of r. A nil batch holds nothing.
+func (b *logBatch[T]) Take(r blockRange) (T, bool) {
+	if b == nil {
+		var zero T
+		return zero, false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	result, ok := b.results[r]
+	delete(b.results, r)
+	return result, ok
+}
@@ -0,0 +1,122 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillRanges(t *testing.T) {
+	assert.Equal(t, []blockRange{{1, 11}, {11, 21}, {21, 25}}, backfillRanges(1, 25, 10, 5))
+	assert.Equal(t, []blockRange{{1, 11}, {11, 21}}, backfillRanges(1, 25, 10, 2))
+	assert.Empty(t, backfillRanges(25, 25, 10, 5))
+}
+
+func TestLogBatch(t *testing.T) {
+	sizes := func(ctx context.Context, ranges []blockRange) ([]uint64, error) {
+		results := make([]uint64, len(ranges))
+		for i, r := range ranges {
+			results[i] = r.to - r.from
+		}
+		return results, nil
+	}
+
+	t.Run("returns each prefetched range once", func(t *testing.T) {
+		calls := 0
+		batch := newLogBatch("Base", func(ctx context.Context, ranges []blockRange) ([]uint64, error) {
+			calls++
+			return sizes(ctx, ranges)
+		})
+		batch.Prefetch(context.Background(), []blockRange{{1, 11}, {11, 15}})
+		assert.Equal(t, 1, calls)
+
+		got, ok := batch.Take(blockRange{11, 15})
+		assert.True(t, ok)
+		assert.Equal(t, uint64(4), got)
+		_, ok = batch.Take(blockRange{11, 15})
+		assert.False(t, ok, "taken already")
+		_, ok = batch.Take(blockRange{15, 25})
+		assert.False(t, ok, "not prefetched")
+	})
+
+	t.Run("a refused batch disables batching", func(t *testing.T) {
+		calls := 0
+		batch := newLogBatch("Base", func(ctx context.Context, ranges []blockRange) ([]uint64, error) {
+			calls++
+			return nil, errors.New("batch requests are not supported")
+		})
+		batch.Prefetch(context.Background(), []blockRange{{1, 11}, {11, 21}})
+		assert.Equal(t, 0, batch.BatchSize())
+		_, ok := batch.Take(blockRange{1, 11})
+		assert.False(t, ok)
+
+		batch.Prefetch(context.Background(), []blockRange{{21, 31}, {31, 41}})
+		assert.Equal(t, 1, calls, "no batch once disabled")
+	})
+
+	t.Run("waits the minimum interval between batches", func(t *testing.T) {
+		t.Setenv("RPC_BATCH_MIN_INTERVAL_MS", "30")
+		batch := newLogBatch("Base", sizes)
+		batch.Prefetch(context.Background(), []blockRange{{1, 11}, {11, 21}})
+
+		started := time.Now()
+		batch.Prefetch(context.Background(), []blockRange{{21, 31}, {31, 41}})
+		assert.GreaterOrEqual(t, time.Since(started), 30*time.Millisecond)
+	})
+
+	t.Run("size per network", func(t *testing.T) {
+		t.Setenv("RPC_BATCH_SIZE", "4")
+		t.Setenv("RPC_BATCH_BASE_SIZE", "1")
+		assert.Equal(t, 1, newLogBatch("Base", sizes).BatchSize())
+		assert.Equal(t, 4, newLogBatch("Ethereum", sizes).BatchSize())
+	})
+
+	t.Run("nil batch holds nothing", func(t *testing.T) {
+		var batch *logBatch[uint64]
+		_, ok := batch.Take(blockRange{1, 11})
+		assert.False(t, ok)
+	})
+}
+
+// countingPrefetcher records the batches CatchUpHistoricalBlocks asks for
+type countingPrefetcher struct {
+	size    int
+	batches [][]blockRange
+}
+
+func (p *countingPrefetcher) BatchSize() int { return p.size }
+
+func (p *countingPrefetcher) Prefetch(_ context.Context, ranges []blockRange) {
+	p.batches = append(p.batches, ranges)
+}
+
+func TestCatchUpPrefetchesBatches(t *testing.T) {
+	t.Setenv("SOLVER_STATE_FILE", filepath.Join(t.TempDir(), "solver-state.json"))
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+
+	backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+	backend.SetBlockNumber(45)
+	bl := NewBaseListener(base.ListenerConfig{ChainName: "Base", MaxBlockRange: 10}, backend, "EVM")
+	prefetcher := &countingPrefetcher{size: 3}
+	bl.prefetch = prefetcher
+
+	var processed []blockRange
+	err := bl.CatchUpHistoricalBlocks(context.Background(), nil, func(_ context.Context, from, to uint64, _ base.EventHandler) (uint64, error) {
+		processed = append(processed, blockRange{from, to})
+		return to, nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []blockRange{{1, 11}, {11, 21}, {21, 31}, {31, 41}, {41, 45}}, processed)
+	assert.Equal(t, [][]blockRange{{{1, 11}, {11, 21}, {21, 31}}, {{31, 41}, {41, 45}}}, prefetcher.batches)
+}
@@ -0,0 +1,84 @@
+package hyperlane7683
+
+// Module: Manual order actions
+// - Operator-driven recovery of stuck orders from the order store (admin API POST /orders/{id}/fill|settle)
+// - ForceFill runs the order through ProcessIntent right away instead of waiting for a listener or
+//   the backfill order queue; allow/block lists, paused fills, rules and spend limits still apply
+// - ForceSettle settles an order the solver filled without waiting for the settlement worker; the
+//   order is taken out of the worker's queue first and put back if the settlement fails
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+)
+
+// ErrActionNotAllowed is returned for manual actions the order's status doesn't allow
+var ErrActionNotAllowed = errors.New("action not allowed")
+
+// ForceFill fills a stored order now and returns its updated record
+func (f *Hyperlane7683Solver) ForceFill(ctx context.Context, orderID string) (orders.Order, error) {
+	order, err := f.storedOrder(orderID)
+	if err != nil {
+		return order, err
+	}
+	switch order.Status {
+	case orders.StatusFilled, orders.StatusSettled:
+		return order, fmt.Errorf("%w: order %s is already %s", ErrActionNotAllowed, orderID, order.Status)
+	}
+
+	fmt.Printf("🛠️  Manual fill of order %s (was %s)\n", orderID, order.Status)
+	_, err = f.ProcessIntent(ctx, order.Args)
+	updated, _ := f.orderStore.Get(orderID)
+	return updated, err
+}
+
+// ForceSettle settles a stored order the solver filled now and returns its updated record
+func (f *Hyperlane7683Solver) ForceSettle(ctx context.Context, orderID string) (orders.Order, error) {
+	order, err := f.storedOrder(orderID)
+	if err != nil {
+		return order, err
+	}
+	if order.Status != orders.StatusFilled || !order.FilledByUs() {
+		return order, fmt.Errorf("%w: order %s is %s, only orders filled by the solver can be settled", ErrActionNotAllowed, orderID, order.Status)
+	}
+
+	queued := false
+	if f.settler != nil {
+		if queued, err = f.settler.Take(orderID); err != nil {
+			return order, fmt.Errorf("%w: order %s: %w", ErrActionNotAllowed, orderID, err)
+		}
+	}
+
+	fmt.Printf("🛠️  Manual settlement of order %s\n", orderID)
+	if err := f.SettleOrder(ctx, order.Args); err != nil {
+		logutil.LogOperationComplete(order.Args, "Order settlement", false)
+		f.recordOrderStatus(order.Args, orders.StatusFilled, "manual settlement failed: "+err.Error())
+		if queued {
+			f.settler.Schedule(order.Args)
+		}
+		updated, _ := f.orderStore.Get(orderID)
+		return updated, fmt.Errorf("order settlement failed: %w", err)
+	}
+	f.recordOrderStatus(order.Args, orders.StatusSettled, "")
+	updated, _ := f.orderStore.Get(orderID)
+	return updated, nil
+}
+
+// storedOrder returns an order of the store that can be acted on
+func (f *Hyperlane7683Solver) storedOrder(orderID string) (orders.Order, error) {
+	if f.orderStore == nil {
+		return orders.Order{}, fmt.Errorf("no order store")
+	}
+	order, ok := f.orderStore.Get(orderID)
+	if !ok {
+		return order, fmt.Errorf("%w: %s", orders.ErrOrderNotFound, orderID)
+	}
+	if order.Args == nil {
+		return order, fmt.Errorf("%w: order %s has no recorded order data", ErrActionNotAllowed, orderID)
+	}
+	return order, nil
+}
@@ -0,0 +1,139 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// manualChainHandler counts fills and settlements and fails settlements with settleErr
+type manualChainHandler struct {
+	statusChainHandler
+	mu        sync.Mutex
+	fills     int
+	settles   int
+	settleErr error
+}
+
+func (h *manualChainHandler) Fill(context.Context, *types.ParsedArgs) (OrderAction, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fills++
+	return OrderActionSettle, nil
+}
+
+func (h *manualChainHandler) Settle(context.Context, *types.ParsedArgs) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.settles++
+	return h.settleErr
+}
+
+// TestManualActions tests operator-driven fills and settlements of stored orders
+func TestManualActions(t *testing.T) {
+	t.Setenv("SOLVER_PUB_KEY", "0x00000000000000000000000000000000000000aa")
+	t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+	require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Rules: config.RulesConfig{Disabled: []string{"BalanceCheck"}}}))
+
+	ctx := context.Background()
+	orderID := settleOrderID(7)
+
+	newSolver := func(t *testing.T) (*Hyperlane7683Solver, *orders.Store, *manualChainHandler, *SettleScheduler) {
+		handler := &manualChainHandler{}
+		solver := NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{})
+		solver.evmHandlers[config.BaseSepoliaChainID] = handler
+
+		scheduler, store, _ := newTestSettleScheduler(t, &testSettler{})
+		solver.SetOrderStore(store)
+		solver.ScheduleSettlements(scheduler)
+		return solver, store, handler, scheduler
+	}
+	storeOrder := func(t *testing.T, store *orders.Store, status orders.Status, filledByUs bool) *types.ParsedArgs {
+		args := endToEndArgs(orderID, "0x00000000000000000000000000000000000000a1", "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+		require.NoError(t, store.Observe(&args))
+		require.NoError(t, store.SetStatus(orderID, status, "stuck"))
+		if filledByUs {
+			require.NoError(t, store.AddCost(orderID, orders.TxCost{ChainID: config.BaseSepoliaChainID, Kind: orders.TxKindFill, TxHash: "0xf1"}))
+		}
+		return &args
+	}
+
+	t.Run("fill runs the order through the pipeline", func(t *testing.T) {
+		solver, store, handler, scheduler := newSolver(t)
+		storeOrder(t, store, orders.StatusFailed, false)
+
+		order, err := solver.ForceFill(ctx, orderID)
+		require.NoError(t, err)
+		assert.Equal(t, orders.StatusFilled, order.Status)
+		assert.Equal(t, 1, handler.fills)
+		assert.Equal(t, 1, scheduler.Len(), "settlement handed to the worker")
+	})
+
+	t.Run("fill keeps the safety checks", func(t *testing.T) {
+		solver, store, handler, _ := newSolver(t)
+		args := storeOrder(t, store, orders.StatusRejected, false)
+		args.Recipients = []types.Recipient{{DestinationChainName: "Base", RecipientAddress: "*"}}
+		require.NoError(t, store.Update(orderID, func(o *orders.Order) { o.Args = args }))
+		solver.SetAllowBlockLists(types.AllowBlockLists{BlockList: []types.AllowBlockListItem{{SenderAddress: "*", DestinationDomain: "*", RecipientAddress: "*"}}})
+
+		order, err := solver.ForceFill(ctx, orderID)
+		assert.ErrorContains(t, err, "blocked")
+		assert.Equal(t, orders.StatusRejected, order.Status)
+		assert.Zero(t, handler.fills)
+	})
+
+	t.Run("fill refuses filled orders", func(t *testing.T) {
+		solver, store, handler, _ := newSolver(t)
+		storeOrder(t, store, orders.StatusSettled, true)
+
+		_, err := solver.ForceFill(ctx, orderID)
+		assert.ErrorIs(t, err, ErrActionNotAllowed)
+		assert.Zero(t, handler.fills)
+	})
+
+	t.Run("settle bypasses the settlement queue", func(t *testing.T) {
+		solver, store, handler, scheduler := newSolver(t)
+		scheduler.Schedule(storeOrder(t, store, orders.StatusFilled, true))
+
+		order, err := solver.ForceSettle(ctx, orderID)
+		require.NoError(t, err)
+		assert.Equal(t, orders.StatusSettled, order.Status)
+		assert.Equal(t, 1, handler.settles)
+		assert.Zero(t, scheduler.Len())
+	})
+
+	t.Run("failed settlement goes back to the queue", func(t *testing.T) {
+		solver, store, handler, scheduler := newSolver(t)
+		handler.settleErr = errors.New("settle reverted")
+		scheduler.Schedule(storeOrder(t, store, orders.StatusFilled, true))
+
+		order, err := solver.ForceSettle(ctx, orderID)
+		assert.ErrorContains(t, err, "settle reverted")
+		assert.Equal(t, orders.StatusFilled, order.Status)
+		assert.True(t, strings.HasPrefix(order.Reason, "manual settlement failed: "), order.Reason)
+		assert.Equal(t, 1, scheduler.Len())
+	})
+
+	t.Run("settle refuses orders the solver didn't fill", func(t *testing.T) {
+		solver, store, handler, _ := newSolver(t)
+		storeOrder(t, store, orders.StatusFilled, false)
+		_, err := solver.ForceSettle(ctx, orderID)
+		assert.ErrorIs(t, err, ErrActionNotAllowed)
+
+		_, err = solver.ForceSettle(ctx, settleOrderID(8))
+		assert.ErrorIs(t, err, orders.ErrOrderNotFound)
+		assert.Zero(t, handler.settles)
+	})
+}
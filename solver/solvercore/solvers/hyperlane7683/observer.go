@@ -0,0 +1,65 @@
+package hyperlane7683
+
+// Module: Observer mode
+// - A read-only mode for shadow-testing a config against live traffic: listeners, decoding, token
+//   discovery and rules run as usual, and every order is recorded in the order store and metrics
+// - Orders passing every rule stay OBSERVED with an "observer mode" reason and are counted in
+//   solver_orders_would_fill_total{origin,destination}; rejections are recorded as usual
+// - Nothing is ever signed: fills, settlements and approvals, including those requested through the
+//   admin API, fail with ErrObserverMode
+//
+// Settings:
+// - OBSERVER_MODE: run without signing transactions (default false)
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const (
+	wouldFillOrdersMetric = "solver_orders_would_fill_total"
+	wouldFillReason       = "observer mode: passed all rules, not filled"
+)
+
+// ErrObserverMode is returned by every operation that would sign a transaction in observer mode
+var ErrObserverMode = errors.New("observer mode: the solver does not sign transactions")
+
+// observerModeFromEnv reports whether OBSERVER_MODE is set
+func observerModeFromEnv() bool {
+	return envutil.GetEnvBool("OBSERVER_MODE", false)
+}
+
+// ObserverMode reports whether the solver runs without signing transactions
+func (f *Hyperlane7683Solver) ObserverMode() bool {
+	return f.observerMode
+}
+
+// SetObserverMode turns observer mode on or off; it must be called before the solver processes orders
+func (f *Hyperlane7683Solver) SetObserverMode(enabled bool) {
+	f.observerMode = enabled
+}
+
+// recordWouldFill records an order the solver would have filled outside observer mode
+func (f *Hyperlane7683Solver) recordWouldFill(args *types.ParsedArgs) {
+	origin, destination := orderChainNames(args)
+	fmt.Printf("👀 Observer mode: order %s (%s → %s) passed all rules, not filling\n", args.OrderID, origin, destination)
+	f.metrics.Counter(wouldFillOrdersMetric, "origin", origin, "destination", destination).Inc()
+	f.recordOrderStatus(args, orders.StatusObserved, wouldFillReason)
+}
+
+// orderChainNames returns the network names of an order's origin and first destination
+func orderChainNames(args *types.ParsedArgs) (string, string) {
+	origin, destination := "unknown", "unknown"
+	if args.ResolvedOrder.OriginChainID != nil {
+		origin = logutil.NetworkNameByChainID(args.ResolvedOrder.OriginChainID.Uint64())
+	}
+	if instructions := args.ResolvedOrder.FillInstructions; len(instructions) > 0 && instructions[0].DestinationChainID != nil {
+		destination = logutil.NetworkNameByChainID(instructions[0].DestinationChainID.Uint64())
+	}
+	return origin, destination
+}
@@ -0,0 +1,92 @@
+package hyperlane7683
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestObserverMode tests that orders are evaluated and recorded without any transaction being signed
+func TestObserverMode(t *testing.T) {
+	t.Setenv("SOLVER_PUB_KEY", "0x00000000000000000000000000000000000000aa")
+	t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+	require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{}))
+
+	orderID := "0x4444444444444444444444444444444444444444444444444444444444444444"
+	token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+
+	newObserver := func(t *testing.T, balance int64) (*Hyperlane7683Solver, *orders.Store) {
+		destination := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		destination.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(balance)))
+		store, err := orders.NewStore(filepath.Join(t.TempDir(), "orders.json"))
+		require.NoError(t, err)
+
+		// No signer getters: signing anything would fail the test
+		solver := NewHyperlane7683Solver(
+			func(uint64) (EVMClient, error) { return destination, nil },
+			nil, nil, nil,
+			types.AllowBlockLists{},
+		)
+		solver.SetOrderStore(store)
+		solver.SetObserverMode(true)
+		solver.metrics = metrics.NewRegistry()
+		return solver, store
+	}
+
+	t.Run("order passing the rules is recorded, not filled", func(t *testing.T) {
+		solver, store := newObserver(t, 1_000_000)
+
+		args := endToEndArgs(orderID, token.Hex(), "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		stored, _ := store.Get(orderID)
+		assert.Equal(t, orders.StatusObserved, stored.Status)
+		assert.Equal(t, wouldFillReason, stored.Reason)
+		assert.Equal(t, float64(1), solver.metrics.Counter(wouldFillOrdersMetric, "origin", "Ethereum", "destination", "Base").Value())
+	})
+
+	t.Run("rejections are recorded as usual", func(t *testing.T) {
+		solver, store := newObserver(t, 1)
+
+		args := endToEndArgs(orderID, token.Hex(), "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+		_, err := solver.ProcessIntent(context.Background(), &args)
+		assert.ErrorContains(t, err, "Insufficient balance")
+
+		stored, _ := store.Get(orderID)
+		assert.Equal(t, orders.StatusRejected, stored.Status)
+		assert.Equal(t, float64(1), solver.metrics.Counter(rejectedOrdersMetric, "reason", RejectInsufficientBalance).Value())
+	})
+
+	t.Run("fills, settlements and approvals are refused", func(t *testing.T) {
+		solver, _ := newObserver(t, 1_000_000)
+		args := endToEndArgs(orderID, token.Hex(), "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+
+		_, err := solver.Fill(context.Background(), &args)
+		assert.ErrorIs(t, err, ErrObserverMode)
+		assert.ErrorIs(t, solver.SettleOrder(context.Background(), &args), ErrObserverMode)
+		assert.NoError(t, solver.PrewarmApprovals(context.Background()))
+	})
+
+	t.Run("enabled from the environment", func(t *testing.T) {
+		t.Setenv("OBSERVER_MODE", "true")
+		assert.True(t, NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{}).ObserverMode())
+		t.Setenv("OBSERVER_MODE", "")
+		assert.False(t, NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{}).ObserverMode())
+	})
+}
@@ -0,0 +1,95 @@
+package hyperlane7683
+
+// Module: Order intake by age
+// - Orders handed over by the listeners that are past their fill deadline, or whose origin block is
+//   older than rules.maxOrderAge, are recorded as EXPIRED instead of entering the fill pipeline, so a
+//   backfill over old history doesn't try to fill orders nobody can fill anymore
+// - The origin block's timestamp is only fetched when maxOrderAge is set; if it can't be read the
+//   order is processed as usual
+// - Replays call ProcessIntent directly and are not filtered, since recorded orders are old by design
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// ProcessOpenedIntent processes an order opened in blockNumber of its origin chain, skipping
+// expired orders
+func (f *Hyperlane7683Solver) ProcessOpenedIntent(ctx context.Context, args *types.ParsedArgs, blockNumber uint64) (bool, error) {
+	if reason := f.expiredReason(ctx, args, blockNumber); reason != "" {
+		fillerLog.Printf("⌛ Order %s expired (%s), skipping\n", args.OrderID, reason)
+		f.observeOrder(args)
+		logutil.LogOperationComplete(args, "Order processing", false)
+		f.recordOrderStatus(args, orders.StatusExpired, reason)
+		return false, nil
+	}
+	return f.ProcessIntent(ctx, args)
+}
+
+// expiredReason returns why an order is expired, or "" if it can still be filled
+func (f *Hyperlane7683Solver) expiredReason(ctx context.Context, args *types.ParsedArgs, blockNumber uint64) string {
+	now := f.now()
+	if deadline := args.ResolvedOrder.FillDeadline; deadline != 0 && int64(deadline) <= now.Unix() {
+		return fmt.Sprintf("fill deadline %s passed", time.Unix(int64(deadline), 0).UTC().Format(time.RFC3339))
+	}
+
+	maxAge := config.ActiveRuntime().MaxOrderAge()
+	if maxAge == 0 || args.ResolvedOrder.OriginChainID == nil {
+		return ""
+	}
+	openedAt, err := f.blockTime(ctx, args.ResolvedOrder.OriginChainID, blockNumber)
+	if err != nil {
+		fillerLog.Printf("⚠️  Failed to read the time of block %d for order %s: %v\n", blockNumber, args.OrderID, err)
+		return ""
+	}
+	if age := now.Sub(openedAt); age > maxAge {
+		return fmt.Sprintf("opened %s ago, max order age %s", age.Truncate(time.Second), maxAge)
+	}
+	return ""
+}
+
+// blockTime returns the timestamp of a block of the given chain
+func (f *Hyperlane7683Solver) blockTime(ctx context.Context, chainID *big.Int, blockNumber uint64) (time.Time, error) {
+	if f.isStarknetChain(chainID) {
+		if f.getStarknetClient == nil {
+			return time.Time{}, fmt.Errorf("no Starknet client")
+		}
+		provider, err := f.getStarknetClient(chainID.Uint64())
+		if err != nil {
+			return time.Time{}, err
+		}
+		block, err := provider.BlockWithTxHashes(ctx, rpc.WithBlockNumber(blockNumber))
+		if err != nil {
+			return time.Time{}, err
+		}
+		switch b := block.(type) {
+		case *rpc.BlockTxHashes:
+			return time.Unix(int64(b.Timestamp), 0), nil
+		case *rpc.PreConfirmedBlockTxHashes:
+			return time.Unix(int64(b.Timestamp), 0), nil
+		default:
+			return time.Time{}, fmt.Errorf("unexpected block type %T", block)
+		}
+	}
+
+	if f.getEVMClient == nil {
+		return time.Time{}, fmt.Errorf("no EVM client")
+	}
+	client, err := f.getEVMClient(chainID.Uint64())
+	if err != nil {
+		return time.Time{}, err
+	}
+	header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(header.Time), 0), nil
+}
@@ -0,0 +1,124 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessOpenedIntent(t *testing.T) {
+	t.Setenv("SOLVER_PUB_KEY", "0x00000000000000000000000000000000000000aa")
+	t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+
+	orderID := "0x2222222222222222222222222222222222222222222222222222222222222222"
+	now := time.Unix(1_700_000_000, 0)
+	token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+
+	newSolver := func(t *testing.T) (*Hyperlane7683Solver, *orders.Store, *chainmock.EVMBackend, *chainmock.EVMBackend) {
+		origin := chainmock.NewEVMBackend(config.EthereumSepoliaChainID)
+		destination := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		destination.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(1)))
+		store, err := orders.NewStore(filepath.Join(t.TempDir(), "orders.json"))
+		require.NoError(t, err)
+
+		solver := NewHyperlane7683Solver(
+			func(chainID uint64) (EVMClient, error) {
+				if chainID == config.EthereumSepoliaChainID {
+					return origin, nil
+				}
+				return destination, nil
+			},
+			nil, nil, nil,
+			types.AllowBlockLists{},
+		)
+		solver.SetOrderStore(store)
+		solver.now = func() time.Time { return now }
+		return solver, store, origin, destination
+	}
+	args := func(fillDeadline uint32) types.ParsedArgs {
+		a := endToEndArgs(orderID, token.Hex(), "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+		a.ResolvedOrder.FillDeadline = fillDeadline
+		return a
+	}
+
+	t.Run("past fill deadline is expired", func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{}))
+		solver, store, _, destination := newSolver(t)
+
+		order := args(uint32(now.Unix()) - 60)
+		ok, err := solver.ProcessOpenedIntent(context.Background(), &order, 100)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Empty(t, destination.Sent())
+
+		stored, _ := store.Get(orderID)
+		assert.Equal(t, orders.StatusExpired, stored.Status)
+		assert.Contains(t, stored.Reason, "fill deadline 2023-11-14T22:12:20Z passed")
+	})
+
+	t.Run("origin block older than max order age is expired", func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Rules: config.RulesConfig{MaxOrderAge: "1h"}}))
+		solver, store, origin, destination := newSolver(t)
+		origin.SetBlockTime(100, uint64(now.Add(-2*time.Hour).Unix()))
+
+		order := args(uint32(now.Add(time.Hour).Unix()))
+		ok, err := solver.ProcessOpenedIntent(context.Background(), &order, 100)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Empty(t, destination.Sent())
+
+		stored, _ := store.Get(orderID)
+		assert.Equal(t, orders.StatusExpired, stored.Status)
+		assert.Equal(t, "opened 2h0m0s ago, max order age 1h0m0s", stored.Reason)
+	})
+
+	t.Run("recent order goes through the rules", func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Rules: config.RulesConfig{MaxOrderAge: "1h"}}))
+		solver, store, origin, _ := newSolver(t)
+		origin.SetBlockTime(100, uint64(now.Add(-10*time.Minute).Unix()))
+
+		order := args(0) // no deadline
+		ok, err := solver.ProcessOpenedIntent(context.Background(), &order, 100)
+		assert.False(t, ok)
+		assert.ErrorContains(t, err, "Insufficient balance")
+
+		stored, _ := store.Get(orderID)
+		assert.Equal(t, orders.StatusRejected, stored.Status)
+	})
+
+	t.Run("unreadable block time keeps the order", func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Rules: config.RulesConfig{MaxOrderAge: "1h"}}))
+		solver, _, _, _ := newSolver(t)
+		solver.getEVMClient = func(uint64) (EVMClient, error) { return nil, errors.New("rpc down") }
+
+		order := args(0)
+		assert.Empty(t, solver.expiredReason(context.Background(), &order, 100))
+	})
+
+	t.Run("Starknet block time", func(t *testing.T) {
+		backend := chainmock.NewStarknetBackend()
+		backend.SetBlockTime(7, uint64(now.Unix()))
+		solver, _, _, _ := newSolver(t)
+		solver.getStarknetClient = func(uint64) (rpc.RPCProvider, error) { return backend, nil }
+
+		openedAt, err := solver.blockTime(context.Background(), big.NewInt(config.StarknetSepoliaChainID), 7)
+		require.NoError(t, err)
+		assert.Equal(t, now, openedAt)
+	})
+}
@@ -0,0 +1,87 @@
+package hyperlane7683
+
+// Module: Order lifecycle events
+// - Publishes on the solver's event bus (see package events) when an order is first seen and
+//   when it becomes FILLED, SETTLED or REJECTED; metrics, webhooks and the admin API subscribe
+//   there instead of being called from the pipeline
+// - With an order store, an event is only published when the stored status changes, so retried
+//   settlements and replayed orders do not repeat it
+// - FAILED, LOST_RACE and EXPIRED have no event
+// - FILLED and SETTLED events carry the mined fill or settle transaction recorded in the order
+//   store and its explorer link (see config.ExplorerTxURL)
+
+import (
+	"slices"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/events"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// statusEvents maps the statuses with an event to its kind
+var statusEvents = map[orders.Status]events.Kind{
+	orders.StatusFilled:   events.OrderFilled,
+	orders.StatusSettled:  events.OrderSettled,
+	orders.StatusRejected: events.OrderRejected,
+}
+
+// eventTxKinds are the transaction kinds an event links to
+var eventTxKinds = map[events.Kind][]string{
+	events.OrderFilled:  {orders.TxKindFill, orders.TxKindFillSettle},
+	events.OrderSettled: {orders.TxKindSettle, orders.TxKindFillSettle},
+}
+
+// SetEventBus replaces the bus order events are published on; nil disables them
+func (f *Hyperlane7683Solver) SetEventBus(bus *events.Bus) {
+	f.eventBus = bus
+}
+
+// publishOrderEvent publishes an event of the order; args may be nil when only the ID is known
+func (f *Hyperlane7683Solver) publishOrderEvent(kind events.Kind, orderID string, args *types.ParsedArgs, reason string) {
+	if f.eventBus == nil {
+		return
+	}
+	event := events.Event{Kind: kind, OrderID: orderID, Reason: reason}
+	if args != nil {
+		if chainID := args.ResolvedOrder.OriginChainID; chainID != nil {
+			event.OriginChainID = chainID.Uint64()
+		}
+		if len(args.ResolvedOrder.FillInstructions) > 0 && args.ResolvedOrder.FillInstructions[0].DestinationChainID != nil {
+			event.DestinationChainID = args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
+		}
+	}
+	if tx, ok := f.eventTx(kind, orderID); ok {
+		event.TxHash = tx.TxHash
+		event.ExplorerURL = config.ExplorerTxURL(tx.ChainID, tx.TxHash)
+	}
+	f.eventBus.Publish(event)
+}
+
+// eventTx returns the last mined transaction of the order an event of kind links to
+func (f *Hyperlane7683Solver) eventTx(kind events.Kind, orderID string) (orders.TxCost, bool) {
+	txKinds, ok := eventTxKinds[kind]
+	if !ok || f.orderStore == nil {
+		return orders.TxCost{}, false
+	}
+	order, ok := f.orderStore.Get(orderID)
+	if !ok {
+		return orders.TxCost{}, false
+	}
+	for i := len(order.Costs) - 1; i >= 0; i-- {
+		cost := order.Costs[i]
+		if cost.TxHash != "" && slices.Contains(txKinds, cost.Kind) {
+			return cost, true
+		}
+	}
+	return orders.TxCost{}, false
+}
+
+// publishStatusChange publishes the event of status, if it has one, when the order left previous for it
+func (f *Hyperlane7683Solver) publishStatusChange(orderID string, args *types.ParsedArgs, previous, status orders.Status, reason string) {
+	kind, ok := statusEvents[status]
+	if !ok || previous == status {
+		return
+	}
+	f.publishOrderEvent(kind, orderID, args, reason)
+}
@@ -0,0 +1,140 @@
+package hyperlane7683
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/events"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// received drains the events already published on ch
+func received(ch <-chan events.Event) []events.Event {
+	var list []events.Event
+	for {
+		select {
+		case event := <-ch:
+			list = append(list, event)
+		default:
+			return list
+		}
+	}
+}
+
+// kinds returns the kinds of list, in order
+func kinds(list []events.Event) []events.Kind {
+	out := make([]events.Kind, len(list))
+	for i, event := range list {
+		out[i] = event.Kind
+	}
+	return out
+}
+
+func TestOrderEvents(t *testing.T) {
+	t.Setenv("SOLVER_PUB_KEY", "0x00000000000000000000000000000000000000aa")
+	t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+	require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{}))
+
+	orderID := "0x4444444444444444444444444444444444444444444444444444444444444444"
+	token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+
+	newSolver := func(t *testing.T, withStore bool) (*Hyperlane7683Solver, <-chan events.Event) {
+		destination := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		destination.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(1)))
+		solver := NewHyperlane7683Solver(
+			func(uint64) (EVMClient, error) { return destination, nil },
+			nil, nil, nil,
+			types.AllowBlockLists{},
+		)
+		if withStore {
+			store, err := orders.NewStore(filepath.Join(t.TempDir(), "orders.json"))
+			require.NoError(t, err)
+			solver.SetOrderStore(store)
+		}
+		solver.metrics = metrics.NewRegistry()
+		bus := events.NewBus()
+		solver.SetEventBus(bus)
+		ch, unsubscribe := bus.Subscribe(16)
+		t.Cleanup(unsubscribe)
+		return solver, ch
+	}
+
+	t.Run("observed then rejected with the reason code", func(t *testing.T) {
+		solver, ch := newSolver(t, true)
+		args := endToEndArgs(orderID, token.Hex(), "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+		_, err := solver.ProcessIntent(context.Background(), &args)
+		require.Error(t, err)
+
+		list := received(ch)
+		require.Equal(t, []events.Kind{events.OrderObserved, events.OrderRejected}, kinds(list))
+		assert.Equal(t, orderID, list[1].OrderID)
+		assert.Equal(t, RejectInsufficientBalance, list[1].Reason)
+		assert.Equal(t, uint64(config.BaseSepoliaChainID), list[1].DestinationChainID)
+		assert.False(t, list[1].At.IsZero())
+
+		// Seen again, e.g. replayed: nothing new
+		_, err = solver.ProcessIntent(context.Background(), &args)
+		require.Error(t, err)
+		assert.Empty(t, received(ch))
+	})
+
+	t.Run("published once per status change", func(t *testing.T) {
+		solver, ch := newSolver(t, true)
+		args := endToEndArgs(orderID, token.Hex(), "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+		solver.observeOrder(&args)
+		solver.recordOrderStatus(&args, orders.StatusFilled, "")
+		solver.recordOrderStatus(&args, orders.StatusFilled, "settlement attempt 1 failed, retrying")
+		solver.recordOrderStatus(&args, orders.StatusFailed, "rpc down")
+		solver.recordOrderStatus(&args, orders.StatusSettled, "")
+		assert.Equal(t, []events.Kind{events.OrderObserved, events.OrderFilled, events.OrderSettled}, kinds(received(ch)))
+	})
+
+	t.Run("filled and settled link their transactions", func(t *testing.T) {
+		t.Setenv("IS_DEVNET", "false")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		solver, ch := newSolver(t, true)
+		args := endToEndArgs(orderID, token.Hex(), "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+		solver.observeOrder(&args)
+		solver.recordTxCost(&args, orders.TxCost{ChainID: config.BaseSepoliaChainID, Kind: orders.TxKindFill, TxHash: "0xf1"})
+		solver.recordOrderStatus(&args, orders.StatusFilled, "")
+		solver.recordTxCost(&args, orders.TxCost{ChainID: config.BaseSepoliaChainID, Kind: orders.TxKindSettle, TxHash: "0x5e"})
+		solver.recordOrderStatus(&args, orders.StatusSettled, "")
+
+		list := received(ch)
+		require.Len(t, list, 3)
+		assert.Empty(t, list[0].TxHash)
+		assert.Equal(t, "0xf1", list[1].TxHash)
+		assert.Equal(t, "https://sepolia.basescan.org/tx/0xf1", list[1].ExplorerURL)
+		assert.Equal(t, "0x5e", list[2].TxHash)
+	})
+
+	t.Run("without a store", func(t *testing.T) {
+		solver, ch := newSolver(t, false)
+		args := endToEndArgs(orderID, token.Hex(), "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+		solver.observeOrder(&args)
+		solver.recordOrderStatus(&args, orders.StatusSettled, "")
+		assert.Equal(t, []events.Kind{events.OrderObserved, events.OrderSettled}, kinds(received(ch)))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		solver, ch := newSolver(t, true)
+		solver.SetEventBus(nil)
+		args := endToEndArgs(orderID, token.Hex(), "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+		solver.observeOrder(&args)
+		assert.Empty(t, received(ch))
+	})
+}
@@ -0,0 +1,223 @@
+package hyperlane7683
+
+// Module: Backfill order selection
+// - While a listener catches up on historical blocks, the Open events of each backfill range are
+//   queued and handed to the solver in the order of the selected strategy instead of block order
+// - Built-in strategies: fifo (block order, the default; nothing is queued), deadline (closest fill
+//   deadline first) and profit (highest expected profit first, valued at the price feed's prices;
+//   orders with a token it can't value go last)
+// - Custom policies implement OrderStrategy and are registered with RegisterOrderStrategy
+// - Queued events are only marked seen once handed to the solver, and block checkpoints wait
+//   until the queue is drained, so a restart never skips a queued order
+//
+// Settings:
+// - ORDER_SELECTION: name of the strategy used during backfill (default fifo)
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/pricefeed"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// PendingOrder is an Open event waiting in the backfill queue
+type PendingOrder struct {
+	Args        types.ParsedArgs
+	ChainName   string
+	BlockNumber uint64
+	TxHash      string
+	LogIndex    uint
+	dedupe      bool
+
+	profit       *big.Int // see expectedProfit; nil when the order can't be valued
+	profitValued bool
+}
+
+// expectedProfit returns the value received minus the value spent at the prices of the default
+// feed, valued once per queued order; ok is false when a side can't be valued
+func (o *PendingOrder) expectedProfit() (profit *big.Int, ok bool) {
+	if !o.profitValued {
+		o.profitValued = true
+		o.profit = orderProfit(context.Background(), pricefeed.Default(), &o.Args)
+	}
+	return o.profit, o.profit != nil
+}
+
+// OrderStrategy decides which queued order is handed to the solver first
+type OrderStrategy interface {
+	Name() string
+	// Less reports whether a goes before b; orders it doesn't separate keep block order
+	Less(a, b *PendingOrder) bool
+}
+
+// fifoStrategy keeps block order
+type fifoStrategy struct{}
+
+func (fifoStrategy) Name() string                 { return "fifo" }
+func (fifoStrategy) Less(_, _ *PendingOrder) bool { return false }
+
+// deadlineStrategy hands out the orders closest to their fill deadline first
+type deadlineStrategy struct{}
+
+func (deadlineStrategy) Name() string { return "deadline" }
+func (deadlineStrategy) Less(a, b *PendingOrder) bool {
+	return a.Args.ResolvedOrder.FillDeadline < b.Args.ResolvedOrder.FillDeadline
+}
+
+// profitStrategy hands out the orders with the highest expected profit first; orders that can't
+// be valued go last
+type profitStrategy struct{}
+
+func (profitStrategy) Name() string { return "profit" }
+func (profitStrategy) Less(a, b *PendingOrder) bool {
+	profitA, okA := a.expectedProfit()
+	profitB, okB := b.expectedProfit()
+	if !okA || !okB {
+		return okA && !okB
+	}
+	return profitA.Cmp(profitB) > 0
+}
+
+// orderProfit is the value of MinReceived minus the value of MaxSpent at feed's prices (fees are
+// ignored), nil when a side can't be valued
+func orderProfit(ctx context.Context, feed pricefeed.Feed, args *types.ParsedArgs) *big.Int {
+	sides := orderSides(args)
+	spent, err := sideValue(ctx, feed, sides[0])
+	if err != nil {
+		return nil
+	}
+	received, err := sideValue(ctx, feed, sides[1])
+	if err != nil {
+		return nil
+	}
+	return received.Sub(received, spent)
+}
+
+var (
+	orderStrategies = map[string]OrderStrategy{
+		"fifo":     fifoStrategy{},
+		"deadline": deadlineStrategy{},
+		"profit":   profitStrategy{},
+	}
+	orderStrategiesMu sync.RWMutex
+)
+
+// RegisterOrderStrategy makes a custom strategy selectable through ORDER_SELECTION; it replaces
+// a strategy registered under the same name
+func RegisterOrderStrategy(strategy OrderStrategy) {
+	orderStrategiesMu.Lock()
+	defer orderStrategiesMu.Unlock()
+	orderStrategies[strings.ToLower(strategy.Name())] = strategy
+}
+
+// OrderStrategyByName returns a registered strategy
+func OrderStrategyByName(name string) (OrderStrategy, error) {
+	orderStrategiesMu.RLock()
+	defer orderStrategiesMu.RUnlock()
+	strategy, ok := orderStrategies[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("unknown order selection strategy %q", name)
+	}
+	return strategy, nil
+}
+
+// orderStrategyFromEnv returns the strategy selected by ORDER_SELECTION, falling back to fifo
+func orderStrategyFromEnv() OrderStrategy {
+	strategy, err := OrderStrategyByName(envutil.GetEnvWithDefault("ORDER_SELECTION", "fifo"))
+	if err != nil {
+		fillerLog.Printf("⚠️  %v, using fifo\n", err)
+		return fifoStrategy{}
+	}
+	return strategy
+}
+
+// orderQueue holds the Open events of the backfill range being processed.
+// Used from the listener's polling goroutine only; a nil queue dispatches every event right away.
+type orderQueue struct {
+	strategy   OrderStrategy
+	collecting bool
+	pending    []*PendingOrder
+}
+
+// newOrderQueue returns a queue ordering by strategy, or nil when strategy keeps block order
+func newOrderQueue(strategy OrderStrategy) *orderQueue {
+	if strategy == nil {
+		return nil
+	}
+	if _, fifo := strategy.(fifoStrategy); fifo {
+		return nil
+	}
+	return &orderQueue{strategy: strategy}
+}
+
+// Begin starts queueing the events passed to Dispatch
+func (q *orderQueue) Begin() {
+	if q == nil {
+		return
+	}
+	q.collecting = true
+	q.pending = nil
+}
+
+// End stops queueing and drops orders left in the queue, e.g. after a failed range
+func (q *orderQueue) End() {
+	if q == nil {
+		return
+	}
+	q.collecting = false
+	q.pending = nil
+}
+
+// Len returns the number of queued orders
+func (q *orderQueue) Len() int {
+	if q == nil {
+		return 0
+	}
+	return len(q.pending)
+}
+
+// Dispatch queues the event while collecting, otherwise hands it to the handler (see dispatchOnce)
+func (q *orderQueue) Dispatch(dedupe bool, handler base.EventHandler, args types.ParsedArgs, chainName string, blockNumber uint64, txHash string, logIndex uint) (bool, error) {
+	if q == nil || !q.collecting {
+		return dispatchOnce(dedupe, handler, args, chainName, blockNumber, txHash, logIndex)
+	}
+	q.pending = append(q.pending, &PendingOrder{
+		Args:        args,
+		ChainName:   chainName,
+		BlockNumber: blockNumber,
+		TxHash:      txHash,
+		LogIndex:    logIndex,
+		dedupe:      dedupe,
+	})
+	return false, nil
+}
+
+// Drain hands the queued orders to the handler in strategy order. It stops when ctx is done;
+// the remaining orders are dropped and handled again once their blocks are processed again.
+func (q *orderQueue) Drain(ctx context.Context, handler base.EventHandler) error {
+	if q.Len() == 0 {
+		return nil
+	}
+	pending := q.pending
+	q.pending = nil
+	sort.SliceStable(pending, func(i, j int) bool { return q.strategy.Less(pending[i], pending[j]) })
+
+	fillerLog.Printf("%s🔢 Handing %d queued orders to the solver (%s order)\n", logutil.Prefix(pending[0].ChainName), len(pending), q.strategy.Name())
+	for _, order := range pending {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := dispatchOnce(order.dedupe, handler, order.Args, order.ChainName, order.BlockNumber, order.TxHash, order.LogIndex); err != nil {
+			fillerLog.Printf("❌ Failed to handle Open event: %v\n", err)
+		}
+	}
+	return nil
+}
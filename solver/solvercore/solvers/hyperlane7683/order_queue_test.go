@@ -0,0 +1,198 @@
+package hyperlane7683
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// queuedArgs builds an order with a fill deadline and amounts spent and received
+func queuedArgs(orderID string, fillDeadline uint32, spent, received int64) types.ParsedArgs {
+	return types.ParsedArgs{
+		OrderID: orderID,
+		ResolvedOrder: types.ResolvedCrossChainOrder{
+			FillDeadline: fillDeadline,
+			MaxSpent:     []types.Output{{Amount: big.NewInt(spent)}},
+			MinReceived:  []types.Output{{Amount: big.NewInt(received)}},
+		},
+	}
+}
+
+// lowestIDFirst is a custom strategy ordering by order ID
+type lowestIDFirst struct{}
+
+func (lowestIDFirst) Name() string { return "Lowest-ID" }
+func (lowestIDFirst) Less(a, b *PendingOrder) bool {
+	return a.Args.OrderID < b.Args.OrderID
+}
+
+func TestOrderQueue(t *testing.T) {
+	orders := []types.ParsedArgs{
+		queuedArgs("0x03", 300, 100, 150), // profit 50
+		queuedArgs("0x01", 100, 100, 110), // profit 10
+		queuedArgs("0x02", 200, 100, 190), // profit 90
+		queuedArgs("0x04", 100, 100, 150), // profit 50
+	}
+	run := func(t *testing.T, strategy OrderStrategy) []string {
+		queue := newOrderQueue(strategy)
+		var handled []string
+		handler := func(args types.ParsedArgs, _ string, _ uint64) (bool, error) {
+			handled = append(handled, args.OrderID)
+			return true, nil
+		}
+		queue.Begin()
+		for i, args := range orders {
+			_, err := queue.Dispatch(false, handler, args, "Base", uint64(10+i), "0xaa", 0)
+			require.NoError(t, err)
+		}
+		require.NoError(t, queue.Drain(context.Background(), handler))
+		queue.End()
+		return handled
+	}
+
+	t.Run("fifo dispatches in block order without queueing", func(t *testing.T) {
+		assert.Nil(t, newOrderQueue(fifoStrategy{}))
+		assert.Equal(t, []string{"0x03", "0x01", "0x02", "0x04"}, run(t, fifoStrategy{}))
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		assert.Equal(t, []string{"0x01", "0x04", "0x02", "0x03"}, run(t, deadlineStrategy{}), "ties keep block order")
+	})
+
+	t.Run("profit values tokens of different decimals", func(t *testing.T) {
+		usdc := "0x00000000000000000000000000000000000000a1"
+		weth := "0x00000000000000000000000000000000000000d4"
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+			{ChainID: config.BaseSepoliaChainID, Address: usdc, Symbol: "USDC", Decimals: 6, Price: "1"},
+			{ChainID: config.EthereumSepoliaChainID, Address: weth, Symbol: "WETH", Decimals: 18, Price: "2000"},
+		}}))
+		defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+
+		// Spends USDC on Base for WETH received on Ethereum Sepolia
+		priced := func(orderID string, spentUSDC, receivedMilliETH int64) types.ParsedArgs {
+			args := queuedArgs(orderID, 100, 0, 0)
+			args.ResolvedOrder.MaxSpent[0] = types.Output{Token: usdc, ChainID: big.NewInt(config.BaseSepoliaChainID), Amount: new(big.Int).Mul(big.NewInt(spentUSDC), big.NewInt(1e6))}
+			args.ResolvedOrder.MinReceived[0] = types.Output{Token: weth, ChainID: big.NewInt(config.EthereumSepoliaChainID), Amount: new(big.Int).Mul(big.NewInt(receivedMilliETH), big.NewInt(1e15))}
+			return args
+		}
+		unpriced := queuedArgs("0x04", 100, 100, 1_000_000)
+		orders := []types.ParsedArgs{
+			priced("0x01", 1000, 510), // profit $20, the largest raw difference
+			unpriced,
+			priced("0x02", 100, 100), // profit $100
+			priced("0x03", 10, 6),    // profit $2
+		}
+		queue := newOrderQueue(profitStrategy{})
+		var handled []string
+		handler := func(args types.ParsedArgs, _ string, _ uint64) (bool, error) {
+			handled = append(handled, args.OrderID)
+			return true, nil
+		}
+		queue.Begin()
+		for i, args := range orders {
+			_, err := queue.Dispatch(false, handler, args, "Base", uint64(10+i), "0xaa", 0)
+			require.NoError(t, err)
+		}
+		require.NoError(t, queue.Drain(context.Background(), handler))
+		assert.Equal(t, []string{"0x02", "0x01", "0x03", "0x04"}, handled, "orders that can't be valued go last")
+	})
+
+	t.Run("custom strategy selected through ORDER_SELECTION", func(t *testing.T) {
+		RegisterOrderStrategy(lowestIDFirst{})
+		t.Setenv("ORDER_SELECTION", "lowest-id")
+		strategy := orderStrategyFromEnv()
+		assert.Equal(t, "Lowest-ID", strategy.Name())
+		assert.Equal(t, []string{"0x01", "0x02", "0x03", "0x04"}, run(t, strategy))
+	})
+
+	t.Run("unknown strategy falls back to fifo", func(t *testing.T) {
+		_, err := OrderStrategyByName("cheapest")
+		assert.ErrorContains(t, err, `unknown order selection strategy "cheapest"`)
+		t.Setenv("ORDER_SELECTION", "cheapest")
+		assert.Equal(t, fifoStrategy{}, orderStrategyFromEnv())
+	})
+
+	t.Run("cancelled drain stops", func(t *testing.T) {
+		queue := newOrderQueue(deadlineStrategy{})
+		handled := 0
+		handler := func(types.ParsedArgs, string, uint64) (bool, error) { handled++; return true, nil }
+		queue.Begin()
+		_, err := queue.Dispatch(false, handler, orders[0], "Base", 10, "0xaa", 0)
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, queue.Drain(ctx, handler), context.Canceled)
+		assert.Zero(t, handled)
+		assert.Zero(t, queue.Len())
+	})
+}
+
+// TestBackfillOrderSelection tests that backfilled orders reach the solver in strategy order and that
+// no checkpoint is persisted past orders still waiting in the queue
+func TestBackfillOrderSelection(t *testing.T) {
+	t.Setenv("SOLVER_STATE_FILE", filepath.Join(t.TempDir(), "solver-state.json"))
+	t.Setenv("CHECKPOINT_BLOCKS", "5")
+	t.Setenv("ORDER_SELECTION", "deadline")
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+	config.ResetSeenEvents()
+	t.Cleanup(config.ResetSeenEvents)
+
+	settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	backend := chainmock.NewEVMBackend(config.EthereumSepoliaChainID)
+	backend.AddLog(openLogWithDeadline(t, settler, [32]byte{0x01}, 12, 300))
+	backend.AddLog(openLogWithDeadline(t, settler, [32]byte{0x02}, 13, 100))
+	backend.AddLog(openLogWithDeadline(t, settler, [32]byte{0x03}, 17, 200))
+	backend.SetBlockNumber(20)
+
+	cfg := &base.ListenerConfig{ChainName: "Ethereum", ContractAddress: settler.Hex(), MaxBlockRange: 1000}
+	baseListener := NewBaseListener(*cfg, backend, "EVM")
+	baseListener.SetLastProcessedBlock(1)
+	listener := &evmListener{
+		config:             cfg,
+		client:             backend,
+		contractAddress:    settler,
+		lastProcessedBlock: 1,
+		stopChan:           make(chan struct{}),
+		baseListener:       baseListener,
+		status:             baseListener.Status(),
+		checkpoint:         baseListener.checkpoint,
+		dedupeEvents:       true,
+		queue:              baseListener.queue,
+	}
+
+	var handled []byte
+	persisted := make(map[byte]uint64)
+	handler := func(args types.ParsedArgs, _ string, _ uint64) (bool, error) {
+		id := common.HexToHash(args.OrderID)[0]
+		handled = append(handled, id)
+		state, err := config.GetSolverState()
+		require.NoError(t, err)
+		persisted[id] = state.Networks["Ethereum"].LastIndexedBlock
+		return true, nil
+	}
+
+	require.NoError(t, listener.catchUpHistoricalBlocks(context.Background(), handler))
+	assert.Equal(t, []byte{0x02, 0x03, 0x01}, handled)
+	assert.Equal(t, map[byte]uint64{0x01: 11, 0x02: 11, 0x03: 11}, persisted, "no checkpoint past the first queued order")
+
+	state, err := config.GetSolverState()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20), state.Networks["Ethereum"].LastIndexedBlock)
+
+	// Polling after the backfill dispatches right away
+	backend.AddLog(openLogWithDeadline(t, settler, [32]byte{0x04}, 22, 50))
+	_, err = listener.processBlockRange(context.Background(), 21, 22, handler)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x02, 0x03, 0x01, 0x04}, handled)
+}
@@ -0,0 +1,164 @@
+package hyperlane7683
+
+// Module: EVM fill origin_data validation
+// - Before an EVM fill is sent, its calldata is packed and decoded again with the router ABI, and
+//   the origin_data it carries is decoded as the OrderData struct of OrderEncoder.sol
+// - The decoded order must match the ResolvedCrossChainOrder the rules approved: origin and
+//   destination domains, destination settler, input and output tokens and amounts, and fill deadline
+// - A mismatch fails the fill with ErrOriginDataMismatch before any approval or transaction, so a
+//   conversion bug (e.g. in the Starknet → EVM origin_data translation) never moves funds. The
+//   mismatch is the order's, so it doesn't count against the chain's circuit breaker.
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// ErrOriginDataMismatch is returned when a fill's origin_data doesn't describe the resolved order
+var ErrOriginDataMismatch = errors.New("fill origin_data does not match the resolved order")
+
+// evmOrderData mirrors the OrderData struct of OrderEncoder.sol
+type evmOrderData struct {
+	Sender             [32]byte
+	Recipient          [32]byte
+	InputToken         [32]byte
+	OutputToken        [32]byte
+	AmountIn           *big.Int
+	AmountOut          *big.Int
+	SenderNonce        *big.Int
+	OriginDomain       uint32
+	DestinationDomain  uint32
+	DestinationSettler [32]byte
+	FillDeadline       uint32
+	Data               []byte
+}
+
+// orderDataArguments decodes abi.encode(OrderData)
+var orderDataArguments = func() abi.Arguments {
+	tuple, err := abi.NewType("tuple", "", []abi.ArgumentMarshaling{
+		{Name: "sender", Type: "bytes32"},
+		{Name: "recipient", Type: "bytes32"},
+		{Name: "inputToken", Type: "bytes32"},
+		{Name: "outputToken", Type: "bytes32"},
+		{Name: "amountIn", Type: "uint256"},
+		{Name: "amountOut", Type: "uint256"},
+		{Name: "senderNonce", Type: "uint256"},
+		{Name: "originDomain", Type: "uint32"},
+		{Name: "destinationDomain", Type: "uint32"},
+		{Name: "destinationSettler", Type: "bytes32"},
+		{Name: "fillDeadline", Type: "uint32"},
+		{Name: "data", Type: "bytes"},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return abi.Arguments{{Type: tuple}}
+}()
+
+// decodeFillOriginData packs fill(orderId, originData, fillerData) with the router ABI and decodes
+// the origin_data back out of the calldata as an OrderData
+func decodeFillOriginData(orderID [32]byte, originData, fillerData []byte) (evmOrderData, error) {
+	routerABI, err := contracts.Hyperlane7683MetaData.GetAbi()
+	if err != nil {
+		return evmOrderData{}, fmt.Errorf("failed to load router ABI: %w", err)
+	}
+	calldata, err := routerABI.Pack("fill", orderID, originData, fillerData)
+	if err != nil {
+		return evmOrderData{}, fmt.Errorf("%w: failed to encode fill call: %v", ErrOriginDataMismatch, err)
+	}
+	inputs, err := routerABI.Methods["fill"].Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return evmOrderData{}, fmt.Errorf("%w: failed to decode fill call: %v", ErrOriginDataMismatch, err)
+	}
+
+	values, err := orderDataArguments.Unpack(inputs[1].([]byte))
+	if err != nil {
+		return evmOrderData{}, fmt.Errorf("%w: origin_data is not an OrderData: %v", ErrOriginDataMismatch, err)
+	}
+	return *abi.ConvertType(values[0], new(evmOrderData)).(*evmOrderData), nil
+}
+
+// validateFillOriginData checks the origin_data of the order's first fill instruction against the
+// resolved order; handlers see one leg of multi-leg orders, with the outputs paid on its chain
+func validateFillOriginData(args *types.ParsedArgs, orderID [32]byte, fillerData []byte) error {
+	order := args.ResolvedOrder
+	if len(order.FillInstructions) == 0 {
+		return fmt.Errorf("no fill instructions found")
+	}
+	instruction := order.FillInstructions[0]
+
+	data, err := decodeFillOriginData(orderID, instruction.OriginData, fillerData)
+	if err != nil {
+		return err
+	}
+
+	var mismatches []string
+	mismatch := func(field string, got, want interface{}) {
+		mismatches = append(mismatches, fmt.Sprintf("%s %v, order has %v", field, got, want))
+	}
+	if !domainMatchesChain(data.OriginDomain, order.OriginChainID) {
+		mismatch("origin domain", data.OriginDomain, order.OriginChainID)
+	}
+	if !domainMatchesChain(data.DestinationDomain, instruction.DestinationChainID) {
+		mismatch("destination domain", data.DestinationDomain, instruction.DestinationChainID)
+	}
+	if !sameBytes32(data.DestinationSettler, instruction.DestinationSettler) {
+		mismatch("destination settler", common.Hash(data.DestinationSettler).Hex(), instruction.DestinationSettler)
+	}
+	if data.FillDeadline != order.FillDeadline {
+		mismatch("fill deadline", data.FillDeadline, order.FillDeadline)
+	}
+	mismatches = append(mismatches, outputMismatches("output", order.MaxSpent, data.OutputToken, data.AmountOut)...)
+	mismatches = append(mismatches, outputMismatches("input", order.MinReceived, data.InputToken, data.AmountIn)...)
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%w: %s", ErrOriginDataMismatch, strings.Join(mismatches, "; "))
+	}
+	return nil
+}
+
+// outputMismatches compares the single output of a resolved order side with the origin_data token and amount
+func outputMismatches(side string, outputs []types.Output, token [32]byte, amount *big.Int) []string {
+	if len(outputs) != 1 {
+		return []string{fmt.Sprintf("origin_data has one %s token, order has %d", side, len(outputs))}
+	}
+	var mismatches []string
+	if !sameBytes32(token, outputs[0].Token) {
+		mismatches = append(mismatches, fmt.Sprintf("%s token %s, order has %s", side, common.Hash(token).Hex(), outputs[0].Token))
+	}
+	if outputs[0].Amount == nil || amount.Cmp(outputs[0].Amount) != 0 {
+		mismatches = append(mismatches, fmt.Sprintf("%s amount %s, order has %v", side, amount, outputs[0].Amount))
+	}
+	return mismatches
+}
+
+// domainMatchesChain reports whether a Hyperlane domain is the chain ID's, either because they are
+// equal (as on EVM orders, which carry domains as chain IDs) or through the domain registry
+func domainMatchesChain(domain uint32, chainID *big.Int) bool {
+	if chainID == nil || !chainID.IsUint64() {
+		return false
+	}
+	if chainID.Uint64() == uint64(domain) {
+		return true
+	}
+	registry, err := config.GetDomainRegistry()
+	if err != nil {
+		return false
+	}
+	registered, err := registry.DomainForChainID(chainID.Uint64())
+	return err == nil && registered == domain
+}
+
+// sameBytes32 compares a bytes32 with an address of any width (EVM, Starknet felt, bytes32)
+func sameBytes32(value [32]byte, address string) bool {
+	return common.HexToHash(address) == common.Hash(value)
+}
@@ -0,0 +1,122 @@
+package hyperlane7683
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// evmOriginData encodes the OrderData the router emits for fill instruction i of an order
+func evmOriginData(args types.ParsedArgs, i int) []byte {
+	leg := legArgs(&args, i).ResolvedOrder
+	instruction := leg.FillInstructions[0]
+	encoded, err := orderDataArguments.Pack(evmOrderData{
+		Sender:             common.HexToHash(args.ResolvedOrder.User),
+		Recipient:          common.HexToHash(leg.MaxSpent[0].Recipient),
+		InputToken:         common.HexToHash(leg.MinReceived[0].Token),
+		OutputToken:        common.HexToHash(leg.MaxSpent[0].Token),
+		AmountIn:           leg.MinReceived[0].Amount,
+		AmountOut:          leg.MaxSpent[0].Amount,
+		SenderNonce:        big.NewInt(1),
+		OriginDomain:       uint32(leg.OriginChainID.Uint64()),
+		DestinationDomain:  uint32(instruction.DestinationChainID.Uint64()),
+		DestinationSettler: common.HexToHash(instruction.DestinationSettler),
+		FillDeadline:       leg.FillDeadline,
+		Data:               []byte{},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return encoded
+}
+
+func TestValidateFillOriginData(t *testing.T) {
+	config.InitializeNetworks()
+	orderID := [32]byte{0x11}
+	newArgs := func() types.ParsedArgs {
+		args := endToEndArgs(common.Hash(orderID).Hex(), "0x00000000000000000000000000000000000000a1", "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+		args.ResolvedOrder.FillDeadline = 1_700_000_000
+		args.ResolvedOrder.FillInstructions[0].OriginData = evmOriginData(args, 0)
+		return args
+	}
+
+	t.Run("origin_data describing the order passes", func(t *testing.T) {
+		args := newArgs()
+		require.NoError(t, validateFillOriginData(&args, orderID, nil))
+
+		decoded, err := decodeFillOriginData(orderID, args.ResolvedOrder.FillInstructions[0].OriginData, nil)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(config.BaseSepoliaChainID), decoded.DestinationDomain)
+		assert.Equal(t, big.NewInt(1000), decoded.AmountOut)
+		assert.Equal(t, uint32(1_700_000_000), decoded.FillDeadline)
+	})
+
+	t.Run("Starknet orders match through the domain registry", func(t *testing.T) {
+		registry, err := config.GetDomainRegistry()
+		require.NoError(t, err)
+		domain, err := registry.DomainForChainID(config.StarknetSepoliaChainID)
+		require.NoError(t, err)
+		assert.True(t, domainMatchesChain(domain, big.NewInt(config.StarknetSepoliaChainID)))
+		assert.False(t, domainMatchesChain(domain+1, big.NewInt(config.StarknetSepoliaChainID)))
+		assert.False(t, domainMatchesChain(domain, nil))
+	})
+
+	t.Run("fields differing from the order are all reported", func(t *testing.T) {
+		args := newArgs()
+		order := &args.ResolvedOrder
+		order.FillDeadline++
+		order.MaxSpent[0].Amount = big.NewInt(999)
+		order.MinReceived[0].Token = "0x00000000000000000000000000000000000000ee"
+		order.FillInstructions[0].DestinationSettler = "0x00000000000000000000000000000000000000b3"
+		order.FillInstructions[0].DestinationChainID = big.NewInt(config.OptimismSepoliaChainID)
+
+		err := validateFillOriginData(&args, orderID, nil)
+		require.ErrorIs(t, err, ErrOriginDataMismatch)
+		for _, want := range []string{
+			"destination domain 84532, order has 11155420",
+			"destination settler 0x00000000000000000000000000000000000000000000000000000000000000b2",
+			"fill deadline 1700000000, order has 1700000001",
+			"output amount 1000, order has 999",
+			"input token 0x00000000000000000000000000000000000000000000000000000000000000d4",
+		} {
+			assert.ErrorContains(t, err, want)
+		}
+	})
+
+	t.Run("orders with several outputs on the leg are rejected", func(t *testing.T) {
+		args := newArgs()
+		args.ResolvedOrder.MaxSpent = append(args.ResolvedOrder.MaxSpent, args.ResolvedOrder.MaxSpent[0])
+		assert.ErrorContains(t, validateFillOriginData(&args, orderID, nil), "origin_data has one output token, order has 2")
+	})
+
+	t.Run("a data payload reaches the fill calldata", func(t *testing.T) {
+		args := newArgs()
+		instruction := &args.ResolvedOrder.FillInstructions[0]
+		values, err := orderDataArguments.Unpack(instruction.OriginData)
+		require.NoError(t, err)
+		withData := *abi.ConvertType(values[0], new(evmOrderData)).(*evmOrderData)
+		withData.Data = []byte("custom filler instructions")
+		instruction.OriginData, err = orderDataArguments.Pack(withData)
+		require.NoError(t, err)
+
+		require.NoError(t, validateFillOriginData(&args, orderID, nil))
+		decoded, err := decodeFillOriginData(orderID, instruction.OriginData, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("custom filler instructions"), decoded.Data)
+	})
+
+	t.Run("origin_data that isn't an OrderData is rejected", func(t *testing.T) {
+		args := newArgs()
+		args.ResolvedOrder.FillInstructions[0].OriginData = []byte{0x01}
+		err := validateFillOriginData(&args, orderID, nil)
+		assert.ErrorIs(t, err, ErrOriginDataMismatch)
+		assert.ErrorContains(t, err, "origin_data is not an OrderData")
+	})
+}
@@ -0,0 +1,174 @@
+package hyperlane7683
+
+// Module: ERC-2612 permit approvals
+// - EVM tokens implementing ERC-2612 are approved for the router with a signed permit instead of
+//   approve: the solver signs Permit(owner, spender, value, nonce, deadline) against the token's own
+//   DOMAIN_SEPARATOR and sends permit(...), checked against the spend and tx limits like an approve
+// - The router pulls fill outputs from the filler with transferFrom and the solver is an EOA that
+//   can't batch calls, so the permit is still sent ahead of the fill; the amount follows the approval
+//   policy like an approve's
+// - ERC-2612 has no EIP-165 interface id, so support is detected by try-calling DOMAIN_SEPARATOR()
+//   and nonces(owner), once per token and handler. A permit that can't be sent or reverts (e.g. DAI's
+//   permit, which has another signature) marks the token unsupported and falls back to approve
+//
+// Settings:
+// - PERMIT_APPROVALS: use permits for tokens that support them (default true)
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// permitDeadline bounds how long a signed permit can be used
+const permitDeadline = 30 * time.Minute
+
+// Permit support of a token, as last seen; the zero value is not known yet
+const (
+	permitUnknown = iota
+	permitSupported
+	permitUnsupported
+)
+
+// permitTypeHash is the EIP-712 type hash of ERC-2612 permits
+var permitTypeHash = crypto.Keccak256Hash([]byte("Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)"))
+
+// erc2612ABI holds the ERC-2612 functions the solver calls
+var erc2612ABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(`[
+		{"type":"function","name":"DOMAIN_SEPARATOR","inputs":[],"outputs":[{"name":"","type":"bytes32"}],"stateMutability":"view"},
+		{"type":"function","name":"nonces","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+		{"type":"function","name":"permit","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"},{"name":"value","type":"uint256"},{"name":"deadline","type":"uint256"},{"name":"v","type":"uint8"},{"name":"r","type":"bytes32"},{"name":"s","type":"bytes32"}],"outputs":[],"stateMutability":"nonpayable"}
+	]`))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// permitSigner signs a 32-byte EIP-712 digest, returning the 65-byte [R || S || V] signature with V in {0, 1}
+type permitSigner func(digest []byte) ([]byte, error)
+
+// permitSignerFromEnv returns a signer using the solver's EVM key, or nil when permits are disabled
+// or the key doesn't belong to from
+func permitSignerFromEnv(from common.Address) permitSigner {
+	if !envutil.GetEnvBool("PERMIT_APPROVALS", true) {
+		return nil
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(envutil.GetConditionalAccountEnv("SOLVER_PRIVATE_KEY"), "0x"))
+	if err != nil || crypto.PubkeyToAddress(key.PublicKey) != from {
+		return nil
+	}
+	return func(digest []byte) ([]byte, error) { return crypto.Sign(digest, key) }
+}
+
+// permitDigest returns the EIP-712 digest of a permit under the token's domain separator
+func permitDigest(domainSeparator [32]byte, owner, spender common.Address, value, nonce, deadline *big.Int) []byte {
+	structHash := crypto.Keccak256(
+		permitTypeHash.Bytes(),
+		common.LeftPadBytes(owner.Bytes(), 32),
+		common.LeftPadBytes(spender.Bytes(), 32),
+		common.LeftPadBytes(value.Bytes(), 32),
+		common.LeftPadBytes(nonce.Bytes(), 32),
+		common.LeftPadBytes(deadline.Bytes(), 32),
+	)
+	return crypto.Keccak256([]byte{0x19, 0x01}, domainSeparator[:], structHash)
+}
+
+// approveWithPermit raises the allowance of spender to amount (per the approval policy) with a permit.
+// It returns false, leaving the approval to approve, when permits are disabled or the token has none.
+func (h *HyperlaneEVM) approveWithPermit(ctx context.Context, args *types.ParsedArgs, tokenAddr, spender common.Address, amount *big.Int) (bool, error) {
+	if h.signPermit == nil || h.permitTokens[tokenAddr] == permitUnsupported {
+		return false, nil
+	}
+	if h.permitTokens == nil {
+		h.permitTokens = make(map[common.Address]int)
+	}
+	token := bind.NewBoundContract(tokenAddr, erc2612ABI, h.client, h.client, h.client)
+
+	domainSeparator, nonce, err := permitState(ctx, token, h.signer.From)
+	if err != nil {
+		h.permitTokens[tokenAddr] = permitUnsupported
+		return false, nil
+	}
+
+	value := h.approvalPolicy.approveAmount(amount)
+	deadline := big.NewInt(time.Now().Add(permitDeadline).Unix())
+	signature, err := h.signPermit(permitDigest(domainSeparator, h.signer.From, spender, value, nonce, deadline))
+	if err != nil {
+		return false, fmt.Errorf("failed to sign permit: %w", err)
+	}
+	var r, s [32]byte
+	copy(r[:], signature[:32])
+	copy(s[:], signature[32:64])
+	v := signature[64] + 27
+
+	gasPrice, err := h.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	if err := h.spendLimits.CheckGas(h.chainID, new(big.Int).Mul(gasPrice, big.NewInt(approveGasLimit))); err != nil {
+		return false, err
+	}
+	release, err := h.txLimits.Acquire(ctx, h.chainID)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	// The gas is estimated, so a token whose permit doesn't match ERC-2612 fails here without sending
+	opts := *h.signer
+	opts.Context = ctx
+	opts.GasPrice = gasPrice
+	tx, err := token.Transact(&opts, "permit", h.signer.From, spender, value, deadline, v, r, s)
+	sent := h.auditSend(args, orders.TxKindApprove, tokenAddr, tx, err)
+	if err != nil {
+		fillerLog.Printf("   ⚠️  Permit for %s failed (%v), approving instead\n", tokenAddr.Hex(), err)
+		h.permitTokens[tokenAddr] = permitUnsupported
+		return false, nil
+	}
+	fillerLog.Printf("   🚀 Permit transaction sent: %s\n", tx.Hash().Hex())
+
+	receipt, err := bind.WaitMined(ctx, h.client, tx)
+	h.txAudit.record(evmReceiptAuditEntry(sent, receipt, err))
+	if err != nil {
+		return false, fmt.Errorf("failed to wait for permit confirmation: %w", err)
+	}
+	cost := evmTxCost(h.chainID, orders.TxKindApprove, tx, receipt, nil)
+	h.recordCost.record(args, cost)
+	h.spendLimits.RecordTx(cost)
+
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		fillerLog.Printf("   ⚠️  Permit for %s reverted, approving instead\n", tokenAddr.Hex())
+		h.permitTokens[tokenAddr] = permitUnsupported
+		return false, nil
+	}
+	h.permitTokens[tokenAddr] = permitSupported
+	fillerLog.Printf("   ✅ Permit confirmed! Gas used: %d\n", receipt.GasUsed)
+	return true, nil
+}
+
+// permitState reads the token's domain separator and the owner's permit nonce
+func permitState(ctx context.Context, token *bind.BoundContract, owner common.Address) ([32]byte, *big.Int, error) {
+	callOpts := &bind.CallOpts{Context: ctx}
+	var separator, nonce []interface{}
+	if err := token.Call(callOpts, &separator, "DOMAIN_SEPARATOR"); err != nil {
+		return [32]byte{}, nil, err
+	}
+	if err := token.Call(callOpts, &nonce, "nonces", owner); err != nil {
+		return [32]byte{}, nil, err
+	}
+	return separator[0].([32]byte), nonce[0].(*big.Int), nil
+}
@@ -0,0 +1,119 @@
+package hyperlane7683
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+func TestPermitApprovals(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(config.BaseSepoliaChainID))
+	require.NoError(t, err)
+	router := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	separator := common.HexToHash("0x5e")
+	permitSelector := chainmock.Selector("permit(address,address,uint256,uint256,uint8,bytes32,bytes32)")
+	approveSelector := chainmock.Selector("approve(address,uint256)")
+
+	newHandler := func(backend *chainmock.EVMBackend) *HyperlaneEVM {
+		handler := NewHyperlaneEVM(backend, signer, config.BaseSepoliaChainID)
+		handler.spendLimits = NewSpendLimiter(nil, nil)
+		handler.txLimits = NewTxRateLimiter(TxRateLimits{})
+		handler.signPermit = func(digest []byte) ([]byte, error) { return crypto.Sign(digest, key) }
+		return handler
+	}
+	permitToken := func(backend *chainmock.EVMBackend, token common.Address) {
+		backend.HandleCall(token, chainmock.Selector("allowance(address,address)"), uint256Result(big.NewInt(0)))
+		backend.HandleCall(token, chainmock.Selector("DOMAIN_SEPARATOR()"), func(ethereum.CallMsg) ([]byte, error) { return separator.Bytes(), nil })
+		backend.HandleCall(token, chainmock.Selector("nonces(address)"), uint256Result(big.NewInt(3)))
+	}
+
+	t.Run("permit tokens are approved with a signed permit", func(t *testing.T) {
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+		permitToken(backend, token)
+
+		handler := newHandler(backend)
+		require.NoError(t, handler.ensureTokenApproval(context.Background(), nil, token, router, big.NewInt(500)))
+
+		sent := backend.SentTo(token)
+		require.Len(t, sent, 1)
+		data := sent[0].Data()
+		require.True(t, bytes.HasPrefix(data, permitSelector[:]))
+		args, err := erc2612ABI.Methods["permit"].Inputs.Unpack(data[4:])
+		require.NoError(t, err)
+		assert.Equal(t, signer.From, args[0])
+		assert.Equal(t, router, args[1])
+		assert.Equal(t, big.NewInt(500), args[2])
+
+		// The signature recovers to the solver over the permit's EIP-712 digest
+		r, s := args[5].([32]byte), args[6].([32]byte)
+		signature := append(append(r[:], s[:]...), args[4].(uint8)-27)
+		digest := permitDigest(separator, signer.From, router, big.NewInt(500), big.NewInt(3), args[3].(*big.Int))
+		pub, err := crypto.SigToPub(digest, signature)
+		require.NoError(t, err)
+		assert.Equal(t, signer.From, crypto.PubkeyToAddress(*pub))
+		assert.Equal(t, permitSupported, handler.permitTokens[token])
+	})
+
+	t.Run("tokens without permits are approved", func(t *testing.T) {
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		token := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+		backend.HandleCall(token, chainmock.Selector("allowance(address,address)"), uint256Result(big.NewInt(0)))
+
+		handler := newHandler(backend)
+		for i := 0; i < 2; i++ {
+			require.NoError(t, handler.ensureTokenApproval(context.Background(), nil, token, router, big.NewInt(500)))
+		}
+		sent := backend.SentTo(token)
+		require.Len(t, sent, 2)
+		for _, tx := range sent {
+			assert.True(t, bytes.HasPrefix(tx.Data(), approveSelector[:]))
+		}
+		assert.Equal(t, permitUnsupported, handler.permitTokens[token], "support is detected once")
+	})
+
+	t.Run("a reverted permit falls back to approve", func(t *testing.T) {
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		token := common.HexToAddress("0x00000000000000000000000000000000000000a3")
+		permitToken(backend, token)
+		backend.OnTransaction(token, func(tx *gethtypes.Transaction) uint64 {
+			if bytes.HasPrefix(tx.Data(), permitSelector[:]) {
+				return gethtypes.ReceiptStatusFailed
+			}
+			return gethtypes.ReceiptStatusSuccessful
+		})
+
+		handler := newHandler(backend)
+		require.NoError(t, handler.ensureTokenApproval(context.Background(), nil, token, router, big.NewInt(500)))
+		sent := backend.SentTo(token)
+		require.Len(t, sent, 2)
+		assert.True(t, bytes.HasPrefix(sent[0].Data(), permitSelector[:]))
+		assert.True(t, bytes.HasPrefix(sent[1].Data(), approveSelector[:]))
+		assert.Equal(t, permitUnsupported, handler.permitTokens[token])
+	})
+
+	t.Run("disabled without a signer", func(t *testing.T) {
+		t.Setenv("PERMIT_APPROVALS", "false")
+		t.Setenv("SOLVER_PRIVATE_KEY", common.Bytes2Hex(crypto.FromECDSA(key)))
+		t.Setenv("IS_DEVNET", "")
+		assert.Nil(t, permitSignerFromEnv(signer.From))
+
+		t.Setenv("PERMIT_APPROVALS", "")
+		assert.NotNil(t, permitSignerFromEnv(signer.From))
+		assert.Nil(t, permitSignerFromEnv(router), "the key must belong to the signer")
+	})
+}
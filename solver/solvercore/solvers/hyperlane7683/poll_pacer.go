@@ -0,0 +1,94 @@
+package hyperlane7683
+
+// Module: Listener poll pacing
+// - Spreads polls with random jitter around the poll interval, so listeners sharing an RPC
+//   provider don't hit it in lockstep
+// - Optional idle backoff: once a listener saw no new Open events for a while, each further idle
+//   poll doubles the wait up to a cap; the next poll that finds events goes back to the poll interval
+// - Only events count as activity: testnets produce blocks whether or not anyone opens orders
+//
+// Settings:
+// - POLL_JITTER_PERCENT: +/- spread applied to every wait, in percent of it (default 10, 0 = off)
+// - POLL_IDLE_BACKOFF_SECONDS: time without events before backing off (default 0 = never)
+// - POLL_IDLE_MAX_INTERVAL_MS: longest wait while idle (default 30000)
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+)
+
+const (
+	defaultPollJitterPercent     = 10
+	defaultPollIdleMaxIntervalMs = 30000
+)
+
+// pollPacer decides how long a listener waits before its next poll. Used from the listener's
+// polling goroutine only; a nil pacer always waits the poll interval.
+type pollPacer struct {
+	chainName   string
+	jitter      float64       // fraction of the wait, e.g. 0.1 for +/-10%
+	idleAfter   time.Duration // 0 disables the idle backoff
+	maxInterval time.Duration
+	eventsSeen  uint64
+	lastActive  time.Time
+	current     time.Duration // wait before jitter; 0 until the first poll
+	now         func() time.Time
+	rand        func() float64 // in [0, 1)
+}
+
+// newPollPacer creates a pacer for chainName
+func newPollPacer(chainName string, jitterPercent int, idleAfter, maxInterval time.Duration) *pollPacer {
+	return &pollPacer{
+		chainName:   chainName,
+		jitter:      float64(max(jitterPercent, 0)) / 100,
+		idleAfter:   idleAfter,
+		maxInterval: maxInterval,
+		now:         time.Now,
+		rand:        rand.Float64,
+	}
+}
+
+// pollPacerFromEnv creates a pacer for chainName configured by the POLL_* settings
+func pollPacerFromEnv(chainName string) *pollPacer {
+	return newPollPacer(chainName,
+		envutil.GetEnvInt("POLL_JITTER_PERCENT", defaultPollJitterPercent),
+		time.Duration(envutil.GetEnvInt("POLL_IDLE_BACKOFF_SECONDS", 0))*time.Second,
+		time.Duration(envutil.GetEnvInt("POLL_IDLE_MAX_INTERVAL_MS", defaultPollIdleMaxIntervalMs))*time.Millisecond)
+}
+
+// Next returns the wait before the next poll, given the poll interval and the listener's progress
+// after the poll that just ran
+func (p *pollPacer) Next(interval time.Duration, snapshot base.ListenerSnapshot) time.Duration {
+	if p == nil {
+		return interval
+	}
+	now := p.now()
+	switch {
+	case p.lastActive.IsZero() || snapshot.EventsSeen > p.eventsSeen:
+		if p.current > interval {
+			listenerLog.Printf("%s⏩ New events, polling every %s again\n", logutil.Prefix(p.chainName), interval)
+		}
+		p.lastActive = now
+		p.current = interval
+	case p.idleAfter > 0 && now.Sub(p.lastActive) >= p.idleAfter:
+		next := min(max(p.current, interval)*2, max(p.maxInterval, interval))
+		if p.current <= interval && next > interval {
+			listenerLog.Printf("%s⏪ No new events for %s, slowing polling down (up to %s)\n",
+				logutil.Prefix(p.chainName), now.Sub(p.lastActive).Round(time.Second), max(p.maxInterval, interval))
+		}
+		p.current = next
+	default:
+		p.current = interval
+	}
+	p.eventsSeen = snapshot.EventsSeen
+
+	if p.jitter == 0 {
+		return p.current
+	}
+	spread := (p.rand()*2 - 1) * p.jitter
+	return time.Duration(float64(p.current) * (1 + spread))
+}
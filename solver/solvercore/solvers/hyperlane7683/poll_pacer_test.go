@@ -0,0 +1,59 @@
+package hyperlane7683
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollPacer(t *testing.T) {
+	const interval = time.Second
+	idle := func(events uint64) base.ListenerSnapshot { return base.ListenerSnapshot{EventsSeen: events} }
+
+	t.Run("backs off while idle and resets on events", func(t *testing.T) {
+		clock := time.Unix(1_700_000_000, 0)
+		pacer := newPollPacer("Base", 0, 10*time.Second, 5*time.Second)
+		pacer.now = func() time.Time { return clock }
+
+		assert.Equal(t, interval, pacer.Next(interval, idle(3)), "first poll")
+		clock = clock.Add(5 * time.Second)
+		assert.Equal(t, interval, pacer.Next(interval, idle(3)), "not idle long enough")
+
+		clock = clock.Add(5 * time.Second)
+		assert.Equal(t, 2*time.Second, pacer.Next(interval, idle(3)))
+		assert.Equal(t, 4*time.Second, pacer.Next(interval, idle(3)))
+		assert.Equal(t, 5*time.Second, pacer.Next(interval, idle(3)), "capped")
+		assert.Equal(t, 5*time.Second, pacer.Next(interval, idle(3)))
+
+		assert.Equal(t, interval, pacer.Next(interval, idle(4)), "new events")
+		clock = clock.Add(5 * time.Second)
+		assert.Equal(t, interval, pacer.Next(interval, idle(4)))
+	})
+
+	t.Run("backoff disabled keeps the poll interval", func(t *testing.T) {
+		clock := time.Unix(1_700_000_000, 0)
+		pacer := newPollPacer("Base", 0, 0, 5*time.Second)
+		pacer.now = func() time.Time { return clock }
+
+		pacer.Next(interval, idle(0))
+		clock = clock.Add(time.Hour)
+		assert.Equal(t, interval, pacer.Next(interval, idle(0)))
+	})
+
+	t.Run("jitter spreads the wait", func(t *testing.T) {
+		pacer := newPollPacer("Base", 10, 0, 0)
+		pacer.rand = func() float64 { return 0 }
+		assert.Equal(t, 900*time.Millisecond, pacer.Next(interval, idle(0)))
+		pacer.rand = func() float64 { return 0.5 }
+		assert.Equal(t, interval, pacer.Next(interval, idle(0)))
+		pacer.rand = func() float64 { return 0.999 }
+		assert.InDelta(t, float64(1100*time.Millisecond), float64(pacer.Next(interval, idle(0))), float64(time.Millisecond))
+	})
+
+	t.Run("nil pacer waits the poll interval", func(t *testing.T) {
+		var pacer *pollPacer
+		assert.Equal(t, interval, pacer.Next(interval, idle(0)))
+	})
+}
@@ -0,0 +1,531 @@
+package hyperlane7683
+
+// Module: Cross-chain inventory rebalancer
+// - Registry tokens with a targetBalance are rebalanced through the Hyperlane7683 routers themselves:
+//   when a chain's balance drops below the token's lowBalance (or its target when unset), the solver
+//   opens an order of its own on the chain holding the largest excess of the same symbol, paying
+//   itself the output on the low chain
+// - An order moves the smaller of the deficit (up to the target) and the excess (above the target),
+//   and leaves REBALANCE_FEE_BPS of the amount to whoever fills it. The solver never fills its own
+//   orders (it would only move its funds back), so other fillers carry them out
+// - Fees left to fillers are capped over a rolling 24h window; the gas of opening counts towards the
+//   spend limits like any other transaction
+// - A token is not rebalanced toward a chain again until the fill window of its last order there has
+//   passed, so an order waiting for a filler is not doubled. Unfilled orders keep their funds locked
+//   until they are refunded after the fill deadline
+// - Runs on the leader only, and never in observer mode
+//
+// Settings:
+// - REBALANCER_ENABLED: opens rebalancing orders (default false)
+// - REBALANCE_INTERVAL_SECONDS: time between checks (default 900)
+// - REBALANCE_FEE_BPS: fee left to fillers, in basis points of the amount moved (default 10)
+// - REBALANCE_MAX_FEE_PER_DAY: max fees per 24h, in whole 18-decimal tokens summed across tokens;
+//   unset or 0 disables the limit, an invalid value disables the rebalancer
+// - REBALANCE_FILL_WINDOW_SECONDS: fill deadline of the orders, and the wait before a token is
+//   rebalanced toward the same chain again (default 3600)
+//
+// Metrics:
+// - solver_rebalance_orders_total{origin,destination,token}: rebalancing orders opened
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const (
+	defaultRebalanceIntervalSeconds   = 900
+	defaultRebalanceFeeBps            = 10
+	defaultRebalanceFillWindowSeconds = 3600
+
+	rebalanceOrdersMetric = "solver_rebalance_orders_total"
+)
+
+// orderDataTypeHash is the orderDataType of OrderData orders on both the Solidity and Cairo routers
+var orderDataTypeHash = crypto.Keccak256Hash([]byte("OrderData(bytes32 sender,bytes32 recipient,bytes32 inputToken,bytes32 outputToken," +
+	"uint256 amountIn,uint256 amountOut,uint256 senderNonce,uint32 originDomain,uint32 destinationDomain,bytes32 destinationSettler," +
+	"uint32 fillDeadline,bytes data)"))
+
+// rebalanceMove is one planned transfer of a token from a chain with excess to a chain below its threshold
+type rebalanceMove struct {
+	origin      config.TokenConfig
+	destination config.TokenConfig
+	amountIn    *big.Int // Paid on the origin chain, in origin token units
+	amountOut   *big.Int // Received on the destination chain, in destination token units
+	fee         *big.Int // Left to the filler, at 18 decimals
+}
+
+// rebalanceOrder is an order ready to be opened on its origin chain
+type rebalanceOrder struct {
+	originChainID uint64
+	router        string
+	inputToken    string
+	amountIn      *big.Int
+	fillDeadline  uint32
+	orderData     []byte // abi.encode(OrderData)
+}
+
+// orderOpener is implemented by chain handlers that can open orders on their chain's router
+type orderOpener interface {
+	openOrder(ctx context.Context, order rebalanceOrder) (string, error)
+}
+
+// rebalanceFee is a fee left to fillers, kept for the 24h window
+type rebalanceFee struct {
+	at  time.Time
+	fee *big.Int
+}
+
+// Rebalancer opens orders of the solver's own to move inventory between chains
+type Rebalancer struct {
+	readBalance func(ctx context.Context, network config.NetworkConfig, token string) (*big.Int, error)
+	open        func(ctx context.Context, order rebalanceOrder) (string, error)
+	metrics     *metrics.Registry
+	now         func() time.Time
+
+	enabled    bool
+	interval   time.Duration
+	feeBps     int64
+	maxFee     *big.Int // Per 24h at 18 decimals; nil is unlimited
+	fillWindow time.Duration
+
+	mu      sync.Mutex
+	waiting map[string]time.Time // End of the fill window of the last order, by symbol and destination chain
+	fees    []rebalanceFee
+}
+
+// NewRebalancer creates a rebalancer opening orders through the solver's chain handlers and reading
+// balances through the monitor
+func NewRebalancer(solver *Hyperlane7683Solver, balances *BalanceMonitor) *Rebalancer {
+	r := &Rebalancer{
+		readBalance: balances.readBalance,
+		open:        solver.openOrder,
+		metrics:     metrics.Default(),
+		now:         time.Now,
+		enabled:     envutil.GetEnvBool("REBALANCER_ENABLED", false) && !solver.ObserverMode(),
+		interval:    time.Duration(envutil.GetEnvInt("REBALANCE_INTERVAL_SECONDS", defaultRebalanceIntervalSeconds)) * time.Second,
+		feeBps:      int64(envutil.GetEnvInt("REBALANCE_FEE_BPS", defaultRebalanceFeeBps)),
+		fillWindow:  time.Duration(envutil.GetEnvInt("REBALANCE_FILL_WINDOW_SECONDS", defaultRebalanceFillWindowSeconds)) * time.Second,
+		waiting:     make(map[string]time.Time),
+	}
+
+	maxFee, err := parseFeeCap("REBALANCE_MAX_FEE_PER_DAY")
+	if err == nil && (r.feeBps < 0 || r.feeBps >= bpsDenominator) {
+		err = fmt.Errorf("invalid REBALANCE_FEE_BPS: %d is not between 0 and %d", r.feeBps, bpsDenominator-1)
+	}
+	if err == nil && (r.interval <= 0 || r.fillWindow <= 0) {
+		err = fmt.Errorf("REBALANCE_INTERVAL_SECONDS and REBALANCE_FILL_WINDOW_SECONDS must be positive")
+	}
+	if err != nil && r.enabled {
+		// Fail closed: a mistyped limit must not turn into no limit
+		fmt.Printf("⚠️  %v; the rebalancer is disabled until it is fixed\n", err)
+		r.enabled = false
+	}
+	r.maxFee = maxFee
+	return r
+}
+
+// Start checks the balances right away and then every interval until ctx is done or the returned
+// function is called. It returns a no-op shutdown function when the rebalancer is disabled.
+func (r *Rebalancer) Start(ctx context.Context) func() {
+	if !r.enabled {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			r.RunOnce(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	fmt.Printf("   ⚖️  Inventory rebalancing every %s (fee %d bps)\n", r.interval, r.feeBps)
+	return cancel
+}
+
+// RunOnce reads the balances of tokens with a target and opens the orders that bring low chains back
+// to their target. It returns the moves whose orders were opened.
+func (r *Rebalancer) RunOnce(ctx context.Context) []rebalanceMove {
+	var tokens []config.TokenConfig
+	for _, token := range config.ActiveRuntime().Tokens {
+		if token.TargetBalanceAmount() != nil && !types.IsNativeToken(token.Address) {
+			tokens = append(tokens, token)
+		}
+	}
+	if len(tokens) < 2 {
+		return nil
+	}
+
+	balances := make(map[string]*big.Int, len(tokens))
+	for _, token := range tokens {
+		network, ok := networkForChainID(token.ChainID)
+		if !ok {
+			continue
+		}
+		balance, err := r.readBalance(ctx, network, token.Address)
+		if err != nil {
+			fmt.Printf("⚠️  Rebalancer: failed to read %s balance on %s: %v\n", token.Symbol, network.Name, err)
+			continue
+		}
+		balances[tokenKey(token)] = balance
+	}
+
+	var opened []rebalanceMove
+	for _, move := range planMoves(tokens, balances, r.feeBps, r.isWaiting) {
+		originName, destinationName := chainLabel(new(big.Int).SetUint64(move.origin.ChainID)), chainLabel(new(big.Int).SetUint64(move.destination.ChainID))
+		if err := r.checkFee(move.fee); err != nil {
+			fmt.Printf("⏸️  Rebalancer: not moving %s from %s to %s: %v\n", move.origin.Format(move.amountIn), originName, destinationName, err)
+			continue
+		}
+
+		now := r.now()
+		order, err := buildRebalanceOrder(move, uint32(now.Add(r.fillWindow).Unix()), big.NewInt(now.UnixNano()))
+		var txHash string
+		if err == nil {
+			txHash, err = r.open(ctx, order)
+		}
+		if err != nil {
+			fmt.Printf("❌ Rebalancer: failed to move %s from %s to %s: %v\n", move.origin.Format(move.amountIn), originName, destinationName, err)
+			continue
+		}
+
+		r.recordOpened(move, now)
+		r.metrics.Counter(rebalanceOrdersMetric, "origin", originName, "destination", destinationName, "token", move.origin.Symbol).Inc()
+		fmt.Printf("⚖️  Rebalancer: opened order moving %s from %s to %s (receiving %s): %s\n",
+			move.origin.Format(move.amountIn), originName, destinationName, move.destination.Format(move.amountOut), txHash)
+		opened = append(opened, move)
+	}
+	return opened
+}
+
+// isWaiting reports whether an order toward token's chain is still within its fill window
+func (r *Rebalancer) isWaiting(token config.TokenConfig) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.now().Before(r.waiting[waitingKey(token)])
+}
+
+// checkFee fails when leaving fee to a filler would exceed the 24h limit
+func (r *Rebalancer) checkFee(fee *big.Int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	spent := new(big.Int)
+	kept := r.fees[:0]
+	for _, entry := range r.fees {
+		if now.Sub(entry.at) < spendWindow {
+			kept = append(kept, entry)
+			spent.Add(spent, entry.fee)
+		}
+	}
+	r.fees = kept
+
+	if r.maxFee != nil && new(big.Int).Add(spent, fee).Cmp(r.maxFee) > 0 {
+		format := func(value *big.Int) string { return amount.New(value, config.NotionalDecimals).String() }
+		return fmt.Errorf("fee %s would exceed the daily limit (%s of %s used)", format(fee), format(spent), format(r.maxFee))
+	}
+	return nil
+}
+
+// recordOpened counts the fee of an opened order and starts the fill window of its destination
+func (r *Rebalancer) recordOpened(move rebalanceMove, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fees = append(r.fees, rebalanceFee{at: at, fee: new(big.Int).Set(move.fee)})
+	r.waiting[waitingKey(move.destination)] = at.Add(r.fillWindow)
+}
+
+// planMoves pairs every token below its threshold with the token of the same symbol holding the
+// largest excess over its target on another chain. balances are keyed by tokenKey; tokens without a
+// balance are left out, and skip leaves out destinations (e.g. ones still waiting for a fill).
+func planMoves(tokens []config.TokenConfig, balances map[string]*big.Int, feeBps int64, skip func(config.TokenConfig) bool) []rebalanceMove {
+	notional := func(token config.TokenConfig, units *big.Int) *big.Int {
+		return amount.New(units, token.Decimals).Scale(config.NotionalDecimals).Units
+	}
+
+	// Excess over the target at 18 decimals, used up as moves are planned
+	excess := make(map[string]*big.Int)
+	for _, token := range tokens {
+		if balance, ok := balances[tokenKey(token)]; ok {
+			if over := new(big.Int).Sub(notional(token, balance), notional(token, token.TargetBalanceAmount())); over.Sign() > 0 {
+				excess[tokenKey(token)] = over
+			}
+		}
+	}
+
+	var moves []rebalanceMove
+	for _, destination := range tokens {
+		balance, ok := balances[tokenKey(destination)]
+		if !ok || (skip != nil && skip(destination)) {
+			continue
+		}
+		threshold := destination.LowBalanceThreshold()
+		if threshold == nil {
+			threshold = destination.TargetBalanceAmount()
+		}
+		if balance.Cmp(threshold) >= 0 {
+			continue
+		}
+		deficit := new(big.Int).Sub(notional(destination, destination.TargetBalanceAmount()), notional(destination, balance))
+
+		var origin config.TokenConfig
+		var available *big.Int
+		for _, candidate := range tokens {
+			over := excess[tokenKey(candidate)]
+			if candidate.ChainID == destination.ChainID || !strings.EqualFold(candidate.Symbol, destination.Symbol) || over == nil {
+				continue
+			}
+			if available == nil || over.Cmp(available) > 0 {
+				origin, available = candidate, over
+			}
+		}
+		if available == nil {
+			continue
+		}
+
+		moved := deficit
+		if available.Cmp(moved) < 0 {
+			moved = available
+		}
+		// Round to what both tokens can represent, so the amount in and out describe the same value
+		amountIn := amount.New(moved, config.NotionalDecimals).Scale(origin.Decimals).Units
+		moved = notional(origin, amountIn)
+		received := new(big.Int).Mul(moved, big.NewInt(bpsDenominator-feeBps))
+		received.Quo(received, big.NewInt(bpsDenominator))
+		amountOut := amount.New(received, config.NotionalDecimals).Scale(destination.Decimals).Units
+		if amountIn.Sign() <= 0 || amountOut.Sign() <= 0 {
+			continue
+		}
+
+		available.Sub(available, moved)
+		moves = append(moves, rebalanceMove{
+			origin:      origin,
+			destination: destination,
+			amountIn:    amountIn,
+			amountOut:   amountOut,
+			fee:         new(big.Int).Sub(moved, notional(destination, amountOut)),
+		})
+	}
+	return moves
+}
+
+// buildRebalanceOrder encodes the OrderData of a move, from and to the solver's own addresses
+func buildRebalanceOrder(move rebalanceMove, fillDeadline uint32, nonce *big.Int) (rebalanceOrder, error) {
+	originNetwork, ok := networkForChainID(move.origin.ChainID)
+	if !ok {
+		return rebalanceOrder{}, fmt.Errorf("network config not found for chain ID %d", move.origin.ChainID)
+	}
+	destinationNetwork, ok := networkForChainID(move.destination.ChainID)
+	if !ok {
+		return rebalanceOrder{}, fmt.Errorf("network config not found for chain ID %d", move.destination.ChainID)
+	}
+	sender, err := solverAddress(originNetwork)
+	if err != nil {
+		return rebalanceOrder{}, err
+	}
+	recipient, err := solverAddress(destinationNetwork)
+	if err != nil {
+		return rebalanceOrder{}, err
+	}
+	originRouter, err := hyperlaneRouterAddress(originNetwork.ChainID)
+	if err != nil {
+		return rebalanceOrder{}, err
+	}
+	destinationRouter, err := hyperlaneRouterAddress(destinationNetwork.ChainID)
+	if err != nil {
+		return rebalanceOrder{}, err
+	}
+
+	orderData, err := orderDataArguments.Pack(evmOrderData{
+		Sender:             common.HexToHash(sender),
+		Recipient:          common.HexToHash(recipient),
+		InputToken:         common.HexToHash(move.origin.Address),
+		OutputToken:        common.HexToHash(move.destination.Address),
+		AmountIn:           move.amountIn,
+		AmountOut:          move.amountOut,
+		SenderNonce:        nonce,
+		OriginDomain:       uint32(originNetwork.HyperlaneDomain),
+		DestinationDomain:  uint32(destinationNetwork.HyperlaneDomain),
+		DestinationSettler: common.HexToHash(destinationRouter),
+		FillDeadline:       fillDeadline,
+		Data:               []byte{},
+	})
+	if err != nil {
+		return rebalanceOrder{}, fmt.Errorf("failed to encode order data: %w", err)
+	}
+	return rebalanceOrder{
+		originChainID: originNetwork.ChainID,
+		router:        originRouter,
+		inputToken:    move.origin.Address,
+		amountIn:      move.amountIn,
+		fillDeadline:  fillDeadline,
+		orderData:     orderData,
+	}, nil
+}
+
+// openOrder opens the order through the handler of its origin chain
+func (f *Hyperlane7683Solver) openOrder(ctx context.Context, order rebalanceOrder) (string, error) {
+	handler, _, err := f.handlerForChain(new(big.Int).SetUint64(order.originChainID))
+	if err != nil {
+		return "", err
+	}
+	opener, ok := handler.(orderOpener)
+	if !ok {
+		return "", fmt.Errorf("chain handler does not support opening orders")
+	}
+	return opener.openOrder(ctx, order)
+}
+
+// openOrder approves the router for the input token when needed and opens the order
+func (h *HyperlaneEVM) openOrder(ctx context.Context, order rebalanceOrder) (string, error) {
+	tokenAddr, err := types.ToEVMAddress(order.inputToken)
+	if err != nil {
+		return "", err
+	}
+	routerAddr, err := types.ToEVMAddress(order.router)
+	if err != nil {
+		return "", err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.ensureTokenApproval(ctx, nil, tokenAddr, routerAddr, order.amountIn); err != nil {
+		return "", fmt.Errorf("failed to approve the router: %w", err)
+	}
+	contract, err := contracts.NewHyperlane7683(routerAddr, h.client)
+	if err != nil {
+		return "", fmt.Errorf("failed to bind contract at %s: %w", order.router, err)
+	}
+
+	release, err := h.txLimits.Acquire(ctx, h.chainID)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	tx, err := contract.Open(h.unsentSigner(), contracts.OnchainCrossChainOrder{
+		FillDeadline:  order.fillDeadline,
+		OrderDataType: orderDataTypeHash,
+		OrderData:     order.orderData,
+	})
+	if err != nil {
+		h.auditSend(nil, orders.TxKindOpen, routerAddr, nil, err)
+		return "", fmt.Errorf("open transaction failed: %w", err)
+	}
+	if err := h.spendLimits.CheckGas(h.chainID, maxTxFee(tx)); err != nil {
+		return "", err
+	}
+	err = h.client.SendTransaction(ctx, tx)
+	sent := h.auditSend(nil, orders.TxKindOpen, routerAddr, tx, err)
+	if err != nil {
+		return "", fmt.Errorf("open transaction failed: %w", err)
+	}
+
+	receipt, err := bind.WaitMined(ctx, h.client, tx)
+	h.txAudit.record(evmReceiptAuditEntry(sent, receipt, err))
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for open confirmation: %w", err)
+	}
+	h.spendLimits.RecordTx(evmTxCost(h.chainID, orders.TxKindOpen, tx, receipt, nil))
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		return "", fmt.Errorf("open transaction %s reverted", tx.Hash().Hex())
+	}
+	return tx.Hash().Hex(), nil
+}
+
+// openOrder opens the order in one invoke, with the router approval when the allowance is too low
+func (h *HyperlaneStarknet) openOrder(ctx context.Context, order rebalanceOrder) (string, error) {
+	tokenFelt, err := utils.HexToFelt(order.inputToken)
+	if err != nil {
+		return "", fmt.Errorf("invalid Starknet token address %s: %w", order.inputToken, err)
+	}
+	routerFelt, err := utils.HexToFelt(order.router)
+	if err != nil {
+		return "", fmt.Errorf("invalid Starknet router address %s: %w", order.router, err)
+	}
+	openCall, err := hyperlane7683StarknetABI.InvokeCall(routerFelt, "open", map[string]interface{}{
+		"fill_deadline":   uint64(order.fillDeadline),
+		"order_data_type": orderDataTypeHash.Big(),
+		"order_data":      order.orderData,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	calls, err := h.buildApprovalCalls(ctx, []tokenAmount{{token: tokenFelt, amount: order.amountIn}}, routerFelt)
+	if err != nil {
+		return "", fmt.Errorf("failed to setup approvals: %w", err)
+	}
+	txHash, _, err := h.executeCalls(ctx, nil, orders.TxKindOpen, append(calls, openCall), new(big.Int), nil)
+	if err != nil {
+		return "", fmt.Errorf("starknet open failed: %w", err)
+	}
+	return txHash, nil
+}
+
+// isOwnOrder reports whether the order was opened by the solver itself, e.g. by the rebalancer
+func isOwnOrder(args *types.ParsedArgs) bool {
+	if args.SenderAddress == "" {
+		return false
+	}
+	sender := common.HexToHash(args.SenderAddress)
+	for _, own := range []string{envutil.GetSolverPublicKey(), envutil.GetStarknetSolverAddress()} {
+		if own != "" && common.HexToHash(own) == sender {
+			return true
+		}
+	}
+	return false
+}
+
+// solverAddress returns the solver's address on network
+func solverAddress(network config.NetworkConfig) (string, error) {
+	if config.IsStarknetNetwork(network.Name) {
+		if address := envutil.GetStarknetSolverAddress(); address != "" {
+			return address, nil
+		}
+		return "", fmt.Errorf("Starknet solver address not set")
+	}
+	if address := envutil.GetSolverPublicKey(); address != "" {
+		return address, nil
+	}
+	return "", fmt.Errorf("solver public key not set")
+}
+
+// tokenKey identifies a registry token by chain and address
+func tokenKey(token config.TokenConfig) string {
+	return fmt.Sprintf("%d/%s", token.ChainID, strings.ToLower(token.Address))
+}
+
+// waitingKey identifies the rebalancing of a symbol toward a chain
+func waitingKey(token config.TokenConfig) string {
+	return fmt.Sprintf("%d/%s", token.ChainID, strings.ToUpper(token.Symbol))
+}
@@ -0,0 +1,245 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+func TestPlanMoves(t *testing.T) {
+	baseDog := config.TokenConfig{ChainID: config.BaseSepoliaChainID, Address: "0xd0", Symbol: "DOG", Decimals: 18, LowBalance: "100", TargetBalance: "500"}
+	ethDog := config.TokenConfig{ChainID: config.EthereumSepoliaChainID, Address: "0xd1", Symbol: "DOG", Decimals: 6, LowBalance: "100", TargetBalance: "500"}
+	opDog := config.TokenConfig{ChainID: config.OptimismSepoliaChainID, Address: "0xd2", Symbol: "dog", Decimals: 18, TargetBalance: "500"}
+	tokens := []config.TokenConfig{baseDog, ethDog, opDog}
+	whole := func(n int64, decimals int) *big.Int {
+		return new(big.Int).Mul(big.NewInt(n), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	}
+
+	t.Run("low chains are refilled from the largest excess", func(t *testing.T) {
+		balances := map[string]*big.Int{
+			tokenKey(baseDog): whole(50, 18),
+			tokenKey(ethDog):  whole(800, 6),
+			tokenKey(opDog):   whole(2000, 18),
+		}
+		moves := planMoves(tokens, balances, 10, nil)
+		require.Len(t, moves, 1)
+
+		move := moves[0]
+		assert.Equal(t, opDog, move.origin, "Optimism holds the largest excess")
+		assert.Equal(t, baseDog, move.destination)
+		assert.Equal(t, whole(450, 18), move.amountIn, "the deficit up to the target")
+		assert.Equal(t, "449550000000000000000", move.amountOut.String(), "10 bps are left to the filler")
+		assert.Equal(t, "450000000000000000", move.fee.String())
+	})
+
+	t.Run("moves are capped by the excess and scaled per token", func(t *testing.T) {
+		balances := map[string]*big.Int{
+			tokenKey(baseDog): whole(600, 18),
+			tokenKey(ethDog):  whole(50, 6),
+		}
+		moves := planMoves(tokens, balances, 0, nil)
+		require.Len(t, moves, 1)
+		assert.Equal(t, baseDog, moves[0].origin)
+		assert.Equal(t, whole(100, 18), moves[0].amountIn, "only the excess over the origin's target moves")
+		assert.Equal(t, whole(100, 6), moves[0].amountOut)
+		assert.Zero(t, moves[0].fee.Sign())
+	})
+
+	t.Run("excess is shared between low chains", func(t *testing.T) {
+		balances := map[string]*big.Int{
+			tokenKey(baseDog): whole(0, 18),
+			tokenKey(ethDog):  whole(0, 6),
+			tokenKey(opDog):   whole(700, 18),
+		}
+		moves := planMoves(tokens, balances, 0, nil)
+		require.Len(t, moves, 1, "the first move uses up the excess")
+		assert.Equal(t, whole(200, 18), moves[0].amountIn)
+	})
+
+	t.Run("without a lowBalance the target is the threshold", func(t *testing.T) {
+		balances := map[string]*big.Int{
+			tokenKey(baseDog): whole(1000, 18),
+			tokenKey(opDog):   whole(450, 18),
+		}
+		moves := planMoves(tokens, balances, 0, nil)
+		require.Len(t, moves, 1)
+		assert.Equal(t, opDog, moves[0].destination)
+		assert.Equal(t, whole(50, 18), moves[0].amountIn)
+	})
+
+	t.Run("nothing moves", func(t *testing.T) {
+		balanced := map[string]*big.Int{tokenKey(baseDog): whole(150, 18), tokenKey(ethDog): whole(900, 6)}
+		assert.Empty(t, planMoves(tokens, balanced, 10, nil), "balances above the low threshold")
+
+		noExcess := map[string]*big.Int{tokenKey(baseDog): whole(50, 18), tokenKey(ethDog): whole(500, 6)}
+		assert.Empty(t, planMoves(tokens, noExcess, 10, nil), "no chain above its target")
+
+		unread := map[string]*big.Int{tokenKey(baseDog): whole(50, 18)}
+		assert.Empty(t, planMoves(tokens, unread, 10, nil), "unread balances are left out")
+
+		low := map[string]*big.Int{tokenKey(baseDog): whole(50, 18), tokenKey(opDog): whole(2000, 18)}
+		skip := func(token config.TokenConfig) bool { return token.ChainID == config.BaseSepoliaChainID }
+		assert.Empty(t, planMoves(tokens, low, 10, skip), "skipped destinations")
+	})
+}
+
+func TestRebalancer(t *testing.T) {
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+	require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+		{ChainID: config.BaseSepoliaChainID, Address: "0x00000000000000000000000000000000000000d0", Symbol: "DOG", Decimals: 18, LowBalance: "100", TargetBalance: "500"},
+		{ChainID: config.EthereumSepoliaChainID, Address: "0x00000000000000000000000000000000000000d1", Symbol: "DOG", Decimals: 18, TargetBalance: "500"},
+		{ChainID: config.BaseSepoliaChainID, Address: "0x00000000000000000000000000000000000000c0", Symbol: "CAT", Decimals: 18, LowBalance: "1"},
+	}}))
+	t.Setenv("IS_DEVNET", "")
+	t.Setenv("SOLVER_PUB_KEY", "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+
+	dogs := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e18)) }
+	newRebalancer := func(balances map[uint64]*big.Int, open func(ctx context.Context, order rebalanceOrder) (string, error)) (*Rebalancer, *time.Time) {
+		now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+		return &Rebalancer{
+			readBalance: func(_ context.Context, network config.NetworkConfig, _ string) (*big.Int, error) {
+				if balance, ok := balances[network.ChainID]; ok {
+					return balance, nil
+				}
+				return nil, errors.New("unreachable")
+			},
+			open:       open,
+			metrics:    metrics.NewRegistry(),
+			now:        func() time.Time { return now },
+			enabled:    true,
+			feeBps:     10,
+			fillWindow: time.Hour,
+			waiting:    make(map[string]time.Time),
+		}, &now
+	}
+
+	t.Run("opens an order from the solver to itself", func(t *testing.T) {
+		var opened []rebalanceOrder
+		r, _ := newRebalancer(map[uint64]*big.Int{config.BaseSepoliaChainID: dogs(50), config.EthereumSepoliaChainID: dogs(1000)},
+			func(_ context.Context, order rebalanceOrder) (string, error) {
+				opened = append(opened, order)
+				return "0x01", nil
+			})
+
+		moves := r.RunOnce(context.Background())
+		require.Len(t, moves, 1)
+		require.Len(t, opened, 1)
+		order := opened[0]
+		assert.Equal(t, uint64(config.EthereumSepoliaChainID), order.originChainID)
+		assert.Equal(t, "0x00000000000000000000000000000000000000d1", order.inputToken)
+		assert.Equal(t, dogs(450), order.amountIn)
+
+		data, err := orderDataArguments.Unpack(order.orderData)
+		require.NoError(t, err)
+		decoded := *abi.ConvertType(data[0], new(evmOrderData)).(*evmOrderData)
+		solver := common.HexToHash("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+		assert.Equal(t, solver, common.Hash(decoded.Sender))
+		assert.Equal(t, solver, common.Hash(decoded.Recipient))
+		assert.Equal(t, common.HexToHash("0xd1"), common.Hash(decoded.InputToken))
+		assert.Equal(t, common.HexToHash("0xd0"), common.Hash(decoded.OutputToken))
+		assert.Equal(t, "449550000000000000000", decoded.AmountOut.String())
+		assert.Equal(t, uint32(config.EthereumSepoliaChainID), decoded.OriginDomain)
+		assert.Equal(t, uint32(config.BaseSepoliaChainID), decoded.DestinationDomain)
+		assert.Equal(t, common.HexToHash(config.Networks["Base"].HyperlaneAddress.Hex()), common.Hash(decoded.DestinationSettler))
+		assert.Equal(t, order.fillDeadline, decoded.FillDeadline)
+
+		assert.Equal(t, 1.0, r.metrics.Counter(rebalanceOrdersMetric, "origin", "Ethereum", "destination", "Base", "token", "DOG").Value())
+	})
+
+	t.Run("waits for the fill window before refilling a chain again", func(t *testing.T) {
+		calls := 0
+		r, now := newRebalancer(map[uint64]*big.Int{config.BaseSepoliaChainID: dogs(50), config.EthereumSepoliaChainID: dogs(1000)},
+			func(context.Context, rebalanceOrder) (string, error) {
+				calls++
+				return "0x01", nil
+			})
+
+		r.RunOnce(context.Background())
+		assert.Empty(t, r.RunOnce(context.Background()))
+		*now = now.Add(time.Hour)
+		assert.Len(t, r.RunOnce(context.Background()), 1)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("fees are capped per day", func(t *testing.T) {
+		r, now := newRebalancer(map[uint64]*big.Int{config.BaseSepoliaChainID: dogs(50), config.EthereumSepoliaChainID: dogs(10_000)},
+			func(context.Context, rebalanceOrder) (string, error) { return "0x01", nil })
+		r.maxFee = new(big.Int).Div(dogs(1), big.NewInt(2)) // One 0.45 DOG fee fits, two don't
+
+		assert.Len(t, r.RunOnce(context.Background()), 1)
+		*now = now.Add(time.Hour)
+		assert.Empty(t, r.RunOnce(context.Background()))
+		*now = now.Add(spendWindow)
+		assert.Len(t, r.RunOnce(context.Background()), 1, "fees older than 24h no longer count")
+	})
+
+	t.Run("failed opens are retried on the next run", func(t *testing.T) {
+		fail := true
+		r, _ := newRebalancer(map[uint64]*big.Int{config.BaseSepoliaChainID: dogs(50), config.EthereumSepoliaChainID: dogs(1000)},
+			func(context.Context, rebalanceOrder) (string, error) {
+				if fail {
+					return "", errors.New("reverted")
+				}
+				return "0x01", nil
+			})
+		r.maxFee = dogs(1)
+
+		assert.Empty(t, r.RunOnce(context.Background()))
+		assert.Empty(t, r.fees, "the fee of a failed open is not counted")
+		fail = false
+		assert.Len(t, r.RunOnce(context.Background()), 1)
+	})
+
+	t.Run("unreadable balances move nothing", func(t *testing.T) {
+		r, _ := newRebalancer(map[uint64]*big.Int{config.BaseSepoliaChainID: dogs(50)},
+			func(context.Context, rebalanceOrder) (string, error) { return "0x01", nil })
+		assert.Empty(t, r.RunOnce(context.Background()))
+	})
+
+	t.Run("disabled by default and in observer mode", func(t *testing.T) {
+		t.Setenv("REBALANCER_ENABLED", "")
+		assert.False(t, NewRebalancer(NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{}), NewBalanceMonitor(nil, nil)).enabled)
+
+		t.Setenv("REBALANCER_ENABLED", "true")
+		assert.True(t, NewRebalancer(NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{}), NewBalanceMonitor(nil, nil)).enabled)
+
+		t.Setenv("REBALANCE_MAX_FEE_PER_DAY", "lots")
+		assert.False(t, NewRebalancer(NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{}), NewBalanceMonitor(nil, nil)).enabled,
+			"an invalid limit disables the rebalancer")
+	})
+}
+
+func TestStarknetOpenCall(t *testing.T) {
+	orderData, err := orderDataArguments.Pack(evmOrderData{AmountIn: big.NewInt(1), AmountOut: big.NewInt(1), SenderNonce: big.NewInt(1), Data: []byte{}})
+	require.NoError(t, err)
+	router := utils.Uint64ToFelt(0x7683)
+
+	call, err := hyperlane7683StarknetABI.InvokeCall(router, "open", map[string]interface{}{
+		"fill_deadline":   uint64(1_700_000_000),
+		"order_data_type": orderDataTypeHash.Big(),
+		"order_data":      orderData,
+	})
+	require.NoError(t, err)
+
+	// The Starknet open-order tool's default ORDER_DATA_TYPE_HASH
+	assert.Equal(t, "0x08d75650babf4de09c9273d48ef647876057ed91d4323f8a2e3ebc2cd8a63b5e", orderDataTypeHash.Hex())
+	assert.Equal(t, "open", call.FunctionName)
+	assert.Equal(t, uint64(1_700_000_000), call.CallData[0].Uint64())
+	assert.Equal(t, uint64(len(orderData)), call.CallData[3].Uint64(), "order_data follows the u256 type hash as Bytes")
+	assert.Len(t, call.CallData, 3+2+(len(orderData)+15)/16)
+}
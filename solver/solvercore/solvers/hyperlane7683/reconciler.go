@@ -0,0 +1,315 @@
+package hyperlane7683
+
+// Module: Order state reconciliation
+// - Periodically samples orders from the local order store (least recently checked first)
+// - Re-queries the order status on the destination router and the origin router
+// - Repairs local records that diverge from the destination chain (e.g. marked filled locally
+//   but UNKNOWN on-chain) and reports every discrepancy as a metric
+// - Records the order input as received once the origin router reports the order SETTLED
+// - Flags orders we settled whose proceeds did not arrive on the origin within the timeout
+//   (no Settled event seen, router not SETTLED) for manual investigation
+//
+// Settings:
+// - ORDER_RECONCILE_INTERVAL_SECONDS: time between runs, 0 disables the job (default 300)
+// - ORDER_RECONCILE_SAMPLE_SIZE: orders checked per run (default 25)
+// - SETTLEMENT_PROCEEDS_TIMEOUT_SECONDS: time after settling before missing proceeds are flagged (default 3600)
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const (
+	defaultReconcileIntervalSeconds = 300
+	defaultReconcileSampleSize      = 25
+	defaultProceedsTimeoutSeconds   = 3600
+)
+
+// Discrepancy kinds reported by the reconciler
+const (
+	DiscrepancyMissingFill      = "missing_fill"      // Filled/settled locally, UNKNOWN on-chain
+	DiscrepancyMissingSettle    = "missing_settle"    // Settled locally, only FILLED on-chain
+	DiscrepancyUnrecordedFill   = "unrecorded_fill"   // FILLED on-chain, not recorded locally
+	DiscrepancyUnrecordedSettle = "unrecorded_settle" // SETTLED on-chain, not recorded locally
+)
+
+// Discrepancy is a single divergence found (and repaired) by a reconciliation run
+type Discrepancy struct {
+	OrderID     string
+	Kind        string
+	LocalStatus orders.Status
+	ChainStatus string
+	Repaired    orders.Status
+}
+
+// ReconcileReport summarizes one reconciliation run
+type ReconcileReport struct {
+	Checked         int
+	Errors          int
+	Discrepancies   []Discrepancy
+	ProceedsOverdue []string // Orders flagged in this run because their proceeds did not arrive
+}
+
+// Reconciler compares the order store with on-chain router state
+type Reconciler struct {
+	store           *orders.Store
+	handlerFor      func(chainID *big.Int) (ChainHandler, error)
+	routerFor       func(chainID uint64) (string, error)
+	metrics         *metrics.Registry
+	interval        time.Duration
+	sampleSize      int
+	proceedsTimeout time.Duration // Time after settling before proceeds that have not arrived are flagged
+	now             func() time.Time
+}
+
+// NewReconciler creates a reconciler using the solver's chain handlers
+func NewReconciler(solver *Hyperlane7683Solver, store *orders.Store) *Reconciler {
+	return &Reconciler{
+		store: store,
+		handlerFor: func(chainID *big.Int) (ChainHandler, error) {
+			handler, _, err := solver.handlerForChain(chainID)
+			return handler, err
+		},
+		routerFor:       hyperlaneRouterAddress,
+		metrics:         metrics.Default(),
+		interval:        time.Duration(envutil.GetEnvInt("ORDER_RECONCILE_INTERVAL_SECONDS", defaultReconcileIntervalSeconds)) * time.Second,
+		sampleSize:      envutil.GetEnvInt("ORDER_RECONCILE_SAMPLE_SIZE", defaultReconcileSampleSize),
+		proceedsTimeout: time.Duration(envutil.GetEnvInt("SETTLEMENT_PROCEEDS_TIMEOUT_SECONDS", defaultProceedsTimeoutSeconds)) * time.Second,
+		now:             time.Now,
+	}
+}
+
+// Start runs the reconciler every interval until ctx is done or the returned function is called.
+// It returns a no-op shutdown function when the job is disabled.
+func (r *Reconciler) Start(ctx context.Context) func() {
+	if r.interval <= 0 || r.sampleSize <= 0 {
+		fmt.Printf("   ⏭️  Order reconciliation disabled\n")
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				report := r.RunOnce(ctx)
+				if len(report.Discrepancies) > 0 || len(report.ProceedsOverdue) > 0 || report.Errors > 0 {
+					fmt.Printf("🔎 Reconciliation checked %d orders: %d discrepancies repaired, %d with overdue proceeds, %d errors\n",
+						report.Checked, len(report.Discrepancies), len(report.ProceedsOverdue), report.Errors)
+				}
+			}
+		}
+	}()
+
+	fmt.Printf("   🔎 Order reconciliation every %s (%d orders per run)\n", r.interval, r.sampleSize)
+	return cancel
+}
+
+// RunOnce checks one sample of orders against the chains
+func (r *Reconciler) RunOnce(ctx context.Context) ReconcileReport {
+	var report ReconcileReport
+	r.metrics.Counter("solver_reconcile_runs_total").Inc()
+
+	for _, order := range r.store.Sample(r.sampleSize, needsReconciliation) {
+		if ctx.Err() != nil {
+			break
+		}
+
+		discrepancy, err := r.reconcileOrder(ctx, order)
+		report.Checked++
+		r.metrics.Counter("solver_reconcile_orders_checked_total").Inc()
+		if err != nil {
+			report.Errors++
+			r.metrics.Counter("solver_reconcile_errors_total", "chain", logutil.NetworkNameByChainID(order.DestinationChainID)).Inc()
+			fmt.Printf("   ⚠️  Reconciliation of order %s failed: %v\n", order.OrderID, err)
+			continue
+		}
+		if discrepancy != nil {
+			report.Discrepancies = append(report.Discrepancies, *discrepancy)
+			r.metrics.Counter("solver_reconcile_discrepancies_total",
+				"kind", discrepancy.Kind,
+				"chain", logutil.NetworkNameByChainID(order.DestinationChainID),
+			).Inc()
+		}
+		if r.flagOverdueProceeds(order.OrderID) {
+			report.ProceedsOverdue = append(report.ProceedsOverdue, order.OrderID)
+		}
+	}
+
+	return report
+}
+
+// flagOverdueProceeds flags an order settled by us whose proceeds have not reached the solver
+// proceedsTimeout after settling, once per order. It reads the record updated by reconcileOrder.
+func (r *Reconciler) flagOverdueProceeds(orderID string) bool {
+	order, ok := r.store.Get(orderID)
+	if !ok || r.proceedsTimeout <= 0 || !order.ProceedsPending() || !order.ProceedsOverdueAt.IsZero() {
+		return false
+	}
+	if order.SettledAt.IsZero() || r.now().Sub(order.SettledAt) < r.proceedsTimeout {
+		return false
+	}
+
+	if err := r.store.FlagProceedsOverdue(orderID); err != nil {
+		fmt.Printf("   ⚠️  Failed to flag overdue proceeds of order %s: %v\n", orderID, err)
+		return false
+	}
+	originName := logutil.NetworkNameByChainID(order.OriginChainID)
+	r.metrics.Counter("solver_settlement_proceeds_overdue_total", "chain", originName).Inc()
+	logutil.LogWithNetworkTagf(originName, "🚨 Order %s settled %s ago but its proceeds have not arrived (origin status %q), needs investigation\n",
+		orderID, r.now().Sub(order.SettledAt).Round(time.Second), order.OriginStatus)
+	return true
+}
+
+// needsReconciliation skips orders without data to query and orders that are final on both
+// chains: settled on the destination and settled (or refunded) on the origin
+func needsReconciliation(o orders.Order) bool {
+	if o.Args == nil || len(o.Args.ResolvedOrder.FillInstructions) == 0 {
+		return false
+	}
+	settled := o.Status == orders.StatusSettled && o.DestinationStatus == orderStatusSettled
+	originFinal := o.OriginStatus == orderStatusSettled || o.OriginStatus == orderStatusRefunded
+	return !(settled && originFinal)
+}
+
+// reconcileOrder queries both routers, stores what it saw and repairs the local status if needed
+func (r *Reconciler) reconcileOrder(ctx context.Context, order orders.Order) (*Discrepancy, error) {
+	args := order.Args
+	destHandler, err := r.handlerFor(args.ResolvedOrder.FillInstructions[0].DestinationChainID)
+	if err != nil {
+		return nil, err
+	}
+	destStatus, err := destHandler.GetOrderStatus(ctx, args)
+	if err != nil {
+		return nil, fmt.Errorf("destination status: %w", err)
+	}
+
+	// The origin status is informational (OPENED/SETTLED/REFUNDED); failing to read it is not fatal
+	originStatus, err := r.originStatus(ctx, args)
+	if err != nil {
+		fmt.Printf("   ⚠️  Origin status of order %s unavailable: %v\n", order.OrderID, err)
+	}
+
+	discrepancy := compareOrderStatus(order, destStatus)
+
+	err = r.store.Update(order.OrderID, func(o *orders.Order) {
+		o.DestinationStatus = destStatus
+		if originStatus != "" {
+			o.OriginStatus = originStatus
+		}
+		o.LastReconciledAt = r.now()
+		if discrepancy != nil {
+			o.Transition(discrepancy.Repaired, fmt.Sprintf("reconciled: %s locally, %s on-chain", discrepancy.LocalStatus, discrepancy.ChainStatus), r.now())
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Settlement proceeds reach the solver on the origin once the router marks the order SETTLED
+	if originStatus == orderStatusSettled && order.FilledByUs() && order.ProceedsReceivedAt.IsZero() {
+		if err := r.store.RecordOriginSettled(order.OrderID, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	if discrepancy != nil {
+		logutil.LogWithNetworkTagf(logutil.NetworkNameByChainID(order.DestinationChainID),
+			"🔧 Order %s: %s (local %s, on-chain %s) -> %s\n",
+			order.OrderID, discrepancy.Kind, discrepancy.LocalStatus, discrepancy.ChainStatus, discrepancy.Repaired)
+	}
+	return discrepancy, nil
+}
+
+// originStatus reads the order status from the origin chain's router
+func (r *Reconciler) originStatus(ctx context.Context, args *types.ParsedArgs) (string, error) {
+	originChainID := args.ResolvedOrder.OriginChainID
+	if originChainID == nil {
+		return "", fmt.Errorf("no origin chain ID in resolved order")
+	}
+
+	router, err := r.routerFor(originChainID.Uint64())
+	if err != nil {
+		return "", err
+	}
+	handler, err := r.handlerFor(originChainID)
+	if err != nil {
+		return "", err
+	}
+
+	// Handlers query the router named by the first fill instruction, so point it at the origin router
+	originArgs := *args
+	originArgs.ResolvedOrder.FillInstructions = []types.FillInstruction{{
+		DestinationChainID: originChainID,
+		DestinationSettler: router,
+	}}
+	return handler.GetOrderStatus(ctx, &originArgs)
+}
+
+// compareOrderStatus returns the discrepancy between the local status and the destination
+// router status, with the local status it should be repaired to, or nil if they agree
+func compareOrderStatus(order orders.Order, chainStatus string) *Discrepancy {
+	d := &Discrepancy{OrderID: order.OrderID, LocalStatus: order.Status, ChainStatus: chainStatus}
+
+	switch chainStatus {
+	case orderStatusUnknown:
+		if order.Status != orders.StatusFilled && order.Status != orders.StatusSettled {
+			return nil
+		}
+		d.Kind = DiscrepancyMissingFill
+		d.Repaired = orders.StatusFailed
+	case orderStatusFilled:
+		switch order.Status {
+		case orders.StatusFilled, orders.StatusLostRace:
+			return nil
+		case orders.StatusSettled:
+			d.Kind = DiscrepancyMissingSettle
+		default:
+			d.Kind = DiscrepancyUnrecordedFill
+		}
+		d.Repaired = orders.StatusFilled
+	case orderStatusSettled:
+		if order.Status == orders.StatusSettled || order.Status == orders.StatusLostRace {
+			return nil
+		}
+		d.Kind = DiscrepancyUnrecordedSettle
+		d.Repaired = orders.StatusSettled
+	default:
+		// Unrecognized status value; nothing safe to repair
+		return nil
+	}
+	return d
+}
+
+// hyperlaneRouterAddress returns the configured Hyperlane7683 router for a chain
+func hyperlaneRouterAddress(chainID uint64) (string, error) {
+	config.InitializeNetworks()
+	for name, network := range config.Networks {
+		if network.ChainID != chainID {
+			continue
+		}
+		// Starknet addresses do not fit the EVM address type in NetworkConfig
+		if config.IsStarknetNetwork(name) {
+			addr := config.StarknetHyperlaneAddress(name)
+			if addr == "" {
+				return "", fmt.Errorf("%s not set", config.StarknetHyperlaneAddressEnv(name))
+			}
+			return addr, nil
+		}
+		return network.HyperlaneAddress.Hex(), nil
+	}
+	return "", fmt.Errorf("network config not found for chain ID %d", chainID)
+}
@@ -0,0 +1,243 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testOriginChainID = 84532
+	testDestChainID   = 23448591
+	testOriginRouter  = "0x00000000000000000000000000000000000000aa"
+	testDestRouter    = "0x00000000000000000000000000000000000000bb"
+)
+
+// statusChainHandler answers GetOrderStatus from a map keyed by router and order ID
+type statusChainHandler struct {
+	mockChainHandler
+	statuses map[string]string
+	err      error
+}
+
+func (h *statusChainHandler) GetOrderStatus(_ context.Context, args *types.ParsedArgs) (string, error) {
+	if h.err != nil {
+		return "", h.err
+	}
+	status, ok := h.statuses[args.ResolvedOrder.FillInstructions[0].DestinationSettler+"/"+args.OrderID]
+	if !ok {
+		return orderStatusUnknown, nil
+	}
+	return status, nil
+}
+
+func reconcileArgs(orderID string) *types.ParsedArgs {
+	return &types.ParsedArgs{
+		OrderID: orderID,
+		ResolvedOrder: types.ResolvedCrossChainOrder{
+			OriginChainID: big.NewInt(testOriginChainID),
+			FillInstructions: []types.FillInstruction{
+				{DestinationChainID: big.NewInt(testDestChainID), DestinationSettler: testDestRouter},
+			},
+		},
+	}
+}
+
+func newTestReconciler(t *testing.T, handler ChainHandler) (*Reconciler, *orders.Store, *metrics.Registry) {
+	store, err := orders.NewStore(filepath.Join(t.TempDir(), "orders.json"))
+	require.NoError(t, err)
+
+	registry := metrics.NewRegistry()
+	r := &Reconciler{
+		store:      store,
+		handlerFor: func(*big.Int) (ChainHandler, error) { return handler, nil },
+		routerFor:  func(uint64) (string, error) { return testOriginRouter, nil },
+		metrics:    registry,
+		sampleSize: 10,
+		now:        func() time.Time { return time.Unix(1_700_000_000, 0) },
+	}
+	return r, store, registry
+}
+
+func seedOrder(t *testing.T, store *orders.Store, orderID string, status orders.Status) {
+	require.NoError(t, store.Observe(reconcileArgs(orderID)))
+	require.NoError(t, store.SetStatus(orderID, status, ""))
+}
+
+// TestReconciler tests repairing local order state from on-chain status
+func TestReconciler(t *testing.T) {
+	t.Run("repairs_divergences", func(t *testing.T) {
+		handler := &statusChainHandler{statuses: map[string]string{
+			testDestRouter + "/0x02":   orderStatusFilled,
+			testDestRouter + "/0x03":   orderStatusSettled,
+			testDestRouter + "/0x04":   orderStatusFilled,
+			testOriginRouter + "/0x03": orderStatusSettled,
+		}}
+		r, store, registry := newTestReconciler(t, handler)
+
+		seedOrder(t, store, "0x01", orders.StatusFilled)   // UNKNOWN on-chain
+		seedOrder(t, store, "0x02", orders.StatusObserved) // filled by us, not recorded
+		seedOrder(t, store, "0x03", orders.StatusFilled)   // settled on-chain
+		seedOrder(t, store, "0x04", orders.StatusFilled)   // consistent
+
+		report := r.RunOnce(context.Background())
+		assert.Equal(t, 4, report.Checked)
+		assert.Zero(t, report.Errors)
+		require.Len(t, report.Discrepancies, 3)
+
+		order, _ := store.Get("0x01")
+		assert.Equal(t, orders.StatusFailed, order.Status)
+		assert.Equal(t, orderStatusUnknown, order.DestinationStatus)
+		assert.Contains(t, order.Reason, "reconciled")
+
+		order, _ = store.Get("0x02")
+		assert.Equal(t, orders.StatusFilled, order.Status)
+
+		order, _ = store.Get("0x03")
+		assert.Equal(t, orders.StatusSettled, order.Status)
+		assert.Equal(t, orderStatusSettled, order.OriginStatus)
+
+		order, _ = store.Get("0x04")
+		assert.Equal(t, orders.StatusFilled, order.Status)
+		assert.False(t, order.LastReconciledAt.IsZero())
+
+		chain := logutil.NetworkNameByChainID(testDestChainID)
+		assert.Equal(t, 1.0, registry.Counter("solver_reconcile_discrepancies_total", "kind", DiscrepancyMissingFill, "chain", chain).Value())
+		assert.Equal(t, 1.0, registry.Counter("solver_reconcile_discrepancies_total", "kind", DiscrepancyUnrecordedFill, "chain", chain).Value())
+		assert.Equal(t, 4.0, registry.Counter("solver_reconcile_orders_checked_total").Value())
+	})
+
+	t.Run("skips_orders_final_on_both_chains", func(t *testing.T) {
+		handler := &statusChainHandler{statuses: map[string]string{
+			testDestRouter + "/0x01": orderStatusSettled,
+		}}
+		r, store, _ := newTestReconciler(t, handler)
+		seedOrder(t, store, "0x01", orders.StatusSettled)
+
+		// Still OPENED on the origin: keep checking until settlement arrives
+		assert.Equal(t, 1, r.RunOnce(context.Background()).Checked)
+		assert.Equal(t, 1, r.RunOnce(context.Background()).Checked)
+
+		handler.statuses[testOriginRouter+"/0x01"] = orderStatusSettled
+		assert.Equal(t, 1, r.RunOnce(context.Background()).Checked)
+		assert.Equal(t, 0, r.RunOnce(context.Background()).Checked)
+	})
+
+	t.Run("records_input_received_for_our_fills", func(t *testing.T) {
+		handler := &statusChainHandler{statuses: map[string]string{
+			testDestRouter + "/0x01":   orderStatusSettled,
+			testOriginRouter + "/0x01": orderStatusSettled,
+			testDestRouter + "/0x02":   orderStatusSettled,
+			testOriginRouter + "/0x02": orderStatusSettled,
+		}}
+		r, store, _ := newTestReconciler(t, handler)
+
+		for _, id := range []string{"0x01", "0x02"} {
+			args := reconcileArgs(id)
+			args.ResolvedOrder.MinReceived = []types.Output{{Token: "0xin", Amount: big.NewInt(100), ChainID: big.NewInt(testOriginChainID)}}
+			require.NoError(t, store.Observe(args))
+			require.NoError(t, store.SetStatus(id, orders.StatusSettled, ""))
+		}
+		require.NoError(t, store.AddCost("0x01", orders.TxCost{Kind: orders.TxKindFillSettle, Fee: big.NewInt(1), FeeUnit: "FRI"}))
+
+		r.RunOnce(context.Background())
+
+		ours, _ := store.Get("0x01")
+		require.Len(t, ours.InputReceived, 1)
+		assert.Equal(t, big.NewInt(100), ours.InputReceived[0].Amount)
+
+		theirs, _ := store.Get("0x02")
+		assert.Empty(t, theirs.InputReceived)
+	})
+
+	t.Run("flags_overdue_proceeds_once", func(t *testing.T) {
+		handler := &statusChainHandler{statuses: map[string]string{
+			testDestRouter + "/0x01":   orderStatusSettled, // origin still OPENED
+			testDestRouter + "/0x02":   orderStatusSettled,
+			testOriginRouter + "/0x02": orderStatusSettled,
+			testDestRouter + "/0x03":   orderStatusSettled, // not filled by us
+		}}
+		r, store, registry := newTestReconciler(t, handler)
+		r.proceedsTimeout = time.Hour
+		now := time.Now().Add(30 * time.Minute)
+		r.now = func() time.Time { return now }
+
+		for _, id := range []string{"0x01", "0x02", "0x03"} {
+			seedOrder(t, store, id, orders.StatusSettled)
+		}
+		require.NoError(t, store.AddCost("0x01", orders.TxCost{Kind: orders.TxKindFill}))
+		require.NoError(t, store.AddCost("0x02", orders.TxCost{Kind: orders.TxKindFill}))
+
+		assert.Empty(t, r.RunOnce(context.Background()).ProceedsOverdue, "within the timeout")
+
+		now = now.Add(time.Hour)
+		assert.Equal(t, []string{"0x01"}, r.RunOnce(context.Background()).ProceedsOverdue)
+		assert.Empty(t, r.RunOnce(context.Background()).ProceedsOverdue, "flagged once")
+
+		order, _ := store.Get("0x01")
+		assert.False(t, order.ProceedsOverdueAt.IsZero())
+		order, _ = store.Get("0x02")
+		assert.False(t, order.ProceedsReceivedAt.IsZero(), "origin SETTLED records the proceeds")
+		assert.True(t, order.ProceedsOverdueAt.IsZero())
+		assert.Equal(t, 1.0, registry.Counter("solver_settlement_proceeds_overdue_total", "chain", logutil.NetworkNameByChainID(testOriginChainID)).Value())
+	})
+
+	t.Run("query_errors_are_counted", func(t *testing.T) {
+		r, store, registry := newTestReconciler(t, &statusChainHandler{err: errors.New("rpc down")})
+		seedOrder(t, store, "0x01", orders.StatusFilled)
+
+		report := r.RunOnce(context.Background())
+		assert.Equal(t, 1, report.Errors)
+		assert.Empty(t, report.Discrepancies)
+
+		order, _ := store.Get("0x01")
+		assert.Equal(t, orders.StatusFilled, order.Status)
+		assert.Equal(t, 1.0, registry.Counter("solver_reconcile_runs_total").Value())
+	})
+}
+
+// TestCompareOrderStatus tests the local vs on-chain status matrix
+func TestCompareOrderStatus(t *testing.T) {
+	tests := []struct {
+		local    orders.Status
+		chain    string
+		kind     string
+		repaired orders.Status
+	}{
+		{orders.StatusObserved, orderStatusUnknown, "", ""},
+		{orders.StatusRejected, orderStatusUnknown, "", ""},
+		{orders.StatusFilled, orderStatusUnknown, DiscrepancyMissingFill, orders.StatusFailed},
+		{orders.StatusSettled, orderStatusUnknown, DiscrepancyMissingFill, orders.StatusFailed},
+		{orders.StatusSettled, orderStatusFilled, DiscrepancyMissingSettle, orders.StatusFilled},
+		{orders.StatusFailed, orderStatusFilled, DiscrepancyUnrecordedFill, orders.StatusFilled},
+		{orders.StatusFilled, orderStatusFilled, "", ""},
+		{orders.StatusFilled, orderStatusSettled, DiscrepancyUnrecordedSettle, orders.StatusSettled},
+		{orders.StatusSettled, orderStatusSettled, "", ""},
+		{orders.StatusLostRace, orderStatusFilled, "", ""},
+		{orders.StatusLostRace, orderStatusSettled, "", ""},
+		{orders.StatusFilled, "0xdeadbeef", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.local)+"_"+tt.chain, func(t *testing.T) {
+			d := compareOrderStatus(orders.Order{OrderID: "0x01", Status: tt.local}, tt.chain)
+			if tt.kind == "" {
+				assert.Nil(t, d)
+				return
+			}
+			require.NotNil(t, d)
+			assert.Equal(t, tt.kind, d.Kind)
+			assert.Equal(t, tt.repaired, d.Repaired)
+		})
+	}
+}
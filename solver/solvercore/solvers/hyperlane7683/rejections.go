@@ -0,0 +1,40 @@
+package hyperlane7683
+
+// Module: Rejection tracking
+// - Rejected orders are recorded in the order store with a reason code (see the Reject* constants)
+//   next to the detailed reason, and counted in solver_orders_rejected_total{reason}
+// - SeedRejectionMetrics starts the counters from the store, so they cover every order the solver
+//   has seen rather than only those since the last restart
+// - `solver report rejections` prints the same breakdown from the store
+
+import (
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const rejectedOrdersMetric = "solver_orders_rejected_total"
+
+// SeedRejectionMetrics adds the rejected orders of the store to the rejection counters; call it
+// once at startup
+func SeedRejectionMetrics(registry *metrics.Registry, store *orders.Store) {
+	for _, count := range orders.CountRejections(store.List(), orders.Filter{}) {
+		registry.Counter(rejectedOrdersMetric, "reason", count.Reason).Add(float64(count.Count))
+	}
+}
+
+// rejectOrder counts a rejection, records it in the order store and publishes OrderRejected with
+// the reason code
+func (f *Hyperlane7683Solver) rejectOrder(args *types.ParsedArgs, code, reason string) {
+	f.metrics.Counter(rejectedOrdersMetric, "reason", code).Inc()
+	if f.orderStore == nil {
+		f.publishStatusChange(args.OrderID, args, "", orders.StatusRejected, code)
+		return
+	}
+	previous, _ := f.orderStore.Get(args.OrderID)
+	if err := f.orderStore.Reject(args.OrderID, code, reason); err != nil {
+		fillerLog.Printf("⚠️  Failed to record order %s as rejected: %v\n", args.OrderID, err)
+		return
+	}
+	f.publishStatusChange(args.OrderID, args, previous.Status, orders.StatusRejected, code)
+}
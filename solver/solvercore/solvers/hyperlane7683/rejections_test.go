@@ -0,0 +1,104 @@
+package hyperlane7683
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRejectionTracking(t *testing.T) {
+	t.Setenv("SOLVER_PUB_KEY", "0x00000000000000000000000000000000000000aa")
+	t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+	require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{}))
+
+	orderID := "0x3333333333333333333333333333333333333333333333333333333333333333"
+	token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+
+	newSolver := func(t *testing.T) (*Hyperlane7683Solver, *orders.Store, *metrics.Registry) {
+		destination := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		destination.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(1)))
+		store, err := orders.NewStore(filepath.Join(t.TempDir(), "orders.json"))
+		require.NoError(t, err)
+
+		solver := NewHyperlane7683Solver(
+			func(uint64) (EVMClient, error) { return destination, nil },
+			nil, nil, nil,
+			types.AllowBlockLists{},
+		)
+		solver.SetOrderStore(store)
+		solver.metrics = metrics.NewRegistry()
+		return solver, store, solver.metrics
+	}
+
+	t.Run("rule rejection is recorded with its reason", func(t *testing.T) {
+		solver, store, registry := newSolver(t)
+
+		args := endToEndArgs(orderID, token.Hex(), "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		assert.False(t, ok)
+		assert.ErrorContains(t, err, "Insufficient balance")
+
+		stored, _ := store.Get(orderID)
+		assert.Equal(t, orders.StatusRejected, stored.Status)
+		assert.Equal(t, RejectInsufficientBalance, stored.RejectReason)
+		assert.Equal(t, float64(1), registry.Counter("solver_orders_rejected_total", "reason", RejectInsufficientBalance).Value())
+	})
+
+	t.Run("allow/block lists rejection", func(t *testing.T) {
+		solver, store, registry := newSolver(t)
+		solver.SetAllowBlockLists(types.AllowBlockLists{BlockList: []types.AllowBlockListItem{{SenderAddress: "*", DestinationDomain: "*", RecipientAddress: "*"}}})
+
+		args := endToEndArgs(orderID, token.Hex(), "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+		args.Recipients = []types.Recipient{{DestinationChainName: "Base", RecipientAddress: "*"}}
+		_, err := solver.ProcessIntent(context.Background(), &args)
+		assert.Error(t, err)
+
+		stored, _ := store.Get(orderID)
+		assert.Equal(t, RejectBlocked, stored.RejectReason)
+		assert.Equal(t, float64(1), registry.Counter("solver_orders_rejected_total", "reason", RejectBlocked).Value())
+	})
+
+	t.Run("own orders are left to other fillers", func(t *testing.T) {
+		solver, store, registry := newSolver(t)
+		t.Setenv("IS_DEVNET", "")
+		t.Setenv("SOLVER_PUB_KEY", "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+
+		args := endToEndArgs(orderID, token.Hex(), "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+		args.SenderAddress = "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266"
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		assert.False(t, ok)
+		assert.NoError(t, err)
+
+		stored, _ := store.Get(orderID)
+		assert.Equal(t, RejectOwnOrder, stored.RejectReason)
+		assert.Equal(t, float64(1), registry.Counter("solver_orders_rejected_total", "reason", RejectOwnOrder).Value())
+	})
+
+	t.Run("counters are seeded from the store", func(t *testing.T) {
+		_, store, _ := newSolver(t)
+		for i, code := range []string{RejectBelowMargin, RejectBelowMargin, RejectDeadlineTooClose} {
+			args := endToEndArgs(common.BigToHash(big.NewInt(int64(i+1))).Hex(), token.Hex(), "0xb2", config.BaseSepoliaChainID)
+			require.NoError(t, store.Observe(&args))
+			require.NoError(t, store.Reject(args.OrderID, code, "rejected"))
+		}
+
+		registry := metrics.NewRegistry()
+		SeedRejectionMetrics(registry, store)
+		assert.Equal(t, float64(2), registry.Counter("solver_orders_rejected_total", "reason", RejectBelowMargin).Value())
+		assert.Equal(t, float64(1), registry.Counter("solver_orders_rejected_total", "reason", RejectDeadlineTooClose).Value())
+	})
+}
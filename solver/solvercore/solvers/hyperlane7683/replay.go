@@ -0,0 +1,128 @@
+package hyperlane7683
+
+// Module: Replay of recorded chain traffic through the listeners
+// - Re-runs every recorded event query (EVM FilterLogs, Starknet Events) in recorded order
+// - Events go through the same decoding as the live listeners and are passed to the handler
+// - Listeners are built directly on the recording, bypassing start block resolution and solver state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/replay"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ReplayedOrder is the outcome of one replayed Open event
+type ReplayedOrder struct {
+	OrderID     string `json:"orderId"`
+	OriginChain string `json:"originChain"`
+	BlockNumber uint64 `json:"blockNumber"`
+	Processed   bool   `json:"processed"`
+	Error       string `json:"error,omitempty"`
+}
+
+// ReplayRecording passes the Open events of every recorded event query to handler and
+// returns the handler's outcome per event, in order
+func ReplayRecording(ctx context.Context, recording *replay.Recording, handler base.EventHandler) ([]ReplayedOrder, error) {
+	var results []ReplayedOrder
+	recordingHandler := func(args types.ParsedArgs, originChainName string, blockNumber uint64) (bool, error) {
+		processed, err := handler(args, originChainName, blockNumber)
+		result := ReplayedOrder{
+			OrderID:     args.OrderID,
+			OriginChain: originChainName,
+			BlockNumber: blockNumber,
+			Processed:   processed,
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+		return processed, err
+	}
+
+	evmListeners := make(map[uint64]*evmListener)
+	starknetListeners := make(map[uint64]*starknetListener)
+
+	for _, entry := range recording.Entries() {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		switch entry.Method {
+		case replay.MethodEVMFilterLogs:
+			var query ethereum.FilterQuery
+			if err := json.Unmarshal(entry.Request, &query); err != nil {
+				return results, fmt.Errorf("invalid recorded log query %d: %w", entry.Seq, err)
+			}
+			if query.FromBlock == nil || query.ToBlock == nil || len(query.Addresses) != 1 {
+				continue // Not a listener query
+			}
+
+			listener, exists := evmListeners[entry.ChainID]
+			if !exists {
+				listener = newReplayEVMListener(recording, entry.ChainID, query.Addresses[0])
+				evmListeners[entry.ChainID] = listener
+			}
+			if _, err := listener.processBlockRange(ctx, query.FromBlock.Uint64(), query.ToBlock.Uint64(), recordingHandler); err != nil {
+				// The live listener retried failed ranges, so the retry follows in the recording
+				fmt.Printf("%s⚠️  Replayed range failed: %v\n", logutil.Prefix(listener.config.ChainName), err)
+			}
+
+		case replay.MethodStarknetEvents:
+			var input rpc.EventsInput
+			if err := json.Unmarshal(entry.Request, &input); err != nil {
+				return results, fmt.Errorf("invalid recorded event query %d: %w", entry.Seq, err)
+			}
+			if input.FromBlock.Number == nil || input.ToBlock.Number == nil || input.Address == nil {
+				continue // Not a listener query
+			}
+
+			listener, exists := starknetListeners[entry.ChainID]
+			if !exists {
+				listener = newReplayStarknetListener(recording, entry.ChainID, input)
+				starknetListeners[entry.ChainID] = listener
+			}
+			if _, err := listener.processBlockRange(ctx, *input.FromBlock.Number, *input.ToBlock.Number, recordingHandler); err != nil {
+				fmt.Printf("%s⚠️  Replayed range failed: %v\n", logutil.Prefix(listener.config.ChainName), err)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// newReplayEVMListener builds an EVM listener reading from the recording
+func newReplayEVMListener(recording *replay.Recording, chainID uint64, contract common.Address) *evmListener {
+	return &evmListener{
+		config: &base.ListenerConfig{
+			ContractAddress: contract.Hex(),
+			ChainName:       logutil.NetworkNameByChainID(chainID),
+		},
+		client:          recording.EVMClient(chainID),
+		contractAddress: contract,
+		stopChan:        make(chan struct{}),
+		mu:              sync.RWMutex{},
+	}
+}
+
+// newReplayStarknetListener builds a Starknet listener reading from the recording
+func newReplayStarknetListener(recording *replay.Recording, chainID uint64, input rpc.EventsInput) *starknetListener {
+	return &starknetListener{
+		config: &base.ListenerConfig{
+			ContractAddress: input.Address.String(),
+			ChainName:       logutil.NetworkNameByChainID(chainID),
+		},
+		provider:        recording.StarknetProvider(chainID),
+		contractAddress: input.Address,
+		stopChan:        make(chan struct{}),
+		mu:              sync.RWMutex{},
+	}
+}
@@ -0,0 +1,74 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/replay"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReplayRecording tests that Open events recorded by a live listener are dispatched again on replay
+func TestReplayRecording(t *testing.T) {
+	settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	backend := chainmock.NewEVMBackend(config.EthereumSepoliaChainID)
+
+	var orderID [32]byte
+	orderID[31] = 0x42
+	backend.AddLog(openLog(t, settler, orderID, 12))
+	backend.SetBlockNumber(20)
+
+	// Record a live run: one range with the order, one failing range
+	path := filepath.Join(t.TempDir(), "traffic.jsonl")
+	recorder, err := replay.NewRecorder(path)
+	require.NoError(t, err)
+	listener := &evmListener{
+		config:             &base.ListenerConfig{ChainName: "Ethereum", ContractAddress: settler.Hex()},
+		client:             replay.NewRecordingEVMClient(backend, config.EthereumSepoliaChainID, recorder),
+		contractAddress:    settler,
+		lastProcessedBlock: 9,
+		stopChan:           make(chan struct{}),
+	}
+	noop := func(types.ParsedArgs, string, uint64) (bool, error) { return true, nil }
+	_, err = listener.processBlockRange(context.Background(), 10, 15, noop)
+	require.NoError(t, err)
+	backend.FailNext("FilterLogs", errors.New("rpc down"))
+	_, err = listener.processBlockRange(context.Background(), 16, 20, noop)
+	require.Error(t, err)
+	require.NoError(t, recorder.Close())
+
+	recording, err := replay.Load(path)
+	require.NoError(t, err)
+
+	t.Run("orders are replayed with the handler outcome", func(t *testing.T) {
+		var received []string
+		results, err := ReplayRecording(context.Background(), recording, func(args types.ParsedArgs, originChainName string, blockNumber uint64) (bool, error) {
+			received = append(received, args.OrderID)
+			return false, errors.New("insufficient balance")
+		})
+		require.NoError(t, err)
+
+		orderHex := common.BytesToHash(orderID[:]).Hex()
+		assert.Equal(t, []string{orderHex}, received)
+		require.Len(t, results, 1)
+		assert.Equal(t, orderHex, results[0].OrderID)
+		assert.Equal(t, uint64(12), results[0].BlockNumber)
+		assert.False(t, results[0].Processed)
+		assert.Equal(t, "insufficient balance", results[0].Error)
+	})
+
+	t.Run("cancelled context stops the replay", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := ReplayRecording(ctx, recording, noop)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
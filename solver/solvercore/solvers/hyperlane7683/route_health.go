@@ -0,0 +1,268 @@
+package hyperlane7683
+
+// Module: Hyperlane route health pre-check (optional)
+// - A settlement is a Hyperlane message from the fill chain to the order's origin chain, verified there
+//   by the interchain security module (ISM) of the origin router, or the mailbox default ISM
+// - Before settling, reads that ISM's validators and threshold; with a ValidatorAnnounce contract configured
+//   for the fill chain, also counts the validators that announced where they publish signatures
+// - A route looks unhealthy when the ISM can't be read, has no usable threshold, or fewer validators than
+//   the threshold announced themselves. It is logged and exposed as metrics; with deferral enabled the
+//   settlement is not sent and the order stays FILLED
+// - ISMs that aren't multisig ISMs (e.g. test ISMs on devnets) are not inspected; routes to Starknet
+//   origins are not checked
+// - Results are cached per route for HYPERLANE_ROUTE_CHECK_TTL_SECONDS
+//
+// Settings:
+// - HYPERLANE_ROUTE_CHECK: enable the pre-check (default false)
+// - HYPERLANE_ROUTE_CHECK_DEFER: defer settlement over unhealthy routes (default false: warn only)
+// - HYPERLANE_ROUTE_CHECK_TTL_SECONDS: how long a route check is reused (default 300)
+// - HYPERLANE_VALIDATOR_ANNOUNCE_<NETWORK>: ValidatorAnnounce contract of a network, e.g.
+//   HYPERLANE_VALIDATOR_ANNOUNCE_BASE (unset: announcements are not checked)
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const defaultRouteCheckTTLSeconds = 300
+
+// ErrRouteUnhealthy is returned for settlements deferred because their Hyperlane route looks unhealthy
+var ErrRouteUnhealthy = errors.New("hyperlane route unhealthy")
+
+// routeHealthABI holds the Hyperlane calls the check makes: mailbox, multisig ISM and ValidatorAnnounce
+const routeHealthABI = `[
+	{"type": "function", "name": "defaultIsm", "inputs": [], "outputs": [{"type": "address"}], "stateMutability": "view"},
+	{"type": "function", "name": "validatorsAndThreshold", "inputs": [{"type": "bytes", "name": "message"}],
+	 "outputs": [{"type": "address[]"}, {"type": "uint8"}], "stateMutability": "view"},
+	{"type": "function", "name": "getAnnouncedStorageLocations", "inputs": [{"type": "address[]", "name": "validators"}],
+	 "outputs": [{"type": "string[][]"}], "stateMutability": "view"}
+]`
+
+// RouteHealth is the result of checking the route from a fill chain to an order's origin chain
+type RouteHealth struct {
+	FromChainID uint64
+	ToChainID   uint64
+	ISM         string
+	Validators  int
+	Threshold   int
+	Announced   int // Validators with announced storage locations, -1 when not checked
+	Healthy     bool
+	Reason      string
+	CheckedAt   time.Time
+}
+
+type routeKey struct {
+	from, to uint64
+}
+
+// RouteHealthChecker checks Hyperlane routes before settlements. A nil checker reports every route healthy.
+type RouteHealthChecker struct {
+	mu           sync.Mutex
+	getEVMClient func(chainID uint64) (EVMClient, error)
+	routerFor    func(chainID uint64) (string, error)
+	announceFor  func(chainID uint64) string
+	deferSettle  bool
+	ttl          time.Duration
+	results      map[routeKey]RouteHealth
+	metrics      *metrics.Registry
+	now          func() time.Time
+}
+
+// NewRouteHealthChecker creates a checker reading the chains through getEVMClient
+func NewRouteHealthChecker(getEVMClient func(chainID uint64) (EVMClient, error), deferSettle bool, ttl time.Duration) *RouteHealthChecker {
+	return &RouteHealthChecker{
+		getEVMClient: getEVMClient,
+		routerFor:    hyperlaneRouterAddress,
+		announceFor:  validatorAnnounceAddress,
+		deferSettle:  deferSettle,
+		ttl:          ttl,
+		results:      make(map[routeKey]RouteHealth),
+		metrics:      metrics.Default(),
+		now:          time.Now,
+	}
+}
+
+// routeHealthCheckerFromEnv returns the configured checker, or nil when HYPERLANE_ROUTE_CHECK is off
+func routeHealthCheckerFromEnv(getEVMClient func(chainID uint64) (EVMClient, error)) *RouteHealthChecker {
+	if !envutil.GetEnvBool("HYPERLANE_ROUTE_CHECK", false) || getEVMClient == nil {
+		return nil
+	}
+	ttl := time.Duration(envutil.GetEnvInt("HYPERLANE_ROUTE_CHECK_TTL_SECONDS", defaultRouteCheckTTLSeconds)) * time.Second
+	return NewRouteHealthChecker(getEVMClient, envutil.GetEnvBool("HYPERLANE_ROUTE_CHECK_DEFER", false), ttl)
+}
+
+// validatorAnnounceAddress returns the ValidatorAnnounce contract configured for a chain, if any
+func validatorAnnounceAddress(chainID uint64) string {
+	network, ok := networkForChainID(chainID)
+	if !ok {
+		return ""
+	}
+	return envutil.GetEnvWithDefault("HYPERLANE_VALIDATOR_ANNOUNCE_"+strings.ToUpper(network.Name), "")
+}
+
+// BeforeSettle checks the route of a settlement sent from fromChainID to the origin toChainID. It returns
+// ErrRouteUnhealthy when the route looks unhealthy and deferral is enabled, nil otherwise.
+func (c *RouteHealthChecker) BeforeSettle(ctx context.Context, fromChainID, toChainID uint64) error {
+	if c == nil || isStarknetChain(toChainID) {
+		return nil
+	}
+	health := c.Check(ctx, fromChainID, toChainID)
+	if health.Healthy || !c.deferSettle {
+		return nil
+	}
+	return fmt.Errorf("%w: %s, settlement deferred", ErrRouteUnhealthy, health.Reason)
+}
+
+// Check returns the health of the route from fromChainID to toChainID, reusing a recent result
+func (c *RouteHealthChecker) Check(ctx context.Context, fromChainID, toChainID uint64) RouteHealth {
+	key := routeKey{from: fromChainID, to: toChainID}
+	c.mu.Lock()
+	cached, ok := c.results[key]
+	c.mu.Unlock()
+	if ok && c.now().Sub(cached.CheckedAt) < c.ttl {
+		return cached
+	}
+
+	health := c.check(ctx, fromChainID, toChainID)
+	health.CheckedAt = c.now()
+	c.mu.Lock()
+	c.results[key] = health
+	c.mu.Unlock()
+
+	from, to := logutil.NetworkNameByChainID(fromChainID), logutil.NetworkNameByChainID(toChainID)
+	healthy := 0.0
+	if health.Healthy {
+		healthy = 1
+	} else {
+		c.metrics.Counter("solver_hyperlane_route_unhealthy_total", "from", from, "to", to).Inc()
+		fmt.Printf("%s⚠️  Hyperlane route %s → %s looks unhealthy: %s\n", logutil.Prefix(from), from, to, health.Reason)
+	}
+	c.metrics.Gauge("solver_hyperlane_route_healthy", "from", from, "to", to).Set(healthy)
+	return health
+}
+
+// check reads the ISM of the origin router and the announcements of its validators
+func (c *RouteHealthChecker) check(ctx context.Context, fromChainID, toChainID uint64) RouteHealth {
+	health := RouteHealth{FromChainID: fromChainID, ToChainID: toChainID, Announced: -1}
+	unhealthy := func(format string, args ...any) RouteHealth {
+		health.Reason = fmt.Sprintf(format, args...)
+		return health
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(routeHealthABI))
+	if err != nil {
+		return unhealthy("failed to parse route health ABI: %v", err)
+	}
+	client, err := c.getEVMClient(toChainID)
+	if err != nil {
+		return unhealthy("no client for origin chain %d: %v", toChainID, err)
+	}
+	ism, err := c.originISM(ctx, client, parsedABI, toChainID)
+	if err != nil {
+		return unhealthy("ISM unreadable: %v", err)
+	}
+	health.ISM = ism.Hex()
+
+	out, err := callView(ctx, client, parsedABI, ism, "validatorsAndThreshold", []byte{})
+	if err != nil {
+		health.Healthy = true
+		health.Reason = "ISM is not a multisig ISM, validators not checked"
+		return health
+	}
+	validators := out[0].([]common.Address)
+	health.Validators = len(validators)
+	health.Threshold = int(out[1].(uint8))
+	if health.Threshold == 0 || health.Validators < health.Threshold {
+		return unhealthy("ISM %s has %d validators for a threshold of %d", health.ISM, health.Validators, health.Threshold)
+	}
+
+	announce := c.announceFor(fromChainID)
+	if announce == "" || isStarknetChain(fromChainID) {
+		health.Healthy = true
+		health.Reason = fmt.Sprintf("%d-of-%d multisig ISM", health.Threshold, health.Validators)
+		return health
+	}
+	fromClient, err := c.getEVMClient(fromChainID)
+	if err != nil {
+		return unhealthy("no client for chain %d: %v", fromChainID, err)
+	}
+	out, err = callView(ctx, fromClient, parsedABI, common.HexToAddress(announce), "getAnnouncedStorageLocations", validators)
+	if err != nil {
+		return unhealthy("ValidatorAnnounce %s unreadable: %v", announce, err)
+	}
+	health.Announced = 0
+	for _, locations := range out[0].([][]string) {
+		if len(locations) > 0 {
+			health.Announced++
+		}
+	}
+	if health.Announced < health.Threshold {
+		return unhealthy("only %d of %d validators announced, threshold %d", health.Announced, health.Validators, health.Threshold)
+	}
+	health.Healthy = true
+	health.Reason = fmt.Sprintf("%d-of-%d multisig ISM, %d validators announced", health.Threshold, health.Validators, health.Announced)
+	return health
+}
+
+// originISM returns the ISM of the origin router, falling back to the mailbox default ISM
+func (c *RouteHealthChecker) originISM(ctx context.Context, client EVMClient, parsedABI abi.ABI, chainID uint64) (common.Address, error) {
+	routerAddress, err := c.routerFor(chainID)
+	if err != nil {
+		return common.Address{}, err
+	}
+	router, err := contracts.NewHyperlane7683Caller(common.HexToAddress(routerAddress), client)
+	if err != nil {
+		return common.Address{}, err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	ism, err := router.InterchainSecurityModule(opts)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("interchainSecurityModule: %w", err)
+	}
+	if ism != (common.Address{}) {
+		return ism, nil
+	}
+
+	mailbox, err := router.Mailbox(opts)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("mailbox: %w", err)
+	}
+	out, err := callView(ctx, client, parsedABI, mailbox, "defaultIsm")
+	if err != nil {
+		return common.Address{}, fmt.Errorf("defaultIsm: %w", err)
+	}
+	ism = out[0].(common.Address)
+	if ism == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("mailbox %s has no default ISM", mailbox.Hex())
+	}
+	return ism, nil
+}
+
+// callView calls a view method of routeHealthABI and unpacks its outputs
+func callView(ctx context.Context, client EVMClient, parsedABI abi.ABI, to common.Address, method string, args ...any) ([]any, error) {
+	data, err := parsedABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("%s returned no data", method)
+	}
+	return parsedABI.Unpack(method, res)
+}
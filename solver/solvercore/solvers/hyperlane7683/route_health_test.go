@@ -0,0 +1,132 @@
+package hyperlane7683
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// routeHealthChains returns a checker over two in-memory chains: the origin (Ethereum) holding the router,
+// mailbox and ISM, and the fill chain (Base) holding the ValidatorAnnounce contract
+func routeHealthChains(t *testing.T, routerISM common.Address, threshold uint8, announced int) (*RouteHealthChecker, *chainmock.EVMBackend, *atomic.Int32) {
+	t.Helper()
+	parsedABI, err := abi.JSON(strings.NewReader(routeHealthABI))
+	require.NoError(t, err)
+	pack := func(method string, values ...any) chainmock.EVMCallHandler {
+		out, err := parsedABI.Methods[method].Outputs.Pack(values...)
+		require.NoError(t, err)
+		return func(ethereum.CallMsg) ([]byte, error) { return out, nil }
+	}
+
+	router := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	mailbox := common.HexToAddress("0x00000000000000000000000000000000000000c3")
+	defaultISM := common.HexToAddress("0x00000000000000000000000000000000000000d4")
+	announce := common.HexToAddress("0x00000000000000000000000000000000000000e5")
+	validators := []common.Address{{0x01}, {0x02}, {0x03}}
+
+	origin := chainmock.NewEVMBackend(config.EthereumSepoliaChainID)
+	origin.HandleCall(router, chainmock.Selector("interchainSecurityModule()"), uint256Result(new(big.Int).SetBytes(routerISM.Bytes())))
+	origin.HandleCall(router, chainmock.Selector("mailbox()"), uint256Result(new(big.Int).SetBytes(mailbox.Bytes())))
+	origin.HandleCall(mailbox, chainmock.Selector("defaultIsm()"), pack("defaultIsm", defaultISM))
+	origin.HandleCall(defaultISM, chainmock.Selector("validatorsAndThreshold(bytes)"), pack("validatorsAndThreshold", validators, threshold))
+
+	locations := make([][]string, len(validators))
+	for i := 0; i < announced; i++ {
+		locations[i] = []string{"s3://signatures"}
+	}
+	var announceCalls atomic.Int32
+	announcedResult := pack("getAnnouncedStorageLocations", locations)
+	fill := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+	fill.HandleCall(announce, chainmock.Selector("getAnnouncedStorageLocations(address[])"), func(msg ethereum.CallMsg) ([]byte, error) {
+		announceCalls.Add(1)
+		return announcedResult(msg)
+	})
+
+	clients := map[uint64]EVMClient{config.EthereumSepoliaChainID: origin, config.BaseSepoliaChainID: fill}
+	checker := NewRouteHealthChecker(func(chainID uint64) (EVMClient, error) { return clients[chainID], nil }, true, time.Minute)
+	checker.routerFor = func(uint64) (string, error) { return router.Hex(), nil }
+	checker.announceFor = func(chainID uint64) string {
+		if chainID == config.BaseSepoliaChainID {
+			return announce.Hex()
+		}
+		return ""
+	}
+	checker.metrics = metrics.NewRegistry()
+	return checker, origin, &announceCalls
+}
+
+func TestRouteHealthChecker(t *testing.T) {
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+	ctx := context.Background()
+
+	t.Run("announced multisig validators meet the threshold", func(t *testing.T) {
+		checker, _, calls := routeHealthChains(t, common.Address{}, 2, 2)
+
+		health := checker.Check(ctx, config.BaseSepoliaChainID, config.EthereumSepoliaChainID)
+		assert.True(t, health.Healthy, health.Reason)
+		assert.Equal(t, "0x00000000000000000000000000000000000000D4", health.ISM, "mailbox default ISM")
+		assert.Equal(t, 3, health.Validators)
+		assert.Equal(t, 2, health.Threshold)
+		assert.Equal(t, 2, health.Announced)
+		require.NoError(t, checker.BeforeSettle(ctx, config.BaseSepoliaChainID, config.EthereumSepoliaChainID))
+		assert.Equal(t, int32(1), calls.Load(), "results are cached")
+	})
+
+	t.Run("too few announced validators defer settlement", func(t *testing.T) {
+		checker, _, _ := routeHealthChains(t, common.Address{}, 2, 1)
+
+		err := checker.BeforeSettle(ctx, config.BaseSepoliaChainID, config.EthereumSepoliaChainID)
+		assert.ErrorIs(t, err, ErrRouteUnhealthy)
+		assert.ErrorContains(t, err, "only 1 of 3 validators announced")
+		assert.Equal(t, 1.0, checker.metrics.Counter("solver_hyperlane_route_unhealthy_total", "from", "Base", "to", "Ethereum").Value())
+		assert.Equal(t, 0.0, checker.metrics.Gauge("solver_hyperlane_route_healthy", "from", "Base", "to", "Ethereum").Value())
+
+		checker.deferSettle = false
+		assert.NoError(t, checker.BeforeSettle(ctx, config.BaseSepoliaChainID, config.EthereumSepoliaChainID), "warn only")
+	})
+
+	t.Run("zero threshold is unhealthy", func(t *testing.T) {
+		checker, _, _ := routeHealthChains(t, common.Address{}, 0, 3)
+		health := checker.Check(ctx, config.BaseSepoliaChainID, config.EthereumSepoliaChainID)
+		assert.False(t, health.Healthy)
+		assert.Contains(t, health.Reason, "threshold of 0")
+	})
+
+	t.Run("non-multisig router ISM is not inspected", func(t *testing.T) {
+		checker, _, _ := routeHealthChains(t, common.Address{0x0f}, 2, 0)
+		health := checker.Check(ctx, config.BaseSepoliaChainID, config.EthereumSepoliaChainID)
+		assert.True(t, health.Healthy)
+		assert.Equal(t, common.Address{0x0f}.Hex(), health.ISM)
+		assert.Equal(t, -1, health.Announced)
+	})
+
+	t.Run("unreadable ISM is unhealthy", func(t *testing.T) {
+		checker, origin, _ := routeHealthChains(t, common.Address{}, 2, 2)
+		origin.FailNext("CallContract", assert.AnError)
+		health := checker.Check(ctx, config.BaseSepoliaChainID, config.EthereumSepoliaChainID)
+		assert.False(t, health.Healthy)
+		assert.Contains(t, health.Reason, "ISM unreadable")
+	})
+
+	t.Run("nil checker and Starknet origins are not checked", func(t *testing.T) {
+		var checker *RouteHealthChecker
+		assert.NoError(t, checker.BeforeSettle(ctx, config.BaseSepoliaChainID, config.EthereumSepoliaChainID))
+
+		checker, _, _ = routeHealthChains(t, common.Address{}, 0, 0)
+		assert.NoError(t, checker.BeforeSettle(ctx, config.BaseSepoliaChainID, config.StarknetSepoliaChainID))
+	})
+}
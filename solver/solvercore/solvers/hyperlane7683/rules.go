@@ -8,12 +8,17 @@ package hyperlane7683
 import (
 	"context"
 	"fmt"
+	"math/big"
+	"time"
 
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/pricefeed"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/rpctimeout"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/ethereum/go-ethereum/common"
@@ -21,16 +26,51 @@ import (
 	"github.com/holiman/uint256"
 )
 
+// rulesLog logs rule results at the "rules" log level
+var rulesLog = logutil.For(logutil.ComponentRules)
+
 const (
-	starknetNetworkName = "Starknet"
 	// Profit margin calculation (100 = 100%)
 	profitMarginMultiplier = 100
 )
 
+// Rejection reason codes recorded with rejected orders and counted in solver_orders_rejected_total
+const (
+	RejectOwnOrder            = "own_order"            // Opened by the solver itself (see rebalancer.go)
+	RejectBlocked             = "blocked"              // Allow/block lists
+	RejectFillsPaused         = "fills_paused"         // Fills toward the destination paused by the operator
+	RejectRouteDisabled       = "route_disabled"       // RouteCheck
+	RejectDeadlineTooClose    = "deadline_too_close"   // DeadlineCheck
+	RejectUntrustedSettler    = "untrusted_settler"    // SettlerCheck
+	RejectTokenNotAllowed     = "token_not_allowed"    // TokenCheck, unknown token
+	RejectOrderSize           = "order_size"           // TokenCheck, outside the token's min/max order size
+	RejectDecimalsMismatch    = "decimals_mismatch"    // DecimalsCheck
+	RejectDustOrder           = "dust_order"           // MinFillCheck
+	RejectInsufficientBalance = "insufficient_balance" // BalanceCheck
+	RejectBelowMargin         = "below_margin"         // ProfitabilityCheck
+	RejectNoPrice             = "no_price"             // ProfitabilityCheck, cross-token order with an unpriced token
+)
+
+// ruleRejectReasons is the reason code of each default rule, for results that don't set one;
+// custom rules default to their name
+var ruleRejectReasons = map[string]string{
+	"RouteCheck":         RejectRouteDisabled,
+	"DeadlineCheck":      RejectDeadlineTooClose,
+	"SettlerCheck":       RejectUntrustedSettler,
+	"TokenCheck":         RejectTokenNotAllowed,
+	"DecimalsCheck":      RejectDecimalsMismatch,
+	"MinFillCheck":       RejectDustOrder,
+	"BalanceCheck":       RejectInsufficientBalance,
+	"ProfitabilityCheck": RejectBelowMargin,
+}
+
 // RuleResult represents the result of a rule evaluation
 type RuleResult struct {
 	Passed bool
 	Reason string
+	// Code is the machine-readable rejection reason of a failed rule; EvaluateAll fills it in
+	// from the rule when empty
+	Code string
 }
 
 // Rule defines the interface for validation rules
@@ -47,12 +87,62 @@ type RulesEngine struct {
 
 // NewRulesEngine creates a new rules engine with default rules
 func NewRulesEngine() *RulesEngine {
-	return &RulesEngine{
-		rules: []Rule{
-			&BalanceRule{},
-			&ProfitabilityRule{},
+	return NewRulesEngineWithClients(nil, nil)
+}
+
+// NewRulesEngineWithClients creates a rules engine with default rules whose balance checks
+// use the given client getters; nil getters dial the configured RPC for every check.
+// Rule settings are taken from the active runtime config, so a reload applies to the next engine.
+func NewRulesEngineWithClients(
+	getEVMClient func(chainID uint64) (EVMClient, error),
+	getStarknetClient func(chainID uint64) (rpc.RPCProvider, error),
+) *RulesEngine {
+	runtime := config.ActiveRuntime()
+	defaults := []Rule{
+		&RouteRule{Routes: runtime},
+		&DeadlineRule{MinWindow: runtime.MinFillWindow(), now: time.Now},
+		&SettlerRule{TrustedSettlers: runtime.Rules.TrustedSettlers},
+		&TokenRule{Registry: runtime.TokenRegistry(), AllowUnknown: runtime.Rules.AllowUnknownTokens, Pairs: runtime},
+		&DecimalsRule{MaxRateFactor: runtime.MaxRateFactor()},
+		&MinFillRule{MinNotional: runtime.MinFillNotional()},
+		&BalanceRule{getEVMClient: getEVMClient, getStarknetClient: getStarknetClient},
+		&ProfitabilityRule{
+			MinProfit:      runtime.MinProfit(),
+			Prices:         pricefeed.Default(),
+			MinMarginBps:   runtime.Rules.MinMarginBps,
+			MaxSlippageBps: runtime.Rules.MaxSlippageBps,
+			Pairs:          runtime,
 		},
 	}
+
+	engine := &RulesEngine{}
+	for _, rule := range defaults {
+		if runtime.RuleEnabled(rule.Name()) {
+			engine.rules = append(engine.rules, rule)
+		}
+	}
+	return engine
+}
+
+// ValidateRuleConfig rejects runtime configs that disable rules which do not exist
+func ValidateRuleConfig(runtime *config.RuntimeConfig) error {
+	known := make(map[string]bool)
+	for _, rule := range NewRulesEngine().rules {
+		known[rule.Name()] = true
+	}
+	for _, name := range runtime.Rules.Disabled {
+		if !known[name] {
+			return fmt.Errorf("rules.disabled: unknown rule %q", name)
+		}
+	}
+	for network, settlers := range runtime.Rules.TrustedSettlers {
+		for i, settler := range settlers {
+			if _, err := normalizeSettler(network, settler); err != nil {
+				return fmt.Errorf("rules.trustedSettlers.%s[%d]: %w", network, i, err)
+			}
+		}
+	}
+	return nil
 }
 
 // AddRule adds a custom rule to the engine
@@ -69,16 +159,273 @@ func (re *RulesEngine) EvaluateAll(ctx context.Context, args *types.ParsedArgs)
 	for _, rule := range re.rules {
 		result := rule.Evaluate(ctx, args)
 		if !result.Passed {
-			logutil.CrossChainOperation(fmt.Sprintf("Rule '%s' failed: %s", rule.Name(), result.Reason), originChainID, destChainID, args.OrderID)
+			if result.Code == "" {
+				result.Code = rejectReason(rule)
+			}
+			rulesLog.CrossChainOperation(fmt.Sprintf("Rule '%s' failed: %s", rule.Name(), result.Reason), originChainID, destChainID, args.OrderID)
 			return result
 		}
-		logutil.CrossChainOperation(fmt.Sprintf("Rule '%s' passed", rule.Name()), originChainID, destChainID, args.OrderID)
+		rulesLog.CrossChainOperation(fmt.Sprintf("Rule '%s' passed", rule.Name()), originChainID, destChainID, args.OrderID)
 	}
 	return RuleResult{Passed: true, Reason: "All rules passed"}
 }
 
+// rejectReason returns the default rejection reason code of a rule
+func rejectReason(rule Rule) string {
+	if code, ok := ruleRejectReasons[rule.Name()]; ok {
+		return code
+	}
+	return rule.Name()
+}
+
+// RouteRule rejects orders from an origin to a destination network the routing table doesn't serve
+type RouteRule struct {
+	Routes interface {
+		RouteEnabled(origin, destination string) bool
+	}
+}
+
+func (rr *RouteRule) Name() string {
+	return "RouteCheck"
+}
+
+func (rr *RouteRule) Evaluate(_ context.Context, args *types.ParsedArgs) RuleResult {
+	origin, ok := networkForChainID(args.ResolvedOrder.OriginChainID.Uint64())
+	if !ok {
+		return RuleResult{Passed: false, Reason: fmt.Sprintf("Unknown origin chain %s", args.ResolvedOrder.OriginChainID.String())}
+	}
+	for _, instruction := range args.ResolvedOrder.FillInstructions {
+		destination, ok := networkForChainID(instruction.DestinationChainID.Uint64())
+		if !ok {
+			return RuleResult{Passed: false, Reason: fmt.Sprintf("Unknown destination chain %s", instruction.DestinationChainID.String())}
+		}
+		if !rr.Routes.RouteEnabled(origin.Name, destination.Name) {
+			return RuleResult{Passed: false, Reason: fmt.Sprintf("Route %s → %s is not enabled in the routing table", origin.Name, destination.Name)}
+		}
+	}
+	return RuleResult{Passed: true, Reason: "Route enabled"}
+}
+
+// DeadlineRule rejects orders whose fill deadline has passed or is less than MinWindow away,
+// since the fill would land too late to be accepted by the destination settler
+type DeadlineRule struct {
+	MinWindow time.Duration
+	now       func() time.Time
+}
+
+func (dr *DeadlineRule) Name() string {
+	return "DeadlineCheck"
+}
+
+func (dr *DeadlineRule) Evaluate(_ context.Context, args *types.ParsedArgs) RuleResult {
+	if args.ResolvedOrder.FillDeadline == 0 {
+		return RuleResult{Passed: true, Reason: "No fill deadline"}
+	}
+
+	now := time.Now
+	if dr.now != nil {
+		now = dr.now
+	}
+	deadline := time.Unix(int64(args.ResolvedOrder.FillDeadline), 0)
+	left := deadline.Sub(now())
+	if left <= 0 || left < dr.MinWindow {
+		return RuleResult{Passed: false, Reason: fmt.Sprintf("Fill deadline %s too close: %s left, need %s",
+			deadline.UTC().Format(time.RFC3339), left.Truncate(time.Second), dr.MinWindow)}
+	}
+	return RuleResult{Passed: true, Reason: fmt.Sprintf("%s left before the fill deadline", left.Truncate(time.Second))}
+}
+
+// SettlerRule rejects orders whose destination settler is neither the configured Hyperlane7683
+// contract of the destination chain nor a trusted settler, so funds never go to unknown contracts
+type SettlerRule struct {
+	// TrustedSettlers lists extra accepted settler addresses by network name
+	TrustedSettlers map[string][]string
+}
+
+func (sr *SettlerRule) Name() string {
+	return "SettlerCheck"
+}
+
+func (sr *SettlerRule) Evaluate(_ context.Context, args *types.ParsedArgs) RuleResult {
+	for i, instruction := range args.ResolvedOrder.FillInstructions {
+		network, ok := networkForChainID(instruction.DestinationChainID.Uint64())
+		if !ok {
+			return RuleResult{Passed: false, Reason: fmt.Sprintf("Unknown destination chain %s", instruction.DestinationChainID.String())}
+		}
+
+		settler, err := normalizeSettler(network.Name, instruction.DestinationSettler)
+		if err != nil {
+			return RuleResult{Passed: false, Reason: fmt.Sprintf("Invalid destination settler %q: %v", instruction.DestinationSettler, err)}
+		}
+		if !sr.trusted(network, settler) {
+			return RuleResult{
+				Passed: false,
+				Reason: fmt.Sprintf("Destination settler %s of fill instruction %d is not a known Hyperlane7683 contract on %s",
+					settler, i+1, network.Name),
+			}
+		}
+	}
+	return RuleResult{Passed: true, Reason: "Destination settlers are known contracts"}
+}
+
+// trusted reports whether a normalized settler is the configured Hyperlane7683 address or a trusted settler
+func (sr *SettlerRule) trusted(network config.NetworkConfig, settler string) bool {
+	candidates := append([]string{configuredSettler(network)}, sr.TrustedSettlers[network.Name]...)
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+		if normalized, err := normalizeSettler(network.Name, candidate); err == nil && normalized == settler {
+			return true
+		}
+	}
+	return false
+}
+
+// configuredSettler returns the Hyperlane7683 address configured for a network; Starknet addresses
+// are read from the environment because NetworkConfig only holds 20-byte addresses
+func configuredSettler(network config.NetworkConfig) string {
+	if config.IsStarknetNetwork(network.Name) {
+		return config.StarknetHyperlaneAddress(network.Name)
+	}
+	return network.HyperlaneAddress.Hex()
+}
+
+// normalizeSettler converts a settler address into the form the fill handlers call on the network:
+// a felt on Starknet, the last 20 bytes on EVM chains
+func normalizeSettler(networkName, address string) (string, error) {
+	if config.IsStarknetNetwork(networkName) {
+		settler, err := types.ToStarknetAddress(address)
+		if err != nil {
+			return "", err
+		}
+		return settler.String(), nil
+	}
+	settler, err := types.ToEVMAddress(address)
+	if err != nil {
+		return "", err
+	}
+	return settler.Hex(), nil
+}
+
+// TokenRule rejects orders with tokens missing from the token registry (unless AllowUnknown is set)
+// and orders whose spent amount is outside a token's min/max order size or the max order size of
+// their token pair. Without a registry every token is accepted.
+type TokenRule struct {
+	Registry     *config.TokenRegistry
+	AllowUnknown bool
+	// Pairs holds per token pair thresholds; nil means none
+	Pairs pairRulesLookup
+}
+
+func (tr *TokenRule) Name() string {
+	return "TokenCheck"
+}
+
+func (tr *TokenRule) Evaluate(_ context.Context, args *types.ParsedArgs) RuleResult {
+	if tr.Registry == nil || tr.Registry.Len() == 0 {
+		return RuleResult{Passed: true, Reason: "No token registry configured"}
+	}
+
+	// Tokens received on the origin chain must be known too, the solver is paid in them
+	for _, minReceived := range args.ResolvedOrder.MinReceived {
+		chainID := outputChainID(minReceived, args.ResolvedOrder.OriginChainID)
+		if _, ok := tr.Registry.Lookup(chainID, minReceived.Token); !ok && !tr.AllowUnknown {
+			return RuleResult{Passed: false, Reason: fmt.Sprintf("Unknown token %s on chain %d", minReceived.Token, chainID)}
+		}
+	}
+
+	pair, _ := orderPairRules(tr.Pairs, args)
+	for _, maxSpent := range args.ResolvedOrder.MaxSpent {
+		chainID := outputChainID(maxSpent, args.ResolvedOrder.FillInstructions[0].DestinationChainID)
+		token, ok := tr.Registry.Lookup(chainID, maxSpent.Token)
+		if !ok {
+			if tr.AllowUnknown {
+				continue
+			}
+			return RuleResult{Passed: false, Reason: fmt.Sprintf("Unknown token %s on chain %d", maxSpent.Token, chainID)}
+		}
+
+		if minOrder := token.MinOrder(); minOrder != nil && maxSpent.Amount.Cmp(minOrder) < 0 {
+			return RuleResult{Passed: false, Code: RejectOrderSize, Reason: fmt.Sprintf("Order size %s below minimum %s",
+				token.Format(maxSpent.Amount), token.Format(minOrder))}
+		}
+		if maxOrder := token.MaxOrder(); maxOrder != nil && maxSpent.Amount.Cmp(maxOrder) > 0 {
+			return RuleResult{Passed: false, Code: RejectOrderSize, Reason: fmt.Sprintf("Order size %s above maximum %s",
+				token.Format(maxSpent.Amount), token.Format(maxOrder))}
+		}
+		if maxOrder := pair.MaxOrder(token.Decimals); maxOrder != nil && maxSpent.Amount.Cmp(maxOrder) > 0 {
+			return RuleResult{Passed: false, Code: RejectOrderSize, Reason: fmt.Sprintf("Order size %s above the token pair maximum %s",
+				token.Format(maxSpent.Amount), token.Format(maxOrder))}
+		}
+	}
+	return RuleResult{Passed: true, Reason: "Order tokens are known"}
+}
+
+// MinFillRule rejects dust orders: orders whose fill notional (see fillNotional) is below MinNotional,
+// which would cost more in gas than they are worth
+type MinFillRule struct {
+	// Minimum fill notional in 18-decimal base units; nil means no minimum
+	MinNotional *big.Int
+}
+
+func (mr *MinFillRule) Name() string {
+	return "MinFillCheck"
+}
+
+func (mr *MinFillRule) Evaluate(_ context.Context, args *types.ParsedArgs) RuleResult {
+	if mr.MinNotional == nil || mr.MinNotional.Sign() == 0 {
+		return RuleResult{Passed: true, Reason: "No minimum fill configured"}
+	}
+
+	notional := fillNotional(args)
+	if notional.Cmp(mr.MinNotional) < 0 {
+		return RuleResult{Passed: false, Reason: fmt.Sprintf("Dust order: fill notional %s below minimum %s",
+			amount.New(notional, config.NotionalDecimals), amount.New(mr.MinNotional, config.NotionalDecimals))}
+	}
+	return RuleResult{Passed: true, Reason: fmt.Sprintf("Fill notional %s meets minimum", amount.New(notional, config.NotionalDecimals))}
+}
+
+// fillNotional returns the size of an order's fill: its MaxSpent amounts summed, scaled to 18
+// decimals when the token's decimals are known (registry or discovered on-chain) and in base
+// units otherwise. It is an amount of tokens, not a value; see CheckOrderFill for the value.
+func fillNotional(args *types.ParsedArgs) *big.Int {
+	var destinationChainID *big.Int
+	if len(args.ResolvedOrder.FillInstructions) > 0 {
+		destinationChainID = args.ResolvedOrder.FillInstructions[0].DestinationChainID
+	}
+	notional := new(big.Int)
+	for _, maxSpent := range args.ResolvedOrder.MaxSpent {
+		if maxSpent.Amount == nil {
+			continue
+		}
+		chainID := outputChainID(maxSpent, destinationChainID)
+		if scaled, ok := config.ScaleTokenAmount(chainID, maxSpent.Token, maxSpent.Amount, config.NotionalDecimals); ok {
+			notional.Add(notional, scaled)
+			continue
+		}
+		notional.Add(notional, maxSpent.Amount)
+	}
+	return notional
+}
+
+// outputChainID returns the chain of an output, or fallback when the order does not set it
+func outputChainID(output types.Output, fallback *big.Int) uint64 {
+	if output.ChainID != nil {
+		return output.ChainID.Uint64()
+	}
+	if fallback != nil {
+		return fallback.Uint64()
+	}
+	return 0
+}
+
 // BalanceRule validates that the solver has sufficient balance for the order
-type BalanceRule struct{}
+type BalanceRule struct {
+	// Client getters shared with the solver; nil dials the configured RPC
+	getEVMClient      func(chainID uint64) (EVMClient, error)
+	getStarknetClient func(chainID uint64) (rpc.RPCProvider, error)
+}
 
 func (br *BalanceRule) Name() string {
 	return "BalanceCheck"
@@ -89,16 +436,26 @@ func (br *BalanceRule) Evaluate(ctx context.Context, args *types.ParsedArgs) Rul
 		return RuleResult{Passed: true, Reason: "No tokens to spend"}
 	}
 
-	// Get destination chain ID for routing
-	destinationChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
+	// Each leg spends its outputs on its own destination chain
+	for i, instruction := range args.ResolvedOrder.FillInstructions {
+		leg := legArgs(args, i)
+		if len(leg.ResolvedOrder.MaxSpent) == 0 {
+			continue
+		}
 
-	// Switch based on destination chain type
-	switch {
-	case isStarknetChain(destinationChainID):
-		return br.checkStarknetBalance(ctx, args)
-	default:
-		return br.checkEVMBalance(ctx, args)
+		// Switch based on destination chain type
+		var result RuleResult
+		switch {
+		case isStarknetChain(instruction.DestinationChainID.Uint64()):
+			result = br.checkStarknetBalance(ctx, leg)
+		default:
+			result = br.checkEVMBalance(ctx, leg)
+		}
+		if !result.Passed {
+			return result
+		}
 	}
+	return RuleResult{Passed: true, Reason: "Balance check passed"}
 }
 
 func (br *BalanceRule) checkStarknetBalance(_ context.Context, args *types.ParsedArgs) RuleResult {
@@ -108,21 +465,16 @@ func (br *BalanceRule) checkStarknetBalance(_ context.Context, args *types.Parse
 		return RuleResult{Passed: false, Reason: "Starknet solver address not set"}
 	}
 
-	// Get Starknet RPC URL (conditional based on IS_DEVNET)
-	starknetRPC := envutil.GetStarknetRPCURL()
-	if starknetRPC == "" {
-		return RuleResult{Passed: false, Reason: "STARKNET_RPC_URL not set"}
-	}
-
-	provider, err := rpc.NewProvider(starknetRPC)
+	destinationChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
+	provider, err := br.starknetProvider(destinationChainID)
 	if err != nil {
-		return RuleResult{Passed: false, Reason: fmt.Sprintf("Failed to create Starknet provider: %v", err)}
+		return RuleResult{Passed: false, Reason: err.Error()}
 	}
 
 	// Check balance for each token in MaxSpent (what solver needs to provide on Starknet)
 	for _, maxSpent := range args.ResolvedOrder.MaxSpent {
-		// Skip native ETH (empty string)
-		if maxSpent.Token == "" || maxSpent.Token == "0x0" {
+		// Skip the zero address; Starknet's ETH and STRK are regular ERC20 contracts
+		if types.IsNativeToken(maxSpent.Token) {
 			continue
 		}
 
@@ -134,14 +486,15 @@ func (br *BalanceRule) checkStarknetBalance(_ context.Context, args *types.Parse
 
 		if balance.Cmp(maxSpent.Amount) < 0 {
 			return RuleResult{Passed: false, Reason: fmt.Sprintf("Insufficient balance for token %s: have %s, need %s",
-				maxSpent.Token, balance.String(), maxSpent.Amount.String())}
+				maxSpent.Token, config.FormatTokenAmount(destinationChainID, maxSpent.Token, balance),
+				config.FormatTokenAmount(destinationChainID, maxSpent.Token, maxSpent.Amount))}
 		}
 	}
 
 	return RuleResult{Passed: true, Reason: "Starknet balance check passed"}
 }
 
-func (br *BalanceRule) checkEVMBalance(_ context.Context, args *types.ParsedArgs) RuleResult {
+func (br *BalanceRule) checkEVMBalance(ctx context.Context, args *types.ParsedArgs) RuleResult {
 	// Get solver's EVM address from environment (conditional based on IS_DEVNET)
 	solverAddrHex := envutil.GetSolverPublicKey()
 	if solverAddrHex == "" {
@@ -164,43 +517,99 @@ func (br *BalanceRule) checkEVMBalance(_ context.Context, args *types.ParsedArgs
 		return RuleResult{Passed: false, Reason: fmt.Sprintf("No network config found for chain ID %d", destinationChainID)}
 	}
 
-	// Connect to destination chain RPC
-	client, err := ethclient.Dial(networkConfig.RPCURL)
+	client, release, err := br.evmClient(networkConfig)
 	if err != nil {
-		return RuleResult{Passed: false, Reason: fmt.Sprintf("Failed to connect to EVM RPC: %v", err)}
+		return RuleResult{Passed: false, Reason: err.Error()}
 	}
-	defer client.Close()
+	defer release()
 
 	// Check balance for each token in MaxSpent (what solver needs to provide on destination chain)
 	for _, maxSpent := range args.ResolvedOrder.MaxSpent {
-		// Skip native ETH (empty string)
-		if maxSpent.Token == "" {
-			continue
+		var balance *big.Int
+		if types.IsNativeToken(maxSpent.Token) {
+			// Native ETH is sent with the fill
+			balance, err = client.BalanceAt(ctx, solverAddr, nil)
+			if err != nil {
+				return RuleResult{Passed: false, Reason: fmt.Sprintf("Failed to check native balance: %v", err)}
+			}
+		} else {
+			// Convert token address using address_utils (assume valid input from order creation)
+			tokenAddr, err := types.ToEVMAddress(maxSpent.Token)
+			if err != nil {
+				return RuleResult{Passed: false, Reason: fmt.Sprintf("Failed to convert token address %s: %v", maxSpent.Token, err)}
+			}
+
+			// Use ethutil for balance check
+			balance, err = ethutil.ERC20Balance(client, tokenAddr, solverAddr)
+			if err != nil {
+				return RuleResult{Passed: false, Reason: fmt.Sprintf("Failed to check balance for token %s: %v", maxSpent.Token, err)}
+			}
 		}
 
-		// Convert token address using address_utils (assume valid input from order creation)
-		tokenAddr, err := types.ToEVMAddress(maxSpent.Token)
-		if err != nil {
-			return RuleResult{Passed: false, Reason: fmt.Sprintf("Failed to convert token address %s: %v", maxSpent.Token, err)}
+		if balance.Cmp(maxSpent.Amount) < 0 {
+			return RuleResult{Passed: false, Reason: fmt.Sprintf("Insufficient balance for token %s: have %s, need %s",
+				maxSpent.Token, config.FormatTokenAmount(destinationChainID, maxSpent.Token, balance),
+				config.FormatTokenAmount(destinationChainID, maxSpent.Token, maxSpent.Amount))}
 		}
+	}
 
-		// Use ethutil for balance check
-		balance, err := ethutil.ERC20Balance(client, tokenAddr, solverAddr)
+	return RuleResult{Passed: true, Reason: "EVM balance check passed"}
+}
+
+// starknetProvider returns the shared provider of a Starknet chain or dials its configured RPC
+func (br *BalanceRule) starknetProvider(chainID uint64) (rpc.RPCProvider, error) {
+	if br.getStarknetClient != nil {
+		provider, err := br.getStarknetClient(chainID)
 		if err != nil {
-			return RuleResult{Passed: false, Reason: fmt.Sprintf("Failed to check balance for token %s: %v", maxSpent.Token, err)}
+			return nil, fmt.Errorf("Failed to get Starknet client: %v", err)
 		}
+		return provider, nil
+	}
 
-		if balance.Cmp(maxSpent.Amount) < 0 {
-			return RuleResult{Passed: false, Reason: fmt.Sprintf("Insufficient balance for token %s: have %s, need %s",
-				maxSpent.Token, balance.String(), maxSpent.Amount.String())}
+	network, ok := networkForChainID(chainID)
+	if !ok || network.RPCURL == "" {
+		return nil, fmt.Errorf("no Starknet RPC URL configured for chain %d", chainID)
+	}
+
+	provider, err := rpc.NewProvider(network.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create Starknet provider: %v", err)
+	}
+	return rpctimeout.NewStarknetProvider(provider, network.Name), nil
+}
+
+// evmClient returns the shared client of the network or dials its RPC; release closes dialed clients
+func (br *BalanceRule) evmClient(networkConfig *config.NetworkConfig) (EVMClient, func(), error) {
+	if br.getEVMClient != nil {
+		client, err := br.getEVMClient(networkConfig.ChainID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to get EVM client: %v", err)
 		}
+		return client, func() {}, nil
 	}
 
-	return RuleResult{Passed: true, Reason: "EVM balance check passed"}
+	// Connect to destination chain RPC
+	client, err := ethclient.Dial(networkConfig.RPCURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to connect to EVM RPC: %v", err)
+	}
+	return rpctimeout.NewEVMClient(client, networkConfig.Name), client.Close, nil
 }
 
-// ProfitabilityRule validates that the order is profitable for the solver
-type ProfitabilityRule struct{}
+// ProfitabilityRule validates that the order is profitable for the solver. Orders spending and
+// receiving different tokens are valued at the feed's prices (see exchange_rate.go). The thresholds
+// of an order's token pair entry, if any, replace the ones below.
+type ProfitabilityRule struct {
+	// Minimum net profit in token base units; nil means 0
+	MinProfit *big.Int
+	// Prices of cross-token orders; nil uses pricefeed.Default()
+	Prices pricefeed.Feed
+	// Minimum margin and tolerated adverse price move of cross-token orders, in basis points
+	MinMarginBps   int
+	MaxSlippageBps int
+	// Pairs holds per token pair thresholds; nil means none
+	Pairs pairRulesLookup
+}
 
 func (pr *ProfitabilityRule) Name() string {
 	return "ProfitabilityCheck"
@@ -213,6 +622,9 @@ func (pr *ProfitabilityRule) Evaluate(ctx context.Context, args *types.ParsedArg
 	if len(args.ResolvedOrder.MaxSpent) == 0 || len(args.ResolvedOrder.MinReceived) == 0 {
 		return RuleResult{Passed: false, Reason: "Missing MaxSpent or MinReceived data"}
 	}
+	if pair, ok := orderPairRules(pr.Pairs, args); ok {
+		pr = pr.withPair(pair)
+	}
 
 	// Simple profitability check: ensure MaxSpent > MinReceived
 	// In a real implementation, this would be more sophisticated
@@ -221,7 +633,12 @@ func (pr *ProfitabilityRule) Evaluate(ctx context.Context, args *types.ParsedArg
 	// Get chain IDs for cross-chain logging
 	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
 	destChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
-	logutil.CrossChainOperation("Checking order profitability", originChainID, destChainID, args.OrderID)
+	rulesLog.CrossChainOperation("Checking order profitability", originChainID, destChainID, args.OrderID)
+
+	// Different tokens can't be compared in base units
+	if isCrossTokenOrder(args) {
+		return pr.evaluateCrossToken(ctx, args)
+	}
 
 	// Basic profitability check: ensure MinReceived > MaxSpent + expectedFees
 	// NOTE: This is a simplified check that assumes same token types and doesn't account for:
@@ -234,27 +651,26 @@ func (pr *ProfitabilityRule) Evaluate(ctx context.Context, args *types.ParsedArg
 	// - Using approved token allow lists with known price feeds
 	// - Implementing more sophisticated profit margin calculations
 	// - Accounting for actual gas costs and protocol fees
-	// - Adding minimum profit thresholds based on risk tolerance
 
 	// Expected fees and minimum profit threshold
 	// TODO: Calculate actual gas costs for fill + settle operations
 	// TODO: Add protocol fees (Hyperlane, etc.)
-	// TODO: Consider minimum profit threshold based on risk/reward
-	expectedFees := uint256.NewInt(0)       // Placeholder - should be calculated based on gas costs
-	minProfitThreshold := uint256.NewInt(0) // Placeholder - minimum profit to consider order worthwhile
+	expectedFees := uint256.NewInt(0) // Placeholder - should be calculated based on gas costs
+	minProfitThreshold, err := amount.ToUint256(pr.MinProfit)
+	if err != nil {
+		return RuleResult{Passed: false, Reason: fmt.Sprintf("Invalid minimum profit: %v", err)}
+	}
 
 	// Calculate total MaxSpent (what we're spending)
-	totalMaxSpent := uint256.NewInt(0)
-	for _, maxSpent := range args.ResolvedOrder.MaxSpent {
-		maxSpentU256, _ := uint256.FromBig(maxSpent.Amount)
-		totalMaxSpent.Add(totalMaxSpent, maxSpentU256)
+	totalMaxSpent, err := sumUint256(args.ResolvedOrder.MaxSpent)
+	if err != nil {
+		return RuleResult{Passed: false, Reason: fmt.Sprintf("Invalid MaxSpent: %v", err)}
 	}
 
 	// Calculate total MinReceived (what we expect to receive)
-	totalMinReceived := uint256.NewInt(0)
-	for _, minReceived := range args.ResolvedOrder.MinReceived {
-		minReceivedU256, _ := uint256.FromBig(minReceived.Amount)
-		totalMinReceived.Add(totalMinReceived, minReceivedU256)
+	totalMinReceived, err := sumUint256(args.ResolvedOrder.MinReceived)
+	if err != nil {
+		return RuleResult{Passed: false, Reason: fmt.Sprintf("Invalid MinReceived: %v", err)}
 	}
 
 	// Calculate total costs (MaxSpent + expected fees)
@@ -286,17 +702,91 @@ func (pr *ProfitabilityRule) Evaluate(ctx context.Context, args *types.ParsedArg
 	profitMargin := new(uint256.Int).Mul(grossProfit, uint256.NewInt(profitMarginMultiplier))
 	profitMargin.Div(profitMargin, totalMaxSpent)
 
-	logutil.CrossChainOperation(fmt.Sprintf("Profitability check passed: NetProfit=%s, GrossProfit=%s (%.2f%% margin)",
+	rulesLog.CrossChainOperation(fmt.Sprintf("Profitability check passed: NetProfit=%s, GrossProfit=%s (%.2f%% margin)",
 		netProfit.Dec(), grossProfit.Dec(), float64(profitMargin.Uint64())), originChainID, destChainID, args.OrderID)
 
 	return RuleResult{Passed: true, Reason: fmt.Sprintf("Order profitable: NetProfit=%s, GrossProfit=%s (%.2f%% margin)",
 		netProfit.Dec(), grossProfit.Dec(), float64(profitMargin.Uint64()))}
 }
 
+// withPair returns a copy of the rule with the thresholds a token pair entry sets
+func (pr *ProfitabilityRule) withPair(pair config.PairRules) *ProfitabilityRule {
+	rule := *pr
+	if minProfit := pair.MinProfitAmount(); minProfit != nil {
+		rule.MinProfit = minProfit
+	}
+	if pair.MinMarginBps != nil {
+		rule.MinMarginBps = *pair.MinMarginBps
+	}
+	if pair.MaxSlippageBps != nil {
+		rule.MaxSlippageBps = *pair.MaxSlippageBps
+	}
+	return &rule
+}
+
+// pairRulesLookup finds the token pair thresholds of an order (see config.PairRules)
+type pairRulesLookup interface {
+	PairRulesFor(inputToken, outputToken, origin, destination string) (config.PairRules, bool)
+}
+
+// orderPairRules returns the token pair entry of an order, by the registry symbols of its first
+// received and spent tokens and its origin and first destination networks
+func orderPairRules(pairs pairRulesLookup, args *types.ParsedArgs) (config.PairRules, bool) {
+	if pairs == nil || len(args.ResolvedOrder.FillInstructions) == 0 {
+		return config.PairRules{}, false
+	}
+	sides := orderSides(args)
+	symbol := func(side orderSide) string {
+		if len(side.outputs) == 0 {
+			return ""
+		}
+		token, _ := config.LookupToken(outputChainID(side.outputs[0], side.fallbackChain), side.outputs[0].Token)
+		return token.Symbol
+	}
+	origin, _ := networkForChainID(args.ResolvedOrder.OriginChainID.Uint64())
+	destination, _ := networkForChainID(args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64())
+	return pairs.PairRulesFor(symbol(sides[1]), symbol(sides[0]), origin.Name, destination.Name)
+}
+
+// sumUint256 adds up output amounts, failing on amounts or totals that don't fit uint256
+func sumUint256(outputs []types.Output) (*uint256.Int, error) {
+	total := uint256.NewInt(0)
+	for _, output := range outputs {
+		value, err := amount.ToUint256(output.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if _, overflow := total.AddOverflow(total, value); overflow {
+			return nil, amount.ErrUint256Overflow
+		}
+	}
+	return total, nil
+}
+
+// networkForChainID returns the configured network of a chain ID
+func networkForChainID(chainID uint64) (config.NetworkConfig, bool) {
+	for _, network := range config.Networks {
+		if network.ChainID == chainID {
+			return network, true
+		}
+	}
+	return config.NetworkConfig{}, false
+}
+
 // Helper function to determine if a chain ID is Starknet
 func isStarknetChain(chainID uint64) bool {
 	for _, network := range config.Networks {
-		if network.ChainID == chainID && network.Name == starknetNetworkName {
+		if network.ChainID == chainID && config.IsStarknetNetwork(network.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isStarknetDomain reports whether a Hyperlane domain belongs to one of the configured Starknet networks
+func isStarknetDomain(domain uint32) bool {
+	for _, network := range config.Networks {
+		if uint64(domain) == network.HyperlaneDomain && config.IsStarknetNetwork(network.Name) {
 			return true
 		}
 	}
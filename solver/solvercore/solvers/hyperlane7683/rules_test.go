@@ -2,11 +2,21 @@ package hyperlane7683
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
@@ -139,38 +149,294 @@ func TestBalanceRule(t *testing.T) {
 		assert.Equal(t, "No tokens to spend", result.Reason)
 	})
 
-	t.Run("Single token with sufficient balance", func(t *testing.T) {
-		rule := &BalanceRule{}
-		args := types.ParsedArgs{
-			OrderID: "0x1234567890123456789012345678901234567890123456789012345678901234",
-			ResolvedOrder: types.ResolvedCrossChainOrder{
-				OriginChainID: big.NewInt(1),
-				MaxSpent: []types.Output{
-					{
-						Token:     "0x1234567890123456789012345678901234567890",
-						Amount:    big.NewInt(1000),
-						Recipient: "0x0987654321098765432109876543210987654321",
-						ChainID:   big.NewInt(84532),
-					},
+	t.Run("EVM destination balance", func(t *testing.T) {
+		solver := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+		t.Setenv("SOLVER_PUB_KEY", solver.Hex())
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := common.HexToAddress("0x1234567890123456789012345678901234567890")
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), func(msg ethereum.CallMsg) ([]byte, error) {
+			return common.LeftPadBytes(big.NewInt(1000).Bytes(), 32), nil
+		})
+		rule := &BalanceRule{getEVMClient: func(chainID uint64) (EVMClient, error) {
+			assert.Equal(t, uint64(config.BaseSepoliaChainID), chainID)
+			return backend, nil
+		}}
+
+		args := balanceRuleArgs(token.Hex(), 1000, config.BaseSepoliaChainID)
+		result := rule.Evaluate(context.Background(), &args)
+		assert.True(t, result.Passed, result.Reason)
+
+		args = balanceRuleArgs(token.Hex(), 1001, config.BaseSepoliaChainID)
+		result = rule.Evaluate(context.Background(), &args)
+		assert.False(t, result.Passed)
+		assert.Contains(t, result.Reason, "Insufficient balance")
+
+		backend.FailNext("CallContract", errors.New("rpc down"))
+		result = rule.Evaluate(context.Background(), &args)
+		assert.False(t, result.Passed)
+		assert.Contains(t, result.Reason, "Failed to check balance")
+	})
+
+	t.Run("Starknet destination balance", func(t *testing.T) {
+		solver := "0x0000000000000000000000000000000000000000000000000000000000000abc"
+		t.Setenv("STARKNET_SOLVER_ADDRESS", solver)
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := "0x0000000000000000000000000000000000000000000000000000000000001234"
+		tokenFelt, err := utils.HexToFelt(token)
+		require.NoError(t, err)
+		backend := chainmock.NewStarknetBackend()
+		backend.HandleCall(tokenFelt, "balanceOf", func(call rpc.FunctionCall) ([]*felt.Felt, error) {
+			return []*felt.Felt{new(felt.Felt).SetUint64(500), new(felt.Felt)}, nil
+		})
+		rule := &BalanceRule{getStarknetClient: func(uint64) (rpc.RPCProvider, error) { return backend, nil }}
+
+		args := balanceRuleArgs(token, 500, config.StarknetSepoliaChainID)
+		result := rule.Evaluate(context.Background(), &args)
+		assert.True(t, result.Passed, result.Reason)
+
+		args = balanceRuleArgs(token, 501, config.StarknetSepoliaChainID)
+		result = rule.Evaluate(context.Background(), &args)
+		assert.False(t, result.Passed)
+		assert.Contains(t, result.Reason, "Insufficient balance")
+	})
+
+	t.Run("Client getter error", func(t *testing.T) {
+		t.Setenv("SOLVER_PUB_KEY", "0x00000000000000000000000000000000000000aa")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		rule := &BalanceRule{getEVMClient: func(uint64) (EVMClient, error) { return nil, errors.New("no client") }}
+		args := balanceRuleArgs("0x1234567890123456789012345678901234567890", 1, config.BaseSepoliaChainID)
+		result := rule.Evaluate(context.Background(), &args)
+		assert.False(t, result.Passed)
+		assert.Contains(t, result.Reason, "no client")
+	})
+}
+
+// balanceRuleArgs builds an order spending amount of token on the destination chain
+func balanceRuleArgs(token string, amount int64, destinationChainID uint64) types.ParsedArgs {
+	return types.ParsedArgs{
+		OrderID: "0x1234567890123456789012345678901234567890123456789012345678901234",
+		ResolvedOrder: types.ResolvedCrossChainOrder{
+			OriginChainID: big.NewInt(1),
+			MaxSpent: []types.Output{
+				{
+					Token:     token,
+					Amount:    big.NewInt(amount),
+					Recipient: "0x0987654321098765432109876543210987654321",
+					ChainID:   new(big.Int).SetUint64(destinationChainID),
 				},
-				MinReceived: []types.Output{}, // Empty list should pass
-				FillInstructions: []types.FillInstruction{
-					{
-						DestinationChainID: big.NewInt(84532), // Base Sepolia
-					},
+			},
+			FillInstructions: []types.FillInstruction{
+				{
+					DestinationChainID: new(big.Int).SetUint64(destinationChainID),
 				},
 			},
-		}
+		},
+	}
+}
 
-		// This would normally check actual balances, but for unit tests
-		// we'll test the structure and basic logic
-		result := rule.Evaluate(context.Background(), &args)
-		// The actual result depends on the implementation
-		// For now, we just ensure it doesn't panic
-		assert.NotNil(t, result)
+func TestSettlerRule(t *testing.T) {
+	const starknetSettler = "0x0000000000000000000000000000000000000000000000000000000000005678"
+	t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+	t.Setenv("STARKNET_HYPERLANE_ADDRESS", starknetSettler)
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+
+	evaluate := func(rule *SettlerRule, settler string, destinationChainID uint64) RuleResult {
+		args := types.ParsedArgs{ResolvedOrder: types.ResolvedCrossChainOrder{
+			FillInstructions: []types.FillInstruction{{
+				DestinationChainID: new(big.Int).SetUint64(destinationChainID),
+				DestinationSettler: settler,
+			}},
+		}}
+		return rule.Evaluate(context.Background(), &args)
+	}
+
+	t.Run("Rule name", func(t *testing.T) {
+		assert.Equal(t, "SettlerCheck", (&SettlerRule{}).Name())
+	})
+
+	t.Run("Configured Hyperlane7683 address", func(t *testing.T) {
+		rule := &SettlerRule{}
+		assert.True(t, evaluate(rule, "0x00000000000000000000000000000000000000B2", config.BaseSepoliaChainID).Passed)
+		assert.True(t, evaluate(rule, "0x00000000000000000000000000000000000000000000000000000000000000b2", config.BaseSepoliaChainID).Passed,
+			"bytes32 settlers from EVM events")
+		assert.True(t, evaluate(rule, "0x5678", config.StarknetSepoliaChainID).Passed)
+	})
+
+	t.Run("Unknown settler is rejected", func(t *testing.T) {
+		result := evaluate(&SettlerRule{}, "0x00000000000000000000000000000000000000c3", config.BaseSepoliaChainID)
+		assert.False(t, result.Passed)
+		assert.Contains(t, result.Reason, "not a known Hyperlane7683 contract on Base")
+
+		result = evaluate(&SettlerRule{}, "0x00000000000000000000000000000000000000b2", config.StarknetSepoliaChainID)
+		assert.False(t, result.Passed, "the EVM address is not trusted on Starknet")
+	})
+
+	t.Run("Trusted settlers are accepted on their network only", func(t *testing.T) {
+		rule := &SettlerRule{TrustedSettlers: map[string][]string{"Base": {"0x00000000000000000000000000000000000000c3"}}}
+		assert.True(t, evaluate(rule, "0x00000000000000000000000000000000000000c3", config.BaseSepoliaChainID).Passed)
+		assert.False(t, evaluate(rule, "0x00000000000000000000000000000000000000c3", config.OptimismSepoliaChainID).Passed)
+	})
+
+	t.Run("Invalid settler or chain", func(t *testing.T) {
+		result := evaluate(&SettlerRule{}, "0x1234", config.BaseSepoliaChainID)
+		assert.False(t, result.Passed)
+		assert.Contains(t, result.Reason, "Invalid destination settler")
+
+		result = evaluate(&SettlerRule{}, "0x00000000000000000000000000000000000000b2", 999999)
+		assert.False(t, result.Passed)
+		assert.Contains(t, result.Reason, "Unknown destination chain")
+	})
+
+	t.Run("Trusted settlers are validated per chain type", func(t *testing.T) {
+		valid := &config.RuntimeConfig{Rules: config.RulesConfig{TrustedSettlers: map[string][]string{
+			"Base": {"0x00000000000000000000000000000000000000c3"}, "Starknet": {starknetSettler},
+		}}}
+		assert.NoError(t, ValidateRuleConfig(valid))
+
+		invalid := &config.RuntimeConfig{Rules: config.RulesConfig{TrustedSettlers: map[string][]string{"Base": {"0xc3"}}}}
+		assert.ErrorContains(t, ValidateRuleConfig(invalid), "rules.trustedSettlers.Base[0]")
 	})
 }
 
+func TestTokenRule(t *testing.T) {
+	const dogAddress = "0x00000000000000000000000000000000000000a1"
+	registry, err := config.NewTokenRegistry([]config.TokenConfig{
+		{ChainID: config.BaseSepoliaChainID, Address: dogAddress, Symbol: "DOG", Decimals: 6, MinOrderSize: "1", MaxOrderSize: "100"},
+		{ChainID: config.EthereumSepoliaChainID, Address: dogAddress, Symbol: "DOG", Decimals: 6},
+	})
+	require.NoError(t, err)
+
+	orderArgs := func(spent int64, spentToken, receivedToken string) types.ParsedArgs {
+		return types.ParsedArgs{ResolvedOrder: types.ResolvedCrossChainOrder{
+			OriginChainID:    big.NewInt(config.EthereumSepoliaChainID),
+			MaxSpent:         []types.Output{{Token: spentToken, Amount: big.NewInt(spent), ChainID: big.NewInt(config.BaseSepoliaChainID)}},
+			MinReceived:      []types.Output{{Token: receivedToken, Amount: big.NewInt(spent)}},
+			FillInstructions: []types.FillInstruction{{DestinationChainID: big.NewInt(config.BaseSepoliaChainID)}},
+		}}
+	}
+	evaluate := func(rule *TokenRule, args types.ParsedArgs) RuleResult {
+		return rule.Evaluate(context.Background(), &args)
+	}
+
+	t.Run("Rule name", func(t *testing.T) {
+		assert.Equal(t, "TokenCheck", (&TokenRule{}).Name())
+	})
+
+	t.Run("No registry accepts every token", func(t *testing.T) {
+		empty, err := config.NewTokenRegistry(nil)
+		require.NoError(t, err)
+		assert.True(t, evaluate(&TokenRule{Registry: empty}, orderArgs(1, "0xbad", "0xbad")).Passed)
+	})
+
+	t.Run("Known tokens within bounds", func(t *testing.T) {
+		result := evaluate(&TokenRule{Registry: registry}, orderArgs(12_500_000, dogAddress, dogAddress))
+		assert.True(t, result.Passed, result.Reason)
+	})
+
+	t.Run("Unknown tokens are rejected by default", func(t *testing.T) {
+		result := evaluate(&TokenRule{Registry: registry}, orderArgs(12_500_000, "0x00000000000000000000000000000000000000c3", dogAddress))
+		assert.False(t, result.Passed)
+		assert.Contains(t, result.Reason, "Unknown token 0x00000000000000000000000000000000000000c3 on chain 84532")
+
+		result = evaluate(&TokenRule{Registry: registry}, orderArgs(12_500_000, dogAddress, "0x00000000000000000000000000000000000000c3"))
+		assert.False(t, result.Passed)
+		assert.Contains(t, result.Reason, "on chain 11155111", "received tokens are looked up on the origin chain")
+
+		result = evaluate(&TokenRule{Registry: registry, AllowUnknown: true}, orderArgs(12_500_000, "0xc3", "0xc3"))
+		assert.True(t, result.Passed)
+	})
+
+	t.Run("Order size bounds", func(t *testing.T) {
+		result := evaluate(&TokenRule{Registry: registry}, orderArgs(500_000, dogAddress, dogAddress))
+		assert.False(t, result.Passed)
+		assert.Equal(t, "Order size 0.5 DOG below minimum 1 DOG", result.Reason)
+
+		result = evaluate(&TokenRule{Registry: registry}, orderArgs(100_000_001, dogAddress, dogAddress))
+		assert.False(t, result.Passed)
+		assert.Equal(t, "Order size 100.000001 DOG above maximum 100 DOG", result.Reason)
+		assert.Equal(t, RejectOrderSize, result.Code)
+	})
+}
+
+func TestDeadlineRule(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	rule := &DeadlineRule{MinWindow: 2 * time.Minute, now: func() time.Time { return now }}
+	evaluate := func(rule *DeadlineRule, fillDeadline time.Time) RuleResult {
+		args := types.ParsedArgs{ResolvedOrder: types.ResolvedCrossChainOrder{FillDeadline: uint32(fillDeadline.Unix())}}
+		return rule.Evaluate(context.Background(), &args)
+	}
+
+	t.Run("Rule name", func(t *testing.T) {
+		assert.Equal(t, "DeadlineCheck", rule.Name())
+	})
+
+	t.Run("No deadline", func(t *testing.T) {
+		assert.True(t, evaluate(rule, time.Unix(0, 0)).Passed)
+	})
+
+	t.Run("Deadline inside the fill window is rejected", func(t *testing.T) {
+		result := evaluate(rule, now.Add(90*time.Second))
+		assert.False(t, result.Passed)
+		assert.Equal(t, "Fill deadline 2023-11-14T22:14:50Z too close: 1m30s left, need 2m0s", result.Reason)
+
+		assert.True(t, evaluate(rule, now.Add(2*time.Minute)).Passed)
+	})
+
+	t.Run("Passed deadline is rejected without a window", func(t *testing.T) {
+		noWindow := &DeadlineRule{now: rule.now}
+		assert.False(t, evaluate(noWindow, now).Passed)
+		assert.True(t, evaluate(noWindow, now.Add(time.Second)).Passed)
+	})
+}
+
+func TestRejectReasonCodes(t *testing.T) {
+	args := types.ParsedArgs{OrderID: "0x1234567890abcdef", ResolvedOrder: types.ResolvedCrossChainOrder{
+		OriginChainID:    big.NewInt(1),
+		FillInstructions: []types.FillInstruction{{DestinationChainID: big.NewInt(84532)}},
+	}}
+
+	t.Run("default rules map to their reason", func(t *testing.T) {
+		engine := &RulesEngine{rules: []Rule{&ProfitabilityRule{}}}
+		result := engine.EvaluateAll(context.Background(), &args)
+		assert.False(t, result.Passed)
+		assert.Equal(t, RejectBelowMargin, result.Code)
+	})
+
+	t.Run("codes set by the rule are kept", func(t *testing.T) {
+		engine := &RulesEngine{rules: []Rule{&DeadlineRule{}, &codedRule{code: "custom_code"}}}
+		result := engine.EvaluateAll(context.Background(), &args)
+		assert.Equal(t, "custom_code", result.Code)
+	})
+
+	t.Run("custom rules default to their name", func(t *testing.T) {
+		engine := &RulesEngine{rules: []Rule{&MockRule{name: "VenueCheck"}}}
+		assert.Equal(t, "VenueCheck", engine.EvaluateAll(context.Background(), &args).Code)
+	})
+}
+
+// codedRule fails with its own reason code
+type codedRule struct {
+	code string
+}
+
+func (c *codedRule) Name() string { return "Coded" }
+
+func (c *codedRule) Evaluate(context.Context, *types.ParsedArgs) RuleResult {
+	return RuleResult{Passed: false, Reason: "coded failure", Code: c.code}
+}
+
 func TestProfitabilityRule(t *testing.T) {
 	t.Run("Rule name", func(t *testing.T) {
 		rule := &ProfitabilityRule{}
@@ -229,6 +495,257 @@ func TestProfitabilityRule(t *testing.T) {
 		// For now, we just ensure it doesn't panic
 		assert.NotNil(t, result)
 	})
+
+	t.Run("Profit below minimum", func(t *testing.T) {
+		args := types.ParsedArgs{
+			OrderID: "0x1234567890123456789012345678901234567890123456789012345678901234",
+			ResolvedOrder: types.ResolvedCrossChainOrder{
+				OriginChainID: big.NewInt(1),
+				MaxSpent:      []types.Output{{Amount: big.NewInt(1000)}},
+				MinReceived:   []types.Output{{Amount: big.NewInt(1100)}},
+				FillInstructions: []types.FillInstruction{
+					{DestinationChainID: big.NewInt(84532)},
+				},
+			},
+		}
+
+		result := (&ProfitabilityRule{MinProfit: big.NewInt(100)}).Evaluate(context.Background(), &args)
+		assert.True(t, result.Passed)
+
+		result = (&ProfitabilityRule{MinProfit: big.NewInt(101)}).Evaluate(context.Background(), &args)
+		assert.False(t, result.Passed)
+		assert.Contains(t, result.Reason, "below threshold")
+	})
+
+	t.Run("Amounts overflowing uint256 are rejected", func(t *testing.T) {
+		maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+		args := types.ParsedArgs{
+			OrderID: "0x1234567890123456789012345678901234567890123456789012345678901234",
+			ResolvedOrder: types.ResolvedCrossChainOrder{
+				OriginChainID: big.NewInt(1),
+				MaxSpent:      []types.Output{{Amount: big.NewInt(1000)}},
+				MinReceived:   []types.Output{{Amount: maxUint256}, {Amount: big.NewInt(1)}},
+				FillInstructions: []types.FillInstruction{
+					{DestinationChainID: big.NewInt(84532)},
+				},
+			},
+		}
+
+		result := (&ProfitabilityRule{}).Evaluate(context.Background(), &args)
+		assert.False(t, result.Passed)
+		assert.Contains(t, result.Reason, "Invalid MinReceived")
+	})
+}
+
+func TestMinFillRule(t *testing.T) {
+	config.InitializeNetworks()
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+	orderArgs := func(spent ...*big.Int) types.ParsedArgs {
+		args := types.ParsedArgs{ResolvedOrder: types.ResolvedCrossChainOrder{
+			OriginChainID:    big.NewInt(config.EthereumSepoliaChainID),
+			FillInstructions: []types.FillInstruction{{DestinationChainID: big.NewInt(config.BaseSepoliaChainID)}},
+		}}
+		for _, amount := range spent {
+			args.ResolvedOrder.MaxSpent = append(args.ResolvedOrder.MaxSpent, types.Output{Token: "0xa1", Amount: amount})
+		}
+		return args
+	}
+	evaluate := func(rule *MinFillRule, args types.ParsedArgs) RuleResult {
+		return rule.Evaluate(context.Background(), &args)
+	}
+	minimum := big.NewInt(1e16) // 0.01 tokens at 18 decimals
+
+	t.Run("Rule name", func(t *testing.T) {
+		assert.Equal(t, "MinFillCheck", (&MinFillRule{}).Name())
+	})
+
+	t.Run("No minimum accepts dust", func(t *testing.T) {
+		assert.True(t, evaluate(&MinFillRule{}, orderArgs(big.NewInt(1))).Passed)
+		assert.True(t, evaluate(&MinFillRule{MinNotional: new(big.Int)}, orderArgs(big.NewInt(1))).Passed)
+	})
+
+	t.Run("Dust orders are rejected with the amounts", func(t *testing.T) {
+		result := evaluate(&MinFillRule{MinNotional: minimum}, orderArgs(big.NewInt(1000)))
+		assert.False(t, result.Passed)
+		assert.Equal(t, "Dust order: fill notional 0.000000000000001 below minimum 0.01", result.Reason)
+	})
+
+	t.Run("Orders at the minimum pass; outputs are summed", func(t *testing.T) {
+		assert.True(t, evaluate(&MinFillRule{MinNotional: minimum}, orderArgs(minimum)).Passed)
+		assert.True(t, evaluate(&MinFillRule{MinNotional: minimum}, orderArgs(big.NewInt(6e15), big.NewInt(4e15))).Passed)
+		assert.False(t, evaluate(&MinFillRule{MinNotional: minimum}, orderArgs(big.NewInt(6e15), big.NewInt(3e15))).Passed)
+	})
+
+	t.Run("Amounts are scaled by the registry decimals", func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+			{ChainID: config.BaseSepoliaChainID, Address: "0xa1", Symbol: "USDC", Decimals: 6},
+		}}))
+		// 0.02 USDC is 20000 base units, far below 1e16 unscaled
+		assert.True(t, evaluate(&MinFillRule{MinNotional: minimum}, orderArgs(big.NewInt(20_000))).Passed)
+		assert.False(t, evaluate(&MinFillRule{MinNotional: minimum}, orderArgs(big.NewInt(5_000))).Passed)
+	})
+}
+
+func TestRulesEngineRuntimeConfig(t *testing.T) {
+	config.InitializeNetworks()
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+
+	names := func(engine *RulesEngine) []string {
+		var out []string
+		for _, rule := range engine.rules {
+			out = append(out, rule.Name())
+		}
+		return out
+	}
+
+	t.Run("all rules run by default", func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{}))
+		assert.Equal(t, []string{"RouteCheck", "DeadlineCheck", "SettlerCheck", "TokenCheck", "DecimalsCheck", "MinFillCheck", "BalanceCheck", "ProfitabilityCheck"}, names(NewRulesEngine()))
+	})
+
+	t.Run("disabled rules are skipped and min profit applied", func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{
+			Rules: config.RulesConfig{Disabled: []string{"RouteCheck", "DeadlineCheck", "SettlerCheck", "TokenCheck", "DecimalsCheck", "MinFillCheck", "BalanceCheck"}, MinProfit: "42"},
+		}))
+		engine := NewRulesEngine()
+		require.Equal(t, []string{"ProfitabilityCheck"}, names(engine))
+		assert.Equal(t, big.NewInt(42), engine.rules[0].(*ProfitabilityRule).MinProfit)
+	})
+
+	t.Run("min fill notional applied", func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Rules: config.RulesConfig{MinFillNotional: "0.5"}}))
+		for _, rule := range NewRulesEngine().rules {
+			if minFill, ok := rule.(*MinFillRule); ok {
+				assert.Equal(t, big.NewInt(5e17), minFill.MinNotional)
+				return
+			}
+		}
+		t.Fatal("MinFillCheck not in the default rules")
+	})
+
+	t.Run("unknown rule names are rejected", func(t *testing.T) {
+		err := config.ApplyRuntime(&config.RuntimeConfig{Rules: config.RulesConfig{Disabled: []string{"BalanceChek"}}}, ValidateRuleConfig)
+		assert.ErrorContains(t, err, `unknown rule "BalanceChek"`)
+		assert.NoError(t, ValidateRuleConfig(&config.RuntimeConfig{Rules: config.RulesConfig{Disabled: []string{"ProfitabilityCheck"}}}))
+	})
+}
+
+// TestPairRuleThresholds tests that token pair entries override the global thresholds
+func TestPairRuleThresholds(t *testing.T) {
+	config.InitializeNetworks()
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+	margin := 300
+	require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{
+		Tokens: []config.TokenConfig{
+			{ChainID: config.BaseSepoliaChainID, Address: "0xc0", Symbol: "USDC", Decimals: 6, MaxOrderSize: "1000"},
+			{ChainID: config.EthereumSepoliaChainID, Address: "0xc1", Symbol: "USDC", Decimals: 6},
+			{ChainID: config.BaseSepoliaChainID, Address: "0xd0", Symbol: "DOG", Decimals: 18},
+		},
+		Rules: config.RulesConfig{MinProfit: "1", Pairs: []config.PairRules{
+			{InputToken: "USDC", OutputToken: "USDC", Destination: "Base", MinProfit: "50000", MinMarginBps: &margin, MaxOrderSize: "100"},
+		}},
+	}))
+
+	// Spends USDC on Base Sepolia for USDC received on Ethereum Sepolia
+	orderArgs := func(spentToken string, spent, received int64) *types.ParsedArgs {
+		return &types.ParsedArgs{
+			OrderID: "0x5555555555555555555555555555555555555555555555555555555555555555",
+			ResolvedOrder: types.ResolvedCrossChainOrder{
+				OriginChainID:    big.NewInt(config.EthereumSepoliaChainID),
+				MaxSpent:         []types.Output{{Token: spentToken, Amount: big.NewInt(spent)}},
+				MinReceived:      []types.Output{{Token: "0xc1", Amount: big.NewInt(received)}},
+				FillInstructions: []types.FillInstruction{{DestinationChainID: big.NewInt(config.BaseSepoliaChainID)}},
+			},
+		}
+	}
+	var tokenRule *TokenRule
+	var profitRule *ProfitabilityRule
+	for _, rule := range NewRulesEngine().rules {
+		switch rule := rule.(type) {
+		case *TokenRule:
+			tokenRule = rule
+		case *ProfitabilityRule:
+			profitRule = rule
+		}
+	}
+	require.NotNil(t, tokenRule)
+	require.NotNil(t, profitRule)
+
+	t.Run("pair max order size", func(t *testing.T) {
+		result := tokenRule.Evaluate(context.Background(), orderArgs("0xc0", 150_000_000, 151_000_000))
+		assert.False(t, result.Passed)
+		assert.Equal(t, RejectOrderSize, result.Code)
+		assert.Equal(t, "Order size 150 USDC above the token pair maximum 100 USDC", result.Reason)
+		assert.True(t, tokenRule.Evaluate(context.Background(), orderArgs("0xc0", 90_000_000, 91_000_000)).Passed)
+	})
+
+	t.Run("pair min profit replaces the global one", func(t *testing.T) {
+		assert.False(t, profitRule.Evaluate(context.Background(), orderArgs("0xc0", 90_000_000, 90_000_010)).Passed)
+		assert.True(t, profitRule.Evaluate(context.Background(), orderArgs("0xc0", 90_000_000, 90_050_000)).Passed)
+		assert.Equal(t, big.NewInt(1), profitRule.MinProfit, "the rule itself is unchanged")
+	})
+
+	t.Run("other pairs keep the global thresholds", func(t *testing.T) {
+		args := orderArgs("0xd0", 150, 151)
+		assert.True(t, tokenRule.Evaluate(context.Background(), args).Passed)
+		pair, ok := orderPairRules(profitRule.Pairs, args)
+		assert.False(t, ok, "matched %+v", pair)
+	})
+}
+
+// TestRouteRule tests that only the origin→destination pairs of the routing table are served
+func TestRouteRule(t *testing.T) {
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+
+	order := func(origin uint64, destinations ...uint64) *types.ParsedArgs {
+		args := &types.ParsedArgs{OrderID: "0x5555555555555555555555555555555555555555555555555555555555555555", ResolvedOrder: types.ResolvedCrossChainOrder{OriginChainID: new(big.Int).SetUint64(origin)}}
+		for _, destination := range destinations {
+			args.ResolvedOrder.FillInstructions = append(args.ResolvedOrder.FillInstructions,
+				types.FillInstruction{DestinationChainID: new(big.Int).SetUint64(destination)})
+		}
+		return args
+	}
+	rule := func(routes config.RoutesConfig) *RouteRule {
+		return &RouteRule{Routes: &config.RuntimeConfig{Routes: routes}}
+	}
+
+	t.Run("every pair is served without a routing table", func(t *testing.T) {
+		result := rule(nil).Evaluate(context.Background(), order(config.BaseSepoliaChainID, config.StarknetSepoliaChainID))
+		assert.True(t, result.Passed)
+	})
+
+	t.Run("only enabled pairs are served", func(t *testing.T) {
+		routes := rule(config.RoutesConfig{"Base": {"Starknet": true}, "Starknet": {"Arbitrum": false}})
+		assert.True(t, routes.Evaluate(context.Background(), order(config.BaseSepoliaChainID, config.StarknetSepoliaChainID)).Passed)
+
+		result := routes.Evaluate(context.Background(), order(config.StarknetSepoliaChainID, config.ArbitrumSepoliaChainID))
+		assert.False(t, result.Passed)
+		assert.Equal(t, "Route Starknet → Arbitrum is not enabled in the routing table", result.Reason)
+
+		// Pairs missing from the table are not served
+		assert.False(t, routes.Evaluate(context.Background(), order(config.BaseSepoliaChainID, config.ArbitrumSepoliaChainID)).Passed)
+	})
+
+	t.Run("every leg of the order needs an enabled route", func(t *testing.T) {
+		routes := rule(config.RoutesConfig{"Base": {"Starknet": true}})
+		result := routes.Evaluate(context.Background(), order(config.BaseSepoliaChainID, config.StarknetSepoliaChainID, config.OptimismSepoliaChainID))
+		assert.False(t, result.Passed)
+		assert.Contains(t, result.Reason, "Base → Optimism")
+	})
+
+	t.Run("unknown chains are rejected", func(t *testing.T) {
+		result := rule(nil).Evaluate(context.Background(), order(1, config.BaseSepoliaChainID))
+		assert.False(t, result.Passed)
+		assert.Equal(t, "Unknown origin chain 1", result.Reason)
+	})
+
+	t.Run("rejections use the route_disabled code", func(t *testing.T) {
+		engine := &RulesEngine{rules: []Rule{rule(config.RoutesConfig{"*": {"*": false}})}}
+		result := engine.EvaluateAll(context.Background(), order(config.BaseSepoliaChainID, config.StarknetSepoliaChainID))
+		assert.Equal(t, RejectRouteDisabled, result.Code)
+	})
 }
 
 func TestRuleResult(t *testing.T) {
@@ -269,3 +786,25 @@ func (m *MockRule) Evaluate(ctx context.Context, args *types.ParsedArgs) RuleRes
 	}
 	return RuleResult{Passed: false, Reason: "Mock rule failed"}
 }
+
+func TestFillNotional(t *testing.T) {
+	args := &types.ParsedArgs{ResolvedOrder: types.ResolvedCrossChainOrder{
+		MaxSpent: []types.Output{{Amount: big.NewInt(100)}, {Amount: big.NewInt(23)}, {Amount: nil}},
+	}}
+	assert.Equal(t, big.NewInt(123), fillNotional(args))
+	assert.Equal(t, big.NewInt(0), fillNotional(&types.ParsedArgs{}))
+
+	t.Run("amounts are scaled to 18 decimals", func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+			{ChainID: 84532, Address: "0xa1", Symbol: "DOG", Decimals: 6},
+		}}))
+		defer config.ApplyRuntime(&config.RuntimeConfig{})
+
+		args := &types.ParsedArgs{ResolvedOrder: types.ResolvedCrossChainOrder{
+			MaxSpent:         []types.Output{{Token: "0xa1", Amount: big.NewInt(2_000_000)}, {Token: "0xb2", Amount: big.NewInt(5)}},
+			FillInstructions: []types.FillInstruction{{DestinationChainID: big.NewInt(84532)}},
+		}}
+		expected, _ := new(big.Int).SetString("2000000000000000005", 10)
+		assert.Equal(t, expected, fillNotional(args))
+	})
+}
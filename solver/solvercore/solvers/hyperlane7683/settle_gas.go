@@ -0,0 +1,141 @@
+package hyperlane7683
+
+// Module: Settlement gas vs order margin
+// - Before the settlement worker settles an order, it quotes the Hyperlane gas payment of every leg
+//   and compares the total with the order's margin (MinReceived − MaxSpent); a payment that would use
+//   up the margin defers the settlement, as does a quote above SETTLE_MAX_GAS_PAYMENT_ETH
+// - Starknet fills normally settle in the same multicall; a settle that would be deferred is split off
+//   the fill and left to the settlement worker instead (see HyperlaneStarknet.splitSettle)
+// - Deferred orders are checked again every SETTLE_DEFER_INTERVAL_SECONDS without using up settlement
+//   attempts, waiting for the gas payment to drop; SETTLE_MAX_DEFER_SECONDS after an order was first
+//   due it is settled regardless, since an order that is never settled never pays the solver
+// - Gas payments and margins are valued through the price feed, so the gas tokens (ETH on Starknet,
+//   the native token registered at pricefeed.NativeToken on EVM chains) and the order's tokens need
+//   prices; orders with a token without one are settled without the check
+//
+// Settings:
+// - SETTLE_MAX_DEFER_SECONDS: longest a settlement is deferred for its gas payment (default 3600; 0 never
+//   defers, and always bundles Starknet settles with their fill)
+// - SETTLE_DEFER_INTERVAL_SECONDS: time between gas checks of a deferred settlement (default 60)
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/pricefeed"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const (
+	defaultSettleMaxDeferSeconds      = 3600
+	defaultSettleDeferIntervalSeconds = 60
+)
+
+// ErrSettlementDeferred is returned when settling now would cost more than the order is worth
+var ErrSettlementDeferred = errors.New("settlement deferred")
+
+// settleMaxDeferFromEnv reads how long a settlement may be deferred for its gas payment
+func settleMaxDeferFromEnv() time.Duration {
+	return time.Duration(envutil.GetEnvInt("SETTLE_MAX_DEFER_SECONDS", defaultSettleMaxDeferSeconds)) * time.Second
+}
+
+// settlementGas is an amount of a gas token paid on a chain, e.g. the quoted Hyperlane gas payment of
+// settling one leg of an order
+type settlementGas struct {
+	chainID uint64
+	token   string // token the payment is made in
+	amount  *big.Int
+}
+
+// settlementQuoter is implemented by chain handlers that can quote a settlement without sending it
+type settlementQuoter interface {
+	quoteSettlement(ctx context.Context, args *types.ParsedArgs) (settlementGas, error)
+}
+
+// CheckSettlementGas quotes the settlement of every leg of an order and returns ErrSettlementDeferred
+// when the gas payments would use up the order's margin or exceed the gas payment cap
+func (f *Hyperlane7683Solver) CheckSettlementGas(ctx context.Context, args *types.ParsedArgs) error {
+	quotes, err := f.settlementGasQuotes(ctx, args)
+	if errors.Is(err, ErrGasPaymentAboveCap) {
+		return fmt.Errorf("%w: %v", ErrSettlementDeferred, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = checkSettlementMargin(ctx, pricefeed.Default(), args, quotes)
+	if errors.Is(err, pricefeed.ErrNoPrice) {
+		fillerLog.Printf("⚠️  Cannot compare the settlement gas of order %s with its margin, settling: %v\n", args.OrderID, err)
+		return nil
+	}
+	return err
+}
+
+// settlementGasQuotes quotes the gas payment of every leg of an order that settles through Hyperlane
+func (f *Hyperlane7683Solver) settlementGasQuotes(ctx context.Context, args *types.ParsedArgs) ([]settlementGas, error) {
+	var quotes []settlementGas
+	for i, instruction := range args.ResolvedOrder.FillInstructions {
+		leg := legArgs(args, i)
+		handler, _, err := f.handlerForChain(instruction.DestinationChainID)
+		if err != nil {
+			return nil, err
+		}
+		// Handlers that can't quote ahead of time are settled without a check
+		quoter, ok := handler.(settlementQuoter)
+		if !ok {
+			continue
+		}
+		quote, err := quoter.quoteSettlement(ctx, leg)
+		if err != nil {
+			return nil, fmt.Errorf("settlement instruction %d: %w", i+1, err)
+		}
+		quotes = append(quotes, quote)
+	}
+	return quotes, nil
+}
+
+// checkSettlementMargin returns ErrSettlementDeferred when the gas payments reach the order's margin
+func checkSettlementMargin(ctx context.Context, feed pricefeed.Feed, args *types.ParsedArgs, quotes []settlementGas) error {
+	if len(quotes) == 0 {
+		return nil
+	}
+
+	margin, gasCost, err := valuedOrderMargin(ctx, feed, args, quotes)
+	if err != nil {
+		return err
+	}
+	if gasCost.Sign() > 0 && gasCost.Cmp(margin) >= 0 {
+		return fmt.Errorf("%w: gas payment %s would use up the order's margin %s", ErrSettlementDeferred,
+			amount.New(gasCost, config.NotionalDecimals), amount.New(margin, config.NotionalDecimals))
+	}
+	return nil
+}
+
+// valuedOrderMargin values the order's margin and the costs of handling it, paid in gas tokens, at the feed's prices
+func valuedOrderMargin(ctx context.Context, feed pricefeed.Feed, args *types.ParsedArgs, costs []settlementGas) (margin, total *big.Int, err error) {
+	total = new(big.Int)
+	for _, cost := range costs {
+		value, err := pricefeed.Value(ctx, feed, cost.chainID, cost.token, cost.amount)
+		if err != nil {
+			return nil, nil, fmt.Errorf("gas token %s on chain %d: %w", cost.token, cost.chainID, err)
+		}
+		total.Add(total, value)
+	}
+
+	sides := orderSides(args)
+	spent, err := sideValue(ctx, feed, sides[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("tokens spent: %w", err)
+	}
+	received, err := sideValue(ctx, feed, sides[1])
+	if err != nil {
+		return nil, nil, fmt.Errorf("tokens received: %w", err)
+	}
+	return received.Sub(received, spent), total, nil
+}
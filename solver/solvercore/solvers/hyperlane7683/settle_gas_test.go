@@ -0,0 +1,94 @@
+package hyperlane7683
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/pricefeed"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// Both handlers quote settlements for the settlement worker's gas check
+var (
+	_ settlementQuoter = (*HyperlaneEVM)(nil)
+	_ settlementQuoter = (*HyperlaneStarknet)(nil)
+)
+
+func TestSettlementGasMargin(t *testing.T) {
+	config.InitializeNetworks()
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+
+	ctx := context.Background()
+	dogs := new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))
+	// Spends 100 DOG on Base Sepolia and receives 101 DOG (or 51 USDC) on Ethereum Sepolia
+	orderArgs := func(receivedToken string, received *big.Int) *types.ParsedArgs {
+		return &types.ParsedArgs{
+			OrderID: "0x5555555555555555555555555555555555555555555555555555555555555555",
+			ResolvedOrder: types.ResolvedCrossChainOrder{
+				OriginChainID:    big.NewInt(config.EthereumSepoliaChainID),
+				MaxSpent:         []types.Output{{Token: "0xd0", Amount: dogs}},
+				MinReceived:      []types.Output{{Token: receivedToken, Amount: received}},
+				FillInstructions: []types.FillInstruction{{DestinationChainID: big.NewInt(config.BaseSepoliaChainID)}},
+			},
+		}
+	}
+	oneDog := big.NewInt(1e18)
+	likeForLike := orderArgs("0xd1", new(big.Int).Add(dogs, oneDog))
+	gas := func(wei int64) []settlementGas {
+		return []settlementGas{{chainID: config.BaseSepoliaChainID, token: pricefeed.NativeToken, amount: big.NewInt(wei)}}
+	}
+	const milliETH = 1e15
+
+	t.Run("unpriced like-for-like orders can't be compared", func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{}))
+		err := checkSettlementMargin(ctx, pricefeed.Default(), likeForLike, gas(1e18))
+		assert.ErrorIs(t, err, pricefeed.ErrNoPrice)
+		assert.NotErrorIs(t, err, ErrSettlementDeferred)
+	})
+
+	require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+		{ChainID: config.BaseSepoliaChainID, Address: "0xd0", Symbol: "DOG", Decimals: 18, Price: "1"},
+		{ChainID: config.BaseSepoliaChainID, Address: pricefeed.NativeToken, Symbol: "ETH", Decimals: 18, Price: "2000"},
+		{ChainID: config.EthereumSepoliaChainID, Address: "0xc0", Symbol: "USDC", Decimals: 6, Price: "2"},
+		{ChainID: config.EthereumSepoliaChainID, Address: "0xd1", Symbol: "DOG", Decimals: 18, Price: "1"},
+		{ChainID: config.EthereumSepoliaChainID, Address: "0xe0", Symbol: "CAT", Decimals: 18},
+	}}))
+
+	t.Run("priced gas is compared with the order's value margin", func(t *testing.T) {
+		// 0.1 mETH at 2000 is worth 0.2 DOG, 0.5 mETH is worth the whole 1 DOG margin
+		assert.NoError(t, checkSettlementMargin(ctx, pricefeed.Default(), likeForLike, gas(milliETH/10)))
+
+		err := checkSettlementMargin(ctx, pricefeed.Default(), likeForLike, gas(milliETH/2))
+		assert.ErrorIs(t, err, ErrSettlementDeferred)
+		assert.EqualError(t, err, "settlement deferred: gas payment 1 would use up the order's margin 1")
+	})
+
+	t.Run("gas of every leg adds up", func(t *testing.T) {
+		quotes := append(gas(milliETH/4), gas(milliETH/4)...)
+		assert.ErrorIs(t, checkSettlementMargin(ctx, pricefeed.Default(), likeForLike, quotes), ErrSettlementDeferred)
+	})
+
+	t.Run("cross-token orders are valued at their prices", func(t *testing.T) {
+		// 51 USDC at 2 is worth 102 DOG: a 2 DOG margin
+		args := orderArgs("0xc0", big.NewInt(51_000_000))
+		assert.NoError(t, checkSettlementMargin(ctx, pricefeed.Default(), args, gas(milliETH/2)))
+		assert.ErrorIs(t, checkSettlementMargin(ctx, pricefeed.Default(), args, gas(milliETH)), ErrSettlementDeferred)
+	})
+
+	t.Run("unpriced cross-token orders can't be compared", func(t *testing.T) {
+		err := checkSettlementMargin(ctx, pricefeed.Default(), orderArgs("0xe0", dogs), gas(milliETH))
+		assert.ErrorIs(t, err, pricefeed.ErrNoPrice)
+		assert.NotErrorIs(t, err, ErrSettlementDeferred)
+	})
+
+	t.Run("free settlements are never deferred", func(t *testing.T) {
+		args := orderArgs("0xd1", dogs)
+		assert.NoError(t, checkSettlementMargin(ctx, pricefeed.Default(), args, gas(0)))
+		assert.NoError(t, checkSettlementMargin(ctx, pricefeed.Default(), args, nil))
+	})
+}
@@ -0,0 +1,371 @@
+package hyperlane7683
+
+// Module: Settlement scheduler
+// - Settling no longer blocks filling: once every leg of an order is filled, ProcessIntent hands the
+//   order to the scheduler and returns, and a separate worker sends the settlement
+// - Orders are settled SETTLE_DELAY_SECONDS after their fill, so the fill is indexed before settling;
+//   due orders are taken in rounds of at most SETTLE_BATCH_SIZE, oldest first
+// - Failed settlements are retried with exponential backoff; orders that run out of attempts stay
+//   FILLED with the last error as reason, for the reconciler and operators
+// - On start, FILLED orders the solver filled are taken from the order store, so settlements pending
+//   at shutdown are resumed
+// - Unless SETTLE_MAX_DEFER_SECONDS is 0, due orders whose Hyperlane gas payment would make them
+//   unprofitable are deferred instead of settled (see settle_gas.go); the window starts when an order
+//   is scheduled, so it restarts for orders resumed after a restart
+// - Settlements whose earlier settle transaction is still in flight (ErrTxPending, see
+//   tx_outbox.go) are looked up again after SETTLE_RETRY_BACKOFF_SECONDS without counting an attempt
+//
+// Settings:
+// - SETTLE_DELAY_SECONDS: time between a fill and its settlement (default 2)
+// - SETTLE_BATCH_SIZE: orders settled per round (default 10)
+// - SETTLE_MAX_ATTEMPTS: settlement attempts per order, 0 retries forever (default 5)
+// - SETTLE_RETRY_BACKOFF_SECONDS: wait after the first failed attempt, doubled after each failure (default 30)
+// - SETTLE_MAX_DEFER_SECONDS, SETTLE_DEFER_INTERVAL_SECONDS: see settle_gas.go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const (
+	defaultSettleDelaySeconds        = 2
+	defaultSettleBatchSize           = 10
+	defaultSettleMaxAttempts         = 5
+	defaultSettleRetryBackoffSeconds = 30
+
+	// maxSettleRetryBackoff caps the doubling of the retry backoff
+	maxSettleRetryBackoff = time.Hour
+)
+
+// ErrSettlementRunning is returned by Take for an order whose settlement the worker is sending
+var ErrSettlementRunning = errors.New("settlement already in progress")
+
+// settleJob is an order waiting for its settlement
+type settleJob struct {
+	args       *types.ParsedArgs
+	dueAt      time.Time
+	deferUntil time.Time // settled regardless of its gas payment from then on
+	deferrals  int
+	attempts   int
+	running    bool
+}
+
+// SettleScheduler settles filled orders on its own worker. Methods are safe for concurrent use.
+type SettleScheduler struct {
+	settle   func(ctx context.Context, args *types.ParsedArgs) error
+	checkGas func(ctx context.Context, args *types.ParsedArgs) error // nil settles without a gas check
+	record   func(args *types.ParsedArgs, status orders.Status, reason string)
+	store    *orders.Store
+	metrics  *metrics.Registry
+
+	delay       time.Duration
+	batchSize   int
+	maxAttempts int
+	backoff     time.Duration
+	// maxDefer is how long a settlement may be deferred for its gas payment, 0 never defers
+	maxDefer      time.Duration
+	deferInterval time.Duration
+	now           func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]*settleJob
+	wake    chan struct{}
+}
+
+// NewSettleScheduler creates a scheduler settling through the solver and resuming the FILLED orders of store
+func NewSettleScheduler(solver *Hyperlane7683Solver, store *orders.Store) *SettleScheduler {
+	return &SettleScheduler{
+		settle:        solver.SettleOrder,
+		checkGas:      solver.CheckSettlementGas,
+		record:        solver.recordOrderStatus,
+		store:         store,
+		metrics:       metrics.Default(),
+		delay:         time.Duration(envutil.GetEnvInt("SETTLE_DELAY_SECONDS", defaultSettleDelaySeconds)) * time.Second,
+		batchSize:     envutil.GetEnvInt("SETTLE_BATCH_SIZE", defaultSettleBatchSize),
+		maxAttempts:   envutil.GetEnvInt("SETTLE_MAX_ATTEMPTS", defaultSettleMaxAttempts),
+		backoff:       time.Duration(envutil.GetEnvInt("SETTLE_RETRY_BACKOFF_SECONDS", defaultSettleRetryBackoffSeconds)) * time.Second,
+		maxDefer:      settleMaxDeferFromEnv(),
+		deferInterval: time.Duration(envutil.GetEnvInt("SETTLE_DEFER_INTERVAL_SECONDS", defaultSettleDeferIntervalSeconds)) * time.Second,
+		now:           time.Now,
+		pending:       make(map[string]*settleJob),
+		wake:          make(chan struct{}, 1),
+	}
+}
+
+// Schedule queues the settlement of a filled order; an order already queued keeps its schedule
+func (s *SettleScheduler) Schedule(args *types.ParsedArgs) {
+	s.mu.Lock()
+	if _, queued := s.pending[args.OrderID]; queued {
+		s.mu.Unlock()
+		return
+	}
+	dueAt := s.now().Add(s.delay)
+	s.pending[args.OrderID] = &settleJob{args: args, dueAt: dueAt, deferUntil: dueAt.Add(s.maxDefer)}
+	s.updatePendingGauge()
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Take removes an order from the queue so it can be settled outside the worker, e.g. by an
+// operator; queued reports whether it was waiting
+func (s *SettleScheduler) Take(orderID string) (queued bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.pending[orderID]
+	if !ok {
+		return false, nil
+	}
+	if job.running {
+		return true, ErrSettlementRunning
+	}
+	delete(s.pending, orderID)
+	s.updatePendingGauge()
+	return true, nil
+}
+
+// Len returns the number of orders waiting for their settlement
+func (s *SettleScheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending)
+}
+
+// Start resumes pending settlements from the order store and runs the worker until ctx is done
+// or the returned function is called
+func (s *SettleScheduler) Start(ctx context.Context) func() {
+	if resumed := s.resume(); resumed > 0 {
+		fillerLog.Printf("   📬 Resuming settlement of %d filled orders\n", resumed)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	go s.run(ctx)
+
+	fillerLog.Printf("   📬 Settlement worker started (delay %s, %d orders per round)\n", s.delay, s.batchSize)
+	return cancel
+}
+
+// resume schedules the orders the store holds as FILLED by the solver
+func (s *SettleScheduler) resume() int {
+	if s.store == nil {
+		return 0
+	}
+	resumed := 0
+	for _, order := range s.store.List() {
+		if order.Status != orders.StatusFilled || !order.FilledByUs() || order.Args == nil {
+			continue
+		}
+		s.Schedule(order.Args)
+		resumed++
+	}
+	return resumed
+}
+
+// run settles due orders whenever the earliest one is due or a new order is scheduled
+func (s *SettleScheduler) run(ctx context.Context) {
+	for {
+		var timer *time.Timer
+		var due <-chan time.Time
+		if next, ok := s.nextDue(); ok {
+			timer = time.NewTimer(max(next.Sub(s.now()), 0))
+			due = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-s.wake:
+		case <-due:
+			s.RunDue(ctx)
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// nextDue returns when the earliest queued order is due
+func (s *SettleScheduler) nextDue() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var next time.Time
+	for _, job := range s.pending {
+		if !job.running && (next.IsZero() || job.dueAt.Before(next)) {
+			next = job.dueAt
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// RunDue settles one round of due orders, oldest first, and returns how many were settled
+func (s *SettleScheduler) RunDue(ctx context.Context) int {
+	settled := 0
+	for _, job := range s.takeDue() {
+		if ctx.Err() != nil {
+			s.release(job)
+			continue
+		}
+		if s.deferForGas(ctx, job) {
+			continue
+		}
+		err := s.settle(ctx, job.args)
+		if err == nil {
+			s.finish(job)
+			settled++
+			continue
+		}
+		// Shutting down is not the order's fault; keep its attempts for the next run
+		if ctx.Err() != nil {
+			s.release(job)
+			continue
+		}
+		// A settle sent earlier is still in flight; look it up again later instead of failing
+		if errors.Is(err, ErrTxPending) {
+			s.postpone(job, s.now().Add(s.backoff), "pending", err)
+			continue
+		}
+		s.fail(job, err)
+	}
+	return settled
+}
+
+// takeDue marks up to batchSize due orders as running and returns them
+func (s *SettleScheduler) takeDue() []*settleJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var due []*settleJob
+	for _, job := range s.pending {
+		if !job.running && !job.dueAt.After(now) {
+			due = append(due, job)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].dueAt.Before(due[j].dueAt) })
+	if s.batchSize > 0 && len(due) > s.batchSize {
+		due = due[:s.batchSize]
+	}
+	for _, job := range due {
+		job.running = true
+	}
+	return due
+}
+
+// release puts a job back without counting an attempt
+func (s *SettleScheduler) release(job *settleJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job.running = false
+}
+
+// finish records a settled order and drops it
+func (s *SettleScheduler) finish(job *settleJob) {
+	s.mu.Lock()
+	delete(s.pending, job.args.OrderID)
+	s.updatePendingGauge()
+	s.mu.Unlock()
+
+	s.record(job.args, orders.StatusSettled, "")
+	s.metrics.Counter("solver_settlements_total", "result", "settled").Inc()
+	logutil.LogOperationComplete(job.args, "Order settlement", true)
+}
+
+// deferForGas checks the gas payment of a job still within its deferral window and pushes the job
+// back when settling now would make the order unprofitable; it reports whether the job was deferred.
+// Other check errors (e.g. a failed quote) are left for the settlement to report.
+func (s *SettleScheduler) deferForGas(ctx context.Context, job *settleJob) bool {
+	if s.checkGas == nil || s.maxDefer <= 0 {
+		return false
+	}
+	now := s.now()
+	if !now.Before(job.deferUntil) {
+		if job.deferrals > 0 {
+			fillerLog.Printf("⏰ Settlement of order %s deferred %d times, settling regardless of its gas payment\n", job.args.OrderID, job.deferrals)
+		}
+		return false
+	}
+
+	err := s.checkGas(ctx, job.args)
+	if !errors.Is(err, ErrSettlementDeferred) {
+		return false
+	}
+
+	dueAt := now.Add(s.deferInterval)
+	if dueAt.After(job.deferUntil) {
+		dueAt = job.deferUntil
+	}
+	s.mu.Lock()
+	job.deferrals++
+	s.mu.Unlock()
+	s.postpone(job, dueAt, "deferred", err)
+	return true
+}
+
+// postpone puts a job back until dueAt without counting an attempt, recording why
+func (s *SettleScheduler) postpone(job *settleJob, dueAt time.Time, result string, reason error) {
+	s.mu.Lock()
+	job.running = false
+	job.dueAt = dueAt
+	s.mu.Unlock()
+
+	s.record(job.args, orders.StatusFilled, reason.Error())
+	s.metrics.Counter("solver_settlements_total", "result", result).Inc()
+	fillerLog.Printf("⏳ Settlement of order %s postponed until %s: %v\n", job.args.OrderID, dueAt.Format(time.RFC3339), reason)
+}
+
+// fail schedules a retry of a failed settlement, or gives up once the order ran out of attempts
+func (s *SettleScheduler) fail(job *settleJob, err error) {
+	s.mu.Lock()
+	job.running = false
+	job.attempts++
+	giveUp := s.maxAttempts > 0 && job.attempts >= s.maxAttempts
+	if giveUp {
+		delete(s.pending, job.args.OrderID)
+		s.updatePendingGauge()
+	} else {
+		job.dueAt = s.now().Add(s.retryBackoff(job.attempts))
+	}
+	attempts, dueAt := job.attempts, job.dueAt
+	s.mu.Unlock()
+
+	logutil.LogOperationComplete(job.args, "Order settlement", false)
+	if giveUp {
+		s.record(job.args, orders.StatusFilled, "settlement failed: "+err.Error())
+		s.metrics.Counter("solver_settlements_total", "result", "abandoned").Inc()
+		fillerLog.Printf("❌ Settlement of order %s failed after %d attempts, giving up: %v\n", job.args.OrderID, attempts, err)
+		return
+	}
+	s.record(job.args, orders.StatusFilled, fmt.Sprintf("settlement attempt %d failed, retrying: %v", attempts, err))
+	s.metrics.Counter("solver_settlements_total", "result", "retry").Inc()
+	fillerLog.Printf("⚠️  Settlement of order %s failed (attempt %d), retrying at %s: %v\n",
+		job.args.OrderID, attempts, dueAt.Format(time.RFC3339), err)
+}
+
+// retryBackoff returns the wait after the given number of failed attempts
+func (s *SettleScheduler) retryBackoff(attempts int) time.Duration {
+	backoff := s.backoff
+	for i := 1; i < attempts && backoff < maxSettleRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxSettleRetryBackoff)
+}
+
+// updatePendingGauge publishes the queue length; the caller holds s.mu
+func (s *SettleScheduler) updatePendingGauge() {
+	s.metrics.Gauge("solver_settlements_pending").Set(float64(len(s.pending)))
+}
@@ -0,0 +1,298 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSettler records settle calls and fails the orders listed in failures
+type testSettler struct {
+	mu       sync.Mutex
+	settled  []string
+	failures map[string]error
+}
+
+func (s *testSettler) settle(_ context.Context, args *types.ParsedArgs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.failures[args.OrderID]; err != nil {
+		return err
+	}
+	s.settled = append(s.settled, args.OrderID)
+	return nil
+}
+
+func (s *testSettler) calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.settled...)
+}
+
+func newTestSettleScheduler(t *testing.T, settler *testSettler) (*SettleScheduler, *orders.Store, *time.Time) {
+	store, err := orders.NewStore(filepath.Join(t.TempDir(), "orders.json"))
+	require.NoError(t, err)
+
+	now := time.Unix(1_700_000_000, 0)
+	s := &SettleScheduler{
+		settle: settler.settle,
+		record: func(args *types.ParsedArgs, status orders.Status, reason string) {
+			require.NoError(t, store.SetStatus(args.OrderID, status, reason))
+		},
+		store:       store,
+		metrics:     metrics.NewRegistry(),
+		delay:       2 * time.Second,
+		batchSize:   2,
+		maxAttempts: 3,
+		backoff:     30 * time.Second,
+		now:         func() time.Time { return now },
+		pending:     make(map[string]*settleJob),
+		wake:        make(chan struct{}, 1),
+	}
+	return s, store, &now
+}
+
+// settleOrderID returns a full-length order ID
+func settleOrderID(n int) string {
+	return fmt.Sprintf("0x%064x", n)
+}
+
+// filledOrder adds an order the solver filled to the store and returns its args
+func filledOrder(t *testing.T, store *orders.Store, orderID string) *types.ParsedArgs {
+	args := reconcileArgs(orderID)
+	require.NoError(t, store.Observe(args))
+	require.NoError(t, store.SetStatus(orderID, orders.StatusFilled, ""))
+	return args
+}
+
+// TestSettleScheduler tests delayed, batched and retried settlement
+func TestSettleScheduler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("settles after the delay", func(t *testing.T) {
+		settler := &testSettler{}
+		s, store, now := newTestSettleScheduler(t, settler)
+		s.Schedule(filledOrder(t, store, settleOrderID(1)))
+
+		assert.Zero(t, s.RunDue(ctx), "not due yet")
+		*now = now.Add(2 * time.Second)
+		assert.Equal(t, 1, s.RunDue(ctx))
+
+		assert.Equal(t, []string{settleOrderID(1)}, settler.calls())
+		assert.Zero(t, s.Len())
+		order, _ := store.Get(settleOrderID(1))
+		assert.Equal(t, orders.StatusSettled, order.Status)
+		assert.Equal(t, 1.0, s.metrics.Counter("solver_settlements_total", "result", "settled").Value())
+	})
+
+	t.Run("scheduling twice keeps one settlement", func(t *testing.T) {
+		settler := &testSettler{}
+		s, store, now := newTestSettleScheduler(t, settler)
+		args := filledOrder(t, store, settleOrderID(1))
+		s.Schedule(args)
+		*now = now.Add(time.Second)
+		s.Schedule(args)
+
+		*now = now.Add(time.Second)
+		assert.Equal(t, 1, s.RunDue(ctx), "due on the first schedule")
+		assert.Equal(t, []string{settleOrderID(1)}, settler.calls())
+	})
+
+	t.Run("settles at most a batch per round, oldest first", func(t *testing.T) {
+		settler := &testSettler{}
+		s, store, now := newTestSettleScheduler(t, settler)
+		for i := range 3 {
+			s.Schedule(filledOrder(t, store, settleOrderID(i)))
+			*now = now.Add(time.Second)
+		}
+
+		*now = now.Add(2 * time.Second)
+		assert.Equal(t, 2, s.RunDue(ctx))
+		assert.Equal(t, []string{settleOrderID(0), settleOrderID(1)}, settler.calls())
+		assert.Equal(t, 1, s.Len())
+		assert.Equal(t, 1, s.RunDue(ctx))
+		assert.Equal(t, []string{settleOrderID(0), settleOrderID(1), settleOrderID(2)}, settler.calls())
+	})
+
+	t.Run("retries with backoff, then gives up", func(t *testing.T) {
+		settler := &testSettler{failures: map[string]error{settleOrderID(1): errors.New("settle reverted")}}
+		s, store, now := newTestSettleScheduler(t, settler)
+		s.Schedule(filledOrder(t, store, settleOrderID(1)))
+		*now = now.Add(2 * time.Second)
+
+		assert.Zero(t, s.RunDue(ctx))
+		order, _ := store.Get(settleOrderID(1))
+		assert.Equal(t, orders.StatusFilled, order.Status)
+		assert.Contains(t, order.Reason, "attempt 1 failed, retrying: settle reverted")
+
+		// First retry after the backoff, the second after twice the backoff
+		*now = now.Add(29 * time.Second)
+		s.RunDue(ctx)
+		assert.Equal(t, 1.0, s.metrics.Counter("solver_settlements_total", "result", "retry").Value(), "not due before the backoff")
+		*now = now.Add(time.Second)
+		s.RunDue(ctx)
+		assert.Equal(t, 2.0, s.metrics.Counter("solver_settlements_total", "result", "retry").Value())
+		*now = now.Add(60 * time.Second)
+		s.RunDue(ctx)
+
+		assert.Zero(t, s.Len(), "dropped after the last attempt")
+		assert.Equal(t, 1.0, s.metrics.Counter("solver_settlements_total", "result", "abandoned").Value())
+		order, _ = store.Get(settleOrderID(1))
+		assert.Equal(t, orders.StatusFilled, order.Status)
+		assert.Equal(t, "settlement failed: settle reverted", order.Reason)
+	})
+
+	t.Run("a failing order doesn't hold up the others", func(t *testing.T) {
+		settler := &testSettler{failures: map[string]error{settleOrderID(1): errors.New("route unhealthy")}}
+		s, store, now := newTestSettleScheduler(t, settler)
+		s.Schedule(filledOrder(t, store, settleOrderID(1)))
+		s.Schedule(filledOrder(t, store, settleOrderID(2)))
+
+		*now = now.Add(2 * time.Second)
+		assert.Equal(t, 1, s.RunDue(ctx))
+		assert.Equal(t, []string{settleOrderID(2)}, settler.calls())
+		assert.Equal(t, 1, s.Len())
+	})
+
+	t.Run("cancelled runs don't count an attempt", func(t *testing.T) {
+		settler := &testSettler{}
+		s, store, now := newTestSettleScheduler(t, settler)
+		s.Schedule(filledOrder(t, store, settleOrderID(1)))
+		*now = now.Add(2 * time.Second)
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		assert.Zero(t, s.RunDue(cancelled))
+		assert.Empty(t, settler.calls())
+		assert.Equal(t, 1, s.Len())
+		assert.Equal(t, 1, s.RunDue(ctx))
+	})
+
+	t.Run("resumes filled orders from the store", func(t *testing.T) {
+		settler := &testSettler{}
+		s, store, _ := newTestSettleScheduler(t, settler)
+		filledOrder(t, store, settleOrderID(1))
+		require.NoError(t, store.AddCost(settleOrderID(1), orders.TxCost{Kind: orders.TxKindFill, TxHash: "0xf1"}))
+		filledOrder(t, store, settleOrderID(2)) // Found FILLED on-chain by the reconciler, not our fill
+		seedOrder(t, store, settleOrderID(3), orders.StatusSettled)
+		seedOrder(t, store, settleOrderID(4), orders.StatusFailed)
+
+		assert.Equal(t, 1, s.resume())
+		assert.Equal(t, 1, s.Len())
+	})
+
+	t.Run("take removes a waiting order", func(t *testing.T) {
+		settler := &testSettler{}
+		s, store, now := newTestSettleScheduler(t, settler)
+		s.Schedule(filledOrder(t, store, settleOrderID(1)))
+		s.Schedule(filledOrder(t, store, settleOrderID(2)))
+
+		queued, err := s.Take(settleOrderID(1))
+		require.NoError(t, err)
+		assert.True(t, queued)
+		queued, err = s.Take(settleOrderID(3))
+		require.NoError(t, err)
+		assert.False(t, queued)
+
+		*now = now.Add(2 * time.Second)
+		s.takeDue() // order 2 is now being settled
+		_, err = s.Take(settleOrderID(2))
+		assert.ErrorIs(t, err, ErrSettlementRunning)
+		assert.Equal(t, 1, s.Len())
+	})
+
+	t.Run("defers unprofitable settlements until the max defer", func(t *testing.T) {
+		settler := &testSettler{}
+		s, store, now := newTestSettleScheduler(t, settler)
+		s.maxDefer = 5 * time.Minute
+		s.deferInterval = 2 * time.Minute
+		gasChecks := 0
+		s.checkGas = func(context.Context, *types.ParsedArgs) error {
+			gasChecks++
+			return fmt.Errorf("%w: gas payment 3 would use up the order's margin 2", ErrSettlementDeferred)
+		}
+		s.Schedule(filledOrder(t, store, settleOrderID(1)))
+
+		*now = now.Add(2 * time.Second)
+		assert.Zero(t, s.RunDue(ctx))
+		order, _ := store.Get(settleOrderID(1))
+		assert.Equal(t, orders.StatusFilled, order.Status)
+		assert.Equal(t, "settlement deferred: gas payment 3 would use up the order's margin 2", order.Reason)
+		assert.Equal(t, 1.0, s.metrics.Counter("solver_settlements_total", "result", "deferred").Value())
+
+		*now = now.Add(2 * time.Minute)
+		assert.Zero(t, s.RunDue(ctx))
+		*now = now.Add(2 * time.Minute)
+		assert.Zero(t, s.RunDue(ctx))
+		next, _ := s.nextDue()
+		assert.Equal(t, time.Minute, next.Sub(*now), "the last check is at the deadline")
+
+		*now = next
+		assert.Equal(t, 1, s.RunDue(ctx), "settled regardless once the deadline passed")
+		assert.Equal(t, 3, gasChecks)
+		assert.Equal(t, []string{settleOrderID(1)}, settler.calls())
+	})
+
+	t.Run("gas check errors are left to the settlement", func(t *testing.T) {
+		settler := &testSettler{}
+		s, store, now := newTestSettleScheduler(t, settler)
+		s.maxDefer = 5 * time.Minute
+		s.checkGas = func(context.Context, *types.ParsedArgs) error { return errors.New("quote failed") }
+		s.Schedule(filledOrder(t, store, settleOrderID(1)))
+
+		*now = now.Add(2 * time.Second)
+		assert.Equal(t, 1, s.RunDue(ctx))
+	})
+
+	t.Run("settles still in flight don't count an attempt", func(t *testing.T) {
+		pending := fmt.Errorf("settlement instruction 1 failed: %w: 0xaa", ErrTxPending)
+		settler := &testSettler{failures: map[string]error{settleOrderID(1): pending}}
+		s, store, now := newTestSettleScheduler(t, settler)
+		s.Schedule(filledOrder(t, store, settleOrderID(1)))
+
+		for range s.maxAttempts + 1 {
+			*now = now.Add(s.backoff)
+			s.RunDue(ctx)
+		}
+		assert.Equal(t, 1, s.Len(), "never given up on")
+		assert.Equal(t, float64(s.maxAttempts+1), s.metrics.Counter("solver_settlements_total", "result", "pending").Value())
+		order, _ := store.Get(settleOrderID(1))
+		assert.Equal(t, "settlement instruction 1 failed: transaction still pending: 0xaa", order.Reason)
+
+		settler.mu.Lock()
+		delete(settler.failures, settleOrderID(1))
+		settler.mu.Unlock()
+		*now = now.Add(s.backoff)
+		assert.Equal(t, 1, s.RunDue(ctx))
+	})
+
+	t.Run("backoff doubles up to the cap", func(t *testing.T) {
+		s, _, _ := newTestSettleScheduler(t, &testSettler{})
+		assert.Equal(t, 30*time.Second, s.retryBackoff(1))
+		assert.Equal(t, 60*time.Second, s.retryBackoff(2))
+		assert.Equal(t, 120*time.Second, s.retryBackoff(3))
+		assert.Equal(t, maxSettleRetryBackoff, s.retryBackoff(50))
+	})
+
+	t.Run("worker settles scheduled orders", func(t *testing.T) {
+		settler := &testSettler{}
+		s, store, _ := newTestSettleScheduler(t, settler)
+		s.now = time.Now
+		s.delay = 0
+
+		stop := s.Start(ctx)
+		defer stop()
+		s.Schedule(filledOrder(t, store, settleOrderID(1)))
+		assert.Eventually(t, func() bool { return len(settler.calls()) == 1 }, time.Second, 5*time.Millisecond)
+	})
+}
@@ -0,0 +1,102 @@
+package hyperlane7683
+
+// Module: Origin-side settlement bookkeeping
+// - The listeners report every Settled and Refunded event seen on the origin routers they watch
+// - Settled events of orders the solver filled mark the settlement proceeds as received in the order store
+// - Refunded events record that the input went back to the user; a refund of an order we filled is a loss
+// - Events of orders missing from the store (opened before the solver ran, or filled by others) are ignored
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+)
+
+// SettlementTracker records origin-side events in the order store. Methods are safe for concurrent
+// use and no-ops on a nil receiver or without a store.
+type SettlementTracker struct {
+	mu      sync.RWMutex
+	store   *orders.Store
+	metrics *metrics.Registry
+}
+
+// NewSettlementTracker creates a tracker without a store
+func NewSettlementTracker() *SettlementTracker {
+	return &SettlementTracker{metrics: metrics.Default()}
+}
+
+var (
+	defaultSettlementTracker     *SettlementTracker
+	defaultSettlementTrackerOnce sync.Once
+)
+
+// DefaultSettlementTracker returns the process-wide tracker shared by the listeners and the solver
+func DefaultSettlementTracker() *SettlementTracker {
+	defaultSettlementTrackerOnce.Do(func() {
+		defaultSettlementTracker = NewSettlementTracker()
+	})
+	return defaultSettlementTracker
+}
+
+// SetOrderStore sets the store origin events are recorded in
+func (t *SettlementTracker) SetOrderStore(store *orders.Store) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.store = store
+}
+
+// RecordSettled records a Settled event seen on the origin chain chainName: the input of the order
+// was released to receiver
+func (t *SettlementTracker) RecordSettled(chainName, orderID, receiver, txHash string) {
+	store, order, ok := t.storedOrder(orderID)
+	if !ok || !order.FilledByUs() {
+		return
+	}
+	if err := store.RecordOriginSettled(orderID, txHash); err != nil && !errors.Is(err, orders.ErrOrderNotFound) {
+		fillerLog.Printf("⚠️  Failed to record settlement of order %s: %v\n", orderID, err)
+		return
+	}
+	t.metrics.Counter("solver_settlement_proceeds_total", "chain", chainName).Inc()
+	fillerLog.Printf("%s💰 Settlement proceeds of order %s released to %s (tx %s)\n", logutil.Prefix(chainName), orderID, receiver, txHash)
+}
+
+// RecordRefunded records a Refunded event seen on the origin chain chainName: the input of the order
+// went back to receiver, the user
+func (t *SettlementTracker) RecordRefunded(chainName, orderID, receiver, txHash string) {
+	store, order, ok := t.storedOrder(orderID)
+	if !ok {
+		return
+	}
+	if err := store.RecordOriginRefunded(orderID, txHash); err != nil && !errors.Is(err, orders.ErrOrderNotFound) {
+		fillerLog.Printf("⚠️  Failed to record refund of order %s: %v\n", orderID, err)
+		return
+	}
+	if order.FilledByUs() {
+		t.metrics.Counter("solver_refunded_fills_total", "chain", chainName).Inc()
+		fillerLog.Printf("%s🚨 Order %s was refunded to %s after we filled it (tx %s), proceeds will not arrive\n",
+			logutil.Prefix(chainName), orderID, receiver, txHash)
+		return
+	}
+	fillerLog.Printf("%s↩️  Order %s refunded to %s (tx %s)\n", logutil.Prefix(chainName), orderID, receiver, txHash)
+}
+
+// storedOrder returns the tracker's store and its record of an order, if it holds one
+func (t *SettlementTracker) storedOrder(orderID string) (*orders.Store, orders.Order, bool) {
+	if t == nil {
+		return nil, orders.Order{}, false
+	}
+	t.mu.RLock()
+	store := t.store
+	t.mu.RUnlock()
+	if store == nil {
+		return nil, orders.Order{}, false
+	}
+	order, ok := store.Get(orderID)
+	return store, order, ok
+}
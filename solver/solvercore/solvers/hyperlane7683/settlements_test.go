@@ -0,0 +1,113 @@
+package hyperlane7683
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// settlementStore returns a store holding order 0x01, filled by us, and order 0x02, not filled
+func settlementStore(t *testing.T) *orders.Store {
+	t.Helper()
+	store, err := orders.NewStore(filepath.Join(t.TempDir(), "orders.json"))
+	require.NoError(t, err)
+
+	filled := &types.ParsedArgs{OrderID: common.Hash{0x01}.Hex()}
+	filled.ResolvedOrder.MinReceived = []types.Output{{Token: "0xin", Amount: big.NewInt(100), ChainID: big.NewInt(config.BaseSepoliaChainID)}}
+	require.NoError(t, store.Observe(filled))
+	require.NoError(t, store.AddCost(filled.OrderID, orders.TxCost{Kind: orders.TxKindFill, TxHash: "0xaa"}))
+	require.NoError(t, store.Observe(&types.ParsedArgs{OrderID: common.Hash{0x02}.Hex()}))
+	return store
+}
+
+func TestSettlementTracker(t *testing.T) {
+	t.Run("records proceeds of our fills and refunds", func(t *testing.T) {
+		store := settlementStore(t)
+		tracker := NewSettlementTracker()
+		tracker.SetOrderStore(store)
+
+		tracker.RecordSettled("Base", common.Hash{0x01}.Hex(), "0x00000000000000000000000000000000000000cc", "0xbb")
+		tracker.RecordSettled("Base", common.Hash{0x02}.Hex(), "0x00000000000000000000000000000000000000dd", "0xcc")
+		tracker.RecordRefunded("Base", common.Hash{0x02}.Hex(), "0x00000000000000000000000000000000000000ee", "0xdd")
+		tracker.RecordSettled("Base", common.Hash{0x03}.Hex(), "0x00000000000000000000000000000000000000cc", "0xee") // unknown order
+
+		order, _ := store.Get(common.Hash{0x01}.Hex())
+		assert.Equal(t, orderStatusSettled, order.OriginStatus)
+		assert.Equal(t, "0xbb", order.OriginEventTxHash)
+		assert.False(t, order.ProceedsReceivedAt.IsZero())
+		require.Len(t, order.InputReceived, 1)
+
+		order, _ = store.Get(common.Hash{0x02}.Hex())
+		assert.Equal(t, orderStatusRefunded, order.OriginStatus, "another filler's settlement is not ours")
+		assert.Equal(t, "0xdd", order.OriginEventTxHash)
+		assert.Empty(t, order.InputReceived)
+	})
+
+	t.Run("nil tracker and missing store are no-ops", func(t *testing.T) {
+		var tracker *SettlementTracker
+		tracker.SetOrderStore(nil)
+		tracker.RecordSettled("Base", "0x01", "0xcc", "0xbb")
+		tracker.RecordRefunded("Base", "0x01", "0xcc", "0xbb")
+		NewSettlementTracker().RecordSettled("Base", "0x01", "0xcc", "0xbb")
+	})
+}
+
+// originEventLog builds a Settled or Refunded log emitted by router
+func originEventLog(t testing.TB, router common.Address, event string, orderID [32]byte, receiver common.Address, txHash common.Hash, blockNumber uint64) gethtypes.Log {
+	t.Helper()
+	parsedABI, err := contracts.Hyperlane7683MetaData.GetAbi()
+	require.NoError(t, err)
+	data, err := parsedABI.Events[event].Inputs.NonIndexed().Pack(orderID, receiver)
+	require.NoError(t, err)
+	return gethtypes.Log{
+		Address:     router,
+		Topics:      []common.Hash{parsedABI.Events[event].ID},
+		Data:        data,
+		BlockNumber: blockNumber,
+		TxHash:      txHash,
+	}
+}
+
+func TestEVMListenerReportsOriginEvents(t *testing.T) {
+	router := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	receiver := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+	backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+	backend.AddLog(originEventLog(t, router, "Settled", [32]byte{0x01}, receiver, common.HexToHash("0xbb"), 12))
+	backend.AddLog(originEventLog(t, router, "Refunded", [32]byte{0x02}, receiver, common.HexToHash("0xcc"), 13))
+
+	store := settlementStore(t)
+	tracker := NewSettlementTracker()
+	tracker.SetOrderStore(store)
+	listener := &evmListener{
+		config:          &base.ListenerConfig{ChainName: "Base", ContractAddress: router.Hex()},
+		client:          backend,
+		contractAddress: router,
+		stopChan:        make(chan struct{}),
+		settlements:     tracker,
+	}
+	handler := func(types.ParsedArgs, string, uint64) (bool, error) {
+		t.Fatal("Settled and Refunded events are not dispatched")
+		return false, nil
+	}
+
+	_, err := listener.processBlockRange(context.Background(), 10, 15, handler)
+	require.NoError(t, err)
+
+	order, _ := store.Get(common.Hash{0x01}.Hex())
+	assert.Equal(t, orderStatusSettled, order.OriginStatus)
+	assert.Equal(t, common.HexToHash("0xbb").Hex(), order.OriginEventTxHash)
+	order, _ = store.Get(common.Hash{0x02}.Hex())
+	assert.Equal(t, orderStatusRefunded, order.OriginStatus)
+}
@@ -9,44 +9,96 @@ import (
 	"context"
 	"fmt"
 	"math/big"
-	"strings"
 	"sync"
 	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/events"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 
 	"github.com/NethermindEth/starknet.go/account"
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
+// fillerLog logs order processing, fills and settlements at the "filler" log level
+var fillerLog = logutil.For(logutil.ComponentFiller)
+
+// fillSettleDelay gives the fill transaction time to be processed before settling;
+// a variable so benchmarks can run without it
+var fillSettleDelay = 2 * time.Second
+
 type Hyperlane7683Solver struct {
 	// Centralized client and signer management functions from SolverManager
-	getEVMClient      func(chainID uint64) (*ethclient.Client, error)
-	getStarknetClient func() (*rpc.Provider, error)
+	getEVMClient      func(chainID uint64) (EVMClient, error)
+	getStarknetClient func(chainID uint64) (rpc.RPCProvider, error)
 	getEVMSigner      func(chainID uint64) (*bind.TransactOpts, error)
-	getStarknetSigner func() (*account.Account, error)
+	getStarknetSigner func(chainID uint64) (*account.Account, error)
 
 	// Chain handlers implementing ChainHandler interface - now per-chain
-	evmHandlers       map[uint64]ChainHandler // Map of chainID -> handler
-	evmHandlersMux    sync.RWMutex            // Protects evmHandlers map
-	hyperlaneStarknet ChainHandler
+	evmHandlers         map[uint64]ChainHandler // Map of chainID -> handler
+	evmHandlersMux      sync.RWMutex            // Protects evmHandlers map
+	starknetHandlers    map[uint64]ChainHandler // Map of chainID -> handler, one per Starknet network
+	starknetHandlersMux sync.Mutex              // Protects starknetHandlers map
+
+	// Allow/block lists for controlling which orders to process; replaced on config reload
+	allowBlockLists   types.AllowBlockLists
+	allowBlockListsMu sync.RWMutex
+
+	// Pauses operations on chains with repeated failures
+	breaker *CircuitBreaker
+
+	// Records order progress; nil disables recording
+	orderStore *orders.Store
+
+	// Records every transaction the chain handlers send; nil disables the audit log (see tx_audit.go)
+	txAudit *TxAuditLog
+
+	// Fill and settle transactions in flight, so each is sent once; nil disables it (see tx_outbox.go)
+	txOutbox *TxOutbox
+
+	// How much the chain handlers approve when an allowance is too low (see approvals.go)
+	approvalPolicy ApprovalPolicy
+
+	// Reads metadata of tokens missing from the registry; nil disables discovery
+	tokenDiscovery *TokenDiscovery
 
-	// Allow/block lists for controlling which orders to process
-	allowBlockLists types.AllowBlockLists
+	// Fills seen on destination chains; orders a competitor already filled are skipped. nil disables the check
+	competition *CompetitionTracker
+
+	// Checks the Hyperlane route before settling; nil disables the check (see route_health.go)
+	routeHealth *RouteHealthChecker
+
+	// Settles filled orders on its own worker; nil settles inline right after the fill (see settle_scheduler.go)
+	settler *SettleScheduler
+
+	// Rules evaluated after the default rules; added before the solver starts
+	customRules []Rule
+
+	// Clock of the order age checks (see order_age.go)
+	now func() time.Time
+
+	// Counts rejected orders by reason (see rejections.go)
+	metrics *metrics.Registry
+
+	// Receives the order lifecycle events; nil disables them (see order_events.go)
+	eventBus *events.Bus
+
+	// Runs rules without signing anything (see observer.go)
+	observerMode bool
 
 	// Metadata for this solver
 	metadata types.Hyperlane7683Metadata
 }
 
 func NewHyperlane7683Solver(
-	getEVMClient func(chainID uint64) (*ethclient.Client, error),
-	getStarknetClient func() (*rpc.Provider, error),
+	getEVMClient func(chainID uint64) (EVMClient, error),
+	getStarknetClient func(chainID uint64) (rpc.RPCProvider, error),
 	getEVMSigner func(chainID uint64) (*bind.TransactOpts, error),
-	getStarknetSigner func() (*account.Account, error),
+	getStarknetSigner func(chainID uint64) (*account.Account, error),
 	allowBlockLists types.AllowBlockLists,
 ) *Hyperlane7683Solver {
 	metadata := types.Hyperlane7683Metadata{
@@ -62,55 +114,167 @@ func NewHyperlane7683Solver(
 		getStarknetSigner: getStarknetSigner,
 		evmHandlers:       make(map[uint64]ChainHandler),
 		evmHandlersMux:    sync.RWMutex{},
-		hyperlaneStarknet: nil, // Will be created when needed
+		starknetHandlers:  make(map[uint64]ChainHandler), // Created when needed
 		allowBlockLists:   allowBlockLists,
+		breaker:           DefaultCircuitBreaker(),
+		routeHealth:       routeHealthCheckerFromEnv(getEVMClient),
+		approvalPolicy:    approvalPolicyFromEnv(),
+		now:               time.Now,
+		metrics:           metrics.Default(),
+		eventBus:          events.Default(),
+		observerMode:      observerModeFromEnv(),
 		metadata:          metadata,
 	}
 }
 
+// SetAllowBlockLists replaces the allow/block lists; orders already past the check are not affected
+func (f *Hyperlane7683Solver) SetAllowBlockLists(allowBlockLists types.AllowBlockLists) {
+	f.allowBlockListsMu.Lock()
+	defer f.allowBlockListsMu.Unlock()
+	f.allowBlockLists = allowBlockLists
+}
+
+// SetOrderStore enables recording of order progress in store
+func (f *Hyperlane7683Solver) SetOrderStore(store *orders.Store) {
+	f.orderStore = store
+}
+
+// SetTxAuditLog enables the audit log of outbound transactions; it must be called before the solver sends any
+func (f *Hyperlane7683Solver) SetTxAuditLog(log *TxAuditLog) {
+	f.txAudit = log
+}
+
+// SetTxOutbox enables exactly-once fills and settlements through outbox; it must be called before
+// the solver fills or settles
+func (f *Hyperlane7683Solver) SetTxOutbox(outbox *TxOutbox) {
+	f.txOutbox = outbox
+}
+
+// SetTokenDiscovery enables on-chain metadata discovery for tokens missing from the registry
+func (f *Hyperlane7683Solver) SetTokenDiscovery(discovery *TokenDiscovery) {
+	f.tokenDiscovery = discovery
+}
+
+// TrackCompetition skips orders the tracker saw filled by another solver, and lets it tell
+// the solver's own fills apart using the order store
+func (f *Hyperlane7683Solver) TrackCompetition(tracker *CompetitionTracker) {
+	f.competition = tracker
+	tracker.SetOwnFillCheck(f.filledInTx)
+}
+
+// ScheduleSettlements hands filled orders to scheduler instead of settling them inline
+func (f *Hyperlane7683Solver) ScheduleSettlements(scheduler *SettleScheduler) {
+	f.settler = scheduler
+}
+
+// TrackSettlements lets the tracker record origin-side Settled/Refunded events in the solver's order store
+func (f *Hyperlane7683Solver) TrackSettlements(tracker *SettlementTracker) {
+	tracker.SetOrderStore(f.orderStore)
+}
+
 func (f *Hyperlane7683Solver) ProcessIntent(ctx context.Context, args *types.ParsedArgs) (bool, error) {
 	// Log the cross-chain operation
 	logutil.LogOrderProcessing(args, "Processing Order")
+	if f.tokenDiscovery != nil {
+		f.tokenDiscovery.DiscoverOrderTokens(ctx, args)
+	}
+	logutil.LogOrderAmounts(args)
+	f.observeOrder(args)
+
+	// Drop orders a competing solver already filled while they waited to be processed
+	if fill, ok := f.competition.FilledByCompetitor(args.OrderID); ok {
+		fillerLog.Printf("🏁 Order %s already filled by another solver on %s (tx %s), skipping\n", args.OrderID, fill.ChainName, fill.TxHash)
+		logutil.LogOperationComplete(args, "Order processing", false)
+		f.recordOrderStatus(args, orders.StatusLostRace, "filled by another solver first")
+		return false, nil
+	}
+
+	// Filling its own orders would only move the solver's funds back; they are left to other fillers
+	if isOwnOrder(args) {
+		logutil.LogOperationComplete(args, "Order processing", false)
+		f.rejectOrder(args, RejectOwnOrder, "opened by this solver")
+		return false, nil
+	}
 
 	// Check allow/block lists first
 	if !f.isAllowedIntent(args) {
 		logutil.LogOperationComplete(args, "Order processing", false)
+		f.rejectOrder(args, RejectBlocked, "blocked by allow/block lists")
 		return false, fmt.Errorf("order blocked by allow/block lists")
 	}
 
+	// Refuse orders toward networks whose fills are paused by the operator
+	if err := checkFillsEnabled(args); err != nil {
+		logutil.LogOperationComplete(args, "Order processing", false)
+		f.rejectOrder(args, RejectFillsPaused, err.Error())
+		return false, err
+	}
+
 	// Run validation rules before processing
-	rulesEngine := NewRulesEngine()
+	rulesEngine := NewRulesEngineWithClients(f.getEVMClient, f.getStarknetClient)
+	for _, rule := range f.customRules {
+		rulesEngine.AddRule(rule)
+	}
 	if result := rulesEngine.EvaluateAll(ctx, args); !result.Passed {
 		logutil.LogOperationComplete(args, "Order validation", false)
+		f.rejectOrder(args, result.Code, result.Reason)
 		return false, fmt.Errorf("order validation failed: %s", result.Reason)
 	}
 
+	// Shadow runs stop here: the order is recorded as one the solver would fill
+	if f.observerMode {
+		logutil.LogOperationComplete(args, "Order processing", false)
+		f.recordWouldFill(args)
+		return false, nil
+	}
+
 	// Fill method handles its own status checks efficiently (skip if already filled)
 	action, err := f.Fill(ctx, args)
 	if err != nil {
 		logutil.LogOperationComplete(args, "Fill execution", false)
+		f.recordOrderStatus(args, orders.StatusFailed, err.Error())
 		return false, fmt.Errorf("fill execution failed: %w", err)
 	}
 
-	// Check if order is already complete (filled + settled)
+	// A competing solver filled the order first; nothing left for us to do
+	if action == OrderActionLostRace {
+		logutil.LogOperationComplete(args, "Order processing", false)
+		f.recordOrderStatus(args, orders.StatusLostRace, "filled by another solver first")
+		return false, nil
+	}
+
+	// Check if order is complete (filled + settled), either previously or by a fill+settle multicall
 	if action == OrderActionComplete {
-		fmt.Printf("✅ Order already complete (filled + settled), nothing to do\n")
+		fillerLog.Printf("✅ Order complete (filled + settled), nothing left to do\n")
+		logutil.LogOperationComplete(args, "Order processing", true)
+		f.recordOrderStatus(args, orders.StatusSettled, "")
 		return true, nil
 	}
 
 	// If fill returned OrderActionSettle, we need to settle the order
 	if action == OrderActionSettle {
+		f.recordOrderStatus(args, orders.StatusFilled, "")
+
+		// The settle worker takes it from here; new fills don't wait for the settlement
+		if f.settler != nil {
+			f.settler.Schedule(args)
+			logutil.LogOperationComplete(args, "Order processing", true)
+			return true, nil
+		}
+
 		// Add a small delay to ensure fill transaction is processed before settling
-		time.Sleep(2 * time.Second)
+		time.Sleep(fillSettleDelay)
 
 		// Settle the order
 		if err := f.SettleOrder(ctx, args); err != nil {
 			logutil.LogOperationComplete(args, "Order settlement", false)
+			f.recordOrderStatus(args, orders.StatusFilled, "settlement failed: "+err.Error())
 			return false, fmt.Errorf("order settlement failed: %w", err)
 		}
+		f.recordOrderStatus(args, orders.StatusSettled, "")
 	}
 
-	// Only return true when settle completes successfully
+	// Only return true when the order is settled or handed to the settle worker
 	logutil.LogOperationComplete(args, "Order processing", true)
 	return true, nil
 }
@@ -122,13 +286,16 @@ func (f *Hyperlane7683Solver) Fill(ctx context.Context, args *types.ParsedArgs)
 		return OrderActionError, fmt.Errorf("no fill instructions found")
 	}
 
-	// Process all fill instructions (supports both single and multiple instructions)
+	// Fill every leg before settling anything: an order is only settled once all its outputs are delivered.
+	// Legs filled before a failure are reported FILLED by their router on retry and are not filled twice.
+	needsSettlement := false
 	for i, instruction := range args.ResolvedOrder.FillInstructions {
 		logutil.LogWithNetworkTagf("", "Processing fill instruction %d/%d for chain %s",
 			i+1, len(args.ResolvedOrder.FillInstructions), instruction.DestinationChainID.String())
 
-		action, err := f.executeChainOperation(ctx, args, instruction.DestinationChainID, "fill", func(handler ChainHandler) (OrderAction, error) {
-			return handler.Fill(ctx, args)
+		leg := legArgs(args, i)
+		action, err := f.executeChainOperation(ctx, leg, instruction.DestinationChainID, "fill", func(handler ChainHandler) (OrderAction, error) {
+			return handler.Fill(ctx, leg)
 		})
 		if err != nil {
 			return OrderActionError, fmt.Errorf("fill instruction %d failed: %w", i+1, err)
@@ -139,19 +306,25 @@ func (f *Hyperlane7683Solver) Fill(ctx context.Context, args *types.ParsedArgs)
 			return OrderActionError, fmt.Errorf("fill instruction %d returned error", i+1)
 		}
 
-		// If this instruction needs settlement, return that action
+		if action == OrderActionLostRace {
+			return OrderActionLostRace, nil
+		}
+
 		if action == OrderActionSettle {
 			logutil.LogWithNetworkTagf("", "Fill instruction %d completed, needs settlement", i+1)
-			return OrderActionSettle, nil
+			needsSettlement = true
+			continue
 		}
 
-		// If this instruction completed successfully, continue to next
-		// (In most cases there will only be one instruction, but this supports multiple)
 		if action == OrderActionComplete {
 			logutil.LogWithNetworkTagf("", "Fill instruction %d completed successfully", i+1)
 		}
 	}
 
+	if needsSettlement {
+		return OrderActionSettle, nil
+	}
+
 	// All instructions processed successfully
 	return OrderActionComplete, nil
 }
@@ -166,11 +339,18 @@ func (f *Hyperlane7683Solver) SettleOrder(ctx context.Context, args *types.Parse
 
 	// Process all settlement instructions (supports both single and multiple instructions)
 	for i, instruction := range args.ResolvedOrder.FillInstructions {
+		leg := legArgs(args, i)
+
 		logutil.LogWithNetworkTagf("", "Processing settlement instruction %d/%d for chain %s",
 			i+1, len(args.ResolvedOrder.FillInstructions), instruction.DestinationChainID.String())
 
-		_, err := f.executeChainOperation(ctx, args, instruction.DestinationChainID, "settle", func(handler ChainHandler) (OrderAction, error) {
-			err := handler.Settle(ctx, args)
+		// The settlement message travels from the fill chain back to the origin
+		if err := f.routeHealth.BeforeSettle(ctx, instruction.DestinationChainID.Uint64(), args.ResolvedOrder.OriginChainID.Uint64()); err != nil {
+			return fmt.Errorf("settlement instruction %d: %w", i+1, err)
+		}
+
+		_, err := f.executeChainOperation(ctx, leg, instruction.DestinationChainID, "settle", func(handler ChainHandler) (OrderAction, error) {
+			err := handler.Settle(ctx, leg)
 			return OrderActionComplete, err // Return OrderActionComplete for successful settlement
 		})
 		if err != nil {
@@ -193,37 +373,142 @@ func (f *Hyperlane7683Solver) executeChainOperation(
 	operation string,
 	operationFunc func(ChainHandler) (OrderAction, error),
 ) (OrderAction, error) {
-	var handler ChainHandler
-	var err error
-	var chainType string
+	if f.observerMode {
+		return OrderActionError, fmt.Errorf("%s on chain %s: %w", operation, chainID.String(), ErrObserverMode)
+	}
+
+	handler, chainType, err := f.handlerForChain(chainID)
+	if err != nil {
+		return OrderActionError, err
+	}
+
+	// Skip chains whose circuit is open instead of piling more failures onto them
+	breaker := f.circuitBreaker()
+	if err := breaker.Allow(chainID.Uint64()); err != nil {
+		return OrderActionError, fmt.Errorf("%s %s paused for chain %s: %w", chainType, operation, chainID.String(), err)
+	}
+
+	// Execute the operation
+	action, err := operationFunc(handler)
+	if err != nil {
+		// A transaction still in flight or a refused order is not a failure of the chain, nor a
+		// sign it recovered
+		if !isChainFailure(err) {
+			breaker.ReleaseProbe(chainID.Uint64())
+		} else {
+			breaker.RecordFailure(chainID.Uint64(), err)
+		}
+		return OrderActionError, fmt.Errorf("%s %s failed for chain %s: %w", chainType, operation, chainID.String(), err)
+	}
+	breaker.RecordSuccess(chainID.Uint64())
 
-	// Chain detection and handler retrieval
+	return action, nil
+}
+
+// handlerForChain detects the chain type and returns its handler
+func (f *Hyperlane7683Solver) handlerForChain(chainID *big.Int) (ChainHandler, string, error) {
 	switch {
 	case f.isStarknetChain(chainID):
-		chainType = "Starknet"
-		handler, err = f.getStarknetHandler(chainID)
+		handler, err := f.getStarknetHandler(chainID)
 		if err != nil {
-			return OrderActionError, fmt.Errorf("failed to get %s handler for chain %s: %w", chainType, chainID.String(), err)
+			return nil, "Starknet", fmt.Errorf("failed to get Starknet handler for chain %s: %w", chainID.String(), err)
 		}
+		return handler, "Starknet", nil
 
 	case f.isEVMChain(chainID):
-		chainType = "EVM"
-		handler, err = f.getEVMHandler(chainID)
+		handler, err := f.getEVMHandler(chainID)
 		if err != nil {
-			return OrderActionError, fmt.Errorf("failed to get %s handler for chain %s: %w", chainType, chainID.String(), err)
+			return nil, "EVM", fmt.Errorf("failed to get EVM handler for chain %s: %w", chainID.String(), err)
 		}
+		return handler, "EVM", nil
 
 	default:
-		return OrderActionError, fmt.Errorf("unsupported destination chain: %s", chainID.String())
+		return nil, "", fmt.Errorf("unsupported destination chain: %s", chainID.String())
 	}
+}
 
-	// Execute the operation
-	action, err := operationFunc(handler)
-	if err != nil {
-		return OrderActionError, fmt.Errorf("%s %s failed for chain %s: %w", chainType, operation, chainID.String(), err)
+// observeOrder adds the order to the store and publishes OrderObserved the first time it is seen
+func (f *Hyperlane7683Solver) observeOrder(args *types.ParsedArgs) {
+	if f.orderStore == nil {
+		f.publishOrderEvent(events.OrderObserved, args.OrderID, args, "")
+		return
+	}
+	_, seen := f.orderStore.Get(args.OrderID)
+	if err := f.orderStore.Observe(args); err != nil {
+		fillerLog.Printf("⚠️  Failed to record order %s: %v\n", args.OrderID, err)
+		return
+	}
+	if !seen {
+		f.publishOrderEvent(events.OrderObserved, args.OrderID, args, "")
 	}
+}
 
-	return action, nil
+// recordOrderStatus updates the order's local status; store errors never fail processing
+func (f *Hyperlane7683Solver) recordOrderStatus(args *types.ParsedArgs, status orders.Status, reason string) {
+	if f.orderStore == nil {
+		f.publishStatusChange(args.OrderID, args, "", status, "")
+		return
+	}
+	previous, _ := f.orderStore.Get(args.OrderID)
+	if err := f.orderStore.SetStatus(args.OrderID, status, reason); err != nil {
+		fillerLog.Printf("⚠️  Failed to record order %s as %s: %v\n", args.OrderID, status, err)
+		return
+	}
+	f.publishStatusChange(args.OrderID, args, previous.Status, status, "")
+}
+
+// filledByUs reports whether the solver sent the fill of an order, according to the order store.
+// Without a store every fill is assumed to be ours.
+func (f *Hyperlane7683Solver) filledByUs(orderID string) bool {
+	if f.orderStore == nil {
+		return true
+	}
+	order, ok := f.orderStore.Get(orderID)
+	return ok && order.FilledByUs()
+}
+
+// filledInTx reports whether txHash is a fill the solver sent for the order, according to the order store
+func (f *Hyperlane7683Solver) filledInTx(orderID, txHash string) bool {
+	if f.orderStore == nil {
+		return false
+	}
+	order, ok := f.orderStore.Get(orderID)
+	return ok && order.FilledInTx(txHash)
+}
+
+// recordTxCost stores the cost of a transaction sent for the order and measures fills (see fill_metrics.go)
+func (f *Hyperlane7683Solver) recordTxCost(args *types.ParsedArgs, cost orders.TxCost) {
+	// Approvals sent ahead of any order (see approvals.go) have no order to charge
+	if args == nil {
+		return
+	}
+	if cost.Kind == orders.TxKindFill || cost.Kind == orders.TxKindFillSettle {
+		f.recordFillMetrics(args, time.Now())
+	}
+	if f.orderStore == nil {
+		return
+	}
+	if err := f.orderStore.AddCost(args.OrderID, cost); err != nil {
+		fillerLog.Printf("⚠️  Failed to record %s cost for order %s: %v\n", cost.Kind, args.OrderID, err)
+	}
+}
+
+// recordTxSent stores the hash of a fill or settle broadcast for the order
+func (f *Hyperlane7683Solver) recordTxSent(orderID string, tx orders.OrderTx) {
+	if f.orderStore == nil {
+		return
+	}
+	if err := f.orderStore.RecordTx(orderID, tx); err != nil {
+		fillerLog.Printf("⚠️  Failed to record %s tx %s for order %s: %v\n", tx.Kind, tx.TxHash, orderID, err)
+	}
+}
+
+// circuitBreaker returns the solver's breaker, falling back to the shared one
+func (f *Hyperlane7683Solver) circuitBreaker() *CircuitBreaker {
+	if f.breaker == nil {
+		return DefaultCircuitBreaker()
+	}
+	return f.breaker
 }
 
 // getEVMHandler gets or creates an EVM chain handler for the given chain ID
@@ -257,16 +542,34 @@ func (f *Hyperlane7683Solver) getEVMHandler(chainID *big.Int) (ChainHandler, err
 		return nil, fmt.Errorf("failed to get EVM signer for chain %d: %w", chainIDUint, err)
 	}
 
+	replace, err := loadEVMReplaceConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid EVM tx replacement configuration: %w", err)
+	}
+
 	handler := NewHyperlaneEVM(client, signer, chainIDUint)
+	handler.replace = replace
+	handler.recordCost = f.recordTxCost
+	handler.recordSent = f.recordTxSent
+	handler.fillOwnership = f.filledByUs
+	handler.txAudit = f.txAudit
+	handler.txOutbox = f.txOutbox
+	handler.approvalPolicy = f.approvalPolicy
+	handler.signPermit = permitSignerFromEnv(signer.From)
 	f.evmHandlers[chainIDUint] = handler
 	return handler, nil
 }
 
 // getStarknetHandler gets or creates a Starknet chain handler for the given chain ID
 func (f *Hyperlane7683Solver) getStarknetHandler(chainID *big.Int) (ChainHandler, error) {
+	chainIDUint := chainID.Uint64()
+
+	f.starknetHandlersMux.Lock()
+	defer f.starknetHandlersMux.Unlock()
+
 	// Reuse existing handler if available
-	if f.hyperlaneStarknet != nil {
-		return f.hyperlaneStarknet, nil
+	if handler, exists := f.starknetHandlers[chainIDUint]; exists {
+		return handler, nil
 	}
 
 	// Create new Starknet handler
@@ -275,8 +578,30 @@ func (f *Hyperlane7683Solver) getStarknetHandler(chainID *big.Int) (ChainHandler
 		return nil, fmt.Errorf("starknet network not found for chain ID %s: %w", chainID.String(), err)
 	}
 
-	f.hyperlaneStarknet = NewHyperlaneStarknet(chainConfig.RPCURL, chainConfig.ChainID)
-	return f.hyperlaneStarknet, nil
+	// Share the manager's provider when there is one; otherwise dial the configured RPC
+	var handler *HyperlaneStarknet
+	if f.getStarknetClient != nil {
+		provider, err := f.getStarknetClient(chainIDUint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Starknet client for chain %d: %w", chainIDUint, err)
+		}
+		handler = newHyperlaneStarknetWithProvider(provider, chainConfig.ChainID)
+	} else {
+		handler = NewHyperlaneStarknet(chainConfig.RPCURL, chainConfig.ChainID)
+	}
+	if handler == nil {
+		return nil, fmt.Errorf("failed to create Starknet handler for chain ID %s", chainID.String())
+	}
+	handler.recordCost = f.recordTxCost
+	handler.recordSent = f.recordTxSent
+	handler.txAudit = f.txAudit
+	handler.txOutbox = f.txOutbox
+	handler.approvalPolicy = f.approvalPolicy
+	if f.starknetHandlers == nil {
+		f.starknetHandlers = make(map[uint64]ChainHandler)
+	}
+	f.starknetHandlers[chainIDUint] = handler
+	return handler, nil
 }
 
 // AddDefaultRules adds standard validation rules to the solver
@@ -285,6 +610,12 @@ func (f *Hyperlane7683Solver) AddDefaultRules() {
 	// For now, validation happens within the chain handlers themselves
 }
 
+// AddRule adds a custom rule evaluated after the default rules; it must be called before the
+// solver processes orders
+func (f *Hyperlane7683Solver) AddRule(rule Rule) {
+	f.customRules = append(f.customRules, rule)
+}
+
 // Simple chain identification helpers - works with any Starknet/EVM network names
 func (f *Hyperlane7683Solver) isStarknetChain(chainID *big.Int) bool {
 	// Ensure config is initialized to prevent segfault
@@ -294,7 +625,7 @@ func (f *Hyperlane7683Solver) isStarknetChain(chainID *big.Int) bool {
 	for networkName, network := range config.Networks {
 		if network.ChainID == chainID.Uint64() {
 			// Check if network name contains "Starknet" (case insensitive)
-			return strings.Contains(strings.ToLower(networkName), "starknet")
+			return config.IsStarknetNetwork(networkName)
 		}
 	}
 	return false
@@ -308,28 +639,66 @@ func (f *Hyperlane7683Solver) isEVMChain(chainID *big.Int) bool {
 	for networkName, network := range config.Networks {
 		if network.ChainID == chainID.Uint64() {
 			// If it's not Starknet, it's EVM
-			return !strings.Contains(strings.ToLower(networkName), "starknet")
+			return !config.IsStarknetNetwork(networkName)
 		}
 	}
 	return false
 }
 
+// legArgs returns the view of an order that chain handlers work on for fill instruction i: handlers
+// act on the first fill instruction and the MaxSpent outputs, so the copy keeps only that instruction
+// and the outputs paid on its chain. Single-leg orders are returned unchanged.
+func legArgs(args *types.ParsedArgs, i int) *types.ParsedArgs {
+	instructions := args.ResolvedOrder.FillInstructions
+	if len(instructions) <= 1 {
+		return args
+	}
+
+	leg := *args
+	leg.ResolvedOrder.FillInstructions = []types.FillInstruction{instructions[i]}
+	leg.ResolvedOrder.MaxSpent = nil
+	for _, maxSpent := range args.ResolvedOrder.MaxSpent {
+		// Outputs without a chain belong to the first leg, as in the rules
+		if outputChainID(maxSpent, instructions[0].DestinationChainID) == instructions[i].DestinationChainID.Uint64() {
+			leg.ResolvedOrder.MaxSpent = append(leg.ResolvedOrder.MaxSpent, maxSpent)
+		}
+	}
+	return &leg
+}
+
+// checkFillsEnabled returns an error when a fill instruction targets a paused network
+func checkFillsEnabled(args *types.ParsedArgs) error {
+	for _, instruction := range args.ResolvedOrder.FillInstructions {
+		if instruction.DestinationChainID == nil {
+			continue
+		}
+		if !config.FillsAllowedByChainID(instruction.DestinationChainID.Uint64()) {
+			return fmt.Errorf("fills paused for chain %s", instruction.DestinationChainID.String())
+		}
+	}
+	return nil
+}
+
 // isAllowedIntent checks if an intent is allowed based on allow/block lists
 func (f *Hyperlane7683Solver) isAllowedIntent(args *types.ParsedArgs) bool {
+	f.allowBlockListsMu.RLock()
+	lists := f.allowBlockLists
+	f.allowBlockListsMu.RUnlock()
+
 	// Check block list first
-	for _, blockItem := range f.allowBlockLists.BlockList {
+	for _, blockItem := range lists.BlockList {
 		if f.matchesAllowBlockItem(blockItem, args) {
 			return false
 		}
 	}
 
 	// If no allow list is specified, allow everything
-	if len(f.allowBlockLists.AllowList) == 0 {
+	if len(lists.AllowList) == 0 {
 		return true
 	}
 
 	// Check allow list
-	for _, allowItem := range f.allowBlockLists.AllowList {
+	for _, allowItem := range lists.AllowList {
 		if f.matchesAllowBlockItem(allowItem, args) {
 			return true
 		}
@@ -377,4 +746,3 @@ func (f *Hyperlane7683Solver) getNetworkConfigByChainID(chainID *big.Int) (confi
 	}
 	return config.NetworkConfig{}, fmt.Errorf("network config not found for chain ID %d", chainIDUint)
 }
-
@@ -1,13 +1,31 @@
 package hyperlane7683
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/NethermindEth/starknet.go/account"
 	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -16,16 +34,16 @@ import (
 func TestHyperlane7683Solver(t *testing.T) {
 	t.Run("NewHyperlane7683Solver", func(t *testing.T) {
 		// Create mock functions
-		getEVMClient := func(chainID uint64) (*ethclient.Client, error) {
+		getEVMClient := func(chainID uint64) (EVMClient, error) {
 			return nil, nil
 		}
-		getStarknetClient := func() (*rpc.Provider, error) {
+		getStarknetClient := func(chainID uint64) (rpc.RPCProvider, error) {
 			return nil, nil
 		}
 		getEVMSigner := func(chainID uint64) (*bind.TransactOpts, error) {
 			return nil, nil
 		}
-		getStarknetSigner := func() (*account.Account, error) {
+		getStarknetSigner := func(chainID uint64) (*account.Account, error) {
 			return nil, nil
 		}
 
@@ -69,13 +87,14 @@ func TestOrderAction(t *testing.T) {
 		assert.Equal(t, OrderAction(0), OrderActionSettle)
 		assert.Equal(t, OrderAction(1), OrderActionComplete)
 		assert.Equal(t, OrderAction(2), OrderActionError)
+		assert.Equal(t, OrderAction(3), OrderActionLostRace)
 	})
 
 	t.Run("OrderAction_string", func(t *testing.T) {
 		// Test that we can convert to string (useful for logging)
-		actions := []OrderAction{OrderActionSettle, OrderActionComplete, OrderActionError}
+		actions := []OrderAction{OrderActionSettle, OrderActionComplete, OrderActionError, OrderActionLostRace}
 		for _, action := range actions {
-			assert.True(t, int(action) >= 0 && int(action) <= 2)
+			assert.True(t, int(action) >= 0 && int(action) <= 3)
 		}
 	})
 }
@@ -130,6 +149,22 @@ func TestSolverInitialization(t *testing.T) {
 }
 
 // TestSolverConcurrency tests basic concurrency safety
+func TestSetAllowBlockLists(t *testing.T) {
+	args := endToEndArgs("0x01", "0x00000000000000000000000000000000000000a1", "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+	args.SenderAddress = "0x00000000000000000000000000000000000000cc"
+	args.Recipients = []types.Recipient{{DestinationChainName: "Base", RecipientAddress: "*"}}
+	solver := NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{})
+	assert.True(t, solver.isAllowedIntent(&args))
+
+	solver.SetAllowBlockLists(types.AllowBlockLists{
+		BlockList: []types.AllowBlockListItem{{SenderAddress: args.SenderAddress, DestinationDomain: "*", RecipientAddress: "*"}},
+	})
+	assert.False(t, solver.isAllowedIntent(&args))
+
+	solver.SetAllowBlockLists(types.AllowBlockLists{})
+	assert.True(t, solver.isAllowedIntent(&args))
+}
+
 func TestSolverConcurrency(t *testing.T) {
 	t.Run("concurrent_solver_creation", func(t *testing.T) {
 		allowBlockLists := types.AllowBlockLists{
@@ -163,3 +198,1010 @@ func TestSolverConcurrency(t *testing.T) {
 		}
 	})
 }
+
+// TestProcessIntentEndToEnd runs ProcessIntent against in-memory chains
+func TestProcessIntentEndToEnd(t *testing.T) {
+	orderID := "0x1111111111111111111111111111111111111111111111111111111111111111"
+
+	t.Run("EVM destination approves, fills and settles", func(t *testing.T) {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		signer, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(config.BaseSepoliaChainID))
+		require.NoError(t, err)
+		t.Setenv("SOLVER_PUB_KEY", signer.From.Hex())
+		t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+		settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+
+		var filled atomic.Bool
+		backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(10_000)))
+		backend.HandleCall(token, chainmock.Selector("allowance(address,address)"), uint256Result(big.NewInt(0)))
+		backend.HandleCall(settler, chainmock.Selector("orderStatus(bytes32)"), func(ethereum.CallMsg) ([]byte, error) {
+			if filled.Load() {
+				return common.RightPadBytes([]byte(orderStatusFilled), 32), nil
+			}
+			return make([]byte, 32), nil
+		})
+		backend.HandleCall(settler, chainmock.Selector("quoteGasPayment(uint32)"), uint256Result(big.NewInt(7)))
+		fillSelector := chainmock.Selector("fill(bytes32,bytes,bytes)")
+		backend.OnTransaction(settler, func(tx *gethtypes.Transaction) uint64 {
+			if bytes.HasPrefix(tx.Data(), fillSelector[:]) {
+				filled.Store(true)
+			}
+			return gethtypes.ReceiptStatusSuccessful
+		})
+
+		solver := NewHyperlane7683Solver(
+			func(uint64) (EVMClient, error) { return backend, nil },
+			nil,
+			func(uint64) (*bind.TransactOpts, error) { return signer, nil },
+			nil,
+			types.AllowBlockLists{},
+		)
+
+		args := endToEndArgs(orderID, token.Hex(), settler.Hex(), config.BaseSepoliaChainID)
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		require.Len(t, backend.SentTo(token), 1, "approve")
+		settlerTxs := backend.SentTo(settler)
+		require.Len(t, settlerTxs, 2, "fill and settle")
+		assert.True(t, bytes.HasPrefix(settlerTxs[0].Data(), fillSelector[:]))
+		assert.Equal(t, big.NewInt(7), settlerTxs[1].Value(), "settle pays the quoted gas")
+	})
+
+	t.Run("EVM fill hands the settlement to the scheduler", func(t *testing.T) {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		signer, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(config.BaseSepoliaChainID))
+		require.NoError(t, err)
+		t.Setenv("SOLVER_PUB_KEY", signer.From.Hex())
+		t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+		settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		var filled atomic.Bool
+		backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(10_000)))
+		backend.HandleCall(token, chainmock.Selector("allowance(address,address)"), uint256Result(big.NewInt(10_000)))
+		backend.HandleCall(settler, chainmock.Selector("orderStatus(bytes32)"), func(ethereum.CallMsg) ([]byte, error) {
+			if filled.Load() {
+				return common.RightPadBytes([]byte(orderStatusFilled), 32), nil
+			}
+			return make([]byte, 32), nil
+		})
+		backend.HandleCall(settler, chainmock.Selector("quoteGasPayment(uint32)"), uint256Result(big.NewInt(7)))
+		backend.OnTransaction(settler, func(*gethtypes.Transaction) uint64 {
+			filled.Store(true)
+			return gethtypes.ReceiptStatusSuccessful
+		})
+
+		store, err := orders.NewStore(filepath.Join(t.TempDir(), "orders.json"))
+		require.NoError(t, err)
+		solver := NewHyperlane7683Solver(
+			func(uint64) (EVMClient, error) { return backend, nil },
+			nil,
+			func(uint64) (*bind.TransactOpts, error) { return signer, nil },
+			nil,
+			types.AllowBlockLists{},
+		)
+		solver.SetOrderStore(store)
+		scheduler := NewSettleScheduler(solver, store)
+		scheduler.delay = 0
+		solver.ScheduleSettlements(scheduler)
+
+		args := endToEndArgs(orderID, token.Hex(), settler.Hex(), config.BaseSepoliaChainID)
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Len(t, backend.SentTo(settler), 1, "only the fill is sent inline")
+		assert.Equal(t, 1, scheduler.Len())
+		order, _ := store.Get(orderID)
+		assert.Equal(t, orders.StatusFilled, order.Status)
+
+		assert.Equal(t, 1, scheduler.RunDue(context.Background()))
+		require.Len(t, backend.SentTo(settler), 2, "fill and settle")
+		order, _ = store.Get(orderID)
+		assert.Equal(t, orders.StatusSettled, order.Status)
+	})
+
+	t.Run("EVM settle is not sent again after an interrupted wait", func(t *testing.T) {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		signer, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(config.BaseSepoliaChainID))
+		require.NoError(t, err)
+		t.Setenv("SOLVER_PUB_KEY", signer.From.Hex())
+		t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+		settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		var filled atomic.Bool
+		backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(10_000)))
+		backend.HandleCall(token, chainmock.Selector("allowance(address,address)"), uint256Result(big.NewInt(10_000)))
+		// The router keeps reporting FILLED, as a lagging node would after the settle
+		backend.HandleCall(settler, chainmock.Selector("orderStatus(bytes32)"), func(ethereum.CallMsg) ([]byte, error) {
+			if filled.Load() {
+				return common.RightPadBytes([]byte(orderStatusFilled), 32), nil
+			}
+			return make([]byte, 32), nil
+		})
+		backend.HandleCall(settler, chainmock.Selector("quoteGasPayment(uint32)"), uint256Result(big.NewInt(7)))
+
+		settleCtx, interrupt := context.WithCancel(context.Background())
+		settleSelector := chainmock.Selector("settle(bytes32[])")
+		backend.OnTransaction(settler, func(tx *gethtypes.Transaction) uint64 {
+			if bytes.HasPrefix(tx.Data(), settleSelector[:]) {
+				// The process stops while waiting for the receipt
+				backend.FailNext("TransactionReceipt", errors.New("connection reset"))
+				interrupt()
+			}
+			filled.Store(true)
+			return gethtypes.ReceiptStatusSuccessful
+		})
+
+		store, err := orders.NewStore(filepath.Join(t.TempDir(), "orders.json"))
+		require.NoError(t, err)
+		outbox, err := OpenTxOutbox(storage.NewFileStorage(t.TempDir(), nil))
+		require.NoError(t, err)
+		solver := NewHyperlane7683Solver(
+			func(uint64) (EVMClient, error) { return backend, nil },
+			nil,
+			func(uint64) (*bind.TransactOpts, error) { return signer, nil },
+			nil,
+			types.AllowBlockLists{},
+		)
+		solver.SetOrderStore(store)
+		solver.SetTxOutbox(outbox)
+		scheduler := NewSettleScheduler(solver, store)
+		solver.ScheduleSettlements(scheduler)
+
+		args := endToEndArgs(orderID, token.Hex(), settler.Hex(), config.BaseSepoliaChainID)
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		assert.Error(t, solver.SettleOrder(settleCtx, &args))
+		require.Len(t, backend.SentTo(settler), 2, "fill and settle")
+		entry, ok := outbox.Get(orders.TxKindSettle, config.BaseSepoliaChainID, orderID)
+		require.True(t, ok, "the settle stays in the outbox without a receipt")
+		assert.Equal(t, []string{backend.SentTo(settler)[1].Hash().Hex()}, entry.TxHashes)
+
+		require.NoError(t, solver.SettleOrder(context.Background(), &args))
+		assert.Len(t, backend.SentTo(settler), 2, "the included settle is not sent again")
+		assert.Empty(t, outbox.List())
+	})
+
+	t.Run("EVM fill is not sent again after a restart", func(t *testing.T) {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		signer, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(config.BaseSepoliaChainID))
+		require.NoError(t, err)
+		t.Setenv("SOLVER_PUB_KEY", signer.From.Hex())
+		t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+		settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		var filled atomic.Bool
+		backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(10_000)))
+		backend.HandleCall(token, chainmock.Selector("allowance(address,address)"), uint256Result(big.NewInt(10_000)))
+		backend.HandleCall(settler, chainmock.Selector("orderStatus(bytes32)"), func(ethereum.CallMsg) ([]byte, error) {
+			if filled.Load() {
+				return common.RightPadBytes([]byte(orderStatusFilled), 32), nil
+			}
+			return make([]byte, 32), nil
+		})
+
+		fillCtx, interrupt := context.WithCancel(context.Background())
+		backend.OnTransaction(settler, func(*gethtypes.Transaction) uint64 {
+			// The process stops while waiting for the receipt
+			backend.FailNext("TransactionReceipt", errors.New("connection reset"))
+			interrupt()
+			filled.Store(true)
+			return gethtypes.ReceiptStatusSuccessful
+		})
+
+		storeDir, outboxDir := t.TempDir(), t.TempDir()
+		// start opens the order store and outbox as a (re)started solver does
+		start := func() (*Hyperlane7683Solver, *orders.Store, *SettleScheduler) {
+			store, err := orders.NewStore(filepath.Join(storeDir, "orders.json"))
+			require.NoError(t, err)
+			outbox, err := OpenTxOutbox(storage.NewFileStorage(outboxDir, nil))
+			require.NoError(t, err)
+			solver := NewHyperlane7683Solver(
+				func(uint64) (EVMClient, error) { return backend, nil },
+				nil,
+				func(uint64) (*bind.TransactOpts, error) { return signer, nil },
+				nil,
+				types.AllowBlockLists{},
+			)
+			solver.SetOrderStore(store)
+			solver.SetTxOutbox(outbox)
+			solver.RecoverTxOutbox(context.Background())
+			scheduler := NewSettleScheduler(solver, store)
+			solver.ScheduleSettlements(scheduler)
+			return solver, store, scheduler
+		}
+
+		solver, _, _ := start()
+		args := endToEndArgs(orderID, token.Hex(), settler.Hex(), config.BaseSepoliaChainID)
+		_, err = solver.ProcessIntent(fillCtx, &args)
+		assert.Error(t, err)
+		require.Len(t, backend.SentTo(settler), 1)
+
+		solver, store, scheduler := start()
+		order, _ := store.Get(orderID)
+		assert.Equal(t, orders.StatusFilled, order.Status, "the recovery finds the included fill")
+		assert.True(t, order.FilledInTx(backend.SentTo(settler)[0].Hash().Hex()))
+
+		// The replayed Open event goes on to the settlement
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Len(t, backend.SentTo(settler), 1, "the included fill is not sent again")
+		assert.Equal(t, 1, scheduler.Len())
+	})
+
+	t.Run("EVM native output is paid with the fill", func(t *testing.T) {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		signer, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(config.BaseSepoliaChainID))
+		require.NoError(t, err)
+		t.Setenv("SOLVER_PUB_KEY", signer.From.Hex())
+		t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		backend.SetBalance(signer.From, big.NewInt(10_000))
+		var filled atomic.Bool
+		backend.HandleCall(settler, chainmock.Selector("orderStatus(bytes32)"), func(ethereum.CallMsg) ([]byte, error) {
+			if filled.Load() {
+				return common.RightPadBytes([]byte(orderStatusFilled), 32), nil
+			}
+			return make([]byte, 32), nil
+		})
+		backend.HandleCall(settler, chainmock.Selector("quoteGasPayment(uint32)"), uint256Result(big.NewInt(7)))
+		fillSelector := chainmock.Selector("fill(bytes32,bytes,bytes)")
+		backend.OnTransaction(settler, func(tx *gethtypes.Transaction) uint64 {
+			if bytes.HasPrefix(tx.Data(), fillSelector[:]) {
+				filled.Store(true)
+			}
+			return gethtypes.ReceiptStatusSuccessful
+		})
+
+		solver := NewHyperlane7683Solver(
+			func(uint64) (EVMClient, error) { return backend, nil },
+			nil,
+			func(uint64) (*bind.TransactOpts, error) { return signer, nil },
+			nil,
+			types.AllowBlockLists{},
+		)
+
+		// The listener decodes address(0) as a zero bytes32
+		native := "0x" + strings.Repeat("0", 64)
+		args := endToEndArgs(orderID, native, settler.Hex(), config.BaseSepoliaChainID)
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		assert.Len(t, backend.Sent(), 2, "no approval, only fill and settle")
+		settlerTxs := backend.SentTo(settler)
+		require.Len(t, settlerTxs, 2)
+		assert.True(t, bytes.HasPrefix(settlerTxs[0].Data(), fillSelector[:]))
+		assert.Equal(t, big.NewInt(1000), settlerTxs[0].Value(), "fill carries the native amount")
+		assert.Equal(t, big.NewInt(7), settlerTxs[1].Value(), "settle only pays the quoted gas")
+
+		// Not enough native balance for the output
+		backend.SetBalance(signer.From, big.NewInt(999))
+		filled.Store(false)
+		args = endToEndArgs("0x2222222222222222222222222222222222222222222222222222222222222222", native, settler.Hex(), config.BaseSepoliaChainID)
+		_, err = solver.ProcessIntent(context.Background(), &args)
+		assert.ErrorContains(t, err, "Insufficient balance")
+		assert.Len(t, backend.Sent(), 2)
+	})
+
+	t.Run("EVM same-chain swap settles through the router to its own domain", func(t *testing.T) {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		signer, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(config.BaseSepoliaChainID))
+		require.NoError(t, err)
+		t.Setenv("SOLVER_PUB_KEY", signer.From.Hex())
+		t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+		registry, err := config.GetDomainRegistry()
+		require.NoError(t, err)
+		localDomain, err := registry.DomainForChainID(config.BaseSepoliaChainID)
+		require.NoError(t, err)
+
+		token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+		settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(10_000)))
+		backend.HandleCall(token, chainmock.Selector("allowance(address,address)"), uint256Result(big.NewInt(10_000)))
+		var filled atomic.Bool
+		backend.HandleCall(settler, chainmock.Selector("orderStatus(bytes32)"), func(ethereum.CallMsg) ([]byte, error) {
+			if filled.Load() {
+				return common.RightPadBytes([]byte(orderStatusFilled), 32), nil
+			}
+			return make([]byte, 32), nil
+		})
+		var quotedDomains []uint64
+		backend.HandleCall(settler, chainmock.Selector("quoteGasPayment(uint32)"), func(call ethereum.CallMsg) ([]byte, error) {
+			quotedDomains = append(quotedDomains, new(big.Int).SetBytes(call.Data[4:]).Uint64())
+			return common.LeftPadBytes(big.NewInt(7).Bytes(), 32), nil
+		})
+		fillSelector := chainmock.Selector("fill(bytes32,bytes,bytes)")
+		backend.OnTransaction(settler, func(tx *gethtypes.Transaction) uint64 {
+			if bytes.HasPrefix(tx.Data(), fillSelector[:]) {
+				filled.Store(true)
+			}
+			return gethtypes.ReceiptStatusSuccessful
+		})
+
+		store, err := orders.NewStore(filepath.Join(t.TempDir(), "orders.json"))
+		require.NoError(t, err)
+		solver := NewHyperlane7683Solver(
+			func(uint64) (EVMClient, error) { return backend, nil },
+			nil,
+			func(uint64) (*bind.TransactOpts, error) { return signer, nil },
+			nil,
+			types.AllowBlockLists{},
+		)
+		solver.SetOrderStore(store)
+
+		args := endToEndArgs(orderID, token.Hex(), settler.Hex(), config.BaseSepoliaChainID)
+		args.ResolvedOrder.OriginChainID = big.NewInt(config.BaseSepoliaChainID)
+		args.ResolvedOrder.MinReceived[0].ChainID = big.NewInt(config.BaseSepoliaChainID)
+		args.ResolvedOrder.FillInstructions[0].OriginData = evmOriginData(args, 0)
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		// The router only releases the input when the settle message comes back to it
+		settleSelector := chainmock.Selector("settle(bytes32[])")
+		settlerTxs := backend.SentTo(settler)
+		require.Len(t, settlerTxs, 2, "fill and settle")
+		assert.True(t, bytes.HasPrefix(settlerTxs[0].Data(), fillSelector[:]))
+		assert.True(t, bytes.HasPrefix(settlerTxs[1].Data(), settleSelector[:]))
+		assert.Equal(t, big.NewInt(7), settlerTxs[1].Value(), "settle pays the gas of the local message")
+		require.NotEmpty(t, quotedDomains)
+		for _, domain := range quotedDomains {
+			assert.Equal(t, uint64(localDomain), domain)
+		}
+
+		order, found := store.Get(orderID)
+		require.True(t, found)
+		assert.Equal(t, orders.StatusSettled, order.Status)
+	})
+
+	t.Run("EVM order filled by a competing solver is recorded as lost race", func(t *testing.T) {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		signer, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(config.BaseSepoliaChainID))
+		require.NoError(t, err)
+		t.Setenv("SOLVER_PUB_KEY", signer.From.Hex())
+		t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+		settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+		fillSelector := chainmock.Selector("fill(bytes32,bytes,bytes)")
+
+		// competitorFillsOnOurTx makes the competing fill land just before ours, which then reverts
+		run := func(t *testing.T, competitorFillsOnOurTx bool) (*chainmock.EVMBackend, orders.Order) {
+			backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+			var filled atomic.Bool
+			filled.Store(!competitorFillsOnOurTx)
+			backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(10_000)))
+			backend.HandleCall(token, chainmock.Selector("allowance(address,address)"), uint256Result(big.NewInt(10_000)))
+			backend.HandleCall(settler, chainmock.Selector("orderStatus(bytes32)"), func(ethereum.CallMsg) ([]byte, error) {
+				if filled.Load() {
+					return common.RightPadBytes([]byte(orderStatusFilled), 32), nil
+				}
+				return make([]byte, 32), nil
+			})
+			backend.OnTransaction(settler, func(tx *gethtypes.Transaction) uint64 {
+				if bytes.HasPrefix(tx.Data(), fillSelector[:]) {
+					filled.Store(true)
+					return gethtypes.ReceiptStatusFailed
+				}
+				return gethtypes.ReceiptStatusSuccessful
+			})
+
+			store, err := orders.NewStore(filepath.Join(t.TempDir(), "orders.json"))
+			require.NoError(t, err)
+			solver := NewHyperlane7683Solver(
+				func(uint64) (EVMClient, error) { return backend, nil },
+				nil,
+				func(uint64) (*bind.TransactOpts, error) { return signer, nil },
+				nil,
+				types.AllowBlockLists{},
+			)
+			solver.SetOrderStore(store)
+
+			args := endToEndArgs(orderID, token.Hex(), settler.Hex(), config.BaseSepoliaChainID)
+			ok, err := solver.ProcessIntent(context.Background(), &args)
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			order, found := store.Get(orderID)
+			require.True(t, found)
+			return backend, order
+		}
+
+		t.Run("filled before the pre-check", func(t *testing.T) {
+			backend, order := run(t, false)
+			assert.Equal(t, orders.StatusLostRace, order.Status)
+			assert.Empty(t, backend.SentTo(settler), "neither fill nor settle is sent")
+		})
+
+		t.Run("filled while our fill was in flight", func(t *testing.T) {
+			backend, order := run(t, true)
+			assert.Equal(t, orders.StatusLostRace, order.Status)
+			require.Len(t, backend.SentTo(settler), 1, "no settle after the reverted fill")
+		})
+	})
+
+	t.Run("EVM destination rejected by balance rule", func(t *testing.T) {
+		t.Setenv("SOLVER_PUB_KEY", "0x00000000000000000000000000000000000000aa")
+		t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(1)))
+
+		solver := NewHyperlane7683Solver(
+			func(uint64) (EVMClient, error) { return backend, nil },
+			nil, nil, nil,
+			types.AllowBlockLists{},
+		)
+
+		args := endToEndArgs(orderID, token.Hex(), "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		assert.False(t, ok)
+		assert.ErrorContains(t, err, "Insufficient balance")
+		assert.Empty(t, backend.Sent())
+	})
+
+	t.Run("EVM destination rejected by custom rule", func(t *testing.T) {
+		t.Setenv("SOLVER_PUB_KEY", "0x00000000000000000000000000000000000000aa")
+		t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(10_000)))
+
+		solver := NewHyperlane7683Solver(
+			func(uint64) (EVMClient, error) { return backend, nil },
+			nil, nil, nil,
+			types.AllowBlockLists{},
+		)
+		solver.AddRule(&MockRule{name: "Custom", shouldPass: false})
+
+		args := endToEndArgs(orderID, token.Hex(), "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		assert.False(t, ok)
+		assert.ErrorContains(t, err, "Mock rule failed")
+		assert.Empty(t, backend.Sent())
+	})
+
+	t.Run("Starknet destination sends one fill+settle multicall, same-chain swaps too", func(t *testing.T) {
+		t.Setenv("STARKNET_SOLVER_PUBLIC_KEY", "0x2c2b8f559e1221468140ad7b2352b1a5be32660d0bf1a3ae3a054a4ec5254e4")
+		t.Setenv("STARKNET_SOLVER_PRIVATE_KEY", "0x1")
+		t.Setenv("STARKNET_SOLVER_ADDRESS", "0x0000000000000000000000000000000000000000000000000000000000000abc")
+		t.Setenv("STARKNET_HYPERLANE_ADDRESS", "0x0000000000000000000000000000000000000000000000000000000000005678")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := "0x0000000000000000000000000000000000000000000000000000000000001234"
+		settler := "0x0000000000000000000000000000000000000000000000000000000000005678"
+		tokenFelt, err := utils.HexToFelt(token)
+		require.NoError(t, err)
+		settlerFelt, err := utils.HexToFelt(settler)
+		require.NoError(t, err)
+		ethFelt, err := utils.HexToFelt(starknetETHAddress)
+		require.NoError(t, err)
+
+		backend := chainmock.NewStarknetBackend()
+		feltResult := func(values ...uint64) chainmock.StarknetCallHandler {
+			return func(rpc.FunctionCall) ([]*felt.Felt, error) {
+				out := make([]*felt.Felt, len(values))
+				for i, v := range values {
+					out[i] = new(felt.Felt).SetUint64(v)
+				}
+				return out, nil
+			}
+		}
+		backend.HandleCall(tokenFelt, "balanceOf", feltResult(10_000, 0))
+		backend.HandleCall(tokenFelt, "allowance", feltResult(0, 0))
+		backend.HandleCall(ethFelt, "allowance", feltResult(0, 0))
+		backend.HandleCall(settlerFelt, "order_status", feltResult(0))
+		backend.HandleCall(settlerFelt, "quote_gas_payment", feltResult(7, 0))
+
+		solver := NewHyperlane7683Solver(
+			nil,
+			func(uint64) (rpc.RPCProvider, error) { return backend, nil },
+			nil, nil,
+			types.AllowBlockLists{},
+		)
+
+		args := endToEndArgs(orderID, token, settler, config.StarknetSepoliaChainID)
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Len(t, backend.Invokes(), 1)
+
+		// A Starknet to Starknet swap settles to Starknet's own domain in the same multicall
+		args = endToEndArgs("0x2222222222222222222222222222222222222222222222222222222222222222", token, settler, config.StarknetSepoliaChainID)
+		args.ResolvedOrder.OriginChainID = big.NewInt(config.StarknetSepoliaChainID)
+		ok, err = solver.ProcessIntent(context.Background(), &args)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		invokes := backend.Invokes()
+		require.Len(t, invokes, 2)
+		assert.Equal(t, uint64(4), invokes[1].Calldata[0].Uint64(), "token and ETH approvals, fill and settle")
+	})
+
+	t.Run("Starknet fill whose settle would use up the margin leaves the settle out of the multicall", func(t *testing.T) {
+		t.Setenv("STARKNET_SOLVER_PUBLIC_KEY", "0x2c2b8f559e1221468140ad7b2352b1a5be32660d0bf1a3ae3a054a4ec5254e4")
+		t.Setenv("STARKNET_SOLVER_PRIVATE_KEY", "0x1")
+		t.Setenv("STARKNET_SOLVER_ADDRESS", "0x0000000000000000000000000000000000000000000000000000000000000abc")
+		t.Setenv("STARKNET_HYPERLANE_ADDRESS", "0x0000000000000000000000000000000000000000000000000000000000005678")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := "0x0000000000000000000000000000000000000000000000000000000000001234"
+		settler := "0x0000000000000000000000000000000000000000000000000000000000005678"
+		// The order spends and receives 1000 base units of DOG worth the same: no margin for the gas payment
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+			{ChainID: config.StarknetSepoliaChainID, Address: token, Symbol: "DOG", Decimals: 18, Price: "1"},
+			{ChainID: config.StarknetSepoliaChainID, Address: starknetETHAddress, Symbol: "ETH", Decimals: 18, Price: "2000"},
+			{ChainID: config.EthereumSepoliaChainID, Address: "0x00000000000000000000000000000000000000d4", Symbol: "DOG", Decimals: 20, Price: "1"},
+		}}))
+		defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+		tokenFelt, err := utils.HexToFelt(token)
+		require.NoError(t, err)
+		settlerFelt, err := utils.HexToFelt(settler)
+		require.NoError(t, err)
+		ethFelt, err := utils.HexToFelt(starknetETHAddress)
+		require.NoError(t, err)
+
+		feltResult := func(values ...uint64) chainmock.StarknetCallHandler {
+			return func(rpc.FunctionCall) ([]*felt.Felt, error) {
+				out := make([]*felt.Felt, len(values))
+				for i, v := range values {
+					out[i] = new(felt.Felt).SetUint64(v)
+				}
+				return out, nil
+			}
+		}
+		// The order reads as FILLED once the first invoke is included
+		newBackend := func() *chainmock.StarknetBackend {
+			backend := chainmock.NewStarknetBackend()
+			backend.HandleCall(tokenFelt, "balanceOf", feltResult(10_000, 0))
+			backend.HandleCall(tokenFelt, "allowance", feltResult(0, 0))
+			backend.HandleCall(ethFelt, "allowance", feltResult(0, 0))
+			backend.HandleCall(settlerFelt, "order_status", func(rpc.FunctionCall) ([]*felt.Felt, error) {
+				if len(backend.Invokes()) == 0 {
+					return []*felt.Felt{new(felt.Felt)}, nil
+				}
+				filled, err := utils.HexToFelt("0x46494c4c4544")
+				return []*felt.Felt{filled}, err
+			})
+			backend.HandleCall(settlerFelt, "quote_gas_payment", feltResult(7, 0))
+			return backend
+		}
+
+		newSolver := func(backend *chainmock.StarknetBackend) *Hyperlane7683Solver {
+			return NewHyperlane7683Solver(
+				nil,
+				func(uint64) (rpc.RPCProvider, error) { return backend, nil },
+				nil, nil,
+				types.AllowBlockLists{},
+			)
+		}
+
+		// Settled inline here, the split-off settle follows the fill as its own transaction
+		args := endToEndArgs(orderID, token, settler, config.StarknetSepoliaChainID)
+		backend := newBackend()
+		ok, err := newSolver(backend).ProcessIntent(context.Background(), &args)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		invokes := backend.Invokes()
+		require.Len(t, invokes, 2)
+		assert.Equal(t, uint64(2), invokes[0].Calldata[0].Uint64(), "token approval and fill")
+		assert.Equal(t, uint64(2), invokes[1].Calldata[0].Uint64(), "ETH approval and settle")
+
+		// With deferral disabled the settle stays in the multicall
+		t.Setenv("SETTLE_MAX_DEFER_SECONDS", "0")
+		backend = newBackend()
+		args = endToEndArgs(orderID, token, settler, config.StarknetSepoliaChainID)
+		ok, err = newSolver(backend).ProcessIntent(context.Background(), &args)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		invokes = backend.Invokes()
+		require.Len(t, invokes, 1)
+		assert.Equal(t, uint64(4), invokes[0].Calldata[0].Uint64(), "token and ETH approvals, fill and settle")
+	})
+
+	t.Run("Starknet fills use the provider of their chain", func(t *testing.T) {
+		const devnetChainID = 5050
+		settler := "0x0000000000000000000000000000000000000000000000000000000000005678"
+		t.Setenv("STARKNET_SOLVER_PUBLIC_KEY", "0x2c2b8f559e1221468140ad7b2352b1a5be32660d0bf1a3ae3a054a4ec5254e4")
+		t.Setenv("STARKNET_SOLVER_PRIVATE_KEY", "0x1")
+		t.Setenv("STARKNET_SOLVER_ADDRESS", "0x0000000000000000000000000000000000000000000000000000000000000abc")
+		t.Setenv("STARKNET_HYPERLANE_ADDRESS", settler)
+		t.Setenv("STARKNET_NETWORKS", "StarknetDevnet")
+		t.Setenv("STARKNETDEVNET_RPC_URL", "http://localhost:5050")
+		t.Setenv("STARKNETDEVNET_CHAIN_ID", "5050")
+		t.Setenv("STARKNETDEVNET_HYPERLANE_ADDRESS", settler)
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := "0x0000000000000000000000000000000000000000000000000000000000001234"
+		tokenFelt, err := utils.HexToFelt(token)
+		require.NoError(t, err)
+		settlerFelt, err := utils.HexToFelt(settler)
+		require.NoError(t, err)
+		ethFelt, err := utils.HexToFelt(starknetETHAddress)
+		require.NoError(t, err)
+
+		newBackend := func() *chainmock.StarknetBackend {
+			backend := chainmock.NewStarknetBackend()
+			backend.HandleCall(tokenFelt, "balanceOf", func(rpc.FunctionCall) ([]*felt.Felt, error) {
+				return []*felt.Felt{new(felt.Felt).SetUint64(10_000), new(felt.Felt)}, nil
+			})
+			for _, call := range []struct {
+				contract *felt.Felt
+				function string
+			}{{tokenFelt, "allowance"}, {ethFelt, "allowance"}, {settlerFelt, "order_status"}, {settlerFelt, "quote_gas_payment"}} {
+				backend.HandleCall(call.contract, call.function, func(rpc.FunctionCall) ([]*felt.Felt, error) {
+					return []*felt.Felt{new(felt.Felt), new(felt.Felt)}, nil
+				})
+			}
+			return backend
+		}
+		backends := map[uint64]*chainmock.StarknetBackend{
+			config.StarknetSepoliaChainID: newBackend(),
+			devnetChainID:                 newBackend(),
+		}
+
+		solver := NewHyperlane7683Solver(
+			nil,
+			func(chainID uint64) (rpc.RPCProvider, error) {
+				backend, ok := backends[chainID]
+				if !ok {
+					return nil, fmt.Errorf("no Starknet client for chain %d", chainID)
+				}
+				return backend, nil
+			},
+			nil, nil,
+			types.AllowBlockLists{},
+		)
+
+		args := endToEndArgs(orderID, token, settler, devnetChainID)
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Len(t, backends[devnetChainID].Invokes(), 1)
+		assert.Empty(t, backends[config.StarknetSepoliaChainID].Invokes())
+
+		args = endToEndArgs("0x2222222222222222222222222222222222222222222222222222222222222222", token, settler, config.StarknetSepoliaChainID)
+		ok, err = solver.ProcessIntent(context.Background(), &args)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Len(t, backends[config.StarknetSepoliaChainID].Invokes(), 1)
+		assert.Len(t, backends[devnetChainID].Invokes(), 1)
+	})
+
+	t.Run("multi-leg order fills every leg before settling", func(t *testing.T) {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		t.Setenv("SOLVER_PUB_KEY", crypto.PubkeyToAddress(key.PublicKey).Hex())
+		t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+		settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+		fillSelector := chainmock.Selector("fill(bytes32,bytes,bytes)")
+
+		// Transactions sent to the routers of both legs, in order
+		var mu sync.Mutex
+		var sequence []string
+		newLeg := func(chainID uint64, failFill bool) *chainmock.EVMBackend {
+			backend := chainmock.NewEVMBackend(chainID)
+			var filled atomic.Bool
+			backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(10_000)))
+			backend.HandleCall(token, chainmock.Selector("allowance(address,address)"), uint256Result(big.NewInt(0)))
+			backend.HandleCall(settler, chainmock.Selector("orderStatus(bytes32)"), func(ethereum.CallMsg) ([]byte, error) {
+				if filled.Load() {
+					return common.RightPadBytes([]byte(orderStatusFilled), 32), nil
+				}
+				return make([]byte, 32), nil
+			})
+			backend.HandleCall(settler, chainmock.Selector("quoteGasPayment(uint32)"), uint256Result(big.NewInt(7)))
+			backend.OnTransaction(settler, func(tx *gethtypes.Transaction) uint64 {
+				kind := "settle"
+				if bytes.HasPrefix(tx.Data(), fillSelector[:]) {
+					kind = "fill"
+					if failFill {
+						return gethtypes.ReceiptStatusFailed
+					}
+					filled.Store(true)
+				}
+				mu.Lock()
+				sequence = append(sequence, fmt.Sprintf("%s %d", kind, chainID))
+				mu.Unlock()
+				return gethtypes.ReceiptStatusSuccessful
+			})
+			return backend
+		}
+		newSolver := func(backends map[uint64]*chainmock.EVMBackend) *Hyperlane7683Solver {
+			return NewHyperlane7683Solver(
+				func(chainID uint64) (EVMClient, error) { return backends[chainID], nil },
+				nil,
+				func(chainID uint64) (*bind.TransactOpts, error) {
+					return bind.NewKeyedTransactorWithChainID(key, new(big.Int).SetUint64(chainID))
+				},
+				nil,
+				types.AllowBlockLists{},
+			)
+		}
+		twoLegArgs := func() types.ParsedArgs {
+			args := endToEndArgs(orderID, token.Hex(), settler.Hex(), config.BaseSepoliaChainID)
+			optimism := big.NewInt(config.OptimismSepoliaChainID)
+			args.ResolvedOrder.MaxSpent = append(args.ResolvedOrder.MaxSpent,
+				types.Output{Token: token.Hex(), Amount: big.NewInt(2000), Recipient: "0x00000000000000000000000000000000000000cc", ChainID: optimism})
+			args.ResolvedOrder.FillInstructions = append(args.ResolvedOrder.FillInstructions,
+				types.FillInstruction{DestinationChainID: optimism, DestinationSettler: settler.Hex()})
+			args.ResolvedOrder.FillInstructions[1].OriginData = evmOriginData(args, 1)
+			return args
+		}
+
+		base := newLeg(config.BaseSepoliaChainID, false)
+		optimism := newLeg(config.OptimismSepoliaChainID, false)
+		solver := newSolver(map[uint64]*chainmock.EVMBackend{config.BaseSepoliaChainID: base, config.OptimismSepoliaChainID: optimism})
+
+		args := twoLegArgs()
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []string{"fill 84532", "fill 11155420", "settle 84532", "settle 11155420"}, sequence)
+
+		// Each leg approves only its own output
+		require.Len(t, base.SentTo(token), 1)
+		require.Len(t, optimism.SentTo(token), 1)
+		assert.Equal(t, big.NewInt(1000), new(big.Int).SetBytes(base.SentTo(token)[0].Data()[36:68]))
+		assert.Equal(t, big.NewInt(2000), new(big.Int).SetBytes(optimism.SentTo(token)[0].Data()[36:68]))
+
+		// A failed leg leaves the order unsettled
+		sequence = nil
+		base = newLeg(config.BaseSepoliaChainID, false)
+		optimism = newLeg(config.OptimismSepoliaChainID, true)
+		solver = newSolver(map[uint64]*chainmock.EVMBackend{config.BaseSepoliaChainID: base, config.OptimismSepoliaChainID: optimism})
+
+		args = twoLegArgs()
+		ok, err = solver.ProcessIntent(context.Background(), &args)
+		assert.False(t, ok)
+		assert.ErrorContains(t, err, "fill instruction 2 failed")
+		assert.Equal(t, []string{"fill 84532"}, sequence)
+	})
+
+	t.Run("EVM fill above the daily notional limit sends nothing", func(t *testing.T) {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		signer, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(config.BaseSepoliaChainID))
+		require.NoError(t, err)
+		t.Setenv("SOLVER_PUB_KEY", signer.From.Hex())
+		t.Setenv("SPEND_LIMIT_FILL_NOTIONAL_PER_DAY", "0.000000000000000999")
+		defaultSpendLimiterOnce = sync.Once{}
+		defer func() { defaultSpendLimiterOnce = sync.Once{} }()
+		t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+		// The 1000 base units filled are worth 1000 at a price of 1
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+			{ChainID: config.BaseSepoliaChainID, Address: "0x00000000000000000000000000000000000000a1", Symbol: "DOG", Decimals: 18, Price: "1"},
+			{ChainID: config.EthereumSepoliaChainID, Address: "0x00000000000000000000000000000000000000d4", Symbol: "DOG", Decimals: 20, Price: "1"},
+		}}))
+		defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+
+		token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+		settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(10_000)))
+		backend.HandleCall(settler, chainmock.Selector("orderStatus(bytes32)"), uint256Result(big.NewInt(0)))
+
+		solver := NewHyperlane7683Solver(
+			func(uint64) (EVMClient, error) { return backend, nil },
+			nil,
+			func(uint64) (*bind.TransactOpts, error) { return signer, nil },
+			nil,
+			types.AllowBlockLists{},
+		)
+
+		args := endToEndArgs(orderID, token.Hex(), settler.Hex(), config.BaseSepoliaChainID)
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		assert.False(t, ok)
+		assert.ErrorIs(t, err, ErrSpendLimitReached)
+		assert.Empty(t, backend.Sent())
+	})
+
+	t.Run("EVM fill whose estimated fee exceeds the daily gas limit sends nothing", func(t *testing.T) {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		signer, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(config.BaseSepoliaChainID))
+		require.NoError(t, err)
+		t.Setenv("SOLVER_PUB_KEY", signer.From.Hex())
+		t.Setenv("SPEND_LIMIT_GAS_PER_DAY", "0.000000000000000001")
+		defaultSpendLimiterOnce = sync.Once{}
+		defer func() { defaultSpendLimiterOnce = sync.Once{} }()
+		t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+		settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(10_000)))
+		backend.HandleCall(token, chainmock.Selector("allowance(address,address)"), uint256Result(big.NewInt(10_000)))
+		backend.HandleCall(settler, chainmock.Selector("orderStatus(bytes32)"), uint256Result(big.NewInt(0)))
+
+		solver := NewHyperlane7683Solver(
+			func(uint64) (EVMClient, error) { return backend, nil },
+			nil,
+			func(uint64) (*bind.TransactOpts, error) { return signer, nil },
+			nil,
+			types.AllowBlockLists{},
+		)
+
+		args := endToEndArgs(orderID, token.Hex(), settler.Hex(), config.BaseSepoliaChainID)
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		assert.False(t, ok)
+		assert.ErrorIs(t, err, ErrSpendLimitReached)
+		assert.Empty(t, backend.Sent())
+	})
+
+	t.Run("EVM destination with fills paused sends nothing", func(t *testing.T) {
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+		_, err := config.SetFillsEnabled("Base", false)
+		require.NoError(t, err)
+
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		solver := NewHyperlane7683Solver(
+			func(uint64) (EVMClient, error) { return backend, nil },
+			nil, nil, nil,
+			types.AllowBlockLists{},
+		)
+
+		args := endToEndArgs(orderID, "0x00000000000000000000000000000000000000a1", "0x00000000000000000000000000000000000000b2", config.BaseSepoliaChainID)
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		assert.False(t, ok)
+		assert.ErrorContains(t, err, "fills paused for chain 84532")
+		assert.Empty(t, backend.Sent())
+	})
+
+	t.Run("Starknet reverted multicall still counts towards the gas limit", func(t *testing.T) {
+		t.Setenv("STARKNET_SOLVER_PUBLIC_KEY", "0x2c2b8f559e1221468140ad7b2352b1a5be32660d0bf1a3ae3a054a4ec5254e4")
+		t.Setenv("STARKNET_SOLVER_PRIVATE_KEY", "0x1")
+		t.Setenv("STARKNET_SOLVER_ADDRESS", "0x0000000000000000000000000000000000000000000000000000000000000abc")
+		defaultSpendLimiterOnce = sync.Once{}
+		defer func() { defaultSpendLimiterOnce = sync.Once{} }()
+		t.Setenv("STARKNET_HYPERLANE_ADDRESS", "0x0000000000000000000000000000000000000000000000000000000000005678")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := "0x0000000000000000000000000000000000000000000000000000000000001234"
+		settler := "0x0000000000000000000000000000000000000000000000000000000000005678"
+		tokenFelt, err := utils.HexToFelt(token)
+		require.NoError(t, err)
+		settlerFelt, err := utils.HexToFelt(settler)
+		require.NoError(t, err)
+		ethFelt, err := utils.HexToFelt(starknetETHAddress)
+		require.NoError(t, err)
+
+		backend := chainmock.NewStarknetBackend()
+		zero := func(n int) chainmock.StarknetCallHandler {
+			return func(rpc.FunctionCall) ([]*felt.Felt, error) {
+				out := make([]*felt.Felt, n)
+				for i := range out {
+					out[i] = new(felt.Felt)
+				}
+				return out, nil
+			}
+		}
+		backend.HandleCall(tokenFelt, "balanceOf", func(rpc.FunctionCall) ([]*felt.Felt, error) {
+			return []*felt.Felt{new(felt.Felt).SetUint64(10_000), new(felt.Felt)}, nil
+		})
+		backend.HandleCall(tokenFelt, "allowance", zero(2))
+		backend.HandleCall(ethFelt, "allowance", zero(2))
+		backend.HandleCall(settlerFelt, "order_status", zero(1))
+		backend.HandleCall(settlerFelt, "quote_gas_payment", zero(2))
+		backend.OnInvoke(func(*rpc.BroadcastInvokeTxnV3) rpc.TxnExecutionStatus {
+			return rpc.TxnExecutionStatusREVERTED
+		})
+
+		solver := NewHyperlane7683Solver(
+			nil,
+			func(uint64) (rpc.RPCProvider, error) { return backend, nil },
+			nil, nil,
+			types.AllowBlockLists{},
+		)
+
+		args := endToEndArgs(orderID, token, settler, config.StarknetSepoliaChainID)
+		ok, err := solver.ProcessIntent(context.Background(), &args)
+		assert.False(t, ok)
+		assert.ErrorContains(t, err, "reverted")
+		assert.Equal(t, []SpendStatus{{ChainID: config.StarknetSepoliaChainID, Gas: "1000", Notional: "0"}},
+			DefaultSpendLimiter().Status())
+	})
+}
+
+// uint256Result answers an EVM call with an ABI-encoded uint256
+func uint256Result(v *big.Int) chainmock.EVMCallHandler {
+	return func(ethereum.CallMsg) ([]byte, error) {
+		return common.LeftPadBytes(v.Bytes(), 32), nil
+	}
+}
+
+// endToEndArgs builds a profitable Ethereum-origin order filled with token on the destination chain
+func endToEndArgs(orderID, token, settler string, destinationChainID uint64) types.ParsedArgs {
+	destination := new(big.Int).SetUint64(destinationChainID)
+	args := types.ParsedArgs{
+		OrderID: orderID,
+		ResolvedOrder: types.ResolvedCrossChainOrder{
+			User:          "0x00000000000000000000000000000000000000cc",
+			OriginChainID: big.NewInt(config.EthereumSepoliaChainID),
+			MaxSpent: []types.Output{
+				{Token: token, Amount: big.NewInt(1000), Recipient: "0x00000000000000000000000000000000000000cc", ChainID: destination},
+			},
+			MinReceived: []types.Output{
+				{Token: "0x00000000000000000000000000000000000000d4", Amount: big.NewInt(100_000), ChainID: big.NewInt(config.EthereumSepoliaChainID)},
+			},
+			FillInstructions: []types.FillInstruction{
+				{DestinationChainID: destination, DestinationSettler: settler},
+			},
+		},
+	}
+	args.ResolvedOrder.FillInstructions[0].OriginData = evmOriginData(args, 0)
+	return args
+}
@@ -0,0 +1,311 @@
+package hyperlane7683
+
+// Module: Daily spend guardrails
+// - Tracks, per chain, the native token spent on transaction fees and the notional filled (the
+//   value of the orders' MaxSpent tokens at the price feed's prices) over a rolling 24h window
+// - Checked before every transaction is submitted; a transaction that would exceed a limit is refused.
+//   EVM transactions are signed first and checked against their max fee (gas limit × fee cap)
+// - Windows can be cleared at runtime through the admin API (POST /spend-limits/reset)
+// - Windows are kept in the spend_limits collection of the storage backend, so a restart doesn't
+//   clear them
+//
+// Settings:
+// - SPEND_LIMIT_GAS_PER_DAY: max fees per chain per 24h, in the fee token (ETH on EVM chains, STRK on Starknet)
+// - SPEND_LIMIT_FILL_NOTIONAL_PER_DAY: max filled notional per chain per 24h, in the price feed's quote
+//   currency (e.g. USD); while it is set, fills of tokens without a price are refused
+// - Unset or 0 disables a limit; an invalid value blocks all transactions until it is fixed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/pricefeed"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const spendWindow = 24 * time.Hour
+
+// ErrSpendLimitReached is returned when a transaction would exceed a daily spend limit
+var ErrSpendLimitReached = errors.New("daily spend limit reached")
+
+// spendEntry is one recorded spend
+type spendEntry struct {
+	at       time.Time
+	gas      *big.Int
+	notional *big.Int
+}
+
+// spendRecord is a spend entry as kept in the storage backend
+type spendRecord struct {
+	At       time.Time `json:"at"`
+	Gas      string    `json:"gas"`
+	Notional string    `json:"notional"`
+}
+
+// SpendStatus is a chain's spending over the current window
+type SpendStatus struct {
+	ChainID     uint64 `json:"chainId"`
+	Gas         string `json:"gas"`
+	Notional    string `json:"notional"`
+	MaxGas      string `json:"maxGas,omitempty"`
+	MaxNotional string `json:"maxNotional,omitempty"`
+}
+
+// SpendLimiter enforces the daily spend limits of all chains
+type SpendLimiter struct {
+	mu          sync.Mutex
+	maxGas      *big.Int // nil means no limit
+	maxNotional *big.Int // nil means no limit
+	limitErr    error    // invalid limit setting; every check fails until it is fixed
+	now         func() time.Time
+	entries     map[uint64][]spendEntry
+	backend     storage.Storage // nil keeps the windows in memory only
+}
+
+// NewSpendLimiter creates a limiter; a nil limit disables that check
+func NewSpendLimiter(maxGas, maxNotional *big.Int) *SpendLimiter {
+	return &SpendLimiter{
+		maxGas:      maxGas,
+		maxNotional: maxNotional,
+		now:         time.Now,
+		entries:     make(map[uint64][]spendEntry),
+	}
+}
+
+var (
+	defaultSpendLimiter     *SpendLimiter
+	defaultSpendLimiterOnce sync.Once
+)
+
+// DefaultSpendLimiter returns the process-wide limiter shared by the chain handlers
+func DefaultSpendLimiter() *SpendLimiter {
+	defaultSpendLimiterOnce.Do(func() {
+		maxGas, gasErr := parseFeeCap("SPEND_LIMIT_GAS_PER_DAY")
+		maxNotional, notionalErr := parseFeeCap("SPEND_LIMIT_FILL_NOTIONAL_PER_DAY")
+		defaultSpendLimiter = NewSpendLimiter(maxGas, maxNotional)
+		if err := errors.Join(gasErr, notionalErr); err != nil {
+			// Fail closed: a mistyped limit must not turn into no limit
+			fmt.Printf("⚠️  %v; transactions are disabled until it is fixed\n", err)
+			defaultSpendLimiter.limitErr = err
+		}
+	})
+	return defaultSpendLimiter
+}
+
+// SetStorage loads the windows kept in backend and keeps every chain's window there from now on.
+// It replaces the windows in memory, so it is called on startup before any transaction is sent.
+func (l *SpendLimiter) SetStorage(backend storage.Storage) error {
+	docs, err := backend.Load(storage.SpendLimits)
+	if err != nil {
+		return fmt.Errorf("failed to load spend windows: %w", err)
+	}
+	entries := make(map[uint64][]spendEntry, len(docs))
+	for key, doc := range docs {
+		chainID, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid chain ID %q in spend windows", key)
+		}
+		var records []spendRecord
+		if err := json.Unmarshal(doc, &records); err != nil {
+			return fmt.Errorf("failed to parse the spend window of chain %d: %w", chainID, err)
+		}
+		for _, record := range records {
+			gas, gasOK := new(big.Int).SetString(record.Gas, 10)
+			notional, notionalOK := new(big.Int).SetString(record.Notional, 10)
+			if !gasOK || !notionalOK {
+				return fmt.Errorf("invalid amount in the spend window of chain %d", chainID)
+			}
+			entries[chainID] = append(entries[chainID], spendEntry{at: record.At, gas: gas, notional: notional})
+		}
+		sort.SliceStable(entries[chainID], func(i, j int) bool {
+			return entries[chainID][i].at.Before(entries[chainID][j].at)
+		})
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = entries
+	l.backend = backend
+	return nil
+}
+
+// CheckGas returns ErrSpendLimitReached if spending fee more on the chain would exceed the gas limit.
+// fee may be nil when the cost is not known up front; the check then only refuses once the limit is used up.
+func (l *SpendLimiter) CheckGas(chainID uint64, fee *big.Int) error {
+	return l.check(chainID, fee, nil)
+}
+
+// CheckFill returns ErrSpendLimitReached if filling notional more on the chain would exceed the notional limit
+func (l *SpendLimiter) CheckFill(chainID uint64, notional *big.Int) error {
+	return l.check(chainID, nil, notional)
+}
+
+func (l *SpendLimiter) check(chainID uint64, fee, notional *big.Int) error {
+	if l.limitErr != nil {
+		return l.limitErr
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	gas, filled := l.totals(chainID)
+
+	if err := checkLimit("gas", gas, fee, l.maxGas); err != nil {
+		return fmt.Errorf("chain %d: %w", chainID, err)
+	}
+	if err := checkLimit("fill notional", filled, notional, l.maxNotional); err != nil {
+		return fmt.Errorf("chain %d: %w", chainID, err)
+	}
+	return nil
+}
+
+// checkLimit refuses once spent reaches max, or when spent + next would exceed it
+func checkLimit(name string, spent, next, max *big.Int) error {
+	if max == nil {
+		return nil
+	}
+	total := new(big.Int).Set(spent)
+	if next != nil {
+		total.Add(total, next)
+	}
+	if spent.Cmp(max) >= 0 || total.Cmp(max) > 0 {
+		return fmt.Errorf("%w: %s spent %s, next %v, limit %s per 24h", ErrSpendLimitReached, name, spent, next, max)
+	}
+	return nil
+}
+
+// RecordTx adds the fee of a sent transaction to its chain's window
+func (l *SpendLimiter) RecordTx(cost orders.TxCost) {
+	l.record(cost.ChainID, cost.Fee, nil)
+}
+
+// RecordFill adds the notional of a completed fill to the chain's window
+func (l *SpendLimiter) RecordFill(chainID uint64, notional *big.Int) {
+	l.record(chainID, nil, notional)
+}
+
+func (l *SpendLimiter) record(chainID uint64, gas, notional *big.Int) {
+	entry := spendEntry{at: l.now(), gas: new(big.Int), notional: new(big.Int)}
+	if gas != nil {
+		entry.gas.Set(gas)
+	}
+	if notional != nil {
+		entry.notional.Set(notional)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.totals(chainID)
+	l.entries[chainID] = append(l.entries[chainID], entry)
+	l.saveLocked(chainID)
+}
+
+// saveLocked writes the window of a chain to the backend, if any; must be called with mu held.
+// A failed write is logged, as the spend already happened.
+func (l *SpendLimiter) saveLocked(chainID uint64) {
+	if l.backend == nil {
+		return
+	}
+	key := strconv.FormatUint(chainID, 10)
+	entries := l.entries[chainID]
+	if len(entries) == 0 {
+		if err := l.backend.Delete(storage.SpendLimits, key); err != nil {
+			fillerLog.Printf("⚠️  Failed to clear the spend window of chain %d: %v\n", chainID, err)
+		}
+		return
+	}
+
+	records := make([]spendRecord, len(entries))
+	for i, entry := range entries {
+		records[i] = spendRecord{At: entry.at.UTC(), Gas: entry.gas.String(), Notional: entry.notional.String()}
+	}
+	doc, err := json.Marshal(records)
+	if err == nil {
+		err = l.backend.Put(storage.SpendLimits, key, doc)
+	}
+	if err != nil {
+		fillerLog.Printf("⚠️  Failed to store the spend window of chain %d: %v\n", chainID, err)
+	}
+}
+
+// totals prunes expired entries and sums the rest; must be called with mu held
+func (l *SpendLimiter) totals(chainID uint64) (gas, notional *big.Int) {
+	cutoff := l.now().Add(-spendWindow)
+	entries := l.entries[chainID]
+	for len(entries) > 0 && !entries[0].at.After(cutoff) {
+		entries = entries[1:]
+	}
+	l.entries[chainID] = entries
+
+	gas, notional = new(big.Int), new(big.Int)
+	for _, entry := range entries {
+		gas.Add(gas, entry.gas)
+		notional.Add(notional, entry.notional)
+	}
+	return gas, notional
+}
+
+// Reset clears the window of a chain
+func (l *SpendLimiter) Reset(chainID uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.entries, chainID)
+	l.saveLocked(chainID)
+}
+
+// ResetAll clears the windows of all chains
+func (l *SpendLimiter) ResetAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	chains := l.entries
+	l.entries = make(map[uint64][]spendEntry)
+	for chainID := range chains {
+		l.saveLocked(chainID)
+	}
+}
+
+// Status returns the spending of every chain with spending in the current window, sorted by chain ID
+func (l *SpendLimiter) Status() []SpendStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	statuses := make([]SpendStatus, 0, len(l.entries))
+	for chainID := range l.entries {
+		gas, notional := l.totals(chainID)
+		status := SpendStatus{ChainID: chainID, Gas: gas.String(), Notional: notional.String()}
+		if l.maxGas != nil {
+			status.MaxGas = l.maxGas.String()
+		}
+		if l.maxNotional != nil {
+			status.MaxNotional = l.maxNotional.String()
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ChainID < statuses[j].ChainID })
+	return statuses
+}
+
+// CheckOrderFill values the tokens an order's fill spends at the price feed's prices and checks the
+// value against the chain's notional limit; it returns the value to record once the fill succeeds.
+// While a notional limit is set, fills the feed can't value are refused; otherwise they count as zero.
+func (l *SpendLimiter) CheckOrderFill(ctx context.Context, chainID uint64, args *types.ParsedArgs) (*big.Int, error) {
+	notional, err := sideValue(ctx, pricefeed.Default(), orderSides(args)[0])
+	if err != nil {
+		if l.maxNotional != nil {
+			return nil, fmt.Errorf("%w: chain %d: cannot value the fill for the notional limit: %w", ErrSpendLimitReached, chainID, err)
+		}
+		notional = new(big.Int)
+	}
+	if err := l.CheckFill(chainID, notional); err != nil {
+		return nil, err
+	}
+	return notional, nil
+}
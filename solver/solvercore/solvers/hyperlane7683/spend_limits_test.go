@@ -0,0 +1,220 @@
+package hyperlane7683
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/pricefeed"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpendLimiter(t *testing.T) {
+	feeTx := func(chainID uint64, fee int64) orders.TxCost {
+		return orders.TxCost{ChainID: chainID, Kind: orders.TxKindFill, Fee: big.NewInt(fee)}
+	}
+
+	t.Run("gas limit refuses transactions that would exceed it", func(t *testing.T) {
+		limiter := NewSpendLimiter(big.NewInt(100), nil)
+		require.NoError(t, limiter.CheckGas(1, big.NewInt(100)))
+
+		limiter.RecordTx(feeTx(1, 60))
+		require.NoError(t, limiter.CheckGas(1, big.NewInt(40)))
+		assert.ErrorIs(t, limiter.CheckGas(1, big.NewInt(41)), ErrSpendLimitReached)
+		require.NoError(t, limiter.CheckGas(1, nil))
+
+		limiter.RecordTx(feeTx(1, 40))
+		assert.ErrorIs(t, limiter.CheckGas(1, nil), ErrSpendLimitReached)
+
+		// Other chains have their own window
+		require.NoError(t, limiter.CheckGas(2, big.NewInt(100)))
+	})
+
+	t.Run("fill notional limit refuses fills that would exceed it", func(t *testing.T) {
+		limiter := NewSpendLimiter(nil, big.NewInt(1000))
+		require.NoError(t, limiter.CheckFill(1, big.NewInt(1000)))
+		assert.ErrorIs(t, limiter.CheckFill(1, big.NewInt(1001)), ErrSpendLimitReached)
+
+		limiter.RecordFill(1, big.NewInt(700))
+		assert.ErrorIs(t, limiter.CheckFill(1, big.NewInt(301)), ErrSpendLimitReached)
+		require.NoError(t, limiter.CheckFill(1, big.NewInt(300)))
+
+		// Fees do not count towards the notional limit
+		limiter.RecordTx(feeTx(1, 1_000_000))
+		require.NoError(t, limiter.CheckFill(1, big.NewInt(300)))
+	})
+
+	t.Run("spending expires after 24 hours", func(t *testing.T) {
+		limiter := NewSpendLimiter(big.NewInt(100), nil)
+		now := time.Unix(1_700_000_000, 0)
+		limiter.now = func() time.Time { return now }
+
+		limiter.RecordTx(feeTx(1, 70))
+		now = now.Add(12 * time.Hour)
+		limiter.RecordTx(feeTx(1, 30))
+		assert.ErrorIs(t, limiter.CheckGas(1, nil), ErrSpendLimitReached)
+
+		now = now.Add(12 * time.Hour)
+		require.NoError(t, limiter.CheckGas(1, big.NewInt(70)))
+		assert.ErrorIs(t, limiter.CheckGas(1, big.NewInt(71)), ErrSpendLimitReached)
+
+		now = now.Add(12 * time.Hour)
+		require.NoError(t, limiter.CheckGas(1, big.NewInt(100)))
+	})
+
+	t.Run("reset clears the window", func(t *testing.T) {
+		limiter := NewSpendLimiter(big.NewInt(100), big.NewInt(100))
+		limiter.RecordTx(feeTx(1, 100))
+		limiter.RecordFill(2, big.NewInt(100))
+
+		limiter.Reset(1)
+		require.NoError(t, limiter.CheckGas(1, big.NewInt(100)))
+		assert.ErrorIs(t, limiter.CheckFill(2, big.NewInt(1)), ErrSpendLimitReached)
+
+		limiter.ResetAll()
+		require.NoError(t, limiter.CheckFill(2, big.NewInt(100)))
+	})
+
+	t.Run("windows are kept in the storage backend", func(t *testing.T) {
+		backend := storage.NewFileStorage(t.TempDir(), nil)
+		now := time.Unix(1_700_000_000, 0)
+
+		limiter := NewSpendLimiter(big.NewInt(100), big.NewInt(1000))
+		limiter.now = func() time.Time { return now }
+		require.NoError(t, limiter.SetStorage(backend))
+		limiter.RecordTx(feeTx(1, 60))
+		limiter.RecordFill(1, big.NewInt(700))
+		limiter.RecordTx(feeTx(2, 100))
+
+		// A restarted solver picks up the windows where they were
+		reopened := NewSpendLimiter(big.NewInt(100), big.NewInt(1000))
+		reopened.now = func() time.Time { return now.Add(time.Hour) }
+		require.NoError(t, reopened.SetStorage(backend))
+		assert.ErrorIs(t, reopened.CheckGas(1, big.NewInt(41)), ErrSpendLimitReached)
+		assert.ErrorIs(t, reopened.CheckFill(1, big.NewInt(301)), ErrSpendLimitReached)
+		assert.ErrorIs(t, reopened.CheckGas(2, nil), ErrSpendLimitReached)
+
+		// Resets are kept too
+		reopened.Reset(2)
+		docs, err := backend.Load(storage.SpendLimits)
+		require.NoError(t, err)
+		assert.Len(t, docs, 1)
+		reopened.ResetAll()
+		docs, err = backend.Load(storage.SpendLimits)
+		require.NoError(t, err)
+		assert.Empty(t, docs)
+	})
+
+	t.Run("expired spending is dropped from the storage backend", func(t *testing.T) {
+		backend := storage.NewFileStorage(t.TempDir(), nil)
+		now := time.Unix(1_700_000_000, 0)
+
+		limiter := NewSpendLimiter(big.NewInt(100), nil)
+		limiter.now = func() time.Time { return now }
+		require.NoError(t, limiter.SetStorage(backend))
+		limiter.RecordTx(feeTx(1, 100))
+		now = now.Add(25 * time.Hour)
+		limiter.RecordTx(feeTx(1, 10))
+
+		reopened := NewSpendLimiter(big.NewInt(100), nil)
+		reopened.now = func() time.Time { return now }
+		require.NoError(t, reopened.SetStorage(backend))
+		require.NoError(t, reopened.CheckGas(1, big.NewInt(90)))
+		assert.ErrorIs(t, reopened.CheckGas(1, big.NewInt(91)), ErrSpendLimitReached)
+	})
+
+	t.Run("corrupt stored window fails to load", func(t *testing.T) {
+		backend := storage.NewFileStorage(t.TempDir(), nil)
+		require.NoError(t, backend.Put(storage.SpendLimits, "1", []byte(`[{"at":"2024-01-01T00:00:00Z","gas":"lots","notional":"0"}]`)))
+		assert.ErrorContains(t, NewSpendLimiter(nil, nil).SetStorage(backend), "invalid amount in the spend window of chain 1")
+	})
+
+	t.Run("no limits allow everything", func(t *testing.T) {
+		limiter := NewSpendLimiter(nil, nil)
+		limiter.RecordTx(feeTx(1, 1_000_000))
+		limiter.RecordFill(1, big.NewInt(1_000_000))
+		require.NoError(t, limiter.CheckGas(1, big.NewInt(1_000_000)))
+		require.NoError(t, limiter.CheckFill(1, big.NewInt(1_000_000)))
+	})
+
+	t.Run("status reports spending per chain", func(t *testing.T) {
+		limiter := NewSpendLimiter(big.NewInt(100), nil)
+		limiter.RecordTx(feeTx(10, 5))
+		limiter.RecordFill(10, big.NewInt(50))
+		limiter.RecordTx(feeTx(2, 7))
+
+		assert.Equal(t, []SpendStatus{
+			{ChainID: 2, Gas: "7", Notional: "0", MaxGas: "100"},
+			{ChainID: 10, Gas: "5", Notional: "50", MaxGas: "100"},
+		}, limiter.Status())
+	})
+
+	t.Run("invalid limit setting fails closed", func(t *testing.T) {
+		t.Setenv("SPEND_LIMIT_FILL_NOTIONAL_PER_DAY", "plenty")
+		defaultSpendLimiterOnce = sync.Once{}
+		defer func() { defaultSpendLimiterOnce = sync.Once{} }()
+
+		limiter := DefaultSpendLimiter()
+		assert.ErrorContains(t, limiter.CheckGas(1, nil), "SPEND_LIMIT_FILL_NOTIONAL_PER_DAY")
+		assert.ErrorContains(t, limiter.CheckFill(1, big.NewInt(1)), "SPEND_LIMIT_FILL_NOTIONAL_PER_DAY")
+	})
+
+	t.Run("limit settings are in whole tokens", func(t *testing.T) {
+		t.Setenv("SPEND_LIMIT_GAS_PER_DAY", "0.5")
+		t.Setenv("SPEND_LIMIT_FILL_NOTIONAL_PER_DAY", "1000")
+		defaultSpendLimiterOnce = sync.Once{}
+		defer func() { defaultSpendLimiterOnce = sync.Once{} }()
+
+		limiter := DefaultSpendLimiter()
+		assert.Equal(t, big.NewInt(500_000_000_000_000_000), limiter.maxGas)
+		expectedNotional, _ := new(big.Int).SetString("1000000000000000000000", 10)
+		assert.Equal(t, expectedNotional, limiter.maxNotional)
+	})
+}
+
+func TestCheckOrderFill(t *testing.T) {
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+	ctx := context.Background()
+	args := &types.ParsedArgs{ResolvedOrder: types.ResolvedCrossChainOrder{
+		MaxSpent: []types.Output{
+			{Token: "0xa1", Amount: big.NewInt(2_000_000)},
+			{Token: "0xb2", Amount: new(big.Int).Mul(big.NewInt(3), big.NewInt(1e18))},
+		},
+		FillInstructions: []types.FillInstruction{{DestinationChainID: big.NewInt(84532)}},
+	}}
+
+	t.Run("fills are valued at the price feed's prices", func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+			{ChainID: 84532, Address: "0xa1", Symbol: "USDC", Decimals: 6, Price: "1"},
+			{ChainID: 84532, Address: "0xb2", Symbol: "ETH", Decimals: 18, Price: "2500"},
+		}}))
+		// 2 USDC and 3 ETH are worth 7502
+		worth, _ := new(big.Int).SetString("7502000000000000000000", 10)
+
+		limiter := NewSpendLimiter(nil, worth)
+		notional, err := limiter.CheckOrderFill(ctx, 84532, args)
+		require.NoError(t, err)
+		assert.Equal(t, worth, notional)
+
+		limiter.RecordFill(84532, big.NewInt(1))
+		_, err = limiter.CheckOrderFill(ctx, 84532, args)
+		assert.ErrorIs(t, err, ErrSpendLimitReached)
+	})
+
+	t.Run("unpriced fills are refused while a notional limit is set", func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{}))
+		_, err := NewSpendLimiter(nil, big.NewInt(1e18)).CheckOrderFill(ctx, 84532, args)
+		assert.ErrorIs(t, err, pricefeed.ErrNoPrice)
+
+		notional, err := NewSpendLimiter(big.NewInt(1), nil).CheckOrderFill(ctx, 84532, args)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(0), notional)
+	})
+}
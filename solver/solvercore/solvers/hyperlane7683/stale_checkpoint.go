@@ -0,0 +1,48 @@
+package hyperlane7683
+
+// Module: Stale checkpoint detection
+// - Listeners resume from the LastIndexedBlock persisted in the solver state. When a local fork is
+//   restarted at an earlier block, that checkpoint is ahead of the chain head and the listener would
+//   wait for blocks the chain may not reach for a long time
+// - At startup a checkpoint ahead of the head is reported; when resetting is enabled the checkpoint is
+//   moved back to the head and persisted, otherwise the listener keeps it and waits for the chain to
+//   catch up (e.g. behind an RPC node that lags a few blocks)
+// - Moving back is safe: blocks processed again don't dispatch their events twice (dispatchOnce)
+//
+// Settings:
+// - RESET_STALE_CHECKPOINTS: reset checkpoints ahead of the chain head (default true on devnet, false otherwise)
+
+import (
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+)
+
+// resetStaleCheckpointsFromEnv reports whether checkpoints ahead of the chain head are reset
+func resetStaleCheckpointsFromEnv() bool {
+	return envutil.GetEnvBool("RESET_STALE_CHECKPOINTS", envutil.IsDevnet())
+}
+
+// resolveStaleCheckpoint returns the block a listener resumes from given its persisted checkpoint and
+// the chain head; a checkpoint ahead of the head is logged and, when reset, replaced by the head in the
+// solver state
+func resolveStaleCheckpoint(chainName string, checkpoint, head uint64, reset bool) uint64 {
+	if checkpoint <= head {
+		return checkpoint
+	}
+
+	p := logutil.Prefix(chainName)
+	listenerLog.Printf("%s⚠️  Persisted checkpoint block %d is ahead of the chain head %d - was the chain restarted at an earlier block?\n",
+		p, checkpoint, head)
+	if !reset {
+		listenerLog.Printf("%s⏸️  Keeping the checkpoint: no blocks are processed until the chain reaches block %d "+
+			"(set RESET_STALE_CHECKPOINTS=true or run make clean-solver to resume from the head)\n", p, checkpoint+1)
+		return checkpoint
+	}
+
+	if err := config.UpdateLastIndexedBlock(chainName, head); err != nil {
+		listenerLog.Printf("%s⚠️  Failed to persist the reset checkpoint: %v\n", p, err)
+	}
+	listenerLog.Printf("%s🔄 Checkpoint reset from block %d to the chain head %d\n", p, checkpoint, head)
+	return head
+}
@@ -0,0 +1,77 @@
+package hyperlane7683
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStaleCheckpoint tests that a persisted checkpoint ahead of the chain head, as left by a fork
+// restarted at an earlier block, is detected at startup and reset when enabled
+func TestStaleCheckpoint(t *testing.T) {
+	setup := func(t *testing.T, checkpoint uint64) {
+		t.Helper()
+		t.Setenv("SOLVER_STATE_FILE", filepath.Join(t.TempDir(), "solver-state.json"))
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		t.Cleanup(config.ResetNetworks)
+		require.NoError(t, config.UpdateLastIndexedBlock("Ethereum", checkpoint))
+	}
+	persisted := func(t *testing.T) uint64 {
+		t.Helper()
+		state, err := config.GetSolverState()
+		require.NoError(t, err)
+		return state.Networks["Ethereum"].LastIndexedBlock
+	}
+	resolve := func(t *testing.T, startBlock int64, head uint64) uint64 {
+		t.Helper()
+		backend := chainmock.NewEVMBackend(config.EthereumSepoliaChainID)
+		backend.SetBlockNumber(head)
+		cfg := &base.ListenerConfig{ChainName: "Ethereum", InitialBlock: big.NewInt(startBlock)}
+		resolved, err := ResolveCommonListenerConfig(context.Background(), cfg, backend)
+		require.NoError(t, err)
+		return resolved.LastProcessedBlock
+	}
+
+	t.Run("checkpoint behind the head is kept", func(t *testing.T) {
+		setup(t, 500)
+		t.Setenv("RESET_STALE_CHECKPOINTS", "true")
+		assert.Equal(t, uint64(500), resolve(t, 100, 900))
+		assert.Equal(t, uint64(500), persisted(t))
+	})
+
+	t.Run("stale checkpoint is reset to the head", func(t *testing.T) {
+		setup(t, 900)
+		t.Setenv("RESET_STALE_CHECKPOINTS", "true")
+		assert.Equal(t, uint64(300), resolve(t, 100, 300))
+		assert.Equal(t, uint64(300), persisted(t))
+	})
+
+	t.Run("reset never goes below the config start block", func(t *testing.T) {
+		setup(t, 900)
+		t.Setenv("RESET_STALE_CHECKPOINTS", "true")
+		assert.Equal(t, uint64(400), resolve(t, 400, 300))
+	})
+
+	t.Run("stale checkpoint is kept without reset", func(t *testing.T) {
+		setup(t, 900)
+		t.Setenv("RESET_STALE_CHECKPOINTS", "false")
+		assert.Equal(t, uint64(900), resolve(t, 100, 300))
+		assert.Equal(t, uint64(900), persisted(t))
+	})
+
+	t.Run("reset defaults to devnet", func(t *testing.T) {
+		t.Setenv("RESET_STALE_CHECKPOINTS", "")
+		t.Setenv("IS_DEVNET", "true")
+		assert.True(t, resetStaleCheckpointsFromEnv())
+		t.Setenv("IS_DEVNET", "false")
+		assert.False(t, resetStaleCheckpointsFromEnv())
+	})
+}
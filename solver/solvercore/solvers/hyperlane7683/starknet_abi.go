@@ -0,0 +1,97 @@
+package hyperlane7683
+
+// Module: Starknet Hyperlane7683 ABI
+// - The entries of the Cairo contract ABI (cairo/src/erc7683/interface.cairo) the solver calls, in the
+//   Sierra ABI format of the compiled contract class
+// - Open, fill and settle calldata is serialized from these entries by starknetutil.ABI, which checks the
+//   arguments against the declared types before anything is signed
+
+import "github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+
+const hyperlane7683StarknetABIJSON = `[
+  {
+    "type": "interface",
+    "name": "oif_starknet::erc7683::interface::IOriginSettler",
+    "items": [
+      {
+        "type": "function",
+        "name": "open",
+        "inputs": [
+          {"name": "order", "type": "oif_starknet::erc7683::interface::OnchainCrossChainOrder"}
+        ],
+        "outputs": [],
+        "state_mutability": "external"
+      }
+    ]
+  },
+  {
+    "type": "interface",
+    "name": "oif_starknet::erc7683::interface::IDestinationSettler",
+    "items": [
+      {
+        "type": "function",
+        "name": "fill",
+        "inputs": [
+          {"name": "order_id", "type": "core::integer::u256"},
+          {"name": "origin_data", "type": "alexandria_bytes::bytes::Bytes"},
+          {"name": "filler_data", "type": "alexandria_bytes::bytes::Bytes"}
+        ],
+        "outputs": [],
+        "state_mutability": "external"
+      }
+    ]
+  },
+  {
+    "type": "interface",
+    "name": "oif_starknet::erc7683::interface::IERC7683Extra",
+    "items": [
+      {
+        "type": "function",
+        "name": "settle",
+        "inputs": [
+          {"name": "order_ids", "type": "core::array::Array::<core::integer::u256>"},
+          {"name": "value", "type": "core::integer::u256"}
+        ],
+        "outputs": [],
+        "state_mutability": "external"
+      },
+      {
+        "type": "function",
+        "name": "order_status",
+        "inputs": [
+          {"name": "order_id", "type": "core::integer::u256"}
+        ],
+        "outputs": [{"type": "core::felt252"}],
+        "state_mutability": "view"
+      }
+    ]
+  },
+  {
+    "type": "struct",
+    "name": "oif_starknet::erc7683::interface::OnchainCrossChainOrder",
+    "members": [
+      {"name": "fill_deadline", "type": "core::integer::u64"},
+      {"name": "order_data_type", "type": "core::integer::u256"},
+      {"name": "order_data", "type": "alexandria_bytes::bytes::Bytes"}
+    ]
+  },
+  {
+    "type": "struct",
+    "name": "core::integer::u256",
+    "members": [
+      {"name": "low", "type": "core::integer::u128"},
+      {"name": "high", "type": "core::integer::u128"}
+    ]
+  },
+  {
+    "type": "struct",
+    "name": "alexandria_bytes::bytes::Bytes",
+    "members": [
+      {"name": "size", "type": "core::integer::usize"},
+      {"name": "data", "type": "core::array::Array::<core::integer::u128>"}
+    ]
+  }
+]`
+
+// hyperlane7683StarknetABI serializes calls to the Starknet Hyperlane7683 contract
+var hyperlane7683StarknetABI = starknetutil.MustParseABI(hyperlane7683StarknetABIJSON)
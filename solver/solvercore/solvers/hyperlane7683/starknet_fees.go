@@ -0,0 +1,217 @@
+package hyperlane7683
+
+// Module: Fee estimation and caps for Starknet invoke transactions
+// - Estimates the invoke fee explicitly instead of letting the account do it implicitly
+// - Applies a configurable overhead multiplier to the estimate (resource bounds)
+// - Enforces absolute per-transaction caps on the network fee and aborts fills whose fees use up
+//   the order's margin
+//
+// Caps, each in its own unit (0 or unset = no cap):
+// - STARKNET_MAX_FEE_STRK caps the network fee in STRK (v3 invokes are paid in fri)
+// - STARKNET_MAX_FEE_ETH caps the network fee valued in ETH at the price feed's STRK and ETH prices;
+//   while it is set, transactions are refused when either token has no price
+// - The Hyperlane gas payment attached to settles is capped by SETTLE_MAX_GAS_PAYMENT_ETH (see gas_quotes.go)
+//
+// Margin:
+// - The network fee (STRK) and settle gas payment (ETH) of a fill are valued with the price feed
+//   (see package pricefeed) and must stay below the order's margin, value received − value spent
+// - Fills whose fee tokens or order tokens have no price are sent without the margin check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/pricefeed"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+const (
+	// Default overhead applied to the estimated fee (estimate + 50%), same as starknet.go
+	defaultStarknetFeeMultiplier = 1.5
+	// STRK and ETH both use 18 decimals
+	starknetFeeTokenDecimals = 18
+)
+
+// starknetFeePolicy holds the fee settings applied to every Starknet invoke
+type starknetFeePolicy struct {
+	Multiplier float64
+	MaxFeeFri  *big.Int       // nil = no cap
+	MaxFeeWei  *big.Int       // Network fee valued in ETH; nil = no cap
+	Prices     pricefeed.Feed // nil uses pricefeed.Default()
+}
+
+// starknetFeeEstimate is the worst-case cost of a transaction
+type starknetFeeEstimate struct {
+	MaxFeeFri     *big.Int // network fee upper bound from the resource bounds
+	GasPaymentWei *big.Int // ETH sent to Hyperlane by a settle call, zero if none
+}
+
+// loadStarknetFeePolicy reads the fee policy from the environment
+func loadStarknetFeePolicy() (starknetFeePolicy, error) {
+	policy := starknetFeePolicy{
+		Multiplier: envutil.GetEnvFloat64("STARKNET_FEE_MULTIPLIER", defaultStarknetFeeMultiplier),
+	}
+	if policy.Multiplier < 1 {
+		return policy, fmt.Errorf("STARKNET_FEE_MULTIPLIER must be >= 1, got %v", policy.Multiplier)
+	}
+
+	var err error
+	if policy.MaxFeeFri, err = parseFeeCap("STARKNET_MAX_FEE_STRK"); err != nil {
+		return policy, err
+	}
+	if policy.MaxFeeWei, err = parseFeeCap("STARKNET_MAX_FEE_ETH"); err != nil {
+		return policy, err
+	}
+	return policy, nil
+}
+
+// parseFeeCap parses a decimal token amount (e.g. "0.01") into base units, returning nil when unset or zero
+func parseFeeCap(key string) (*big.Int, error) {
+	value := envutil.GetEnvWithDefault(key, "")
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := amount.Parse(value, starknetFeeTokenDecimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	if parsed.Units.Sign() == 0 {
+		return nil, nil
+	}
+	return parsed.Units, nil
+}
+
+// check returns an error if the estimate exceeds a cap or the margin of order.
+// order is nil when the transaction fills nothing, so there is no margin to check (e.g. settle-only txs).
+func (p starknetFeePolicy) check(ctx context.Context, chainID uint64, estimate starknetFeeEstimate, order *types.ParsedArgs) error {
+	if p.MaxFeeFri != nil && estimate.MaxFeeFri.Cmp(p.MaxFeeFri) > 0 {
+		return fmt.Errorf("estimated fee %s fri exceeds cap %s fri", estimate.MaxFeeFri, p.MaxFeeFri)
+	}
+
+	feed := p.Prices
+	if feed == nil {
+		feed = pricefeed.Default()
+	}
+	if p.MaxFeeWei != nil {
+		feeWei, err := starknetFeeInETH(ctx, feed, chainID, estimate.MaxFeeFri)
+		if err != nil {
+			return fmt.Errorf("cannot value the estimated fee in ETH for STARKNET_MAX_FEE_ETH: %w", err)
+		}
+		if feeWei.Cmp(p.MaxFeeWei) > 0 {
+			return fmt.Errorf("estimated fee %s fri (%s wei) exceeds cap %s wei", estimate.MaxFeeFri, feeWei, p.MaxFeeWei)
+		}
+	}
+
+	if order == nil {
+		return nil
+	}
+	costs := []settlementGas{{chainID: chainID, token: starknetutil.STRKTokenAddress, amount: estimate.MaxFeeFri}}
+	if estimate.GasPaymentWei.Sign() > 0 {
+		costs = append(costs, settlementGas{chainID: chainID, token: starknetETHAddress, amount: estimate.GasPaymentWei})
+	}
+	margin, fees, err := valuedOrderMargin(ctx, feed, order, costs)
+	if errors.Is(err, pricefeed.ErrNoPrice) {
+		fillerLog.Printf("⚠️  Cannot compare the fees of order %s with its margin, sending: %v\n", order.OrderID, err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fees.Cmp(margin) >= 0 {
+		return fmt.Errorf("estimated fees %s would use up the order's margin %s",
+			amount.New(fees, config.NotionalDecimals), amount.New(margin, config.NotionalDecimals))
+	}
+	return nil
+}
+
+// starknetFeeInETH converts a fee in fri to wei at the feed's STRK and ETH prices
+func starknetFeeInETH(ctx context.Context, feed pricefeed.Feed, chainID uint64, fri *big.Int) (*big.Int, error) {
+	value, err := pricefeed.Value(ctx, feed, chainID, starknetutil.STRKTokenAddress, fri)
+	if err != nil {
+		return nil, fmt.Errorf("STRK: %w", err)
+	}
+	ethPrice, err := feed.Price(ctx, chainID, starknetETHAddress)
+	if err != nil {
+		return nil, fmt.Errorf("ETH: %w", err)
+	}
+	if ethPrice.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: ETH is priced at %s", pricefeed.ErrNoPrice, ethPrice)
+	}
+	wei := value.Mul(value, amount.Pow10(starknetFeeTokenDecimals))
+	return wei.Quo(wei, ethPrice), nil
+}
+
+// buildInvokeWithFeeCaps estimates the fee for calls at the given nonce, enforces the fee policy
+// using multiplier as the overhead, and returns the signed transaction ready to broadcast.
+// It mirrors account.BuildAndSendInvokeTxn but checks the estimate before anything is broadcast.
+func (h *HyperlaneStarknet) buildInvokeWithFeeCaps(
+	ctx context.Context,
+	calls []rpc.InvokeFunctionCall,
+	nonce *felt.Felt,
+	multiplier float64,
+	gasPayment *big.Int,
+	order *types.ParsedArgs,
+) (*rpc.BroadcastInvokeTxnV3, error) {
+	callData, err := h.account.FmtCalldata(utils.InvokeFuncCallsToFunctionCalls(calls))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format calldata: %w", err)
+	}
+
+	// Sign with zero resource bounds to estimate the fee
+	zeroBounds := &rpc.ResourceBoundsMapping{
+		L1Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		L1DataGas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		L2Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+	}
+	txn := utils.BuildInvokeTxn(h.account.Address, nonce, callData, zeroBounds, nil)
+	if err := h.account.SignInvokeTransaction(ctx, txn); err != nil {
+		return nil, fmt.Errorf("failed to sign txn for estimation: %w", err)
+	}
+
+	opts := account.TxnOptions{}
+	estimates, err := h.provider.EstimateFee(ctx, []rpc.BroadcastTxn{txn}, opts.SimulationFlags(), opts.BlockID())
+	if err != nil {
+		return nil, fmt.Errorf("fee estimation failed: %w", err)
+	}
+	if len(estimates) == 0 {
+		return nil, fmt.Errorf("fee estimation returned no results")
+	}
+
+	bounds := utils.FeeEstToResBoundsMap(estimates[0], multiplier)
+	maxFee, err := starknetutil.MaxFeeFromBounds(bounds)
+	if err != nil {
+		return nil, err
+	}
+	estimate := starknetFeeEstimate{MaxFeeFri: maxFee, GasPaymentWei: new(big.Int)}
+	if gasPayment != nil {
+		estimate.GasPaymentWei.Set(gasPayment)
+	}
+
+	fmt.Printf("   ⛽ Starknet fee estimate: %s fri (max %s fri with %.2fx overhead)\n",
+		estimates[0].OverallFee.String(), maxFee.String(), multiplier)
+	if err := h.feePolicy.check(ctx, h.chainID, estimate, order); err != nil {
+		return nil, fmt.Errorf("aborting transaction: %w", err)
+	}
+	if err := h.spendLimits.CheckGas(h.chainID, maxFee); err != nil {
+		return nil, fmt.Errorf("aborting transaction: %w", err)
+	}
+
+	// Re-sign with the estimated resource bounds, as they are part of the txn hash
+	txn.ResourceBounds = bounds
+	if err := h.account.SignInvokeTransaction(ctx, txn); err != nil {
+		return nil, fmt.Errorf("failed to sign txn: %w", err)
+	}
+	return txn, nil
+}
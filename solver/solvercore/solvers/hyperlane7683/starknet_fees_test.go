@@ -0,0 +1,132 @@
+package hyperlane7683
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/pricefeed"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStarknetFeePolicy tests loading and enforcing the Starknet fee policy
+func TestStarknetFeePolicy(t *testing.T) {
+	defer func() { require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{})) }()
+	ctx := context.Background()
+	chainID := uint64(config.StarknetSepoliaChainID)
+	dog := "0x0000000000000000000000000000000000000000000000000000000000001234"
+	fee := func(fri, gasPaymentWei int64) starknetFeeEstimate {
+		return starknetFeeEstimate{MaxFeeFri: big.NewInt(fri), GasPaymentWei: big.NewInt(gasPaymentWei)}
+	}
+	applyPrices := func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+			{ChainID: chainID, Address: starknetutil.STRKTokenAddress, Symbol: "STRK", Decimals: 18, Price: "0.5"},
+			{ChainID: chainID, Address: starknetETHAddress, Symbol: "ETH", Decimals: 18, Price: "2500"},
+			{ChainID: chainID, Address: dog, Symbol: "DOG", Decimals: 18, Price: "1"},
+		}}))
+	}
+	// Spends 100 DOG on Starknet Sepolia for DOG received on the same chain
+	orderArgs := func(received *big.Int) *types.ParsedArgs {
+		return &types.ParsedArgs{
+			OrderID: "0x5555555555555555555555555555555555555555555555555555555555555555",
+			ResolvedOrder: types.ResolvedCrossChainOrder{
+				OriginChainID:    big.NewInt(int64(chainID)),
+				MaxSpent:         []types.Output{{Token: dog, Amount: new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))}},
+				MinReceived:      []types.Output{{Token: dog, Amount: received}},
+				FillInstructions: []types.FillInstruction{{DestinationChainID: big.NewInt(int64(chainID))}},
+			},
+		}
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("STARKNET_FEE_MULTIPLIER", "")
+		t.Setenv("STARKNET_MAX_FEE_STRK", "")
+		t.Setenv("STARKNET_MAX_FEE_ETH", "")
+
+		policy, err := loadStarknetFeePolicy()
+		require.NoError(t, err)
+		assert.InDelta(t, defaultStarknetFeeMultiplier, policy.Multiplier, 1e-9)
+		assert.Nil(t, policy.MaxFeeFri)
+		assert.Nil(t, policy.MaxFeeWei)
+	})
+
+	t.Run("caps_are_parsed_in_base_units", func(t *testing.T) {
+		t.Setenv("STARKNET_FEE_MULTIPLIER", "1.2")
+		t.Setenv("STARKNET_MAX_FEE_STRK", "2.5")
+		t.Setenv("STARKNET_MAX_FEE_ETH", "0.01")
+
+		policy, err := loadStarknetFeePolicy()
+		require.NoError(t, err)
+		assert.InDelta(t, 1.2, policy.Multiplier, 1e-9)
+		assert.Equal(t, "2500000000000000000", policy.MaxFeeFri.String())
+		assert.Equal(t, "10000000000000000", policy.MaxFeeWei.String())
+	})
+
+	t.Run("invalid_values", func(t *testing.T) {
+		t.Setenv("STARKNET_FEE_MULTIPLIER", "0.5")
+		_, err := loadStarknetFeePolicy()
+		assert.Error(t, err)
+
+		t.Setenv("STARKNET_FEE_MULTIPLIER", "")
+		t.Setenv("STARKNET_MAX_FEE_STRK", "abc")
+		_, err = loadStarknetFeePolicy()
+		assert.Error(t, err)
+	})
+
+	t.Run("check_strk_cap", func(t *testing.T) {
+		policy := starknetFeePolicy{Multiplier: 1.5, MaxFeeFri: big.NewInt(1000)}
+		assert.NoError(t, policy.check(ctx, chainID, fee(1000, 0), nil))
+		assert.ErrorContains(t, policy.check(ctx, chainID, fee(1001, 0), nil), "estimated fee 1001 fri exceeds cap 1000 fri")
+		// The gas payment is capped by SETTLE_MAX_GAS_PAYMENT_ETH, not the network fee caps
+		assert.NoError(t, policy.check(ctx, chainID, fee(1, 1e18), nil))
+	})
+
+	t.Run("check_eth_cap_values_the_fee_in_eth", func(t *testing.T) {
+		applyPrices(t)
+		// 10 STRK at 0.5 is worth 0.002 ETH at 2500
+		tenSTRK := new(big.Int).Mul(big.NewInt(10), big.NewInt(1e18))
+		estimate := starknetFeeEstimate{MaxFeeFri: tenSTRK, GasPaymentWei: new(big.Int)}
+
+		policy := starknetFeePolicy{Multiplier: 1.5, MaxFeeWei: big.NewInt(2e15)}
+		assert.NoError(t, policy.check(ctx, chainID, estimate, nil))
+		policy.MaxFeeWei = big.NewInt(2e15 - 1)
+		assert.ErrorContains(t, policy.check(ctx, chainID, estimate, nil), "(2000000000000000 wei) exceeds cap 1999999999999999 wei")
+	})
+
+	t.Run("check_eth_cap_refuses_unpriced_fees", func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{}))
+		policy := starknetFeePolicy{Multiplier: 1.5, MaxFeeWei: big.NewInt(1e18)}
+		err := policy.check(ctx, chainID, fee(1, 0), nil)
+		assert.ErrorIs(t, err, pricefeed.ErrNoPrice)
+		assert.ErrorContains(t, err, "STARKNET_MAX_FEE_ETH")
+	})
+
+	t.Run("check_margin_values_fees_and_order", func(t *testing.T) {
+		applyPrices(t)
+		policy := starknetFeePolicy{Multiplier: 1.5}
+		// 100 DOG spent for 110 DOG received: a margin of 10
+		order := orderArgs(new(big.Int).Mul(big.NewInt(110), big.NewInt(1e18)))
+		tenSTRK := new(big.Int).Mul(big.NewInt(10), big.NewInt(1e18))
+
+		// 10 STRK (5) + 0.001 ETH (2.5) stay below the margin
+		ok := starknetFeeEstimate{MaxFeeFri: tenSTRK, GasPaymentWei: big.NewInt(1e15)}
+		assert.NoError(t, policy.check(ctx, chainID, ok, order))
+		assert.NoError(t, policy.check(ctx, chainID, ok, nil))
+
+		// 10 STRK (5) + 0.002 ETH (5) use it up
+		over := starknetFeeEstimate{MaxFeeFri: tenSTRK, GasPaymentWei: big.NewInt(2e15)}
+		assert.EqualError(t, policy.check(ctx, chainID, over, order), "estimated fees 10 would use up the order's margin 10")
+		assert.NoError(t, policy.check(ctx, chainID, over, nil), "transactions that fill nothing have no margin")
+	})
+
+	t.Run("check_margin_skips_unpriced_orders", func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{}))
+		policy := starknetFeePolicy{Multiplier: 1.5}
+		order := orderArgs(big.NewInt(1))
+		assert.NoError(t, policy.check(ctx, chainID, fee(1e18, 1e18), order))
+	})
+}
@@ -0,0 +1,342 @@
+package hyperlane7683
+
+// Module: Starknet nonce management and transaction queue
+// - Tracks the solver account nonce locally instead of re-querying it for every transaction
+// - Serializes submissions through a single queue worker so nonces are handed out in order
+// - Detects stuck transactions and resubmits them with the same nonce and a re-estimated, bumped fee
+//
+// Settings:
+// - STARKNET_TX_STUCK_TIMEOUT_SECONDS: time without a receipt before a tx is considered stuck (default 90)
+// - STARKNET_TX_MAX_RESUBMITS: resubmissions of a stuck tx before giving up (default 2)
+// - STARKNET_TX_FEE_BUMP: factor applied to the fee multiplier on each resubmission (default 1.25)
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+const (
+	defaultStarknetStuckTimeoutSeconds = 90
+	defaultStarknetMaxResubmits        = 2
+	defaultStarknetFeeBump             = 1.25
+	starknetReceiptPollInterval        = 2 * time.Second
+	starknetTxQueueSize                = 16
+)
+
+// errStarknetTxStuck is returned when no submitted version of a transaction was included in time
+var errStarknetTxStuck = errors.New("starknet transaction stuck")
+
+// starknetNonceManager hands out account nonces from a local counter, syncing from chain when unknown
+type starknetNonceManager struct {
+	mu    sync.Mutex
+	fetch func(ctx context.Context) (*felt.Felt, error)
+	next  *felt.Felt // nil until synced, or after Reset
+}
+
+func newStarknetNonceManager(fetch func(ctx context.Context) (*felt.Felt, error)) *starknetNonceManager {
+	return &starknetNonceManager{fetch: fetch}
+}
+
+// Current returns the nonce the next transaction should use
+func (m *starknetNonceManager) Current(ctx context.Context) (*felt.Felt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.next == nil {
+		nonce, err := m.fetch(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch starknet nonce: %w", err)
+		}
+		m.next = nonce
+	}
+	return new(felt.Felt).Set(m.next), nil
+}
+
+// Advance marks the current nonce as used
+func (m *starknetNonceManager) Advance() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.next != nil {
+		m.next = new(felt.Felt).Add(m.next, new(felt.Felt).SetUint64(1))
+	}
+}
+
+// Reset drops the local nonce so the next call to Current resyncs from chain
+func (m *starknetNonceManager) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.next = nil
+}
+
+// starknetQueueConfig controls stuck detection and resubmission
+type starknetQueueConfig struct {
+	StuckTimeout time.Duration
+	MaxResubmits int
+	FeeBump      float64
+}
+
+// loadStarknetQueueConfig reads the queue settings from the environment
+func loadStarknetQueueConfig() (starknetQueueConfig, error) {
+	cfg := starknetQueueConfig{
+		StuckTimeout: time.Duration(envutil.GetEnvInt("STARKNET_TX_STUCK_TIMEOUT_SECONDS", defaultStarknetStuckTimeoutSeconds)) * time.Second,
+		MaxResubmits: envutil.GetEnvInt("STARKNET_TX_MAX_RESUBMITS", defaultStarknetMaxResubmits),
+		FeeBump:      envutil.GetEnvFloat64("STARKNET_TX_FEE_BUMP", defaultStarknetFeeBump),
+	}
+	if cfg.StuckTimeout <= 0 {
+		return cfg, fmt.Errorf("STARKNET_TX_STUCK_TIMEOUT_SECONDS must be > 0")
+	}
+	if cfg.MaxResubmits < 0 {
+		return cfg, fmt.Errorf("STARKNET_TX_MAX_RESUBMITS must be >= 0, got %d", cfg.MaxResubmits)
+	}
+	if cfg.FeeBump < 1 {
+		return cfg, fmt.Errorf("STARKNET_TX_FEE_BUMP must be >= 1, got %v", cfg.FeeBump)
+	}
+	return cfg, nil
+}
+
+// starknetTxTag tells what a transaction is sent for, as recorded in the tx audit log
+type starknetTxTag struct {
+	kind    string // orders.TxKind*
+	orderID string
+}
+
+// starknetTxRequest is a batch of calls waiting to be submitted
+type starknetTxRequest struct {
+	ctx        context.Context
+	tag        starknetTxTag
+	calls      []rpc.InvokeFunctionCall
+	gasPayment *big.Int
+	order      *types.ParsedArgs // Order whose margin the fees are checked against; nil when the tx fills nothing
+	result     chan starknetTxResult
+}
+
+type starknetTxResult struct {
+	hash *felt.Felt
+	fee  rpc.FeePayment // Actual fee from the receipt of the included version
+	err  error
+}
+
+// starknetTxQueue submits transactions one at a time and waits for each to be included
+type starknetTxQueue struct {
+	handler  *HyperlaneStarknet
+	nonces   *starknetNonceManager
+	cfg      starknetQueueConfig
+	requests chan *starknetTxRequest
+}
+
+// newStarknetTxQueue creates the queue and starts its worker
+func newStarknetTxQueue(handler *HyperlaneStarknet, cfg starknetQueueConfig) *starknetTxQueue {
+	q := &starknetTxQueue{
+		handler:  handler,
+		nonces:   newStarknetNonceManager(handler.account.Nonce),
+		cfg:      cfg,
+		requests: make(chan *starknetTxRequest, starknetTxQueueSize),
+	}
+	go q.run()
+	return q
+}
+
+// Submit queues the calls and blocks until the transaction is included or fails.
+// It returns the hash of the included transaction and the fee it actually paid.
+func (q *starknetTxQueue) Submit(ctx context.Context, tag starknetTxTag, calls []rpc.InvokeFunctionCall, gasPayment *big.Int, order *types.ParsedArgs) (*felt.Felt, rpc.FeePayment, error) {
+	req := &starknetTxRequest{
+		ctx:        ctx,
+		tag:        tag,
+		calls:      calls,
+		gasPayment: gasPayment,
+		order:      order,
+		result:     make(chan starknetTxResult, 1),
+	}
+
+	select {
+	case q.requests <- req:
+	case <-ctx.Done():
+		return nil, rpc.FeePayment{}, ctx.Err()
+	}
+
+	select {
+	case res := <-req.result:
+		return res.hash, res.fee, res.err
+	case <-ctx.Done():
+		return nil, rpc.FeePayment{}, ctx.Err()
+	}
+}
+
+func (q *starknetTxQueue) run() {
+	for req := range q.requests {
+		if req.ctx.Err() != nil {
+			req.result <- starknetTxResult{err: req.ctx.Err()}
+			continue
+		}
+		hash, fee, err := q.process(req)
+		req.result <- starknetTxResult{hash: hash, fee: fee, err: err}
+	}
+}
+
+// process submits a request, resubmitting with the same nonce and a bumped fee while it is stuck
+func (q *starknetTxQueue) process(req *starknetTxRequest) (*felt.Felt, rpc.FeePayment, error) {
+	ctx := req.ctx
+	nonce, err := q.nonces.Current(ctx)
+	if err != nil {
+		return nil, rpc.FeePayment{}, err
+	}
+
+	multiplier := q.handler.feePolicy.Multiplier
+	var submitted []*felt.Felt
+	for attempt := 0; attempt <= q.cfg.MaxResubmits; attempt++ {
+		if attempt > 0 {
+			multiplier *= q.cfg.FeeBump
+			fillerLog.Printf("   🔁 Starknet tx stuck at nonce %s, resubmitting (%d/%d) with %.2fx fee overhead\n",
+				nonce.String(), attempt, q.cfg.MaxResubmits, multiplier)
+		}
+
+		hash, err := q.send(ctx, req, nonce, multiplier)
+		if err != nil && len(submitted) == 0 && isStarknetRPCError(err, rpc.ErrInvalidTransactionNonce) {
+			// Local nonce is out of sync (e.g. a tx sent outside the solver); resync and retry once
+			q.nonces.Reset()
+			fresh, nerr := q.nonces.Current(ctx)
+			if nerr != nil {
+				return nil, rpc.FeePayment{}, nerr
+			}
+			fillerLog.Printf("   🔄 Starknet nonce out of sync (had %s, chain has %s), retrying\n", nonce.String(), fresh.String())
+			nonce = fresh
+			hash, err = q.send(ctx, req, nonce, multiplier)
+		}
+		if err != nil {
+			if len(submitted) == 0 {
+				return nil, rpc.FeePayment{}, err
+			}
+			// A failed resubmission usually means an earlier version was just included
+			fillerLog.Printf("   ⚠️  Starknet resubmission failed: %v\n", err)
+		} else {
+			submitted = append(submitted, hash)
+			if len(submitted) == 1 {
+				q.nonces.Advance()
+			}
+		}
+
+		included, fee, err := q.waitForAny(ctx, submitted)
+		if err == nil {
+			q.auditOutcome(req, included, TxAuditSuccess, fee, nil)
+			return included, fee, nil
+		}
+		if !errors.Is(err, errStarknetTxStuck) {
+			if included != nil {
+				q.auditOutcome(req, included, TxAuditReverted, fee, err)
+			} else {
+				q.auditOutcomes(req, submitted, err)
+			}
+			return nil, fee, err
+		}
+	}
+
+	// The nonce may still be pending in the mempool; resync from chain before the next tx
+	q.nonces.Reset()
+	err = fmt.Errorf("%w: nonce %s not included after %d resubmissions", errStarknetTxStuck, nonce.String(), q.cfg.MaxResubmits)
+	q.auditOutcomes(req, submitted, err)
+	return nil, rpc.FeePayment{}, err
+}
+
+// send builds, signs and broadcasts the calls at nonce
+func (q *starknetTxQueue) send(ctx context.Context, req *starknetTxRequest, nonce *felt.Felt, multiplier float64) (*felt.Felt, error) {
+	txn, err := q.handler.buildInvokeWithFeeCaps(ctx, req.calls, nonce, multiplier, req.gasPayment, req.order)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := q.handler.provider.AddInvokeTransaction(ctx, txn)
+	entry := starknetTxAuditEntry(q.handler.chainID, req.tag.kind, req.tag.orderID, req.calls, txn)
+	if err != nil {
+		entry.Result = TxAuditFailed
+		entry.Error = err.Error()
+		q.handler.txAudit.record(entry)
+		return nil, err
+	}
+	entry.Result = TxAuditSent
+	entry.TxHash = resp.Hash.String()
+	q.handler.txAudit.record(entry)
+	q.handler.txOutbox.AddTx(req.tag.kind, q.handler.chainID, req.tag.orderID, resp.Hash.String())
+	q.handler.recordSent.record(req.tag.orderID, q.handler.chainID, req.tag.kind, resp.Hash.String())
+
+	fillerLog.Printf("   🔄 Starknet tx sent (%d calls, nonce %s): %s\n", len(req.calls), nonce.String(), logutil.TxRef(q.handler.chainID, resp.Hash.String()))
+	return resp.Hash, nil
+}
+
+// waitForAny polls for a receipt of any submitted hash until the stuck timeout elapses.
+// A reverted transaction is returned with its hash, its fee and an error.
+func (q *starknetTxQueue) waitForAny(ctx context.Context, hashes []*felt.Felt) (*felt.Felt, rpc.FeePayment, error) {
+	deadline := time.Now().Add(q.cfg.StuckTimeout)
+	ticker := time.NewTicker(starknetReceiptPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, hash := range hashes {
+			receipt, err := q.handler.provider.TransactionReceipt(ctx, hash)
+			if err != nil {
+				if isStarknetRPCError(err, rpc.ErrHashNotFound) {
+					continue
+				}
+				return nil, rpc.FeePayment{}, fmt.Errorf("failed to get receipt for %s: %w", hash.String(), err)
+			}
+			if receipt.ExecutionStatus == rpc.TxnExecutionStatusREVERTED {
+				// The fee of a reverted tx is still charged, so it is returned with the error
+				return hash, receipt.ActualFee, fmt.Errorf("starknet tx %s reverted: %s", hash.String(), receipt.RevertReason)
+			}
+			return hash, receipt.ActualFee, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, rpc.FeePayment{}, errStarknetTxStuck
+		}
+		select {
+		case <-ctx.Done():
+			return nil, rpc.FeePayment{}, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// isStarknetRPCError reports whether err is the given Starknet RPC error (matched by code)
+func isStarknetRPCError(err error, target *rpc.RPCError) bool {
+	var rpcErr *rpc.RPCError
+	return errors.As(err, &rpcErr) && rpcErr.Code == target.Code
+}
+
+// auditOutcome records the outcome of the submitted transaction hash
+func (q *starknetTxQueue) auditOutcome(req *starknetTxRequest, hash *felt.Felt, result string, fee rpc.FeePayment, txErr error) {
+	entry := TxAuditEntry{
+		ChainID: q.handler.chainID,
+		Kind:    req.tag.kind,
+		OrderID: req.tag.orderID,
+		Result:  result,
+		TxHash:  hash.String(),
+		FeeUnit: string(fee.Unit),
+	}
+	if fee.Amount != nil {
+		entry.Fee = fee.Amount.BigInt(new(big.Int)).String()
+	}
+	if txErr != nil {
+		entry.Error = txErr.Error()
+	}
+	q.handler.txAudit.record(entry)
+}
+
+// auditOutcomes records every submitted version of a request that was given up on as failed
+func (q *starknetTxQueue) auditOutcomes(req *starknetTxRequest, submitted []*felt.Felt, txErr error) {
+	for _, hash := range submitted {
+		q.auditOutcome(req, hash, TxAuditFailed, rpc.FeePayment{}, txErr)
+	}
+}
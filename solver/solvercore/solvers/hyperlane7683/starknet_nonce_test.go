@@ -0,0 +1,107 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStarknetNonceManager tests local nonce tracking
+func TestStarknetNonceManager(t *testing.T) {
+	t.Run("syncs_once_then_tracks_locally", func(t *testing.T) {
+		fetches := 0
+		m := newStarknetNonceManager(func(context.Context) (*felt.Felt, error) {
+			fetches++
+			return new(felt.Felt).SetUint64(7), nil
+		})
+
+		nonce, err := m.Current(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(7), nonce.Uint64())
+
+		m.Advance()
+		m.Advance()
+		nonce, err = m.Current(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(9), nonce.Uint64())
+		assert.Equal(t, 1, fetches)
+	})
+
+	t.Run("reset_resyncs_from_chain", func(t *testing.T) {
+		chainNonce := uint64(3)
+		m := newStarknetNonceManager(func(context.Context) (*felt.Felt, error) {
+			return new(felt.Felt).SetUint64(chainNonce), nil
+		})
+
+		_, err := m.Current(context.Background())
+		require.NoError(t, err)
+		m.Advance()
+
+		chainNonce = 10
+		m.Reset()
+		nonce, err := m.Current(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(10), nonce.Uint64())
+	})
+
+	t.Run("returned_nonce_is_a_copy", func(t *testing.T) {
+		m := newStarknetNonceManager(func(context.Context) (*felt.Felt, error) {
+			return new(felt.Felt).SetUint64(1), nil
+		})
+
+		nonce, err := m.Current(context.Background())
+		require.NoError(t, err)
+		nonce.SetUint64(100)
+
+		again, err := m.Current(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), again.Uint64())
+	})
+
+	t.Run("fetch_error", func(t *testing.T) {
+		m := newStarknetNonceManager(func(context.Context) (*felt.Felt, error) {
+			return nil, errors.New("rpc down")
+		})
+
+		_, err := m.Current(context.Background())
+		assert.ErrorContains(t, err, "rpc down")
+	})
+}
+
+// TestStarknetQueueConfig tests loading the tx queue settings
+func TestStarknetQueueConfig(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("STARKNET_TX_STUCK_TIMEOUT_SECONDS", "")
+		t.Setenv("STARKNET_TX_MAX_RESUBMITS", "")
+		t.Setenv("STARKNET_TX_FEE_BUMP", "")
+
+		cfg, err := loadStarknetQueueConfig()
+		require.NoError(t, err)
+		assert.Equal(t, defaultStarknetStuckTimeoutSeconds*time.Second, cfg.StuckTimeout)
+		assert.Equal(t, defaultStarknetMaxResubmits, cfg.MaxResubmits)
+		assert.InDelta(t, defaultStarknetFeeBump, cfg.FeeBump, 1e-9)
+	})
+
+	t.Run("invalid_fee_bump", func(t *testing.T) {
+		t.Setenv("STARKNET_TX_FEE_BUMP", "0.9")
+
+		_, err := loadStarknetQueueConfig()
+		assert.Error(t, err)
+	})
+}
+
+// TestIsStarknetRPCError tests matching wrapped Starknet RPC errors
+func TestIsStarknetRPCError(t *testing.T) {
+	wrapped := fmt.Errorf("send failed: %w", &rpc.RPCError{Code: rpc.ErrInvalidTransactionNonce.Code, Message: "nonce"})
+
+	assert.True(t, isStarknetRPCError(wrapped, rpc.ErrInvalidTransactionNonce))
+	assert.False(t, isStarknetRPCError(wrapped, rpc.ErrHashNotFound))
+	assert.False(t, isStarknetRPCError(errors.New("plain"), rpc.ErrHashNotFound))
+}
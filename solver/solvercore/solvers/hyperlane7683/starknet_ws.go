@@ -0,0 +1,182 @@
+package hyperlane7683
+
+// Module: Starknet WebSocket event subscription (optional)
+// - With a WebSocket endpoint configured, the Starknet listener subscribes to the Open events of its
+//   router (starknet_subscribeEvents, served by pathfinder and juno)
+// - A notification wakes the polling loop right away instead of waiting for the poll interval; the
+//   events are still fetched by the regular block range processing, so confirmations, checkpoints
+//   and dedupe work as with polling alone
+// - Polling goes on either way: if the first subscription fails (no WebSocket support, method not
+//   found) the listener only polls; a subscription dropped later is retried with backoff
+//
+// Settings:
+// - STARKNET_WS_URL (LOCAL_STARKNET_WS_URL with IS_DEVNET=true): WebSocket RPC endpoint (unset: polling only)
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/starknet.go/client"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+const (
+	starknetWSRetryMin = time.Second
+	starknetWSRetryMax = time.Minute
+)
+
+// eventSubscription is a live starknet_subscribeEvents stream
+type eventSubscription interface {
+	Err() <-chan error
+	Unsubscribe()
+}
+
+// subscribeEventsFunc opens an event subscription delivering to events
+type subscribeEventsFunc func(ctx context.Context, events chan<- *rpc.EmittedEventWithFinalityStatus, input *rpc.EventSubscriptionInput) (eventSubscription, error)
+
+// starknetEventWaker wakes the Starknet polling loop on Open event notifications. A nil waker
+// never wakes it, the loop then waits the full poll interval.
+type starknetEventWaker struct {
+	chainName string
+	subscribe subscribeEventsFunc
+	input     *rpc.EventSubscriptionInput
+	wake      chan struct{}
+	retryMin  time.Duration
+	retryMax  time.Duration
+}
+
+// newStarknetEventWaker creates a waker subscribing to the Open events emitted by contract
+func newStarknetEventWaker(chainName string, contract *felt.Felt, subscribe subscribeEventsFunc) *starknetEventWaker {
+	return &starknetEventWaker{
+		chainName: chainName,
+		subscribe: subscribe,
+		input:     &rpc.EventSubscriptionInput{FromAddress: contract, Keys: [][]*felt.Felt{{openEventSelector}}},
+		wake:      make(chan struct{}, 1),
+		retryMin:  starknetWSRetryMin,
+		retryMax:  starknetWSRetryMax,
+	}
+}
+
+// starknetEventWakerFromEnv returns a waker on the configured WebSocket endpoint, or nil when none is set
+func starknetEventWakerFromEnv(chainName string, contract *felt.Felt) *starknetEventWaker {
+	url := envutil.GetConditionalEnv("STARKNET_WS_URL", "")
+	if url == "" {
+		return nil
+	}
+	return newStarknetEventWaker(chainName, contract, dialEventSubscription(url))
+}
+
+// wsSubscription closes its WebSocket connection on Unsubscribe
+type wsSubscription struct {
+	*client.ClientSubscription
+	provider *rpc.WsProvider
+}
+
+func (s *wsSubscription) Unsubscribe() {
+	s.ClientSubscription.Unsubscribe()
+	s.provider.Close()
+}
+
+// dialEventSubscription subscribes over a new WebSocket connection to url
+func dialEventSubscription(url string) subscribeEventsFunc {
+	return func(ctx context.Context, events chan<- *rpc.EmittedEventWithFinalityStatus, input *rpc.EventSubscriptionInput) (eventSubscription, error) {
+		provider, err := rpc.NewWebsocketProvider(url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect Starknet WebSocket: %w", err)
+		}
+		sub, err := provider.SubscribeEvents(ctx, events, input)
+		if err != nil {
+			provider.Close()
+			return nil, fmt.Errorf("starknet_subscribeEvents: %w", err)
+		}
+		return &wsSubscription{ClientSubscription: sub, provider: provider}, nil
+	}
+}
+
+// Run keeps the subscription open until ctx is done or stop is closed
+func (w *starknetEventWaker) Run(ctx context.Context, stop <-chan struct{}) {
+	if w == nil {
+		return
+	}
+	p := logutil.Prefix(w.chainName)
+	subscribed := false
+	retry := w.retryMin
+	for {
+		connected, err := w.listen(ctx, stop, func() {
+			if !subscribed {
+				listenerLog.Printf("%s🔌 Subscribed to Open events over WebSocket\n", p)
+			}
+			subscribed = true
+			retry = w.retryMin
+		})
+		if err == nil {
+			return
+		}
+		if !subscribed {
+			listenerLog.Printf("%s⚠️  WebSocket event subscription unavailable, polling only: %v\n", p, err)
+			return
+		}
+		if connected {
+			listenerLog.Printf("%s⚠️  WebSocket event subscription dropped, retrying in %s: %v\n", p, retry, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case <-time.After(retry):
+		}
+		retry = min(retry*2, w.retryMax)
+	}
+}
+
+// listen runs one subscription. It returns nil once stopped, or the error that ended the
+// subscription; connected reports whether the subscription had been opened.
+func (w *starknetEventWaker) listen(ctx context.Context, stop <-chan struct{}, onSubscribed func()) (bool, error) {
+	events := make(chan *rpc.EmittedEventWithFinalityStatus, 16)
+	sub, err := w.subscribe(ctx, events, w.input)
+	if err != nil {
+		return false, err
+	}
+	defer sub.Unsubscribe()
+	onSubscribed()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, nil
+		case <-stop:
+			return true, nil
+		case err := <-sub.Err():
+			if err == nil {
+				err = fmt.Errorf("subscription closed")
+			}
+			return true, err
+		case <-events:
+			select {
+			case w.wake <- struct{}{}:
+			default: // a wake-up is already pending
+			}
+		}
+	}
+}
+
+// Wait blocks for d, or until an Open event notification arrives, ctx is done or stop is closed
+func (w *starknetEventWaker) Wait(ctx context.Context, stop <-chan struct{}, d time.Duration) {
+	var wake <-chan struct{}
+	if w != nil {
+		wake = w.wake
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-wake:
+	case <-ctx.Done():
+	case <-stop:
+	}
+}
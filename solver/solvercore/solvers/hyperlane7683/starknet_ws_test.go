@@ -0,0 +1,97 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSubscription is an event subscription closed by sending on errs
+type fakeSubscription struct {
+	errs         chan error
+	unsubscribed atomic.Bool
+}
+
+func (s *fakeSubscription) Err() <-chan error { return s.errs }
+func (s *fakeSubscription) Unsubscribe()      { s.unsubscribed.Store(true) }
+
+func TestStarknetEventWaker(t *testing.T) {
+	contract := new(felt.Felt).SetUint64(0xabc)
+
+	t.Run("notifications wake the polling loop", func(t *testing.T) {
+		events := make(chan chan<- *rpc.EmittedEventWithFinalityStatus, 1)
+		sub := &fakeSubscription{errs: make(chan error, 1)}
+		waker := newStarknetEventWaker("Starknet", contract, func(_ context.Context, ch chan<- *rpc.EmittedEventWithFinalityStatus, input *rpc.EventSubscriptionInput) (eventSubscription, error) {
+			assert.Equal(t, contract, input.FromAddress)
+			assert.Equal(t, openEventSelector, input.Keys[0][0])
+			events <- ch
+			return sub, nil
+		})
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() { waker.Run(context.Background(), stop); close(done) }()
+
+		ch := <-events
+		ch <- &rpc.EmittedEventWithFinalityStatus{}
+		start := time.Now()
+		waker.Wait(context.Background(), nil, time.Minute)
+		assert.Less(t, time.Since(start), 10*time.Second)
+
+		close(stop)
+		<-done
+		assert.True(t, sub.unsubscribed.Load())
+	})
+
+	t.Run("failed first subscription falls back to polling", func(t *testing.T) {
+		var attempts atomic.Int32
+		waker := newStarknetEventWaker("Starknet", contract, func(context.Context, chan<- *rpc.EmittedEventWithFinalityStatus, *rpc.EventSubscriptionInput) (eventSubscription, error) {
+			attempts.Add(1)
+			return nil, errors.New("Method not found")
+		})
+		waker.Run(context.Background(), make(chan struct{}))
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+
+	t.Run("dropped subscription is reopened", func(t *testing.T) {
+		subs := make(chan *fakeSubscription, 2)
+		waker := newStarknetEventWaker("Starknet", contract, func(context.Context, chan<- *rpc.EmittedEventWithFinalityStatus, *rpc.EventSubscriptionInput) (eventSubscription, error) {
+			sub := &fakeSubscription{errs: make(chan error, 1)}
+			subs <- sub
+			return sub, nil
+		})
+		waker.retryMin = time.Millisecond
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() { waker.Run(ctx, nil); close(done) }()
+
+		first := <-subs
+		first.errs <- errors.New("connection reset")
+		select {
+		case <-subs:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "subscription not reopened")
+		}
+		cancel()
+		<-done
+		assert.True(t, first.unsubscribed.Load())
+	})
+
+	t.Run("nil waker waits the poll interval", func(t *testing.T) {
+		var waker *starknetEventWaker
+		waker.Run(context.Background(), nil)
+		start := time.Now()
+		waker.Wait(context.Background(), nil, 20*time.Millisecond)
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+		stop := make(chan struct{})
+		close(stop)
+		waker.Wait(context.Background(), stop, time.Minute)
+	})
+}
@@ -0,0 +1,140 @@
+package hyperlane7683
+
+// Module: On-chain token metadata discovery
+// - For order tokens missing from the token registry, reads symbol()/decimals() on EVM chains and
+//   the Cairo symbol/decimals entry points on Starknet
+// - Results are cached in the solver state file (config.SaveDiscoveredToken) and used to format
+//   amounts in logs and reports and to normalize fill notionals
+// - Failed lookups are retried after discoveryRetryAfter so broken tokens don't cost an RPC call per order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+const discoveryRetryAfter = 10 * time.Minute
+
+// errDiscoveryBackoff is returned for tokens whose lookup failed less than discoveryRetryAfter ago
+var errDiscoveryBackoff = errors.New("metadata lookup failed recently")
+
+// TokenDiscovery looks up and caches metadata of tokens the registry doesn't know
+type TokenDiscovery struct {
+	getEVMClient      func(chainID uint64) (EVMClient, error)
+	getStarknetClient func(chainID uint64) (rpc.RPCProvider, error)
+
+	mu     sync.Mutex
+	failed map[string]time.Time // "<chainID>:<address>" -> time of the last failed lookup
+	now    func() time.Time
+}
+
+// NewTokenDiscovery creates a TokenDiscovery using the solver's client getters
+func NewTokenDiscovery(
+	getEVMClient func(chainID uint64) (EVMClient, error),
+	getStarknetClient func(chainID uint64) (rpc.RPCProvider, error),
+) *TokenDiscovery {
+	return &TokenDiscovery{
+		getEVMClient:      getEVMClient,
+		getStarknetClient: getStarknetClient,
+		failed:            make(map[string]time.Time),
+		now:               time.Now,
+	}
+}
+
+// Discover returns the metadata of a token, reading it on-chain when neither the registry nor
+// the cache has it
+func (d *TokenDiscovery) Discover(_ context.Context, chainID uint64, address string) (config.TokenConfig, error) {
+	if token, ok := config.LookupToken(chainID, address); ok {
+		return token, nil
+	}
+
+	key := fmt.Sprintf("%d:%s", chainID, address)
+	d.mu.Lock()
+	failedAt, failed := d.failed[key]
+	d.mu.Unlock()
+	if failed && d.now().Sub(failedAt) < discoveryRetryAfter {
+		return config.TokenConfig{}, fmt.Errorf("token %s on chain %d: %w", address, chainID, errDiscoveryBackoff)
+	}
+
+	token, err := d.fetch(chainID, address)
+	if err == nil {
+		err = config.SaveDiscoveredToken(token)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err != nil {
+		d.failed[key] = d.now()
+		return config.TokenConfig{}, err
+	}
+	delete(d.failed, key)
+	return token, nil
+}
+
+// fetch reads symbol and decimals from the token contract
+func (d *TokenDiscovery) fetch(chainID uint64, address string) (config.TokenConfig, error) {
+	var symbol string
+	var decimals uint8
+	if isStarknetChain(chainID) {
+		provider, err := d.getStarknetClient(chainID)
+		if err != nil {
+			return config.TokenConfig{}, fmt.Errorf("failed to get Starknet client for chain %d: %w", chainID, err)
+		}
+		if symbol, decimals, err = starknetutil.ERC20Metadata(provider, address); err != nil {
+			return config.TokenConfig{}, fmt.Errorf("failed to read metadata of token %s: %w", address, err)
+		}
+	} else {
+		client, err := d.getEVMClient(chainID)
+		if err != nil {
+			return config.TokenConfig{}, fmt.Errorf("failed to get EVM client for chain %d: %w", chainID, err)
+		}
+		tokenAddr, err := types.ToEVMAddress(address)
+		if err != nil {
+			return config.TokenConfig{}, fmt.Errorf("failed to convert token address %s: %w", address, err)
+		}
+		if symbol, decimals, err = ethutil.ERC20Metadata(client, tokenAddr); err != nil {
+			return config.TokenConfig{}, fmt.Errorf("failed to read metadata of token %s: %w", address, err)
+		}
+	}
+	return config.TokenConfig{ChainID: chainID, Address: address, Symbol: symbol, Decimals: int(decimals)}, nil
+}
+
+// DiscoverOrderTokens discovers the tokens an order spends (on the destination) and receives
+// (on the origin); failures are logged and do not affect the order
+func (d *TokenDiscovery) DiscoverOrderTokens(ctx context.Context, args *types.ParsedArgs) {
+	var destinationChainID *big.Int
+	if len(args.ResolvedOrder.FillInstructions) > 0 {
+		destinationChainID = args.ResolvedOrder.FillInstructions[0].DestinationChainID
+	}
+	discover := func(outputs []types.Output, fallback *big.Int) {
+		for _, output := range outputs {
+			// Native token
+			if types.IsNativeToken(output.Token) {
+				continue
+			}
+			chainID := outputChainID(output, fallback)
+			if _, ok := config.LookupToken(chainID, output.Token); ok {
+				continue
+			}
+			token, err := d.Discover(ctx, chainID, output.Token)
+			if errors.Is(err, errDiscoveryBackoff) {
+				continue
+			}
+			if err != nil {
+				fmt.Printf("⚠️  Token metadata discovery failed: %v\n", err)
+				continue
+			}
+			fmt.Printf("🔎 Discovered token %s on chain %d: %s (%d decimals)\n", output.Token, chainID, token.Symbol, token.Decimals)
+		}
+	}
+	discover(args.ResolvedOrder.MaxSpent, destinationChainID)
+	discover(args.ResolvedOrder.MinReceived, args.ResolvedOrder.OriginChainID)
+}
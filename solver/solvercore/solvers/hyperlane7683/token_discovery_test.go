@@ -0,0 +1,146 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenDiscovery(t *testing.T) {
+	t.Setenv("SOLVER_STATE_FILE", filepath.Join(t.TempDir(), "solver-state.json"))
+	config.ResetDiscoveredTokens()
+	t.Cleanup(config.ResetDiscoveredTokens)
+	config.ResetNetworks()
+	config.InitializeNetworks()
+	defer config.ResetNetworks()
+
+	token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	stringResult := func(s string) chainmock.EVMCallHandler {
+		return func(ethereum.CallMsg) ([]byte, error) {
+			encoded := common.LeftPadBytes(big.NewInt(32).Bytes(), 32)
+			encoded = append(encoded, common.LeftPadBytes(big.NewInt(int64(len(s))).Bytes(), 32)...)
+			return append(encoded, common.RightPadBytes([]byte(s), 32)...), nil
+		}
+	}
+	newEVMDiscovery := func(backend *chainmock.EVMBackend) *TokenDiscovery {
+		return NewTokenDiscovery(func(uint64) (EVMClient, error) { return backend, nil }, nil)
+	}
+
+	t.Run("EVM metadata is read once and cached", func(t *testing.T) {
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		calls := 0
+		backend.HandleCall(token, chainmock.Selector("symbol()"), func(msg ethereum.CallMsg) ([]byte, error) {
+			calls++
+			return stringResult("DOG")(msg)
+		})
+		backend.HandleCall(token, chainmock.Selector("decimals()"), uint256Result(big.NewInt(6)))
+		discovery := newEVMDiscovery(backend)
+
+		for range 2 {
+			discovered, err := discovery.Discover(context.Background(), config.BaseSepoliaChainID, token.Hex())
+			require.NoError(t, err)
+			assert.Equal(t, "DOG", discovered.Symbol)
+			assert.Equal(t, 6, discovered.Decimals)
+		}
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, "1.5 DOG", config.FormatTokenAmount(config.BaseSepoliaChainID, token.Hex(), big.NewInt(1_500_000)))
+
+		// Survives a restart through the state file
+		config.ResetDiscoveredTokens()
+		_, ok := config.LookupDiscoveredToken(config.BaseSepoliaChainID, token.Hex())
+		assert.True(t, ok)
+	})
+
+	t.Run("registered tokens are not queried", func(t *testing.T) {
+		require.NoError(t, config.ApplyRuntime(&config.RuntimeConfig{Tokens: []config.TokenConfig{
+			{ChainID: config.EthereumSepoliaChainID, Address: token.Hex(), Symbol: "REG", Decimals: 2},
+		}}))
+		defer config.ApplyRuntime(&config.RuntimeConfig{})
+
+		discovered, err := newEVMDiscovery(chainmock.NewEVMBackend(config.EthereumSepoliaChainID)).
+			Discover(context.Background(), config.EthereumSepoliaChainID, token.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, "REG", discovered.Symbol)
+	})
+
+	t.Run("failed lookups back off", func(t *testing.T) {
+		backend := chainmock.NewEVMBackend(config.OptimismSepoliaChainID)
+		discovery := newEVMDiscovery(backend)
+		now := time.Now()
+		discovery.now = func() time.Time { return now }
+
+		_, err := discovery.Discover(context.Background(), config.OptimismSepoliaChainID, token.Hex())
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, errDiscoveryBackoff))
+
+		backend.HandleCall(token, chainmock.Selector("symbol()"), stringResult("DOG"))
+		backend.HandleCall(token, chainmock.Selector("decimals()"), uint256Result(big.NewInt(18)))
+		_, err = discovery.Discover(context.Background(), config.OptimismSepoliaChainID, token.Hex())
+		assert.ErrorIs(t, err, errDiscoveryBackoff)
+
+		now = now.Add(discoveryRetryAfter)
+		discovered, err := discovery.Discover(context.Background(), config.OptimismSepoliaChainID, token.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, 18, discovered.Decimals)
+	})
+
+	t.Run("Starknet metadata", func(t *testing.T) {
+		const starknetToken = "0x0000000000000000000000000000000000000000000000000000000000001234"
+		tokenFelt, err := utils.HexToFelt(starknetToken)
+		require.NoError(t, err)
+		backend := chainmock.NewStarknetBackend()
+		backend.HandleCall(tokenFelt, "symbol", func(rpc.FunctionCall) ([]*felt.Felt, error) {
+			return utils.StringToByteArrFelt("SDOG")
+		})
+		backend.HandleCall(tokenFelt, "decimals", func(rpc.FunctionCall) ([]*felt.Felt, error) {
+			return []*felt.Felt{new(felt.Felt).SetUint64(8)}, nil
+		})
+		discovery := NewTokenDiscovery(nil, func(uint64) (rpc.RPCProvider, error) { return backend, nil })
+
+		discovered, err := discovery.Discover(context.Background(), config.StarknetSepoliaChainID, starknetToken)
+		require.NoError(t, err)
+		assert.Equal(t, config.TokenConfig{ChainID: config.StarknetSepoliaChainID, Address: starknetToken, Symbol: "SDOG", Decimals: 8}, discovered)
+	})
+
+	t.Run("order tokens are discovered on their chains", func(t *testing.T) {
+		config.ResetDiscoveredTokens()
+		origin := chainmock.NewEVMBackend(config.ArbitrumSepoliaChainID)
+		origin.HandleCall(token, chainmock.Selector("symbol()"), stringResult("ARB"))
+		origin.HandleCall(token, chainmock.Selector("decimals()"), uint256Result(big.NewInt(18)))
+		discovery := NewTokenDiscovery(func(chainID uint64) (EVMClient, error) {
+			if chainID == config.ArbitrumSepoliaChainID {
+				return origin, nil
+			}
+			return nil, errors.New("no client")
+		}, nil)
+
+		args := &types.ParsedArgs{ResolvedOrder: types.ResolvedCrossChainOrder{
+			OriginChainID:    big.NewInt(config.ArbitrumSepoliaChainID),
+			MaxSpent:         []types.Output{{Token: "0x0", Amount: big.NewInt(1)}, {Token: token.Hex(), Amount: big.NewInt(1)}},
+			MinReceived:      []types.Output{{Token: token.Hex(), Amount: big.NewInt(1)}},
+			FillInstructions: []types.FillInstruction{{DestinationChainID: big.NewInt(config.BaseSepoliaChainID)}},
+		}}
+		discovery.DiscoverOrderTokens(context.Background(), args)
+
+		received, ok := config.LookupDiscoveredToken(config.ArbitrumSepoliaChainID, token.Hex())
+		require.True(t, ok)
+		assert.Equal(t, "ARB", received.Symbol)
+		spent, ok := config.LookupDiscoveredToken(config.BaseSepoliaChainID, token.Hex())
+		require.True(t, ok, "cached by the first subtest")
+		assert.Equal(t, "DOG", spent.Symbol)
+	})
+}
@@ -0,0 +1,217 @@
+package hyperlane7683
+
+// Module: Outbound transaction audit log
+// - Appends every transaction the solver signs to a JSONL file, a forensic trail independent of node and process logs
+// - A transaction gets a "sent" entry when it is broadcast, then one entry with its outcome: "success",
+//   "reverted" or "failed" (not broadcast, or given up on before a receipt was seen)
+// - Entries carry the chain, kind (approve, fill, settle, fill+settle), order, target contracts, nonce,
+//   keccak256 hash of the calldata, gas parameters, tx hash and, once included, the fee paid
+// - Starknet resubmissions with a bumped fee are separate transactions and each get a "sent" entry
+// - The log is only ever appended to; a failed write is logged and never holds up a transaction
+// - Entries go to the tx_audit log of the storage backend: a JSONL file with the file backend
+//
+// Settings:
+// - SOLVER_TX_AUDIT_FILE: path of the audit log of the file backend (default state/solver_state/tx-audit.jsonl)
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Results of audited transactions
+const (
+	TxAuditSent     = "sent"
+	TxAuditSuccess  = "success"
+	TxAuditReverted = "reverted"
+	TxAuditFailed   = "failed"
+)
+
+// TxAuditEntry is one step in the life of a signed transaction
+type TxAuditEntry struct {
+	Time         time.Time         `json:"time"`
+	ChainID      uint64            `json:"chainId"`
+	Kind         string            `json:"kind"` // orders.TxKind*
+	OrderID      string            `json:"orderId,omitempty"`
+	Result       string            `json:"result"`
+	TxHash       string            `json:"txHash,omitempty"`
+	To           []string          `json:"to,omitempty"` // Contract called; every call target of a Starknet multicall
+	Nonce        string            `json:"nonce,omitempty"`
+	CalldataHash string            `json:"calldataHash,omitempty"`
+	Gas          map[string]string `json:"gas,omitempty"` // Gas limit and price fields of the transaction type
+	GasUsed      uint64            `json:"gasUsed,omitempty"`
+	Fee          string            `json:"fee,omitempty"`
+	FeeUnit      string            `json:"feeUnit,omitempty"`
+	Error        string            `json:"error,omitempty"`
+}
+
+// TxAuditLog appends signed transactions to a storage log. Methods are safe for concurrent use and a nil log records nothing.
+type TxAuditLog struct {
+	path    string // File of the file backend, empty for database backends
+	backend storage.Storage
+	now     func() time.Time
+}
+
+// NewTxAuditLog creates an audit log writing to the file at path; the file is created on the first entry
+func NewTxAuditLog(path string) *TxAuditLog {
+	backend := storage.NewFileStorage(filepath.Dir(path), map[string]string{storage.TxAudit: path})
+	return &TxAuditLog{path: path, backend: backend, now: time.Now}
+}
+
+// TxAuditLogFromEnv returns the audit log of the default storage backend, the file at SOLVER_TX_AUDIT_FILE
+// with the file backend
+func TxAuditLogFromEnv() *TxAuditLog {
+	return txAuditLogIn(storage.Default())
+}
+
+// txAuditLogIn returns the audit log of backend, the file at SOLVER_TX_AUDIT_FILE when backend is
+// the file backend or failed to open
+func txAuditLogIn(backend storage.Storage, err error) *TxAuditLog {
+	if err != nil || backend.Backend() == storage.BackendFile {
+		return NewTxAuditLog(storage.FilePath(storage.TxAudit))
+	}
+	return &TxAuditLog{backend: backend, now: time.Now}
+}
+
+// Path returns the audit log file, empty for database backends
+func (a *TxAuditLog) Path() string {
+	return a.path
+}
+
+// Record appends entry, stamped with the current time
+func (a *TxAuditLog) Record(entry TxAuditEntry) error {
+	if a == nil {
+		return nil
+	}
+	entry.Time = a.now().UTC()
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode tx audit entry: %w", err)
+	}
+	if err := a.backend.Append(storage.TxAudit, line); err != nil {
+		return fmt.Errorf("failed to write tx audit entry: %w", err)
+	}
+	return nil
+}
+
+// record appends entry, logging instead of returning a write error
+func (a *TxAuditLog) record(entry TxAuditEntry) {
+	if err := a.Record(entry); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+}
+
+// evmTxAuditEntry describes a signed EVM transaction; tx is nil when signing or sending failed before a
+// transaction was returned, and only the target contract is known
+func evmTxAuditEntry(chainID uint64, kind string, args *types.ParsedArgs, to string, tx *gethtypes.Transaction) TxAuditEntry {
+	entry := TxAuditEntry{ChainID: chainID, Kind: kind, OrderID: auditOrderID(args), To: []string{to}}
+	if tx == nil {
+		return entry
+	}
+	entry.TxHash = tx.Hash().Hex()
+	entry.Nonce = fmt.Sprintf("%d", tx.Nonce())
+	entry.CalldataHash = crypto.Keccak256Hash(tx.Data()).Hex()
+	entry.Gas = map[string]string{"gasLimit": fmt.Sprintf("%d", tx.Gas())}
+	if tx.Type() == gethtypes.LegacyTxType || tx.Type() == gethtypes.AccessListTxType {
+		entry.Gas["gasPrice"] = tx.GasPrice().String()
+	} else {
+		entry.Gas["gasFeeCap"] = tx.GasFeeCap().String()
+		entry.Gas["gasTipCap"] = tx.GasTipCap().String()
+	}
+	if tx.Value().Sign() > 0 {
+		entry.Gas["value"] = tx.Value().String()
+	}
+	return entry
+}
+
+// auditSend records a transaction the handler sent to the contract at to, or the error that kept it from
+// being sent, and returns the entry its outcome is recorded against
+func (h *HyperlaneEVM) auditSend(args *types.ParsedArgs, kind string, to common.Address, tx *gethtypes.Transaction, sendErr error) TxAuditEntry {
+	entry := evmTxAuditEntry(h.chainID, kind, args, to.Hex(), tx)
+	entry.Result = TxAuditSent
+	if sendErr != nil {
+		entry.Result = TxAuditFailed
+		entry.Error = sendErr.Error()
+	}
+	h.txAudit.record(entry)
+	return entry
+}
+
+// evmReceiptAuditEntry describes the outcome of a sent EVM transaction; receipt is nil when waiting for it failed
+func evmReceiptAuditEntry(sent TxAuditEntry, receipt *gethtypes.Receipt, waitErr error) TxAuditEntry {
+	entry := TxAuditEntry{ChainID: sent.ChainID, Kind: sent.Kind, OrderID: sent.OrderID, TxHash: sent.TxHash}
+	if receipt == nil {
+		entry.Result = TxAuditFailed
+		if waitErr != nil {
+			entry.Error = waitErr.Error()
+		}
+		return entry
+	}
+	entry.Result = TxAuditSuccess
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		entry.Result = TxAuditReverted
+	}
+	entry.GasUsed = receipt.GasUsed
+	if receipt.EffectiveGasPrice != nil {
+		entry.Fee = new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice).String()
+		entry.FeeUnit = "WEI"
+	}
+	return entry
+}
+
+// starknetTxAuditEntry describes a signed Starknet invoke of calls
+func starknetTxAuditEntry(chainID uint64, kind, orderID string, calls []rpc.InvokeFunctionCall, txn *rpc.BroadcastInvokeTxnV3) TxAuditEntry {
+	entry := TxAuditEntry{ChainID: chainID, Kind: kind, OrderID: orderID}
+	for _, call := range calls {
+		if call.ContractAddress != nil {
+			entry.To = append(entry.To, call.ContractAddress.String())
+		}
+	}
+	if txn == nil {
+		return entry
+	}
+	if txn.Nonce != nil {
+		entry.Nonce = txn.Nonce.String()
+	}
+	entry.CalldataHash = feltsHash(txn.Calldata)
+	if bounds := txn.ResourceBounds; bounds != nil {
+		entry.Gas = map[string]string{
+			"l1GasMaxAmount":           string(bounds.L1Gas.MaxAmount),
+			"l1GasMaxPricePerUnit":     string(bounds.L1Gas.MaxPricePerUnit),
+			"l1DataGasMaxAmount":       string(bounds.L1DataGas.MaxAmount),
+			"l1DataGasMaxPricePerUnit": string(bounds.L1DataGas.MaxPricePerUnit),
+			"l2GasMaxAmount":           string(bounds.L2Gas.MaxAmount),
+			"l2GasMaxPricePerUnit":     string(bounds.L2Gas.MaxPricePerUnit),
+			"tip":                      string(txn.Tip),
+		}
+	}
+	return entry
+}
+
+// feltsHash is the keccak256 hash of the felts' 32-byte big-endian encodings
+func feltsHash(felts []*felt.Felt) string {
+	data := make([]byte, 0, len(felts)*felt.Bytes)
+	for _, f := range felts {
+		b := f.Bytes()
+		data = append(data, b[:]...)
+	}
+	return crypto.Keccak256Hash(data).Hex()
+}
+
+// auditOrderID returns the order a transaction is sent for, if any
+func auditOrderID(args *types.ParsedArgs) string {
+	if args == nil {
+		return ""
+	}
+	return args.OrderID
+}
@@ -0,0 +1,246 @@
+package hyperlane7683
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readTxAudit returns the entries of the audit log at path
+func readTxAudit(t *testing.T, path string) []TxAuditEntry {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var entries []TxAuditEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry TxAuditEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.NoError(t, scanner.Err())
+	return entries
+}
+
+// TestTxAuditLog tests the audit trail of the transactions the chain handlers send
+func TestTxAuditLog(t *testing.T) {
+	orderID := "0x1111111111111111111111111111111111111111111111111111111111111111"
+
+	t.Run("entries are appended one per line", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit", "tx-audit.jsonl")
+		log := NewTxAuditLog(path)
+		now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		log.now = func() time.Time { return now }
+
+		require.NoError(t, log.Record(TxAuditEntry{ChainID: 1, Kind: orders.TxKindFill, Result: TxAuditSent, TxHash: "0x01"}))
+		require.NoError(t, NewTxAuditLog(path).Record(TxAuditEntry{ChainID: 1, Kind: orders.TxKindFill, Result: TxAuditSuccess, TxHash: "0x01"}))
+
+		entries := readTxAudit(t, path)
+		require.Len(t, entries, 2)
+		assert.Equal(t, now, entries[0].Time)
+		assert.Equal(t, TxAuditSent, entries[0].Result)
+		assert.Equal(t, TxAuditSuccess, entries[1].Result)
+
+		var nilLog *TxAuditLog
+		assert.NoError(t, nilLog.Record(TxAuditEntry{}))
+	})
+
+	t.Run("path from the environment", func(t *testing.T) {
+		files := storage.NewFileStorage(t.TempDir(), nil)
+		t.Setenv("SOLVER_TX_AUDIT_FILE", "/tmp/audit.jsonl")
+		assert.Equal(t, "/tmp/audit.jsonl", txAuditLogIn(files, nil).Path())
+		t.Setenv("SOLVER_TX_AUDIT_FILE", "")
+		assert.Equal(t, "state/solver_state/tx-audit.jsonl", txAuditLogIn(files, nil).Path())
+
+		database, err := storage.OpenSQL(storage.BackendSQLite, filepath.Join(t.TempDir(), "solver.db"))
+		require.NoError(t, err)
+		defer database.Close()
+		assert.Empty(t, txAuditLogIn(database, nil).Path(), "database backends have no file")
+	})
+
+	t.Run("EVM approve, fill and settle are recorded when sent and when mined", func(t *testing.T) {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		signer, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(config.BaseSepoliaChainID))
+		require.NoError(t, err)
+		t.Setenv("SOLVER_PUB_KEY", signer.From.Hex())
+		t.Setenv("EVM_HYPERLANE_ADDRESS", "0x00000000000000000000000000000000000000b2")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+		settler := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		backend.HandleCall(token, chainmock.Selector("balanceOf(address)"), uint256Result(big.NewInt(10_000)))
+		backend.HandleCall(token, chainmock.Selector("allowance(address,address)"), uint256Result(big.NewInt(0)))
+		var filled atomic.Bool
+		backend.HandleCall(settler, chainmock.Selector("orderStatus(bytes32)"), func(ethereum.CallMsg) ([]byte, error) {
+			if filled.Load() {
+				return common.RightPadBytes([]byte(orderStatusFilled), 32), nil
+			}
+			return make([]byte, 32), nil
+		})
+		backend.HandleCall(settler, chainmock.Selector("quoteGasPayment(uint32)"), uint256Result(big.NewInt(7)))
+		fillSelector := chainmock.Selector("fill(bytes32,bytes,bytes)")
+		backend.OnTransaction(settler, func(tx *gethtypes.Transaction) uint64 {
+			if !bytes.HasPrefix(tx.Data(), fillSelector[:]) {
+				return gethtypes.ReceiptStatusFailed // settle reverts
+			}
+			filled.Store(true)
+			return gethtypes.ReceiptStatusSuccessful
+		})
+
+		path := filepath.Join(t.TempDir(), "tx-audit.jsonl")
+		solver := NewHyperlane7683Solver(
+			func(uint64) (EVMClient, error) { return backend, nil },
+			nil,
+			func(uint64) (*bind.TransactOpts, error) { return signer, nil },
+			nil,
+			types.AllowBlockLists{},
+		)
+		solver.SetTxAuditLog(NewTxAuditLog(path))
+
+		args := endToEndArgs(orderID, token.Hex(), settler.Hex(), config.BaseSepoliaChainID)
+		_, err = solver.ProcessIntent(context.Background(), &args)
+		assert.ErrorContains(t, err, "settle")
+
+		sent := backend.Sent()
+		require.Len(t, sent, 3)
+		entries := readTxAudit(t, path)
+		require.Len(t, entries, 6)
+
+		kinds := []string{orders.TxKindApprove, orders.TxKindFill, orders.TxKindSettle}
+		targets := []common.Address{token, settler, settler}
+		for i, tx := range sent {
+			sentEntry, outcome := entries[2*i], entries[2*i+1]
+			assert.Equal(t, kinds[i], sentEntry.Kind)
+			assert.Equal(t, TxAuditSent, sentEntry.Result)
+			assert.Equal(t, uint64(config.BaseSepoliaChainID), sentEntry.ChainID)
+			assert.Equal(t, orderID, sentEntry.OrderID)
+			assert.Equal(t, tx.Hash().Hex(), sentEntry.TxHash)
+			assert.Equal(t, []string{targets[i].Hex()}, sentEntry.To)
+			assert.Equal(t, crypto.Keccak256Hash(tx.Data()).Hex(), sentEntry.CalldataHash)
+			assert.NotEmpty(t, sentEntry.Gas["gasLimit"])
+
+			assert.Equal(t, kinds[i], outcome.Kind)
+			assert.Equal(t, tx.Hash().Hex(), outcome.TxHash)
+		}
+		assert.Equal(t, TxAuditSuccess, entries[1].Result)
+		assert.Equal(t, TxAuditSuccess, entries[3].Result)
+		assert.Equal(t, TxAuditReverted, entries[5].Result)
+		assert.Equal(t, "7", entries[4].Gas["value"], "settle pays the quoted gas")
+	})
+
+	t.Run("EVM transaction the node refuses is recorded as failed", func(t *testing.T) {
+		backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+		path := filepath.Join(t.TempDir(), "tx-audit.jsonl")
+		handler := NewHyperlaneEVM(backend, nil, config.BaseSepoliaChainID)
+		handler.txAudit = NewTxAuditLog(path)
+
+		to := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+		handler.auditSend(nil, orders.TxKindSettle, to, nil, assert.AnError)
+		entries := readTxAudit(t, path)
+		require.Len(t, entries, 1)
+		assert.Equal(t, TxAuditFailed, entries[0].Result)
+		assert.Equal(t, assert.AnError.Error(), entries[0].Error)
+		assert.Empty(t, entries[0].TxHash)
+	})
+
+	t.Run("Starknet reverted multicall is recorded with its fee", func(t *testing.T) {
+		t.Setenv("STARKNET_SOLVER_PUBLIC_KEY", "0x2c2b8f559e1221468140ad7b2352b1a5be32660d0bf1a3ae3a054a4ec5254e4")
+		t.Setenv("STARKNET_SOLVER_PRIVATE_KEY", "0x1")
+		t.Setenv("STARKNET_SOLVER_ADDRESS", "0x0000000000000000000000000000000000000000000000000000000000000abc")
+		t.Setenv("STARKNET_HYPERLANE_ADDRESS", "0x0000000000000000000000000000000000000000000000000000000000005678")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		token := "0x0000000000000000000000000000000000000000000000000000000000001234"
+		settler := "0x0000000000000000000000000000000000000000000000000000000000005678"
+		tokenFelt, err := utils.HexToFelt(token)
+		require.NoError(t, err)
+		settlerFelt, err := utils.HexToFelt(settler)
+		require.NoError(t, err)
+		ethFelt, err := utils.HexToFelt(starknetETHAddress)
+		require.NoError(t, err)
+
+		backend := chainmock.NewStarknetBackend()
+		zero := func(n int) chainmock.StarknetCallHandler {
+			return func(rpc.FunctionCall) ([]*felt.Felt, error) {
+				out := make([]*felt.Felt, n)
+				for i := range out {
+					out[i] = new(felt.Felt)
+				}
+				return out, nil
+			}
+		}
+		backend.HandleCall(tokenFelt, "balanceOf", func(rpc.FunctionCall) ([]*felt.Felt, error) {
+			return []*felt.Felt{new(felt.Felt).SetUint64(10_000), new(felt.Felt)}, nil
+		})
+		backend.HandleCall(tokenFelt, "allowance", zero(2))
+		backend.HandleCall(ethFelt, "allowance", zero(2))
+		backend.HandleCall(settlerFelt, "order_status", zero(1))
+		backend.HandleCall(settlerFelt, "quote_gas_payment", zero(2))
+		backend.OnInvoke(func(*rpc.BroadcastInvokeTxnV3) rpc.TxnExecutionStatus {
+			return rpc.TxnExecutionStatusREVERTED
+		})
+
+		path := filepath.Join(t.TempDir(), "tx-audit.jsonl")
+		solver := NewHyperlane7683Solver(
+			nil,
+			func(uint64) (rpc.RPCProvider, error) { return backend, nil },
+			nil, nil,
+			types.AllowBlockLists{},
+		)
+		solver.SetTxAuditLog(NewTxAuditLog(path))
+
+		args := endToEndArgs(orderID, token, settler, config.StarknetSepoliaChainID)
+		_, err = solver.ProcessIntent(context.Background(), &args)
+		assert.ErrorContains(t, err, "reverted")
+
+		invokes := backend.Invokes()
+		require.Len(t, invokes, 1)
+		entries := readTxAudit(t, path)
+		require.Len(t, entries, 2)
+
+		sent, outcome := entries[0], entries[1]
+		assert.Equal(t, TxAuditSent, sent.Result)
+		assert.Equal(t, orders.TxKindFillSettle, sent.Kind)
+		assert.Equal(t, orderID, sent.OrderID)
+		assert.Equal(t, []string{tokenFelt.String(), settlerFelt.String(), settlerFelt.String()}, sent.To)
+		assert.Equal(t, feltsHash(invokes[0].Calldata), sent.CalldataHash)
+		assert.Equal(t, invokes[0].Nonce.String(), sent.Nonce)
+		assert.Contains(t, sent.Gas, "l2GasMaxAmount")
+
+		assert.Equal(t, TxAuditReverted, outcome.Result)
+		assert.Equal(t, sent.TxHash, outcome.TxHash)
+		assert.Equal(t, "1000", outcome.Fee)
+		assert.Contains(t, outcome.Error, "reverted")
+	})
+}
@@ -0,0 +1,364 @@
+package hyperlane7683
+
+// Module: Transaction outbox
+// - Makes fills and settles exactly-once across crashes and retries: before a fill or settle is
+//   signed the handler writes an outbox entry for the order, kind and chain, adds the hash of every
+//   broadcast version of the transaction, and removes the entry once the outcome is known
+// - Handlers read the router's order status before settling, so an order a previous settle already
+//   settled is done without a transaction; for a fill or settle still in flight they look up the
+//   transactions of the order's entry instead of sending (and paying for) it again:
+//   - a successful transaction completes the operation, and its fee is recorded on the order
+//   - reverted transactions drop the entry, and the operation is sent again
+//   - transactions the chain doesn't know yet hold the operation back with ErrTxPending until
+//     TX_OUTBOX_PENDING_TIMEOUT_SECONDS after the entry was written; they are then taken as dropped.
+//     Settles are retried by the settlement worker without using up attempts, fills wait for them
+//     before taking the handler lock, so other fills and settles on the chain are not held up
+// - The transactions of an entry are versions of one transaction with the same nonce, resubmitted
+//   with higher fees while stuck (see evm_replace.go, starknet_nonce.go): once one of them is
+//   included the others never will be, so a reverted version isn't waited on for the others
+// - An entry without transactions is an operation that was about to be broadcast when the process
+//   stopped, and is treated as in flight the same way
+// - On startup, before listeners and the settlement worker run, RecoverTxOutbox looks up every
+//   entry: orders whose fill or settle was included are recorded FILLED or SETTLED with our fill's
+//   fee, so the settlement worker picks them up and the replayed Open event is not filled again
+// - Entries are kept in the tx_outbox collection of the storage backend
+//
+// Settings:
+// - TX_OUTBOX_PENDING_TIMEOUT_SECONDS: how long an unknown fill or settle transaction is waited for (default 900)
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
+)
+
+const (
+	defaultTxOutboxPendingTimeoutSeconds = 900
+	defaultTxOutboxPollInterval          = 5 * time.Second
+)
+
+// ErrTxPending is returned while a fill or settle sent earlier may still be included
+var ErrTxPending = errors.New("transaction still pending")
+
+// TxOutboxEntry is a fill or settle transaction of one order on one chain whose outcome is not known yet
+type TxOutboxEntry struct {
+	Kind      string    `json:"kind"` // orders.TxKindFill, TxKindSettle or TxKindFillSettle
+	ChainID   uint64    `json:"chainId"`
+	OrderID   string    `json:"orderId"`
+	TxHashes  []string  `json:"txHashes,omitempty"` // Every broadcast version of the transaction
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// outboxTxStatus is what a chain reports about an outbox transaction
+type outboxTxStatus int
+
+const (
+	outboxTxUnknown   outboxTxStatus = iota // Not included (yet), or dropped
+	outboxTxSucceeded                       // Included and successful
+	outboxTxReverted                        // Included and reverted
+)
+
+// outboxTx is the outcome of an outbox transaction looked up on its chain
+type outboxTx struct {
+	status  outboxTxStatus
+	fee     *big.Int // Network fee paid by an included transaction
+	feeUnit string
+}
+
+// outboxTxLookup looks up a transaction by hash on the chain of a handler
+type outboxTxLookup func(ctx context.Context, txHash string) (outboxTx, error)
+
+// outboxTxLooker is implemented by chain handlers that can look up their outbox transactions
+type outboxTxLooker interface {
+	lookupOutboxTx(ctx context.Context, txHash string) (outboxTx, error)
+}
+
+// TxOutbox persists fill and settle transactions in flight. Methods are safe for concurrent use and
+// a nil outbox records nothing.
+type TxOutbox struct {
+	backend        storage.Storage
+	pendingTimeout time.Duration
+	pollInterval   time.Duration // Between lookups of a fill waited for
+	now            func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*TxOutboxEntry
+}
+
+// OpenTxOutbox loads the entries kept in the backend
+func OpenTxOutbox(backend storage.Storage) (*TxOutbox, error) {
+	docs, err := backend.Load(storage.TxOutbox)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tx outbox: %w", err)
+	}
+	o := &TxOutbox{
+		backend:        backend,
+		pendingTimeout: time.Duration(envutil.GetEnvInt("TX_OUTBOX_PENDING_TIMEOUT_SECONDS", defaultTxOutboxPendingTimeoutSeconds)) * time.Second,
+		pollInterval:   defaultTxOutboxPollInterval,
+		now:            time.Now,
+		entries:        make(map[string]*TxOutboxEntry, len(docs)),
+	}
+	for key, doc := range docs {
+		var entry TxOutboxEntry
+		if err := json.Unmarshal(doc, &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse tx outbox entry %s: %w", key, err)
+		}
+		o.entries[key] = &entry
+	}
+	return o, nil
+}
+
+// TxOutboxFromEnv opens the outbox of the default storage backend
+func TxOutboxFromEnv() (*TxOutbox, error) {
+	backend, err := storage.Default()
+	if err != nil {
+		return nil, err
+	}
+	return OpenTxOutbox(backend)
+}
+
+// txOutboxKey identifies the entry of an operation of an order on a chain
+func txOutboxKey(kind string, chainID uint64, orderID string) string {
+	return fmt.Sprintf("%s:%d:%s", kind, chainID, strings.ToLower(orderID))
+}
+
+// List returns the entries, oldest first
+func (o *TxOutbox) List() []TxOutboxEntry {
+	if o == nil {
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	list := make([]TxOutboxEntry, 0, len(o.entries))
+	for _, entry := range o.entries {
+		list = append(list, o.copyLocked(entry))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	return list
+}
+
+// Get returns the entry of an operation of an order on a chain
+func (o *TxOutbox) Get(kind string, chainID uint64, orderID string) (TxOutboxEntry, bool) {
+	if o == nil {
+		return TxOutboxEntry{}, false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	entry, ok := o.entries[txOutboxKey(kind, chainID, orderID)]
+	if !ok {
+		return TxOutboxEntry{}, false
+	}
+	return o.copyLocked(entry), true
+}
+
+// Begin records that a transaction of the order is about to be signed; it must not be sent when Begin fails
+func (o *TxOutbox) Begin(kind string, chainID uint64, orderID string) error {
+	if o == nil {
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	now := o.now().UTC()
+	entry := &TxOutboxEntry{Kind: kind, ChainID: chainID, OrderID: orderID, CreatedAt: now, UpdatedAt: now}
+	return o.saveLocked(entry)
+}
+
+// AddTx records a broadcast transaction of an entry begun earlier; transactions without an entry
+// (e.g. approvals) are ignored, and a failed write is logged, as the transaction is already out
+func (o *TxOutbox) AddTx(kind string, chainID uint64, orderID, txHash string) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	entry, ok := o.entries[txOutboxKey(kind, chainID, orderID)]
+	if !ok {
+		return
+	}
+	updated := o.copyLocked(entry)
+	updated.TxHashes = append(updated.TxHashes, txHash)
+	updated.UpdatedAt = o.now().UTC()
+	if err := o.saveLocked(&updated); err != nil {
+		fillerLog.Printf("⚠️  Failed to record %s tx %s of order %s: %v\n", kind, txHash, orderID, err)
+	}
+}
+
+// Done removes the entry of an operation whose outcome is known; a failed write is logged
+func (o *TxOutbox) Done(kind string, chainID uint64, orderID string) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	key := txOutboxKey(kind, chainID, orderID)
+	if _, ok := o.entries[key]; !ok {
+		return
+	}
+	delete(o.entries, key)
+	if err := o.backend.Delete(storage.TxOutbox, key); err != nil {
+		fillerLog.Printf("⚠️  Failed to remove %s outbox entry of order %s: %v\n", kind, orderID, err)
+	}
+}
+
+// Release removes the entry of an operation when none of its transactions was broadcast, after it
+// failed; entries with transactions stay for the next attempt to look up
+func (o *TxOutbox) Release(kind string, chainID uint64, orderID string) {
+	if entry, ok := o.Get(kind, chainID, orderID); ok && len(entry.TxHashes) == 0 {
+		o.Done(kind, chainID, orderID)
+	}
+}
+
+// Resume looks up the transactions of the entry of an operation and returns the cost of the one that
+// completed it, if any. Reverted transactions drop the entry so the operation is sent again; while a
+// transaction is unknown and the entry is younger than the pending timeout, ErrTxPending is returned.
+func (o *TxOutbox) Resume(ctx context.Context, kind string, chainID uint64, orderID string, lookup outboxTxLookup) (*orders.TxCost, error) {
+	entry, ok := o.Get(kind, chainID, orderID)
+	if !ok {
+		return nil, nil
+	}
+
+	unknown := len(entry.TxHashes) == 0
+	included := false
+	for _, txHash := range entry.TxHashes {
+		tx, err := lookup(ctx, txHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up %s tx %s: %w", kind, txHash, err)
+		}
+		switch tx.status {
+		case outboxTxSucceeded:
+			o.Done(kind, chainID, orderID)
+			fillerLog.Printf("♻️  Order %s was handled by %s tx %s sent earlier, not sending it again\n", orderID, kind, txHash)
+			return &orders.TxCost{ChainID: chainID, Kind: kind, TxHash: txHash, Fee: tx.fee, FeeUnit: tx.feeUnit}, nil
+		case outboxTxReverted:
+			included = true
+		case outboxTxUnknown:
+			unknown = true
+		}
+	}
+
+	// The transactions of an entry are versions of one transaction sharing a nonce: once one of them
+	// reverted, the others can't be included anymore
+	if unknown && !included && o.now().Sub(entry.UpdatedAt) < o.pendingTimeout {
+		if len(entry.TxHashes) == 0 {
+			return nil, fmt.Errorf("%w: a %s of order %s was being sent at %s", ErrTxPending, kind, orderID, entry.UpdatedAt.Format(time.RFC3339))
+		}
+		return nil, fmt.Errorf("%w: %s", ErrTxPending, strings.Join(entry.TxHashes, ", "))
+	}
+	o.Done(kind, chainID, orderID)
+	fillerLog.Printf("🔁 Earlier %s of order %s reverted or was dropped, sending it again\n", kind, orderID)
+	return nil, nil
+}
+
+// Await resumes the entry of an operation like Resume, but waits for transactions still pending
+// instead of returning ErrTxPending
+func (o *TxOutbox) Await(ctx context.Context, kind string, chainID uint64, orderID string, lookup outboxTxLookup) (*orders.TxCost, error) {
+	for {
+		cost, err := o.Resume(ctx, kind, chainID, orderID, lookup)
+		if !errors.Is(err, ErrTxPending) {
+			return cost, err
+		}
+		fillerLog.Printf("⏳ Waiting for the %s of order %s sent earlier (%v)\n", kind, orderID, err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(o.pollInterval):
+		}
+	}
+}
+
+// saveLocked stores entry; the caller holds o.mu
+func (o *TxOutbox) saveLocked(entry *TxOutboxEntry) error {
+	doc, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode tx outbox entry: %w", err)
+	}
+	key := txOutboxKey(entry.Kind, entry.ChainID, entry.OrderID)
+	if err := o.backend.Put(storage.TxOutbox, key, doc); err != nil {
+		return fmt.Errorf("failed to store tx outbox entry: %w", err)
+	}
+	o.entries[key] = entry
+	return nil
+}
+
+// copyLocked returns a copy of entry that doesn't share its hashes; the caller holds o.mu
+func (o *TxOutbox) copyLocked(entry *TxOutboxEntry) TxOutboxEntry {
+	copied := *entry
+	copied.TxHashes = slices.Clone(entry.TxHashes)
+	return copied
+}
+
+// RecoverTxOutbox looks up every outbox entry left by a previous run. Orders whose fill or settle was
+// included are recorded FILLED or SETTLED with the transaction's fee, so the settlement worker picks
+// them up and replayed Open events are recognized as our fills; reverted and dropped entries are
+// removed so the operation can be sent again, and pending ones are kept for the next attempt.
+// It must run before the listeners and the settlement worker start.
+func (f *Hyperlane7683Solver) RecoverTxOutbox(ctx context.Context) {
+	entries := f.txOutbox.List()
+	if len(entries) == 0 {
+		return
+	}
+	fillerLog.Printf("📮 Recovering %d transaction(s) from the outbox\n", len(entries))
+
+	for _, entry := range entries {
+		handler, _, err := f.handlerForChain(new(big.Int).SetUint64(entry.ChainID))
+		if err != nil {
+			fillerLog.Printf("⚠️  Cannot look up the %s of order %s: %v\n", entry.Kind, entry.OrderID, err)
+			continue
+		}
+		looker, ok := handler.(outboxTxLooker)
+		if !ok {
+			continue
+		}
+
+		cost, err := f.txOutbox.Resume(ctx, entry.Kind, entry.ChainID, entry.OrderID, looker.lookupOutboxTx)
+		if errors.Is(err, ErrTxPending) {
+			fillerLog.Printf("⏳ The %s of order %s is still pending, the next attempt waits for it\n", entry.Kind, entry.OrderID)
+			continue
+		}
+		if err != nil {
+			fillerLog.Printf("⚠️  Failed to recover the %s of order %s: %v\n", entry.Kind, entry.OrderID, err)
+			continue
+		}
+		if cost != nil {
+			f.recordRecoveredTx(entry.OrderID, *cost)
+		}
+	}
+}
+
+// recordRecoveredTx records an included outbox transaction on its order, as the handler would have
+// had the process not stopped
+func (f *Hyperlane7683Solver) recordRecoveredTx(orderID string, cost orders.TxCost) {
+	if f.orderStore == nil {
+		return
+	}
+	if err := f.orderStore.AddCost(orderID, cost); err != nil {
+		fillerLog.Printf("⚠️  Failed to record %s cost for order %s: %v\n", cost.Kind, orderID, err)
+	}
+
+	// The other legs of multi-leg orders are resumed by the replayed Open event
+	order, ok := f.orderStore.Get(orderID)
+	if ok && order.Args != nil && len(order.Args.ResolvedOrder.FillInstructions) > 1 {
+		return
+	}
+	status := orders.StatusSettled
+	if cost.Kind == orders.TxKindFill {
+		status = orders.StatusFilled
+	}
+	if err := f.orderStore.SetStatus(orderID, status, ""); err != nil {
+		fillerLog.Printf("⚠️  Failed to record order %s as %s: %v\n", orderID, status, err)
+		return
+	}
+	f.publishStatusChange(orderID, order.Args, order.Status, status, "")
+}
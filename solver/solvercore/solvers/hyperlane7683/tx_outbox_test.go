@@ -0,0 +1,326 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chainmock"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orders"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/storage"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// Both handlers look up outbox transactions for the startup recovery
+var (
+	_ outboxTxLooker = (*HyperlaneEVM)(nil)
+	_ outboxTxLooker = (*HyperlaneStarknet)(nil)
+)
+
+// newTestTxOutbox opens an outbox in dir with a fixed clock
+func newTestTxOutbox(t *testing.T, dir string, now *time.Time) *TxOutbox {
+	outbox, err := OpenTxOutbox(storage.NewFileStorage(dir, nil))
+	require.NoError(t, err)
+	outbox.pendingTimeout = 10 * time.Minute
+	outbox.pollInterval = time.Millisecond
+	outbox.now = func() time.Time { return *now }
+	return outbox
+}
+
+// outboxLookup reports fixed statuses by tx hash and counts lookups
+func outboxLookup(statuses map[string]outboxTxStatus, lookups *int) outboxTxLookup {
+	return func(_ context.Context, txHash string) (outboxTx, error) {
+		*lookups++
+		status, ok := statuses[txHash]
+		if !ok {
+			return outboxTx{}, errors.New("rpc unavailable")
+		}
+		return outboxTx{status: status, fee: big.NewInt(21_000), feeUnit: "WEI"}, nil
+	}
+}
+
+func TestTxOutbox(t *testing.T) {
+	ctx := context.Background()
+	const chainID = 84532
+	orderID := settleOrderID(1)
+	settle := orders.TxKindSettle
+
+	t.Run("entries survive a restart until done", func(t *testing.T) {
+		dir := t.TempDir()
+		now := time.Unix(1_700_000_000, 0)
+		outbox := newTestTxOutbox(t, dir, &now)
+		require.NoError(t, outbox.Begin(settle, chainID, orderID))
+		outbox.AddTx(settle, chainID, orderID, "0xaa")
+		outbox.AddTx(settle, chainID, orderID, "0xbb")
+
+		reopened := newTestTxOutbox(t, dir, &now)
+		entry, ok := reopened.Get(settle, chainID, orderID)
+		require.True(t, ok)
+		assert.Equal(t, []string{"0xaa", "0xbb"}, entry.TxHashes)
+		assert.Len(t, reopened.List(), 1)
+		_, ok = reopened.Get(settle, 1, orderID)
+		assert.False(t, ok, "entries are per chain")
+		_, ok = reopened.Get(orders.TxKindFill, chainID, orderID)
+		assert.False(t, ok, "entries are per kind")
+
+		reopened.Done(settle, chainID, orderID)
+		assert.Empty(t, newTestTxOutbox(t, dir, &now).List())
+	})
+
+	t.Run("transactions without an entry are not recorded", func(t *testing.T) {
+		now := time.Unix(1_700_000_000, 0)
+		outbox := newTestTxOutbox(t, t.TempDir(), &now)
+		outbox.AddTx(orders.TxKindApprove, chainID, orderID, "0xaa")
+		assert.Empty(t, outbox.List())
+	})
+
+	t.Run("release keeps entries with broadcast transactions", func(t *testing.T) {
+		now := time.Unix(1_700_000_000, 0)
+		outbox := newTestTxOutbox(t, t.TempDir(), &now)
+		require.NoError(t, outbox.Begin(settle, chainID, orderID))
+		outbox.Release(settle, chainID, orderID)
+		assert.Empty(t, outbox.List(), "nothing was sent")
+
+		require.NoError(t, outbox.Begin(settle, chainID, orderID))
+		outbox.AddTx(settle, chainID, orderID, "0xaa")
+		outbox.Release(settle, chainID, orderID)
+		assert.Len(t, outbox.List(), 1)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			hashes    []string
+			statuses  map[string]outboxTxStatus
+			elapsed   time.Duration
+			wantTx    string
+			wantErr   error
+			wantEntry bool
+		}{
+			{"an included transaction completes the operation", []string{"0xaa", "0xbb"},
+				map[string]outboxTxStatus{"0xaa": outboxTxReverted, "0xbb": outboxTxSucceeded}, 0, "0xbb", nil, false},
+			{"reverted transactions are sent again", []string{"0xaa"},
+				map[string]outboxTxStatus{"0xaa": outboxTxReverted}, 0, "", nil, false},
+			{"replacements of a reverted transaction are not waited for", []string{"0xaa", "0xbb"},
+				map[string]outboxTxStatus{"0xaa": outboxTxUnknown, "0xbb": outboxTxReverted}, 0, "", nil, false},
+			{"unknown transactions are waited for", []string{"0xaa"},
+				map[string]outboxTxStatus{"0xaa": outboxTxUnknown}, 9 * time.Minute, "", ErrTxPending, true},
+			{"unknown transactions are dropped after the timeout", []string{"0xaa"},
+				map[string]outboxTxStatus{"0xaa": outboxTxUnknown}, 10 * time.Minute, "", nil, false},
+			{"a transaction interrupted before broadcast is waited for", nil, nil, time.Minute, "", ErrTxPending, true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				now := time.Unix(1_700_000_000, 0)
+				outbox := newTestTxOutbox(t, t.TempDir(), &now)
+				require.NoError(t, outbox.Begin(settle, chainID, orderID))
+				for _, hash := range tt.hashes {
+					outbox.AddTx(settle, chainID, orderID, hash)
+				}
+				now = now.Add(tt.elapsed)
+
+				lookups := 0
+				cost, err := outbox.Resume(ctx, settle, chainID, orderID, outboxLookup(tt.statuses, &lookups))
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				} else {
+					assert.NoError(t, err)
+				}
+				if tt.wantTx == "" {
+					assert.Nil(t, cost)
+				} else {
+					require.NotNil(t, cost)
+					assert.Equal(t, orders.TxCost{ChainID: chainID, Kind: settle, TxHash: tt.wantTx, Fee: big.NewInt(21_000), FeeUnit: "WEI"}, *cost)
+				}
+				_, ok := outbox.Get(settle, chainID, orderID)
+				assert.Equal(t, tt.wantEntry, ok)
+			})
+		}
+	})
+
+	t.Run("failed lookups keep the entry", func(t *testing.T) {
+		now := time.Unix(1_700_000_000, 0)
+		outbox := newTestTxOutbox(t, t.TempDir(), &now)
+		require.NoError(t, outbox.Begin(settle, chainID, orderID))
+		outbox.AddTx(settle, chainID, orderID, "0xaa")
+
+		lookups := 0
+		_, err := outbox.Resume(ctx, settle, chainID, orderID, outboxLookup(nil, &lookups))
+		assert.ErrorContains(t, err, "failed to look up settle tx 0xaa: rpc unavailable")
+		assert.NotErrorIs(t, err, ErrTxPending)
+		assert.Len(t, outbox.List(), 1)
+	})
+
+	t.Run("await waits for a pending transaction", func(t *testing.T) {
+		now := time.Unix(1_700_000_000, 0)
+		outbox := newTestTxOutbox(t, t.TempDir(), &now)
+		require.NoError(t, outbox.Begin(orders.TxKindFill, chainID, orderID))
+		outbox.AddTx(orders.TxKindFill, chainID, orderID, "0xaa")
+
+		lookups := 0
+		cost, err := outbox.Await(ctx, orders.TxKindFill, chainID, orderID, func(ctx context.Context, txHash string) (outboxTx, error) {
+			status := outboxTxUnknown
+			if lookups++; lookups == 3 {
+				status = outboxTxSucceeded
+			}
+			return outboxLookup(map[string]outboxTxStatus{txHash: status}, new(int))(ctx, txHash)
+		})
+		require.NoError(t, err)
+		require.NotNil(t, cost)
+		assert.Equal(t, "0xaa", cost.TxHash)
+		assert.Equal(t, 3, lookups)
+
+		require.NoError(t, outbox.Begin(orders.TxKindFill, chainID, orderID))
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = outbox.Await(canceled, orders.TxKindFill, chainID, orderID, outboxLookup(nil, &lookups))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("a fill waits for an earlier fill without holding the handler lock", func(t *testing.T) {
+		outbox, err := OpenTxOutbox(storage.NewFileStorage(t.TempDir(), nil))
+		require.NoError(t, err)
+		outbox.pollInterval = time.Millisecond
+		handler := NewHyperlaneEVM(chainmock.NewEVMBackend(config.BaseSepoliaChainID), nil, config.BaseSepoliaChainID)
+		handler.txOutbox = outbox
+		args := &types.ParsedArgs{OrderID: orderID, ResolvedOrder: types.ResolvedCrossChainOrder{
+			FillInstructions: []types.FillInstruction{{
+				DestinationChainID: big.NewInt(config.BaseSepoliaChainID),
+				DestinationSettler: "0x00000000000000000000000000000000000000b2",
+			}},
+		}}
+		require.NoError(t, outbox.Begin(orders.TxKindFill, config.BaseSepoliaChainID, orderID))
+		outbox.AddTx(orders.TxKindFill, config.BaseSepoliaChainID, orderID, "0xaa")
+
+		// A settle holds the lock while the fill waits for its pending transaction
+		handler.mu.Lock()
+		defer handler.mu.Unlock()
+		waiting, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		done := make(chan error, 1)
+		go func() {
+			_, err := handler.Fill(waiting, args)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the fill blocked on the handler lock")
+		}
+	})
+
+	t.Run("orders without an entry are sent", func(t *testing.T) {
+		now := time.Unix(1_700_000_000, 0)
+		outbox := newTestTxOutbox(t, t.TempDir(), &now)
+		lookups := 0
+		cost, err := outbox.Resume(ctx, settle, chainID, orderID, outboxLookup(nil, &lookups))
+		assert.NoError(t, err)
+		assert.Nil(t, cost)
+		assert.Zero(t, lookups)
+
+		var disabled *TxOutbox
+		require.NoError(t, disabled.Begin(settle, chainID, orderID))
+		cost, err = disabled.Await(ctx, settle, chainID, orderID, outboxLookup(nil, &lookups))
+		assert.NoError(t, err)
+		assert.Nil(t, cost)
+	})
+}
+
+func TestRecoverTxOutbox(t *testing.T) {
+	config.InitializeNetworks()
+	ctx := context.Background()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(config.BaseSepoliaChainID))
+	require.NoError(t, err)
+	backend := chainmock.NewEVMBackend(config.BaseSepoliaChainID)
+	reverting := common.HexToAddress("0x00000000000000000000000000000000000000e1")
+	backend.OnTransaction(reverting, func(*gethtypes.Transaction) uint64 { return gethtypes.ReceiptStatusFailed })
+	// send mines a transaction to the address and returns its hash
+	nonce := uint64(0)
+	send := func(to common.Address) string {
+		tx, err := signer.Signer(signer.From, gethtypes.NewTransaction(nonce, to, big.NewInt(0), 21_000, big.NewInt(1), nil))
+		require.NoError(t, err)
+		require.NoError(t, backend.SendTransaction(ctx, tx))
+		nonce++
+		return tx.Hash().Hex()
+	}
+	included := send(common.HexToAddress("0x00000000000000000000000000000000000000e0"))
+	reverted := send(reverting)
+	unknown := common.HexToHash("0xdead").Hex()
+
+	// Orders filled on Base Sepolia, from Ethereum Sepolia or within Base Sepolia
+	order := func(n int, originChainID int64) *types.ParsedArgs {
+		return &types.ParsedArgs{
+			OrderID: settleOrderID(n),
+			ResolvedOrder: types.ResolvedCrossChainOrder{
+				OriginChainID:    big.NewInt(originChainID),
+				FillInstructions: []types.FillInstruction{{DestinationChainID: big.NewInt(config.BaseSepoliaChainID)}},
+			},
+		}
+	}
+	entries := []struct {
+		args   *types.ParsedArgs
+		kind   string
+		txHash string
+	}{
+		{order(1, config.EthereumSepoliaChainID), orders.TxKindFill, included},
+		{order(2, config.BaseSepoliaChainID), orders.TxKindFill, included},
+		{order(3, config.EthereumSepoliaChainID), orders.TxKindSettle, included},
+		{order(4, config.EthereumSepoliaChainID), orders.TxKindFill, reverted},
+		{order(5, config.EthereumSepoliaChainID), orders.TxKindFill, unknown},
+	}
+
+	now := time.Unix(1_700_000_000, 0)
+	outbox := newTestTxOutbox(t, t.TempDir(), &now)
+	store, err := orders.NewStore(filepath.Join(t.TempDir(), "orders.json"))
+	require.NoError(t, err)
+	for _, entry := range entries {
+		require.NoError(t, store.Observe(entry.args))
+		require.NoError(t, outbox.Begin(entry.kind, config.BaseSepoliaChainID, entry.args.OrderID))
+		outbox.AddTx(entry.kind, config.BaseSepoliaChainID, entry.args.OrderID, entry.txHash)
+	}
+
+	solver := NewHyperlane7683Solver(
+		func(uint64) (EVMClient, error) { return backend, nil },
+		nil,
+		func(uint64) (*bind.TransactOpts, error) { return signer, nil },
+		nil,
+		types.AllowBlockLists{},
+	)
+	solver.SetOrderStore(store)
+	solver.SetTxOutbox(outbox)
+	solver.RecoverTxOutbox(ctx)
+
+	statuses := map[string]orders.Status{}
+	for _, entry := range entries {
+		record, ok := store.Get(entry.args.OrderID)
+		require.True(t, ok)
+		statuses[entry.args.OrderID] = record.Status
+	}
+	assert.Equal(t, map[string]orders.Status{
+		settleOrderID(1): orders.StatusFilled,
+		settleOrderID(2): orders.StatusFilled, // Same-chain swaps still need their settle
+		settleOrderID(3): orders.StatusSettled,
+		settleOrderID(4): orders.StatusObserved,
+		settleOrderID(5): orders.StatusObserved,
+	}, statuses)
+
+	filled, _ := store.Get(settleOrderID(1))
+	assert.True(t, filled.FilledInTx(included), "the recovered fill is recorded as ours")
+	require.Len(t, outbox.List(), 1, "only the pending fill is kept")
+	assert.Equal(t, settleOrderID(5), outbox.List()[0].OrderID)
+}
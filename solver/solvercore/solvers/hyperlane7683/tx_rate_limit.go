@@ -0,0 +1,238 @@
+package hyperlane7683
+
+// Module: Per-chain transaction rate limits
+// - Bounds the transactions the solver has in flight on each chain and how many it sends per
+//   minute, so a backfill of hundreds of historical orders can't flood an RPC or burn the gas
+//   budget in seconds
+// - Every transaction takes a slot before it is sent and frees it once it is included (or failed);
+//   a transaction over a limit waits for a free slot instead of being refused
+//
+// Settings (0 or unset = no limit):
+// - TX_RATE_MAX_PENDING: max transactions in flight per chain
+// - TX_RATE_MAX_PER_MINUTE: max transactions sent per chain over a rolling minute
+// - TX_RATE_<NETWORK>_MAX_PENDING, TX_RATE_<NETWORK>_MAX_PER_MINUTE: override for one network,
+//   e.g. TX_RATE_BASE_MAX_PER_MINUTE
+// - Limits are read at a chain's first transaction; a negative value refuses the chain's transactions
+//   until it is fixed and the solver restarted
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+const txRateWindow = time.Minute
+
+// TxRateLimits are the transaction limits of one chain; 0 disables a limit
+type TxRateLimits struct {
+	MaxPending   int
+	MaxPerMinute int
+}
+
+// TxRateStatus is a chain's transaction activity
+type TxRateStatus struct {
+	ChainID        uint64 `json:"chainId"`
+	Pending        int    `json:"pending"`
+	SentLastMinute int    `json:"sentLastMinute"`
+	Waiting        int    `json:"waiting"`
+	MaxPending     int    `json:"maxPending,omitempty"`
+	MaxPerMinute   int    `json:"maxPerMinute,omitempty"`
+}
+
+// chainTxState is the activity of one chain
+type chainTxState struct {
+	limits  TxRateLimits
+	err     error // invalid limit setting
+	pending int
+	waiting int
+	sent    []time.Time // send times within the window, oldest first
+}
+
+// TxRateLimiter enforces the transaction limits of all chains
+type TxRateLimiter struct {
+	mu       sync.Mutex
+	limits   func(chainID uint64) (TxRateLimits, error)
+	window   time.Duration
+	now      func() time.Time
+	chains   map[uint64]*chainTxState
+	released chan struct{} // closed and replaced whenever a slot frees up
+}
+
+// NewTxRateLimiter creates a limiter applying the same limits to every chain
+func NewTxRateLimiter(limits TxRateLimits) *TxRateLimiter {
+	return newTxRateLimiter(func(uint64) (TxRateLimits, error) { return limits, nil })
+}
+
+func newTxRateLimiter(limits func(chainID uint64) (TxRateLimits, error)) *TxRateLimiter {
+	return &TxRateLimiter{
+		limits:   limits,
+		window:   txRateWindow,
+		now:      time.Now,
+		chains:   make(map[uint64]*chainTxState),
+		released: make(chan struct{}),
+	}
+}
+
+var (
+	defaultTxRateLimiter     *TxRateLimiter
+	defaultTxRateLimiterOnce sync.Once
+)
+
+// DefaultTxRateLimiter returns the process-wide limiter shared by the chain handlers
+func DefaultTxRateLimiter() *TxRateLimiter {
+	defaultTxRateLimiterOnce.Do(func() {
+		defaultTxRateLimiter = newTxRateLimiter(txRateLimitsFromEnv)
+	})
+	return defaultTxRateLimiter
+}
+
+// txRateLimitsFromEnv reads the limits of a chain, preferring its network's override
+func txRateLimitsFromEnv(chainID uint64) (TxRateLimits, error) {
+	limits := TxRateLimits{
+		MaxPending:   envutil.GetEnvInt("TX_RATE_MAX_PENDING", 0),
+		MaxPerMinute: envutil.GetEnvInt("TX_RATE_MAX_PER_MINUTE", 0),
+	}
+	if network, ok := networkForChainID(chainID); ok {
+		prefix := "TX_RATE_" + config.NetworkEnvPrefix(network.Name)
+		limits.MaxPending = envutil.GetEnvInt(prefix+"_MAX_PENDING", limits.MaxPending)
+		limits.MaxPerMinute = envutil.GetEnvInt(prefix+"_MAX_PER_MINUTE", limits.MaxPerMinute)
+	}
+	if limits.MaxPending < 0 || limits.MaxPerMinute < 0 {
+		return limits, fmt.Errorf("tx rate limits of chain %d must be >= 0, got max pending %d, max per minute %d",
+			chainID, limits.MaxPending, limits.MaxPerMinute)
+	}
+	return limits, nil
+}
+
+// Acquire waits until the chain has a free slot and takes it; release frees the slot once the
+// transaction is included or failed. It returns early when ctx is done. A nil limiter never waits.
+func (l *TxRateLimiter) Acquire(ctx context.Context, chainID uint64) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	logged := false
+	for {
+		l.mu.Lock()
+		state := l.chainLocked(chainID)
+		if state.err != nil {
+			l.mu.Unlock()
+			return nil, state.err
+		}
+		wait, ok := l.tryTakeLocked(state)
+		if ok {
+			l.mu.Unlock()
+			return l.releaseFunc(chainID), nil
+		}
+		released := l.released
+		state.waiting++
+		pending, sent := state.pending, len(state.sent)
+		l.mu.Unlock()
+
+		if !logged {
+			fmt.Printf("⏳ Chain %d at its transaction rate limit (%d pending, %d sent in the last minute), waiting\n",
+				chainID, pending, sent)
+			logged = true
+		}
+
+		// Without a window slot to wait for, only a release can free a slot
+		var timeout <-chan time.Time
+		var timer *time.Timer
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			timeout = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf("waiting for a transaction slot on chain %d: %w", chainID, ctx.Err())
+		case <-released:
+		case <-timeout:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		l.mu.Lock()
+		state.waiting--
+		l.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// chainLocked returns the state of a chain, loading its limits on first use; must be called with mu held
+func (l *TxRateLimiter) chainLocked(chainID uint64) *chainTxState {
+	state, ok := l.chains[chainID]
+	if !ok {
+		state = &chainTxState{}
+		state.limits, state.err = l.limits(chainID)
+		l.chains[chainID] = state
+	}
+	return state
+}
+
+// tryTakeLocked takes a slot if the limits allow it; otherwise it returns how long until the
+// per-minute window frees a slot (0 when only a release can free one). Must be called with mu held.
+func (l *TxRateLimiter) tryTakeLocked(state *chainTxState) (time.Duration, bool) {
+	now := l.now()
+	cutoff := now.Add(-l.window)
+	for len(state.sent) > 0 && !state.sent[0].After(cutoff) {
+		state.sent = state.sent[1:]
+	}
+
+	if state.limits.MaxPending > 0 && state.pending >= state.limits.MaxPending {
+		return 0, false
+	}
+	if state.limits.MaxPerMinute > 0 && len(state.sent) >= state.limits.MaxPerMinute {
+		return state.sent[0].Add(l.window).Sub(now), false
+	}
+	state.pending++
+	state.sent = append(state.sent, now)
+	return 0, true
+}
+
+// releaseFunc frees a chain's slot once; later calls are no-ops
+func (l *TxRateLimiter) releaseFunc(chainID uint64) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.chains[chainID].pending--
+			close(l.released)
+			l.released = make(chan struct{})
+		})
+	}
+}
+
+// Status returns the activity of every chain that sent transactions, sorted by chain ID
+func (l *TxRateLimiter) Status() []TxRateStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cutoff := l.now().Add(-l.window)
+	statuses := make([]TxRateStatus, 0, len(l.chains))
+	for chainID, state := range l.chains {
+		sent := 0
+		for _, at := range state.sent {
+			if at.After(cutoff) {
+				sent++
+			}
+		}
+		statuses = append(statuses, TxRateStatus{
+			ChainID:        chainID,
+			Pending:        state.pending,
+			SentLastMinute: sent,
+			Waiting:        state.waiting,
+			MaxPending:     state.limits.MaxPending,
+			MaxPerMinute:   state.limits.MaxPerMinute,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ChainID < statuses[j].ChainID })
+	return statuses
+}
@@ -0,0 +1,108 @@
+package hyperlane7683
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxRateLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pending transactions wait for a release", func(t *testing.T) {
+		limiter := NewTxRateLimiter(TxRateLimits{MaxPending: 2})
+		first, err := limiter.Acquire(ctx, 1)
+		require.NoError(t, err)
+		_, err = limiter.Acquire(ctx, 1)
+		require.NoError(t, err)
+
+		// Other chains have their own slots
+		other, err := limiter.Acquire(ctx, 2)
+		require.NoError(t, err)
+		other()
+
+		acquired := make(chan struct{})
+		go func() {
+			release, err := limiter.Acquire(ctx, 1)
+			assert.NoError(t, err)
+			release()
+			close(acquired)
+		}()
+		require.Eventually(t, func() bool { return limiter.Status()[0].Waiting == 1 }, time.Second, time.Millisecond)
+
+		first()
+		first() // a second release is a no-op
+		select {
+		case <-acquired:
+		case <-time.After(time.Second):
+			t.Fatal("waiting transaction did not get the released slot")
+		}
+		assert.Equal(t, TxRateStatus{ChainID: 1, Pending: 1, SentLastMinute: 3, MaxPending: 2}, limiter.Status()[0])
+	})
+
+	t.Run("transactions per window wait for the oldest to expire", func(t *testing.T) {
+		limiter := NewTxRateLimiter(TxRateLimits{MaxPerMinute: 2})
+		limiter.window = 50 * time.Millisecond
+		for range 2 {
+			release, err := limiter.Acquire(ctx, 1)
+			require.NoError(t, err)
+			release()
+		}
+
+		start := time.Now()
+		release, err := limiter.Acquire(ctx, 1)
+		require.NoError(t, err)
+		release()
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	})
+
+	t.Run("waiting stops with the context", func(t *testing.T) {
+		limiter := NewTxRateLimiter(TxRateLimits{MaxPending: 1})
+		_, err := limiter.Acquire(ctx, 1)
+		require.NoError(t, err)
+
+		timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		_, err = limiter.Acquire(timeout, 1)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 0, limiter.Status()[0].Waiting)
+	})
+
+	t.Run("no limits and nil limiter never wait", func(t *testing.T) {
+		limiter := NewTxRateLimiter(TxRateLimits{})
+		for range 100 {
+			_, err := limiter.Acquire(ctx, 1)
+			require.NoError(t, err)
+		}
+		var disabled *TxRateLimiter
+		release, err := disabled.Acquire(ctx, 1)
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("limits from env with network overrides", func(t *testing.T) {
+		t.Setenv("TX_RATE_MAX_PENDING", "4")
+		t.Setenv("TX_RATE_MAX_PER_MINUTE", "30")
+		t.Setenv("TX_RATE_BASE_MAX_PER_MINUTE", "5")
+		config.ResetNetworks()
+		config.InitializeNetworks()
+		defer config.ResetNetworks()
+
+		limits, err := txRateLimitsFromEnv(config.BaseSepoliaChainID)
+		require.NoError(t, err)
+		assert.Equal(t, TxRateLimits{MaxPending: 4, MaxPerMinute: 5}, limits)
+
+		limits, err = txRateLimitsFromEnv(config.EthereumSepoliaChainID)
+		require.NoError(t, err)
+		assert.Equal(t, TxRateLimits{MaxPending: 4, MaxPerMinute: 30}, limits)
+
+		t.Setenv("TX_RATE_ETHEREUM_MAX_PENDING", "-1")
+		limiter := newTxRateLimiter(txRateLimitsFromEnv)
+		_, err = limiter.Acquire(ctx, config.EthereumSepoliaChainID)
+		assert.ErrorContains(t, err, "must be >= 0")
+	})
+}
@@ -0,0 +1,5 @@
+package storage
+
+// Links the Postgres driver of the postgres backend
+
+import _ "github.com/jackc/pgx/v5/stdlib"
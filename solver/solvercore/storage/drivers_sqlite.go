@@ -0,0 +1,5 @@
+package storage
+
+// Links the pure-Go SQLite driver of the sqlite backend, the default
+
+import _ "modernc.org/sqlite"
@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	defaultDirPerms  = 0755
+	defaultFilePerms = 0600
+)
+
+// FileStorage keeps each collection in a JSON file, {"<collection>": {"<key>": <document>}}, rewritten
+// atomically on every change, and each log in a JSONL file that is only appended to
+type FileStorage struct {
+	dir   string
+	paths map[string]string // Files of collections and logs not kept in dir under their name
+
+	mu          sync.Mutex
+	collections map[string]map[string]json.RawMessage // Collections read so far
+}
+
+// NewFileStorage keeps collections in dir/<collection>.json and logs in dir/<log>.jsonl, unless paths
+// names another file for them
+func NewFileStorage(dir string, paths map[string]string) *FileStorage {
+	return &FileStorage{dir: dir, paths: paths, collections: make(map[string]map[string]json.RawMessage)}
+}
+
+// Backend implements Storage
+func (s *FileStorage) Backend() string {
+	return BackendFile
+}
+
+// Path returns the file of a collection or log
+func (s *FileStorage) Path(name, ext string) string {
+	if path := s.paths[name]; path != "" {
+		return path
+	}
+	return filepath.Join(s.dir, name+ext)
+}
+
+// Load implements Storage
+func (s *FileStorage) Load(collection string) (map[string]json.RawMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	docs, err := s.collectionLocked(collection)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]json.RawMessage, len(docs))
+	for key, doc := range docs {
+		out[key] = doc
+	}
+	return out, nil
+}
+
+// Put implements Storage
+func (s *FileStorage) Put(collection, key string, doc json.RawMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	docs, err := s.collectionLocked(collection)
+	if err != nil {
+		return err
+	}
+	docs[key] = doc
+	return s.saveLocked(collection, docs)
+}
+
+// Delete implements Storage
+func (s *FileStorage) Delete(collection, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	docs, err := s.collectionLocked(collection)
+	if err != nil {
+		return err
+	}
+	if _, ok := docs[key]; !ok {
+		return nil
+	}
+	delete(docs, key)
+	return s.saveLocked(collection, docs)
+}
+
+// Append implements Storage
+func (s *FileStorage) Append(log string, record json.RawMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.Path(log, ".jsonl")
+	if err := os.MkdirAll(filepath.Dir(path), defaultDirPerms); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", log, err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, defaultFilePerms)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if _, err := file.Write(append(record, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to append to %s: %w", path, err)
+	}
+	return file.Close()
+}
+
+// Close implements Storage
+func (s *FileStorage) Close() error {
+	return nil
+}
+
+// collectionLocked returns the documents of a collection, reading its file on first use; s.mu must be held
+func (s *FileStorage) collectionLocked(collection string) (map[string]json.RawMessage, error) {
+	if docs, ok := s.collections[collection]; ok {
+		return docs, nil
+	}
+
+	docs := make(map[string]json.RawMessage)
+	path := s.Path(collection, ".json")
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err), err == nil && len(data) == 0:
+	case err != nil:
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	default:
+		var file map[string]map[string]json.RawMessage
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if file[collection] != nil {
+			docs = file[collection]
+		}
+	}
+	s.collections[collection] = docs
+	return docs, nil
+}
+
+// saveLocked writes a collection atomically; s.mu must be held
+func (s *FileStorage) saveLocked(collection string, docs map[string]json.RawMessage) error {
+	path := s.Path(collection, ".json")
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, defaultDirPerms); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", collection, err)
+	}
+
+	data, err := json.MarshalIndent(map[string]map[string]json.RawMessage{collection: docs}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", collection, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, collection+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp %s file: %w", collection, err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { tmp.Close(); os.Remove(tmpPath) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		return fmt.Errorf("failed to write temp %s file: %w", collection, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp %s file: %w", collection, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFileStorage tests collections and logs kept in JSON files
+func TestFileStorage(t *testing.T) {
+	t.Run("collections survive a reopen", func(t *testing.T) {
+		dir := t.TempDir()
+		s := NewFileStorage(dir, nil)
+		require.NoError(t, s.Put(Checkpoints, "Ethereum", json.RawMessage(`{"lastIndexedBlock":10}`)))
+		require.NoError(t, s.Put(Checkpoints, "Base", json.RawMessage(`{"lastIndexedBlock":20}`)))
+		require.NoError(t, s.Delete(Checkpoints, "Base"))
+		require.NoError(t, s.Delete(Checkpoints, "Unknown"))
+
+		docs, err := NewFileStorage(dir, nil).Load(Checkpoints)
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		assert.JSONEq(t, `{"lastIndexedBlock":10}`, string(docs["Ethereum"]))
+		assert.FileExists(t, filepath.Join(dir, "checkpoints.json"))
+	})
+
+	t.Run("orders file keeps its layout", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "custom-orders.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"orders":{"0x01":{"orderId":"0x01"}}}`), 0600))
+
+		s := NewFileStorage(filepath.Dir(path), map[string]string{Orders: path})
+		docs, err := s.Load(Orders)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"orderId":"0x01"}`, string(docs["0x01"]))
+
+		require.NoError(t, s.Put(Orders, "0x02", json.RawMessage(`{"orderId":"0x02"}`)))
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var file map[string]map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(data, &file))
+		assert.Len(t, file[Orders], 2)
+	})
+
+	t.Run("missing and empty files are empty collections", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "empty.json"), nil, 0600))
+		s := NewFileStorage(dir, nil)
+		for _, name := range []string{"missing", "empty"} {
+			docs, err := s.Load(name)
+			require.NoError(t, err)
+			assert.Empty(t, docs)
+		}
+	})
+
+	t.Run("corrupted file is an error", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, Orders+".json"), []byte("{"), 0600))
+		_, err := NewFileStorage(dir, nil).Load(Orders)
+		assert.ErrorContains(t, err, "failed to parse")
+	})
+
+	t.Run("logs are appended as JSON lines", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nested", "audit.jsonl")
+		s := NewFileStorage(t.TempDir(), map[string]string{TxAudit: path})
+		require.NoError(t, s.Append(TxAudit, json.RawMessage(`{"n":1}`)))
+		require.NoError(t, s.Append(TxAudit, json.RawMessage(`{"n":2}`)))
+
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+		var lines []string
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		assert.Equal(t, []string{`{"n":1}`, `{"n":2}`}, lines)
+	})
+}
+
+// TestFromEnv tests selecting the backend from the environment
+func TestFromEnv(t *testing.T) {
+	t.Run("file backend uses the SOLVER_*_FILE paths", func(t *testing.T) {
+		t.Setenv("STORAGE_BACKEND", BackendFile)
+		t.Setenv("SOLVER_ORDER_STORE_FILE", "custom/orders.json")
+		s, err := FromEnv()
+		require.NoError(t, err)
+		fs, ok := s.(*FileStorage)
+		require.True(t, ok)
+		assert.Equal(t, "custom/orders.json", fs.Path(Orders, ".json"))
+		assert.Equal(t, "state/solver_state/admin-audit.jsonl", fs.Path(AdminAudit, ".jsonl"))
+		assert.Equal(t, filepath.Join(defaultFileDir, "checkpoints.json"), fs.Path(Checkpoints, ".json"))
+	})
+
+	t.Run("sqlite is the default backend", func(t *testing.T) {
+		t.Setenv("STORAGE_BACKEND", "")
+		t.Setenv("STORAGE_DSN", filepath.Join(t.TempDir(), "solver.db"))
+		s, err := FromEnv()
+		require.NoError(t, err)
+		defer s.Close()
+		assert.Equal(t, BackendSQLite, s.Backend())
+	})
+
+	t.Run("postgres requires a DSN", func(t *testing.T) {
+		t.Setenv("STORAGE_BACKEND", BackendPostgres)
+		t.Setenv("STORAGE_DSN", "")
+		_, err := FromEnv()
+		assert.ErrorContains(t, err, "STORAGE_DSN is required")
+	})
+
+	t.Run("unknown backend", func(t *testing.T) {
+		t.Setenv("STORAGE_BACKEND", "redis")
+		_, err := FromEnv()
+		assert.ErrorContains(t, err, "unknown STORAGE_BACKEND")
+	})
+}
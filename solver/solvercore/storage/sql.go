@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sqlSchema creates the tables of each SQL backend; documents hold collections, log_records the logs
+var sqlSchema = map[string][]string{
+	BackendSQLite: {
+		`CREATE TABLE IF NOT EXISTS documents (collection TEXT NOT NULL, key TEXT NOT NULL, doc TEXT NOT NULL, updated_at TIMESTAMP NOT NULL, PRIMARY KEY (collection, key))`,
+		`CREATE TABLE IF NOT EXISTS log_records (id INTEGER PRIMARY KEY AUTOINCREMENT, log TEXT NOT NULL, record TEXT NOT NULL, created_at TIMESTAMP NOT NULL)`,
+	},
+	BackendPostgres: {
+		`CREATE TABLE IF NOT EXISTS documents (collection TEXT NOT NULL, key TEXT NOT NULL, doc JSONB NOT NULL, updated_at TIMESTAMPTZ NOT NULL, PRIMARY KEY (collection, key))`,
+		`CREATE TABLE IF NOT EXISTS log_records (id BIGSERIAL PRIMARY KEY, log TEXT NOT NULL, record JSONB NOT NULL, created_at TIMESTAMPTZ NOT NULL)`,
+		`CREATE INDEX IF NOT EXISTS log_records_log ON log_records (log, id)`,
+	},
+}
+
+// SQLStorage keeps collections and logs in a SQLite or Postgres database
+type SQLStorage struct {
+	backend string
+	db      *sql.DB
+	now     func() time.Time
+}
+
+// OpenSQL opens the database of a SQL backend with its registered driver and creates the tables
+func OpenSQL(backend, dsn string) (*SQLStorage, error) {
+	driver := SQLDriver(backend)
+	if driver == "" {
+		return nil, fmt.Errorf("no database/sql driver registered for the %s storage backend", backend)
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s storage: %w", backend, err)
+	}
+	if backend == BackendSQLite {
+		// SQLite allows one writer at a time; a single connection avoids "database is locked" errors
+		db.SetMaxOpenConns(1)
+	}
+	s, err := NewSQLStorage(backend, db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewSQLStorage uses db as the database of backend, creating the tables when missing
+func NewSQLStorage(backend string, db *sql.DB) (*SQLStorage, error) {
+	schema, ok := sqlSchema[backend]
+	if !ok {
+		return nil, fmt.Errorf("unknown SQL storage backend %q", backend)
+	}
+	for _, stmt := range schema {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, fmt.Errorf("failed to create %s storage tables: %w", backend, err)
+		}
+	}
+	return &SQLStorage{backend: backend, db: db, now: time.Now}, nil
+}
+
+// Backend implements Storage
+func (s *SQLStorage) Backend() string {
+	return s.backend
+}
+
+// Load implements Storage
+func (s *SQLStorage) Load(collection string) (map[string]json.RawMessage, error) {
+	rows, err := s.db.Query(s.query(`SELECT key, doc FROM documents WHERE collection = ?`), collection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", collection, err)
+	}
+	defer rows.Close()
+
+	docs := make(map[string]json.RawMessage)
+	for rows.Next() {
+		var key, doc string
+		if err := rows.Scan(&key, &doc); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", collection, err)
+		}
+		docs[key] = json.RawMessage(doc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", collection, err)
+	}
+	return docs, nil
+}
+
+// Put implements Storage
+func (s *SQLStorage) Put(collection, key string, doc json.RawMessage) error {
+	_, err := s.db.Exec(s.query(`INSERT INTO documents (collection, key, doc, updated_at) VALUES (?, ?, ?, ?) `+
+		`ON CONFLICT (collection, key) DO UPDATE SET doc = excluded.doc, updated_at = excluded.updated_at`),
+		collection, key, string(doc), s.now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to store %s %s: %w", collection, key, err)
+	}
+	return nil
+}
+
+// Delete implements Storage
+func (s *SQLStorage) Delete(collection, key string) error {
+	if _, err := s.db.Exec(s.query(`DELETE FROM documents WHERE collection = ? AND key = ?`), collection, key); err != nil {
+		return fmt.Errorf("failed to delete %s %s: %w", collection, key, err)
+	}
+	return nil
+}
+
+// Append implements Storage
+func (s *SQLStorage) Append(log string, record json.RawMessage) error {
+	_, err := s.db.Exec(s.query(`INSERT INTO log_records (log, record, created_at) VALUES (?, ?, ?)`),
+		log, string(record), s.now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to append to %s: %w", log, err)
+	}
+	return nil
+}
+
+// Close implements Storage
+func (s *SQLStorage) Close() error {
+	return s.db.Close()
+}
+
+// query rewrites the ? placeholders of query as $1, $2... for Postgres
+func (s *SQLStorage) query(query string) string {
+	if s.backend != BackendPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
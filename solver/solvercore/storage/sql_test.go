@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSQLStorage tests the parts of the SQL backends that need no database
+func TestSQLStorage(t *testing.T) {
+	t.Run("placeholders are rewritten for postgres", func(t *testing.T) {
+		query := `DELETE FROM documents WHERE collection = ? AND key = ?`
+		assert.Equal(t, query, (&SQLStorage{backend: BackendSQLite}).query(query))
+		assert.Equal(t, `DELETE FROM documents WHERE collection = $1 AND key = $2`,
+			(&SQLStorage{backend: BackendPostgres}).query(query))
+	})
+
+	t.Run("drivers are linked in", func(t *testing.T) {
+		assert.Equal(t, "sqlite", SQLDriver(BackendSQLite))
+		assert.Equal(t, "pgx", SQLDriver(BackendPostgres))
+		assert.Empty(t, SQLDriver("mysql"))
+	})
+
+	t.Run("unknown backend", func(t *testing.T) {
+		_, err := NewSQLStorage("mysql", nil)
+		assert.ErrorContains(t, err, "unknown SQL storage backend")
+	})
+}
+
+// TestSQLiteStorage tests collections and logs kept in a SQLite database
+func TestSQLiteStorage(t *testing.T) {
+	open := func(t *testing.T, dsn string) *SQLStorage {
+		s, err := OpenSQL(BackendSQLite, dsn)
+		require.NoError(t, err)
+		t.Cleanup(func() { s.Close() })
+		return s
+	}
+
+	t.Run("collections survive a reopen", func(t *testing.T) {
+		dsn := filepath.Join(t.TempDir(), "solver.db")
+		s := open(t, dsn)
+		require.NoError(t, s.Put(Checkpoints, "Ethereum", json.RawMessage(`{"lastIndexedBlock":10}`)))
+		require.NoError(t, s.Put(Checkpoints, "Base", json.RawMessage(`{"lastIndexedBlock":20}`)))
+		require.NoError(t, s.Put(Checkpoints, "Ethereum", json.RawMessage(`{"lastIndexedBlock":11}`)))
+		require.NoError(t, s.Delete(Checkpoints, "Base"))
+		require.NoError(t, s.Delete(Checkpoints, "Unknown"))
+		require.NoError(t, s.Put(Orders, "0x01", json.RawMessage(`{"orderId":"0x01"}`)))
+		require.NoError(t, s.Close())
+
+		reopened := open(t, dsn)
+		docs, err := reopened.Load(Checkpoints)
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		assert.JSONEq(t, `{"lastIndexedBlock":11}`, string(docs["Ethereum"]))
+
+		docs, err = reopened.Load(Orders)
+		require.NoError(t, err)
+		assert.Len(t, docs, 1)
+	})
+
+	t.Run("unknown collections are empty", func(t *testing.T) {
+		docs, err := open(t, filepath.Join(t.TempDir(), "solver.db")).Load("missing")
+		require.NoError(t, err)
+		assert.Empty(t, docs)
+	})
+
+	t.Run("logs are appended in order", func(t *testing.T) {
+		dsn := filepath.Join(t.TempDir(), "solver.db")
+		s := open(t, dsn)
+		require.NoError(t, s.Append(TxAudit, json.RawMessage(`{"n":1}`)))
+		require.NoError(t, s.Append(AdminAudit, json.RawMessage(`{"n":2}`)))
+		require.NoError(t, s.Append(TxAudit, json.RawMessage(`{"n":3}`)))
+
+		db, err := sql.Open("sqlite", dsn)
+		require.NoError(t, err)
+		defer db.Close()
+		rows, err := db.Query(`SELECT record FROM log_records WHERE log = ? ORDER BY id`, TxAudit)
+		require.NoError(t, err)
+		defer rows.Close()
+		var records []string
+		for rows.Next() {
+			var record string
+			require.NoError(t, rows.Scan(&record))
+			records = append(records, record)
+		}
+		require.NoError(t, rows.Err())
+		assert.Equal(t, []string{`{"n":1}`, `{"n":3}`}, records)
+	})
+}
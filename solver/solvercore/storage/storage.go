@@ -0,0 +1,151 @@
+// Package storage persists the solver's checkpoints, orders and audit logs behind one interface, so
+// high-volume deployments can move them from JSON files to a database.
+//
+// Records are JSON documents: collections hold documents by key (orders by order ID, checkpoints by
+// network) and logs are append-only (transaction and admin audit). Three backends implement it:
+// - file: one JSON file per collection and one JSONL file per log, the layout the solver always used
+// - sqlite: a local database file, the default
+// - postgres: a shared database for production deployments
+//
+// The SQL backends use database/sql with the drivers linked in by drivers_*.go.
+//
+// Settings:
+// - STORAGE_BACKEND: file, sqlite or postgres (default sqlite)
+// - STORAGE_DSN: database file for sqlite (default state/solver_state/solver.db), connection string for postgres (required)
+// - SOLVER_ORDER_STORE_FILE, SOLVER_TX_AUDIT_FILE, SOLVER_ADMIN_AUDIT_FILE: files of the file backend
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+)
+
+// Collections and logs of the solver
+const (
+	Orders      = "orders"       // Order records by order ID
+	Checkpoints = "checkpoints"  // Last indexed block by network
+	TxAudit     = "tx_audit"     // Signed transactions
+	AdminAudit  = "admin_audit"  // Manual actions taken through the admin API
+	TxOutbox    = "tx_outbox"    // Fill and settle transactions in flight
+	SpendLimits = "spend_limits" // Daily spend windows by chain ID
+	SeenEvents  = "seen_events"  // Open events already handled, by event key
+)
+
+// Backends
+const (
+	BackendFile     = "file"
+	BackendSQLite   = "sqlite"
+	BackendPostgres = "postgres"
+)
+
+const (
+	defaultFileDir   = "state/solver_state"
+	defaultSQLiteDSN = "state/solver_state/solver.db"
+)
+
+// Storage persists JSON documents by collection and key, and append-only logs of JSON records.
+// Implementations are safe for concurrent use.
+type Storage interface {
+	// Backend names the implementation: file, sqlite or postgres
+	Backend() string
+	// Load returns every document of a collection by key; an unknown collection is empty
+	Load(collection string) (map[string]json.RawMessage, error)
+	// Put stores doc under key, replacing the previous document
+	Put(collection, key string, doc json.RawMessage) error
+	// Delete removes the document stored under key, if any
+	Delete(collection, key string) error
+	// Append adds a record to a log
+	Append(log string, record json.RawMessage) error
+	// Close releases the backend
+	Close() error
+}
+
+// fileLocations are the files of the file backend that predate it, with the variables moving them
+var fileLocations = map[string]struct{ env, path string }{
+	Orders:     {"SOLVER_ORDER_STORE_FILE", "state/solver_state/orders.json"},
+	TxAudit:    {"SOLVER_TX_AUDIT_FILE", "state/solver_state/tx-audit.jsonl"},
+	AdminAudit: {"SOLVER_ADMIN_AUDIT_FILE", "state/solver_state/admin-audit.jsonl"},
+}
+
+// sqlDrivers are the database/sql driver names accepted for each SQL backend, in order of preference
+var sqlDrivers = map[string][]string{
+	BackendSQLite:   {"sqlite", "sqlite3"},
+	BackendPostgres: {"pgx", "postgres"},
+}
+
+// FilePath returns the file of a collection or log of the file backend at SOLVER_*_FILE
+func FilePath(name string) string {
+	if location, ok := fileLocations[name]; ok {
+		return envutil.GetEnvWithDefault(location.env, location.path)
+	}
+	return ""
+}
+
+// SQLDriver returns the registered driver of a SQL backend, empty when none is registered
+func SQLDriver(backend string) string {
+	registered := sql.Drivers()
+	for _, name := range sqlDrivers[backend] {
+		if slices.Contains(registered, name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// FromEnv opens the backend selected by STORAGE_BACKEND
+func FromEnv() (Storage, error) {
+	backend := envutil.GetEnvWithDefault("STORAGE_BACKEND", BackendSQLite)
+	dsn := envutil.GetEnvWithDefault("STORAGE_DSN", "")
+
+	switch backend {
+	case BackendFile:
+		paths := make(map[string]string, len(fileLocations))
+		for name := range fileLocations {
+			paths[name] = FilePath(name)
+		}
+		return NewFileStorage(defaultFileDir, paths), nil
+	case BackendSQLite:
+		if dsn == "" {
+			dsn = defaultSQLiteDSN
+			if err := os.MkdirAll(filepath.Dir(dsn), defaultDirPerms); err != nil {
+				return nil, fmt.Errorf("failed to create the sqlite storage directory: %w", err)
+			}
+		}
+	case BackendPostgres:
+		if dsn == "" {
+			return nil, fmt.Errorf("STORAGE_DSN is required for the postgres backend")
+		}
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q (use file, sqlite or postgres)", backend)
+	}
+
+	s, err := OpenSQL(backend, dsn)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+var (
+	defaultStorage     Storage
+	defaultStorageErr  error
+	defaultStorageOnce sync.Once
+)
+
+// Default returns the process-wide storage opened from the environment
+func Default() (Storage, error) {
+	defaultStorageOnce.Do(func() {
+		defaultStorage, defaultStorageErr = FromEnv()
+		if defaultStorageErr == nil {
+			fmt.Printf("💾 Storage backend: %s\n", defaultStorage.Backend())
+		}
+	})
+	return defaultStorage, defaultStorageErr
+}
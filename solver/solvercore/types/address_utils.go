@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/amount"
 	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -36,7 +37,10 @@ func ToEVMAddress(address string) (common.Address, error) {
 
 	// If it's a 40-character hex string (EVM address), use directly
 	if len(cleanAddr) == EthereumAddressLength {
-		return common.HexToAddress(address), nil
+		if !common.IsHexAddress(cleanAddr) {
+			return common.Address{}, fmt.Errorf("invalid EVM address: %s", address)
+		}
+		return common.HexToAddress(cleanAddr), nil
 	}
 
 	// If it's a 64-character hex string (EVM bytes32 - 32 bytes), extract the address
@@ -100,7 +104,10 @@ func (ac *AddressConverter) ToBytes32(address string) ([32]byte, error) {
 
 	// If it's a 40-character hex string (EVM address), left-pad to 32 bytes
 	if len(cleanAddr) == EthereumAddressLength {
-		evmAddr := common.HexToAddress(address)
+		if !common.IsHexAddress(cleanAddr) {
+			return [32]byte{}, fmt.Errorf("invalid EVM address: %s", address)
+		}
+		evmAddr := common.HexToAddress(cleanAddr)
 		var result [32]byte
 		copy(result[12:], evmAddr.Bytes()) // Left-pad with 12 zero bytes
 		return result, nil
@@ -148,18 +155,24 @@ func HexToBytes32(hexStr string) ([32]byte, error) {
 	return ac.ToBytes32(hexStr)
 }
 
+// IsNativeToken reports whether an order token is the chain's native asset, encoded by the
+// contracts as the zero address ("", "0x0" or zero-padded bytes32)
+func IsNativeToken(token string) bool {
+	clean := strings.TrimPrefix(strings.TrimPrefix(token, "0x"), "0X")
+	return strings.Trim(clean, "0") == ""
+}
+
 // FormatTokenAmount formats a token amount from wei to tokens with specified decimals
 // This is a shared utility function used by both EVM and Starknet operations
-func FormatTokenAmount(amount *big.Int, decimals int) string {
-	if amount == nil {
+func FormatTokenAmount(units *big.Int, decimals int) string {
+	if units == nil {
 		return "0"
 	}
 
-	// Convert from wei to tokens
-	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
-	tokenAmount := new(big.Float).Quo(
-		new(big.Float).SetInt(amount),
-		new(big.Float).SetInt(divisor))
+	return amount.New(units, decimals).Fixed(2) + " tokens"
+}
 
-	return tokenAmount.Text('f', 2) + " tokens"
+// FormatTokenAmountWithSymbol formats a token amount exactly, without trailing zeros (e.g. "12.5 DOG")
+func FormatTokenAmountWithSymbol(units *big.Int, decimals int, symbol string) string {
+	return amount.New(units, decimals).String() + " " + symbol
 }
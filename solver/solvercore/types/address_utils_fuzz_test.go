@@ -0,0 +1,50 @@
+package types
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FuzzBytes32AddressConversions checks that ToBytes32 and ToEVMAddress never panic and that
+// EVM addresses survive the bytes32 round trip
+func FuzzBytes32AddressConversions(f *testing.F) {
+	f.Add("0x1234567890123456789012345678901234567890")
+	f.Add("0x0000000000000000000000001234567890123456789012345678901234567890")
+	f.Add("0x04718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d")
+	f.Add("0x" + strings.Repeat("ab", 31))
+	f.Add("0xzz34567890123456789012345678901234567890")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, address string) {
+		b32, b32Err := HexToBytes32(address)
+		evmAddr, evmErr := ToEVMAddress(address)
+
+		// Re-encoding a decoded bytes32 must give the same bytes32
+		if b32Err == nil {
+			again, err := HexToBytes32("0x" + hex.EncodeToString(b32[:]))
+			if err != nil || again != b32 {
+				t.Fatalf("bytes32 %x does not round trip: %x, %v", b32, again, err)
+			}
+		}
+
+		if evmErr != nil {
+			return
+		}
+		if len(strings.TrimPrefix(address, "0x")) == EthereumAddressLength {
+			// An EVM address is left-padded to bytes32 and recovered from its last 20 bytes
+			if b32Err != nil {
+				t.Fatalf("valid EVM address %q rejected by ToBytes32: %v", address, b32Err)
+			}
+			if common.BytesToAddress(b32[12:]) != evmAddr {
+				t.Fatalf("bytes32 %x does not hold address %s", b32, evmAddr)
+			}
+			back, err := ToEVMAddress("0x" + hex.EncodeToString(b32[:]))
+			if err != nil || back != evmAddr {
+				t.Fatalf("address %s does not round trip: %s, %v", evmAddr, back, err)
+			}
+		}
+	})
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -218,6 +219,15 @@ func TestToEVMAddress(t *testing.T) {
 
 		assert.Error(t, err)
 	})
+
+	t.Run("Non-hex EVM-length address", func(t *testing.T) {
+		address := "0xzz34567890123456789012345678901234567890"
+		_, err := ToEVMAddress(address)
+		assert.Error(t, err)
+
+		_, err = HexToBytes32(address)
+		assert.Error(t, err)
+	})
 }
 
 func TestToStarknetAddress(t *testing.T) {
@@ -269,6 +279,15 @@ func TestIsBytes32Address(t *testing.T) {
 	})
 }
 
+func TestIsNativeToken(t *testing.T) {
+	for _, token := range []string{"", "0x0", "0x0000000000000000000000000000000000000000", "0x" + strings.Repeat("0", 64)} {
+		assert.True(t, IsNativeToken(token), token)
+	}
+	for _, token := range []string{"0x1234567890123456789012345678901234567890", "0x0000000000000000000000000000000000000000000000000000000000000001"} {
+		assert.False(t, IsNativeToken(token), token)
+	}
+}
+
 func TestFormatAddress(t *testing.T) {
 	ac := NewAddressConverter()
 
@@ -330,6 +349,28 @@ func TestFormatTokenAmount(t *testing.T) {
 	})
 }
 
+func TestFormatTokenAmountWithSymbol(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   *big.Int
+		decimals int
+		expected string
+	}{
+		{"whole amount", big.NewInt(12_000_000), 6, "12 DOG"},
+		{"fraction without trailing zeros", big.NewInt(12_500_000), 6, "12.5 DOG"},
+		{"small fraction keeps leading zeros", big.NewInt(1), 6, "0.000001 DOG"},
+		{"zero decimals", big.NewInt(42), 0, "42 DOG"},
+		{"negative amount", big.NewInt(-1_500_000), 6, "-1.5 DOG"},
+		{"nil amount", nil, 18, "0 DOG"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FormatTokenAmountWithSymbol(tt.amount, tt.decimals, "DOG"))
+		})
+	}
+}
+
 func TestGetOrderIDBytes(t *testing.T) {
 	t.Run("Valid order ID", func(t *testing.T) {
 		args := ParsedArgs{